@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/spf13/cobra"
+)
+
+var assetTagRulesCmd = &cobra.Command{
+	Use:   "asset-tag-rules",
+	Short: "Manage rules that tag app assets on every sync.",
+	Long: `Manage rules that tag app assets on every sync.
+
+A rule tags each app asset matching all of its conditions. A condition compares one asset
+field (asset_kind, external_id, parent_external_id, display_name, or status) to a value,
+case-insensitively: field=value matches exactly and field~value matches a substring. Tags
+are re-evaluated when each connector source finishes a sync, and filter the App Assets page
+with ?tag=key or ?tag=key=value.`,
+}
+
+var (
+	assetTagRuleName    string
+	assetTagRuleMatches []string
+	assetTagRuleTag     string
+)
+
+var assetTagRulesCreateCmd = &cobra.Command{
+	Use:     "create",
+	Short:   "Create a tag rule.",
+	Example: `  open-sspm asset-tag-rules create --name bots --match asset_kind=github_app_installation --match display_name~bot --tag automation`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, value, _ := strings.Cut(strings.TrimSpace(assetTagRuleTag), "=")
+		rule := registry.AssetTagRule{
+			Name:     strings.TrimSpace(assetTagRuleName),
+			TagKey:   strings.ToLower(strings.TrimSpace(key)),
+			TagValue: strings.TrimSpace(value),
+		}
+		for _, raw := range assetTagRuleMatches {
+			cond, err := registry.ParseAssetTagCondition(raw)
+			if err != nil {
+				return err
+			}
+			rule.Conditions = append(rule.Conditions, cond)
+		}
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+		conditions, err := json.Marshal(rule.Conditions)
+		if err != nil {
+			return err
+		}
+		return withOrgQueries(func(ctx context.Context, q *gen.Queries) error {
+			row, err := q.CreateAppAssetTagRule(ctx, gen.CreateAppAssetTagRuleParams{
+				Name:       rule.Name,
+				Conditions: conditions,
+				TagKey:     rule.TagKey,
+				TagValue:   rule.TagValue,
+			})
+			if err != nil {
+				return err
+			}
+			cmd.Printf("created tag rule %s (id %d); it applies when each source next syncs\n", row.Name, row.ID)
+			return nil
+		})
+	},
+}
+
+var assetTagRulesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tag rules.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withOrgQueries(func(ctx context.Context, q *gen.Queries) error {
+			rows, err := q.ListAppAssetTagRules(ctx)
+			if err != nil {
+				return err
+			}
+			for _, row := range rows {
+				rule, err := registry.AssetTagRuleFromRow(row)
+				if err != nil {
+					cmd.Printf("%d\t%s\tinvalid: %v\n", row.ID, row.Name, err)
+					continue
+				}
+				conditions := make([]string, 0, len(rule.Conditions))
+				for _, cond := range rule.Conditions {
+					conditions = append(conditions, cond.String())
+				}
+				tag := rule.TagKey
+				if rule.TagValue != "" {
+					tag += "=" + rule.TagValue
+				}
+				cmd.Printf("%d\t%s\t%s\t%s\n", rule.ID, rule.Name, strings.Join(conditions, " AND "), tag)
+			}
+			return nil
+		})
+	},
+}
+
+var assetTagRulesDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a tag rule and the tags it applied.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.TrimSpace(assetTagRuleName)
+		if name == "" {
+			return errors.New("--name is required")
+		}
+		return withOrgQueries(func(ctx context.Context, q *gen.Queries) error {
+			removed, err := q.DeleteAppAssetTagRuleByName(ctx, name)
+			if err != nil {
+				return err
+			}
+			if removed == 0 {
+				cmd.Printf("tag rule %s not found; nothing to do\n", name)
+				return nil
+			}
+			cmd.Printf("deleted tag rule %s\n", name)
+			return nil
+		})
+	},
+}
+
+func init() {
+	assetTagRulesCmd.AddCommand(assetTagRulesCreateCmd, assetTagRulesListCmd, assetTagRulesDeleteCmd)
+
+	assetTagRulesCreateCmd.Flags().StringVar(&assetTagRuleName, "name", "", "Unique rule name")
+	assetTagRulesCreateCmd.Flags().StringArrayVar(&assetTagRuleMatches, "match", nil, "Condition as field=value or field~value; repeat to require several")
+	assetTagRulesCreateCmd.Flags().StringVar(&assetTagRuleTag, "tag", "", "Tag to apply as key or key=value")
+	_ = assetTagRulesCreateCmd.MarkFlagRequired("name")
+	_ = assetTagRulesCreateCmd.MarkFlagRequired("match")
+	_ = assetTagRulesCreateCmd.MarkFlagRequired("tag")
+
+	assetTagRulesDeleteCmd.Flags().StringVar(&assetTagRuleName, "name", "", "Rule name")
+	_ = assetTagRulesDeleteCmd.MarkFlagRequired("name")
+}
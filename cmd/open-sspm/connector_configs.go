@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-sspm/open-sspm/internal/config"
+	"github.com/open-sspm/open-sspm/internal/connectors/configstore"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/spf13/cobra"
+)
+
+var connectorsCmd = &cobra.Command{
+	Use:   "connectors",
+	Short: "Export and import connector configuration.",
+	Long: `Export and import connector configuration.
+
+The exported JSON document carries every configured connector without its secrets: each
+secret field is written as a secret:// reference (an existing reference, or one named after
+the connector and field such as secret://GITHUB_TOKEN) that the importing deployment resolves
+through CONNECTOR_SECRET_BACKEND.`,
+}
+
+var (
+	connectorsExportOutput    string
+	connectorsImportFile      string
+	connectorsImportDryRun    bool
+	connectorsImportOverwrite bool
+)
+
+var connectorsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write connector configuration as a portable JSON document.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withConnectorConfigPool(func(ctx context.Context, pool *pgxpool.Pool) error {
+			rows, err := gen.New(pool).ListConnectorConfigs(ctx)
+			if err != nil {
+				return err
+			}
+			doc, err := exportConnectorConfigs(rows)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if path := strings.TrimSpace(connectorsExportOutput); path != "" && path != "-" {
+				f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				out = f
+			}
+			enc := json.NewEncoder(out)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(doc); err != nil {
+				return err
+			}
+			for _, connector := range doc.Connectors {
+				for _, ref := range connector.SecretRefs {
+					cmd.PrintErrf("%s needs secret %s%s\n", connector.Kind, configstore.SecretRefPrefix, ref)
+				}
+			}
+			return nil
+		})
+	},
+}
+
+var connectorsImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Load connector configuration from a portable JSON document.",
+	Long: `Load connector configuration from a portable JSON document.
+
+The whole document is validated before anything is written. Connectors already configured
+with different values are reported as conflicts and the import stops unless --overwrite is
+set; --dry-run prints the plan without writing.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		doc, err := readConnectorConfigDocument(cmd.InOrStdin(), connectorsImportFile)
+		if err != nil {
+			return err
+		}
+		return withConnectorConfigPool(func(ctx context.Context, pool *pgxpool.Pool) error {
+			rows, err := gen.New(pool).ListConnectorConfigs(ctx)
+			if err != nil {
+				return err
+			}
+			current := make([]configstore.PortableConnector, 0, len(rows))
+			for _, row := range rows {
+				current = append(current, configstore.PortableConnector{Kind: row.Kind, Enabled: row.Enabled, Config: row.Config})
+			}
+			plan, err := configstore.PlanImport(doc, current)
+			if err != nil {
+				return fmt.Errorf("invalid connector config document:\n%w", err)
+			}
+
+			for _, change := range plan.Changes {
+				cmd.Printf("%s\t%s\tenabled=%t\n", change.Kind, change.Action, change.Enabled)
+			}
+			for _, conflict := range plan.Conflicts {
+				cmd.PrintErrf("conflict: %s\n", conflict)
+			}
+			if connectorsImportDryRun {
+				return nil
+			}
+			if len(plan.Conflicts) > 0 && !connectorsImportOverwrite {
+				return fmt.Errorf("%d connector(s) conflict with the target; rerun with --overwrite to replace them", len(plan.Conflicts))
+			}
+			if err := applyConnectorConfigImport(ctx, pool, plan); err != nil {
+				return err
+			}
+			cmd.Println("imported connector configuration")
+			return nil
+		})
+	},
+}
+
+func exportConnectorConfigs(rows []gen.ConnectorConfig) (configstore.PortableDocument, error) {
+	doc := configstore.PortableDocument{
+		Version:    configstore.PortableVersion,
+		Connectors: make([]configstore.PortableConnector, 0, len(rows)),
+	}
+	for _, row := range rows {
+		connector, ok, err := configstore.ExportConnector(row.Kind, row.Enabled, row.Config)
+		if err != nil {
+			return configstore.PortableDocument{}, err
+		}
+		if ok {
+			doc.Connectors = append(doc.Connectors, connector)
+		}
+	}
+	return doc, nil
+}
+
+func readConnectorConfigDocument(stdin io.Reader, path string) (configstore.PortableDocument, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return configstore.PortableDocument{}, errors.New("--file is required")
+	}
+	in := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return configstore.PortableDocument{}, err
+		}
+		defer f.Close()
+		in = f
+	}
+	var doc configstore.PortableDocument
+	dec := json.NewDecoder(in)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return configstore.PortableDocument{}, fmt.Errorf("decode connector config document: %w", err)
+	}
+	return doc, nil
+}
+
+func applyConnectorConfigImport(ctx context.Context, pool *pgxpool.Pool, plan configstore.ImportPlan) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := gen.New(tx)
+	for _, change := range plan.Changes {
+		if change.Action == configstore.ImportActionUnchanged {
+			continue
+		}
+		if _, err := qtx.UpdateConnectorConfig(ctx, gen.UpdateConnectorConfigParams{Kind: change.Kind, Config: change.Config}); err != nil {
+			return fmt.Errorf("%s: %w", change.Kind, err)
+		}
+		if _, err := qtx.UpdateConnectorConfigEnabled(ctx, gen.UpdateConnectorConfigEnabledParams{Kind: change.Kind, Enabled: change.Enabled}); err != nil {
+			return fmt.Errorf("%s: %w", change.Kind, err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func withConnectorConfigPool(fn func(context.Context, *pgxpool.Pool) error) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	return fn(ctx, pool)
+}
+
+func init() {
+	connectorsCmd.AddCommand(connectorsExportCmd, connectorsImportCmd)
+
+	connectorsExportCmd.Flags().StringVarP(&connectorsExportOutput, "output", "o", "", "File to write the document to (defaults to stdout)")
+
+	connectorsImportCmd.Flags().StringVarP(&connectorsImportFile, "file", "f", "", "Document to import (- for stdin)")
+	connectorsImportCmd.Flags().BoolVar(&connectorsImportDryRun, "dry-run", false, "Validate and print the plan without writing")
+	connectorsImportCmd.Flags().BoolVar(&connectorsImportOverwrite, "overwrite", false, "Replace connectors that are configured differently in the target")
+	_ = connectorsImportCmd.MarkFlagRequired("file")
+}
@@ -3,21 +3,59 @@ package main
 import (
 	"github.com/open-sspm/open-sspm/internal/config"
 	"github.com/open-sspm/open-sspm/internal/connectors/aws"
+	"github.com/open-sspm/open-sspm/internal/connectors/bitbucket"
+	"github.com/open-sspm/open-sspm/internal/connectors/configstore"
 	"github.com/open-sspm/open-sspm/internal/connectors/datadog"
 	"github.com/open-sspm/open-sspm/internal/connectors/entra"
 	"github.com/open-sspm/open-sspm/internal/connectors/github"
 	"github.com/open-sspm/open-sspm/internal/connectors/googleworkspace"
+	"github.com/open-sspm/open-sspm/internal/connectors/httpclient"
 	"github.com/open-sspm/open-sspm/internal/connectors/okta"
 	"github.com/open-sspm/open-sspm/internal/connectors/registry"
 	"github.com/open-sspm/open-sspm/internal/connectors/vault"
+	"github.com/open-sspm/open-sspm/internal/discovery"
 )
 
 func buildConnectorRegistry(cfg config.Config) (*registry.ConnectorRegistry, error) {
+	if err := configureDiscoveryVendorCatalog(cfg); err != nil {
+		return nil, err
+	}
+	strategy, err := discovery.ParseCanonicalKeyStrategy(cfg.DiscoveryCanonicalKeyStrategy)
+	if err != nil {
+		return nil, err
+	}
+	discovery.SetCanonicalKeyStrategy(strategy)
+
+	secrets, err := configstore.NewSecretProvider(cfg.ConnectorSecretBackend, cfg.ConnectorSecretVaultMount)
+	if err != nil {
+		return nil, err
+	}
+
+	registry.SetMaxRawJSONBytes(cfg.RawJSONMaxBytes)
+	registry.SetAutoBindPolicy(registry.AutoBindPolicy{
+		ClientIDConfidence: cfg.DiscoveryAutoBindClientIDConfidence,
+		NameConfidence:     cfg.DiscoveryAutoBindNameConfidence,
+		MinConfidence:      cfg.DiscoveryAutoBindMinConfidence,
+		Disabled:           cfg.DiscoveryAutoBindDisabled,
+	})
+
+	if err := httpclient.Configure(httpclient.Options{
+		ProxyURL:       cfg.ConnectorHTTPProxy,
+		NoProxy:        cfg.ConnectorNoProxy,
+		CABundleFile:   cfg.ConnectorCABundlePath,
+		RequestTimeout: cfg.ConnectorHTTPTimeout,
+	}); err != nil {
+		return nil, err
+	}
+	httpclient.ConfigureLimits(connectorRequestLimits(cfg))
+
 	reg := registry.NewRegistry()
+	reg.SetSecretProvider(secrets)
+	reg.SetSyncRunTimeouts(cfg.SyncRunTimeouts)
 	if err := reg.Register(okta.NewDefinition(cfg.SyncOktaWorkers)); err != nil {
 		return nil, err
 	}
-	if err := reg.Register(&entra.Definition{}); err != nil {
+	if err := reg.Register(entra.NewDefinition(cfg.SyncEntraWorkers)); err != nil {
 		return nil, err
 	}
 	if err := reg.Register(github.NewDefinition(cfg.SyncGitHubWorkers)); err != nil {
@@ -35,5 +73,51 @@ func buildConnectorRegistry(cfg config.Config) (*registry.ConnectorRegistry, err
 	if err := reg.Register(&googleworkspace.Definition{}); err != nil {
 		return nil, err
 	}
+	if err := reg.Register(&bitbucket.Definition{}); err != nil {
+		return nil, err
+	}
 	return reg, nil
 }
+
+// configureDiscoveryVendorCatalog extends the built-in vendor catalog with operator entries so
+// discovery rows from every connector are enriched the same way.
+// connectorRequestLimits applies the per-kind overrides in cfg on top of each kind's default
+// request limits.
+func connectorRequestLimits(cfg config.Config) map[string]httpclient.Limits {
+	limits := map[string]httpclient.Limits{}
+	limitsFor := func(kind string) httpclient.Limits {
+		if l, ok := limits[kind]; ok {
+			return l
+		}
+		return httpclient.DefaultLimits(kind)
+	}
+	for kind, rps := range cfg.ConnectorRequestsPerSecond {
+		l := limitsFor(kind)
+		l.RequestsPerSecond = rps
+		limits[kind] = l
+	}
+	for kind, n := range cfg.ConnectorMaxConcurrentRequests {
+		l := limitsFor(kind)
+		l.MaxConcurrent = int(n)
+		limits[kind] = l
+	}
+	for kind, d := range cfg.ConnectorMaxBackoff {
+		l := limitsFor(kind)
+		l.MaxBackoff = d
+		limits[kind] = l
+	}
+	return limits
+}
+
+func configureDiscoveryVendorCatalog(cfg config.Config) error {
+	entries := discovery.SeedVendorCatalogEntries()
+	if path := cfg.DiscoveryVendorCatalogPath; path != "" {
+		extra, err := discovery.LoadVendorCatalogFile(path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, extra...)
+	}
+	discovery.SetVendorCatalog(discovery.NewVendorCatalog(entries))
+	return nil
+}
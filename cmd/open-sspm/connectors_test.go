@@ -0,0 +1,137 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/config"
+)
+
+// producedCredentialKinds collects the credential kinds the connector packages write: string
+// literals and package constants assigned to a CredentialKind field, and string literals
+// returned by functions whose name mentions CredentialKind.
+func producedCredentialKinds(t *testing.T, root string) map[string]string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(root, "*", "*.go"))
+	if err != nil {
+		t.Fatalf("glob connector sources: %v", err)
+	}
+	type pkgFiles struct {
+		consts map[string]string
+		files  []*ast.File
+	}
+	pkgs := map[string]*pkgFiles{}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		dir := filepath.Dir(path)
+		if pkgs[dir] == nil {
+			pkgs[dir] = &pkgFiles{consts: map[string]string{}}
+		}
+		pkgs[dir].files = append(pkgs[dir].files, file)
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, name := range vs.Names {
+					if i < len(vs.Values) {
+						if value, ok := stringLiteral(vs.Values[i]); ok {
+							pkgs[dir].consts[name.Name] = value
+						}
+					}
+				}
+			}
+		}
+	}
+
+	kinds := map[string]string{}
+	for dir, pkg := range pkgs {
+		resolve := func(expr ast.Expr) {
+			if value, ok := stringLiteral(expr); ok && value != "" {
+				kinds[value] = dir
+				return
+			}
+			if ident, ok := expr.(*ast.Ident); ok && pkg.consts[ident.Name] != "" {
+				kinds[pkg.consts[ident.Name]] = dir
+			}
+		}
+		for _, file := range pkg.files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.KeyValueExpr:
+					if key, ok := n.Key.(*ast.Ident); ok && key.Name == "CredentialKind" {
+						resolve(n.Value)
+					}
+				case *ast.FuncDecl:
+					if n.Body == nil || !strings.Contains(strings.ToLower(n.Name.Name), "credentialkind") {
+						return true
+					}
+					ast.Inspect(n.Body, func(m ast.Node) bool {
+						if ret, ok := m.(*ast.ReturnStmt); ok {
+							for _, result := range ret.Results {
+								resolve(result)
+							}
+						}
+						return true
+					})
+				}
+				return true
+			})
+		}
+	}
+	return kinds
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+func TestCredentialKindGlossaryCoversConnectorKinds(t *testing.T) {
+	reg, err := buildConnectorRegistry(config.Config{})
+	if err != nil {
+		t.Fatalf("buildConnectorRegistry() error = %v", err)
+	}
+	documented := map[string]bool{}
+	for _, info := range reg.CredentialKindGlossary() {
+		if info.Label == "" || info.Description == "" || info.RotationGuidance == "" {
+			t.Fatalf("glossary entry %+v is incomplete", info)
+		}
+		documented[info.Kind] = true
+	}
+
+	produced := producedCredentialKinds(t, filepath.Join("..", "..", "internal", "connectors"))
+	if len(produced) == 0 {
+		t.Fatalf("found no credential kinds in the connector sources")
+	}
+	var missing []string
+	for kind, dir := range produced {
+		if !documented[kind] {
+			missing = append(missing, kind+" ("+filepath.Base(dir)+")")
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		t.Fatalf("credential kinds without a glossary entry: %s", strings.Join(missing, ", "))
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	// Embedded so DISPLAY_TIMEZONE and per-user zones resolve on images without zoneinfo.
+	_ "time/tzdata"
 
 	"github.com/open-sspm/open-sspm/internal/logging"
 )
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-sspm/open-sspm/internal/auth"
+	"github.com/open-sspm/open-sspm/internal/config"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/spf13/cobra"
+)
+
+var orgsCmd = &cobra.Command{
+	Use:   "orgs",
+	Short: "Manage tenant orgs for hosted deployments.",
+	Long: `Manage tenant orgs for hosted deployments.
+
+Each connector source (kind + name, e.g. github/acme) belongs to one org, and UI users only
+see inventory from their org's sources. Sources and users without an assignment belong to
+the "default" org.`,
+}
+
+var (
+	orgSlug       string
+	orgName       string
+	orgSourceKind string
+	orgSourceName string
+	orgUserEmail  string
+)
+
+var orgsCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an org.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		slug := strings.ToLower(strings.TrimSpace(orgSlug))
+		if slug == "" {
+			return errors.New("--slug is required")
+		}
+		name := strings.TrimSpace(orgName)
+		if name == "" {
+			name = slug
+		}
+		return withOrgQueries(func(ctx context.Context, q *gen.Queries) error {
+			org, err := q.CreateOrg(ctx, gen.CreateOrgParams{Slug: slug, Name: name})
+			if err != nil {
+				return err
+			}
+			cmd.Printf("created org %s (id %d)\n", org.Slug, org.ID)
+			return nil
+		})
+	},
+}
+
+var orgsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List orgs and their connector sources.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withOrgQueries(func(ctx context.Context, q *gen.Queries) error {
+			orgs, err := q.ListOrgs(ctx)
+			if err != nil {
+				return err
+			}
+			sources, err := q.ListOrgSources(ctx)
+			if err != nil {
+				return err
+			}
+			sourcesByOrg := map[int64][]string{}
+			for _, source := range sources {
+				sourcesByOrg[source.OrgID] = append(sourcesByOrg[source.OrgID], source.SourceKind+"/"+source.SourceName)
+			}
+			for _, org := range orgs {
+				assigned := strings.Join(sourcesByOrg[org.ID], ", ")
+				if assigned == "" {
+					assigned = "-"
+				}
+				cmd.Printf("%d\t%s\t%s\t%s\n", org.ID, org.Slug, org.Name, assigned)
+			}
+			return nil
+		})
+	},
+}
+
+var orgsAssignSourceCmd = &cobra.Command{
+	Use:   "assign-source",
+	Short: "Assign a connector source to an org.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, name, err := orgSourceFlags()
+		if err != nil {
+			return err
+		}
+		return withOrgQueries(func(ctx context.Context, q *gen.Queries) error {
+			org, err := lookupOrg(ctx, q, orgSlug)
+			if err != nil {
+				return err
+			}
+			if err := q.AssignOrgSource(ctx, gen.AssignOrgSourceParams{SourceKind: kind, SourceName: name, OrgID: org.ID}); err != nil {
+				return err
+			}
+			cmd.Printf("assigned %s/%s to org %s\n", kind, name, org.Slug)
+			return nil
+		})
+	},
+}
+
+var orgsUnassignSourceCmd = &cobra.Command{
+	Use:   "unassign-source",
+	Short: "Return a connector source to the default org.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, name, err := orgSourceFlags()
+		if err != nil {
+			return err
+		}
+		return withOrgQueries(func(ctx context.Context, q *gen.Queries) error {
+			removed, err := q.UnassignOrgSource(ctx, gen.UnassignOrgSourceParams{SourceKind: kind, SourceName: name})
+			if err != nil {
+				return err
+			}
+			if removed == 0 {
+				cmd.Printf("%s/%s was not assigned; nothing to do\n", kind, name)
+				return nil
+			}
+			cmd.Printf("returned %s/%s to the default org\n", kind, name)
+			return nil
+		})
+	},
+}
+
+var orgsSetUserCmd = &cobra.Command{
+	Use:   "set-user",
+	Short: "Move a UI user to an org.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		email := auth.NormalizeEmail(orgUserEmail)
+		if email == "" {
+			return errors.New("--email is required")
+		}
+		return withOrgQueries(func(ctx context.Context, q *gen.Queries) error {
+			org, err := lookupOrg(ctx, q, orgSlug)
+			if err != nil {
+				return err
+			}
+			user, err := q.GetAuthUserByEmail(ctx, email)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return fmt.Errorf("user not found: %s", email)
+				}
+				return err
+			}
+			if err := q.UpdateAuthUserOrg(ctx, gen.UpdateAuthUserOrgParams{OrgID: org.ID, ID: user.ID}); err != nil {
+				return err
+			}
+			cmd.Printf("moved %s to org %s\n", user.Email, org.Slug)
+			return nil
+		})
+	},
+}
+
+func orgSourceFlags() (string, string, error) {
+	kind := strings.ToLower(strings.TrimSpace(orgSourceKind))
+	name := strings.TrimSpace(orgSourceName)
+	if kind == "" || name == "" {
+		return "", "", errors.New("--source-kind and --source-name are required")
+	}
+	return kind, name, nil
+}
+
+func lookupOrg(ctx context.Context, q *gen.Queries, slug string) (gen.Org, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if slug == "" {
+		return gen.Org{}, errors.New("--org is required")
+	}
+	org, err := q.GetOrgBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return gen.Org{}, fmt.Errorf("org not found: %s", slug)
+		}
+		return gen.Org{}, err
+	}
+	return org, nil
+}
+
+func withOrgQueries(fn func(context.Context, *gen.Queries) error) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	return fn(ctx, gen.New(pool))
+}
+
+func init() {
+	orgsCmd.AddCommand(orgsCreateCmd, orgsListCmd, orgsAssignSourceCmd, orgsUnassignSourceCmd, orgsSetUserCmd)
+
+	orgsCreateCmd.Flags().StringVar(&orgSlug, "slug", "", "Short unique identifier for the org")
+	orgsCreateCmd.Flags().StringVar(&orgName, "name", "", "Display name (defaults to the slug)")
+	_ = orgsCreateCmd.MarkFlagRequired("slug")
+
+	for _, c := range []*cobra.Command{orgsAssignSourceCmd, orgsUnassignSourceCmd} {
+		c.Flags().StringVar(&orgSourceKind, "source-kind", "", "Connector source kind (e.g. github, okta, entra)")
+		c.Flags().StringVar(&orgSourceName, "source-name", "", "Connector source name (e.g. the GitHub org or Entra tenant ID)")
+		_ = c.MarkFlagRequired("source-kind")
+		_ = c.MarkFlagRequired("source-name")
+	}
+	orgsAssignSourceCmd.Flags().StringVar(&orgSlug, "org", "", "Org slug")
+	_ = orgsAssignSourceCmd.MarkFlagRequired("org")
+
+	orgsSetUserCmd.Flags().StringVar(&orgUserEmail, "email", "", "Email address of the UI user")
+	orgsSetUserCmd.Flags().StringVar(&orgSlug, "org", "", "Org slug")
+	_ = orgsSetUserCmd.MarkFlagRequired("email")
+	_ = orgsSetUserCmd.MarkFlagRequired("org")
+}
@@ -108,5 +108,8 @@ func init() {
 		validateRulesCmd,
 		specVersionCmd,
 		usersCmd,
+		orgsCmd,
+		assetTagRulesCmd,
+		connectorsCmd,
 	)
 }
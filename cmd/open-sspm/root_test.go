@@ -30,6 +30,8 @@ func TestCommandUsesStructuredLogging(t *testing.T) {
 		{name: "seed-rules", args: []string{"seed-rules"}, want: true},
 		{name: "validate-rules", args: []string{"validate-rules"}, want: true},
 		{name: "users bootstrap-admin", args: []string{"users", "bootstrap-admin"}, want: false},
+		{name: "orgs assign-source", args: []string{"orgs", "assign-source"}, want: false},
+		{name: "asset-tag-rules create", args: []string{"asset-tag-rules", "create"}, want: false},
 		{name: "spec-version", args: []string{"spec-version"}, want: false},
 	}
 
@@ -71,13 +71,17 @@ func runServe() error {
 		return err
 	}
 
+	runLimiter := sync.NewRunLimiter(cfg.SyncMaxConcurrentRuns)
+
 	fullDBRunner := sync.NewDBRunner(pool, reg)
 	fullDBRunner.SetLockManager(locks)
+	fullDBRunner.SetRunLimiter(runLimiter)
 	fullDBRunner.SetRunMode(registry.RunModeFull)
 	fullDBRunner.SetGlobalEvalMode(cfg.GlobalEvalMode)
 
 	discoveryDBRunner := sync.NewDBRunner(pool, reg)
 	discoveryDBRunner.SetLockManager(locks)
+	discoveryDBRunner.SetRunLimiter(runLimiter)
 	discoveryDBRunner.SetRunMode(registry.RunModeDiscovery)
 	discoveryDBRunner.SetGlobalEvalMode(cfg.GlobalEvalMode)
 
@@ -116,6 +120,15 @@ func runServe() error {
 	}
 
 	errCh := make(chan error, 1)
+	if cfg.MetricsAddr != "" {
+		exportRisk := !cfg.CredentialRiskPolicyCustomized()
+		if !exportRisk {
+			slog.Info("credentials_by_risk metric disabled: the credential risk policy is customized")
+		}
+		if err := metrics.RegisterInventoryCollector(queries, cfg.MetricsInventoryCacheTTL, exportRisk); err != nil {
+			return err
+		}
+	}
 	metricsServer, metricsErrCh := metrics.StartServer(ctx, cfg.MetricsAddr)
 	go func() {
 		slog.Info("listening", "addr", cfg.HTTPAddr)
@@ -176,6 +189,7 @@ func maybeSeedDevAdmin(ctx context.Context, q *gen.Queries) error {
 		PasswordHash: hash,
 		Role:         auth.RoleAdmin,
 		IsActive:     true,
+		OrgID:        auth.DefaultOrgID,
 	})
 	if err != nil {
 		return err
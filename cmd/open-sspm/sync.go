@@ -54,9 +54,12 @@ func runSync() error {
 		return err
 	}
 
+	runLimiter := sync.NewRunLimiter(cfg.SyncMaxConcurrentRuns)
+
 	fullDBRunner := sync.NewDBRunner(pool, reg)
 	fullDBRunner.SetReporter(&sync.LogReporter{})
 	fullDBRunner.SetLockManager(locks)
+	fullDBRunner.SetRunLimiter(runLimiter)
 	fullDBRunner.SetRunMode(registry.RunModeFull)
 	fullDBRunner.SetGlobalEvalMode(cfg.GlobalEvalMode)
 	fullRunner := sync.NewBlockingRunOnceLockRunnerWithScope(locks, fullDBRunner, sync.RunOnceScopeNameFull)
@@ -64,6 +67,7 @@ func runSync() error {
 	discoveryDBRunner := sync.NewDBRunner(pool, reg)
 	discoveryDBRunner.SetReporter(&sync.LogReporter{})
 	discoveryDBRunner.SetLockManager(locks)
+	discoveryDBRunner.SetRunLimiter(runLimiter)
 	discoveryDBRunner.SetRunMode(registry.RunModeDiscovery)
 	discoveryDBRunner.SetGlobalEvalMode(cfg.GlobalEvalMode)
 	discoveryRunner := sync.NewBlockingRunOnceLockRunnerWithScope(locks, discoveryDBRunner, sync.RunOnceScopeNameDiscovery)
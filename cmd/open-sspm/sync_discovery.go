@@ -3,17 +3,26 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/open-sspm/open-sspm/internal/config"
 	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/discovery"
 	"github.com/open-sspm/open-sspm/internal/sync"
 	"github.com/spf13/cobra"
 )
 
+var (
+	syncDiscoveryBackfill      bool
+	syncDiscoveryBackfillSince string
+)
+
 var syncDiscoveryCmd = &cobra.Command{
 	Use:   "sync-discovery",
 	Short: "Run a one-off SaaS discovery sync against configured Okta/Entra connectors.",
@@ -23,6 +32,11 @@ var syncDiscoveryCmd = &cobra.Command{
 	},
 }
 
+func init() {
+	syncDiscoveryCmd.Flags().BoolVar(&syncDiscoveryBackfill, "backfill", false, "Re-ingest discovery evidence from DISCOVERY_BACKFILL_LOOKBACK ago, ignoring the stored watermark")
+	syncDiscoveryCmd.Flags().StringVar(&syncDiscoveryBackfillSince, "backfill-since", "", "Backfill start date (YYYY-MM-DD or RFC3339); implies --backfill")
+}
+
 func runSyncDiscovery() error {
 	cfg, err := config.Load()
 	if err != nil {
@@ -32,6 +46,14 @@ func runSyncDiscovery() error {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	if syncDiscoveryBackfill || strings.TrimSpace(syncDiscoveryBackfillSince) != "" {
+		since, err := discoveryBackfillStart(time.Now().UTC(), cfg.DiscoveryBackfillLookback, syncDiscoveryBackfillSince)
+		if err != nil {
+			return err
+		}
+		ctx = sync.WithForcedSync(discovery.WithBackfill(ctx, since))
+	}
+
 	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
 	if err != nil {
 		return err
@@ -57,6 +79,7 @@ func runSyncDiscovery() error {
 	dbRunner := sync.NewDBRunner(pool, reg)
 	dbRunner.SetReporter(&sync.LogReporter{})
 	dbRunner.SetLockManager(locks)
+	dbRunner.SetRunLimiter(sync.NewRunLimiter(cfg.SyncMaxConcurrentRuns))
 	dbRunner.SetRunMode(registry.RunModeDiscovery)
 	dbRunner.SetGlobalEvalMode(cfg.GlobalEvalMode)
 	runner := sync.NewBlockingRunOnceLockRunnerWithScope(locks, dbRunner, sync.RunOnceScopeNameDiscovery)
@@ -70,3 +93,22 @@ func runSyncDiscovery() error {
 	}
 	return &exitError{code: 1, err: syncErr, silent: false}
 }
+
+// discoveryBackfillStart resolves the backfill start from an explicit date or, when raw is
+// empty, lookback before now.
+func discoveryBackfillStart(now time.Time, lookback time.Duration, raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return now.Add(-lookback), nil
+	}
+	since, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		if since, err = time.Parse(time.RFC3339, raw); err != nil {
+			return time.Time{}, fmt.Errorf("invalid --backfill-since %q: use YYYY-MM-DD or RFC3339", raw)
+		}
+	}
+	if !since.Before(now) {
+		return time.Time{}, fmt.Errorf("--backfill-since %q must be in the past", raw)
+	}
+	return since.UTC(), nil
+}
@@ -87,6 +87,7 @@ var bootstrapAdminCmd = &cobra.Command{
 			PasswordHash: hash,
 			Role:         auth.RoleAdmin,
 			IsActive:     true,
+			OrgID:        auth.DefaultOrgID,
 		})
 		if err != nil {
 			return err
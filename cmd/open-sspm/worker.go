@@ -12,6 +12,8 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/open-sspm/open-sspm/internal/config"
 	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/incidents"
 	"github.com/open-sspm/open-sspm/internal/metrics"
 	"github.com/open-sspm/open-sspm/internal/sync"
 	"github.com/spf13/cobra"
@@ -63,6 +65,7 @@ func runWorker() error {
 	dbRunner := sync.NewDBRunner(pool, reg)
 	dbRunner.SetReporter(&sync.LogReporter{})
 	dbRunner.SetLockManager(locks)
+	dbRunner.SetRunLimiter(sync.NewRunLimiter(cfg.SyncMaxConcurrentRuns))
 	dbRunner.SetRunMode(registry.RunModeFull)
 	dbRunner.SetGlobalEvalMode(cfg.GlobalEvalMode)
 	backoffMax := cfg.SyncFailureBackoffMax
@@ -78,12 +81,34 @@ func runWorker() error {
 			"datadog":          cfg.SyncDatadogInterval,
 			"aws":              cfg.SyncAWSInterval,
 		},
-		FailureBackoffBase:   cfg.SyncInterval,
-		FailureBackoffMax:    backoffMax,
-		RecentFinishedRunCap: 10,
+		FailureBackoffBase:     cfg.SyncInterval,
+		FailureBackoffMax:      backoffMax,
+		RecentFinishedRunCap:   10,
+		APIRequestBudgetByKind: cfg.SyncAPIRequestBudgets,
+		APIRequestBudgetWindow: cfg.SyncAPIRequestBudgetWindow,
 	})
 	runner := sync.NewBlockingRunOnceLockRunnerWithScope(locks, dbRunner, sync.RunOnceScopeNameFull)
 
+	incidentSink, err := newConnectorIncidentSink(cfg)
+	if err != nil {
+		return err
+	}
+	if incidentSink != nil {
+		reconciler := incidents.NewReconciler(gen.New(pool), reg, incidentSink, cfg.ConnectorIncidentSLA)
+		slog.Info("connector incident reconciler started", "sla", cfg.ConnectorIncidentSLA, "interval", cfg.ConnectorIncidentCheckInterval)
+		go reconciler.Run(ctx, cfg.ConnectorIncidentCheckInterval)
+	}
+
+	if cfg.SyncRunRetention > 0 {
+		slog.Info("sync run retention started", "keep", cfg.SyncRunRetention, "interval", sync.SyncRunRetentionInterval)
+		go sync.RunSyncRunRetention(ctx, gen.New(pool), cfg.SyncRunRetention, sync.SyncRunRetentionInterval)
+	}
+
+	if cfg.InventorySnapshotsEnabled {
+		slog.Info("inventory snapshots started", "interval", sync.InventorySnapshotInterval)
+		go sync.RunInventorySnapshots(ctx, gen.New(pool), sync.InventorySnapshotInterval)
+	}
+
 	slog.Info("sync worker started", "interval", cfg.SyncInterval)
 	triggers := make(chan sync.TriggerRequest, 1)
 	go func() {
@@ -134,3 +159,16 @@ func runWorker() error {
 	}
 	return nil
 }
+
+// newConnectorIncidentSink returns the configured stale-connector incident sink, or nil when
+// incidents are not enabled.
+func newConnectorIncidentSink(cfg config.Config) (incidents.Sink, error) {
+	switch {
+	case cfg.ConnectorIncidentPagerDutyRoutingKey != "":
+		return incidents.NewPagerDutySink(cfg.ConnectorIncidentPagerDutyRoutingKey)
+	case cfg.ConnectorIncidentWebhookURL != "":
+		return incidents.NewWebhookSink(cfg.ConnectorIncidentWebhookURL)
+	default:
+		return nil, nil
+	}
+}
@@ -63,6 +63,7 @@ func runWorkerDiscovery() error {
 	dbRunner := sync.NewDBRunner(pool, reg)
 	dbRunner.SetReporter(&sync.LogReporter{})
 	dbRunner.SetLockManager(locks)
+	dbRunner.SetRunLimiter(sync.NewRunLimiter(cfg.SyncMaxConcurrentRuns))
 	dbRunner.SetRunMode(registry.RunModeDiscovery)
 	dbRunner.SetGlobalEvalMode(cfg.GlobalEvalMode)
 	backoffMax := cfg.SyncFailureBackoffMax
@@ -75,9 +76,11 @@ func runWorkerDiscovery() error {
 			"entra_discovery":            cfg.SyncDiscoveryInterval,
 			"google_workspace_discovery": cfg.SyncDiscoveryInterval,
 		},
-		FailureBackoffBase:   cfg.SyncDiscoveryInterval,
-		FailureBackoffMax:    backoffMax,
-		RecentFinishedRunCap: 10,
+		FailureBackoffBase:     cfg.SyncDiscoveryInterval,
+		FailureBackoffMax:      backoffMax,
+		RecentFinishedRunCap:   10,
+		APIRequestBudgetByKind: cfg.SyncAPIRequestBudgets,
+		APIRequestBudgetWindow: cfg.SyncAPIRequestBudgetWindow,
 	})
 	runner := sync.NewBlockingRunOnceLockRunnerWithScope(locks, dbRunner, sync.RunOnceScopeNameDiscovery)
 
@@ -0,0 +1,3 @@
+-- Non-fatal problems recorded during a sync run, kept apart from the fatal message/error_kind.
+ALTER TABLE sync_runs
+  ADD COLUMN IF NOT EXISTS warnings JSONB NOT NULL DEFAULT '[]'::jsonb;
@@ -0,0 +1,14 @@
+-- When a credential first appeared in inventory; set on insert and never updated.
+ALTER TABLE credential_artifacts
+  ADD COLUMN IF NOT EXISTS first_seen_at TIMESTAMPTZ;
+
+UPDATE credential_artifacts
+SET first_seen_at = created_at
+WHERE first_seen_at IS NULL;
+
+ALTER TABLE credential_artifacts
+  ALTER COLUMN first_seen_at SET DEFAULT now(),
+  ALTER COLUMN first_seen_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_credential_artifacts_first_seen_at
+  ON credential_artifacts (first_seen_at DESC);
@@ -0,0 +1,3 @@
+INSERT INTO connector_configs (kind, enabled, config)
+VALUES ('bitbucket', false, '{}'::jsonb)
+ON CONFLICT (kind) DO NOTHING;
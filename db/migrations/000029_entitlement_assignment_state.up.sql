@@ -0,0 +1,4 @@
+-- How a role is held where the source distinguishes it (e.g. Entra PIM): permanent, active
+-- (time-bound or activated), or eligible. Empty for sources without the concept.
+ALTER TABLE entitlements
+  ADD COLUMN IF NOT EXISTS assignment_state TEXT NOT NULL DEFAULT '';
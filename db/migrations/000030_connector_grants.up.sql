@@ -0,0 +1,12 @@
+-- Scopes/permissions a connector's credentials actually hold, as reported by the source API on
+-- the latest run, alongside what the connector needs for a complete sync.
+CREATE TABLE IF NOT EXISTS connector_grants (
+  source_kind TEXT NOT NULL,
+  source_name TEXT NOT NULL,
+  granted TEXT[] NOT NULL DEFAULT '{}'::text[],
+  required TEXT[] NOT NULL DEFAULT '{}'::text[],
+  missing TEXT[] NOT NULL DEFAULT '{}'::text[],
+  last_run_id BIGINT REFERENCES sync_runs(id) ON DELETE SET NULL,
+  checked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (source_kind, source_name)
+);
@@ -0,0 +1,4 @@
+-- The run that observed an entitlement before its latest observation. Together with
+-- last_observed_run_id and expired_run_id this lets the last two runs of a source be diffed.
+ALTER TABLE entitlements
+  ADD COLUMN IF NOT EXISTS previous_observed_run_id BIGINT REFERENCES sync_runs(id);
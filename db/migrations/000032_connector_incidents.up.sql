@@ -0,0 +1,16 @@
+-- Incidents opened with an external sink (PagerDuty, webhook) when a connector misses its
+-- freshness SLA. At most one incident per connector is open at a time; dedup_key is the key
+-- the sink uses to correlate trigger and resolve events.
+CREATE TABLE IF NOT EXISTS connector_incidents (
+  id BIGSERIAL PRIMARY KEY,
+  source_kind TEXT NOT NULL,
+  source_name TEXT NOT NULL,
+  dedup_key TEXT NOT NULL,
+  summary TEXT NOT NULL DEFAULT '',
+  opened_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  resolved_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS connector_incidents_open_source_idx
+  ON connector_incidents (source_kind, source_name)
+  WHERE resolved_at IS NULL;
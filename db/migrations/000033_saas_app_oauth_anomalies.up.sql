@@ -0,0 +1,14 @@
+-- Newly-discovered SaaS apps whose early OAuth grants look like a malicious-consent campaign:
+-- many distinct users granting within a short window of first sight, or high-risk scopes on the
+-- very first grants. Rows are kept once detected so the finding survives the app ageing out.
+CREATE TABLE IF NOT EXISTS saas_app_oauth_anomalies (
+  saas_app_id BIGINT PRIMARY KEY REFERENCES saas_apps(id) ON DELETE CASCADE,
+  reasons TEXT[] NOT NULL DEFAULT '{}'::text[],
+  first_grant_at TIMESTAMPTZ NOT NULL,
+  actors_in_window BIGINT NOT NULL DEFAULT 0,
+  high_risk_scopes TEXT[] NOT NULL DEFAULT '{}'::text[],
+  detected_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_saas_app_oauth_anomalies_detected_at ON saas_app_oauth_anomalies (detected_at DESC);
@@ -0,0 +1,18 @@
+-- Operator-assigned key/value tags on credentials, used to route ownership. Tags are keyed by
+-- the credential's natural identity rather than credential_artifacts.id so they survive a
+-- re-ingest that recreates the artifact row.
+CREATE TABLE IF NOT EXISTS credential_tags (
+  id BIGSERIAL PRIMARY KEY,
+  source_kind TEXT NOT NULL,
+  source_name TEXT NOT NULL,
+  credential_kind TEXT NOT NULL,
+  external_id TEXT NOT NULL,
+  tag_key TEXT NOT NULL,
+  tag_value TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (source_kind, source_name, credential_kind, external_id, tag_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_credential_tags_key_value
+  ON credential_tags (tag_key, tag_value);
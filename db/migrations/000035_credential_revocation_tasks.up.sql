@@ -0,0 +1,27 @@
+-- Revocation requests raised from the credential page. Connectors that can revoke through the
+-- provider API record a completed 'api' task; everything else (or a failed API call) leaves an
+-- open 'manual' task for follow-up. Keyed by the credential's natural identity like
+-- credential_tags.
+CREATE TABLE IF NOT EXISTS credential_revocation_tasks (
+  id BIGSERIAL PRIMARY KEY,
+  source_kind TEXT NOT NULL,
+  source_name TEXT NOT NULL,
+  credential_kind TEXT NOT NULL,
+  external_id TEXT NOT NULL,
+  method TEXT NOT NULL CHECK (method IN ('api', 'manual')),
+  status TEXT NOT NULL CHECK (status IN ('open', 'completed')),
+  reason TEXT NOT NULL DEFAULT '',
+  error_message TEXT NOT NULL DEFAULT '',
+  requested_by_auth_user_id BIGINT REFERENCES auth_users(id) ON DELETE SET NULL,
+  resolved_by_auth_user_id BIGINT REFERENCES auth_users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_credential_revocation_tasks_credential
+  ON credential_revocation_tasks (source_kind, source_name, credential_kind, external_id, created_at DESC);
+
+-- At most one open task per credential; repeated requests refresh it.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_credential_revocation_tasks_open
+  ON credential_revocation_tasks (source_kind, source_name, credential_kind, external_id)
+  WHERE status = 'open';
@@ -0,0 +1,31 @@
+-- Tenant organizations for hosted/MSP deployments. Inventory rows are already keyed by their
+-- connector source, so a tenant owns sources (org_sources) rather than individual rows; sources
+-- with no assignment belong to the default org, which keeps single-tenant installs unchanged.
+CREATE TABLE IF NOT EXISTS orgs (
+  id BIGSERIAL PRIMARY KEY,
+  slug TEXT NOT NULL UNIQUE,
+  name TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT orgs_slug_nonempty CHECK (slug <> '')
+);
+
+INSERT INTO orgs (id, slug, name)
+VALUES (1, 'default', 'Default')
+ON CONFLICT (id) DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('orgs', 'id'), GREATEST((SELECT max(id) FROM orgs), 1));
+
+CREATE TABLE IF NOT EXISTS org_sources (
+  source_kind TEXT NOT NULL,
+  source_name TEXT NOT NULL,
+  org_id BIGINT NOT NULL REFERENCES orgs(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (source_kind, source_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_sources_org ON org_sources (org_id);
+
+ALTER TABLE auth_users
+  ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES orgs(id);
+
+CREATE INDEX IF NOT EXISTS idx_auth_users_org ON auth_users (org_id);
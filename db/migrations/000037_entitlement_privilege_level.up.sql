@@ -0,0 +1,8 @@
+-- Canonical, cross-source privilege of an entitlement derived from its provider-native
+-- permission: 0 unknown, 1 read, 2 write, 3 admin, 4 owner. See registry.PrivilegeLevel.
+ALTER TABLE entitlements
+  ADD COLUMN IF NOT EXISTS privilege_level SMALLINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_entitlements_active_privilege_level
+  ON entitlements (privilege_level)
+  WHERE expired_at IS NULL AND privilege_level >= 3;
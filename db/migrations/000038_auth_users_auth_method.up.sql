@@ -0,0 +1,10 @@
+-- How a UI user signs in. 'oidc' users are provisioned by SSO, have no password, and get their
+-- role from IdP groups on every login; 'password' users are local accounts.
+ALTER TABLE auth_users
+  ADD COLUMN IF NOT EXISTS auth_method TEXT NOT NULL DEFAULT 'password';
+
+ALTER TABLE auth_users
+  DROP CONSTRAINT IF EXISTS auth_users_auth_method_check;
+
+ALTER TABLE auth_users
+  ADD CONSTRAINT auth_users_auth_method_check CHECK (auth_method IN ('password', 'oidc'));
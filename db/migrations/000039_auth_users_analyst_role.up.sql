@@ -0,0 +1,7 @@
+-- Analysts sit between viewers and admins: they can annotate inventory (credential tags, finding
+-- attestations) but cannot run syncs, revoke credentials, or change configuration.
+ALTER TABLE auth_users
+  DROP CONSTRAINT IF EXISTS auth_users_role_check;
+
+ALTER TABLE auth_users
+  ADD CONSTRAINT auth_users_role_check CHECK (role IN ('admin', 'analyst', 'viewer'));
@@ -0,0 +1,23 @@
+-- Operator actions taken in the app (connector changes, sync triggers, bindings, revocations,
+-- finding overrides, user management). Actor fields are copied rather than referenced so entries
+-- survive user deletion.
+CREATE TABLE IF NOT EXISTS app_audit_log (
+  id BIGSERIAL PRIMARY KEY,
+  occurred_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  org_id BIGINT NOT NULL DEFAULT 1,
+  actor_auth_user_id BIGINT,
+  actor_email TEXT NOT NULL DEFAULT '',
+  actor_role TEXT NOT NULL DEFAULT '',
+  action TEXT NOT NULL,
+  method TEXT NOT NULL,
+  route TEXT NOT NULL,
+  target TEXT NOT NULL DEFAULT '',
+  status_code INTEGER NOT NULL,
+  before_json JSONB,
+  after_json JSONB,
+  request_id TEXT NOT NULL DEFAULT '',
+  remote_ip TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_app_audit_log_org_occurred ON app_audit_log (org_id, occurred_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_app_audit_log_action ON app_audit_log (action, occurred_at DESC);
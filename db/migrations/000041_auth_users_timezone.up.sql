@@ -0,0 +1,3 @@
+-- IANA zone used to render dates for a UI user. '' follows the deployment's DISPLAY_TIMEZONE.
+ALTER TABLE auth_users
+  ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT '';
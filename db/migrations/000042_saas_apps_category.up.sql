@@ -0,0 +1,8 @@
+-- Category of a discovered SaaS app (e.g. "AI/LLM", "File sharing") from the vendor catalog.
+-- '' means the app is not in the catalog.
+ALTER TABLE saas_apps
+  ADD COLUMN IF NOT EXISTS category TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_saas_apps_category
+  ON saas_apps (category)
+  WHERE category <> '';
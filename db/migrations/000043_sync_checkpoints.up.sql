@@ -0,0 +1,11 @@
+-- Per-item progress of long enumeration stages so a failed sync can resume from the items it
+-- already completed instead of re-listing everything.
+CREATE TABLE IF NOT EXISTS sync_checkpoints (
+  source_kind TEXT NOT NULL,
+  source_name TEXT NOT NULL,
+  stage TEXT NOT NULL,
+  item_key TEXT NOT NULL,
+  payload_json JSONB NOT NULL DEFAULT '{}'::jsonb,
+  checkpointed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (source_kind, source_name, stage, item_key)
+);
@@ -0,0 +1,24 @@
+-- Suggested merges of identities that probably belong to the same person. Each pair is stored
+-- once, ordered by id; survivor_identity_id says which side keeps the merged accounts. Accepting a
+-- suggestion deletes the duplicate identity (and with it the suggestion); dismissed suggestions
+-- are kept so the pair is not suggested again.
+CREATE TABLE IF NOT EXISTS identity_merge_suggestions (
+  id BIGSERIAL PRIMARY KEY,
+  low_identity_id BIGINT NOT NULL REFERENCES identities(id) ON DELETE CASCADE,
+  high_identity_id BIGINT NOT NULL REFERENCES identities(id) ON DELETE CASCADE,
+  survivor_identity_id BIGINT NOT NULL REFERENCES identities(id) ON DELETE CASCADE,
+  score REAL NOT NULL,
+  reasons TEXT[] NOT NULL DEFAULT '{}',
+  status TEXT NOT NULL DEFAULT 'open',
+  decided_by_auth_user_id BIGINT REFERENCES auth_users(id) ON DELETE SET NULL,
+  decided_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT identity_merge_suggestions_pair_order CHECK (low_identity_id < high_identity_id),
+  CONSTRAINT identity_merge_suggestions_status_check CHECK (status IN ('open', 'dismissed')),
+  UNIQUE (low_identity_id, high_identity_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_identity_merge_suggestions_open
+  ON identity_merge_suggestions (score DESC, id)
+  WHERE status = 'open';
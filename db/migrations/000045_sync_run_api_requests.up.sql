@@ -0,0 +1,3 @@
+-- Provider API requests made by a sync run, used to keep rate-limited tenants within budget.
+ALTER TABLE sync_runs
+  ADD COLUMN IF NOT EXISTS api_request_count BIGINT NOT NULL DEFAULT 0;
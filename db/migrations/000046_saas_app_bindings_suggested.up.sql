@@ -0,0 +1,7 @@
+-- Auto-bind candidates below the configured confidence threshold are kept as suggestions
+-- for an admin to confirm instead of being bound.
+ALTER TABLE saas_app_bindings
+  DROP CONSTRAINT IF EXISTS saas_app_bindings_binding_source_check;
+
+ALTER TABLE saas_app_bindings
+  ADD CONSTRAINT saas_app_bindings_binding_source_check CHECK (binding_source IN ('auto', 'manual', 'suggested'));
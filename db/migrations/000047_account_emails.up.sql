@@ -0,0 +1,11 @@
+-- Secondary email addresses an account is known by besides accounts.email (Entra other mails
+-- and proxy addresses, a GitHub profile email replaced by the SCIM one), so identity matching
+-- can link accounts whose primary emails differ.
+CREATE TABLE IF NOT EXISTS account_emails (
+  account_id BIGINT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+  email TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (account_id, email)
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_emails_email ON account_emails (email);
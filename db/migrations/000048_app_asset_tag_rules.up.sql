@@ -0,0 +1,31 @@
+-- Operator-defined rules that tag app assets on every sync. A rule tags an asset when all of
+-- its conditions match; a condition only compares one asset field to a literal, see
+-- registry.AssetTagCondition.
+CREATE TABLE IF NOT EXISTS app_asset_tag_rules (
+  id BIGSERIAL PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE,
+  conditions JSONB NOT NULL DEFAULT '[]'::jsonb,
+  tag_key TEXT NOT NULL,
+  tag_value TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Tags applied by app_asset_tag_rules. Like credential_tags they are keyed by the asset's
+-- natural identity, and each finalized sync replaces its source's rows.
+CREATE TABLE IF NOT EXISTS app_asset_tags (
+  id BIGSERIAL PRIMARY KEY,
+  source_kind TEXT NOT NULL,
+  source_name TEXT NOT NULL,
+  asset_kind TEXT NOT NULL,
+  external_id TEXT NOT NULL,
+  tag_key TEXT NOT NULL,
+  tag_value TEXT NOT NULL DEFAULT '',
+  rule_id BIGINT NOT NULL REFERENCES app_asset_tag_rules(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (source_kind, source_name, asset_kind, external_id, tag_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_app_asset_tags_key_value
+  ON app_asset_tags (tag_key, tag_value);
@@ -0,0 +1,4 @@
+-- Breakdown of the provider API requests made by a sync run: bytes, calls per host, and error
+-- classes, next to the total in api_request_count. See httpclient.RequestStats.
+ALTER TABLE sync_runs
+  ADD COLUMN IF NOT EXISTS api_stats JSONB NOT NULL DEFAULT '{}'::jsonb;
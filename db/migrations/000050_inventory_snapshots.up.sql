@@ -0,0 +1,13 @@
+-- Daily point-in-time copies of the credential and entitlement inventory, kept as audit
+-- evidence of the state as of a date while the live tables keep being upserted. Rows are
+-- append-only: one per UTC day and kind, holding the gzip-compressed JSON rows, see
+-- sync.CaptureInventorySnapshot.
+CREATE TABLE IF NOT EXISTS inventory_snapshots (
+  id BIGSERIAL PRIMARY KEY,
+  snapshot_date DATE NOT NULL,
+  kind TEXT NOT NULL,
+  row_count INTEGER NOT NULL,
+  payload BYTEA NOT NULL,
+  captured_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (snapshot_date, kind)
+);
@@ -0,0 +1,16 @@
+-- MFA factors enrolled by each Okta user. Each full sync replaces the factors of every user whose
+-- factors it could list; strength is the connector's classification, see okta.FactorStrength.
+CREATE TABLE IF NOT EXISTS okta_user_factors (
+  id BIGSERIAL PRIMARY KEY,
+  okta_user_account_id BIGINT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+  external_id TEXT NOT NULL,
+  factor_type TEXT NOT NULL,
+  provider TEXT NOT NULL DEFAULT '',
+  vendor_name TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL DEFAULT '',
+  strength TEXT NOT NULL DEFAULT '',
+  raw_json JSONB NOT NULL DEFAULT '{}'::jsonb,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (okta_user_account_id, external_id)
+);
@@ -0,0 +1,50 @@
+-- Heuristic risk level of a credential, shared by the credential list filter, the risk sort and
+-- the inventory metrics. Keep in step with credentialHeuristicRiskLevel in the handlers package.
+CREATE OR REPLACE FUNCTION credential_heuristic_risk_level(
+  expires_at_source TIMESTAMPTZ,
+  last_used_at_source TIMESTAMPTZ,
+  status TEXT,
+  credential_kind TEXT,
+  created_by_external_id TEXT,
+  approved_by_external_id TEXT
+) RETURNS TEXT
+LANGUAGE sql
+STABLE
+AS $$
+  SELECT CASE
+    WHEN expires_at_source IS NOT NULL
+      AND expires_at_source < now()
+      AND lower(COALESCE(NULLIF(trim(status), ''), 'active')) IN ('active', 'approved', 'pending_approval')
+      THEN 'critical'
+    WHEN expires_at_source IS NOT NULL
+      AND expires_at_source < now()
+      THEN 'high'
+    WHEN lower(credential_kind) IN ('entra_client_secret', 'github_deploy_key', 'github_pat_request', 'github_pat_fine_grained')
+      AND trim(created_by_external_id) = ''
+      AND trim(approved_by_external_id) = ''
+      THEN 'critical'
+    WHEN expires_at_source IS NOT NULL
+      AND expires_at_source >= now()
+      AND expires_at_source <= now() + make_interval(days => 7)
+      THEN 'high'
+    WHEN expires_at_source IS NULL
+      AND lower(credential_kind) IN ('vault_approle_secret_id', 'vault_token')
+      AND lower(COALESCE(NULLIF(trim(status), ''), 'active')) IN ('active', 'approved', 'pending_approval')
+      THEN 'high'
+    WHEN expires_at_source IS NULL
+      AND last_used_at_source IS NULL
+      AND lower(credential_kind) IN ('bitbucket_app_password')
+      AND lower(COALESCE(NULLIF(trim(status), ''), 'active')) IN ('active', 'approved', 'pending_approval')
+      THEN 'high'
+    WHEN trim(created_by_external_id) = ''
+      THEN 'high'
+    WHEN last_used_at_source IS NOT NULL
+      AND last_used_at_source <= now() - make_interval(days => 90)
+      THEN 'high'
+    WHEN expires_at_source IS NOT NULL
+      AND expires_at_source >= now()
+      AND expires_at_source <= now() + make_interval(days => 30)
+      THEN 'medium'
+    ELSE 'low'
+  END
+$$;
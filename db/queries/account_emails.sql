@@ -0,0 +1,41 @@
+-- name: ReplaceAccountSecondaryEmailsBySource :exec
+-- Sets the secondary emails of the source's accounts named in account_external_ids to the
+-- (external_ids[i], emails[i]) pairs; accounts without pairs lose theirs. An account's primary
+-- email is never stored as a secondary one.
+WITH input AS (
+  SELECT
+    (sqlc.arg(external_ids)::text[])[i] AS external_id,
+    lower(trim((sqlc.arg(emails)::text[])[i])) AS email
+  FROM generate_subscripts(sqlc.arg(external_ids)::text[], 1) AS s(i)
+),
+targets AS (
+  SELECT a.id, a.external_id, a.email AS primary_email
+  FROM accounts a
+  WHERE a.source_kind = sqlc.arg(source_kind)::text
+    AND a.source_name = sqlc.arg(source_name)::text
+    AND a.external_id = ANY(sqlc.arg(account_external_ids)::text[])
+),
+deleted AS (
+  DELETE FROM account_emails ae
+  USING targets t
+  WHERE ae.account_id = t.id
+    AND NOT EXISTS (
+      SELECT 1
+      FROM input
+      WHERE input.external_id = t.external_id
+        AND input.email = ae.email
+    )
+)
+INSERT INTO account_emails (account_id, email)
+SELECT DISTINCT t.id, input.email
+FROM input
+JOIN targets t ON t.external_id = input.external_id
+WHERE input.email <> ''
+  AND input.email <> lower(trim(t.primary_email))
+ON CONFLICT (account_id, email) DO NOTHING;
+
+-- name: ListAccountSecondaryEmails :many
+SELECT email
+FROM account_emails
+WHERE account_id = sqlc.arg(account_id)::bigint
+ORDER BY email;
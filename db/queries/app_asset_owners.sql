@@ -90,6 +90,7 @@ WHERE aa.id = aao.app_asset_id
   AND aao.seen_in_run_id = sqlc.arg(last_observed_run_id)::bigint;
 
 -- name: ExpireAppAssetOwnersNotSeenInRunBySource :execrows
+-- Owners of the held (asset kind, external id) pairs are skipped.
 UPDATE app_asset_owners aao
 SET
   expired_at = now(),
@@ -103,4 +104,11 @@ WHERE aa.id = aao.app_asset_id
   AND (
     aao.seen_in_run_id <> sqlc.arg(expired_run_id)::bigint
     OR aao.seen_in_run_id IS NULL
+  )
+  AND NOT EXISTS (
+    SELECT 1
+    FROM unnest(sqlc.arg(held_asset_kinds)::text[]) WITH ORDINALITY AS k(kind, ord)
+    JOIN unnest(sqlc.arg(held_asset_external_ids)::text[]) WITH ORDINALITY AS x(external_id, ord) USING (ord)
+    WHERE k.kind = aa.asset_kind
+      AND x.external_id = aa.external_id
   );
@@ -0,0 +1,79 @@
+-- name: CreateAppAssetTagRule :one
+INSERT INTO app_asset_tag_rules (name, conditions, tag_key, tag_value)
+VALUES (
+  sqlc.arg(name)::text,
+  sqlc.arg(conditions)::jsonb,
+  sqlc.arg(tag_key)::text,
+  sqlc.arg(tag_value)::text
+)
+RETURNING *;
+
+-- name: DeleteAppAssetTagRuleByName :execrows
+DELETE FROM app_asset_tag_rules
+WHERE name = sqlc.arg(name)::text;
+
+-- name: ListAppAssetTagRules :many
+SELECT *
+FROM app_asset_tag_rules
+ORDER BY id ASC;
+
+-- name: ListAppAssetTagTargetsBySource :many
+-- The fields tag rule conditions compare, for the source's current assets.
+SELECT aa.asset_kind, aa.external_id, aa.parent_external_id, aa.display_name, aa.status
+FROM app_assets aa
+WHERE aa.source_kind = sqlc.arg(source_kind)::text
+  AND aa.source_name = sqlc.arg(source_name)::text
+  AND aa.expired_at IS NULL
+  AND aa.last_observed_run_id IS NOT NULL
+ORDER BY aa.asset_kind, aa.external_id;
+
+-- name: ReplaceAppAssetTagsBySource :execrows
+-- Sets the source's rule-applied tags to the given rows: tags not among them are deleted and
+-- the rest are inserted or updated. Returns the number of tags added or changed.
+WITH input AS (
+  SELECT DISTINCT ON (asset_kind, external_id, tag_key)
+    asset_kind,
+    external_id,
+    tag_key,
+    tag_value,
+    rule_id
+  FROM (
+    SELECT
+      i,
+      (sqlc.arg(asset_kinds)::text[])[i] AS asset_kind,
+      (sqlc.arg(external_ids)::text[])[i] AS external_id,
+      (sqlc.arg(tag_keys)::text[])[i] AS tag_key,
+      (sqlc.arg(tag_values)::text[])[i] AS tag_value,
+      (sqlc.arg(rule_ids)::bigint[])[i] AS rule_id
+    FROM generate_subscripts(sqlc.arg(external_ids)::text[], 1) AS s(i)
+  ) rows
+  ORDER BY asset_kind, external_id, tag_key, i ASC
+),
+deleted AS (
+  DELETE FROM app_asset_tags aat
+  WHERE aat.source_kind = sqlc.arg(source_kind)::text
+    AND aat.source_name = sqlc.arg(source_name)::text
+    AND NOT EXISTS (
+      SELECT 1
+      FROM input
+      WHERE input.asset_kind = aat.asset_kind
+        AND input.external_id = aat.external_id
+        AND input.tag_key = aat.tag_key
+    )
+)
+INSERT INTO app_asset_tags (source_kind, source_name, asset_kind, external_id, tag_key, tag_value, rule_id)
+SELECT
+  sqlc.arg(source_kind)::text,
+  sqlc.arg(source_name)::text,
+  input.asset_kind,
+  input.external_id,
+  input.tag_key,
+  input.tag_value,
+  input.rule_id
+FROM input
+ON CONFLICT (source_kind, source_name, asset_kind, external_id, tag_key) DO UPDATE SET
+  tag_value = EXCLUDED.tag_value,
+  rule_id = EXCLUDED.rule_id,
+  updated_at = now()
+WHERE app_asset_tags.tag_value IS DISTINCT FROM EXCLUDED.tag_value
+   OR app_asset_tags.rule_id IS DISTINCT FROM EXCLUDED.rule_id;
@@ -78,13 +78,109 @@ WHERE
     sqlc.arg(asset_kind)::text = ''
     OR aa.asset_kind = sqlc.arg(asset_kind)::text
   )
+  AND (
+    NOT sqlc.arg(owner_filter)::bool
+    OR EXISTS (
+      SELECT 1
+      FROM app_asset_owners aao
+      WHERE aao.app_asset_id = aa.id
+        AND aao.expired_at IS NULL
+        AND lower(trim(aao.owner_external_id)) = ANY(sqlc.arg(owner_external_ids)::text[])
+    )
+  )
   AND (
     sqlc.arg(query)::text = ''
     OR aa.display_name ILIKE ('%' || sqlc.arg(query)::text || '%')
     OR aa.external_id ILIKE ('%' || sqlc.arg(query)::text || '%')
     OR aa.parent_external_id ILIKE ('%' || sqlc.arg(query)::text || '%')
+  )
+  AND (
+    sqlc.arg(tag_key)::text = ''
+    OR EXISTS (
+      SELECT 1
+      FROM app_asset_tags aat
+      WHERE aat.source_kind = aa.source_kind
+        AND aat.source_name = aa.source_name
+        AND aat.asset_kind = aa.asset_kind
+        AND aat.external_id = aa.external_id
+        AND aat.tag_key = sqlc.arg(tag_key)::text
+        AND (sqlc.arg(tag_value)::text = '' OR aat.tag_value = sqlc.arg(tag_value)::text)
+    )
   );
 
+-- name: CountAppAssetsGroupedBySource :many
+SELECT aa.source_kind, aa.source_name, count(*) AS asset_count
+FROM app_assets aa
+WHERE
+  aa.expired_at IS NULL
+  AND aa.last_observed_run_id IS NOT NULL
+GROUP BY aa.source_kind, aa.source_name
+ORDER BY aa.source_kind, aa.source_name;
+
+-- name: CountAppAssetsGroupedBySourceKind :many
+SELECT aa.source_kind, count(*) AS asset_count
+FROM app_assets aa
+WHERE
+  aa.expired_at IS NULL
+  AND aa.last_observed_run_id IS NOT NULL
+GROUP BY aa.source_kind
+ORDER BY aa.source_kind;
+
+-- name: ListAppAssetKindCountsBySourceSet :many
+WITH requested AS (
+  SELECT k.kind AS source_kind, n.name AS source_name
+  FROM unnest(sqlc.arg(source_kinds)::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest(sqlc.arg(source_names)::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+),
+owner_accounts AS (
+  SELECT k.kind AS source_kind, n.name AS source_name, e.external_id
+  FROM unnest(sqlc.arg(owner_source_kinds)::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest(sqlc.arg(owner_source_names)::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+  JOIN unnest(sqlc.arg(owner_external_ids)::text[]) WITH ORDINALITY AS e(external_id, ord) USING (ord)
+)
+SELECT aa.asset_kind, count(*) AS asset_count
+FROM app_assets aa
+JOIN requested r
+  ON r.source_kind = aa.source_kind
+ AND r.source_name = aa.source_name
+WHERE
+  aa.expired_at IS NULL
+  AND aa.last_observed_run_id IS NOT NULL
+  AND (
+    NOT sqlc.arg(owner_filter)::bool
+    OR EXISTS (
+      SELECT 1
+      FROM app_asset_owners aao
+      JOIN owner_accounts oa
+        ON oa.source_kind = aa.source_kind
+       AND oa.source_name = aa.source_name
+       AND oa.external_id = lower(trim(aao.owner_external_id))
+      WHERE aao.app_asset_id = aa.id
+        AND aao.expired_at IS NULL
+    )
+  )
+  AND (
+    sqlc.arg(query)::text = ''
+    OR aa.display_name ILIKE ('%' || sqlc.arg(query)::text || '%')
+    OR aa.external_id ILIKE ('%' || sqlc.arg(query)::text || '%')
+    OR aa.parent_external_id ILIKE ('%' || sqlc.arg(query)::text || '%')
+  )
+  AND (
+    sqlc.arg(tag_key)::text = ''
+    OR EXISTS (
+      SELECT 1
+      FROM app_asset_tags aat
+      WHERE aat.source_kind = aa.source_kind
+        AND aat.source_name = aa.source_name
+        AND aat.asset_kind = aa.asset_kind
+        AND aat.external_id = aa.external_id
+        AND aat.tag_key = sqlc.arg(tag_key)::text
+        AND (sqlc.arg(tag_value)::text = '' OR aat.tag_value = sqlc.arg(tag_value)::text)
+    )
+  )
+GROUP BY aa.asset_kind
+ORDER BY asset_count DESC, aa.asset_kind;
+
 -- name: ListAppAssetsPageBySourceAndQueryAndKind :many
 SELECT aa.*
 FROM app_assets aa
@@ -97,13 +193,40 @@ WHERE
     sqlc.arg(asset_kind)::text = ''
     OR aa.asset_kind = sqlc.arg(asset_kind)::text
   )
+  AND (
+    NOT sqlc.arg(owner_filter)::bool
+    OR EXISTS (
+      SELECT 1
+      FROM app_asset_owners aao
+      WHERE aao.app_asset_id = aa.id
+        AND aao.expired_at IS NULL
+        AND lower(trim(aao.owner_external_id)) = ANY(sqlc.arg(owner_external_ids)::text[])
+    )
+  )
   AND (
     sqlc.arg(query)::text = ''
     OR aa.display_name ILIKE ('%' || sqlc.arg(query)::text || '%')
     OR aa.external_id ILIKE ('%' || sqlc.arg(query)::text || '%')
     OR aa.parent_external_id ILIKE ('%' || sqlc.arg(query)::text || '%')
   )
+  AND (
+    sqlc.arg(tag_key)::text = ''
+    OR EXISTS (
+      SELECT 1
+      FROM app_asset_tags aat
+      WHERE aat.source_kind = aa.source_kind
+        AND aat.source_name = aa.source_name
+        AND aat.asset_kind = aa.asset_kind
+        AND aat.external_id = aa.external_id
+        AND aat.tag_key = sqlc.arg(tag_key)::text
+        AND (sqlc.arg(tag_value)::text = '' OR aat.tag_value = sqlc.arg(tag_value)::text)
+    )
+  )
 ORDER BY
+  CASE WHEN sqlc.arg(sort)::text = 'name_desc' THEN lower(COALESCE(NULLIF(trim(aa.display_name), ''), aa.external_id)) END DESC,
+  CASE WHEN sqlc.arg(sort)::text = 'kind_asc' THEN aa.asset_kind END ASC,
+  CASE WHEN sqlc.arg(sort)::text = 'updated_desc' THEN aa.updated_at_source END DESC NULLS LAST,
+  CASE WHEN sqlc.arg(sort)::text = 'updated_asc' THEN aa.updated_at_source END ASC NULLS FIRST,
   lower(COALESCE(NULLIF(trim(aa.display_name), ''), aa.external_id)) ASC,
   aa.id ASC
 LIMIT sqlc.arg(page_limit)::int
@@ -150,3 +273,32 @@ WHERE source_kind = sqlc.arg(source_kind)::text
     seen_in_run_id <> sqlc.arg(expired_run_id)::bigint
     OR seen_in_run_id IS NULL
   );
+
+-- name: ListAppAssetsOwnedByAccounts :many
+WITH owner_accounts AS (
+  SELECT k.kind AS source_kind, n.name AS source_name, e.external_id
+  FROM unnest(sqlc.arg(source_kinds)::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest(sqlc.arg(source_names)::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+  JOIN unnest(sqlc.arg(external_ids)::text[]) WITH ORDINALITY AS e(external_id, ord) USING (ord)
+)
+SELECT aa.*
+FROM app_assets aa
+WHERE
+  aa.expired_at IS NULL
+  AND aa.last_observed_run_id IS NOT NULL
+  AND EXISTS (
+    SELECT 1
+    FROM app_asset_owners aao
+    JOIN owner_accounts oa
+      ON oa.source_kind = aa.source_kind
+     AND oa.source_name = aa.source_name
+     AND oa.external_id = lower(trim(aao.owner_external_id))
+    WHERE aao.app_asset_id = aa.id
+      AND aao.expired_at IS NULL
+  )
+ORDER BY
+  aa.source_kind,
+  aa.source_name,
+  lower(COALESCE(NULLIF(trim(aa.display_name), ''), aa.external_id)) ASC,
+  aa.id ASC
+LIMIT sqlc.arg(row_limit)::int;
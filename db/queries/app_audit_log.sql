@@ -0,0 +1,54 @@
+-- name: InsertAppAuditLogEntry :exec
+INSERT INTO app_audit_log (
+  org_id,
+  actor_auth_user_id,
+  actor_email,
+  actor_role,
+  action,
+  method,
+  route,
+  target,
+  status_code,
+  before_json,
+  after_json,
+  request_id,
+  remote_ip
+)
+VALUES (
+  sqlc.arg(org_id)::bigint,
+  sqlc.narg(actor_auth_user_id)::bigint,
+  sqlc.arg(actor_email)::text,
+  sqlc.arg(actor_role)::text,
+  sqlc.arg(action)::text,
+  sqlc.arg(method)::text,
+  sqlc.arg(route)::text,
+  sqlc.arg(target)::text,
+  sqlc.arg(status_code)::integer,
+  sqlc.narg(before_json)::jsonb,
+  sqlc.narg(after_json)::jsonb,
+  sqlc.arg(request_id)::text,
+  sqlc.arg(remote_ip)::text
+);
+
+-- name: CountAppAuditLogEntries :one
+SELECT count(*)
+FROM app_audit_log
+WHERE org_id = sqlc.arg(org_id)::bigint
+  AND (sqlc.arg(action)::text = '' OR action = sqlc.arg(action)::text)
+  AND (sqlc.arg(actor_email)::text = '' OR actor_email = sqlc.arg(actor_email)::text);
+
+-- name: ListAppAuditLogEntries :many
+SELECT *
+FROM app_audit_log
+WHERE org_id = sqlc.arg(org_id)::bigint
+  AND (sqlc.arg(action)::text = '' OR action = sqlc.arg(action)::text)
+  AND (sqlc.arg(actor_email)::text = '' OR actor_email = sqlc.arg(actor_email)::text)
+ORDER BY occurred_at DESC, id DESC
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
+
+-- name: ListAppAuditLogActions :many
+SELECT DISTINCT action
+FROM app_audit_log
+WHERE org_id = sqlc.arg(org_id)::bigint
+ORDER BY action;
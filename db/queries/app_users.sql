@@ -166,6 +166,11 @@ WHERE
     OR au.external_id ILIKE ('%' || sqlc.arg(query)::text || '%')
     OR au.email ILIKE ('%' || sqlc.arg(query)::text || '%')
     OR au.display_name ILIKE ('%' || sqlc.arg(query)::text || '%')
+  )
+  AND (
+    sqlc.arg(user_type)::text = ''
+    OR (sqlc.arg(user_type)::text = 'guest' AND au.raw_json->'is_guest' = 'true'::jsonb)
+    OR (sqlc.arg(user_type)::text = 'member' AND au.raw_json->'is_guest' IS DISTINCT FROM 'true'::jsonb)
   );
 
 -- name: ListAppUsersWithLinkPageBySourceAndQuery :many
@@ -185,6 +190,11 @@ WHERE
     OR au.email ILIKE ('%' || sqlc.arg(query)::text || '%')
     OR au.display_name ILIKE ('%' || sqlc.arg(query)::text || '%')
   )
+  AND (
+    sqlc.arg(user_type)::text = ''
+    OR (sqlc.arg(user_type)::text = 'guest' AND au.raw_json->'is_guest' = 'true'::jsonb)
+    OR (sqlc.arg(user_type)::text = 'member' AND au.raw_json->'is_guest' IS DISTINCT FROM 'true'::jsonb)
+  )
 ORDER BY au.id DESC
 LIMIT sqlc.arg(page_limit)::int
 OFFSET sqlc.arg(page_offset)::int;
@@ -318,6 +328,15 @@ WHERE id = $1
   AND expired_at IS NULL
   AND last_observed_run_id IS NOT NULL;
 
+-- name: CountAppUsersGroupedBySource :many
+SELECT a.source_kind, a.source_name, count(*) AS user_count
+FROM accounts a
+WHERE
+  a.expired_at IS NULL
+  AND a.last_observed_run_id IS NOT NULL
+GROUP BY a.source_kind, a.source_name
+ORDER BY a.source_kind, a.source_name;
+
 -- name: CountAppUsersBySource :one
 SELECT count(*)
 FROM accounts
@@ -347,6 +366,18 @@ WHERE au.source_kind = $1
   AND au.expired_at IS NULL
   AND au.last_observed_run_id IS NOT NULL;
 
+-- name: CountDormantAppUsersGroupedBySourceKind :many
+SELECT au.source_kind, count(*) AS account_count
+FROM accounts au
+WHERE
+  au.expired_at IS NULL
+  AND au.last_observed_run_id IS NOT NULL
+  AND lower(COALESCE(NULLIF(trim(au.status), ''), NULLIF(trim(au.raw_json->>'status'), ''), '')) = 'active'
+  AND au.last_login_at IS NOT NULL
+  AND au.last_login_at < now() - make_interval(days => sqlc.arg(dormant_days)::int)
+GROUP BY au.source_kind
+ORDER BY au.source_kind;
+
 -- name: CountUnmatchedAppUsersBySource :one
 WITH authoritative_identities AS (
   SELECT DISTINCT ia.identity_id
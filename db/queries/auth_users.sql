@@ -40,6 +40,7 @@ INSERT INTO auth_users (
   password_hash,
   role,
   is_active,
+  org_id,
   created_at,
   updated_at
 )
@@ -48,6 +49,7 @@ VALUES (
   sqlc.arg(password_hash)::text,
   sqlc.arg(role)::text,
   sqlc.arg(is_active)::boolean,
+  sqlc.arg(org_id)::bigint,
   now(),
   now()
 )
@@ -78,3 +80,38 @@ SET
   last_login_ip = sqlc.arg(last_login_ip)::text,
   updated_at = now()
 WHERE id = sqlc.arg(id)::bigint;
+
+-- name: UpdateAuthUserOrg :exec
+UPDATE auth_users
+SET
+  org_id = sqlc.arg(org_id)::bigint,
+  updated_at = now()
+WHERE id = sqlc.arg(id)::bigint;
+
+-- name: CreateOIDCAuthUser :one
+INSERT INTO auth_users (
+  email,
+  password_hash,
+  role,
+  is_active,
+  auth_method,
+  created_at,
+  updated_at
+)
+VALUES (
+  lower(trim(sqlc.arg(email)::text)),
+  '',
+  sqlc.arg(role)::text,
+  true,
+  'oidc',
+  now(),
+  now()
+)
+RETURNING *;
+
+-- name: UpdateAuthUserTimezone :exec
+UPDATE auth_users
+SET
+  timezone = sqlc.arg(timezone)::text,
+  updated_at = now()
+WHERE id = sqlc.arg(id)::bigint;
@@ -0,0 +1,22 @@
+-- name: UpsertConnectorGrant :exec
+INSERT INTO connector_grants (source_kind, source_name, granted, required, missing, last_run_id, checked_at)
+VALUES (
+  sqlc.arg(source_kind)::text,
+  sqlc.arg(source_name)::text,
+  sqlc.arg(granted)::text[],
+  sqlc.arg(required)::text[],
+  sqlc.arg(missing)::text[],
+  sqlc.arg(last_run_id)::bigint,
+  now()
+)
+ON CONFLICT (source_kind, source_name) DO UPDATE SET
+  granted = EXCLUDED.granted,
+  required = EXCLUDED.required,
+  missing = EXCLUDED.missing,
+  last_run_id = EXCLUDED.last_run_id,
+  checked_at = EXCLUDED.checked_at;
+
+-- name: ListConnectorGrants :many
+SELECT *
+FROM connector_grants
+ORDER BY source_kind, source_name;
@@ -0,0 +1,23 @@
+-- name: ListOpenConnectorIncidents :many
+SELECT *
+FROM connector_incidents
+WHERE resolved_at IS NULL
+ORDER BY source_kind, source_name;
+
+-- name: OpenConnectorIncident :exec
+INSERT INTO connector_incidents (source_kind, source_name, dedup_key, summary, opened_at)
+VALUES (
+  sqlc.arg(source_kind)::text,
+  sqlc.arg(source_name)::text,
+  sqlc.arg(dedup_key)::text,
+  sqlc.arg(summary)::text,
+  sqlc.arg(opened_at)::timestamptz
+)
+ON CONFLICT (source_kind, source_name) WHERE resolved_at IS NULL DO NOTHING;
+
+-- name: ResolveConnectorIncident :exec
+UPDATE connector_incidents
+SET resolved_at = sqlc.arg(resolved_at)::timestamptz
+WHERE source_kind = sqlc.arg(source_kind)::text
+  AND source_name = sqlc.arg(source_name)::text
+  AND resolved_at IS NULL;
@@ -68,6 +68,7 @@ INSERT INTO credential_artifacts (
   raw_json,
   seen_in_run_id,
   seen_at,
+  first_seen_at,
   updated_at
 )
 SELECT
@@ -93,6 +94,7 @@ SELECT
   input.raw_json,
   sqlc.arg(seen_in_run_id)::bigint,
   now(),
+  now(),
   now()
 FROM dedup input
 ON CONFLICT (source_kind, source_name, credential_kind, external_id, asset_ref_kind, asset_ref_external_id) DO UPDATE SET
@@ -132,35 +134,7 @@ WHERE
   )
   AND (
     sqlc.arg(risk_level)::text = ''
-    OR lower(sqlc.arg(risk_level)::text) = (
-      CASE
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source < now()
-          AND lower(COALESCE(NULLIF(trim(ca.status), ''), 'active')) IN ('active', 'approved', 'pending_approval')
-          THEN 'critical'
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source < now()
-          THEN 'high'
-        WHEN lower(ca.credential_kind) IN ('entra_client_secret', 'github_deploy_key', 'github_pat_request', 'github_pat_fine_grained')
-          AND trim(ca.created_by_external_id) = ''
-          AND trim(ca.approved_by_external_id) = ''
-          THEN 'critical'
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source >= now()
-          AND ca.expires_at_source <= now() + make_interval(days => 7)
-          THEN 'high'
-        WHEN trim(ca.created_by_external_id) = ''
-          THEN 'high'
-        WHEN ca.last_used_at_source IS NOT NULL
-          AND ca.last_used_at_source <= now() - make_interval(days => 90)
-          THEN 'high'
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source >= now()
-          AND ca.expires_at_source <= now() + make_interval(days => 30)
-          THEN 'medium'
-        ELSE 'low'
-      END
-    )
+    OR lower(sqlc.arg(risk_level)::text) = credential_heuristic_risk_level(ca.expires_at_source, ca.last_used_at_source, ca.status, ca.credential_kind, ca.created_by_external_id, ca.approved_by_external_id)
   )
   AND (
     sqlc.arg(expiry_state)::text = ''
@@ -182,6 +156,39 @@ WHERE
       AND ca.expires_at_source <= now() + make_interval(days => sqlc.arg(expires_in_days)::int)
     )
   )
+  AND (
+    sqlc.arg(introduced_in_days)::int <= 0
+    OR ca.first_seen_at >= now() - make_interval(days => sqlc.arg(introduced_in_days)::int)
+  )
+  AND (
+    NOT sqlc.arg(shared_only)::bool
+    OR lower(ca.created_by_kind) ~ '(service_account|service_principal|bot|application)'
+    OR (
+      trim(ca.created_by_external_id) <> ''
+      AND EXISTS (
+        SELECT 1
+        FROM unnest(sqlc.arg(shared_name_patterns)::text[]) AS p(pattern)
+        WHERE p.pattern <> ''
+          AND (
+            p.pattern = ANY(regexp_split_to_array(lower(ca.created_by_external_id), '[^a-z0-9]+'))
+            OR p.pattern = ANY(regexp_split_to_array(lower(ca.created_by_display_name), '[^a-z0-9]+'))
+          )
+      )
+    )
+    OR (
+      trim(ca.created_by_external_id) = ''
+      AND EXISTS (
+        SELECT 1
+        FROM unnest(sqlc.arg(shared_name_patterns)::text[]) AS p(pattern)
+        WHERE p.pattern <> ''
+          AND p.pattern = ANY(regexp_split_to_array(lower(COALESCE(NULLIF(trim(ca.display_name), ''), ca.external_id)), '[^a-z0-9]+'))
+      )
+    )
+  )
+  AND (
+    NOT sqlc.arg(created_by_filter)::bool
+    OR lower(trim(ca.created_by_external_id)) = ANY(sqlc.arg(created_by_external_ids)::text[])
+  )
   AND (
     sqlc.arg(query)::text = ''
     OR ca.display_name ILIKE ('%' || sqlc.arg(query)::text || '%')
@@ -189,6 +196,30 @@ WHERE
     OR ca.asset_ref_external_id ILIKE ('%' || sqlc.arg(query)::text || '%')
     OR ca.created_by_external_id ILIKE ('%' || sqlc.arg(query)::text || '%')
     OR ca.approved_by_external_id ILIKE ('%' || sqlc.arg(query)::text || '%')
+  )
+  AND (
+    sqlc.arg(last_used)::text = ''
+    OR (sqlc.arg(last_used)::text = 'never' AND ca.last_used_at_source IS NULL)
+    OR (sqlc.arg(last_used)::text = 'used_30d' AND ca.last_used_at_source >= now() - make_interval(days => 30))
+    OR (sqlc.arg(last_used)::text = 'unused_90d' AND ca.last_used_at_source < now() - make_interval(days => 90))
+    OR (sqlc.arg(last_used)::text = 'unused_180d' AND ca.last_used_at_source < now() - make_interval(days => 180))
+  )
+  AND (
+    sqlc.arg(tag_key)::text = ''
+    OR EXISTS (
+      SELECT 1
+      FROM credential_tags ct
+      WHERE ct.source_kind = ca.source_kind
+        AND ct.source_name = ca.source_name
+        AND ct.credential_kind = ca.credential_kind
+        AND ct.external_id = ca.external_id
+        AND ct.tag_key = sqlc.arg(tag_key)::text
+        AND (sqlc.arg(tag_value)::text = '' OR ct.tag_value = sqlc.arg(tag_value)::text)
+    )
+  )
+  AND (
+    NOT sqlc.arg(broad_scope_only)::bool
+    OR lower(ca.scope_json->>'repository_selection') = 'all'
   );
 
 -- name: ListCredentialArtifactsPageBySourceAndQueryAndFilters :many
@@ -209,35 +240,7 @@ WHERE
   )
   AND (
     sqlc.arg(risk_level)::text = ''
-    OR lower(sqlc.arg(risk_level)::text) = (
-      CASE
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source < now()
-          AND lower(COALESCE(NULLIF(trim(ca.status), ''), 'active')) IN ('active', 'approved', 'pending_approval')
-          THEN 'critical'
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source < now()
-          THEN 'high'
-        WHEN lower(ca.credential_kind) IN ('entra_client_secret', 'github_deploy_key', 'github_pat_request', 'github_pat_fine_grained')
-          AND trim(ca.created_by_external_id) = ''
-          AND trim(ca.approved_by_external_id) = ''
-          THEN 'critical'
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source >= now()
-          AND ca.expires_at_source <= now() + make_interval(days => 7)
-          THEN 'high'
-        WHEN trim(ca.created_by_external_id) = ''
-          THEN 'high'
-        WHEN ca.last_used_at_source IS NOT NULL
-          AND ca.last_used_at_source <= now() - make_interval(days => 90)
-          THEN 'high'
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source >= now()
-          AND ca.expires_at_source <= now() + make_interval(days => 30)
-          THEN 'medium'
-        ELSE 'low'
-      END
-    )
+    OR lower(sqlc.arg(risk_level)::text) = credential_heuristic_risk_level(ca.expires_at_source, ca.last_used_at_source, ca.status, ca.credential_kind, ca.created_by_external_id, ca.approved_by_external_id)
   )
   AND (
     sqlc.arg(expiry_state)::text = ''
@@ -259,6 +262,39 @@ WHERE
       AND ca.expires_at_source <= now() + make_interval(days => sqlc.arg(expires_in_days)::int)
     )
   )
+  AND (
+    sqlc.arg(introduced_in_days)::int <= 0
+    OR ca.first_seen_at >= now() - make_interval(days => sqlc.arg(introduced_in_days)::int)
+  )
+  AND (
+    NOT sqlc.arg(shared_only)::bool
+    OR lower(ca.created_by_kind) ~ '(service_account|service_principal|bot|application)'
+    OR (
+      trim(ca.created_by_external_id) <> ''
+      AND EXISTS (
+        SELECT 1
+        FROM unnest(sqlc.arg(shared_name_patterns)::text[]) AS p(pattern)
+        WHERE p.pattern <> ''
+          AND (
+            p.pattern = ANY(regexp_split_to_array(lower(ca.created_by_external_id), '[^a-z0-9]+'))
+            OR p.pattern = ANY(regexp_split_to_array(lower(ca.created_by_display_name), '[^a-z0-9]+'))
+          )
+      )
+    )
+    OR (
+      trim(ca.created_by_external_id) = ''
+      AND EXISTS (
+        SELECT 1
+        FROM unnest(sqlc.arg(shared_name_patterns)::text[]) AS p(pattern)
+        WHERE p.pattern <> ''
+          AND p.pattern = ANY(regexp_split_to_array(lower(COALESCE(NULLIF(trim(ca.display_name), ''), ca.external_id)), '[^a-z0-9]+'))
+      )
+    )
+  )
+  AND (
+    NOT sqlc.arg(created_by_filter)::bool
+    OR lower(trim(ca.created_by_external_id)) = ANY(sqlc.arg(created_by_external_ids)::text[])
+  )
   AND (
     sqlc.arg(query)::text = ''
     OR ca.display_name ILIKE ('%' || sqlc.arg(query)::text || '%')
@@ -267,13 +303,83 @@ WHERE
     OR ca.created_by_external_id ILIKE ('%' || sqlc.arg(query)::text || '%')
     OR ca.approved_by_external_id ILIKE ('%' || sqlc.arg(query)::text || '%')
   )
+  AND (
+    sqlc.arg(last_used)::text = ''
+    OR (sqlc.arg(last_used)::text = 'never' AND ca.last_used_at_source IS NULL)
+    OR (sqlc.arg(last_used)::text = 'used_30d' AND ca.last_used_at_source >= now() - make_interval(days => 30))
+    OR (sqlc.arg(last_used)::text = 'unused_90d' AND ca.last_used_at_source < now() - make_interval(days => 90))
+    OR (sqlc.arg(last_used)::text = 'unused_180d' AND ca.last_used_at_source < now() - make_interval(days => 180))
+  )
+  AND (
+    sqlc.arg(tag_key)::text = ''
+    OR EXISTS (
+      SELECT 1
+      FROM credential_tags ct
+      WHERE ct.source_kind = ca.source_kind
+        AND ct.source_name = ca.source_name
+        AND ct.credential_kind = ca.credential_kind
+        AND ct.external_id = ca.external_id
+        AND ct.tag_key = sqlc.arg(tag_key)::text
+        AND (sqlc.arg(tag_value)::text = '' OR ct.tag_value = sqlc.arg(tag_value)::text)
+    )
+  )
+  AND (
+    NOT sqlc.arg(broad_scope_only)::bool
+    OR lower(ca.scope_json->>'repository_selection') = 'all'
+  )
 ORDER BY
+  CASE sqlc.arg(sort)::text
+    WHEN 'risk_desc' THEN -1
+    WHEN 'risk_asc' THEN 1
+  END * (
+    CASE credential_heuristic_risk_level(ca.expires_at_source, ca.last_used_at_source, ca.status, ca.credential_kind, ca.created_by_external_id, ca.approved_by_external_id)
+      WHEN 'critical' THEN 4
+      WHEN 'high' THEN 3
+      WHEN 'medium' THEN 2
+      ELSE 1
+    END
+  ) ASC,
+  CASE WHEN sqlc.arg(sort)::text = 'last_used_desc' THEN ca.last_used_at_source END DESC NULLS LAST,
+  CASE WHEN sqlc.arg(sort)::text = 'last_used_asc' THEN ca.last_used_at_source END ASC NULLS FIRST,
+  CASE WHEN sqlc.arg(sort)::text = 'name_asc' THEN lower(COALESCE(NULLIF(trim(ca.display_name), ''), ca.external_id)) END ASC,
+  CASE WHEN sqlc.arg(sort)::text = 'name_desc' THEN lower(COALESCE(NULLIF(trim(ca.display_name), ''), ca.external_id)) END DESC,
+  CASE WHEN sqlc.arg(sort)::text = 'expires_desc' THEN COALESCE(ca.expires_at_source, 'infinity'::timestamptz) END DESC,
   COALESCE(ca.expires_at_source, 'infinity'::timestamptz) ASC,
   lower(COALESCE(NULLIF(trim(ca.display_name), ''), ca.external_id)) ASC,
   ca.id ASC
 LIMIT sqlc.arg(page_limit)::int
 OFFSET sqlc.arg(page_offset)::int;
 
+-- name: CountActiveCredentialArtifactsGroupedByKind :many
+SELECT ca.credential_kind, count(*) AS credential_count
+FROM credential_artifacts ca
+WHERE
+  ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
+  AND lower(COALESCE(NULLIF(trim(ca.status), ''), 'active')) IN ('active', 'approved', 'pending_approval')
+GROUP BY ca.credential_kind
+ORDER BY ca.credential_kind;
+
+-- name: CountCredentialArtifactsGroupedBySource :many
+SELECT ca.source_kind, ca.source_name, count(*) AS credential_count
+FROM credential_artifacts ca
+WHERE
+  ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
+GROUP BY ca.source_kind, ca.source_name
+ORDER BY ca.source_kind, ca.source_name;
+
+-- name: CountCredentialArtifactsGroupedByRiskLevel :many
+SELECT
+  credential_heuristic_risk_level(ca.expires_at_source, ca.last_used_at_source, ca.status, ca.credential_kind, ca.created_by_external_id, ca.approved_by_external_id)::text AS risk_level,
+  count(*) AS credential_count
+FROM credential_artifacts ca
+WHERE
+  ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
+GROUP BY 1
+ORDER BY 1;
+
 -- name: ListCredentialArtifactsForAssetRef :many
 SELECT ca.*
 FROM credential_artifacts ca
@@ -287,6 +393,26 @@ ORDER BY
   COALESCE(ca.expires_at_source, 'infinity'::timestamptz) ASC,
   ca.id ASC;
 
+-- name: ListCredentialArtifactsForAssetRefs :many
+WITH requested AS (
+  SELECT
+    k.asset_ref_kind::text AS asset_ref_kind,
+    e.asset_ref_external_id::text AS asset_ref_external_id
+  FROM unnest(sqlc.arg(asset_ref_kinds)::text[]) WITH ORDINALITY AS k(asset_ref_kind, ord)
+  JOIN unnest(sqlc.arg(asset_ref_external_ids)::text[]) WITH ORDINALITY AS e(asset_ref_external_id, ord)
+    USING (ord)
+)
+SELECT ca.*
+FROM credential_artifacts ca
+JOIN requested r
+  ON ca.asset_ref_kind = r.asset_ref_kind
+  AND ca.asset_ref_external_id = r.asset_ref_external_id
+WHERE ca.source_kind = sqlc.arg(source_kind)::text
+  AND ca.source_name = sqlc.arg(source_name)::text
+  AND ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
+ORDER BY ca.asset_ref_kind, ca.asset_ref_external_id, ca.id;
+
 -- name: GetCredentialArtifactByID :one
 SELECT *
 FROM credential_artifacts
@@ -318,6 +444,91 @@ LEFT JOIN credential_artifacts ca
 GROUP BY r.asset_ref_kind, r.asset_ref_external_id
 ORDER BY r.asset_ref_kind, r.asset_ref_external_id;
 
+-- name: ListCredentialArtifactsPastRotationSLA :many
+WITH policy AS (
+  SELECT
+    lower(k.credential_kind)::text AS credential_kind,
+    d.sla_days::int AS sla_days
+  FROM unnest(sqlc.arg(policy_kinds)::text[]) WITH ORDINALITY AS k(credential_kind, ord)
+  JOIN unnest(sqlc.arg(policy_days)::int[]) WITH ORDINALITY AS d(sla_days, ord)
+    USING (ord)
+),
+aged AS (
+  SELECT
+    ca.id,
+    ca.source_kind,
+    ca.source_name,
+    ca.credential_kind,
+    ca.external_id,
+    ca.display_name,
+    ca.status,
+    ca.created_at_source,
+    ca.expires_at_source,
+    ca.last_used_at_source,
+    COALESCE(p.sla_days, sqlc.arg(default_sla_days)::int)::int AS sla_days,
+    floor(extract(epoch FROM (now() - ca.created_at_source)) / 86400)::int AS age_days
+  FROM credential_artifacts ca
+  LEFT JOIN policy p ON p.credential_kind = lower(ca.credential_kind)
+  WHERE
+    ca.expired_at IS NULL
+    AND ca.last_observed_run_id IS NOT NULL
+    AND ca.created_at_source IS NOT NULL
+    AND (ca.expires_at_source IS NULL OR ca.expires_at_source >= now())
+    AND lower(COALESCE(NULLIF(trim(ca.status), ''), 'active')) = ANY(sqlc.arg(active_statuses)::text[])
+)
+SELECT
+  id,
+  source_kind,
+  source_name,
+  credential_kind,
+  external_id,
+  display_name,
+  status,
+  created_at_source,
+  expires_at_source,
+  last_used_at_source,
+  sla_days,
+  age_days
+FROM aged
+WHERE sla_days > 0
+  AND age_days >= sla_days
+ORDER BY age_days - sla_days DESC, id ASC
+LIMIT sqlc.arg(row_limit)::int;
+
+-- name: ListCredentialArtifactsExpiringWithin :many
+SELECT
+  ca.id,
+  ca.source_kind,
+  ca.source_name,
+  ca.credential_kind,
+  ca.external_id,
+  ca.display_name,
+  ca.status,
+  ca.expires_at_source,
+  ca.created_by_external_id,
+  ca.created_by_display_name,
+  ca.approved_by_external_id,
+  ca.approved_by_display_name,
+  aa.id AS app_asset_id
+FROM credential_artifacts ca
+LEFT JOIN app_assets aa
+  ON ca.asset_ref_kind = 'app_asset'
+  AND strpos(ca.asset_ref_external_id, ':') > 0
+  AND aa.source_kind = ca.source_kind
+  AND aa.source_name = ca.source_name
+  AND aa.asset_kind = split_part(ca.asset_ref_external_id, ':', 1)
+  AND aa.external_id = substr(ca.asset_ref_external_id, strpos(ca.asset_ref_external_id, ':') + 1)
+  AND aa.expired_at IS NULL
+  AND aa.last_observed_run_id IS NOT NULL
+WHERE
+  ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
+  AND ca.expires_at_source >= now()
+  AND ca.expires_at_source < now() + make_interval(days => sqlc.arg(within_days)::int)
+  AND lower(COALESCE(NULLIF(trim(ca.status), ''), 'active')) = ANY(sqlc.arg(active_statuses)::text[])
+ORDER BY ca.expires_at_source ASC, ca.id ASC
+LIMIT sqlc.arg(row_limit)::int;
+
 -- name: PromoteCredentialArtifactsSeenInRunBySource :execrows
 UPDATE credential_artifacts
 SET
@@ -330,15 +541,69 @@ WHERE source_kind = sqlc.arg(source_kind)::text
   AND seen_in_run_id = sqlc.arg(last_observed_run_id)::bigint;
 
 -- name: ExpireCredentialArtifactsNotSeenInRunBySource :execrows
-UPDATE credential_artifacts
+-- Held (credential kind, asset ref external id) pairs are skipped; an empty held asset ref
+-- covers every credential of the kind.
+UPDATE credential_artifacts ca
 SET
   expired_at = now(),
   expired_run_id = sqlc.arg(expired_run_id)::bigint
-WHERE source_kind = sqlc.arg(source_kind)::text
-  AND source_name = sqlc.arg(source_name)::text
-  AND expired_at IS NULL
-  AND last_observed_run_id IS NOT NULL
+WHERE ca.source_kind = sqlc.arg(source_kind)::text
+  AND ca.source_name = sqlc.arg(source_name)::text
+  AND ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
   AND (
-    seen_in_run_id <> sqlc.arg(expired_run_id)::bigint
-    OR seen_in_run_id IS NULL
+    ca.seen_in_run_id <> sqlc.arg(expired_run_id)::bigint
+    OR ca.seen_in_run_id IS NULL
+  )
+  AND NOT EXISTS (
+    SELECT 1
+    FROM unnest(sqlc.arg(held_credential_kinds)::text[]) WITH ORDINALITY AS k(kind, ord)
+    JOIN unnest(sqlc.arg(held_asset_ref_external_ids)::text[]) WITH ORDINALITY AS r(external_id, ord) USING (ord)
+    WHERE k.kind = ca.credential_kind
+      AND (r.external_id = '' OR r.external_id = ca.asset_ref_external_id)
   );
+
+-- name: ListCredentialArtifactsByActorAccounts :many
+WITH actor_accounts AS (
+  SELECT k.kind AS source_kind, n.name AS source_name, e.external_id
+  FROM unnest(sqlc.arg(source_kinds)::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest(sqlc.arg(source_names)::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+  JOIN unnest(sqlc.arg(external_ids)::text[]) WITH ORDINALITY AS e(external_id, ord) USING (ord)
+)
+SELECT ca.*
+FROM credential_artifacts ca
+WHERE
+  ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
+  AND EXISTS (
+    SELECT 1
+    FROM actor_accounts act
+    WHERE act.source_kind = ca.source_kind
+      AND act.source_name = ca.source_name
+      AND act.external_id IN (lower(trim(ca.created_by_external_id)), lower(trim(ca.approved_by_external_id)))
+  )
+ORDER BY
+  ca.source_kind,
+  ca.source_name,
+  COALESCE(ca.expires_at_source, 'infinity'::timestamptz) ASC,
+  ca.id ASC
+LIMIT sqlc.arg(row_limit)::int;
+
+-- name: ListCredentialArtifactsForSnapshot :many
+-- Every active credential, the credential half of a daily inventory snapshot.
+SELECT
+  ca.source_kind,
+  ca.source_name,
+  ca.credential_kind,
+  ca.external_id,
+  ca.display_name,
+  ca.status,
+  ca.asset_ref_kind,
+  ca.asset_ref_external_id,
+  ca.created_at_source,
+  ca.expires_at_source,
+  ca.last_used_at_source
+FROM credential_artifacts ca
+WHERE ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
+ORDER BY ca.source_kind, ca.source_name, ca.credential_kind, ca.external_id, ca.id;
@@ -97,3 +97,25 @@ WHERE cae.source_kind = sqlc.arg(source_kind)::text
   AND cae.target_external_id = sqlc.arg(target_external_id)::text
 ORDER BY cae.event_time DESC, cae.id DESC
 LIMIT sqlc.arg(limit_rows)::int;
+
+-- name: ListCredentialAuditEventsForActors :many
+WITH actor AS (
+  SELECT k.kind AS source_kind, n.name AS source_name, e.external_id AS actor_external_id
+  FROM unnest(sqlc.arg(source_kinds)::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest(sqlc.arg(source_names)::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+  JOIN unnest(sqlc.arg(actor_external_ids)::text[]) WITH ORDINALITY AS e(external_id, ord) USING (ord)
+)
+SELECT cae.*
+FROM credential_audit_events cae
+JOIN actor
+  ON actor.source_kind = cae.source_kind
+  AND actor.source_name = cae.source_name
+  AND actor.actor_external_id = cae.actor_external_id
+ORDER BY cae.event_time DESC, cae.id DESC
+LIMIT sqlc.arg(limit_rows)::int;
+
+-- name: GetLatestCredentialAuditEventTimeBySource :one
+SELECT max(event_time)::timestamptz AS last_event_time
+FROM credential_audit_events
+WHERE source_kind = $1
+  AND source_name = $2;
@@ -0,0 +1,55 @@
+-- name: CompleteOpenCredentialRevocationTasks :execrows
+UPDATE credential_revocation_tasks
+SET
+  status = 'completed',
+  resolved_by_auth_user_id = sqlc.narg(resolved_by_auth_user_id)::bigint,
+  resolved_at = now()
+WHERE source_kind = sqlc.arg(source_kind)::text
+  AND source_name = sqlc.arg(source_name)::text
+  AND credential_kind = sqlc.arg(credential_kind)::text
+  AND external_id = sqlc.arg(external_id)::text
+  AND status = 'open';
+
+-- name: CreateCredentialRevocationTask :one
+INSERT INTO credential_revocation_tasks (
+  source_kind,
+  source_name,
+  credential_kind,
+  external_id,
+  method,
+  status,
+  reason,
+  error_message,
+  requested_by_auth_user_id,
+  resolved_at
+)
+VALUES (
+  sqlc.arg(source_kind)::text,
+  sqlc.arg(source_name)::text,
+  sqlc.arg(credential_kind)::text,
+  sqlc.arg(external_id)::text,
+  sqlc.arg(method)::text,
+  sqlc.arg(status)::text,
+  sqlc.arg(reason)::text,
+  sqlc.arg(error_message)::text,
+  sqlc.narg(requested_by_auth_user_id)::bigint,
+  CASE WHEN sqlc.arg(status)::text = 'completed' THEN now() END
+)
+ON CONFLICT (source_kind, source_name, credential_kind, external_id) WHERE status = 'open' DO UPDATE SET
+  reason = EXCLUDED.reason,
+  error_message = EXCLUDED.error_message,
+  requested_by_auth_user_id = COALESCE(EXCLUDED.requested_by_auth_user_id, credential_revocation_tasks.requested_by_auth_user_id)
+RETURNING *;
+
+-- name: ListCredentialRevocationTasksForCredential :many
+SELECT
+  t.*,
+  COALESCE(requested_by.email, '')::text AS requested_by_email
+FROM credential_revocation_tasks t
+LEFT JOIN auth_users requested_by ON requested_by.id = t.requested_by_auth_user_id
+WHERE t.source_kind = sqlc.arg(source_kind)::text
+  AND t.source_name = sqlc.arg(source_name)::text
+  AND t.credential_kind = sqlc.arg(credential_kind)::text
+  AND t.external_id = sqlc.arg(external_id)::text
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT 20;
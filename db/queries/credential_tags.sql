@@ -0,0 +1,30 @@
+-- name: DeleteCredentialTag :exec
+DELETE FROM credential_tags
+WHERE source_kind = sqlc.arg(source_kind)::text
+  AND source_name = sqlc.arg(source_name)::text
+  AND credential_kind = sqlc.arg(credential_kind)::text
+  AND external_id = sqlc.arg(external_id)::text
+  AND tag_key = sqlc.arg(tag_key)::text;
+
+-- name: ListCredentialTagsForCredential :many
+SELECT *
+FROM credential_tags
+WHERE source_kind = sqlc.arg(source_kind)::text
+  AND source_name = sqlc.arg(source_name)::text
+  AND credential_kind = sqlc.arg(credential_kind)::text
+  AND external_id = sqlc.arg(external_id)::text
+ORDER BY tag_key ASC;
+
+-- name: UpsertCredentialTag :exec
+INSERT INTO credential_tags (source_kind, source_name, credential_kind, external_id, tag_key, tag_value)
+VALUES (
+  sqlc.arg(source_kind)::text,
+  sqlc.arg(source_name)::text,
+  sqlc.arg(credential_kind)::text,
+  sqlc.arg(external_id)::text,
+  sqlc.arg(tag_key)::text,
+  sqlc.arg(tag_value)::text
+)
+ON CONFLICT (source_kind, source_name, credential_kind, external_id, tag_key) DO UPDATE SET
+  tag_value = EXCLUDED.tag_value,
+  updated_at = now();
@@ -6,7 +6,9 @@ WITH input AS (
     (sqlc.arg(kinds)::text[])[i] AS kind,
     (sqlc.arg(resources)::text[])[i] AS resource,
     (sqlc.arg(permissions)::text[])[i] AS permission,
-    (sqlc.arg(raw_jsons)::jsonb[])[i] AS raw_json
+    (sqlc.arg(raw_jsons)::jsonb[])[i] AS raw_json,
+    COALESCE((sqlc.arg(assignment_states)::text[])[i], '') AS assignment_state,
+    COALESCE((sqlc.arg(privilege_levels)::smallint[])[i], 0) AS privilege_level
   FROM generate_subscripts(sqlc.arg(app_user_external_ids)::text[], 1) AS s(i)
 ),
 dedup AS (
@@ -15,7 +17,9 @@ dedup AS (
     kind,
     resource,
     permission,
-    raw_json
+    raw_json,
+    assignment_state,
+    privilege_level
   FROM input
   ORDER BY app_user_external_id, kind, resource, permission, i DESC
 )
@@ -25,6 +29,8 @@ INSERT INTO entitlements (
   resource,
   permission,
   raw_json,
+  assignment_state,
+  privilege_level,
   seen_in_run_id,
   seen_at,
   updated_at
@@ -35,6 +41,8 @@ SELECT
   input.resource,
   input.permission,
   input.raw_json,
+  input.assignment_state,
+  input.privilege_level,
   sqlc.arg(seen_in_run_id)::bigint,
   now(),
   now()
@@ -45,6 +53,8 @@ JOIN accounts au
   AND au.external_id = input.app_user_external_id
 ON CONFLICT (app_user_id, kind, resource, permission) DO UPDATE SET
   raw_json = EXCLUDED.raw_json,
+  assignment_state = EXCLUDED.assignment_state,
+  privilege_level = EXCLUDED.privilege_level,
   seen_in_run_id = EXCLUDED.seen_in_run_id,
   seen_at = EXCLUDED.seen_at,
   updated_at = now();
@@ -83,6 +93,7 @@ SELECT
   e.resource AS entitlement_resource,
   e.permission AS entitlement_permission,
   e.raw_json AS entitlement_raw_json,
+  e.assignment_state AS entitlement_assignment_state,
   e.created_at AS entitlement_created_at,
   e.updated_at AS entitlement_updated_at,
   au.id AS app_user_id,
@@ -109,3 +120,123 @@ WHERE au.source_kind = sqlc.arg(source_kind)::text
   AND e.expired_at IS NULL
   AND e.last_observed_run_id IS NOT NULL
 ORDER BY i.primary_email, au.external_id, e.permission, e.id;
+
+-- name: ListEntitlementChangeCandidatesBySource :many
+-- Entitlements that differ between the previous and current successful runs of a source:
+-- observed in the current run but not the previous one, or expired by the current run.
+SELECT
+  e.id AS entitlement_id,
+  e.kind AS entitlement_kind,
+  e.resource AS entitlement_resource,
+  e.permission AS entitlement_permission,
+  e.assignment_state AS entitlement_assignment_state,
+  e.raw_json AS entitlement_raw_json,
+  e.last_observed_run_id,
+  e.previous_observed_run_id,
+  e.expired_run_id,
+  au.id AS app_user_id,
+  au.external_id AS app_user_external_id,
+  au.email AS app_user_email,
+  au.display_name AS app_user_display_name,
+  i.id AS identity_id,
+  i.display_name AS identity_display_name,
+  i.primary_email AS identity_primary_email
+FROM entitlements e
+JOIN accounts au ON au.id = e.app_user_id
+LEFT JOIN identity_accounts ia ON ia.account_id = au.id
+LEFT JOIN identities i ON i.id = ia.identity_id
+WHERE au.source_kind = sqlc.arg(source_kind)::text
+  AND au.source_name = sqlc.arg(source_name)::text
+  AND (
+    (
+      e.last_observed_run_id = sqlc.arg(current_run_id)::bigint
+      AND e.expired_at IS NULL
+      AND e.previous_observed_run_id IS DISTINCT FROM sqlc.arg(previous_run_id)::bigint
+    )
+    OR e.expired_run_id = sqlc.arg(current_run_id)::bigint
+  )
+ORDER BY e.kind, e.resource, au.external_id, e.permission, e.id
+LIMIT sqlc.arg(limit_rows);
+
+-- name: ListPrivilegedEntitlements :many
+-- Active entitlements at or above a canonical privilege level, or of one of extra_kinds, across
+-- every source, with the linked identity where one exists.
+SELECT
+  e.id AS entitlement_id,
+  e.kind AS entitlement_kind,
+  e.resource AS entitlement_resource,
+  e.permission AS entitlement_permission,
+  e.privilege_level AS entitlement_privilege_level,
+  e.assignment_state AS entitlement_assignment_state,
+  e.raw_json AS entitlement_raw_json,
+  e.last_observed_at AS entitlement_last_observed_at,
+  au.id AS app_user_id,
+  au.source_kind AS app_user_source_kind,
+  au.source_name AS app_user_source_name,
+  au.external_id AS app_user_external_id,
+  au.email AS app_user_email,
+  au.display_name AS app_user_display_name,
+  i.id AS identity_id,
+  i.display_name AS identity_display_name,
+  i.primary_email AS identity_primary_email
+FROM entitlements e
+JOIN accounts au ON au.id = e.app_user_id
+LEFT JOIN identity_accounts ia ON ia.account_id = au.id
+LEFT JOIN identities i ON i.id = ia.identity_id
+WHERE (
+    e.privilege_level >= sqlc.arg(min_privilege_level)::smallint
+    OR e.kind = ANY(sqlc.arg(extra_kinds)::text[])
+  )
+  AND (sqlc.arg(source_kind)::text = '' OR au.source_kind = sqlc.arg(source_kind)::text)
+  AND au.expired_at IS NULL
+  AND au.last_observed_run_id IS NOT NULL
+  AND e.expired_at IS NULL
+  AND e.last_observed_run_id IS NOT NULL
+ORDER BY e.privilege_level DESC, au.source_kind, au.source_name, au.external_id, e.kind, e.resource, e.id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListActiveEntitlementsForEscalation :many
+-- Every active entitlement of every active account, the input of escalation path detection.
+-- Membership entitlements are included whatever their level, since they are the graph's edges.
+SELECT
+  e.id AS entitlement_id,
+  e.kind AS entitlement_kind,
+  e.resource AS entitlement_resource,
+  e.permission AS entitlement_permission,
+  e.privilege_level AS entitlement_privilege_level,
+  e.raw_json AS entitlement_raw_json,
+  au.id AS app_user_id,
+  au.source_kind AS app_user_source_kind,
+  au.source_name AS app_user_source_name,
+  au.external_id AS app_user_external_id,
+  au.email AS app_user_email,
+  au.display_name AS app_user_display_name
+FROM entitlements e
+JOIN accounts au ON au.id = e.app_user_id
+WHERE (sqlc.arg(source_kind)::text = '' OR au.source_kind = sqlc.arg(source_kind)::text)
+  AND au.expired_at IS NULL
+  AND au.last_observed_run_id IS NOT NULL
+  AND e.expired_at IS NULL
+  AND e.last_observed_run_id IS NOT NULL
+ORDER BY au.source_kind, au.source_name, au.external_id, e.kind, e.resource, e.id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListActiveEntitlementsForSnapshot :many
+-- Every active entitlement of every active account, the entitlement half of a daily inventory
+-- snapshot.
+SELECT
+  au.source_kind AS app_user_source_kind,
+  au.source_name AS app_user_source_name,
+  au.external_id AS app_user_external_id,
+  au.email AS app_user_email,
+  e.kind AS entitlement_kind,
+  e.resource AS entitlement_resource,
+  e.permission AS entitlement_permission,
+  e.privilege_level AS entitlement_privilege_level
+FROM entitlements e
+JOIN accounts au ON au.id = e.app_user_id
+WHERE au.expired_at IS NULL
+  AND au.last_observed_run_id IS NOT NULL
+  AND e.expired_at IS NULL
+  AND e.last_observed_run_id IS NOT NULL
+ORDER BY au.source_kind, au.source_name, au.external_id, e.kind, e.resource, e.id;
@@ -120,19 +120,29 @@ WHERE source_kind = $2
   AND seen_in_run_id = $1;
 
 -- name: ExpireAppUsersNotSeenInRun :execrows
+-- Accounts whose raw_json sets one of the held flags to true are skipped.
 UPDATE accounts
 SET
   expired_at = now(),
-  expired_run_id = $1
-WHERE source_kind = $2
-  AND source_name = $3
+  expired_run_id = sqlc.arg(expired_run_id)::bigint
+WHERE source_kind = sqlc.arg(source_kind)::text
+  AND source_name = sqlc.arg(source_name)::text
   AND expired_at IS NULL
   AND last_observed_run_id IS NOT NULL
-  AND (seen_in_run_id <> $1 OR seen_in_run_id IS NULL);
+  AND (seen_in_run_id <> sqlc.arg(expired_run_id)::bigint OR seen_in_run_id IS NULL)
+  AND NOT EXISTS (
+    SELECT 1
+    FROM unnest(sqlc.arg(held_raw_json_flags)::text[]) AS f(flag)
+    WHERE accounts.raw_json ->> f.flag = 'true'
+  );
 
 -- name: PromoteEntitlementsSeenInRunBySource :execrows
 UPDATE entitlements e
 SET
+  previous_observed_run_id = CASE
+    WHEN e.last_observed_run_id IS DISTINCT FROM $1 THEN e.last_observed_run_id
+    ELSE e.previous_observed_run_id
+  END,
   last_observed_run_id = $1,
   last_observed_at = now(),
   expired_at = NULL,
@@ -144,14 +154,22 @@ WHERE au.id = e.app_user_id
   AND e.seen_in_run_id = $1;
 
 -- name: ExpireEntitlementsNotSeenInRunBySource :execrows
+-- Held (kind, resource) pairs are skipped; an empty held resource covers every resource of the kind.
 UPDATE entitlements e
 SET
   expired_at = now(),
-  expired_run_id = $1
+  expired_run_id = sqlc.arg(expired_run_id)::bigint
 FROM accounts au
 WHERE au.id = e.app_user_id
-  AND au.source_kind = $2
-  AND au.source_name = $3
+  AND au.source_kind = sqlc.arg(source_kind)::text
+  AND au.source_name = sqlc.arg(source_name)::text
   AND e.expired_at IS NULL
   AND e.last_observed_run_id IS NOT NULL
-  AND (e.seen_in_run_id <> $1 OR e.seen_in_run_id IS NULL);
+  AND (e.seen_in_run_id <> sqlc.arg(expired_run_id)::bigint OR e.seen_in_run_id IS NULL)
+  AND NOT EXISTS (
+    SELECT 1
+    FROM unnest(sqlc.arg(held_kinds)::text[]) WITH ORDINALITY AS k(kind, ord)
+    JOIN unnest(sqlc.arg(held_resources)::text[]) WITH ORDINALITY AS r(resource, ord) USING (ord)
+    WHERE k.kind = e.kind
+      AND (r.resource = '' OR r.resource = e.resource)
+  );
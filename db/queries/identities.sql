@@ -26,6 +26,54 @@ WHERE lower(trim(i.primary_email)) = lower(trim(sqlc.arg(primary_email)::text))
 ORDER BY (ai.identity_id IS NOT NULL) DESC, i.id ASC
 LIMIT 1;
 
+-- name: GetPreferredIdentityBySecondaryEmail :one
+-- The identity linked to an active account that lists email as a secondary address, preferring
+-- identities anchored by an authoritative source.
+WITH authoritative_identities AS (
+  SELECT DISTINCT ia.identity_id
+  FROM identity_accounts ia
+  JOIN accounts anchor ON anchor.id = ia.account_id
+  JOIN identity_source_settings iss
+    ON iss.source_kind = anchor.source_kind
+   AND iss.source_name = anchor.source_name
+   AND iss.is_authoritative
+  WHERE anchor.expired_at IS NULL
+    AND anchor.last_observed_run_id IS NOT NULL
+)
+SELECT i.*
+FROM identities i
+JOIN identity_accounts ia ON ia.identity_id = i.id
+JOIN accounts a ON a.id = ia.account_id
+JOIN account_emails ae ON ae.account_id = a.id
+LEFT JOIN authoritative_identities ai ON ai.identity_id = i.id
+WHERE ae.email = lower(trim(sqlc.arg(email)::text))
+  AND a.expired_at IS NULL
+  AND a.last_observed_run_id IS NOT NULL
+ORDER BY (ai.identity_id IS NOT NULL) DESC, i.id ASC
+LIMIT 1;
+
+-- name: ListPreferredIdentitiesByPrimaryEmails :many
+WITH authoritative_identities AS (
+  SELECT DISTINCT ia.identity_id
+  FROM identity_accounts ia
+  JOIN accounts anchor ON anchor.id = ia.account_id
+  JOIN identity_source_settings iss
+    ON iss.source_kind = anchor.source_kind
+   AND iss.source_name = anchor.source_name
+   AND iss.is_authoritative
+  WHERE anchor.expired_at IS NULL
+    AND anchor.last_observed_run_id IS NOT NULL
+)
+SELECT DISTINCT ON (lower(trim(i.primary_email)))
+  lower(trim(i.primary_email))::text AS email,
+  i.id AS identity_id,
+  i.display_name,
+  i.primary_email
+FROM identities i
+LEFT JOIN authoritative_identities ai ON ai.identity_id = i.id
+WHERE lower(trim(i.primary_email)) = ANY(sqlc.arg(primary_emails)::text[])
+ORDER BY lower(trim(i.primary_email)), (ai.identity_id IS NOT NULL) DESC, i.id ASC;
+
 -- name: UpdateIdentityAttributes :exec
 UPDATE identities
 SET
@@ -166,7 +214,7 @@ status_stats AS (
     aa.identity_id,
     COUNT(*)::bigint AS account_count,
     BOOL_OR(aa.normalized_status IN ('active', 'enabled')) AS has_active,
-    BOOL_OR(aa.normalized_status IN ('suspended', 'disabled', 'inactive', 'locked')) AS has_suspended,
+    BOOL_OR(aa.normalized_status IN ('suspended', 'archived', 'disabled', 'inactive', 'locked')) AS has_suspended,
     BOOL_AND(aa.normalized_status IN ('deleted', 'deprovisioned', 'terminated')) AS all_deleted
   FROM all_active_accounts aa
   GROUP BY aa.identity_id
@@ -415,7 +463,7 @@ status_stats AS (
     aa.identity_id,
     COUNT(*)::bigint AS account_count,
     BOOL_OR(aa.normalized_status IN ('active', 'enabled')) AS has_active,
-    BOOL_OR(aa.normalized_status IN ('suspended', 'disabled', 'inactive', 'locked')) AS has_suspended,
+    BOOL_OR(aa.normalized_status IN ('suspended', 'archived', 'disabled', 'inactive', 'locked')) AS has_suspended,
     BOOL_AND(aa.normalized_status IN ('deleted', 'deprovisioned', 'terminated')) AS all_deleted
   FROM all_active_accounts aa
   GROUP BY aa.identity_id
@@ -32,6 +32,31 @@ WHERE lower(trim(a.source_kind)) = lower(trim(sqlc.arg(source_kind)::text))
 ORDER BY i.id ASC
 LIMIT 1;
 
+-- name: ListIdentitiesBySourceAndExternalIDs :many
+WITH requested AS (
+  SELECT k.source_kind, n.source_name, e.external_id
+  FROM unnest(sqlc.arg(source_kinds)::text[]) WITH ORDINALITY AS k(source_kind, ord)
+  JOIN unnest(sqlc.arg(source_names)::text[]) WITH ORDINALITY AS n(source_name, ord) USING (ord)
+  JOIN unnest(sqlc.arg(external_ids)::text[]) WITH ORDINALITY AS e(external_id, ord) USING (ord)
+)
+SELECT DISTINCT ON (r.source_kind, r.source_name, r.external_id)
+  r.source_kind::text AS source_kind,
+  r.source_name::text AS source_name,
+  r.external_id::text AS external_id,
+  i.id AS identity_id,
+  i.display_name,
+  i.primary_email
+FROM requested r
+JOIN accounts a
+  ON lower(trim(a.source_kind)) = lower(trim(r.source_kind))
+ AND lower(trim(a.source_name)) = lower(trim(r.source_name))
+ AND lower(trim(a.external_id)) = lower(trim(r.external_id))
+JOIN identity_accounts ia ON ia.account_id = a.id
+JOIN identities i ON i.id = ia.identity_id
+WHERE a.expired_at IS NULL
+  AND a.last_observed_run_id IS NOT NULL
+ORDER BY r.source_kind, r.source_name, r.external_id, i.id ASC;
+
 -- name: ListLinkedAccountsForIdentity :many
 SELECT a.*
 FROM accounts a
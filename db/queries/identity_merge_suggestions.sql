@@ -0,0 +1,121 @@
+-- Duplicate-identity merge suggestions.
+
+-- name: ListIdentityDuplicateCandidates :many
+SELECT
+  i.id AS identity_id,
+  i.display_name,
+  i.primary_email,
+  a.external_id,
+  a.email AS account_email,
+  COALESCE(iss.is_authoritative, false)::boolean AS is_authoritative
+FROM identities i
+JOIN identity_accounts ia ON ia.identity_id = i.id
+JOIN accounts a ON a.id = ia.account_id
+LEFT JOIN identity_source_settings iss
+  ON iss.source_kind = a.source_kind
+ AND iss.source_name = a.source_name
+WHERE i.kind IN ('human', 'unknown')
+  AND a.expired_at IS NULL
+  AND a.last_observed_run_id IS NOT NULL
+ORDER BY i.id, a.id;
+
+-- name: UpsertIdentityMergeSuggestion :exec
+INSERT INTO identity_merge_suggestions (
+  low_identity_id,
+  high_identity_id,
+  survivor_identity_id,
+  score,
+  reasons
+)
+VALUES (
+  LEAST(sqlc.arg(survivor_identity_id)::bigint, sqlc.arg(duplicate_identity_id)::bigint),
+  GREATEST(sqlc.arg(survivor_identity_id)::bigint, sqlc.arg(duplicate_identity_id)::bigint),
+  sqlc.arg(survivor_identity_id)::bigint,
+  sqlc.arg(score)::real,
+  sqlc.arg(reasons)::text[]
+)
+ON CONFLICT (low_identity_id, high_identity_id) DO UPDATE
+SET
+  survivor_identity_id = EXCLUDED.survivor_identity_id,
+  score = EXCLUDED.score,
+  reasons = EXCLUDED.reasons,
+  updated_at = now()
+WHERE identity_merge_suggestions.status = 'open';
+
+-- name: DeleteStaleIdentityMergeSuggestions :execrows
+DELETE FROM identity_merge_suggestions s
+WHERE s.status = 'open'
+  AND NOT EXISTS (
+    SELECT 1
+    FROM unnest(sqlc.arg(low_identity_ids)::bigint[]) WITH ORDINALITY AS l(id, ord)
+    JOIN unnest(sqlc.arg(high_identity_ids)::bigint[]) WITH ORDINALITY AS h(id, ord) USING (ord)
+    WHERE l.id = s.low_identity_id
+      AND h.id = s.high_identity_id
+  );
+
+-- name: ListOpenIdentityMergeSuggestions :many
+SELECT
+  s.id,
+  s.score,
+  s.reasons,
+  s.updated_at,
+  survivor.id AS survivor_identity_id,
+  survivor.display_name AS survivor_display_name,
+  survivor.primary_email AS survivor_primary_email,
+  (SELECT count(*) FROM identity_accounts ia WHERE ia.identity_id = survivor.id)::bigint AS survivor_account_count,
+  duplicate.id AS duplicate_identity_id,
+  duplicate.display_name AS duplicate_display_name,
+  duplicate.primary_email AS duplicate_primary_email,
+  (SELECT count(*) FROM identity_accounts ia WHERE ia.identity_id = duplicate.id)::bigint AS duplicate_account_count
+FROM identity_merge_suggestions s
+JOIN identities survivor ON survivor.id = s.survivor_identity_id
+JOIN identities duplicate
+  ON duplicate.id = CASE WHEN s.survivor_identity_id = s.low_identity_id THEN s.high_identity_id ELSE s.low_identity_id END
+WHERE s.status = 'open'
+ORDER BY s.score DESC, s.id ASC
+LIMIT sqlc.arg(limit_rows)::int;
+
+-- name: ListIdentityAccountSources :many
+-- Every linked account counts, expired or not, because a merge moves all of them.
+SELECT DISTINCT ia.identity_id, a.source_kind, a.source_name
+FROM identity_accounts ia
+JOIN accounts a ON a.id = ia.account_id
+WHERE ia.identity_id = ANY(sqlc.arg(identity_ids)::bigint[])
+ORDER BY ia.identity_id, a.source_kind, a.source_name;
+
+-- name: GetOpenIdentityMergeSuggestionForUpdate :one
+SELECT *
+FROM identity_merge_suggestions
+WHERE id = sqlc.arg(id)::bigint
+  AND status = 'open'
+FOR UPDATE;
+
+-- name: DismissIdentityMergeSuggestion :execrows
+UPDATE identity_merge_suggestions
+SET
+  status = 'dismissed',
+  decided_by_auth_user_id = sqlc.narg(decided_by_auth_user_id)::bigint,
+  decided_at = now(),
+  updated_at = now()
+WHERE id = sqlc.arg(id)::bigint
+  AND status = 'open';
+
+-- name: MoveIdentityAccountLinks :execrows
+UPDATE identity_accounts
+SET
+  identity_id = sqlc.arg(to_identity_id)::bigint,
+  link_reason = 'manual',
+  confidence = 1.0,
+  updated_at = now()
+WHERE identity_id = sqlc.arg(from_identity_id)::bigint;
+
+-- name: ReassignSaaSAppGovernanceOwnerIdentity :exec
+UPDATE saas_app_governance_overrides
+SET
+  owner_identity_id = sqlc.arg(to_identity_id)::bigint,
+  updated_at = now()
+WHERE owner_identity_id = sqlc.arg(from_identity_id)::bigint;
+
+-- name: DeleteIdentity :exec
+DELETE FROM identities
+WHERE id = sqlc.arg(id)::bigint;
@@ -32,6 +32,24 @@ WHERE
     sqlc.arg(state)::text = ''
     OR (sqlc.arg(state)::text = 'active' AND lower(status) = 'active')
     OR (sqlc.arg(state)::text = 'inactive' AND lower(status) <> 'active')
+  )
+  AND (
+    sqlc.arg(mfa)::text = ''
+    OR (sqlc.arg(mfa)::text = 'none' AND NOT EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE'
+    ))
+    OR (sqlc.arg(mfa)::text = 'weak' AND EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE'
+    ) AND NOT EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE' AND f.strength = 'strong'
+    ))
+    OR (sqlc.arg(mfa)::text = 'strong' AND EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE' AND f.strength = 'strong'
+    ))
   );
 
 -- name: ListIdPUsersPageByQueryAndState :many
@@ -52,6 +70,24 @@ WHERE
     OR (sqlc.arg(state)::text = 'active' AND lower(status) = 'active')
     OR (sqlc.arg(state)::text = 'inactive' AND lower(status) <> 'active')
   )
+  AND (
+    sqlc.arg(mfa)::text = ''
+    OR (sqlc.arg(mfa)::text = 'none' AND NOT EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE'
+    ))
+    OR (sqlc.arg(mfa)::text = 'weak' AND EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE'
+    ) AND NOT EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE' AND f.strength = 'strong'
+    ))
+    OR (sqlc.arg(mfa)::text = 'strong' AND EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE' AND f.strength = 'strong'
+    ))
+  )
 ORDER BY id ASC
 LIMIT sqlc.arg(page_limit)::int
 OFFSET sqlc.arg(page_offset)::int;
@@ -0,0 +1,23 @@
+-- name: InsertInventorySnapshot :execrows
+-- Snapshots are append-only: a day already captured for a kind keeps its first snapshot.
+INSERT INTO inventory_snapshots (snapshot_date, kind, row_count, payload)
+VALUES (sqlc.arg(snapshot_date)::date, sqlc.arg(kind)::text, sqlc.arg(row_count)::int, sqlc.arg(payload)::bytea)
+ON CONFLICT (snapshot_date, kind) DO NOTHING;
+
+-- name: CountInventorySnapshotsForDate :one
+SELECT count(*)
+FROM inventory_snapshots
+WHERE snapshot_date = sqlc.arg(snapshot_date)::date;
+
+-- name: ListInventorySnapshots :many
+-- Snapshot metadata, newest first, without the payloads.
+SELECT snapshot_date, kind, row_count, captured_at
+FROM inventory_snapshots
+ORDER BY snapshot_date DESC, kind ASC
+LIMIT sqlc.arg(row_limit)::int;
+
+-- name: GetInventorySnapshot :one
+SELECT *
+FROM inventory_snapshots
+WHERE snapshot_date = sqlc.arg(snapshot_date)::date
+  AND kind = sqlc.arg(kind)::text;
@@ -0,0 +1,42 @@
+-- name: DeleteOktaUserFactorsBySourceAndUserExternalIDs :execrows
+DELETE FROM okta_user_factors f
+USING accounts a
+WHERE a.id = f.okta_user_account_id
+  AND a.source_kind = 'okta'
+  AND a.source_name = sqlc.arg(source_name)::text
+  AND a.external_id = ANY(sqlc.arg(user_external_ids)::text[]);
+
+-- name: InsertOktaUserFactorsBulk :execrows
+WITH input AS (
+  SELECT
+    (sqlc.arg(user_external_ids)::text[])[i] AS user_external_id,
+    (sqlc.arg(external_ids)::text[])[i] AS external_id,
+    (sqlc.arg(factor_types)::text[])[i] AS factor_type,
+    (sqlc.arg(providers)::text[])[i] AS provider,
+    (sqlc.arg(vendor_names)::text[])[i] AS vendor_name,
+    (sqlc.arg(statuses)::text[])[i] AS status,
+    (sqlc.arg(strengths)::text[])[i] AS strength,
+    (sqlc.arg(raw_jsons)::jsonb[])[i] AS raw_json
+  FROM generate_subscripts(sqlc.arg(user_external_ids)::text[], 1) AS s(i)
+)
+INSERT INTO okta_user_factors (okta_user_account_id, external_id, factor_type, provider, vendor_name, status, strength, raw_json)
+SELECT a.id, input.external_id, input.factor_type, input.provider, input.vendor_name, input.status, input.strength, input.raw_json
+FROM input
+JOIN accounts a
+  ON a.source_kind = 'okta'
+  AND a.source_name = sqlc.arg(source_name)::text
+  AND a.external_id = input.user_external_id
+ON CONFLICT (okta_user_account_id, external_id) DO UPDATE SET
+  factor_type = EXCLUDED.factor_type,
+  provider = EXCLUDED.provider,
+  vendor_name = EXCLUDED.vendor_name,
+  status = EXCLUDED.status,
+  strength = EXCLUDED.strength,
+  raw_json = EXCLUDED.raw_json,
+  updated_at = now();
+
+-- name: ListOktaUserFactorsByAccountID :many
+SELECT *
+FROM okta_user_factors
+WHERE okta_user_account_id = sqlc.arg(okta_user_account_id)::bigint
+ORDER BY factor_type ASC, id ASC;
@@ -0,0 +1,30 @@
+-- name: CreateOrg :one
+INSERT INTO orgs (slug, name)
+VALUES (lower(trim(sqlc.arg(slug)::text)), trim(sqlc.arg(name)::text))
+RETURNING *;
+
+-- name: GetOrgBySlug :one
+SELECT *
+FROM orgs
+WHERE slug = lower(trim(sqlc.arg(slug)::text));
+
+-- name: ListOrgs :many
+SELECT *
+FROM orgs
+ORDER BY id ASC;
+
+-- name: ListOrgSources :many
+SELECT *
+FROM org_sources
+ORDER BY source_kind, source_name;
+
+-- name: AssignOrgSource :exec
+INSERT INTO org_sources (source_kind, source_name, org_id)
+VALUES (trim(sqlc.arg(source_kind)::text), trim(sqlc.arg(source_name)::text), sqlc.arg(org_id)::bigint)
+ON CONFLICT (source_kind, source_name) DO UPDATE
+SET org_id = EXCLUDED.org_id;
+
+-- name: UnassignOrgSource :execrows
+DELETE FROM org_sources
+WHERE source_kind = trim(sqlc.arg(source_kind)::text)
+  AND source_name = trim(sqlc.arg(source_name)::text);
@@ -27,7 +27,7 @@ ON CONFLICT (saas_app_id, connector_kind, connector_source_name) DO UPDATE SET
   updated_at = now()
 WHERE NOT (
   saas_app_bindings.binding_source = 'manual'
-  AND EXCLUDED.binding_source = 'auto'
+  AND EXCLUDED.binding_source IN ('auto', 'suggested')
 );
 
 -- name: RecomputePrimarySaaSAppBindingsForAll :execrows
@@ -38,16 +38,27 @@ WITH ranked AS (
     row_number() OVER (
       PARTITION BY saas_app_id
       ORDER BY
-        CASE WHEN binding_source = 'manual' THEN 0 ELSE 1 END,
+        CASE binding_source WHEN 'manual' THEN 0 WHEN 'auto' THEN 1 ELSE 2 END,
         confidence DESC,
         id ASC
-    ) AS rn
+    ) AS rn,
+    binding_source
   FROM saas_app_bindings
 )
 UPDATE saas_app_bindings b
 SET
-  is_primary = (r.rn = 1),
+  is_primary = (r.rn = 1 AND r.binding_source <> 'suggested'),
   updated_at = now()
 FROM ranked r
 WHERE b.id = r.id
-  AND b.is_primary IS DISTINCT FROM (r.rn = 1);
+  AND b.is_primary IS DISTINCT FROM (r.rn = 1 AND r.binding_source <> 'suggested');
+
+-- name: ListSaaSAppBindingsBySaaSAppID :many
+SELECT *
+FROM saas_app_bindings
+WHERE saas_app_id = $1
+ORDER BY
+  is_primary DESC,
+  CASE binding_source WHEN 'manual' THEN 0 WHEN 'auto' THEN 1 ELSE 2 END,
+  confidence DESC,
+  id ASC;
@@ -1,4 +1,4 @@
--- name: UpsertSaaSAppEventsBulkBySource :execrows
+-- name: UpsertSaaSAppEventsBulkBySource :many
 WITH input AS (
   SELECT
     i,
@@ -37,60 +37,70 @@ dedup AS (
   FROM input
   WHERE trim(event_external_id) <> ''
   ORDER BY source_kind, source_name, signal_kind, event_external_id, i DESC
-)
-INSERT INTO saas_app_events (
-  saas_app_id,
-  source_kind,
-  source_name,
-  signal_kind,
-  event_external_id,
-  source_app_id,
-  source_app_name,
-  source_app_domain,
-  actor_external_id,
-  actor_email,
-  actor_display_name,
-  observed_at,
-  scopes_json,
-  raw_json,
-  seen_in_run_id,
-  seen_at,
-  updated_at
+),
+upserted AS (
+  INSERT INTO saas_app_events (
+    saas_app_id,
+    source_kind,
+    source_name,
+    signal_kind,
+    event_external_id,
+    source_app_id,
+    source_app_name,
+    source_app_domain,
+    actor_external_id,
+    actor_email,
+    actor_display_name,
+    observed_at,
+    scopes_json,
+    raw_json,
+    seen_in_run_id,
+    seen_at,
+    updated_at
+  )
+  SELECT
+    sa.id,
+    d.source_kind,
+    d.source_name,
+    d.signal_kind,
+    d.event_external_id,
+    d.source_app_id,
+    d.source_app_name,
+    d.source_app_domain,
+    d.actor_external_id,
+    lower(trim(d.actor_email)),
+    d.actor_display_name,
+    d.observed_at,
+    COALESCE(d.scopes_json, '[]'::jsonb),
+    COALESCE(d.raw_json, '{}'::jsonb),
+    sqlc.arg(seen_in_run_id)::bigint,
+    now(),
+    now()
+  FROM dedup d
+  JOIN saas_apps sa ON sa.canonical_key = d.canonical_key
+  ON CONFLICT (source_kind, source_name, signal_kind, event_external_id) DO UPDATE SET
+    saas_app_id = EXCLUDED.saas_app_id,
+    source_app_id = EXCLUDED.source_app_id,
+    source_app_name = EXCLUDED.source_app_name,
+    source_app_domain = EXCLUDED.source_app_domain,
+    actor_external_id = EXCLUDED.actor_external_id,
+    actor_email = EXCLUDED.actor_email,
+    actor_display_name = EXCLUDED.actor_display_name,
+    observed_at = EXCLUDED.observed_at,
+    scopes_json = EXCLUDED.scopes_json,
+    raw_json = EXCLUDED.raw_json,
+    seen_in_run_id = EXCLUDED.seen_in_run_id,
+    seen_at = EXCLUDED.seen_at,
+    updated_at = now()
+  RETURNING signal_kind, (xmax = 0) AS inserted
 )
 SELECT
-  sa.id,
-  d.source_kind,
-  d.source_name,
-  d.signal_kind,
-  d.event_external_id,
-  d.source_app_id,
-  d.source_app_name,
-  d.source_app_domain,
-  d.actor_external_id,
-  lower(trim(d.actor_email)),
-  d.actor_display_name,
-  d.observed_at,
-  COALESCE(d.scopes_json, '[]'::jsonb),
-  COALESCE(d.raw_json, '{}'::jsonb),
-  sqlc.arg(seen_in_run_id)::bigint,
-  now(),
-  now()
-FROM dedup d
-JOIN saas_apps sa ON sa.canonical_key = d.canonical_key
-ON CONFLICT (source_kind, source_name, signal_kind, event_external_id) DO UPDATE SET
-  saas_app_id = EXCLUDED.saas_app_id,
-  source_app_id = EXCLUDED.source_app_id,
-  source_app_name = EXCLUDED.source_app_name,
-  source_app_domain = EXCLUDED.source_app_domain,
-  actor_external_id = EXCLUDED.actor_external_id,
-  actor_email = EXCLUDED.actor_email,
-  actor_display_name = EXCLUDED.actor_display_name,
-  observed_at = EXCLUDED.observed_at,
-  scopes_json = EXCLUDED.scopes_json,
-  raw_json = EXCLUDED.raw_json,
-  seen_in_run_id = EXCLUDED.seen_in_run_id,
-  seen_at = EXCLUDED.seen_at,
-  updated_at = now();
+  signal_kind,
+  count(*) FILTER (WHERE inserted)::bigint AS inserted_count,
+  count(*) FILTER (WHERE NOT inserted)::bigint AS updated_count
+FROM upserted
+GROUP BY signal_kind
+ORDER BY signal_kind;
 
 -- name: PromoteSaaSAppEventsSeenInRunBySource :execrows
 UPDATE saas_app_events
@@ -136,7 +146,10 @@ SELECT
   COALESCE(NULLIF(trim(actor_display_name), ''), NULLIF(trim(actor_email), ''), NULLIF(trim(actor_external_id), ''), '')::text AS actor_label,
   COALESCE(NULLIF(trim(actor_email), ''), '')::text AS actor_email,
   COALESCE(NULLIF(trim(actor_external_id), ''), '')::text AS actor_external_id,
+  (array_agg(source_kind ORDER BY observed_at DESC))[1]::text AS source_kind,
+  (array_agg(source_name ORDER BY observed_at DESC))[1]::text AS source_name,
   count(*) AS event_count,
+  min(observed_at)::timestamptz AS first_observed_at,
   max(observed_at)::timestamptz AS last_observed_at
 FROM saas_app_events
 WHERE saas_app_id = sqlc.arg(saas_app_id)::bigint
@@ -147,3 +160,108 @@ GROUP BY actor_label, actor_email, actor_external_id
 ORDER BY event_count DESC, last_observed_at DESC
 LIMIT sqlc.arg(limit_rows)::int;
 
+
+-- name: ListSaaSAppWeeklyActorCountsBySaaSAppID :many
+-- Distinct actors per UTC week (weeks start Monday) observed using a discovered app since a
+-- cutoff. Expired events are kept so the series covers weeks older than the active window.
+SELECT
+  (date_trunc('week', observed_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC')::timestamptz AS week_start,
+  count(DISTINCT COALESCE(
+    NULLIF(lower(trim(actor_email)), ''),
+    NULLIF(trim(actor_external_id), ''),
+    NULLIF(trim(actor_display_name), '')
+  ))::bigint AS actor_count
+FROM saas_app_events
+WHERE saas_app_id = sqlc.arg(saas_app_id)::bigint
+  AND last_observed_run_id IS NOT NULL
+  AND observed_at >= sqlc.arg(observed_since)::timestamptz
+GROUP BY week_start
+ORDER BY week_start;
+
+-- name: ListSaaSAppSignalKindCountsBySaaSAppID :many
+-- Active events and distinct actors per signal kind for a discovered app, so SSO-federated
+-- use can be told apart from user-consented OAuth grants.
+SELECT
+  lower(trim(signal_kind))::text AS signal_kind,
+  count(*)::bigint AS event_count,
+  count(DISTINCT COALESCE(
+    NULLIF(lower(trim(actor_email)), ''),
+    NULLIF(trim(actor_external_id), ''),
+    NULLIF(trim(actor_display_name), '')
+  ))::bigint AS actor_count,
+  max(observed_at)::timestamptz AS last_observed_at
+FROM saas_app_events
+WHERE saas_app_id = sqlc.arg(saas_app_id)::bigint
+  AND expired_at IS NULL
+  AND last_observed_run_id IS NOT NULL
+GROUP BY 1
+ORDER BY event_count DESC, signal_kind;
+
+-- name: ListSaaSAppEventsExportPage :many
+SELECT
+  e.id,
+  e.saas_app_id,
+  sa.canonical_key AS saas_app_canonical_key,
+  sa.display_name AS saas_app_display_name,
+  e.source_kind,
+  e.source_name,
+  e.signal_kind,
+  e.event_external_id,
+  e.source_app_id,
+  e.source_app_name,
+  e.source_app_domain,
+  e.actor_external_id,
+  e.actor_email,
+  e.actor_display_name,
+  e.observed_at,
+  e.scopes_json,
+  e.raw_json,
+  e.expired_at
+FROM saas_app_events e
+JOIN saas_apps sa ON sa.id = e.saas_app_id
+WHERE e.id > sqlc.arg(after_id)::bigint
+  AND (sqlc.arg(source_kind)::text = '' OR e.source_kind = sqlc.arg(source_kind)::text)
+  AND (sqlc.arg(source_name)::text = '' OR e.source_name = sqlc.arg(source_name)::text)
+  AND (sqlc.arg(signal_kind)::text = '' OR e.signal_kind = sqlc.arg(signal_kind)::text)
+  AND (sqlc.narg(observed_from)::timestamptz IS NULL OR e.observed_at >= sqlc.narg(observed_from)::timestamptz)
+  AND (sqlc.narg(observed_to)::timestamptz IS NULL OR e.observed_at < sqlc.narg(observed_to)::timestamptz)
+ORDER BY e.id ASC
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: ListSaaSAppOAuthGrantsByActors :many
+WITH actor_accounts AS (
+  SELECT k.kind AS source_kind, n.name AS source_name, e.external_id
+  FROM unnest(sqlc.arg(source_kinds)::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest(sqlc.arg(source_names)::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+  JOIN unnest(sqlc.arg(external_ids)::text[]) WITH ORDINALITY AS e(external_id, ord) USING (ord)
+)
+SELECT
+  ev.saas_app_id,
+  sa.canonical_key AS saas_app_canonical_key,
+  sa.display_name AS saas_app_display_name,
+  ev.source_kind,
+  ev.source_name,
+  count(*) AS event_count,
+  min(ev.observed_at)::timestamptz AS first_observed_at,
+  max(ev.observed_at)::timestamptz AS last_observed_at,
+  (array_agg(ev.scopes_json ORDER BY ev.observed_at DESC, ev.id DESC))[1]::jsonb AS latest_scopes_json
+FROM saas_app_events ev
+JOIN saas_apps sa ON sa.id = ev.saas_app_id
+WHERE
+  ev.signal_kind = 'oauth_grant'
+  AND ev.expired_at IS NULL
+  AND ev.last_observed_run_id IS NOT NULL
+  AND (
+    lower(trim(ev.actor_email)) = ANY(sqlc.arg(emails)::text[])
+    OR lower(trim(ev.actor_external_id)) = ANY(sqlc.arg(emails)::text[])
+    OR EXISTS (
+      SELECT 1
+      FROM actor_accounts act
+      WHERE act.source_kind = ev.source_kind
+        AND act.source_name = ev.source_name
+        AND act.external_id = lower(trim(ev.actor_external_id))
+    )
+  )
+GROUP BY ev.saas_app_id, sa.canonical_key, sa.display_name, ev.source_kind, ev.source_name
+ORDER BY max(ev.observed_at) DESC, ev.saas_app_id ASC, ev.source_kind, ev.source_name
+LIMIT sqlc.arg(row_limit)::int;
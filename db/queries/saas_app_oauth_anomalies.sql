@@ -0,0 +1,91 @@
+-- name: ListSaaSAppOAuthGrantWindows :many
+-- For each SaaS app first seen within new_app_max_age_seconds, counts distinct OAuth grant actors
+-- and collects the scopes requested within window_seconds of the app's first observed grant.
+WITH grants AS (
+  SELECT
+    e.saas_app_id,
+    e.observed_at,
+    e.scopes_json,
+    COALESCE(NULLIF(trim(e.actor_external_id), ''), NULLIF(lower(trim(e.actor_email)), '')) AS actor_key
+  FROM saas_app_events e
+  WHERE e.signal_kind = 'oauth_grant'
+),
+first_grant AS (
+  SELECT g.saas_app_id, min(g.observed_at) AS first_grant_at
+  FROM grants g
+  GROUP BY g.saas_app_id
+),
+candidates AS (
+  SELECT
+    fg.saas_app_id,
+    LEAST(fg.first_grant_at, sa.first_seen_at) AS first_seen_at,
+    fg.first_grant_at
+  FROM first_grant fg
+  JOIN saas_apps sa ON sa.id = fg.saas_app_id
+  WHERE LEAST(fg.first_grant_at, sa.first_seen_at) >= now() - make_interval(secs => sqlc.arg(new_app_max_age_seconds)::int)
+),
+early_grants AS (
+  SELECT g.saas_app_id, g.actor_key, g.scopes_json
+  FROM grants g
+  JOIN candidates c ON c.saas_app_id = g.saas_app_id
+  WHERE g.observed_at < c.first_grant_at + make_interval(secs => sqlc.arg(window_seconds)::int)
+)
+SELECT
+  c.saas_app_id,
+  c.first_seen_at::timestamptz AS first_seen_at,
+  c.first_grant_at::timestamptz AS first_grant_at,
+  (
+    SELECT count(DISTINCT eg.actor_key)
+    FROM early_grants eg
+    WHERE eg.saas_app_id = c.saas_app_id
+  )::bigint AS actors_in_window,
+  COALESCE((
+    SELECT array_agg(DISTINCT lower(s.scope) ORDER BY lower(s.scope))
+    FROM early_grants eg
+    CROSS JOIN LATERAL jsonb_array_elements_text(
+      CASE WHEN jsonb_typeof(eg.scopes_json) = 'array' THEN eg.scopes_json ELSE '[]'::jsonb END
+    ) AS s(scope)
+    WHERE eg.saas_app_id = c.saas_app_id
+  ), '{}'::text[])::text[] AS first_sight_scopes
+FROM candidates c
+ORDER BY c.saas_app_id;
+
+-- name: UpsertSaaSAppOAuthAnomaly :exec
+INSERT INTO saas_app_oauth_anomalies (saas_app_id, reasons, first_grant_at, actors_in_window, high_risk_scopes, detected_at, updated_at)
+VALUES (
+  sqlc.arg(saas_app_id)::bigint,
+  sqlc.arg(reasons)::text[],
+  sqlc.arg(first_grant_at)::timestamptz,
+  sqlc.arg(actors_in_window)::bigint,
+  sqlc.arg(high_risk_scopes)::text[],
+  now(),
+  now()
+)
+ON CONFLICT (saas_app_id) DO UPDATE SET
+  reasons = EXCLUDED.reasons,
+  first_grant_at = EXCLUDED.first_grant_at,
+  actors_in_window = EXCLUDED.actors_in_window,
+  high_risk_scopes = EXCLUDED.high_risk_scopes,
+  updated_at = EXCLUDED.updated_at;
+
+-- name: GetSaaSAppOAuthAnomalyBySaaSAppID :one
+SELECT *
+FROM saas_app_oauth_anomalies
+WHERE saas_app_id = $1;
+
+-- name: ListSaaSAppOAuthAnomalies :many
+SELECT
+  a.saas_app_id,
+  a.reasons,
+  a.first_grant_at,
+  a.actors_in_window,
+  a.high_risk_scopes,
+  a.detected_at,
+  sa.canonical_key,
+  sa.display_name,
+  sa.primary_domain,
+  sa.vendor_name
+FROM saas_app_oauth_anomalies a
+JOIN saas_apps sa ON sa.id = a.saas_app_id
+ORDER BY a.detected_at DESC, a.saas_app_id DESC
+LIMIT sqlc.arg(limit_rows)::int;
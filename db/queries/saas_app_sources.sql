@@ -55,6 +55,15 @@ ON CONFLICT (source_kind, source_name, source_app_id) DO UPDATE SET
   seen_at = EXCLUDED.seen_at,
   updated_at = now();
 
+-- name: CountSaaSAppSourcesGroupedBySource :many
+SELECT sas.source_kind, sas.source_name, count(DISTINCT sas.saas_app_id) AS app_count
+FROM saas_app_sources sas
+WHERE
+  sas.expired_at IS NULL
+  AND sas.last_observed_run_id IS NOT NULL
+GROUP BY sas.source_kind, sas.source_name
+ORDER BY sas.source_kind, sas.source_name;
+
 -- name: PromoteSaaSAppSourcesSeenInRunBySource :execrows
 UPDATE saas_app_sources
 SET
@@ -106,8 +115,13 @@ WHERE sas.source_kind = 'okta'
   AND sas.last_observed_run_id IS NOT NULL
 ORDER BY sas.saas_app_id ASC, m.integration_kind ASC;
 
--- name: ListEntraDiscoveryAppIDsWithManagedAssetsBySource :many
-SELECT DISTINCT sas.saas_app_id
+-- name: ListEntraDiscoveryAutoBindCandidatesBySource :many
+SELECT
+  sas.saas_app_id,
+  bool_or(
+    sas.source_app_id <> ''
+    AND (aa.external_id = sas.source_app_id OR aa.parent_external_id = sas.source_app_id)
+  )::boolean AS client_id_match
 FROM saas_app_sources sas
 JOIN app_assets aa
   ON aa.source_kind = 'entra'
@@ -124,4 +138,5 @@ WHERE sas.source_kind = 'entra'
   AND sas.source_name = sqlc.arg(source_name)::text
   AND sas.expired_at IS NULL
   AND sas.last_observed_run_id IS NOT NULL
+GROUP BY sas.saas_app_id
 ORDER BY sas.saas_app_id ASC;
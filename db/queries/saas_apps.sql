@@ -6,6 +6,7 @@ WITH input AS (
     (sqlc.arg(display_names)::text[])[i] AS display_name,
     (sqlc.arg(primary_domains)::text[])[i] AS primary_domain,
     (sqlc.arg(vendor_names)::text[])[i] AS vendor_name,
+    (sqlc.arg(categories)::text[])[i] AS category,
     (sqlc.arg(first_seen_ats)::timestamptz[])[i] AS first_seen_at,
     (sqlc.arg(last_seen_ats)::timestamptz[])[i] AS last_seen_at
   FROM generate_subscripts(sqlc.arg(canonical_keys)::text[], 1) AS s(i)
@@ -16,6 +17,7 @@ dedup AS (
     display_name,
     primary_domain,
     vendor_name,
+    category,
     first_seen_at,
     last_seen_at
   FROM input
@@ -26,6 +28,7 @@ INSERT INTO saas_apps (
   display_name,
   primary_domain,
   vendor_name,
+  category,
   first_seen_at,
   last_seen_at,
   updated_at
@@ -35,6 +38,7 @@ SELECT
   d.display_name,
   d.primary_domain,
   d.vendor_name,
+  d.category,
   COALESCE(d.first_seen_at, now()),
   COALESCE(d.last_seen_at, now()),
   now()
@@ -52,6 +56,10 @@ ON CONFLICT (canonical_key) DO UPDATE SET
     WHEN trim(EXCLUDED.vendor_name) <> '' THEN EXCLUDED.vendor_name
     ELSE saas_apps.vendor_name
   END,
+  category = CASE
+    WHEN trim(EXCLUDED.category) <> '' THEN EXCLUDED.category
+    ELSE saas_apps.category
+  END,
   first_seen_at = LEAST(saas_apps.first_seen_at, COALESCE(EXCLUDED.first_seen_at, saas_apps.first_seen_at)),
   last_seen_at = GREATEST(saas_apps.last_seen_at, COALESCE(EXCLUDED.last_seen_at, saas_apps.last_seen_at)),
   updated_at = now();
@@ -92,6 +100,10 @@ WHERE EXISTS (
     sqlc.arg(risk_level)::text = ''
     OR sa.risk_level = sqlc.arg(risk_level)::text
   )
+  AND (
+    sqlc.arg(category)::text = ''
+    OR sa.category = sqlc.arg(category)::text
+  )
   AND (
     sqlc.arg(query)::text = ''
     OR sa.display_name ILIKE ('%' || sqlc.arg(query)::text || '%')
@@ -152,6 +164,10 @@ WHERE EXISTS (
     sqlc.arg(risk_level)::text = ''
     OR sa.risk_level = sqlc.arg(risk_level)::text
   )
+  AND (
+    sqlc.arg(category)::text = ''
+    OR sa.category = sqlc.arg(category)::text
+  )
   AND (
     sqlc.arg(query)::text = ''
     OR sa.display_name ILIKE ('%' || sqlc.arg(query)::text || '%')
@@ -167,6 +183,53 @@ ORDER BY
 LIMIT sqlc.arg(page_limit)::int
 OFFSET sqlc.arg(page_offset)::int;
 
+-- name: CountSaaSAppsGroupedByCategory :many
+WITH configured_sources AS (
+  SELECT
+    k.kind AS source_kind,
+    n.name AS source_name
+  FROM unnest(sqlc.arg(configured_source_kinds)::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest(sqlc.arg(configured_source_names)::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+)
+SELECT sa.category, count(*) AS app_count
+FROM saas_apps sa
+WHERE EXISTS (
+  SELECT 1
+  FROM saas_app_sources sas
+  JOIN configured_sources cs
+    ON cs.source_kind = sas.source_kind
+   AND cs.source_name = sas.source_name
+  WHERE sas.saas_app_id = sa.id
+    AND sas.expired_at IS NULL
+    AND sas.last_observed_run_id IS NOT NULL
+    AND (
+      sqlc.arg(source_kind)::text = ''
+      OR sas.source_kind = sqlc.arg(source_kind)::text
+    )
+    AND (
+      sqlc.arg(source_name)::text = ''
+      OR sas.source_name = sqlc.arg(source_name)::text
+    )
+)
+  AND sa.category <> ''
+  AND (
+    sqlc.arg(managed_state)::text = ''
+    OR sa.managed_state = sqlc.arg(managed_state)::text
+  )
+  AND (
+    sqlc.arg(risk_level)::text = ''
+    OR sa.risk_level = sqlc.arg(risk_level)::text
+  )
+  AND (
+    sqlc.arg(query)::text = ''
+    OR sa.display_name ILIKE ('%' || sqlc.arg(query)::text || '%')
+    OR sa.primary_domain ILIKE ('%' || sqlc.arg(query)::text || '%')
+    OR sa.vendor_name ILIKE ('%' || sqlc.arg(query)::text || '%')
+    OR sa.canonical_key ILIKE ('%' || sqlc.arg(query)::text || '%')
+  )
+GROUP BY sa.category
+ORDER BY app_count DESC, sa.category;
+
 -- name: GetSaaSAppByID :one
 SELECT *
 FROM saas_apps
@@ -220,6 +283,73 @@ WHERE sa.risk_score >= 60
 ORDER BY sa.risk_score DESC, sa.last_seen_at DESC, sa.id ASC
 LIMIT sqlc.arg(limit_rows)::int;
 
+-- name: ListSaaSAppInventoryReport :many
+WITH configured_sources AS (
+  SELECT
+    k.kind AS source_kind,
+    n.name AS source_name
+  FROM unnest(sqlc.arg(configured_source_kinds)::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest(sqlc.arg(configured_source_names)::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+),
+active_sources AS (
+  SELECT sas.saas_app_id, sas.source_kind
+  FROM saas_app_sources sas
+  JOIN configured_sources cs
+    ON cs.source_kind = sas.source_kind
+   AND cs.source_name = sas.source_name
+  WHERE sas.expired_at IS NULL
+    AND sas.last_observed_run_id IS NOT NULL
+),
+source_kinds AS (
+  SELECT
+    s.saas_app_id,
+    string_agg(DISTINCT s.source_kind, ',' ORDER BY s.source_kind) AS discovery_sources
+  FROM active_sources s
+  GROUP BY s.saas_app_id
+),
+actor_counts AS (
+  SELECT
+    e.saas_app_id,
+    count(DISTINCT COALESCE(NULLIF(trim(e.actor_external_id), ''), NULLIF(lower(trim(e.actor_email)), ''))) AS user_count,
+    count(DISTINCT COALESCE(NULLIF(trim(e.actor_external_id), ''), NULLIF(lower(trim(e.actor_email)), ''))) FILTER (WHERE e.observed_at >= now() - interval '30 days') AS actors_30d
+  FROM saas_app_events e
+  WHERE e.expired_at IS NULL
+    AND e.last_observed_run_id IS NOT NULL
+  GROUP BY e.saas_app_id
+),
+primary_binding AS (
+  SELECT
+    b.saas_app_id,
+    b.connector_kind,
+    b.connector_source_name,
+    b.binding_source
+  FROM saas_app_bindings b
+  WHERE b.is_primary
+)
+SELECT
+  sa.id,
+  sa.canonical_key,
+  sa.display_name,
+  sa.primary_domain,
+  sa.vendor_name,
+  sa.managed_state,
+  sa.risk_level,
+  sa.first_seen_at,
+  sa.last_seen_at,
+  COALESCE(ac.user_count, 0)::bigint AS user_count,
+  COALESCE(ac.actors_30d, 0)::bigint AS actors_30d,
+  sk.discovery_sources::text AS discovery_sources,
+  COALESCE(pb.connector_kind, '')::text AS binding_connector_kind,
+  COALESCE(pb.connector_source_name, '')::text AS binding_connector_source_name,
+  COALESCE(pb.binding_source, '')::text AS binding_source
+FROM saas_apps sa
+JOIN source_kinds sk ON sk.saas_app_id = sa.id
+LEFT JOIN actor_counts ac ON ac.saas_app_id = sa.id
+LEFT JOIN primary_binding pb ON pb.saas_app_id = sa.id
+ORDER BY
+  lower(COALESCE(NULLIF(trim(sa.display_name), ''), sa.canonical_key)) ASC,
+  sa.id ASC;
+
 -- name: CountSaaSAppsGroupedByManagedState :many
 WITH configured_sources AS (
   SELECT
@@ -0,0 +1,42 @@
+-- Resumable per-item progress for long sync enumeration stages.
+
+-- name: ListSyncCheckpoints :many
+SELECT
+  item_key,
+  payload_json
+FROM sync_checkpoints
+WHERE
+  source_kind = sqlc.arg(source_kind)
+  AND source_name = sqlc.arg(source_name)
+  AND stage = sqlc.arg(stage)
+  AND checkpointed_at >= sqlc.arg(since)::timestamptz
+ORDER BY item_key;
+
+-- name: UpsertSyncCheckpoint :exec
+INSERT INTO sync_checkpoints (
+  source_kind,
+  source_name,
+  stage,
+  item_key,
+  payload_json,
+  checkpointed_at
+)
+VALUES (
+  sqlc.arg(source_kind),
+  sqlc.arg(source_name),
+  sqlc.arg(stage),
+  sqlc.arg(item_key),
+  sqlc.arg(payload_json),
+  now()
+)
+ON CONFLICT (source_kind, source_name, stage, item_key) DO UPDATE
+SET
+  payload_json = EXCLUDED.payload_json,
+  checkpointed_at = EXCLUDED.checkpointed_at;
+
+-- name: DeleteSyncCheckpoints :exec
+DELETE FROM sync_checkpoints
+WHERE
+  source_kind = sqlc.arg(source_kind)
+  AND source_name = sqlc.arg(source_name)
+  AND stage = sqlc.arg(stage);
@@ -12,6 +12,16 @@ WHERE source_kind = $1
 ORDER BY finished_at DESC
 LIMIT $3;
 
+-- name: ListRecentSuccessfulSyncRunsBySource :many
+SELECT id, finished_at
+FROM sync_runs
+WHERE source_kind = $1
+  AND source_name = $2
+  AND finished_at IS NOT NULL
+  AND status = 'success'
+ORDER BY finished_at DESC, id DESC
+LIMIT $3;
+
 -- name: ListRecentNonSuccessSyncRunsBySource :many
 SELECT id, status, finished_at, error_kind, message
 FROM sync_runs
@@ -81,7 +91,8 @@ last_run AS (
     r.status AS last_run_status,
     r.started_at AS last_run_started_at,
     r.finished_at AS last_run_finished_at,
-    r.error_kind AS last_run_error_kind
+    r.error_kind AS last_run_error_kind,
+    jsonb_array_length(r.warnings) AS last_run_warning_count
   FROM sync_runs r
   JOIN requested q
     ON r.source_kind = q.source_kind
@@ -126,6 +137,7 @@ SELECT
   lr.last_run_started_at,
   lr.last_run_finished_at,
   lr.last_run_error_kind,
+  lr.last_run_warning_count,
   ls.last_success_at::timestamptz AS last_success_at,
   COALESCE(s.finished_count_7d, 0) AS finished_count_7d,
   COALESCE(s.success_count_7d, 0) AS success_count_7d,
@@ -142,6 +154,24 @@ LEFT JOIN stats_7d s
  AND s.source_name = q.source_name
 ORDER BY q.source_kind, q.source_name;
 
+-- name: GetLatestRunningSyncRunIDBySource :one
+SELECT id
+FROM sync_runs
+WHERE source_kind = $1
+  AND source_name = $2
+  AND status = 'running'
+ORDER BY started_at DESC, id DESC
+LIMIT 1;
+
+-- name: GetLatestSyncRunIDStartedSinceBySource :one
+SELECT id
+FROM sync_runs
+WHERE source_kind = $1
+  AND source_name = $2
+  AND started_at >= $3
+ORDER BY started_at DESC, id DESC
+LIMIT 1;
+
 -- name: FailSyncRun :exec
 UPDATE sync_runs
 SET status = $2, finished_at = now(), message = $3, error_kind = $4
@@ -152,6 +182,110 @@ UPDATE sync_runs
 SET status = 'success', finished_at = now(), message = '', stats = $2, error_kind = ''
 WHERE id = $1;
 
+-- name: SetSyncRunWarnings :exec
+UPDATE sync_runs
+SET warnings = $2
+WHERE id = $1;
+
+-- name: AppendSyncRunWarnings :exec
+UPDATE sync_runs
+SET warnings = warnings || sqlc.arg(warnings)::jsonb
+WHERE id = sqlc.arg(id)::bigint;
+
+-- name: GetLatestSuccessfulSyncRunStatsBySource :one
+SELECT stats
+FROM sync_runs
+WHERE source_kind = $1
+  AND source_name = $2
+  AND status = 'success'
+ORDER BY finished_at DESC, id DESC
+LIMIT 1;
+
+-- name: SetSyncRunAPIRequestCount :exec
+UPDATE sync_runs
+SET api_request_count = $2,
+  api_stats = $3
+WHERE id = $1;
+
+-- name: GetSyncRunAPIRequestUsage :one
+SELECT
+  COALESCE((
+    SELECT sum(r.api_request_count)
+    FROM sync_runs r
+    WHERE r.source_kind = ANY(sqlc.arg(source_kinds)::text[])
+      AND r.source_name = sqlc.arg(source_name)::text
+      AND r.started_at >= sqlc.arg(since)::timestamptz
+  ), 0)::bigint AS used_since,
+  COALESCE((
+    SELECT r.api_request_count
+    FROM sync_runs r
+    WHERE r.source_kind = sqlc.arg(run_source_kind)::text
+      AND r.source_name = sqlc.arg(source_name)::text
+      AND r.finished_at IS NOT NULL
+    ORDER BY r.finished_at DESC, r.id DESC
+    LIMIT 1
+  ), 0)::bigint AS last_run_count;
+
+-- name: CountSyncRunsByFilters :one
+SELECT count(*)
+FROM sync_runs r
+WHERE (sqlc.arg(source_kind)::text = '' OR r.source_kind = sqlc.arg(source_kind)::text)
+  AND (sqlc.arg(source_name)::text = '' OR r.source_name = sqlc.arg(source_name)::text)
+  AND (
+    sqlc.arg(outcome)::text = ''
+    OR (sqlc.arg(outcome)::text = 'warning' AND r.status = 'success' AND jsonb_array_length(r.warnings) > 0)
+    OR r.status = sqlc.arg(outcome)::text
+  )
+  AND EXISTS (
+    SELECT 1
+    FROM unnest(sqlc.arg(visible_source_kinds)::text[]) WITH ORDINALITY AS k(kind, ord)
+    JOIN unnest(sqlc.arg(visible_source_names)::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+    WHERE k.kind = r.source_kind
+      AND n.name = r.source_name
+  );
+
+-- name: ListSyncRunsPageByFilters :many
+SELECT r.*
+FROM sync_runs r
+WHERE (sqlc.arg(source_kind)::text = '' OR r.source_kind = sqlc.arg(source_kind)::text)
+  AND (sqlc.arg(source_name)::text = '' OR r.source_name = sqlc.arg(source_name)::text)
+  AND (
+    sqlc.arg(outcome)::text = ''
+    OR (sqlc.arg(outcome)::text = 'warning' AND r.status = 'success' AND jsonb_array_length(r.warnings) > 0)
+    OR r.status = sqlc.arg(outcome)::text
+  )
+  AND EXISTS (
+    SELECT 1
+    FROM unnest(sqlc.arg(visible_source_kinds)::text[]) WITH ORDINALITY AS k(kind, ord)
+    JOIN unnest(sqlc.arg(visible_source_names)::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+    WHERE k.kind = r.source_kind
+      AND n.name = r.source_name
+  )
+ORDER BY r.started_at DESC, r.id DESC
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
+
+-- name: ListSyncRunSources :many
+SELECT DISTINCT source_kind, source_name
+FROM sync_runs
+ORDER BY source_kind, source_name;
+
+-- name: GetSyncRun :one
+SELECT *
+FROM sync_runs
+WHERE id = sqlc.arg(id)::bigint;
+
+-- name: ListFinishedSyncRunsForRetention :many
+SELECT id, source_kind, source_name
+FROM sync_runs
+WHERE finished_at IS NOT NULL
+ORDER BY source_kind, source_name, started_at DESC, id DESC;
+
+-- name: DeleteSyncRun :execrows
+DELETE FROM sync_runs
+WHERE id = sqlc.arg(id)::bigint
+  AND finished_at IS NOT NULL;
+
 -- name: AcquireAdvisoryLock :exec
 SELECT pg_advisory_lock($1::bigint);
 
@@ -15,6 +15,10 @@ const (
 
 	ResourceKindAWSAccount = "aws_account"
 
+	ResourceKindEntraDirectoryRole = "entra_directory_role"
+
+	ResourceKindGoogleGroup = "google_group"
+
 	ResourceKindVaultPolicy       = "vault_policy"
 	ResourceKindVaultGroup        = "vault_group"
 	ResourceKindVaultAuthMount    = "vault_auth_mount"
@@ -75,7 +79,7 @@ func DisplayResourceLabel(resourceRef string, rawJSON []byte) string {
 		return strings.TrimSpace(resourceRef)
 	}
 
-	if resourceKind == ResourceKindDatadogRole && len(rawJSON) > 0 {
+	if (resourceKind == ResourceKindDatadogRole || resourceKind == ResourceKindEntraDirectoryRole) && len(rawJSON) > 0 {
 		var payload struct {
 			RoleID   string `json:"role_id"`
 			RoleName string `json:"role_name"`
@@ -0,0 +1,338 @@
+package accessgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+)
+
+// Escalation detection walks one source's entitlement graph: accounts, the groups and teams they
+// belong to or manage, and the resources those grant. A path is flagged when it ends at a level
+// above the highest one the account holds itself, either a grant that only a group holds or a
+// role whose holder can hand out a higher level.
+
+const (
+	// maxEscalationDepth bounds how many edges a path may follow from its account.
+	maxEscalationDepth = 4
+	// maxEscalationVisited bounds how many nodes one account's traversal may visit.
+	maxEscalationVisited = 256
+)
+
+// Edge labels of an escalation path step.
+const (
+	EscalationEdgeMemberOf = "member of"
+	EscalationEdgeManages  = "manages"
+	EscalationEdgeActsAs   = "acts as"
+	EscalationEdgeHolds    = "holds"
+	EscalationEdgeGrants   = "grants"
+)
+
+// escalationAccountPrefix marks account nodes, whose ids are external ids rather than
+// canonical resource refs.
+const escalationAccountPrefix = "account:"
+
+// escalationMembershipKinds are the entitlements that make an account part of a group-like
+// resource, and so edges of the graph rather than leaves.
+var escalationMembershipKinds = map[string]bool{
+	"github_team_member":  true,
+	"vault_group_member":  true,
+	"google_group_member": true,
+}
+
+// escalationManagerPermissions are the membership permissions that let the holder add members,
+// themselves included.
+var escalationManagerPermissions = map[string]bool{
+	"owner":   true,
+	"manager": true,
+}
+
+// escalationRule marks a role whose holder can grant a level they do not hold. Role is a
+// path.Match pattern on the lowercased role or policy name.
+type escalationRule struct {
+	Kind   string
+	Role   string
+	Grants registry.PrivilegeLevel
+	Reason string
+}
+
+// escalationRules are deliberately few: only roles documented to reach the top of their
+// provider's ladder are listed, so a flagged path is worth a reviewer's time.
+var escalationRules = []escalationRule{
+	{Kind: "entra_directory_role", Role: "application administrator", Grants: registry.PrivilegeOwner, Reason: "can add credentials to any application, including applications assigned privileged directory roles"},
+	{Kind: "entra_directory_role", Role: "cloud application administrator", Grants: registry.PrivilegeOwner, Reason: "can add credentials to any application, including applications assigned privileged directory roles"},
+	{Kind: "entra_directory_role", Role: "privileged authentication administrator", Grants: registry.PrivilegeOwner, Reason: "can reset the credentials of any user, including global administrators"},
+	{Kind: "vault_entity_policy", Role: "*admin*", Grants: registry.PrivilegeOwner, Reason: "admin policies conventionally manage sys/policy, so the holder can write and attach a root-equivalent policy"},
+	{Kind: "vault_group_policy", Role: "*admin*", Grants: registry.PrivilegeOwner, Reason: "admin policies conventionally manage sys/policy, so the holder can write and attach a root-equivalent policy"},
+	{Kind: "vault_auth_role_policy", Role: "*admin*", Grants: registry.PrivilegeOwner, Reason: "admin policies conventionally manage sys/policy, so the holder can write and attach a root-equivalent policy"},
+}
+
+// EscalationGrant is one active entitlement of an account, as escalation detection reads it.
+type EscalationGrant struct {
+	SourceKind        string
+	SourceName        string
+	AccountExternalID string
+	Kind              string
+	Resource          string
+	Permission        string
+	Level             registry.PrivilegeLevel
+	RawJSON           []byte
+}
+
+// EscalationStep is one node of a path. Edge is how the node was reached from the previous step
+// and is empty for the account the path starts at.
+type EscalationStep struct {
+	Node string `json:"node"`
+	Edge string `json:"edge,omitempty"`
+}
+
+// EscalationPath is a flagged path from an account to a level above the one it holds.
+type EscalationPath struct {
+	SourceKind        string
+	SourceName        string
+	AccountExternalID string
+	HeldLevel         registry.PrivilegeLevel
+	ReachableLevel    registry.PrivilegeLevel
+	Steps             []EscalationStep
+	Reason            string
+}
+
+// EscalationAccountNode returns the node id of the account with externalID.
+func EscalationAccountNode(externalID string) string {
+	return escalationAccountPrefix + strings.TrimSpace(externalID)
+}
+
+// EscalationAccountExternalID returns the external id of an account node.
+func EscalationAccountExternalID(node string) (string, bool) {
+	return strings.CutPrefix(node, escalationAccountPrefix)
+}
+
+type escalationEdge struct {
+	to    string
+	label string
+	grant *EscalationGrant
+}
+
+type escalationSourceKey struct {
+	kind string
+	name string
+}
+
+// escalationGraph is the entitlement graph of one source.
+type escalationGraph struct {
+	edges map[string][]escalationEdge
+	seen  map[string]bool
+	held  map[string]registry.PrivilegeLevel
+}
+
+func (g *escalationGraph) addEdge(from string, edge escalationEdge) {
+	key := from + "\x00" + edge.label + "\x00" + edge.to
+	if g.seen[key] {
+		return
+	}
+	g.seen[key] = true
+	g.edges[from] = append(g.edges[from], edge)
+}
+
+// DetectEscalationPaths returns the escalation paths found in grants, one per account and end
+// node, highest reachable level first. Grants of different sources never share a path.
+func DetectEscalationPaths(grants []EscalationGrant) []EscalationPath {
+	graphs := map[escalationSourceKey]*escalationGraph{}
+	var sources []escalationSourceKey
+	for i := range grants {
+		grant := &grants[i]
+		key := escalationSourceKey{kind: strings.TrimSpace(grant.SourceKind), name: strings.TrimSpace(grant.SourceName)}
+		graph, ok := graphs[key]
+		if !ok {
+			graph = &escalationGraph{edges: map[string][]escalationEdge{}, seen: map[string]bool{}, held: map[string]registry.PrivilegeLevel{}}
+			graphs[key] = graph
+			sources = append(sources, key)
+		}
+		addEscalationGrant(graph, grant)
+	}
+
+	var out []EscalationPath
+	for _, key := range sources {
+		graph := graphs[key]
+		accounts := make([]string, 0, len(graph.held))
+		for account := range graph.held {
+			accounts = append(accounts, account)
+		}
+		sort.Strings(accounts)
+		for _, account := range accounts {
+			for _, found := range traverseEscalationGraph(graph, account) {
+				found.SourceKind, found.SourceName = key.kind, key.name
+				out = append(out, found)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].ReachableLevel != out[j].ReachableLevel {
+			return out[i].ReachableLevel > out[j].ReachableLevel
+		}
+		if out[i].SourceKind != out[j].SourceKind {
+			return out[i].SourceKind < out[j].SourceKind
+		}
+		if out[i].SourceName != out[j].SourceName {
+			return out[i].SourceName < out[j].SourceName
+		}
+		return out[i].AccountExternalID < out[j].AccountExternalID
+	})
+	return out
+}
+
+// addEscalationGrant adds grant's edges: membership entitlements link the account to the group,
+// entitlements inherited through a group hang off the group, and the rest off the account.
+func addEscalationGrant(graph *escalationGraph, grant *EscalationGrant) {
+	externalID := strings.TrimSpace(grant.AccountExternalID)
+	resource := strings.TrimSpace(grant.Resource)
+	if externalID == "" || resource == "" {
+		return
+	}
+	account := EscalationAccountNode(externalID)
+	if level, ok := graph.held[account]; !ok || grant.Level > level {
+		graph.held[account] = grant.Level
+	}
+
+	kind := strings.ToLower(strings.TrimSpace(grant.Kind))
+	if escalationMembershipKinds[kind] {
+		label := EscalationEdgeMemberOf
+		if escalationManagerPermissions[strings.ToLower(strings.TrimSpace(grant.Permission))] {
+			label = EscalationEdgeManages
+		}
+		graph.addEdge(account, escalationEdge{to: resource, label: label, grant: grant})
+		// A group that is itself assigned roles is an account of the same source.
+		if resourceKind, groupID, ok := ParseCanonicalResourceRef(resource); ok && resourceKind == ResourceKindGoogleGroup {
+			graph.addEdge(resource, escalationEdge{to: EscalationAccountNode(groupID), label: EscalationEdgeActsAs})
+		}
+		return
+	}
+	if group := escalationGrantGroup(kind, resource, grant.RawJSON); group != "" {
+		graph.addEdge(group, escalationEdge{to: resource, label: EscalationEdgeGrants, grant: grant})
+		return
+	}
+	graph.addEdge(account, escalationEdge{to: resource, label: EscalationEdgeHolds, grant: grant})
+}
+
+// escalationGrantGroup returns the group an entitlement is inherited through, or "" for a
+// direct one.
+func escalationGrantGroup(kind, resource string, rawJSON []byte) string {
+	var payload struct {
+		Team      string `json:"team"`
+		GroupID   string `json:"group_id"`
+		GroupName string `json:"group_name"`
+	}
+	if len(rawJSON) == 0 || json.Unmarshal(rawJSON, &payload) != nil {
+		return ""
+	}
+	switch kind {
+	case "github_team_repo_permission":
+		_, repo, ok := ParseCanonicalResourceRef(resource)
+		org, _, hasOrg := strings.Cut(repo, "/")
+		if team := strings.TrimSpace(payload.Team); ok && hasOrg && team != "" {
+			return ResourceKindGitHubTeam + ":" + org + "/" + team
+		}
+	case "vault_group_policy":
+		if group := firstNonEmpty(payload.GroupName, payload.GroupID); group != "" {
+			return ResourceKindVaultGroup + ":" + group
+		}
+	}
+	return ""
+}
+
+// traverseEscalationGraph walks breadth first from account, so each end node is reported with
+// its shortest path, and stops at maxEscalationDepth edges or maxEscalationVisited nodes.
+func traverseEscalationGraph(graph *escalationGraph, account string) []EscalationPath {
+	type visit struct {
+		node  string
+		depth int
+		steps []EscalationStep
+	}
+	held := graph.held[account]
+	visited := map[string]bool{account: true}
+	queue := []visit{{node: account, steps: []EscalationStep{{Node: account}}}}
+	found := map[string]int{}
+	var out []EscalationPath
+
+	flag := func(steps []EscalationStep, level registry.PrivilegeLevel, reason string) {
+		end := steps[len(steps)-1].Node
+		if idx, ok := found[end]; ok {
+			if out[idx].ReachableLevel < level {
+				out[idx].ReachableLevel, out[idx].Reason = level, reason
+			}
+			return
+		}
+		externalID, _ := EscalationAccountExternalID(account)
+		found[end] = len(out)
+		out = append(out, EscalationPath{
+			AccountExternalID: externalID,
+			HeldLevel:         held,
+			ReachableLevel:    level,
+			Steps:             steps,
+			Reason:            reason,
+		})
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current.depth >= maxEscalationDepth {
+			continue
+		}
+		for _, edge := range graph.edges[current.node] {
+			steps := append(append([]EscalationStep(nil), current.steps...), EscalationStep{Node: edge.to, Edge: edge.label})
+			if edge.grant != nil {
+				if edge.grant.Level > held {
+					flag(steps, edge.grant.Level, fmt.Sprintf("reaches %s through %s", edge.grant.Level, steps[1].Node))
+				}
+				if rule, ok := matchEscalationRule(edge.grant); ok && rule.Grants > held {
+					flag(steps, rule.Grants, rule.Reason)
+				}
+			}
+			if visited[edge.to] || len(visited) >= maxEscalationVisited {
+				continue
+			}
+			visited[edge.to] = true
+			queue = append(queue, visit{node: edge.to, depth: current.depth + 1, steps: steps})
+		}
+	}
+	return out
+}
+
+// matchEscalationRule returns the first rule matching grant's kind and role. The role is the
+// role_name in the raw JSON when present, and the resource's external id otherwise.
+func matchEscalationRule(grant *EscalationGrant) (escalationRule, bool) {
+	kind := strings.ToLower(strings.TrimSpace(grant.Kind))
+	role := ""
+	var payload struct {
+		RoleName string `json:"role_name"`
+	}
+	if len(grant.RawJSON) > 0 && json.Unmarshal(grant.RawJSON, &payload) == nil {
+		role = payload.RoleName
+	}
+	if strings.TrimSpace(role) == "" {
+		_, role, _ = ParseCanonicalResourceRef(grant.Resource)
+	}
+	role = strings.ToLower(strings.TrimSpace(role))
+	for _, rule := range escalationRules {
+		if rule.Kind != kind {
+			continue
+		}
+		if matched, err := path.Match(rule.Role, role); err == nil && matched {
+			return rule, true
+		}
+	}
+	return escalationRule{}, false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
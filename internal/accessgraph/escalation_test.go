@@ -0,0 +1,107 @@
+package accessgraph
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+)
+
+func TestDetectEscalationPathsFlagsGroupManagerReachingSuperAdmin(t *testing.T) {
+	t.Parallel()
+
+	// alice manages a group that is assigned the super admin role, so she can add herself to it.
+	paths := DetectEscalationPaths([]EscalationGrant{
+		{SourceKind: "google_workspace", SourceName: "example.com", AccountExternalID: "alice", Kind: "google_group_member", Resource: "google_group:g1", Permission: "manager", Level: registry.PrivilegeAdmin},
+		{SourceKind: "google_workspace", SourceName: "example.com", AccountExternalID: "g1", Kind: "google_admin_role", Resource: "google_admin_role:r1", Permission: "global", Level: registry.PrivilegeOwner, RawJSON: []byte(`{"role_name":"Super Admin"}`)},
+	})
+
+	var found *EscalationPath
+	for i := range paths {
+		if paths[i].AccountExternalID == "alice" {
+			found = &paths[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("paths=%+v, want a path for alice", paths)
+	}
+	if found.HeldLevel != registry.PrivilegeAdmin || found.ReachableLevel != registry.PrivilegeOwner {
+		t.Fatalf("held=%s reachable=%s, want admin and owner", found.HeldLevel, found.ReachableLevel)
+	}
+	want := []EscalationStep{
+		{Node: "account:alice"},
+		{Node: "google_group:g1", Edge: EscalationEdgeManages},
+		{Node: "account:g1", Edge: EscalationEdgeActsAs},
+		{Node: "google_admin_role:r1", Edge: EscalationEdgeHolds},
+	}
+	if fmt.Sprint(found.Steps) != fmt.Sprint(want) {
+		t.Fatalf("steps=%v, want %v", found.Steps, want)
+	}
+}
+
+func TestDetectEscalationPathsFlagsRoleThatGrantsHigherLevel(t *testing.T) {
+	t.Parallel()
+
+	paths := DetectEscalationPaths([]EscalationGrant{
+		{SourceKind: "vault", SourceName: "prod", AccountExternalID: "entity:bob", Kind: "vault_group_member", Resource: "vault_group:ops", Permission: "member", Level: registry.PrivilegeRead},
+		{SourceKind: "vault", SourceName: "prod", AccountExternalID: "entity:bob", Kind: "vault_group_policy", Resource: "vault_policy:ops-admin", Permission: "attached", Level: registry.PrivilegeAdmin, RawJSON: []byte(`{"group_name":"ops","policy":"ops-admin"}`)},
+	})
+	if len(paths) != 1 {
+		t.Fatalf("paths=%+v, want 1", paths)
+	}
+	got := paths[0]
+	if got.ReachableLevel != registry.PrivilegeOwner || got.HeldLevel != registry.PrivilegeAdmin {
+		t.Fatalf("held=%s reachable=%s, want admin and owner", got.HeldLevel, got.ReachableLevel)
+	}
+	if len(got.Steps) != 3 || got.Steps[1].Node != "vault_group:ops" || got.Steps[2].Node != "vault_policy:ops-admin" {
+		t.Fatalf("steps=%v, want account -> vault_group:ops -> vault_policy:ops-admin", got.Steps)
+	}
+}
+
+func TestDetectEscalationPathsIgnoresBenignChain(t *testing.T) {
+	t.Parallel()
+
+	// carol's team grants push on a repo, which she already holds through the team.
+	paths := DetectEscalationPaths([]EscalationGrant{
+		{SourceKind: "github", SourceName: "acme", AccountExternalID: "carol", Kind: "github_org_role", Resource: "github_org:acme", Permission: "member", Level: registry.PrivilegeRead},
+		{SourceKind: "github", SourceName: "acme", AccountExternalID: "carol", Kind: "github_team_member", Resource: "github_team:acme/web", Permission: "member", Level: registry.PrivilegeRead},
+		{SourceKind: "github", SourceName: "acme", AccountExternalID: "carol", Kind: "github_team_repo_permission", Resource: "github_repo:acme/site", Permission: "push", Level: registry.PrivilegeWrite, RawJSON: []byte(`{"team":"web","repo":"site","permission":"push"}`)},
+		{SourceKind: "github", SourceName: "acme", AccountExternalID: "dave", Kind: "github_team_member", Resource: "github_team:acme/web", Permission: "member", Level: registry.PrivilegeRead},
+		{SourceKind: "github", SourceName: "acme", AccountExternalID: "dave", Kind: "github_team_repo_permission", Resource: "github_repo:acme/site", Permission: "push", Level: registry.PrivilegeWrite, RawJSON: []byte(`{"team":"web","repo":"site","permission":"push"}`)},
+	})
+	if len(paths) != 0 {
+		t.Fatalf("paths=%+v, want none", paths)
+	}
+}
+
+func TestDetectEscalationPathsStopsAtMaxDepth(t *testing.T) {
+	t.Parallel()
+
+	// A chain of nested groups deeper than the traversal bound ends at a super admin role.
+	var grants []EscalationGrant
+	member := "erin"
+	for i := 0; i < maxEscalationDepth; i++ {
+		group := fmt.Sprintf("g%d", i)
+		grants = append(grants, EscalationGrant{SourceKind: "google_workspace", SourceName: "example.com", AccountExternalID: member, Kind: "google_group_member", Resource: "google_group:" + group, Permission: "member", Level: registry.PrivilegeRead})
+		member = group
+	}
+	grants = append(grants, EscalationGrant{SourceKind: "google_workspace", SourceName: "example.com", AccountExternalID: member, Kind: "google_admin_role", Resource: "google_admin_role:r1", Permission: "global", Level: registry.PrivilegeOwner})
+
+	for _, path := range DetectEscalationPaths(grants) {
+		if path.AccountExternalID == "erin" {
+			t.Fatalf("path=%+v, want erin's chain cut off at %d edges", path, maxEscalationDepth)
+		}
+	}
+}
+
+func TestDetectEscalationPathsKeepsSourcesApart(t *testing.T) {
+	t.Parallel()
+
+	paths := DetectEscalationPaths([]EscalationGrant{
+		{SourceKind: "google_workspace", SourceName: "a.example", AccountExternalID: "alice", Kind: "google_group_member", Resource: "google_group:g1", Permission: "manager", Level: registry.PrivilegeAdmin},
+		{SourceKind: "google_workspace", SourceName: "b.example", AccountExternalID: "g1", Kind: "google_admin_role", Resource: "google_admin_role:r1", Permission: "global", Level: registry.PrivilegeOwner},
+	})
+	if len(paths) != 0 {
+		t.Fatalf("paths=%+v, want none across sources", paths)
+	}
+}
@@ -3,23 +3,61 @@ package auth
 import "strings"
 
 const (
-	RoleAdmin  = "admin"
-	RoleViewer = "viewer"
+	RoleAdmin   = "admin"
+	RoleAnalyst = "analyst"
+	RoleViewer  = "viewer"
 
 	MethodPassword = "password"
+	MethodOIDC     = "oidc"
+
+	// DefaultOrgID is the org created by the orgs migration. Users and connector sources
+	// without an explicit assignment belong to it.
+	DefaultOrgID int64 = 1
 )
 
+// roleRank orders roles by privilege: viewers read, analysts also annotate (tags,
+// attestations), and admins additionally run syncs, revoke credentials, and edit configuration.
+var roleRank = map[string]int{
+	RoleViewer:  1,
+	RoleAnalyst: 2,
+	RoleAdmin:   3,
+}
+
+// IsValidRole reports whether role is one of the known roles.
+func IsValidRole(role string) bool {
+	_, ok := roleRank[strings.ToLower(strings.TrimSpace(role))]
+	return ok
+}
+
+// RoleAtLeast reports whether role grants at least the privileges of min. Unknown roles grant
+// nothing.
+func RoleAtLeast(role, min string) bool {
+	have, ok := roleRank[strings.ToLower(strings.TrimSpace(role))]
+	if !ok {
+		return false
+	}
+	return have >= roleRank[strings.ToLower(strings.TrimSpace(min))]
+}
+
 type Principal struct {
 	UserID int64
 	Email  string
-	Role   string // "admin" or "viewer"
-	Method string // "password" now; "oidc" later
+	Role   string // "admin", "analyst", or "viewer"
+	Method string // "password" or "oidc"
+	OrgID  int64
+	// Timezone is the user's IANA display zone; empty means the deployment default.
+	Timezone string
 }
 
 func (p Principal) IsAdmin() bool {
 	return p.Role == RoleAdmin
 }
 
+// HasRole reports whether the principal's role grants at least min.
+func (p Principal) HasRole(min string) bool {
+	return RoleAtLeast(p.Role, min)
+}
+
 func NormalizeEmail(email string) string {
 	return strings.ToLower(strings.TrimSpace(email))
 }
@@ -0,0 +1,327 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/open-sspm/open-sspm/internal/auth"
+	"golang.org/x/oauth2"
+)
+
+// ErrOIDCTokenInvalid is returned when the IdP's ID token fails verification or lacks the claims
+// needed to sign a user in.
+var ErrOIDCTokenInvalid = errors.New("invalid oidc id token")
+
+// oidcClockSkew tolerates small clock differences between the app and the IdP.
+const oidcClockSkew = time.Minute
+
+var oidcSignatureAlgorithms = []jose.SignatureAlgorithm{jose.RS256, jose.RS384, jose.RS512, jose.ES256, jose.ES384, jose.PS256}
+
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	GroupsClaim  string
+	HTTPClient   *http.Client
+}
+
+// OIDCIdentity is the verified subset of ID token claims used to resolve a login user.
+type OIDCIdentity struct {
+	Subject string
+	Email   string
+	Groups  []string
+}
+
+// OIDCProvider signs users in with the OIDC authorization code flow (with PKCE). Provider
+// metadata and signing keys are discovered from the issuer on first use and cached; keys are
+// refetched when a token names an unknown key ID.
+type OIDCProvider struct {
+	cfg    OIDCConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *oidcMetadata
+	keys     jose.JSONWebKeySet
+}
+
+type oidcMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func NewOIDCProvider(cfg OIDCConfig) *OIDCProvider {
+	cfg.IssuerURL = strings.TrimRight(strings.TrimSpace(cfg.IssuerURL), "/")
+	if strings.TrimSpace(cfg.GroupsClaim) == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	if !slices.Contains(cfg.Scopes, "openid") {
+		cfg.Scopes = append([]string{"openid"}, cfg.Scopes...)
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &OIDCProvider{cfg: cfg, client: client}
+}
+
+func (p *OIDCProvider) Name() string {
+	return auth.MethodOIDC
+}
+
+// AuthCodeURL returns the IdP authorization URL for a login attempt. state and nonce are bound
+// to the user's session, and verifier is the PKCE code verifier later passed to Exchange.
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	conf, err := p.oauth2Config(ctx)
+	if err != nil {
+		return "", err
+	}
+	return conf.AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Exchange redeems an authorization code and verifies the returned ID token's signature, issuer,
+// audience, expiry, and nonce.
+func (p *OIDCProvider) Exchange(ctx context.Context, code, verifier, nonce string) (OIDCIdentity, error) {
+	conf, err := p.oauth2Config(ctx)
+	if err != nil {
+		return OIDCIdentity{}, err
+	}
+	token, err := conf.Exchange(context.WithValue(ctx, oauth2.HTTPClient, p.client), code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return OIDCIdentity{}, fmt.Errorf("oidc code exchange: %w", err)
+	}
+	rawIDToken, _ := token.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return OIDCIdentity{}, fmt.Errorf("%w: token response has no id_token", ErrOIDCTokenInvalid)
+	}
+	return p.verifyIDToken(ctx, rawIDToken, nonce, time.Now())
+}
+
+func (p *OIDCProvider) oauth2Config(ctx context.Context) (*oauth2.Config, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Scopes:       p.cfg.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  metadata.AuthorizationEndpoint,
+			TokenURL: metadata.TokenEndpoint,
+		},
+	}, nil
+}
+
+func (p *OIDCProvider) discover(ctx context.Context) (*oidcMetadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	var metadata oidcMetadata
+	if err := p.getJSON(ctx, p.cfg.IssuerURL+"/.well-known/openid-configuration", &metadata); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if strings.TrimRight(metadata.Issuer, "/") != p.cfg.IssuerURL {
+		return nil, fmt.Errorf("oidc discovery: issuer %q does not match configured issuer %q", metadata.Issuer, p.cfg.IssuerURL)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, errors.New("oidc discovery: provider metadata is missing required endpoints")
+	}
+	p.metadata = &metadata
+	return p.metadata, nil
+}
+
+// signingKeys returns the cached JWKS entries for kid, refetching the key set when none match.
+func (p *OIDCProvider) signingKeys(ctx context.Context, kid string) ([]jose.JSONWebKey, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if keys := lookupSigningKeys(p.keys, kid); len(keys) > 0 {
+		return keys, nil
+	}
+	var keys jose.JSONWebKeySet
+	if err := p.getJSON(ctx, metadata.JWKSURI, &keys); err != nil {
+		return nil, fmt.Errorf("oidc jwks: %w", err)
+	}
+	p.keys = keys
+	return lookupSigningKeys(p.keys, kid), nil
+}
+
+func lookupSigningKeys(set jose.JSONWebKeySet, kid string) []jose.JSONWebKey {
+	if kid != "" {
+		return set.Key(kid)
+	}
+	return set.Keys
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out)
+}
+
+type oidcIDTokenClaims struct {
+	Issuer        string          `json:"iss"`
+	Subject       string          `json:"sub"`
+	Audience      json.RawMessage `json:"aud"`
+	Expiry        int64           `json:"exp"`
+	Nonce         string          `json:"nonce"`
+	Email         string          `json:"email"`
+	EmailVerified any             `json:"email_verified"`
+}
+
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, rawIDToken, nonce string, now time.Time) (OIDCIdentity, error) {
+	jws, err := jose.ParseSigned(rawIDToken, oidcSignatureAlgorithms)
+	if err != nil {
+		return OIDCIdentity{}, fmt.Errorf("%w: %v", ErrOIDCTokenInvalid, err)
+	}
+	if len(jws.Signatures) != 1 {
+		return OIDCIdentity{}, fmt.Errorf("%w: expected exactly one signature", ErrOIDCTokenInvalid)
+	}
+	keys, err := p.signingKeys(ctx, jws.Signatures[0].Header.KeyID)
+	if err != nil {
+		return OIDCIdentity{}, err
+	}
+	var payload []byte
+	for _, key := range keys {
+		if payload, err = jws.Verify(key); err == nil {
+			break
+		}
+	}
+	if payload == nil {
+		return OIDCIdentity{}, fmt.Errorf("%w: signature does not match any provider key", ErrOIDCTokenInvalid)
+	}
+
+	var claims oidcIDTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return OIDCIdentity{}, fmt.Errorf("%w: %v", ErrOIDCTokenInvalid, err)
+	}
+	switch {
+	case strings.TrimRight(claims.Issuer, "/") != p.cfg.IssuerURL:
+		return OIDCIdentity{}, fmt.Errorf("%w: unexpected issuer %q", ErrOIDCTokenInvalid, claims.Issuer)
+	case !oidcAudienceContains(claims.Audience, p.cfg.ClientID):
+		return OIDCIdentity{}, fmt.Errorf("%w: audience does not include client id", ErrOIDCTokenInvalid)
+	case claims.Expiry == 0 || now.Add(-oidcClockSkew).After(time.Unix(claims.Expiry, 0)):
+		return OIDCIdentity{}, fmt.Errorf("%w: token expired", ErrOIDCTokenInvalid)
+	case nonce == "" || claims.Nonce != nonce:
+		return OIDCIdentity{}, fmt.Errorf("%w: nonce mismatch", ErrOIDCTokenInvalid)
+	case !oidcClaimIsTrue(claims.EmailVerified):
+		return OIDCIdentity{}, fmt.Errorf("%w: email is not verified", ErrOIDCTokenInvalid)
+	}
+
+	email := auth.NormalizeEmail(claims.Email)
+	if email == "" {
+		return OIDCIdentity{}, fmt.Errorf("%w: token has no email claim", ErrOIDCTokenInvalid)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return OIDCIdentity{}, fmt.Errorf("%w: %v", ErrOIDCTokenInvalid, err)
+	}
+	return OIDCIdentity{
+		Subject: strings.TrimSpace(claims.Subject),
+		Email:   email,
+		Groups:  oidcGroupsClaim(raw[p.cfg.GroupsClaim]),
+	}, nil
+}
+
+// oidcAudienceContains accepts the aud claim as either a string or an array of strings.
+func oidcAudienceContains(raw json.RawMessage, clientID string) bool {
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return single == clientID
+	}
+	var many []string
+	if err := json.Unmarshal(raw, &many); err == nil {
+		return slices.Contains(many, clientID)
+	}
+	return false
+}
+
+// oidcClaimIsTrue reports an explicit true; some IdPs send booleans as strings. A missing claim
+// is not true.
+func oidcClaimIsTrue(v any) bool {
+	switch value := v.(type) {
+	case bool:
+		return value
+	case string:
+		return strings.EqualFold(strings.TrimSpace(value), "true")
+	default:
+		return false
+	}
+}
+
+func oidcGroupsClaim(v any) []string {
+	var out []string
+	switch value := v.(type) {
+	case string:
+		if group := strings.TrimSpace(value); group != "" {
+			out = append(out, group)
+		}
+	case []any:
+		for _, item := range value {
+			if group, ok := item.(string); ok && strings.TrimSpace(group) != "" {
+				out = append(out, strings.TrimSpace(group))
+			}
+		}
+	}
+	return out
+}
+
+// OIDCRoleForGroups maps IdP groups to an app role; the highest matching role wins. When viewer
+// groups are configured only their members may sign in as viewers; otherwise every
+// authenticated user is at least a viewer. ok is false when the user may not sign in.
+func OIDCRoleForGroups(groups, adminGroups, analystGroups, viewerGroups []string) (role string, ok bool) {
+	if oidcGroupsIntersect(groups, adminGroups) {
+		return auth.RoleAdmin, true
+	}
+	if oidcGroupsIntersect(groups, analystGroups) {
+		return auth.RoleAnalyst, true
+	}
+	if len(viewerGroups) == 0 || oidcGroupsIntersect(groups, viewerGroups) {
+		return auth.RoleViewer, true
+	}
+	return "", false
+}
+
+func oidcGroupsIntersect(groups, allowed []string) bool {
+	for _, group := range groups {
+		for _, candidate := range allowed {
+			if strings.EqualFold(strings.TrimSpace(group), strings.TrimSpace(candidate)) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package providers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/open-sspm/open-sspm/internal/auth"
+)
+
+type testIdP struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims map[string]any
+}
+
+func newTestIdP(t *testing.T) *testIdP {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	idp := &testIdP{t: t, key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "test-key", Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("code") != "good-code" || r.PostForm.Get("code_verifier") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     idp.sign(idp.claims),
+		})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (idp *testIdP) sign(claims map[string]any) string {
+	idp.t.Helper()
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: idp.key}, (&jose.SignerOptions{}).WithHeader("kid", "test-key"))
+	if err != nil {
+		idp.t.Fatalf("jose.NewSigner() error = %v", err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		idp.t.Fatalf("json.Marshal() error = %v", err)
+	}
+	jws, err := signer.Sign(payload)
+	if err != nil {
+		idp.t.Fatalf("signer.Sign() error = %v", err)
+	}
+	token, err := jws.CompactSerialize()
+	if err != nil {
+		idp.t.Fatalf("CompactSerialize() error = %v", err)
+	}
+	return token
+}
+
+func (idp *testIdP) provider() *OIDCProvider {
+	return NewOIDCProvider(OIDCConfig{
+		IssuerURL:    idp.server.URL,
+		ClientID:     "open-sspm",
+		ClientSecret: "secret",
+		RedirectURL:  "https://sspm.example.com/login/oidc/callback",
+		Scopes:       []string{"email", "profile"},
+		HTTPClient:   idp.server.Client(),
+	})
+}
+
+func (idp *testIdP) validClaims() map[string]any {
+	return map[string]any{
+		"iss":            idp.server.URL,
+		"sub":            "user-1",
+		"aud":            "open-sspm",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          "nonce-1",
+		"email":          "Alice@Example.com",
+		"email_verified": true,
+		"groups":         []string{"sspm-admins", "engineering"},
+	}
+}
+
+func TestOIDCProviderAuthCodeURL(t *testing.T) {
+	idp := newTestIdP(t)
+
+	location, err := idp.provider().AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	query := u.Query()
+	if u.Path != "/authorize" || query.Get("state") != "state-1" || query.Get("nonce") != "nonce-1" {
+		t.Fatalf("AuthCodeURL() = %q, want authorize endpoint with state and nonce", location)
+	}
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		t.Fatalf("AuthCodeURL() = %q, want a PKCE S256 challenge", location)
+	}
+	if query.Get("scope") != "openid email profile" {
+		t.Fatalf("scope = %q, want openid prepended", query.Get("scope"))
+	}
+}
+
+func TestOIDCProviderExchange(t *testing.T) {
+	idp := newTestIdP(t)
+	idp.claims = idp.validClaims()
+
+	identity, err := idp.provider().Exchange(context.Background(), "good-code", "verifier-1", "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if identity.Email != "alice@example.com" || identity.Subject != "user-1" {
+		t.Fatalf("identity = %+v, want normalized email and subject", identity)
+	}
+	if !slices.Equal(identity.Groups, []string{"sspm-admins", "engineering"}) {
+		t.Fatalf("groups = %v", identity.Groups)
+	}
+}
+
+func TestOIDCProviderExchangeRejectsInvalidTokens(t *testing.T) {
+	cases := map[string]func(idp *testIdP, claims map[string]any){
+		"nonce":                  func(_ *testIdP, claims map[string]any) { claims["nonce"] = "other" },
+		"audience":               func(_ *testIdP, claims map[string]any) { claims["aud"] = []string{"someone-else"} },
+		"issuer":                 func(_ *testIdP, claims map[string]any) { claims["iss"] = "https://evil.example.com" },
+		"expired":                func(_ *testIdP, claims map[string]any) { claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"email verified":         func(_ *testIdP, claims map[string]any) { claims["email_verified"] = "false" },
+		"email verified missing": func(_ *testIdP, claims map[string]any) { delete(claims, "email_verified") },
+		"missing email":          func(_ *testIdP, claims map[string]any) { delete(claims, "email") },
+		"wrong key": func(idp *testIdP, _ map[string]any) {
+			other, err := rsa.GenerateKey(rand.Reader, 2048)
+			if err != nil {
+				idp.t.Fatalf("rsa.GenerateKey() error = %v", err)
+			}
+			idp.key = other
+		},
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			idp := newTestIdP(t)
+			idp.claims = idp.validClaims()
+			mutate(idp, idp.claims)
+
+			_, err := idp.provider().Exchange(context.Background(), "good-code", "verifier-1", "nonce-1")
+			if !errors.Is(err, ErrOIDCTokenInvalid) {
+				t.Fatalf("Exchange() error = %v, want ErrOIDCTokenInvalid", err)
+			}
+		})
+	}
+}
+
+func TestOIDCRoleForGroups(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name          string
+		groups        []string
+		adminGroups   []string
+		analystGroups []string
+		viewerGroups  []string
+		wantRole      string
+		wantOK        bool
+	}{
+		{name: "admin group wins", groups: []string{"Engineering", "SSPM-Admins"}, adminGroups: []string{"sspm-admins"}, viewerGroups: []string{"engineering"}, wantRole: auth.RoleAdmin, wantOK: true},
+		{name: "analyst group", groups: []string{"secops", "engineering"}, adminGroups: []string{"sspm-admins"}, analystGroups: []string{"secops"}, viewerGroups: []string{"engineering"}, wantRole: auth.RoleAnalyst, wantOK: true},
+		{name: "viewer group", groups: []string{"engineering"}, adminGroups: []string{"sspm-admins"}, viewerGroups: []string{"engineering"}, wantRole: auth.RoleViewer, wantOK: true},
+		{name: "no viewer groups allows everyone", groups: nil, adminGroups: []string{"sspm-admins"}, wantRole: auth.RoleViewer, wantOK: true},
+		{name: "not in any allowed group", groups: []string{"sales"}, adminGroups: []string{"sspm-admins"}, viewerGroups: []string{"engineering"}, wantOK: false},
+	}
+	for _, tc := range cases {
+		role, ok := OIDCRoleForGroups(tc.groups, tc.adminGroups, tc.analystGroups, tc.viewerGroups)
+		if role != tc.wantRole || ok != tc.wantOK {
+			t.Fatalf("%s: OIDCRoleForGroups() = (%q, %v), want (%q, %v)", tc.name, role, ok, tc.wantRole, tc.wantOK)
+		}
+	}
+}
@@ -34,7 +34,8 @@ func (p *PasswordProvider) Authenticate(ctx context.Context, email, password str
 		}
 		return auth.Principal{}, err
 	}
-	if !user.IsActive {
+	// SSO-provisioned users have no password and must sign in through their IdP.
+	if !user.IsActive || user.AuthMethod == auth.MethodOIDC {
 		return auth.Principal{}, auth.ErrInvalidCredentials
 	}
 
@@ -51,5 +52,6 @@ func (p *PasswordProvider) Authenticate(ctx context.Context, email, password str
 		Email:  user.Email,
 		Role:   user.Role,
 		Method: auth.MethodPassword,
+		OrgID:  user.OrgID,
 	}, nil
 }
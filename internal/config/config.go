@@ -3,7 +3,10 @@ package config
 import (
 	"errors"
 	"fmt"
+	"math"
 	"os"
+	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -14,23 +17,94 @@ import (
 const (
 	defaultHTTPAddr              = ":8080"
 	defaultMetricsAddr           = ""
+	defaultMetricsInventoryTTL   = 60 * time.Second
 	defaultSyncInterval          = 15 * time.Minute
 	defaultSyncDiscoveryInterval = 15 * time.Minute
 
 	defaultSyncOktaWorkers    = 3
+	defaultSyncEntraWorkers   = 6
 	defaultSyncGitHubWorkers  = 6
 	defaultSyncDatadogWorkers = 3
 
+	// defaultSyncMaxConcurrentRuns caps integration runs executing at once in one process.
+	defaultSyncMaxConcurrentRuns = 3
+
+	// defaultSyncRunRetention is how many finished sync runs are kept per source and mode.
+	defaultSyncRunRetention = 500
+
+	// defaultSyncRunTimeouts bounds each connector sync run as kind=duration pairs; "default"
+	// applies to kinds without their own entry and 0 lets a kind run without a deadline.
+	defaultSyncRunTimeouts = "default=2h"
+
+	// defaultSyncAPIRequestBudgetWindow is the window SYNC_API_REQUEST_BUDGET limits apply to.
+	defaultSyncAPIRequestBudgetWindow = time.Hour
+
+	// defaultCredentialSharedNamePatterns are matched case-insensitively as whole words of
+	// credential and creator names to flag shared or service credentials.
+	defaultCredentialSharedNamePatterns = "bot,svc,service,shared,automation,ci"
+
+	// defaultCredentialRotationSLADays is the rotation policy as kind=days pairs; "default"
+	// applies to kinds without their own entry and 0 exempts a kind.
+	defaultCredentialRotationSLADays = "default=365,entra_client_secret=180,entra_certificate=365,github_deploy_key=180,github_pat_fine_grained=180"
+
+	// defaultCredentialHighPrivilegeKinds are the credential kinds rated critical when neither a
+	// creator nor an approver is recorded.
+	defaultCredentialHighPrivilegeKinds = "entra_client_secret,github_deploy_key,github_pat_request,github_pat_fine_grained"
+
+	// defaultCredentialActiveStatuses are the normalized credential statuses that still grant
+	// access. A connector whose source reports another word for a usable credential (say
+	// "enabled") either maps it to one of these when syncing or adds it here, or its expired
+	// credentials are rated as if they were already disabled.
+	defaultCredentialActiveStatuses = "active,approved,pending_approval"
+
+	// defaultEntitlementHighPrivilegeMinLevel and defaultEntitlementHighPrivilegePatterns classify
+	// entitlements as high privilege: at or above the canonical level, or matching a kind[=value]
+	// pattern whatever their level.
+	defaultEntitlementHighPrivilegeMinLevel = "admin"
+	defaultEntitlementHighPrivilegePatterns = "github_org_role=admin,entra_directory_role=global administrator,google_admin_role"
+
+	// defaultRawJSONMaxBytes caps provider raw JSON stored per row; larger payloads are truncated.
+	defaultRawJSONMaxBytes = 256 * 1024
+
+	// defaultMultiSourceListRowLimit caps the rows merged in memory by "All configured" list views.
+	defaultMultiSourceListRowLimit = 50000
+
 	defaultSyncLockMode              = "lease"
 	defaultSyncLockTTL               = 60 * time.Second
 	defaultSyncLockHeartbeatInterval = 15 * time.Second
 	defaultSyncLockHeartbeatTimeout  = 15 * time.Second
+
+	defaultConnectorIncidentSLA           = 24 * time.Hour
+	defaultConnectorIncidentCheckInterval = 5 * time.Minute
+
+	defaultConnectorFreshnessAmberAfter = 6 * time.Hour
+	defaultConnectorFreshnessRedAfter   = 24 * time.Hour
+
+	defaultDiscoveryOAuthAnomalyWindow       = 24 * time.Hour
+	defaultDiscoveryOAuthAnomalyMinActors    = 10
+	defaultDiscoveryOAuthAnomalyNewAppMaxAge = 7 * 24 * time.Hour
+
+	// Discovery auto-binding scores an app id match high and a display name match low;
+	// candidates below the minimum are kept as suggestions instead of being bound.
+	defaultDiscoveryAutoBindClientIDConfidence = 0.95
+	defaultDiscoveryAutoBindNameConfidence     = 0.5
+	defaultDiscoveryAutoBindMinConfidence      = 0.7
+
+	defaultDisplayTimezone = "UTC"
+
+	defaultOIDCScopes      = "openid,email,profile"
+	defaultOIDCGroupsClaim = "groups"
+
+	// defaultDiscoveryBackfillLookback is how far back `sync-discovery --backfill` reads when no
+	// explicit start date is given.
+	defaultDiscoveryBackfillLookback = 90 * 24 * time.Hour
 )
 
 type Config struct {
 	DatabaseURL                 string
 	HTTPAddr                    string
 	MetricsAddr                 string
+	MetricsInventoryCacheTTL    time.Duration
 	StaticDir                   string
 	AuthCookieSecure            bool
 	DevSeedAdmin                bool
@@ -44,16 +118,168 @@ type Config struct {
 	SyncAWSInterval             time.Duration
 	SyncFailureBackoffMax       time.Duration
 	SyncOktaWorkers             int
+	SyncEntraWorkers            int
 	SyncGitHubWorkers           int
 	SyncDatadogWorkers          int
-	ResyncEnabled               bool
-	ResyncMode                  string
-	GlobalEvalMode              string
-	SyncLockMode                string
-	SyncLockTTL                 time.Duration
-	SyncLockHeartbeatInterval   time.Duration
-	SyncLockHeartbeatTimeout    time.Duration
-	SyncLockInstanceID          string
+	SyncMaxConcurrentRuns       int
+	// SyncRunRetention is how many finished runs the worker keeps per source and mode; 0 keeps all.
+	SyncRunRetention          int
+	ResyncEnabled             bool
+	ResyncMode                string
+	GlobalEvalMode            string
+	SyncLockMode              string
+	SyncLockTTL               time.Duration
+	SyncLockHeartbeatInterval time.Duration
+	SyncLockHeartbeatTimeout  time.Duration
+	SyncLockInstanceID        string
+	// SyncRunTimeouts maps connector kind (or "default") to the deadline for a whole sync run.
+	SyncRunTimeouts map[string]time.Duration
+	// SyncAPIRequestBudgets maps connector kind to the provider API requests its source may use
+	// per SyncAPIRequestBudgetWindow; a run expected to exceed it is deferred. Unset kinds have none.
+	SyncAPIRequestBudgets      map[string]int64
+	SyncAPIRequestBudgetWindow time.Duration
+
+	// InventorySnapshotsEnabled has the worker keep a daily snapshot of the credential and
+	// entitlement inventory.
+	InventorySnapshotsEnabled bool
+
+	// Connector config values written as "secret://<ref>" are resolved through this backend.
+	ConnectorSecretBackend    string
+	ConnectorSecretVaultMount string
+
+	// Egress settings for connector HTTP clients. An empty proxy falls back to the standard
+	// HTTP(S)_PROXY environment; the CA bundle is trusted in addition to the system roots.
+	ConnectorHTTPProxy    string
+	ConnectorNoProxy      string
+	ConnectorCABundlePath string
+	// ConnectorHTTPTimeout replaces every connector's own per-request timeout when set.
+	ConnectorHTTPTimeout time.Duration
+	// Per-kind request pacing of connector HTTP clients as kind=value overrides; kinds left out
+	// keep their built-in limits. A rate or concurrency of 0 removes that limit.
+	ConnectorRequestsPerSecond     map[string]float64
+	ConnectorMaxConcurrentRequests map[string]int64
+	ConnectorMaxBackoff            map[string]time.Duration
+
+	CredentialSharedNamePatterns []string
+	// CredentialRotationSLADays maps credential kind (or "default") to the maximum age in days
+	// before a credential must be rotated, regardless of its expiry.
+	CredentialRotationSLADays map[string]int
+	// CredentialHighPrivilegeKinds are rated critical when unattributed; CredentialRiskOverrides
+	// then bound the computed risk level per kind, in order.
+	CredentialHighPrivilegeKinds []string
+	CredentialRiskOverrides      []CredentialRiskOverride
+	// CredentialActiveStatuses are the credential statuses treated as still usable; a blank
+	// status counts as "active".
+	CredentialActiveStatuses   []string
+	DiscoveryVendorCatalogPath string
+	MultiSourceListRowLimit    int
+	RawJSONMaxBytes            int64
+	// DisplayTimezone is the IANA zone dates are rendered in for users without their own preference.
+	DisplayTimezone string
+
+	// Entitlements at or above EntitlementHighPrivilegeMinLevel ("none" disables the level test),
+	// or matching one of EntitlementHighPrivilegePatterns, are high privilege.
+	EntitlementHighPrivilegeMinLevel string
+	EntitlementHighPrivilegePatterns []EntitlementPrivilegePattern
+
+	// Anomalous OAuth grant detection for newly discovered SaaS apps.
+	DiscoveryOAuthAnomalyWindow       time.Duration
+	DiscoveryOAuthAnomalyMinActors    int
+	DiscoveryOAuthAnomalyNewAppMaxAge time.Duration
+	DiscoveryBackfillLookback         time.Duration
+	// DiscoveryCanonicalKeyStrategy decides how discovery rows merge into apps: domain (default),
+	// client_id, vendor, or name.
+	DiscoveryCanonicalKeyStrategy string
+
+	// Discovery auto-bind confidence by match strength, and the threshold below which a
+	// candidate is recorded as a suggestion.
+	DiscoveryAutoBindClientIDConfidence float32
+	DiscoveryAutoBindNameConfidence     float32
+	DiscoveryAutoBindMinConfidence      float32
+	// DiscoveryAutoBindDisabled stops discovery runs from creating bindings at all.
+	DiscoveryAutoBindDisabled bool
+
+	// Stale-connector incidents are raised only when a PagerDuty routing key or webhook URL is set.
+	ConnectorIncidentSLA                 time.Duration
+	ConnectorIncidentCheckInterval       time.Duration
+	ConnectorIncidentPagerDutyRoutingKey string
+	ConnectorIncidentWebhookURL          string
+
+	// Freshness badges in the nav and source pickers turn amber, then red, once a connector's
+	// last successful sync is older than these.
+	ConnectorFreshnessAmberAfter time.Duration
+	ConnectorFreshnessRedAfter   time.Duration
+
+	// OIDC single sign-on for UI users. SSO is enabled when OIDCIssuerURL is set; members of
+	// OIDCAdminGroups become admins and members of OIDCAnalystGroups analysts, and when
+	// OIDCViewerGroups is set only its members (or admins and analysts) may sign in.
+	OIDCIssuerURL     string
+	OIDCClientID      string
+	OIDCClientSecret  string
+	OIDCRedirectURL   string
+	OIDCScopes        []string
+	OIDCGroupsClaim   string
+	OIDCAdminGroups   []string
+	OIDCAnalystGroups []string
+	OIDCViewerGroups  []string
+}
+
+// Credential risk override operators: a cap lowers the level to at most Level, a floor raises
+// it to at least Level, and a force replaces it.
+const (
+	CredentialRiskCap   = "cap"
+	CredentialRiskFloor = "floor"
+	CredentialRiskForce = "force"
+)
+
+// CredentialRiskOverride bounds the risk level of credentials of Kind. When Scope is set the
+// override applies only to credentials whose scope lists it (an array entry, a string value,
+// or an object key set to true, e.g. "read_only" on GitHub deploy keys).
+type CredentialRiskOverride struct {
+	Kind  string
+	Scope string
+	Op    string
+	Level string
+}
+
+// EntitlementPrivilegePattern marks entitlements of Kind as high privilege. When Value is set
+// only entitlements whose permission, role name, or resource id matches it do; Value is
+// lowercased and may use * wildcards (e.g. "entra_directory_role=*administrator").
+type EntitlementPrivilegePattern struct {
+	Kind  string
+	Value string
+}
+
+// DefaultEntitlementHighPrivilegePatterns returns the patterns used when
+// ENTITLEMENT_HIGH_PRIVILEGE_PATTERNS is unset.
+func DefaultEntitlementHighPrivilegePatterns() []EntitlementPrivilegePattern {
+	patterns, _ := parseEntitlementPrivilegePatternsEnv(defaultEntitlementHighPrivilegePatterns)
+	return patterns
+}
+
+// DefaultCredentialHighPrivilegeKinds returns the credential kinds treated as high privilege
+// when CREDENTIAL_HIGH_PRIVILEGE_KINDS is unset.
+func DefaultCredentialHighPrivilegeKinds() []string {
+	return parseListEnv(defaultCredentialHighPrivilegeKinds)
+}
+
+// DefaultCredentialActiveStatuses returns the credential statuses treated as still usable when
+// CREDENTIAL_ACTIVE_STATUSES is unset.
+func DefaultCredentialActiveStatuses() []string {
+	return parseListEnv(defaultCredentialActiveStatuses)
+}
+
+// CredentialRiskPolicyCustomized reports whether the credential risk settings differ from the
+// built-in policy encoded by the credential_heuristic_risk_level SQL function.
+func (c Config) CredentialRiskPolicyCustomized() bool {
+	return len(c.CredentialRiskOverrides) > 0 ||
+		!slices.Equal(c.CredentialHighPrivilegeKinds, DefaultCredentialHighPrivilegeKinds()) ||
+		!slices.Equal(c.CredentialActiveStatuses, DefaultCredentialActiveStatuses())
+}
+
+// OIDCEnabled reports whether UI users can sign in through the configured OIDC provider.
+func (c Config) OIDCEnabled() bool {
+	return c.OIDCIssuerURL != ""
 }
 
 type LoadOptions struct {
@@ -77,25 +303,119 @@ func LoadWithOptions(opts LoadOptions) (Config, error) {
 	}
 
 	cfg := Config{
-		DatabaseURL:               os.Getenv("DATABASE_URL"),
-		HTTPAddr:                  getenvDefault("HTTP_ADDR", defaultHTTPAddr),
-		MetricsAddr:               defaultMetricsAddr,
-		StaticDir:                 strings.TrimSpace(os.Getenv("STATIC_DIR")),
-		AuthCookieSecure:          getenvBoolDefault("AUTH_COOKIE_SECURE", false),
-		DevSeedAdmin:              getenvBoolDefault("DEV_SEED_ADMIN", false),
-		SyncInterval:              defaultSyncInterval,
-		SyncDiscoveryInterval:     defaultSyncDiscoveryInterval,
-		SyncOktaWorkers:           getenvIntDefault("SYNC_OKTA_WORKERS", defaultSyncOktaWorkers),
-		SyncGitHubWorkers:         getenvIntDefault("SYNC_GITHUB_WORKERS", defaultSyncGitHubWorkers),
-		SyncDatadogWorkers:        getenvIntDefault("SYNC_DATADOG_WORKERS", defaultSyncDatadogWorkers),
-		ResyncEnabled:             getenvBoolDefault("RESYNC_ENABLED", true),
-		ResyncMode:                getenvDefault("RESYNC_MODE", "signal"),
-		GlobalEvalMode:            strings.ToLower(strings.TrimSpace(getenvDefault("GLOBAL_EVAL_MODE", "best_effort"))),
-		SyncLockMode:              strings.ToLower(strings.TrimSpace(getenvDefault("SYNC_LOCK_MODE", defaultSyncLockMode))),
-		SyncLockTTL:               defaultSyncLockTTL,
-		SyncLockHeartbeatInterval: defaultSyncLockHeartbeatInterval,
-		SyncLockHeartbeatTimeout:  defaultSyncLockHeartbeatTimeout,
-		SyncLockInstanceID:        strings.TrimSpace(os.Getenv("SYNC_LOCK_INSTANCE_ID")),
+		DatabaseURL:                os.Getenv("DATABASE_URL"),
+		HTTPAddr:                   getenvDefault("HTTP_ADDR", defaultHTTPAddr),
+		MetricsAddr:                defaultMetricsAddr,
+		MetricsInventoryCacheTTL:   defaultMetricsInventoryTTL,
+		StaticDir:                  strings.TrimSpace(os.Getenv("STATIC_DIR")),
+		AuthCookieSecure:           getenvBoolDefault("AUTH_COOKIE_SECURE", false),
+		DevSeedAdmin:               getenvBoolDefault("DEV_SEED_ADMIN", false),
+		SyncInterval:               defaultSyncInterval,
+		SyncDiscoveryInterval:      defaultSyncDiscoveryInterval,
+		SyncOktaWorkers:            getenvIntDefault("SYNC_OKTA_WORKERS", defaultSyncOktaWorkers),
+		SyncEntraWorkers:           getenvIntDefault("SYNC_ENTRA_WORKERS", defaultSyncEntraWorkers),
+		SyncGitHubWorkers:          getenvIntDefault("SYNC_GITHUB_WORKERS", defaultSyncGitHubWorkers),
+		SyncDatadogWorkers:         getenvIntDefault("SYNC_DATADOG_WORKERS", defaultSyncDatadogWorkers),
+		SyncMaxConcurrentRuns:      defaultSyncMaxConcurrentRuns,
+		SyncRunRetention:           defaultSyncRunRetention,
+		InventorySnapshotsEnabled:  getenvBoolDefault("INVENTORY_SNAPSHOTS_ENABLED", true),
+		SyncAPIRequestBudgetWindow: defaultSyncAPIRequestBudgetWindow,
+		ResyncEnabled:              getenvBoolDefault("RESYNC_ENABLED", true),
+		ResyncMode:                 getenvDefault("RESYNC_MODE", "signal"),
+		GlobalEvalMode:             strings.ToLower(strings.TrimSpace(getenvDefault("GLOBAL_EVAL_MODE", "best_effort"))),
+		SyncLockMode:               strings.ToLower(strings.TrimSpace(getenvDefault("SYNC_LOCK_MODE", defaultSyncLockMode))),
+		SyncLockTTL:                defaultSyncLockTTL,
+		SyncLockHeartbeatInterval:  defaultSyncLockHeartbeatInterval,
+		SyncLockHeartbeatTimeout:   defaultSyncLockHeartbeatTimeout,
+		SyncLockInstanceID:         strings.TrimSpace(os.Getenv("SYNC_LOCK_INSTANCE_ID")),
+
+		ConnectorSecretBackend:    strings.ToLower(strings.TrimSpace(getenvDefault("CONNECTOR_SECRET_BACKEND", "env"))),
+		ConnectorSecretVaultMount: strings.TrimSpace(getenvDefault("CONNECTOR_SECRET_VAULT_MOUNT", "secret")),
+		ConnectorHTTPProxy:        strings.TrimSpace(os.Getenv("CONNECTOR_HTTP_PROXY")),
+		ConnectorNoProxy:          strings.TrimSpace(os.Getenv("CONNECTOR_NO_PROXY")),
+		ConnectorCABundlePath:     strings.TrimSpace(os.Getenv("CONNECTOR_CA_BUNDLE_PATH")),
+
+		CredentialSharedNamePatterns: parseListEnv(defaultCredentialSharedNamePatterns),
+		CredentialHighPrivilegeKinds: DefaultCredentialHighPrivilegeKinds(),
+		CredentialActiveStatuses:     parseListEnv(getenvDefault("CREDENTIAL_ACTIVE_STATUSES", defaultCredentialActiveStatuses)),
+		DiscoveryVendorCatalogPath:   strings.TrimSpace(os.Getenv("DISCOVERY_VENDOR_CATALOG_PATH")),
+		MultiSourceListRowLimit:      getenvIntDefault("MULTI_SOURCE_LIST_ROW_LIMIT", defaultMultiSourceListRowLimit),
+		RawJSONMaxBytes:              defaultRawJSONMaxBytes,
+
+		DiscoveryOAuthAnomalyWindow:       defaultDiscoveryOAuthAnomalyWindow,
+		DiscoveryOAuthAnomalyMinActors:    getenvIntDefault("DISCOVERY_OAUTH_ANOMALY_MIN_ACTORS", defaultDiscoveryOAuthAnomalyMinActors),
+		DiscoveryOAuthAnomalyNewAppMaxAge: defaultDiscoveryOAuthAnomalyNewAppMaxAge,
+		DiscoveryBackfillLookback:         defaultDiscoveryBackfillLookback,
+		DiscoveryCanonicalKeyStrategy:     strings.TrimSpace(os.Getenv("DISCOVERY_CANONICAL_KEY_STRATEGY")),
+
+		DiscoveryAutoBindClientIDConfidence: defaultDiscoveryAutoBindClientIDConfidence,
+		DiscoveryAutoBindNameConfidence:     defaultDiscoveryAutoBindNameConfidence,
+		DiscoveryAutoBindMinConfidence:      defaultDiscoveryAutoBindMinConfidence,
+		DiscoveryAutoBindDisabled:           getenvBoolDefault("DISCOVERY_AUTO_BIND_DISABLED", false),
+
+		ConnectorIncidentSLA:                 defaultConnectorIncidentSLA,
+		ConnectorIncidentCheckInterval:       defaultConnectorIncidentCheckInterval,
+		ConnectorIncidentPagerDutyRoutingKey: strings.TrimSpace(os.Getenv("CONNECTOR_INCIDENT_PAGERDUTY_ROUTING_KEY")),
+		ConnectorIncidentWebhookURL:          strings.TrimSpace(os.Getenv("CONNECTOR_INCIDENT_WEBHOOK_URL")),
+
+		ConnectorFreshnessAmberAfter: defaultConnectorFreshnessAmberAfter,
+		ConnectorFreshnessRedAfter:   defaultConnectorFreshnessRedAfter,
+
+		DisplayTimezone: strings.TrimSpace(getenvDefault("DISPLAY_TIMEZONE", defaultDisplayTimezone)),
+
+		OIDCIssuerURL:     strings.TrimRight(strings.TrimSpace(os.Getenv("OIDC_ISSUER_URL")), "/"),
+		OIDCClientID:      strings.TrimSpace(os.Getenv("OIDC_CLIENT_ID")),
+		OIDCClientSecret:  strings.TrimSpace(os.Getenv("OIDC_CLIENT_SECRET")),
+		OIDCRedirectURL:   strings.TrimSpace(os.Getenv("OIDC_REDIRECT_URL")),
+		OIDCScopes:        parseListEnv(getenvDefault("OIDC_SCOPES", defaultOIDCScopes)),
+		OIDCGroupsClaim:   strings.TrimSpace(getenvDefault("OIDC_GROUPS_CLAIM", defaultOIDCGroupsClaim)),
+		OIDCAdminGroups:   parseListEnv(os.Getenv("OIDC_ADMIN_GROUPS")),
+		OIDCAnalystGroups: parseListEnv(os.Getenv("OIDC_ANALYST_GROUPS")),
+		OIDCViewerGroups:  parseListEnv(os.Getenv("OIDC_VIEWER_GROUPS")),
+	}
+
+	// An explicitly empty CREDENTIAL_SHARED_NAME_PATTERNS disables name-based shared credential detection.
+	if v, ok := os.LookupEnv("CREDENTIAL_SHARED_NAME_PATTERNS"); ok {
+		cfg.CredentialSharedNamePatterns = parseListEnv(v)
+	}
+	for _, pattern := range cfg.CredentialSharedNamePatterns {
+		if strings.ContainsFunc(pattern, func(r rune) bool { return (r < 'a' || r > 'z') && (r < '0' || r > '9') }) {
+			return cfg, fmt.Errorf("CREDENTIAL_SHARED_NAME_PATTERNS entries must be single words of letters and digits, got %q", pattern)
+		}
+	}
+
+	rotationSLA := defaultCredentialRotationSLADays
+	if v, ok := os.LookupEnv("CREDENTIAL_ROTATION_SLA_DAYS"); ok {
+		rotationSLA = v
+	}
+	rotationSLADays, err := parseDaysMapEnv(rotationSLA)
+	if err != nil {
+		return cfg, fmt.Errorf("CREDENTIAL_ROTATION_SLA_DAYS: %w", err)
+	}
+	cfg.CredentialRotationSLADays = rotationSLADays
+
+	// An explicitly empty CREDENTIAL_HIGH_PRIVILEGE_KINDS rates no kind as high privilege.
+	if v, ok := os.LookupEnv("CREDENTIAL_HIGH_PRIVILEGE_KINDS"); ok {
+		cfg.CredentialHighPrivilegeKinds = parseListEnv(v)
+	}
+	riskOverrides, err := parseCredentialRiskOverridesEnv(os.Getenv("CREDENTIAL_RISK_OVERRIDES"))
+	if err != nil {
+		return cfg, fmt.Errorf("CREDENTIAL_RISK_OVERRIDES: %w", err)
+	}
+	cfg.CredentialRiskOverrides = riskOverrides
+
+	minLevel := strings.ToLower(strings.TrimSpace(getenvDefault("ENTITLEMENT_HIGH_PRIVILEGE_MIN_LEVEL", defaultEntitlementHighPrivilegeMinLevel)))
+	if !slices.Contains([]string{"none", "read", "write", "admin", "owner"}, minLevel) {
+		return cfg, errors.New("ENTITLEMENT_HIGH_PRIVILEGE_MIN_LEVEL must be none, read, write, admin, or owner")
+	}
+	cfg.EntitlementHighPrivilegeMinLevel = minLevel
+	// An explicitly empty ENTITLEMENT_HIGH_PRIVILEGE_PATTERNS leaves only the level test.
+	privilegePatterns := defaultEntitlementHighPrivilegePatterns
+	if v, ok := os.LookupEnv("ENTITLEMENT_HIGH_PRIVILEGE_PATTERNS"); ok {
+		privilegePatterns = v
+	}
+	if cfg.EntitlementHighPrivilegePatterns, err = parseEntitlementPrivilegePatternsEnv(privilegePatterns); err != nil {
+		return cfg, fmt.Errorf("ENTITLEMENT_HIGH_PRIVILEGE_PATTERNS: %w", err)
 	}
 
 	// Metrics are disabled by default in the Go binary (empty address). Some deployment methods (e.g. Helm)
@@ -113,6 +433,38 @@ func LoadWithOptions(opts LoadOptions) (Config, error) {
 		cfg.MetricsAddr = ""
 	}
 
+	// getenvIntDefault rejects 0, which here disables the limit.
+	if v := strings.TrimSpace(os.Getenv("SYNC_MAX_CONCURRENT_RUNS")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return cfg, fmt.Errorf("SYNC_MAX_CONCURRENT_RUNS must be a non-negative integer, got %q", v)
+		}
+		cfg.SyncMaxConcurrentRuns = n
+	}
+
+	// As above, 0 is meaningful: it keeps every sync run.
+	if v := strings.TrimSpace(os.Getenv("SYNC_RUN_RETENTION")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return cfg, fmt.Errorf("SYNC_RUN_RETENTION must be a non-negative integer, got %q", v)
+		}
+		cfg.SyncRunRetention = n
+	}
+
+	if v := strings.TrimSpace(os.Getenv("RAW_JSON_MAX_BYTES")); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return cfg, fmt.Errorf("RAW_JSON_MAX_BYTES must be a non-negative integer, got %q", v)
+		}
+		cfg.RawJSONMaxBytes = n
+	}
+
+	if d, ok, err := parseDurationEnv("METRICS_INVENTORY_CACHE_TTL", true); err != nil {
+		return cfg, err
+	} else if ok {
+		cfg.MetricsInventoryCacheTTL = d
+	}
+
 	if d, ok, err := parseDurationEnv("SYNC_INTERVAL", false); err != nil {
 		return cfg, err
 	} else if ok {
@@ -158,6 +510,46 @@ func LoadWithOptions(opts LoadOptions) (Config, error) {
 	} else if ok {
 		cfg.SyncFailureBackoffMax = d
 	}
+	if d, ok, err := parseDurationEnv("CONNECTOR_HTTP_TIMEOUT", true); err != nil {
+		return cfg, err
+	} else if ok {
+		cfg.ConnectorHTTPTimeout = d
+	}
+	requestRates, err := parseRateMapEnv(os.Getenv("CONNECTOR_REQUESTS_PER_SECOND"))
+	if err != nil {
+		return cfg, fmt.Errorf("CONNECTOR_REQUESTS_PER_SECOND: %w", err)
+	}
+	cfg.ConnectorRequestsPerSecond = requestRates
+	maxConcurrentRequests, err := parseCountMapEnv(os.Getenv("CONNECTOR_MAX_CONCURRENT_REQUESTS"))
+	if err != nil {
+		return cfg, fmt.Errorf("CONNECTOR_MAX_CONCURRENT_REQUESTS: %w", err)
+	}
+	cfg.ConnectorMaxConcurrentRequests = maxConcurrentRequests
+	maxBackoff, err := parseDurationMapEnv(os.Getenv("CONNECTOR_MAX_BACKOFF"))
+	if err != nil {
+		return cfg, fmt.Errorf("CONNECTOR_MAX_BACKOFF: %w", err)
+	}
+	for kind, d := range maxBackoff {
+		if d <= 0 {
+			return cfg, fmt.Errorf("CONNECTOR_MAX_BACKOFF: entry for %q must have a positive duration", kind)
+		}
+	}
+	cfg.ConnectorMaxBackoff = maxBackoff
+	runTimeouts, err := parseDurationMapEnv(getenvDefault("SYNC_RUN_TIMEOUT", defaultSyncRunTimeouts))
+	if err != nil {
+		return cfg, fmt.Errorf("SYNC_RUN_TIMEOUT: %w", err)
+	}
+	cfg.SyncRunTimeouts = runTimeouts
+	apiRequestBudgets, err := parseCountMapEnv(os.Getenv("SYNC_API_REQUEST_BUDGET"))
+	if err != nil {
+		return cfg, fmt.Errorf("SYNC_API_REQUEST_BUDGET: %w", err)
+	}
+	cfg.SyncAPIRequestBudgets = apiRequestBudgets
+	if d, ok, err := parseDurationEnv("SYNC_API_REQUEST_BUDGET_WINDOW", true); err != nil {
+		return cfg, err
+	} else if ok {
+		cfg.SyncAPIRequestBudgetWindow = d
+	}
 	if d, ok, err := parseDurationEnv("SYNC_LOCK_TTL", true); err != nil {
 		return cfg, err
 	} else if ok {
@@ -173,6 +565,80 @@ func LoadWithOptions(opts LoadOptions) (Config, error) {
 	} else if ok {
 		cfg.SyncLockHeartbeatTimeout = d
 	}
+	if d, ok, err := parseDurationEnv("CONNECTOR_INCIDENT_SLA", true); err != nil {
+		return cfg, err
+	} else if ok {
+		cfg.ConnectorIncidentSLA = d
+	}
+	if d, ok, err := parseDurationEnv("CONNECTOR_INCIDENT_CHECK_INTERVAL", true); err != nil {
+		return cfg, err
+	} else if ok {
+		cfg.ConnectorIncidentCheckInterval = d
+	}
+	if d, ok, err := parseDurationEnv("CONNECTOR_FRESHNESS_AMBER_AFTER", true); err != nil {
+		return cfg, err
+	} else if ok {
+		cfg.ConnectorFreshnessAmberAfter = d
+	}
+	if d, ok, err := parseDurationEnv("CONNECTOR_FRESHNESS_RED_AFTER", true); err != nil {
+		return cfg, err
+	} else if ok {
+		cfg.ConnectorFreshnessRedAfter = d
+	}
+	if d, ok, err := parseDurationEnv("DISCOVERY_OAUTH_ANOMALY_WINDOW", true); err != nil {
+		return cfg, err
+	} else if ok {
+		cfg.DiscoveryOAuthAnomalyWindow = d
+	}
+	if d, ok, err := parseDurationEnv("DISCOVERY_OAUTH_ANOMALY_NEW_APP_MAX_AGE", true); err != nil {
+		return cfg, err
+	} else if ok {
+		cfg.DiscoveryOAuthAnomalyNewAppMaxAge = d
+	}
+	if d, ok, err := parseDurationEnv("DISCOVERY_BACKFILL_LOOKBACK", true); err != nil {
+		return cfg, err
+	} else if ok {
+		cfg.DiscoveryBackfillLookback = d
+	}
+	if c, ok, err := parseConfidenceEnv("DISCOVERY_AUTO_BIND_CLIENT_ID_CONFIDENCE"); err != nil {
+		return cfg, err
+	} else if ok {
+		cfg.DiscoveryAutoBindClientIDConfidence = c
+	}
+	if c, ok, err := parseConfidenceEnv("DISCOVERY_AUTO_BIND_NAME_CONFIDENCE"); err != nil {
+		return cfg, err
+	} else if ok {
+		cfg.DiscoveryAutoBindNameConfidence = c
+	}
+	if c, ok, err := parseConfidenceEnv("DISCOVERY_AUTO_BIND_MIN_CONFIDENCE"); err != nil {
+		return cfg, err
+	} else if ok {
+		cfg.DiscoveryAutoBindMinConfidence = c
+	}
+	switch cfg.ConnectorSecretBackend {
+	case "env", "vault":
+	default:
+		return cfg, fmt.Errorf("CONNECTOR_SECRET_BACKEND must be env or vault, got %q", cfg.ConnectorSecretBackend)
+	}
+	if cfg.ConnectorIncidentPagerDutyRoutingKey != "" && cfg.ConnectorIncidentWebhookURL != "" {
+		return cfg, errors.New("set only one of CONNECTOR_INCIDENT_PAGERDUTY_ROUTING_KEY and CONNECTOR_INCIDENT_WEBHOOK_URL")
+	}
+	if cfg.ConnectorFreshnessRedAfter < cfg.ConnectorFreshnessAmberAfter {
+		return cfg, fmt.Errorf("CONNECTOR_FRESHNESS_RED_AFTER (%s) must not be shorter than CONNECTOR_FRESHNESS_AMBER_AFTER (%s)", cfg.ConnectorFreshnessRedAfter, cfg.ConnectorFreshnessAmberAfter)
+	}
+
+	if _, err := time.LoadLocation(cfg.DisplayTimezone); err != nil {
+		return cfg, fmt.Errorf("DISPLAY_TIMEZONE must be an IANA time zone such as Europe/Berlin, got %q", cfg.DisplayTimezone)
+	}
+
+	if cfg.OIDCEnabled() {
+		if cfg.OIDCClientID == "" || cfg.OIDCClientSecret == "" || cfg.OIDCRedirectURL == "" {
+			return cfg, errors.New("OIDC_ISSUER_URL requires OIDC_CLIENT_ID, OIDC_CLIENT_SECRET, and OIDC_REDIRECT_URL")
+		}
+		if !slices.Contains(cfg.OIDCScopes, "openid") {
+			cfg.OIDCScopes = append([]string{"openid"}, cfg.OIDCScopes...)
+		}
+	}
 
 	if opts.RequireDatabaseURL && cfg.DatabaseURL == "" {
 		return cfg, errors.New("DATABASE_URL is required")
@@ -215,6 +681,172 @@ func getenvBoolDefault(key string, def bool) bool {
 	}
 }
 
+// parseListEnv splits a comma-separated value into trimmed, lowercased, de-duplicated entries.
+func parseListEnv(v string) []string {
+	out := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, part := range strings.Split(v, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		if _, ok := seen[part]; ok {
+			continue
+		}
+		seen[part] = struct{}{}
+		out = append(out, part)
+	}
+	return out
+}
+
+// parseDaysMapEnv parses comma-separated key=days pairs into a map keyed by lowercased key.
+func parseDaysMapEnv(v string) (map[string]int, error) {
+	out := make(map[string]int)
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, raw, ok := strings.Cut(part, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || key == "" {
+			return nil, fmt.Errorf("entry %q must be key=days", part)
+		}
+		days, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || days < 0 {
+			return nil, fmt.Errorf("entry %q must have a non-negative number of days", part)
+		}
+		out[key] = days
+	}
+	return out, nil
+}
+
+// parseCountMapEnv parses comma-separated key=count pairs into a map keyed by lowercased key.
+func parseCountMapEnv(v string) (map[string]int64, error) {
+	out := make(map[string]int64)
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, raw, ok := strings.Cut(part, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || key == "" {
+			return nil, fmt.Errorf("entry %q must be key=count", part)
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("entry %q must have a non-negative count", part)
+		}
+		out[key] = n
+	}
+	return out, nil
+}
+
+// parseRateMapEnv parses comma-separated key=rate pairs into a map keyed by lowercased key.
+func parseRateMapEnv(v string) (map[string]float64, error) {
+	out := make(map[string]float64)
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, raw, ok := strings.Cut(part, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || key == "" {
+			return nil, fmt.Errorf("entry %q must be key=rate", part)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || rate < 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+			return nil, fmt.Errorf("entry %q must have a non-negative rate", part)
+		}
+		out[key] = rate
+	}
+	return out, nil
+}
+
+func parseDurationMapEnv(v string) (map[string]time.Duration, error) {
+	out := make(map[string]time.Duration)
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, raw, ok := strings.Cut(part, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || key == "" {
+			return nil, fmt.Errorf("entry %q must be key=duration", part)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("entry %q must have a non-negative duration", part)
+		}
+		out[key] = d
+	}
+	return out, nil
+}
+
+// parseCredentialRiskOverridesEnv parses comma-separated kind[:scope]<=level (cap),
+// kind[:scope]>=level (floor), and kind[:scope]=level (force) entries.
+func parseCredentialRiskOverridesEnv(v string) ([]CredentialRiskOverride, error) {
+	var out []CredentialRiskOverride
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		idx := strings.LastIndex(part, "=")
+		if idx <= 0 {
+			return nil, fmt.Errorf("entry %q must be kind[:scope]<=level, >=level, or =level", part)
+		}
+		target, level := part[:idx], strings.ToLower(strings.TrimSpace(part[idx+1:]))
+		override := CredentialRiskOverride{Op: CredentialRiskForce, Level: level}
+		switch {
+		case strings.HasSuffix(target, "<"):
+			override.Op = CredentialRiskCap
+			target = strings.TrimSuffix(target, "<")
+		case strings.HasSuffix(target, ">"):
+			override.Op = CredentialRiskFloor
+			target = strings.TrimSuffix(target, ">")
+		}
+		kind, scope, _ := strings.Cut(target, ":")
+		override.Kind = strings.ToLower(strings.TrimSpace(kind))
+		override.Scope = strings.TrimSpace(scope)
+		if override.Kind == "" {
+			return nil, fmt.Errorf("entry %q must name a credential kind", part)
+		}
+		if !slices.Contains([]string{"low", "medium", "high", "critical"}, level) {
+			return nil, fmt.Errorf("entry %q must set low, medium, high, or critical", part)
+		}
+		out = append(out, override)
+	}
+	return out, nil
+}
+
+// parseEntitlementPrivilegePatternsEnv parses comma-separated kind[=value] patterns.
+func parseEntitlementPrivilegePatternsEnv(v string) ([]EntitlementPrivilegePattern, error) {
+	var out []EntitlementPrivilegePattern
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kind, value, _ := strings.Cut(part, "=")
+		pattern := EntitlementPrivilegePattern{
+			Kind:  strings.ToLower(strings.TrimSpace(kind)),
+			Value: strings.ToLower(strings.TrimSpace(value)),
+		}
+		if pattern.Kind == "" {
+			return nil, fmt.Errorf("entry %q must name an entitlement kind", part)
+		}
+		if _, err := path.Match(pattern.Value, ""); err != nil {
+			return nil, fmt.Errorf("entry %q has an invalid pattern: %w", part, err)
+		}
+		out = append(out, pattern)
+	}
+	return out, nil
+}
+
 func parseDurationEnv(key string, requirePositive bool) (time.Duration, bool, error) {
 	v := strings.TrimSpace(os.Getenv(key))
 	if v == "" {
@@ -229,3 +861,16 @@ func parseDurationEnv(key string, requirePositive bool) (time.Duration, bool, er
 	}
 	return d, true, nil
 }
+
+// parseConfidenceEnv parses a confidence between 0 and 1.
+func parseConfidenceEnv(key string) (float32, bool, error) {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return 0, false, nil
+	}
+	c, err := strconv.ParseFloat(v, 32)
+	if err != nil || c < 0 || c > 1 {
+		return 0, false, fmt.Errorf("%s must be a number between 0 and 1, got %q", key, v)
+	}
+	return float32(c), true, nil
+}
@@ -1,6 +1,10 @@
 package config
 
-import "testing"
+import (
+	"slices"
+	"testing"
+	"time"
+)
 
 func TestLoadWithOptions_DefaultSyncDiscoveryInterval(t *testing.T) {
 	t.Setenv("DATABASE_URL", "")
@@ -47,3 +51,377 @@ func TestLoadWithOptions_RejectsNonPositiveConnectorInterval(t *testing.T) {
 		t.Fatalf("expected non-positive interval error")
 	}
 }
+
+func TestLoadWithOptions_CredentialSharedNamePatterns(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+
+	t.Setenv("CREDENTIAL_SHARED_NAME_PATTERNS", " Robot, svc ,,robot ")
+	cfg, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if len(cfg.CredentialSharedNamePatterns) != 2 || cfg.CredentialSharedNamePatterns[0] != "robot" || cfg.CredentialSharedNamePatterns[1] != "svc" {
+		t.Fatalf("CredentialSharedNamePatterns = %v, want [robot svc]", cfg.CredentialSharedNamePatterns)
+	}
+
+	t.Setenv("CREDENTIAL_SHARED_NAME_PATTERNS", "")
+	cfg, err = LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if len(cfg.CredentialSharedNamePatterns) != 0 {
+		t.Fatalf("CredentialSharedNamePatterns = %v, want disabled when explicitly empty", cfg.CredentialSharedNamePatterns)
+	}
+
+	t.Setenv("CREDENTIAL_SHARED_NAME_PATTERNS", "bot,ci-")
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected error for a pattern that is not a single word")
+	}
+}
+
+func TestLoadWithOptions_SyncMaxConcurrentRunsAllowsZero(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+	t.Setenv("SYNC_MAX_CONCURRENT_RUNS", "0")
+
+	cfg, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.SyncMaxConcurrentRuns != 0 {
+		t.Fatalf("SyncMaxConcurrentRuns = %d, want 0 (unlimited)", cfg.SyncMaxConcurrentRuns)
+	}
+
+	t.Setenv("SYNC_MAX_CONCURRENT_RUNS", "-1")
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected error for negative SYNC_MAX_CONCURRENT_RUNS")
+	}
+}
+
+func TestLoadWithOptions_RejectsUnknownConnectorSecretBackend(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+	t.Setenv("CONNECTOR_SECRET_BACKEND", "keychain")
+
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected error for unknown CONNECTOR_SECRET_BACKEND")
+	}
+}
+
+func TestLoadWithOptions_CredentialRotationSLADays(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+
+	t.Setenv("CREDENTIAL_ROTATION_SLA_DAYS", " Default=180, vault_token=0 ")
+	cfg, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if len(cfg.CredentialRotationSLADays) != 2 || cfg.CredentialRotationSLADays["default"] != 180 || cfg.CredentialRotationSLADays["vault_token"] != 0 {
+		t.Fatalf("CredentialRotationSLADays = %v, want map[default:180 vault_token:0]", cfg.CredentialRotationSLADays)
+	}
+
+	t.Setenv("CREDENTIAL_ROTATION_SLA_DAYS", "github_deploy_key=soon")
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected invalid CREDENTIAL_ROTATION_SLA_DAYS error")
+	}
+}
+
+func TestLoadWithOptions_EntitlementHighPrivilegePolicy(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+
+	cfg, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.EntitlementHighPrivilegeMinLevel != "admin" || !slices.Equal(cfg.EntitlementHighPrivilegePatterns, DefaultEntitlementHighPrivilegePatterns()) {
+		t.Fatalf("defaults = %q %+v", cfg.EntitlementHighPrivilegeMinLevel, cfg.EntitlementHighPrivilegePatterns)
+	}
+
+	t.Setenv("ENTITLEMENT_HIGH_PRIVILEGE_MIN_LEVEL", "Owner")
+	t.Setenv("ENTITLEMENT_HIGH_PRIVILEGE_PATTERNS", "aws_permission_set=*poweruser*, Datadog_Role")
+	cfg, err = LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	want := []EntitlementPrivilegePattern{
+		{Kind: "aws_permission_set", Value: "*poweruser*"},
+		{Kind: "datadog_role"},
+	}
+	if cfg.EntitlementHighPrivilegeMinLevel != "owner" || !slices.Equal(cfg.EntitlementHighPrivilegePatterns, want) {
+		t.Fatalf("custom = %q %+v, want owner %+v", cfg.EntitlementHighPrivilegeMinLevel, cfg.EntitlementHighPrivilegePatterns, want)
+	}
+
+	t.Setenv("ENTITLEMENT_HIGH_PRIVILEGE_MIN_LEVEL", "root")
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected invalid ENTITLEMENT_HIGH_PRIVILEGE_MIN_LEVEL error")
+	}
+	t.Setenv("ENTITLEMENT_HIGH_PRIVILEGE_MIN_LEVEL", "none")
+	t.Setenv("ENTITLEMENT_HIGH_PRIVILEGE_PATTERNS", "=admin")
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected invalid ENTITLEMENT_HIGH_PRIVILEGE_PATTERNS error")
+	}
+}
+
+func TestLoadWithOptions_CredentialRiskPolicy(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+
+	t.Setenv("CREDENTIAL_HIGH_PRIVILEGE_KINDS", "entra_client_secret, Google_OAuth_Grant")
+	t.Setenv("CREDENTIAL_RISK_OVERRIDES", "github_deploy_key:read_only<=medium, google_oauth_grant:https://mail.google.com/>=Critical, vault_token=low")
+	cfg, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if !slices.Equal(cfg.CredentialHighPrivilegeKinds, []string{"entra_client_secret", "google_oauth_grant"}) {
+		t.Fatalf("CredentialHighPrivilegeKinds = %v", cfg.CredentialHighPrivilegeKinds)
+	}
+	want := []CredentialRiskOverride{
+		{Kind: "github_deploy_key", Scope: "read_only", Op: CredentialRiskCap, Level: "medium"},
+		{Kind: "google_oauth_grant", Scope: "https://mail.google.com/", Op: CredentialRiskFloor, Level: "critical"},
+		{Kind: "vault_token", Op: CredentialRiskForce, Level: "low"},
+	}
+	if !slices.Equal(cfg.CredentialRiskOverrides, want) {
+		t.Fatalf("CredentialRiskOverrides = %+v, want %+v", cfg.CredentialRiskOverrides, want)
+	}
+
+	if !slices.Equal(cfg.CredentialActiveStatuses, DefaultCredentialActiveStatuses()) {
+		t.Fatalf("CredentialActiveStatuses = %v, want defaults", cfg.CredentialActiveStatuses)
+	}
+
+	t.Setenv("CREDENTIAL_ACTIVE_STATUSES", "active, Enabled")
+	cfg, err = LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if !slices.Equal(cfg.CredentialActiveStatuses, []string{"active", "enabled"}) {
+		t.Fatalf("CredentialActiveStatuses = %v", cfg.CredentialActiveStatuses)
+	}
+
+	t.Setenv("CREDENTIAL_RISK_OVERRIDES", "github_deploy_key<=severe")
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected invalid CREDENTIAL_RISK_OVERRIDES error")
+	}
+}
+
+func TestLoadWithOptions_OIDC(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+	t.Setenv("OIDC_ISSUER_URL", "https://idp.example.com/")
+	t.Setenv("OIDC_CLIENT_ID", "open-sspm")
+	t.Setenv("OIDC_CLIENT_SECRET", "")
+	t.Setenv("OIDC_REDIRECT_URL", "https://sspm.example.com/login/oidc/callback")
+
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected error when OIDC_CLIENT_SECRET is missing")
+	}
+
+	t.Setenv("OIDC_CLIENT_SECRET", "secret")
+	t.Setenv("OIDC_SCOPES", "email,groups")
+	t.Setenv("OIDC_ADMIN_GROUPS", "SSPM-Admins")
+	cfg, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if !cfg.OIDCEnabled() || cfg.OIDCIssuerURL != "https://idp.example.com" {
+		t.Fatalf("OIDCIssuerURL = %q, want trailing slash trimmed", cfg.OIDCIssuerURL)
+	}
+	if len(cfg.OIDCScopes) != 3 || cfg.OIDCScopes[0] != "openid" {
+		t.Fatalf("OIDCScopes = %v, want openid prepended", cfg.OIDCScopes)
+	}
+	if cfg.OIDCGroupsClaim != "groups" || len(cfg.OIDCAdminGroups) != 1 || cfg.OIDCAdminGroups[0] != "sspm-admins" {
+		t.Fatalf("groups claim %q admin groups %v", cfg.OIDCGroupsClaim, cfg.OIDCAdminGroups)
+	}
+}
+
+func TestLoadWithOptions_DisplayTimezone(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+	t.Setenv("DISPLAY_TIMEZONE", "Mars/Olympus")
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected error for an unknown DISPLAY_TIMEZONE")
+	}
+
+	t.Setenv("DISPLAY_TIMEZONE", "")
+	cfg, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.DisplayTimezone != "UTC" {
+		t.Fatalf("DisplayTimezone = %q, want UTC", cfg.DisplayTimezone)
+	}
+}
+
+func TestLoadWithOptions_SyncRunRetention(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+	t.Setenv("SYNC_RUN_RETENTION", "")
+
+	cfg, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.SyncRunRetention != defaultSyncRunRetention {
+		t.Fatalf("SyncRunRetention = %d, want default %d", cfg.SyncRunRetention, defaultSyncRunRetention)
+	}
+
+	t.Setenv("SYNC_RUN_RETENTION", "0")
+	cfg, err = LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.SyncRunRetention != 0 {
+		t.Fatalf("SyncRunRetention = %d, want 0 (keep all)", cfg.SyncRunRetention)
+	}
+
+	t.Setenv("SYNC_RUN_RETENTION", "-5")
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected error for negative SYNC_RUN_RETENTION")
+	}
+}
+
+func TestLoadWithOptions_SyncRunTimeouts(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+	t.Setenv("SYNC_RUN_TIMEOUT", "")
+	t.Setenv("CONNECTOR_HTTP_TIMEOUT", "")
+
+	cfg, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if len(cfg.SyncRunTimeouts) != 1 || cfg.SyncRunTimeouts["default"] != 2*time.Hour {
+		t.Fatalf("SyncRunTimeouts = %v, want map[default:2h]", cfg.SyncRunTimeouts)
+	}
+	if cfg.ConnectorHTTPTimeout != 0 {
+		t.Fatalf("ConnectorHTTPTimeout = %s, want 0 (connector defaults)", cfg.ConnectorHTTPTimeout)
+	}
+
+	t.Setenv("SYNC_RUN_TIMEOUT", "default=90m, Okta=6h, vault=0")
+	t.Setenv("CONNECTOR_HTTP_TIMEOUT", "45s")
+	cfg, err = LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.SyncRunTimeouts["default"] != 90*time.Minute || cfg.SyncRunTimeouts["okta"] != 6*time.Hour || cfg.SyncRunTimeouts["vault"] != 0 {
+		t.Fatalf("SyncRunTimeouts = %v", cfg.SyncRunTimeouts)
+	}
+	if cfg.ConnectorHTTPTimeout != 45*time.Second {
+		t.Fatalf("ConnectorHTTPTimeout = %s, want 45s", cfg.ConnectorHTTPTimeout)
+	}
+
+	t.Setenv("SYNC_RUN_TIMEOUT", "okta=forever")
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected invalid SYNC_RUN_TIMEOUT error")
+	}
+	t.Setenv("SYNC_RUN_TIMEOUT", "")
+	t.Setenv("CONNECTOR_HTTP_TIMEOUT", "0s")
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected error for non-positive CONNECTOR_HTTP_TIMEOUT")
+	}
+}
+
+func TestLoadWithOptions_SyncAPIRequestBudgets(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+	t.Setenv("SYNC_API_REQUEST_BUDGET", "")
+	t.Setenv("SYNC_API_REQUEST_BUDGET_WINDOW", "")
+
+	cfg, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if len(cfg.SyncAPIRequestBudgets) != 0 || cfg.SyncAPIRequestBudgetWindow != time.Hour {
+		t.Fatalf("budgets/window = %v/%s, want none per 1h", cfg.SyncAPIRequestBudgets, cfg.SyncAPIRequestBudgetWindow)
+	}
+
+	t.Setenv("SYNC_API_REQUEST_BUDGET", "GitHub=4500, entra=10000")
+	t.Setenv("SYNC_API_REQUEST_BUDGET_WINDOW", "30m")
+	cfg, err = LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.SyncAPIRequestBudgets["github"] != 4500 || cfg.SyncAPIRequestBudgets["entra"] != 10000 || cfg.SyncAPIRequestBudgetWindow != 30*time.Minute {
+		t.Fatalf("budgets/window = %v/%s", cfg.SyncAPIRequestBudgets, cfg.SyncAPIRequestBudgetWindow)
+	}
+
+	t.Setenv("SYNC_API_REQUEST_BUDGET", "github=-1")
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected invalid SYNC_API_REQUEST_BUDGET error")
+	}
+}
+
+func TestLoadWithOptions_ConnectorRateLimits(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+	t.Setenv("CONNECTOR_REQUESTS_PER_SECOND", "GitHub=2.5, google_workspace=50")
+	t.Setenv("CONNECTOR_MAX_CONCURRENT_REQUESTS", "github=2,google_workspace=0")
+	t.Setenv("CONNECTOR_MAX_BACKOFF", "github=2m")
+
+	cfg, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.ConnectorRequestsPerSecond["github"] != 2.5 || cfg.ConnectorRequestsPerSecond["google_workspace"] != 50 {
+		t.Fatalf("requests per second = %v", cfg.ConnectorRequestsPerSecond)
+	}
+	if n, ok := cfg.ConnectorMaxConcurrentRequests["google_workspace"]; cfg.ConnectorMaxConcurrentRequests["github"] != 2 || !ok || n != 0 {
+		t.Fatalf("max concurrent requests = %v", cfg.ConnectorMaxConcurrentRequests)
+	}
+	if cfg.ConnectorMaxBackoff["github"] != 2*time.Minute || len(cfg.ConnectorMaxBackoff) != 1 {
+		t.Fatalf("max backoff = %v", cfg.ConnectorMaxBackoff)
+	}
+
+	t.Setenv("CONNECTOR_REQUESTS_PER_SECOND", "github=fast")
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected invalid CONNECTOR_REQUESTS_PER_SECOND error")
+	}
+	t.Setenv("CONNECTOR_REQUESTS_PER_SECOND", "")
+	t.Setenv("CONNECTOR_MAX_BACKOFF", "github=0s")
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected error for non-positive CONNECTOR_MAX_BACKOFF")
+	}
+}
+
+func TestLoadWithOptions_DiscoveryAutoBindConfidence(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+	t.Setenv("DISCOVERY_AUTO_BIND_CLIENT_ID_CONFIDENCE", "")
+	t.Setenv("DISCOVERY_AUTO_BIND_NAME_CONFIDENCE", "0.75")
+	t.Setenv("DISCOVERY_AUTO_BIND_MIN_CONFIDENCE", "0.6")
+	t.Setenv("DISCOVERY_AUTO_BIND_DISABLED", "1")
+
+	cfg, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.DiscoveryAutoBindClientIDConfidence != 0.95 || cfg.DiscoveryAutoBindNameConfidence != 0.75 || cfg.DiscoveryAutoBindMinConfidence != 0.6 {
+		t.Fatalf("auto-bind confidence = %v/%v/%v, want 0.95/0.75/0.6", cfg.DiscoveryAutoBindClientIDConfidence, cfg.DiscoveryAutoBindNameConfidence, cfg.DiscoveryAutoBindMinConfidence)
+	}
+	if !cfg.DiscoveryAutoBindDisabled {
+		t.Fatalf("DiscoveryAutoBindDisabled = false, want true")
+	}
+
+	t.Setenv("DISCOVERY_AUTO_BIND_MIN_CONFIDENCE", "1.5")
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected invalid DISCOVERY_AUTO_BIND_MIN_CONFIDENCE error")
+	}
+}
+
+func TestLoadWithOptions_ConnectorFreshnessThresholds(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+	t.Setenv("CONNECTOR_FRESHNESS_AMBER_AFTER", "")
+	t.Setenv("CONNECTOR_FRESHNESS_RED_AFTER", "")
+
+	cfg, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.ConnectorFreshnessAmberAfter != defaultConnectorFreshnessAmberAfter || cfg.ConnectorFreshnessRedAfter != defaultConnectorFreshnessRedAfter {
+		t.Fatalf("freshness thresholds = %s/%s, want defaults", cfg.ConnectorFreshnessAmberAfter, cfg.ConnectorFreshnessRedAfter)
+	}
+
+	t.Setenv("CONNECTOR_FRESHNESS_AMBER_AFTER", "2h")
+	t.Setenv("CONNECTOR_FRESHNESS_RED_AFTER", "8h")
+	cfg, err = LoadWithOptions(LoadOptions{RequireDatabaseURL: false})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.ConnectorFreshnessAmberAfter != 2*time.Hour || cfg.ConnectorFreshnessRedAfter != 8*time.Hour {
+		t.Fatalf("freshness thresholds = %s/%s, want 2h/8h", cfg.ConnectorFreshnessAmberAfter, cfg.ConnectorFreshnessRedAfter)
+	}
+
+	t.Setenv("CONNECTOR_FRESHNESS_RED_AFTER", "1h")
+	if _, err := LoadWithOptions(LoadOptions{RequireDatabaseURL: false}); err == nil {
+		t.Fatalf("expected error for a red threshold shorter than the amber one")
+	}
+}
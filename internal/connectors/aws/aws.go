@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
 	"strings"
 	"time"
 
@@ -16,6 +15,7 @@ import (
 	identitystoretypes "github.com/aws/aws-sdk-go-v2/service/identitystore/types"
 	"github.com/aws/aws-sdk-go-v2/service/ssoadmin"
 	ssoadmintypes "github.com/aws/aws-sdk-go-v2/service/ssoadmin/types"
+	"github.com/open-sspm/open-sspm/internal/connectors/httpclient"
 )
 
 const defaultHTTPTimeout = 120 * time.Second
@@ -108,7 +108,7 @@ func New(ctx context.Context, opts Options) (*Client, error) {
 
 	loadOpts := []func(*config.LoadOptions) error{
 		config.WithRegion(region),
-		config.WithHTTPClient(&http.Client{Timeout: defaultHTTPTimeout}),
+		config.WithHTTPClient(httpclient.NewForKind("aws", defaultHTTPTimeout)),
 	}
 	if authType == "access_key" {
 		loadOpts = append(loadOpts, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
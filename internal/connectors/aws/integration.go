@@ -46,7 +46,7 @@ func (i *AWSIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpool.
 	started := time.Now()
 	slog.Info("syncing AWS Identity Center")
 
-	runID, err := q.CreateSyncRun(ctx, gen.CreateSyncRunParams{
+	runID, err := registry.CreateSyncRun(ctx, q, gen.CreateSyncRunParams{
 		SourceKind: "aws",
 		SourceName: i.sourceName,
 	})
@@ -111,7 +111,7 @@ func (i *AWSIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpool.
 		emails = append(emails, email)
 		displayNames = append(displayNames, display)
 		accountKinds = append(accountKinds, awsUserAccountKind(user))
-		rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeJSON(user.RawJSON), registry.EntityCategoryUser))
+		rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeRawJSON(user.RawJSON), registry.EntityCategoryUser))
 		lastLoginAts = append(lastLoginAts, pgtype.Timestamptz{})
 		lastLoginIps = append(lastLoginIps, "")
 		lastLoginRegions = append(lastLoginRegions, "")
@@ -131,38 +131,43 @@ func (i *AWSIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpool.
 		emails = append(emails, "")
 		displayNames = append(displayNames, display)
 		accountKinds = append(accountKinds, registry.AccountKindService)
-		rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeJSON(group.RawJSON), registry.EntityCategoryGroup))
+		rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeRawJSON(group.RawJSON), registry.EntityCategoryGroup))
 		lastLoginAts = append(lastLoginAts, pgtype.Timestamptz{})
 		lastLoginIps = append(lastLoginIps, "")
 		lastLoginRegions = append(lastLoginRegions, "")
 	}
 
-	for start := 0; start < len(externalIDs); start += userBatchSize {
-		end := min(start+userBatchSize, len(externalIDs))
-		_, err := q.UpsertAppUsersBulkBySource(ctx, gen.UpsertAppUsersBulkBySourceParams{
-			SourceKind:       "aws",
-			SourceName:       i.sourceName,
-			SeenInRunID:      runID,
-			ExternalIds:      externalIDs[start:end],
-			Emails:           emails[start:end],
-			DisplayNames:     displayNames[start:end],
-			AccountKinds:     accountKinds[start:end],
-			RawJsons:         rawJSONs[start:end],
-			LastLoginAts:     lastLoginAts[start:end],
-			LastLoginIps:     lastLoginIps[start:end],
-			LastLoginRegions: lastLoginRegions[start:end],
-		})
-		if err != nil {
-			report(registry.Event{Source: "aws", Stage: "write-users", Message: err.Error(), Err: err})
-			return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		for start := 0; start < len(externalIDs); start += userBatchSize {
+			end := min(start+userBatchSize, len(externalIDs))
+			_, err := qtx.UpsertAppUsersBulkBySource(ctx, gen.UpsertAppUsersBulkBySourceParams{
+				SourceKind:       "aws",
+				SourceName:       i.sourceName,
+				SeenInRunID:      runID,
+				ExternalIds:      externalIDs[start:end],
+				Emails:           emails[start:end],
+				DisplayNames:     displayNames[start:end],
+				AccountKinds:     accountKinds[start:end],
+				RawJsons:         rawJSONs[start:end],
+				LastLoginAts:     lastLoginAts[start:end],
+				LastLoginIps:     lastLoginIps[start:end],
+				LastLoginRegions: lastLoginRegions[start:end],
+			})
+			if err != nil {
+				return err
+			}
+			report(registry.Event{
+				Source:  "aws",
+				Stage:   "write-users",
+				Current: int64(end),
+				Total:   int64(len(externalIDs)),
+				Message: fmt.Sprintf("principals %d/%d", end, len(externalIDs)),
+			})
 		}
-		report(registry.Event{
-			Source:  "aws",
-			Stage:   "write-users",
-			Current: int64(end),
-			Total:   int64(len(externalIDs)),
-			Message: fmt.Sprintf("principals %d/%d", end, len(externalIDs)),
-		})
+		return nil
+	}); err != nil {
+		report(registry.Event{Source: "aws", Stage: "write-users", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
 	const entitlementBatchSize = 5000
@@ -198,25 +203,33 @@ func (i *AWSIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpool.
 		}
 	}
 
-	for start := 0; start < len(entAppUserExternalIDs); start += entitlementBatchSize {
-		end := min(start+entitlementBatchSize, len(entAppUserExternalIDs))
-		_, err := q.UpsertEntitlementsBulkBySource(ctx, gen.UpsertEntitlementsBulkBySourceParams{
-			SeenInRunID:        runID,
-			SourceKind:         "aws",
-			SourceName:         i.sourceName,
-			AppUserExternalIds: entAppUserExternalIDs[start:end],
-			Kinds:              entKinds[start:end],
-			Resources:          entResources[start:end],
-			Permissions:        entPermissions[start:end],
-			RawJsons:           entRawJSONs[start:end],
-		})
-		if err != nil {
-			report(registry.Event{Source: "aws", Stage: "write-users", Message: err.Error(), Err: err})
-			return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
+	entPrivilegeLevels := awsPrivilegeMapping.Levels(entKinds, entPermissions, nil)
+
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		for start := 0; start < len(entAppUserExternalIDs); start += entitlementBatchSize {
+			end := min(start+entitlementBatchSize, len(entAppUserExternalIDs))
+			_, err := qtx.UpsertEntitlementsBulkBySource(ctx, gen.UpsertEntitlementsBulkBySourceParams{
+				SeenInRunID:        runID,
+				SourceKind:         "aws",
+				SourceName:         i.sourceName,
+				AppUserExternalIds: entAppUserExternalIDs[start:end],
+				Kinds:              entKinds[start:end],
+				Resources:          entResources[start:end],
+				Permissions:        entPermissions[start:end],
+				RawJsons:           entRawJSONs[start:end],
+				PrivilegeLevels:    entPrivilegeLevels[start:end],
+			})
+			if err != nil {
+				return err
+			}
 		}
+		return nil
+	}); err != nil {
+		report(registry.Event{Source: "aws", Stage: "write-users", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
-	if err := registry.FinalizeAppRun(ctx, q, pool, runID, "aws", i.sourceName, time.Since(started), false); err != nil {
+	if err := registry.FinalizeAppRun(ctx, q, pool, runID, "aws", i.sourceName, time.Since(started), false, nil); err != nil {
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 	slog.Info("aws sync complete", "users", len(users))
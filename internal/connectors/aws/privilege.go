@@ -0,0 +1,13 @@
+package aws
+
+import "github.com/open-sspm/open-sspm/internal/connectors/registry"
+
+// awsPrivilegeMapping maps Identity Center permission set names to canonical levels using the
+// AWS managed job-function naming; custom permission sets default to write.
+var awsPrivilegeMapping = registry.PrivilegeMapping{
+	{Kind: "aws_permission_set", Permission: "*administrator*", Level: registry.PrivilegeAdmin},
+	{Kind: "aws_permission_set", Permission: "*readonly*", Level: registry.PrivilegeRead},
+	{Kind: "aws_permission_set", Permission: "*viewonly*", Level: registry.PrivilegeRead},
+	{Kind: "aws_permission_set", Permission: "*auditor*", Level: registry.PrivilegeRead},
+	{Kind: "aws_permission_set", Level: registry.PrivilegeWrite},
+}
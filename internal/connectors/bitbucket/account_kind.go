@@ -0,0 +1,15 @@
+package bitbucket
+
+import (
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+)
+
+func bitbucketUserAccountKind(user User) string {
+	signal := registry.ClassifyKindFromSignals(user.Nickname, user.DisplayName)
+	switch signal {
+	case registry.AccountKindBot, registry.AccountKindService:
+		return signal
+	default:
+		return registry.AccountKindHuman
+	}
+}
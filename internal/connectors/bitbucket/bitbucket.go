@@ -0,0 +1,649 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/httpclient"
+)
+
+const (
+	defaultTimeout   = 120 * time.Second
+	defaultPageLen   = 100
+	maxRetriesOn429  = 3
+	maxErrorBodySize = 1 << 20 // 1 MiB
+)
+
+type Client struct {
+	BaseURL   string
+	Workspace string
+	Username  string
+	Token     string
+	HTTP      *http.Client
+}
+
+type User struct {
+	UUID        string
+	AccountID   string
+	DisplayName string
+	Nickname    string
+	RawJSON     []byte
+}
+
+type WorkspacePermission struct {
+	User       User
+	Permission string
+	RawJSON    []byte
+}
+
+type Project struct {
+	UUID      string
+	Key       string
+	Name      string
+	IsPrivate bool
+	RawJSON   []byte
+}
+
+type ProjectPermission struct {
+	ProjectKey string
+	User       User
+	Permission string
+	RawJSON    []byte
+}
+
+type Repository struct {
+	UUID       string
+	Slug       string
+	FullName   string
+	Name       string
+	ProjectKey string
+	IsPrivate  bool
+	RawJSON    []byte
+}
+
+// AppPassword is a user-scoped app password. Bitbucket app passwords have no expiry.
+type AppPassword struct {
+	ID               string
+	Name             string
+	OwnerUUID        string
+	OwnerDisplayName string
+	Scopes           []string
+	CreatedAt        time.Time
+	LastUsedAt       time.Time
+	RawJSON          []byte
+}
+
+type AccessToken struct {
+	ID             string
+	Name           string
+	RepositorySlug string
+	Scopes         []string
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+	LastUsedAt     time.Time
+	RawJSON        []byte
+}
+
+// APIError is returned for non-2xx Bitbucket responses.
+type APIError struct {
+	StatusCode int
+	Status     string
+	URL        string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("bitbucket api failed: %s: %s (url=%s)", e.Status, e.Message, e.URL)
+	}
+	return fmt.Sprintf("bitbucket api failed: %s (url=%s)", e.Status, e.URL)
+}
+
+// HTTPStatusCode reports the response status for sync error classification.
+func (e *APIError) HTTPStatusCode() int { return e.StatusCode }
+
+// IsPermissionError reports whether err is a 401/403/404 response, which Bitbucket returns
+// when the configured credential cannot read an optional inventory endpoint.
+func IsPermissionError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return true
+	default:
+		return false
+	}
+}
+
+// New creates a new Bitbucket Cloud client. When username is set the token is sent as an
+// app password over basic auth; otherwise it is sent as a bearer access token.
+func New(baseURL, workspace, username, token string) (*Client, error) {
+	base := strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	workspace = strings.TrimSpace(workspace)
+	token = strings.TrimSpace(token)
+
+	if base == "" {
+		return nil, errors.New("bitbucket base URL is required")
+	}
+	if workspace == "" {
+		return nil, errors.New("bitbucket workspace is required")
+	}
+	if token == "" {
+		return nil, errors.New("bitbucket token is required")
+	}
+
+	return &Client{
+		BaseURL:   base,
+		Workspace: workspace,
+		Username:  strings.TrimSpace(username),
+		Token:     token,
+		HTTP:      httpclient.NewForKind("bitbucket", defaultTimeout),
+	}, nil
+}
+
+func (c *Client) ensureClient() error {
+	if c.BaseURL == "" {
+		return errors.New("bitbucket base URL is required")
+	}
+	if c.Workspace == "" {
+		return errors.New("bitbucket workspace is required")
+	}
+	if c.Token == "" {
+		return errors.New("bitbucket token is required")
+	}
+	if c.HTTP == nil {
+		return errors.New("bitbucket http client is not configured")
+	}
+	return nil
+}
+
+func (c *Client) ListWorkspacePermissions(ctx context.Context) ([]WorkspacePermission, error) {
+	if err := c.ensureClient(); err != nil {
+		return nil, err
+	}
+
+	var out []WorkspacePermission
+	path := fmt.Sprintf("/workspaces/%s/permissions", url.PathEscape(c.Workspace))
+	err := c.forEachValue(ctx, path, func(raw json.RawMessage) error {
+		var payload struct {
+			Permission string          `json:"permission"`
+			User       json.RawMessage `json:"user"`
+		}
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return err
+		}
+		user, err := mapUser(payload.User)
+		if err != nil {
+			return err
+		}
+		out = append(out, WorkspacePermission{
+			User:       user,
+			Permission: strings.ToLower(strings.TrimSpace(payload.Permission)),
+			RawJSON:    raw,
+		})
+		return nil
+	})
+	return out, err
+}
+
+func (c *Client) ListProjects(ctx context.Context) ([]Project, error) {
+	if err := c.ensureClient(); err != nil {
+		return nil, err
+	}
+
+	var out []Project
+	path := fmt.Sprintf("/workspaces/%s/projects", url.PathEscape(c.Workspace))
+	err := c.forEachValue(ctx, path, func(raw json.RawMessage) error {
+		var payload struct {
+			UUID      string `json:"uuid"`
+			Key       string `json:"key"`
+			Name      string `json:"name"`
+			IsPrivate bool   `json:"is_private"`
+		}
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return err
+		}
+		out = append(out, Project{
+			UUID:      strings.TrimSpace(payload.UUID),
+			Key:       strings.TrimSpace(payload.Key),
+			Name:      strings.TrimSpace(payload.Name),
+			IsPrivate: payload.IsPrivate,
+			RawJSON:   raw,
+		})
+		return nil
+	})
+	return out, err
+}
+
+func (c *Client) ListProjectUserPermissions(ctx context.Context, projectKey string) ([]ProjectPermission, error) {
+	if err := c.ensureClient(); err != nil {
+		return nil, err
+	}
+	projectKey = strings.TrimSpace(projectKey)
+	if projectKey == "" {
+		return nil, errors.New("bitbucket project key is required")
+	}
+
+	var out []ProjectPermission
+	path := fmt.Sprintf("/workspaces/%s/projects/%s/permissions-config/users", url.PathEscape(c.Workspace), url.PathEscape(projectKey))
+	err := c.forEachValue(ctx, path, func(raw json.RawMessage) error {
+		var payload struct {
+			Permission string          `json:"permission"`
+			User       json.RawMessage `json:"user"`
+		}
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return err
+		}
+		user, err := mapUser(payload.User)
+		if err != nil {
+			return err
+		}
+		out = append(out, ProjectPermission{
+			ProjectKey: projectKey,
+			User:       user,
+			Permission: strings.ToLower(strings.TrimSpace(payload.Permission)),
+			RawJSON:    raw,
+		})
+		return nil
+	})
+	return out, err
+}
+
+func (c *Client) ListRepositories(ctx context.Context) ([]Repository, error) {
+	if err := c.ensureClient(); err != nil {
+		return nil, err
+	}
+
+	var out []Repository
+	path := fmt.Sprintf("/repositories/%s", url.PathEscape(c.Workspace))
+	err := c.forEachValue(ctx, path, func(raw json.RawMessage) error {
+		var payload struct {
+			UUID      string `json:"uuid"`
+			Slug      string `json:"slug"`
+			FullName  string `json:"full_name"`
+			Name      string `json:"name"`
+			IsPrivate bool   `json:"is_private"`
+			Project   struct {
+				Key string `json:"key"`
+			} `json:"project"`
+		}
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return err
+		}
+		type sanitizedRepository struct {
+			FullName   string `json:"full_name"`
+			ProjectKey string `json:"project_key,omitempty"`
+			IsPrivate  bool   `json:"is_private"`
+		}
+		sanitized, err := json.Marshal(sanitizedRepository{
+			FullName:   strings.TrimSpace(payload.FullName),
+			ProjectKey: strings.TrimSpace(payload.Project.Key),
+			IsPrivate:  payload.IsPrivate,
+		})
+		if err != nil {
+			return err
+		}
+		out = append(out, Repository{
+			UUID:       strings.TrimSpace(payload.UUID),
+			Slug:       strings.TrimSpace(payload.Slug),
+			FullName:   strings.TrimSpace(payload.FullName),
+			Name:       strings.TrimSpace(payload.Name),
+			ProjectKey: strings.TrimSpace(payload.Project.Key),
+			IsPrivate:  payload.IsPrivate,
+			RawJSON:    sanitized,
+		})
+		return nil
+	})
+	return out, err
+}
+
+// ListAppPasswords lists the app passwords owned by a workspace member. Reading another
+// member's app passwords requires a workspace admin credential.
+func (c *Client) ListAppPasswords(ctx context.Context, owner User) ([]AppPassword, error) {
+	if err := c.ensureClient(); err != nil {
+		return nil, err
+	}
+	ownerUUID := strings.TrimSpace(owner.UUID)
+	if ownerUUID == "" {
+		return nil, errors.New("bitbucket user uuid is required")
+	}
+
+	var out []AppPassword
+	path := fmt.Sprintf("/users/%s/app-passwords", url.PathEscape(ownerUUID))
+	err := c.forEachValue(ctx, path, func(raw json.RawMessage) error {
+		var payload struct {
+			UUID         string          `json:"uuid"`
+			Name         string          `json:"name"`
+			Scopes       []string        `json:"scopes"`
+			CreatedOn    string          `json:"created_on"`
+			LastAccessed json.RawMessage `json:"last_accessed"`
+		}
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return err
+		}
+		createdAt, err := parseTime(payload.CreatedOn)
+		if err != nil {
+			return fmt.Errorf("bitbucket app password %s created_on: %w", payload.UUID, err)
+		}
+		lastUsedAt, err := parseOptionalTime(payload.LastAccessed)
+		if err != nil {
+			return fmt.Errorf("bitbucket app password %s last_accessed: %w", payload.UUID, err)
+		}
+		out = append(out, AppPassword{
+			ID:               strings.TrimSpace(payload.UUID),
+			Name:             strings.TrimSpace(payload.Name),
+			OwnerUUID:        ownerUUID,
+			OwnerDisplayName: strings.TrimSpace(owner.DisplayName),
+			Scopes:           payload.Scopes,
+			CreatedAt:        createdAt,
+			LastUsedAt:       lastUsedAt,
+			RawJSON:          raw,
+		})
+		return nil
+	})
+	return out, err
+}
+
+func (c *Client) ListRepositoryAccessTokens(ctx context.Context, repoSlug string) ([]AccessToken, error) {
+	if err := c.ensureClient(); err != nil {
+		return nil, err
+	}
+	repoSlug = strings.TrimSpace(repoSlug)
+	if repoSlug == "" {
+		return nil, errors.New("bitbucket repository slug is required")
+	}
+
+	var out []AccessToken
+	path := fmt.Sprintf("/repositories/%s/%s/access-tokens", url.PathEscape(c.Workspace), url.PathEscape(repoSlug))
+	err := c.forEachValue(ctx, path, func(raw json.RawMessage) error {
+		var payload struct {
+			ID           json.Number     `json:"id"`
+			Name         string          `json:"name"`
+			Scopes       []string        `json:"scopes"`
+			CreatedOn    string          `json:"created_on"`
+			ExpiresOn    json.RawMessage `json:"expires_on"`
+			LastAccessed json.RawMessage `json:"last_accessed"`
+		}
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return err
+		}
+		createdAt, err := parseTime(payload.CreatedOn)
+		if err != nil {
+			return fmt.Errorf("bitbucket access token %s created_on: %w", payload.ID, err)
+		}
+		expiresAt, err := parseOptionalTime(payload.ExpiresOn)
+		if err != nil {
+			return fmt.Errorf("bitbucket access token %s expires_on: %w", payload.ID, err)
+		}
+		lastUsedAt, err := parseOptionalTime(payload.LastAccessed)
+		if err != nil {
+			return fmt.Errorf("bitbucket access token %s last_accessed: %w", payload.ID, err)
+		}
+		out = append(out, AccessToken{
+			ID:             strings.TrimSpace(payload.ID.String()),
+			Name:           strings.TrimSpace(payload.Name),
+			RepositorySlug: repoSlug,
+			Scopes:         payload.Scopes,
+			CreatedAt:      createdAt,
+			ExpiresAt:      expiresAt,
+			LastUsedAt:     lastUsedAt,
+			RawJSON:        raw,
+		})
+		return nil
+	})
+	return out, err
+}
+
+// forEachValue walks a paginated Bitbucket collection, following "next" links until exhausted.
+func (c *Client) forEachValue(ctx context.Context, path string, fn func(json.RawMessage) error) error {
+	endpoint, err := c.endpoint(path)
+	if err != nil {
+		return err
+	}
+	for endpoint != "" {
+		body, err := c.get(ctx, endpoint)
+		if err != nil {
+			return err
+		}
+		var payload struct {
+			Values []json.RawMessage `json:"values"`
+			Next   string            `json:"next"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return err
+		}
+		for _, raw := range payload.Values {
+			if err := fn(raw); err != nil {
+				return err
+			}
+		}
+		endpoint = strings.TrimSpace(payload.Next)
+	}
+	return nil
+}
+
+// endpoint joins the base URL and path. Path segments must already be escaped.
+func (c *Client) endpoint(path string) (string, error) {
+	base := strings.TrimRight(c.BaseURL, "/")
+	if base == "" {
+		return "", errors.New("bitbucket base URL is required")
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	rawPath := strings.TrimRight(u.EscapedPath(), "/") + path
+	unescaped, err := url.PathUnescape(rawPath)
+	if err != nil {
+		return "", err
+	}
+	u.Path = unescaped
+	u.RawPath = rawPath
+	q := u.Query()
+	q.Set("pagelen", strconv.Itoa(defaultPageLen))
+	u.RawQuery = q.Encode()
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+func (c *Client) get(ctx context.Context, endpoint string) ([]byte, error) {
+	var lastErr error
+	for attempt := 0; attempt <= maxRetriesOn429; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		if c.Username != "" {
+			req.SetBasicAuth(c.Username, c.Token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+c.Token)
+		}
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", "open-sspm")
+
+		resp, err := c.HTTP.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			return body, nil
+		}
+		body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		resp.Body.Close()
+		if readErr != nil {
+			return nil, readErr
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			lastErr = newAPIError(endpoint, resp, body)
+			if attempt == maxRetriesOn429 {
+				return nil, lastErr
+			}
+			wait, ok := retryAfterDuration(resp.Header.Get("Retry-After"))
+			if !ok {
+				wait = time.Second
+			}
+			if err := sleep(ctx, min(wait, httpclient.MaxBackoff("bitbucket"))); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		return nil, newAPIError(endpoint, resp, body)
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, errors.New("bitbucket request failed")
+}
+
+func newAPIError(endpoint string, resp *http.Response, body []byte) *APIError {
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		URL:        safeURL(endpoint),
+		Message:    extractAPIErrorMessage(body),
+	}
+}
+
+func extractAPIErrorMessage(body []byte) string {
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+			Detail  string `json:"detail"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if msg := strings.TrimSpace(payload.Error.Message); msg != "" {
+			return msg
+		}
+		if msg := strings.TrimSpace(payload.Error.Detail); msg != "" {
+			return msg
+		}
+	}
+
+	msg := strings.TrimSpace(string(body))
+	if msg == "" || strings.HasPrefix(msg, "<!DOCTYPE html") || strings.HasPrefix(msg, "<html") {
+		return ""
+	}
+	msg = strings.Join(strings.Fields(msg), " ")
+	const maxLen = 300
+	if len(msg) > maxLen {
+		msg = msg[:maxLen] + "…"
+	}
+	return msg
+}
+
+func mapUser(raw json.RawMessage) (User, error) {
+	var payload struct {
+		UUID        string `json:"uuid"`
+		AccountID   string `json:"account_id"`
+		DisplayName string `json:"display_name"`
+		Nickname    string `json:"nickname"`
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return User{}, err
+		}
+	}
+
+	type sanitizedUser struct {
+		DisplayName string `json:"display_name"`
+		Nickname    string `json:"nickname,omitempty"`
+		AccountID   string `json:"account_id,omitempty"`
+	}
+	sanitized, err := json.Marshal(sanitizedUser{
+		DisplayName: strings.TrimSpace(payload.DisplayName),
+		Nickname:    strings.TrimSpace(payload.Nickname),
+		AccountID:   strings.TrimSpace(payload.AccountID),
+	})
+	if err != nil {
+		return User{}, err
+	}
+
+	return User{
+		UUID:        strings.TrimSpace(payload.UUID),
+		AccountID:   strings.TrimSpace(payload.AccountID),
+		DisplayName: strings.TrimSpace(payload.DisplayName),
+		Nickname:    strings.TrimSpace(payload.Nickname),
+		RawJSON:     sanitized,
+	}, nil
+}
+
+func parseTime(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", raw)
+	}
+	return t, nil
+}
+
+func parseOptionalTime(raw json.RawMessage) (time.Time, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return time.Time{}, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return time.Time{}, err
+	}
+	return parseTime(s)
+}
+
+func retryAfterDuration(header string) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+	secs, err := strconv.Atoi(header)
+	if err != nil || secs < 0 {
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func safeURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	if u.RawQuery != "" {
+		return u.Scheme + "://" + u.Host + u.Path + "?" + u.RawQuery
+	}
+	return u.Scheme + "://" + u.Host + u.Path
+}
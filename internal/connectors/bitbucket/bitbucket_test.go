@@ -0,0 +1,144 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestClientFollowsPaginationAndUsesBasicAuth(t *testing.T) {
+	t.Parallel()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "admin" || pass != "app-secret" {
+			t.Errorf("basic auth = %q %q %v", user, pass, ok)
+		}
+		if r.URL.Path != "/2.0/workspaces/acme/permissions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("page") == "" {
+			writeJSON(t, w, map[string]any{
+				"values": []any{
+					map[string]any{"permission": "owner", "user": map[string]any{"uuid": "{u-1}", "display_name": "Alice"}},
+				},
+				"next": server.URL + "/2.0/workspaces/acme/permissions?page=2",
+			})
+			return
+		}
+		writeJSON(t, w, map[string]any{
+			"values": []any{
+				map[string]any{"permission": "member", "user": map[string]any{"uuid": "{u-2}", "nickname": "deploy-bot"}},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL+"/2.0", "acme", "admin", "app-secret")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	members, err := client.ListWorkspacePermissions(context.Background())
+	if err != nil {
+		t.Fatalf("ListWorkspacePermissions() error = %v", err)
+	}
+	if len(members) != 2 {
+		t.Fatalf("members = %+v, want 2", members)
+	}
+	if members[0].User.UUID != "{u-1}" || members[0].Permission != "owner" {
+		t.Fatalf("members[0] = %+v", members[0])
+	}
+	if members[1].User.Nickname != "deploy-bot" || members[1].Permission != "member" {
+		t.Fatalf("members[1] = %+v", members[1])
+	}
+}
+
+func TestClientAppPasswordsPermissionError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer access-token" {
+			t.Errorf("authorization = %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"message":"Your credentials lack one or more required privilege scopes."}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL, "acme", "", "access-token")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	_, err = client.ListAppPasswords(context.Background(), User{UUID: "{u-1}"})
+	if err == nil {
+		t.Fatalf("expected permission error")
+	}
+	if !IsPermissionError(err) {
+		t.Fatalf("IsPermissionError(%v) = false", err)
+	}
+}
+
+func TestClientReadsSuccessBodiesPastErrorBodyCap(t *testing.T) {
+	t.Parallel()
+
+	values := make([]any, 0, 6000)
+	for n := range 6000 {
+		values = append(values, map[string]any{
+			"uuid":      "{repo-" + strings.Repeat("x", 200) + "-" + strconv.Itoa(n) + "}",
+			"slug":      "repo-" + strconv.Itoa(n),
+			"full_name": "acme/repo-" + strconv.Itoa(n),
+		})
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"values": values})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL, "acme", "", "access-token")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	repositories, err := client.ListRepositories(context.Background())
+	if err != nil {
+		t.Fatalf("ListRepositories() error = %v, want a page larger than the error body cap to decode", err)
+	}
+	if len(repositories) != len(values) {
+		t.Fatalf("repositories = %d, want %d", len(repositories), len(values))
+	}
+}
+
+func TestClientEscapesPathSegments(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.EscapedPath(), "/repositories/acme%20co/team%2Fapp/access-tokens"; got != want {
+			t.Errorf("path = %s, want %s", got, want)
+		}
+		writeJSON(t, w, map[string]any{"values": []any{}})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL, "acme co", "", "access-token")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := client.ListRepositoryAccessTokens(context.Background(), "team/app"); err != nil {
+		t.Fatalf("ListRepositoryAccessTokens() error = %v", err)
+	}
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, payload any) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		t.Fatalf("encode response: %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package bitbucket
+
+import (
+	"context"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/configstore"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+type Definition struct{}
+
+func (d *Definition) Kind() string {
+	return configstore.KindBitbucket
+}
+
+func (d *Definition) DisplayName() string {
+	return "Bitbucket"
+}
+
+func (d *Definition) Role() registry.IntegrationRole {
+	return registry.RoleApp
+}
+
+func (d *Definition) DecodeConfig(raw []byte) (any, error) {
+	cfg, err := configstore.DecodeBitbucketConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Normalized(), nil
+}
+
+func (d *Definition) ValidateConfig(cfg any) error {
+	return cfg.(configstore.BitbucketConfig).Validate()
+}
+
+func (d *Definition) IsConfigured(cfg any) bool {
+	c := cfg.(configstore.BitbucketConfig).Normalized()
+	return c.Workspace != "" && c.Token != ""
+}
+
+func (d *Definition) SourceName(cfg any) string {
+	return cfg.(configstore.BitbucketConfig).Normalized().Workspace
+}
+
+func (d *Definition) DefaultSubtitle() string {
+	return "Workspace members, project permissions, app passwords, and access tokens."
+}
+
+func (d *Definition) ConfiguredSubtitle(cfg any) string {
+	c := cfg.(configstore.BitbucketConfig).Normalized()
+	if c.Workspace == "" {
+		return d.DefaultSubtitle()
+	}
+	return "Workspace " + c.Workspace
+}
+
+func (d *Definition) SettingsHref() string {
+	return "/settings/connectors?open=bitbucket"
+}
+
+func (d *Definition) MetricsProvider() registry.MetricsProvider {
+	return &bitbucketMetrics{}
+}
+
+func (d *Definition) NewIntegration(cfg any) (registry.Integration, error) {
+	c := cfg.(configstore.BitbucketConfig).Normalized()
+	client, err := New(c.APIBase, c.Workspace, c.Username, c.Token)
+	if err != nil {
+		return nil, err
+	}
+	return NewBitbucketIntegration(client, c.Workspace), nil
+}
+
+// CredentialKinds documents the credential kinds the integration syncs.
+func (d *Definition) CredentialKinds() []registry.CredentialKindInfo {
+	return []registry.CredentialKindInfo{
+		{
+			Kind:             credentialKindAppPassword,
+			Label:            "Bitbucket app password",
+			Description:      "A per-user password with selected scopes, used by scripts and tools to access Bitbucket.",
+			RotationGuidance: "Create a new app password with the fewest scopes, update the tool, and revoke the old one. Atlassian is replacing app passwords with API tokens.",
+		},
+		{
+			Kind:             credentialKindAccessToken,
+			Label:            "Bitbucket access token",
+			Description:      "A repository, project, or workspace access token that acts as a bot user with its own scopes.",
+			RotationGuidance: "Create a replacement token with the fewest scopes and an expiry, update the integration, then revoke the old token.",
+		},
+	}
+}
+
+type bitbucketMetrics struct{}
+
+func (m *bitbucketMetrics) FetchMetrics(ctx context.Context, q *gen.Queries, sourceName string) (registry.ConnectorMetrics, error) {
+	total, err := q.CountAppUsersBySource(ctx, gen.CountAppUsersBySourceParams{
+		SourceKind: "bitbucket",
+		SourceName: sourceName,
+	})
+	if err != nil {
+		return registry.ConnectorMetrics{}, err
+	}
+	matched, err := q.CountMatchedAppUsersBySource(ctx, gen.CountMatchedAppUsersBySourceParams{
+		SourceKind: "bitbucket",
+		SourceName: sourceName,
+	})
+	if err != nil {
+		return registry.ConnectorMetrics{}, err
+	}
+	unmatched, err := q.CountUnmatchedAppUsersBySource(ctx, gen.CountUnmatchedAppUsersBySourceParams{
+		SourceKind: "bitbucket",
+		SourceName: sourceName,
+	})
+	if err != nil {
+		return registry.ConnectorMetrics{}, err
+	}
+	return registry.ConnectorMetrics{
+		Total:     total,
+		Matched:   matched,
+		Unmatched: unmatched,
+	}, nil
+}
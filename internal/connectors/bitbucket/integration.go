@@ -0,0 +1,744 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+const (
+	bitbucketUserBatchSize        = 1000
+	bitbucketEntitlementBatchSize = 5000
+	bitbucketAssetBatchSize       = 1000
+	bitbucketCredentialBatchSize  = 1000
+
+	credentialKindAppPassword = "bitbucket_app_password"
+	credentialKindAccessToken = "bitbucket_access_token"
+)
+
+type BitbucketIntegration struct {
+	client    *Client
+	workspace string
+}
+
+type bitbucketAccountUpsertRow struct {
+	ExternalID  string
+	DisplayName string
+	AccountKind string
+	RawJSON     []byte
+}
+
+type bitbucketEntitlementUpsertRow struct {
+	AppUserExternalID string
+	Kind              string
+	Resource          string
+	Permission        string
+	RawJSON           []byte
+}
+
+type bitbucketAssetUpsertRow struct {
+	AssetKind        string
+	ExternalID       string
+	ParentExternalID string
+	DisplayName      string
+	RawJSON          []byte
+}
+
+type bitbucketCredentialUpsertRow struct {
+	AssetRefKind         string
+	AssetRefExternalID   string
+	CredentialKind       string
+	ExternalID           string
+	DisplayName          string
+	ScopeJSON            []byte
+	Status               string
+	CreatedAtSource      pgtype.Timestamptz
+	ExpiresAtSource      pgtype.Timestamptz
+	LastUsedAtSource     pgtype.Timestamptz
+	CreatedByKind        string
+	CreatedByExternalID  string
+	CreatedByDisplayName string
+	RawJSON              []byte
+}
+
+func NewBitbucketIntegration(client *Client, workspace string) *BitbucketIntegration {
+	return &BitbucketIntegration{
+		client:    client,
+		workspace: strings.TrimSpace(workspace),
+	}
+}
+
+func (i *BitbucketIntegration) Kind() string { return "bitbucket" }
+func (i *BitbucketIntegration) Name() string { return i.workspace }
+func (i *BitbucketIntegration) Role() registry.IntegrationRole {
+	return registry.RoleApp
+}
+
+func (i *BitbucketIntegration) InitEvents() []registry.Event {
+	return []registry.Event{
+		{Source: "bitbucket", Stage: "list-members", Current: 0, Total: 1, Message: "listing workspace members"},
+		{Source: "bitbucket", Stage: "list-projects", Current: 0, Total: registry.UnknownTotal, Message: "listing projects and project permissions"},
+		{Source: "bitbucket", Stage: "list-repositories", Current: 0, Total: 1, Message: "listing repositories"},
+		{Source: "bitbucket", Stage: "list-credentials", Current: 0, Total: registry.UnknownTotal, Message: "listing app passwords and repository access tokens"},
+		{Source: "bitbucket", Stage: "write-users", Current: 0, Total: registry.UnknownTotal, Message: "writing members"},
+		{Source: "bitbucket", Stage: "write-entitlements", Current: 0, Total: registry.UnknownTotal, Message: "writing workspace and project permissions"},
+		{Source: "bitbucket", Stage: "write-assets", Current: 0, Total: registry.UnknownTotal, Message: "writing workspace, project, and repository assets"},
+		{Source: "bitbucket", Stage: "write-credentials", Current: 0, Total: registry.UnknownTotal, Message: "writing app password and access token credentials"},
+	}
+}
+
+func (i *BitbucketIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, report func(registry.Event), _ registry.RunMode) error {
+	started := time.Now()
+	slog.Info("syncing Bitbucket", "workspace", i.workspace)
+
+	runID, err := registry.CreateSyncRun(ctx, q, gen.CreateSyncRunParams{
+		SourceKind: "bitbucket",
+		SourceName: i.workspace,
+	})
+	if err != nil {
+		return err
+	}
+
+	warnings := registry.NewWarningReporter(report)
+	defer func() {
+		_ = registry.PersistSyncRunWarnings(ctx, q, runID, warnings.Warnings())
+	}()
+	holds := registry.NewExpiryHolds()
+
+	members, err := i.client.ListWorkspacePermissions(ctx)
+	if err != nil {
+		report(registry.Event{Source: "bitbucket", Stage: "list-members", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindAPI)
+	}
+	report(registry.Event{Source: "bitbucket", Stage: "list-members", Current: 1, Total: 1, Message: fmt.Sprintf("found %d members", len(members))})
+
+	projects, err := i.client.ListProjects(ctx)
+	if err != nil {
+		report(registry.Event{Source: "bitbucket", Stage: "list-projects", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindAPI)
+	}
+	var projectPermissions []ProjectPermission
+	for idx, project := range projects {
+		perms, err := i.client.ListProjectUserPermissions(ctx, project.Key)
+		if err != nil {
+			if IsPermissionError(err) {
+				warnings.ReportWarning(registry.Event{Source: "bitbucket", Stage: "list-projects", Message: fmt.Sprintf("project %s permissions unavailable: %v", project.Key, err)})
+				holds.HoldEntitlements("bitbucket_project_permission", "bitbucket_project:"+strings.TrimSpace(project.Key))
+				continue
+			}
+			report(registry.Event{Source: "bitbucket", Stage: "list-projects", Message: err.Error(), Err: err})
+			return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindAPI)
+		}
+		projectPermissions = append(projectPermissions, perms...)
+		report(registry.Event{
+			Source:  "bitbucket",
+			Stage:   "list-projects",
+			Current: int64(idx + 1),
+			Total:   int64(len(projects)),
+			Message: fmt.Sprintf("projects %d/%d", idx+1, len(projects)),
+		})
+	}
+	if len(projects) == 0 {
+		report(registry.Event{Source: "bitbucket", Stage: "list-projects", Current: 1, Total: 1, Message: "found 0 projects"})
+	}
+
+	repositories, err := i.client.ListRepositories(ctx)
+	if err != nil {
+		report(registry.Event{Source: "bitbucket", Stage: "list-repositories", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindAPI)
+	}
+	report(registry.Event{Source: "bitbucket", Stage: "list-repositories", Current: 1, Total: 1, Message: fmt.Sprintf("found %d repositories", len(repositories))})
+
+	appPasswords, accessTokens, err := i.listCredentials(ctx, members, repositories, warnings, holds)
+	if err != nil {
+		report(registry.Event{Source: "bitbucket", Stage: "list-credentials", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindAPI)
+	}
+	report(registry.Event{
+		Source:  "bitbucket",
+		Stage:   "list-credentials",
+		Current: 1,
+		Total:   1,
+		Message: fmt.Sprintf("found %d app passwords and %d repository access tokens", len(appPasswords), len(accessTokens)),
+	})
+
+	accountRows := buildBitbucketAccountRows(members, projectPermissions)
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return upsertBitbucketAccounts(ctx, qtx, report, runID, i.workspace, accountRows)
+	}); err != nil {
+		report(registry.Event{Source: "bitbucket", Stage: "write-users", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
+	}
+
+	entitlementRows := buildBitbucketEntitlementRows(i.workspace, members, projectPermissions)
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return upsertBitbucketEntitlements(ctx, qtx, report, runID, i.workspace, entitlementRows)
+	}); err != nil {
+		report(registry.Event{Source: "bitbucket", Stage: "write-entitlements", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
+	}
+
+	assetRows := buildBitbucketAssetRows(i.workspace, projects, repositories)
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return upsertBitbucketAssets(ctx, qtx, report, runID, i.workspace, assetRows)
+	}); err != nil {
+		report(registry.Event{Source: "bitbucket", Stage: "write-assets", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
+	}
+
+	credentialRows := buildBitbucketCredentialRows(i.workspace, appPasswords, accessTokens, time.Now().UTC())
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return upsertBitbucketCredentials(ctx, qtx, report, runID, i.workspace, credentialRows)
+	}); err != nil {
+		report(registry.Event{Source: "bitbucket", Stage: "write-credentials", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
+	}
+
+	if err := registry.FinalizeAppRun(ctx, q, pool, runID, "bitbucket", i.workspace, time.Since(started), false, holds); err != nil {
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
+	}
+
+	slog.Info(
+		"bitbucket sync complete",
+		"workspace", i.workspace,
+		"members", len(members),
+		"projects", len(projects),
+		"repositories", len(repositories),
+		"entitlements", len(entitlementRows),
+		"credentials", len(credentialRows),
+	)
+	return nil
+}
+
+// listCredentials collects app passwords per member and access tokens per repository.
+// Both endpoints need admin rights; the first permission error stops that listing with a
+// warning instead of failing the run, since membership inventory is still valid. The
+// credentials of a stopped listing are held from expiry.
+func (i *BitbucketIntegration) listCredentials(ctx context.Context, members []WorkspacePermission, repositories []Repository, warnings *registry.WarningReporter, holds *registry.ExpiryHolds) ([]AppPassword, []AccessToken, error) {
+	var appPasswords []AppPassword
+	for _, member := range members {
+		if strings.TrimSpace(member.User.UUID) == "" {
+			continue
+		}
+		passwords, err := i.client.ListAppPasswords(ctx, member.User)
+		if err != nil {
+			if IsPermissionError(err) {
+				warnings.ReportWarning(registry.Event{Source: "bitbucket", Stage: "list-credentials", Message: fmt.Sprintf("skipped app password inventory: %v", err)})
+				holds.HoldCredentials(credentialKindAppPassword, "")
+				break
+			}
+			return nil, nil, err
+		}
+		appPasswords = append(appPasswords, passwords...)
+	}
+
+	var accessTokens []AccessToken
+	for _, repo := range repositories {
+		if strings.TrimSpace(repo.Slug) == "" {
+			continue
+		}
+		tokens, err := i.client.ListRepositoryAccessTokens(ctx, repo.Slug)
+		if err != nil {
+			if IsPermissionError(err) {
+				warnings.ReportWarning(registry.Event{Source: "bitbucket", Stage: "list-credentials", Message: fmt.Sprintf("skipped repository access token inventory: %v", err)})
+				holds.HoldCredentials(credentialKindAccessToken, "")
+				break
+			}
+			return nil, nil, err
+		}
+		accessTokens = append(accessTokens, tokens...)
+	}
+
+	return appPasswords, accessTokens, nil
+}
+
+func buildBitbucketAccountRows(members []WorkspacePermission, projectPermissions []ProjectPermission) []bitbucketAccountUpsertRow {
+	seen := make(map[string]struct{}, len(members))
+	rows := make([]bitbucketAccountUpsertRow, 0, len(members))
+
+	add := func(user User) {
+		externalID := strings.TrimSpace(user.UUID)
+		if externalID == "" {
+			return
+		}
+		if _, ok := seen[externalID]; ok {
+			return
+		}
+		seen[externalID] = struct{}{}
+		rows = append(rows, bitbucketAccountUpsertRow{
+			ExternalID:  externalID,
+			DisplayName: firstNonEmptyString(user.DisplayName, user.Nickname, externalID),
+			AccountKind: bitbucketUserAccountKind(user),
+			RawJSON:     registry.WithEntityCategory(registry.NormalizeRawJSON(user.RawJSON), registry.EntityCategoryUser),
+		})
+	}
+
+	for _, member := range members {
+		add(member.User)
+	}
+	for _, perm := range projectPermissions {
+		add(perm.User)
+	}
+	return rows
+}
+
+func buildBitbucketEntitlementRows(workspace string, members []WorkspacePermission, projectPermissions []ProjectPermission) []bitbucketEntitlementUpsertRow {
+	seen := make(map[string]struct{})
+	rows := make([]bitbucketEntitlementUpsertRow, 0, len(members)+len(projectPermissions))
+
+	add := func(row bitbucketEntitlementUpsertRow) {
+		row.AppUserExternalID = strings.TrimSpace(row.AppUserExternalID)
+		row.Permission = strings.TrimSpace(row.Permission)
+		if row.AppUserExternalID == "" || row.Resource == "" || row.Permission == "" {
+			return
+		}
+		key := row.AppUserExternalID + "::" + row.Kind + "::" + row.Resource + "::" + row.Permission
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		rows = append(rows, row)
+	}
+
+	workspace = strings.TrimSpace(workspace)
+	for _, member := range members {
+		add(bitbucketEntitlementUpsertRow{
+			AppUserExternalID: member.User.UUID,
+			Kind:              "bitbucket_workspace_permission",
+			Resource:          "bitbucket_workspace:" + workspace,
+			Permission:        member.Permission,
+			RawJSON: registry.MarshalJSON(map[string]string{
+				"workspace":  workspace,
+				"permission": member.Permission,
+			}),
+		})
+	}
+	for _, perm := range projectPermissions {
+		projectKey := strings.TrimSpace(perm.ProjectKey)
+		if projectKey == "" {
+			continue
+		}
+		add(bitbucketEntitlementUpsertRow{
+			AppUserExternalID: perm.User.UUID,
+			Kind:              "bitbucket_project_permission",
+			Resource:          "bitbucket_project:" + projectKey,
+			Permission:        perm.Permission,
+			RawJSON: registry.MarshalJSON(map[string]string{
+				"workspace":   workspace,
+				"project_key": projectKey,
+				"permission":  perm.Permission,
+			}),
+		})
+	}
+	return rows
+}
+
+func buildBitbucketAssetRows(workspace string, projects []Project, repositories []Repository) []bitbucketAssetUpsertRow {
+	workspace = strings.TrimSpace(workspace)
+	rows := make([]bitbucketAssetUpsertRow, 0, 1+len(projects)+len(repositories))
+	if workspace != "" {
+		rows = append(rows, bitbucketAssetUpsertRow{
+			AssetKind:   "bitbucket_workspace",
+			ExternalID:  workspace,
+			DisplayName: workspace,
+			RawJSON:     registry.MarshalJSON(map[string]string{"workspace": workspace}),
+		})
+	}
+	for _, project := range projects {
+		if project.Key == "" {
+			continue
+		}
+		rows = append(rows, bitbucketAssetUpsertRow{
+			AssetKind:        "bitbucket_project",
+			ExternalID:       project.Key,
+			ParentExternalID: workspace,
+			DisplayName:      firstNonEmptyString(project.Name, project.Key),
+			RawJSON:          project.RawJSON,
+		})
+	}
+	for _, repo := range repositories {
+		if repo.Slug == "" {
+			continue
+		}
+		rows = append(rows, bitbucketAssetUpsertRow{
+			AssetKind:        "bitbucket_repository",
+			ExternalID:       repo.Slug,
+			ParentExternalID: firstNonEmptyString(repo.ProjectKey, workspace),
+			DisplayName:      firstNonEmptyString(repo.FullName, repo.Name, repo.Slug),
+			RawJSON:          repo.RawJSON,
+		})
+	}
+	return rows
+}
+
+// buildBitbucketCredentialRows maps app passwords and repository access tokens to credential rows.
+// App passwords never expire, so they keep an empty expiry and rely on last-used data for dormancy.
+func buildBitbucketCredentialRows(workspace string, appPasswords []AppPassword, accessTokens []AccessToken, now time.Time) []bitbucketCredentialUpsertRow {
+	workspace = strings.TrimSpace(workspace)
+	seen := make(map[string]struct{})
+	rows := make([]bitbucketCredentialUpsertRow, 0, len(appPasswords)+len(accessTokens))
+
+	add := func(row bitbucketCredentialUpsertRow) {
+		row.ExternalID = strings.TrimSpace(row.ExternalID)
+		if row.ExternalID == "" {
+			return
+		}
+		key := row.CredentialKind + "::" + row.ExternalID
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		rows = append(rows, row)
+	}
+
+	for _, password := range appPasswords {
+		ownerUUID := strings.TrimSpace(password.OwnerUUID)
+		passwordID := strings.TrimSpace(password.ID)
+		if ownerUUID == "" || passwordID == "" {
+			continue
+		}
+		add(bitbucketCredentialUpsertRow{
+			AssetRefKind:       "app_asset",
+			AssetRefExternalID: "bitbucket_workspace:" + workspace,
+			CredentialKind:     credentialKindAppPassword,
+			ExternalID:         ownerUUID + ":" + passwordID,
+			DisplayName:        firstNonEmptyString(password.Name, passwordID),
+			ScopeJSON: registry.MarshalJSON(map[string]any{
+				"scopes": dedupeStringSlice(password.Scopes),
+			}),
+			Status:               "active",
+			CreatedAtSource:      bitbucketTimestamptz(password.CreatedAt),
+			LastUsedAtSource:     bitbucketTimestamptz(password.LastUsedAt),
+			CreatedByKind:        "bitbucket_user",
+			CreatedByExternalID:  ownerUUID,
+			CreatedByDisplayName: password.OwnerDisplayName,
+			RawJSON:              password.RawJSON,
+		})
+	}
+
+	for _, token := range accessTokens {
+		repoSlug := strings.TrimSpace(token.RepositorySlug)
+		tokenID := strings.TrimSpace(token.ID)
+		if repoSlug == "" || tokenID == "" {
+			continue
+		}
+		expiresAt := bitbucketTimestamptz(token.ExpiresAt)
+		add(bitbucketCredentialUpsertRow{
+			AssetRefKind:       "app_asset",
+			AssetRefExternalID: "bitbucket_repository:" + repoSlug,
+			CredentialKind:     credentialKindAccessToken,
+			ExternalID:         repoSlug + ":" + tokenID,
+			DisplayName:        firstNonEmptyString(token.Name, tokenID),
+			ScopeJSON: registry.MarshalJSON(map[string]any{
+				"repository": repoSlug,
+				"scopes":     dedupeStringSlice(token.Scopes),
+			}),
+			Status:           bitbucketCredentialStatus(expiresAt, now),
+			CreatedAtSource:  bitbucketTimestamptz(token.CreatedAt),
+			ExpiresAtSource:  expiresAt,
+			LastUsedAtSource: bitbucketTimestamptz(token.LastUsedAt),
+			RawJSON:          token.RawJSON,
+		})
+	}
+
+	return rows
+}
+
+func upsertBitbucketAccounts(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, workspace string, rows []bitbucketAccountUpsertRow) error {
+	report(registry.Event{Source: "bitbucket", Stage: "write-users", Current: 0, Total: int64(len(rows)), Message: fmt.Sprintf("writing %d members", len(rows))})
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for start := 0; start < len(rows); start += bitbucketUserBatchSize {
+		end := min(start+bitbucketUserBatchSize, len(rows))
+		batch := rows[start:end]
+
+		externalIDs := make([]string, 0, len(batch))
+		emails := make([]string, 0, len(batch))
+		displayNames := make([]string, 0, len(batch))
+		accountKinds := make([]string, 0, len(batch))
+		rawJSONs := make([][]byte, 0, len(batch))
+		lastLoginAts := make([]pgtype.Timestamptz, 0, len(batch))
+		lastLoginIPs := make([]string, 0, len(batch))
+		lastLoginRegions := make([]string, 0, len(batch))
+
+		for _, row := range batch {
+			externalIDs = append(externalIDs, row.ExternalID)
+			emails = append(emails, "")
+			displayNames = append(displayNames, row.DisplayName)
+			accountKinds = append(accountKinds, registry.NormalizeAccountKind(row.AccountKind))
+			rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(row.RawJSON))
+			lastLoginAts = append(lastLoginAts, pgtype.Timestamptz{})
+			lastLoginIPs = append(lastLoginIPs, "")
+			lastLoginRegions = append(lastLoginRegions, "")
+		}
+
+		if _, err := q.UpsertAppUsersBulkBySource(ctx, gen.UpsertAppUsersBulkBySourceParams{
+			SourceKind:       "bitbucket",
+			SourceName:       workspace,
+			SeenInRunID:      runID,
+			ExternalIds:      externalIDs,
+			Emails:           emails,
+			DisplayNames:     displayNames,
+			AccountKinds:     accountKinds,
+			RawJsons:         rawJSONs,
+			LastLoginAts:     lastLoginAts,
+			LastLoginIps:     lastLoginIPs,
+			LastLoginRegions: lastLoginRegions,
+		}); err != nil {
+			return err
+		}
+
+		report(registry.Event{
+			Source:  "bitbucket",
+			Stage:   "write-users",
+			Current: int64(end),
+			Total:   int64(len(rows)),
+			Message: fmt.Sprintf("members %d/%d", end, len(rows)),
+		})
+	}
+
+	return nil
+}
+
+func upsertBitbucketEntitlements(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, workspace string, rows []bitbucketEntitlementUpsertRow) error {
+	report(registry.Event{Source: "bitbucket", Stage: "write-entitlements", Current: 0, Total: int64(len(rows)), Message: fmt.Sprintf("writing %d entitlements", len(rows))})
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for start := 0; start < len(rows); start += bitbucketEntitlementBatchSize {
+		end := min(start+bitbucketEntitlementBatchSize, len(rows))
+		batch := rows[start:end]
+
+		appUserExternalIDs := make([]string, 0, len(batch))
+		kinds := make([]string, 0, len(batch))
+		resources := make([]string, 0, len(batch))
+		permissions := make([]string, 0, len(batch))
+		rawJSONs := make([][]byte, 0, len(batch))
+		privilegeLevels := make([]int16, 0, len(batch))
+
+		for _, row := range batch {
+			appUserExternalIDs = append(appUserExternalIDs, row.AppUserExternalID)
+			kinds = append(kinds, row.Kind)
+			resources = append(resources, row.Resource)
+			permissions = append(permissions, row.Permission)
+			rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(row.RawJSON))
+			privilegeLevels = append(privilegeLevels, int16(bitbucketPrivilegeMapping.Level(row.Kind, row.Permission, "")))
+		}
+
+		if _, err := q.UpsertEntitlementsBulkBySource(ctx, gen.UpsertEntitlementsBulkBySourceParams{
+			SeenInRunID:        runID,
+			SourceKind:         "bitbucket",
+			SourceName:         workspace,
+			AppUserExternalIds: appUserExternalIDs,
+			Kinds:              kinds,
+			Resources:          resources,
+			Permissions:        permissions,
+			RawJsons:           rawJSONs,
+			PrivilegeLevels:    privilegeLevels,
+		}); err != nil {
+			return err
+		}
+
+		report(registry.Event{
+			Source:  "bitbucket",
+			Stage:   "write-entitlements",
+			Current: int64(end),
+			Total:   int64(len(rows)),
+			Message: fmt.Sprintf("entitlements %d/%d", end, len(rows)),
+		})
+	}
+
+	return nil
+}
+
+func upsertBitbucketAssets(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, workspace string, rows []bitbucketAssetUpsertRow) error {
+	report(registry.Event{Source: "bitbucket", Stage: "write-assets", Current: 0, Total: int64(len(rows)), Message: fmt.Sprintf("writing %d app assets", len(rows))})
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for start := 0; start < len(rows); start += bitbucketAssetBatchSize {
+		end := min(start+bitbucketAssetBatchSize, len(rows))
+		batch := rows[start:end]
+
+		assetKinds := make([]string, 0, len(batch))
+		externalIDs := make([]string, 0, len(batch))
+		parentExternalIDs := make([]string, 0, len(batch))
+		displayNames := make([]string, 0, len(batch))
+		statuses := make([]string, 0, len(batch))
+		createdAtSources := make([]pgtype.Timestamptz, 0, len(batch))
+		updatedAtSources := make([]pgtype.Timestamptz, 0, len(batch))
+		rawJSONs := make([][]byte, 0, len(batch))
+
+		for _, row := range batch {
+			assetKinds = append(assetKinds, row.AssetKind)
+			externalIDs = append(externalIDs, row.ExternalID)
+			parentExternalIDs = append(parentExternalIDs, row.ParentExternalID)
+			displayNames = append(displayNames, row.DisplayName)
+			statuses = append(statuses, "active")
+			createdAtSources = append(createdAtSources, pgtype.Timestamptz{})
+			updatedAtSources = append(updatedAtSources, pgtype.Timestamptz{})
+			rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(row.RawJSON))
+		}
+
+		if _, err := q.UpsertAppAssetsBulkBySource(ctx, gen.UpsertAppAssetsBulkBySourceParams{
+			SourceKind:        "bitbucket",
+			SourceName:        workspace,
+			SeenInRunID:       runID,
+			AssetKinds:        assetKinds,
+			ExternalIds:       externalIDs,
+			ParentExternalIds: parentExternalIDs,
+			DisplayNames:      displayNames,
+			Statuses:          statuses,
+			CreatedAtSources:  createdAtSources,
+			UpdatedAtSources:  updatedAtSources,
+			RawJsons:          rawJSONs,
+		}); err != nil {
+			return err
+		}
+
+		report(registry.Event{
+			Source:  "bitbucket",
+			Stage:   "write-assets",
+			Current: int64(end),
+			Total:   int64(len(rows)),
+			Message: fmt.Sprintf("assets %d/%d", end, len(rows)),
+		})
+	}
+
+	return nil
+}
+
+func upsertBitbucketCredentials(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, workspace string, rows []bitbucketCredentialUpsertRow) error {
+	report(registry.Event{Source: "bitbucket", Stage: "write-credentials", Current: 0, Total: int64(len(rows)), Message: fmt.Sprintf("writing %d credential rows", len(rows))})
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for start := 0; start < len(rows); start += bitbucketCredentialBatchSize {
+		end := min(start+bitbucketCredentialBatchSize, len(rows))
+		batch := rows[start:end]
+
+		assetRefKinds := make([]string, 0, len(batch))
+		assetRefExternalIDs := make([]string, 0, len(batch))
+		credentialKinds := make([]string, 0, len(batch))
+		externalIDs := make([]string, 0, len(batch))
+		displayNames := make([]string, 0, len(batch))
+		fingerprints := make([]string, 0, len(batch))
+		scopeJSONs := make([][]byte, 0, len(batch))
+		statuses := make([]string, 0, len(batch))
+		createdAtSources := make([]pgtype.Timestamptz, 0, len(batch))
+		expiresAtSources := make([]pgtype.Timestamptz, 0, len(batch))
+		lastUsedAtSources := make([]pgtype.Timestamptz, 0, len(batch))
+		createdByKinds := make([]string, 0, len(batch))
+		createdByExternalIDs := make([]string, 0, len(batch))
+		createdByDisplayNames := make([]string, 0, len(batch))
+		emptyStrings := make([]string, 0, len(batch))
+		rawJSONs := make([][]byte, 0, len(batch))
+
+		for _, row := range batch {
+			assetRefKinds = append(assetRefKinds, row.AssetRefKind)
+			assetRefExternalIDs = append(assetRefExternalIDs, row.AssetRefExternalID)
+			credentialKinds = append(credentialKinds, row.CredentialKind)
+			externalIDs = append(externalIDs, row.ExternalID)
+			displayNames = append(displayNames, row.DisplayName)
+			fingerprints = append(fingerprints, "")
+			scopeJSONs = append(scopeJSONs, registry.NormalizeJSON(row.ScopeJSON))
+			statuses = append(statuses, row.Status)
+			createdAtSources = append(createdAtSources, row.CreatedAtSource)
+			expiresAtSources = append(expiresAtSources, row.ExpiresAtSource)
+			lastUsedAtSources = append(lastUsedAtSources, row.LastUsedAtSource)
+			createdByKinds = append(createdByKinds, row.CreatedByKind)
+			createdByExternalIDs = append(createdByExternalIDs, row.CreatedByExternalID)
+			createdByDisplayNames = append(createdByDisplayNames, row.CreatedByDisplayName)
+			emptyStrings = append(emptyStrings, "")
+			rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(row.RawJSON))
+		}
+
+		if _, err := q.UpsertCredentialArtifactsBulkBySource(ctx, gen.UpsertCredentialArtifactsBulkBySourceParams{
+			SourceKind:             "bitbucket",
+			SourceName:             workspace,
+			SeenInRunID:            runID,
+			AssetRefKinds:          assetRefKinds,
+			AssetRefExternalIds:    assetRefExternalIDs,
+			CredentialKinds:        credentialKinds,
+			ExternalIds:            externalIDs,
+			DisplayNames:           displayNames,
+			Fingerprints:           fingerprints,
+			ScopeJsons:             scopeJSONs,
+			Statuses:               statuses,
+			CreatedAtSources:       createdAtSources,
+			ExpiresAtSources:       expiresAtSources,
+			LastUsedAtSources:      lastUsedAtSources,
+			CreatedByKinds:         createdByKinds,
+			CreatedByExternalIds:   createdByExternalIDs,
+			CreatedByDisplayNames:  createdByDisplayNames,
+			ApprovedByKinds:        emptyStrings,
+			ApprovedByExternalIds:  emptyStrings,
+			ApprovedByDisplayNames: emptyStrings,
+			RawJsons:               rawJSONs,
+		}); err != nil {
+			return err
+		}
+
+		report(registry.Event{
+			Source:  "bitbucket",
+			Stage:   "write-credentials",
+			Current: int64(end),
+			Total:   int64(len(rows)),
+			Message: fmt.Sprintf("credentials %d/%d", end, len(rows)),
+		})
+	}
+
+	return nil
+}
+
+func dedupeStringSlice(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		trimmed := strings.TrimSpace(value)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		out = append(out, trimmed)
+	}
+	return out
+}
+
+func firstNonEmptyString(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+func bitbucketCredentialStatus(expiresAt pgtype.Timestamptz, now time.Time) string {
+	if expiresAt.Valid && expiresAt.Time.UTC().Before(now.UTC()) {
+		return "expired"
+	}
+	return "active"
+}
+
+func bitbucketTimestamptz(t time.Time) pgtype.Timestamptz {
+	if t.IsZero() {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: t.UTC(), Valid: true}
+}
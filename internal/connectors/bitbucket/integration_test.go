@@ -0,0 +1,179 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+)
+
+func TestBuildBitbucketCredentialRowsNeverExpiringAppPassword(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	lastUsed := now.AddDate(0, 0, -180)
+	appPasswords := []AppPassword{
+		{
+			ID:               "7",
+			Name:             "deploy-script",
+			OwnerUUID:        "{u-1}",
+			OwnerDisplayName: "Alice",
+			Scopes:           []string{"repository:write", "repository:write", "pipeline"},
+			CreatedAt:        now.AddDate(-2, 0, 0),
+			LastUsedAt:       lastUsed,
+			RawJSON:          []byte(`{"uuid":"7"}`),
+		},
+	}
+
+	rows := buildBitbucketCredentialRows("acme", appPasswords, nil, now)
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 credential row, got %d", len(rows))
+	}
+	row := rows[0]
+	if row.CredentialKind != credentialKindAppPassword {
+		t.Fatalf("credential kind = %q", row.CredentialKind)
+	}
+	if row.ExternalID != "{u-1}:7" {
+		t.Fatalf("external id = %q", row.ExternalID)
+	}
+	if row.Status != "active" {
+		t.Fatalf("status = %q, want active", row.Status)
+	}
+	if row.ExpiresAtSource.Valid {
+		t.Fatalf("app password must not carry an expiry, got %v", row.ExpiresAtSource.Time)
+	}
+	if !row.LastUsedAtSource.Valid || !row.LastUsedAtSource.Time.Equal(lastUsed) {
+		t.Fatalf("last used = %+v, want %v", row.LastUsedAtSource, lastUsed)
+	}
+	if row.AssetRefKind != "app_asset" || row.AssetRefExternalID != "bitbucket_workspace:acme" {
+		t.Fatalf("asset ref = %s %s", row.AssetRefKind, row.AssetRefExternalID)
+	}
+	if row.CreatedByKind != "bitbucket_user" || row.CreatedByExternalID != "{u-1}" || row.CreatedByDisplayName != "Alice" {
+		t.Fatalf("created by = %s %s %s", row.CreatedByKind, row.CreatedByExternalID, row.CreatedByDisplayName)
+	}
+
+	var scope struct {
+		Scopes []string `json:"scopes"`
+	}
+	if err := json.Unmarshal(row.ScopeJSON, &scope); err != nil {
+		t.Fatalf("unmarshal scope json: %v", err)
+	}
+	if len(scope.Scopes) != 2 || scope.Scopes[0] != "repository:write" || scope.Scopes[1] != "pipeline" {
+		t.Fatalf("scopes = %v", scope.Scopes)
+	}
+}
+
+func TestBuildBitbucketCredentialRowsAccessTokenStatus(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	tokens := []AccessToken{
+		{ID: "1", Name: "ci", RepositorySlug: "api", ExpiresAt: now.Add(-time.Hour)},
+		{ID: "2", Name: "release", RepositorySlug: "api", ExpiresAt: now.Add(24 * time.Hour)},
+		{ID: "2", Name: "release", RepositorySlug: "api", ExpiresAt: now.Add(24 * time.Hour)},
+	}
+
+	rows := buildBitbucketCredentialRows("acme", nil, tokens, now)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 deduped credential rows, got %d", len(rows))
+	}
+	if rows[0].ExternalID != "api:1" || rows[0].Status != "expired" {
+		t.Fatalf("row[0] = %s %s", rows[0].ExternalID, rows[0].Status)
+	}
+	if rows[1].ExternalID != "api:2" || rows[1].Status != "active" || !rows[1].ExpiresAtSource.Valid {
+		t.Fatalf("row[1] = %s %s expires=%v", rows[1].ExternalID, rows[1].Status, rows[1].ExpiresAtSource.Valid)
+	}
+	if rows[0].CredentialKind != credentialKindAccessToken || rows[0].AssetRefExternalID != "bitbucket_repository:api" {
+		t.Fatalf("row[0] kind/asset = %s %s", rows[0].CredentialKind, rows[0].AssetRefExternalID)
+	}
+}
+
+func TestBuildBitbucketEntitlementRows(t *testing.T) {
+	t.Parallel()
+
+	members := []WorkspacePermission{
+		{User: User{UUID: "{u-1}"}, Permission: "owner"},
+		{User: User{UUID: "{u-2}"}, Permission: "member"},
+	}
+	projectPermissions := []ProjectPermission{
+		{ProjectKey: "CORE", User: User{UUID: "{u-2}"}, Permission: "admin"},
+		{ProjectKey: "CORE", User: User{UUID: "{u-2}"}, Permission: "admin"},
+		{ProjectKey: "", User: User{UUID: "{u-2}"}, Permission: "write"},
+	}
+
+	rows := buildBitbucketEntitlementRows("acme", members, projectPermissions)
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 entitlement rows, got %d", len(rows))
+	}
+	if rows[0].Kind != "bitbucket_workspace_permission" || rows[0].Resource != "bitbucket_workspace:acme" || rows[0].Permission != "owner" {
+		t.Fatalf("row[0] = %+v", rows[0])
+	}
+	if rows[2].Kind != "bitbucket_project_permission" || rows[2].Resource != "bitbucket_project:CORE" || rows[2].AppUserExternalID != "{u-2}" {
+		t.Fatalf("row[2] = %+v", rows[2])
+	}
+}
+
+func TestBuildBitbucketAccountRows(t *testing.T) {
+	t.Parallel()
+
+	members := []WorkspacePermission{
+		{User: User{UUID: "{u-1}", DisplayName: "Alice"}},
+		{User: User{UUID: "{u-2}", Nickname: "deploy-bot"}},
+	}
+	projectPermissions := []ProjectPermission{
+		{ProjectKey: "CORE", User: User{UUID: "{u-1}", DisplayName: "Alice"}},
+		{ProjectKey: "CORE", User: User{UUID: "{u-3}", DisplayName: "Bob"}},
+	}
+
+	rows := buildBitbucketAccountRows(members, projectPermissions)
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 account rows, got %d", len(rows))
+	}
+	if rows[1].DisplayName != "deploy-bot" || rows[1].AccountKind != registry.AccountKindBot {
+		t.Fatalf("row[1] = %+v", rows[1])
+	}
+	if rows[2].ExternalID != "{u-3}" || rows[2].AccountKind != registry.AccountKindHuman {
+		t.Fatalf("row[2] = %+v", rows[2])
+	}
+}
+
+func TestListCredentialsHoldsExpiryWhenListingIsForbidden(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"message":"forbidden"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL, "acme", "", "access-token")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	integration := NewBitbucketIntegration(client, "acme")
+	warnings := registry.NewWarningReporter(nil)
+	holds := registry.NewExpiryHolds()
+
+	appPasswords, accessTokens, err := integration.listCredentials(context.Background(),
+		[]WorkspacePermission{{User: User{UUID: "{u-1}"}}},
+		[]Repository{{Slug: "api"}},
+		warnings, holds)
+	if err != nil {
+		t.Fatalf("listCredentials() error = %v, want permission errors downgraded to warnings", err)
+	}
+	if len(appPasswords) != 0 || len(accessTokens) != 0 {
+		t.Fatalf("credentials = %+v %+v, want none", appPasswords, accessTokens)
+	}
+	if len(warnings.Warnings()) != 2 {
+		t.Fatalf("warnings = %+v, want one per skipped listing", warnings.Warnings())
+	}
+	// Finalize must not expire the app passwords and access tokens the run could not list.
+	if holds.Empty() {
+		t.Fatalf("expiry holds are empty, want the skipped credential kinds held")
+	}
+}
@@ -0,0 +1,14 @@
+package bitbucket
+
+import "github.com/open-sspm/open-sspm/internal/connectors/registry"
+
+// bitbucketPrivilegeMapping maps workspace and project permissions to canonical levels.
+var bitbucketPrivilegeMapping = registry.PrivilegeMapping{
+	{Kind: "bitbucket_workspace_permission", Permission: "owner", Level: registry.PrivilegeOwner},
+	{Kind: "bitbucket_workspace_permission", Permission: "collaborator", Level: registry.PrivilegeWrite},
+	{Kind: "bitbucket_workspace_permission", Permission: "member", Level: registry.PrivilegeRead},
+	{Kind: "bitbucket_project_permission", Permission: "admin", Level: registry.PrivilegeAdmin},
+	{Kind: "bitbucket_project_permission", Permission: "create-repo", Level: registry.PrivilegeWrite},
+	{Kind: "bitbucket_project_permission", Permission: "write", Level: registry.PrivilegeWrite},
+	{Kind: "bitbucket_project_permission", Permission: "read", Level: registry.PrivilegeRead},
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/x509"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/url"
 	"strings"
 )
@@ -16,11 +17,16 @@ const (
 	KindEntra             = "entra"
 	KindVault             = "vault"
 	KindGoogleWorkspace   = "google_workspace"
+	KindBitbucket         = "bitbucket"
 )
 
+// MaxGitHubWorkers bounds a GitHub connector's concurrent team-data and deploy-key requests.
+const MaxGitHubWorkers = 32
+
 const (
 	defaultGitHubAPIBase = "https://api.github.com"
 	defaultDatadogSite   = "datadoghq.com"
+	defaultBitbucketBase = "https://api.bitbucket.org/2.0"
 )
 
 const (
@@ -74,6 +80,11 @@ type GitHubConfig struct {
 	Enterprise string `json:"enterprise"`
 	// SCIMEnabled toggles whether to use the SCIM API for identity/email resolution.
 	SCIMEnabled bool `json:"scim_enabled"`
+	// SecretScanningEnabled toggles collecting org secret scanning alerts.
+	SecretScanningEnabled bool `json:"secret_scanning_enabled"`
+	// Workers sets how many team-data and deploy-key requests run at once; 0 uses the
+	// deployment's SYNC_GITHUB_WORKERS.
+	Workers int `json:"workers,omitempty"`
 }
 
 func (c GitHubConfig) Normalized() GitHubConfig {
@@ -85,6 +96,9 @@ func (c GitHubConfig) Normalized() GitHubConfig {
 	if out.APIBase == "" {
 		out.APIBase = defaultGitHubAPIBase
 	}
+	if out.Workers < 0 {
+		out.Workers = 0
+	}
 	out.APIBase = strings.TrimRight(out.APIBase, "/")
 	return out
 }
@@ -100,6 +114,9 @@ func (c GitHubConfig) Validate() error {
 	if c.APIBase == "" {
 		return errors.New("GitHub API base is required")
 	}
+	if c.Workers > MaxGitHubWorkers {
+		return fmt.Errorf("GitHub workers must be at most %d", MaxGitHubWorkers)
+	}
 	return nil
 }
 
@@ -142,6 +159,41 @@ func (c DatadogConfig) Validate() error {
 	return nil
 }
 
+type BitbucketConfig struct {
+	Workspace string `json:"workspace"`
+	// Username selects basic auth with an app password; when empty Token is sent as a bearer access token.
+	Username string `json:"username"`
+	Token    string `json:"token"`
+	APIBase  string `json:"api_base"`
+}
+
+func (c BitbucketConfig) Normalized() BitbucketConfig {
+	out := c
+	out.Workspace = strings.TrimSpace(out.Workspace)
+	out.Username = strings.TrimSpace(out.Username)
+	out.Token = strings.TrimSpace(out.Token)
+	out.APIBase = strings.TrimSpace(out.APIBase)
+	if out.APIBase == "" {
+		out.APIBase = defaultBitbucketBase
+	}
+	out.APIBase = strings.TrimRight(out.APIBase, "/")
+	return out
+}
+
+func (c BitbucketConfig) Validate() error {
+	c = c.Normalized()
+	if c.Workspace == "" {
+		return errors.New("Bitbucket workspace is required")
+	}
+	if c.Token == "" {
+		return errors.New("Bitbucket token is required")
+	}
+	if c.APIBase == "" {
+		return errors.New("Bitbucket API base is required")
+	}
+	return nil
+}
+
 type AWSIdentityCenterConfig struct {
 	Region          string `json:"region"`
 	Name            string `json:"name"`
@@ -219,6 +271,7 @@ type GoogleWorkspaceConfig struct {
 	ServiceAccountJSON  string `json:"service_account_json"`
 	ServiceAccountEmail string `json:"service_account_email"`
 	DiscoveryEnabled    bool   `json:"discovery_enabled"`
+	DirectLoginEvents   bool   `json:"direct_login_events"`
 }
 
 func (c EntraConfig) Normalized() EntraConfig {
@@ -270,6 +323,10 @@ func (c GoogleWorkspaceConfig) Validate() error {
 		if c.ServiceAccountJSON == "" {
 			return errors.New("Google Workspace service account JSON is required")
 		}
+		if _, ok := SecretRef(c.ServiceAccountJSON); ok {
+			// Checked once the reference is resolved at sync time.
+			return nil
+		}
 		var payload struct {
 			ClientEmail string `json:"client_email"`
 			PrivateKey  string `json:"private_key"`
@@ -386,6 +443,11 @@ func DecodeDatadogConfig(raw []byte) (DatadogConfig, error) {
 	return cfg, decodeJSON(raw, &cfg)
 }
 
+func DecodeBitbucketConfig(raw []byte) (BitbucketConfig, error) {
+	var cfg BitbucketConfig
+	return cfg, decodeJSON(raw, &cfg)
+}
+
 func DecodeAWSIdentityCenterConfig(raw []byte) (AWSIdentityCenterConfig, error) {
 	var cfg AWSIdentityCenterConfig
 	return cfg, decodeJSON(raw, &cfg)
@@ -431,6 +493,8 @@ func MergeGitHubConfig(existing GitHubConfig, update GitHubConfig) GitHubConfig
 	merged.APIBase = strings.TrimSpace(update.APIBase)
 	merged.Enterprise = strings.TrimSpace(update.Enterprise)
 	merged.SCIMEnabled = update.SCIMEnabled
+	merged.SecretScanningEnabled = update.SecretScanningEnabled
+	merged.Workers = update.Workers
 	if token := strings.TrimSpace(update.Token); token != "" {
 		merged.Token = token
 	}
@@ -449,6 +513,17 @@ func MergeDatadogConfig(existing DatadogConfig, update DatadogConfig) DatadogCon
 	return merged
 }
 
+func MergeBitbucketConfig(existing BitbucketConfig, update BitbucketConfig) BitbucketConfig {
+	merged := existing
+	merged.Workspace = strings.TrimSpace(update.Workspace)
+	merged.Username = strings.TrimSpace(update.Username)
+	merged.APIBase = strings.TrimSpace(update.APIBase)
+	if token := strings.TrimSpace(update.Token); token != "" {
+		merged.Token = token
+	}
+	return merged
+}
+
 func MergeAWSIdentityCenterConfig(existing AWSIdentityCenterConfig, update AWSIdentityCenterConfig) AWSIdentityCenterConfig {
 	merged := existing
 	merged.Region = strings.TrimSpace(update.Region)
@@ -496,6 +571,7 @@ func MergeGoogleWorkspaceConfig(existing GoogleWorkspaceConfig, update GoogleWor
 	merged.PrimaryDomain = strings.TrimSpace(update.PrimaryDomain)
 	merged.DelegatedAdminEmail = strings.TrimSpace(update.DelegatedAdminEmail)
 	merged.DiscoveryEnabled = update.DiscoveryEnabled
+	merged.DirectLoginEvents = update.DirectLoginEvents
 	merged.AuthType = strings.ToLower(strings.TrimSpace(update.AuthType))
 	if merged.AuthType == "" {
 		merged.AuthType = GoogleWorkspaceAuthTypeServiceAccountJSON
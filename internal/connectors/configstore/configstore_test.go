@@ -309,3 +309,30 @@ func TestMergeGoogleWorkspaceConfig(t *testing.T) {
 		}
 	})
 }
+
+func TestGitHubConfigWorkers(t *testing.T) {
+	t.Parallel()
+
+	base := GitHubConfig{Org: "acme", Token: "token"}
+	for _, tc := range []struct {
+		workers int
+		want    int
+		wantErr bool
+	}{
+		{workers: 0, want: 0},
+		{workers: -4, want: 0},
+		{workers: MaxGitHubWorkers, want: MaxGitHubWorkers},
+		{workers: MaxGitHubWorkers + 1, wantErr: true},
+	} {
+		cfg := base
+		cfg.Workers = tc.workers
+		cfg = cfg.Normalized()
+		err := cfg.Validate()
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("Validate(workers=%d) error = %v, wantErr %v", tc.workers, err, tc.wantErr)
+		}
+		if !tc.wantErr && cfg.Workers != tc.want {
+			t.Fatalf("Normalized(workers=%d) workers = %d, want %d", tc.workers, cfg.Workers, tc.want)
+		}
+	}
+}
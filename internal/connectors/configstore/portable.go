@@ -0,0 +1,305 @@
+package configstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// PortableVersion is the format version written to and accepted from portable documents.
+const PortableVersion = 1
+
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionUnchanged = "unchanged"
+)
+
+// PortableDocument is connector configuration exported from one deployment for import into
+// another. Secrets never appear in it: every secret field holds a secret:// reference that the
+// target's SecretProvider resolves.
+type PortableDocument struct {
+	Version    int                 `json:"version"`
+	Connectors []PortableConnector `json:"connectors"`
+}
+
+// PortableConnector is one connector_configs row. SecretRefs lists the references its config
+// needs, so operators know what to provision in the target's secret backend.
+type PortableConnector struct {
+	Kind       string          `json:"kind"`
+	Enabled    bool            `json:"enabled"`
+	Config     json.RawMessage `json:"config"`
+	SecretRefs []string        `json:"secret_refs,omitempty"`
+}
+
+// ImportChange is the config an import writes for one connector.
+type ImportChange struct {
+	Kind    string
+	Enabled bool
+	Config  []byte
+	Action  string
+}
+
+// ImportConflict is a connector that is already configured in the target with different
+// values. Fields names the differing config keys, plus "enabled".
+type ImportConflict struct {
+	Kind   string
+	Fields []string
+}
+
+func (c ImportConflict) String() string {
+	return fmt.Sprintf("%s is configured differently in the target (%s)", c.Kind, strings.Join(c.Fields, ", "))
+}
+
+// ImportPlan is the outcome of checking a portable document against the target's configs.
+type ImportPlan struct {
+	Changes   []ImportChange
+	Conflicts []ImportConflict
+}
+
+// portableSecret is a secret field of a decoded config, addressed by its JSON key.
+type portableSecret struct {
+	field string
+	value *string
+}
+
+// ExportConnector converts a stored connector config to its portable form. Literal secrets are
+// replaced with a reference named after the connector and field (secret://GITHUB_TOKEN);
+// existing references are kept. Unconfigured, disabled connectors are skipped (ok is false).
+func ExportConnector(kind string, enabled bool, raw []byte) (PortableConnector, bool, error) {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	cfg, secrets, err := decodePortableConfig(kind, raw)
+	if err != nil {
+		return PortableConnector{}, false, fmt.Errorf("%s: %w", kind, err)
+	}
+	configured, err := portableConfigured(kind, cfg)
+	if err != nil {
+		return PortableConnector{}, false, fmt.Errorf("%s: %w", kind, err)
+	}
+	if !enabled && !configured {
+		return PortableConnector{}, false, nil
+	}
+
+	refs := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		if *secret.value == "" {
+			continue
+		}
+		ref, ok := SecretRef(*secret.value)
+		if !ok {
+			ref = strings.ToUpper(kind + "_" + secret.field)
+		}
+		*secret.value = SecretRefPrefix + ref
+		refs = append(refs, ref)
+	}
+	encoded, err := EncodeConfig(cfg)
+	if err != nil {
+		return PortableConnector{}, false, fmt.Errorf("%s: %w", kind, err)
+	}
+	return PortableConnector{Kind: kind, Enabled: enabled, Config: encoded, SecretRefs: refs}, true, nil
+}
+
+// PlanImport validates doc and compares it with the target's current connector rows (in their
+// stored form). It returns every validation problem at once; a connector whose target row is
+// configured with different values is a conflict, applied only if the caller chooses to
+// overwrite.
+func PlanImport(doc PortableDocument, current []PortableConnector) (ImportPlan, error) {
+	if doc.Version != PortableVersion {
+		return ImportPlan{}, fmt.Errorf("unsupported connector config document version %d (want %d)", doc.Version, PortableVersion)
+	}
+
+	existing := make(map[string]PortableConnector, len(current))
+	for _, row := range current {
+		existing[strings.ToLower(strings.TrimSpace(row.Kind))] = row
+	}
+
+	var plan ImportPlan
+	var errs []error
+	seen := make(map[string]struct{}, len(doc.Connectors))
+	for _, connector := range doc.Connectors {
+		kind := strings.ToLower(strings.TrimSpace(connector.Kind))
+		if _, ok := seen[kind]; ok {
+			errs = append(errs, fmt.Errorf("%s: listed more than once", kind))
+			continue
+		}
+		seen[kind] = struct{}{}
+
+		change, conflict, err := planConnectorImport(kind, connector, existing)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", kind, err))
+			continue
+		}
+		plan.Changes = append(plan.Changes, change)
+		if conflict != nil {
+			plan.Conflicts = append(plan.Conflicts, *conflict)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return ImportPlan{}, err
+	}
+	return plan, nil
+}
+
+func planConnectorImport(kind string, connector PortableConnector, existing map[string]PortableConnector) (ImportChange, *ImportConflict, error) {
+	target, ok := existing[kind]
+	if !ok {
+		return ImportChange{}, nil, errors.New("unknown connector kind in the target")
+	}
+	cfg, secrets, err := decodePortableConfig(kind, connector.Config)
+	if err != nil {
+		return ImportChange{}, nil, err
+	}
+	for _, secret := range secrets {
+		if _, isRef := SecretRef(*secret.value); *secret.value != "" && !isRef {
+			return ImportChange{}, nil, fmt.Errorf("%s holds a literal secret; reference it as %sNAME instead", secret.field, SecretRefPrefix)
+		}
+	}
+	if connector.Enabled {
+		if err := validatePortableConfig(cfg); err != nil {
+			return ImportChange{}, nil, err
+		}
+	}
+	encoded, err := EncodeConfig(cfg)
+	if err != nil {
+		return ImportChange{}, nil, err
+	}
+	change := ImportChange{Kind: kind, Enabled: connector.Enabled, Config: encoded}
+
+	targetCfg, _, err := decodePortableConfig(kind, target.Config)
+	if err != nil {
+		return ImportChange{}, nil, fmt.Errorf("target config: %w", err)
+	}
+	fields, err := portableConfigDiff(cfg, targetCfg)
+	if err != nil {
+		return ImportChange{}, nil, err
+	}
+	if target.Enabled != connector.Enabled {
+		fields = append(fields, "enabled")
+	}
+	configured, err := portableConfigured(kind, targetCfg)
+	if err != nil {
+		return ImportChange{}, nil, err
+	}
+	switch {
+	case len(fields) == 0:
+		change.Action = ImportActionUnchanged
+		return change, nil, nil
+	case !configured && !target.Enabled:
+		change.Action = ImportActionCreate
+		return change, nil, nil
+	default:
+		change.Action = ImportActionUpdate
+		return change, &ImportConflict{Kind: kind, Fields: fields}, nil
+	}
+}
+
+// decodePortableConfig decodes and normalizes raw as kind's config. It returns a pointer to the
+// config and its secret fields, so callers can rewrite secrets in place.
+func decodePortableConfig(kind string, raw []byte) (any, []portableSecret, error) {
+	switch kind {
+	case KindOkta:
+		cfg, err := DecodeOktaConfig(raw)
+		cfg = cfg.Normalized()
+		return &cfg, []portableSecret{{"token", &cfg.Token}}, err
+	case KindGitHub:
+		cfg, err := DecodeGitHubConfig(raw)
+		cfg = cfg.Normalized()
+		return &cfg, []portableSecret{{"token", &cfg.Token}}, err
+	case KindDatadog:
+		cfg, err := DecodeDatadogConfig(raw)
+		cfg = cfg.Normalized()
+		return &cfg, []portableSecret{{"api_key", &cfg.APIKey}, {"app_key", &cfg.AppKey}}, err
+	case KindBitbucket:
+		cfg, err := DecodeBitbucketConfig(raw)
+		cfg = cfg.Normalized()
+		return &cfg, []portableSecret{{"token", &cfg.Token}}, err
+	case KindAWSIdentityCenter:
+		cfg, err := DecodeAWSIdentityCenterConfig(raw)
+		cfg = cfg.Normalized()
+		return &cfg, []portableSecret{
+			{"access_key_id", &cfg.AccessKeyID},
+			{"secret_access_key", &cfg.SecretAccessKey},
+			{"session_token", &cfg.SessionToken},
+		}, err
+	case KindVault:
+		cfg, err := DecodeVaultConfig(raw)
+		cfg = cfg.Normalized()
+		return &cfg, []portableSecret{{"token", &cfg.Token}, {"approle_secret_id", &cfg.AppRoleSecretID}}, err
+	case KindEntra:
+		cfg, err := DecodeEntraConfig(raw)
+		cfg = cfg.Normalized()
+		return &cfg, []portableSecret{{"client_secret", &cfg.ClientSecret}}, err
+	case KindGoogleWorkspace:
+		cfg, err := DecodeGoogleWorkspaceConfig(raw)
+		cfg = cfg.Normalized()
+		return &cfg, []portableSecret{{"service_account_json", &cfg.ServiceAccountJSON}}, err
+	default:
+		return nil, nil, errors.New("unknown connector kind")
+	}
+}
+
+func validatePortableConfig(cfg any) error {
+	switch c := cfg.(type) {
+	case *OktaConfig:
+		return c.Validate()
+	case *GitHubConfig:
+		return c.Validate()
+	case *DatadogConfig:
+		return c.Validate()
+	case *BitbucketConfig:
+		return c.Validate()
+	case *AWSIdentityCenterConfig:
+		return c.Validate()
+	case *VaultConfig:
+		return c.Validate()
+	case *EntraConfig:
+		return c.Validate()
+	case *GoogleWorkspaceConfig:
+		return c.Validate()
+	default:
+		return errors.New("unknown connector kind")
+	}
+}
+
+// portableConfigured reports whether cfg differs from kind's empty config, i.e. someone has
+// set it up.
+func portableConfigured(kind string, cfg any) (bool, error) {
+	empty, _, err := decodePortableConfig(kind, nil)
+	if err != nil {
+		return false, err
+	}
+	fields, err := portableConfigDiff(cfg, empty)
+	return len(fields) > 0, err
+}
+
+// portableConfigDiff returns the sorted JSON keys whose values differ between two configs of
+// the same kind.
+func portableConfigDiff(a, b any) ([]string, error) {
+	left, err := portableConfigFields(a)
+	if err != nil {
+		return nil, err
+	}
+	right, err := portableConfigFields(b)
+	if err != nil {
+		return nil, err
+	}
+	var fields []string
+	for _, key := range slices.Sorted(maps.Keys(left)) {
+		if string(left[key]) != string(right[key]) {
+			fields = append(fields, key)
+		}
+	}
+	return fields, nil
+}
+
+func portableConfigFields(cfg any) (map[string]json.RawMessage, error) {
+	encoded, err := EncodeConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	return fields, json.Unmarshal(encoded, &fields)
+}
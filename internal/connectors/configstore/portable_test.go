@@ -0,0 +1,184 @@
+package configstore
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExportImportConnectorConfigRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	source := []PortableConnector{
+		{Kind: KindGitHub, Enabled: true, Config: json.RawMessage(`{"org":" acme ","token":"ghp_literalsecret","scim_enabled":true}`)},
+		{Kind: KindDatadog, Enabled: true, Config: json.RawMessage(`{"api_key":"dd-api-literal","app_key":"secret://dd/app#key","site":"datadoghq.eu"}`)},
+		{Kind: KindVault, Enabled: false, Config: json.RawMessage(`{"address":"https://vault.example.com","auth_type":"approle","approle_role_id":"role","approle_secret_id":"approle-literal"}`)},
+		{Kind: KindOkta, Enabled: false, Config: json.RawMessage(`{}`)},
+	}
+
+	doc := exportPortable(t, source)
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal document: %v", err)
+	}
+	for _, secret := range []string{"ghp_literalsecret", "dd-api-literal", "approle-literal"} {
+		if strings.Contains(string(encoded), secret) {
+			t.Fatalf("exported document contains literal secret %q: %s", secret, encoded)
+		}
+	}
+	kinds := make([]string, 0, len(doc.Connectors))
+	for _, connector := range doc.Connectors {
+		kinds = append(kinds, connector.Kind)
+	}
+	if !slices.Equal(kinds, []string{KindGitHub, KindDatadog, KindVault}) {
+		t.Fatalf("exported kinds = %v, want the configured connectors only", kinds)
+	}
+	if refs := doc.Connectors[1].SecretRefs; !slices.Equal(refs, []string{"DATADOG_API_KEY", "dd/app#key"}) {
+		t.Fatalf("datadog secret refs = %v, want a generated ref and the existing one", refs)
+	}
+
+	// Import into a fresh deployment: every seeded row is empty and disabled.
+	var decoded PortableDocument
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("unmarshal document: %v", err)
+	}
+	target := []PortableConnector{
+		{Kind: KindGitHub, Config: json.RawMessage(`{}`)},
+		{Kind: KindDatadog, Config: json.RawMessage(`{}`)},
+		{Kind: KindVault, Config: json.RawMessage(`{}`)},
+		{Kind: KindOkta, Config: json.RawMessage(`{}`)},
+	}
+	plan, err := PlanImport(decoded, target)
+	if err != nil {
+		t.Fatalf("PlanImport() error = %v", err)
+	}
+	if len(plan.Conflicts) != 0 {
+		t.Fatalf("PlanImport() conflicts = %v, want none on a fresh target", plan.Conflicts)
+	}
+	imported := make([]PortableConnector, 0, len(plan.Changes))
+	for _, change := range plan.Changes {
+		if change.Action != ImportActionCreate {
+			t.Fatalf("%s action = %q, want %q", change.Kind, change.Action, ImportActionCreate)
+		}
+		imported = append(imported, PortableConnector{Kind: change.Kind, Enabled: change.Enabled, Config: change.Config})
+	}
+
+	// Exporting the imported rows reproduces the document exactly.
+	reexported, err := json.Marshal(exportPortable(t, imported))
+	if err != nil {
+		t.Fatalf("marshal re-exported document: %v", err)
+	}
+	if string(reexported) != string(encoded) {
+		t.Fatalf("round trip changed the document:\n got %s\nwant %s", reexported, encoded)
+	}
+
+	// Importing the same document again changes nothing.
+	plan, err = PlanImport(decoded, imported)
+	if err != nil {
+		t.Fatalf("PlanImport(again) error = %v", err)
+	}
+	for _, change := range plan.Changes {
+		if change.Action != ImportActionUnchanged {
+			t.Fatalf("%s action = %q on re-import, want %q", change.Kind, change.Action, ImportActionUnchanged)
+		}
+	}
+}
+
+func TestPlanImportReportsConflicts(t *testing.T) {
+	t.Parallel()
+
+	doc := PortableDocument{
+		Version: PortableVersion,
+		Connectors: []PortableConnector{
+			{Kind: KindGitHub, Enabled: true, Config: json.RawMessage(`{"org":"acme","token":"secret://GITHUB_TOKEN"}`)},
+		},
+	}
+	target := []PortableConnector{
+		{Kind: KindGitHub, Enabled: true, Config: json.RawMessage(`{"org":"acme-staging","token":"ghp_target"}`)},
+	}
+	plan, err := PlanImport(doc, target)
+	if err != nil {
+		t.Fatalf("PlanImport() error = %v", err)
+	}
+	if len(plan.Conflicts) != 1 || !slices.Equal(plan.Conflicts[0].Fields, []string{"org", "token"}) {
+		t.Fatalf("PlanImport() conflicts = %+v, want github org and token", plan.Conflicts)
+	}
+	if plan.Changes[0].Action != ImportActionUpdate {
+		t.Fatalf("github action = %q, want %q", plan.Changes[0].Action, ImportActionUpdate)
+	}
+}
+
+func TestPlanImportRejectsInvalidDocuments(t *testing.T) {
+	t.Parallel()
+
+	target := []PortableConnector{
+		{Kind: KindGitHub, Config: json.RawMessage(`{}`)},
+		{Kind: KindEntra, Config: json.RawMessage(`{}`)},
+	}
+	tests := []struct {
+		name         string
+		doc          PortableDocument
+		wantContains string
+	}{
+		{
+			name:         "version",
+			doc:          PortableDocument{Version: 2},
+			wantContains: "unsupported connector config document version 2",
+		},
+		{
+			name: "literal secret",
+			doc: PortableDocument{Version: PortableVersion, Connectors: []PortableConnector{
+				{Kind: KindGitHub, Config: json.RawMessage(`{"org":"acme","token":"ghp_embedded"}`)},
+			}},
+			wantContains: "github: token holds a literal secret",
+		},
+		{
+			name: "enabled but invalid",
+			doc: PortableDocument{Version: PortableVersion, Connectors: []PortableConnector{
+				{Kind: KindEntra, Enabled: true, Config: json.RawMessage(`{"tenant_id":"tenant"}`)},
+			}},
+			wantContains: "entra: ",
+		},
+		{
+			name: "unknown kind",
+			doc: PortableDocument{Version: PortableVersion, Connectors: []PortableConnector{
+				{Kind: "jira", Config: json.RawMessage(`{}`)},
+			}},
+			wantContains: "jira: unknown connector kind",
+		},
+		{
+			name: "duplicate kind",
+			doc: PortableDocument{Version: PortableVersion, Connectors: []PortableConnector{
+				{Kind: KindGitHub, Config: json.RawMessage(`{}`)},
+				{Kind: KindGitHub, Config: json.RawMessage(`{}`)},
+			}},
+			wantContains: "github: listed more than once",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := PlanImport(tc.doc, target)
+			if err == nil || !strings.Contains(err.Error(), tc.wantContains) {
+				t.Fatalf("PlanImport() error = %v, want it to contain %q", err, tc.wantContains)
+			}
+		})
+	}
+}
+
+func exportPortable(t *testing.T, rows []PortableConnector) PortableDocument {
+	t.Helper()
+
+	doc := PortableDocument{Version: PortableVersion}
+	for _, row := range rows {
+		connector, ok, err := ExportConnector(row.Kind, row.Enabled, row.Config)
+		if err != nil {
+			t.Fatalf("ExportConnector(%s) error = %v", row.Kind, err)
+		}
+		if ok {
+			doc.Connectors = append(doc.Connectors, connector)
+		}
+	}
+	return doc
+}
@@ -0,0 +1,163 @@
+package configstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// SecretRefPrefix marks a connector config value as a reference to be resolved by the
+// configured SecretProvider instead of a literal secret, e.g. "secret://GITHUB_TOKEN".
+const SecretRefPrefix = "secret://"
+
+const (
+	SecretBackendEnv   = "env"
+	SecretBackendVault = "vault"
+
+	defaultVaultSecretMount = "secret"
+	defaultVaultSecretField = "value"
+)
+
+// SecretProvider resolves secret references found in connector configs.
+type SecretProvider interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// NewSecretProvider returns the provider for backend. The Vault backend reads VAULT_ADDR,
+// VAULT_TOKEN, and VAULT_NAMESPACE like the Vault CLI and looks secrets up in the KV v2 mount.
+func NewSecretProvider(backend, vaultMount string) (SecretProvider, error) {
+	switch strings.ToLower(strings.TrimSpace(backend)) {
+	case "", SecretBackendEnv:
+		return EnvSecretProvider{}, nil
+	case SecretBackendVault:
+		client, err := vaultapi.NewClient(vaultapi.DefaultConfig())
+		if err != nil {
+			return nil, fmt.Errorf("vault secret backend: %w", err)
+		}
+		return NewVaultSecretProvider(client, vaultMount), nil
+	default:
+		return nil, fmt.Errorf("unknown connector secret backend %q", backend)
+	}
+}
+
+// EnvSecretProvider resolves a reference as the name of an environment variable.
+type EnvSecretProvider struct{}
+
+func (EnvSecretProvider) Resolve(_ context.Context, ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", ref)
+	}
+	return value, nil
+}
+
+// VaultSecretProvider resolves "path#field" references against a KV v2 mount. The field
+// defaults to "value" when omitted.
+type VaultSecretProvider struct {
+	client *vaultapi.Client
+	mount  string
+}
+
+func NewVaultSecretProvider(client *vaultapi.Client, mount string) *VaultSecretProvider {
+	mount = normalizeVaultMountPath(mount)
+	if mount == "" {
+		mount = defaultVaultSecretMount
+	}
+	return &VaultSecretProvider{client: client, mount: mount}
+}
+
+func (p *VaultSecretProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	path, field, _ := strings.Cut(ref, "#")
+	path = strings.Trim(strings.TrimSpace(path), "/")
+	field = strings.TrimSpace(field)
+	if path == "" {
+		return "", errors.New("vault secret reference has no path")
+	}
+	if field == "" {
+		field = defaultVaultSecretField
+	}
+	secret, err := p.client.KVv2(p.mount).Get(ctx, path)
+	if err != nil {
+		return "", fmt.Errorf("read vault secret %s/%s: %w", p.mount, path, err)
+	}
+	value, ok := secret.Data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret %s/%s has no string field %q", p.mount, path, field)
+	}
+	return value, nil
+}
+
+// SecretRef returns the reference named by value and whether value is a reference.
+func SecretRef(value string) (string, bool) {
+	ref, ok := strings.CutPrefix(strings.TrimSpace(value), SecretRefPrefix)
+	ref = strings.TrimSpace(ref)
+	return ref, ok && ref != ""
+}
+
+// ResolveSecrets returns a copy of cfg with every secret reference replaced by the value
+// from provider. Literal secrets are left as they are, so existing configs keep working.
+// A nil provider resolves from the environment.
+func ResolveSecrets(ctx context.Context, provider SecretProvider, cfg any) (any, error) {
+	if provider == nil {
+		provider = EnvSecretProvider{}
+	}
+	r := secretResolver{ctx: ctx, provider: provider}
+	switch c := cfg.(type) {
+	case OktaConfig:
+		r.resolve("token", &c.Token)
+		return c, r.err
+	case GitHubConfig:
+		r.resolve("token", &c.Token)
+		return c, r.err
+	case DatadogConfig:
+		r.resolve("api_key", &c.APIKey)
+		r.resolve("app_key", &c.AppKey)
+		return c, r.err
+	case BitbucketConfig:
+		r.resolve("token", &c.Token)
+		return c, r.err
+	case AWSIdentityCenterConfig:
+		r.resolve("access_key_id", &c.AccessKeyID)
+		r.resolve("secret_access_key", &c.SecretAccessKey)
+		r.resolve("session_token", &c.SessionToken)
+		return c, r.err
+	case VaultConfig:
+		r.resolve("token", &c.Token)
+		r.resolve("approle_secret_id", &c.AppRoleSecretID)
+		return c, r.err
+	case EntraConfig:
+		r.resolve("client_secret", &c.ClientSecret)
+		return c, r.err
+	case GoogleWorkspaceConfig:
+		r.resolve("service_account_json", &c.ServiceAccountJSON)
+		return c, r.err
+	default:
+		return cfg, nil
+	}
+}
+
+type secretResolver struct {
+	ctx      context.Context
+	provider SecretProvider
+	err      error
+}
+
+func (r *secretResolver) resolve(field string, value *string) {
+	if r.err != nil {
+		return
+	}
+	ref, ok := SecretRef(*value)
+	if !ok {
+		return
+	}
+	resolved, err := r.provider.Resolve(r.ctx, ref)
+	if err != nil {
+		r.err = fmt.Errorf("resolve %s: %w", field, err)
+		return
+	}
+	*value = resolved
+}
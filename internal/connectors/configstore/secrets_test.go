@@ -0,0 +1,110 @@
+package configstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+type fakeSecretProvider map[string]string
+
+func (p fakeSecretProvider) Resolve(_ context.Context, ref string) (string, error) {
+	value, ok := p[ref]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return value, nil
+}
+
+func TestResolveSecrets(t *testing.T) {
+	t.Parallel()
+
+	provider := fakeSecretProvider{
+		"gh-token": "ghp_resolved",
+		"dd-app":   "app-resolved",
+		"google":   `{"client_email":"sa@example.iam.gserviceaccount.com","private_key":"key"}`,
+	}
+
+	got, err := ResolveSecrets(context.Background(), provider, GitHubConfig{Org: "acme", Token: "secret://gh-token"})
+	if err != nil {
+		t.Fatalf("ResolveSecrets(github) error = %v", err)
+	}
+	if token := got.(GitHubConfig).Token; token != "ghp_resolved" {
+		t.Fatalf("github token = %q", token)
+	}
+
+	got, err = ResolveSecrets(context.Background(), provider, DatadogConfig{APIKey: "literal-key", AppKey: " secret://dd-app "})
+	if err != nil {
+		t.Fatalf("ResolveSecrets(datadog) error = %v", err)
+	}
+	if dd := got.(DatadogConfig); dd.APIKey != "literal-key" || dd.AppKey != "app-resolved" {
+		t.Fatalf("datadog config = %+v, want literal api key kept and app key resolved", dd)
+	}
+
+	google := GoogleWorkspaceConfig{CustomerID: "C01", DelegatedAdminEmail: "admin@example.com", ServiceAccountJSON: "secret://google"}
+	if err := google.Validate(); err != nil {
+		t.Fatalf("Validate() with a secret reference error = %v", err)
+	}
+	got, err = ResolveSecrets(context.Background(), provider, google)
+	if err != nil {
+		t.Fatalf("ResolveSecrets(google) error = %v", err)
+	}
+	if err := got.(GoogleWorkspaceConfig).Validate(); err != nil {
+		t.Fatalf("resolved google config Validate() error = %v", err)
+	}
+
+	if _, err := ResolveSecrets(context.Background(), provider, EntraConfig{ClientSecret: "secret://missing"}); err == nil {
+		t.Fatalf("ResolveSecrets() with an unknown reference returned nil error")
+	}
+}
+
+func TestEnvSecretProvider(t *testing.T) {
+	t.Setenv("OPEN_SSPM_TEST_OKTA_TOKEN", "00a-token")
+
+	got, err := ResolveSecrets(context.Background(), nil, OktaConfig{Domain: "acme.okta.com", Token: "secret://OPEN_SSPM_TEST_OKTA_TOKEN"})
+	if err != nil {
+		t.Fatalf("ResolveSecrets() error = %v", err)
+	}
+	if token := got.(OktaConfig).Token; token != "00a-token" {
+		t.Fatalf("okta token = %q", token)
+	}
+	if _, err := (EnvSecretProvider{}).Resolve(context.Background(), "OPEN_SSPM_TEST_UNSET"); err == nil {
+		t.Fatalf("Resolve() of an unset variable returned nil error")
+	}
+}
+
+func TestVaultSecretProvider(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/kv/data/connectors/github" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"data":{"token":"ghp_from_vault","value":"default-field"},"metadata":{"version":1}}}`))
+	}))
+	defer server.Close()
+
+	cfg := vaultapi.DefaultConfig()
+	cfg.Address = server.URL
+	client, err := vaultapi.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	provider := NewVaultSecretProvider(client, "/kv/")
+
+	if got, err := provider.Resolve(context.Background(), "connectors/github#token"); err != nil || got != "ghp_from_vault" {
+		t.Fatalf("Resolve(#token) = %q, %v", got, err)
+	}
+	if got, err := provider.Resolve(context.Background(), "connectors/github"); err != nil || got != "default-field" {
+		t.Fatalf("Resolve(default field) = %q, %v", got, err)
+	}
+	if _, err := provider.Resolve(context.Background(), "connectors/github#missing"); err == nil {
+		t.Fatalf("Resolve() of a missing field returned nil error")
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/httpclient"
 )
 
 const (
@@ -72,7 +74,7 @@ func New(baseURL, apiKey, appKey string) (*Client, error) {
 		BaseURL: base,
 		APIKey:  apiKey,
 		AppKey:  appKey,
-		HTTP:    &http.Client{Timeout: defaultTimeout},
+		HTTP:    httpclient.NewForKind("datadog", defaultTimeout),
 	}, nil
 }
 
@@ -283,7 +285,7 @@ func (c *Client) get(ctx context.Context, endpoint string) ([]byte, error) {
 			if !ok {
 				wait = time.Second
 			}
-			if err := sleep(ctx, wait); err != nil {
+			if err := sleep(ctx, min(wait, httpclient.MaxBackoff("datadog"))); err != nil {
 				return nil, err
 			}
 			continue
@@ -54,7 +54,7 @@ func (i *DatadogIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxp
 	started := time.Now()
 	slog.Info("syncing Datadog")
 
-	runID, err := q.CreateSyncRun(ctx, gen.CreateSyncRunParams{
+	runID, err := registry.CreateSyncRun(ctx, q, gen.CreateSyncRunParams{
 		SourceKind: "datadog",
 		SourceName: i.site,
 	})
@@ -209,7 +209,7 @@ func (i *DatadogIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxp
 		emails = append(emails, matching.NormalizeEmail(userName))
 		displayNames = append(displayNames, userName)
 		accountKinds = append(accountKinds, datadogUserAccountKind(user))
-		rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeJSON(user.RawJSON), registry.EntityCategoryUser))
+		rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeRawJSON(user.RawJSON), registry.EntityCategoryUser))
 		lastLoginAts = append(lastLoginAts, registry.PgTimestamptzPtr(user.LastLoginAt))
 		lastLoginIps = append(lastLoginIps, "")
 		lastLoginRegions = append(lastLoginRegions, "")
@@ -228,7 +228,7 @@ func (i *DatadogIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxp
 		emails = append(emails, matching.NormalizeEmail(serviceAccount.Email))
 		displayNames = append(displayNames, display)
 		accountKinds = append(accountKinds, registry.AccountKindService)
-		rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeJSON(serviceAccount.RawJSON), registry.EntityCategoryServiceAccount))
+		rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeRawJSON(serviceAccount.RawJSON), registry.EntityCategoryServiceAccount))
 		lastLoginAts = append(lastLoginAts, pgtype.Timestamptz{})
 		lastLoginIps = append(lastLoginIps, "")
 		lastLoginRegions = append(lastLoginRegions, "")
@@ -247,38 +247,43 @@ func (i *DatadogIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxp
 		emails = append(emails, "")
 		displayNames = append(displayNames, display)
 		accountKinds = append(accountKinds, registry.AccountKindService)
-		rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeJSON(role.RawJSON), registry.EntityCategoryRole))
+		rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeRawJSON(role.RawJSON), registry.EntityCategoryRole))
 		lastLoginAts = append(lastLoginAts, pgtype.Timestamptz{})
 		lastLoginIps = append(lastLoginIps, "")
 		lastLoginRegions = append(lastLoginRegions, "")
 	}
 
-	for start := 0; start < len(externalIDs); start += userBatchSize {
-		end := min(start+userBatchSize, len(externalIDs))
-		_, err := q.UpsertAppUsersBulkBySource(ctx, gen.UpsertAppUsersBulkBySourceParams{
-			SourceKind:       "datadog",
-			SourceName:       i.site,
-			SeenInRunID:      runID,
-			ExternalIds:      externalIDs[start:end],
-			Emails:           emails[start:end],
-			DisplayNames:     displayNames[start:end],
-			AccountKinds:     accountKinds[start:end],
-			RawJsons:         rawJSONs[start:end],
-			LastLoginAts:     lastLoginAts[start:end],
-			LastLoginIps:     lastLoginIps[start:end],
-			LastLoginRegions: lastLoginRegions[start:end],
-		})
-		if err != nil {
-			report(registry.Event{Source: "datadog", Stage: "write-users", Message: err.Error(), Err: err})
-			return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		for start := 0; start < len(externalIDs); start += userBatchSize {
+			end := min(start+userBatchSize, len(externalIDs))
+			_, err := qtx.UpsertAppUsersBulkBySource(ctx, gen.UpsertAppUsersBulkBySourceParams{
+				SourceKind:       "datadog",
+				SourceName:       i.site,
+				SeenInRunID:      runID,
+				ExternalIds:      externalIDs[start:end],
+				Emails:           emails[start:end],
+				DisplayNames:     displayNames[start:end],
+				AccountKinds:     accountKinds[start:end],
+				RawJsons:         rawJSONs[start:end],
+				LastLoginAts:     lastLoginAts[start:end],
+				LastLoginIps:     lastLoginIps[start:end],
+				LastLoginRegions: lastLoginRegions[start:end],
+			})
+			if err != nil {
+				return err
+			}
+			report(registry.Event{
+				Source:  "datadog",
+				Stage:   "write-users",
+				Current: int64(end),
+				Total:   int64(len(externalIDs)),
+				Message: fmt.Sprintf("principals %d/%d", end, len(externalIDs)),
+			})
 		}
-		report(registry.Event{
-			Source:  "datadog",
-			Stage:   "write-users",
-			Current: int64(end),
-			Total:   int64(len(externalIDs)),
-			Message: fmt.Sprintf("principals %d/%d", end, len(externalIDs)),
-		})
+		return nil
+	}); err != nil {
+		report(registry.Event{Source: "datadog", Stage: "write-users", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
 	const entitlementBatchSize = 5000
@@ -286,6 +291,7 @@ func (i *DatadogIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxp
 	entKinds := make([]string, 0, len(users))
 	entResources := make([]string, 0, len(users))
 	entPermissions := make([]string, 0, len(users))
+	entRoleNames := make([]string, 0, len(users))
 	entRawJSONs := make([][]byte, 0, len(users))
 
 	for _, user := range users {
@@ -307,6 +313,7 @@ func (i *DatadogIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxp
 			entKinds = append(entKinds, "datadog_role")
 			entResources = append(entResources, "datadog_role:"+externalID)
 			entPermissions = append(entPermissions, "member")
+			entRoleNames = append(entRoleNames, roleName)
 			entRawJSONs = append(entRawJSONs, registry.MarshalJSON(map[string]string{
 				"role_id":   roleID,
 				"role_name": roleName,
@@ -314,25 +321,33 @@ func (i *DatadogIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxp
 		}
 	}
 
-	for start := 0; start < len(entAppUserExternalIDs); start += entitlementBatchSize {
-		end := min(start+entitlementBatchSize, len(entAppUserExternalIDs))
-		_, err := q.UpsertEntitlementsBulkBySource(ctx, gen.UpsertEntitlementsBulkBySourceParams{
-			SeenInRunID:        runID,
-			SourceKind:         "datadog",
-			SourceName:         i.site,
-			AppUserExternalIds: entAppUserExternalIDs[start:end],
-			Kinds:              entKinds[start:end],
-			Resources:          entResources[start:end],
-			Permissions:        entPermissions[start:end],
-			RawJsons:           entRawJSONs[start:end],
-		})
-		if err != nil {
-			report(registry.Event{Source: "datadog", Stage: "write-users", Message: err.Error(), Err: err})
-			return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
+	entPrivilegeLevels := datadogPrivilegeMapping.Levels(entKinds, entPermissions, entRoleNames)
+
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		for start := 0; start < len(entAppUserExternalIDs); start += entitlementBatchSize {
+			end := min(start+entitlementBatchSize, len(entAppUserExternalIDs))
+			_, err := qtx.UpsertEntitlementsBulkBySource(ctx, gen.UpsertEntitlementsBulkBySourceParams{
+				SeenInRunID:        runID,
+				SourceKind:         "datadog",
+				SourceName:         i.site,
+				AppUserExternalIds: entAppUserExternalIDs[start:end],
+				Kinds:              entKinds[start:end],
+				Resources:          entResources[start:end],
+				Permissions:        entPermissions[start:end],
+				RawJsons:           entRawJSONs[start:end],
+				PrivilegeLevels:    entPrivilegeLevels[start:end],
+			})
+			if err != nil {
+				return err
+			}
 		}
+		return nil
+	}); err != nil {
+		report(registry.Event{Source: "datadog", Stage: "write-users", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
-	if err := registry.FinalizeAppRun(ctx, q, pool, runID, "datadog", i.site, time.Since(started), false); err != nil {
+	if err := registry.FinalizeAppRun(ctx, q, pool, runID, "datadog", i.site, time.Since(started), false, nil); err != nil {
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 	slog.Info("datadog sync complete", "users", len(users))
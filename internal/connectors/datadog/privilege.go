@@ -0,0 +1,12 @@
+package datadog
+
+import "github.com/open-sspm/open-sspm/internal/connectors/registry"
+
+// datadogPrivilegeMapping maps Datadog role names to canonical levels. The managed roles are
+// matched by name; custom roles default to write.
+var datadogPrivilegeMapping = registry.PrivilegeMapping{
+	{Kind: "datadog_role", Role: "*admin*", Level: registry.PrivilegeAdmin},
+	{Kind: "datadog_role", Role: "*read only*", Level: registry.PrivilegeRead},
+	{Kind: "datadog_role", Role: "*standard*", Level: registry.PrivilegeWrite},
+	{Kind: "datadog_role", Level: registry.PrivilegeWrite},
+}
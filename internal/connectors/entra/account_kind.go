@@ -16,6 +16,12 @@ func entraUserAccountKind(user User) string {
 	}
 }
 
+// entraUserIsGuest reports whether user is a B2B guest (userType Guest): an external account
+// invited into the tenant rather than one of its members.
+func entraUserIsGuest(user User) bool {
+	return strings.EqualFold(strings.TrimSpace(user.UserType), "Guest")
+}
+
 func entraServicePrincipalAccountKind(ServicePrincipal) string {
 	return registry.AccountKindService
 }
@@ -20,6 +20,24 @@ func TestEntraUserAccountKind(t *testing.T) {
 	}
 }
 
+func TestEntraUserIsGuest(t *testing.T) {
+	t.Parallel()
+
+	guest := User{DisplayName: "Bob (Partner)", UserPrincipalName: "bob_partner.com#EXT#@tenant.onmicrosoft.com", UserType: "Guest"}
+	member := User{DisplayName: "Alice", UserPrincipalName: "alice@example.com", UserType: "Member"}
+
+	if !entraUserIsGuest(guest) {
+		t.Fatalf("entraUserIsGuest(guest)=false want true")
+	}
+	if entraUserIsGuest(member) || entraUserIsGuest(User{DisplayName: "No Type"}) {
+		t.Fatalf("entraUserIsGuest(member)=true want false")
+	}
+	// Guests are still people: the flag sits beside the account kind rather than replacing it.
+	if got := entraUserAccountKind(guest); got != registry.AccountKindHuman {
+		t.Fatalf("entraUserAccountKind(guest)=%q want %q", got, registry.AccountKindHuman)
+	}
+}
+
 func TestEntraExternalIDs(t *testing.T) {
 	t.Parallel()
 
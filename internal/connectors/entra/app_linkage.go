@@ -0,0 +1,71 @@
+package entra
+
+import "strings"
+
+// Orphan flags stored in an app asset's raw JSON under "orphan".
+const (
+	// OrphanApplicationWithoutServicePrincipal is an app registration with no service principal in
+	// the tenant, so nothing can sign in as it; usually a stale registration.
+	OrphanApplicationWithoutServicePrincipal = "application_without_service_principal"
+	// OrphanServicePrincipalWithoutApplication is a service principal for an application this
+	// tenant owns whose registration no longer exists.
+	OrphanServicePrincipalWithoutApplication = "service_principal_without_application"
+)
+
+// entraAppLinkage pairs applications and service principals by their shared AppID.
+type entraAppLinkage struct {
+	servicePrincipalIDByAppID map[string]string
+	applicationIDByAppID      map[string]string
+}
+
+func linkEntraApplications(applications []Application, servicePrincipals []ServicePrincipal) entraAppLinkage {
+	linkage := entraAppLinkage{
+		servicePrincipalIDByAppID: make(map[string]string, len(servicePrincipals)),
+		applicationIDByAppID:      make(map[string]string, len(applications)),
+	}
+	for _, app := range applications {
+		appID := strings.ToLower(strings.TrimSpace(app.AppID))
+		if appID != "" && strings.TrimSpace(app.ID) != "" {
+			linkage.applicationIDByAppID[appID] = strings.TrimSpace(app.ID)
+		}
+	}
+	for _, sp := range servicePrincipals {
+		appID := strings.ToLower(strings.TrimSpace(sp.AppID))
+		if appID != "" && strings.TrimSpace(sp.ID) != "" {
+			linkage.servicePrincipalIDByAppID[appID] = strings.TrimSpace(sp.ID)
+		}
+	}
+	return linkage
+}
+
+// application returns the service principal linked to app and its orphan flag, if any.
+func (l entraAppLinkage) application(app Application) (servicePrincipalID, orphan string) {
+	appID := strings.ToLower(strings.TrimSpace(app.AppID))
+	if appID == "" {
+		return "", ""
+	}
+	servicePrincipalID = l.servicePrincipalIDByAppID[appID]
+	if servicePrincipalID == "" {
+		orphan = OrphanApplicationWithoutServicePrincipal
+	}
+	return servicePrincipalID, orphan
+}
+
+// servicePrincipal returns the application linked to sp and its orphan flag, if any. Only
+// application service principals owned by tenantID are expected to have a registration here;
+// managed identities and multi-tenant apps from other publishers never do.
+func (l entraAppLinkage) servicePrincipal(sp ServicePrincipal, tenantID string) (applicationID, orphan string) {
+	appID := strings.ToLower(strings.TrimSpace(sp.AppID))
+	if appID == "" {
+		return "", ""
+	}
+	applicationID = l.applicationIDByAppID[appID]
+	if applicationID != "" {
+		return applicationID, ""
+	}
+	owner := strings.TrimSpace(sp.AppOwnerOrganizationID)
+	if strings.EqualFold(strings.TrimSpace(sp.ServicePrincipalType), "Application") && owner != "" && strings.EqualFold(owner, strings.TrimSpace(tenantID)) {
+		orphan = OrphanServicePrincipalWithoutApplication
+	}
+	return "", orphan
+}
@@ -8,7 +8,13 @@ import (
 	"github.com/open-sspm/open-sspm/internal/db/gen"
 )
 
-type Definition struct{}
+type Definition struct {
+	workers int
+}
+
+func NewDefinition(workers int) *Definition {
+	return &Definition{workers: workers}
+}
 
 func (d *Definition) Kind() string {
 	return configstore.KindEntra
@@ -69,7 +75,25 @@ func (d *Definition) NewIntegration(cfg any) (registry.Integration, error) {
 	if err != nil {
 		return nil, err
 	}
-	return NewEntraIntegration(client, c.TenantID, c.DiscoveryEnabled), nil
+	return NewEntraIntegration(client, c.TenantID, d.workers, c.DiscoveryEnabled), nil
+}
+
+// CredentialKinds documents the credential kinds the integration syncs.
+func (d *Definition) CredentialKinds() []registry.CredentialKindInfo {
+	return []registry.CredentialKindInfo{
+		{
+			Kind:             "entra_client_secret",
+			Label:            "Entra client secret",
+			Description:      "A password credential on an Entra ID app registration that lets the application authenticate as itself.",
+			RotationGuidance: "Add a new secret under Certificates & secrets, update the service that uses it, then delete the old one. Prefer certificates or managed identities and keep lifetimes short.",
+		},
+		{
+			Kind:             "entra_certificate",
+			Label:            "Entra certificate",
+			Description:      "A certificate (key credential) on an Entra ID app registration, used for certificate-based app authentication.",
+			RotationGuidance: "Upload a new certificate before the current one expires, switch the service to the new private key, then remove the old certificate.",
+		},
+	}
 }
 
 type entraMetrics struct{}
@@ -2,11 +2,9 @@ package entra
 
 import (
 	"strings"
-)
 
-func normalizeEmail(email string) string {
-	return strings.ToLower(strings.TrimSpace(email))
-}
+	"github.com/open-sspm/open-sspm/internal/matching"
+)
 
 func looksLikeEmail(s string) bool {
 	s = strings.TrimSpace(s)
@@ -23,7 +21,7 @@ func preferredEmail(u User) string {
 	if v := strings.TrimSpace(u.Mail); looksLikeEmail(v) {
 		return v
 	}
-	if strings.EqualFold(strings.TrimSpace(u.UserType), "Guest") {
+	if entraUserIsGuest(u) {
 		for _, v := range u.OtherMails {
 			if v = strings.TrimSpace(v); looksLikeEmail(v) {
 				return v
@@ -52,3 +50,43 @@ func preferredEmail(u User) string {
 	}
 	return ""
 }
+
+// secondaryEmails returns the user's other addresses besides primary (already normalized):
+// mail, UPN, other mails, and SMTP proxy addresses. Guest UPNs are skipped since they are
+// tenant-local rewrites of the guest's real address.
+func secondaryEmails(u User, primary string) []string {
+	candidates := make([]string, 0, 2+len(u.OtherMails)+len(u.ProxyAddresses))
+	candidates = append(candidates, u.Mail)
+	if upn := strings.TrimSpace(u.UserPrincipalName); !strings.Contains(strings.ToUpper(upn), "#EXT#") {
+		candidates = append(candidates, upn)
+	}
+	candidates = append(candidates, u.OtherMails...)
+	for _, v := range u.ProxyAddresses {
+		v = strings.TrimSpace(v)
+		if prefix, address, ok := strings.Cut(v, ":"); ok {
+			if !strings.EqualFold(prefix, "smtp") {
+				continue
+			}
+			v = address
+		}
+		candidates = append(candidates, v)
+	}
+
+	seen := map[string]struct{}{primary: {}}
+	out := make([]string, 0, len(candidates))
+	for _, v := range candidates {
+		if !looksLikeEmail(v) {
+			continue
+		}
+		email := matching.NormalizeEmail(v)
+		if email == "" {
+			continue
+		}
+		if _, ok := seen[email]; ok {
+			continue
+		}
+		seen[email] = struct{}{}
+		out = append(out, email)
+	}
+	return out
+}
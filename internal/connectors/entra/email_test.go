@@ -1,6 +1,9 @@
 package entra
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 func TestPreferredEmail(t *testing.T) {
 	tests := []struct {
@@ -49,3 +52,22 @@ func TestPreferredEmail(t *testing.T) {
 		})
 	}
 }
+
+func TestSecondaryEmails(t *testing.T) {
+	user := User{
+		Mail:              "Alice@corp.example.com",
+		UserPrincipalName: "alice@tenant.onmicrosoft.com",
+		OtherMails:        []string{"alice.dev@example.net", "ALICE@corp.example.com", "not an email"},
+		ProxyAddresses:    []string{"SMTP:alice@corp.example.com", "smtp:a.smith@corp.example.com", "SIP:alice@corp.example.com", "x500:/o=corp"},
+	}
+	got := secondaryEmails(user, "alice@corp.example.com")
+	want := []string{"alice@tenant.onmicrosoft.com", "alice.dev@example.net", "a.smith@corp.example.com"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("secondaryEmails() = %v, want %v", got, want)
+	}
+
+	guest := User{UserType: "Guest", UserPrincipalName: "bob_example.com#EXT#@tenant.onmicrosoft.com", OtherMails: []string{"bob@example.com"}}
+	if got := secondaryEmails(guest, "bob@example.com"); len(got) != 0 {
+		t.Fatalf("secondaryEmails(guest) = %v, want the #EXT# UPN skipped", got)
+	}
+}
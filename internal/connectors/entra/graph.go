@@ -2,6 +2,7 @@ package entra
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,6 +13,9 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/httpclient"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
 )
 
 const (
@@ -93,16 +97,17 @@ type Application struct {
 }
 
 type ServicePrincipal struct {
-	ID                   string               `json:"id"`
-	AppID                string               `json:"appId"`
-	DisplayName          string               `json:"displayName"`
-	PublisherName        string               `json:"publisherName"`
-	AccountEnabled       *bool                `json:"accountEnabled"`
-	ServicePrincipalType string               `json:"servicePrincipalType"`
-	CreatedDateTimeRaw   string               `json:"createdDateTime"`
-	PasswordCredentials  []PasswordCredential `json:"passwordCredentials"`
-	KeyCredentials       []KeyCredential      `json:"keyCredentials"`
-	RawJSON              []byte               `json:"-"`
+	ID                     string               `json:"id"`
+	AppID                  string               `json:"appId"`
+	DisplayName            string               `json:"displayName"`
+	PublisherName          string               `json:"publisherName"`
+	AccountEnabled         *bool                `json:"accountEnabled"`
+	ServicePrincipalType   string               `json:"servicePrincipalType"`
+	AppOwnerOrganizationID string               `json:"appOwnerOrganizationId"`
+	CreatedDateTimeRaw     string               `json:"createdDateTime"`
+	PasswordCredentials    []PasswordCredential `json:"passwordCredentials"`
+	KeyCredentials         []KeyCredential      `json:"keyCredentials"`
+	RawJSON                []byte               `json:"-"`
 }
 
 type Group struct {
@@ -220,7 +225,7 @@ func NewWithOptions(tenantID, clientID, clientSecret string, opts Options) (*Cli
 
 	httpClient := opts.HTTPClient
 	if httpClient == nil {
-		httpClient = &http.Client{Timeout: defaultTimeout}
+		httpClient = httpclient.NewForKind("entra", defaultTimeout)
 	}
 
 	return &Client{
@@ -233,6 +238,35 @@ func NewWithOptions(tenantID, clientID, clientSecret string, opts Options) (*Cli
 	}, nil
 }
 
+type RoleDefinition struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	TemplateID  string `json:"templateId"`
+}
+
+type RolePrincipal struct {
+	ID        string `json:"id"`
+	ODataType string `json:"@odata.type"`
+}
+
+// DirectoryRoleAssignment is a directory role held by a principal. It is decoded from
+// roleAssignments as well as the PIM roleAssignmentScheduleInstances and
+// roleEligibilityScheduleInstances collections; the schedule fields are empty for plain
+// role assignments.
+type DirectoryRoleAssignment struct {
+	ID               string          `json:"id"`
+	PrincipalID      string          `json:"principalId"`
+	RoleDefinitionID string          `json:"roleDefinitionId"`
+	DirectoryScopeID string          `json:"directoryScopeId"`
+	AssignmentType   string          `json:"assignmentType"`
+	MemberType       string          `json:"memberType"`
+	StartDateTimeRaw string          `json:"startDateTime"`
+	EndDateTimeRaw   string          `json:"endDateTime"`
+	RoleDefinition   *RoleDefinition `json:"roleDefinition"`
+	Principal        *RolePrincipal  `json:"principal"`
+	RawJSON          []byte          `json:"-"`
+}
+
 func (c *Client) ListUsers(ctx context.Context) ([]User, error) {
 	endpoint, err := c.graphURL("/users", url.Values{
 		"$select": []string{"id,displayName,mail,userPrincipalName,otherMails,proxyAddresses,userType,accountEnabled,createdDateTime"},
@@ -287,7 +321,7 @@ func (c *Client) ListApplications(ctx context.Context) ([]Application, error) {
 
 func (c *Client) ListServicePrincipals(ctx context.Context) ([]ServicePrincipal, error) {
 	endpoint, err := c.graphURL("/servicePrincipals", url.Values{
-		"$select": []string{"id,appId,displayName,publisherName,accountEnabled,servicePrincipalType,createdDateTime,passwordCredentials,keyCredentials"},
+		"$select": []string{"id,appId,displayName,publisherName,accountEnabled,servicePrincipalType,appOwnerOrganizationId,createdDateTime,passwordCredentials,keyCredentials"},
 		"$top":    []string{"999"},
 	})
 	if err != nil {
@@ -337,7 +371,7 @@ func (c *Client) ListGroups(ctx context.Context) ([]Group, error) {
 	return out, nil
 }
 
-func (c *Client) ListApplicationOwners(ctx context.Context, applicationID string) ([]DirectoryOwner, error) {
+func (c *Client) ListApplicationOwners(ctx context.Context, applicationID string, opts ...registry.ListOption) ([]DirectoryOwner, error) {
 	applicationID = strings.TrimSpace(applicationID)
 	if applicationID == "" {
 		return nil, errors.New("application id is required")
@@ -349,10 +383,10 @@ func (c *Client) ListApplicationOwners(ctx context.Context, applicationID string
 	if err != nil {
 		return nil, err
 	}
-	return c.listOwners(ctx, endpoint)
+	return c.listOwners(ctx, endpoint, opts...)
 }
 
-func (c *Client) ListServicePrincipalOwners(ctx context.Context, servicePrincipalID string) ([]DirectoryOwner, error) {
+func (c *Client) ListServicePrincipalOwners(ctx context.Context, servicePrincipalID string, opts ...registry.ListOption) ([]DirectoryOwner, error) {
 	servicePrincipalID = strings.TrimSpace(servicePrincipalID)
 	if servicePrincipalID == "" {
 		return nil, errors.New("service principal id is required")
@@ -364,7 +398,7 @@ func (c *Client) ListServicePrincipalOwners(ctx context.Context, servicePrincipa
 	if err != nil {
 		return nil, err
 	}
-	return c.listOwners(ctx, endpoint)
+	return c.listOwners(ctx, endpoint, opts...)
 }
 
 func (c *Client) ListDirectoryAudits(ctx context.Context, since *time.Time) ([]DirectoryAuditEvent, error) {
@@ -457,12 +491,55 @@ func (c *Client) ListOAuth2PermissionGrants(ctx context.Context) ([]OAuth2Permis
 	return out, nil
 }
 
-func (c *Client) listOwners(ctx context.Context, endpoint string) ([]DirectoryOwner, error) {
+// ListDirectoryRoleAssignments lists active directory role assignments without PIM schedule
+// information. It is the fallback for tenants where PIM is not licensed.
+func (c *Client) ListDirectoryRoleAssignments(ctx context.Context) ([]DirectoryRoleAssignment, error) {
+	return c.listDirectoryRoleAssignments(ctx, "/roleManagement/directory/roleAssignments")
+}
+
+// ListRoleAssignmentScheduleInstances lists the PIM view of active directory role assignments,
+// including permanent ones and time-bound activations of eligible roles.
+func (c *Client) ListRoleAssignmentScheduleInstances(ctx context.Context) ([]DirectoryRoleAssignment, error) {
+	return c.listDirectoryRoleAssignments(ctx, "/roleManagement/directory/roleAssignmentScheduleInstances")
+}
+
+// ListRoleEligibilityScheduleInstances lists PIM-eligible directory role assignments.
+func (c *Client) ListRoleEligibilityScheduleInstances(ctx context.Context) ([]DirectoryRoleAssignment, error) {
+	return c.listDirectoryRoleAssignments(ctx, "/roleManagement/directory/roleEligibilityScheduleInstances")
+}
+
+func (c *Client) listDirectoryRoleAssignments(ctx context.Context, path string) ([]DirectoryRoleAssignment, error) {
+	endpoint, err := c.graphURL(path, url.Values{
+		"$expand": []string{"roleDefinition,principal"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	rawItems, err := c.listPagedRaw(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
 
+	out := make([]DirectoryRoleAssignment, 0, len(rawItems))
+	for _, raw := range rawItems {
+		var assignment DirectoryRoleAssignment
+		if err := json.Unmarshal(raw, &assignment); err != nil {
+			return nil, err
+		}
+		assignment.RawJSON = raw
+		out = append(out, assignment)
+	}
+	return out, nil
+}
+
+// listOwners passes a *registry.PartialPagesError through with the owners decoded from the pages fetched.
+func (c *Client) listOwners(ctx context.Context, endpoint string, opts ...registry.ListOption) ([]DirectoryOwner, error) {
+	rawItems, pageErr := c.listPagedRaw(ctx, endpoint, opts...)
+	if pageErr != nil && rawItems == nil {
+		return nil, pageErr
+	}
+
 	out := make([]DirectoryOwner, 0, len(rawItems))
 	for _, raw := range rawItems {
 		var owner DirectoryOwner
@@ -472,16 +549,18 @@ func (c *Client) listOwners(ctx context.Context, endpoint string) ([]DirectoryOw
 		owner.RawJSON = raw
 		out = append(out, owner)
 	}
-	return out, nil
+	return out, pageErr
 }
 
-func (c *Client) listPagedRaw(ctx context.Context, endpoint string) ([]json.RawMessage, error) {
+func (c *Client) listPagedRaw(ctx context.Context, endpoint string, opts ...registry.ListOption) ([]json.RawMessage, error) {
 	var out []json.RawMessage
+	pages := 0
 	for {
 		body, err := c.get(ctx, endpoint)
 		if err != nil {
-			return nil, err
+			return registry.PageFailure(out, pages, err, opts)
 		}
+		pages++
 		var page struct {
 			Value    []json.RawMessage `json:"value"`
 			NextLink string            `json:"@odata.nextLink"`
@@ -552,7 +631,7 @@ func (c *Client) get(ctx context.Context, endpoint string) ([]byte, error) {
 			if !ok {
 				wait = retryBackoff(attempt)
 			}
-			if err := sleep(ctx, wait); err != nil {
+			if err := sleep(ctx, min(wait, httpclient.MaxBackoff("entra"))); err != nil {
 				return nil, err
 			}
 			continue
@@ -605,6 +684,34 @@ func (c *Client) token(ctx context.Context) (string, error) {
 	return accessToken, nil
 }
 
+// GrantedRoles returns the application permissions (the roles claim) carried by the client's
+// app-only access token, i.e. the Graph permissions that have been admin-consented.
+func (c *Client) GrantedRoles(ctx context.Context) ([]string, error) {
+	accessToken, err := c.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return accessTokenRoles(accessToken)
+}
+
+func accessTokenRoles(accessToken string) ([]string, error) {
+	parts := strings.Split(accessToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("entra access token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("decode entra access token claims: %w", err)
+	}
+	var claims struct {
+		Roles []string `json:"roles"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("decode entra access token claims: %w", err)
+	}
+	return claims.Roles, nil
+}
+
 func (c *Client) fetchToken(ctx context.Context) (string, time.Time, error) {
 	authority := strings.TrimRight(strings.TrimSpace(c.authorityBase), "/")
 	if authority == "" {
@@ -2,13 +2,18 @@ package entra
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"slices"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
 )
 
 func TestListUsersPaging(t *testing.T) {
@@ -72,6 +77,22 @@ func TestListUsersPaging(t *testing.T) {
 	}
 }
 
+func TestAccessTokenRoles(t *testing.T) {
+	t.Parallel()
+
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"aud":"https://graph.microsoft.com","roles":["Directory.Read.All","AuditLog.Read.All"]}`))
+	roles, err := accessTokenRoles("header." + claims + ".signature")
+	if err != nil {
+		t.Fatalf("accessTokenRoles: %v", err)
+	}
+	if !slices.Equal(roles, []string{"Directory.Read.All", "AuditLog.Read.All"}) {
+		t.Fatalf("roles = %v", roles)
+	}
+	if _, err := accessTokenRoles("opaque-token"); err == nil {
+		t.Fatalf("expected error for non-JWT token")
+	}
+}
+
 func TestNormalizeGUID(t *testing.T) {
 	t.Parallel()
 
@@ -424,3 +445,52 @@ func TestGraphURL(t *testing.T) {
 		t.Fatalf("graphURL=%q", got)
 	}
 }
+
+func TestListApplicationOwnersPartialPages(t *testing.T) {
+	t.Parallel()
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token") {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"tkn","expires_in":3600,"token_type":"Bearer"}`))
+			return
+		}
+		page := r.URL.Query().Get("page")
+		switch page {
+		case "3":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"code":"Authorization_RequestDenied","message":"denied"}}`))
+			return
+		case "":
+			page = "1"
+		}
+		next := map[string]string{"1": "2", "2": "3"}[page]
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"value":           []map[string]any{{"id": "owner-" + page, "displayName": "Owner " + page}},
+			"@odata.nextLink": srv.URL + "/graph/v1.0/applications/app-1/owners?page=" + next,
+		})
+	}))
+	defer srv.Close()
+
+	c, err := NewWithOptions("tenant", "client", "secret", Options{
+		AuthorityBaseURL: srv.URL,
+		GraphBaseURL:     srv.URL + "/graph/v1.0",
+	})
+	if err != nil {
+		t.Fatalf("NewWithOptions: %v", err)
+	}
+
+	if owners, err := c.ListApplicationOwners(context.Background(), "app-1"); err == nil || owners != nil {
+		t.Fatalf("ListApplicationOwners() = %v, %v; want no owners and an error by default", owners, err)
+	}
+
+	owners, err := c.ListApplicationOwners(context.Background(), "app-1", registry.AllowPartialPages())
+	var partial *registry.PartialPagesError
+	if !errors.As(err, &partial) || partial.Pages != 2 {
+		t.Fatalf("ListApplicationOwners(AllowPartialPages) error = %v, want *registry.PartialPagesError after 2 pages", err)
+	}
+	if len(owners) != 2 || owners[0].ID != "owner-1" || owners[1].ID != "owner-2" {
+		t.Fatalf("owners = %+v, want pages 1 and 2", owners)
+	}
+}
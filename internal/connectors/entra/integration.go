@@ -3,34 +3,75 @@ package entra
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"log/slog"
 	"regexp"
 	"strings"
+	gosync "sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-sspm/open-sspm/internal/accessgraph"
 	"github.com/open-sspm/open-sspm/internal/connectors/registry"
 	"github.com/open-sspm/open-sspm/internal/db/gen"
 	"github.com/open-sspm/open-sspm/internal/discovery"
+	"github.com/open-sspm/open-sspm/internal/matching"
 	"github.com/open-sspm/open-sspm/internal/metrics"
 )
 
 const (
-	entraUserBatchSize       = 1000
-	entraAppAssetBatchSize   = 1000
-	entraOwnerBatchSize      = 2000
-	entraCredentialBatchSize = 2000
-	entraAuditEventBatchSize = 2000
+	entraUserBatchSize        = 1000
+	entraAppAssetBatchSize    = 1000
+	entraOwnerBatchSize       = 2000
+	entraCredentialBatchSize  = 2000
+	entraAuditEventBatchSize  = 2000
+	entraEntitlementBatchSize = 5000
 )
 
+// Directory role assignment states recorded on entitlements. Permanent assignments are standing
+// privilege; active covers time-bound assignments and PIM activations; eligible roles must be
+// activated on demand before they grant anything. Unknown marks assignments listed without their
+// PIM schedule, which could be either permanent or active.
+const (
+	entraAssignmentStatePermanent = "permanent"
+	entraAssignmentStateActive    = "active"
+	entraAssignmentStateEligible  = "eligible"
+	entraAssignmentStateUnknown   = "unknown"
+)
+
+const entraDirectoryRoleEntitlementKind = "entra_directory_role"
+
+// entraRequiredRoles are the Graph application permissions a full sync reads with: users, groups,
+// applications and their owners, directory audits, and (PIM) role assignments.
+var entraRequiredRoles = []string{
+	"User.Read.All",
+	"Group.Read.All",
+	"Application.Read.All",
+	"AuditLog.Read.All",
+	"RoleManagement.Read.Directory",
+}
+
+// entraRoleImplications lists the broader permissions that confer a required one.
+var entraRoleImplications = map[string][]string{
+	"directory.readwrite.all":            {"directory.read.all"},
+	"directory.read.all":                 {"user.read.all", "group.read.all", "application.read.all"},
+	"user.readwrite.all":                 {"user.read.all"},
+	"group.readwrite.all":                {"group.read.all"},
+	"application.readwrite.all":          {"application.read.all"},
+	"rolemanagement.readwrite.directory": {"rolemanagement.read.directory"},
+	"rolemanagement.read.all":            {"rolemanagement.read.directory"},
+}
+
 var credentialGUIDPattern = regexp.MustCompile(`(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
 
 type EntraIntegration struct {
 	client           *Client
 	tenantID         string
+	workers          int
 	discoveryEnabled bool
 }
 
@@ -70,6 +111,16 @@ type credentialArtifactUpsertRow struct {
 	RawJSON            []byte
 }
 
+type entitlementUpsertRow struct {
+	AppUserExternalID string
+	Kind              string
+	Resource          string
+	Permission        string
+	AssignmentState   string
+	RoleName          string
+	RawJSON           []byte
+}
+
 type credentialAuditEventUpsertRow struct {
 	EventExternalID      string
 	EventType            string
@@ -85,10 +136,14 @@ type credentialAuditEventUpsertRow struct {
 	RawJSON              []byte
 }
 
-func NewEntraIntegration(client *Client, tenantID string, discoveryEnabled bool) *EntraIntegration {
+func NewEntraIntegration(client *Client, tenantID string, workers int, discoveryEnabled bool) *EntraIntegration {
+	if workers < 1 {
+		workers = 6
+	}
 	return &EntraIntegration{
 		client:           client,
 		tenantID:         strings.ToLower(strings.TrimSpace(tenantID)),
+		workers:          workers,
 		discoveryEnabled: discoveryEnabled,
 	}
 }
@@ -121,6 +176,8 @@ func (i *EntraIntegration) InitEvents() []registry.Event {
 		{Source: "entra", Stage: "list-owners", Current: 0, Total: registry.UnknownTotal, Message: "listing Entra app owners"},
 		{Source: "entra", Stage: "write-owners", Current: 0, Total: registry.UnknownTotal, Message: "writing Entra app owners"},
 		{Source: "entra", Stage: "write-credentials", Current: 0, Total: registry.UnknownTotal, Message: "writing Entra credential metadata"},
+		{Source: "entra", Stage: "list-role-assignments", Current: 0, Total: 1, Message: "listing Entra directory role assignments"},
+		{Source: "entra", Stage: "write-entitlements", Current: 0, Total: registry.UnknownTotal, Message: "writing Entra directory role entitlements"},
 		{Source: "entra", Stage: "list-audit-events", Current: 0, Total: 1, Message: "listing Entra directory audit events"},
 		{Source: "entra", Stage: "write-audit-events", Current: 0, Total: registry.UnknownTotal, Message: "writing Entra credential audit events"},
 		{Source: "entra", Stage: "list-discovery-events", Current: 0, Total: 1, Message: "listing Entra discovery signals"},
@@ -145,7 +202,7 @@ func (i *EntraIntegration) runFull(ctx context.Context, q *gen.Queries, pool *pg
 	started := time.Now()
 	slog.Info("syncing Microsoft Entra ID")
 
-	runID, err := q.CreateSyncRun(ctx, gen.CreateSyncRunParams{
+	runID, err := registry.CreateSyncRun(ctx, q, gen.CreateSyncRunParams{
 		SourceKind: registry.SyncRunSourceKind("entra", registry.RunModeFull),
 		SourceName: i.tenantID,
 	})
@@ -153,6 +210,13 @@ func (i *EntraIntegration) runFull(ctx context.Context, q *gen.Queries, pool *pg
 		return err
 	}
 
+	warnings := registry.NewWarningReporter(report)
+	defer func() {
+		_ = registry.PersistSyncRunWarnings(ctx, q, runID, warnings.Warnings())
+	}()
+	defer i.recordGrants(ctx, q, runID)
+	holds := registry.NewExpiryHolds()
+
 	usersWritten, err := i.syncUsers(ctx, q, report, runID)
 	if err != nil {
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindUnknown)
@@ -181,34 +245,60 @@ func (i *EntraIntegration) runFull(ctx context.Context, q *gen.Queries, pool *pg
 		Message: fmt.Sprintf("found %d applications and %d service principals", len(applications), len(servicePrincipals)),
 	})
 
-	servicePrincipalsWritten, err := i.syncServicePrincipalAccounts(ctx, q, report, runID, servicePrincipals)
-	if err != nil {
+	servicePrincipalsWritten := 0
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		written, err := i.syncServicePrincipalAccounts(ctx, qtx, report, runID, servicePrincipals)
+		servicePrincipalsWritten = written
+		return err
+	}); err != nil {
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
-	assetRows, credentialRows := buildEntraAssetAndCredentialRows(applications, servicePrincipals)
-	if err := i.upsertAppAssets(ctx, q, report, runID, assetRows); err != nil {
+	assetRows, credentialRows := buildEntraAssetAndCredentialRows(i.tenantID, applications, servicePrincipals)
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertAppAssets(ctx, qtx, report, runID, assetRows)
+	}); err != nil {
 		report(registry.Event{Source: "entra", Stage: "write-app-assets", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
-	ownerRows, err := i.collectAppAssetOwners(ctx, report, applications, servicePrincipals)
+	ownerRows, err := i.collectAppAssetOwners(ctx, warnings, holds, applications, servicePrincipals)
 	if err != nil {
 		report(registry.Event{Source: "entra", Stage: "list-owners", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindAPI)
 	}
-	if err := i.upsertAppAssetOwners(ctx, q, report, runID, ownerRows); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertAppAssetOwners(ctx, qtx, report, runID, ownerRows)
+	}); err != nil {
 		report(registry.Event{Source: "entra", Stage: "write-owners", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
-	if err := i.upsertCredentialArtifacts(ctx, q, report, runID, credentialRows); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertCredentialArtifacts(ctx, qtx, report, runID, credentialRows)
+	}); err != nil {
 		report(registry.Event{Source: "entra", Stage: "write-credentials", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
+	entitlementRows := i.collectDirectoryRoleEntitlements(ctx, warnings, holds, servicePrincipals)
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertEntitlements(ctx, qtx, report, runID, entitlementRows)
+	}); err != nil {
+		report(registry.Event{Source: "entra", Stage: "write-entitlements", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
+	}
+
 	report(registry.Event{Source: "entra", Stage: "list-audit-events", Current: 0, Total: 1, Message: "listing directory audit events"})
-	directoryAudits, err := i.client.ListDirectoryAudits(ctx, nil)
+	auditWatermark, err := q.GetLatestCredentialAuditEventTimeBySource(ctx, gen.GetLatestCredentialAuditEventTimeBySourceParams{
+		SourceKind: "entra",
+		SourceName: i.tenantID,
+	})
+	if err != nil {
+		report(registry.Event{Source: "entra", Stage: "list-audit-events", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, fmt.Errorf("query latest directory audit watermark: %w", err), registry.SyncErrorKindDB)
+	}
+	directoryAudits, err := i.client.ListDirectoryAudits(ctx, directoryAuditSince(auditWatermark))
 	if err != nil {
 		report(registry.Event{Source: "entra", Stage: "list-audit-events", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindAPI)
@@ -222,12 +312,14 @@ func (i *EntraIntegration) runFull(ctx context.Context, q *gen.Queries, pool *pg
 	})
 
 	auditEventRows := buildCredentialAuditEventRows(directoryAudits)
-	if err := i.upsertCredentialAuditEvents(ctx, q, report, auditEventRows); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertCredentialAuditEvents(ctx, qtx, report, auditEventRows)
+	}); err != nil {
 		report(registry.Event{Source: "entra", Stage: "write-audit-events", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
-	if err := registry.FinalizeAppRun(ctx, q, pool, runID, "entra", i.tenantID, time.Since(started), false); err != nil {
+	if err := registry.FinalizeAppRun(ctx, q, pool, runID, "entra", i.tenantID, time.Since(started), false, holds); err != nil {
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
@@ -240,16 +332,46 @@ func (i *EntraIntegration) runFull(ctx context.Context, q *gen.Queries, pool *pg
 		"app_assets", len(assetRows),
 		"owners", len(ownerRows),
 		"credentials", len(credentialRows),
+		"role_entitlements", len(entitlementRows),
 		"audit_events", len(auditEventRows),
 	)
 	return nil
 }
 
+// recordGrants stores the Graph application permissions in the token against what a full sync
+// needs. A token that could not be fetched leaves the stored grants untouched.
+func (i *EntraIntegration) recordGrants(ctx context.Context, q *gen.Queries, runID int64) {
+	roles, err := i.client.GrantedRoles(ctx)
+	if err != nil {
+		slog.Warn("entra grants not recorded", "tenant", i.tenantID, "err", err)
+		return
+	}
+	grants := registry.EvaluateGrants(roles, entraRequiredRoles, entraRoleImplications)
+	if err := registry.PersistConnectorGrants(ctx, q, "entra", i.tenantID, runID, grants); err != nil {
+		slog.Warn("entra grants not recorded", "tenant", i.tenantID, "err", err)
+	}
+}
+
+// RequiredGrants lists the Graph application permissions a full sync needs.
+func (i *EntraIntegration) RequiredGrants() []string {
+	return append([]string(nil), entraRequiredRoles...)
+}
+
+// TestConnection acquires an app-only token, which checks the tenant and client credentials, and
+// evaluates the permissions it carries.
+func (i *EntraIntegration) TestConnection(ctx context.Context) (registry.ConnectorGrants, bool, error) {
+	roles, err := i.client.GrantedRoles(ctx)
+	if err != nil {
+		return registry.ConnectorGrants{}, false, err
+	}
+	return registry.EvaluateGrants(roles, entraRequiredRoles, entraRoleImplications), true, nil
+}
+
 func (i *EntraIntegration) runDiscovery(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, report func(registry.Event)) error {
 	started := time.Now()
 	slog.Info("syncing Microsoft Entra ID discovery")
 
-	runID, err := q.CreateSyncRun(ctx, gen.CreateSyncRunParams{
+	runID, err := registry.CreateSyncRun(ctx, q, gen.CreateSyncRunParams{
 		SourceKind: registry.SyncRunSourceKind("entra", registry.RunModeDiscovery),
 		SourceName: i.tenantID,
 	})
@@ -268,7 +390,7 @@ func (i *EntraIntegration) runDiscovery(ctx context.Context, q *gen.Queries, poo
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindAPI)
 	}
 
-	if err := i.syncDiscovery(ctx, q, report, runID, applications, servicePrincipals); err != nil {
+	if err := i.syncDiscovery(ctx, q, pool, report, runID, applications, servicePrincipals); err != nil {
 		report(registry.Event{Source: "entra", Stage: "write-discovery", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindUnknown)
 	}
@@ -296,6 +418,7 @@ func (i *EntraIntegration) syncUsers(ctx context.Context, q *gen.Queries, report
 	lastLoginAts := make([]pgtype.Timestamptz, 0, len(users))
 	lastLoginIps := make([]string, 0, len(users))
 	lastLoginRegions := make([]string, 0, len(users))
+	otherEmails := make([][]string, 0, len(users))
 
 	for _, user := range users {
 		externalID := strings.TrimSpace(user.ID)
@@ -303,7 +426,7 @@ func (i *EntraIntegration) syncUsers(ctx context.Context, q *gen.Queries, report
 			continue
 		}
 
-		email := normalizeEmail(preferredEmail(user))
+		email := matching.NormalizeEmail(preferredEmail(user))
 
 		display := strings.TrimSpace(user.DisplayName)
 		if display == "" {
@@ -321,6 +444,7 @@ func (i *EntraIntegration) syncUsers(ctx context.Context, q *gen.Queries, report
 			OtherMails:         user.OtherMails,
 			ProxyAddresses:     user.ProxyAddresses,
 			UserType:           strings.TrimSpace(user.UserType),
+			IsGuest:            entraUserIsGuest(user),
 			AccountEnabled:     user.AccountEnabled,
 			Status:             entraAccountStatus(user.AccountEnabled),
 			CreatedDateTimeRaw: strings.TrimSpace(user.CreatedDateTimeRaw),
@@ -337,6 +461,7 @@ func (i *EntraIntegration) syncUsers(ctx context.Context, q *gen.Queries, report
 		lastLoginAts = append(lastLoginAts, pgtype.Timestamptz{})
 		lastLoginIps = append(lastLoginIps, "")
 		lastLoginRegions = append(lastLoginRegions, "")
+		otherEmails = append(otherEmails, secondaryEmails(user, email))
 	}
 
 	for start := 0; start < len(externalIDs); start += entraUserBatchSize {
@@ -358,6 +483,9 @@ func (i *EntraIntegration) syncUsers(ctx context.Context, q *gen.Queries, report
 		if err != nil {
 			return 0, err
 		}
+		if err := q.ReplaceAccountSecondaryEmailsBySource(ctx, registry.AccountSecondaryEmailsParams("entra", i.tenantID, externalIDs[start:end], otherEmails[start:end])); err != nil {
+			return 0, err
+		}
 
 		report(registry.Event{
 			Source:  "entra",
@@ -405,10 +533,10 @@ func (i *EntraIntegration) syncGroups(ctx context.Context, q *gen.Queries, repor
 		}
 
 		externalIDs = append(externalIDs, externalID)
-		emails = append(emails, normalizeEmail(strings.TrimSpace(group.Mail)))
+		emails = append(emails, matching.NormalizeEmail(group.Mail))
 		displayNames = append(displayNames, display)
 		accountKinds = append(accountKinds, entraGroupAccountKind(group))
-		rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeJSON(group.RawJSON), registry.EntityCategoryGroup))
+		rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeRawJSON(group.RawJSON), registry.EntityCategoryGroup))
 		lastLoginAts = append(lastLoginAts, pgtype.Timestamptz{})
 		lastLoginIps = append(lastLoginIps, "")
 		lastLoginRegions = append(lastLoginRegions, "")
@@ -486,7 +614,7 @@ func (i *EntraIntegration) syncServicePrincipalAccounts(ctx context.Context, q *
 		emails = append(emails, "")
 		displayNames = append(displayNames, display)
 		accountKinds = append(accountKinds, entraServicePrincipalAccountKind(sp))
-		rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeJSON(sp.RawJSON), registry.EntityCategoryServicePrincipal))
+		rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeRawJSON(sp.RawJSON), registry.EntityCategoryServicePrincipal))
 		lastLoginAts = append(lastLoginAts, pgtype.Timestamptz{})
 		lastLoginIps = append(lastLoginIps, "")
 		lastLoginRegions = append(lastLoginRegions, "")
@@ -524,9 +652,10 @@ func (i *EntraIntegration) syncServicePrincipalAccounts(ctx context.Context, q *
 	return len(externalIDs), nil
 }
 
-func buildEntraAssetAndCredentialRows(applications []Application, servicePrincipals []ServicePrincipal) ([]appAssetUpsertRow, []credentialArtifactUpsertRow) {
+func buildEntraAssetAndCredentialRows(tenantID string, applications []Application, servicePrincipals []ServicePrincipal) ([]appAssetUpsertRow, []credentialArtifactUpsertRow) {
 	assetRows := make([]appAssetUpsertRow, 0, len(applications)+len(servicePrincipals))
 	credentialRows := make([]credentialArtifactUpsertRow, 0)
+	linkage := linkEntraApplications(applications, servicePrincipals)
 
 	for _, app := range applications {
 		externalID := strings.TrimSpace(app.ID)
@@ -537,6 +666,7 @@ func buildEntraAssetAndCredentialRows(applications []Application, servicePrincip
 		if displayName == "" {
 			displayName = externalID
 		}
+		servicePrincipalID, orphan := linkage.application(app)
 
 		assetRows = append(assetRows, appAssetUpsertRow{
 			AssetKind:        "entra_application",
@@ -555,6 +685,8 @@ func buildEntraAssetAndCredentialRows(applications []Application, servicePrincip
 				"created_date_time":               strings.TrimSpace(app.CreatedDateTimeRaw),
 				"password_credentials":            sanitizePasswordCredentials(app.PasswordCredentials),
 				"key_credentials":                 sanitizeKeyCredentials(app.KeyCredentials),
+				"service_principal_id":            servicePrincipalID,
+				"orphan":                          orphan,
 			}),
 		})
 
@@ -576,6 +708,7 @@ func buildEntraAssetAndCredentialRows(applications []Application, servicePrincip
 		if displayName == "" {
 			displayName = externalID
 		}
+		applicationID, orphan := linkage.servicePrincipal(sp, tenantID)
 
 		assetRows = append(assetRows, appAssetUpsertRow{
 			AssetKind:        "entra_service_principal",
@@ -586,15 +719,18 @@ func buildEntraAssetAndCredentialRows(applications []Application, servicePrincip
 			CreatedAtSource:  parseGraphTime(sp.CreatedDateTimeRaw),
 			UpdatedAtSource:  pgtype.Timestamptz{},
 			RawJSON: registry.MarshalJSON(map[string]any{
-				"id":                     externalID,
-				"app_id":                 strings.TrimSpace(sp.AppID),
-				"display_name":           strings.TrimSpace(sp.DisplayName),
-				"publisher_name":         strings.TrimSpace(sp.PublisherName),
-				"account_enabled":        sp.AccountEnabled,
-				"service_principal_type": strings.TrimSpace(sp.ServicePrincipalType),
-				"created_date_time":      strings.TrimSpace(sp.CreatedDateTimeRaw),
-				"password_credentials":   sanitizePasswordCredentials(sp.PasswordCredentials),
-				"key_credentials":        sanitizeKeyCredentials(sp.KeyCredentials),
+				"id":                        externalID,
+				"app_id":                    strings.TrimSpace(sp.AppID),
+				"display_name":              strings.TrimSpace(sp.DisplayName),
+				"publisher_name":            strings.TrimSpace(sp.PublisherName),
+				"account_enabled":           sp.AccountEnabled,
+				"service_principal_type":    strings.TrimSpace(sp.ServicePrincipalType),
+				"app_owner_organization_id": strings.TrimSpace(sp.AppOwnerOrganizationID),
+				"created_date_time":         strings.TrimSpace(sp.CreatedDateTimeRaw),
+				"password_credentials":      sanitizePasswordCredentials(sp.PasswordCredentials),
+				"key_credentials":           sanitizeKeyCredentials(sp.KeyCredentials),
+				"application_id":            applicationID,
+				"orphan":                    orphan,
 			}),
 		})
 
@@ -708,48 +844,147 @@ func buildEntraCertificateCredentialRow(assetKind, assetExternalID, assetRefExte
 	}
 }
 
-func (i *EntraIntegration) collectAppAssetOwners(ctx context.Context, report func(registry.Event), applications []Application, servicePrincipals []ServicePrincipal) ([]appAssetOwnerUpsertRow, error) {
+func (i *EntraIntegration) collectAppAssetOwners(ctx context.Context, warnings *registry.WarningReporter, holds *registry.ExpiryHolds, applications []Application, servicePrincipals []ServicePrincipal) ([]appAssetOwnerUpsertRow, error) {
 	totalAssets := len(applications) + len(servicePrincipals)
-	report(registry.Event{Source: "entra", Stage: "list-owners", Current: 0, Total: int64(totalAssets), Message: fmt.Sprintf("listing owners for %d app assets", totalAssets)})
+	warnings.Report(registry.Event{Source: "entra", Stage: "list-owners", Current: 0, Total: int64(totalAssets), Message: fmt.Sprintf("listing owners for %d app assets", totalAssets)})
 
-	rows := make([]appAssetOwnerUpsertRow, 0)
-	processed := 0
+	type ownerLookup struct {
+		index           int
+		assetKind       string
+		assetExternalID string
+	}
+	type ownerResult struct {
+		index int
+		rows  []appAssetOwnerUpsertRow
+		err   error
+	}
 
+	lookups := make([]ownerLookup, 0, totalAssets)
+	var processed int64
 	for _, app := range applications {
-		assetExternalID := strings.TrimSpace(app.ID)
-		if assetExternalID == "" {
+		if assetExternalID := strings.TrimSpace(app.ID); assetExternalID != "" {
+			lookups = append(lookups, ownerLookup{index: len(lookups), assetKind: "entra_application", assetExternalID: assetExternalID})
+		} else {
 			processed++
-			continue
 		}
-
-		owners, err := i.client.ListApplicationOwners(ctx, assetExternalID)
-		if err != nil {
-			return nil, fmt.Errorf("entra application owners %s: %w", assetExternalID, err)
-		}
-		rows = append(rows, buildOwnerRows("entra_application", assetExternalID, owners)...)
-		processed++
-		report(registry.Event{Source: "entra", Stage: "list-owners", Current: int64(processed), Total: int64(totalAssets), Message: fmt.Sprintf("owners for assets %d/%d", processed, totalAssets)})
 	}
-
 	for _, sp := range servicePrincipals {
-		assetExternalID := strings.TrimSpace(sp.ID)
-		if assetExternalID == "" {
+		if assetExternalID := strings.TrimSpace(sp.ID); assetExternalID != "" {
+			lookups = append(lookups, ownerLookup{index: len(lookups), assetKind: "entra_service_principal", assetExternalID: assetExternalID})
+		} else {
 			processed++
-			continue
 		}
+	}
+	if len(lookups) == 0 {
+		return []appAssetOwnerUpsertRow{}, nil
+	}
 
-		owners, err := i.client.ListServicePrincipalOwners(ctx, assetExternalID)
-		if err != nil {
-			return nil, fmt.Errorf("entra service principal owners %s: %w", assetExternalID, err)
+	ownerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan ownerLookup, len(lookups))
+	results := make(chan ownerResult, len(lookups))
+
+	workers := min(len(lookups), i.workers)
+	if workers < 1 {
+		workers = 1
+	}
+
+	var wg gosync.WaitGroup
+	for j := 0; j < workers; j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for lookup := range jobs {
+				if ownerCtx.Err() != nil {
+					return
+				}
+				var owners []DirectoryOwner
+				var err error
+				// Owners are enrichment: an owner listing that breaks midway keeps the pages
+				// already fetched and the run records a warning instead of failing. The asset's
+				// owners are held from expiry since the missing pages may list more.
+				if lookup.assetKind == "entra_application" {
+					owners, err = i.client.ListApplicationOwners(ownerCtx, lookup.assetExternalID, registry.AllowPartialPages())
+					if err = keepPartialOwners(ownerCtx, warnings, holds, lookup.assetKind, lookup.assetExternalID, err); err != nil {
+						err = fmt.Errorf("entra application owners %s: %w", lookup.assetExternalID, err)
+					}
+				} else {
+					owners, err = i.client.ListServicePrincipalOwners(ownerCtx, lookup.assetExternalID, registry.AllowPartialPages())
+					if err = keepPartialOwners(ownerCtx, warnings, holds, lookup.assetKind, lookup.assetExternalID, err); err != nil {
+						err = fmt.Errorf("entra service principal owners %s: %w", lookup.assetExternalID, err)
+					}
+				}
+				if err != nil {
+					results <- ownerResult{index: lookup.index, err: err}
+					cancel()
+					continue
+				}
+				n := atomic.AddInt64(&processed, 1)
+				warnings.Report(registry.Event{Source: "entra", Stage: "list-owners", Current: n, Total: int64(totalAssets), Message: fmt.Sprintf("owners for assets %d/%d", n, totalAssets)})
+				results <- ownerResult{index: lookup.index, rows: buildOwnerRows(lookup.assetKind, lookup.assetExternalID, owners)}
+			}
+		}()
+	}
+
+	for _, lookup := range lookups {
+		jobs <- lookup
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	// Results arrive in completion order; slot them by lookup index so rows keep
+	// the same order as a serial pass.
+	rowsByLookup := make([][]appAssetOwnerUpsertRow, len(lookups))
+	var firstErr error
+	var firstNonCancelErr error
+	for res := range results {
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = res.err
+			}
+			if firstNonCancelErr == nil && !errors.Is(res.err, context.Canceled) {
+				firstNonCancelErr = res.err
+			}
+			continue
 		}
-		rows = append(rows, buildOwnerRows("entra_service_principal", assetExternalID, owners)...)
-		processed++
-		report(registry.Event{Source: "entra", Stage: "list-owners", Current: int64(processed), Total: int64(totalAssets), Message: fmt.Sprintf("owners for assets %d/%d", processed, totalAssets)})
+		rowsByLookup[res.index] = res.rows
+	}
+	if firstNonCancelErr != nil {
+		firstErr = firstNonCancelErr
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
+	rows := make([]appAssetOwnerUpsertRow, 0)
+	for _, lookupRows := range rowsByLookup {
+		rows = append(rows, lookupRows...)
+	}
 	return rows, nil
 }
 
+// keepPartialOwners reports an owner listing that failed after some pages as a warning, holds the
+// asset's owners from expiry, and clears the error so the collected owners are used. Other errors,
+// and failures caused by the owner lookups being cancelled, are returned unchanged.
+func keepPartialOwners(ctx context.Context, warnings *registry.WarningReporter, holds *registry.ExpiryHolds, assetKind, assetExternalID string, err error) error {
+	var partial *registry.PartialPagesError
+	if !errors.As(err, &partial) || ctx.Err() != nil {
+		return err
+	}
+	holds.HoldAppAssetOwners(assetKind, assetExternalID)
+	warnings.ReportWarning(registry.Event{
+		Source:  "entra",
+		Stage:   "list-owners",
+		Message: fmt.Sprintf("%s %s owners incomplete after %d pages: %v", assetKind, assetExternalID, partial.Pages, partial.Err),
+	})
+	return nil
+}
+
 func buildOwnerRows(assetKind, assetExternalID string, owners []DirectoryOwner) []appAssetOwnerUpsertRow {
 	rows := make([]appAssetOwnerUpsertRow, 0, len(owners))
 	for _, owner := range owners {
@@ -765,9 +1000,9 @@ func buildOwnerRows(assetKind, assetExternalID string, owners []DirectoryOwner)
 		if ownerDisplayName == "" {
 			ownerDisplayName = ownerExternalID
 		}
-		ownerEmail := normalizeEmail(strings.TrimSpace(owner.Mail))
+		ownerEmail := matching.NormalizeEmail(owner.Mail)
 		if ownerEmail == "" {
-			ownerEmail = normalizeEmail(strings.TrimSpace(owner.UserPrincipalName))
+			ownerEmail = matching.NormalizeEmail(owner.UserPrincipalName)
 		}
 
 		rows = append(rows, appAssetOwnerUpsertRow{
@@ -970,6 +1205,280 @@ func (i *EntraIntegration) upsertCredentialArtifacts(ctx context.Context, q *gen
 	return nil
 }
 
+// collectDirectoryRoleEntitlements lists directory role assignments, preferring the PIM schedule
+// instances so that eligible and time-bound roles can be told apart from standing ones. When PIM
+// is unavailable (unlicensed tenant or missing RoleManagement permissions) it falls back to plain
+// role assignments, whose state is unknown, and holds role entitlements from expiry because
+// eligible assignments cannot be listed; when that also fails no roles are written and they are
+// held the same way. Either failure is a warning rather than a failed sync.
+func (i *EntraIntegration) collectDirectoryRoleEntitlements(ctx context.Context, warnings *registry.WarningReporter, holds *registry.ExpiryHolds, servicePrincipals []ServicePrincipal) []entitlementUpsertRow {
+	warnings.Report(registry.Event{Source: "entra", Stage: "list-role-assignments", Current: 0, Total: 1, Message: "listing directory role assignments"})
+
+	active, err := i.client.ListRoleAssignmentScheduleInstances(ctx)
+	var eligible []DirectoryRoleAssignment
+	if err == nil {
+		eligible, err = i.client.ListRoleEligibilityScheduleInstances(ctx)
+	}
+	schedulesListed := err == nil
+	if err != nil {
+		holds.HoldEntitlements(entraDirectoryRoleEntitlementKind, "")
+		warnings.ReportWarning(registry.Event{Source: "entra", Stage: "list-role-assignments", Message: fmt.Sprintf("PIM role schedules unavailable, recording directory role assignments without their state and keeping unseen roles: %v", err)})
+		eligible = nil
+		active, err = i.client.ListDirectoryRoleAssignments(ctx)
+		if err != nil {
+			warnings.ReportWarning(registry.Event{Source: "entra", Stage: "list-role-assignments", Message: fmt.Sprintf("skipped directory role inventory: %v", err)})
+			return nil
+		}
+	}
+
+	warnings.Report(registry.Event{
+		Source:  "entra",
+		Stage:   "list-role-assignments",
+		Current: 1,
+		Total:   1,
+		Message: fmt.Sprintf("found %d active and %d eligible directory role assignments", len(active), len(eligible)),
+	})
+	return buildEntraRoleEntitlementRows(active, eligible, schedulesListed, servicePrincipals)
+}
+
+// buildEntraRoleEntitlementRows maps directory role assignments to entitlements, one per principal,
+// role, and directory scope. A principal that is both eligible for and holding a role keeps the
+// strongest state (permanent, then active, then eligible); the eligibility window is still kept in
+// the raw JSON so reviewers can see that standing access also exists as a just-in-time grant.
+// Without schedulesListed the active assignments come from plain role assignments and are recorded
+// as unknown.
+func buildEntraRoleEntitlementRows(active, eligible []DirectoryRoleAssignment, schedulesListed bool, servicePrincipals []ServicePrincipal) []entitlementUpsertRow {
+	servicePrincipalIDs := make(map[string]struct{}, len(servicePrincipals))
+	for _, sp := range servicePrincipals {
+		if id := strings.TrimSpace(sp.ID); id != "" {
+			servicePrincipalIDs[id] = struct{}{}
+		}
+	}
+
+	type roleGrant struct {
+		row        entitlementUpsertRow
+		assignment DirectoryRoleAssignment
+		eligible   *DirectoryRoleAssignment
+	}
+	byKey := make(map[string]*roleGrant)
+	order := make([]string, 0, len(active)+len(eligible))
+
+	add := func(assignment DirectoryRoleAssignment, state string) {
+		appUserExternalID := entraRolePrincipalExternalID(assignment, servicePrincipalIDs)
+		roleID := entraRoleDefinitionID(assignment)
+		if appUserExternalID == "" || roleID == "" {
+			return
+		}
+		scope := strings.TrimSpace(assignment.DirectoryScopeID)
+		if scope == "" {
+			scope = "/"
+		}
+		permission := "member"
+		if scope != "/" {
+			permission = scope
+		}
+
+		row := entitlementUpsertRow{
+			AppUserExternalID: appUserExternalID,
+			Kind:              entraDirectoryRoleEntitlementKind,
+			Resource:          accessgraph.ResourceKindEntraDirectoryRole + ":" + roleID,
+			Permission:        permission,
+			AssignmentState:   state,
+			RoleName:          entraRoleDisplayName(assignment),
+		}
+		key := row.AppUserExternalID + "::" + row.Resource + "::" + row.Permission
+		existing, ok := byKey[key]
+		if !ok {
+			byKey[key] = &roleGrant{row: row, assignment: assignment}
+			if state == entraAssignmentStateEligible {
+				byKey[key].eligible = &assignment
+			}
+			order = append(order, key)
+			return
+		}
+		if state == entraAssignmentStateEligible && existing.eligible == nil {
+			existing.eligible = &assignment
+		}
+		if entraAssignmentStateRank(state) > entraAssignmentStateRank(existing.row.AssignmentState) {
+			existing.row = row
+			existing.assignment = assignment
+		}
+	}
+
+	for _, assignment := range active {
+		state := entraAssignmentStateUnknown
+		if schedulesListed {
+			state = entraActiveAssignmentState(assignment)
+		}
+		add(assignment, state)
+	}
+	for _, assignment := range eligible {
+		add(assignment, entraAssignmentStateEligible)
+	}
+
+	rows := make([]entitlementUpsertRow, 0, len(order))
+	for _, key := range order {
+		grant := byKey[key]
+		raw := map[string]any{
+			"role_id":            entraRoleDefinitionID(grant.assignment),
+			"role_name":          entraRoleDisplayName(grant.assignment),
+			"directory_scope_id": strings.TrimSpace(grant.assignment.DirectoryScopeID),
+			"assignment_state":   grant.row.AssignmentState,
+			"assignment_type":    strings.TrimSpace(grant.assignment.AssignmentType),
+			"member_type":        strings.TrimSpace(grant.assignment.MemberType),
+			"activation_window":  entraRoleWindow(grant.assignment),
+		}
+		if grant.eligible != nil {
+			raw["eligibility_window"] = entraRoleWindow(*grant.eligible)
+		}
+		grant.row.RawJSON = registry.MarshalJSON(raw)
+		rows = append(rows, grant.row)
+	}
+	return rows
+}
+
+// entraActiveAssignmentState classifies an active role assignment. PIM activations and
+// assignments with an end date are time-bound; anything else is standing privilege.
+func entraActiveAssignmentState(assignment DirectoryRoleAssignment) string {
+	if strings.EqualFold(strings.TrimSpace(assignment.AssignmentType), "Activated") {
+		return entraAssignmentStateActive
+	}
+	if strings.TrimSpace(assignment.EndDateTimeRaw) != "" {
+		return entraAssignmentStateActive
+	}
+	return entraAssignmentStatePermanent
+}
+
+func entraAssignmentStateRank(state string) int {
+	switch state {
+	case entraAssignmentStatePermanent:
+		return 3
+	case entraAssignmentStateActive:
+		return 2
+	case entraAssignmentStateEligible:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func entraRolePrincipalExternalID(assignment DirectoryRoleAssignment, servicePrincipalIDs map[string]struct{}) string {
+	principalID := strings.TrimSpace(assignment.PrincipalID)
+	if principalID == "" {
+		return ""
+	}
+	if assignment.Principal != nil {
+		switch strings.ToLower(strings.TrimSpace(assignment.Principal.ODataType)) {
+		case "#microsoft.graph.serviceprincipal":
+			return entraServicePrincipalExternalID(principalID)
+		case "#microsoft.graph.group":
+			return entraGroupExternalID(principalID)
+		}
+	}
+	if _, ok := servicePrincipalIDs[principalID]; ok {
+		return entraServicePrincipalExternalID(principalID)
+	}
+	return principalID
+}
+
+func entraRoleDefinitionID(assignment DirectoryRoleAssignment) string {
+	if id := strings.TrimSpace(assignment.RoleDefinitionID); id != "" {
+		return id
+	}
+	if assignment.RoleDefinition != nil {
+		return strings.TrimSpace(assignment.RoleDefinition.ID)
+	}
+	return ""
+}
+
+func entraRoleDisplayName(assignment DirectoryRoleAssignment) string {
+	if assignment.RoleDefinition != nil {
+		if name := strings.TrimSpace(assignment.RoleDefinition.DisplayName); name != "" {
+			return name
+		}
+	}
+	return entraRoleDefinitionID(assignment)
+}
+
+func entraRoleWindow(assignment DirectoryRoleAssignment) map[string]string {
+	return map[string]string{
+		"start_date_time": strings.TrimSpace(assignment.StartDateTimeRaw),
+		"end_date_time":   strings.TrimSpace(assignment.EndDateTimeRaw),
+	}
+}
+
+func (i *EntraIntegration) upsertEntitlements(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, rows []entitlementUpsertRow) error {
+	report(registry.Event{Source: "entra", Stage: "write-entitlements", Current: 0, Total: int64(len(rows)), Message: fmt.Sprintf("writing %d directory role entitlements", len(rows))})
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for start := 0; start < len(rows); start += entraEntitlementBatchSize {
+		end := min(start+entraEntitlementBatchSize, len(rows))
+		batch := rows[start:end]
+
+		appUserExternalIDs := make([]string, 0, len(batch))
+		kinds := make([]string, 0, len(batch))
+		resources := make([]string, 0, len(batch))
+		permissions := make([]string, 0, len(batch))
+		rawJSONs := make([][]byte, 0, len(batch))
+		assignmentStates := make([]string, 0, len(batch))
+		privilegeLevels := make([]int16, 0, len(batch))
+
+		for _, row := range batch {
+			appUserExternalIDs = append(appUserExternalIDs, row.AppUserExternalID)
+			kinds = append(kinds, row.Kind)
+			resources = append(resources, row.Resource)
+			permissions = append(permissions, row.Permission)
+			rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(row.RawJSON))
+			assignmentStates = append(assignmentStates, row.AssignmentState)
+			privilegeLevels = append(privilegeLevels, int16(entraPrivilegeMapping.Level(row.Kind, row.Permission, row.RoleName)))
+		}
+
+		if _, err := q.UpsertEntitlementsBulkBySource(ctx, gen.UpsertEntitlementsBulkBySourceParams{
+			SeenInRunID:        runID,
+			SourceKind:         "entra",
+			SourceName:         i.tenantID,
+			AppUserExternalIds: appUserExternalIDs,
+			Kinds:              kinds,
+			Resources:          resources,
+			Permissions:        permissions,
+			RawJsons:           rawJSONs,
+			AssignmentStates:   assignmentStates,
+			PrivilegeLevels:    privilegeLevels,
+		}); err != nil {
+			return err
+		}
+
+		report(registry.Event{
+			Source:  "entra",
+			Stage:   "write-entitlements",
+			Current: int64(end),
+			Total:   int64(len(rows)),
+			Message: fmt.Sprintf("entitlements %d/%d", end, len(rows)),
+		})
+	}
+
+	return nil
+}
+
+// directoryAuditWatermarkSkew is how far behind the newest stored audit event an incremental read
+// starts. Graph can surface a directory audit event minutes after its activityDateTime, and the
+// re-read events upsert idempotently.
+const directoryAuditWatermarkSkew = 15 * time.Minute
+
+// directoryAuditSince returns where an incremental directory audit read starts: the newest audit
+// event stored for the tenant, minus directoryAuditWatermarkSkew. The watermark is read from the
+// stored events, so it only moves once a run's events are written and a run that fails earlier
+// re-reads the same window. A tenant without stored events reads the full retention window (nil).
+func directoryAuditSince(watermark pgtype.Timestamptz) *time.Time {
+	if !watermark.Valid {
+		return nil
+	}
+	since := watermark.Time.UTC().Add(-directoryAuditWatermarkSkew)
+	return &since
+}
+
 func buildCredentialAuditEventRows(events []DirectoryAuditEvent) []credentialAuditEventUpsertRow {
 	rows := make([]credentialAuditEventUpsertRow, 0, len(events))
 	for _, event := range events {
@@ -1060,7 +1569,7 @@ func entraAuditActor(initiatedBy DirectoryAuditInitiatedBy) (string, string, str
 	if initiatedBy.User != nil {
 		externalID := strings.TrimSpace(initiatedBy.User.ID)
 		if externalID == "" {
-			externalID = normalizeEmail(strings.TrimSpace(initiatedBy.User.UserPrincipalName))
+			externalID = matching.NormalizeEmail(initiatedBy.User.UserPrincipalName)
 		}
 		displayName := strings.TrimSpace(initiatedBy.User.DisplayName)
 		if displayName == "" {
@@ -1304,25 +1813,21 @@ type normalizedDiscoveryEvent struct {
 	RawJSON          []byte
 }
 
-func (i *EntraIntegration) syncDiscovery(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, applications []Application, servicePrincipals []ServicePrincipal) error {
+func (i *EntraIntegration) syncDiscovery(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, report func(registry.Event), runID int64, applications []Application, servicePrincipals []ServicePrincipal) error {
 	now := time.Now().UTC()
 
 	report(registry.Event{Source: "entra", Stage: "list-discovery-events", Current: 0, Total: 1, Message: "listing sign-ins and oauth grants"})
-	since := now.Add(-7 * 24 * time.Hour)
-	latestObservedAt, err := q.GetLatestSaaSDiscoveryObservedAtBySource(ctx, gen.GetLatestSaaSDiscoveryObservedAtBySourceParams{
-		SourceKind: "entra",
-		SourceName: i.tenantID,
+	since, err := discovery.IngestSince(ctx, now, discovery.DefaultIngestLookback, 15*time.Minute, func(ctx context.Context) (time.Time, bool, error) {
+		latest, err := q.GetLatestSaaSDiscoveryObservedAtBySource(ctx, gen.GetLatestSaaSDiscoveryObservedAtBySourceParams{
+			SourceKind: "entra",
+			SourceName: i.tenantID,
+		})
+		return latest.Time, latest.Valid, err
 	})
 	if err != nil {
 		metrics.DiscoveryIngestFailuresTotal.WithLabelValues("entra", "idp_sso", "watermark_query_error").Inc()
 		return fmt.Errorf("query latest discovery watermark: %w", err)
 	}
-	if latestObservedAt.Valid {
-		candidate := latestObservedAt.Time.UTC().Add(-15 * time.Minute)
-		if candidate.After(since) {
-			since = candidate
-		}
-	}
 
 	signIns, err := i.client.ListSignIns(ctx, &since)
 	if err != nil {
@@ -1352,7 +1857,9 @@ func (i *EntraIntegration) syncDiscovery(ctx context.Context, q *gen.Queries, re
 		Message: fmt.Sprintf("normalized %d source rows and %d events", len(sources), len(events)),
 	})
 
-	if err := i.writeDiscoveryRows(ctx, q, report, runID, sources, events); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.writeDiscoveryRows(ctx, qtx, report, runID, sources, events)
+	}); err != nil {
 		metrics.DiscoveryIngestFailuresTotal.WithLabelValues("entra", "idp_sso", "db_error").Inc()
 		return err
 	}
@@ -1367,7 +1874,7 @@ func normalizeEntraDiscovery(signIns []SignInEvent, grants []OAuth2PermissionGra
 	events := make([]normalizedDiscoveryEvent, 0, len(signIns)+len(grants))
 
 	appDisplayByAppID := make(map[string]string, len(applications))
-	appVendorByAppID := make(map[string]string, len(applications))
+	appVendorByAppID := make(map[string]discovery.VendorEvidence, len(applications))
 	for _, app := range applications {
 		appID := strings.TrimSpace(app.AppID)
 		if appID == "" {
@@ -1378,13 +1885,9 @@ func normalizeEntraDiscovery(signIns []SignInEvent, grants []OAuth2PermissionGra
 			name = appID
 		}
 		appDisplayByAppID[appID] = name
-
-		vendorName := strings.TrimSpace(app.VerifiedPublisher.DisplayName)
-		if vendorName == "" {
-			vendorName = discovery.VendorLabelFromDomain(app.PublisherDomain)
-		}
-		if vendorName != "" {
-			appVendorByAppID[appID] = vendorName
+		appVendorByAppID[appID] = discovery.VendorEvidence{
+			VerifiedPublisher: strings.TrimSpace(app.VerifiedPublisher.DisplayName),
+			PublisherDomain:   strings.TrimSpace(app.PublisherDomain),
 		}
 	}
 
@@ -1427,10 +1930,11 @@ func normalizeEntraDiscovery(signIns []SignInEvent, grants []OAuth2PermissionGra
 		}
 
 		observedAt := graphObservedAtOrNow(signIn.CreatedDateTimeRaw, now)
-		sourceVendorName := appVendorByAppID[sourceAppID]
-		if sourceVendorName == "" {
-			sourceVendorName = servicePrincipalVendorByAppID[sourceAppID]
-		}
+		vendorEvidence := appVendorByAppID[sourceAppID]
+		vendorEvidence.PublisherName = servicePrincipalVendorByAppID[sourceAppID]
+		vendorEvidence.AppID = sourceAppID
+		vendorEvidence.AppName = sourceAppName
+		sourceVendorName := discovery.ResolveVendorName(vendorEvidence)
 
 		metadata := discovery.BuildMetadata(discovery.CanonicalInput{
 			SourceKind:       "entra",
@@ -1468,11 +1972,11 @@ func normalizeEntraDiscovery(signIns []SignInEvent, grants []OAuth2PermissionGra
 			SourceAppDomain:  metadata.Domain,
 			SourceVendorName: metadata.VendorName,
 			ActorExternalID:  strings.TrimSpace(signIn.UserID),
-			ActorEmail:       normalizeEmail(strings.TrimSpace(signIn.UserPrincipalName)),
+			ActorEmail:       matching.NormalizeEmail(signIn.UserPrincipalName),
 			ActorDisplayName: strings.TrimSpace(signIn.UserDisplayName),
 			ObservedAt:       observedAt,
 			Scopes:           nil,
-			RawJSON:          registry.NormalizeJSON(signIn.RawJSON),
+			RawJSON:          registry.NormalizeRawJSON(signIn.RawJSON),
 		})
 	}
 
@@ -1502,13 +2006,14 @@ func normalizeEntraDiscovery(signIns []SignInEvent, grants []OAuth2PermissionGra
 
 		observedAt := graphObservedAtOrNow(grant.CreatedDateTimeRaw, now)
 		scopes := discovery.NormalizeScopes(strings.Fields(strings.ReplaceAll(grant.Scope, ",", " ")))
-		sourceVendorName := appVendorByAppID[entraAppID]
-		if sourceVendorName == "" {
-			sourceVendorName = strings.TrimSpace(servicePrincipal.PublisherName)
-		}
-		if sourceVendorName == "" {
-			sourceVendorName = servicePrincipalVendorByAppID[entraAppID]
+		vendorEvidence := appVendorByAppID[entraAppID]
+		vendorEvidence.PublisherName = strings.TrimSpace(servicePrincipal.PublisherName)
+		if vendorEvidence.PublisherName == "" {
+			vendorEvidence.PublisherName = servicePrincipalVendorByAppID[entraAppID]
 		}
+		vendorEvidence.AppID = sourceAppID
+		vendorEvidence.AppName = sourceAppName
+		sourceVendorName := discovery.ResolveVendorName(vendorEvidence)
 
 		metadata := discovery.BuildMetadata(discovery.CanonicalInput{
 			SourceKind:       "entra",
@@ -1550,7 +2055,7 @@ func normalizeEntraDiscovery(signIns []SignInEvent, grants []OAuth2PermissionGra
 			ActorDisplayName: strings.TrimSpace(grant.PrincipalID),
 			ObservedAt:       observedAt,
 			Scopes:           scopes,
-			RawJSON:          registry.NormalizeJSON(grant.RawJSON),
+			RawJSON:          registry.NormalizeRawJSON(grant.RawJSON),
 		})
 	}
 
@@ -1562,6 +2067,10 @@ func normalizeEntraDiscovery(signIns []SignInEvent, grants []OAuth2PermissionGra
 }
 
 func (i *EntraIntegration) writeDiscoveryRows(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, sources []normalizedDiscoverySource, events []normalizedDiscoveryEvent) error {
+	ingestedTotal := metrics.DiscoveryEventsIngestedTotal
+	if _, backfill := discovery.BackfillSince(ctx); backfill {
+		ingestedTotal = metrics.DiscoveryBackfillEventsIngestedTotal
+	}
 	total := len(sources) + len(events)
 	report(registry.Event{
 		Source:  "entra",
@@ -1622,6 +2131,7 @@ func (i *EntraIntegration) writeDiscoveryRows(ctx context.Context, q *gen.Querie
 		displayNames := make([]string, 0, len(appMeta))
 		primaryDomains := make([]string, 0, len(appMeta))
 		vendorNames := make([]string, 0, len(appMeta))
+		categories := make([]string, 0, len(appMeta))
 		firstSeenAts := make([]pgtype.Timestamptz, 0, len(appMeta))
 		lastSeenAts := make([]pgtype.Timestamptz, 0, len(appMeta))
 		for key, meta := range appMeta {
@@ -1629,6 +2139,7 @@ func (i *EntraIntegration) writeDiscoveryRows(ctx context.Context, q *gen.Querie
 			displayNames = append(displayNames, meta.DisplayName)
 			primaryDomains = append(primaryDomains, meta.Domain)
 			vendorNames = append(vendorNames, meta.VendorName)
+			categories = append(categories, meta.Category)
 			firstSeenAt := firstSeenByKey[key]
 			lastSeenAt := lastSeenByKey[key]
 			firstSeenAts = append(firstSeenAts, registry.PgTimestamptzPtr(&firstSeenAt))
@@ -1639,6 +2150,7 @@ func (i *EntraIntegration) writeDiscoveryRows(ctx context.Context, q *gen.Querie
 			DisplayNames:   displayNames,
 			PrimaryDomains: primaryDomains,
 			VendorNames:    vendorNames,
+			Categories:     categories,
 			FirstSeenAts:   firstSeenAts,
 			LastSeenAts:    lastSeenAts,
 		}); err != nil {
@@ -1708,10 +2220,10 @@ func (i *EntraIntegration) writeDiscoveryRows(ctx context.Context, q *gen.Querie
 			actorDisplayNames = append(actorDisplayNames, event.ActorDisplayName)
 			observedAts = append(observedAts, registry.PgTimestamptzPtr(&event.ObservedAt))
 			scopesJSONs = append(scopesJSONs, discovery.ScopesJSON(event.Scopes))
-			rawJSONs = append(rawJSONs, registry.NormalizeJSON(event.RawJSON))
+			rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(event.RawJSON))
 			ingestedBySignal[event.SignalKind]++
 		}
-		if _, err := q.UpsertSaaSAppEventsBulkBySource(ctx, gen.UpsertSaaSAppEventsBulkBySourceParams{
+		if _, err := registry.UpsertDiscoveryEvents(ctx, q, gen.UpsertSaaSAppEventsBulkBySourceParams{
 			SourceKind:        "entra",
 			SourceName:        i.tenantID,
 			SeenInRunID:       runID,
@@ -1731,7 +2243,7 @@ func (i *EntraIntegration) writeDiscoveryRows(ctx context.Context, q *gen.Querie
 			return fmt.Errorf("upsert saas app events: %w", err)
 		}
 		for signalKind, count := range ingestedBySignal {
-			metrics.DiscoveryEventsIngestedTotal.WithLabelValues("entra", signalKind).Add(float64(count))
+			ingestedTotal.WithLabelValues("entra", signalKind).Add(float64(count))
 		}
 		written += len(events)
 		report(registry.Event{
@@ -1756,28 +2268,26 @@ func (i *EntraIntegration) writeDiscoveryRows(ctx context.Context, q *gen.Querie
 }
 
 func (i *EntraIntegration) seedEntraAutoBindings(ctx context.Context, q *gen.Queries) error {
-	appIDs, err := q.ListEntraDiscoveryAppIDsWithManagedAssetsBySource(ctx, i.tenantID)
+	if registry.CurrentAutoBindPolicy().Disabled {
+		return nil
+	}
+	candidates, err := q.ListEntraDiscoveryAutoBindCandidatesBySource(ctx, i.tenantID)
 	if err != nil {
 		return fmt.Errorf("list entra discovery auto-bind candidates: %w", err)
 	}
-	for _, appID := range appIDs {
-		if err := q.UpsertSaaSAppBinding(ctx, gen.UpsertSaaSAppBindingParams{
-			SaasAppID:           appID,
-			ConnectorKind:       "entra",
-			ConnectorSourceName: i.tenantID,
-			BindingSource:       "auto",
-			Confidence:          0.8,
-			IsPrimary:           false,
-			CreatedByAuthUserID: pgtype.Int8{},
-		}); err != nil {
-			return fmt.Errorf("upsert entra auto binding for app %d: %w", appID, err)
+	for _, candidate := range candidates {
+		// Candidates matched only by display name score lower than an app id match.
+		match := registry.AutoBindMatchName
+		if candidate.ClientIDMatch {
+			match = registry.AutoBindMatchClientID
 		}
-	}
-	if len(appIDs) > 0 {
-		if _, err := q.RecomputePrimarySaaSAppBindingsForAll(ctx); err != nil {
-			return fmt.Errorf("recompute primary bindings: %w", err)
+		if err := q.UpsertSaaSAppBinding(ctx, registry.AutoBindingParams(candidate.SaasAppID, "entra", i.tenantID, match)); err != nil {
+			return fmt.Errorf("upsert entra auto binding for app %d: %w", candidate.SaasAppID, err)
 		}
 	}
+	if len(candidates) > 0 {
+		registry.RequestPrimaryBindingRecompute(q)
+	}
 	return nil
 }
 
@@ -1889,6 +2399,7 @@ type sanitizedUser struct {
 	OtherMails        []string `json:"other_mails,omitempty"`
 	ProxyAddresses    []string `json:"proxy_addresses,omitempty"`
 	UserType          string   `json:"user_type,omitempty"`
+	IsGuest           bool     `json:"is_guest,omitempty"`
 	AccountEnabled    *bool    `json:"account_enabled,omitempty"`
 	Status            string   `json:"status,omitempty"`
 	// Kept as-is to avoid timezone parsing/format churn until needed.
@@ -9,7 +9,7 @@ import (
 func TestEntraIntegration_SupportsRunMode(t *testing.T) {
 	t.Parallel()
 
-	full := NewEntraIntegration(nil, "tenant", false)
+	full := NewEntraIntegration(nil, "tenant", 1, false)
 	if !full.SupportsRunMode(registry.RunModeFull) {
 		t.Fatalf("full mode should always be supported")
 	}
@@ -17,7 +17,7 @@ func TestEntraIntegration_SupportsRunMode(t *testing.T) {
 		t.Fatalf("discovery mode should be disabled when discovery is not configured")
 	}
 
-	discovery := NewEntraIntegration(nil, "tenant", true)
+	discovery := NewEntraIntegration(nil, "tenant", 1, true)
 	if !discovery.SupportsRunMode(registry.RunModeDiscovery) {
 		t.Fatalf("discovery mode should be supported when discovery is enabled")
 	}
@@ -1,8 +1,25 @@
 package entra
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	gosync "sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
 )
 
 func TestBuildCredentialAuditEventRowsMapsCredentialAuditFields(t *testing.T) {
@@ -86,6 +103,79 @@ func TestBuildCredentialAuditEventRowsSkipsInvalidOrIrrelevantEvents(t *testing.
 	}
 }
 
+func TestDirectoryAuditSinceFiltersSecondRunToNewEvents(t *testing.T) {
+	t.Parallel()
+
+	auditEvent := func(id, at string) map[string]any {
+		return map[string]any{
+			"id":                  id,
+			"category":            "ApplicationManagement",
+			"result":              "success",
+			"activityDisplayName": "Add application password credential",
+			"activityDateTime":    at,
+			"initiatedBy":         map[string]any{"user": map[string]any{"id": "user-1"}},
+			"targetResources":     []map[string]any{{"id": "app-1", "type": "Application"}},
+		}
+	}
+	var mu gosync.Mutex
+	var filters []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token") {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"tkn","expires_in":3600,"token_type":"Bearer"}`))
+			return
+		}
+		filter := r.URL.Query().Get("$filter")
+		mu.Lock()
+		filters = append(filters, filter)
+		mu.Unlock()
+		events := []map[string]any{auditEvent("event-2", "2026-02-01T11:00:00Z"), auditEvent("event-1", "2026-02-01T10:00:00Z")}
+		if filter != "" {
+			events = []map[string]any{auditEvent("event-3", "2026-02-01T12:00:00Z")}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"value": events})
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewWithOptions("tenant", "client", "secret", Options{
+		AuthorityBaseURL: srv.URL,
+		GraphBaseURL:     srv.URL + "/graph/v1.0",
+	})
+	if err != nil {
+		t.Fatalf("NewWithOptions: %v", err)
+	}
+
+	// The watermark is the newest event the previous run stored.
+	var watermark pgtype.Timestamptz
+	run := func() []credentialAuditEventUpsertRow {
+		events, err := client.ListDirectoryAudits(context.Background(), directoryAuditSince(watermark))
+		if err != nil {
+			t.Fatalf("ListDirectoryAudits: %v", err)
+		}
+		rows := buildCredentialAuditEventRows(events)
+		for _, row := range rows {
+			if !watermark.Valid || row.EventTime.Time.After(watermark.Time) {
+				watermark = row.EventTime
+			}
+		}
+		return rows
+	}
+
+	if rows := run(); len(rows) != 2 {
+		t.Fatalf("first run rows = %d, want 2", len(rows))
+	}
+	if rows := run(); len(rows) != 1 || rows[0].EventExternalID != "event-3" {
+		t.Fatalf("second run rows = %+v, want only event-3", rows)
+	}
+
+	firstRunMax := time.Date(2026, time.February, 1, 11, 0, 0, 0, time.UTC)
+	want := "activityDateTime ge " + firstRunMax.Add(-directoryAuditWatermarkSkew).Format(time.RFC3339)
+	if len(filters) != 2 || filters[0] != "" || filters[1] != want {
+		t.Fatalf("filters = %q, want no filter then %q", filters, want)
+	}
+}
+
 func TestExtractCredentialExternalIDNestedJSON(t *testing.T) {
 	t.Parallel()
 
@@ -117,3 +207,474 @@ func TestGraphObservedAtOrNowUsesValidity(t *testing.T) {
 		t.Fatalf("valid timestamp parsed as %s want %s", valid.Format(time.RFC3339Nano), want.Format(time.RFC3339Nano))
 	}
 }
+
+func TestBuildEntraRoleEntitlementRowsDistinguishesEligibleFromPermanent(t *testing.T) {
+	t.Parallel()
+
+	globalAdmin := &RoleDefinition{ID: "62e90394-69f5-4237-9190-012177145e10", DisplayName: "Global Administrator"}
+	active := []DirectoryRoleAssignment{
+		{
+			ID:               "assign-1",
+			PrincipalID:      "user-standing",
+			RoleDefinitionID: globalAdmin.ID,
+			DirectoryScopeID: "/",
+			AssignmentType:   "Assigned",
+			MemberType:       "Direct",
+			StartDateTimeRaw: "2025-01-01T00:00:00Z",
+			RoleDefinition:   globalAdmin,
+			Principal:        &RolePrincipal{ID: "user-standing", ODataType: "#microsoft.graph.user"},
+		},
+	}
+	eligible := []DirectoryRoleAssignment{
+		{
+			ID:               "elig-1",
+			PrincipalID:      "user-jit",
+			RoleDefinitionID: globalAdmin.ID,
+			DirectoryScopeID: "/",
+			MemberType:       "Direct",
+			StartDateTimeRaw: "2026-01-01T00:00:00Z",
+			EndDateTimeRaw:   "2026-07-01T00:00:00Z",
+			RoleDefinition:   globalAdmin,
+		},
+	}
+
+	rows := buildEntraRoleEntitlementRows(active, eligible, true, nil)
+	if len(rows) != 2 {
+		t.Fatalf("len(rows)=%d want 2", len(rows))
+	}
+
+	standing, jit := rows[0], rows[1]
+	if standing.AppUserExternalID != "user-standing" || standing.AssignmentState != entraAssignmentStatePermanent {
+		t.Fatalf("standing row = %+v, want permanent", standing)
+	}
+	if jit.AppUserExternalID != "user-jit" || jit.AssignmentState != entraAssignmentStateEligible {
+		t.Fatalf("jit row = %+v, want eligible", jit)
+	}
+	for _, row := range rows {
+		if row.Kind != "entra_directory_role" || row.Resource != "entra_directory_role:"+globalAdmin.ID || row.Permission != "member" {
+			t.Fatalf("unexpected role mapping %+v", row)
+		}
+	}
+
+	var raw struct {
+		RoleName         string            `json:"role_name"`
+		AssignmentState  string            `json:"assignment_state"`
+		ActivationWindow map[string]string `json:"activation_window"`
+	}
+	if err := json.Unmarshal(jit.RawJSON, &raw); err != nil {
+		t.Fatalf("unmarshal raw json: %v", err)
+	}
+	if raw.RoleName != "Global Administrator" || raw.AssignmentState != entraAssignmentStateEligible {
+		t.Fatalf("raw = %+v", raw)
+	}
+	if raw.ActivationWindow["end_date_time"] != "2026-07-01T00:00:00Z" {
+		t.Fatalf("activation window = %v", raw.ActivationWindow)
+	}
+}
+
+func TestBuildEntraRoleEntitlementRowsKeepsStrongestState(t *testing.T) {
+	t.Parallel()
+
+	role := &RoleDefinition{ID: "role-1", DisplayName: "Privileged Role Administrator"}
+	active := []DirectoryRoleAssignment{
+		{PrincipalID: "user-1", RoleDefinitionID: role.ID, DirectoryScopeID: "/", AssignmentType: "Activated", EndDateTimeRaw: "2026-02-07T20:00:00Z", RoleDefinition: role},
+		{PrincipalID: "sp-object-1", RoleDefinitionID: role.ID, DirectoryScopeID: "/administrativeUnits/au-1", AssignmentType: "Assigned", RoleDefinition: role},
+	}
+	eligible := []DirectoryRoleAssignment{
+		{PrincipalID: "user-1", RoleDefinitionID: role.ID, DirectoryScopeID: "/", EndDateTimeRaw: "2027-01-01T00:00:00Z", RoleDefinition: role},
+	}
+
+	rows := buildEntraRoleEntitlementRows(active, eligible, true, []ServicePrincipal{{ID: "sp-object-1"}})
+	if len(rows) != 2 {
+		t.Fatalf("len(rows)=%d want 2", len(rows))
+	}
+	if rows[0].AppUserExternalID != "user-1" || rows[0].AssignmentState != entraAssignmentStateActive {
+		t.Fatalf("activated row = %+v, want active", rows[0])
+	}
+	var raw struct {
+		EligibilityWindow map[string]string `json:"eligibility_window"`
+	}
+	if err := json.Unmarshal(rows[0].RawJSON, &raw); err != nil {
+		t.Fatalf("unmarshal raw json: %v", err)
+	}
+	if raw.EligibilityWindow["end_date_time"] != "2027-01-01T00:00:00Z" {
+		t.Fatalf("eligibility window = %v", raw.EligibilityWindow)
+	}
+	if rows[1].AppUserExternalID != "sp:sp-object-1" || rows[1].Permission != "/administrativeUnits/au-1" || rows[1].AssignmentState != entraAssignmentStatePermanent {
+		t.Fatalf("service principal row = %+v", rows[1])
+	}
+}
+
+func TestBuildEntraAssetAndCredentialRowsFlagsOrphans(t *testing.T) {
+	t.Parallel()
+
+	const tenantID = "11111111-1111-1111-1111-111111111111"
+	applications := []Application{
+		{ID: "app-linked", AppID: "client-linked", DisplayName: "Linked"},
+		{ID: "app-stale", AppID: "client-stale", DisplayName: "Stale registration"},
+	}
+	servicePrincipals := []ServicePrincipal{
+		{ID: "sp-linked", AppID: "CLIENT-LINKED", ServicePrincipalType: "Application", AppOwnerOrganizationID: tenantID},
+		{ID: "sp-missing-app", AppID: "client-deleted", ServicePrincipalType: "Application", AppOwnerOrganizationID: strings.ToUpper(tenantID)},
+		{ID: "sp-third-party", AppID: "client-vendor", ServicePrincipalType: "Application", AppOwnerOrganizationID: "22222222-2222-2222-2222-222222222222"},
+		{ID: "sp-managed-identity", AppID: "client-mi", ServicePrincipalType: "ManagedIdentity", AppOwnerOrganizationID: tenantID},
+	}
+
+	assetRows, _ := buildEntraAssetAndCredentialRows(tenantID, applications, servicePrincipals)
+	linkage := map[string]map[string]any{}
+	for _, row := range assetRows {
+		var raw map[string]any
+		if err := json.Unmarshal(row.RawJSON, &raw); err != nil {
+			t.Fatalf("unmarshal %s raw json: %v", row.ExternalID, err)
+		}
+		linkage[row.ExternalID] = raw
+	}
+
+	want := map[string][3]string{
+		// external id: service_principal_id, application_id, orphan
+		"app-linked":          {"sp-linked", "", ""},
+		"app-stale":           {"", "", OrphanApplicationWithoutServicePrincipal},
+		"sp-linked":           {"", "app-linked", ""},
+		"sp-missing-app":      {"", "", OrphanServicePrincipalWithoutApplication},
+		"sp-third-party":      {"", "", ""},
+		"sp-managed-identity": {"", "", ""},
+	}
+	for externalID, fields := range want {
+		raw, ok := linkage[externalID]
+		if !ok {
+			t.Fatalf("no asset row for %s", externalID)
+		}
+		got := [3]string{}
+		for idx, key := range []string{"service_principal_id", "application_id", "orphan"} {
+			got[idx], _ = raw[key].(string)
+		}
+		if got != fields {
+			t.Fatalf("%s linkage = %v, want %v", externalID, got, fields)
+		}
+	}
+}
+
+func TestCollectAppAssetOwnersParallelMatchesSerial(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token") {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"tkn","expires_in":3600,"token_type":"Bearer"}`))
+			return
+		}
+		if !strings.HasSuffix(r.URL.Path, "/owners") {
+			http.NotFound(w, r)
+			return
+		}
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		assetID := parts[len(parts)-2]
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"value": []map[string]any{
+				{"@odata.type": "#microsoft.graph.user", "id": "owner-" + assetID, "displayName": "Owner " + assetID},
+				{"@odata.type": "#microsoft.graph.servicePrincipal", "id": "sp-owner-" + assetID},
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewWithOptions("tenant", "client", "secret", Options{
+		AuthorityBaseURL: srv.URL,
+		GraphBaseURL:     srv.URL + "/graph/v1.0",
+	})
+	if err != nil {
+		t.Fatalf("NewWithOptions: %v", err)
+	}
+
+	var applications []Application
+	var servicePrincipals []ServicePrincipal
+	for n := range 12 {
+		applications = append(applications, Application{ID: fmt.Sprintf("app-%02d", n)})
+		servicePrincipals = append(servicePrincipals, ServicePrincipal{ID: fmt.Sprintf("sp-%02d", n)})
+	}
+	applications = append(applications, Application{ID: " "})
+	totalAssets := int64(len(applications) + len(servicePrincipals))
+
+	collect := func(workers int) ([]appAssetOwnerUpsertRow, []registry.Event) {
+		var mu gosync.Mutex
+		var events []registry.Event
+		report := func(event registry.Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, event)
+		}
+		integration := NewEntraIntegration(client, "tenant", workers, false)
+		rows, err := integration.collectAppAssetOwners(context.Background(), registry.NewWarningReporter(report), registry.NewExpiryHolds(), applications, servicePrincipals)
+		if err != nil {
+			t.Fatalf("collectAppAssetOwners(workers=%d) error = %v", workers, err)
+		}
+		return rows, events
+	}
+
+	serialRows, serialEvents := collect(1)
+	parallelRows, parallelEvents := collect(8)
+
+	if len(serialRows) != 48 {
+		t.Fatalf("serial rows = %d, want 48", len(serialRows))
+	}
+	if !reflect.DeepEqual(serialRows, parallelRows) {
+		t.Fatalf("parallel rows differ from serial rows")
+	}
+	if serialRows[0].AssetExternalID != "app-00" || serialRows[len(serialRows)-1].AssetExternalID != "sp-11" {
+		t.Fatalf("rows not in asset order: first=%s last=%s", serialRows[0].AssetExternalID, serialRows[len(serialRows)-1].AssetExternalID)
+	}
+
+	for _, events := range [][]registry.Event{serialEvents, parallelEvents} {
+		if len(events) != 25 {
+			t.Fatalf("progress events = %d, want 25", len(events))
+		}
+		var maxCurrent int64
+		for _, event := range events {
+			if event.Total != totalAssets {
+				t.Fatalf("event total = %d, want %d", event.Total, totalAssets)
+			}
+			maxCurrent = max(maxCurrent, event.Current)
+		}
+		if maxCurrent != totalAssets {
+			t.Fatalf("final progress = %d, want %d", maxCurrent, totalAssets)
+		}
+	}
+}
+
+func TestCollectAppAssetOwnersKeepsPartialOwnersAndHoldsExpiry(t *testing.T) {
+	t.Parallel()
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token") {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"tkn","expires_in":3600,"token_type":"Bearer"}`))
+			return
+		}
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"code":"Authorization_RequestDenied","message":"denied"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"value":           []map[string]any{{"@odata.type": "#microsoft.graph.user", "id": "owner-1"}},
+			"@odata.nextLink": srv.URL + r.URL.Path + "?page=2",
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewWithOptions("tenant", "client", "secret", Options{
+		AuthorityBaseURL: srv.URL,
+		GraphBaseURL:     srv.URL + "/graph/v1.0",
+	})
+	if err != nil {
+		t.Fatalf("NewWithOptions: %v", err)
+	}
+
+	warnings := registry.NewWarningReporter(nil)
+	holds := registry.NewExpiryHolds()
+	integration := NewEntraIntegration(client, "tenant", 1, false)
+	rows, err := integration.collectAppAssetOwners(context.Background(), warnings, holds, []Application{{ID: "app-1"}}, nil)
+	if err != nil {
+		t.Fatalf("collectAppAssetOwners() error = %v, want the partial owners kept", err)
+	}
+	if len(rows) != 1 || rows[0].OwnerExternalID != "owner-1" {
+		t.Fatalf("rows = %+v, want the owner from the first page", rows)
+	}
+	if len(warnings.Warnings()) != 1 {
+		t.Fatalf("warnings = %+v, want one for the incomplete owner listing", warnings.Warnings())
+	}
+	// Finalize must not expire the owners that were on the page that failed.
+	if holds.Empty() {
+		t.Fatalf("expiry holds are empty, want the asset's owners held")
+	}
+}
+
+// autoBindDB serves Entra auto-bind candidates and records the bindings upserted for them.
+type autoBindDB struct {
+	candidates []gen.ListEntraDiscoveryAutoBindCandidatesBySourceRow
+	bound      []int64
+}
+
+func (db *autoBindDB) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	if strings.Contains(sql, "-- name: UpsertSaaSAppBinding ") {
+		db.bound = append(db.bound, args[0].(int64))
+	}
+	return pgconn.CommandTag{}, nil
+}
+
+func (db *autoBindDB) Query(_ context.Context, sql string, _ ...interface{}) (pgx.Rows, error) {
+	if !strings.Contains(sql, "-- name: ListEntraDiscoveryAutoBindCandidatesBySource ") {
+		return nil, fmt.Errorf("unexpected Query: %s", strings.SplitN(sql, "\n", 2)[0])
+	}
+	return &autoBindCandidateRows{rows: db.candidates, idx: -1}, nil
+}
+
+func (db *autoBindDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	panic("unexpected QueryRow call")
+}
+
+type autoBindCandidateRows struct {
+	rows []gen.ListEntraDiscoveryAutoBindCandidatesBySourceRow
+	idx  int
+}
+
+func (r *autoBindCandidateRows) Close()                                       {}
+func (r *autoBindCandidateRows) Err() error                                   { return nil }
+func (r *autoBindCandidateRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *autoBindCandidateRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *autoBindCandidateRows) Values() ([]any, error)                       { return nil, nil }
+func (r *autoBindCandidateRows) RawValues() [][]byte                          { return nil }
+func (r *autoBindCandidateRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *autoBindCandidateRows) Next() bool {
+	r.idx++
+	return r.idx < len(r.rows)
+}
+
+func (r *autoBindCandidateRows) Scan(dest ...any) error {
+	*(dest[0].(*int64)) = r.rows[r.idx].SaasAppID
+	*(dest[1].(*bool)) = r.rows[r.idx].ClientIDMatch
+	return nil
+}
+
+// Not parallel: the auto-bind policy is process-wide.
+func TestSeedEntraAutoBindingsRespectsDisabledPolicy(t *testing.T) {
+	t.Cleanup(func() { registry.SetAutoBindPolicy(registry.DefaultAutoBindPolicy()) })
+	candidates := []gen.ListEntraDiscoveryAutoBindCandidatesBySourceRow{
+		{SaasAppID: 11, ClientIDMatch: true},
+		{SaasAppID: 12},
+	}
+	integration := NewEntraIntegration(nil, "tenant", 1, false)
+
+	disabled := registry.DefaultAutoBindPolicy()
+	disabled.Disabled = true
+	registry.SetAutoBindPolicy(disabled)
+	db := &autoBindDB{candidates: candidates}
+	if err := integration.seedEntraAutoBindings(context.Background(), gen.New(db)); err != nil {
+		t.Fatalf("seedEntraAutoBindings() error = %v", err)
+	}
+	if len(db.bound) != 0 {
+		t.Fatalf("bound apps = %v with auto-binding disabled, want none", db.bound)
+	}
+
+	registry.SetAutoBindPolicy(registry.DefaultAutoBindPolicy())
+	db = &autoBindDB{candidates: candidates}
+	if err := integration.seedEntraAutoBindings(context.Background(), gen.New(db)); err != nil {
+		t.Fatalf("seedEntraAutoBindings() error = %v", err)
+	}
+	if !reflect.DeepEqual(db.bound, []int64{11, 12}) {
+		t.Fatalf("bound apps = %v with auto-binding enabled, want [11 12]", db.bound)
+	}
+}
+
+func TestEntraIntegrationTestConnection(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		roles       []string
+		wantMissing []string
+	}{
+		{name: "enable blocked on missing permission", roles: []string{"User.Read.All", "Group.Read.All"}, wantMissing: []string{"Application.Read.All", "AuditLog.Read.All", "RoleManagement.Read.Directory"}},
+		{name: "enable allowed with sufficient permissions", roles: []string{"Directory.Read.All", "AuditLog.Read.All", "RoleManagement.Read.All"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			claims, err := json.Marshal(map[string]any{"roles": tc.roles})
+			if err != nil {
+				t.Fatalf("marshal claims: %v", err)
+			}
+			accessToken := "header." + base64.RawURLEncoding.EncodeToString(claims) + ".signature"
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token") {
+					http.NotFound(w, r)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]any{"access_token": accessToken, "expires_in": 3600, "token_type": "Bearer"})
+			}))
+			t.Cleanup(srv.Close)
+
+			client, err := NewWithOptions("tenant", "client", "secret", Options{AuthorityBaseURL: srv.URL, GraphBaseURL: srv.URL + "/graph/v1.0"})
+			if err != nil {
+				t.Fatalf("NewWithOptions: %v", err)
+			}
+
+			err = registry.VerifyConnection(context.Background(), NewEntraIntegration(client, "tenant", 1, false))
+			if len(tc.wantMissing) == 0 {
+				if err != nil {
+					t.Fatalf("VerifyConnection() error = %v, want nil", err)
+				}
+				return
+			}
+			var missing *registry.MissingGrantsError
+			if !errors.As(err, &missing) || !reflect.DeepEqual(missing.Missing, tc.wantMissing) {
+				t.Fatalf("VerifyConnection() error = %v, want missing %v", err, tc.wantMissing)
+			}
+		})
+	}
+}
+
+func TestCollectDirectoryRoleEntitlementsHoldsExpiryWithoutPIM(t *testing.T) {
+	t.Parallel()
+
+	var plainFails atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token") {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"tkn","expires_in":3600,"token_type":"Bearer"}`))
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "ScheduleInstances") || plainFails.Load() {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"code":"Authorization_RequestDenied","message":"denied"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"value": []map[string]any{{
+				"id":               "assign-1",
+				"principalId":      "user-1",
+				"roleDefinitionId": "62e90394-69f5-4237-9190-012177145e10",
+				"directoryScopeId": "/",
+			}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewWithOptions("tenant", "client", "secret", Options{
+		AuthorityBaseURL: srv.URL,
+		GraphBaseURL:     srv.URL + "/graph/v1.0",
+	})
+	if err != nil {
+		t.Fatalf("NewWithOptions: %v", err)
+	}
+	integration := NewEntraIntegration(client, "tenant", 1, false)
+
+	warnings := registry.NewWarningReporter(nil)
+	holds := registry.NewExpiryHolds()
+	rows := integration.collectDirectoryRoleEntitlements(context.Background(), warnings, holds, nil)
+	if len(rows) != 1 || rows[0].AppUserExternalID != "user-1" {
+		t.Fatalf("rows = %+v, want the plain role assignment", rows)
+	}
+	// Plain assignments carry no schedule, so they must not be labelled as standing access.
+	if rows[0].AssignmentState != entraAssignmentStateUnknown {
+		t.Fatalf("assignment state = %q, want %q", rows[0].AssignmentState, entraAssignmentStateUnknown)
+	}
+	if len(warnings.Warnings()) != 1 || holds.Empty() {
+		t.Fatalf("warnings = %+v, holds empty = %v, want one warning and role entitlements held", warnings.Warnings(), holds.Empty())
+	}
+
+	plainFails.Store(true)
+	warnings = registry.NewWarningReporter(nil)
+	holds = registry.NewExpiryHolds()
+	rows = integration.collectDirectoryRoleEntitlements(context.Background(), warnings, holds, nil)
+	if rows != nil || len(warnings.Warnings()) != 2 || holds.Empty() {
+		t.Fatalf("rows = %+v, warnings = %+v, holds empty = %v, want no rows, two warnings, and role entitlements held", rows, warnings.Warnings(), holds.Empty())
+	}
+}
@@ -0,0 +1,17 @@
+package entra
+
+import "github.com/open-sspm/open-sspm/internal/connectors/registry"
+
+// entraPrivilegeMapping maps directory role assignments to canonical levels by role name. The
+// tenant-wide roles also match by template ID for assignments fetched without the role
+// definition expanded. Any other directory role can change something, so it is at least write.
+var entraPrivilegeMapping = registry.PrivilegeMapping{
+	{Kind: "entra_directory_role", Role: "global administrator", Level: registry.PrivilegeOwner},
+	{Kind: "entra_directory_role", Role: "company administrator", Level: registry.PrivilegeOwner},
+	{Kind: "entra_directory_role", Role: "privileged role administrator", Level: registry.PrivilegeOwner},
+	{Kind: "entra_directory_role", Role: "62e90394-69f5-4237-9190-012177145e10", Level: registry.PrivilegeOwner},
+	{Kind: "entra_directory_role", Role: "e8611ab8-c189-46e8-94e1-60213ab1f814", Level: registry.PrivilegeOwner},
+	{Kind: "entra_directory_role", Role: "*administrator", Level: registry.PrivilegeAdmin},
+	{Kind: "entra_directory_role", Role: "*reader*", Level: registry.PrivilegeRead},
+	{Kind: "entra_directory_role", Level: registry.PrivilegeWrite},
+}
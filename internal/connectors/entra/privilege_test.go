@@ -0,0 +1,29 @@
+package entra
+
+import (
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+)
+
+func TestEntraPrivilegeMapping(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		role string
+		want registry.PrivilegeLevel
+	}{
+		{role: "Global Administrator", want: registry.PrivilegeOwner},
+		{role: "Privileged Role Administrator", want: registry.PrivilegeOwner},
+		{role: "62e90394-69f5-4237-9190-012177145e10", want: registry.PrivilegeOwner},
+		{role: "User Administrator", want: registry.PrivilegeAdmin},
+		{role: "Security Reader", want: registry.PrivilegeRead},
+		{role: "Global Reader", want: registry.PrivilegeRead},
+		{role: "Application Developer", want: registry.PrivilegeWrite},
+	}
+	for _, tc := range cases {
+		if got := entraPrivilegeMapping.Level("entra_directory_role", "member", tc.role); got != tc.want {
+			t.Fatalf("entraPrivilegeMapping.Level(%q) = %v, want %v", tc.role, got, tc.want)
+		}
+	}
+}
@@ -15,6 +15,9 @@ type Config struct {
 	Enterprise string `json:"enterprise"`
 	// SCIMEnabled toggles whether to use the SCIM API for identity/email resolution.
 	SCIMEnabled bool `json:"scim_enabled"`
+	// SecretScanningEnabled toggles collecting org secret scanning alerts, which needs secret
+	// scanning read access on top of the default scopes.
+	SecretScanningEnabled bool `json:"secret_scanning_enabled"`
 }
 
 // Normalized returns a copy of the config with trimmed whitespace and defaults applied.
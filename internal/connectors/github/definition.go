@@ -75,7 +75,47 @@ func (d *Definition) NewIntegration(cfg any) (registry.Integration, error) {
 	if err != nil {
 		return nil, err
 	}
-	return NewGitHubIntegration(client, c.Org, c.Enterprise, d.workers, c.SCIMEnabled), nil
+	workers := d.workers
+	if c.Workers > 0 {
+		workers = c.Workers
+	}
+	return NewGitHubIntegration(client, c.Org, c.Enterprise, workers, c.SCIMEnabled, c.SecretScanningEnabled), nil
+}
+
+// CredentialKinds documents the credential kinds the integration syncs.
+func (d *Definition) CredentialKinds() []registry.CredentialKindInfo {
+	return []registry.CredentialKindInfo{
+		{
+			Kind:             "github_deploy_key",
+			Label:            "GitHub deploy key",
+			Description:      "An SSH key that grants read or read-write access to a single repository.",
+			RotationGuidance: "Generate a new key pair, add it as a deploy key, update the system that uses it, and delete the old key. Grant write access only when pushing is needed.",
+		},
+		{
+			Kind:             "github_oauth_app_token",
+			Label:            "GitHub OAuth app token",
+			Description:      "A token issued to an OAuth app that a member authorized, seen in organization audit events.",
+			RotationGuidance: "Revoke the authorization when the app is no longer needed. The app must be re-authorized to receive a new token.",
+		},
+		{
+			Kind:             "github_pat_fine_grained",
+			Label:            "GitHub fine-grained PAT",
+			Description:      "A fine-grained personal access token approved to access organization repositories on behalf of a user.",
+			RotationGuidance: "Have the owner regenerate the token with a short expiration and the fewest repositories and permissions. Revoke tokens that are unused or whose owner left.",
+		},
+		{
+			Kind:             "github_pat_request",
+			Label:            "GitHub PAT request",
+			Description:      "A pending request for a fine-grained personal access token to access organization resources.",
+			RotationGuidance: "Review the requested repositories and permissions, then approve or deny it. Nothing needs rotating until it is approved.",
+		},
+		{
+			Kind:             "github_secret_scanning_alert",
+			Label:            "GitHub secret scanning alert",
+			Description:      "A secret that GitHub secret scanning found committed to a repository. The leaked value itself is not stored.",
+			RotationGuidance: "Revoke or rotate the exposed secret at its provider first, then resolve the alert. Removing the commit does not make the secret safe.",
+		},
+	}
 }
 
 type githubMetrics struct{}
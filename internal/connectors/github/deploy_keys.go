@@ -0,0 +1,239 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	gosync "sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+const (
+	githubDeployKeyStage = "list-deploy-keys"
+	// githubDeployKeyCheckpointMaxAge bounds how old a checkpoint may be and still be trusted on
+	// resume; older ones are re-listed so deleted keys do not linger.
+	githubDeployKeyCheckpointMaxAge = 24 * time.Hour
+)
+
+// deployKeyCheckpoints records the deploy keys listed per repository so a failed sync resumes
+// from the repositories it already completed instead of re-listing every one.
+type deployKeyCheckpoints interface {
+	Load(ctx context.Context) (map[string][]DeployKey, error)
+	Save(ctx context.Context, repoName string, keys []DeployKey) error
+	Clear(ctx context.Context) error
+}
+
+// newDeployKeyCheckpoints returns the database-backed checkpoint store for org, or a store that
+// keeps nothing when q is nil.
+func newDeployKeyCheckpoints(q *gen.Queries, org string) deployKeyCheckpoints {
+	if q == nil {
+		return noDeployKeyCheckpoints{}
+	}
+	return &dbDeployKeyCheckpoints{q: q, org: org, now: time.Now}
+}
+
+type noDeployKeyCheckpoints struct{}
+
+func (noDeployKeyCheckpoints) Load(context.Context) (map[string][]DeployKey, error) { return nil, nil }
+func (noDeployKeyCheckpoints) Save(context.Context, string, []DeployKey) error      { return nil }
+func (noDeployKeyCheckpoints) Clear(context.Context) error                          { return nil }
+
+type dbDeployKeyCheckpoints struct {
+	q   *gen.Queries
+	org string
+	now func() time.Time
+}
+
+func (s *dbDeployKeyCheckpoints) Load(ctx context.Context) (map[string][]DeployKey, error) {
+	rows, err := s.q.ListSyncCheckpoints(ctx, gen.ListSyncCheckpointsParams{
+		SourceKind: "github",
+		SourceName: s.org,
+		Stage:      githubDeployKeyStage,
+		Since:      pgtype.Timestamptz{Time: s.now().Add(-githubDeployKeyCheckpointMaxAge), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	completed := make(map[string][]DeployKey, len(rows))
+	for _, row := range rows {
+		var keys []DeployKey
+		if err := json.Unmarshal(row.PayloadJson, &keys); err != nil {
+			// An unreadable checkpoint only costs a re-list of that repository.
+			continue
+		}
+		completed[row.ItemKey] = keys
+	}
+	return completed, nil
+}
+
+func (s *dbDeployKeyCheckpoints) Save(ctx context.Context, repoName string, keys []DeployKey) error {
+	if keys == nil {
+		keys = []DeployKey{}
+	}
+	payload, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	return s.q.UpsertSyncCheckpoint(ctx, gen.UpsertSyncCheckpointParams{
+		SourceKind:  "github",
+		SourceName:  s.org,
+		Stage:       githubDeployKeyStage,
+		ItemKey:     repoName,
+		PayloadJson: payload,
+	})
+}
+
+func (s *dbDeployKeyCheckpoints) Clear(ctx context.Context) error {
+	return s.q.DeleteSyncCheckpoints(ctx, gen.DeleteSyncCheckpointsParams{
+		SourceKind: "github",
+		SourceName: s.org,
+		Stage:      githubDeployKeyStage,
+	})
+}
+
+// githubRepoName is the repository name used for deploy-key lookups, falling back to the full name
+// without the org prefix.
+func githubRepoName(org string, repo Repository) string {
+	repoName := strings.TrimSpace(repo.Name)
+	if repoName == "" {
+		repoName = strings.TrimSpace(strings.TrimPrefix(repo.FullName, org+"/"))
+	}
+	return repoName
+}
+
+// listDeployKeyCredentialRows lists deploy keys for every repository on a bounded worker pool and
+// returns their credential rows in repository order. Repositories with a checkpoint from an
+// earlier, failed run are not listed again, and each newly listed repository is checkpointed.
+func (i *GitHubIntegration) listDeployKeyCredentialRows(ctx context.Context, repositories []Repository, checkpoints deployKeyCheckpoints, report func(registry.Event)) ([]githubCredentialArtifactUpsertRow, error) {
+	total := int64(len(repositories))
+	if total == 0 {
+		return nil, nil
+	}
+
+	completed, err := checkpoints.Load(ctx)
+	if err != nil {
+		slog.Warn("github deploy key checkpoints unavailable; listing all repositories", "org", i.org, "err", err)
+		completed = nil
+	}
+
+	keysByRepo := make([][]DeployKey, len(repositories))
+	pending := make([]int, 0, len(repositories))
+	var done int64
+	resumed := 0
+	for idx, repo := range repositories {
+		repoName := githubRepoName(i.org, repo)
+		if repoName == "" {
+			done++
+			report(registry.Event{Source: "github", Stage: githubDeployKeyStage, Current: done, Total: total, Message: fmt.Sprintf("skipping repository %q (missing name)", repo.FullName)})
+			continue
+		}
+		if keys, ok := completed[repoName]; ok {
+			keysByRepo[idx] = keys
+			resumed++
+			done++
+			continue
+		}
+		pending = append(pending, idx)
+	}
+
+	if resumed > 0 {
+		report(registry.Event{Source: "github", Stage: githubDeployKeyStage, Current: done, Total: total, Message: fmt.Sprintf("resuming: %d/%d repositories already listed", resumed, len(repositories))})
+	} else {
+		report(registry.Event{Source: "github", Stage: githubDeployKeyStage, Current: done, Total: total, Message: fmt.Sprintf("listing deploy keys for %d repositories", len(repositories))})
+	}
+
+	if len(pending) > 0 {
+		type deployKeyResult struct {
+			idx  int
+			keys []DeployKey
+			err  error
+		}
+
+		listCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		jobs := make(chan int, len(pending))
+		results := make(chan deployKeyResult, len(pending))
+
+		workers := min(len(pending), i.workers)
+		if workers < 1 {
+			workers = 1
+		}
+
+		var wg gosync.WaitGroup
+		for j := 0; j < workers; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for idx := range jobs {
+					if listCtx.Err() != nil {
+						return
+					}
+					repoName := githubRepoName(i.org, repositories[idx])
+					keys, err := i.client.ListRepoDeployKeys(listCtx, i.org, repoName)
+					if err != nil {
+						results <- deployKeyResult{idx: idx, err: fmt.Errorf("github deploy key lookup failed for %s: %w", repoName, err)}
+						cancel()
+						continue
+					}
+					// Checkpointing is best effort: losing one only costs a re-list on resume.
+					if err := checkpoints.Save(ctx, repoName, keys); err != nil {
+						slog.Warn("github deploy key checkpoint failed", "org", i.org, "repo", repoName, "err", err)
+					}
+					n := atomic.AddInt64(&done, 1)
+					report(registry.Event{
+						Source:  "github",
+						Stage:   githubDeployKeyStage,
+						Current: n,
+						Total:   total,
+						Message: fmt.Sprintf("deploy keys %d/%d", n, total),
+					})
+					results <- deployKeyResult{idx: idx, keys: keys}
+				}
+			}()
+		}
+
+		for _, idx := range pending {
+			jobs <- idx
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+
+		var firstErr error
+		var firstNonCancelErr error
+		for res := range results {
+			if res.err != nil {
+				if firstErr == nil {
+					firstErr = res.err
+				}
+				if firstNonCancelErr == nil && !errors.Is(res.err, context.Canceled) {
+					firstNonCancelErr = res.err
+				}
+				continue
+			}
+			keysByRepo[res.idx] = res.keys
+		}
+
+		if firstNonCancelErr != nil {
+			firstErr = firstNonCancelErr
+		}
+		if firstErr != nil {
+			return nil, firstErr
+		}
+	}
+
+	rows := make([]githubCredentialArtifactUpsertRow, 0)
+	for idx, repo := range repositories {
+		rows = append(rows, buildGitHubDeployKeyCredentialRows(i.org, repo, keysByRepo[idx])...)
+	}
+	return rows, nil
+}
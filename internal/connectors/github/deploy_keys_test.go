@@ -0,0 +1,196 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	gosync "sync"
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+)
+
+type memoryDeployKeyCheckpoints struct {
+	mu   gosync.Mutex
+	keys map[string][]DeployKey
+}
+
+func (m *memoryDeployKeyCheckpoints) Load(context.Context) (map[string][]DeployKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string][]DeployKey, len(m.keys))
+	for repo, keys := range m.keys {
+		out[repo] = keys
+	}
+	return out, nil
+}
+
+func (m *memoryDeployKeyCheckpoints) Save(_ context.Context, repoName string, keys []DeployKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.keys == nil {
+		m.keys = make(map[string][]DeployKey)
+	}
+	m.keys[repoName] = keys
+	return nil
+}
+
+func (m *memoryDeployKeyCheckpoints) Clear(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.keys = nil
+	return nil
+}
+
+// deployKeyServer serves one deploy key per repository and fails lookups for repositories in
+// failing. It records which repositories were listed.
+func deployKeyServer(t *testing.T, failing map[string]bool) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var mu gosync.Mutex
+	var listed []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repo, ok := strings.CutPrefix(r.URL.Path, "/repos/acme/")
+		repo, isKeys := strings.CutSuffix(repo, "/keys")
+		if !ok || !isKeys {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		listed = append(listed, repo)
+		mu.Unlock()
+		if failing[repo] {
+			http.Error(w, `{"message":"boom"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `[{"id":%d,"key":"ssh-ed25519 AAAA%s","title":"%s deploy","read_only":true,"verified":true,"created_at":"2024-01-02T03:04:05Z"}]`, len(repo), repo, repo)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), listed...)
+	}
+}
+
+func testRepositories(n int) []Repository {
+	repos := make([]Repository, 0, n)
+	for idx := 0; idx < n; idx++ {
+		name := fmt.Sprintf("repo-%02d", idx)
+		repos = append(repos, Repository{ID: int64(idx + 1), Name: name, FullName: "acme/" + name})
+	}
+	return repos
+}
+
+func TestListDeployKeyCredentialRowsConcurrentMatchesSerial(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := deployKeyServer(t, nil)
+	client, err := New(srv.URL, "token")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	repos := testRepositories(25)
+
+	serial, err := NewGitHubIntegration(client, "acme", "", 1, false, false).
+		listDeployKeyCredentialRows(context.Background(), repos, noDeployKeyCheckpoints{}, func(registry.Event) {})
+	if err != nil {
+		t.Fatalf("serial listDeployKeyCredentialRows() error = %v", err)
+	}
+
+	var mu gosync.Mutex
+	var progress []int64
+	concurrent, err := NewGitHubIntegration(client, "acme", "", 8, false, false).
+		listDeployKeyCredentialRows(context.Background(), repos, noDeployKeyCheckpoints{}, func(e registry.Event) {
+			mu.Lock()
+			progress = append(progress, e.Current)
+			mu.Unlock()
+		})
+	if err != nil {
+		t.Fatalf("concurrent listDeployKeyCredentialRows() error = %v", err)
+	}
+
+	if len(serial) != len(repos) {
+		t.Fatalf("serial rows = %d, want %d", len(serial), len(repos))
+	}
+	if !reflect.DeepEqual(serial, concurrent) {
+		t.Fatalf("concurrent rows differ from serial rows")
+	}
+
+	seen := make(map[int64]bool)
+	for _, current := range progress[1:] {
+		if current < 1 || current > int64(len(repos)) || seen[current] {
+			t.Fatalf("progress = %v, want each of 1..%d exactly once", progress, len(repos))
+		}
+		seen[current] = true
+	}
+	if len(seen) != len(repos) {
+		t.Fatalf("progress = %v, want %d completion events", progress, len(repos))
+	}
+}
+
+func TestListDeployKeyCredentialRowsResumesFromCheckpoints(t *testing.T) {
+	t.Parallel()
+
+	repos := testRepositories(10)
+	checkpoints := &memoryDeployKeyCheckpoints{}
+
+	failingSrv, _ := deployKeyServer(t, map[string]bool{"repo-07": true})
+	failingClient, err := New(failingSrv.URL, "token")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	_, err = NewGitHubIntegration(failingClient, "acme", "", 1, false, false).
+		listDeployKeyCredentialRows(context.Background(), repos, checkpoints, func(registry.Event) {})
+	if err == nil || !strings.Contains(err.Error(), "repo-07") {
+		t.Fatalf("first run error = %v, want failure for repo-07", err)
+	}
+	saved, _ := checkpoints.Load(context.Background())
+	if len(saved) != 7 {
+		t.Fatalf("checkpointed repos = %d, want the 7 listed before the failure", len(saved))
+	}
+
+	srv, listed := deployKeyServer(t, nil)
+	client, err := New(srv.URL, "token")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	var mu gosync.Mutex
+	var events []registry.Event
+	resumed, err := NewGitHubIntegration(client, "acme", "", 4, false, false).
+		listDeployKeyCredentialRows(context.Background(), repos, checkpoints, func(e registry.Event) {
+			mu.Lock()
+			events = append(events, e)
+			mu.Unlock()
+		})
+	if err != nil {
+		t.Fatalf("resumed run error = %v", err)
+	}
+
+	got := listed()
+	if len(got) != 3 {
+		t.Fatalf("resumed run listed %v, want only the 3 repos without checkpoints", got)
+	}
+	for _, repo := range got {
+		if _, ok := saved[repo]; ok {
+			t.Fatalf("resumed run re-listed checkpointed repo %s", repo)
+		}
+	}
+	if events[0].Current != 7 || events[0].Total != 10 {
+		t.Fatalf("first progress event = %d/%d, want 7/10", events[0].Current, events[0].Total)
+	}
+
+	fresh, err := NewGitHubIntegration(client, "acme", "", 4, false, false).
+		listDeployKeyCredentialRows(context.Background(), repos, noDeployKeyCheckpoints{}, func(registry.Event) {})
+	if err != nil {
+		t.Fatalf("fresh run error = %v", err)
+	}
+	if !reflect.DeepEqual(resumed, fresh) {
+		t.Fatalf("resumed rows differ from a fresh listing")
+	}
+}
@@ -12,20 +12,26 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/httpclient"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
 )
 
 const defaultTimeout = 120 * time.Second
 const maxRetries = 3
 
-const maxRetryAfter = 30 * time.Second
-
 var ErrDatasetUnavailable = errors.New("github dataset unavailable")
 
 type Client struct {
 	BaseURL string
 	Token   string
 	HTTP    *http.Client
+
+	scopesMu    sync.Mutex
+	scopes      []string
+	scopesKnown bool
 }
 
 type Member struct {
@@ -34,8 +40,10 @@ type Member struct {
 	Role        string
 	AccountType string
 	Email       string
-	DisplayName string
-	RawJSON     []byte
+	// ProfileEmail is the public profile email when Email was replaced by the SAML/SCIM one.
+	ProfileEmail string
+	DisplayName  string
+	RawJSON      []byte
 }
 
 type Team struct {
@@ -55,6 +63,23 @@ type TeamRepo struct {
 	RawJSON    []byte
 }
 
+// OutsideCollaborator is a user with access to at least one org repository who is not an
+// org member.
+type OutsideCollaborator struct {
+	Login       string
+	ID          int64
+	AccountType string
+	RawJSON     []byte
+}
+
+// RepoCollaborator is a user's direct access to a single repository.
+type RepoCollaborator struct {
+	Login      string
+	ID         int64
+	Permission string
+	RawJSON    []byte
+}
+
 type Repository struct {
 	ID            int64
 	Name          string
@@ -118,6 +143,7 @@ type PersonalAccessTokenRequest struct {
 
 type PersonalAccessToken struct {
 	ID                  int64
+	TokenID             int64
 	Name                string
 	Status              string
 	OwnerLogin          string
@@ -157,6 +183,22 @@ type AuditLogEvent struct {
 	RawJSON           []byte `json:"-"`
 }
 
+// SecretScanningAlert is an org secret scanning alert. The leaked secret value the API returns
+// is deliberately not kept.
+type SecretScanningAlert struct {
+	Number                int64
+	State                 string
+	Resolution            string
+	SecretType            string
+	SecretTypeDisplayName string
+	RepositoryFullName    string
+	ResolvedByLogin       string
+	HTMLURL               string
+	CreatedAtRaw          string
+	UpdatedAtRaw          string
+	ResolvedAtRaw         string
+}
+
 type repoPermissions struct {
 	Admin    bool `json:"admin"`
 	Maintain bool `json:"maintain"`
@@ -180,7 +222,7 @@ func New(baseURL, token string) (*Client, error) {
 	return &Client{
 		BaseURL: base,
 		Token:   token,
-		HTTP:    &http.Client{Timeout: defaultTimeout},
+		HTTP:    httpclient.NewForKind("github", defaultTimeout),
 	}, nil
 }
 
@@ -189,7 +231,7 @@ func (c *Client) httpClient() (*http.Client, error) {
 		return nil, errors.New("github base URL and token are required")
 	}
 	if c.HTTP == nil {
-		return &http.Client{Timeout: defaultTimeout}, nil
+		return httpclient.NewForKind("github", defaultTimeout), nil
 	}
 	if c.HTTP.Timeout > 0 {
 		return c.HTTP, nil
@@ -278,14 +320,16 @@ func (c *Client) ListTeams(ctx context.Context, org string) ([]Team, error) {
 	return out, nil
 }
 
-func (c *Client) ListTeamMembers(ctx context.Context, org, teamSlug string) ([]TeamMember, error) {
+func (c *Client) ListTeamMembers(ctx context.Context, org, teamSlug string, opts ...registry.ListOption) ([]TeamMember, error) {
 	url := fmt.Sprintf("%s/orgs/%s/teams/%s/members?per_page=100", c.BaseURL, org, teamSlug)
 	var out []TeamMember
+	pages := 0
 	for url != "" {
 		rawItems, next, err := c.getRawPage(ctx, url)
 		if err != nil {
-			return nil, err
+			return registry.PageFailure(out, pages, err, opts)
 		}
+		pages++
 		for _, raw := range rawItems {
 			var u struct {
 				Login string `json:"login"`
@@ -301,14 +345,16 @@ func (c *Client) ListTeamMembers(ctx context.Context, org, teamSlug string) ([]T
 	return out, nil
 }
 
-func (c *Client) ListTeamRepos(ctx context.Context, org, teamSlug string) ([]TeamRepo, error) {
+func (c *Client) ListTeamRepos(ctx context.Context, org, teamSlug string, opts ...registry.ListOption) ([]TeamRepo, error) {
 	url := fmt.Sprintf("%s/orgs/%s/teams/%s/repos?per_page=100", c.BaseURL, org, teamSlug)
 	var out []TeamRepo
+	pages := 0
 	for url != "" {
 		rawItems, next, err := c.getRawPage(ctx, url)
 		if err != nil {
-			return nil, err
+			return registry.PageFailure(out, pages, err, opts)
 		}
+		pages++
 		for _, raw := range rawItems {
 			var r struct {
 				FullName    string          `json:"full_name"`
@@ -372,6 +418,63 @@ func (c *Client) ListOrgRepos(ctx context.Context, org string) ([]Repository, er
 	return out, nil
 }
 
+// ListOrgOutsideCollaborators lists users with repository access who are not org members.
+// Only org owners can read this endpoint.
+func (c *Client) ListOrgOutsideCollaborators(ctx context.Context, org string) ([]OutsideCollaborator, error) {
+	url := fmt.Sprintf("%s/orgs/%s/outside_collaborators?per_page=100", c.BaseURL, org)
+	var out []OutsideCollaborator
+	for url != "" {
+		rawItems, next, err := c.getRawPage(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range rawItems {
+			var u struct {
+				Login string `json:"login"`
+				ID    int64  `json:"id"`
+				Type  string `json:"type"`
+			}
+			if err := json.Unmarshal(raw, &u); err != nil {
+				return nil, err
+			}
+			out = append(out, OutsideCollaborator{Login: u.Login, ID: u.ID, AccountType: u.Type, RawJSON: raw})
+		}
+		url = next
+	}
+	return out, nil
+}
+
+// ListRepoOutsideCollaborators lists the outside collaborators of a repository with their
+// effective permission on it.
+func (c *Client) ListRepoOutsideCollaborators(ctx context.Context, owner, repo string) ([]RepoCollaborator, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/collaborators?affiliation=outside&per_page=100", c.BaseURL, owner, repo)
+	var out []RepoCollaborator
+	for url != "" {
+		rawItems, next, err := c.getRawPage(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range rawItems {
+			var u struct {
+				Login       string          `json:"login"`
+				ID          int64           `json:"id"`
+				Permissions repoPermissions `json:"permissions"`
+			}
+			if err := json.Unmarshal(raw, &u); err != nil {
+				return nil, err
+			}
+			out = append(out, RepoCollaborator{
+				Login:      u.Login,
+				ID:         u.ID,
+				Permission: highestPermission(u.Permissions),
+				RawJSON:    raw,
+			})
+		}
+		url = next
+	}
+	return out, nil
+}
+
 func (c *Client) ListRepoDeployKeys(ctx context.Context, org, repo string) ([]DeployKey, error) {
 	url := fmt.Sprintf("%s/repos/%s/%s/keys?per_page=100", c.BaseURL, org, repo)
 	var out []DeployKey
@@ -411,6 +514,35 @@ func (c *Client) ListRepoDeployKeys(ctx context.Context, org, repo string) ([]De
 	return out, nil
 }
 
+// DeleteRepoDeployKey removes a deploy key from a repository. A key that is already gone is
+// treated as deleted.
+func (c *Client) DeleteRepoDeployKey(ctx context.Context, owner, repo string, keyID int64) error {
+	url := fmt.Sprintf("%s/repos/%s/%s/keys/%d", c.BaseURL, owner, repo, keyID)
+	return c.doWrite(ctx, http.MethodDelete, url, nil, "github delete deploy key failed")
+}
+
+// RevokeOrgPersonalAccessToken revokes an organization's access for a fine-grained PAT.
+func (c *Client) RevokeOrgPersonalAccessToken(ctx context.Context, org string, patID int64) error {
+	url := fmt.Sprintf("%s/orgs/%s/personal-access-tokens/%d", c.BaseURL, org, patID)
+	return c.doWrite(ctx, http.MethodPost, url, []byte(`{"action":"revoke"}`), "github revoke personal access token failed")
+}
+
+func (c *Client) doWrite(ctx context.Context, method, url string, body []byte, errPrefix string) error {
+	resp, err := c.doMethodRequest(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodDelete {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return formatGitHubAPIError(errPrefix, url, resp, respBody)
+	}
+	return nil
+}
+
 func (c *Client) ListOrgPersonalAccessTokenRequests(ctx context.Context, org string) ([]PersonalAccessTokenRequest, error) {
 	url := fmt.Sprintf("%s/orgs/%s/personal-access-token-requests?per_page=100", c.BaseURL, org)
 	var out []PersonalAccessTokenRequest
@@ -614,6 +746,73 @@ func (c *Client) ListOrgAuditLog(ctx context.Context, org string) ([]AuditLogEve
 	return out, nil
 }
 
+// ListOrgSecretScanningAlerts lists secret scanning alerts across the org's repositories. It needs
+// secret scanning read access, so a 403/404 is reported as ErrDatasetUnavailable.
+func (c *Client) ListOrgSecretScanningAlerts(ctx context.Context, org string) ([]SecretScanningAlert, error) {
+	url := fmt.Sprintf("%s/orgs/%s/secret-scanning/alerts?per_page=100", c.BaseURL, org)
+	var out []SecretScanningAlert
+
+	for url != "" {
+		resp, err := c.doRequest(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: org secret scanning alerts endpoint unavailable (%s)", ErrDatasetUnavailable, resp.Status)
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, formatGitHubAPIError("github secret scanning alerts api failed", url, resp, body)
+		}
+
+		var items []struct {
+			Number                int64  `json:"number"`
+			State                 string `json:"state"`
+			Resolution            string `json:"resolution"`
+			SecretType            string `json:"secret_type"`
+			SecretTypeDisplayName string `json:"secret_type_display_name"`
+			HTMLURL               string `json:"html_url"`
+			CreatedAt             string `json:"created_at"`
+			UpdatedAt             string `json:"updated_at"`
+			ResolvedAt            string `json:"resolved_at"`
+			ResolvedBy            struct {
+				Login string `json:"login"`
+			} `json:"resolved_by"`
+			Repository struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+		}
+		if err := json.Unmarshal(body, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			out = append(out, SecretScanningAlert{
+				Number:                item.Number,
+				State:                 item.State,
+				Resolution:            item.Resolution,
+				SecretType:            item.SecretType,
+				SecretTypeDisplayName: item.SecretTypeDisplayName,
+				RepositoryFullName:    item.Repository.FullName,
+				ResolvedByLogin:       item.ResolvedBy.Login,
+				HTMLURL:               item.HTMLURL,
+				CreatedAtRaw:          item.CreatedAt,
+				UpdatedAtRaw:          item.UpdatedAt,
+				ResolvedAtRaw:         item.ResolvedAt,
+			})
+		}
+
+		url = parseNextLink(resp.Header.Get("Link"))
+	}
+
+	return out, nil
+}
+
 func parseGitHubPATRequest(raw json.RawMessage) (PersonalAccessTokenRequest, error) {
 	var payload map[string]any
 	if err := json.Unmarshal(raw, &payload); err != nil {
@@ -665,6 +864,7 @@ func parseGitHubPAT(raw json.RawMessage) (PersonalAccessToken, error) {
 
 	return PersonalAccessToken{
 		ID:                  id,
+		TokenID:             asInt64(payload["token_id"]),
 		Name:                firstStringValue(payload["name"], payload["token_name"], payload["token_display_name"]),
 		Status:              firstStringValue(payload["status"], payload["state"]),
 		OwnerLogin:          firstStringValue(owner["login"], owner["name"], payload["owner"]),
@@ -826,6 +1026,21 @@ func highestPermission(p repoPermissions) string {
 	return ""
 }
 
+// GetOrg checks that the org exists and is readable with the client's token.
+func (c *Client) GetOrg(ctx context.Context, org string) error {
+	url := fmt.Sprintf("%s/orgs/%s", c.BaseURL, org)
+	resp, err := c.doRequest(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return formatGitHubAPIError("github org lookup failed", url, resp, body)
+	}
+	return nil
+}
+
 func (c *Client) getOrgRole(ctx context.Context, org, login string) (string, error) {
 	url := fmt.Sprintf("%s/orgs/%s/memberships/%s", c.BaseURL, org, login)
 	resp, err := c.doRequest(ctx, url)
@@ -1048,6 +1263,7 @@ func (c *Client) doGraphQL(ctx context.Context, query string, variables map[stri
 			}
 			return err
 		}
+		c.recordOAuthScopes(resp)
 
 		body, err = io.ReadAll(resp.Body)
 		resp.Body.Close()
@@ -1103,6 +1319,10 @@ func (c *Client) graphQLEndpoint() string {
 }
 
 func (c *Client) doRequest(ctx context.Context, url string) (*http.Response, error) {
+	return c.doMethodRequest(ctx, http.MethodGet, url, nil)
+}
+
+func (c *Client) doMethodRequest(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
 	httpClient, err := c.httpClient()
 	if err != nil {
 		return nil, err
@@ -1113,12 +1333,19 @@ func (c *Client) doRequest(ctx context.Context, url string) (*http.Response, err
 			return nil, ctx.Err()
 		}
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		var reqBody io.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 		if err != nil {
 			return nil, err
 		}
 		req.Header.Set("Authorization", "Bearer "+c.Token)
 		req.Header.Set("Accept", "application/vnd.github+json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
 		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
 		req.Header.Set("User-Agent", "open-sspm")
 
@@ -1132,6 +1359,7 @@ func (c *Client) doRequest(ctx context.Context, url string) (*http.Response, err
 			}
 			return nil, err
 		}
+		c.recordOAuthScopes(resp)
 		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 			return resp, nil
 		}
@@ -1147,6 +1375,34 @@ func (c *Client) doRequest(ctx context.Context, url string) (*http.Response, err
 	return nil, errors.New("github request failed after retries")
 }
 
+// OAuthScopes returns the scopes GitHub reported for the token on the latest response. ok is
+// false until a response carried X-OAuth-Scopes; fine-grained and GitHub App tokens never send it.
+func (c *Client) OAuthScopes() (scopes []string, ok bool) {
+	c.scopesMu.Lock()
+	defer c.scopesMu.Unlock()
+	return append([]string(nil), c.scopes...), c.scopesKnown
+}
+
+func (c *Client) recordOAuthScopes(resp *http.Response) {
+	values := resp.Header.Values("X-OAuth-Scopes")
+	if len(values) == 0 {
+		return
+	}
+	var scopes []string
+	for _, value := range values {
+		for scope := range strings.SplitSeq(value, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	c.scopesMu.Lock()
+	c.scopes = scopes
+	c.scopesKnown = true
+	c.scopesMu.Unlock()
+}
+
 func formatGitHubAPIError(prefix, reqURL string, resp *http.Response, body []byte) error {
 	message := extractGitHubAPIErrorMessage(body)
 	details := formatGitHubAPIErrorDetails(reqURL, resp)
@@ -1303,6 +1559,7 @@ func retryAfter(resp *http.Response) time.Duration {
 	if v == "" {
 		return 0
 	}
+	maxRetryAfter := httpclient.MaxBackoff("github")
 	if secs, err := strconv.Atoi(v); err == nil {
 		d := time.Duration(secs) * time.Second
 		if d > maxRetryAfter {
@@ -1327,14 +1584,15 @@ func backoffDelay(attempt int) time.Duration {
 	if attempt < 0 {
 		return 0
 	}
+	maxDelay := min(5*time.Second, httpclient.MaxBackoff("github"))
 	d := 200 * time.Millisecond
 	for range attempt {
 		d *= 2
-		if d >= 5*time.Second {
-			return 5 * time.Second
+		if d >= maxDelay {
+			return maxDelay
 		}
 	}
-	return d
+	return min(d, maxDelay)
 }
 
 func sleepWithContext(ctx context.Context, d time.Duration) error {
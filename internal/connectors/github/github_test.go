@@ -7,9 +7,13 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
 )
 
 func TestNewSetsHTTPTimeout(t *testing.T) {
@@ -141,6 +145,18 @@ func TestProgrammaticGovernanceEndpoints(t *testing.T) {
 			w.Header().Set("Content-Type", "application/json")
 			_, _ = w.Write([]byte(`[{"id":1,"name":"repo-one","full_name":"acme/repo-one","private":false,"archived":false,"disabled":false,"default_branch":"main","created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-02T00:00:00Z","pushed_at":"2026-01-03T00:00:00Z"}]`))
 			return
+		case "/orgs/acme/outside_collaborators":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"login":"contractor","id":7,"type":"User"}]`))
+			return
+		case "/repos/acme/repo-one/collaborators":
+			if r.URL.Query().Get("affiliation") != "outside" {
+				http.Error(w, "unexpected affiliation", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"login":"contractor","id":7,"permissions":{"admin":false,"maintain":false,"push":true,"triage":true,"pull":true}}]`))
+			return
 		case "/repos/acme/repo-one/keys":
 			w.Header().Set("Content-Type", "application/json")
 			_, _ = w.Write([]byte(`[{"id":11,"key":"ssh-ed25519 AAAA1234567890 key@example","title":"deploy","read_only":true,"verified":true,"added_by":"alice","created_at":"2026-01-05T00:00:00Z","last_used":"2026-01-06T00:00:00Z"}]`))
@@ -180,6 +196,22 @@ func TestProgrammaticGovernanceEndpoints(t *testing.T) {
 		t.Fatalf("unexpected repos result: %+v", repos)
 	}
 
+	outside, err := c.ListOrgOutsideCollaborators(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("ListOrgOutsideCollaborators: %v", err)
+	}
+	if len(outside) != 1 || outside[0].Login != "contractor" || outside[0].AccountType != "User" {
+		t.Fatalf("unexpected outside collaborators result: %+v", outside)
+	}
+
+	repoCollaborators, err := c.ListRepoOutsideCollaborators(context.Background(), "acme", "repo-one")
+	if err != nil {
+		t.Fatalf("ListRepoOutsideCollaborators: %v", err)
+	}
+	if len(repoCollaborators) != 1 || repoCollaborators[0].Permission != "push" {
+		t.Fatalf("unexpected repo collaborators result: %+v", repoCollaborators)
+	}
+
 	keys, err := c.ListRepoDeployKeys(context.Background(), "acme", "repo-one")
 	if err != nil {
 		t.Fatalf("ListRepoDeployKeys: %v", err)
@@ -256,3 +288,102 @@ func TestProgrammaticGovernanceUnavailable(t *testing.T) {
 		t.Fatalf("ListOrgPersonalAccessTokens error=%v, want ErrDatasetUnavailable", err)
 	}
 }
+
+func TestClientOAuthScopesDriveConnectorGrants(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		scopes      string
+		status      int
+		wantMissing []string
+	}{
+		{name: "missing audit scope", scopes: "read:org, repo", status: http.StatusForbidden, wantMissing: []string{"read:audit_log"}},
+		{name: "sufficient token", scopes: "admin:org, repo, read:audit_log", status: http.StatusOK, wantMissing: []string{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/orgs/acme/audit-log" {
+					http.NotFound(w, r)
+					return
+				}
+				w.Header().Set("X-OAuth-Scopes", tc.scopes)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.status)
+				if tc.status == http.StatusOK {
+					_, _ = w.Write([]byte(`[]`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"message":"must have admin rights"}`))
+			}))
+			t.Cleanup(srv.Close)
+
+			c, err := New(srv.URL, "token")
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if _, ok := c.OAuthScopes(); ok {
+				t.Fatalf("OAuthScopes reported before any request")
+			}
+			_, _ = c.ListOrgAuditLog(context.Background(), "acme")
+
+			scopes, ok := c.OAuthScopes()
+			if !ok {
+				t.Fatalf("OAuthScopes not recorded")
+			}
+			grants := registry.EvaluateGrants(scopes, githubRequiredScopes, githubScopeImplications)
+			if !slices.Equal(grants.Missing, tc.wantMissing) {
+				t.Fatalf("missing = %v, want %v", grants.Missing, tc.wantMissing)
+			}
+			if len(grants.Granted) != len(scopes) || len(grants.Required) != len(githubRequiredScopes) {
+				t.Fatalf("grants = %+v", grants)
+			}
+		})
+	}
+}
+
+func TestListTeamMembersPartialPages(t *testing.T) {
+	t.Parallel()
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		if raw := r.URL.Query().Get("page"); raw != "" {
+			page, _ = strconv.Atoi(raw)
+		}
+		if page == 3 {
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s/orgs/acme/teams/core/members?per_page=100&page=%d>; rel="next"`, srv.URL, page+1))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `[{"login":"user-%d","id":%d}]`, page, page)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, "token")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	members, err := c.ListTeamMembers(context.Background(), "acme", "core")
+	if err == nil || members != nil {
+		t.Fatalf("ListTeamMembers() = %v, %v; want no members and an error by default", members, err)
+	}
+
+	members, err = c.ListTeamMembers(context.Background(), "acme", "core", registry.AllowPartialPages())
+	var partial *registry.PartialPagesError
+	if !errors.As(err, &partial) {
+		t.Fatalf("ListTeamMembers(AllowPartialPages) error = %v, want *registry.PartialPagesError", err)
+	}
+	if partial.Pages != 2 {
+		t.Fatalf("partial.Pages = %d, want 2", partial.Pages)
+	}
+	if len(members) != 2 || members[0].Login != "user-1" || members[1].Login != "user-2" {
+		t.Fatalf("members = %+v, want pages 1 and 2", members)
+	}
+}
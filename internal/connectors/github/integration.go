@@ -2,10 +2,12 @@ package github
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"hash/fnv"
 	"log/slog"
+	"sort"
 	"strconv"
 	"strings"
 	gosync "sync"
@@ -26,12 +28,30 @@ const (
 	githubAuditEventBatchSize = 2000
 )
 
+const (
+	// githubOutsideCollaboratorFlag marks outside collaborator app users in their raw JSON.
+	githubOutsideCollaboratorFlag            = "is_outside_collaborator"
+	githubOutsideCollaboratorEntitlementKind = "github_outside_collaborator_repo_permission"
+)
+
+// githubRequiredScopes are the classic OAuth scopes a full sync uses: org membership and teams,
+// private repositories and their deploy keys, and the org audit log.
+var githubRequiredScopes = []string{"read:org", "repo", "read:audit_log"}
+
+// githubScopeImplications lists the parent scopes that confer a required scope.
+var githubScopeImplications = map[string][]string{
+	"admin:org": {"write:org", "read:org"},
+	"write:org": {"read:org"},
+}
+
 type GitHubIntegration struct {
 	client     *Client
 	org        string
 	enterprise string
 	workers    int
 	scim       bool
+
+	secretScanning bool
 }
 
 type githubAppAssetUpsertRow struct {
@@ -117,9 +137,21 @@ func (e *programmaticSyncError) Unwrap() error {
 	return e.err
 }
 
-func NewGitHubIntegration(client *Client, org string, enterprise string, workers int, scimEnabled bool) *GitHubIntegration {
+// programmaticSyncErrorKind returns the sync error kind a programmaticSyncError carries.
+func programmaticSyncErrorKind(err error) string {
+	var programmaticErr *programmaticSyncError
+	if errors.As(err, &programmaticErr) && strings.TrimSpace(programmaticErr.kind) != "" {
+		return strings.TrimSpace(programmaticErr.kind)
+	}
+	return registry.SyncErrorKindUnknown
+}
+
+// defaultGitHubWorkers is the team-data and deploy-key concurrency when none is configured.
+const defaultGitHubWorkers = 6
+
+func NewGitHubIntegration(client *Client, org string, enterprise string, workers int, scimEnabled bool, secretScanningEnabled bool) *GitHubIntegration {
 	if workers < 1 {
-		workers = 6
+		workers = defaultGitHubWorkers
 	}
 	return &GitHubIntegration{
 		client:     client,
@@ -127,6 +159,8 @@ func NewGitHubIntegration(client *Client, org string, enterprise string, workers
 		enterprise: strings.TrimSpace(enterprise),
 		workers:    workers,
 		scim:       scimEnabled,
+
+		secretScanning: secretScanningEnabled,
 	}
 }
 
@@ -148,6 +182,7 @@ func (i *GitHubIntegration) InitEvents() []registry.Event {
 		{Source: "github", Stage: "list-pat-governance", Current: 0, Total: 2, Message: "listing PAT governance datasets"},
 		{Source: "github", Stage: "list-installations", Current: 0, Total: 1, Message: "listing GitHub app installations"},
 		{Source: "github", Stage: "list-audit-events", Current: 0, Total: 1, Message: "listing GitHub audit events"},
+		{Source: "github", Stage: "list-secret-scanning-alerts", Current: 0, Total: 1, Message: "listing secret scanning alerts"},
 		{Source: "github", Stage: "write-programmatic-assets", Current: 0, Total: registry.UnknownTotal, Message: "writing programmatic app assets"},
 		{Source: "github", Stage: "write-programmatic-owners", Current: 0, Total: registry.UnknownTotal, Message: "writing programmatic asset owners"},
 		{Source: "github", Stage: "write-programmatic-credentials", Current: 0, Total: registry.UnknownTotal, Message: "writing programmatic credential metadata"},
@@ -159,13 +194,19 @@ func (i *GitHubIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpo
 	started := time.Now()
 	slog.Info("syncing GitHub", "org", i.org)
 
-	runID, err := q.CreateSyncRun(ctx, gen.CreateSyncRunParams{
+	runID, err := registry.CreateSyncRun(ctx, q, gen.CreateSyncRunParams{
 		SourceKind: "github",
 		SourceName: i.org,
 	})
 	if err != nil {
 		return err
 	}
+	warnings := registry.NewWarningReporter(report)
+	defer func() {
+		_ = registry.PersistSyncRunWarnings(ctx, q, runID, warnings.Warnings())
+	}()
+	defer i.recordGrants(ctx, q, runID)
+	holds := registry.NewExpiryHolds()
 
 	members, err := i.client.ListOrgMembers(ctx, i.org)
 	if err != nil {
@@ -173,24 +214,18 @@ func (i *GitHubIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpo
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindAPI)
 	}
 
-	type externalIdentity struct {
-		nameID       string
-		scimUserName string
-		scimEmail    string
-		userEmail    string
-	}
-	externalByLogin := make(map[string]externalIdentity)
+	externalByLogin := make(map[string]githubExternalIdentity)
 
 	samlIdentities, err := i.client.ListOrgSAMLExternalIdentities(ctx, i.org)
 	if err != nil {
 		slog.Warn("github saml external identities lookup failed", "org", i.org, "err", err)
-		report(registry.Event{Source: "github", Stage: "resolve-emails", Message: fmt.Sprintf("saml identity lookup failed: %v", err), Err: err})
+		warnings.ReportWarning(registry.Event{Source: "github", Stage: "resolve-emails", Message: fmt.Sprintf("saml identity lookup failed: %v", err), Err: err})
 		if errors.Is(err, ErrNoSAMLIdentityProvider) && strings.TrimSpace(i.enterprise) != "" {
 			slog.Info("github trying enterprise external identities", "enterprise", i.enterprise, "org", i.org)
 			samlIdentities, err = i.client.ListEnterpriseSAMLExternalIdentities(ctx, i.enterprise)
 			if err != nil {
 				slog.Warn("github enterprise external identities lookup failed", "enterprise", i.enterprise, "err", err)
-				report(registry.Event{Source: "github", Stage: "resolve-emails", Message: fmt.Sprintf("enterprise saml identity lookup failed: %v", err), Err: err})
+				warnings.ReportWarning(registry.Event{Source: "github", Stage: "resolve-emails", Message: fmt.Sprintf("enterprise saml identity lookup failed: %v", err), Err: err})
 			} else {
 				for _, identity := range samlIdentities {
 					login := strings.ToLower(strings.TrimSpace(identity.Login))
@@ -201,7 +236,7 @@ func (i *GitHubIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpo
 					if login == "" || (nameID == "" && scimUserName == "" && scimEmail == "" && userEmail == "") {
 						continue
 					}
-					externalByLogin[login] = externalIdentity{nameID: nameID, scimUserName: scimUserName, scimEmail: scimEmail, userEmail: userEmail}
+					externalByLogin[login] = githubExternalIdentity{nameID: nameID, scimUserName: scimUserName, scimEmail: scimEmail, userEmail: userEmail}
 				}
 			}
 		}
@@ -215,7 +250,7 @@ func (i *GitHubIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpo
 			if login == "" || (nameID == "" && scimUserName == "" && scimEmail == "" && userEmail == "") {
 				continue
 			}
-			externalByLogin[login] = externalIdentity{nameID: nameID, scimUserName: scimUserName, scimEmail: scimEmail, userEmail: userEmail}
+			externalByLogin[login] = githubExternalIdentity{nameID: nameID, scimUserName: scimUserName, scimEmail: scimEmail, userEmail: userEmail}
 		}
 	}
 
@@ -225,7 +260,7 @@ func (i *GitHubIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpo
 		scimUsers, err := i.client.ListOrgSCIMUsers(ctx, i.org)
 		if err != nil {
 			slog.Warn("github scim users lookup failed", "org", i.org, "err", err)
-			report(registry.Event{Source: "github", Stage: "resolve-emails", Message: fmt.Sprintf("scim user lookup failed: %v", err), Err: err})
+			warnings.ReportWarning(registry.Event{Source: "github", Stage: "resolve-emails", Message: fmt.Sprintf("scim user lookup failed: %v", err), Err: err})
 		} else {
 			for _, u := range scimUsers {
 				if v := strings.ToLower(strings.TrimSpace(u.UserName)); v != "" {
@@ -237,59 +272,25 @@ func (i *GitHubIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpo
 			}
 			if len(scimUsers) == 0 {
 				slog.Warn("github scim enabled but returned 0 users", "org", i.org)
+				warnings.ReportWarning(registry.Event{Source: "github", Stage: "resolve-emails", Message: "scim enabled but returned 0 users"})
 			}
 		}
 	}
 
-	resolveEmail := func(login string) string {
-		loginKey := strings.ToLower(strings.TrimSpace(login))
-		if loginKey == "" {
-			return ""
-		}
-
-		identity := externalByLogin[loginKey]
-		nameID := strings.TrimSpace(identity.nameID)
-		scimUserName := strings.TrimSpace(identity.scimUserName)
-		scimEmail := strings.TrimSpace(identity.scimEmail)
-		userEmail := strings.TrimSpace(identity.userEmail)
-
-		if i.scim {
-			if u, ok := scimByLogin[loginKey]; ok {
-				return u.PreferredEmail()
-			}
-			if u, ok := scimByUserName[loginKey]; ok {
-				return u.PreferredEmail()
-			}
-			if scimUserName != "" {
-				if u, ok := scimByUserName[strings.ToLower(scimUserName)]; ok {
-					return u.PreferredEmail()
-				}
-			}
-			if nameID != "" {
-				if u, ok := scimByUserName[strings.ToLower(nameID)]; ok {
-					return u.PreferredEmail()
-				}
-			}
-		}
-
-		if scimUserName != "" && strings.Contains(scimUserName, "@") {
-			return scimUserName
-		}
-		if scimEmail != "" && strings.Contains(scimEmail, "@") {
-			return scimEmail
-		}
-		if userEmail != "" && strings.Contains(userEmail, "@") {
-			return userEmail
-		}
-		if nameID != "" && strings.Contains(nameID, "@") {
-			return nameID
-		}
-		return ""
+	emailResolver := githubLoginEmailResolver{
+		scim:            i.scim,
+		externalByLogin: externalByLogin,
+		scimByUserName:  scimByUserName,
+		scimByLogin:     scimByLogin,
 	}
+	resolveEmail := emailResolver.Resolve
 
 	resolvedEmails := 0
 	for idx := range members {
 		if email := strings.TrimSpace(resolveEmail(members[idx].Login)); email != "" {
+			if profile := matching.NormalizeEmail(members[idx].Email); profile != "" && profile != matching.NormalizeEmail(email) {
+				members[idx].ProfileEmail = profile
+			}
 			members[idx].Email = email
 			resolvedEmails++
 		}
@@ -351,14 +352,17 @@ func (i *GitHubIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpo
 					if teamCtx.Err() != nil {
 						return
 					}
-					members, err := i.client.ListTeamMembers(teamCtx, i.org, team.Slug)
-					if err != nil {
+					// Team data is enrichment: a team whose listing breaks midway keeps the pages
+					// already fetched and the run records a warning instead of failing. The
+					// entitlements the missing pages would have confirmed are held from expiry.
+					members, err := i.client.ListTeamMembers(teamCtx, i.org, team.Slug, registry.AllowPartialPages())
+					if err = i.keepPartialTeamData(teamCtx, warnings, holds, team.Slug, "members", err); err != nil {
 						results <- teamResult{slug: team.Slug, err: fmt.Errorf("github team %s members: %w", team.Slug, err)}
 						cancel()
 						continue
 					}
-					repos, err := i.client.ListTeamRepos(teamCtx, i.org, team.Slug)
-					if err != nil {
+					repos, err := i.client.ListTeamRepos(teamCtx, i.org, team.Slug, registry.AllowPartialPages())
+					if err = i.keepPartialTeamData(teamCtx, warnings, holds, team.Slug, "repos", err); err != nil {
 						results <- teamResult{slug: team.Slug, err: fmt.Errorf("github team %s repos: %w", team.Slug, err)}
 						cancel()
 						continue
@@ -410,16 +414,29 @@ func (i *GitHubIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpo
 		}
 	}
 
+	// Repositories are listed once for both outside collaborator and deploy key inventory.
+	report(registry.Event{Source: "github", Stage: "list-repositories", Current: 0, Total: 1, Message: "listing repositories"})
+	repositories, err := i.client.ListOrgRepos(ctx, i.org)
+	if err != nil {
+		wrapped := fmt.Errorf("github repository listing failed: %w", err)
+		report(registry.Event{Source: "github", Stage: "list-repositories", Message: wrapped.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, wrapped, registry.SyncErrorKindAPI)
+	}
+	report(registry.Event{Source: "github", Stage: "list-repositories", Current: 1, Total: 1, Message: fmt.Sprintf("found %d repositories", len(repositories))})
+
+	outsideCollaborators, repoCollaborators := i.listOutsideCollaborators(ctx, report, warnings, holds, repositories)
+	collaboratorUsers, collaboratorEntitlements := buildGitHubOutsideCollaboratorRows(members, outsideCollaborators, repoCollaborators, resolveEmail)
+
 	report(registry.Event{
 		Source:  "github",
 		Stage:   "write-members",
 		Current: 0,
-		Total:   int64(len(members) + len(teams)),
-		Message: fmt.Sprintf("writing %d principals", len(members)+len(teams)),
+		Total:   int64(len(members) + len(teams) + len(collaboratorUsers)),
+		Message: fmt.Sprintf("writing %d principals", len(members)+len(teams)+len(collaboratorUsers)),
 	})
 
 	const userBatchSize = 1000
-	totalPrincipals := len(members) + len(teams)
+	totalPrincipals := len(members) + len(teams) + len(collaboratorUsers)
 	externalIDs := make([]string, 0, totalPrincipals)
 	emails := make([]string, 0, totalPrincipals)
 	displayNames := make([]string, 0, totalPrincipals)
@@ -428,24 +445,25 @@ func (i *GitHubIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpo
 	lastLoginAts := make([]pgtype.Timestamptz, 0, totalPrincipals)
 	lastLoginIps := make([]string, 0, totalPrincipals)
 	lastLoginRegions := make([]string, 0, totalPrincipals)
+	secondaryEmails := make([][]string, 0, totalPrincipals)
 
+	userRows := make([]githubAppUserUpsertRow, 0, len(members)+len(collaboratorUsers))
 	for _, member := range members {
-		externalID := strings.TrimSpace(member.Login)
-		if externalID == "" {
-			continue
+		if row, ok := buildGitHubMemberUserRow(member, false); ok {
+			userRows = append(userRows, row)
 		}
-		display := strings.TrimSpace(member.DisplayName)
-		if display == "" {
-			display = externalID
-		}
-		externalIDs = append(externalIDs, externalID)
-		emails = append(emails, matching.NormalizeEmail(member.Email))
-		displayNames = append(displayNames, display)
-		accountKinds = append(accountKinds, githubMemberAccountKind(member))
-		rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeJSON(member.RawJSON), registry.EntityCategoryUser))
+	}
+	userRows = append(userRows, collaboratorUsers...)
+	for _, row := range userRows {
+		externalIDs = append(externalIDs, row.ExternalID)
+		emails = append(emails, row.Email)
+		displayNames = append(displayNames, row.DisplayName)
+		accountKinds = append(accountKinds, row.AccountKind)
+		rawJSONs = append(rawJSONs, row.RawJSON)
 		lastLoginAts = append(lastLoginAts, pgtype.Timestamptz{})
 		lastLoginIps = append(lastLoginIps, "")
 		lastLoginRegions = append(lastLoginRegions, "")
+		secondaryEmails = append(secondaryEmails, row.SecondaryEmails)
 	}
 
 	for _, team := range teams {
@@ -473,6 +491,7 @@ func (i *GitHubIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpo
 		lastLoginAts = append(lastLoginAts, pgtype.Timestamptz{})
 		lastLoginIps = append(lastLoginIps, "")
 		lastLoginRegions = append(lastLoginRegions, "")
+		secondaryEmails = append(secondaryEmails, nil)
 	}
 
 	for start := 0; start < len(externalIDs); start += userBatchSize {
@@ -490,6 +509,9 @@ func (i *GitHubIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpo
 			LastLoginIps:     lastLoginIps[start:end],
 			LastLoginRegions: lastLoginRegions[start:end],
 		})
+		if err == nil {
+			err = q.ReplaceAccountSecondaryEmailsBySource(ctx, registry.AccountSecondaryEmailsParams("github", i.org, externalIDs[start:end], secondaryEmails[start:end]))
+		}
 		if err != nil {
 			report(registry.Event{Source: "github", Stage: "write-members", Message: err.Error(), Err: err})
 			return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
@@ -553,6 +575,15 @@ func (i *GitHubIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpo
 		}
 	}
 
+	for _, ent := range collaboratorEntitlements {
+		entAppUserExternalIDs = append(entAppUserExternalIDs, ent.AppUserExternalID)
+		entKinds = append(entKinds, ent.Kind)
+		entResources = append(entResources, ent.Resource)
+		entPermissions = append(entPermissions, ent.Permission)
+		entRawJSONs = append(entRawJSONs, ent.RawJSON)
+	}
+	entPrivilegeLevels := githubPrivilegeMapping.Levels(entKinds, entPermissions, nil)
+
 	for start := 0; start < len(entAppUserExternalIDs); start += entitlementBatchSize {
 		end := min(start+entitlementBatchSize, len(entAppUserExternalIDs))
 		_, err := q.UpsertEntitlementsBulkBySource(ctx, gen.UpsertEntitlementsBulkBySourceParams{
@@ -564,6 +595,7 @@ func (i *GitHubIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpo
 			Resources:          entResources[start:end],
 			Permissions:        entPermissions[start:end],
 			RawJsons:           entRawJSONs[start:end],
+			PrivilegeLevels:    entPrivilegeLevels[start:end],
 		})
 		if err != nil {
 			report(registry.Event{Source: "github", Stage: "write-members", Message: err.Error(), Err: err})
@@ -571,72 +603,176 @@ func (i *GitHubIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpo
 		}
 	}
 
-	programmaticSummary, err := i.syncProgrammaticAccess(ctx, q, report, runID)
+	programmaticSummary, err := i.syncProgrammaticAccess(ctx, q, pool, report, runID, repositories, resolveEmail)
 	if err != nil {
-		errorKind := registry.SyncErrorKindUnknown
-		var programmaticErr *programmaticSyncError
-		if errors.As(err, &programmaticErr) && strings.TrimSpace(programmaticErr.kind) != "" {
-			errorKind = strings.TrimSpace(programmaticErr.kind)
+		return registry.FailSyncRun(ctx, q, runID, err, programmaticSyncErrorKind(err))
+	}
+
+	secretScanningAlerts := 0
+	if i.secretScanning {
+		secretScanningAlerts, err = i.syncSecretScanningAlerts(ctx, q, pool, warnings)
+		if err != nil {
+			return registry.FailSyncRun(ctx, q, runID, err, programmaticSyncErrorKind(err))
 		}
-		return registry.FailSyncRun(ctx, q, runID, err, errorKind)
 	}
 
-	if err := registry.FinalizeAppRun(ctx, q, pool, runID, "github", i.org, time.Since(started), false); err != nil {
+	if err := registry.FinalizeAppRun(ctx, q, pool, runID, "github", i.org, time.Since(started), false, holds); err != nil {
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 	slog.Info(
 		"github sync complete",
 		"org", i.org,
 		"members", len(members),
+		"outside_collaborators", len(collaboratorUsers),
 		"programmatic_app_assets", programmaticSummary.AppAssets,
 		"programmatic_owners", programmaticSummary.Owners,
 		"programmatic_credentials", programmaticSummary.Credentials,
 		"programmatic_audit_events", programmaticSummary.AuditEvents,
+		"secret_scanning_alerts", secretScanningAlerts,
 	)
 	return nil
 }
 
-func (i *GitHubIntegration) syncProgrammaticAccess(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64) (githubProgrammaticSyncSummary, error) {
-	summary := githubProgrammaticSyncSummary{}
+// keepPartialTeamData reports a team listing that failed after some pages as a warning and clears
+// the error so the collected pages are used. The entitlements the missing pages could carry are
+// held from expiry: the team's memberships when members are incomplete, and team repository
+// permissions either way, since they are derived from both lists. Other errors, and failures
+// caused by the team fetch being cancelled, are returned unchanged.
+func (i *GitHubIntegration) keepPartialTeamData(ctx context.Context, warnings *registry.WarningReporter, holds *registry.ExpiryHolds, teamSlug, dataset string, err error) error {
+	var partial *registry.PartialPagesError
+	if !errors.As(err, &partial) || ctx.Err() != nil {
+		return err
+	}
+	if dataset == "members" {
+		holds.HoldEntitlements("github_team_member", "github_team:"+i.org+"/"+teamSlug)
+	}
+	holds.HoldEntitlements("github_team_repo_permission", "")
+	warnings.ReportWarning(registry.Event{
+		Source:  "github",
+		Stage:   "fetch-team-data",
+		Message: fmt.Sprintf("team %s %s incomplete after %d pages: %v", teamSlug, dataset, partial.Pages, partial.Err),
+	})
+	return nil
+}
 
-	report(registry.Event{Source: "github", Stage: "list-programmatic-assets", Current: 0, Total: 1, Message: "listing repositories for deploy-key governance"})
-	repositories, err := i.client.ListOrgRepos(ctx, i.org)
+// listOutsideCollaborators collects the org's outside collaborators and their direct repository
+// access. The org endpoint requires an owner token, so failures are reported as warnings and the
+// sync continues with members only. Outside collaborators, and the repository permissions of
+// every repository that could not be read, are held from expiry until a run lists them.
+func (i *GitHubIntegration) listOutsideCollaborators(ctx context.Context, report func(registry.Event), warnings *registry.WarningReporter, holds *registry.ExpiryHolds, repositories []Repository) ([]OutsideCollaborator, map[string][]RepoCollaborator) {
+	collaborators, err := i.client.ListOrgOutsideCollaborators(ctx, i.org)
 	if err != nil {
-		wrapped := fmt.Errorf("github repository listing failed: %w", err)
-		report(registry.Event{Source: "github", Stage: "list-programmatic-assets", Message: wrapped.Error(), Err: err})
-		return summary, &programmaticSyncError{kind: registry.SyncErrorKindAPI, err: wrapped}
-	} else {
-		report(registry.Event{
-			Source:  "github",
-			Stage:   "list-programmatic-assets",
-			Current: 1,
-			Total:   1,
-			Message: fmt.Sprintf("found %d repositories", len(repositories)),
-		})
+		holds.HoldAppUsers(githubOutsideCollaboratorFlag)
+		holds.HoldEntitlements(githubOutsideCollaboratorEntitlementKind, "")
+		warnings.ReportWarning(registry.Event{Source: "github", Stage: "list-outside-collaborators", Message: fmt.Sprintf("outside collaborator lookup failed: %v", err), Err: err})
+		return nil, nil
+	}
+	report(registry.Event{Source: "github", Stage: "list-outside-collaborators", Current: 1, Total: 1, Message: fmt.Sprintf("found %d outside collaborators", len(collaborators))})
+	if len(collaborators) == 0 || len(repositories) == 0 {
+		return collaborators, nil
 	}
 
-	credentialRows := make([]githubCredentialArtifactUpsertRow, 0)
-	if len(repositories) > 0 {
-		report(registry.Event{Source: "github", Stage: "list-deploy-keys", Current: 0, Total: int64(len(repositories)), Message: fmt.Sprintf("listing deploy keys for %d repositories", len(repositories))})
-		for idx, repo := range repositories {
-			repoName := strings.TrimSpace(repo.Name)
-			if repoName == "" {
-				repoName = strings.TrimSpace(strings.TrimPrefix(repo.FullName, i.org+"/"))
-			}
-			if repoName == "" {
-				report(registry.Event{Source: "github", Stage: "list-deploy-keys", Current: int64(idx + 1), Total: int64(len(repositories)), Message: fmt.Sprintf("skipping repository %q (missing name)", repo.FullName)})
-				continue
-			}
+	type repoCollaboratorResult struct {
+		fullName string
+		users    []RepoCollaborator
+	}
 
-			keys, err := i.client.ListRepoDeployKeys(ctx, i.org, repoName)
-			if err != nil {
-				wrapped := fmt.Errorf("github deploy key lookup failed for %s: %w", repoName, err)
-				report(registry.Event{Source: "github", Stage: "list-deploy-keys", Message: wrapped.Error(), Err: err})
-				return summary, &programmaticSyncError{kind: registry.SyncErrorKindAPI, err: wrapped}
+	jobs := make(chan Repository, len(repositories))
+	results := make(chan repoCollaboratorResult, len(repositories))
+	var done int64
+
+	workers := min(len(repositories), i.workers)
+	if workers < 1 {
+		workers = 1
+	}
+
+	var wg gosync.WaitGroup
+	for j := 0; j < workers; j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for repo := range jobs {
+				repoName := githubRepoName(i.org, repo)
+				if repoName == "" {
+					continue
+				}
+				fullName := strings.TrimSpace(repo.FullName)
+				if fullName == "" {
+					fullName = i.org + "/" + repoName
+				}
+				repoUsers, err := i.client.ListRepoOutsideCollaborators(ctx, i.org, repoName)
+				if err != nil {
+					holds.HoldEntitlements(githubOutsideCollaboratorEntitlementKind, "github_repo:"+fullName)
+					warnings.ReportWarning(registry.Event{Source: "github", Stage: "list-outside-collaborators", Message: fmt.Sprintf("outside collaborator lookup failed for %s: %v", fullName, err), Err: err})
+					continue
+				}
+				n := atomic.AddInt64(&done, 1)
+				report(registry.Event{Source: "github", Stage: "list-outside-collaborators", Current: n, Total: int64(len(repositories)), Message: fmt.Sprintf("repositories %d/%d", n, len(repositories))})
+				if len(repoUsers) > 0 {
+					results <- repoCollaboratorResult{fullName: fullName, users: repoUsers}
+				}
 			}
-			credentialRows = append(credentialRows, buildGitHubDeployKeyCredentialRows(i.org, repo, keys)...)
-			report(registry.Event{Source: "github", Stage: "list-deploy-keys", Current: int64(idx + 1), Total: int64(len(repositories)), Message: fmt.Sprintf("deploy keys %d/%d", idx+1, len(repositories))})
-		}
+		}()
+	}
+
+	for _, repo := range repositories {
+		jobs <- repo
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	repoCollaborators := make(map[string][]RepoCollaborator)
+	for res := range results {
+		repoCollaborators[res.fullName] = res.users
+	}
+	return collaborators, repoCollaborators
+}
+
+// recordGrants stores the token's classic OAuth scopes against what a full sync needs, so a run
+// that failed on e.g. the audit log can be traced to a missing read:audit_log scope. Tokens that do
+// not report scopes (fine-grained PATs, GitHub Apps) leave the stored grants untouched.
+func (i *GitHubIntegration) recordGrants(ctx context.Context, q *gen.Queries, runID int64) {
+	scopes, ok := i.client.OAuthScopes()
+	if !ok {
+		return
+	}
+	grants := registry.EvaluateGrants(scopes, githubRequiredScopes, githubScopeImplications)
+	if err := registry.PersistConnectorGrants(ctx, q, "github", i.org, runID, grants); err != nil {
+		slog.Warn("github grants not recorded", "org", i.org, "err", err)
+	}
+}
+
+// RequiredGrants lists the classic OAuth scopes a full sync needs.
+func (i *GitHubIntegration) RequiredGrants() []string {
+	return append([]string(nil), githubRequiredScopes...)
+}
+
+// TestConnection reads the org, which checks the token and org name, and evaluates the token's
+// classic OAuth scopes. Fine-grained PATs and GitHub App tokens report no scopes, so only
+// connectivity is checked for them.
+func (i *GitHubIntegration) TestConnection(ctx context.Context) (registry.ConnectorGrants, bool, error) {
+	if err := i.client.GetOrg(ctx, i.org); err != nil {
+		return registry.ConnectorGrants{}, false, err
+	}
+	scopes, ok := i.client.OAuthScopes()
+	if !ok {
+		return registry.ConnectorGrants{}, false, nil
+	}
+	return registry.EvaluateGrants(scopes, githubRequiredScopes, githubScopeImplications), true, nil
+}
+
+// syncProgrammaticAccess collects deploy keys, PAT governance, app installations, and credential
+// audit events. resolveEmail maps a GitHub login to the email resolved from SAML/SCIM for members
+// so installation owners and audit actors can be linked to identities by email.
+func (i *GitHubIntegration) syncProgrammaticAccess(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, report func(registry.Event), runID int64, repositories []Repository, resolveEmail func(login string) string) (githubProgrammaticSyncSummary, error) {
+	summary := githubProgrammaticSyncSummary{}
+
+	checkpoints := newDeployKeyCheckpoints(q, i.org)
+	credentialRows, err := i.listDeployKeyCredentialRows(ctx, repositories, checkpoints, report)
+	if err != nil {
+		report(registry.Event{Source: "github", Stage: githubDeployKeyStage, Message: err.Error(), Err: err})
+		return summary, &programmaticSyncError{kind: registry.SyncErrorKindAPI, err: err}
 	}
 
 	report(registry.Event{Source: "github", Stage: "list-pat-governance", Current: 0, Total: 2, Message: "listing fine-grained PAT requests"})
@@ -705,40 +841,293 @@ func (i *GitHubIntegration) syncProgrammaticAccess(ctx context.Context, q *gen.Q
 		}
 	} else {
 		report(registry.Event{Source: "github", Stage: "list-audit-events", Current: 1, Total: 1, Message: fmt.Sprintf("found %d audit events", len(auditEvents))})
-		auditRows = buildGitHubAuditEventRows(i.org, auditEvents)
+		auditRows = buildGitHubAuditEventRows(i.org, auditEvents, newGitHubCredentialRefs(credentialRows))
 	}
+	enrichGitHubOwnerEmails(ownerRows, resolveEmail)
+	enrichGitHubAuditActorEmails(auditRows, resolveEmail)
 
-	if err := i.upsertProgrammaticAppAssets(ctx, q, report, runID, installationRows); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertProgrammaticAppAssets(ctx, qtx, report, runID, installationRows)
+	}); err != nil {
 		report(registry.Event{Source: "github", Stage: "write-programmatic-assets", Message: err.Error(), Err: err})
 		return summary, &programmaticSyncError{kind: registry.SyncErrorKindDB, err: fmt.Errorf("github app installation upsert failed: %w", err)}
 	} else {
 		summary.AppAssets = len(installationRows)
 	}
 
-	if err := i.upsertProgrammaticAssetOwners(ctx, q, report, runID, ownerRows); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertProgrammaticAssetOwners(ctx, qtx, report, runID, ownerRows)
+	}); err != nil {
 		report(registry.Event{Source: "github", Stage: "write-programmatic-owners", Message: err.Error(), Err: err})
 		return summary, &programmaticSyncError{kind: registry.SyncErrorKindDB, err: fmt.Errorf("github app installation owner upsert failed: %w", err)}
 	} else {
 		summary.Owners = len(ownerRows)
 	}
 
-	if err := i.upsertProgrammaticCredentials(ctx, q, report, runID, credentialRows); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertProgrammaticCredentials(ctx, qtx, report, runID, credentialRows)
+	}); err != nil {
 		report(registry.Event{Source: "github", Stage: "write-programmatic-credentials", Message: err.Error(), Err: err})
 		return summary, &programmaticSyncError{kind: registry.SyncErrorKindDB, err: fmt.Errorf("github credential metadata upsert failed: %w", err)}
 	} else {
 		summary.Credentials = len(credentialRows)
 	}
 
-	if err := i.upsertProgrammaticAuditEvents(ctx, q, report, auditRows); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertProgrammaticAuditEvents(ctx, qtx, report, auditRows)
+	}); err != nil {
 		report(registry.Event{Source: "github", Stage: "write-programmatic-audit-events", Message: err.Error(), Err: err})
 		return summary, &programmaticSyncError{kind: registry.SyncErrorKindDB, err: fmt.Errorf("github credential audit-event upsert failed: %w", err)}
 	} else {
 		summary.AuditEvents = len(auditRows)
 	}
 
+	if err := checkpoints.Clear(ctx); err != nil {
+		slog.Warn("github deploy key checkpoints not cleared", "org", i.org, "err", err)
+	}
+
 	return summary, nil
 }
 
+// syncSecretScanningAlerts stores the org's secret scanning alerts as credential audit events on
+// their repository. Listing alerts needs secret scanning read access the rest of the sync does not,
+// so an unavailable dataset is a warning: alerts are never expired, so earlier ones stay as they were.
+func (i *GitHubIntegration) syncSecretScanningAlerts(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, warnings *registry.WarningReporter) (int, error) {
+	warnings.Report(registry.Event{Source: "github", Stage: "list-secret-scanning-alerts", Current: 0, Total: 1, Message: "listing secret scanning alerts"})
+	alerts, err := i.client.ListOrgSecretScanningAlerts(ctx, i.org)
+	if err != nil {
+		if errors.Is(err, ErrDatasetUnavailable) {
+			warnings.ReportWarning(registry.Event{Source: "github", Stage: "list-secret-scanning-alerts", Message: fmt.Sprintf("secret scanning alerts skipped; token lacks secret scanning access: %v", err), Err: err})
+			return 0, nil
+		}
+		wrapped := fmt.Errorf("github secret scanning alert listing failed: %w", err)
+		warnings.Report(registry.Event{Source: "github", Stage: "list-secret-scanning-alerts", Message: wrapped.Error(), Err: err})
+		return 0, &programmaticSyncError{kind: registry.SyncErrorKindAPI, err: wrapped}
+	}
+	warnings.Report(registry.Event{Source: "github", Stage: "list-secret-scanning-alerts", Current: 1, Total: 1, Message: fmt.Sprintf("found %d secret scanning alerts", len(alerts))})
+
+	rows := buildGitHubSecretScanningAlertRows(alerts)
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertProgrammaticAuditEvents(ctx, qtx, warnings.Report, rows)
+	}); err != nil {
+		warnings.Report(registry.Event{Source: "github", Stage: "write-programmatic-audit-events", Message: err.Error(), Err: err})
+		return 0, &programmaticSyncError{kind: registry.SyncErrorKindDB, err: fmt.Errorf("github secret scanning alert upsert failed: %w", err)}
+	}
+	return len(rows), nil
+}
+
+// githubExternalIdentity is the SAML/SCIM identity GitHub links to an organization member login.
+type githubExternalIdentity struct {
+	nameID       string
+	scimUserName string
+	scimEmail    string
+	userEmail    string
+}
+
+// githubLoginEmailResolver resolves a GitHub login to an email using the organization's SAML
+// external identities and, when SCIM is enabled, its SCIM users.
+type githubLoginEmailResolver struct {
+	scim            bool
+	externalByLogin map[string]githubExternalIdentity
+	scimByUserName  map[string]SCIMUser
+	scimByLogin     map[string]SCIMUser
+}
+
+func (r githubLoginEmailResolver) Resolve(login string) string {
+	loginKey := strings.ToLower(strings.TrimSpace(login))
+	if loginKey == "" {
+		return ""
+	}
+
+	identity := r.externalByLogin[loginKey]
+	nameID := strings.TrimSpace(identity.nameID)
+	scimUserName := strings.TrimSpace(identity.scimUserName)
+	scimEmail := strings.TrimSpace(identity.scimEmail)
+	userEmail := strings.TrimSpace(identity.userEmail)
+
+	if r.scim {
+		if u, ok := r.scimByLogin[loginKey]; ok {
+			return u.PreferredEmail()
+		}
+		if u, ok := r.scimByUserName[loginKey]; ok {
+			return u.PreferredEmail()
+		}
+		if scimUserName != "" {
+			if u, ok := r.scimByUserName[strings.ToLower(scimUserName)]; ok {
+				return u.PreferredEmail()
+			}
+		}
+		if nameID != "" {
+			if u, ok := r.scimByUserName[strings.ToLower(nameID)]; ok {
+				return u.PreferredEmail()
+			}
+		}
+	}
+
+	if scimUserName != "" && strings.Contains(scimUserName, "@") {
+		return scimUserName
+	}
+	if scimEmail != "" && strings.Contains(scimEmail, "@") {
+		return scimEmail
+	}
+	if userEmail != "" && strings.Contains(userEmail, "@") {
+		return userEmail
+	}
+	if nameID != "" && strings.Contains(nameID, "@") {
+		return nameID
+	}
+	return ""
+}
+
+// enrichGitHubOwnerEmails fills in owner emails for user-owned installations; the installations
+// API only returns the account login.
+func enrichGitHubOwnerEmails(rows []githubAppAssetOwnerUpsertRow, resolveEmail func(login string) string) {
+	if resolveEmail == nil {
+		return
+	}
+	for idx := range rows {
+		if strings.TrimSpace(rows[idx].OwnerEmail) != "" || rows[idx].OwnerKind != "github_user" {
+			continue
+		}
+		rows[idx].OwnerEmail = strings.TrimSpace(resolveEmail(rows[idx].OwnerExternalID))
+	}
+}
+
+// enrichGitHubAuditActorEmails replaces the bare login display name of audit actors with their
+// resolved email. The login stays the actor external ID.
+func enrichGitHubAuditActorEmails(rows []githubCredentialAuditEventUpsertRow, resolveEmail func(login string) string) {
+	if resolveEmail == nil {
+		return
+	}
+	for idx := range rows {
+		if rows[idx].ActorKind != "github_user" {
+			continue
+		}
+		if email := strings.TrimSpace(resolveEmail(rows[idx].ActorExternalID)); email != "" {
+			rows[idx].ActorDisplayName = email
+		}
+	}
+}
+
+type githubAppUserUpsertRow struct {
+	ExternalID  string
+	Email       string
+	DisplayName string
+	AccountKind string
+	RawJSON     []byte
+	// SecondaryEmails are stored as the account's aliases for identity matching.
+	SecondaryEmails []string
+}
+
+type githubEntitlementUpsertRow struct {
+	AppUserExternalID string
+	Kind              string
+	Resource          string
+	Permission        string
+	RawJSON           []byte
+}
+
+func buildGitHubMemberUserRow(member Member, outsideCollaborator bool) (githubAppUserUpsertRow, bool) {
+	externalID := strings.TrimSpace(member.Login)
+	if externalID == "" {
+		return githubAppUserUpsertRow{}, false
+	}
+	display := strings.TrimSpace(member.DisplayName)
+	if display == "" {
+		display = externalID
+	}
+	rawJSON := registry.NormalizeRawJSON(member.RawJSON)
+	if outsideCollaborator {
+		payload := make(map[string]any)
+		if err := json.Unmarshal(rawJSON, &payload); err != nil {
+			payload = make(map[string]any)
+		}
+		payload[githubOutsideCollaboratorFlag] = true
+		rawJSON = registry.MarshalJSON(payload)
+	}
+	row := githubAppUserUpsertRow{
+		ExternalID:  externalID,
+		Email:       matching.NormalizeEmail(member.Email),
+		DisplayName: display,
+		AccountKind: githubMemberAccountKind(member),
+		RawJSON:     registry.WithEntityCategory(rawJSON, registry.EntityCategoryUser),
+	}
+	if member.ProfileEmail != "" {
+		row.SecondaryEmails = []string{member.ProfileEmail}
+	}
+	return row, true
+}
+
+// buildGitHubOutsideCollaboratorRows turns outside collaborators into app users flagged with
+// is_outside_collaborator and one github_outside_collaborator_repo_permission entitlement per
+// repository they can access directly. Logins that are also org members are skipped so the
+// member row wins.
+func buildGitHubOutsideCollaboratorRows(members []Member, collaborators []OutsideCollaborator, repoCollaborators map[string][]RepoCollaborator, resolveEmail func(login string) string) ([]githubAppUserUpsertRow, []githubEntitlementUpsertRow) {
+	memberLogins := make(map[string]struct{}, len(members))
+	for _, member := range members {
+		if login := strings.ToLower(strings.TrimSpace(member.Login)); login != "" {
+			memberLogins[login] = struct{}{}
+		}
+	}
+
+	users := make([]githubAppUserUpsertRow, 0, len(collaborators))
+	collaboratorLogins := make(map[string]string, len(collaborators))
+	for _, collaborator := range collaborators {
+		login := strings.TrimSpace(collaborator.Login)
+		key := strings.ToLower(login)
+		if login == "" {
+			continue
+		}
+		if _, isMember := memberLogins[key]; isMember {
+			continue
+		}
+		if _, seen := collaboratorLogins[key]; seen {
+			continue
+		}
+		member := Member{Login: login, ID: collaborator.ID, AccountType: collaborator.AccountType, RawJSON: collaborator.RawJSON}
+		if resolveEmail != nil {
+			member.Email = strings.TrimSpace(resolveEmail(login))
+		}
+		row, ok := buildGitHubMemberUserRow(member, true)
+		if !ok {
+			continue
+		}
+		collaboratorLogins[key] = row.ExternalID
+		users = append(users, row)
+	}
+
+	repoNames := make([]string, 0, len(repoCollaborators))
+	for fullName := range repoCollaborators {
+		repoNames = append(repoNames, fullName)
+	}
+	sort.Strings(repoNames)
+
+	entitlements := make([]githubEntitlementUpsertRow, 0)
+	for _, fullName := range repoNames {
+		repoFullName := strings.TrimSpace(fullName)
+		if repoFullName == "" {
+			continue
+		}
+		for _, repoUser := range repoCollaborators[fullName] {
+			externalID, ok := collaboratorLogins[strings.ToLower(strings.TrimSpace(repoUser.Login))]
+			if !ok || strings.TrimSpace(repoUser.Permission) == "" {
+				continue
+			}
+			entitlements = append(entitlements, githubEntitlementUpsertRow{
+				AppUserExternalID: externalID,
+				Kind:              githubOutsideCollaboratorEntitlementKind,
+				Resource:          "github_repo:" + repoFullName,
+				Permission:        repoUser.Permission,
+				RawJSON: registry.MarshalJSON(map[string]string{
+					"repo":       repoFullName,
+					"permission": repoUser.Permission,
+				}),
+			})
+		}
+	}
+	return users, entitlements
+}
+
 func buildGitHubInstallationRows(installations []AppInstallation) ([]githubAppAssetUpsertRow, []githubAppAssetOwnerUpsertRow) {
 	assetRows := make([]githubAppAssetUpsertRow, 0, len(installations))
 	ownerRows := make([]githubAppAssetOwnerUpsertRow, 0, len(installations))
@@ -1027,6 +1416,10 @@ func buildGitHubPATCredentialRows(org string, pats []PersonalAccessToken) []gith
 		if displayName == "" {
 			displayName = externalID
 		}
+		fingerprint := externalID
+		if pat.TokenID > 0 {
+			fingerprint = strconv.FormatInt(pat.TokenID, 10)
+		}
 
 		createdByKind, createdByExternalID, createdByDisplayName := githubActorIdentity(pat.OwnerLogin, pat.OwnerID)
 		approvedByKind, approvedByExternalID, approvedByDisplayName := githubActorIdentity(pat.ReviewerLogin, pat.ReviewerID)
@@ -1035,6 +1428,7 @@ func buildGitHubPATCredentialRows(org string, pats []PersonalAccessToken) []gith
 		if len(rawJSON) == 0 {
 			rawJSON = registry.MarshalJSON(map[string]any{
 				"id":                   pat.ID,
+				"token_id":             pat.TokenID,
 				"name":                 strings.TrimSpace(pat.Name),
 				"status":               strings.TrimSpace(pat.Status),
 				"owner_login":          strings.TrimSpace(pat.OwnerLogin),
@@ -1059,7 +1453,7 @@ func buildGitHubPATCredentialRows(org string, pats []PersonalAccessToken) []gith
 			CredentialKind:     "github_pat_fine_grained",
 			ExternalID:         externalID,
 			DisplayName:        displayName,
-			Fingerprint:        externalID,
+			Fingerprint:        fingerprint,
 			ScopeJSON: registry.MarshalJSON(map[string]any{
 				"organization":         organization,
 				"repository_selection": strings.TrimSpace(pat.RepositorySelection),
@@ -1166,7 +1560,70 @@ func repositoryOwner(fullName string) string {
 	return strings.TrimSpace(parts[0])
 }
 
-func buildGitHubAuditEventRows(org string, events []AuditLogEvent) []githubCredentialAuditEventUpsertRow {
+// githubCredentialRefs maps, per credential kind, the ids an audit log event can name a credential
+// by to the external id of the artifact synced for it. A fine-grained PAT, for one, is synced under
+// its grant id but audit events carry the user's token id, kept as the artifact's fingerprint.
+type githubCredentialRefs map[string]map[string]string
+
+func newGitHubCredentialRefs(rows []githubCredentialArtifactUpsertRow) githubCredentialRefs {
+	refs := githubCredentialRefs{}
+	for _, row := range rows {
+		kind := strings.TrimSpace(row.CredentialKind)
+		externalID := strings.TrimSpace(row.ExternalID)
+		if kind == "" || externalID == "" {
+			continue
+		}
+		if refs[kind] == nil {
+			refs[kind] = map[string]string{}
+		}
+		// The artifact's own id wins over another artifact's fingerprint.
+		refs[kind][externalID] = externalID
+		if fingerprint := strings.TrimSpace(row.Fingerprint); fingerprint != "" {
+			if _, taken := refs[kind][fingerprint]; !taken {
+				refs[kind][fingerprint] = externalID
+			}
+		}
+	}
+	return refs
+}
+
+// resolve returns the external id of the kind's artifact named by the first matching candidate,
+// or the first candidate when none matches a synced artifact.
+func (r githubCredentialRefs) resolve(kind string, candidates ...string) string {
+	fallback := ""
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "" {
+			continue
+		}
+		if externalID, ok := r[kind][candidate]; ok {
+			return externalID
+		}
+		if fallback == "" {
+			fallback = candidate
+		}
+	}
+	return fallback
+}
+
+// githubAuditCredentialIDCandidates lists the ids an audit event may name its credential by, most
+// specific to the credential kind first.
+func githubAuditCredentialIDCandidates(kind string, event AuditLogEvent) []string {
+	switch kind {
+	case "github_deploy_key":
+		return []string{event.DeployKeyID, event.KeyID, event.Fingerprint}
+	case "github_pat_fine_grained":
+		return []string{event.TokenID, event.PersonalTokenID}
+	case "github_pat_request":
+		return []string{event.PATRequestID, event.RequestID, event.TokenID}
+	default:
+		return []string{event.DeployKeyID, event.KeyID, event.TokenID, event.PersonalTokenID, event.PATRequestID, event.RequestID}
+	}
+}
+
+// buildGitHubAuditEventRows maps credential audit events to rows, linking each to the synced
+// artifact it names through refs so the credential's event timeline finds it.
+func buildGitHubAuditEventRows(org string, events []AuditLogEvent, refs githubCredentialRefs) []githubCredentialAuditEventUpsertRow {
 	rows := make([]githubCredentialAuditEventUpsertRow, 0, len(events))
 
 	for _, event := range events {
@@ -1201,14 +1658,7 @@ func buildGitHubAuditEventRows(org string, events []AuditLogEvent) []githubCrede
 		}
 
 		credentialKind := githubCredentialKindFromAuditAction(event.Action)
-		credentialExternalID := firstNonEmpty(
-			strings.TrimSpace(event.DeployKeyID),
-			strings.TrimSpace(event.KeyID),
-			strings.TrimSpace(event.TokenID),
-			strings.TrimSpace(event.PersonalTokenID),
-			strings.TrimSpace(event.PATRequestID),
-			strings.TrimSpace(event.RequestID),
-		)
+		credentialExternalID := refs.resolve(credentialKind, githubAuditCredentialIDCandidates(credentialKind, event)...)
 		if credentialKind == "" && credentialExternalID == "" {
 			continue
 		}
@@ -1264,6 +1714,73 @@ func buildGitHubAuditEventRows(org string, events []AuditLogEvent) []githubCrede
 	return rows
 }
 
+// buildGitHubSecretScanningAlertRows maps each alert to one audit event keyed by its repository and
+// alert number, so a later sync updates the event in place when the alert is resolved or reopened.
+// The event type carries the state, the credential external id the secret type, and the event
+// time is when the alert reached that state.
+func buildGitHubSecretScanningAlertRows(alerts []SecretScanningAlert) []githubCredentialAuditEventUpsertRow {
+	rows := make([]githubCredentialAuditEventUpsertRow, 0, len(alerts))
+
+	for _, alert := range alerts {
+		repository := strings.TrimSpace(alert.RepositoryFullName)
+		if repository == "" || alert.Number == 0 {
+			continue
+		}
+
+		state := "open"
+		resolvedBy := ""
+		eventTime := parseGitHubTime(alert.CreatedAtRaw)
+		if strings.EqualFold(strings.TrimSpace(alert.State), "resolved") {
+			state = "resolved"
+			resolvedBy = strings.TrimSpace(alert.ResolvedByLogin)
+			if resolvedAt := parseGitHubTime(alert.ResolvedAtRaw); resolvedAt.Valid {
+				eventTime = resolvedAt
+			}
+		}
+		if !eventTime.Valid {
+			eventTime = parseGitHubTime(alert.UpdatedAtRaw)
+		}
+		if !eventTime.Valid {
+			continue
+		}
+
+		actorKind := "unknown"
+		if resolvedBy != "" {
+			actorKind = "github_user"
+		}
+
+		secretType := firstNonEmpty(strings.TrimSpace(alert.SecretType), "unknown")
+		rows = append(rows, githubCredentialAuditEventUpsertRow{
+			EventExternalID:      fmt.Sprintf("secret_scanning_alert:%s:%d", repository, alert.Number),
+			EventType:            "secret_scanning_alert." + state,
+			EventTime:            eventTime,
+			ActorKind:            actorKind,
+			ActorExternalID:      resolvedBy,
+			ActorDisplayName:     resolvedBy,
+			TargetKind:           "repository",
+			TargetExternalID:     repository,
+			TargetDisplayName:    repository,
+			CredentialKind:       "github_secret_scanning_alert",
+			CredentialExternalID: secretType,
+			RawJSON: registry.MarshalJSON(map[string]any{
+				"number":                   alert.Number,
+				"state":                    state,
+				"resolution":               strings.TrimSpace(alert.Resolution),
+				"secret_type":              secretType,
+				"secret_type_display_name": strings.TrimSpace(alert.SecretTypeDisplayName),
+				"repository":               repository,
+				"resolved_by":              resolvedBy,
+				"html_url":                 strings.TrimSpace(alert.HTMLURL),
+				"created_at":               strings.TrimSpace(alert.CreatedAtRaw),
+				"updated_at":               strings.TrimSpace(alert.UpdatedAtRaw),
+				"resolved_at":              strings.TrimSpace(alert.ResolvedAtRaw),
+			}),
+		})
+	}
+
+	return rows
+}
+
 func githubCredentialKindFromAuditAction(action string) string {
 	action = strings.ToLower(strings.TrimSpace(action))
 	switch {
@@ -6,8 +6,10 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 
+	"github.com/open-sspm/open-sspm/internal/connectors/configstore"
 	"github.com/open-sspm/open-sspm/internal/connectors/registry"
 )
 
@@ -96,7 +98,7 @@ func TestBuildGitHubAuditEventRowsPATRequest(t *testing.T) {
 			CreatedAtRaw: "2026-01-07T00:00:00Z",
 			RequestID:    "701",
 		},
-	})
+	}, nil)
 	if len(rows) != 1 {
 		t.Fatalf("expected 1 row, got %d", len(rows))
 	}
@@ -113,14 +115,58 @@ func TestBuildGitHubAuditEventRowsPATRequest(t *testing.T) {
 	}
 }
 
+func TestBuildGitHubAuditEventRowsLinksPATToArtifact(t *testing.T) {
+	t.Parallel()
+
+	pat := parseTestGitHubPAT(t, `{"id":25381,"token_id":98716,"token_name":"deploy bot","owner":{"login":"octocat","id":1}}`)
+	credentials := buildGitHubPATCredentialRows("acme", []PersonalAccessToken{pat})
+	if len(credentials) != 1 || credentials[0].ExternalID != "25381" {
+		t.Fatalf("pat credential rows = %+v, want one keyed by the grant id", credentials)
+	}
+
+	// The audit log names the PAT by the user's token id, not the grant id.
+	rows := buildGitHubAuditEventRows("acme", []AuditLogEvent{
+		{
+			DocumentID:   "doc-1",
+			Action:       "personal_access_token.access_granted",
+			Actor:        "octocat",
+			CreatedAtRaw: "2026-01-07T00:00:00Z",
+			TokenID:      "98716",
+		},
+		{
+			DocumentID:   "doc-2",
+			Action:       "personal_access_token.access_revoked",
+			Actor:        "octocat",
+			CreatedAtRaw: "2026-01-08T00:00:00Z",
+			TokenID:      "55555",
+		},
+	}, newGitHubCredentialRefs(credentials))
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0].CredentialKind != credentials[0].CredentialKind || rows[0].CredentialExternalID != credentials[0].ExternalID {
+		t.Fatalf("event credential = %q/%q, want %q/%q", rows[0].CredentialKind, rows[0].CredentialExternalID, credentials[0].CredentialKind, credentials[0].ExternalID)
+	}
+	// An event for a token that was not synced keeps the id the event names.
+	if rows[1].CredentialExternalID != "55555" {
+		t.Fatalf("unmatched event credential external id = %q, want 55555", rows[1].CredentialExternalID)
+	}
+}
+
+func parseTestGitHubPAT(t *testing.T, raw string) PersonalAccessToken {
+	t.Helper()
+	pat, err := parseGitHubPAT(json.RawMessage(raw))
+	if err != nil {
+		t.Fatalf("parseGitHubPAT() error = %v", err)
+	}
+	return pat
+}
+
 func TestSyncProgrammaticAccessFailsWhenDatasetUnavailable(t *testing.T) {
 	t.Parallel()
 
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
-		case "/orgs/acme/repos":
-			w.Header().Set("Content-Type", "application/json")
-			_, _ = w.Write([]byte(`[]`))
 		case "/orgs/acme/personal-access-token-requests":
 			http.Error(w, `{"message":"forbidden"}`, http.StatusForbidden)
 		default:
@@ -134,8 +180,8 @@ func TestSyncProgrammaticAccessFailsWhenDatasetUnavailable(t *testing.T) {
 		t.Fatalf("New() error = %v", err)
 	}
 
-	integration := NewGitHubIntegration(client, "acme", "", 1, false)
-	_, err = integration.syncProgrammaticAccess(context.Background(), nil, func(registry.Event) {}, 42)
+	integration := NewGitHubIntegration(client, "acme", "", 1, false, false)
+	_, err = integration.syncProgrammaticAccess(context.Background(), nil, nil, func(registry.Event) {}, 42, nil, nil)
 	if err == nil {
 		t.Fatalf("syncProgrammaticAccess() error = nil, want non-nil")
 	}
@@ -151,3 +197,319 @@ func TestSyncProgrammaticAccessFailsWhenDatasetUnavailable(t *testing.T) {
 		t.Fatalf("syncProgrammaticAccess() error kind = %q, want %q", syncErr.kind, registry.SyncErrorKindAPI)
 	}
 }
+
+func TestBuildGitHubSecretScanningAlertRowsMapsState(t *testing.T) {
+	t.Parallel()
+
+	rows := buildGitHubSecretScanningAlertRows([]SecretScanningAlert{
+		{
+			Number:             3,
+			State:              "open",
+			SecretType:         "github_personal_access_token",
+			RepositoryFullName: "acme/api",
+			CreatedAtRaw:       "2026-02-01T10:00:00Z",
+			UpdatedAtRaw:       "2026-02-02T10:00:00Z",
+		},
+		{
+			Number:             4,
+			State:              "resolved",
+			Resolution:         "revoked",
+			SecretType:         "aws_access_key_id",
+			RepositoryFullName: "acme/web",
+			ResolvedByLogin:    "security-admin",
+			CreatedAtRaw:       "2026-01-01T10:00:00Z",
+			ResolvedAtRaw:      "2026-01-05T10:00:00Z",
+		},
+		{Number: 5, State: "open", SecretType: "slack_api_token", CreatedAtRaw: "2026-01-01T10:00:00Z"},
+	})
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+
+	open, resolved := rows[0], rows[1]
+	if open.EventExternalID != "secret_scanning_alert:acme/api:3" || open.EventType != "secret_scanning_alert.open" {
+		t.Fatalf("open alert = %q/%q", open.EventExternalID, open.EventType)
+	}
+	if open.TargetKind != "repository" || open.TargetExternalID != "acme/api" {
+		t.Fatalf("open alert target = %q/%q, want repository acme/api", open.TargetKind, open.TargetExternalID)
+	}
+	if open.CredentialKind != "github_secret_scanning_alert" || open.CredentialExternalID != "github_personal_access_token" {
+		t.Fatalf("open alert credential = %q/%q", open.CredentialKind, open.CredentialExternalID)
+	}
+	if open.ActorKind != "unknown" || open.ActorExternalID != "" || open.EventTime.Time.Day() != 1 {
+		t.Fatalf("open alert actor=%q/%q time=%s, want no actor at creation", open.ActorKind, open.ActorExternalID, open.EventTime.Time)
+	}
+
+	if resolved.EventType != "secret_scanning_alert.resolved" || resolved.CredentialExternalID != "aws_access_key_id" {
+		t.Fatalf("resolved alert = %q/%q", resolved.EventType, resolved.CredentialExternalID)
+	}
+	if resolved.ActorKind != "github_user" || resolved.ActorExternalID != "security-admin" || resolved.EventTime.Time.Day() != 5 {
+		t.Fatalf("resolved alert actor=%q/%q time=%s, want resolver at resolution", resolved.ActorKind, resolved.ActorExternalID, resolved.EventTime.Time)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(resolved.RawJSON, &raw); err != nil {
+		t.Fatalf("unmarshal raw json: %v", err)
+	}
+	if raw["state"] != "resolved" || raw["resolution"] != "revoked" {
+		t.Fatalf("resolved alert raw json = %v", raw)
+	}
+}
+
+func TestSyncSecretScanningAlertsWarnsWhenDatasetUnavailable(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Resource not accessible by personal access token"}`, http.StatusForbidden)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := New(srv.URL, "token")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	warnings := registry.NewWarningReporter(func(registry.Event) {})
+	count, err := NewGitHubIntegration(client, "acme", "", 1, false, true).syncSecretScanningAlerts(context.Background(), nil, nil, warnings)
+	if err != nil || count != 0 {
+		t.Fatalf("syncSecretScanningAlerts() = %d, %v, want 0, nil", count, err)
+	}
+	if got := warnings.Warnings(); len(got) != 1 || got[0].Stage != "list-secret-scanning-alerts" {
+		t.Fatalf("warnings = %+v, want one secret scanning warning", got)
+	}
+}
+
+func TestEnrichGitHubOwnerEmailsFromSCIMLogin(t *testing.T) {
+	t.Parallel()
+
+	resolver := githubLoginEmailResolver{
+		scim: true,
+		scimByLogin: map[string]SCIMUser{
+			"octocat": {UserName: "octocat", Emails: []SCIMEmail{{Value: "octocat@example.com", Primary: true}}},
+		},
+	}
+
+	_, ownerRows := buildGitHubInstallationRows([]AppInstallation{
+		{ID: 1, AppSlug: "deploy-helper", AccountLogin: "OctoCat", AccountType: "User"},
+		{ID: 2, AppSlug: "ci", AccountLogin: "stranger", AccountType: "User"},
+		{ID: 3, AppSlug: "ci", AccountLogin: "acme", AccountType: "Organization"},
+	})
+	enrichGitHubOwnerEmails(ownerRows, resolver.Resolve)
+
+	if len(ownerRows) != 3 {
+		t.Fatalf("expected 3 owner rows, got %d", len(ownerRows))
+	}
+	if ownerRows[0].OwnerEmail != "octocat@example.com" {
+		t.Fatalf("owner email = %q, want octocat@example.com", ownerRows[0].OwnerEmail)
+	}
+	if ownerRows[1].OwnerEmail != "" || ownerRows[2].OwnerEmail != "" {
+		t.Fatalf("unexpected owner emails %q %q", ownerRows[1].OwnerEmail, ownerRows[2].OwnerEmail)
+	}
+
+	auditRows := []githubCredentialAuditEventUpsertRow{
+		{ActorKind: "github_user", ActorExternalID: "octocat", ActorDisplayName: "octocat"},
+		{ActorKind: "github_user", ActorExternalID: "stranger", ActorDisplayName: "stranger"},
+	}
+	enrichGitHubAuditActorEmails(auditRows, resolver.Resolve)
+	if auditRows[0].ActorDisplayName != "octocat@example.com" || auditRows[0].ActorExternalID != "octocat" {
+		t.Fatalf("audit actor = %+v", auditRows[0])
+	}
+	if auditRows[1].ActorDisplayName != "stranger" {
+		t.Fatalf("audit actor = %+v", auditRows[1])
+	}
+}
+
+func TestBuildGitHubOutsideCollaboratorRows(t *testing.T) {
+	t.Parallel()
+
+	members := []Member{{Login: "alice", ID: 1, AccountType: "User", RawJSON: []byte(`{"login":"alice"}`)}}
+	member, ok := buildGitHubMemberUserRow(members[0], false)
+	if !ok {
+		t.Fatalf("member row skipped")
+	}
+	collaborators := []OutsideCollaborator{
+		{Login: "contractor", ID: 7, AccountType: "User", RawJSON: []byte(`{"login":"contractor","id":7}`)},
+		{Login: "Alice", ID: 1, AccountType: "User"},
+	}
+	repoCollaborators := map[string][]RepoCollaborator{
+		"acme/api": {
+			{Login: "contractor", ID: 7, Permission: "push"},
+			{Login: "alice", ID: 1, Permission: "admin"},
+		},
+	}
+
+	users, ents := buildGitHubOutsideCollaboratorRows(members, collaborators, repoCollaborators, func(login string) string {
+		if login == "contractor" {
+			return "Contractor@Example.com"
+		}
+		return ""
+	})
+	if len(users) != 1 {
+		t.Fatalf("expected 1 outside collaborator user, got %+v", users)
+	}
+	user := users[0]
+	if user.ExternalID != "contractor" || user.Email != "contractor@example.com" || user.AccountKind != registry.AccountKindHuman {
+		t.Fatalf("unexpected outside collaborator row: %+v", user)
+	}
+
+	var userRaw, memberRaw map[string]any
+	if err := json.Unmarshal(user.RawJSON, &userRaw); err != nil {
+		t.Fatalf("collaborator raw json unmarshal: %v", err)
+	}
+	if err := json.Unmarshal(member.RawJSON, &memberRaw); err != nil {
+		t.Fatalf("member raw json unmarshal: %v", err)
+	}
+	if userRaw["is_outside_collaborator"] != true || userRaw["entity_category"] != registry.EntityCategoryUser {
+		t.Fatalf("collaborator raw json = %s", user.RawJSON)
+	}
+	if _, flagged := memberRaw["is_outside_collaborator"]; flagged {
+		t.Fatalf("member raw json is flagged as outside collaborator: %s", member.RawJSON)
+	}
+
+	if len(ents) != 1 {
+		t.Fatalf("expected 1 entitlement, got %+v", ents)
+	}
+	ent := ents[0]
+	if ent.AppUserExternalID != "contractor" || ent.Kind != "github_outside_collaborator_repo_permission" || ent.Resource != "github_repo:acme/api" || ent.Permission != "push" {
+		t.Fatalf("unexpected entitlement: %+v", ent)
+	}
+}
+
+func TestGitHubIntegrationTestConnection(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		scopes      string
+		wantMissing []string
+	}{
+		{name: "enable blocked on missing scope", scopes: "read:org", wantMissing: []string{"repo", "read:audit_log"}},
+		{name: "enable allowed with sufficient scope", scopes: "admin:org, repo, read:audit_log"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/orgs/acme" {
+					http.NotFound(w, r)
+					return
+				}
+				w.Header().Set("X-OAuth-Scopes", tc.scopes)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"login":"acme","id":1}`))
+			}))
+			t.Cleanup(srv.Close)
+
+			client, err := New(srv.URL, "token")
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			integration := NewGitHubIntegration(client, "acme", "", 1, false, false)
+
+			err = registry.VerifyConnection(context.Background(), integration)
+			if len(tc.wantMissing) == 0 {
+				if err != nil {
+					t.Fatalf("VerifyConnection() error = %v, want nil", err)
+				}
+				return
+			}
+			var missing *registry.MissingGrantsError
+			if !errors.As(err, &missing) || !slices.Equal(missing.Missing, tc.wantMissing) {
+				t.Fatalf("VerifyConnection() error = %v, want missing %v", err, tc.wantMissing)
+			}
+		})
+	}
+
+	// An unknown org fails the connection test rather than the first sync.
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+	client, err := New(srv.URL, "token")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := registry.VerifyConnection(context.Background(), NewGitHubIntegration(client, "acme", "", 1, false, false)); err == nil {
+		t.Fatalf("VerifyConnection() for unknown org error = nil")
+	}
+}
+
+func TestDefinitionNewIntegrationWorkers(t *testing.T) {
+	t.Parallel()
+
+	def := NewDefinition(6)
+	for _, tc := range []struct {
+		workers int
+		want    int
+	}{
+		{workers: 20, want: 20},
+		{workers: configstore.MaxGitHubWorkers, want: configstore.MaxGitHubWorkers},
+		{workers: 0, want: 6},
+		{workers: -3, want: 6},
+	} {
+		integration, err := def.NewIntegration(configstore.GitHubConfig{Org: "acme", APIBase: "https://api.github.com", Token: "token", Workers: tc.workers})
+		if err != nil {
+			t.Fatalf("NewIntegration(workers=%d): %v", tc.workers, err)
+		}
+		if got := integration.(*GitHubIntegration).workers; got != tc.want {
+			t.Fatalf("NewIntegration(workers=%d) workers = %d, want %d", tc.workers, got, tc.want)
+		}
+	}
+
+	if got := NewGitHubIntegration(nil, "acme", "", -3, false, false).workers; got != defaultGitHubWorkers {
+		t.Fatalf("NewGitHubIntegration(workers=-3) workers = %d, want %d", got, defaultGitHubWorkers)
+	}
+}
+
+func TestListOutsideCollaboratorsHoldsUnreadableRepositories(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/orgs/acme/outside_collaborators":
+			_, _ = w.Write([]byte(`[{"login":"contractor","id":7}]`))
+		case "/repos/acme/api/collaborators":
+			_, _ = w.Write([]byte(`[{"login":"contractor","id":7,"role_name":"push"}]`))
+		case "/repos/acme/web/collaborators":
+			http.Error(w, `{"message":"forbidden"}`, http.StatusForbidden)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := New(srv.URL, "token")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	integration := NewGitHubIntegration(client, "acme", "", 2, false, false)
+	warnings := registry.NewWarningReporter(nil)
+	holds := registry.NewExpiryHolds()
+
+	collaborators, repoCollaborators := integration.listOutsideCollaborators(context.Background(), func(registry.Event) {}, warnings, holds, []Repository{
+		{Name: "api", FullName: "acme/api"},
+		{Name: "web", FullName: "acme/web"},
+	})
+	if len(collaborators) != 1 || collaborators[0].Login != "contractor" {
+		t.Fatalf("collaborators = %+v, want contractor", collaborators)
+	}
+	if len(repoCollaborators) != 1 || len(repoCollaborators["acme/api"]) != 1 {
+		t.Fatalf("repo collaborators = %+v, want only acme/api", repoCollaborators)
+	}
+	if len(warnings.Warnings()) != 1 {
+		t.Fatalf("warnings = %+v, want one for acme/web", warnings.Warnings())
+	}
+	if holds.Empty() {
+		t.Fatalf("expiry holds are empty, want acme/web's permissions held")
+	}
+
+	// Without an owner token the org listing fails: members sync on and collaborators are held.
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"forbidden"}`, http.StatusForbidden)
+	})
+	warnings = registry.NewWarningReporter(nil)
+	holds = registry.NewExpiryHolds()
+	collaborators, repoCollaborators = integration.listOutsideCollaborators(context.Background(), func(registry.Event) {}, warnings, holds, []Repository{{Name: "api", FullName: "acme/api"}})
+	if collaborators != nil || repoCollaborators != nil || len(warnings.Warnings()) != 1 || holds.Empty() {
+		t.Fatalf("org lookup failure: collaborators = %+v, repos = %+v, warnings = %+v, holds empty = %v", collaborators, repoCollaborators, warnings.Warnings(), holds.Empty())
+	}
+}
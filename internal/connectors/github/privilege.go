@@ -0,0 +1,21 @@
+package github
+
+import "github.com/open-sspm/open-sspm/internal/connectors/registry"
+
+// githubPrivilegeMapping maps GitHub org roles and repository permissions to canonical levels.
+// Repository permissions follow GitHub's role ladder: pull < triage < push < maintain < admin.
+var githubPrivilegeMapping = registry.PrivilegeMapping{
+	{Kind: "github_org_role", Permission: "admin", Level: registry.PrivilegeOwner},
+	{Kind: "github_org_role", Level: registry.PrivilegeRead},
+	{Kind: "github_team_member", Level: registry.PrivilegeRead},
+	{Kind: "github_team_repo_permission", Permission: "admin", Level: registry.PrivilegeAdmin},
+	{Kind: "github_team_repo_permission", Permission: "maintain", Level: registry.PrivilegeWrite},
+	{Kind: "github_team_repo_permission", Permission: "push", Level: registry.PrivilegeWrite},
+	{Kind: "github_team_repo_permission", Permission: "triage", Level: registry.PrivilegeRead},
+	{Kind: "github_team_repo_permission", Permission: "pull", Level: registry.PrivilegeRead},
+	{Kind: "github_outside_collaborator_repo_permission", Permission: "admin", Level: registry.PrivilegeAdmin},
+	{Kind: "github_outside_collaborator_repo_permission", Permission: "maintain", Level: registry.PrivilegeWrite},
+	{Kind: "github_outside_collaborator_repo_permission", Permission: "push", Level: registry.PrivilegeWrite},
+	{Kind: "github_outside_collaborator_repo_permission", Permission: "triage", Level: registry.PrivilegeRead},
+	{Kind: "github_outside_collaborator_repo_permission", Permission: "pull", Level: registry.PrivilegeRead},
+}
@@ -0,0 +1,30 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+)
+
+func TestGitHubPrivilegeMapping(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		kind, permission string
+		want             registry.PrivilegeLevel
+	}{
+		{kind: "github_org_role", permission: "admin", want: registry.PrivilegeOwner},
+		{kind: "github_org_role", permission: "member", want: registry.PrivilegeRead},
+		{kind: "github_team_repo_permission", permission: "admin", want: registry.PrivilegeAdmin},
+		{kind: "github_team_repo_permission", permission: "maintain", want: registry.PrivilegeWrite},
+		{kind: "github_team_repo_permission", permission: "triage", want: registry.PrivilegeRead},
+		{kind: "github_outside_collaborator_repo_permission", permission: "admin", want: registry.PrivilegeAdmin},
+		{kind: "github_outside_collaborator_repo_permission", permission: "maintain", want: registry.PrivilegeWrite},
+		{kind: "github_team_member", permission: "member", want: registry.PrivilegeRead},
+	}
+	for _, tc := range cases {
+		if got := githubPrivilegeMapping.Level(tc.kind, tc.permission, ""); got != tc.want {
+			t.Fatalf("githubPrivilegeMapping.Level(%q, %q) = %v, want %v", tc.kind, tc.permission, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+var _ registry.CredentialRevoker = (*GitHubIntegration)(nil)
+
+// Revoke deletes a deploy key or revokes a fine-grained PAT's access to the organization.
+// Other credential kinds, and credentials whose id was synthesized from the audit log, are
+// reported as unsupported.
+func (i *GitHubIntegration) Revoke(ctx context.Context, credential gen.CredentialArtifact) error {
+	if !strings.EqualFold(strings.TrimSpace(credential.SourceName), i.org) {
+		return fmt.Errorf("credential belongs to %q, not %q", strings.TrimSpace(credential.SourceName), i.org)
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(credential.ExternalID), 10, 64)
+	if err != nil || id <= 0 {
+		return registry.ErrRevocationUnsupported
+	}
+
+	switch strings.TrimSpace(credential.CredentialKind) {
+	case "github_deploy_key":
+		owner, repo, ok := strings.Cut(strings.TrimSpace(credential.AssetRefExternalID), "/")
+		if !ok || owner == "" || repo == "" {
+			return registry.ErrRevocationUnsupported
+		}
+		return i.client.DeleteRepoDeployKey(ctx, owner, repo, id)
+	case "github_pat_fine_grained":
+		return i.client.RevokeOrgPersonalAccessToken(ctx, i.org, id)
+	default:
+		return registry.ErrRevocationUnsupported
+	}
+}
@@ -0,0 +1,67 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestGitHubIntegrationRevoke(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+string(body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, "token")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	integration := NewGitHubIntegration(c, "acme", "", 1, false, false)
+	ctx := context.Background()
+
+	if err := integration.Revoke(ctx, gen.CredentialArtifact{
+		SourceName:         "acme",
+		CredentialKind:     "github_deploy_key",
+		ExternalID:         "42",
+		AssetRefExternalID: "acme/api",
+	}); err != nil {
+		t.Fatalf("Revoke(deploy key): %v", err)
+	}
+	if err := integration.Revoke(ctx, gen.CredentialArtifact{
+		SourceName:     "acme",
+		CredentialKind: "github_pat_fine_grained",
+		ExternalID:     "7",
+	}); err != nil {
+		t.Fatalf("Revoke(pat): %v", err)
+	}
+	want := []string{
+		"DELETE /repos/acme/api/keys/42 ",
+		`POST /orgs/acme/personal-access-tokens/7 {"action":"revoke"}`,
+	}
+	if len(calls) != len(want) || calls[0] != want[0] || calls[1] != want[1] {
+		t.Fatalf("calls = %q, want %q", calls, want)
+	}
+
+	for _, credential := range []gen.CredentialArtifact{
+		{SourceName: "acme", CredentialKind: "github_pat_request", ExternalID: "9"},
+		{SourceName: "acme", CredentialKind: "github_deploy_key", ExternalID: "audit:deploy_key:abc", AssetRefExternalID: "acme/api"},
+	} {
+		if err := integration.Revoke(ctx, credential); !errors.Is(err, registry.ErrRevocationUnsupported) {
+			t.Fatalf("Revoke(%s %s) error = %v, want ErrRevocationUnsupported", credential.CredentialKind, credential.ExternalID, err)
+		}
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("unsupported credentials reached the API: %q", calls)
+	}
+}
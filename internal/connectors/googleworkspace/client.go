@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/open-sspm/open-sspm/internal/connectors/configstore"
+	"github.com/open-sspm/open-sspm/internal/connectors/httpclient"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 )
@@ -71,12 +72,18 @@ type Client struct {
 	cachedTokenExp  time.Time
 	parsedPrivatePK *rsa.PrivateKey
 	saClientEmail   string
+	saClientID      string
 }
 
 type WorkspaceUser struct {
-	ID           string `json:"id"`
-	PrimaryEmail string `json:"primaryEmail"`
-	Suspended    bool   `json:"suspended"`
+	ID               string `json:"id"`
+	PrimaryEmail     string `json:"primaryEmail"`
+	Suspended        bool   `json:"suspended"`
+	SuspensionReason string `json:"suspensionReason"`
+	Archived         bool   `json:"archived"`
+	// DeletionTime is only set on users returned with showDeleted, which are pending
+	// permanent deletion and can still be restored.
+	DeletionTime string `json:"deletionTime"`
 	Name         struct {
 		FullName string `json:"fullName"`
 	} `json:"name"`
@@ -169,7 +176,7 @@ func NewClientWithOptions(cfg configstore.GoogleWorkspaceConfig, opts ClientOpti
 
 	httpClient := opts.HTTPClient
 	if httpClient == nil {
-		httpClient = &http.Client{Timeout: defaultGoogleTimeout}
+		httpClient = httpclient.NewForKind(configstore.KindGoogleWorkspace, defaultGoogleTimeout)
 	}
 
 	directoryBaseURL := strings.TrimRight(strings.TrimSpace(opts.DirectoryBaseURL), "/")
@@ -244,6 +251,7 @@ func normalizeScopes(scopes []string) []string {
 func (c *Client) initServiceAccountJSON() error {
 	var payload struct {
 		ClientEmail string `json:"client_email"`
+		ClientID    string `json:"client_id"`
 		PrivateKey  string `json:"private_key"`
 		TokenURI    string `json:"token_uri"`
 	}
@@ -259,6 +267,7 @@ func (c *Client) initServiceAccountJSON() error {
 	if c.saClientEmail == "" {
 		return errors.New("service account json missing client_email")
 	}
+	c.saClientID = strings.TrimSpace(payload.ClientID)
 	c.parsedPrivatePK = privateKey
 	if tokenURI := strings.TrimSpace(payload.TokenURI); tokenURI != "" {
 		c.tokenURL = tokenURI
@@ -290,16 +299,30 @@ func parseRSAPrivateKey(raw string) (*rsa.PrivateKey, error) {
 }
 
 func (c *Client) ListUsers(ctx context.Context, customerID string) ([]WorkspaceUser, error) {
+	return c.listUsers(ctx, customerID, false)
+}
+
+// ListDeletedUsers returns users deleted within the restore window. The Directory API only
+// returns them when showDeleted is set, and then returns nothing else.
+func (c *Client) ListDeletedUsers(ctx context.Context, customerID string) ([]WorkspaceUser, error) {
+	return c.listUsers(ctx, customerID, true)
+}
+
+func (c *Client) listUsers(ctx context.Context, customerID string, showDeleted bool) ([]WorkspaceUser, error) {
 	customerID = strings.TrimSpace(customerID)
 	if customerID == "" {
 		return nil, errors.New("google workspace customer id is required")
 	}
 	endpoint := c.directoryBaseURL + "/users"
-	items, err := c.listDirectoryPaged(ctx, endpoint, "users", url.Values{
+	params := url.Values{
 		"customer":   []string{customerID},
 		"maxResults": []string{"500"},
 		"orderBy":    []string{"email"},
-	})
+	}
+	if showDeleted {
+		params.Set("showDeleted", "true")
+	}
+	items, err := c.listDirectoryPaged(ctx, endpoint, "users", params)
 	if err != nil {
 		return nil, err
 	}
@@ -617,6 +640,27 @@ func cloneURLValues(values url.Values) url.Values {
 
 var errNotFound = errors.New("google api resource not found")
 
+const (
+	googleOpAPIRequest    = "google api request"
+	googleOpTokenExchange = "google oauth token exchange"
+	googleOpIAMSignJWT    = "google iam signJwt"
+)
+
+// googleResponseError is a non-2xx response from a Google endpoint. It keeps the status and body
+// so setup problems can be told apart (see explainSetupError).
+type googleResponseError struct {
+	op         string
+	statusCode int
+	body       string
+}
+
+func (e *googleResponseError) Error() string {
+	return fmt.Sprintf("%s failed: status=%d body=%s", e.op, e.statusCode, e.body)
+}
+
+// HTTPStatusCode reports the response status for sync error classification.
+func (e *googleResponseError) HTTPStatusCode() int { return e.statusCode }
+
 func (c *Client) doAuthorizedJSONRequest(ctx context.Context, method, requestURL string, body []byte) ([]byte, int, error) {
 	var lastErr error
 	statusCode := 0
@@ -629,7 +673,7 @@ func (c *Client) doAuthorizedJSONRequest(ctx context.Context, method, requestURL
 				return nil, statusCode, ctx.Err()
 			case <-time.After(backoff):
 			}
-			backoff = minDuration(backoff*2, 8*time.Second)
+			backoff = minDuration(backoff*2, httpclient.MaxBackoff(configstore.KindGoogleWorkspace))
 		}
 
 		accessToken, err := c.accessToken(ctx)
@@ -670,7 +714,7 @@ func (c *Client) doAuthorizedJSONRequest(ctx context.Context, method, requestURL
 		}
 
 		if !shouldRetryGoogleStatus(statusCode) {
-			return nil, statusCode, fmt.Errorf("google api request failed: status=%d body=%s", statusCode, strings.TrimSpace(string(respBody)))
+			return nil, statusCode, &googleResponseError{op: googleOpAPIRequest, statusCode: statusCode, body: strings.TrimSpace(string(respBody))}
 		}
 
 		lastErr = fmt.Errorf("google api temporary failure: status=%d body=%s", statusCode, strings.TrimSpace(string(respBody)))
@@ -711,7 +755,7 @@ func (c *Client) accessToken(ctx context.Context) (string, error) {
 	}
 	c.mu.Unlock()
 
-	token, expiry, err := c.fetchAccessToken(ctx)
+	token, expiry, err := c.fetchAccessToken(ctx, c.scopes)
 	if err != nil {
 		return "", err
 	}
@@ -723,8 +767,10 @@ func (c *Client) accessToken(ctx context.Context) (string, error) {
 	return token, nil
 }
 
-func (c *Client) fetchAccessToken(ctx context.Context) (string, time.Time, error) {
-	assertion, err := c.signedAssertion(ctx)
+// fetchAccessToken exchanges a signed assertion impersonating the delegated admin for a token
+// carrying scopes.
+func (c *Client) fetchAccessToken(ctx context.Context, scopes []string) (string, time.Time, error) {
+	assertion, err := c.signedAssertion(ctx, scopes)
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -751,7 +797,7 @@ func (c *Client) fetchAccessToken(ctx context.Context) (string, time.Time, error
 		return "", time.Time{}, err
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", time.Time{}, fmt.Errorf("google oauth token exchange failed: status=%d body=%s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		return "", time.Time{}, &googleResponseError{op: googleOpTokenExchange, statusCode: resp.StatusCode, body: strings.TrimSpace(string(respBody))}
 	}
 
 	var payload struct {
@@ -773,14 +819,14 @@ func (c *Client) fetchAccessToken(ctx context.Context) (string, time.Time, error
 	return payload.AccessToken, expiry, nil
 }
 
-func (c *Client) signedAssertion(ctx context.Context) (string, error) {
+func (c *Client) signedAssertion(ctx context.Context, scopes []string) (string, error) {
 	issuedAt := time.Now().UTC()
 	expiresAt := issuedAt.Add(1 * time.Hour)
 
 	claims := map[string]any{
 		"iss":   c.issuer(),
 		"sub":   strings.TrimSpace(c.cfg.DelegatedAdminEmail),
-		"scope": strings.Join(c.scopes, " "),
+		"scope": strings.Join(scopes, " "),
 		"aud":   c.tokenURL,
 		"iat":   issuedAt.Unix(),
 		"exp":   expiresAt.Unix(),
@@ -838,7 +884,9 @@ func (c *Client) signJWTViaIAM(ctx context.Context, claims map[string]any) (stri
 	adcTS := c.adcTokenSource
 	if adcTS == nil {
 		var err error
-		adcTS, err = google.DefaultTokenSource(ctx, "https://www.googleapis.com/auth/cloud-platform")
+		// Fetch ADC tokens through the connector client so proxy and CA settings apply.
+		adcCtx := context.WithValue(ctx, oauth2.HTTPClient, c.http)
+		adcTS, err = google.DefaultTokenSource(adcCtx, "https://www.googleapis.com/auth/cloud-platform")
 		if err != nil {
 			return "", fmt.Errorf("google adc token source: %w", err)
 		}
@@ -878,7 +926,7 @@ func (c *Client) signJWTViaIAM(ctx context.Context, claims map[string]any) (stri
 		return "", err
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("google iam signJwt failed: status=%d body=%s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		return "", &googleResponseError{op: googleOpIAMSignJWT, statusCode: resp.StatusCode, body: strings.TrimSpace(string(respBody))}
 	}
 
 	var payload struct {
@@ -5,12 +5,14 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -201,7 +203,7 @@ func TestSignedAssertionADCUsesSignJWT(t *testing.T) {
 		t.Fatalf("NewClientWithOptions() error = %v", err)
 	}
 
-	signedJWT, err := client.signedAssertion(context.Background())
+	signedJWT, err := client.signedAssertion(context.Background(), client.scopes)
 	if err != nil {
 		t.Fatalf("signedAssertion() error = %v", err)
 	}
@@ -244,7 +246,7 @@ func TestFetchAccessTokenFailsOnExchangeError(t *testing.T) {
 		t.Fatalf("NewClientWithOptions() error = %v", err)
 	}
 
-	_, _, err = client.fetchAccessToken(context.Background())
+	_, _, err = client.fetchAccessToken(context.Background(), client.scopes)
 	if err == nil {
 		t.Fatalf("fetchAccessToken() expected error")
 	}
@@ -253,6 +255,80 @@ func TestFetchAccessTokenFailsOnExchangeError(t *testing.T) {
 	}
 }
 
+// TestRotatedServiceAccountAppliesToNextIntegration pins how key rotation works without a
+// restart: every sync builds its integration from the stored config, so an integration that is
+// already running keeps signing with the key it was built with, and the next one uses the new key.
+func TestRotatedServiceAccountAppliesToNextIntegration(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		issuers []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm() error = %v", err)
+		}
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		if len(parts) != 3 {
+			t.Errorf("assertion has %d parts, want 3", len(parts))
+			return
+		}
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			t.Errorf("decode assertion payload: %v", err)
+			return
+		}
+		var claims struct {
+			Iss string `json:"iss"`
+		}
+		_ = json.Unmarshal(payload, &claims)
+		mu.Lock()
+		issuers = append(issuers, claims.Iss)
+		mu.Unlock()
+		_, _ = io.WriteString(w, `{"access_token":"token","expires_in":3600,"token_type":"Bearer"}`)
+	}))
+	defer server.Close()
+
+	oldCfg := testServiceAccountConfig(t, server.URL+"/token")
+	newCfg := oldCfg
+	newCfg.ServiceAccountJSON = strings.Replace(testServiceAccountJSON(t, server.URL+"/token"), "svc-account@", "svc-rotated@", 1)
+
+	def := &Definition{}
+	running, err := def.NewIntegration(oldCfg)
+	if err != nil {
+		t.Fatalf("NewIntegration(old) error = %v", err)
+	}
+	inFlight := running.(*GoogleWorkspaceIntegration).client
+	if _, err := inFlight.accessToken(context.Background()); err != nil {
+		t.Fatalf("accessToken() before rotation error = %v", err)
+	}
+
+	next, err := def.NewIntegration(newCfg)
+	if err != nil {
+		t.Fatalf("NewIntegration(new) error = %v", err)
+	}
+	// The running sync refreshes its token after the rotation and still signs with the old key.
+	inFlight.invalidateToken()
+	if _, err := inFlight.accessToken(context.Background()); err != nil {
+		t.Fatalf("accessToken() of the running sync error = %v", err)
+	}
+	if _, err := next.(*GoogleWorkspaceIntegration).client.accessToken(context.Background()); err != nil {
+		t.Fatalf("accessToken() of the next sync error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{
+		"svc-account@example.iam.gserviceaccount.com",
+		"svc-account@example.iam.gserviceaccount.com",
+		"svc-rotated@example.iam.gserviceaccount.com",
+	}
+	if strings.Join(issuers, ",") != strings.Join(want, ",") {
+		t.Fatalf("token requests signed by %v, want %v", issuers, want)
+	}
+}
+
 func TestListGroupMembersReturnsEmptyOnNotFound(t *testing.T) {
 	t.Parallel()
 
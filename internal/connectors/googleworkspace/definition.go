@@ -82,7 +82,19 @@ func (d *Definition) NewIntegration(cfg any) (registry.Integration, error) {
 	if err != nil {
 		return nil, err
 	}
-	return NewGoogleWorkspaceIntegration(client, googleCfg.CustomerID, googleCfg.PrimaryDomain, googleCfg.DiscoveryEnabled), nil
+	return NewGoogleWorkspaceIntegration(client, googleCfg.CustomerID, googleCfg.PrimaryDomain, googleCfg.DiscoveryEnabled, googleCfg.DirectLoginEvents), nil
+}
+
+// CredentialKinds documents the credential kinds the integration syncs.
+func (d *Definition) CredentialKinds() []registry.CredentialKindInfo {
+	return []registry.CredentialKindInfo{
+		{
+			Kind:             "google_oauth_grant",
+			Label:            "Google OAuth grant",
+			Description:      "A token a Workspace user granted to a third-party app, with the OAuth scopes it may use on that user's data.",
+			RotationGuidance: "Grants cannot be rotated. Revoke grants with broad scopes or to untrusted apps, and restrict the app under API controls in the Admin console.",
+		},
+	}
 }
 
 type googleWorkspaceMetrics struct{}
@@ -0,0 +1,194 @@
+package googleworkspace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const googleDelegationConsolePath = "Admin console > Security > Access and data control > API controls > Manage Domain Wide Delegation"
+
+// SetupError is a domain-wide delegation misconfiguration, with a message saying how to fix it.
+// Err is the Google response it was derived from.
+type SetupError struct {
+	Message string
+	Err     error
+}
+
+func (e *SetupError) Error() string { return e.Message }
+
+func (e *SetupError) Unwrap() error { return e.Err }
+
+// ValidateDelegation checks the setup every sync depends on: a delegated admin to impersonate,
+// a token for all of the client's scopes, and a Directory API user listing for customerID that
+// is not empty. Known misconfigurations are returned as a *SetupError.
+func (c *Client) ValidateDelegation(ctx context.Context, customerID string) error {
+	subject := strings.TrimSpace(c.cfg.DelegatedAdminEmail)
+	switch {
+	case subject == "":
+		return &SetupError{Message: "no delegated admin email is set: domain-wide delegation needs a Workspace admin user for the service account to impersonate"}
+	case !strings.Contains(subject, "@"):
+		return &SetupError{Message: fmt.Sprintf("delegated admin email %q is not an email address: set it to the primary email of a Workspace admin user", subject)}
+	case isGoogleServiceAccountEmail(subject):
+		return &SetupError{Message: fmt.Sprintf("delegated admin email %s is a service account: set it to a Workspace admin user for the service account to impersonate", subject)}
+	}
+
+	if _, _, err := c.fetchAccessToken(ctx, c.scopes); err != nil {
+		return c.explainSetupError(ctx, customerID, err)
+	}
+
+	customerID = strings.TrimSpace(customerID)
+	query := url.Values{
+		"customer":   []string{customerID},
+		"maxResults": []string{"1"},
+	}
+	body, _, err := c.doAuthorizedJSONRequest(ctx, http.MethodGet, c.directoryBaseURL+"/users?"+query.Encode(), nil)
+	if err != nil {
+		return c.explainSetupError(ctx, customerID, err)
+	}
+	var payload struct {
+		Users []json.RawMessage `json:"users"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return fmt.Errorf("decode google workspace users: %w", err)
+	}
+	if len(payload.Users) == 0 {
+		return c.noUsersError(customerID)
+	}
+	return nil
+}
+
+// explainSetupError maps a token exchange, IAM signing, or Directory API failure caused by a
+// known delegation misconfiguration to a *SetupError. Other errors are returned unchanged.
+func (c *Client) explainSetupError(ctx context.Context, customerID string, err error) error {
+	var respErr *googleResponseError
+	if !errors.As(err, &respErr) {
+		return err
+	}
+	subject := strings.TrimSpace(c.cfg.DelegatedAdminEmail)
+
+	switch respErr.op {
+	case googleOpIAMSignJWT:
+		if respErr.statusCode == http.StatusForbidden {
+			return &SetupError{Err: err, Message: fmt.Sprintf("the application default credentials cannot sign tokens as %s: grant them the Service Account Token Creator role on that service account", c.issuer())}
+		}
+	case googleOpTokenExchange:
+		code, description := googleOAuthError(respErr.body)
+		description = strings.ToLower(description)
+		switch {
+		case code == "unauthorized_client" || code == "access_denied":
+			return c.explainUnauthorizedScopes(ctx, err)
+		case code == "invalid_grant" && (strings.Contains(description, "email") || strings.Contains(description, "user id")):
+			return &SetupError{Err: err, Message: fmt.Sprintf("delegated admin %s is not a user in this Workspace account: set it to the primary email of an existing admin user, not an alias or group", subject)}
+		case code == "invalid_grant" && strings.Contains(description, "signature"):
+			return &SetupError{Err: err, Message: "Google rejected the service account key: it was deleted or disabled; create a new key and update the service account JSON"}
+		case code == "invalid_grant" && (strings.Contains(description, "short-lived") || strings.Contains(description, "timeframe")):
+			return &SetupError{Err: err, Message: "Google rejected the token request's timestamps: check that this server's clock is in sync"}
+		case code == "invalid_client":
+			return &SetupError{Err: err, Message: fmt.Sprintf("Google does not recognize service account %s: it was deleted or disabled", c.issuer())}
+		}
+	case googleOpAPIRequest:
+		reasons, message := googleAPIErrorReasons(respErr.body)
+		message = strings.ToLower(message)
+		switch {
+		case slices.Contains(reasons, "accessNotConfigured") || slices.Contains(reasons, "SERVICE_DISABLED"):
+			return &SetupError{Err: err, Message: "the Admin SDK API is not enabled in the service account's Google Cloud project: enable admin.googleapis.com there"}
+		case slices.Contains(reasons, "insufficientPermissions") || strings.Contains(message, "insufficient authentication scopes"):
+			return &SetupError{Err: err, Message: fmt.Sprintf("the token is missing Directory API scopes: authorize these scopes for %s in the %s: %s", c.delegationClientRef(), googleDelegationConsolePath, strings.Join(c.scopes, ","))}
+		case respErr.statusCode == http.StatusForbidden:
+			return &SetupError{Err: err, Message: fmt.Sprintf("delegated admin %s cannot read users of customer %s: impersonate a super admin, or an admin whose role can read users, in the same Workspace account", subject, customerID)}
+		case respErr.statusCode == http.StatusBadRequest || respErr.statusCode == http.StatusNotFound:
+			return &SetupError{Err: err, Message: fmt.Sprintf("customer ID %s was not found: use the ID from Admin console > Account > Account settings, or my_customer", customerID)}
+		}
+	}
+	return err
+}
+
+// explainUnauthorizedScopes tells delegation that is not set up at all apart from delegation that
+// is missing some scopes. Google rejects a token request when any requested scope is not
+// authorized, so each scope is requested on its own.
+func (c *Client) explainUnauthorizedScopes(ctx context.Context, cause error) error {
+	var denied []string
+	for _, scope := range c.scopes {
+		_, _, err := c.fetchAccessToken(ctx, []string{scope})
+		if err == nil {
+			continue
+		}
+		var respErr *googleResponseError
+		if !errors.As(err, &respErr) {
+			// Probing failed for another reason; fall back to the generic guidance.
+			denied = nil
+			break
+		}
+		if code, _ := googleOAuthError(respErr.body); code != "unauthorized_client" && code != "access_denied" {
+			denied = nil
+			break
+		}
+		denied = append(denied, scope)
+	}
+
+	if len(denied) == 0 || len(denied) == len(c.scopes) {
+		return &SetupError{Err: cause, Message: fmt.Sprintf("domain-wide delegation is not enabled for %s: in the %s, add it with these scopes: %s", c.delegationClientRef(), googleDelegationConsolePath, strings.Join(c.scopes, ","))}
+	}
+	return &SetupError{Err: cause, Message: fmt.Sprintf("domain-wide delegation for %s does not authorize %s: add them to its scopes in the %s", c.delegationClientRef(), strings.Join(denied, ", "), googleDelegationConsolePath)}
+}
+
+func (c *Client) noUsersError(customerID string) error {
+	return &SetupError{Message: fmt.Sprintf("the Directory API returned no users for customer %s: check that the customer ID belongs to the Workspace account of delegated admin %s", customerID, strings.TrimSpace(c.cfg.DelegatedAdminEmail))}
+}
+
+// delegationClientRef names the OAuth client an admin authorizes for domain-wide delegation.
+func (c *Client) delegationClientRef() string {
+	if c.saClientID != "" {
+		return "client ID " + c.saClientID
+	}
+	return "the client ID (unique ID) of service account " + c.issuer()
+}
+
+// googleOAuthError decodes an OAuth token endpoint error response.
+func googleOAuthError(body string) (code, description string) {
+	var payload struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		return "", ""
+	}
+	return strings.TrimSpace(payload.Error), strings.TrimSpace(payload.ErrorDescription)
+}
+
+// googleAPIErrorReasons decodes a Google API error response into its error reasons (legacy
+// errors[].reason and ErrorInfo details) and message.
+func googleAPIErrorReasons(body string) ([]string, string) {
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+			Errors  []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+			Details []struct {
+				Reason string `json:"reason"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		return nil, ""
+	}
+	var reasons []string
+	for _, item := range payload.Error.Errors {
+		if reason := strings.TrimSpace(item.Reason); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	for _, item := range payload.Error.Details {
+		if reason := strings.TrimSpace(item.Reason); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	return reasons, strings.TrimSpace(payload.Error.Message)
+}
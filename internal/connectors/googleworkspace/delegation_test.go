@@ -0,0 +1,222 @@
+package googleworkspace
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateDelegationExplainsGoogleErrors(t *testing.T) {
+	t.Parallel()
+
+	const usersOK = `{"users":[{"id":"u-1","primaryEmail":"admin@example.com"}]}`
+	tests := []struct {
+		name         string
+		subject      string
+		tokenStatus  int
+		tokenBody    func(scope string) string
+		usersStatus  int
+		usersBody    string
+		wantContains string
+	}{
+		{
+			name:         "subject missing",
+			subject:      " ",
+			wantContains: "no delegated admin email is set",
+		},
+		{
+			name:         "subject is the service account",
+			subject:      "svc-account@example.iam.gserviceaccount.com",
+			wantContains: "is a service account",
+		},
+		{
+			name:        "delegation not enabled",
+			tokenStatus: http.StatusUnauthorized,
+			tokenBody: func(string) string {
+				return `{"error":"unauthorized_client","error_description":"Client is unauthorized to retrieve access tokens using this method, or client not authorized for any of the scopes requested."}`
+			},
+			wantContains: "domain-wide delegation is not enabled for the client ID (unique ID) of service account svc-account@example.iam.gserviceaccount.com",
+		},
+		{
+			name:        "one scope not authorized",
+			tokenStatus: http.StatusUnauthorized,
+			tokenBody: func(scope string) string {
+				if strings.Contains(scope, "admin.reports.audit.readonly") {
+					return `{"error":"unauthorized_client","error_description":"Client is unauthorized to retrieve access tokens using this method, or client not authorized for any of the scopes requested."}`
+				}
+				return ""
+			},
+			wantContains: "does not authorize https://www.googleapis.com/auth/admin.reports.audit.readonly:",
+		},
+		{
+			name:        "subject not a workspace user",
+			tokenStatus: http.StatusBadRequest,
+			tokenBody: func(string) string {
+				return `{"error":"invalid_grant","error_description":"Invalid email or User ID"}`
+			},
+			wantContains: "delegated admin admin@example.com is not a user in this Workspace account",
+		},
+		{
+			name:        "key deleted",
+			tokenStatus: http.StatusBadRequest,
+			tokenBody: func(string) string {
+				return `{"error":"invalid_grant","error_description":"Invalid JWT Signature."}`
+			},
+			wantContains: "Google rejected the service account key",
+		},
+		{
+			name:         "subject not an admin",
+			usersStatus:  http.StatusForbidden,
+			usersBody:    `{"error":{"code":403,"message":"Not Authorized to access this resource/api","errors":[{"message":"Not Authorized to access this resource/api","domain":"global","reason":"forbidden"}]}}`,
+			wantContains: "delegated admin admin@example.com cannot read users of customer C0123",
+		},
+		{
+			name:         "admin sdk disabled",
+			usersStatus:  http.StatusForbidden,
+			usersBody:    `{"error":{"code":403,"message":"Admin SDK API has not been used in project 123 before or it is disabled.","status":"PERMISSION_DENIED","details":[{"@type":"type.googleapis.com/google.rpc.ErrorInfo","reason":"SERVICE_DISABLED"}]}}`,
+			wantContains: "the Admin SDK API is not enabled",
+		},
+		{
+			name:         "scope missing from token",
+			usersStatus:  http.StatusForbidden,
+			usersBody:    `{"error":{"code":403,"message":"Request had insufficient authentication scopes.","errors":[{"reason":"insufficientPermissions"}],"status":"PERMISSION_DENIED"}}`,
+			wantContains: "the token is missing Directory API scopes",
+		},
+		{
+			name:         "unknown customer",
+			usersStatus:  http.StatusBadRequest,
+			usersBody:    `{"error":{"code":400,"message":"Bad Request","errors":[{"reason":"badRequest"}]}}`,
+			wantContains: "customer ID C0123 was not found",
+		},
+		{
+			name:         "empty user list",
+			usersStatus:  http.StatusOK,
+			usersBody:    `{"kind":"admin#directory#users"}`,
+			wantContains: "returned no users for customer C0123",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/token":
+					if tc.tokenBody != nil {
+						if body := tc.tokenBody(assertionScope(t, r)); body != "" {
+							w.WriteHeader(tc.tokenStatus)
+							_, _ = io.WriteString(w, body)
+							return
+						}
+					}
+					_, _ = io.WriteString(w, `{"access_token":"access-token","expires_in":3600}`)
+				case "/admin/directory/v1/users":
+					if tc.usersStatus != 0 {
+						w.WriteHeader(tc.usersStatus)
+						_, _ = io.WriteString(w, tc.usersBody)
+						return
+					}
+					_, _ = io.WriteString(w, usersOK)
+				default:
+					t.Errorf("unexpected path %q", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			client, err := NewClientWithOptions(testServiceAccountConfig(t, server.URL+"/token"), ClientOptions{
+				HTTPClient:       server.Client(),
+				DirectoryBaseURL: server.URL + "/admin/directory/v1",
+				TokenURL:         server.URL + "/token",
+			})
+			if err != nil {
+				t.Fatalf("NewClientWithOptions() error = %v", err)
+			}
+			if tc.subject != "" {
+				client.cfg.DelegatedAdminEmail = tc.subject
+			}
+
+			err = client.ValidateDelegation(context.Background(), "C0123")
+			var setupErr *SetupError
+			if !errors.As(err, &setupErr) {
+				t.Fatalf("ValidateDelegation() error = %v, want *SetupError", err)
+			}
+			if !strings.Contains(setupErr.Message, tc.wantContains) {
+				t.Fatalf("ValidateDelegation() error = %q, want it to contain %q", setupErr.Message, tc.wantContains)
+			}
+		})
+	}
+}
+
+func TestValidateDelegationSucceeds(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/token":
+			_, _ = io.WriteString(w, `{"access_token":"access-token","expires_in":3600}`)
+		case "/admin/directory/v1/users":
+			if got := r.URL.Query().Get("customer"); got != "C0123" {
+				t.Errorf("customer = %q, want C0123", got)
+			}
+			_, _ = io.WriteString(w, `{"users":[{"id":"u-1","primaryEmail":"admin@example.com"}]}`)
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOptions(testServiceAccountConfig(t, server.URL+"/token"), ClientOptions{
+		HTTPClient:       server.Client(),
+		DirectoryBaseURL: server.URL + "/admin/directory/v1",
+		TokenURL:         server.URL + "/token",
+	})
+	if err != nil {
+		t.Fatalf("NewClientWithOptions() error = %v", err)
+	}
+	integration := NewGoogleWorkspaceIntegration(client, "C0123", "example.com", false, false)
+
+	grants, ok, err := integration.TestConnection(context.Background())
+	if err != nil {
+		t.Fatalf("TestConnection() error = %v", err)
+	}
+	if !ok || len(grants.Missing) != 0 {
+		t.Fatalf("TestConnection() = %+v, %v, want all scopes held", grants, ok)
+	}
+}
+
+// assertionScope returns the scope claim of the JWT bearer assertion in a token request.
+func assertionScope(t *testing.T, r *http.Request) string {
+	t.Helper()
+
+	if err := r.ParseForm(); err != nil {
+		t.Errorf("parse token form: %v", err)
+		return ""
+	}
+	parts := strings.Split(r.PostForm.Get("assertion"), ".")
+	if len(parts) != 3 {
+		t.Errorf("assertion has %d parts, want 3", len(parts))
+		return ""
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Errorf("decode assertion claims: %v", err)
+		return ""
+	}
+	var claims struct {
+		Scope string `json:"scope"`
+	}
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		t.Errorf("decode assertion claims: %v", err)
+		return ""
+	}
+	return claims.Scope
+}
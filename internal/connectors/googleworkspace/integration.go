@@ -16,6 +16,7 @@ import (
 	"github.com/open-sspm/open-sspm/internal/connectors/registry"
 	"github.com/open-sspm/open-sspm/internal/db/gen"
 	"github.com/open-sspm/open-sspm/internal/discovery"
+	"github.com/open-sspm/open-sspm/internal/matching"
 	"github.com/open-sspm/open-sspm/internal/metrics"
 )
 
@@ -27,6 +28,9 @@ const (
 	googleWorkspaceCredentialBatchSize    = 2000
 	googleWorkspaceAuditEventBatchSize    = 2000
 	googleWorkspaceDiscoveryWatermarkSkew = 15 * time.Minute
+
+	googleWorkspaceDirectLoginAppID   = "google_direct_login"
+	googleWorkspaceDirectLoginAppName = "Google direct login"
 )
 
 type GoogleWorkspaceIntegration struct {
@@ -34,6 +38,8 @@ type GoogleWorkspaceIntegration struct {
 	customerID       string
 	primaryDomain    string
 	discoveryEnabled bool
+	// directLogins attributes activities with no derivable app to a pseudo-app instead of dropping them.
+	directLogins bool
 }
 
 type googleWorkspaceAccountRow struct {
@@ -50,6 +56,7 @@ type googleWorkspaceEntitlementRow struct {
 	Kind              string
 	Resource          string
 	Permission        string
+	RoleName          string
 	RawJSON           []byte
 }
 
@@ -135,12 +142,13 @@ type normalizedDiscoveryEvent struct {
 	RawJSON          []byte
 }
 
-func NewGoogleWorkspaceIntegration(client *Client, customerID, primaryDomain string, discoveryEnabled bool) *GoogleWorkspaceIntegration {
+func NewGoogleWorkspaceIntegration(client *Client, customerID, primaryDomain string, discoveryEnabled, directLogins bool) *GoogleWorkspaceIntegration {
 	return &GoogleWorkspaceIntegration{
 		client:           client,
 		customerID:       strings.TrimSpace(customerID),
 		primaryDomain:    strings.TrimSpace(primaryDomain),
 		discoveryEnabled: discoveryEnabled,
+		directLogins:     directLogins,
 	}
 }
 
@@ -194,9 +202,23 @@ func (i *GoogleWorkspaceIntegration) Run(ctx context.Context, q *gen.Queries, po
 	}
 }
 
+// RequiredGrants lists the OAuth scopes domain-wide delegation must authorize for a full sync.
+func (i *GoogleWorkspaceIntegration) RequiredGrants() []string {
+	return append([]string(nil), googleWorkspaceDefaultScopes...)
+}
+
+// TestConnection validates the domain-wide delegation setup. Google refuses a delegated token
+// unless every requested scope is authorized, so a passing check holds all of the client's scopes.
+func (i *GoogleWorkspaceIntegration) TestConnection(ctx context.Context) (registry.ConnectorGrants, bool, error) {
+	if err := i.client.ValidateDelegation(ctx, i.customerID); err != nil {
+		return registry.ConnectorGrants{}, false, err
+	}
+	return registry.EvaluateGrants(i.client.scopes, googleWorkspaceDefaultScopes, nil), true, nil
+}
+
 func (i *GoogleWorkspaceIntegration) runFull(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, report func(registry.Event)) error {
 	started := time.Now()
-	runID, err := q.CreateSyncRun(ctx, gen.CreateSyncRunParams{
+	runID, err := registry.CreateSyncRun(ctx, q, gen.CreateSyncRunParams{
 		SourceKind: registry.SyncRunSourceKind(configstore.KindGoogleWorkspace, registry.RunModeFull),
 		SourceName: i.customerID,
 	})
@@ -206,10 +228,22 @@ func (i *GoogleWorkspaceIntegration) runFull(ctx context.Context, q *gen.Queries
 
 	report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "list-users", Current: 0, Total: 1, Message: "listing users"})
 	users, err := i.client.ListUsers(ctx, i.customerID)
+	if err == nil && len(users) == 0 {
+		// Every Workspace account has at least the delegated admin, so an empty listing means the
+		// customer ID does not match the admin's account.
+		err = i.client.noUsersError(i.customerID)
+	}
 	if err != nil {
+		err = i.client.explainSetupError(ctx, i.customerID, err)
 		report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "list-users", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindAPI)
 	}
+	deletedUsers, err := i.client.ListDeletedUsers(ctx, i.customerID)
+	if err != nil {
+		report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "list-users", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindAPI)
+	}
+	users = append(users, deletedUsers...)
 	report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "list-users", Current: 1, Total: 1, Message: fmt.Sprintf("found %d users", len(users))})
 
 	report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "list-groups", Current: 0, Total: 1, Message: "listing groups"})
@@ -221,7 +255,9 @@ func (i *GoogleWorkspaceIntegration) runFull(ctx context.Context, q *gen.Queries
 	report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "list-groups", Current: 1, Total: 1, Message: fmt.Sprintf("found %d groups", len(groups))})
 
 	accounts := buildGoogleWorkspaceAccountRows(users, groups)
-	if err := i.upsertAccounts(ctx, q, report, runID, accounts); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertAccounts(ctx, qtx, report, runID, accounts)
+	}); err != nil {
 		report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "write-users", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
@@ -249,7 +285,9 @@ func (i *GoogleWorkspaceIntegration) runFull(ctx context.Context, q *gen.Queries
 	allEntitlements := make([]googleWorkspaceEntitlementRow, 0, len(groupEntitlements)+len(adminEntitlements))
 	allEntitlements = append(allEntitlements, groupEntitlements...)
 	allEntitlements = append(allEntitlements, adminEntitlements...)
-	if err := i.upsertEntitlements(ctx, q, report, runID, allEntitlements); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertEntitlements(ctx, qtx, report, runID, allEntitlements)
+	}); err != nil {
 		report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "write-entitlements", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
@@ -263,15 +301,21 @@ func (i *GoogleWorkspaceIntegration) runFull(ctx context.Context, q *gen.Queries
 	report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "list-oauth-grants", Current: 1, Total: 1, Message: fmt.Sprintf("found %d OAuth grants", len(grants))})
 
 	assets, owners, credentials := i.buildOAuthInventoryRows(grants, users)
-	if err := i.upsertAppAssets(ctx, q, report, runID, assets); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertAppAssets(ctx, qtx, report, runID, assets)
+	}); err != nil {
 		report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "write-app-assets", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
-	if err := i.upsertAppAssetOwners(ctx, q, report, runID, owners); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertAppAssetOwners(ctx, qtx, report, runID, owners)
+	}); err != nil {
 		report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "write-owners", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
-	if err := i.upsertCredentialArtifacts(ctx, q, report, runID, credentials); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertCredentialArtifacts(ctx, qtx, report, runID, credentials)
+	}); err != nil {
 		report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "write-credentials", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
@@ -284,13 +328,15 @@ func (i *GoogleWorkspaceIntegration) runFull(ctx context.Context, q *gen.Queries
 	}
 	report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "list-token-audit", Current: 1, Total: 1, Message: fmt.Sprintf("found %d token audit activities", len(tokenActivities))})
 
-	auditRows := buildGoogleWorkspaceAuditEventRows(tokenActivities)
-	if err := i.upsertCredentialAuditEvents(ctx, q, report, auditRows); err != nil {
+	auditRows := buildGoogleWorkspaceAuditEventRows(tokenActivities, i.directLogins)
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.upsertCredentialAuditEvents(ctx, qtx, report, auditRows)
+	}); err != nil {
 		report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "write-audit-events", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
-	if err := registry.FinalizeAppRun(ctx, q, pool, runID, configstore.KindGoogleWorkspace, i.customerID, time.Since(started), false); err != nil {
+	if err := registry.FinalizeAppRun(ctx, q, pool, runID, configstore.KindGoogleWorkspace, i.customerID, time.Since(started), false, nil); err != nil {
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
@@ -308,7 +354,7 @@ func (i *GoogleWorkspaceIntegration) runFull(ctx context.Context, q *gen.Queries
 
 func (i *GoogleWorkspaceIntegration) runDiscovery(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, report func(registry.Event)) error {
 	started := time.Now()
-	runID, err := q.CreateSyncRun(ctx, gen.CreateSyncRunParams{
+	runID, err := registry.CreateSyncRun(ctx, q, gen.CreateSyncRunParams{
 		SourceKind: registry.SyncRunSourceKind(configstore.KindGoogleWorkspace, registry.RunModeDiscovery),
 		SourceName: i.customerID,
 	})
@@ -316,7 +362,7 @@ func (i *GoogleWorkspaceIntegration) runDiscovery(ctx context.Context, q *gen.Qu
 		return err
 	}
 
-	if err := i.syncDiscovery(ctx, q, report, runID); err != nil {
+	if err := i.syncDiscovery(ctx, q, pool, report, runID); err != nil {
 		report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "write-discovery", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindUnknown)
 	}
@@ -327,6 +373,46 @@ func (i *GoogleWorkspaceIntegration) runDiscovery(ctx context.Context, q *gen.Qu
 	return nil
 }
 
+// Workspace user lifecycle states as reported by the Directory API. A user can carry several
+// flags at once (an archived user is also suspended); the most final one wins.
+const (
+	googleWorkspaceUserStateActive          = "active"
+	googleWorkspaceUserStateSuspended       = "suspended"
+	googleWorkspaceUserStateArchived        = "archived"
+	googleWorkspaceUserStatePendingDeletion = "pending_deletion"
+)
+
+func googleWorkspaceUserState(user WorkspaceUser) string {
+	switch {
+	case strings.TrimSpace(user.DeletionTime) != "":
+		return googleWorkspaceUserStatePendingDeletion
+	case user.Archived:
+		return googleWorkspaceUserStateArchived
+	case user.Suspended:
+		return googleWorkspaceUserStateSuspended
+	default:
+		return googleWorkspaceUserStateActive
+	}
+}
+
+// googleWorkspaceUserStatus maps a Workspace state to the normalized account status used by
+// identity rollups. Unrecognized states map to "unknown" so they count neither as active nor
+// as offboarded.
+func googleWorkspaceUserStatus(state string) string {
+	switch strings.ToLower(strings.TrimSpace(state)) {
+	case googleWorkspaceUserStateActive:
+		return "active"
+	case googleWorkspaceUserStateSuspended:
+		return "suspended"
+	case googleWorkspaceUserStateArchived:
+		return "archived"
+	case googleWorkspaceUserStatePendingDeletion:
+		return "deleted"
+	default:
+		return "unknown"
+	}
+}
+
 func buildGoogleWorkspaceAccountRows(users []WorkspaceUser, groups []WorkspaceGroup) []googleWorkspaceAccountRow {
 	rows := make([]googleWorkspaceAccountRow, 0, len(users)+len(groups))
 	for _, user := range users {
@@ -334,7 +420,7 @@ func buildGoogleWorkspaceAccountRows(users []WorkspaceUser, groups []WorkspaceGr
 		if externalID == "" {
 			continue
 		}
-		email := normalizeEmail(strings.TrimSpace(user.PrimaryEmail))
+		email := matching.NormalizeEmail(user.PrimaryEmail)
 		displayName := strings.TrimSpace(user.Name.FullName)
 		if displayName == "" {
 			displayName = email
@@ -342,17 +428,19 @@ func buildGoogleWorkspaceAccountRows(users []WorkspaceUser, groups []WorkspaceGr
 		if displayName == "" {
 			displayName = externalID
 		}
-		status := "active"
-		if user.Suspended {
-			status = "suspended"
-		}
+		state := googleWorkspaceUserState(user)
+		status := googleWorkspaceUserStatus(state)
 
 		raw := registry.WithEntityCategory(registry.MarshalJSON(map[string]any{
-			"id":            externalID,
-			"primary_email": strings.TrimSpace(user.PrimaryEmail),
-			"full_name":     strings.TrimSpace(user.Name.FullName),
-			"suspended":     user.Suspended,
-			"status":        status,
+			"id":                externalID,
+			"primary_email":     strings.TrimSpace(user.PrimaryEmail),
+			"full_name":         strings.TrimSpace(user.Name.FullName),
+			"suspended":         user.Suspended,
+			"suspension_reason": strings.TrimSpace(user.SuspensionReason),
+			"archived":          user.Archived,
+			"deletion_time":     strings.TrimSpace(user.DeletionTime),
+			"state":             state,
+			"status":            status,
 		}), registry.EntityCategoryUser)
 
 		rows = append(rows, googleWorkspaceAccountRow{
@@ -370,7 +458,7 @@ func buildGoogleWorkspaceAccountRows(users []WorkspaceUser, groups []WorkspaceGr
 		if externalID == "" {
 			continue
 		}
-		email := normalizeEmail(strings.TrimSpace(group.Email))
+		email := matching.NormalizeEmail(group.Email)
 		displayName := strings.TrimSpace(group.Name)
 		if displayName == "" {
 			displayName = email
@@ -403,8 +491,8 @@ func googleWorkspaceUserAccountKind(user WorkspaceUser) string {
 	if signal != registry.AccountKindUnknown {
 		return signal
 	}
-	email := normalizeEmail(user.PrimaryEmail)
-	if strings.HasSuffix(email, ".gserviceaccount.com") {
+	email := matching.NormalizeEmail(user.PrimaryEmail)
+	if isGoogleServiceAccountEmail(email) {
 		return registry.AccountKindService
 	}
 	if email != "" {
@@ -413,6 +501,19 @@ func googleWorkspaceUserAccountKind(user WorkspaceUser) string {
 	return registry.AccountKindUnknown
 }
 
+func isGoogleServiceAccountEmail(email string) bool {
+	return strings.HasSuffix(matching.NormalizeEmail(email), ".gserviceaccount.com")
+}
+
+// googleWorkspaceGrantOwnerKind distinguishes grants held by service accounts, typically
+// through domain-wide delegation, from grants a person approved.
+func googleWorkspaceGrantOwnerKind(ownerEmail string) string {
+	if isGoogleServiceAccountEmail(ownerEmail) {
+		return "google_service_account"
+	}
+	return "google_user"
+}
+
 func (i *GoogleWorkspaceIntegration) upsertAccounts(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, rows []googleWorkspaceAccountRow) error {
 	report(registry.Event{
 		Source:  configstore.KindGoogleWorkspace,
@@ -549,15 +650,20 @@ func buildGoogleWorkspaceAdminRoleEntitlements(roles []WorkspaceAdminRole, assig
 		if permission == "" {
 			permission = "global"
 		}
-		roleName := ""
+		roleName, privilegeRole := "", ""
 		if role, ok := roleByID[roleID]; ok {
 			roleName = strings.TrimSpace(role.RoleName)
+			privilegeRole = roleName
+			if role.IsSuperAdmin {
+				privilegeRole = googleWorkspaceSuperAdminRoleName
+			}
 		}
 		rows = append(rows, googleWorkspaceEntitlementRow{
 			AppUserExternalID: assignedTo,
 			Kind:              "google_admin_role",
 			Resource:          "google_admin_role:" + roleID,
 			Permission:        permission,
+			RoleName:          privilegeRole,
 			RawJSON: registry.MarshalJSON(map[string]any{
 				"role_id":       roleID,
 				"role_name":     roleName,
@@ -586,12 +692,14 @@ func (i *GoogleWorkspaceIntegration) upsertEntitlements(ctx context.Context, q *
 		resources := make([]string, 0, len(batch))
 		permissions := make([]string, 0, len(batch))
 		rawJSONs := make([][]byte, 0, len(batch))
+		privilegeLevels := make([]int16, 0, len(batch))
 		for _, row := range batch {
 			appUserExternalIDs = append(appUserExternalIDs, row.AppUserExternalID)
 			kinds = append(kinds, row.Kind)
 			resources = append(resources, row.Resource)
 			permissions = append(permissions, row.Permission)
 			rawJSONs = append(rawJSONs, row.RawJSON)
+			privilegeLevels = append(privilegeLevels, int16(googleWorkspacePrivilegeMapping.Level(row.Kind, row.Permission, row.RoleName)))
 		}
 
 		if _, err := q.UpsertEntitlementsBulkBySource(ctx, gen.UpsertEntitlementsBulkBySourceParams{
@@ -603,6 +711,7 @@ func (i *GoogleWorkspaceIntegration) upsertEntitlements(ctx context.Context, q *
 			Resources:          resources,
 			Permissions:        permissions,
 			RawJsons:           rawJSONs,
+			PrivilegeLevels:    privilegeLevels,
 		}); err != nil {
 			return fmt.Errorf("upsert google workspace entitlements: %w", err)
 		}
@@ -661,7 +770,7 @@ func (i *GoogleWorkspaceIntegration) buildOAuthInventoryRows(grants []WorkspaceO
 		ownerEmail := ""
 		ownerDisplayName := ownerExternalID
 		if user, ok := userByID[ownerExternalID]; ok {
-			ownerEmail = normalizeEmail(strings.TrimSpace(user.PrimaryEmail))
+			ownerEmail = matching.NormalizeEmail(user.PrimaryEmail)
 			ownerDisplayName = strings.TrimSpace(user.Name.FullName)
 			if ownerDisplayName == "" {
 				ownerDisplayName = ownerEmail
@@ -674,14 +783,16 @@ func (i *GoogleWorkspaceIntegration) buildOAuthInventoryRows(grants []WorkspaceO
 			ownerDisplayName = ownerExternalID
 		}
 		if ownerEmail == "" {
-			ownerEmail = normalizeEmail(ownerExternalID)
+			// The user key may be an ID rather than an address; keep it as the owner's email either way.
+			ownerEmail = strings.ToLower(ownerExternalID)
 		}
+		ownerKind := googleWorkspaceGrantOwnerKind(ownerEmail)
 
 		if ownerExternalID != "" {
 			ownerRows = append(ownerRows, googleWorkspaceAppAssetOwnerRow{
 				AssetKind:        "google_oauth_client",
 				AssetExternalID:  clientExternalID,
-				OwnerKind:        "google_user",
+				OwnerKind:        ownerKind,
 				OwnerExternalID:  ownerExternalID,
 				OwnerDisplayName: ownerDisplayName,
 				OwnerEmail:       ownerEmail,
@@ -704,7 +815,7 @@ func (i *GoogleWorkspaceIntegration) buildOAuthInventoryRows(grants []WorkspaceO
 			CreatedAtSource:       pgtype.Timestamptz{},
 			ExpiresAtSource:       pgtype.Timestamptz{},
 			LastUsedAtSource:      pgtype.Timestamptz{},
-			CreatedByKind:         "google_user",
+			CreatedByKind:         ownerKind,
 			CreatedByExternalID:   ownerExternalID,
 			CreatedByDisplayName:  ownerDisplayName,
 			ApprovedByKind:        "",
@@ -940,20 +1051,24 @@ func (i *GoogleWorkspaceIntegration) upsertCredentialArtifacts(ctx context.Conte
 	return nil
 }
 
-func buildGoogleWorkspaceAuditEventRows(activities []WorkspaceActivity) []googleWorkspaceCredentialAuditEventRow {
+func buildGoogleWorkspaceAuditEventRows(activities []WorkspaceActivity, directLogins bool) []googleWorkspaceCredentialAuditEventRow {
 	now := time.Now().UTC()
 	rows := make([]googleWorkspaceCredentialAuditEventRow, 0, len(activities))
 	for _, activity := range activities {
-		clientID, clientName, _ := discoverySourceFromActivity(activity)
+		clientID, clientName, _ := activitySource(activity, directLogins)
 		if clientID == "" {
 			continue
 		}
+		targetKind := "google_oauth_client"
+		if clientID == googleWorkspaceDirectLoginAppID {
+			targetKind = "google_direct_login"
+		}
 		observedAt := parseGoogleTime(activity.ID.Time)
 		if observedAt.IsZero() {
 			observedAt = now
 		}
 		actorExternalID := strings.TrimSpace(activity.Actor.ProfileID)
-		actorEmail := normalizeEmail(strings.TrimSpace(activity.Actor.Email))
+		actorEmail := matching.NormalizeEmail(activity.Actor.Email)
 		if actorExternalID == "" {
 			actorExternalID = actorEmail
 		}
@@ -966,12 +1081,12 @@ func buildGoogleWorkspaceAuditEventRows(activities []WorkspaceActivity) []google
 				ActorKind:            "google_user",
 				ActorExternalID:      actorExternalID,
 				ActorDisplayName:     actorEmail,
-				TargetKind:           "google_oauth_client",
+				TargetKind:           targetKind,
 				TargetExternalID:     clientID,
 				TargetDisplayName:    clientName,
 				CredentialKind:       "google_oauth_grant",
 				CredentialExternalID: googleWorkspaceGrantExternalID(clientID, actorExternalID),
-				RawJSON:              registry.NormalizeJSON(activity.RawJSON),
+				RawJSON:              registry.NormalizeRawJSON(activity.RawJSON),
 			})
 			continue
 		}
@@ -991,12 +1106,12 @@ func buildGoogleWorkspaceAuditEventRows(activities []WorkspaceActivity) []google
 				ActorKind:            "google_user",
 				ActorExternalID:      actorExternalID,
 				ActorDisplayName:     actorEmail,
-				TargetKind:           "google_oauth_client",
+				TargetKind:           targetKind,
 				TargetExternalID:     clientID,
 				TargetDisplayName:    clientName,
 				CredentialKind:       "google_oauth_grant",
 				CredentialExternalID: googleWorkspaceGrantExternalID(clientID, actorExternalID),
-				RawJSON:              registry.NormalizeJSON(activity.RawJSON),
+				RawJSON:              registry.NormalizeRawJSON(activity.RawJSON),
 			})
 		}
 	}
@@ -1063,25 +1178,21 @@ func (i *GoogleWorkspaceIntegration) upsertCredentialAuditEvents(ctx context.Con
 	return nil
 }
 
-func (i *GoogleWorkspaceIntegration) syncDiscovery(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64) error {
+func (i *GoogleWorkspaceIntegration) syncDiscovery(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, report func(registry.Event), runID int64) error {
 	now := time.Now().UTC()
 	report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "list-discovery-events", Current: 0, Total: 1, Message: "listing login and token activities"})
 
-	since := now.Add(-7 * 24 * time.Hour)
-	latestObservedAt, err := q.GetLatestSaaSDiscoveryObservedAtBySource(ctx, gen.GetLatestSaaSDiscoveryObservedAtBySourceParams{
-		SourceKind: configstore.KindGoogleWorkspace,
-		SourceName: i.customerID,
+	since, err := discovery.IngestSince(ctx, now, discovery.DefaultIngestLookback, googleWorkspaceDiscoveryWatermarkSkew, func(ctx context.Context) (time.Time, bool, error) {
+		latest, err := q.GetLatestSaaSDiscoveryObservedAtBySource(ctx, gen.GetLatestSaaSDiscoveryObservedAtBySourceParams{
+			SourceKind: configstore.KindGoogleWorkspace,
+			SourceName: i.customerID,
+		})
+		return latest.Time, latest.Valid, err
 	})
 	if err != nil {
 		metrics.DiscoveryIngestFailuresTotal.WithLabelValues(configstore.KindGoogleWorkspace, discovery.SignalKindIDPSSO, "watermark_query_error").Inc()
 		return fmt.Errorf("query latest discovery watermark: %w", err)
 	}
-	if latestObservedAt.Valid {
-		candidate := latestObservedAt.Time.UTC().Add(-googleWorkspaceDiscoveryWatermarkSkew)
-		if candidate.After(since) {
-			since = candidate
-		}
-	}
 
 	loginActivities, err := i.client.ListLoginActivities(ctx, &since)
 	if err != nil {
@@ -1104,7 +1215,9 @@ func (i *GoogleWorkspaceIntegration) syncDiscovery(ctx context.Context, q *gen.Q
 	sources, events := i.normalizeDiscovery(loginActivities, tokenActivities, tokenGrants, now)
 	report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "normalize-discovery", Current: 1, Total: 1, Message: fmt.Sprintf("normalized %d source rows and %d events", len(sources), len(events))})
 
-	if err := i.writeDiscoveryRows(ctx, q, report, runID, sources, events); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.writeDiscoveryRows(ctx, qtx, report, runID, sources, events)
+	}); err != nil {
 		metrics.DiscoveryIngestFailuresTotal.WithLabelValues(configstore.KindGoogleWorkspace, discovery.SignalKindIDPSSO, "db_error").Inc()
 		return err
 	}
@@ -1118,7 +1231,7 @@ func (i *GoogleWorkspaceIntegration) normalizeDiscovery(loginActivities, tokenAc
 	sourceByID := map[string]normalizedDiscoverySource{}
 	events := make([]normalizedDiscoveryEvent, 0, len(loginActivities)+len(tokenActivities)+len(tokenGrants))
 
-	upsertSource := func(signalKind, sourceAppID, sourceAppName, sourceDomain, sourceVendor string, seenAt time.Time) (discovery.AppMetadata, bool) {
+	upsertSource := func(signalKind, sourceAppID, sourceAppName, sourceDomain string, seenAt time.Time) (discovery.AppMetadata, bool) {
 		sourceAppID = strings.TrimSpace(sourceAppID)
 		if sourceAppID == "" {
 			return discovery.AppMetadata{}, false
@@ -1131,12 +1244,16 @@ func (i *GoogleWorkspaceIntegration) normalizeDiscovery(loginActivities, tokenAc
 			seenAt = now
 		}
 		metadata := discovery.BuildMetadata(discovery.CanonicalInput{
-			SourceKind:       configstore.KindGoogleWorkspace,
-			SourceName:       i.customerID,
-			SourceAppID:      sourceAppID,
-			SourceAppName:    sourceAppName,
-			SourceDomain:     sourceDomain,
-			SourceVendorName: sourceVendor,
+			SourceKind:    configstore.KindGoogleWorkspace,
+			SourceName:    i.customerID,
+			SourceAppID:   sourceAppID,
+			SourceAppName: sourceAppName,
+			SourceDomain:  sourceDomain,
+			SourceVendorName: discovery.ResolveVendorName(discovery.VendorEvidence{
+				PublisherDomain: sourceDomain,
+				AppID:           sourceAppID,
+				AppName:         sourceAppName,
+			}),
 		})
 		current := sourceByID[sourceAppID]
 		if current.SourceAppID == "" || seenAt.After(current.SeenAt) {
@@ -1154,17 +1271,17 @@ func (i *GoogleWorkspaceIntegration) normalizeDiscovery(loginActivities, tokenAc
 	}
 
 	for _, activity := range loginActivities {
-		sourceAppID, sourceAppName, sourceDomain := discoverySourceFromActivity(activity)
+		sourceAppID, sourceAppName, sourceDomain := activitySource(activity, i.directLogins)
 		observedAt := parseGoogleTime(activity.ID.Time)
 		if observedAt.IsZero() {
 			observedAt = now
 		}
-		metadata, ok := upsertSource(discovery.SignalKindIDPSSO, sourceAppID, sourceAppName, sourceDomain, sourceAppName, observedAt)
+		metadata, ok := upsertSource(discovery.SignalKindIDPSSO, sourceAppID, sourceAppName, sourceDomain, observedAt)
 		if !ok {
 			continue
 		}
 		actorExternalID := strings.TrimSpace(activity.Actor.ProfileID)
-		actorEmail := normalizeEmail(strings.TrimSpace(activity.Actor.Email))
+		actorEmail := matching.NormalizeEmail(activity.Actor.Email)
 		if actorExternalID == "" {
 			actorExternalID = actorEmail
 		}
@@ -1182,7 +1299,7 @@ func (i *GoogleWorkspaceIntegration) normalizeDiscovery(loginActivities, tokenAc
 				ActorDisplayName: actorEmail,
 				ObservedAt:       observedAt,
 				Scopes:           nil,
-				RawJSON:          registry.NormalizeJSON(activity.RawJSON),
+				RawJSON:          registry.NormalizeRawJSON(activity.RawJSON),
 			})
 			continue
 		}
@@ -1200,23 +1317,23 @@ func (i *GoogleWorkspaceIntegration) normalizeDiscovery(loginActivities, tokenAc
 				ActorDisplayName: actorEmail,
 				ObservedAt:       observedAt,
 				Scopes:           nil,
-				RawJSON:          registry.NormalizeJSON(activity.RawJSON),
+				RawJSON:          registry.NormalizeRawJSON(activity.RawJSON),
 			})
 		}
 	}
 
 	for _, activity := range tokenActivities {
-		sourceAppID, sourceAppName, sourceDomain := discoverySourceFromActivity(activity)
+		sourceAppID, sourceAppName, sourceDomain := activitySource(activity, i.directLogins)
 		observedAt := parseGoogleTime(activity.ID.Time)
 		if observedAt.IsZero() {
 			observedAt = now
 		}
-		metadata, ok := upsertSource(discovery.SignalKindOAuth, sourceAppID, sourceAppName, sourceDomain, sourceAppName, observedAt)
+		metadata, ok := upsertSource(discovery.SignalKindOAuth, sourceAppID, sourceAppName, sourceDomain, observedAt)
 		if !ok {
 			continue
 		}
 		actorExternalID := strings.TrimSpace(activity.Actor.ProfileID)
-		actorEmail := normalizeEmail(strings.TrimSpace(activity.Actor.Email))
+		actorEmail := matching.NormalizeEmail(activity.Actor.Email)
 		if actorExternalID == "" {
 			actorExternalID = actorEmail
 		}
@@ -1235,7 +1352,7 @@ func (i *GoogleWorkspaceIntegration) normalizeDiscovery(loginActivities, tokenAc
 				ActorDisplayName: actorEmail,
 				ObservedAt:       observedAt,
 				Scopes:           scopes,
-				RawJSON:          registry.NormalizeJSON(activity.RawJSON),
+				RawJSON:          registry.NormalizeRawJSON(activity.RawJSON),
 			})
 			continue
 		}
@@ -1253,7 +1370,7 @@ func (i *GoogleWorkspaceIntegration) normalizeDiscovery(loginActivities, tokenAc
 				ActorDisplayName: actorEmail,
 				ObservedAt:       observedAt,
 				Scopes:           scopes,
-				RawJSON:          registry.NormalizeJSON(activity.RawJSON),
+				RawJSON:          registry.NormalizeRawJSON(activity.RawJSON),
 			})
 		}
 	}
@@ -1261,12 +1378,12 @@ func (i *GoogleWorkspaceIntegration) normalizeDiscovery(loginActivities, tokenAc
 	for _, grant := range tokenGrants {
 		sourceAppID := googleWorkspaceClientExternalID(grant)
 		sourceAppName := strings.TrimSpace(grant.DisplayText)
-		metadata, ok := upsertSource(discovery.SignalKindOAuth, sourceAppID, sourceAppName, "", sourceAppName, now)
+		metadata, ok := upsertSource(discovery.SignalKindOAuth, sourceAppID, sourceAppName, "", now)
 		if !ok {
 			continue
 		}
 		userKey := strings.TrimSpace(grant.UserKey)
-		actorEmail := normalizeEmail(userKey)
+		actorEmail := strings.ToLower(userKey)
 		events = append(events, normalizedDiscoveryEvent{
 			CanonicalKey:     metadata.CanonicalKey,
 			SignalKind:       discovery.SignalKindOAuth,
@@ -1280,7 +1397,7 @@ func (i *GoogleWorkspaceIntegration) normalizeDiscovery(loginActivities, tokenAc
 			ActorDisplayName: actorEmail,
 			ObservedAt:       now,
 			Scopes:           discovery.NormalizeScopes(grant.Scopes),
-			RawJSON:          registry.NormalizeJSON(grant.RawJSON),
+			RawJSON:          registry.NormalizeRawJSON(grant.RawJSON),
 		})
 	}
 
@@ -1291,6 +1408,17 @@ func (i *GoogleWorkspaceIntegration) normalizeDiscovery(loginActivities, tokenAc
 	return sources, events
 }
 
+// activitySource resolves the app an activity belongs to. Generic logins often carry no app
+// parameters; with directLogins set they are attributed to a synthetic pseudo-app so login
+// analysis still sees them, otherwise they resolve to an empty id and callers drop them.
+func activitySource(activity WorkspaceActivity, directLogins bool) (string, string, string) {
+	sourceAppID, sourceAppName, sourceDomain := discoverySourceFromActivity(activity)
+	if sourceAppID == "" && directLogins {
+		return googleWorkspaceDirectLoginAppID, googleWorkspaceDirectLoginAppName, sourceDomain
+	}
+	return sourceAppID, sourceAppName, sourceDomain
+}
+
 func discoverySourceFromActivity(activity WorkspaceActivity) (string, string, string) {
 	sourceAppID := firstNonEmpty(
 		activity.ParameterValues("client_id"),
@@ -1357,6 +1485,10 @@ func activityEventExternalID(prefix string, activity WorkspaceActivity, idx int)
 }
 
 func (i *GoogleWorkspaceIntegration) writeDiscoveryRows(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, sources []normalizedDiscoverySource, events []normalizedDiscoveryEvent) error {
+	ingestedTotal := metrics.DiscoveryEventsIngestedTotal
+	if _, backfill := discovery.BackfillSince(ctx); backfill {
+		ingestedTotal = metrics.DiscoveryBackfillEventsIngestedTotal
+	}
 	total := len(sources) + len(events)
 	report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "write-discovery", Current: 0, Total: int64(total), Message: fmt.Sprintf("writing %d discovery records", total)})
 
@@ -1411,6 +1543,7 @@ func (i *GoogleWorkspaceIntegration) writeDiscoveryRows(ctx context.Context, q *
 		displayNames := make([]string, 0, len(appMeta))
 		primaryDomains := make([]string, 0, len(appMeta))
 		vendorNames := make([]string, 0, len(appMeta))
+		categories := make([]string, 0, len(appMeta))
 		firstSeenAts := make([]pgtype.Timestamptz, 0, len(appMeta))
 		lastSeenAts := make([]pgtype.Timestamptz, 0, len(appMeta))
 		for key, meta := range appMeta {
@@ -1418,6 +1551,7 @@ func (i *GoogleWorkspaceIntegration) writeDiscoveryRows(ctx context.Context, q *
 			displayNames = append(displayNames, meta.DisplayName)
 			primaryDomains = append(primaryDomains, meta.Domain)
 			vendorNames = append(vendorNames, meta.VendorName)
+			categories = append(categories, meta.Category)
 			firstSeenAt := firstSeenByKey[key]
 			lastSeenAt := lastSeenByKey[key]
 			firstSeenAts = append(firstSeenAts, registry.PgTimestamptzPtr(&firstSeenAt))
@@ -1428,6 +1562,7 @@ func (i *GoogleWorkspaceIntegration) writeDiscoveryRows(ctx context.Context, q *
 			DisplayNames:   displayNames,
 			PrimaryDomains: primaryDomains,
 			VendorNames:    vendorNames,
+			Categories:     categories,
 			FirstSeenAts:   firstSeenAts,
 			LastSeenAts:    lastSeenAts,
 		}); err != nil {
@@ -1491,10 +1626,10 @@ func (i *GoogleWorkspaceIntegration) writeDiscoveryRows(ctx context.Context, q *
 			actorDisplayNames = append(actorDisplayNames, event.ActorDisplayName)
 			observedAts = append(observedAts, registry.PgTimestamptzPtr(&event.ObservedAt))
 			scopesJSONs = append(scopesJSONs, discovery.ScopesJSON(event.Scopes))
-			rawJSONs = append(rawJSONs, registry.NormalizeJSON(event.RawJSON))
+			rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(event.RawJSON))
 			ingestedBySignal[event.SignalKind]++
 		}
-		if _, err := q.UpsertSaaSAppEventsBulkBySource(ctx, gen.UpsertSaaSAppEventsBulkBySourceParams{
+		if _, err := registry.UpsertDiscoveryEvents(ctx, q, gen.UpsertSaaSAppEventsBulkBySourceParams{
 			SourceKind:        configstore.KindGoogleWorkspace,
 			SourceName:        i.customerID,
 			SeenInRunID:       runID,
@@ -1514,7 +1649,7 @@ func (i *GoogleWorkspaceIntegration) writeDiscoveryRows(ctx context.Context, q *
 			return fmt.Errorf("upsert saas app events: %w", err)
 		}
 		for signalKind, count := range ingestedBySignal {
-			metrics.DiscoveryEventsIngestedTotal.WithLabelValues(configstore.KindGoogleWorkspace, signalKind).Add(float64(count))
+			ingestedTotal.WithLabelValues(configstore.KindGoogleWorkspace, signalKind).Add(float64(count))
 		}
 		written += len(events)
 		report(registry.Event{Source: configstore.KindGoogleWorkspace, Stage: "write-discovery", Current: int64(written), Total: int64(total), Message: fmt.Sprintf("events %d/%d", written, total)})
@@ -1527,6 +1662,9 @@ func (i *GoogleWorkspaceIntegration) writeDiscoveryRows(ctx context.Context, q *
 }
 
 func (i *GoogleWorkspaceIntegration) seedGoogleWorkspaceAutoBindings(ctx context.Context, q *gen.Queries, runID int64) error {
+	if registry.CurrentAutoBindPolicy().Disabled {
+		return nil
+	}
 	appIDs, err := q.ListSaaSAppIDsFromSourcesSeenInRunBySource(ctx, gen.ListSaaSAppIDsFromSourcesSeenInRunBySourceParams{
 		SourceKind:  configstore.KindGoogleWorkspace,
 		SourceName:  i.customerID,
@@ -1572,28 +1710,14 @@ func (i *GoogleWorkspaceIntegration) seedGoogleWorkspaceAutoBindings(ctx context
 			continue
 		}
 
-		if err := q.UpsertSaaSAppBinding(ctx, gen.UpsertSaaSAppBindingParams{
-			SaasAppID:           appID,
-			ConnectorKind:       configstore.KindGoogleWorkspace,
-			ConnectorSourceName: i.customerID,
-			BindingSource:       "auto",
-			Confidence:          0.8,
-			IsPrimary:           false,
-			CreatedByAuthUserID: pgtype.Int8{},
-		}); err != nil {
+		if err := q.UpsertSaaSAppBinding(ctx, registry.AutoBindingParams(appID, configstore.KindGoogleWorkspace, i.customerID, registry.AutoBindMatchClientID)); err != nil {
 			return fmt.Errorf("upsert google auto binding for app %d: %w", appID, err)
 		}
 		boundCount++
 	}
 
 	if boundCount > 0 {
-		if _, err := q.RecomputePrimarySaaSAppBindingsForAll(ctx); err != nil {
-			return fmt.Errorf("recompute primary bindings: %w", err)
-		}
+		registry.RequestPrimaryBindingRecompute(q)
 	}
 	return nil
 }
-
-func normalizeEmail(raw string) string {
-	return strings.ToLower(strings.TrimSpace(raw))
-}
@@ -9,7 +9,7 @@ import (
 func TestGoogleWorkspaceIntegrationSupportsRunMode(t *testing.T) {
 	t.Parallel()
 
-	full := NewGoogleWorkspaceIntegration(nil, "C0123", "", false)
+	full := NewGoogleWorkspaceIntegration(nil, "C0123", "", false, false)
 	if !full.SupportsRunMode(registry.RunModeFull) {
 		t.Fatalf("full mode should always be supported")
 	}
@@ -17,7 +17,7 @@ func TestGoogleWorkspaceIntegrationSupportsRunMode(t *testing.T) {
 		t.Fatalf("discovery mode should be disabled when discovery is not configured")
 	}
 
-	discovery := NewGoogleWorkspaceIntegration(nil, "C0123", "", true)
+	discovery := NewGoogleWorkspaceIntegration(nil, "C0123", "", true, false)
 	if !discovery.SupportsRunMode(registry.RunModeDiscovery) {
 		t.Fatalf("discovery mode should be supported when discovery is enabled")
 	}
@@ -1,12 +1,17 @@
 package googleworkspace
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/open-sspm/open-sspm/internal/connectors/configstore"
 	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
 )
 
 func TestGoogleWorkspaceGrantExternalIDIsDeterministic(t *testing.T) {
@@ -144,7 +149,7 @@ func TestBuildGoogleWorkspaceAdminRoleEntitlementsMapsAssignments(t *testing.T)
 func TestBuildOAuthInventoryRowsMapsAssetsOwnersAndCredentials(t *testing.T) {
 	t.Parallel()
 
-	integration := NewGoogleWorkspaceIntegration(nil, "C0123", "example.com", true)
+	integration := NewGoogleWorkspaceIntegration(nil, "C0123", "example.com", true, false)
 	grants := []WorkspaceOAuthTokenGrant{
 		{
 			UserKey:     "u-1",
@@ -211,6 +216,42 @@ func TestBuildOAuthInventoryRowsMapsAssetsOwnersAndCredentials(t *testing.T) {
 	}
 }
 
+func TestBuildOAuthInventoryRowsDistinguishesServiceAccountOwners(t *testing.T) {
+	t.Parallel()
+
+	integration := NewGoogleWorkspaceIntegration(nil, "C0123", "example.com", true, false)
+	grants := []WorkspaceOAuthTokenGrant{
+		{UserKey: "u-human", ClientID: "client-1", DisplayText: "App One", Scopes: []string{"scope.a"}},
+		{UserKey: "u-sa", ClientID: "client-1", DisplayText: "App One", Scopes: []string{"scope.a"}},
+		{UserKey: "deployer@proj.iam.gserviceaccount.com", ClientID: "client-2", DisplayText: "DWD App", Scopes: []string{"scope.b"}},
+	}
+	users := []WorkspaceUser{
+		{ID: "u-human", PrimaryEmail: "alice@example.com"},
+		{ID: "u-sa", PrimaryEmail: "Robot@Proj.iam.gserviceaccount.com"},
+	}
+
+	_, owners, credentials := integration.buildOAuthInventoryRows(grants, users)
+
+	wantKinds := map[string]string{
+		"u-human":                               "google_user",
+		"u-sa":                                  "google_service_account",
+		"deployer@proj.iam.gserviceaccount.com": "google_service_account",
+	}
+	if len(owners) != len(wantKinds) || len(credentials) != len(wantKinds) {
+		t.Fatalf("len(owners) = %d, len(credentials) = %d, want %d each", len(owners), len(credentials), len(wantKinds))
+	}
+	for _, owner := range owners {
+		if want := wantKinds[owner.OwnerExternalID]; owner.OwnerKind != want {
+			t.Fatalf("owner %q kind = %q, want %q", owner.OwnerExternalID, owner.OwnerKind, want)
+		}
+	}
+	for _, cred := range credentials {
+		if want := wantKinds[cred.CreatedByExternalID]; cred.CreatedByKind != want {
+			t.Fatalf("credential created by %q kind = %q, want %q", cred.CreatedByExternalID, cred.CreatedByKind, want)
+		}
+	}
+}
+
 func TestBuildGoogleWorkspaceAuditEventRowsMapsTokenActivity(t *testing.T) {
 	t.Parallel()
 
@@ -236,7 +277,7 @@ func TestBuildGoogleWorkspaceAuditEventRowsMapsTokenActivity(t *testing.T) {
 	withoutEvent.Events[0].Type = ""
 	withoutEvent.RawJSON = []byte(`{"event":"without"}`)
 
-	rows := buildGoogleWorkspaceAuditEventRows([]WorkspaceActivity{withEvent, withoutEvent})
+	rows := buildGoogleWorkspaceAuditEventRows([]WorkspaceActivity{withEvent, withoutEvent}, false)
 	if len(rows) != 2 {
 		t.Fatalf("len(rows) = %d, want 2", len(rows))
 	}
@@ -290,7 +331,7 @@ func TestNormalizeDiscoverySourceFromActivity(t *testing.T) {
 func TestNewGoogleWorkspaceIntegrationIncludesDiscoverySourceName(t *testing.T) {
 	t.Parallel()
 
-	integration := NewGoogleWorkspaceIntegration(nil, "C0123", "example.com", true)
+	integration := NewGoogleWorkspaceIntegration(nil, "C0123", "example.com", true, false)
 	if integration.Kind() != configstore.KindGoogleWorkspace {
 		t.Fatalf("kind = %q, want %q", integration.Kind(), configstore.KindGoogleWorkspace)
 	}
@@ -298,3 +339,153 @@ func TestNewGoogleWorkspaceIntegrationIncludesDiscoverySourceName(t *testing.T)
 		t.Fatalf("name = %q, want %q", integration.Name(), "C0123")
 	}
 }
+
+func TestDirectLoginActivitiesAreGatedByFlag(t *testing.T) {
+	t.Parallel()
+
+	var genericLogin WorkspaceActivity
+	if err := json.Unmarshal([]byte(`{
+		"id":{"time":"2026-02-20T10:00:00Z","uniqueQualifier":"uq-login"},
+		"actor":{"email":"alice@example.com","profileId":"p-1"},
+		"events":[{"name":"login_success","parameters":[{"name":"login_type","value":"google_password"}]}]
+	}`), &genericLogin); err != nil {
+		t.Fatalf("unmarshal login: %v", err)
+	}
+	now := time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC)
+	activities := []WorkspaceActivity{genericLogin}
+
+	disabled := NewGoogleWorkspaceIntegration(nil, "C0123", "example.com", true, false)
+	sources, events := disabled.normalizeDiscovery(activities, nil, nil, now)
+	if len(sources) != 0 || len(events) != 0 {
+		t.Fatalf("disabled: sources=%d events=%d, want generic login dropped", len(sources), len(events))
+	}
+	if rows := buildGoogleWorkspaceAuditEventRows(activities, false); len(rows) != 0 {
+		t.Fatalf("disabled: audit rows = %d, want 0", len(rows))
+	}
+
+	enabled := NewGoogleWorkspaceIntegration(nil, "C0123", "example.com", true, true)
+	sources, events = enabled.normalizeDiscovery(activities, nil, nil, now)
+	if len(sources) != 1 || sources[0].SourceAppID != googleWorkspaceDirectLoginAppID || sources[0].SourceAppName != googleWorkspaceDirectLoginAppName {
+		t.Fatalf("enabled: sources = %+v, want direct login pseudo-app", sources)
+	}
+	if len(events) != 1 || events[0].SourceAppID != googleWorkspaceDirectLoginAppID || events[0].ActorEmail != "alice@example.com" {
+		t.Fatalf("enabled: events = %+v, want one direct login event", events)
+	}
+	rows := buildGoogleWorkspaceAuditEventRows(activities, true)
+	if len(rows) != 1 || rows[0].TargetKind != "google_direct_login" || rows[0].TargetExternalID != googleWorkspaceDirectLoginAppID {
+		t.Fatalf("enabled: audit rows = %+v, want one direct login row", rows)
+	}
+}
+
+func TestNormalizeDiscoveryResolvesVendorFromDomainNotDisplayText(t *testing.T) {
+	t.Parallel()
+
+	var token WorkspaceActivity
+	if err := json.Unmarshal([]byte(`{
+		"id":{"time":"2026-02-20T10:00:00Z","uniqueQualifier":"uq-token"},
+		"actor":{"email":"alice@example.com","profileId":"p-1"},
+		"events":[{"name":"authorize","parameters":[
+			{"name":"client_id","value":"123.apps.googleusercontent.com"},
+			{"name":"app_name","value":"Quarterly Forecast Sync"},
+			{"name":"app_domain","value":"login.salesforce.com"}
+		]}]
+	}`), &token); err != nil {
+		t.Fatalf("unmarshal token: %v", err)
+	}
+	now := time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC)
+	integration := NewGoogleWorkspaceIntegration(nil, "C0123", "example.com", true, false)
+
+	sources, _ := integration.normalizeDiscovery(nil, []WorkspaceActivity{token}, nil, now)
+	if len(sources) != 1 || sources[0].SourceVendorName != "Salesforce" {
+		t.Fatalf("sources = %+v, want vendor derived from the app domain", sources)
+	}
+
+	sources, _ = integration.normalizeDiscovery(nil, nil, []WorkspaceOAuthTokenGrant{
+		{UserKey: "alice@example.com", ClientID: "456.apps.googleusercontent.com"},
+	}, now)
+	if len(sources) != 1 || sources[0].SourceVendorName != "" {
+		t.Fatalf("sources = %+v, want no vendor when only the client ID is known", sources)
+	}
+}
+
+func TestBuildGoogleWorkspaceAccountRowsMapsLifecycleStates(t *testing.T) {
+	t.Parallel()
+
+	users := []WorkspaceUser{
+		{ID: "u-active", PrimaryEmail: "active@example.com"},
+		{ID: "u-suspended", PrimaryEmail: "suspended@example.com", Suspended: true, SuspensionReason: "ADMIN"},
+		// Archiving also suspends the user; the archived state must win.
+		{ID: "u-archived", PrimaryEmail: "archived@example.com", Suspended: true, Archived: true},
+		{ID: "u-deleted", PrimaryEmail: "deleted@example.com", DeletionTime: "2026-03-01T12:00:00.000Z"},
+	}
+
+	rows := buildGoogleWorkspaceAccountRows(users, nil)
+	want := map[string]struct{ status, state string }{
+		"u-active":    {"active", googleWorkspaceUserStateActive},
+		"u-suspended": {"suspended", googleWorkspaceUserStateSuspended},
+		"u-archived":  {"archived", googleWorkspaceUserStateArchived},
+		"u-deleted":   {"deleted", googleWorkspaceUserStatePendingDeletion},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("len(rows) = %d, want %d", len(rows), len(want))
+	}
+	for _, row := range rows {
+		expected := want[row.ExternalID]
+		if row.Status != expected.status {
+			t.Fatalf("%s status = %q, want %q", row.ExternalID, row.Status, expected.status)
+		}
+		var raw map[string]any
+		if err := json.Unmarshal(row.RawJSON, &raw); err != nil {
+			t.Fatalf("%s raw json: %v", row.ExternalID, err)
+		}
+		if raw["state"] != expected.state || raw["status"] != expected.status {
+			t.Fatalf("%s raw state/status = %v/%v, want %s/%s", row.ExternalID, raw["state"], raw["status"], expected.state, expected.status)
+		}
+	}
+}
+
+func TestGoogleWorkspaceUserStatusUnknownState(t *testing.T) {
+	t.Parallel()
+
+	for _, state := range []string{"", "legal_hold", "ARCHIVED "} {
+		got := googleWorkspaceUserStatus(state)
+		want := "unknown"
+		if state == "ARCHIVED " {
+			want = "archived"
+		}
+		if got != want {
+			t.Fatalf("googleWorkspaceUserStatus(%q) = %q, want %q", state, got, want)
+		}
+	}
+}
+
+// unusedDB fails the test on any query.
+type unusedDB struct{ t *testing.T }
+
+func (db unusedDB) Exec(_ context.Context, sql string, _ ...interface{}) (pgconn.CommandTag, error) {
+	db.t.Fatalf("unexpected Exec: %s", strings.SplitN(sql, "\n", 2)[0])
+	return pgconn.CommandTag{}, nil
+}
+
+func (db unusedDB) Query(_ context.Context, sql string, _ ...interface{}) (pgx.Rows, error) {
+	db.t.Fatalf("unexpected Query: %s", strings.SplitN(sql, "\n", 2)[0])
+	return nil, nil
+}
+
+func (db unusedDB) QueryRow(_ context.Context, sql string, _ ...interface{}) pgx.Row {
+	db.t.Fatalf("unexpected QueryRow: %s", strings.SplitN(sql, "\n", 2)[0])
+	return nil
+}
+
+// Not parallel: the auto-bind policy is process-wide.
+func TestSeedGoogleWorkspaceAutoBindingsSkippedWhenDisabled(t *testing.T) {
+	t.Cleanup(func() { registry.SetAutoBindPolicy(registry.DefaultAutoBindPolicy()) })
+	policy := registry.DefaultAutoBindPolicy()
+	policy.Disabled = true
+	registry.SetAutoBindPolicy(policy)
+
+	integration := NewGoogleWorkspaceIntegration(nil, "C123", "example.com", true, false)
+	if err := integration.seedGoogleWorkspaceAutoBindings(context.Background(), gen.New(unusedDB{t: t}), 7); err != nil {
+		t.Fatalf("seedGoogleWorkspaceAutoBindings() error = %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+package googleworkspace
+
+import "github.com/open-sspm/open-sspm/internal/connectors/registry"
+
+// googleWorkspaceSuperAdminRoleName is the name Google seeds the super admin role with. Roles
+// flagged isSuperAdminRole are mapped under this name whatever they are called.
+const googleWorkspaceSuperAdminRoleName = "_SEED_ADMIN_ROLE"
+
+// googleWorkspacePrivilegeMapping maps Google group roles and admin role assignments to
+// canonical levels. Admin roles are matched by role name.
+var googleWorkspacePrivilegeMapping = registry.PrivilegeMapping{
+	{Kind: "google_group_member", Permission: "owner", Level: registry.PrivilegeOwner},
+	{Kind: "google_group_member", Permission: "manager", Level: registry.PrivilegeAdmin},
+	{Kind: "google_group_member", Permission: "member", Level: registry.PrivilegeRead},
+	{Kind: "google_admin_role", Role: "_seed_admin_role", Level: registry.PrivilegeOwner},
+	{Kind: "google_admin_role", Level: registry.PrivilegeAdmin},
+}
@@ -0,0 +1,41 @@
+package googleworkspace
+
+import (
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+)
+
+func TestGoogleWorkspacePrivilegeMapping(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		kind, permission, role string
+		want                   registry.PrivilegeLevel
+	}{
+		{kind: "google_group_member", permission: "owner", want: registry.PrivilegeOwner},
+		{kind: "google_group_member", permission: "manager", want: registry.PrivilegeAdmin},
+		{kind: "google_group_member", permission: "member", want: registry.PrivilegeRead},
+		{kind: "google_admin_role", permission: "global", role: "_SEED_ADMIN_ROLE", want: registry.PrivilegeOwner},
+		{kind: "google_admin_role", permission: "org_unit", role: "_HELP_DESK_ADMIN_ROLE", want: registry.PrivilegeAdmin},
+	}
+	for _, tc := range cases {
+		if got := googleWorkspacePrivilegeMapping.Level(tc.kind, tc.permission, tc.role); got != tc.want {
+			t.Fatalf("googleWorkspacePrivilegeMapping.Level(%q, %q, %q) = %v, want %v", tc.kind, tc.permission, tc.role, got, tc.want)
+		}
+	}
+
+	rows := buildGoogleWorkspaceAdminRoleEntitlements(
+		[]WorkspaceAdminRole{{RoleID: "r1", RoleName: "Tenant Owners", IsSuperAdmin: true}, {RoleID: "r2", RoleName: "Groups Reader"}},
+		[]WorkspaceAdminRoleAssignment{{RoleID: "r1", AssignedTo: "u1"}, {RoleID: "r2", AssignedTo: "u2"}},
+	)
+	if len(rows) != 2 {
+		t.Fatalf("len(rows) = %d, want 2", len(rows))
+	}
+	if got := googleWorkspacePrivilegeMapping.Level(rows[0].Kind, rows[0].Permission, rows[0].RoleName); got != registry.PrivilegeOwner {
+		t.Fatalf("renamed super admin role level = %v, want owner", got)
+	}
+	if got := googleWorkspacePrivilegeMapping.Level(rows[1].Kind, rows[1].Permission, rows[1].RoleName); got != registry.PrivilegeAdmin {
+		t.Fatalf("custom admin role level = %v, want admin", got)
+	}
+}
@@ -0,0 +1,292 @@
+// Package httpclient builds the HTTP clients connectors use to reach provider APIs, so an egress
+// proxy and a custom CA bundle configured once apply to every connector.
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Options configures the transport shared by connector HTTP clients.
+type Options struct {
+	// ProxyURL is used for both http and https requests. When empty the standard
+	// HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment variables apply.
+	ProxyURL string
+	// NoProxy lists hosts, domains, and CIDRs that bypass ProxyURL, in NO_PROXY format.
+	NoProxy string
+	// CABundleFile is a PEM file of CA certificates trusted in addition to the system roots.
+	CABundleFile string
+	// RequestTimeout, when positive, replaces the timeout each connector asks New for.
+	RequestTimeout time.Duration
+}
+
+// DefaultRequestTimeout bounds requests from clients that ask New for no timeout, so a hung
+// provider endpoint cannot stall a sync forever.
+const DefaultRequestTimeout = 2 * time.Minute
+
+var (
+	shared         atomic.Pointer[http.Transport]
+	requestTimeout atomic.Int64
+)
+
+// Configure replaces the transport returned by Transport and used by New, and the request
+// timeout override applied by RequestTimeout.
+func Configure(opts Options) error {
+	transport, err := NewTransport(opts)
+	if err != nil {
+		return err
+	}
+	shared.Store(transport)
+	requestTimeout.Store(int64(max(opts.RequestTimeout, 0)))
+	return nil
+}
+
+// RequestTimeout returns the per-request timeout for a client whose connector default is
+// timeout: the configured override if any, else timeout, else DefaultRequestTimeout.
+func RequestTimeout(timeout time.Duration) time.Duration {
+	if configured := time.Duration(requestTimeout.Load()); configured > 0 {
+		return configured
+	}
+	if timeout > 0 {
+		return timeout
+	}
+	return DefaultRequestTimeout
+}
+
+// Transport returns the configured connector transport, or the default transport when
+// Configure was never called.
+func Transport() *http.Transport {
+	if transport := shared.Load(); transport != nil {
+		return transport
+	}
+	transport, _ := NewTransport(Options{})
+	shared.CompareAndSwap(nil, transport)
+	return shared.Load()
+}
+
+// New returns a client on the shared connector transport with the timeout RequestTimeout
+// picks for timeout. Its requests are counted against the RequestCounter of their context.
+func New(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: RequestTimeout(timeout), Transport: CountRequests(Transport())}
+}
+
+// Error classes of a counted request. A request that got a response is classed by its status;
+// one that did not is classed by its transport error.
+const (
+	ErrorClassThrottled   = "throttled"
+	ErrorClassClientError = "client_error"
+	ErrorClassServerError = "server_error"
+	ErrorClassTimeout     = "timeout"
+	ErrorClassCanceled    = "canceled"
+	ErrorClassNetwork     = "network"
+)
+
+// RequestStats summarizes the requests a RequestCounter counted. Bytes are request and response
+// bodies only; a response body counts as it is read.
+type RequestStats struct {
+	Requests      int64            `json:"requests"`
+	BytesSent     int64            `json:"bytes_sent"`
+	BytesReceived int64            `json:"bytes_received"`
+	Hosts         map[string]int64 `json:"hosts,omitempty"`
+	Errors        map[string]int64 `json:"errors,omitempty"`
+}
+
+// RequestCounter counts the provider API requests made by one sync run.
+type RequestCounter struct {
+	n             atomic.Int64
+	bytesSent     atomic.Int64
+	bytesReceived atomic.Int64
+
+	mu     sync.Mutex
+	hosts  map[string]int64
+	errors map[string]int64
+}
+
+// Count returns the number of requests counted so far.
+func (c *RequestCounter) Count() int64 {
+	if c == nil {
+		return 0
+	}
+	return c.n.Load()
+}
+
+// Stats returns a copy of what was counted so far.
+func (c *RequestCounter) Stats() RequestStats {
+	if c == nil {
+		return RequestStats{}
+	}
+	stats := RequestStats{
+		Requests:      c.n.Load(),
+		BytesSent:     c.bytesSent.Load(),
+		BytesReceived: c.bytesReceived.Load(),
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.hosts) > 0 {
+		stats.Hosts = maps.Clone(c.hosts)
+	}
+	if len(c.errors) > 0 {
+		stats.Errors = maps.Clone(c.errors)
+	}
+	return stats
+}
+
+func (c *RequestCounter) record(req *http.Request, resp *http.Response, err error) {
+	c.n.Add(1)
+	if req.ContentLength > 0 {
+		c.bytesSent.Add(req.ContentLength)
+	}
+	class := RequestErrorClass(req.Context(), resp, err)
+	host := strings.ToLower(req.URL.Hostname())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if host != "" {
+		if c.hosts == nil {
+			c.hosts = map[string]int64{}
+		}
+		c.hosts[host]++
+	}
+	if class != "" {
+		if c.errors == nil {
+			c.errors = map[string]int64{}
+		}
+		c.errors[class]++
+	}
+}
+
+// RequestErrorClass classes the outcome of a round trip, or returns "" for a successful one.
+func RequestErrorClass(ctx context.Context, resp *http.Response, err error) string {
+	if err != nil {
+		var netErr net.Error
+		switch {
+		case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+			return ErrorClassTimeout
+		case errors.Is(err, context.Canceled), ctx.Err() != nil:
+			return ErrorClassCanceled
+		default:
+			return ErrorClassNetwork
+		}
+	}
+	switch {
+	case resp == nil:
+		return ""
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return ErrorClassThrottled
+	case resp.StatusCode >= 500:
+		return ErrorClassServerError
+	case resp.StatusCode >= 400:
+		return ErrorClassClientError
+	}
+	return ""
+}
+
+type requestCounterKey struct{}
+
+// WithRequestCounter returns a context whose requests through counting transports add to counter.
+func WithRequestCounter(ctx context.Context, counter *RequestCounter) context.Context {
+	return context.WithValue(ctx, requestCounterKey{}, counter)
+}
+
+// RequestCounterFromContext returns the counter attached by WithRequestCounter, or nil.
+func RequestCounterFromContext(ctx context.Context) *RequestCounter {
+	counter, _ := ctx.Value(requestCounterKey{}).(*RequestCounter)
+	return counter
+}
+
+// CountRequests wraps base so every round trip adds to its request context's counter.
+// Clients that build their own transport use it to stay visible to the per-run request count.
+func CountRequests(base http.RoundTripper) http.RoundTripper {
+	return countingTransport{base: base}
+}
+
+type countingTransport struct {
+	base http.RoundTripper
+}
+
+func (t countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	counter := RequestCounterFromContext(req.Context())
+	if counter == nil {
+		return t.base.RoundTrip(req)
+	}
+	resp, err := t.base.RoundTrip(req)
+	counter.record(req, resp, err)
+	if resp != nil && resp.Body != nil {
+		resp.Body = &countingBody{ReadCloser: resp.Body, counter: counter}
+	}
+	return resp, err
+}
+
+// countingBody adds the bytes read from a response body to its counter.
+type countingBody struct {
+	io.ReadCloser
+	counter *RequestCounter
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.counter.bytesReceived.Add(int64(n))
+	return n, err
+}
+
+// NewTransport clones http.DefaultTransport and applies opts to it.
+func NewTransport(opts Options) (*http.Transport, error) {
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("http.DefaultTransport is not an *http.Transport")
+	}
+	transport := base.Clone()
+
+	if proxyURL := strings.TrimSpace(opts.ProxyURL); proxyURL != "" {
+		parsed, err := url.Parse(proxyURL)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid connector proxy URL %q", proxyURL)
+		}
+		proxyFunc := (&httpproxy.Config{
+			HTTPProxy:  proxyURL,
+			HTTPSProxy: proxyURL,
+			NoProxy:    strings.TrimSpace(opts.NoProxy),
+		}).ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	if path := strings.TrimSpace(opts.CABundleFile); path != "" {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read connector CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("connector CA bundle %s contains no PEM certificates", path)
+		}
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		} else {
+			transport.TLSClientConfig = transport.TLSClientConfig.Clone()
+		}
+		transport.TLSClientConfig.MinVersion = tls.VersionTLS12
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	return transport, nil
+}
@@ -0,0 +1,235 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewTransportRoutesThroughProxy(t *testing.T) {
+	t.Parallel()
+
+	var proxiedURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedURL = r.URL.String()
+		_, _ = io.WriteString(w, "via proxy")
+	}))
+	defer proxy.Close()
+
+	transport, err := NewTransport(Options{ProxyURL: proxy.URL, NoProxy: "internal.example"})
+	if err != nil {
+		t.Fatalf("NewTransport() error = %v", err)
+	}
+	client := &http.Client{Timeout: 5 * time.Second, Transport: transport}
+
+	resp, err := client.Get("http://api.github.invalid/orgs/acme")
+	if err != nil {
+		t.Fatalf("GET through proxy error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "via proxy" {
+		t.Fatalf("body = %q, want response from proxy", body)
+	}
+	if proxiedURL != "http://api.github.invalid/orgs/acme" {
+		t.Fatalf("proxy saw %q, want absolute target URL", proxiedURL)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://vault.internal.example/v1/sys/health", nil)
+	if got, err := transport.Proxy(req); err != nil || got != nil {
+		t.Fatalf("Proxy(no_proxy host) = %v, %v; want direct", got, err)
+	}
+}
+
+func TestNewTransportTrustsCABundle(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certPEM, 0o600); err != nil {
+		t.Fatalf("write CA bundle: %v", err)
+	}
+
+	untrusted, err := NewTransport(Options{})
+	if err != nil {
+		t.Fatalf("NewTransport() error = %v", err)
+	}
+	if _, err := (&http.Client{Timeout: 5 * time.Second, Transport: untrusted}).Get(server.URL); err == nil {
+		t.Fatal("expected TLS verification failure without the CA bundle")
+	}
+
+	trusted, err := NewTransport(Options{CABundleFile: bundle})
+	if err != nil {
+		t.Fatalf("NewTransport() error = %v", err)
+	}
+	resp, err := (&http.Client{Timeout: 5 * time.Second, Transport: trusted}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET with CA bundle error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestNewTransportRejectsBadOptions(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewTransport(Options{ProxyURL: "proxy.example:3128"}); err == nil {
+		t.Fatal("expected error for proxy URL without scheme")
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("write bundle: %v", err)
+	}
+	if _, err := NewTransport(Options{CABundleFile: empty}); err == nil {
+		t.Fatal("expected error for CA bundle without certificates")
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	t.Cleanup(func() { _ = Configure(Options{}) })
+
+	if err := Configure(Options{}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if got := RequestTimeout(30 * time.Second); got != 30*time.Second {
+		t.Fatalf("RequestTimeout(30s) = %s, want the connector default", got)
+	}
+	if got := New(0).Timeout; got != DefaultRequestTimeout {
+		t.Fatalf("New(0).Timeout = %s, want %s", got, DefaultRequestTimeout)
+	}
+
+	if err := Configure(Options{RequestTimeout: 10 * time.Second}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if got := New(120 * time.Second).Timeout; got != 10*time.Second {
+		t.Fatalf("New(120s).Timeout = %s, want the configured 10s", got)
+	}
+}
+
+func TestNewCountsRequestsPerContext(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	counter := &RequestCounter{}
+	ctx := WithRequestCounter(context.Background(), counter)
+	client := New(5 * time.Second)
+	for i := 1; i <= 3; i++ {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("GET error = %v", err)
+		}
+		resp.Body.Close()
+		if got := counter.Count(); got != int64(i) {
+			t.Fatalf("Count() after %d requests = %d", i, got)
+		}
+	}
+
+	// Requests without a counter in their context are not attributed to any run.
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	resp.Body.Close()
+	if got := counter.Count(); got != 3 {
+		t.Fatalf("Count() = %d, want 3", got)
+	}
+}
+
+func TestNewRecordsRequestStats(t *testing.T) {
+	t.Parallel()
+
+	var received atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := received.Add(1)
+		_, _ = io.Copy(io.Discard, r.Body)
+		switch {
+		case n == 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case n == 3:
+			w.WriteHeader(http.StatusNotFound)
+		case n == 4:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+		_, _ = io.WriteString(w, "hello")
+	}))
+	defer server.Close()
+
+	counter := &RequestCounter{}
+	ctx := WithRequestCounter(context.Background(), counter)
+	client := New(5 * time.Second)
+	for range 5 {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, strings.NewReader("ping"))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("POST error = %v", err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	stats := counter.Stats()
+	if stats.Requests != received.Load() || stats.Requests != 5 {
+		t.Fatalf("Requests = %d, handler received %d", stats.Requests, received.Load())
+	}
+	if stats.BytesSent != 5*int64(len("ping")) || stats.BytesReceived != 5*int64(len("hello")) {
+		t.Fatalf("bytes sent/received = %d/%d, want 20/25", stats.BytesSent, stats.BytesReceived)
+	}
+	host := strings.Split(strings.TrimPrefix(server.URL, "http://"), ":")[0]
+	if stats.Hosts[host] != 5 {
+		t.Fatalf("Hosts = %v, want 5 calls to %s", stats.Hosts, host)
+	}
+	wantErrors := map[string]int64{ErrorClassThrottled: 1, ErrorClassClientError: 1, ErrorClassServerError: 1}
+	if !maps.Equal(stats.Errors, wantErrors) {
+		t.Fatalf("Errors = %v, want %v", stats.Errors, wantErrors)
+	}
+}
+
+func TestRequestErrorClassTransportErrors(t *testing.T) {
+	t.Parallel()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	cases := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want string
+	}{
+		{name: "deadline", ctx: context.Background(), err: context.DeadlineExceeded, want: ErrorClassTimeout},
+		{name: "canceled", ctx: canceled, err: errors.New("request aborted"), want: ErrorClassCanceled},
+		{name: "network", ctx: context.Background(), err: errors.New("connection refused"), want: ErrorClassNetwork},
+		{name: "ok", ctx: context.Background(), want: ""},
+	}
+	for _, tc := range cases {
+		var resp *http.Response
+		if tc.err == nil {
+			resp = &http.Response{StatusCode: http.StatusOK}
+		}
+		if got := RequestErrorClass(tc.ctx, resp, tc.err); got != tc.want {
+			t.Fatalf("%s: RequestErrorClass() = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package httpclient
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limits paces the provider API requests of one connector kind. They apply process-wide, so
+// every source of the kind shares them.
+type Limits struct {
+	// RequestsPerSecond is the sustained request rate; 0 leaves the rate unlimited.
+	RequestsPerSecond float64
+	// MaxConcurrent bounds the requests awaiting a response at once; 0 leaves it unbounded.
+	MaxConcurrent int
+	// MaxBackoff caps how long the connector waits before retrying a throttled or failed
+	// request, including a longer Retry-After asked for by the provider.
+	MaxBackoff time.Duration
+}
+
+// DefaultMaxBackoff caps retry waits of connector kinds without their own limits.
+const DefaultMaxBackoff = 30 * time.Second
+
+// defaultLimits stay below each provider's documented limits for a single tenant: GitHub's
+// secondary limits are the strictest, Google's Admin SDK quotas the most generous.
+var defaultLimits = map[string]Limits{
+	"aws":              {RequestsPerSecond: 20, MaxConcurrent: 16, MaxBackoff: 30 * time.Second},
+	"bitbucket":        {RequestsPerSecond: 5, MaxConcurrent: 4, MaxBackoff: 30 * time.Second},
+	"datadog":          {RequestsPerSecond: 10, MaxConcurrent: 4, MaxBackoff: 30 * time.Second},
+	"entra":            {RequestsPerSecond: 20, MaxConcurrent: 16, MaxBackoff: 30 * time.Second},
+	"github":           {RequestsPerSecond: 10, MaxConcurrent: 8, MaxBackoff: 30 * time.Second},
+	"google_workspace": {RequestsPerSecond: 25, MaxConcurrent: 16, MaxBackoff: 8 * time.Second},
+	"okta":             {RequestsPerSecond: 10, MaxConcurrent: 8, MaxBackoff: 30 * time.Second},
+}
+
+var limiters atomic.Pointer[map[string]*limiter]
+
+// DefaultLimits returns the built-in limits of a connector kind.
+func DefaultLimits(kind string) Limits {
+	if limits, ok := defaultLimits[normalizeKind(kind)]; ok {
+		return limits
+	}
+	return Limits{MaxBackoff: DefaultMaxBackoff}
+}
+
+// ConfigureLimits replaces the limits applied by clients from NewForKind. Kinds missing from
+// limits get their DefaultLimits.
+func ConfigureLimits(limits map[string]Limits) {
+	limiters.Store(buildLimiters(limits))
+}
+
+func buildLimiters(limits map[string]Limits) *map[string]*limiter {
+	out := make(map[string]*limiter, len(defaultLimits)+len(limits))
+	for kind, l := range defaultLimits {
+		out[kind] = newLimiter(l)
+	}
+	for kind, l := range limits {
+		if l.MaxBackoff <= 0 {
+			l.MaxBackoff = DefaultLimits(kind).MaxBackoff
+		}
+		out[normalizeKind(kind)] = newLimiter(l)
+	}
+	return &out
+}
+
+// MaxBackoff returns the longest a connector of kind waits before retrying a request.
+func MaxBackoff(kind string) time.Duration {
+	if l := limiterFor(kind); l != nil {
+		return l.limits.MaxBackoff
+	}
+	return DefaultMaxBackoff
+}
+
+// NewForKind is New for a client whose requests are paced by the limits of a connector kind.
+func NewForKind(kind string, timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: RequestTimeout(timeout), Transport: CountRequests(LimitRequests(kind, Transport()))}
+}
+
+// LimitRequests wraps base so every round trip waits for the rate and concurrency limits of
+// kind. The limits are looked up per request, so ConfigureLimits applies to existing clients.
+func LimitRequests(kind string, base http.RoundTripper) http.RoundTripper {
+	return limitingTransport{kind: normalizeKind(kind), base: base}
+}
+
+type limitingTransport struct {
+	kind string
+	base http.RoundTripper
+}
+
+func (t limitingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	l := limiterFor(t.kind)
+	if l == nil {
+		return t.base.RoundTrip(req)
+	}
+	release, err := l.acquire(req.Context())
+	if err != nil {
+		closeRequestBody(req)
+		return nil, err
+	}
+	defer release()
+	return t.base.RoundTrip(req)
+}
+
+type limiter struct {
+	limits Limits
+	rate   *rate.Limiter
+	slots  chan struct{}
+}
+
+func newLimiter(limits Limits) *limiter {
+	l := &limiter{limits: limits}
+	if limits.RequestsPerSecond > 0 {
+		burst := max(1, int(math.Ceil(limits.RequestsPerSecond)))
+		l.rate = rate.NewLimiter(rate.Limit(limits.RequestsPerSecond), burst)
+	}
+	if limits.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, limits.MaxConcurrent)
+	}
+	return l
+}
+
+// acquire waits for a concurrency slot and then for the rate limiter. The returned func frees
+// the slot.
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+			release = func() { <-l.slots }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if l.rate != nil {
+		if err := l.rate.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+func limiterFor(kind string) *limiter {
+	m := limiters.Load()
+	if m == nil {
+		limiters.CompareAndSwap(nil, buildLimiters(nil))
+		m = limiters.Load()
+	}
+	return (*m)[normalizeKind(kind)]
+}
+
+func normalizeKind(kind string) string {
+	return strings.ToLower(strings.TrimSpace(kind))
+}
+
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
@@ -0,0 +1,56 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewForKindEnforcesConcurrencyCap(t *testing.T) {
+	t.Cleanup(func() { ConfigureLimits(nil) })
+	ConfigureLimits(map[string]Limits{"github": {MaxConcurrent: 2}})
+
+	var inFlight, maxInFlight atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if n <= seen || maxInFlight.CompareAndSwap(seen, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewForKind("GitHub", 5*time.Second)
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Errorf("GET error = %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if got := maxInFlight.Load(); got != 2 {
+		t.Fatalf("max requests in flight = %d, want the configured cap of 2", got)
+	}
+	if got := MaxBackoff("github"); got != DefaultLimits("github").MaxBackoff {
+		t.Fatalf("MaxBackoff(github) = %s, want the default %s when not configured", got, DefaultLimits("github").MaxBackoff)
+	}
+	if got := MaxBackoff("unknown"); got != DefaultMaxBackoff {
+		t.Fatalf("MaxBackoff(unknown) = %s, want %s", got, DefaultMaxBackoff)
+	}
+}
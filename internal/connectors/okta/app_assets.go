@@ -0,0 +1,376 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+const (
+	oktaAppAssetKind              = "okta_app"
+	oktaClientSecretKind          = "okta_oidc_client_secret"
+	oktaSignOnModeOpenIDConnect   = "OPENID_CONNECT"
+	oktaAppAssetBatchSize         = 1000
+	oktaCredentialArtifactBatchSz = 2000
+)
+
+// oktaAppAssignmentCounts tallies the users and groups assigned to each app while the
+// assignment stages run, for the app asset inventory.
+type oktaAppAssignmentCounts struct {
+	mu     sync.Mutex
+	users  map[string]int
+	groups map[string]int
+}
+
+func newOktaAppAssignmentCounts() *oktaAppAssignmentCounts {
+	return &oktaAppAssignmentCounts{users: map[string]int{}, groups: map[string]int{}}
+}
+
+func (c *oktaAppAssignmentCounts) setUsers(appID string, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.users[appID] = n
+}
+
+func (c *oktaAppAssignmentCounts) setGroups(appID string, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.groups[appID] = n
+}
+
+type oktaAppAssetRow struct {
+	ExternalID      string
+	DisplayName     string
+	Status          string
+	CreatedAtSource pgtype.Timestamptz
+	UpdatedAtSource pgtype.Timestamptz
+	RawJSON         []byte
+}
+
+type oktaCredentialArtifactRow struct {
+	AssetRefKind       string
+	AssetRefExternalID string
+	ExternalID         string
+	DisplayName        string
+	Fingerprint        string
+	ScopeJSON          []byte
+	Status             string
+	CreatedAtSource    pgtype.Timestamptz
+	RawJSON            []byte
+}
+
+// oktaAppOAuthClient is the part of an app's credentials block that OIDC apps carry.
+type oktaAppOAuthClient struct {
+	ClientID                string `json:"client_id"`
+	TokenEndpointAuthMethod string `json:"token_endpoint_auth_method"`
+}
+
+type oktaAppPayload struct {
+	Created     string `json:"created"`
+	LastUpdated string `json:"lastUpdated"`
+	Credentials struct {
+		OAuthClient oktaAppOAuthClient `json:"oauthClient"`
+	} `json:"credentials"`
+	Settings struct {
+		OAuthClient struct {
+			ApplicationType string   `json:"application_type"`
+			GrantTypes      []string `json:"grant_types"`
+		} `json:"oauthClient"`
+	} `json:"settings"`
+}
+
+func decodeOktaAppPayload(raw []byte) oktaAppPayload {
+	var payload oktaAppPayload
+	_ = json.Unmarshal(raw, &payload)
+	return payload
+}
+
+// oktaAppUsesClientSecret reports whether app is an OIDC app that may hold client secrets.
+// Public clients and private_key_jwt clients authenticate without one.
+func oktaAppUsesClientSecret(app App) bool {
+	if !strings.EqualFold(strings.TrimSpace(app.SignOnMode), oktaSignOnModeOpenIDConnect) {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(decodeOktaAppPayload(app.RawJSON).Credentials.OAuthClient.TokenEndpointAuthMethod)) {
+	case "none", "private_key_jwt":
+		return false
+	default:
+		return true
+	}
+}
+
+// collectOktaClientSecrets lists the client secrets of every OIDC app that uses them. An app
+// whose secrets cannot be listed is skipped with a warning and its secrets are held from expiry.
+func (i *OktaIntegration) collectOktaClientSecrets(ctx context.Context, warnings *registry.WarningReporter, holds *registry.ExpiryHolds, apps []App) map[string][]ClientSecret {
+	var candidates []App
+	for _, app := range apps {
+		if oktaAppUsesClientSecret(app) {
+			candidates = append(candidates, app)
+		}
+	}
+	warnings.Report(registry.Event{Source: "okta", Stage: "list-client-secrets", Current: 0, Total: int64(len(candidates)), Message: fmt.Sprintf("listing client secrets for %d OIDC apps", len(candidates))})
+
+	out := make(map[string][]ClientSecret, len(candidates))
+	if len(candidates) == 0 {
+		return out
+	}
+
+	workers := min(len(candidates), i.workers)
+	if workers < 1 {
+		workers = 1
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan App, len(candidates))
+	worker := func() {
+		defer wg.Done()
+		for app := range jobs {
+			if ctx.Err() != nil {
+				return
+			}
+			secrets, err := i.client.ListOAuth2ClientSecrets(ctx, app.ID)
+			if err != nil {
+				holds.HoldCredentials(oktaClientSecretKind, oktaAppAssetRefExternalID(app.ID))
+				warnings.ReportWarning(registry.Event{Source: "okta", Stage: "list-client-secrets", Message: fmt.Sprintf("skipped client secrets of app %s: %v", app.ID, err)})
+				continue
+			}
+			mu.Lock()
+			out[app.ID] = secrets
+			mu.Unlock()
+		}
+	}
+
+	for j := 0; j < workers; j++ {
+		wg.Add(1)
+		go worker()
+	}
+	for _, app := range candidates {
+		jobs <- app
+	}
+	close(jobs)
+	wg.Wait()
+
+	warnings.Report(registry.Event{Source: "okta", Stage: "list-client-secrets", Current: int64(len(candidates)), Total: int64(len(candidates)), Message: fmt.Sprintf("listed client secrets for %d OIDC apps", len(candidates))})
+	return out
+}
+
+// oktaAppAssetRefExternalID is the asset reference client secret credentials carry for an app.
+func oktaAppAssetRefExternalID(appID string) string {
+	return oktaAppAssetKind + ":" + strings.TrimSpace(appID)
+}
+
+// buildOktaAppAssetAndCredentialRows maps Okta apps to app assets and their OIDC client secrets
+// to credentials referencing those assets.
+func buildOktaAppAssetAndCredentialRows(apps []App, counts *oktaAppAssignmentCounts, secrets map[string][]ClientSecret) ([]oktaAppAssetRow, []oktaCredentialArtifactRow) {
+	assetRows := make([]oktaAppAssetRow, 0, len(apps))
+	credentialRows := make([]oktaCredentialArtifactRow, 0)
+
+	for _, app := range apps {
+		externalID := strings.TrimSpace(app.ID)
+		if externalID == "" {
+			continue
+		}
+		displayName := strings.TrimSpace(app.Label)
+		if displayName == "" {
+			displayName = strings.TrimSpace(app.Name)
+		}
+		if displayName == "" {
+			displayName = externalID
+		}
+		payload := decodeOktaAppPayload(app.RawJSON)
+		oauthClient := payload.Credentials.OAuthClient
+		counts.mu.Lock()
+		userCount, groupCount := counts.users[externalID], counts.groups[externalID]
+		counts.mu.Unlock()
+
+		assetRows = append(assetRows, oktaAppAssetRow{
+			ExternalID:      externalID,
+			DisplayName:     displayName,
+			Status:          strings.ToLower(strings.TrimSpace(app.Status)),
+			CreatedAtSource: parseOktaTime(payload.Created),
+			UpdatedAtSource: parseOktaTime(payload.LastUpdated),
+			RawJSON: registry.MarshalJSON(map[string]any{
+				"id":                         externalID,
+				"label":                      strings.TrimSpace(app.Label),
+				"name":                       strings.TrimSpace(app.Name),
+				"status":                     strings.TrimSpace(app.Status),
+				"sign_on_mode":               strings.TrimSpace(app.SignOnMode),
+				"client_id":                  strings.TrimSpace(oauthClient.ClientID),
+				"token_endpoint_auth_method": strings.TrimSpace(oauthClient.TokenEndpointAuthMethod),
+				"application_type":           strings.TrimSpace(payload.Settings.OAuthClient.ApplicationType),
+				"grant_types":                payload.Settings.OAuthClient.GrantTypes,
+				"assigned_user_count":        userCount,
+				"assigned_group_count":       groupCount,
+			}),
+		})
+
+		assetRefExternalID := oktaAppAssetRefExternalID(externalID)
+		for _, secret := range secrets[externalID] {
+			secretID := strings.TrimSpace(secret.ID)
+			if secretID == "" {
+				continue
+			}
+			credentialRows = append(credentialRows, oktaCredentialArtifactRow{
+				AssetRefKind:       "app_asset",
+				AssetRefExternalID: assetRefExternalID,
+				ExternalID:         secretID,
+				DisplayName:        displayName + " client secret",
+				Fingerprint:        strings.TrimSpace(secret.SecretHash),
+				ScopeJSON: registry.MarshalJSON(map[string]any{
+					"asset_kind":                 oktaAppAssetKind,
+					"asset_external_id":          externalID,
+					"client_id":                  strings.TrimSpace(oauthClient.ClientID),
+					"token_endpoint_auth_method": strings.TrimSpace(oauthClient.TokenEndpointAuthMethod),
+					"grant_types":                payload.Settings.OAuthClient.GrantTypes,
+				}),
+				Status:          strings.ToLower(strings.TrimSpace(secret.Status)),
+				CreatedAtSource: parseOktaTime(secret.CreatedRaw),
+				RawJSON:         registry.NormalizeRawJSON(secret.RawJSON),
+			})
+		}
+	}
+
+	return assetRows, credentialRows
+}
+
+func (i *OktaIntegration) upsertOktaAppAssets(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, rows []oktaAppAssetRow) error {
+	report(registry.Event{Source: "okta", Stage: "write-app-assets", Current: 0, Total: int64(len(rows)), Message: fmt.Sprintf("writing %d app assets", len(rows))})
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for start := 0; start < len(rows); start += oktaAppAssetBatchSize {
+		end := min(start+oktaAppAssetBatchSize, len(rows))
+		batch := rows[start:end]
+
+		assetKinds := make([]string, 0, len(batch))
+		externalIDs := make([]string, 0, len(batch))
+		parentExternalIDs := make([]string, 0, len(batch))
+		displayNames := make([]string, 0, len(batch))
+		statuses := make([]string, 0, len(batch))
+		createdAtSources := make([]pgtype.Timestamptz, 0, len(batch))
+		updatedAtSources := make([]pgtype.Timestamptz, 0, len(batch))
+		rawJSONs := make([][]byte, 0, len(batch))
+		for _, row := range batch {
+			assetKinds = append(assetKinds, oktaAppAssetKind)
+			externalIDs = append(externalIDs, row.ExternalID)
+			parentExternalIDs = append(parentExternalIDs, "")
+			displayNames = append(displayNames, row.DisplayName)
+			statuses = append(statuses, row.Status)
+			createdAtSources = append(createdAtSources, row.CreatedAtSource)
+			updatedAtSources = append(updatedAtSources, row.UpdatedAtSource)
+			rawJSONs = append(rawJSONs, row.RawJSON)
+		}
+
+		if _, err := q.UpsertAppAssetsBulkBySource(ctx, gen.UpsertAppAssetsBulkBySourceParams{
+			SourceKind:        "okta",
+			SourceName:        i.sourceName,
+			SeenInRunID:       runID,
+			AssetKinds:        assetKinds,
+			ExternalIds:       externalIDs,
+			ParentExternalIds: parentExternalIDs,
+			DisplayNames:      displayNames,
+			Statuses:          statuses,
+			CreatedAtSources:  createdAtSources,
+			UpdatedAtSources:  updatedAtSources,
+			RawJsons:          rawJSONs,
+		}); err != nil {
+			return err
+		}
+
+		report(registry.Event{Source: "okta", Stage: "write-app-assets", Current: int64(end), Total: int64(len(rows)), Message: fmt.Sprintf("app assets %d/%d", end, len(rows))})
+	}
+
+	return nil
+}
+
+func (i *OktaIntegration) upsertOktaCredentialArtifacts(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, rows []oktaCredentialArtifactRow) error {
+	report(registry.Event{Source: "okta", Stage: "write-credentials", Current: 0, Total: int64(len(rows)), Message: fmt.Sprintf("writing %d credential rows", len(rows))})
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for start := 0; start < len(rows); start += oktaCredentialArtifactBatchSz {
+		end := min(start+oktaCredentialArtifactBatchSz, len(rows))
+		batch := rows[start:end]
+
+		assetRefKinds := make([]string, 0, len(batch))
+		assetRefExternalIDs := make([]string, 0, len(batch))
+		credentialKinds := make([]string, 0, len(batch))
+		externalIDs := make([]string, 0, len(batch))
+		displayNames := make([]string, 0, len(batch))
+		fingerprints := make([]string, 0, len(batch))
+		scopeJSONs := make([][]byte, 0, len(batch))
+		statuses := make([]string, 0, len(batch))
+		createdAtSources := make([]pgtype.Timestamptz, 0, len(batch))
+		expiresAtSources := make([]pgtype.Timestamptz, 0, len(batch))
+		lastUsedAtSources := make([]pgtype.Timestamptz, 0, len(batch))
+		emptyActors := make([]string, 0, len(batch))
+		rawJSONs := make([][]byte, 0, len(batch))
+		for _, row := range batch {
+			assetRefKinds = append(assetRefKinds, row.AssetRefKind)
+			assetRefExternalIDs = append(assetRefExternalIDs, row.AssetRefExternalID)
+			credentialKinds = append(credentialKinds, oktaClientSecretKind)
+			externalIDs = append(externalIDs, row.ExternalID)
+			displayNames = append(displayNames, row.DisplayName)
+			fingerprints = append(fingerprints, row.Fingerprint)
+			scopeJSONs = append(scopeJSONs, row.ScopeJSON)
+			statuses = append(statuses, row.Status)
+			createdAtSources = append(createdAtSources, row.CreatedAtSource)
+			expiresAtSources = append(expiresAtSources, pgtype.Timestamptz{})
+			lastUsedAtSources = append(lastUsedAtSources, pgtype.Timestamptz{})
+			emptyActors = append(emptyActors, "")
+			rawJSONs = append(rawJSONs, row.RawJSON)
+		}
+
+		if _, err := q.UpsertCredentialArtifactsBulkBySource(ctx, gen.UpsertCredentialArtifactsBulkBySourceParams{
+			SourceKind:             "okta",
+			SourceName:             i.sourceName,
+			SeenInRunID:            runID,
+			AssetRefKinds:          assetRefKinds,
+			AssetRefExternalIds:    assetRefExternalIDs,
+			CredentialKinds:        credentialKinds,
+			ExternalIds:            externalIDs,
+			DisplayNames:           displayNames,
+			Fingerprints:           fingerprints,
+			ScopeJsons:             scopeJSONs,
+			Statuses:               statuses,
+			CreatedAtSources:       createdAtSources,
+			ExpiresAtSources:       expiresAtSources,
+			LastUsedAtSources:      lastUsedAtSources,
+			CreatedByKinds:         emptyActors,
+			CreatedByExternalIds:   emptyActors,
+			CreatedByDisplayNames:  emptyActors,
+			ApprovedByKinds:        emptyActors,
+			ApprovedByExternalIds:  emptyActors,
+			ApprovedByDisplayNames: emptyActors,
+			RawJsons:               rawJSONs,
+		}); err != nil {
+			return err
+		}
+
+		report(registry.Event{Source: "okta", Stage: "write-credentials", Current: int64(end), Total: int64(len(rows)), Message: fmt.Sprintf("credentials %d/%d", end, len(rows))})
+	}
+
+	return nil
+}
+
+func parseOktaTime(raw string) pgtype.Timestamptz {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return pgtype.Timestamptz{}
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: parsed, Valid: true}
+}
@@ -0,0 +1,124 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+)
+
+func TestBuildOktaAppAssetAndCredentialRowsMapsOIDCAppWithClientSecret(t *testing.T) {
+	t.Parallel()
+
+	oidcApp := App{
+		ID:         "0oa1oidc",
+		Label:      "Billing API",
+		Name:       "oidc_client",
+		Status:     "ACTIVE",
+		SignOnMode: "OPENID_CONNECT",
+		RawJSON: []byte(`{
+			"id": "0oa1oidc",
+			"created": "2025-01-02T03:04:05.000Z",
+			"lastUpdated": "2026-02-03T04:05:06.000Z",
+			"credentials": {"oauthClient": {"client_id": "0oa1oidc", "token_endpoint_auth_method": "client_secret_basic"}},
+			"settings": {"oauthClient": {"application_type": "service", "grant_types": ["client_credentials"]}}
+		}`),
+	}
+	samlApp := App{ID: "0oa2saml", Label: "Wiki", Name: "wiki_saml", Status: "INACTIVE", SignOnMode: "SAML_2_0", RawJSON: []byte(`{"id":"0oa2saml"}`)}
+
+	if !oktaAppUsesClientSecret(oidcApp) {
+		t.Fatalf("oktaAppUsesClientSecret(oidc) = false, want true")
+	}
+	if oktaAppUsesClientSecret(samlApp) {
+		t.Fatalf("oktaAppUsesClientSecret(saml) = true, want false")
+	}
+	publicClient := oidcApp
+	publicClient.RawJSON = []byte(`{"credentials":{"oauthClient":{"token_endpoint_auth_method":"none"}}}`)
+	if oktaAppUsesClientSecret(publicClient) {
+		t.Fatalf("oktaAppUsesClientSecret(public client) = true, want false")
+	}
+
+	counts := newOktaAppAssignmentCounts()
+	counts.setUsers("0oa1oidc", 3)
+	counts.setGroups("0oa1oidc", 2)
+	secrets := map[string][]ClientSecret{
+		"0oa1oidc": {{
+			ID:         "ocs1",
+			Status:     "ACTIVE",
+			SecretHash: "yk4SVx4sUWVJVbHt6M-UPA",
+			CreatedRaw: "2025-06-01T00:00:00.000Z",
+			RawJSON:    []byte(`{"id":"ocs1","status":"ACTIVE","secret_hash":"yk4SVx4sUWVJVbHt6M-UPA"}`),
+		}},
+	}
+
+	assets, credentials := buildOktaAppAssetAndCredentialRows([]App{oidcApp, samlApp}, counts, secrets)
+	if len(assets) != 2 {
+		t.Fatalf("len(assets) = %d, want 2", len(assets))
+	}
+	asset := assets[0]
+	if asset.ExternalID != "0oa1oidc" || asset.DisplayName != "Billing API" || asset.Status != "active" {
+		t.Fatalf("asset = %+v", asset)
+	}
+	if !asset.CreatedAtSource.Valid || !asset.CreatedAtSource.Time.Equal(time.Date(2025, time.January, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Fatalf("asset.CreatedAtSource = %+v", asset.CreatedAtSource)
+	}
+	if !asset.UpdatedAtSource.Valid {
+		t.Fatalf("asset.UpdatedAtSource not set")
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(asset.RawJSON, &raw); err != nil {
+		t.Fatalf("unmarshal asset raw json: %v", err)
+	}
+	if raw["sign_on_mode"] != "OPENID_CONNECT" || raw["assigned_user_count"] != float64(3) || raw["assigned_group_count"] != float64(2) {
+		t.Fatalf("asset raw json = %v", raw)
+	}
+	if assets[1].Status != "inactive" || assets[1].CreatedAtSource.Valid {
+		t.Fatalf("saml asset = %+v", assets[1])
+	}
+
+	if len(credentials) != 1 {
+		t.Fatalf("len(credentials) = %d, want 1", len(credentials))
+	}
+	credential := credentials[0]
+	if credential.AssetRefKind != "app_asset" || credential.AssetRefExternalID != "okta_app:0oa1oidc" {
+		t.Fatalf("credential asset ref = %q/%q, want app_asset/okta_app:0oa1oidc", credential.AssetRefKind, credential.AssetRefExternalID)
+	}
+	if credential.ExternalID != "ocs1" || credential.Status != "active" || credential.Fingerprint != "yk4SVx4sUWVJVbHt6M-UPA" {
+		t.Fatalf("credential = %+v", credential)
+	}
+	if !credential.CreatedAtSource.Valid || !credential.CreatedAtSource.Time.Equal(time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("credential.CreatedAtSource = %+v", credential.CreatedAtSource)
+	}
+	if !strings.Contains(string(credential.ScopeJSON), `"asset_external_id":"0oa1oidc"`) {
+		t.Fatalf("credential scope json = %s", credential.ScopeJSON)
+	}
+}
+
+func TestCollectOktaClientSecretsHoldsAppsWhoseSecretsCannotBeListed(t *testing.T) {
+	t.Parallel()
+
+	oidcApp := App{
+		ID:         "0oa1oidc",
+		SignOnMode: "OPENID_CONNECT",
+		RawJSON:    []byte(`{"credentials":{"oauthClient":{"token_endpoint_auth_method":"client_secret_basic"}}}`),
+	}
+	// An uninitialized client fails every secret listing.
+	integration := NewOktaIntegration(&Client{}, "acme.okta.com", 2, false)
+	warnings := registry.NewWarningReporter(nil)
+	holds := registry.NewExpiryHolds()
+
+	secrets := integration.collectOktaClientSecrets(context.Background(), warnings, holds, []App{oidcApp})
+	if len(secrets) != 0 {
+		t.Fatalf("secrets = %+v, want none", secrets)
+	}
+	if len(warnings.Warnings()) != 1 {
+		t.Fatalf("warnings = %+v, want one for the skipped app", warnings.Warnings())
+	}
+	// Finalize must not expire the secrets of an app whose listing failed.
+	if holds.Empty() {
+		t.Fatalf("expiry holds are empty, want the app's client secrets held")
+	}
+}
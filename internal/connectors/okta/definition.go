@@ -78,6 +78,18 @@ func (d *Definition) NewIntegration(cfg any) (registry.Integration, error) {
 	return NewOktaIntegration(client, c.Domain, d.workers, c.DiscoveryEnabled), nil
 }
 
+// CredentialKinds documents the credential kinds the integration syncs.
+func (d *Definition) CredentialKinds() []registry.CredentialKindInfo {
+	return []registry.CredentialKindInfo{
+		{
+			Kind:             oktaClientSecretKind,
+			Label:            "Okta OIDC client secret",
+			Description:      "A client secret of an Okta OIDC app integration, used by the app to authenticate to Okta's authorization server.",
+			RotationGuidance: "Add a new secret on the app, deploy it, then deactivate and delete the old one. Okta allows two active secrets, so rotation needs no downtime.",
+		},
+	}
+}
+
 type oktaMetrics struct{}
 
 func (m *oktaMetrics) FetchMetrics(ctx context.Context, q *gen.Queries, sourceName string) (registry.ConnectorMetrics, error) {
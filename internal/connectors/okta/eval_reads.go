@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	sdk "github.com/okta/okta-sdk-golang/v6/okta"
+	"github.com/open-sspm/open-sspm/internal/connectors/httpclient"
 	"github.com/open-sspm/open-sspm/internal/connectors/oktaapi"
 )
 
@@ -57,7 +58,7 @@ func (c *Client) ListPolicies(ctx context.Context, policyType string) ([]oktaapi
 		req.Header.Set("Accept", "application/json")
 		req.Header.Set("Authorization", "SSWS "+token)
 
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := httpclient.NewForKind("okta", 0).Do(req)
 		if err != nil {
 			return nil, err
 		}
@@ -141,7 +142,7 @@ func (c *Client) ListOktaSignOnPolicyRules(ctx context.Context, policyID string)
 		req.Header.Set("Accept", "application/json")
 		req.Header.Set("Authorization", "SSWS "+token)
 
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := httpclient.NewForKind("okta", 0).Do(req)
 		if err != nil {
 			return nil, err
 		}
@@ -235,7 +236,7 @@ func (c *Client) SearchApplications(ctx context.Context, query string) ([]oktaap
 		req.Header.Set("Accept", "application/json")
 		req.Header.Set("Authorization", "SSWS "+token)
 
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := httpclient.NewForKind("okta", 0).Do(req)
 		if err != nil {
 			return nil, err
 		}
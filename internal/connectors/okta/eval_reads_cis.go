@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	sdk "github.com/okta/okta-sdk-golang/v6/okta"
+	"github.com/open-sspm/open-sspm/internal/connectors/httpclient"
 	"github.com/open-sspm/open-sspm/internal/connectors/oktaapi"
 )
 
@@ -55,7 +56,7 @@ func (c *Client) ListAccessPolicyRules(ctx context.Context, policyID string) ([]
 		req.Header.Set("Accept", "application/json")
 		req.Header.Set("Authorization", "SSWS "+token)
 
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := httpclient.NewForKind("okta", 0).Do(req)
 		if err != nil {
 			return nil, err
 		}
@@ -209,7 +210,7 @@ func (c *Client) ListPasswordPolicies(ctx context.Context) ([]oktaapi.PasswordPo
 		req.Header.Set("Accept", "application/json")
 		req.Header.Set("Authorization", "SSWS "+token)
 
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := httpclient.NewForKind("okta", 0).Do(req)
 		if err != nil {
 			return nil, err
 		}
@@ -314,7 +315,7 @@ func (c *Client) ListAuthenticators(ctx context.Context) ([]oktaapi.Authenticato
 		req.Header.Set("Accept", "application/json")
 		req.Header.Set("Authorization", "SSWS "+token)
 
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := httpclient.NewForKind("okta", 0).Do(req)
 		if err != nil {
 			return nil, err
 		}
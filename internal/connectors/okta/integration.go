@@ -63,9 +63,13 @@ func (i *OktaIntegration) InitEvents() []registry.Event {
 	return []registry.Event{
 		{Source: "okta", Stage: "list-users", Current: 0, Total: 1, Message: "listing users"},
 		{Source: "okta", Stage: "sync-users", Current: 0, Total: registry.UnknownTotal, Message: "syncing users"},
+		{Source: "okta", Stage: "list-user-factors", Current: 0, Total: registry.UnknownTotal, Message: "listing MFA factors"},
 		{Source: "okta", Stage: "sync-groups", Current: 0, Total: registry.UnknownTotal, Message: "syncing groups"},
 		{Source: "okta", Stage: "sync-app-assignments", Current: 0, Total: registry.UnknownTotal, Message: "syncing app assignments"},
 		{Source: "okta", Stage: "sync-app-group-assignments", Current: 0, Total: registry.UnknownTotal, Message: "syncing app group assignments"},
+		{Source: "okta", Stage: "list-client-secrets", Current: 0, Total: registry.UnknownTotal, Message: "listing OIDC client secrets"},
+		{Source: "okta", Stage: "write-app-assets", Current: 0, Total: registry.UnknownTotal, Message: "writing app assets"},
+		{Source: "okta", Stage: "write-credentials", Current: 0, Total: registry.UnknownTotal, Message: "writing credentials"},
 		{Source: "okta", Stage: "list-discovery-events", Current: 0, Total: 1, Message: "listing discovery events"},
 		{Source: "okta", Stage: "normalize-discovery", Current: 0, Total: 1, Message: "normalizing discovery events"},
 		{Source: "okta", Stage: "write-discovery", Current: 0, Total: registry.UnknownTotal, Message: "writing discovery data"},
@@ -87,7 +91,7 @@ func (i *OktaIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpool
 
 func (i *OktaIntegration) runFull(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, report func(registry.Event)) error {
 	started := time.Now()
-	runID, err := q.CreateSyncRun(ctx, gen.CreateSyncRunParams{
+	runID, err := registry.CreateSyncRun(ctx, q, gen.CreateSyncRunParams{
 		SourceKind: registry.SyncRunSourceKind("okta", registry.RunModeFull),
 		SourceName: i.sourceName,
 	})
@@ -96,6 +100,11 @@ func (i *OktaIntegration) runFull(ctx context.Context, q *gen.Queries, pool *pgx
 	}
 	i.lastRunID = runID
 
+	warnings := registry.NewWarningReporter(report)
+	defer func() {
+		_ = registry.PersistSyncRunWarnings(ctx, q, runID, warnings.Warnings())
+	}()
+
 	users, err := i.client.ListUsers(ctx)
 	if err != nil {
 		err = fmt.Errorf("okta list users (/api/v1/users): %w", err)
@@ -105,28 +114,59 @@ func (i *OktaIntegration) runFull(ctx context.Context, q *gen.Queries, pool *pgx
 	report(registry.Event{Source: "okta", Stage: "list-users", Current: 1, Total: 1, Message: fmt.Sprintf("found %d users", len(users))})
 	report(registry.Event{Source: "okta", Stage: "sync-users", Current: 0, Total: int64(len(users)), Message: fmt.Sprintf("syncing %d users", len(users))})
 
-	if err := i.syncOktaIdpUsers(ctx, q, report, runID, users); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.syncOktaIdpUsers(ctx, qtx, report, runID, users)
+	}); err != nil {
 		report(registry.Event{Source: "okta", Stage: "sync-users", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
+	factors := i.collectOktaUserFactors(ctx, warnings, users)
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.replaceOktaUserFactors(ctx, qtx, factors)
+	}); err != nil {
+		report(registry.Event{Source: "okta", Stage: "list-user-factors", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
+	}
+
 	if err := i.syncOktaGroups(ctx, q, report, runID); err != nil {
 		report(registry.Event{Source: "okta", Stage: "sync-groups", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
-	appIDs, err := i.syncOktaAppAssignments(ctx, q, report, runID)
+	counts := newOktaAppAssignmentCounts()
+	apps, err := i.syncOktaAppAssignments(ctx, q, report, runID, counts)
 	if err != nil {
 		report(registry.Event{Source: "okta", Stage: "sync-app-assignments", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
-	if err := i.syncOktaAppGroupAssignments(ctx, q, report, runID, appIDs); err != nil {
+	appIDs := make([]string, 0, len(apps))
+	for _, app := range apps {
+		appIDs = append(appIDs, app.ID)
+	}
+	if err := i.syncOktaAppGroupAssignments(ctx, q, report, runID, appIDs, counts); err != nil {
 		report(registry.Event{Source: "okta", Stage: "sync-app-group-assignments", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
-	if err := registry.FinalizeOktaRun(ctx, q, pool, runID, i.sourceName, time.Since(started), false); err != nil {
+	holds := registry.NewExpiryHolds()
+	secrets := i.collectOktaClientSecrets(ctx, warnings, holds, apps)
+	assetRows, credentialRows := buildOktaAppAssetAndCredentialRows(apps, counts, secrets)
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		if err := i.upsertOktaAppAssets(ctx, qtx, report, runID, assetRows); err != nil {
+			return fmt.Errorf("upsert okta app assets: %w", err)
+		}
+		if err := i.upsertOktaCredentialArtifacts(ctx, qtx, report, runID, credentialRows); err != nil {
+			return fmt.Errorf("upsert okta credentials: %w", err)
+		}
+		return nil
+	}); err != nil {
+		report(registry.Event{Source: "okta", Stage: "write-app-assets", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
+	}
+
+	if err := registry.FinalizeOktaRun(ctx, q, pool, runID, i.sourceName, time.Since(started), false, holds); err != nil {
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
@@ -136,14 +176,14 @@ func (i *OktaIntegration) runFull(ctx context.Context, q *gen.Queries, pool *pgx
 
 func (i *OktaIntegration) runDiscovery(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, report func(registry.Event)) error {
 	started := time.Now()
-	runID, err := q.CreateSyncRun(ctx, gen.CreateSyncRunParams{
+	runID, err := registry.CreateSyncRun(ctx, q, gen.CreateSyncRunParams{
 		SourceKind: registry.SyncRunSourceKind("okta", registry.RunModeDiscovery),
 		SourceName: i.sourceName,
 	})
 	if err != nil {
 		return err
 	}
-	if err := i.syncDiscovery(ctx, q, report, runID); err != nil {
+	if err := i.syncDiscovery(ctx, q, pool, report, runID); err != nil {
 		report(registry.Event{Source: "okta", Stage: "write-discovery", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindUnknown)
 	}
@@ -225,7 +265,7 @@ func (i *OktaIntegration) syncOktaIdpUsers(ctx context.Context, q *gen.Queries,
 			displayNames = append(displayNames, user.DisplayName)
 			accountKinds = append(accountKinds, oktaUserAccountKind(user))
 			statuses = append(statuses, user.Status)
-			rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeJSON(user.RawJSON), registry.EntityCategoryUser))
+			rawJSONs = append(rawJSONs, registry.WithEntityCategory(registry.NormalizeRawJSON(user.RawJSON), registry.EntityCategoryUser))
 			lastLoginAts = append(lastLoginAts, registry.PgTimestamptzPtr(user.LastLoginAt))
 			lastLoginIPs = append(lastLoginIPs, "")
 			lastLoginRegions = append(lastLoginRegions, "")
@@ -298,7 +338,7 @@ func (i *OktaIntegration) syncOktaGroups(ctx context.Context, q *gen.Queries, re
 			externalIDs = append(externalIDs, id)
 			names = append(names, group.Name)
 			types = append(types, group.Type)
-			rawJSONs = append(rawJSONs, registry.NormalizeJSON(group.RawJSON))
+			rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(group.RawJSON))
 
 			groupExternalID := oktaGroupExternalID(id)
 			if groupExternalID == "" {
@@ -312,7 +352,7 @@ func (i *OktaIntegration) syncOktaGroups(ctx context.Context, q *gen.Queries, re
 			accountEmails = append(accountEmails, "")
 			accountDisplayNames = append(accountDisplayNames, display)
 			accountKinds = append(accountKinds, registry.AccountKindService)
-			accountRawJSONs = append(accountRawJSONs, registry.WithEntityCategory(registry.NormalizeJSON(group.RawJSON), registry.EntityCategoryGroup))
+			accountRawJSONs = append(accountRawJSONs, registry.WithEntityCategory(registry.NormalizeRawJSON(group.RawJSON), registry.EntityCategoryGroup))
 			accountLastLoginAts = append(accountLastLoginAts, pgtype.Timestamptz{})
 			accountLastLoginIPs = append(accountLastLoginIPs, "")
 			accountLastLoginRegions = append(accountLastLoginRegions, "")
@@ -432,7 +472,7 @@ func (i *OktaIntegration) syncOktaGroups(ctx context.Context, q *gen.Queries, re
 	return firstErr
 }
 
-func (i *OktaIntegration) syncOktaAppAssignments(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64) ([]string, error) {
+func (i *OktaIntegration) syncOktaAppAssignments(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, counts *oktaAppAssignmentCounts) ([]App, error) {
 	apps, err := i.client.ListApps(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("okta list apps: %w", err)
@@ -446,9 +486,8 @@ func (i *OktaIntegration) syncOktaAppAssignments(ctx context.Context, q *gen.Que
 	}
 	report(registry.Event{Source: "okta", Stage: "sync-app-assignments", Current: 0, Total: int64(len(validApps)), Message: fmt.Sprintf("syncing %d apps", len(validApps))})
 
-	appExternalIDs := make([]string, 0, len(validApps))
 	if len(validApps) == 0 {
-		return appExternalIDs, nil
+		return validApps, nil
 	}
 
 	const batchSize = 500
@@ -468,13 +507,12 @@ func (i *OktaIntegration) syncOktaAppAssignments(ctx context.Context, q *gen.Que
 			if id == "" {
 				continue
 			}
-			appExternalIDs = append(appExternalIDs, id)
 			externalIDs = append(externalIDs, id)
 			labels = append(labels, app.Label)
 			names = append(names, app.Name)
 			statuses = append(statuses, app.Status)
 			signOnModes = append(signOnModes, app.SignOnMode)
-			rawJSONs = append(rawJSONs, registry.NormalizeJSON(app.RawJSON))
+			rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(app.RawJSON))
 		}
 		if len(externalIDs) == 0 {
 			continue
@@ -520,6 +558,7 @@ func (i *OktaIntegration) syncOktaAppAssignments(ctx context.Context, q *gen.Que
 				})
 				return
 			}
+			counts.setUsers(app.ID, len(assignments))
 			if len(assignments) == 0 {
 				n := atomic.AddInt64(&done, 1)
 				report(registry.Event{
@@ -549,7 +588,7 @@ func (i *OktaIntegration) syncOktaAppAssignments(ctx context.Context, q *gen.Que
 					oktaAppExternalIDs = append(oktaAppExternalIDs, app.ID)
 					scopes = append(scopes, assignment.Scope)
 					profileJSONs = append(profileJSONs, registry.NormalizeJSON(assignment.ProfileJSON))
-					rawJSONs = append(rawJSONs, registry.NormalizeJSON(assignment.RawJSON))
+					rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(assignment.RawJSON))
 				}
 				if len(idpExternalIDs) == 0 {
 					continue
@@ -592,10 +631,10 @@ func (i *OktaIntegration) syncOktaAppAssignments(ctx context.Context, q *gen.Que
 	close(jobs)
 	wg.Wait()
 
-	return appExternalIDs, firstErr
+	return validApps, firstErr
 }
 
-func (i *OktaIntegration) syncOktaAppGroupAssignments(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, appExternalIDs []string) error {
+func (i *OktaIntegration) syncOktaAppGroupAssignments(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, appExternalIDs []string, counts *oktaAppAssignmentCounts) error {
 	if len(appExternalIDs) == 0 {
 		report(registry.Event{Source: "okta", Stage: "sync-app-group-assignments", Current: 0, Total: 0, Message: "no apps to sync"})
 		return nil
@@ -630,6 +669,7 @@ func (i *OktaIntegration) syncOktaAppGroupAssignments(ctx context.Context, q *ge
 				})
 				return
 			}
+			counts.setGroups(appExternalID, len(assignments))
 			if len(assignments) > 0 {
 				externalIDs := make([]string, 0, len(assignments))
 				names := make([]string, 0, len(assignments))
@@ -644,7 +684,7 @@ func (i *OktaIntegration) syncOktaAppGroupAssignments(ctx context.Context, q *ge
 					externalIDs = append(externalIDs, id)
 					names = append(names, group.Name)
 					types = append(types, group.Type)
-					groupRawJSONs = append(groupRawJSONs, registry.NormalizeJSON(group.RawJSON))
+					groupRawJSONs = append(groupRawJSONs, registry.NormalizeRawJSON(group.RawJSON))
 				}
 				if len(externalIDs) > 0 {
 					if _, err := q.UpsertOktaGroupsBulk(jobCtx, gen.UpsertOktaGroupsBulkParams{
@@ -679,7 +719,7 @@ func (i *OktaIntegration) syncOktaAppGroupAssignments(ctx context.Context, q *ge
 						groupExternalIDs = append(groupExternalIDs, groupID)
 						priorities = append(priorities, int32(assignment.Priority))
 						profileJSONs = append(profileJSONs, registry.NormalizeJSON(assignment.ProfileJSON))
-						rawJSONs = append(rawJSONs, registry.NormalizeJSON(assignment.RawJSON))
+						rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(assignment.RawJSON))
 					}
 					if len(oktaAppExternalIDs) == 0 {
 						continue
@@ -754,25 +794,21 @@ type normalizedDiscoveryEvent struct {
 	RawJSON          []byte
 }
 
-func (i *OktaIntegration) syncDiscovery(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64) error {
+func (i *OktaIntegration) syncDiscovery(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, report func(registry.Event), runID int64) error {
 	report(registry.Event{Source: "okta", Stage: "list-discovery-events", Current: 0, Total: 1, Message: "listing discovery events"})
 
 	now := time.Now().UTC()
-	since := now.Add(-7 * 24 * time.Hour)
-	latestObservedAt, err := q.GetLatestSaaSDiscoveryObservedAtBySource(ctx, gen.GetLatestSaaSDiscoveryObservedAtBySourceParams{
-		SourceKind: "okta",
-		SourceName: i.sourceName,
+	since, err := discovery.IngestSince(ctx, now, discovery.DefaultIngestLookback, 15*time.Minute, func(ctx context.Context) (time.Time, bool, error) {
+		latest, err := q.GetLatestSaaSDiscoveryObservedAtBySource(ctx, gen.GetLatestSaaSDiscoveryObservedAtBySourceParams{
+			SourceKind: "okta",
+			SourceName: i.sourceName,
+		})
+		return latest.Time, latest.Valid, err
 	})
 	if err != nil {
 		metrics.DiscoveryIngestFailuresTotal.WithLabelValues("okta", "idp_sso", "watermark_query_error").Inc()
 		return fmt.Errorf("query latest discovery watermark: %w", err)
 	}
-	if latestObservedAt.Valid {
-		candidate := latestObservedAt.Time.UTC().Add(-15 * time.Minute)
-		if candidate.After(since) {
-			since = candidate
-		}
-	}
 
 	events, err := i.client.ListSystemLogEventsSince(ctx, since)
 	if err != nil {
@@ -797,7 +833,9 @@ func (i *OktaIntegration) syncDiscovery(ctx context.Context, q *gen.Queries, rep
 		Message: fmt.Sprintf("normalized %d source rows and %d events", len(sources), len(normalizedEvents)),
 	})
 
-	if err := i.writeDiscoveryRows(ctx, q, report, runID, sources, normalizedEvents); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return i.writeDiscoveryRows(ctx, qtx, report, runID, sources, normalizedEvents)
+	}); err != nil {
 		metrics.DiscoveryIngestFailuresTotal.WithLabelValues("okta", "idp_sso", "db_error").Inc()
 		return err
 	}
@@ -841,17 +879,12 @@ func normalizeOktaDiscovery(events []SystemLogEvent, sourceName string, now time
 			SourceAppID:   sourceAppID,
 			SourceAppName: sourceAppName,
 			SourceDomain:  sourceAppDomain,
+			SourceVendorName: discovery.ResolveVendorName(discovery.VendorEvidence{
+				PublisherDomain: sourceAppDomain,
+				AppID:           sourceAppID,
+				AppName:         sourceAppName,
+			}),
 		})
-		if metadata.VendorName == "" {
-			metadata = discovery.BuildMetadata(discovery.CanonicalInput{
-				SourceKind:       "okta",
-				SourceName:       sourceName,
-				SourceAppID:      sourceAppID,
-				SourceAppName:    sourceAppName,
-				SourceDomain:     sourceAppDomain,
-				SourceVendorName: sourceAppName,
-			})
-		}
 
 		current := sourceByID[sourceAppID]
 		if current.SourceAppID == "" || observedAt.After(current.SeenAt) {
@@ -878,7 +911,7 @@ func normalizeOktaDiscovery(events []SystemLogEvent, sourceName string, now time
 			ActorDisplayName: strings.TrimSpace(event.ActorName),
 			ObservedAt:       observedAt,
 			Scopes:           discovery.NormalizeScopes(event.GrantedScopes),
-			RawJSON:          registry.NormalizeJSON(event.RawJSON),
+			RawJSON:          registry.NormalizeRawJSON(event.RawJSON),
 		})
 	}
 
@@ -909,6 +942,10 @@ func oktaDiscoverySignalKind(eventType string, hasApp bool) string {
 }
 
 func (i *OktaIntegration) writeDiscoveryRows(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, sources []normalizedDiscoverySource, events []normalizedDiscoveryEvent) error {
+	ingestedTotal := metrics.DiscoveryEventsIngestedTotal
+	if _, backfill := discovery.BackfillSince(ctx); backfill {
+		ingestedTotal = metrics.DiscoveryBackfillEventsIngestedTotal
+	}
 	total := len(sources) + len(events)
 	report(registry.Event{
 		Source:  "okta",
@@ -969,6 +1006,7 @@ func (i *OktaIntegration) writeDiscoveryRows(ctx context.Context, q *gen.Queries
 		displayNames := make([]string, 0, len(appMeta))
 		primaryDomains := make([]string, 0, len(appMeta))
 		vendorNames := make([]string, 0, len(appMeta))
+		categories := make([]string, 0, len(appMeta))
 		firstSeenAts := make([]pgtype.Timestamptz, 0, len(appMeta))
 		lastSeenAts := make([]pgtype.Timestamptz, 0, len(appMeta))
 
@@ -977,6 +1015,7 @@ func (i *OktaIntegration) writeDiscoveryRows(ctx context.Context, q *gen.Queries
 			displayNames = append(displayNames, meta.DisplayName)
 			primaryDomains = append(primaryDomains, meta.Domain)
 			vendorNames = append(vendorNames, meta.VendorName)
+			categories = append(categories, meta.Category)
 			firstSeenAt := firstSeenByKey[key]
 			lastSeenAt := lastSeenByKey[key]
 			firstSeenAts = append(firstSeenAts, registry.PgTimestamptzPtr(&firstSeenAt))
@@ -988,6 +1027,7 @@ func (i *OktaIntegration) writeDiscoveryRows(ctx context.Context, q *gen.Queries
 			DisplayNames:   displayNames,
 			PrimaryDomains: primaryDomains,
 			VendorNames:    vendorNames,
+			Categories:     categories,
 			FirstSeenAts:   firstSeenAts,
 			LastSeenAts:    lastSeenAts,
 		}); err != nil {
@@ -1057,10 +1097,10 @@ func (i *OktaIntegration) writeDiscoveryRows(ctx context.Context, q *gen.Queries
 			actorDisplayNames = append(actorDisplayNames, event.ActorDisplayName)
 			observedAts = append(observedAts, registry.PgTimestamptzPtr(&event.ObservedAt))
 			scopesJSONs = append(scopesJSONs, discovery.ScopesJSON(event.Scopes))
-			rawJSONs = append(rawJSONs, registry.NormalizeJSON(event.RawJSON))
+			rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(event.RawJSON))
 			ingestedBySignal[event.SignalKind]++
 		}
-		if _, err := q.UpsertSaaSAppEventsBulkBySource(ctx, gen.UpsertSaaSAppEventsBulkBySourceParams{
+		if _, err := registry.UpsertDiscoveryEvents(ctx, q, gen.UpsertSaaSAppEventsBulkBySourceParams{
 			SourceKind:        "okta",
 			SourceName:        i.sourceName,
 			SeenInRunID:       runID,
@@ -1080,7 +1120,7 @@ func (i *OktaIntegration) writeDiscoveryRows(ctx context.Context, q *gen.Queries
 			return fmt.Errorf("upsert saas app events: %w", err)
 		}
 		for signalKind, count := range ingestedBySignal {
-			metrics.DiscoveryEventsIngestedTotal.WithLabelValues("okta", signalKind).Add(float64(count))
+			ingestedTotal.WithLabelValues("okta", signalKind).Add(float64(count))
 		}
 		written += len(events)
 		report(registry.Event{
@@ -1105,6 +1145,9 @@ func (i *OktaIntegration) writeDiscoveryRows(ctx context.Context, q *gen.Queries
 }
 
 func (i *OktaIntegration) seedOktaAutoBindings(ctx context.Context, q *gen.Queries) error {
+	if registry.CurrentAutoBindPolicy().Disabled {
+		return nil
+	}
 	rows, err := q.ListMappedOktaDiscoveryAppsBySource(ctx, i.sourceName)
 	if err != nil {
 		return fmt.Errorf("list okta discovery auto-bind candidates: %w", err)
@@ -1144,24 +1187,15 @@ func (i *OktaIntegration) seedOktaAutoBindings(ctx context.Context, q *gen.Queri
 		if connectorSource == "" {
 			continue
 		}
-		if err := q.UpsertSaaSAppBinding(ctx, gen.UpsertSaaSAppBindingParams{
-			SaasAppID:           row.SaasAppID,
-			ConnectorKind:       connectorKind,
-			ConnectorSourceName: connectorSource,
-			BindingSource:       "auto",
-			Confidence:          0.8,
-			IsPrimary:           false,
-			CreatedByAuthUserID: pgtype.Int8{},
-		}); err != nil {
+		// The Okta app is explicitly mapped to this integration, so it counts as an id match.
+		if err := q.UpsertSaaSAppBinding(ctx, registry.AutoBindingParams(row.SaasAppID, connectorKind, connectorSource, registry.AutoBindMatchClientID)); err != nil {
 			return fmt.Errorf("upsert okta auto binding for app %d: %w", row.SaasAppID, err)
 		}
 		boundCount++
 	}
 
 	if boundCount > 0 {
-		if _, err := q.RecomputePrimarySaaSAppBindingsForAll(ctx); err != nil {
-			return fmt.Errorf("recompute primary bindings: %w", err)
-		}
+		registry.RequestPrimaryBindingRecompute(q)
 	}
 	return nil
 }
@@ -0,0 +1,48 @@
+package okta
+
+import "strings"
+
+// Factor strengths stored with each enrolled factor, and the MFA levels of a user; the levels
+// are also the values of the ?mfa filter of the accounts list.
+const (
+	FactorStrengthStrong = "strong"
+	FactorStrengthWeak   = "weak"
+
+	MFALevelNone   = "none"
+	MFALevelWeak   = "weak"
+	MFALevelStrong = "strong"
+)
+
+// weakFactorTypes are factors delivered over phone, email, or a shared secret, which can be
+// intercepted or socially engineered. Every other factor type (Okta Verify, TOTP, hardware
+// tokens, WebAuthn, U2F, ...) counts as strong.
+var weakFactorTypes = map[string]struct{}{
+	"sms":      {},
+	"call":     {},
+	"email":    {},
+	"question": {},
+}
+
+// FactorStrength classifies an Okta factor type as strong or weak.
+func FactorStrength(factorType string) string {
+	if _, weak := weakFactorTypes[strings.ToLower(strings.TrimSpace(factorType))]; weak {
+		return FactorStrengthWeak
+	}
+	return FactorStrengthStrong
+}
+
+// UserMFALevel is the MFA level of a user with the given factors. Only active factors count;
+// a user whose active factors are all weak is MFALevelWeak.
+func UserMFALevel(factors []Factor) string {
+	level := MFALevelNone
+	for _, factor := range factors {
+		if !strings.EqualFold(strings.TrimSpace(factor.Status), "ACTIVE") {
+			continue
+		}
+		if FactorStrength(factor.FactorType) == FactorStrengthStrong {
+			return MFALevelStrong
+		}
+		level = MFALevelWeak
+	}
+	return level
+}
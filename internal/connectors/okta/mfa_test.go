@@ -0,0 +1,40 @@
+package okta
+
+import "testing"
+
+func TestOktaUserMFALevel(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		factors []Factor
+		want    string
+	}{
+		{name: "sms only", factors: []Factor{{FactorType: "sms", Status: "ACTIVE"}}, want: MFALevelWeak},
+		{name: "webauthn", factors: []Factor{{FactorType: "webauthn", Status: "ACTIVE"}}, want: MFALevelStrong},
+		{name: "sms and webauthn", factors: []Factor{{FactorType: "sms", Status: "ACTIVE"}, {FactorType: "webauthn", Status: "ACTIVE"}}, want: MFALevelStrong},
+		{name: "weak factors only", factors: []Factor{{FactorType: "call", Status: "ACTIVE"}, {FactorType: "question", Status: "ACTIVE"}}, want: MFALevelWeak},
+		{name: "pending webauthn with active sms", factors: []Factor{{FactorType: "sms", Status: "ACTIVE"}, {FactorType: "webauthn", Status: "PENDING_ACTIVATION"}}, want: MFALevelWeak},
+		{name: "no factors", want: MFALevelNone},
+	}
+	for _, tc := range cases {
+		if got := UserMFALevel(tc.factors); got != tc.want {
+			t.Fatalf("%s: UserMFALevel() = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestMapOktaFactorPayload(t *testing.T) {
+	t.Parallel()
+
+	factor, err := mapOktaFactorPayload([]byte(`{"id":"fwf1","factorType":"webauthn","provider":"FIDO","vendorName":"FIDO","status":"ACTIVE","profile":{"authenticatorName":"YubiKey"}}`))
+	if err != nil {
+		t.Fatalf("mapOktaFactorPayload() error = %v", err)
+	}
+	if factor.ID != "fwf1" || factor.FactorType != "webauthn" || factor.Provider != "FIDO" || factor.Status != "ACTIVE" {
+		t.Fatalf("factor = %+v, want the webauthn factor fwf1", factor)
+	}
+	if FactorStrength(factor.FactorType) != FactorStrengthStrong || FactorStrength("SMS") != FactorStrengthWeak {
+		t.Fatalf("FactorStrength() misclassified webauthn or sms")
+	}
+}
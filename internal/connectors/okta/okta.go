@@ -9,8 +9,11 @@ import (
 	"time"
 
 	sdk "github.com/okta/okta-sdk-golang/v6/okta"
+	"github.com/open-sspm/open-sspm/internal/connectors/httpclient"
 )
 
+const defaultRequestTimeout = 120 * time.Second
+
 type Client struct {
 	BaseURL string
 	Token   string
@@ -48,6 +51,17 @@ type App struct {
 	RawJSON    []byte
 }
 
+// ClientSecret is an OAuth 2.0 client secret of an OIDC app. The secret value itself is never
+// kept; RawJSON has it removed.
+type ClientSecret struct {
+	ID             string
+	Status         string
+	SecretHash     string
+	CreatedRaw     string
+	LastUpdatedRaw string
+	RawJSON        []byte
+}
+
 type UserAppAssignment struct {
 	App         App
 	Scope       string
@@ -70,6 +84,16 @@ type AppGroupAssignment struct {
 	RawJSON     []byte
 }
 
+// Factor is an MFA factor a user has enrolled.
+type Factor struct {
+	ID         string
+	FactorType string
+	Provider   string
+	VendorName string
+	Status     string
+	RawJSON    []byte
+}
+
 type SystemLogEvent struct {
 	ID            string
 	EventType     string
@@ -97,13 +121,15 @@ func New(baseURL, token string) (*Client, error) {
 		return nil, errors.New("okta token is required")
 	}
 
+	requestTimeout := httpclient.RequestTimeout(defaultRequestTimeout)
 	cfg, err := sdk.NewConfiguration(
 		sdk.WithOrgUrl(base),
 		sdk.WithToken(token),
 		sdk.WithCache(false),
-		sdk.WithRequestTimeout(120),
-		sdk.WithRateLimitMaxBackOff(30),
+		sdk.WithRequestTimeout(int64(requestTimeout/time.Second)),
+		sdk.WithRateLimitMaxBackOff(max(1, int64(httpclient.MaxBackoff("okta")/time.Second))),
 		sdk.WithRateLimitMaxRetries(4),
+		sdk.WithHttpClientPtr(httpclient.NewForKind("okta", requestTimeout)),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("okta sdk config: %w", err)
@@ -356,6 +382,32 @@ func (c *Client) ListApps(ctx context.Context) ([]App, error) {
 	return out, nil
 }
 
+// ListOAuth2ClientSecrets lists the client secrets of an OIDC app.
+func (c *Client) ListOAuth2ClientSecrets(ctx context.Context, appID string) ([]ClientSecret, error) {
+	if err := c.ensureClient(); err != nil {
+		return nil, err
+	}
+
+	appID = strings.TrimSpace(appID)
+	if appID == "" {
+		return nil, errors.New("okta app id is required")
+	}
+
+	secrets, resp, err := c.api.ApplicationSSOPublicKeysAPI.ListOAuth2ClientSecrets(ctx, appID).Execute()
+	if err != nil {
+		return nil, formatOktaError(err, resp)
+	}
+	out := make([]ClientSecret, 0, len(secrets))
+	for _, secret := range secrets {
+		mapped, err := mapOktaClientSecret(secret)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, mapped)
+	}
+	return out, nil
+}
+
 func (c *Client) ListApplicationUsers(ctx context.Context, appID string) ([]AppUserAssignment, error) {
 	if err := c.ensureClient(); err != nil {
 		return nil, err
@@ -424,6 +476,36 @@ func (c *Client) ListUserGroups(ctx context.Context, userID string) ([]Group, er
 	return out, nil
 }
 
+// ListUserFactors lists the MFA factors a user has enrolled, in any status.
+func (c *Client) ListUserFactors(ctx context.Context, userID string) ([]Factor, error) {
+	if err := c.ensureClient(); err != nil {
+		return nil, err
+	}
+
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, errors.New("okta user id is required")
+	}
+
+	factors, resp, err := c.api.UserFactorAPI.ListFactors(ctx, userID).Execute()
+	if err != nil {
+		return listUserFactorsFromRaw(resp, err)
+	}
+	out := make([]Factor, 0, len(factors))
+	for _, factor := range factors {
+		raw, err := json.Marshal(factor)
+		if err != nil {
+			return nil, err
+		}
+		mapped, err := mapOktaFactorPayload(raw)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, mapped)
+	}
+	return out, nil
+}
+
 func (c *Client) ListAppsAssignedToUser(ctx context.Context, userID string) ([]UserAppAssignment, error) {
 	if err := c.ensureClient(); err != nil {
 		return nil, err
@@ -704,6 +786,22 @@ func mapOktaAppPayload(raw []byte) (App, error) {
 	}, nil
 }
 
+func mapOktaClientSecret(secret sdk.OAuth2ClientSecret) (ClientSecret, error) {
+	secret.ClientSecret = nil
+	raw, err := json.Marshal(secret)
+	if err != nil {
+		return ClientSecret{}, err
+	}
+	return ClientSecret{
+		ID:             strings.TrimSpace(secret.GetId()),
+		Status:         strings.TrimSpace(secret.GetStatus()),
+		SecretHash:     strings.TrimSpace(secret.GetSecretHash()),
+		CreatedRaw:     strings.TrimSpace(secret.GetCreated()),
+		LastUpdatedRaw: strings.TrimSpace(secret.GetLastUpdated()),
+		RawJSON:        raw,
+	}, nil
+}
+
 func mapOktaAppUserAssignment(appUser sdk.AppUser) (AppUserAssignment, error) {
 	raw, err := json.Marshal(appUser)
 	if err != nil {
@@ -751,6 +849,21 @@ func mapOktaUserAssignmentPayload(raw []byte) (UserAppAssignment, error) {
 	}, nil
 }
 
+func mapOktaFactorPayload(raw []byte) (Factor, error) {
+	var payload map[string]any
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return Factor{}, err
+	}
+	return Factor{
+		ID:         getStringValue(payload["id"]),
+		FactorType: getStringValue(payload["factorType"]),
+		Provider:   getStringValue(payload["provider"]),
+		VendorName: getStringValue(payload["vendorName"]),
+		Status:     getStringValue(payload["status"]),
+		RawJSON:    raw,
+	}, nil
+}
+
 func mapOktaAppGroupAssignment(appID string, assignment sdk.ApplicationGroupAssignment) (AppGroupAssignment, error) {
 	raw, err := json.Marshal(assignment)
 	if err != nil {
@@ -943,6 +1056,36 @@ func listAppsFromRaw(resp *sdk.APIResponse, err error) ([]App, error) {
 	return out, nil
 }
 
+// listUserFactorsFromRaw maps a factor list the SDK could not decode, e.g. one holding a factor
+// type the SDK does not model, from the response body.
+func listUserFactorsFromRaw(resp *sdk.APIResponse, err error) ([]Factor, error) {
+	var apiErr *sdk.GenericOpenAPIError
+	if !errors.As(err, &apiErr) {
+		return nil, formatOktaError(err, resp)
+	}
+	if resp == nil || resp.Response == nil {
+		return nil, formatOktaError(err, resp)
+	}
+	statusCode := resp.Response.StatusCode
+	if statusCode < 200 || statusCode >= 300 {
+		return nil, formatOktaError(err, resp)
+	}
+
+	rawItems, parseErr := parseListApplicationsRaw(apiErr.Body())
+	if parseErr != nil {
+		return nil, formatOktaError(err, resp)
+	}
+	out := make([]Factor, 0, len(rawItems))
+	for _, raw := range rawItems {
+		factor, err := mapOktaFactorPayload(raw)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, factor)
+	}
+	return out, nil
+}
+
 func mapOktaAppsFromRaw(rawItems []json.RawMessage) ([]App, error) {
 	if len(rawItems) == 0 {
 		return nil, nil
@@ -0,0 +1,134 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+const oktaUserFactorBatchSize = 1000
+
+// collectOktaUserFactors lists the enrolled MFA factors of every user, keyed by user ID. Users
+// whose factors cannot be listed are left out, so their stored factors are kept, and are
+// summarized in one sync warning.
+func (i *OktaIntegration) collectOktaUserFactors(ctx context.Context, warnings *registry.WarningReporter, users []User) map[string][]Factor {
+	var userIDs []string
+	for _, user := range users {
+		if id := strings.TrimSpace(user.ID); id != "" {
+			userIDs = append(userIDs, id)
+		}
+	}
+	warnings.Report(registry.Event{Source: "okta", Stage: "list-user-factors", Current: 0, Total: int64(len(userIDs)), Message: fmt.Sprintf("listing MFA factors for %d users", len(userIDs))})
+
+	out := make(map[string][]Factor, len(userIDs))
+	if len(userIDs) == 0 {
+		return out
+	}
+
+	workers := min(len(userIDs), i.workers)
+	if workers < 1 {
+		workers = 1
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var failed int
+	var firstErr error
+	jobs := make(chan string, len(userIDs))
+	worker := func() {
+		defer wg.Done()
+		for userID := range jobs {
+			if ctx.Err() != nil {
+				return
+			}
+			factors, err := i.client.ListUserFactors(ctx, userID)
+			mu.Lock()
+			if err != nil {
+				failed++
+				if firstErr == nil {
+					firstErr = fmt.Errorf("user %s: %w", userID, err)
+				}
+			} else {
+				out[userID] = factors
+			}
+			mu.Unlock()
+		}
+	}
+
+	for j := 0; j < workers; j++ {
+		wg.Add(1)
+		go worker()
+	}
+	for _, userID := range userIDs {
+		jobs <- userID
+	}
+	close(jobs)
+	wg.Wait()
+
+	if failed > 0 {
+		warnings.ReportWarning(registry.Event{Source: "okta", Stage: "list-user-factors", Message: fmt.Sprintf("skipped MFA factors of %d users (first error: %v)", failed, firstErr)})
+	}
+
+	levels := map[string]int{}
+	for _, factors := range out {
+		levels[UserMFALevel(factors)]++
+	}
+	warnings.Report(registry.Event{
+		Source:  "okta",
+		Stage:   "list-user-factors",
+		Current: int64(len(userIDs)),
+		Total:   int64(len(userIDs)),
+		Message: fmt.Sprintf("listed MFA factors for %d users (%d without MFA, %d weak MFA only)", len(out), levels[MFALevelNone], levels[MFALevelWeak]),
+	})
+	return out
+}
+
+// replaceOktaUserFactors replaces the stored factors of every user in factorsByUser.
+func (i *OktaIntegration) replaceOktaUserFactors(ctx context.Context, q *gen.Queries, factorsByUser map[string][]Factor) error {
+	userIDs := make([]string, 0, len(factorsByUser))
+	for userID := range factorsByUser {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	for start := 0; start < len(userIDs); start += oktaUserFactorBatchSize {
+		end := min(start+oktaUserFactorBatchSize, len(userIDs))
+		batch := userIDs[start:end]
+		if _, err := q.DeleteOktaUserFactorsBySourceAndUserExternalIDs(ctx, gen.DeleteOktaUserFactorsBySourceAndUserExternalIDsParams{
+			SourceName:      i.sourceName,
+			UserExternalIds: batch,
+		}); err != nil {
+			return fmt.Errorf("delete okta user factors: %w", err)
+		}
+
+		params := gen.InsertOktaUserFactorsBulkParams{SourceName: i.sourceName}
+		for _, userID := range batch {
+			for _, factor := range factorsByUser[userID] {
+				id := strings.TrimSpace(factor.ID)
+				if id == "" {
+					continue
+				}
+				params.UserExternalIds = append(params.UserExternalIds, userID)
+				params.ExternalIds = append(params.ExternalIds, id)
+				params.FactorTypes = append(params.FactorTypes, factor.FactorType)
+				params.Providers = append(params.Providers, factor.Provider)
+				params.VendorNames = append(params.VendorNames, factor.VendorName)
+				params.Statuses = append(params.Statuses, factor.Status)
+				params.Strengths = append(params.Strengths, FactorStrength(factor.FactorType))
+				params.RawJsons = append(params.RawJsons, registry.NormalizeRawJSON(factor.RawJSON))
+			}
+		}
+		if len(params.ExternalIds) == 0 {
+			continue
+		}
+		if _, err := q.InsertOktaUserFactorsBulk(ctx, params); err != nil {
+			return fmt.Errorf("insert okta user factors: %w", err)
+		}
+	}
+	return nil
+}
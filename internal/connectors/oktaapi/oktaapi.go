@@ -43,6 +43,14 @@ func (e *APIError) Error() string {
 	return "okta api error"
 }
 
+// HTTPStatusCode reports the response status for sync error classification.
+func (e *APIError) HTTPStatusCode() int {
+	if e == nil {
+		return 0
+	}
+	return e.StatusCode
+}
+
 func (e *APIError) Unwrap() error {
 	return ErrAPI
 }
@@ -0,0 +1,26 @@
+package registry
+
+import "github.com/open-sspm/open-sspm/internal/db/gen"
+
+// AccountSecondaryEmailsParams flattens each account's secondary emails (emails[i] belongs to
+// externalIDs[i]) into the pairs ReplaceAccountSecondaryEmailsBySource stores. Every account in
+// externalIDs is replaced, so one that lost all its aliases has them cleared.
+func AccountSecondaryEmailsParams(sourceKind, sourceName string, externalIDs []string, emails [][]string) gen.ReplaceAccountSecondaryEmailsBySourceParams {
+	params := gen.ReplaceAccountSecondaryEmailsBySourceParams{
+		SourceKind:         sourceKind,
+		SourceName:         sourceName,
+		AccountExternalIds: externalIDs,
+		ExternalIds:        []string{},
+		Emails:             []string{},
+	}
+	for idx, externalID := range externalIDs {
+		if idx >= len(emails) {
+			break
+		}
+		for _, email := range emails[idx] {
+			params.ExternalIds = append(params.ExternalIds, externalID)
+			params.Emails = append(params.Emails, email)
+		}
+	}
+	return params
+}
@@ -0,0 +1,25 @@
+package registry
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAccountSecondaryEmailsParamsFlattensPairs(t *testing.T) {
+	t.Parallel()
+
+	params := AccountSecondaryEmailsParams("entra", "tenant", []string{"u1", "u2", "u3"}, [][]string{
+		{"a@example.com", "alias@example.net"},
+		nil,
+		{"c@example.com"},
+	})
+	if !slices.Equal(params.AccountExternalIds, []string{"u1", "u2", "u3"}) {
+		t.Fatalf("AccountExternalIds = %v, want every account replaced", params.AccountExternalIds)
+	}
+	if !slices.Equal(params.ExternalIds, []string{"u1", "u1", "u3"}) || !slices.Equal(params.Emails, []string{"a@example.com", "alias@example.net", "c@example.com"}) {
+		t.Fatalf("pairs = %v / %v, want one pair per secondary email", params.ExternalIds, params.Emails)
+	}
+	if params.SourceKind != "entra" || params.SourceName != "tenant" {
+		t.Fatalf("source = %s/%s, want entra/tenant", params.SourceKind, params.SourceName)
+	}
+}
@@ -0,0 +1,233 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// App asset fields a tag rule condition may compare.
+const (
+	AssetTagFieldAssetKind        = "asset_kind"
+	AssetTagFieldExternalID       = "external_id"
+	AssetTagFieldParentExternalID = "parent_external_id"
+	AssetTagFieldDisplayName      = "display_name"
+	AssetTagFieldStatus           = "status"
+)
+
+// Tag rule condition operators. Both compare case-insensitively.
+const (
+	AssetTagOpEquals   = "equals"
+	AssetTagOpContains = "contains"
+)
+
+var assetTagFields = []string{
+	AssetTagFieldAssetKind,
+	AssetTagFieldExternalID,
+	AssetTagFieldParentExternalID,
+	AssetTagFieldDisplayName,
+	AssetTagFieldStatus,
+}
+
+const (
+	maxAssetTagKeyLen   = 64
+	maxAssetTagValueLen = 256
+)
+
+var assetTagKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._:/-]*$`)
+
+// AssetTagCondition compares one app asset field to a literal. Conditions are limited to field
+// matches so rules stay cheap to evaluate for every asset on every sync.
+type AssetTagCondition struct {
+	Field string `json:"field"`
+	Op    string `json:"op"`
+	Value string `json:"value"`
+}
+
+// ParseAssetTagCondition parses "field=value" as an equals condition and "field~value" as a
+// contains condition.
+func ParseAssetTagCondition(raw string) (AssetTagCondition, error) {
+	raw = strings.TrimSpace(raw)
+	i := strings.IndexAny(raw, "=~")
+	if i < 0 {
+		return AssetTagCondition{}, fmt.Errorf("condition %q must be field=value or field~value", raw)
+	}
+	cond := AssetTagCondition{
+		Field: strings.ToLower(strings.TrimSpace(raw[:i])),
+		Op:    AssetTagOpEquals,
+		Value: strings.TrimSpace(raw[i+1:]),
+	}
+	if raw[i] == '~' {
+		cond.Op = AssetTagOpContains
+	}
+	return cond, cond.Validate()
+}
+
+// Validate reports whether the condition names a known field and operator.
+func (c AssetTagCondition) Validate() error {
+	if !slices.Contains(assetTagFields, c.Field) {
+		return fmt.Errorf("condition field %q must be one of %s", c.Field, strings.Join(assetTagFields, ", "))
+	}
+	switch c.Op {
+	case AssetTagOpEquals:
+	case AssetTagOpContains:
+		if c.Value == "" {
+			return fmt.Errorf("contains condition on %s needs a value", c.Field)
+		}
+	default:
+		return fmt.Errorf("condition operator %q must be %s or %s", c.Op, AssetTagOpEquals, AssetTagOpContains)
+	}
+	return nil
+}
+
+func (c AssetTagCondition) String() string {
+	if c.Op == AssetTagOpContains {
+		return c.Field + "~" + c.Value
+	}
+	return c.Field + "=" + c.Value
+}
+
+func (c AssetTagCondition) matches(asset gen.ListAppAssetTagTargetsBySourceRow) bool {
+	var field string
+	switch c.Field {
+	case AssetTagFieldAssetKind:
+		field = asset.AssetKind
+	case AssetTagFieldExternalID:
+		field = asset.ExternalID
+	case AssetTagFieldParentExternalID:
+		field = asset.ParentExternalID
+	case AssetTagFieldDisplayName:
+		field = asset.DisplayName
+	case AssetTagFieldStatus:
+		field = asset.Status
+	default:
+		return false
+	}
+	field = strings.TrimSpace(field)
+	if c.Op == AssetTagOpContains {
+		return strings.Contains(strings.ToLower(field), strings.ToLower(c.Value))
+	}
+	return strings.EqualFold(field, c.Value)
+}
+
+// AssetTagRule tags every app asset matching all of its conditions with TagKey=TagValue.
+type AssetTagRule struct {
+	ID         int64
+	Name       string
+	Conditions []AssetTagCondition
+	TagKey     string
+	TagValue   string
+}
+
+// Validate reports whether the rule can be stored: it needs a name, at least one valid
+// condition, and a tag key in the credential tag key format.
+func (r AssetTagRule) Validate() error {
+	switch {
+	case strings.TrimSpace(r.Name) == "":
+		return errors.New("rule name is required")
+	case len(r.Conditions) == 0:
+		return errors.New("rule needs at least one condition")
+	case r.TagKey == "":
+		return errors.New("tag key is required")
+	case len(r.TagKey) > maxAssetTagKeyLen:
+		return fmt.Errorf("tag key must be at most %d characters", maxAssetTagKeyLen)
+	case !assetTagKeyPattern.MatchString(r.TagKey):
+		return errors.New("tag key may contain lowercase letters, digits, and . _ : / -")
+	case len(r.TagValue) > maxAssetTagValueLen:
+		return fmt.Errorf("tag value must be at most %d characters", maxAssetTagValueLen)
+	}
+	for _, cond := range r.Conditions {
+		if err := cond.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Matches reports whether every condition of the rule matches the asset.
+func (r AssetTagRule) Matches(asset gen.ListAppAssetTagTargetsBySourceRow) bool {
+	if len(r.Conditions) == 0 {
+		return false
+	}
+	for _, cond := range r.Conditions {
+		if !cond.matches(asset) {
+			return false
+		}
+	}
+	return true
+}
+
+// AssetTagRuleFromRow decodes a stored rule.
+func AssetTagRuleFromRow(row gen.AppAssetTagRule) (AssetTagRule, error) {
+	rule := AssetTagRule{ID: row.ID, Name: row.Name, TagKey: row.TagKey, TagValue: row.TagValue}
+	if err := json.Unmarshal(row.Conditions, &rule.Conditions); err != nil {
+		return AssetTagRule{}, fmt.Errorf("decode conditions of tag rule %q: %w", row.Name, err)
+	}
+	return rule, rule.Validate()
+}
+
+// AssetTagsParams evaluates rules against a source's assets. When rules set the same tag key
+// on an asset, the first rule wins, so rules should be passed in creation order.
+func AssetTagsParams(sourceKind, sourceName string, rules []AssetTagRule, assets []gen.ListAppAssetTagTargetsBySourceRow) gen.ReplaceAppAssetTagsBySourceParams {
+	params := gen.ReplaceAppAssetTagsBySourceParams{
+		AssetKinds:  []string{},
+		ExternalIds: []string{},
+		TagKeys:     []string{},
+		TagValues:   []string{},
+		RuleIds:     []int64{},
+		SourceKind:  sourceKind,
+		SourceName:  sourceName,
+	}
+	for _, asset := range assets {
+		tagged := map[string]bool{}
+		for _, rule := range rules {
+			if tagged[rule.TagKey] || !rule.Matches(asset) {
+				continue
+			}
+			tagged[rule.TagKey] = true
+			params.AssetKinds = append(params.AssetKinds, asset.AssetKind)
+			params.ExternalIds = append(params.ExternalIds, asset.ExternalID)
+			params.TagKeys = append(params.TagKeys, rule.TagKey)
+			params.TagValues = append(params.TagValues, rule.TagValue)
+			params.RuleIds = append(params.RuleIds, rule.ID)
+		}
+	}
+	return params
+}
+
+// applyAppAssetTagRules re-evaluates the tag rules against the source's current assets and
+// replaces its rule-applied tags. Rules that no longer decode are logged and skipped.
+func applyAppAssetTagRules(ctx context.Context, qtx *gen.Queries, sourceKind, sourceName string) (int64, error) {
+	rows, err := qtx.ListAppAssetTagRules(ctx)
+	if err != nil {
+		return 0, err
+	}
+	rules := make([]AssetTagRule, 0, len(rows))
+	for _, row := range rows {
+		rule, err := AssetTagRuleFromRow(row)
+		if err != nil {
+			slog.Warn("skipping invalid app asset tag rule", "rule", row.Name, "err", err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	var assets []gen.ListAppAssetTagTargetsBySourceRow
+	if len(rules) > 0 {
+		assets, err = qtx.ListAppAssetTagTargetsBySource(ctx, gen.ListAppAssetTagTargetsBySourceParams{
+			SourceKind: sourceKind,
+			SourceName: sourceName,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return qtx.ReplaceAppAssetTagsBySource(ctx, AssetTagsParams(sourceKind, sourceName, rules, assets))
+}
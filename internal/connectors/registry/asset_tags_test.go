@@ -0,0 +1,97 @@
+package registry
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestParseAssetTagCondition(t *testing.T) {
+	t.Parallel()
+
+	cond, err := ParseAssetTagCondition(" Display_Name ~ bot ")
+	if err != nil {
+		t.Fatalf("ParseAssetTagCondition() error = %v", err)
+	}
+	if cond != (AssetTagCondition{Field: AssetTagFieldDisplayName, Op: AssetTagOpContains, Value: "bot"}) {
+		t.Fatalf("condition = %+v, want display_name contains bot", cond)
+	}
+	if cond, err := ParseAssetTagCondition("asset_kind=github_app_installation"); err != nil || cond.Op != AssetTagOpEquals || cond.String() != "asset_kind=github_app_installation" {
+		t.Fatalf("ParseAssetTagCondition(equals) = %+v, %v", cond, err)
+	}
+	for _, raw := range []string{"display_name", "raw_json~secret", "display_name~"} {
+		if _, err := ParseAssetTagCondition(raw); err == nil {
+			t.Fatalf("ParseAssetTagCondition(%q) succeeded, want error", raw)
+		}
+	}
+}
+
+func TestAssetTagsParamsTagsOnlyMatchingAssets(t *testing.T) {
+	t.Parallel()
+
+	rules := []AssetTagRule{
+		{
+			ID:   1,
+			Name: "bots",
+			Conditions: []AssetTagCondition{
+				{Field: AssetTagFieldAssetKind, Op: AssetTagOpEquals, Value: "github_app_installation"},
+				{Field: AssetTagFieldDisplayName, Op: AssetTagOpContains, Value: "bot"},
+			},
+			TagKey: "automation",
+		},
+		{
+			ID:         2,
+			Name:       "renovate category",
+			Conditions: []AssetTagCondition{{Field: AssetTagFieldDisplayName, Op: AssetTagOpContains, Value: "renovate"}},
+			TagKey:     "automation",
+			TagValue:   "dependency-updates",
+		},
+	}
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			t.Fatalf("rule %q Validate() error = %v", rule.Name, err)
+		}
+	}
+	assets := []gen.ListAppAssetTagTargetsBySourceRow{
+		{AssetKind: "github_app_installation", ExternalID: "11", DisplayName: "Renovate Bot"},
+		{AssetKind: "github_app_installation", ExternalID: "12", DisplayName: "Slack"},
+		{AssetKind: "github_deploy_key", ExternalID: "13", DisplayName: "ci-bot deploy key"},
+	}
+
+	params := AssetTagsParams("github", "acme", rules, assets)
+	if !slices.Equal(params.ExternalIds, []string{"11"}) {
+		t.Fatalf("tagged assets = %v, want only the bot installation", params.ExternalIds)
+	}
+	if params.TagKeys[0] != "automation" || params.TagValues[0] != "" || params.RuleIds[0] != 1 {
+		t.Fatalf("tag = %s=%q from rule %d, want automation from the first matching rule", params.TagKeys[0], params.TagValues[0], params.RuleIds[0])
+	}
+	if params.SourceKind != "github" || params.SourceName != "acme" {
+		t.Fatalf("source = %s/%s, want github/acme", params.SourceKind, params.SourceName)
+	}
+
+	empty := AssetTagsParams("github", "acme", nil, assets)
+	if empty.ExternalIds == nil || len(empty.ExternalIds) != 0 {
+		t.Fatalf("params without rules = %+v, want empty arrays that clear the source's tags", empty)
+	}
+}
+
+func TestAssetTagRuleFromRow(t *testing.T) {
+	t.Parallel()
+
+	rule, err := AssetTagRuleFromRow(gen.AppAssetTagRule{
+		ID:         7,
+		Name:       "bots",
+		Conditions: []byte(`[{"field":"display_name","op":"contains","value":"bot"}]`),
+		TagKey:     "automation",
+	})
+	if err != nil {
+		t.Fatalf("AssetTagRuleFromRow() error = %v", err)
+	}
+	if !rule.Matches(gen.ListAppAssetTagTargetsBySourceRow{DisplayName: "dependabot"}) {
+		t.Fatalf("rule %+v does not match dependabot", rule)
+	}
+	if _, err := AssetTagRuleFromRow(gen.AppAssetTagRule{Name: "broken", Conditions: []byte(`[{"field":"raw_json","op":"equals"}]`), TagKey: "x"}); err == nil {
+		t.Fatalf("AssetTagRuleFromRow() accepted a condition on an unknown field")
+	}
+}
@@ -0,0 +1,93 @@
+package registry
+
+import (
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// Binding sources recorded on SaaS app bindings.
+const (
+	BindingSourceAuto      = "auto"
+	BindingSourceManual    = "manual"
+	BindingSourceSuggested = "suggested"
+)
+
+// AutoBindMatch is how a discovery run matched a discovered app to a connector source.
+type AutoBindMatch string
+
+const (
+	// AutoBindMatchClientID is an exact app or OAuth client id match, or an explicit app mapping.
+	AutoBindMatchClientID AutoBindMatch = "client_id"
+	// AutoBindMatchName is a match on the app's display name alone.
+	AutoBindMatchName AutoBindMatch = "name"
+)
+
+// AutoBindPolicy scores auto-bind candidates by match strength. Candidates scoring below
+// MinConfidence are recorded as suggestions for an admin to confirm instead of being bound.
+type AutoBindPolicy struct {
+	ClientIDConfidence float32
+	NameConfidence     float32
+	MinConfidence      float32
+
+	// Disabled keeps discovery observational: runs create no auto or suggested bindings and
+	// leave binding entirely to admins.
+	Disabled bool
+}
+
+// DefaultAutoBindPolicy auto-binds id matches and suggests name-only matches.
+func DefaultAutoBindPolicy() AutoBindPolicy {
+	return AutoBindPolicy{
+		ClientIDConfidence: 0.95,
+		NameConfidence:     0.5,
+		MinConfidence:      0.7,
+	}
+}
+
+var autoBindPolicy atomic.Pointer[AutoBindPolicy]
+
+// SetAutoBindPolicy sets the policy every connector's discovery auto-binding uses.
+func SetAutoBindPolicy(p AutoBindPolicy) {
+	autoBindPolicy.Store(&p)
+}
+
+// CurrentAutoBindPolicy returns the policy set by SetAutoBindPolicy, or the default.
+func CurrentAutoBindPolicy() AutoBindPolicy {
+	if p := autoBindPolicy.Load(); p != nil {
+		return *p
+	}
+	return DefaultAutoBindPolicy()
+}
+
+// Confidence returns the confidence of a candidate matched by match.
+func (p AutoBindPolicy) Confidence(match AutoBindMatch) float32 {
+	if match == AutoBindMatchClientID {
+		return p.ClientIDConfidence
+	}
+	return p.NameConfidence
+}
+
+// BindingSource returns "auto" for a candidate confident enough to bind and "suggested" otherwise.
+func (p AutoBindPolicy) BindingSource(confidence float32) string {
+	if confidence < p.MinConfidence {
+		return BindingSourceSuggested
+	}
+	return BindingSourceAuto
+}
+
+// AutoBindingParams builds the binding upsert for a discovery auto-bind candidate, scored by
+// the current policy.
+func AutoBindingParams(saasAppID int64, connectorKind, connectorSourceName string, match AutoBindMatch) gen.UpsertSaaSAppBindingParams {
+	policy := CurrentAutoBindPolicy()
+	confidence := policy.Confidence(match)
+	return gen.UpsertSaaSAppBindingParams{
+		SaasAppID:           saasAppID,
+		ConnectorKind:       connectorKind,
+		ConnectorSourceName: connectorSourceName,
+		BindingSource:       policy.BindingSource(confidence),
+		Confidence:          confidence,
+		IsPrimary:           false,
+		CreatedByAuthUserID: pgtype.Int8{},
+	}
+}
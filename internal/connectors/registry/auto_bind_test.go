@@ -0,0 +1,45 @@
+package registry
+
+import "testing"
+
+func TestAutoBindPolicyScoresByMatchStrength(t *testing.T) {
+	t.Parallel()
+
+	policy := DefaultAutoBindPolicy()
+	for _, tc := range []struct {
+		match          AutoBindMatch
+		wantSource     string
+		wantConfidence float32
+	}{
+		{match: AutoBindMatchClientID, wantSource: BindingSourceAuto, wantConfidence: 0.95},
+		{match: AutoBindMatchName, wantSource: BindingSourceSuggested, wantConfidence: 0.5},
+	} {
+		confidence := policy.Confidence(tc.match)
+		if confidence != tc.wantConfidence {
+			t.Fatalf("Confidence(%s) = %v, want %v", tc.match, confidence, tc.wantConfidence)
+		}
+		if got := policy.BindingSource(confidence); got != tc.wantSource {
+			t.Fatalf("BindingSource(%v) for %s = %q, want %q", confidence, tc.match, got, tc.wantSource)
+		}
+	}
+
+	// Lowering the threshold lets name-only matches auto-bind.
+	policy.MinConfidence = 0.4
+	if got := policy.BindingSource(policy.Confidence(AutoBindMatchName)); got != BindingSourceAuto {
+		t.Fatalf("BindingSource() with min 0.4 = %q, want auto", got)
+	}
+}
+
+func TestAutoBindingParamsUsesCurrentPolicy(t *testing.T) {
+	params := AutoBindingParams(7, "entra", "tenant-1", AutoBindMatchName)
+	if params.BindingSource != BindingSourceSuggested || params.Confidence != 0.5 || params.IsPrimary {
+		t.Fatalf("AutoBindingParams(name) = %+v, want a non-primary suggestion at 0.5", params)
+	}
+	params = AutoBindingParams(7, "entra", "tenant-1", AutoBindMatchClientID)
+	if params.SaasAppID != 7 || params.ConnectorKind != "entra" || params.ConnectorSourceName != "tenant-1" {
+		t.Fatalf("AutoBindingParams(client_id) = %+v, want the candidate's app and source", params)
+	}
+	if params.BindingSource != BindingSourceAuto || params.Confidence != 0.95 {
+		t.Fatalf("AutoBindingParams(client_id) = %+v, want an auto binding at 0.95", params)
+	}
+}
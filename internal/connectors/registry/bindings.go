@@ -0,0 +1,92 @@
+package registry
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// primaryBindingRecomputeDelay is how long a requested recompute waits for other discovery runs
+// to ask for the same thing.
+const primaryBindingRecomputeDelay = 5 * time.Second
+
+// BindingRecomputer coalesces the all-rows primary SaaS app binding recompute that discovery
+// runs need after upserting auto bindings. Requests within one delay window share a single
+// recompute, run by a timer or by Flush, whichever comes first.
+type BindingRecomputer struct {
+	delay     time.Duration
+	recompute func(context.Context, *gen.Queries) error
+
+	mu      sync.Mutex
+	pending *gen.Queries
+	timer   *time.Timer
+}
+
+func NewBindingRecomputer(delay time.Duration) *BindingRecomputer {
+	return &BindingRecomputer{
+		delay: delay,
+		recompute: func(ctx context.Context, q *gen.Queries) error {
+			_, err := q.RecomputePrimarySaaSAppBindingsForAll(ctx)
+			return err
+		},
+	}
+}
+
+var defaultBindingRecomputer = NewBindingRecomputer(primaryBindingRecomputeDelay)
+
+// RequestPrimaryBindingRecompute schedules a primary binding recompute on the process-wide
+// recomputer. q must not be bound to a transaction: the recompute runs after the caller returns.
+func RequestPrimaryBindingRecompute(q *gen.Queries) {
+	defaultBindingRecomputer.Request(q)
+}
+
+// FlushPrimaryBindingRecompute runs a pending primary binding recompute now, if any.
+func FlushPrimaryBindingRecompute(ctx context.Context) error {
+	return defaultBindingRecomputer.Flush(ctx)
+}
+
+// Request marks a recompute as needed and starts the delay timer unless one is already running.
+func (r *BindingRecomputer) Request(q *gen.Queries) {
+	if q == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending = q
+	if r.timer == nil {
+		r.timer = time.AfterFunc(r.delay, func() {
+			if err := r.Flush(context.Background()); err != nil {
+				slog.Warn("primary saas app binding recompute failed", "err", err)
+			}
+		})
+	}
+}
+
+// Flush runs the pending recompute, if any. A failed recompute stays pending so the next
+// request or flush retries it.
+func (r *BindingRecomputer) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	q := r.pending
+	r.pending = nil
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	r.mu.Unlock()
+	if q == nil {
+		return nil
+	}
+
+	if err := r.recompute(ctx, q); err != nil {
+		r.mu.Lock()
+		if r.pending == nil {
+			r.pending = q
+		}
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func countingBindingRecomputer(delay time.Duration, calls *atomic.Int32, err error) *BindingRecomputer {
+	r := NewBindingRecomputer(delay)
+	r.recompute = func(context.Context, *gen.Queries) error {
+		calls.Add(1)
+		return err
+	}
+	return r
+}
+
+func TestBindingRecomputerCoalescesBackToBackRequests(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	r := countingBindingRecomputer(20*time.Millisecond, &calls, nil)
+	q := gen.New(&recordingDB{})
+
+	// Two discovery seedings finishing close together.
+	r.Request(q)
+	r.Request(q)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for calls.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("recompute ran %d times, want 1", got)
+	}
+}
+
+func TestBindingRecomputerFlushRunsPendingOnce(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	r := countingBindingRecomputer(time.Hour, &calls, nil)
+	q := gen.New(&recordingDB{})
+
+	if err := r.Flush(context.Background()); err != nil || calls.Load() != 0 {
+		t.Fatalf("Flush() with nothing pending = %v after %d recomputes, want a no-op", err, calls.Load())
+	}
+	r.Request(q)
+	r.Request(q)
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatalf("second Flush() error = %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("recompute ran %d times, want 1", got)
+	}
+}
+
+func TestBindingRecomputerKeepsFailedRecomputePending(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	failure := errors.New("deadlock detected")
+	r := countingBindingRecomputer(time.Hour, &calls, failure)
+	r.Request(gen.New(&recordingDB{}))
+
+	if err := r.Flush(context.Background()); !errors.Is(err, failure) {
+		t.Fatalf("Flush() error = %v, want %v", err, failure)
+	}
+	r.recompute = func(context.Context, *gen.Queries) error {
+		calls.Add(1)
+		return nil
+	}
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatalf("retry Flush() error = %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("recompute ran %d times, want the failure and one retry", got)
+	}
+}
@@ -0,0 +1,54 @@
+package registry
+
+import (
+	"sort"
+	"strings"
+)
+
+// CredentialKindInfo explains a credential kind to reviewers who do not know the provider.
+type CredentialKindInfo struct {
+	Kind             string `json:"kind"`
+	Label            string `json:"label"`
+	Description      string `json:"description"`
+	RotationGuidance string `json:"rotation_guidance"`
+}
+
+// CredentialKindDescriber is an optional interface that connector definitions implement to
+// document the credential kinds their integration syncs.
+type CredentialKindDescriber interface {
+	CredentialKinds() []CredentialKindInfo
+}
+
+// CredentialKindGlossary returns the credential kinds documented by the registered connectors,
+// sorted by kind. A kind documented twice keeps the entry of the first registered connector.
+func (r *ConnectorRegistry) CredentialKindGlossary() []CredentialKindInfo {
+	seen := map[string]bool{}
+	var out []CredentialKindInfo
+	for _, def := range r.All() {
+		describer, ok := def.(CredentialKindDescriber)
+		if !ok {
+			continue
+		}
+		for _, info := range describer.CredentialKinds() {
+			info.Kind = strings.ToLower(strings.TrimSpace(info.Kind))
+			if info.Kind == "" || seen[info.Kind] {
+				continue
+			}
+			seen[info.Kind] = true
+			out = append(out, info)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Kind < out[j].Kind })
+	return out
+}
+
+// CredentialKindInfo returns the glossary entry for kind.
+func (r *ConnectorRegistry) CredentialKindInfo(kind string) (CredentialKindInfo, bool) {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	for _, info := range r.CredentialKindGlossary() {
+		if info.Kind == kind {
+			return info, true
+		}
+	}
+	return CredentialKindInfo{}, false
+}
@@ -0,0 +1,79 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// transientDBRetryDelays are the waits before each retry of a write that failed on a transient
+// database error. Its length bounds the number of retries.
+var transientDBRetryDelays = []time.Duration{250 * time.Millisecond, time.Second}
+
+// IsTransientDBError reports whether err is a database failure worth retrying: a serialization
+// failure or deadlock, a server restart or connection limit, or a dropped connection. Constraint
+// violations, bad input, and canceled contexts are permanent.
+func IsTransientDBError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40001", // serialization_failure
+			"40P01", // deadlock_detected
+			"53300", // too_many_connections
+			"57P01", // admin_shutdown
+			"57P02", // crash_shutdown
+			"57P03": // cannot_connect_now
+			return true
+		}
+		// Class 08 is connection_exception.
+		return strings.HasPrefix(pgErr.Code, "08")
+	}
+
+	if pgconn.SafeToRetry(err) {
+		return true
+	}
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// retryTransientDB runs write, retrying it after a short wait while it fails with a transient
+// database error. write must be safe to repeat; the bulk upserts keyed by run id are.
+func retryTransientDB(ctx context.Context, write func() error) error {
+	err := write()
+	for attempt, delay := range transientDBRetryDelays {
+		if !IsTransientDBError(err) {
+			return err
+		}
+		slog.Warn("transient database error, retrying write", "attempt", attempt+1, "delay", delay, "err", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+		err = write()
+	}
+	return err
+}
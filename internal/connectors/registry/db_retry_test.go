@@ -0,0 +1,115 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// flakyDB fails its first failures Exec calls with err and succeeds afterwards.
+type flakyDB struct {
+	err       error
+	failures  int
+	execCalls int
+}
+
+func (f *flakyDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	f.execCalls++
+	if f.execCalls <= f.failures {
+		return pgconn.CommandTag{}, f.err
+	}
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (f *flakyDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	panic("unexpected Query call")
+}
+
+func (f *flakyDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	panic("unexpected QueryRow call")
+}
+
+func withoutRetryDelays(t *testing.T) {
+	t.Helper()
+	prev := transientDBRetryDelays
+	transientDBRetryDelays = []time.Duration{0, 0}
+	t.Cleanup(func() { transientDBRetryDelays = prev })
+}
+
+func upsertEntitlementsStage(qtx *gen.Queries) error {
+	_, err := qtx.UpsertEntitlementsBulkBySource(context.Background(), gen.UpsertEntitlementsBulkBySourceParams{
+		SeenInRunID:        7,
+		SourceKind:         "github",
+		SourceName:         "acme",
+		AppUserExternalIds: []string{"u-1"},
+	})
+	return err
+}
+
+func TestRunWriteStageRetriesTransientErrors(t *testing.T) {
+	withoutRetryDelays(t)
+	db := &flakyDB{err: &pgconn.PgError{Code: "40001", Message: "could not serialize access"}, failures: 2}
+
+	if err := RunWriteStage(context.Background(), gen.New(db), nil, upsertEntitlementsStage); err != nil {
+		t.Fatalf("RunWriteStage() error = %v, want success after two transient failures", err)
+	}
+	if db.execCalls != 3 {
+		t.Fatalf("exec calls = %d, want 3", db.execCalls)
+	}
+}
+
+func TestRunWriteStageGivesUpAfterBoundedRetries(t *testing.T) {
+	withoutRetryDelays(t)
+	db := &flakyDB{err: fmt.Errorf("upsert: %w", io.ErrUnexpectedEOF), failures: 10}
+
+	err := RunWriteStage(context.Background(), gen.New(db), nil, upsertEntitlementsStage)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("RunWriteStage() error = %v, want the transient error once retries run out", err)
+	}
+	if want := len(transientDBRetryDelays) + 1; db.execCalls != want {
+		t.Fatalf("exec calls = %d, want %d", db.execCalls, want)
+	}
+}
+
+func TestRunWriteStageDoesNotRetryConstraintViolations(t *testing.T) {
+	withoutRetryDelays(t)
+	db := &flakyDB{err: &pgconn.PgError{Code: "23505", Message: "duplicate key value"}, failures: 1}
+
+	if err := RunWriteStage(context.Background(), gen.New(db), nil, upsertEntitlementsStage); err == nil {
+		t.Fatalf("RunWriteStage() error = nil, want the constraint violation")
+	}
+	if db.execCalls != 1 {
+		t.Fatalf("exec calls = %d, want 1", db.execCalls)
+	}
+}
+
+func TestIsTransientDBError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, want: true},
+		{name: "deadlock", err: &pgconn.PgError{Code: "40P01"}, want: true},
+		{name: "connection failure", err: &pgconn.PgError{Code: "08006"}, want: true},
+		{name: "admin shutdown", err: &pgconn.PgError{Code: "57P01"}, want: true},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, want: false},
+		{name: "invalid input", err: &pgconn.PgError{Code: "22P02"}, want: false},
+		{name: "unexpected EOF", err: fmt.Errorf("read: %w", io.ErrUnexpectedEOF), want: true},
+		{name: "canceled", err: context.Canceled, want: false},
+		{name: "plain error", err: errors.New("boom"), want: false},
+	}
+	for _, tc := range cases {
+		if got := IsTransientDBError(tc.err); got != tc.want {
+			t.Errorf("%s: IsTransientDBError() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package registry
+
+import (
+	"context"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/metrics"
+)
+
+// DiscoveryEventUpserter is the query that writes discovery evidence. *gen.Queries implements it.
+type DiscoveryEventUpserter interface {
+	UpsertSaaSAppEventsBulkBySource(ctx context.Context, arg gen.UpsertSaaSAppEventsBulkBySourceParams) ([]gen.UpsertSaaSAppEventsBulkBySourceRow, error)
+}
+
+// UpsertDiscoveryEvents writes a batch of discovery evidence and counts, per signal kind, how many
+// rows were new and how many matched an event already stored. A re-ingested batch lands entirely
+// under "updated", so a jump in submitted events without new inserts points at repeated or
+// re-keyed evidence rather than new activity. It returns the number of new events.
+func UpsertDiscoveryEvents(ctx context.Context, q DiscoveryEventUpserter, arg gen.UpsertSaaSAppEventsBulkBySourceParams) (int64, error) {
+	rows, err := q.UpsertSaaSAppEventsBulkBySource(ctx, arg)
+	if err != nil {
+		return 0, err
+	}
+	var inserted int64
+	for _, row := range rows {
+		metrics.DiscoveryEventsUpsertedTotal.WithLabelValues(arg.SourceKind, row.SignalKind, "inserted").Add(float64(row.InsertedCount))
+		metrics.DiscoveryEventsUpsertedTotal.WithLabelValues(arg.SourceKind, row.SignalKind, "updated").Add(float64(row.UpdatedCount))
+		inserted += row.InsertedCount
+	}
+	return inserted, nil
+}
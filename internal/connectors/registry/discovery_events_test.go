@@ -0,0 +1,72 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// fakeEventStore mirrors the upsert's conflict key: an event is new the first time its
+// (signal kind, external id) pair is written and updated afterwards.
+type fakeEventStore struct {
+	seen map[string]struct{}
+}
+
+func (s *fakeEventStore) UpsertSaaSAppEventsBulkBySource(_ context.Context, arg gen.UpsertSaaSAppEventsBulkBySourceParams) ([]gen.UpsertSaaSAppEventsBulkBySourceRow, error) {
+	bySignal := map[string]*gen.UpsertSaaSAppEventsBulkBySourceRow{}
+	var out []gen.UpsertSaaSAppEventsBulkBySourceRow
+	for i, id := range arg.EventExternalIds {
+		signal := arg.SignalKinds[i]
+		row, ok := bySignal[signal]
+		if !ok {
+			out = append(out, gen.UpsertSaaSAppEventsBulkBySourceRow{SignalKind: signal})
+			row = &out[len(out)-1]
+			bySignal[signal] = row
+		}
+		key := signal + "|" + id
+		if _, ok := s.seen[key]; ok {
+			row.UpdatedCount++
+			continue
+		}
+		s.seen[key] = struct{}{}
+		row.InsertedCount++
+	}
+	return out, nil
+}
+
+func TestUpsertDiscoveryEventsReportsNoInsertsOnReplay(t *testing.T) {
+	store := &fakeEventStore{seen: map[string]struct{}{}}
+	batch := gen.UpsertSaaSAppEventsBulkBySourceParams{
+		SourceKind:       "idempotency-test",
+		SourceName:       "example.com",
+		SignalKinds:      []string{"oauth", "oauth"},
+		EventExternalIds: []string{"evt-1", "evt-2"},
+	}
+	inserted := metrics.DiscoveryEventsUpsertedTotal.WithLabelValues("idempotency-test", "oauth", "inserted")
+	updated := metrics.DiscoveryEventsUpsertedTotal.WithLabelValues("idempotency-test", "oauth", "updated")
+
+	n, err := UpsertDiscoveryEvents(context.Background(), store, batch)
+	if err != nil {
+		t.Fatalf("first upsert: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("first upsert inserted %d, want 2", n)
+	}
+
+	n, err = UpsertDiscoveryEvents(context.Background(), store, batch)
+	if err != nil {
+		t.Fatalf("replayed upsert: %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("replayed upsert inserted %d, want 0", n)
+	}
+	if got := testutil.ToFloat64(inserted); got != 2 {
+		t.Fatalf("inserted metric = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(updated); got != 2 {
+		t.Fatalf("updated metric = %v, want 2", got)
+	}
+}
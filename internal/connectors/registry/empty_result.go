@@ -0,0 +1,83 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// suspiciousEmptyCountKeys are the finalize counts of a source's primary inventory. A source
+// whose previous successful run observed some of these rows but whose current run observed
+// none has most likely lost API scopes or been misconfigured rather than emptied out.
+var suspiciousEmptyCountKeys = []string{
+	"idp_users_observed",
+	"app_users_observed",
+	"app_assets_observed",
+}
+
+// syncRunCounts decodes the counts from a sync run's stats. Stats that do not decode yield nil.
+func syncRunCounts(stats []byte) map[string]int64 {
+	var decoded struct {
+		Counts map[string]int64 `json:"counts"`
+	}
+	if len(stats) == 0 || json.Unmarshal(stats, &decoded) != nil {
+		return nil
+	}
+	return decoded.Counts
+}
+
+// SuspiciousEmptyResultWarnings returns a warning for each primary inventory count that was
+// non-zero in the prior successful run and is zero in the current one. A source without a
+// prior non-empty run, such as a newly added connector, never warns.
+func SuspiciousEmptyResultWarnings(sourceKind string, prior, current map[string]int64, at time.Time) []SyncWarning {
+	var warnings []SyncWarning
+	for _, key := range suspiciousEmptyCountKeys {
+		observed, ok := current[key]
+		if !ok || observed != 0 || prior[key] <= 0 {
+			continue
+		}
+		what := strings.ReplaceAll(strings.TrimSuffix(key, "_observed"), "_", " ")
+		warnings = append(warnings, SyncWarning{
+			Source:  sourceKind,
+			Stage:   "finalize",
+			Message: fmt.Sprintf("suspicious empty result: the previous successful sync observed %d %s but this sync observed none; check the connector's credentials and permissions", prior[key], what),
+			At:      at.UTC(),
+		})
+	}
+	return warnings
+}
+
+// flagSuspiciousEmptyResult compares the run's counts with the source's latest successful run
+// and appends a warning to the run for inventory that suddenly came back empty. It never
+// fails the run on its own.
+func flagSuspiciousEmptyResult(ctx context.Context, qtx *gen.Queries, runID int64, sourceKind, sourceName string, counts map[string]int64) error {
+	stats, err := qtx.GetLatestSuccessfulSyncRunStatsBySource(ctx, gen.GetLatestSuccessfulSyncRunStatsBySourceParams{
+		SourceKind: sourceKind,
+		SourceName: sourceName,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	warnings := SuspiciousEmptyResultWarnings(sourceKind, syncRunCounts(stats), counts, time.Now())
+	if len(warnings) == 0 {
+		return nil
+	}
+	for _, w := range warnings {
+		slog.Warn("sync returned an empty inventory", "source_kind", sourceKind, "source_name", sourceName, "run_id", runID, "message", w.Message)
+	}
+	return qtx.AppendSyncRunWarnings(ctx, gen.AppendSyncRunWarningsParams{
+		ID:       runID,
+		Warnings: MarshalJSON(warnings),
+	})
+}
@@ -0,0 +1,49 @@
+package registry
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSuspiciousEmptyResultWarningsFlagsPreviouslyPopulatedSource(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	prior := syncRunCounts([]byte(`{"counts":{"app_users_observed":120,"app_assets_observed":0,"entitlements_observed":300},"duration_ms":900}`))
+	current := map[string]int64{"app_users_observed": 0, "app_users_expired": 120, "app_assets_observed": 0, "entitlements_observed": 0}
+
+	warnings := SuspiciousEmptyResultWarnings("github", prior, current, at)
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %+v, want one for app users only", warnings)
+	}
+	w := warnings[0]
+	if w.Source != "github" || w.Stage != "finalize" || !w.At.Equal(at) {
+		t.Fatalf("warning = %+v, want github finalize warning at %s", w, at)
+	}
+	if !strings.Contains(w.Message, "suspicious empty result") || !strings.Contains(w.Message, "120 app users") {
+		t.Fatalf("message = %q, want the prior app user count", w.Message)
+	}
+}
+
+func TestSuspiciousEmptyResultWarningsIgnoresNewEmptySource(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	current := map[string]int64{"app_users_observed": 0, "app_assets_observed": 0}
+
+	if warnings := SuspiciousEmptyResultWarnings("vault", nil, current, at); len(warnings) != 0 {
+		t.Fatalf("warnings without a prior run = %+v, want none", warnings)
+	}
+	prior := syncRunCounts([]byte(`{"counts":{"app_users_observed":0,"app_assets_observed":0}}`))
+	if warnings := SuspiciousEmptyResultWarnings("vault", prior, current, at); len(warnings) != 0 {
+		t.Fatalf("warnings after an empty prior run = %+v, want none", warnings)
+	}
+	prior = map[string]int64{"app_users_observed": 5}
+	if warnings := SuspiciousEmptyResultWarnings("vault", prior, map[string]int64{"app_users_observed": 3}, at); len(warnings) != 0 {
+		t.Fatalf("warnings for a shrinking but non-empty source = %+v, want none", warnings)
+	}
+	if counts := syncRunCounts([]byte(`not json`)); counts != nil {
+		t.Fatalf("syncRunCounts(invalid) = %v, want nil", counts)
+	}
+}
@@ -0,0 +1,98 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	// SyncErrorKindAuth is a 401/403 from the source: bad or revoked credentials, or missing
+	// permissions.
+	SyncErrorKindAuth = "auth"
+	// SyncErrorKindRateLimit is a 429 from the source that outlasted the client's retries.
+	SyncErrorKindRateLimit = "rate_limit"
+)
+
+// HTTPStatusCoder is implemented by connector API errors that carry the response status, so
+// ClassifySyncError does not have to find it in the message.
+type HTTPStatusCoder interface {
+	HTTPStatusCode() int
+}
+
+var (
+	// httpStatusFieldPattern matches "status=403" and "status: 403" in formatted API errors.
+	httpStatusFieldPattern = regexp.MustCompile(`(?i)\bstatus[=:]\s*([1-5]\d{2})\b`)
+	// httpStatusLinePattern matches a response status line such as "403 Forbidden"; the reason
+	// phrase is checked against the code before it is trusted.
+	httpStatusLinePattern = regexp.MustCompile(`\b([1-5]\d{2}) ([A-Z][A-Za-z' -]+)`)
+)
+
+// ClassifySyncError derives a sync error kind from err: sync run timeouts, canceled contexts,
+// database errors, and HTTP failures by status (auth, rate limit, other API errors). Errors it
+// cannot place are SyncErrorKindUnknown.
+func ClassifySyncError(err error) string {
+	if err == nil {
+		return SyncErrorKindUnknown
+	}
+	if errors.As(err, new(*SyncRunTimeoutError)) {
+		return SyncErrorKindTimeout
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return SyncErrorKindContextCanceled
+	}
+
+	var pgErr *pgconn.PgError
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &pgErr) || errors.As(err, &connectErr) ||
+		errors.Is(err, pgx.ErrNoRows) || errors.Is(err, pgx.ErrTxClosed) || errors.Is(err, pgx.ErrTxCommitRollback) {
+		return SyncErrorKindDB
+	}
+
+	if status := httpStatusFromError(err); status > 0 {
+		switch {
+		case status == http.StatusUnauthorized || status == http.StatusForbidden:
+			return SyncErrorKindAuth
+		case status == http.StatusTooManyRequests:
+			return SyncErrorKindRateLimit
+		case status >= 400:
+			return SyncErrorKindAPI
+		}
+	}
+
+	// The HTTP client wraps transport failures (DNS, TLS, resets, timeouts) in *url.Error.
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return SyncErrorKindAPI
+	}
+	return SyncErrorKindUnknown
+}
+
+// httpStatusFromError returns the HTTP status carried by err, from an HTTPStatusCoder in its
+// chain or else from its message, or 0.
+func httpStatusFromError(err error) int {
+	var coder HTTPStatusCoder
+	if errors.As(err, &coder) {
+		return coder.HTTPStatusCode()
+	}
+
+	msg := err.Error()
+	if match := httpStatusFieldPattern.FindStringSubmatch(msg); match != nil {
+		status, _ := strconv.Atoi(match[1])
+		return status
+	}
+	for _, match := range httpStatusLinePattern.FindAllStringSubmatch(msg, -1) {
+		status, _ := strconv.Atoi(match[1])
+		if text := http.StatusText(status); text != "" && strings.HasPrefix(match[2], text) {
+			return status
+		}
+	}
+	return 0
+}
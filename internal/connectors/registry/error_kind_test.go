@@ -0,0 +1,78 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+type statusError struct{ status int }
+
+func (e *statusError) Error() string       { return "source api failed" }
+func (e *statusError) HTTPStatusCode() int { return e.status }
+
+func TestClassifySyncError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"canceled", fmt.Errorf("list users: %w", context.Canceled), SyncErrorKindContextCanceled},
+		{"deadline", context.DeadlineExceeded, SyncErrorKindContextCanceled},
+		{"sync run timeout", fmt.Errorf("%w: %w", &SyncRunTimeoutError{Timeout: time.Hour}, context.DeadlineExceeded), SyncErrorKindTimeout},
+		{"pg error", fmt.Errorf("upsert accounts: %w", &pgconn.PgError{Code: "23505"}), SyncErrorKindDB},
+		{"no rows", fmt.Errorf("load run: %w", pgx.ErrNoRows), SyncErrorKindDB},
+		{"typed 401", fmt.Errorf("list users: %w", &statusError{status: 401}), SyncErrorKindAuth},
+		{"typed 403", &statusError{status: 403}, SyncErrorKindAuth},
+		{"typed 429", &statusError{status: 429}, SyncErrorKindRateLimit},
+		{"typed 500", &statusError{status: 500}, SyncErrorKindAPI},
+		{"status line 403", errors.New("graph api failed: 403 Forbidden: Insufficient privileges (url=https://graph.microsoft.com/v1.0/users)"), SyncErrorKindAuth},
+		{"status line 429", errors.New("github api error: 429 Too Many Requests"), SyncErrorKindRateLimit},
+		{"status line 404", errors.New("datadog api failed: 404 Not Found"), SyncErrorKindAPI},
+		{"status field 401", errors.New("google oauth token exchange failed: status=401 body={}"), SyncErrorKindAuth},
+		{"status field 502", errors.New("google api temporary failure: status=502 body=bad gateway"), SyncErrorKindAPI},
+		{"transport", &url.Error{Op: "Get", URL: "https://api.example.com", Err: errors.New("connection reset by peer")}, SyncErrorKindAPI},
+		{"number without reason phrase", errors.New("wrote 403 rows before failing"), SyncErrorKindUnknown},
+		{"plain", errors.New("unexpected payload"), SyncErrorKindUnknown},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ClassifySyncError(tc.err); got != tc.want {
+				t.Fatalf("ClassifySyncError(%v) = %q, want %q", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFailSyncRunClassifiesUnknownErrors(t *testing.T) {
+	tests := []struct {
+		kind string
+		err  error
+		want string
+	}{
+		{SyncErrorKindUnknown, &statusError{status: 429}, SyncErrorKindRateLimit},
+		{"", &statusError{status: 403}, SyncErrorKindAuth},
+		{SyncErrorKindUnknown, errors.New("unexpected payload"), SyncErrorKindUnknown},
+		// An explicit kind from the caller wins over the classifier.
+		{SyncErrorKindDB, &statusError{status: 429}, SyncErrorKindDB},
+	}
+	for _, tc := range tests {
+		db := &recordingDB{}
+		if err := FailSyncRun(context.Background(), gen.New(db), 7, tc.err, tc.kind); !errors.Is(err, tc.err) {
+			t.Fatalf("FailSyncRun() = %v, want the run error", err)
+		}
+		if len(db.execs) != 1 || len(db.execs[0]) != 4 || db.execs[0][3] != tc.want {
+			t.Fatalf("FailSyncRun(kind=%q, %v) persisted %v, want error kind %q", tc.kind, tc.err, db.execs, tc.want)
+		}
+	}
+}
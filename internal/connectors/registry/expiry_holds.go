@@ -0,0 +1,113 @@
+package registry
+
+import "sync"
+
+// ExpiryHolds collects the scopes a run could not list completely, such as a paginated list
+// that failed part way or an optional listing the token may not read. Finalize leaves rows in a
+// held scope unexpired when the run did not see them, so an incomplete listing never reads as a
+// removal; the next complete run expires them as usual. Rows the run did see are promoted either
+// way. Finalize treats a nil *ExpiryHolds as holding nothing.
+type ExpiryHolds struct {
+	mu sync.Mutex
+
+	appUserFlags []string
+	entitlements []heldScope
+	assetOwners  []heldScope
+	credentials  []heldScope
+}
+
+type heldScope struct {
+	kind string
+	ref  string
+}
+
+func NewExpiryHolds() *ExpiryHolds {
+	return &ExpiryHolds{}
+}
+
+// HoldAppUsers keeps the unseen app users of the source whose raw JSON sets rawJSONFlag to true,
+// e.g. the outside collaborators of a GitHub org.
+func (h *ExpiryHolds) HoldAppUsers(rawJSONFlag string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.appUserFlags = append(h.appUserFlags, rawJSONFlag)
+}
+
+// HoldEntitlements keeps unseen entitlements of kind on resource, or on every resource of the
+// kind when resource is empty.
+func (h *ExpiryHolds) HoldEntitlements(kind, resource string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entitlements = append(h.entitlements, heldScope{kind: kind, ref: resource})
+}
+
+// HoldAppAssetOwners keeps the unseen owners of one app asset.
+func (h *ExpiryHolds) HoldAppAssetOwners(assetKind, assetExternalID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.assetOwners = append(h.assetOwners, heldScope{kind: assetKind, ref: assetExternalID})
+}
+
+// HoldCredentials keeps unseen credentials of kind that reference the asset with
+// assetRefExternalID, or every credential of the kind when assetRefExternalID is empty.
+func (h *ExpiryHolds) HoldCredentials(credentialKind, assetRefExternalID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.credentials = append(h.credentials, heldScope{kind: credentialKind, ref: assetRefExternalID})
+}
+
+// Empty reports whether nothing is held.
+func (h *ExpiryHolds) Empty() bool {
+	if h == nil {
+		return true
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.appUserFlags) == 0 && len(h.entitlements) == 0 && len(h.assetOwners) == 0 && len(h.credentials) == 0
+}
+
+func (h *ExpiryHolds) heldAppUserFlags() []string {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string{}, h.appUserFlags...)
+}
+
+func (h *ExpiryHolds) heldEntitlements() (kinds, resources []string) {
+	if h == nil {
+		return nil, nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return splitHeldScopes(h.entitlements)
+}
+
+func (h *ExpiryHolds) heldAssetOwners() (assetKinds, assetExternalIDs []string) {
+	if h == nil {
+		return nil, nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return splitHeldScopes(h.assetOwners)
+}
+
+func (h *ExpiryHolds) heldCredentials() (credentialKinds, assetRefExternalIDs []string) {
+	if h == nil {
+		return nil, nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return splitHeldScopes(h.credentials)
+}
+
+func splitHeldScopes(scopes []heldScope) (kinds, refs []string) {
+	kinds = make([]string, 0, len(scopes))
+	refs = make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		kinds = append(kinds, scope.kind)
+		refs = append(refs, scope.ref)
+	}
+	return kinds, refs
+}
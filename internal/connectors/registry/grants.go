@@ -0,0 +1,118 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// ConnectorGrants compares the scopes/permissions a connector's credentials hold, as reported
+// by the source API, with the ones the connector needs for a complete sync.
+type ConnectorGrants struct {
+	Granted  []string
+	Required []string
+	Missing  []string
+}
+
+// EvaluateGrants dedupes granted and required and lists every required grant that granted does
+// not cover. Grants compare case-insensitively. implies maps a lowercased grant to the lowercased
+// grants it also confers (e.g. GitHub's admin:org covers read:org); it may be nil.
+func EvaluateGrants(granted, required []string, implies map[string][]string) ConnectorGrants {
+	out := ConnectorGrants{
+		Granted:  normalizeGrants(granted),
+		Required: normalizeGrants(required),
+		Missing:  []string{},
+	}
+
+	held := make(map[string]struct{}, len(out.Granted))
+	var expand func(string)
+	expand = func(grant string) {
+		if _, ok := held[grant]; ok {
+			return
+		}
+		held[grant] = struct{}{}
+		for _, implied := range implies[grant] {
+			expand(strings.ToLower(strings.TrimSpace(implied)))
+		}
+	}
+	for _, grant := range out.Granted {
+		expand(strings.ToLower(grant))
+	}
+
+	for _, grant := range out.Required {
+		if _, ok := held[strings.ToLower(grant)]; !ok {
+			out.Missing = append(out.Missing, grant)
+		}
+	}
+	return out
+}
+
+// MissingGrantsError reports required grants a connector's credentials do not hold.
+type MissingGrantsError struct {
+	Missing []string
+}
+
+func (e *MissingGrantsError) Error() string {
+	return "credentials are missing required permissions: " + strings.Join(e.Missing, ", ")
+}
+
+// VerifyConnection runs the integration's connection test when it implements ConnectionTester.
+// It returns a *MissingGrantsError when the provider reports grants that do not cover
+// RequiredGrants, and nil for integrations that cannot be tested.
+func VerifyConnection(ctx context.Context, integration Integration) error {
+	tester, ok := integration.(ConnectionTester)
+	if !ok {
+		return nil
+	}
+	grants, reported, err := tester.TestConnection(ctx)
+	if err != nil {
+		return fmt.Errorf("connection test failed: %w", err)
+	}
+	if reported && len(grants.Missing) > 0 {
+		return &MissingGrantsError{Missing: grants.Missing}
+	}
+	return nil
+}
+
+func normalizeGrants(values []string) []string {
+	out := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		key := strings.ToLower(value)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, value)
+	}
+	return out
+}
+
+// PersistConnectorGrants replaces the stored grants for the connector with grants observed in runID.
+func PersistConnectorGrants(ctx context.Context, q *gen.Queries, sourceKind, sourceName string, runID int64, grants ConnectorGrants) error {
+	if q == nil {
+		return errors.New("connector grants could not be persisted: queries is nil")
+	}
+	return q.UpsertConnectorGrant(ctx, gen.UpsertConnectorGrantParams{
+		SourceKind: strings.TrimSpace(sourceKind),
+		SourceName: strings.TrimSpace(sourceName),
+		Granted:    nonNilStrings(grants.Granted),
+		Required:   nonNilStrings(grants.Required),
+		Missing:    nonNilStrings(grants.Missing),
+		LastRunID:  runID,
+	})
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/open-sspm/open-sspm/internal/db/gen"
@@ -40,6 +41,11 @@ func FailSyncRun(ctx context.Context, q *gen.Queries, runID int64, err error, er
 	if err == nil {
 		return nil
 	}
+	// A run stopped by its sync run timeout fails with a deadline error from whichever call was
+	// in flight; name the timeout so the failure says why.
+	if cause := SyncRunTimeoutCause(ctx); cause != nil && !errors.As(err, new(*SyncRunTimeoutError)) {
+		err = fmt.Errorf("%w: %w", cause, err)
+	}
 	if q == nil {
 		return errors.Join(err, errors.New("sync run failure could not be persisted: queries is nil"))
 	}
@@ -48,14 +54,18 @@ func FailSyncRun(ctx context.Context, q *gen.Queries, runID int64, err error, er
 	}
 
 	status := SyncStatusError
-	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+	switch {
+	case errors.As(err, new(*SyncRunTimeoutError)):
+		errorKind = SyncErrorKindTimeout
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
 		status = SyncStatusCanceled
 		errorKind = SyncErrorKindContextCanceled
 	}
 
 	errorKind = strings.TrimSpace(errorKind)
-	if errorKind == "" {
-		errorKind = SyncErrorKindUnknown
+	if errorKind == "" || errorKind == SyncErrorKindUnknown {
+		// Callers that cannot tell what failed get the kind derived from the error itself.
+		errorKind = ClassifySyncError(err)
 	}
 
 	msg := strings.TrimSpace(err.Error())
@@ -83,7 +93,45 @@ func FailSyncRun(ctx context.Context, q *gen.Queries, runID int64, err error, er
 	return err
 }
 
-func FinalizeOktaRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, runID int64, sourceName string, duration time.Duration, finalizeDiscovery bool) error {
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// RunWriteStage runs a multi-batch write stage inside a single transaction so
+// a failure on a later batch leaves no rows from the stage stamped with the
+// current seen_in_run_id. Without a pool the stage runs directly against q.
+// A stage that fails on a transient database error is rerun from the start a
+// bounded number of times before the error is returned.
+func RunWriteStage(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, stage func(*gen.Queries) error) error {
+	if pool == nil {
+		return retryTransientDB(ctx, func() error { return stage(q) })
+	}
+	return retryTransientDB(ctx, func() error { return runWriteStageTx(ctx, q, pool, stage) })
+}
+
+func runWriteStageTx(ctx context.Context, q *gen.Queries, db txBeginner, stage func(*gen.Queries) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := stage(q.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// FinalizeOktaRun promotes the rows seen in the run, expires the rest outside
+// holds, and marks the run successful in one transaction. A transient database
+// error reruns the whole transaction.
+func FinalizeOktaRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, runID int64, sourceName string, duration time.Duration, finalizeDiscovery bool, holds *ExpiryHolds) error {
+	return retryTransientDB(ctx, func() error {
+		return finalizeOktaRun(ctx, q, pool, runID, sourceName, duration, finalizeDiscovery, holds)
+	})
+}
+
+func finalizeOktaRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, runID int64, sourceName string, duration time.Duration, finalizeDiscovery bool, holds *ExpiryHolds) error {
 	tx, err := pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -166,6 +214,55 @@ func FinalizeOktaRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, ru
 	}
 	counts["okta_app_group_assignments_expired"] = expired
 
+	observed, err = qtx.PromoteAppAssetsSeenInRunBySource(ctx, gen.PromoteAppAssetsSeenInRunBySourceParams{
+		LastObservedRunID: runID,
+		SourceKind:        "okta",
+		SourceName:        sourceName,
+	})
+	if err != nil {
+		return err
+	}
+	counts["app_assets_observed"] = observed
+
+	expired, err = qtx.ExpireAppAssetsNotSeenInRunBySource(ctx, gen.ExpireAppAssetsNotSeenInRunBySourceParams{
+		ExpiredRunID: runID,
+		SourceKind:   "okta",
+		SourceName:   sourceName,
+	})
+	if err != nil {
+		return err
+	}
+	counts["app_assets_expired"] = expired
+
+	tagged, err := applyAppAssetTagRules(ctx, qtx, "okta", sourceName)
+	if err != nil {
+		return err
+	}
+	counts["app_asset_tags_changed"] = tagged
+
+	observed, err = qtx.PromoteCredentialArtifactsSeenInRunBySource(ctx, gen.PromoteCredentialArtifactsSeenInRunBySourceParams{
+		LastObservedRunID: runID,
+		SourceKind:        "okta",
+		SourceName:        sourceName,
+	})
+	if err != nil {
+		return err
+	}
+	counts["credential_artifacts_observed"] = observed
+
+	heldCredentialKinds, heldAssetRefExternalIDs := holds.heldCredentials()
+	expired, err = qtx.ExpireCredentialArtifactsNotSeenInRunBySource(ctx, gen.ExpireCredentialArtifactsNotSeenInRunBySourceParams{
+		ExpiredRunID:            runID,
+		SourceKind:              "okta",
+		SourceName:              sourceName,
+		HeldCredentialKinds:     heldCredentialKinds,
+		HeldAssetRefExternalIds: heldAssetRefExternalIDs,
+	})
+	if err != nil {
+		return err
+	}
+	counts["credential_artifacts_expired"] = expired
+
 	if finalizeDiscovery {
 		observed, err = qtx.PromoteSaaSAppSourcesSeenInRunBySource(ctx, gen.PromoteSaaSAppSourcesSeenInRunBySourceParams{
 			LastObservedRunID: runID,
@@ -208,6 +305,10 @@ func FinalizeOktaRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, ru
 		counts["saas_app_events_expired"] = expired
 	}
 
+	if err := flagSuspiciousEmptyResult(ctx, qtx, runID, "okta", sourceName, counts); err != nil {
+		return err
+	}
+
 	stats := MarshalJSON(map[string]any{
 		"counts":      counts,
 		"duration_ms": duration.Milliseconds(),
@@ -219,7 +320,14 @@ func FinalizeOktaRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, ru
 	return tx.Commit(ctx)
 }
 
-func FinalizeAppRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, runID int64, sourceKind, sourceName string, duration time.Duration, finalizeDiscovery bool) error {
+// FinalizeAppRun is FinalizeOktaRun for an app connector's rows.
+func FinalizeAppRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, runID int64, sourceKind, sourceName string, duration time.Duration, finalizeDiscovery bool, holds *ExpiryHolds) error {
+	return retryTransientDB(ctx, func() error {
+		return finalizeAppRun(ctx, q, pool, runID, sourceKind, sourceName, duration, finalizeDiscovery, holds)
+	})
+}
+
+func finalizeAppRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, runID int64, sourceKind, sourceName string, duration time.Duration, finalizeDiscovery bool, holds *ExpiryHolds) error {
 	tx, err := pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -243,9 +351,10 @@ func FinalizeAppRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, run
 	counts["app_users_observed"] = observed
 
 	expired, err := qtx.ExpireAppUsersNotSeenInRun(ctx, gen.ExpireAppUsersNotSeenInRunParams{
-		ExpiredRunID: runIDKey,
-		SourceKind:   sourceKind,
-		SourceName:   sourceName,
+		ExpiredRunID:     runID,
+		SourceKind:       sourceKind,
+		SourceName:       sourceName,
+		HeldRawJsonFlags: holds.heldAppUserFlags(),
 	})
 	if err != nil {
 		return err
@@ -262,10 +371,13 @@ func FinalizeAppRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, run
 	}
 	counts["entitlements_observed"] = observed
 
+	heldKinds, heldResources := holds.heldEntitlements()
 	expired, err = qtx.ExpireEntitlementsNotSeenInRunBySource(ctx, gen.ExpireEntitlementsNotSeenInRunBySourceParams{
-		ExpiredRunID: runIDKey,
-		SourceKind:   sourceKind,
-		SourceName:   sourceName,
+		ExpiredRunID:  runID,
+		SourceKind:    sourceKind,
+		SourceName:    sourceName,
+		HeldKinds:     heldKinds,
+		HeldResources: heldResources,
 	})
 	if err != nil {
 		return err
@@ -292,6 +404,12 @@ func FinalizeAppRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, run
 	}
 	counts["app_assets_expired"] = expired
 
+	tagged, err := applyAppAssetTagRules(ctx, qtx, sourceKind, sourceName)
+	if err != nil {
+		return err
+	}
+	counts["app_asset_tags_changed"] = tagged
+
 	observed, err = qtx.PromoteAppAssetOwnersSeenInRunBySource(ctx, gen.PromoteAppAssetOwnersSeenInRunBySourceParams{
 		LastObservedRunID: runID,
 		SourceKind:        sourceKind,
@@ -302,10 +420,13 @@ func FinalizeAppRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, run
 	}
 	counts["app_asset_owners_observed"] = observed
 
+	heldAssetKinds, heldAssetExternalIDs := holds.heldAssetOwners()
 	expired, err = qtx.ExpireAppAssetOwnersNotSeenInRunBySource(ctx, gen.ExpireAppAssetOwnersNotSeenInRunBySourceParams{
-		ExpiredRunID: runID,
-		SourceKind:   sourceKind,
-		SourceName:   sourceName,
+		ExpiredRunID:         runID,
+		SourceKind:           sourceKind,
+		SourceName:           sourceName,
+		HeldAssetKinds:       heldAssetKinds,
+		HeldAssetExternalIds: heldAssetExternalIDs,
 	})
 	if err != nil {
 		return err
@@ -322,10 +443,13 @@ func FinalizeAppRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, run
 	}
 	counts["credential_artifacts_observed"] = observed
 
+	heldCredentialKinds, heldAssetRefExternalIDs := holds.heldCredentials()
 	expired, err = qtx.ExpireCredentialArtifactsNotSeenInRunBySource(ctx, gen.ExpireCredentialArtifactsNotSeenInRunBySourceParams{
-		ExpiredRunID: runID,
-		SourceKind:   sourceKind,
-		SourceName:   sourceName,
+		ExpiredRunID:            runID,
+		SourceKind:              sourceKind,
+		SourceName:              sourceName,
+		HeldCredentialKinds:     heldCredentialKinds,
+		HeldAssetRefExternalIds: heldAssetRefExternalIDs,
 	})
 	if err != nil {
 		return err
@@ -374,6 +498,10 @@ func FinalizeAppRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, run
 		counts["saas_app_events_expired"] = expired
 	}
 
+	if err := flagSuspiciousEmptyResult(ctx, qtx, runID, sourceKind, sourceName, counts); err != nil {
+		return err
+	}
+
 	stats := MarshalJSON(map[string]any{
 		"counts":      counts,
 		"duration_ms": duration.Milliseconds(),
@@ -385,7 +513,13 @@ func FinalizeAppRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, run
 	return tx.Commit(ctx)
 }
 
+// FinalizeDiscoveryRun finalizes a discovery-only run, with the same retry as
+// FinalizeOktaRun.
 func FinalizeDiscoveryRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, runID int64, sourceKind, sourceName string, duration time.Duration) error {
+	return retryTransientDB(ctx, func() error { return finalizeDiscoveryRun(ctx, q, pool, runID, sourceKind, sourceName, duration) })
+}
+
+func finalizeDiscoveryRun(ctx context.Context, q *gen.Queries, pool *pgxpool.Pool, runID int64, sourceKind, sourceName string, duration time.Duration) error {
 	tx, err := pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -471,3 +605,10 @@ func NormalizeJSON(b []byte) []byte {
 	}
 	return b
 }
+
+// NormalizeRawJSON is NormalizeJSON for a provider's raw payload: payloads over MaxRawJSONBytes
+// are replaced by a truncation marker that keeps the top-level keys and identifying scalars.
+// Structured JSON such as scopes or profiles is read back by the app and must use NormalizeJSON.
+func NormalizeRawJSON(b []byte) []byte {
+	return guardRawJSONSize(NormalizeJSON(b))
+}
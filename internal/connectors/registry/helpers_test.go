@@ -69,3 +69,105 @@ func TestMarshalJSONPanicsOnUnsupportedValue(t *testing.T) {
 	}()
 	_ = MarshalJSON(func() {})
 }
+
+// stageStore hands out transactions that count the writes made through them and
+// persist the count only on commit.
+type stageStore struct {
+	committed  int
+	rolledBack bool
+}
+
+type stageTx struct {
+	pgx.Tx
+	store   *stageStore
+	pending int
+	failOn  int
+	done    bool
+}
+
+var errBatchWrite = errors.New("batch write failed")
+
+func (s *stageStore) Begin(context.Context) (pgx.Tx, error) {
+	return &stageTx{store: s, failOn: 3}, nil
+}
+
+func (tx *stageTx) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	tx.pending++
+	if tx.pending == tx.failOn {
+		return pgconn.CommandTag{}, errBatchWrite
+	}
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (tx *stageTx) Commit(context.Context) error {
+	if tx.done {
+		return pgx.ErrTxClosed
+	}
+	tx.done = true
+	tx.store.committed += tx.pending
+	return nil
+}
+
+func (tx *stageTx) Rollback(context.Context) error {
+	if tx.done {
+		return pgx.ErrTxClosed
+	}
+	tx.done = true
+	tx.store.rolledBack = true
+	return nil
+}
+
+// writeBatches upserts each batch through qtx and returns how many succeeded.
+func writeBatches(qtx *gen.Queries, batches [][]string) (int, error) {
+	for i, batch := range batches {
+		if _, err := qtx.UpsertEntitlementsBulkBySource(context.Background(), gen.UpsertEntitlementsBulkBySourceParams{
+			SeenInRunID:        7,
+			SourceKind:         "github",
+			SourceName:         "acme",
+			AppUserExternalIds: batch,
+		}); err != nil {
+			return i, err
+		}
+	}
+	return len(batches), nil
+}
+
+func TestRunWriteStageRollsBackEarlierBatchesOnLaterFailure(t *testing.T) {
+	store := &stageStore{}
+	outside := gen.New(fakeDB{execErr: errors.New("write outside the stage transaction")})
+
+	var written int
+	err := runWriteStageTx(context.Background(), outside, store, func(qtx *gen.Queries) error {
+		var err error
+		written, err = writeBatches(qtx, [][]string{{"u-1", "u-2"}, {"u-3"}, {"u-4"}, {"u-5"}})
+		return err
+	})
+	if !errors.Is(err, errBatchWrite) {
+		t.Fatalf("runWriteStageTx() error = %v, want the batch error", err)
+	}
+	if written != 2 {
+		t.Fatalf("batches written before failure = %d, want 2", written)
+	}
+	if store.committed != 0 {
+		t.Fatalf("committed writes = %d, want none", store.committed)
+	}
+	if !store.rolledBack {
+		t.Fatalf("expected stage transaction to roll back")
+	}
+}
+
+func TestRunWriteStageCommitsAllBatches(t *testing.T) {
+	store := &stageStore{}
+	outside := gen.New(fakeDB{execErr: errors.New("write outside the stage transaction")})
+
+	err := runWriteStageTx(context.Background(), outside, store, func(qtx *gen.Queries) error {
+		_, err := writeBatches(qtx, [][]string{{"u-1"}, {"u-2"}})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("runWriteStageTx() error = %v", err)
+	}
+	if store.committed != 2 || store.rolledBack {
+		t.Fatalf("committed = %d rolledBack = %v, want 2 writes committed", store.committed, store.rolledBack)
+	}
+}
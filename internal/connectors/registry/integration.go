@@ -2,6 +2,7 @@ package registry
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -35,6 +36,40 @@ type ModeAwareIntegration interface {
 	SupportsRunMode(RunMode) bool
 }
 
+// ErrRevocationUnsupported is returned by CredentialRevoker.Revoke for credentials the
+// provider API cannot revoke; callers fall back to a manual revocation task.
+var ErrRevocationUnsupported = errors.New("credential revocation is not supported for this credential")
+
+// CredentialRevoker is an optional interface that integrations can implement
+// to revoke a credential they ingested through the provider API.
+type CredentialRevoker interface {
+	Revoke(ctx context.Context, credential gen.CredentialArtifact) error
+}
+
+// ConnectionTester is an optional interface that integrations can implement so a connector's
+// credentials can be checked before it is enabled.
+type ConnectionTester interface {
+	// RequiredGrants lists the scopes or permissions a complete sync needs.
+	RequiredGrants() []string
+	// TestConnection makes a minimal authenticated call and evaluates the credentials' grants
+	// against RequiredGrants. ok is false when the provider does not report grants for the
+	// credential type, in which case only connectivity is checked.
+	TestConnection(ctx context.Context) (grants ConnectorGrants, ok bool, err error)
+}
+
+// IntegrationSupportsRunMode reports whether integration can run in mode.
+// Integrations that are not mode-aware only support full runs.
+func IntegrationSupportsRunMode(integration Integration, mode RunMode) bool {
+	if integration == nil {
+		return false
+	}
+	mode = mode.Normalize()
+	if modeAware, ok := integration.(ModeAwareIntegration); ok {
+		return modeAware.SupportsRunMode(mode)
+	}
+	return mode == RunModeFull
+}
+
 const UnknownTotal int64 = -1
 
 type Reporter interface {
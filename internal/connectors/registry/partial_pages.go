@@ -0,0 +1,48 @@
+package registry
+
+import "fmt"
+
+// ListOption tunes a connector client's paginated list call.
+type ListOption func(*listOptions)
+
+type listOptions struct {
+	allowPartial bool
+}
+
+// AllowPartialPages makes a list call that fails after its first page return the items collected
+// so far together with a *PartialPagesError instead of discarding them. Only use it where
+// incomplete data is better than none, such as enrichment lookups; core inventory lists should
+// keep failing outright. Callers that write the partial items must hold expiry for the listed
+// scope (see ExpiryHolds) so the rows on the missing pages are not expired.
+func AllowPartialPages() ListOption {
+	return func(o *listOptions) {
+		o.allowPartial = true
+	}
+}
+
+// PartialPagesError reports a paginated list that failed after Pages pages were collected.
+type PartialPagesError struct {
+	Pages int
+	Err   error
+}
+
+func (e *PartialPagesError) Error() string {
+	return fmt.Sprintf("list incomplete after %d pages: %v", e.Pages, e.Err)
+}
+
+func (e *PartialPagesError) Unwrap() error {
+	return e.Err
+}
+
+// PageFailure is what a paginated list returns when fetching a page fails: nothing by default,
+// or the collected items and a *PartialPagesError when the call allowed partial pages.
+func PageFailure[T any](items []T, pages int, err error, opts []ListOption) ([]T, error) {
+	var o listOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if !o.allowPartial || pages == 0 {
+		return nil, err
+	}
+	return items, &PartialPagesError{Pages: pages, Err: err}
+}
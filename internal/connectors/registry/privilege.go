@@ -0,0 +1,108 @@
+package registry
+
+import (
+	"path"
+	"strings"
+)
+
+// PrivilegeLevel is the canonical, ordinal privilege of an entitlement across connectors. It is
+// stored next to the provider-native permission so admin-level access can be queried
+// cross-source.
+type PrivilegeLevel int16
+
+const (
+	PrivilegeUnknown PrivilegeLevel = 0
+	PrivilegeRead    PrivilegeLevel = 1
+	PrivilegeWrite   PrivilegeLevel = 2
+	PrivilegeAdmin   PrivilegeLevel = 3
+	PrivilegeOwner   PrivilegeLevel = 4
+)
+
+func (l PrivilegeLevel) String() string {
+	switch l {
+	case PrivilegeRead:
+		return "read"
+	case PrivilegeWrite:
+		return "write"
+	case PrivilegeAdmin:
+		return "admin"
+	case PrivilegeOwner:
+		return "owner"
+	default:
+		return "unknown"
+	}
+}
+
+// ParsePrivilegeLevel maps a level name back to its value; unrecognized names are
+// PrivilegeUnknown.
+func ParsePrivilegeLevel(raw string) PrivilegeLevel {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "read":
+		return PrivilegeRead
+	case "write":
+		return PrivilegeWrite
+	case "admin":
+		return PrivilegeAdmin
+	case "owner":
+		return PrivilegeOwner
+	default:
+		return PrivilegeUnknown
+	}
+}
+
+// PrivilegeRule maps entitlements of Kind whose permission and role name match the rule's
+// patterns to Level. Patterns use path.Match syntax and compare case-insensitively; an empty
+// pattern matches anything.
+type PrivilegeRule struct {
+	Kind       string
+	Permission string
+	Role       string
+	Level      PrivilegeLevel
+}
+
+// PrivilegeMapping is a connector's permission vocabulary as an ordered rule table; the first
+// matching rule wins.
+type PrivilegeMapping []PrivilegeRule
+
+// Level returns the canonical level for an entitlement. role is the provider role or policy name
+// when the permission alone does not carry the privilege (e.g. Entra directory roles).
+func (m PrivilegeMapping) Level(kind, permission, role string) PrivilegeLevel {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	permission = strings.ToLower(strings.TrimSpace(permission))
+	role = strings.ToLower(strings.TrimSpace(role))
+	for _, rule := range m {
+		if !strings.EqualFold(strings.TrimSpace(rule.Kind), kind) {
+			continue
+		}
+		if privilegePatternMatches(rule.Permission, permission) && privilegePatternMatches(rule.Role, role) {
+			return rule.Level
+		}
+	}
+	return PrivilegeUnknown
+}
+
+func privilegePatternMatches(pattern, value string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return true
+	}
+	ok, err := path.Match(pattern, value)
+	return err == nil && ok
+}
+
+// Levels maps parallel entitlement arrays to the upsert's privilege_levels parameter. roles may
+// be nil, or hold an empty string for rows without a role name.
+func (m PrivilegeMapping) Levels(kinds, permissions, roles []string) []int16 {
+	out := make([]int16, len(kinds))
+	for idx, kind := range kinds {
+		permission, role := "", ""
+		if idx < len(permissions) {
+			permission = permissions[idx]
+		}
+		if idx < len(roles) {
+			role = roles[idx]
+		}
+		out[idx] = int16(m.Level(kind, permission, role))
+	}
+	return out
+}
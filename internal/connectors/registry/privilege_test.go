@@ -0,0 +1,48 @@
+package registry
+
+import "testing"
+
+func TestPrivilegeMappingLevelFirstMatchWins(t *testing.T) {
+	t.Parallel()
+
+	mapping := PrivilegeMapping{
+		{Kind: "role", Role: "global administrator", Level: PrivilegeOwner},
+		{Kind: "role", Role: "*administrator", Level: PrivilegeAdmin},
+		{Kind: "repo", Permission: "pull", Level: PrivilegeRead},
+		{Kind: "role", Level: PrivilegeWrite},
+	}
+	cases := []struct {
+		kind, permission, role string
+		want                   PrivilegeLevel
+	}{
+		{kind: "role", role: "Global Administrator", want: PrivilegeOwner},
+		{kind: "ROLE", role: " User Administrator ", want: PrivilegeAdmin},
+		{kind: "role", role: "Guest Inviter", want: PrivilegeWrite},
+		{kind: "repo", permission: "PULL", want: PrivilegeRead},
+		{kind: "repo", permission: "push", want: PrivilegeUnknown},
+		{kind: "other", permission: "pull", want: PrivilegeUnknown},
+	}
+	for _, tc := range cases {
+		if got := mapping.Level(tc.kind, tc.permission, tc.role); got != tc.want {
+			t.Fatalf("Level(%q, %q, %q) = %v, want %v", tc.kind, tc.permission, tc.role, got, tc.want)
+		}
+	}
+
+	levels := mapping.Levels([]string{"repo", "role"}, []string{"pull", "member"}, nil)
+	if len(levels) != 2 || levels[0] != int16(PrivilegeRead) || levels[1] != int16(PrivilegeWrite) {
+		t.Fatalf("Levels() = %v, want [1 2]", levels)
+	}
+}
+
+func TestParsePrivilegeLevelRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, level := range []PrivilegeLevel{PrivilegeUnknown, PrivilegeRead, PrivilegeWrite, PrivilegeAdmin, PrivilegeOwner} {
+		if got := ParsePrivilegeLevel(level.String()); got != level {
+			t.Fatalf("ParsePrivilegeLevel(%q) = %v, want %v", level.String(), got, level)
+		}
+	}
+	if got := ParsePrivilegeLevel(" Admin "); got != PrivilegeAdmin {
+		t.Fatalf("ParsePrivilegeLevel(\" Admin \") = %v, want admin", got)
+	}
+}
@@ -0,0 +1,90 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"sync/atomic"
+
+	"github.com/open-sspm/open-sspm/internal/metrics"
+)
+
+// DefaultMaxRawJSONBytes bounds stored provider payloads unless overridden by SetMaxRawJSONBytes.
+const DefaultMaxRawJSONBytes = 256 * 1024
+
+// RawJSONTruncatedKey marks a payload that NormalizeRawJSON shrank to fit the size limit.
+const RawJSONTruncatedKey = "_truncated"
+
+// rawJSONMaxScalarBytes caps each top-level scalar kept from a truncated payload so a single
+// huge string cannot crowd out the identifying fields.
+const rawJSONMaxScalarBytes = 1024
+
+var maxRawJSONBytes atomic.Int64
+
+func init() {
+	maxRawJSONBytes.Store(DefaultMaxRawJSONBytes)
+}
+
+// SetMaxRawJSONBytes sets the largest raw payload NormalizeRawJSON stores verbatim. Zero or a
+// negative value disables truncation.
+func SetMaxRawJSONBytes(n int64) {
+	maxRawJSONBytes.Store(n)
+}
+
+// MaxRawJSONBytes returns the current raw payload limit; zero means unlimited.
+func MaxRawJSONBytes() int64 {
+	if n := maxRawJSONBytes.Load(); n > 0 {
+		return n
+	}
+	return 0
+}
+
+// truncateRawJSON replaces an oversized payload with a marker object. Top-level scalars
+// (ids, emails, names) are kept in key order while they fit, nested values are dropped, and
+// every original top-level key is listed so readers can tell what was removed.
+func truncateRawJSON(b []byte, limit int64) []byte {
+	out := map[string]any{
+		RawJSONTruncatedKey: true,
+		"_original_bytes":   len(b),
+	}
+
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(b, &payload); err != nil {
+		return MarshalJSON(out)
+	}
+
+	keys := make([]string, 0, len(payload))
+	for key := range payload {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	out["_top_level_keys"] = keys
+	if encoded := MarshalJSON(out); int64(len(encoded)) > limit {
+		delete(out, "_top_level_keys")
+		return MarshalJSON(out)
+	}
+
+	for _, key := range keys {
+		value := bytes.TrimSpace(payload[key])
+		if len(value) == 0 || value[0] == '{' || value[0] == '[' || len(value) > rawJSONMaxScalarBytes {
+			continue
+		}
+		if _, exists := out[key]; exists {
+			continue
+		}
+		out[key] = json.RawMessage(value)
+		if int64(len(MarshalJSON(out))) > limit {
+			delete(out, key)
+		}
+	}
+	return MarshalJSON(out)
+}
+
+func guardRawJSONSize(b []byte) []byte {
+	limit := MaxRawJSONBytes()
+	if limit == 0 || int64(len(b)) <= limit {
+		return b
+	}
+	metrics.RawJSONTruncationsTotal.Inc()
+	return truncateRawJSON(b, limit)
+}
@@ -0,0 +1,67 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestNormalizeRawJSONTruncatesOversizedPayload(t *testing.T) {
+	permissions := make(map[string]string, 20000)
+	for i := range 20000 {
+		permissions[fmt.Sprintf("repo-%05d", i)] = "write"
+	}
+	raw := MarshalJSON(map[string]any{
+		"id":          "00u1",
+		"email":       "alice@example.com",
+		"permissions": permissions,
+		"groups":      []string{"eng", "ops"},
+	})
+	if int64(len(raw)) <= MaxRawJSONBytes() {
+		t.Fatalf("fixture is %d bytes, want more than %d", len(raw), MaxRawJSONBytes())
+	}
+
+	before := testutil.ToFloat64(metrics.RawJSONTruncationsTotal)
+	got := NormalizeRawJSON(raw)
+	if int64(len(got)) > MaxRawJSONBytes() {
+		t.Fatalf("truncated payload is %d bytes, want at most %d", len(got), MaxRawJSONBytes())
+	}
+	if after := testutil.ToFloat64(metrics.RawJSONTruncationsTotal); after != before+1 {
+		t.Fatalf("truncation metric = %v, want %v", after, before+1)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(got, &payload); err != nil {
+		t.Fatalf("truncated payload is not valid JSON: %v", err)
+	}
+	if payload[RawJSONTruncatedKey] != true {
+		t.Fatalf("truncated payload has no marker: %s", got)
+	}
+	if payload["id"] != "00u1" || payload["email"] != "alice@example.com" {
+		t.Fatalf("identifying fields lost: %s", got)
+	}
+	if _, ok := payload["permissions"]; ok {
+		t.Fatalf("nested value kept in truncated payload")
+	}
+	var keys []string
+	for _, key := range payload["_top_level_keys"].([]any) {
+		keys = append(keys, key.(string))
+	}
+	if want := []string{"email", "groups", "id", "permissions"}; !slices.Equal(keys, want) {
+		t.Fatalf("top-level keys = %v, want %v", keys, want)
+	}
+}
+
+func TestNormalizeRawJSONKeepsSmallPayload(t *testing.T) {
+	raw := []byte(`{"id":"00u1","profile":{"email":"alice@example.com"}}`)
+	if got := NormalizeRawJSON(raw); string(got) != string(raw) {
+		t.Fatalf("NormalizeRawJSON() = %s, want %s", got, raw)
+	}
+	if got := NormalizeRawJSON(nil); string(got) != "{}" {
+		t.Fatalf("NormalizeRawJSON(nil) = %s, want {}", got)
+	}
+}
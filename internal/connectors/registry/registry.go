@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
+	"github.com/open-sspm/open-sspm/internal/connectors/configstore"
 	"github.com/open-sspm/open-sspm/internal/db/gen"
 )
 
@@ -13,6 +15,8 @@ import (
 type ConnectorRegistry struct {
 	definitions map[string]ConnectorDefinition
 	order       []string // Display order
+	secrets     configstore.SecretProvider
+	runTimeouts map[string]time.Duration
 }
 
 // NewRegistry creates a new connector registry.
@@ -37,6 +41,18 @@ func (r *ConnectorRegistry) Register(def ConnectorDefinition) error {
 	return nil
 }
 
+// SetSecretProvider sets the backend ResolveConfig uses for secret references.
+func (r *ConnectorRegistry) SetSecretProvider(p configstore.SecretProvider) {
+	r.secrets = p
+}
+
+// ResolveConfig replaces secret references in a decoded connector config with their values.
+// Call it right before validating a config and building its integration; states returned by
+// LoadStates keep the unresolved references so secrets are never rendered.
+func (r *ConnectorRegistry) ResolveConfig(ctx context.Context, cfg any) (any, error) {
+	return configstore.ResolveSecrets(ctx, r.secrets, cfg)
+}
+
 // Get retrieves a connector definition by kind.
 func (r *ConnectorRegistry) Get(kind string) (ConnectorDefinition, bool) {
 	def, ok := r.definitions[strings.ToLower(strings.TrimSpace(kind))]
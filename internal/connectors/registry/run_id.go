@@ -0,0 +1,37 @@
+package registry
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+type syncRunIDKey struct{}
+
+// WithSyncRunID returns ctx set up to note the sync run an integration creates with
+// CreateSyncRun, so the caller can find that run without searching by time.
+func WithSyncRunID(ctx context.Context) context.Context {
+	return context.WithValue(ctx, syncRunIDKey{}, new(atomic.Int64))
+}
+
+// SyncRunIDFromContext returns the last sync run created under ctx by CreateSyncRun.
+func SyncRunIDFromContext(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(syncRunIDKey{}).(*atomic.Int64)
+	if !ok || id.Load() <= 0 {
+		return 0, false
+	}
+	return id.Load(), true
+}
+
+// CreateSyncRun starts a sync run and notes its ID on ctx for SyncRunIDFromContext.
+func CreateSyncRun(ctx context.Context, q *gen.Queries, arg gen.CreateSyncRunParams) (int64, error) {
+	runID, err := q.CreateSyncRun(ctx, arg)
+	if err != nil {
+		return 0, err
+	}
+	if id, ok := ctx.Value(syncRunIDKey{}).(*atomic.Int64); ok {
+		id.Store(runID)
+	}
+	return runID, nil
+}
@@ -0,0 +1,67 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// runIDDB answers CreateSyncRun with the next run ID.
+type runIDDB struct {
+	next int64
+}
+
+func (db *runIDDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	panic("unexpected Exec call")
+}
+
+func (db *runIDDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	panic("unexpected Query call")
+}
+
+func (db *runIDDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	db.next++
+	return runIDRow(db.next)
+}
+
+type runIDRow int64
+
+func (r runIDRow) Scan(dest ...any) error {
+	*(dest[0].(*int64)) = int64(r)
+	return nil
+}
+
+func TestCreateSyncRunNotesRunIDOnContext(t *testing.T) {
+	t.Parallel()
+
+	q := gen.New(&runIDDB{next: 41})
+	ctx := WithSyncRunID(context.Background())
+	if _, ok := SyncRunIDFromContext(ctx); ok {
+		t.Fatalf("SyncRunIDFromContext() before any run reported a run")
+	}
+
+	for _, want := range []int64{42, 43} {
+		runID, err := CreateSyncRun(ctx, q, gen.CreateSyncRunParams{SourceKind: "github", SourceName: "acme"})
+		if err != nil {
+			t.Fatalf("CreateSyncRun() error = %v", err)
+		}
+		if got, ok := SyncRunIDFromContext(ctx); !ok || got != runID || got != want {
+			t.Fatalf("SyncRunIDFromContext() = %d, %v, want %d", got, ok, want)
+		}
+	}
+}
+
+func TestCreateSyncRunWithoutRecorder(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	if _, err := CreateSyncRun(ctx, gen.New(&runIDDB{}), gen.CreateSyncRunParams{SourceKind: "github", SourceName: "acme"}); err != nil {
+		t.Fatalf("CreateSyncRun() error = %v", err)
+	}
+	if _, ok := SyncRunIDFromContext(ctx); ok {
+		t.Fatalf("SyncRunIDFromContext() reported a run on a context without a recorder")
+	}
+}
@@ -14,6 +14,16 @@ func ParseRunMode(v string) RunMode {
 	return mode.Normalize()
 }
 
+// LookupRunMode parses v without defaulting, reporting whether it names a known mode.
+func LookupRunMode(v string) (RunMode, bool) {
+	switch mode := RunMode(strings.ToLower(strings.TrimSpace(v))); mode {
+	case RunModeFull, RunModeDiscovery:
+		return mode, true
+	default:
+		return "", false
+	}
+}
+
 func (m RunMode) Normalize() RunMode {
 	switch m {
 	case RunModeDiscovery:
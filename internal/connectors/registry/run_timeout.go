@@ -0,0 +1,64 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SyncErrorKindTimeout is a run stopped because it outlasted its sync run timeout.
+const SyncErrorKindTimeout = "timeout"
+
+// SyncRunTimeoutError is the cancellation cause of a run stopped by its sync run timeout. A run
+// that fails this way is recorded as an error, not as canceled, so it counts toward backoff.
+type SyncRunTimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *SyncRunTimeoutError) Error() string {
+	return fmt.Sprintf("sync run exceeded its %s timeout", e.Timeout)
+}
+
+// WithSyncRunTimeout bounds a sync run so a hung provider cannot hold its run slot forever.
+// A non-positive timeout leaves the run without a deadline.
+func WithSyncRunTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, timeout, &SyncRunTimeoutError{Timeout: timeout})
+}
+
+// SyncRunTimeoutCause returns the timeout that stopped ctx, or nil if ctx is live or was
+// stopped for another reason.
+func SyncRunTimeoutCause(ctx context.Context) *SyncRunTimeoutError {
+	if ctx == nil || ctx.Err() == nil {
+		return nil
+	}
+	var timeoutErr *SyncRunTimeoutError
+	if errors.As(context.Cause(ctx), &timeoutErr) {
+		return timeoutErr
+	}
+	return nil
+}
+
+// SetSyncRunTimeouts sets the deadline for each connector kind's sync runs; the "default" entry
+// covers kinds without their own and 0 disables the deadline.
+func (r *ConnectorRegistry) SetSyncRunTimeouts(timeouts map[string]time.Duration) {
+	r.runTimeouts = make(map[string]time.Duration, len(timeouts))
+	for kind, timeout := range timeouts {
+		r.runTimeouts[strings.ToLower(strings.TrimSpace(kind))] = timeout
+	}
+}
+
+// SyncRunTimeout returns the deadline for a sync run of kind, or 0 for none.
+func (r *ConnectorRegistry) SyncRunTimeout(kind string) time.Duration {
+	if r == nil {
+		return 0
+	}
+	if timeout, ok := r.runTimeouts[strings.ToLower(strings.TrimSpace(kind))]; ok {
+		return timeout
+	}
+	return r.runTimeouts["default"]
+}
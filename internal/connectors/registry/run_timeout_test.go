@@ -0,0 +1,72 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestFailSyncRunRecordsSyncRunTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := WithSyncRunTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// A provider call that never answers returns only when the run deadline fires.
+	hungListUsers := func(ctx context.Context) error {
+		<-ctx.Done()
+		return fmt.Errorf("list users: %w", ctx.Err())
+	}
+	runErr := hungListUsers(ctx)
+
+	db := &recordingDB{}
+	err := FailSyncRun(ctx, gen.New(db), 9, runErr, SyncErrorKindAPI)
+	var timeoutErr *SyncRunTimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Timeout != 20*time.Millisecond {
+		t.Fatalf("FailSyncRun() = %v, want a SyncRunTimeoutError", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("FailSyncRun() = %v, want the run error kept", err)
+	}
+	if len(db.execs) != 1 || len(db.execs[0]) != 4 {
+		t.Fatalf("persisted %v, want one FailSyncRun exec", db.execs)
+	}
+	args := db.execs[0]
+	if args[1] != SyncStatusError || args[3] != SyncErrorKindTimeout {
+		t.Fatalf("persisted status/kind = %v/%v, want %s/%s", args[1], args[3], SyncStatusError, SyncErrorKindTimeout)
+	}
+	if msg, _ := args[2].(string); !strings.Contains(msg, "sync run exceeded its 20ms timeout") {
+		t.Fatalf("persisted message = %q, want the timeout named", msg)
+	}
+
+	// A caller cancel is still recorded as canceled.
+	canceledCtx, cancelRun := WithSyncRunTimeout(context.Background(), time.Hour)
+	cancelRun()
+	db = &recordingDB{}
+	_ = FailSyncRun(canceledCtx, gen.New(db), 9, canceledCtx.Err(), SyncErrorKindAPI)
+	if len(db.execs) != 1 || db.execs[0][1] != SyncStatusCanceled || db.execs[0][3] != SyncErrorKindContextCanceled {
+		t.Fatalf("persisted %v, want canceled/context_canceled", db.execs)
+	}
+}
+
+func TestConnectorRegistrySyncRunTimeout(t *testing.T) {
+	t.Parallel()
+
+	var unset *ConnectorRegistry
+	if got := unset.SyncRunTimeout("okta"); got != 0 {
+		t.Fatalf("nil registry SyncRunTimeout() = %s, want 0", got)
+	}
+
+	reg := NewRegistry()
+	reg.SetSyncRunTimeouts(map[string]time.Duration{"default": 2 * time.Hour, "Okta": 6 * time.Hour, "vault": 0})
+	for kind, want := range map[string]time.Duration{"okta": 6 * time.Hour, "github": 2 * time.Hour, "vault": 0} {
+		if got := reg.SyncRunTimeout(kind); got != want {
+			t.Fatalf("SyncRunTimeout(%q) = %s, want %s", kind, got, want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// SyncWarning is a non-fatal problem recorded against a sync run. Warnings never change
+// the run outcome; they are persisted next to it so degraded runs can be told apart
+// from clean ones.
+type SyncWarning struct {
+	Source  string    `json:"source"`
+	Stage   string    `json:"stage"`
+	Message string    `json:"message"`
+	At      time.Time `json:"at"`
+}
+
+// WarningReporter wraps a connector's report func and collects warnings for the run.
+type WarningReporter struct {
+	report func(Event)
+
+	mu       sync.Mutex
+	warnings []SyncWarning
+}
+
+func NewWarningReporter(report func(Event)) *WarningReporter {
+	return &WarningReporter{report: report}
+}
+
+// Report forwards e unchanged.
+func (r *WarningReporter) Report(e Event) {
+	if r.report != nil {
+		r.report(e)
+	}
+}
+
+// ReportWarning records e as a warning and forwards it as a progress message.
+// Err is folded into the message and cleared so reporters do not treat the event as a failure.
+func (r *WarningReporter) ReportWarning(e Event) {
+	message := strings.TrimSpace(e.Message)
+	if message == "" && e.Err != nil {
+		message = e.Err.Error()
+	}
+	at := e.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	r.mu.Lock()
+	r.warnings = append(r.warnings, SyncWarning{
+		Source:  strings.TrimSpace(e.Source),
+		Stage:   strings.TrimSpace(e.Stage),
+		Message: message,
+		At:      at.UTC(),
+	})
+	r.mu.Unlock()
+
+	e.Message = "warning: " + message
+	e.Err = nil
+	r.Report(e)
+}
+
+// Warnings returns a copy of the warnings recorded so far.
+func (r *WarningReporter) Warnings() []SyncWarning {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]SyncWarning(nil), r.warnings...)
+}
+
+// PersistSyncRunWarnings appends warnings to the sync run, keeping any recorded while the run
+// was finalized. It is a no-op when there is nothing to record, and it does not touch the run
+// status, so it is safe to call after the run finished.
+func PersistSyncRunWarnings(ctx context.Context, q *gen.Queries, runID int64, warnings []SyncWarning) error {
+	if len(warnings) == 0 {
+		return nil
+	}
+	if q == nil {
+		return errors.New("sync run warnings could not be persisted: queries is nil")
+	}
+	if runID == 0 {
+		return errors.New("sync run warnings could not be persisted: run id is zero")
+	}
+
+	persistCtx := ctx
+	if persistCtx == nil || persistCtx.Err() != nil {
+		var cancel context.CancelFunc
+		persistCtx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+	}
+
+	if err := q.AppendSyncRunWarnings(persistCtx, gen.AppendSyncRunWarningsParams{
+		ID:       runID,
+		Warnings: MarshalJSON(warnings),
+	}); err != nil {
+		wrapped := fmt.Errorf("record sync run %d warnings: %w", runID, err)
+		slog.Error("failed to persist sync run warnings", "run_id", runID, "err", wrapped)
+		return wrapped
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+type recordingDB struct {
+	sqls  []string
+	execs [][]interface{}
+}
+
+func (r *recordingDB) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	r.sqls = append(r.sqls, sql)
+	r.execs = append(r.execs, args)
+	return pgconn.CommandTag{}, nil
+}
+
+func (r *recordingDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	panic("unexpected Query call")
+}
+
+func (r *recordingDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	panic("unexpected QueryRow call")
+}
+
+func TestWarningReporterForwardsWarningsWithoutError(t *testing.T) {
+	var forwarded []Event
+	reporter := NewWarningReporter(func(e Event) { forwarded = append(forwarded, e) })
+
+	reporter.Report(Event{Source: "github", Stage: "list-members", Message: "found 3 members"})
+	reporter.ReportWarning(Event{Source: "github", Stage: "resolve-emails", Err: errors.New("scim unavailable")})
+
+	if len(forwarded) != 2 {
+		t.Fatalf("forwarded %d events, want 2", len(forwarded))
+	}
+	for _, e := range forwarded {
+		if e.Err != nil {
+			t.Fatalf("forwarded event %+v carries an error; warnings must not fail the run", e)
+		}
+	}
+	if forwarded[1].Message != "warning: scim unavailable" {
+		t.Fatalf("forwarded warning message = %q", forwarded[1].Message)
+	}
+
+	warnings := reporter.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %+v, want 1", warnings)
+	}
+	if warnings[0].Source != "github" || warnings[0].Stage != "resolve-emails" || warnings[0].Message != "scim unavailable" || warnings[0].At.IsZero() {
+		t.Fatalf("warning = %+v", warnings[0])
+	}
+}
+
+func TestPersistSyncRunWarningsStoresJSONWithoutChangingStatus(t *testing.T) {
+	db := &recordingDB{}
+	q := gen.New(db)
+
+	reporter := NewWarningReporter(nil)
+	reporter.ReportWarning(Event{Source: "github", Stage: "resolve-emails", Message: "saml identity lookup failed"})
+	reporter.ReportWarning(Event{Source: "github", Stage: "resolve-emails", Message: "scim user lookup failed"})
+
+	if err := PersistSyncRunWarnings(context.Background(), q, 42, reporter.Warnings()); err != nil {
+		t.Fatalf("PersistSyncRunWarnings() error = %v", err)
+	}
+
+	if len(db.execs) != 1 {
+		t.Fatalf("exec calls = %d, want 1", len(db.execs))
+	}
+	if strings.Contains(db.sqls[0], "status") {
+		t.Fatalf("warnings update must not touch run status: %s", db.sqls[0])
+	}
+	var runID int64
+	var payload []byte
+	for _, arg := range db.execs[0] {
+		switch v := arg.(type) {
+		case int64:
+			runID = v
+		case []byte:
+			payload = v
+		}
+	}
+	if runID != 42 {
+		t.Fatalf("warnings exec args = %v, want run 42", db.execs[0])
+	}
+	var stored []SyncWarning
+	if err := json.Unmarshal(payload, &stored); err != nil {
+		t.Fatalf("unmarshal warnings: %v", err)
+	}
+	if len(stored) != 2 || stored[1].Message != "scim user lookup failed" {
+		t.Fatalf("stored warnings = %+v", stored)
+	}
+}
+
+func TestPersistSyncRunWarningsSkipsEmpty(t *testing.T) {
+	db := &recordingDB{}
+	if err := PersistSyncRunWarnings(context.Background(), gen.New(db), 42, nil); err != nil {
+		t.Fatalf("PersistSyncRunWarnings() error = %v", err)
+	}
+	if len(db.execs) != 0 {
+		t.Fatalf("exec calls = %d, want 0", len(db.execs))
+	}
+}
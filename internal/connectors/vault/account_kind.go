@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/matching"
 )
 
 func vaultEntityAccountKind(entity Entity) string {
@@ -32,7 +33,7 @@ func vaultEntityAccountKind(entity Entity) string {
 }
 
 func vaultHasStrongHumanSignal(entity Entity) bool {
-	email := normalizeEmail(bestEntityEmail(entity))
+	email := matching.NormalizeEmail(bestEntityEmail(entity))
 	if email != "" {
 		emailSignal := registry.ClassifyKindFromSignals(email)
 		if emailSignal == registry.AccountKindUnknown {
@@ -55,7 +55,7 @@ func (d *Definition) SourceName(cfg any) string {
 }
 
 func (d *Definition) DefaultSubtitle() string {
-	return "Identity entities, policies, mounts, and auth roles."
+	return "Identity entities, policies, mounts, auth roles, and AppRole secret-ids."
 }
 
 func (d *Definition) ConfiguredSubtitle(cfg any) string {
@@ -94,6 +94,24 @@ func (d *Definition) NewIntegration(cfg any) (registry.Integration, error) {
 	return NewVaultIntegration(client, c.SourceName(), c.ScanAuthRoles), nil
 }
 
+// CredentialKinds documents the credential kinds the integration syncs.
+func (d *Definition) CredentialKinds() []registry.CredentialKindInfo {
+	return []registry.CredentialKindInfo{
+		{
+			Kind:             "vault_approle_secret_id",
+			Label:            "Vault AppRole secret ID",
+			Description:      "A secret ID that, with a role ID, lets a workload log in to Vault through the AppRole auth method.",
+			RotationGuidance: "Generate a new secret ID, deliver it to the workload, then destroy the old one. Set secret_id_ttl and secret_id_num_uses so IDs expire.",
+		},
+		{
+			Kind:             "vault_token",
+			Label:            "Vault token",
+			Description:      "A Vault token whose policies grant access to secrets.",
+			RotationGuidance: "Revoke long-lived and orphan tokens, and have workloads log in through an auth method that issues short-TTL tokens.",
+		},
+	}
+}
+
 type vaultMetrics struct{}
 
 func (m *vaultMetrics) FetchMetrics(ctx context.Context, q *gen.Queries, sourceName string) (registry.ConnectorMetrics, error) {
@@ -12,12 +12,19 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/open-sspm/open-sspm/internal/connectors/registry"
 	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/matching"
 )
 
 const (
 	vaultUserBatchSize        = 1000
 	vaultEntitlementBatchSize = 5000
 	vaultAssetBatchSize       = 1000
+	vaultCredentialBatchSize  = 1000
+	vaultAuditEventBatchSize  = 1000
+
+	// vaultLongLivedTokenTTL is the creation TTL at or above which a token is inventoried
+	// as a credential; shorter-lived tokens are login sessions and churn every sync.
+	vaultLongLivedTokenTTL = 24 * time.Hour
 )
 
 type VaultIntegration struct {
@@ -52,6 +59,33 @@ type vaultAssetUpsertRow struct {
 	RawJSON          []byte
 }
 
+type vaultCredentialUpsertRow struct {
+	AssetRefKind       string
+	AssetRefExternalID string
+	CredentialKind     string
+	ExternalID         string
+	DisplayName        string
+	Fingerprint        string
+	ScopeJSON          []byte
+	Status             string
+	CreatedAtSource    pgtype.Timestamptz
+	ExpiresAtSource    pgtype.Timestamptz
+	LastUsedAtSource   pgtype.Timestamptz
+	RawJSON            []byte
+}
+
+type vaultCredentialAuditEventUpsertRow struct {
+	EventExternalID      string
+	EventType            string
+	EventTime            pgtype.Timestamptz
+	TargetKind           string
+	TargetExternalID     string
+	TargetDisplayName    string
+	CredentialKind       string
+	CredentialExternalID string
+	RawJSON              []byte
+}
+
 func NewVaultIntegration(client *Client, sourceName string, scanAuthRoles bool) *VaultIntegration {
 	name := strings.TrimSpace(sourceName)
 	if name == "" {
@@ -77,9 +111,12 @@ func (i *VaultIntegration) InitEvents() []registry.Event {
 		{Source: "vault", Stage: "list-policies", Current: 0, Total: 1, Message: "listing Vault ACL policies"},
 		{Source: "vault", Stage: "list-mounts", Current: 0, Total: 1, Message: "listing Vault auth and secrets mounts"},
 		{Source: "vault", Stage: "list-auth-roles", Current: 0, Total: 1, Message: "listing Vault auth roles"},
+		{Source: "vault", Stage: "list-credentials", Current: 0, Total: 1, Message: "listing Vault AppRole secret-ids and tokens"},
 		{Source: "vault", Stage: "write-users", Current: 0, Total: registry.UnknownTotal, Message: "writing Vault principals"},
 		{Source: "vault", Stage: "write-entitlements", Current: 0, Total: registry.UnknownTotal, Message: "writing Vault policy and membership entitlements"},
 		{Source: "vault", Stage: "write-assets", Current: 0, Total: registry.UnknownTotal, Message: "writing Vault mounts and auth role assets"},
+		{Source: "vault", Stage: "write-credentials", Current: 0, Total: registry.UnknownTotal, Message: "writing Vault secret-id and token credentials"},
+		{Source: "vault", Stage: "write-audit-events", Current: 0, Total: registry.UnknownTotal, Message: "writing Vault credential audit events"},
 	}
 }
 
@@ -87,13 +124,18 @@ func (i *VaultIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpoo
 	started := time.Now()
 	slog.Info("syncing Vault", "source", i.sourceName)
 
-	runID, err := q.CreateSyncRun(ctx, gen.CreateSyncRunParams{
+	runID, err := registry.CreateSyncRun(ctx, q, gen.CreateSyncRunParams{
 		SourceKind: "vault",
 		SourceName: i.sourceName,
 	})
 	if err != nil {
 		return err
 	}
+	warnings := registry.NewWarningReporter(report)
+	defer func() {
+		_ = registry.PersistSyncRunWarnings(ctx, q, runID, warnings.Warnings())
+	}()
+	holds := registry.NewExpiryHolds()
 
 	entities, err := i.client.ListEntities(ctx)
 	if err != nil {
@@ -123,14 +165,7 @@ func (i *VaultIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpoo
 
 	policies, err := i.client.ListACLPolicies(ctx)
 	if err != nil {
-		slog.Warn("vault ACL policy listing failed; continuing without policy inventory", "source", i.sourceName, "err", err)
-		report(registry.Event{
-			Source:  "vault",
-			Stage:   "list-policies",
-			Current: 1,
-			Total:   1,
-			Message: fmt.Sprintf("skipped ACL policy inventory: %v", err),
-		})
+		warnings.ReportWarning(registry.Event{Source: "vault", Stage: "list-policies", Message: fmt.Sprintf("skipped ACL policy inventory: %v", err)})
 		policies = nil
 	} else {
 		report(registry.Event{
@@ -167,6 +202,12 @@ func (i *VaultIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpoo
 			report(registry.Event{Source: "vault", Stage: "list-auth-roles", Message: err.Error(), Err: err})
 			return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindAPI)
 		}
+		tokenRoles, err := i.client.ListTokenRoles(ctx, authMounts)
+		if err != nil {
+			report(registry.Event{Source: "vault", Stage: "list-auth-roles", Message: err.Error(), Err: err})
+			return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindAPI)
+		}
+		authRoles = append(authRoles, tokenRoles...)
 	}
 	report(registry.Event{
 		Source:  "vault",
@@ -176,25 +217,74 @@ func (i *VaultIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpoo
 		Message: fmt.Sprintf("found %d auth roles", len(authRoles)),
 	})
 
+	// Secret-id and token inventory need broader policies than the rest of the sync, so a failed
+	// listing is a warning. The credentials it would have confirmed are held from expiry.
+	var secretIDs []AppRoleSecretID
+	var tokens []Token
+	if i.scanAuthRoles {
+		secretIDs, err = i.client.ListAppRoleSecretIDs(ctx, authRoles)
+		if err != nil {
+			warnings.ReportWarning(registry.Event{Source: "vault", Stage: "list-credentials", Message: fmt.Sprintf("skipped AppRole secret-id inventory: %v", err)})
+			holds.HoldCredentials("vault_approle_secret_id", "")
+			secretIDs = nil
+		}
+		tokens, err = i.client.ListTokens(ctx)
+		if err != nil {
+			warnings.ReportWarning(registry.Event{Source: "vault", Stage: "list-credentials", Message: fmt.Sprintf("skipped token inventory: %v", err)})
+			holds.HoldCredentials("vault_token", "")
+			tokens = nil
+		}
+	}
+	report(registry.Event{
+		Source:  "vault",
+		Stage:   "list-credentials",
+		Current: 1,
+		Total:   1,
+		Message: fmt.Sprintf("found %d AppRole secret-ids and %d tokens", len(secretIDs), len(tokens)),
+	})
+
 	accountRows := buildVaultAccountRows(entities, groups, authRoles)
-	if err := upsertVaultAccounts(ctx, q, report, runID, i.sourceName, accountRows); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return upsertVaultAccounts(ctx, qtx, report, runID, i.sourceName, accountRows)
+	}); err != nil {
 		report(registry.Event{Source: "vault", Stage: "write-users", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
 	entitlementRows := buildVaultEntitlementRows(entities, groups, authRoles)
-	if err := upsertVaultEntitlements(ctx, q, report, runID, i.sourceName, entitlementRows); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return upsertVaultEntitlements(ctx, qtx, report, runID, i.sourceName, entitlementRows)
+	}); err != nil {
 		report(registry.Event{Source: "vault", Stage: "write-entitlements", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
 	assetRows := buildVaultAssetRows(authMounts, secretsMounts, authRoles)
-	if err := upsertVaultAssets(ctx, q, report, runID, i.sourceName, assetRows); err != nil {
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return upsertVaultAssets(ctx, qtx, report, runID, i.sourceName, assetRows)
+	}); err != nil {
 		report(registry.Event{Source: "vault", Stage: "write-assets", Message: err.Error(), Err: err})
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
-	if err := registry.FinalizeAppRun(ctx, q, pool, runID, "vault", i.sourceName, time.Since(started), false); err != nil {
+	now := time.Now().UTC()
+	credentialRows := buildVaultCredentialRows(secretIDs, tokens, now)
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return upsertVaultCredentials(ctx, qtx, report, runID, i.sourceName, credentialRows)
+	}); err != nil {
+		report(registry.Event{Source: "vault", Stage: "write-credentials", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
+	}
+
+	auditEventRows := buildVaultCredentialAuditEventRows(secretIDs, tokens)
+	if err := registry.RunWriteStage(ctx, q, pool, func(qtx *gen.Queries) error {
+		return upsertVaultCredentialAuditEvents(ctx, qtx, report, i.sourceName, auditEventRows)
+	}); err != nil {
+		report(registry.Event{Source: "vault", Stage: "write-audit-events", Message: err.Error(), Err: err})
+		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
+	}
+
+	if err := registry.FinalizeAppRun(ctx, q, pool, runID, "vault", i.sourceName, time.Since(started), false, holds); err != nil {
 		return registry.FailSyncRun(ctx, q, runID, err, registry.SyncErrorKindDB)
 	}
 
@@ -210,6 +300,8 @@ func (i *VaultIntegration) Run(ctx context.Context, q *gen.Queries, pool *pgxpoo
 		"accounts", len(accountRows),
 		"entitlements", len(entitlementRows),
 		"assets", len(assetRows),
+		"credentials", len(credentialRows),
+		"audit_events", len(auditEventRows),
 	)
 	return nil
 }
@@ -478,6 +570,138 @@ func buildVaultAssetRows(authMounts []AuthMount, secretsMounts []SecretsMount, a
 	return rows
 }
 
+// buildVaultCredentialRows maps AppRole secret-ids and long-lived tokens to credential rows.
+// Secret-ids and tokens issued without a TTL keep an empty expiry so the credential views
+// can flag them as never expiring.
+func buildVaultCredentialRows(secretIDs []AppRoleSecretID, tokens []Token, now time.Time) []vaultCredentialUpsertRow {
+	seen := make(map[string]struct{})
+	rows := make([]vaultCredentialUpsertRow, 0, len(secretIDs)+len(tokens))
+
+	add := func(row vaultCredentialUpsertRow) {
+		row.ExternalID = strings.TrimSpace(row.ExternalID)
+		if row.ExternalID == "" {
+			return
+		}
+		key := row.CredentialKind + "::" + row.ExternalID
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		rows = append(rows, row)
+	}
+
+	for _, secretID := range secretIDs {
+		roleExternalID := vaultRoleExternalID(vaultAuthTypeAppRole, secretID.MountPath, secretID.RoleName)
+		if roleExternalID == "" {
+			continue
+		}
+		expiresAt := vaultTimestamptz(secretID.ExpiresAt)
+		add(vaultCredentialUpsertRow{
+			AssetRefKind:       "app_asset",
+			AssetRefExternalID: "vault_auth_role:" + roleExternalID,
+			CredentialKind:     "vault_approle_secret_id",
+			ExternalID:         secretID.Accessor,
+			DisplayName:        firstNonEmptyString(secretID.Metadata["name"], secretID.RoleName+" secret-id"),
+			Fingerprint:        secretID.Accessor,
+			ScopeJSON: registry.MarshalJSON(map[string]any{
+				"mount":       secretID.MountPath,
+				"role":        secretID.RoleName,
+				"cidr_list":   secretID.CIDRList,
+				"num_uses":    secretID.NumUses,
+				"ttl_seconds": secretID.TTLSeconds,
+			}),
+			Status:          vaultCredentialStatus(expiresAt, now),
+			CreatedAtSource: vaultTimestamptz(secretID.CreatedAt),
+			ExpiresAtSource: expiresAt,
+			RawJSON:         secretID.RawJSON,
+		})
+	}
+
+	for _, token := range tokens {
+		if !isVaultLongLivedToken(token) {
+			continue
+		}
+		mountPath := vaultTokenMountPath(token.Path)
+		expiresAt := vaultTimestamptz(token.ExpiresAt)
+		add(vaultCredentialUpsertRow{
+			AssetRefKind:       "app_asset",
+			AssetRefExternalID: "vault_auth_mount:" + mountPath,
+			CredentialKind:     "vault_token",
+			ExternalID:         token.Accessor,
+			DisplayName:        firstNonEmptyString(token.DisplayName, token.Accessor),
+			Fingerprint:        token.Accessor,
+			ScopeJSON: registry.MarshalJSON(map[string]any{
+				"policies":  token.Policies,
+				"path":      token.Path,
+				"entity_id": token.EntityID,
+				"orphan":    token.Orphan,
+			}),
+			Status:           vaultCredentialStatus(expiresAt, now),
+			CreatedAtSource:  vaultTimestamptz(token.CreatedAt),
+			ExpiresAtSource:  expiresAt,
+			LastUsedAtSource: vaultTimestamptz(token.LastRenewalAt),
+			RawJSON:          token.RawJSON,
+		})
+	}
+
+	return rows
+}
+
+// buildVaultCredentialAuditEventRows derives creation events from secret-id and token metadata.
+// Vault's audit devices write to files or sockets that are not readable through the API, so
+// issuance is the only lifecycle event the connector can observe.
+func buildVaultCredentialAuditEventRows(secretIDs []AppRoleSecretID, tokens []Token) []vaultCredentialAuditEventUpsertRow {
+	rows := make([]vaultCredentialAuditEventUpsertRow, 0, len(secretIDs)+len(tokens))
+
+	for _, secretID := range secretIDs {
+		accessor := strings.TrimSpace(secretID.Accessor)
+		roleExternalID := vaultRoleExternalID(vaultAuthTypeAppRole, secretID.MountPath, secretID.RoleName)
+		if accessor == "" || roleExternalID == "" || secretID.CreatedAt.IsZero() {
+			continue
+		}
+		rows = append(rows, vaultCredentialAuditEventUpsertRow{
+			EventExternalID:      "vault_approle_secret_id:" + accessor + ":created",
+			EventType:            "vault_approle_secret_id.created",
+			EventTime:            vaultTimestamptz(secretID.CreatedAt),
+			TargetKind:           "vault_auth_role",
+			TargetExternalID:     roleExternalID,
+			TargetDisplayName:    secretID.RoleName,
+			CredentialKind:       "vault_approle_secret_id",
+			CredentialExternalID: accessor,
+			RawJSON: registry.MarshalJSON(map[string]any{
+				"mount":         secretID.MountPath,
+				"role":          secretID.RoleName,
+				"creation_time": secretID.CreatedAt.Format(time.RFC3339),
+			}),
+		})
+	}
+
+	for _, token := range tokens {
+		accessor := strings.TrimSpace(token.Accessor)
+		if accessor == "" || token.CreatedAt.IsZero() || !isVaultLongLivedToken(token) {
+			continue
+		}
+		mountPath := vaultTokenMountPath(token.Path)
+		rows = append(rows, vaultCredentialAuditEventUpsertRow{
+			EventExternalID:      "vault_token:" + accessor + ":created",
+			EventType:            "vault_token.created",
+			EventTime:            vaultTimestamptz(token.CreatedAt),
+			TargetKind:           "vault_auth_mount",
+			TargetExternalID:     mountPath,
+			TargetDisplayName:    mountPath,
+			CredentialKind:       "vault_token",
+			CredentialExternalID: accessor,
+			RawJSON: registry.MarshalJSON(map[string]any{
+				"path":          token.Path,
+				"display_name":  token.DisplayName,
+				"creation_time": token.CreatedAt.Format(time.RFC3339),
+			}),
+		})
+	}
+
+	return rows
+}
+
 func upsertVaultAccounts(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, sourceName string, rows []vaultAccountUpsertRow) error {
 	report(registry.Event{Source: "vault", Stage: "write-users", Current: 0, Total: int64(len(rows)), Message: fmt.Sprintf("writing %d principals", len(rows))})
 	if len(rows) == 0 {
@@ -502,7 +726,7 @@ func upsertVaultAccounts(ctx context.Context, q *gen.Queries, report func(regist
 			emails = append(emails, strings.ToLower(strings.TrimSpace(row.Email)))
 			displayNames = append(displayNames, row.DisplayName)
 			accountKinds = append(accountKinds, registry.NormalizeAccountKind(row.AccountKind))
-			rawJSONs = append(rawJSONs, registry.NormalizeJSON(row.RawJSON))
+			rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(row.RawJSON))
 			lastLoginAts = append(lastLoginAts, pgtype.Timestamptz{})
 			lastLoginIPs = append(lastLoginIPs, "")
 			lastLoginRegions = append(lastLoginRegions, "")
@@ -551,13 +775,15 @@ func upsertVaultEntitlements(ctx context.Context, q *gen.Queries, report func(re
 		resources := make([]string, 0, len(batch))
 		permissions := make([]string, 0, len(batch))
 		rawJSONs := make([][]byte, 0, len(batch))
+		privilegeLevels := make([]int16, 0, len(batch))
 
 		for _, row := range batch {
 			appUserExternalIDs = append(appUserExternalIDs, row.AppUserExternalID)
 			kinds = append(kinds, row.Kind)
 			resources = append(resources, row.Resource)
 			permissions = append(permissions, row.Permission)
-			rawJSONs = append(rawJSONs, registry.NormalizeJSON(row.RawJSON))
+			rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(row.RawJSON))
+			privilegeLevels = append(privilegeLevels, int16(vaultPrivilegeMapping.Level(row.Kind, row.Permission, vaultPolicyName(row.Resource))))
 		}
 
 		if _, err := q.UpsertEntitlementsBulkBySource(ctx, gen.UpsertEntitlementsBulkBySourceParams{
@@ -569,6 +795,7 @@ func upsertVaultEntitlements(ctx context.Context, q *gen.Queries, report func(re
 			Resources:          resources,
 			Permissions:        permissions,
 			RawJsons:           rawJSONs,
+			PrivilegeLevels:    privilegeLevels,
 		}); err != nil {
 			return err
 		}
@@ -612,7 +839,7 @@ func upsertVaultAssets(ctx context.Context, q *gen.Queries, report func(registry
 			statuses = append(statuses, row.Status)
 			createdAtSources = append(createdAtSources, pgtype.Timestamptz{})
 			updatedAtSources = append(updatedAtSources, pgtype.Timestamptz{})
-			rawJSONs = append(rawJSONs, registry.NormalizeJSON(row.RawJSON))
+			rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(row.RawJSON))
 		}
 
 		if _, err := q.UpsertAppAssetsBulkBySource(ctx, gen.UpsertAppAssetsBulkBySourceParams{
@@ -643,6 +870,149 @@ func upsertVaultAssets(ctx context.Context, q *gen.Queries, report func(registry
 	return nil
 }
 
+func upsertVaultCredentials(ctx context.Context, q *gen.Queries, report func(registry.Event), runID int64, sourceName string, rows []vaultCredentialUpsertRow) error {
+	report(registry.Event{Source: "vault", Stage: "write-credentials", Current: 0, Total: int64(len(rows)), Message: fmt.Sprintf("writing %d credential rows", len(rows))})
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for start := 0; start < len(rows); start += vaultCredentialBatchSize {
+		end := min(start+vaultCredentialBatchSize, len(rows))
+		batch := rows[start:end]
+
+		assetRefKinds := make([]string, 0, len(batch))
+		assetRefExternalIDs := make([]string, 0, len(batch))
+		credentialKinds := make([]string, 0, len(batch))
+		externalIDs := make([]string, 0, len(batch))
+		displayNames := make([]string, 0, len(batch))
+		fingerprints := make([]string, 0, len(batch))
+		scopeJSONs := make([][]byte, 0, len(batch))
+		statuses := make([]string, 0, len(batch))
+		createdAtSources := make([]pgtype.Timestamptz, 0, len(batch))
+		expiresAtSources := make([]pgtype.Timestamptz, 0, len(batch))
+		lastUsedAtSources := make([]pgtype.Timestamptz, 0, len(batch))
+		emptyStrings := make([]string, 0, len(batch))
+		rawJSONs := make([][]byte, 0, len(batch))
+
+		for _, row := range batch {
+			assetRefKinds = append(assetRefKinds, row.AssetRefKind)
+			assetRefExternalIDs = append(assetRefExternalIDs, row.AssetRefExternalID)
+			credentialKinds = append(credentialKinds, row.CredentialKind)
+			externalIDs = append(externalIDs, row.ExternalID)
+			displayNames = append(displayNames, row.DisplayName)
+			fingerprints = append(fingerprints, row.Fingerprint)
+			scopeJSONs = append(scopeJSONs, registry.NormalizeJSON(row.ScopeJSON))
+			statuses = append(statuses, row.Status)
+			createdAtSources = append(createdAtSources, row.CreatedAtSource)
+			expiresAtSources = append(expiresAtSources, row.ExpiresAtSource)
+			lastUsedAtSources = append(lastUsedAtSources, row.LastUsedAtSource)
+			emptyStrings = append(emptyStrings, "")
+			rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(row.RawJSON))
+		}
+
+		if _, err := q.UpsertCredentialArtifactsBulkBySource(ctx, gen.UpsertCredentialArtifactsBulkBySourceParams{
+			SourceKind:             "vault",
+			SourceName:             sourceName,
+			SeenInRunID:            runID,
+			AssetRefKinds:          assetRefKinds,
+			AssetRefExternalIds:    assetRefExternalIDs,
+			CredentialKinds:        credentialKinds,
+			ExternalIds:            externalIDs,
+			DisplayNames:           displayNames,
+			Fingerprints:           fingerprints,
+			ScopeJsons:             scopeJSONs,
+			Statuses:               statuses,
+			CreatedAtSources:       createdAtSources,
+			ExpiresAtSources:       expiresAtSources,
+			LastUsedAtSources:      lastUsedAtSources,
+			CreatedByKinds:         emptyStrings,
+			CreatedByExternalIds:   emptyStrings,
+			CreatedByDisplayNames:  emptyStrings,
+			ApprovedByKinds:        emptyStrings,
+			ApprovedByExternalIds:  emptyStrings,
+			ApprovedByDisplayNames: emptyStrings,
+			RawJsons:               rawJSONs,
+		}); err != nil {
+			return err
+		}
+
+		report(registry.Event{
+			Source:  "vault",
+			Stage:   "write-credentials",
+			Current: int64(end),
+			Total:   int64(len(rows)),
+			Message: fmt.Sprintf("credentials %d/%d", end, len(rows)),
+		})
+	}
+
+	return nil
+}
+
+func upsertVaultCredentialAuditEvents(ctx context.Context, q *gen.Queries, report func(registry.Event), sourceName string, rows []vaultCredentialAuditEventUpsertRow) error {
+	report(registry.Event{Source: "vault", Stage: "write-audit-events", Current: 0, Total: int64(len(rows)), Message: fmt.Sprintf("writing %d credential audit events", len(rows))})
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for start := 0; start < len(rows); start += vaultAuditEventBatchSize {
+		end := min(start+vaultAuditEventBatchSize, len(rows))
+		batch := rows[start:end]
+
+		eventExternalIDs := make([]string, 0, len(batch))
+		eventTypes := make([]string, 0, len(batch))
+		eventTimes := make([]pgtype.Timestamptz, 0, len(batch))
+		emptyStrings := make([]string, 0, len(batch))
+		targetKinds := make([]string, 0, len(batch))
+		targetExternalIDs := make([]string, 0, len(batch))
+		targetDisplayNames := make([]string, 0, len(batch))
+		credentialKinds := make([]string, 0, len(batch))
+		credentialExternalIDs := make([]string, 0, len(batch))
+		rawJSONs := make([][]byte, 0, len(batch))
+
+		for _, row := range batch {
+			eventExternalIDs = append(eventExternalIDs, row.EventExternalID)
+			eventTypes = append(eventTypes, row.EventType)
+			eventTimes = append(eventTimes, row.EventTime)
+			emptyStrings = append(emptyStrings, "")
+			targetKinds = append(targetKinds, row.TargetKind)
+			targetExternalIDs = append(targetExternalIDs, row.TargetExternalID)
+			targetDisplayNames = append(targetDisplayNames, row.TargetDisplayName)
+			credentialKinds = append(credentialKinds, row.CredentialKind)
+			credentialExternalIDs = append(credentialExternalIDs, row.CredentialExternalID)
+			rawJSONs = append(rawJSONs, registry.NormalizeRawJSON(row.RawJSON))
+		}
+
+		if _, err := q.UpsertCredentialAuditEventsBulkBySource(ctx, gen.UpsertCredentialAuditEventsBulkBySourceParams{
+			SourceKind:            "vault",
+			SourceName:            sourceName,
+			EventExternalIds:      eventExternalIDs,
+			EventTypes:            eventTypes,
+			EventTimes:            eventTimes,
+			ActorKinds:            emptyStrings,
+			ActorExternalIds:      emptyStrings,
+			ActorDisplayNames:     emptyStrings,
+			TargetKinds:           targetKinds,
+			TargetExternalIds:     targetExternalIDs,
+			TargetDisplayNames:    targetDisplayNames,
+			CredentialKinds:       credentialKinds,
+			CredentialExternalIds: credentialExternalIDs,
+			RawJsons:              rawJSONs,
+		}); err != nil {
+			return err
+		}
+
+		report(registry.Event{
+			Source:  "vault",
+			Stage:   "write-audit-events",
+			Current: int64(end),
+			Total:   int64(len(rows)),
+			Message: fmt.Sprintf("audit events %d/%d", end, len(rows)),
+		})
+	}
+
+	return nil
+}
+
 func bestEntityEmail(entity Entity) string {
 	metadataCandidates := []string{
 		entity.Metadata["email"],
@@ -652,7 +1022,7 @@ func bestEntityEmail(entity Entity) string {
 		entity.Metadata["user_principal_name"],
 	}
 	for _, candidate := range metadataCandidates {
-		if email := normalizeEmail(candidate); email != "" {
+		if email := matching.NormalizeEmail(candidate); email != "" {
 			return email
 		}
 	}
@@ -665,7 +1035,7 @@ func bestEntityEmail(entity Entity) string {
 			alias.Name,
 		}
 		for _, candidate := range aliasCandidates {
-			if email := normalizeEmail(candidate); email != "" {
+			if email := matching.NormalizeEmail(candidate); email != "" {
 				return email
 			}
 		}
@@ -673,21 +1043,6 @@ func bestEntityEmail(entity Entity) string {
 	return ""
 }
 
-func normalizeEmail(raw string) string {
-	email := strings.ToLower(strings.TrimSpace(raw))
-	if strings.Count(email, "@") != 1 {
-		return ""
-	}
-	parts := strings.Split(email, "@")
-	if len(parts) != 2 {
-		return ""
-	}
-	if strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
-		return ""
-	}
-	return email
-}
-
 func dedupeStringSlice(values []string) []string {
 	if len(values) == 0 {
 		return nil
@@ -739,3 +1094,34 @@ func vaultRoleExternalID(authType, mountPath, roleName string) string {
 	}
 	return "role:" + authType + ":" + mountPath + ":" + roleName
 }
+
+func isVaultLongLivedToken(token Token) bool {
+	if token.ExpiresAt.IsZero() {
+		return true
+	}
+	return time.Duration(token.CreationTTLSeconds)*time.Second >= vaultLongLivedTokenTTL
+}
+
+// vaultTokenMountPath returns the auth mount a token was issued from, based on its
+// creation path (for example "auth/approle/login" -> "approle").
+func vaultTokenMountPath(path string) string {
+	parts := strings.Split(strings.Trim(strings.TrimSpace(path), "/"), "/")
+	if len(parts) >= 2 && parts[0] == "auth" && strings.TrimSpace(parts[1]) != "" {
+		return strings.TrimSpace(parts[1])
+	}
+	return vaultAuthTypeToken
+}
+
+func vaultCredentialStatus(expiresAt pgtype.Timestamptz, now time.Time) string {
+	if expiresAt.Valid && expiresAt.Time.UTC().Before(now.UTC()) {
+		return "expired"
+	}
+	return "active"
+}
+
+func vaultTimestamptz(t time.Time) pgtype.Timestamptz {
+	if t.IsZero() {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: t.UTC(), Valid: true}
+}
@@ -1,8 +1,18 @@
 package vault
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
 )
 
 func TestBuildVaultAccountRows(t *testing.T) {
@@ -145,3 +155,114 @@ func TestBuildVaultAssetRows(t *testing.T) {
 		t.Fatalf("expected one vault_auth_role row")
 	}
 }
+
+func TestBuildVaultCredentialRowsNonExpiringSecretID(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	secretIDs := []AppRoleSecretID{
+		{
+			Accessor:  "acc-forever",
+			RoleName:  "ci-role",
+			MountPath: "approle",
+			CreatedAt: now.Add(-400 * 24 * time.Hour),
+			RawJSON:   []byte(`{"secret_id_accessor":"acc-forever"}`),
+		},
+		{
+			Accessor:  "acc-expired",
+			RoleName:  "ci-role",
+			MountPath: "approle",
+			CreatedAt: now.Add(-48 * time.Hour),
+			ExpiresAt: now.Add(-24 * time.Hour),
+		},
+	}
+	tokens := []Token{
+		{Accessor: "tok-session", Path: "auth/userpass/login/alice", CreatedAt: now, ExpiresAt: now.Add(time.Hour), CreationTTLSeconds: 3600},
+		{Accessor: "tok-root", Path: "auth/token/root", CreatedAt: now.Add(-time.Hour)},
+	}
+
+	rows := buildVaultCredentialRows(secretIDs, tokens, now)
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 credential rows, got %d", len(rows))
+	}
+	byExternalID := make(map[string]vaultCredentialUpsertRow, len(rows))
+	for _, row := range rows {
+		byExternalID[row.ExternalID] = row
+	}
+
+	forever := byExternalID["acc-forever"]
+	if forever.CredentialKind != "vault_approle_secret_id" {
+		t.Fatalf("credential kind = %q, want vault_approle_secret_id", forever.CredentialKind)
+	}
+	if forever.ExpiresAtSource.Valid {
+		t.Fatalf("non-expiring secret-id should have no expiry, got %v", forever.ExpiresAtSource.Time)
+	}
+	if forever.Status != "active" {
+		t.Fatalf("status = %q, want active", forever.Status)
+	}
+	if forever.AssetRefExternalID != "vault_auth_role:role:approle:approle:ci-role" {
+		t.Fatalf("asset ref = %q", forever.AssetRefExternalID)
+	}
+	if got := byExternalID["acc-expired"].Status; got != "expired" {
+		t.Fatalf("expired secret-id status = %q, want expired", got)
+	}
+	if _, ok := byExternalID["tok-session"]; ok {
+		t.Fatalf("short-lived session token should not be inventoried")
+	}
+	if got := byExternalID["tok-root"].AssetRefExternalID; got != "vault_auth_mount:token" {
+		t.Fatalf("root token asset ref = %q, want vault_auth_mount:token", got)
+	}
+
+	events := buildVaultCredentialAuditEventRows(secretIDs, tokens)
+	if len(events) != 3 {
+		t.Fatalf("expected 3 audit events, got %d", len(events))
+	}
+}
+
+// execRecorder is a gen.DBTX that records the arguments of every Exec.
+type execRecorder struct {
+	args [][]any
+}
+
+func (r *execRecorder) Exec(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+	r.args = append(r.args, args)
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (r *execRecorder) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, errors.New("unexpected Query")
+}
+
+func (r *execRecorder) QueryRow(context.Context, string, ...any) pgx.Row {
+	return nil
+}
+
+func TestUpsertVaultCredentialsKeepsLargeScopeJSON(t *testing.T) {
+	t.Parallel()
+
+	policies := make([]string, 0, 20000)
+	for i := range 20000 {
+		policies = append(policies, fmt.Sprintf("policy-%05d", i))
+	}
+	scopeJSON, err := json.Marshal(map[string]any{"policies": policies})
+	if err != nil {
+		t.Fatalf("marshal scope: %v", err)
+	}
+	if int64(len(scopeJSON)) <= registry.MaxRawJSONBytes() {
+		t.Fatalf("fixture is %d bytes, want more than %d", len(scopeJSON), registry.MaxRawJSONBytes())
+	}
+
+	db := &execRecorder{}
+	rows := []vaultCredentialUpsertRow{{CredentialKind: "vault_token", ExternalID: "accessor-1", ScopeJSON: scopeJSON, RawJSON: []byte(`{"accessor":"accessor-1"}`)}}
+	if err := upsertVaultCredentials(context.Background(), gen.New(db), func(registry.Event) {}, 1, "vault.example.com", rows); err != nil {
+		t.Fatalf("upsertVaultCredentials() error = %v", err)
+	}
+	if len(db.args) != 1 {
+		t.Fatalf("Exec calls = %d, want 1", len(db.args))
+	}
+	// ScopeJsons is the tenth query argument.
+	stored := db.args[0][9].([][]byte)
+	if len(stored) != 1 || !bytes.Equal(stored[0], scopeJSON) {
+		t.Fatalf("stored scope JSON is %d bytes, want the original %d", len(stored[0]), len(scopeJSON))
+	}
+}
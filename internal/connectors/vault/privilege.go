@@ -0,0 +1,28 @@
+package vault
+
+import (
+	"strings"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+)
+
+// vaultPrivilegeMapping maps attached policies to canonical levels by policy name. Vault
+// policies are arbitrary HCL, so only the built-in and conventionally named policies are
+// classified; the rest stay unknown.
+var vaultPrivilegeMapping = registry.PrivilegeMapping{
+	{Kind: "vault_entity_policy", Role: "root", Level: registry.PrivilegeOwner},
+	{Kind: "vault_group_policy", Role: "root", Level: registry.PrivilegeOwner},
+	{Kind: "vault_auth_role_policy", Role: "root", Level: registry.PrivilegeOwner},
+	{Kind: "vault_entity_policy", Role: "*admin*", Level: registry.PrivilegeAdmin},
+	{Kind: "vault_group_policy", Role: "*admin*", Level: registry.PrivilegeAdmin},
+	{Kind: "vault_auth_role_policy", Role: "*admin*", Level: registry.PrivilegeAdmin},
+	{Kind: "vault_entity_policy", Role: "default", Level: registry.PrivilegeRead},
+	{Kind: "vault_group_policy", Role: "default", Level: registry.PrivilegeRead},
+	{Kind: "vault_auth_role_policy", Role: "default", Level: registry.PrivilegeRead},
+	{Kind: "vault_group_member", Level: registry.PrivilegeRead},
+}
+
+// vaultPolicyName returns the policy name of a vault_policy:<name> resource.
+func vaultPolicyName(resource string) string {
+	return strings.TrimPrefix(resource, "vault_policy:")
+}
@@ -10,10 +10,12 @@ import (
 	"net/http"
 	neturl "net/url"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/open-sspm/open-sspm/internal/connectors/httpclient"
 )
 
 const (
@@ -84,6 +86,38 @@ type AuthRole struct {
 	RawJSON   []byte
 }
 
+// AppRoleSecretID is the non-secret metadata Vault returns for an AppRole secret-id accessor.
+// ExpiresAt is zero when the secret-id was issued without a TTL and never expires.
+type AppRoleSecretID struct {
+	Accessor      string
+	RoleName      string
+	MountPath     string
+	CreatedAt     time.Time
+	ExpiresAt     time.Time
+	LastUpdatedAt time.Time
+	TTLSeconds    int64
+	NumUses       int64
+	CIDRList      []string
+	Metadata      map[string]string
+	RawJSON       []byte
+}
+
+// Token is the metadata returned by a token accessor lookup. ExpiresAt is zero for
+// tokens without a TTL (for example root tokens).
+type Token struct {
+	Accessor           string
+	DisplayName        string
+	Path               string
+	EntityID           string
+	Policies           []string
+	CreatedAt          time.Time
+	ExpiresAt          time.Time
+	LastRenewalAt      time.Time
+	CreationTTLSeconds int64
+	Orphan             bool
+	RawJSON            []byte
+}
+
 type Client struct {
 	client      *vaultapi.Client
 	namespace   string
@@ -103,8 +137,8 @@ func New(opts Options) (*Client, error) {
 	cfg := vaultapi.DefaultConfig()
 	cfg.Address = address
 	cfg.HttpClient = &http.Client{
-		Timeout:   120 * time.Second,
-		Transport: buildHTTPTransport(opts.TLSSkipVerify, strings.TrimSpace(opts.TLSCACertPEM)),
+		Timeout:   httpclient.RequestTimeout(120 * time.Second),
+		Transport: httpclient.CountRequests(buildHTTPTransport(opts.TLSSkipVerify, strings.TrimSpace(opts.TLSCACertPEM))),
 	}
 	addressHost := ""
 	if parsed, err := neturl.Parse(address); err == nil {
@@ -350,6 +384,136 @@ func (c *Client) ListAuthRoles(ctx context.Context, mounts []AuthMount) ([]AuthR
 	return out, nil
 }
 
+// ListTokenRoles reads token store roles, which live under auth/token/roles rather than
+// the <mount>/role path used by other auth methods.
+func (c *Client) ListTokenRoles(ctx context.Context, mounts []AuthMount) ([]AuthRole, error) {
+	out := make([]AuthRole, 0)
+	for _, mount := range mounts {
+		if !strings.EqualFold(strings.TrimSpace(mount.Type), vaultAuthTypeToken) {
+			continue
+		}
+		rolePath := "auth/" + mount.Path + "/roles"
+		roleNames, err := c.listKeys(ctx, rolePath)
+		if err != nil {
+			return nil, fmt.Errorf("vault list token roles for mount %s: %w", mount.Path, err)
+		}
+		for _, roleName := range roleNames {
+			roleName = strings.TrimSpace(roleName)
+			if roleName == "" {
+				continue
+			}
+			data, err := c.read(ctx, rolePath+"/"+pathEscape(roleName))
+			if err != nil {
+				return nil, fmt.Errorf("vault read token role %s on mount %s: %w", roleName, mount.Path, err)
+			}
+			out = append(out, AuthRole{
+				Name:      roleName,
+				MountPath: mount.Path,
+				AuthType:  vaultAuthTypeToken,
+				Policies:  dedupeNonEmpty(stringSlice(data["allowed_policies"])),
+				RawJSON: marshalJSON(map[string]any{
+					"mount_path": mount.Path,
+					"auth_type":  vaultAuthTypeToken,
+					"role_name":  roleName,
+					"role":       data,
+				}),
+			})
+		}
+	}
+	return out, nil
+}
+
+// ListAppRoleSecretIDs looks up every secret-id accessor issued for the AppRole roles in roles.
+// Secret-id values are never returned by Vault; only accessor metadata is read.
+func (c *Client) ListAppRoleSecretIDs(ctx context.Context, roles []AuthRole) ([]AppRoleSecretID, error) {
+	out := make([]AppRoleSecretID, 0)
+	for _, role := range roles {
+		if !strings.EqualFold(strings.TrimSpace(role.AuthType), vaultAuthTypeAppRole) {
+			continue
+		}
+		rolePath := "auth/" + normalizeMountPath(role.MountPath) + "/role/" + pathEscape(role.Name)
+		accessors, err := c.listKeys(ctx, rolePath+"/secret-id")
+		if err != nil {
+			return nil, fmt.Errorf("vault list secret-id accessors for role %s on mount %s: %w", role.Name, role.MountPath, err)
+		}
+		for _, accessor := range accessors {
+			data, err := c.write(ctx, rolePath+"/secret-id-accessor/lookup", map[string]any{"secret_id_accessor": accessor})
+			if err != nil {
+				return nil, fmt.Errorf("vault lookup secret-id accessor for role %s on mount %s: %w", role.Name, role.MountPath, err)
+			}
+			out = append(out, AppRoleSecretID{
+				Accessor:      firstNonEmpty(mapString(data, "secret_id_accessor"), accessor),
+				RoleName:      role.Name,
+				MountPath:     normalizeMountPath(role.MountPath),
+				CreatedAt:     parseVaultTime(data["creation_time"]),
+				ExpiresAt:     parseVaultTime(data["expiration_time"]),
+				LastUpdatedAt: parseVaultTime(data["last_updated_time"]),
+				TTLSeconds:    mapInt64(data, "secret_id_ttl"),
+				NumUses:       mapInt64(data, "secret_id_num_uses"),
+				CIDRList:      dedupeNonEmpty(stringSlice(data["cidr_list"])),
+				Metadata:      stringMap(data["metadata"]),
+				RawJSON:       marshalJSON(data),
+			})
+		}
+	}
+	slices.SortFunc(out, func(a, b AppRoleSecretID) int {
+		return strings.Compare(a.MountPath+":"+a.RoleName+":"+a.Accessor, b.MountPath+":"+b.RoleName+":"+b.Accessor)
+	})
+	return out, nil
+}
+
+// ListTokens looks up every token accessor in the token store. Listing accessors requires
+// sudo on auth/token/accessors, so callers should treat a permission error as non-fatal.
+// Tokens revoked or expired between the listing and their lookup are skipped.
+func (c *Client) ListTokens(ctx context.Context) ([]Token, error) {
+	accessors, err := c.listKeys(ctx, "auth/token/accessors")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Token, 0, len(accessors))
+	for _, accessor := range accessors {
+		data, err := c.write(ctx, "auth/token/lookup-accessor", map[string]any{"accessor": accessor})
+		if isVaultInvalidAccessor(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Accessor lookups blank the token ID, but never persist it if a server returns one.
+		delete(data, "id")
+		out = append(out, Token{
+			Accessor:           firstNonEmpty(mapString(data, "accessor"), accessor),
+			DisplayName:        mapString(data, "display_name"),
+			Path:               mapString(data, "path"),
+			EntityID:           mapString(data, "entity_id"),
+			Policies:           dedupeNonEmpty(stringSlice(data["policies"])),
+			CreatedAt:          parseVaultTime(data["creation_time"]),
+			ExpiresAt:          parseVaultTime(data["expire_time"]),
+			LastRenewalAt:      parseVaultTime(data["last_renewal_time"]),
+			CreationTTLSeconds: mapInt64(data, "creation_ttl"),
+			Orphan:             mapBool(data, "orphan"),
+			RawJSON:            marshalJSON(data),
+		})
+	}
+	slices.SortFunc(out, func(a, b Token) int { return strings.Compare(a.Accessor, b.Accessor) })
+	return out, nil
+}
+
+// isVaultInvalidAccessor reports whether err is Vault rejecting a token accessor it no longer
+// knows, as it does once the token is revoked or expires.
+func isVaultInvalidAccessor(err error) bool {
+	var respErr *vaultapi.ResponseError
+	if !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	for _, message := range respErr.Errors {
+		if strings.Contains(strings.ToLower(message), "invalid accessor") {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Client) listKeys(ctx context.Context, path string) ([]string, error) {
 	secret, err := c.client.Logical().ListWithContext(ctx, path)
 	if err != nil {
@@ -390,6 +554,17 @@ func (c *Client) read(ctx context.Context, path string) (map[string]any, error)
 	return secret.Data, nil
 }
 
+func (c *Client) write(ctx context.Context, path string, body map[string]any) (map[string]any, error) {
+	secret, err := c.client.Logical().WriteWithContext(ctx, path, body)
+	if err != nil {
+		return nil, fmt.Errorf("vault write %s: %w", path, c.withNamespaceHint(err))
+	}
+	if secret == nil || secret.Data == nil {
+		return map[string]any{}, nil
+	}
+	return secret.Data, nil
+}
+
 func supportsAuthRoles(authType string) bool {
 	switch strings.ToLower(strings.TrimSpace(authType)) {
 	case "approle", "kubernetes", "jwt", "oidc":
@@ -493,6 +668,64 @@ func mapBool(data map[string]any, key string) bool {
 	}
 }
 
+func mapInt64(data map[string]any, key string) int64 {
+	if data == nil {
+		return 0
+	}
+	switch value := data[key].(type) {
+	case json.Number:
+		n, err := value.Int64()
+		if err != nil {
+			f, _ := value.Float64()
+			return int64(f)
+		}
+		return n
+	case float64:
+		return int64(value)
+	case int:
+		return int64(value)
+	case int64:
+		return value
+	case string:
+		n, _ := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		return n
+	default:
+		return 0
+	}
+}
+
+// parseVaultTime accepts the RFC 3339 strings and unix-second numbers Vault uses for
+// timestamps. Vault reports "no expiry" as the zero time, which is returned as-is.
+func parseVaultTime(raw any) time.Time {
+	switch value := raw.(type) {
+	case nil:
+		return time.Time{}
+	case string:
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return time.Time{}
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil || parsed.Year() <= 1 {
+			return time.Time{}
+		}
+		return parsed.UTC()
+	case json.Number:
+		seconds, err := value.Int64()
+		if err != nil || seconds <= 0 {
+			return time.Time{}
+		}
+		return time.Unix(seconds, 0).UTC()
+	case float64:
+		if value <= 0 {
+			return time.Time{}
+		}
+		return time.Unix(int64(value), 0).UTC()
+	default:
+		return time.Time{}
+	}
+}
+
 func firstNonEmpty(values ...string) string {
 	for _, value := range values {
 		if trimmed := strings.TrimSpace(value); trimmed != "" {
@@ -552,11 +785,7 @@ func marshalJSON(value any) []byte {
 }
 
 func buildHTTPTransport(skipVerify bool, caCertPEM string) http.RoundTripper {
-	base, _ := http.DefaultTransport.(*http.Transport)
-	if base == nil {
-		return http.DefaultTransport
-	}
-	transport := base.Clone()
+	transport := httpclient.Transport().Clone()
 	if transport.TLSClientConfig == nil {
 		transport.TLSClientConfig = &tls.Config{}
 	} else {
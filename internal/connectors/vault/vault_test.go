@@ -131,6 +131,95 @@ func TestVaultClientTokenInventory(t *testing.T) {
 	}
 }
 
+func TestVaultClientListAppRoleSecretIDs(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/auth/approle/role/ci-role/secret-id":
+			writeJSON(t, w, map[string]any{"data": map[string]any{"keys": []string{"acc-1"}}})
+		case r.URL.Path == "/v1/auth/approle/role/ci-role/secret-id-accessor/lookup" && r.Method == http.MethodPut:
+			writeJSON(t, w, map[string]any{
+				"data": map[string]any{
+					"secret_id_accessor": "acc-1",
+					"creation_time":      "2025-01-02T03:04:05.123456Z",
+					"expiration_time":    "0001-01-01T00:00:00Z",
+					"last_updated_time":  "2025-01-02T03:04:05.123456Z",
+					"secret_id_ttl":      0,
+					"secret_id_num_uses": 0,
+					"metadata":           map[string]any{"name": "deploy"},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(Options{Address: server.URL, AuthType: vaultAuthTypeToken, Token: "s.token"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	secretIDs, err := client.ListAppRoleSecretIDs(context.Background(), []AuthRole{
+		{Name: "ci-role", MountPath: "approle", AuthType: "approle"},
+		{Name: "k8s-role", MountPath: "kubernetes", AuthType: "kubernetes"},
+	})
+	if err != nil {
+		t.Fatalf("ListAppRoleSecretIDs() error = %v", err)
+	}
+	if len(secretIDs) != 1 {
+		t.Fatalf("expected 1 secret-id, got %d", len(secretIDs))
+	}
+	got := secretIDs[0]
+	if got.Accessor != "acc-1" || got.RoleName != "ci-role" || got.Metadata["name"] != "deploy" {
+		t.Fatalf("unexpected secret-id: %+v", got)
+	}
+	if !got.ExpiresAt.IsZero() {
+		t.Fatalf("expected zero expiry for a non-expiring secret-id, got %v", got.ExpiresAt)
+	}
+	if got.CreatedAt.IsZero() {
+		t.Fatalf("expected creation time to be parsed")
+	}
+}
+
+func TestVaultClientListTokensSkipsRevokedAccessor(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/auth/token/accessors":
+			writeJSON(t, w, map[string]any{"data": map[string]any{"keys": []string{"acc-live", "acc-revoked"}}})
+		case r.URL.Path == "/v1/auth/token/lookup-accessor" && r.Method == http.MethodPut:
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["accessor"] == "acc-revoked" {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errors":["invalid accessor"]}`))
+				return
+			}
+			writeJSON(t, w, map[string]any{"data": map[string]any{"accessor": body["accessor"], "display_name": "token-ci"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(Options{Address: server.URL, AuthType: vaultAuthTypeToken, Token: "s.token"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tokens, err := client.ListTokens(context.Background())
+	if err != nil {
+		t.Fatalf("ListTokens() error = %v, want the revoked accessor skipped", err)
+	}
+	if len(tokens) != 1 || tokens[0].Accessor != "acc-live" {
+		t.Fatalf("tokens = %+v, want only acc-live", tokens)
+	}
+}
+
 func TestVaultClientAppRoleLogin(t *testing.T) {
 	t.Parallel()
 
@@ -24,16 +24,32 @@ WHERE aa.id = aao.app_asset_id
     aao.seen_in_run_id <> $1::bigint
     OR aao.seen_in_run_id IS NULL
   )
+  AND NOT EXISTS (
+    SELECT 1
+    FROM unnest($4::text[]) WITH ORDINALITY AS k(kind, ord)
+    JOIN unnest($5::text[]) WITH ORDINALITY AS x(external_id, ord) USING (ord)
+    WHERE k.kind = aa.asset_kind
+      AND x.external_id = aa.external_id
+  )
 `
 
 type ExpireAppAssetOwnersNotSeenInRunBySourceParams struct {
-	ExpiredRunID int64  `json:"expired_run_id"`
-	SourceKind   string `json:"source_kind"`
-	SourceName   string `json:"source_name"`
+	ExpiredRunID         int64    `json:"expired_run_id"`
+	SourceKind           string   `json:"source_kind"`
+	SourceName           string   `json:"source_name"`
+	HeldAssetKinds       []string `json:"held_asset_kinds"`
+	HeldAssetExternalIds []string `json:"held_asset_external_ids"`
 }
 
+// Owners of the held (asset kind, external id) pairs are skipped.
 func (q *Queries) ExpireAppAssetOwnersNotSeenInRunBySource(ctx context.Context, arg ExpireAppAssetOwnersNotSeenInRunBySourceParams) (int64, error) {
-	result, err := q.db.Exec(ctx, expireAppAssetOwnersNotSeenInRunBySource, arg.ExpiredRunID, arg.SourceKind, arg.SourceName)
+	result, err := q.db.Exec(ctx, expireAppAssetOwnersNotSeenInRunBySource,
+		arg.ExpiredRunID,
+		arg.SourceKind,
+		arg.SourceName,
+		arg.HeldAssetKinds,
+		arg.HeldAssetExternalIds,
+	)
 	if err != nil {
 		return 0, err
 	}
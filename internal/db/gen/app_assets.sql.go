@@ -24,18 +24,45 @@ WHERE
     OR aa.asset_kind = $3::text
   )
   AND (
-    $4::text = ''
-    OR aa.display_name ILIKE ('%' || $4::text || '%')
-    OR aa.external_id ILIKE ('%' || $4::text || '%')
-    OR aa.parent_external_id ILIKE ('%' || $4::text || '%')
+    NOT $4::bool
+    OR EXISTS (
+      SELECT 1
+      FROM app_asset_owners aao
+      WHERE aao.app_asset_id = aa.id
+        AND aao.expired_at IS NULL
+        AND lower(trim(aao.owner_external_id)) = ANY($5::text[])
+    )
+  )
+  AND (
+    $6::text = ''
+    OR aa.display_name ILIKE ('%' || $6::text || '%')
+    OR aa.external_id ILIKE ('%' || $6::text || '%')
+    OR aa.parent_external_id ILIKE ('%' || $6::text || '%')
+  )
+  AND (
+    $7::text = ''
+    OR EXISTS (
+      SELECT 1
+      FROM app_asset_tags aat
+      WHERE aat.source_kind = aa.source_kind
+        AND aat.source_name = aa.source_name
+        AND aat.asset_kind = aa.asset_kind
+        AND aat.external_id = aa.external_id
+        AND aat.tag_key = $7::text
+        AND ($8::text = '' OR aat.tag_value = $8::text)
+    )
   )
 `
 
 type CountAppAssetsBySourceAndQueryAndKindParams struct {
-	SourceKind string `json:"source_kind"`
-	SourceName string `json:"source_name"`
-	AssetKind  string `json:"asset_kind"`
-	Query      string `json:"query"`
+	SourceKind       string   `json:"source_kind"`
+	SourceName       string   `json:"source_name"`
+	AssetKind        string   `json:"asset_kind"`
+	OwnerFilter      bool     `json:"owner_filter"`
+	OwnerExternalIds []string `json:"owner_external_ids"`
+	Query            string   `json:"query"`
+	TagKey           string   `json:"tag_key"`
+	TagValue         string   `json:"tag_value"`
 }
 
 func (q *Queries) CountAppAssetsBySourceAndQueryAndKind(ctx context.Context, arg CountAppAssetsBySourceAndQueryAndKindParams) (int64, error) {
@@ -43,13 +70,88 @@ func (q *Queries) CountAppAssetsBySourceAndQueryAndKind(ctx context.Context, arg
 		arg.SourceKind,
 		arg.SourceName,
 		arg.AssetKind,
+		arg.OwnerFilter,
+		arg.OwnerExternalIds,
 		arg.Query,
+		arg.TagKey,
+		arg.TagValue,
 	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
+const countAppAssetsGroupedBySource = `-- name: CountAppAssetsGroupedBySource :many
+SELECT aa.source_kind, aa.source_name, count(*) AS asset_count
+FROM app_assets aa
+WHERE
+  aa.expired_at IS NULL
+  AND aa.last_observed_run_id IS NOT NULL
+GROUP BY aa.source_kind, aa.source_name
+ORDER BY aa.source_kind, aa.source_name
+`
+
+type CountAppAssetsGroupedBySourceRow struct {
+	SourceKind string `json:"source_kind"`
+	SourceName string `json:"source_name"`
+	AssetCount int64  `json:"asset_count"`
+}
+
+func (q *Queries) CountAppAssetsGroupedBySource(ctx context.Context) ([]CountAppAssetsGroupedBySourceRow, error) {
+	rows, err := q.db.Query(ctx, countAppAssetsGroupedBySource)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountAppAssetsGroupedBySourceRow
+	for rows.Next() {
+		var i CountAppAssetsGroupedBySourceRow
+		if err := rows.Scan(&i.SourceKind, &i.SourceName, &i.AssetCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countAppAssetsGroupedBySourceKind = `-- name: CountAppAssetsGroupedBySourceKind :many
+SELECT aa.source_kind, count(*) AS asset_count
+FROM app_assets aa
+WHERE
+  aa.expired_at IS NULL
+  AND aa.last_observed_run_id IS NOT NULL
+GROUP BY aa.source_kind
+ORDER BY aa.source_kind
+`
+
+type CountAppAssetsGroupedBySourceKindRow struct {
+	SourceKind string `json:"source_kind"`
+	AssetCount int64  `json:"asset_count"`
+}
+
+func (q *Queries) CountAppAssetsGroupedBySourceKind(ctx context.Context) ([]CountAppAssetsGroupedBySourceKindRow, error) {
+	rows, err := q.db.Query(ctx, countAppAssetsGroupedBySourceKind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountAppAssetsGroupedBySourceKindRow
+	for rows.Next() {
+		var i CountAppAssetsGroupedBySourceKindRow
+		if err := rows.Scan(&i.SourceKind, &i.AssetCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const expireAppAssetsNotSeenInRunBySource = `-- name: ExpireAppAssetsNotSeenInRunBySource :execrows
 UPDATE app_assets
 SET
@@ -164,6 +266,191 @@ func (q *Queries) GetAppAssetBySourceAndKindAndExternalID(ctx context.Context, a
 	return i, err
 }
 
+const listAppAssetKindCountsBySourceSet = `-- name: ListAppAssetKindCountsBySourceSet :many
+WITH requested AS (
+  SELECT k.kind AS source_kind, n.name AS source_name
+  FROM unnest($5::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest($6::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+),
+owner_accounts AS (
+  SELECT k.kind AS source_kind, n.name AS source_name, e.external_id
+  FROM unnest($7::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest($8::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+  JOIN unnest($9::text[]) WITH ORDINALITY AS e(external_id, ord) USING (ord)
+)
+SELECT aa.asset_kind, count(*) AS asset_count
+FROM app_assets aa
+JOIN requested r
+  ON r.source_kind = aa.source_kind
+ AND r.source_name = aa.source_name
+WHERE
+  aa.expired_at IS NULL
+  AND aa.last_observed_run_id IS NOT NULL
+  AND (
+    NOT $1::bool
+    OR EXISTS (
+      SELECT 1
+      FROM app_asset_owners aao
+      JOIN owner_accounts oa
+        ON oa.source_kind = aa.source_kind
+       AND oa.source_name = aa.source_name
+       AND oa.external_id = lower(trim(aao.owner_external_id))
+      WHERE aao.app_asset_id = aa.id
+        AND aao.expired_at IS NULL
+    )
+  )
+  AND (
+    $2::text = ''
+    OR aa.display_name ILIKE ('%' || $2::text || '%')
+    OR aa.external_id ILIKE ('%' || $2::text || '%')
+    OR aa.parent_external_id ILIKE ('%' || $2::text || '%')
+  )
+  AND (
+    $3::text = ''
+    OR EXISTS (
+      SELECT 1
+      FROM app_asset_tags aat
+      WHERE aat.source_kind = aa.source_kind
+        AND aat.source_name = aa.source_name
+        AND aat.asset_kind = aa.asset_kind
+        AND aat.external_id = aa.external_id
+        AND aat.tag_key = $3::text
+        AND ($4::text = '' OR aat.tag_value = $4::text)
+    )
+  )
+GROUP BY aa.asset_kind
+ORDER BY asset_count DESC, aa.asset_kind
+`
+
+type ListAppAssetKindCountsBySourceSetParams struct {
+	OwnerFilter      bool     `json:"owner_filter"`
+	Query            string   `json:"query"`
+	TagKey           string   `json:"tag_key"`
+	TagValue         string   `json:"tag_value"`
+	SourceKinds      []string `json:"source_kinds"`
+	SourceNames      []string `json:"source_names"`
+	OwnerSourceKinds []string `json:"owner_source_kinds"`
+	OwnerSourceNames []string `json:"owner_source_names"`
+	OwnerExternalIds []string `json:"owner_external_ids"`
+}
+
+type ListAppAssetKindCountsBySourceSetRow struct {
+	AssetKind  string `json:"asset_kind"`
+	AssetCount int64  `json:"asset_count"`
+}
+
+func (q *Queries) ListAppAssetKindCountsBySourceSet(ctx context.Context, arg ListAppAssetKindCountsBySourceSetParams) ([]ListAppAssetKindCountsBySourceSetRow, error) {
+	rows, err := q.db.Query(ctx, listAppAssetKindCountsBySourceSet,
+		arg.OwnerFilter,
+		arg.Query,
+		arg.TagKey,
+		arg.TagValue,
+		arg.SourceKinds,
+		arg.SourceNames,
+		arg.OwnerSourceKinds,
+		arg.OwnerSourceNames,
+		arg.OwnerExternalIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAppAssetKindCountsBySourceSetRow
+	for rows.Next() {
+		var i ListAppAssetKindCountsBySourceSetRow
+		if err := rows.Scan(&i.AssetKind, &i.AssetCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAppAssetsOwnedByAccounts = `-- name: ListAppAssetsOwnedByAccounts :many
+WITH owner_accounts AS (
+  SELECT k.kind AS source_kind, n.name AS source_name, e.external_id
+  FROM unnest($2::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest($3::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+  JOIN unnest($4::text[]) WITH ORDINALITY AS e(external_id, ord) USING (ord)
+)
+SELECT aa.id, aa.source_kind, aa.source_name, aa.asset_kind, aa.external_id, aa.parent_external_id, aa.display_name, aa.status, aa.created_at_source, aa.updated_at_source, aa.raw_json, aa.seen_in_run_id, aa.seen_at, aa.last_observed_run_id, aa.last_observed_at, aa.expired_at, aa.expired_run_id, aa.created_at, aa.updated_at
+FROM app_assets aa
+WHERE
+  aa.expired_at IS NULL
+  AND aa.last_observed_run_id IS NOT NULL
+  AND EXISTS (
+    SELECT 1
+    FROM app_asset_owners aao
+    JOIN owner_accounts oa
+      ON oa.source_kind = aa.source_kind
+     AND oa.source_name = aa.source_name
+     AND oa.external_id = lower(trim(aao.owner_external_id))
+    WHERE aao.app_asset_id = aa.id
+      AND aao.expired_at IS NULL
+  )
+ORDER BY
+  aa.source_kind,
+  aa.source_name,
+  lower(COALESCE(NULLIF(trim(aa.display_name), ''), aa.external_id)) ASC,
+  aa.id ASC
+LIMIT $1::int
+`
+
+type ListAppAssetsOwnedByAccountsParams struct {
+	RowLimit    int32    `json:"row_limit"`
+	SourceKinds []string `json:"source_kinds"`
+	SourceNames []string `json:"source_names"`
+	ExternalIds []string `json:"external_ids"`
+}
+
+func (q *Queries) ListAppAssetsOwnedByAccounts(ctx context.Context, arg ListAppAssetsOwnedByAccountsParams) ([]AppAsset, error) {
+	rows, err := q.db.Query(ctx, listAppAssetsOwnedByAccounts,
+		arg.RowLimit,
+		arg.SourceKinds,
+		arg.SourceNames,
+		arg.ExternalIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppAsset
+	for rows.Next() {
+		var i AppAsset
+		if err := rows.Scan(
+			&i.ID,
+			&i.SourceKind,
+			&i.SourceName,
+			&i.AssetKind,
+			&i.ExternalID,
+			&i.ParentExternalID,
+			&i.DisplayName,
+			&i.Status,
+			&i.CreatedAtSource,
+			&i.UpdatedAtSource,
+			&i.RawJson,
+			&i.SeenInRunID,
+			&i.SeenAt,
+			&i.LastObservedRunID,
+			&i.LastObservedAt,
+			&i.ExpiredAt,
+			&i.ExpiredRunID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAppAssetsPageBySourceAndQueryAndKind = `-- name: ListAppAssetsPageBySourceAndQueryAndKind :many
 SELECT aa.id, aa.source_kind, aa.source_name, aa.asset_kind, aa.external_id, aa.parent_external_id, aa.display_name, aa.status, aa.created_at_source, aa.updated_at_source, aa.raw_json, aa.seen_in_run_id, aa.seen_at, aa.last_observed_run_id, aa.last_observed_at, aa.expired_at, aa.expired_run_id, aa.created_at, aa.updated_at
 FROM app_assets aa
@@ -177,25 +464,57 @@ WHERE
     OR aa.asset_kind = $3::text
   )
   AND (
-    $4::text = ''
-    OR aa.display_name ILIKE ('%' || $4::text || '%')
-    OR aa.external_id ILIKE ('%' || $4::text || '%')
-    OR aa.parent_external_id ILIKE ('%' || $4::text || '%')
+    NOT $4::bool
+    OR EXISTS (
+      SELECT 1
+      FROM app_asset_owners aao
+      WHERE aao.app_asset_id = aa.id
+        AND aao.expired_at IS NULL
+        AND lower(trim(aao.owner_external_id)) = ANY($5::text[])
+    )
+  )
+  AND (
+    $6::text = ''
+    OR aa.display_name ILIKE ('%' || $6::text || '%')
+    OR aa.external_id ILIKE ('%' || $6::text || '%')
+    OR aa.parent_external_id ILIKE ('%' || $6::text || '%')
+  )
+  AND (
+    $7::text = ''
+    OR EXISTS (
+      SELECT 1
+      FROM app_asset_tags aat
+      WHERE aat.source_kind = aa.source_kind
+        AND aat.source_name = aa.source_name
+        AND aat.asset_kind = aa.asset_kind
+        AND aat.external_id = aa.external_id
+        AND aat.tag_key = $7::text
+        AND ($8::text = '' OR aat.tag_value = $8::text)
+    )
   )
 ORDER BY
+  CASE WHEN $9::text = 'name_desc' THEN lower(COALESCE(NULLIF(trim(aa.display_name), ''), aa.external_id)) END DESC,
+  CASE WHEN $9::text = 'kind_asc' THEN aa.asset_kind END ASC,
+  CASE WHEN $9::text = 'updated_desc' THEN aa.updated_at_source END DESC NULLS LAST,
+  CASE WHEN $9::text = 'updated_asc' THEN aa.updated_at_source END ASC NULLS FIRST,
   lower(COALESCE(NULLIF(trim(aa.display_name), ''), aa.external_id)) ASC,
   aa.id ASC
-LIMIT $6::int
-OFFSET $5::int
+LIMIT $11::int
+OFFSET $10::int
 `
 
 type ListAppAssetsPageBySourceAndQueryAndKindParams struct {
-	SourceKind string `json:"source_kind"`
-	SourceName string `json:"source_name"`
-	AssetKind  string `json:"asset_kind"`
-	Query      string `json:"query"`
-	PageOffset int32  `json:"page_offset"`
-	PageLimit  int32  `json:"page_limit"`
+	SourceKind       string   `json:"source_kind"`
+	SourceName       string   `json:"source_name"`
+	AssetKind        string   `json:"asset_kind"`
+	OwnerFilter      bool     `json:"owner_filter"`
+	OwnerExternalIds []string `json:"owner_external_ids"`
+	Query            string   `json:"query"`
+	TagKey           string   `json:"tag_key"`
+	TagValue         string   `json:"tag_value"`
+	Sort             string   `json:"sort"`
+	PageOffset       int32    `json:"page_offset"`
+	PageLimit        int32    `json:"page_limit"`
 }
 
 func (q *Queries) ListAppAssetsPageBySourceAndQueryAndKind(ctx context.Context, arg ListAppAssetsPageBySourceAndQueryAndKindParams) ([]AppAsset, error) {
@@ -203,7 +522,12 @@ func (q *Queries) ListAppAssetsPageBySourceAndQueryAndKind(ctx context.Context,
 		arg.SourceKind,
 		arg.SourceName,
 		arg.AssetKind,
+		arg.OwnerFilter,
+		arg.OwnerExternalIds,
 		arg.Query,
+		arg.TagKey,
+		arg.TagValue,
+		arg.Sort,
 		arg.PageOffset,
 		arg.PageLimit,
 	)
@@ -78,6 +78,42 @@ func (q *Queries) CountAppUsersBySourceAndQueryAndState(ctx context.Context, arg
 	return count, err
 }
 
+const countAppUsersGroupedBySource = `-- name: CountAppUsersGroupedBySource :many
+SELECT a.source_kind, a.source_name, count(*) AS user_count
+FROM accounts a
+WHERE
+  a.expired_at IS NULL
+  AND a.last_observed_run_id IS NOT NULL
+GROUP BY a.source_kind, a.source_name
+ORDER BY a.source_kind, a.source_name
+`
+
+type CountAppUsersGroupedBySourceRow struct {
+	SourceKind string `json:"source_kind"`
+	SourceName string `json:"source_name"`
+	UserCount  int64  `json:"user_count"`
+}
+
+func (q *Queries) CountAppUsersGroupedBySource(ctx context.Context) ([]CountAppUsersGroupedBySourceRow, error) {
+	rows, err := q.db.Query(ctx, countAppUsersGroupedBySource)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountAppUsersGroupedBySourceRow
+	for rows.Next() {
+		var i CountAppUsersGroupedBySourceRow
+		if err := rows.Scan(&i.SourceKind, &i.SourceName, &i.UserCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const countAppUsersWithLinkBySourceAndQuery = `-- name: CountAppUsersWithLinkBySourceAndQuery :one
 SELECT count(*)
 FROM accounts au
@@ -92,21 +128,70 @@ WHERE
     OR au.email ILIKE ('%' || $3::text || '%')
     OR au.display_name ILIKE ('%' || $3::text || '%')
   )
+  AND (
+    $4::text = ''
+    OR ($4::text = 'guest' AND au.raw_json->'is_guest' = 'true'::jsonb)
+    OR ($4::text = 'member' AND au.raw_json->'is_guest' IS DISTINCT FROM 'true'::jsonb)
+  )
 `
 
 type CountAppUsersWithLinkBySourceAndQueryParams struct {
 	SourceKind string `json:"source_kind"`
 	SourceName string `json:"source_name"`
 	Query      string `json:"query"`
+	UserType   string `json:"user_type"`
 }
 
 func (q *Queries) CountAppUsersWithLinkBySourceAndQuery(ctx context.Context, arg CountAppUsersWithLinkBySourceAndQueryParams) (int64, error) {
-	row := q.db.QueryRow(ctx, countAppUsersWithLinkBySourceAndQuery, arg.SourceKind, arg.SourceName, arg.Query)
+	row := q.db.QueryRow(ctx, countAppUsersWithLinkBySourceAndQuery,
+		arg.SourceKind,
+		arg.SourceName,
+		arg.Query,
+		arg.UserType,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
+const countDormantAppUsersGroupedBySourceKind = `-- name: CountDormantAppUsersGroupedBySourceKind :many
+SELECT au.source_kind, count(*) AS account_count
+FROM accounts au
+WHERE
+  au.expired_at IS NULL
+  AND au.last_observed_run_id IS NOT NULL
+  AND lower(COALESCE(NULLIF(trim(au.status), ''), NULLIF(trim(au.raw_json->>'status'), ''), '')) = 'active'
+  AND au.last_login_at IS NOT NULL
+  AND au.last_login_at < now() - make_interval(days => $1::int)
+GROUP BY au.source_kind
+ORDER BY au.source_kind
+`
+
+type CountDormantAppUsersGroupedBySourceKindRow struct {
+	SourceKind   string `json:"source_kind"`
+	AccountCount int64  `json:"account_count"`
+}
+
+func (q *Queries) CountDormantAppUsersGroupedBySourceKind(ctx context.Context, dormantDays int32) ([]CountDormantAppUsersGroupedBySourceKindRow, error) {
+	rows, err := q.db.Query(ctx, countDormantAppUsersGroupedBySourceKind, dormantDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountDormantAppUsersGroupedBySourceKindRow
+	for rows.Next() {
+		var i CountDormantAppUsersGroupedBySourceKindRow
+		if err := rows.Scan(&i.SourceKind, &i.AccountCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const countMatchedAppUsersBySource = `-- name: CountMatchedAppUsersBySource :one
 WITH authoritative_identities AS (
   SELECT DISTINCT ia.identity_id
@@ -365,15 +450,21 @@ WHERE
     OR au.email ILIKE ('%' || $3::text || '%')
     OR au.display_name ILIKE ('%' || $3::text || '%')
   )
+  AND (
+    $4::text = ''
+    OR ($4::text = 'guest' AND au.raw_json->'is_guest' = 'true'::jsonb)
+    OR ($4::text = 'member' AND au.raw_json->'is_guest' IS DISTINCT FROM 'true'::jsonb)
+  )
 ORDER BY au.id DESC
-LIMIT $5::int
-OFFSET $4::int
+LIMIT $6::int
+OFFSET $5::int
 `
 
 type ListAppUsersWithLinkPageBySourceAndQueryParams struct {
 	SourceKind string `json:"source_kind"`
 	SourceName string `json:"source_name"`
 	Query      string `json:"query"`
+	UserType   string `json:"user_type"`
 	PageOffset int32  `json:"page_offset"`
 	PageLimit  int32  `json:"page_limit"`
 }
@@ -407,6 +498,7 @@ func (q *Queries) ListAppUsersWithLinkPageBySourceAndQuery(ctx context.Context,
 		arg.SourceKind,
 		arg.SourceName,
 		arg.Query,
+		arg.UserType,
 		arg.PageOffset,
 		arg.PageLimit,
 	)
@@ -42,6 +42,7 @@ INSERT INTO auth_users (
   password_hash,
   role,
   is_active,
+  org_id,
   created_at,
   updated_at
 )
@@ -50,10 +51,11 @@ VALUES (
   $2::text,
   $3::text,
   $4::boolean,
+  $5::bigint,
   now(),
   now()
 )
-RETURNING id, email, password_hash, role, is_active, created_at, updated_at, last_login_at, last_login_ip
+RETURNING id, email, password_hash, role, is_active, created_at, updated_at, last_login_at, last_login_ip, org_id, auth_method, timezone
 `
 
 type CreateAuthUserParams struct {
@@ -61,6 +63,7 @@ type CreateAuthUserParams struct {
 	PasswordHash string `json:"password_hash"`
 	Role         string `json:"role"`
 	IsActive     bool   `json:"is_active"`
+	OrgID        int64  `json:"org_id"`
 }
 
 func (q *Queries) CreateAuthUser(ctx context.Context, arg CreateAuthUserParams) (AuthUser, error) {
@@ -69,6 +72,7 @@ func (q *Queries) CreateAuthUser(ctx context.Context, arg CreateAuthUserParams)
 		arg.PasswordHash,
 		arg.Role,
 		arg.IsActive,
+		arg.OrgID,
 	)
 	var i AuthUser
 	err := row.Scan(
@@ -81,6 +85,56 @@ func (q *Queries) CreateAuthUser(ctx context.Context, arg CreateAuthUserParams)
 		&i.UpdatedAt,
 		&i.LastLoginAt,
 		&i.LastLoginIp,
+		&i.OrgID,
+		&i.AuthMethod,
+		&i.Timezone,
+	)
+	return i, err
+}
+
+const createOIDCAuthUser = `-- name: CreateOIDCAuthUser :one
+INSERT INTO auth_users (
+  email,
+  password_hash,
+  role,
+  is_active,
+  auth_method,
+  created_at,
+  updated_at
+)
+VALUES (
+  lower(trim($1::text)),
+  '',
+  $2::text,
+  true,
+  'oidc',
+  now(),
+  now()
+)
+RETURNING id, email, password_hash, role, is_active, created_at, updated_at, last_login_at, last_login_ip, org_id, auth_method, timezone
+`
+
+type CreateOIDCAuthUserParams struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+func (q *Queries) CreateOIDCAuthUser(ctx context.Context, arg CreateOIDCAuthUserParams) (AuthUser, error) {
+	row := q.db.QueryRow(ctx, createOIDCAuthUser, arg.Email, arg.Role)
+	var i AuthUser
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Role,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastLoginAt,
+		&i.LastLoginIp,
+		&i.OrgID,
+		&i.AuthMethod,
+		&i.Timezone,
 	)
 	return i, err
 }
@@ -96,7 +150,7 @@ func (q *Queries) DeleteAuthUser(ctx context.Context, id int64) error {
 }
 
 const getAuthUser = `-- name: GetAuthUser :one
-SELECT id, email, password_hash, role, is_active, created_at, updated_at, last_login_at, last_login_ip
+SELECT id, email, password_hash, role, is_active, created_at, updated_at, last_login_at, last_login_ip, org_id, auth_method, timezone
 FROM auth_users
 WHERE id = $1
 `
@@ -114,12 +168,15 @@ func (q *Queries) GetAuthUser(ctx context.Context, id int64) (AuthUser, error) {
 		&i.UpdatedAt,
 		&i.LastLoginAt,
 		&i.LastLoginIp,
+		&i.OrgID,
+		&i.AuthMethod,
+		&i.Timezone,
 	)
 	return i, err
 }
 
 const getAuthUserByEmail = `-- name: GetAuthUserByEmail :one
-SELECT id, email, password_hash, role, is_active, created_at, updated_at, last_login_at, last_login_ip
+SELECT id, email, password_hash, role, is_active, created_at, updated_at, last_login_at, last_login_ip, org_id, auth_method, timezone
 FROM auth_users
 WHERE email = lower(trim($1))
 `
@@ -137,12 +194,15 @@ func (q *Queries) GetAuthUserByEmail(ctx context.Context, btrim string) (AuthUse
 		&i.UpdatedAt,
 		&i.LastLoginAt,
 		&i.LastLoginIp,
+		&i.OrgID,
+		&i.AuthMethod,
+		&i.Timezone,
 	)
 	return i, err
 }
 
 const getAuthUserForUpdate = `-- name: GetAuthUserForUpdate :one
-SELECT id, email, password_hash, role, is_active, created_at, updated_at, last_login_at, last_login_ip
+SELECT id, email, password_hash, role, is_active, created_at, updated_at, last_login_at, last_login_ip, org_id, auth_method, timezone
 FROM auth_users
 WHERE id = $1
 FOR UPDATE
@@ -161,6 +221,9 @@ func (q *Queries) GetAuthUserForUpdate(ctx context.Context, id int64) (AuthUser,
 		&i.UpdatedAt,
 		&i.LastLoginAt,
 		&i.LastLoginIp,
+		&i.OrgID,
+		&i.AuthMethod,
+		&i.Timezone,
 	)
 	return i, err
 }
@@ -193,7 +256,7 @@ func (q *Queries) ListActiveAuthAdminsForUpdate(ctx context.Context) ([]int64, e
 }
 
 const listAuthUsers = `-- name: ListAuthUsers :many
-SELECT id, email, password_hash, role, is_active, created_at, updated_at, last_login_at, last_login_ip
+SELECT id, email, password_hash, role, is_active, created_at, updated_at, last_login_at, last_login_ip, org_id, auth_method, timezone
 FROM auth_users
 ORDER BY email ASC
 `
@@ -217,6 +280,9 @@ func (q *Queries) ListAuthUsers(ctx context.Context) ([]AuthUser, error) {
 			&i.UpdatedAt,
 			&i.LastLoginAt,
 			&i.LastLoginIp,
+			&i.OrgID,
+			&i.AuthMethod,
+			&i.Timezone,
 		); err != nil {
 			return nil, err
 		}
@@ -248,6 +314,24 @@ func (q *Queries) UpdateAuthUserLoginMeta(ctx context.Context, arg UpdateAuthUse
 	return err
 }
 
+const updateAuthUserOrg = `-- name: UpdateAuthUserOrg :exec
+UPDATE auth_users
+SET
+  org_id = $1::bigint,
+  updated_at = now()
+WHERE id = $2::bigint
+`
+
+type UpdateAuthUserOrgParams struct {
+	OrgID int64 `json:"org_id"`
+	ID    int64 `json:"id"`
+}
+
+func (q *Queries) UpdateAuthUserOrg(ctx context.Context, arg UpdateAuthUserOrgParams) error {
+	_, err := q.db.Exec(ctx, updateAuthUserOrg, arg.OrgID, arg.ID)
+	return err
+}
+
 const updateAuthUserPasswordHash = `-- name: UpdateAuthUserPasswordHash :exec
 UPDATE auth_users
 SET
@@ -283,3 +367,21 @@ func (q *Queries) UpdateAuthUserRole(ctx context.Context, arg UpdateAuthUserRole
 	_, err := q.db.Exec(ctx, updateAuthUserRole, arg.Role, arg.ID)
 	return err
 }
+
+const updateAuthUserTimezone = `-- name: UpdateAuthUserTimezone :exec
+UPDATE auth_users
+SET
+  timezone = $1::text,
+  updated_at = now()
+WHERE id = $2::bigint
+`
+
+type UpdateAuthUserTimezoneParams struct {
+	Timezone string `json:"timezone"`
+	ID       int64  `json:"id"`
+}
+
+func (q *Queries) UpdateAuthUserTimezone(ctx context.Context, arg UpdateAuthUserTimezoneParams) error {
+	_, err := q.db.Exec(ctx, updateAuthUserTimezone, arg.Timezone, arg.ID)
+	return err
+}
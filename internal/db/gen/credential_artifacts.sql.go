@@ -11,6 +11,42 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countActiveCredentialArtifactsGroupedByKind = `-- name: CountActiveCredentialArtifactsGroupedByKind :many
+SELECT ca.credential_kind, count(*) AS credential_count
+FROM credential_artifacts ca
+WHERE
+  ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
+  AND lower(COALESCE(NULLIF(trim(ca.status), ''), 'active')) IN ('active', 'approved', 'pending_approval')
+GROUP BY ca.credential_kind
+ORDER BY ca.credential_kind
+`
+
+type CountActiveCredentialArtifactsGroupedByKindRow struct {
+	CredentialKind  string `json:"credential_kind"`
+	CredentialCount int64  `json:"credential_count"`
+}
+
+func (q *Queries) CountActiveCredentialArtifactsGroupedByKind(ctx context.Context) ([]CountActiveCredentialArtifactsGroupedByKindRow, error) {
+	rows, err := q.db.Query(ctx, countActiveCredentialArtifactsGroupedByKind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountActiveCredentialArtifactsGroupedByKindRow
+	for rows.Next() {
+		var i CountActiveCredentialArtifactsGroupedByKindRow
+		if err := rows.Scan(&i.CredentialKind, &i.CredentialCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const countCredentialArtifactsBySourceAndQueryAndFilters = `-- name: CountCredentialArtifactsBySourceAndQueryAndFilters :one
 SELECT count(*)
 FROM credential_artifacts ca
@@ -29,35 +65,7 @@ WHERE
   )
   AND (
     $5::text = ''
-    OR lower($5::text) = (
-      CASE
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source < now()
-          AND lower(COALESCE(NULLIF(trim(ca.status), ''), 'active')) IN ('active', 'approved', 'pending_approval')
-          THEN 'critical'
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source < now()
-          THEN 'high'
-        WHEN lower(ca.credential_kind) IN ('entra_client_secret', 'github_deploy_key', 'github_pat_request', 'github_pat_fine_grained')
-          AND trim(ca.created_by_external_id) = ''
-          AND trim(ca.approved_by_external_id) = ''
-          THEN 'critical'
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source >= now()
-          AND ca.expires_at_source <= now() + make_interval(days => 7)
-          THEN 'high'
-        WHEN trim(ca.created_by_external_id) = ''
-          THEN 'high'
-        WHEN ca.last_used_at_source IS NOT NULL
-          AND ca.last_used_at_source <= now() - make_interval(days => 90)
-          THEN 'high'
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source >= now()
-          AND ca.expires_at_source <= now() + make_interval(days => 30)
-          THEN 'medium'
-        ELSE 'low'
-      END
-    )
+    OR lower($5::text) = credential_heuristic_risk_level(ca.expires_at_source, ca.last_used_at_source, ca.status, ca.credential_kind, ca.created_by_external_id, ca.approved_by_external_id)
   )
   AND (
     $6::text = ''
@@ -80,24 +88,90 @@ WHERE
     )
   )
   AND (
-    $8::text = ''
-    OR ca.display_name ILIKE ('%' || $8::text || '%')
-    OR ca.external_id ILIKE ('%' || $8::text || '%')
-    OR ca.asset_ref_external_id ILIKE ('%' || $8::text || '%')
-    OR ca.created_by_external_id ILIKE ('%' || $8::text || '%')
-    OR ca.approved_by_external_id ILIKE ('%' || $8::text || '%')
+    $8::int <= 0
+    OR ca.first_seen_at >= now() - make_interval(days => $8::int)
+  )
+  AND (
+    NOT $9::bool
+    OR lower(ca.created_by_kind) ~ '(service_account|service_principal|bot|application)'
+    OR (
+      trim(ca.created_by_external_id) <> ''
+      AND EXISTS (
+        SELECT 1
+        FROM unnest($10::text[]) AS p(pattern)
+        WHERE p.pattern <> ''
+          AND (
+            p.pattern = ANY(regexp_split_to_array(lower(ca.created_by_external_id), '[^a-z0-9]+'))
+            OR p.pattern = ANY(regexp_split_to_array(lower(ca.created_by_display_name), '[^a-z0-9]+'))
+          )
+      )
+    )
+    OR (
+      trim(ca.created_by_external_id) = ''
+      AND EXISTS (
+        SELECT 1
+        FROM unnest($10::text[]) AS p(pattern)
+        WHERE p.pattern <> ''
+          AND p.pattern = ANY(regexp_split_to_array(lower(COALESCE(NULLIF(trim(ca.display_name), ''), ca.external_id)), '[^a-z0-9]+'))
+      )
+    )
+  )
+  AND (
+    NOT $11::bool
+    OR lower(trim(ca.created_by_external_id)) = ANY($12::text[])
+  )
+  AND (
+    $13::text = ''
+    OR ca.display_name ILIKE ('%' || $13::text || '%')
+    OR ca.external_id ILIKE ('%' || $13::text || '%')
+    OR ca.asset_ref_external_id ILIKE ('%' || $13::text || '%')
+    OR ca.created_by_external_id ILIKE ('%' || $13::text || '%')
+    OR ca.approved_by_external_id ILIKE ('%' || $13::text || '%')
+  )
+  AND (
+    $14::text = ''
+    OR ($14::text = 'never' AND ca.last_used_at_source IS NULL)
+    OR ($14::text = 'used_30d' AND ca.last_used_at_source >= now() - make_interval(days => 30))
+    OR ($14::text = 'unused_90d' AND ca.last_used_at_source < now() - make_interval(days => 90))
+    OR ($14::text = 'unused_180d' AND ca.last_used_at_source < now() - make_interval(days => 180))
+  )
+  AND (
+    $15::text = ''
+    OR EXISTS (
+      SELECT 1
+      FROM credential_tags ct
+      WHERE ct.source_kind = ca.source_kind
+        AND ct.source_name = ca.source_name
+        AND ct.credential_kind = ca.credential_kind
+        AND ct.external_id = ca.external_id
+        AND ct.tag_key = $15::text
+        AND ($16::text = '' OR ct.tag_value = $16::text)
+    )
+  )
+  AND (
+    NOT $17::bool
+    OR lower(ca.scope_json->>'repository_selection') = 'all'
   )
 `
 
 type CountCredentialArtifactsBySourceAndQueryAndFiltersParams struct {
-	SourceKind     string `json:"source_kind"`
-	SourceName     string `json:"source_name"`
-	CredentialKind string `json:"credential_kind"`
-	Status         string `json:"status"`
-	RiskLevel      string `json:"risk_level"`
-	ExpiryState    string `json:"expiry_state"`
-	ExpiresInDays  int32  `json:"expires_in_days"`
-	Query          string `json:"query"`
+	SourceKind           string   `json:"source_kind"`
+	SourceName           string   `json:"source_name"`
+	CredentialKind       string   `json:"credential_kind"`
+	Status               string   `json:"status"`
+	RiskLevel            string   `json:"risk_level"`
+	ExpiryState          string   `json:"expiry_state"`
+	ExpiresInDays        int32    `json:"expires_in_days"`
+	IntroducedInDays     int32    `json:"introduced_in_days"`
+	SharedOnly           bool     `json:"shared_only"`
+	SharedNamePatterns   []string `json:"shared_name_patterns"`
+	CreatedByFilter      bool     `json:"created_by_filter"`
+	CreatedByExternalIds []string `json:"created_by_external_ids"`
+	Query                string   `json:"query"`
+	LastUsed             string   `json:"last_used"`
+	TagKey               string   `json:"tag_key"`
+	TagValue             string   `json:"tag_value"`
+	BroadScopeOnly       bool     `json:"broad_scope_only"`
 }
 
 func (q *Queries) CountCredentialArtifactsBySourceAndQueryAndFilters(ctx context.Context, arg CountCredentialArtifactsBySourceAndQueryAndFiltersParams) (int64, error) {
@@ -109,36 +183,135 @@ func (q *Queries) CountCredentialArtifactsBySourceAndQueryAndFilters(ctx context
 		arg.RiskLevel,
 		arg.ExpiryState,
 		arg.ExpiresInDays,
+		arg.IntroducedInDays,
+		arg.SharedOnly,
+		arg.SharedNamePatterns,
+		arg.CreatedByFilter,
+		arg.CreatedByExternalIds,
 		arg.Query,
+		arg.LastUsed,
+		arg.TagKey,
+		arg.TagValue,
+		arg.BroadScopeOnly,
 	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
+const countCredentialArtifactsGroupedByRiskLevel = `-- name: CountCredentialArtifactsGroupedByRiskLevel :many
+SELECT
+  credential_heuristic_risk_level(ca.expires_at_source, ca.last_used_at_source, ca.status, ca.credential_kind, ca.created_by_external_id, ca.approved_by_external_id)::text AS risk_level,
+  count(*) AS credential_count
+FROM credential_artifacts ca
+WHERE
+  ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
+GROUP BY 1
+ORDER BY 1
+`
+
+type CountCredentialArtifactsGroupedByRiskLevelRow struct {
+	RiskLevel       string `json:"risk_level"`
+	CredentialCount int64  `json:"credential_count"`
+}
+
+func (q *Queries) CountCredentialArtifactsGroupedByRiskLevel(ctx context.Context) ([]CountCredentialArtifactsGroupedByRiskLevelRow, error) {
+	rows, err := q.db.Query(ctx, countCredentialArtifactsGroupedByRiskLevel)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountCredentialArtifactsGroupedByRiskLevelRow
+	for rows.Next() {
+		var i CountCredentialArtifactsGroupedByRiskLevelRow
+		if err := rows.Scan(&i.RiskLevel, &i.CredentialCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countCredentialArtifactsGroupedBySource = `-- name: CountCredentialArtifactsGroupedBySource :many
+SELECT ca.source_kind, ca.source_name, count(*) AS credential_count
+FROM credential_artifacts ca
+WHERE
+  ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
+GROUP BY ca.source_kind, ca.source_name
+ORDER BY ca.source_kind, ca.source_name
+`
+
+type CountCredentialArtifactsGroupedBySourceRow struct {
+	SourceKind      string `json:"source_kind"`
+	SourceName      string `json:"source_name"`
+	CredentialCount int64  `json:"credential_count"`
+}
+
+func (q *Queries) CountCredentialArtifactsGroupedBySource(ctx context.Context) ([]CountCredentialArtifactsGroupedBySourceRow, error) {
+	rows, err := q.db.Query(ctx, countCredentialArtifactsGroupedBySource)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountCredentialArtifactsGroupedBySourceRow
+	for rows.Next() {
+		var i CountCredentialArtifactsGroupedBySourceRow
+		if err := rows.Scan(&i.SourceKind, &i.SourceName, &i.CredentialCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const expireCredentialArtifactsNotSeenInRunBySource = `-- name: ExpireCredentialArtifactsNotSeenInRunBySource :execrows
-UPDATE credential_artifacts
+UPDATE credential_artifacts ca
 SET
   expired_at = now(),
   expired_run_id = $1::bigint
-WHERE source_kind = $2::text
-  AND source_name = $3::text
-  AND expired_at IS NULL
-  AND last_observed_run_id IS NOT NULL
+WHERE ca.source_kind = $2::text
+  AND ca.source_name = $3::text
+  AND ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
   AND (
-    seen_in_run_id <> $1::bigint
-    OR seen_in_run_id IS NULL
+    ca.seen_in_run_id <> $1::bigint
+    OR ca.seen_in_run_id IS NULL
+  )
+  AND NOT EXISTS (
+    SELECT 1
+    FROM unnest($4::text[]) WITH ORDINALITY AS k(kind, ord)
+    JOIN unnest($5::text[]) WITH ORDINALITY AS r(external_id, ord) USING (ord)
+    WHERE k.kind = ca.credential_kind
+      AND (r.external_id = '' OR r.external_id = ca.asset_ref_external_id)
   )
 `
 
 type ExpireCredentialArtifactsNotSeenInRunBySourceParams struct {
-	ExpiredRunID int64  `json:"expired_run_id"`
-	SourceKind   string `json:"source_kind"`
-	SourceName   string `json:"source_name"`
+	ExpiredRunID            int64    `json:"expired_run_id"`
+	SourceKind              string   `json:"source_kind"`
+	SourceName              string   `json:"source_name"`
+	HeldCredentialKinds     []string `json:"held_credential_kinds"`
+	HeldAssetRefExternalIds []string `json:"held_asset_ref_external_ids"`
 }
 
+// Held (credential kind, asset ref external id) pairs are skipped; an empty held asset ref
+// covers every credential of the kind.
 func (q *Queries) ExpireCredentialArtifactsNotSeenInRunBySource(ctx context.Context, arg ExpireCredentialArtifactsNotSeenInRunBySourceParams) (int64, error) {
-	result, err := q.db.Exec(ctx, expireCredentialArtifactsNotSeenInRunBySource, arg.ExpiredRunID, arg.SourceKind, arg.SourceName)
+	result, err := q.db.Exec(ctx, expireCredentialArtifactsNotSeenInRunBySource,
+		arg.ExpiredRunID,
+		arg.SourceKind,
+		arg.SourceName,
+		arg.HeldCredentialKinds,
+		arg.HeldAssetRefExternalIds,
+	)
 	if err != nil {
 		return 0, err
 	}
@@ -146,7 +319,7 @@ func (q *Queries) ExpireCredentialArtifactsNotSeenInRunBySource(ctx context.Cont
 }
 
 const getCredentialArtifactByID = `-- name: GetCredentialArtifactByID :one
-SELECT id, source_kind, source_name, asset_ref_kind, asset_ref_external_id, credential_kind, external_id, display_name, fingerprint, scope_json, status, created_at_source, expires_at_source, last_used_at_source, created_by_kind, created_by_external_id, created_by_display_name, approved_by_kind, approved_by_external_id, approved_by_display_name, raw_json, seen_in_run_id, seen_at, last_observed_run_id, last_observed_at, expired_at, expired_run_id, created_at, updated_at
+SELECT id, source_kind, source_name, asset_ref_kind, asset_ref_external_id, credential_kind, external_id, display_name, fingerprint, scope_json, status, created_at_source, expires_at_source, last_used_at_source, created_by_kind, created_by_external_id, created_by_display_name, approved_by_kind, approved_by_external_id, approved_by_display_name, raw_json, seen_in_run_id, seen_at, last_observed_run_id, last_observed_at, expired_at, expired_run_id, created_at, updated_at, first_seen_at
 FROM credential_artifacts
 WHERE id = $1
   AND expired_at IS NULL
@@ -186,6 +359,7 @@ func (q *Queries) GetCredentialArtifactByID(ctx context.Context, id int64) (Cred
 		&i.ExpiredRunID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FirstSeenAt,
 	)
 	return i, err
 }
@@ -253,8 +427,189 @@ func (q *Queries) ListCredentialArtifactCountsByAssetRef(ctx context.Context, ar
 	return items, nil
 }
 
+const listCredentialArtifactsByActorAccounts = `-- name: ListCredentialArtifactsByActorAccounts :many
+WITH actor_accounts AS (
+  SELECT k.kind AS source_kind, n.name AS source_name, e.external_id
+  FROM unnest($2::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest($3::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+  JOIN unnest($4::text[]) WITH ORDINALITY AS e(external_id, ord) USING (ord)
+)
+SELECT ca.id, ca.source_kind, ca.source_name, ca.asset_ref_kind, ca.asset_ref_external_id, ca.credential_kind, ca.external_id, ca.display_name, ca.fingerprint, ca.scope_json, ca.status, ca.created_at_source, ca.expires_at_source, ca.last_used_at_source, ca.created_by_kind, ca.created_by_external_id, ca.created_by_display_name, ca.approved_by_kind, ca.approved_by_external_id, ca.approved_by_display_name, ca.raw_json, ca.seen_in_run_id, ca.seen_at, ca.last_observed_run_id, ca.last_observed_at, ca.expired_at, ca.expired_run_id, ca.created_at, ca.updated_at, ca.first_seen_at
+FROM credential_artifacts ca
+WHERE
+  ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
+  AND EXISTS (
+    SELECT 1
+    FROM actor_accounts act
+    WHERE act.source_kind = ca.source_kind
+      AND act.source_name = ca.source_name
+      AND act.external_id IN (lower(trim(ca.created_by_external_id)), lower(trim(ca.approved_by_external_id)))
+  )
+ORDER BY
+  ca.source_kind,
+  ca.source_name,
+  COALESCE(ca.expires_at_source, 'infinity'::timestamptz) ASC,
+  ca.id ASC
+LIMIT $1::int
+`
+
+type ListCredentialArtifactsByActorAccountsParams struct {
+	RowLimit    int32    `json:"row_limit"`
+	SourceKinds []string `json:"source_kinds"`
+	SourceNames []string `json:"source_names"`
+	ExternalIds []string `json:"external_ids"`
+}
+
+func (q *Queries) ListCredentialArtifactsByActorAccounts(ctx context.Context, arg ListCredentialArtifactsByActorAccountsParams) ([]CredentialArtifact, error) {
+	rows, err := q.db.Query(ctx, listCredentialArtifactsByActorAccounts,
+		arg.RowLimit,
+		arg.SourceKinds,
+		arg.SourceNames,
+		arg.ExternalIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CredentialArtifact
+	for rows.Next() {
+		var i CredentialArtifact
+		if err := rows.Scan(
+			&i.ID,
+			&i.SourceKind,
+			&i.SourceName,
+			&i.AssetRefKind,
+			&i.AssetRefExternalID,
+			&i.CredentialKind,
+			&i.ExternalID,
+			&i.DisplayName,
+			&i.Fingerprint,
+			&i.ScopeJson,
+			&i.Status,
+			&i.CreatedAtSource,
+			&i.ExpiresAtSource,
+			&i.LastUsedAtSource,
+			&i.CreatedByKind,
+			&i.CreatedByExternalID,
+			&i.CreatedByDisplayName,
+			&i.ApprovedByKind,
+			&i.ApprovedByExternalID,
+			&i.ApprovedByDisplayName,
+			&i.RawJson,
+			&i.SeenInRunID,
+			&i.SeenAt,
+			&i.LastObservedRunID,
+			&i.LastObservedAt,
+			&i.ExpiredAt,
+			&i.ExpiredRunID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FirstSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCredentialArtifactsExpiringWithin = `-- name: ListCredentialArtifactsExpiringWithin :many
+SELECT
+  ca.id,
+  ca.source_kind,
+  ca.source_name,
+  ca.credential_kind,
+  ca.external_id,
+  ca.display_name,
+  ca.status,
+  ca.expires_at_source,
+  ca.created_by_external_id,
+  ca.created_by_display_name,
+  ca.approved_by_external_id,
+  ca.approved_by_display_name,
+  aa.id AS app_asset_id
+FROM credential_artifacts ca
+LEFT JOIN app_assets aa
+  ON ca.asset_ref_kind = 'app_asset'
+  AND strpos(ca.asset_ref_external_id, ':') > 0
+  AND aa.source_kind = ca.source_kind
+  AND aa.source_name = ca.source_name
+  AND aa.asset_kind = split_part(ca.asset_ref_external_id, ':', 1)
+  AND aa.external_id = substr(ca.asset_ref_external_id, strpos(ca.asset_ref_external_id, ':') + 1)
+  AND aa.expired_at IS NULL
+  AND aa.last_observed_run_id IS NOT NULL
+WHERE
+  ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
+  AND ca.expires_at_source >= now()
+  AND ca.expires_at_source < now() + make_interval(days => $1::int)
+  AND lower(COALESCE(NULLIF(trim(ca.status), ''), 'active')) = ANY($2::text[])
+ORDER BY ca.expires_at_source ASC, ca.id ASC
+LIMIT $3::int
+`
+
+type ListCredentialArtifactsExpiringWithinParams struct {
+	WithinDays     int32    `json:"within_days"`
+	ActiveStatuses []string `json:"active_statuses"`
+	RowLimit       int32    `json:"row_limit"`
+}
+
+type ListCredentialArtifactsExpiringWithinRow struct {
+	ID                    int64              `json:"id"`
+	SourceKind            string             `json:"source_kind"`
+	SourceName            string             `json:"source_name"`
+	CredentialKind        string             `json:"credential_kind"`
+	ExternalID            string             `json:"external_id"`
+	DisplayName           string             `json:"display_name"`
+	Status                string             `json:"status"`
+	ExpiresAtSource       pgtype.Timestamptz `json:"expires_at_source"`
+	CreatedByExternalID   string             `json:"created_by_external_id"`
+	CreatedByDisplayName  string             `json:"created_by_display_name"`
+	ApprovedByExternalID  string             `json:"approved_by_external_id"`
+	ApprovedByDisplayName string             `json:"approved_by_display_name"`
+	AppAssetID            pgtype.Int8        `json:"app_asset_id"`
+}
+
+func (q *Queries) ListCredentialArtifactsExpiringWithin(ctx context.Context, arg ListCredentialArtifactsExpiringWithinParams) ([]ListCredentialArtifactsExpiringWithinRow, error) {
+	rows, err := q.db.Query(ctx, listCredentialArtifactsExpiringWithin, arg.WithinDays, arg.ActiveStatuses, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCredentialArtifactsExpiringWithinRow
+	for rows.Next() {
+		var i ListCredentialArtifactsExpiringWithinRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SourceKind,
+			&i.SourceName,
+			&i.CredentialKind,
+			&i.ExternalID,
+			&i.DisplayName,
+			&i.Status,
+			&i.ExpiresAtSource,
+			&i.CreatedByExternalID,
+			&i.CreatedByDisplayName,
+			&i.ApprovedByExternalID,
+			&i.ApprovedByDisplayName,
+			&i.AppAssetID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listCredentialArtifactsForAssetRef = `-- name: ListCredentialArtifactsForAssetRef :many
-SELECT ca.id, ca.source_kind, ca.source_name, ca.asset_ref_kind, ca.asset_ref_external_id, ca.credential_kind, ca.external_id, ca.display_name, ca.fingerprint, ca.scope_json, ca.status, ca.created_at_source, ca.expires_at_source, ca.last_used_at_source, ca.created_by_kind, ca.created_by_external_id, ca.created_by_display_name, ca.approved_by_kind, ca.approved_by_external_id, ca.approved_by_display_name, ca.raw_json, ca.seen_in_run_id, ca.seen_at, ca.last_observed_run_id, ca.last_observed_at, ca.expired_at, ca.expired_run_id, ca.created_at, ca.updated_at
+SELECT ca.id, ca.source_kind, ca.source_name, ca.asset_ref_kind, ca.asset_ref_external_id, ca.credential_kind, ca.external_id, ca.display_name, ca.fingerprint, ca.scope_json, ca.status, ca.created_at_source, ca.expires_at_source, ca.last_used_at_source, ca.created_by_kind, ca.created_by_external_id, ca.created_by_display_name, ca.approved_by_kind, ca.approved_by_external_id, ca.approved_by_display_name, ca.raw_json, ca.seen_in_run_id, ca.seen_at, ca.last_observed_run_id, ca.last_observed_at, ca.expired_at, ca.expired_run_id, ca.created_at, ca.updated_at, ca.first_seen_at
 FROM credential_artifacts ca
 WHERE ca.source_kind = $1::text
   AND ca.source_name = $2::text
@@ -318,6 +673,157 @@ func (q *Queries) ListCredentialArtifactsForAssetRef(ctx context.Context, arg Li
 			&i.ExpiredRunID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.FirstSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCredentialArtifactsForAssetRefs = `-- name: ListCredentialArtifactsForAssetRefs :many
+WITH requested AS (
+  SELECT
+    k.asset_ref_kind::text AS asset_ref_kind,
+    e.asset_ref_external_id::text AS asset_ref_external_id
+  FROM unnest($3::text[]) WITH ORDINALITY AS k(asset_ref_kind, ord)
+  JOIN unnest($4::text[]) WITH ORDINALITY AS e(asset_ref_external_id, ord)
+    USING (ord)
+)
+SELECT ca.id, ca.source_kind, ca.source_name, ca.asset_ref_kind, ca.asset_ref_external_id, ca.credential_kind, ca.external_id, ca.display_name, ca.fingerprint, ca.scope_json, ca.status, ca.created_at_source, ca.expires_at_source, ca.last_used_at_source, ca.created_by_kind, ca.created_by_external_id, ca.created_by_display_name, ca.approved_by_kind, ca.approved_by_external_id, ca.approved_by_display_name, ca.raw_json, ca.seen_in_run_id, ca.seen_at, ca.last_observed_run_id, ca.last_observed_at, ca.expired_at, ca.expired_run_id, ca.created_at, ca.updated_at, ca.first_seen_at
+FROM credential_artifacts ca
+JOIN requested r
+  ON ca.asset_ref_kind = r.asset_ref_kind
+  AND ca.asset_ref_external_id = r.asset_ref_external_id
+WHERE ca.source_kind = $1::text
+  AND ca.source_name = $2::text
+  AND ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
+ORDER BY ca.asset_ref_kind, ca.asset_ref_external_id, ca.id
+`
+
+type ListCredentialArtifactsForAssetRefsParams struct {
+	SourceKind          string   `json:"source_kind"`
+	SourceName          string   `json:"source_name"`
+	AssetRefKinds       []string `json:"asset_ref_kinds"`
+	AssetRefExternalIds []string `json:"asset_ref_external_ids"`
+}
+
+func (q *Queries) ListCredentialArtifactsForAssetRefs(ctx context.Context, arg ListCredentialArtifactsForAssetRefsParams) ([]CredentialArtifact, error) {
+	rows, err := q.db.Query(ctx, listCredentialArtifactsForAssetRefs,
+		arg.SourceKind,
+		arg.SourceName,
+		arg.AssetRefKinds,
+		arg.AssetRefExternalIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CredentialArtifact
+	for rows.Next() {
+		var i CredentialArtifact
+		if err := rows.Scan(
+			&i.ID,
+			&i.SourceKind,
+			&i.SourceName,
+			&i.AssetRefKind,
+			&i.AssetRefExternalID,
+			&i.CredentialKind,
+			&i.ExternalID,
+			&i.DisplayName,
+			&i.Fingerprint,
+			&i.ScopeJson,
+			&i.Status,
+			&i.CreatedAtSource,
+			&i.ExpiresAtSource,
+			&i.LastUsedAtSource,
+			&i.CreatedByKind,
+			&i.CreatedByExternalID,
+			&i.CreatedByDisplayName,
+			&i.ApprovedByKind,
+			&i.ApprovedByExternalID,
+			&i.ApprovedByDisplayName,
+			&i.RawJson,
+			&i.SeenInRunID,
+			&i.SeenAt,
+			&i.LastObservedRunID,
+			&i.LastObservedAt,
+			&i.ExpiredAt,
+			&i.ExpiredRunID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FirstSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCredentialArtifactsForSnapshot = `-- name: ListCredentialArtifactsForSnapshot :many
+SELECT
+  ca.source_kind,
+  ca.source_name,
+  ca.credential_kind,
+  ca.external_id,
+  ca.display_name,
+  ca.status,
+  ca.asset_ref_kind,
+  ca.asset_ref_external_id,
+  ca.created_at_source,
+  ca.expires_at_source,
+  ca.last_used_at_source
+FROM credential_artifacts ca
+WHERE ca.expired_at IS NULL
+  AND ca.last_observed_run_id IS NOT NULL
+ORDER BY ca.source_kind, ca.source_name, ca.credential_kind, ca.external_id, ca.id
+`
+
+type ListCredentialArtifactsForSnapshotRow struct {
+	SourceKind         string             `json:"source_kind"`
+	SourceName         string             `json:"source_name"`
+	CredentialKind     string             `json:"credential_kind"`
+	ExternalID         string             `json:"external_id"`
+	DisplayName        string             `json:"display_name"`
+	Status             string             `json:"status"`
+	AssetRefKind       string             `json:"asset_ref_kind"`
+	AssetRefExternalID string             `json:"asset_ref_external_id"`
+	CreatedAtSource    pgtype.Timestamptz `json:"created_at_source"`
+	ExpiresAtSource    pgtype.Timestamptz `json:"expires_at_source"`
+	LastUsedAtSource   pgtype.Timestamptz `json:"last_used_at_source"`
+}
+
+// Every active credential, the credential half of a daily inventory snapshot.
+func (q *Queries) ListCredentialArtifactsForSnapshot(ctx context.Context) ([]ListCredentialArtifactsForSnapshotRow, error) {
+	rows, err := q.db.Query(ctx, listCredentialArtifactsForSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCredentialArtifactsForSnapshotRow
+	for rows.Next() {
+		var i ListCredentialArtifactsForSnapshotRow
+		if err := rows.Scan(
+			&i.SourceKind,
+			&i.SourceName,
+			&i.CredentialKind,
+			&i.ExternalID,
+			&i.DisplayName,
+			&i.Status,
+			&i.AssetRefKind,
+			&i.AssetRefExternalID,
+			&i.CreatedAtSource,
+			&i.ExpiresAtSource,
+			&i.LastUsedAtSource,
 		); err != nil {
 			return nil, err
 		}
@@ -330,7 +836,7 @@ func (q *Queries) ListCredentialArtifactsForAssetRef(ctx context.Context, arg Li
 }
 
 const listCredentialArtifactsPageBySourceAndQueryAndFilters = `-- name: ListCredentialArtifactsPageBySourceAndQueryAndFilters :many
-SELECT ca.id, ca.source_kind, ca.source_name, ca.asset_ref_kind, ca.asset_ref_external_id, ca.credential_kind, ca.external_id, ca.display_name, ca.fingerprint, ca.scope_json, ca.status, ca.created_at_source, ca.expires_at_source, ca.last_used_at_source, ca.created_by_kind, ca.created_by_external_id, ca.created_by_display_name, ca.approved_by_kind, ca.approved_by_external_id, ca.approved_by_display_name, ca.raw_json, ca.seen_in_run_id, ca.seen_at, ca.last_observed_run_id, ca.last_observed_at, ca.expired_at, ca.expired_run_id, ca.created_at, ca.updated_at
+SELECT ca.id, ca.source_kind, ca.source_name, ca.asset_ref_kind, ca.asset_ref_external_id, ca.credential_kind, ca.external_id, ca.display_name, ca.fingerprint, ca.scope_json, ca.status, ca.created_at_source, ca.expires_at_source, ca.last_used_at_source, ca.created_by_kind, ca.created_by_external_id, ca.created_by_display_name, ca.approved_by_kind, ca.approved_by_external_id, ca.approved_by_display_name, ca.raw_json, ca.seen_in_run_id, ca.seen_at, ca.last_observed_run_id, ca.last_observed_at, ca.expired_at, ca.expired_run_id, ca.created_at, ca.updated_at, ca.first_seen_at
 FROM credential_artifacts ca
 WHERE
   ca.source_kind = $1::text
@@ -347,35 +853,7 @@ WHERE
   )
   AND (
     $5::text = ''
-    OR lower($5::text) = (
-      CASE
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source < now()
-          AND lower(COALESCE(NULLIF(trim(ca.status), ''), 'active')) IN ('active', 'approved', 'pending_approval')
-          THEN 'critical'
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source < now()
-          THEN 'high'
-        WHEN lower(ca.credential_kind) IN ('entra_client_secret', 'github_deploy_key', 'github_pat_request', 'github_pat_fine_grained')
-          AND trim(ca.created_by_external_id) = ''
-          AND trim(ca.approved_by_external_id) = ''
-          THEN 'critical'
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source >= now()
-          AND ca.expires_at_source <= now() + make_interval(days => 7)
-          THEN 'high'
-        WHEN trim(ca.created_by_external_id) = ''
-          THEN 'high'
-        WHEN ca.last_used_at_source IS NOT NULL
-          AND ca.last_used_at_source <= now() - make_interval(days => 90)
-          THEN 'high'
-        WHEN ca.expires_at_source IS NOT NULL
-          AND ca.expires_at_source >= now()
-          AND ca.expires_at_source <= now() + make_interval(days => 30)
-          THEN 'medium'
-        ELSE 'low'
-      END
-    )
+    OR lower($5::text) = credential_heuristic_risk_level(ca.expires_at_source, ca.last_used_at_source, ca.status, ca.credential_kind, ca.created_by_external_id, ca.approved_by_external_id)
   )
   AND (
     $6::text = ''
@@ -398,32 +876,115 @@ WHERE
     )
   )
   AND (
-    $8::text = ''
-    OR ca.display_name ILIKE ('%' || $8::text || '%')
-    OR ca.external_id ILIKE ('%' || $8::text || '%')
-    OR ca.asset_ref_external_id ILIKE ('%' || $8::text || '%')
-    OR ca.created_by_external_id ILIKE ('%' || $8::text || '%')
-    OR ca.approved_by_external_id ILIKE ('%' || $8::text || '%')
+    $8::int <= 0
+    OR ca.first_seen_at >= now() - make_interval(days => $8::int)
+  )
+  AND (
+    NOT $9::bool
+    OR lower(ca.created_by_kind) ~ '(service_account|service_principal|bot|application)'
+    OR (
+      trim(ca.created_by_external_id) <> ''
+      AND EXISTS (
+        SELECT 1
+        FROM unnest($10::text[]) AS p(pattern)
+        WHERE p.pattern <> ''
+          AND (
+            p.pattern = ANY(regexp_split_to_array(lower(ca.created_by_external_id), '[^a-z0-9]+'))
+            OR p.pattern = ANY(regexp_split_to_array(lower(ca.created_by_display_name), '[^a-z0-9]+'))
+          )
+      )
+    )
+    OR (
+      trim(ca.created_by_external_id) = ''
+      AND EXISTS (
+        SELECT 1
+        FROM unnest($10::text[]) AS p(pattern)
+        WHERE p.pattern <> ''
+          AND p.pattern = ANY(regexp_split_to_array(lower(COALESCE(NULLIF(trim(ca.display_name), ''), ca.external_id)), '[^a-z0-9]+'))
+      )
+    )
+  )
+  AND (
+    NOT $11::bool
+    OR lower(trim(ca.created_by_external_id)) = ANY($12::text[])
+  )
+  AND (
+    $13::text = ''
+    OR ca.display_name ILIKE ('%' || $13::text || '%')
+    OR ca.external_id ILIKE ('%' || $13::text || '%')
+    OR ca.asset_ref_external_id ILIKE ('%' || $13::text || '%')
+    OR ca.created_by_external_id ILIKE ('%' || $13::text || '%')
+    OR ca.approved_by_external_id ILIKE ('%' || $13::text || '%')
+  )
+  AND (
+    $14::text = ''
+    OR ($14::text = 'never' AND ca.last_used_at_source IS NULL)
+    OR ($14::text = 'used_30d' AND ca.last_used_at_source >= now() - make_interval(days => 30))
+    OR ($14::text = 'unused_90d' AND ca.last_used_at_source < now() - make_interval(days => 90))
+    OR ($14::text = 'unused_180d' AND ca.last_used_at_source < now() - make_interval(days => 180))
+  )
+  AND (
+    $15::text = ''
+    OR EXISTS (
+      SELECT 1
+      FROM credential_tags ct
+      WHERE ct.source_kind = ca.source_kind
+        AND ct.source_name = ca.source_name
+        AND ct.credential_kind = ca.credential_kind
+        AND ct.external_id = ca.external_id
+        AND ct.tag_key = $15::text
+        AND ($16::text = '' OR ct.tag_value = $16::text)
+    )
+  )
+  AND (
+    NOT $17::bool
+    OR lower(ca.scope_json->>'repository_selection') = 'all'
   )
 ORDER BY
+  CASE $18::text
+    WHEN 'risk_desc' THEN -1
+    WHEN 'risk_asc' THEN 1
+  END * (
+    CASE credential_heuristic_risk_level(ca.expires_at_source, ca.last_used_at_source, ca.status, ca.credential_kind, ca.created_by_external_id, ca.approved_by_external_id)
+      WHEN 'critical' THEN 4
+      WHEN 'high' THEN 3
+      WHEN 'medium' THEN 2
+      ELSE 1
+    END
+  ) ASC,
+  CASE WHEN $18::text = 'last_used_desc' THEN ca.last_used_at_source END DESC NULLS LAST,
+  CASE WHEN $18::text = 'last_used_asc' THEN ca.last_used_at_source END ASC NULLS FIRST,
+  CASE WHEN $18::text = 'name_asc' THEN lower(COALESCE(NULLIF(trim(ca.display_name), ''), ca.external_id)) END ASC,
+  CASE WHEN $18::text = 'name_desc' THEN lower(COALESCE(NULLIF(trim(ca.display_name), ''), ca.external_id)) END DESC,
+  CASE WHEN $18::text = 'expires_desc' THEN COALESCE(ca.expires_at_source, 'infinity'::timestamptz) END DESC,
   COALESCE(ca.expires_at_source, 'infinity'::timestamptz) ASC,
   lower(COALESCE(NULLIF(trim(ca.display_name), ''), ca.external_id)) ASC,
   ca.id ASC
-LIMIT $10::int
-OFFSET $9::int
+LIMIT $20::int
+OFFSET $19::int
 `
 
 type ListCredentialArtifactsPageBySourceAndQueryAndFiltersParams struct {
-	SourceKind     string `json:"source_kind"`
-	SourceName     string `json:"source_name"`
-	CredentialKind string `json:"credential_kind"`
-	Status         string `json:"status"`
-	RiskLevel      string `json:"risk_level"`
-	ExpiryState    string `json:"expiry_state"`
-	ExpiresInDays  int32  `json:"expires_in_days"`
-	Query          string `json:"query"`
-	PageOffset     int32  `json:"page_offset"`
-	PageLimit      int32  `json:"page_limit"`
+	SourceKind           string   `json:"source_kind"`
+	SourceName           string   `json:"source_name"`
+	CredentialKind       string   `json:"credential_kind"`
+	Status               string   `json:"status"`
+	RiskLevel            string   `json:"risk_level"`
+	ExpiryState          string   `json:"expiry_state"`
+	ExpiresInDays        int32    `json:"expires_in_days"`
+	IntroducedInDays     int32    `json:"introduced_in_days"`
+	SharedOnly           bool     `json:"shared_only"`
+	SharedNamePatterns   []string `json:"shared_name_patterns"`
+	CreatedByFilter      bool     `json:"created_by_filter"`
+	CreatedByExternalIds []string `json:"created_by_external_ids"`
+	Query                string   `json:"query"`
+	LastUsed             string   `json:"last_used"`
+	TagKey               string   `json:"tag_key"`
+	TagValue             string   `json:"tag_value"`
+	BroadScopeOnly       bool     `json:"broad_scope_only"`
+	Sort                 string   `json:"sort"`
+	PageOffset           int32    `json:"page_offset"`
+	PageLimit            int32    `json:"page_limit"`
 }
 
 func (q *Queries) ListCredentialArtifactsPageBySourceAndQueryAndFilters(ctx context.Context, arg ListCredentialArtifactsPageBySourceAndQueryAndFiltersParams) ([]CredentialArtifact, error) {
@@ -435,7 +996,17 @@ func (q *Queries) ListCredentialArtifactsPageBySourceAndQueryAndFilters(ctx cont
 		arg.RiskLevel,
 		arg.ExpiryState,
 		arg.ExpiresInDays,
+		arg.IntroducedInDays,
+		arg.SharedOnly,
+		arg.SharedNamePatterns,
+		arg.CreatedByFilter,
+		arg.CreatedByExternalIds,
 		arg.Query,
+		arg.LastUsed,
+		arg.TagKey,
+		arg.TagValue,
+		arg.BroadScopeOnly,
+		arg.Sort,
 		arg.PageOffset,
 		arg.PageLimit,
 	)
@@ -476,6 +1047,121 @@ func (q *Queries) ListCredentialArtifactsPageBySourceAndQueryAndFilters(ctx cont
 			&i.ExpiredRunID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.FirstSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCredentialArtifactsPastRotationSLA = `-- name: ListCredentialArtifactsPastRotationSLA :many
+WITH policy AS (
+  SELECT
+    lower(k.credential_kind)::text AS credential_kind,
+    d.sla_days::int AS sla_days
+  FROM unnest($2::text[]) WITH ORDINALITY AS k(credential_kind, ord)
+  JOIN unnest($3::int[]) WITH ORDINALITY AS d(sla_days, ord)
+    USING (ord)
+),
+aged AS (
+  SELECT
+    ca.id,
+    ca.source_kind,
+    ca.source_name,
+    ca.credential_kind,
+    ca.external_id,
+    ca.display_name,
+    ca.status,
+    ca.created_at_source,
+    ca.expires_at_source,
+    ca.last_used_at_source,
+    COALESCE(p.sla_days, $4::int)::int AS sla_days,
+    floor(extract(epoch FROM (now() - ca.created_at_source)) / 86400)::int AS age_days
+  FROM credential_artifacts ca
+  LEFT JOIN policy p ON p.credential_kind = lower(ca.credential_kind)
+  WHERE
+    ca.expired_at IS NULL
+    AND ca.last_observed_run_id IS NOT NULL
+    AND ca.created_at_source IS NOT NULL
+    AND (ca.expires_at_source IS NULL OR ca.expires_at_source >= now())
+    AND lower(COALESCE(NULLIF(trim(ca.status), ''), 'active')) = ANY($5::text[])
+)
+SELECT
+  id,
+  source_kind,
+  source_name,
+  credential_kind,
+  external_id,
+  display_name,
+  status,
+  created_at_source,
+  expires_at_source,
+  last_used_at_source,
+  sla_days,
+  age_days
+FROM aged
+WHERE sla_days > 0
+  AND age_days >= sla_days
+ORDER BY age_days - sla_days DESC, id ASC
+LIMIT $1::int
+`
+
+type ListCredentialArtifactsPastRotationSLAParams struct {
+	RowLimit       int32    `json:"row_limit"`
+	PolicyKinds    []string `json:"policy_kinds"`
+	PolicyDays     []int32  `json:"policy_days"`
+	DefaultSlaDays int32    `json:"default_sla_days"`
+	ActiveStatuses []string `json:"active_statuses"`
+}
+
+type ListCredentialArtifactsPastRotationSLARow struct {
+	ID               int64              `json:"id"`
+	SourceKind       string             `json:"source_kind"`
+	SourceName       string             `json:"source_name"`
+	CredentialKind   string             `json:"credential_kind"`
+	ExternalID       string             `json:"external_id"`
+	DisplayName      string             `json:"display_name"`
+	Status           string             `json:"status"`
+	CreatedAtSource  pgtype.Timestamptz `json:"created_at_source"`
+	ExpiresAtSource  pgtype.Timestamptz `json:"expires_at_source"`
+	LastUsedAtSource pgtype.Timestamptz `json:"last_used_at_source"`
+	SlaDays          int32              `json:"sla_days"`
+	AgeDays          int32              `json:"age_days"`
+}
+
+func (q *Queries) ListCredentialArtifactsPastRotationSLA(ctx context.Context, arg ListCredentialArtifactsPastRotationSLAParams) ([]ListCredentialArtifactsPastRotationSLARow, error) {
+	rows, err := q.db.Query(ctx, listCredentialArtifactsPastRotationSLA,
+		arg.RowLimit,
+		arg.PolicyKinds,
+		arg.PolicyDays,
+		arg.DefaultSlaDays,
+		arg.ActiveStatuses,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCredentialArtifactsPastRotationSLARow
+	for rows.Next() {
+		var i ListCredentialArtifactsPastRotationSLARow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SourceKind,
+			&i.SourceName,
+			&i.CredentialKind,
+			&i.ExternalID,
+			&i.DisplayName,
+			&i.Status,
+			&i.CreatedAtSource,
+			&i.ExpiresAtSource,
+			&i.LastUsedAtSource,
+			&i.SlaDays,
+			&i.AgeDays,
 		); err != nil {
 			return nil, err
 		}
@@ -583,6 +1269,7 @@ INSERT INTO credential_artifacts (
   raw_json,
   seen_in_run_id,
   seen_at,
+  first_seen_at,
   updated_at
 )
 SELECT
@@ -608,6 +1295,7 @@ SELECT
   input.raw_json,
   $3::bigint,
   now(),
+  now(),
   now()
 FROM dedup input
 ON CONFLICT (source_kind, source_name, credential_kind, external_id, asset_ref_kind, asset_ref_external_id) DO UPDATE SET
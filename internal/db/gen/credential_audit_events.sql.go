@@ -11,6 +11,91 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const getLatestCredentialAuditEventTimeBySource = `-- name: GetLatestCredentialAuditEventTimeBySource :one
+SELECT max(event_time)::timestamptz AS last_event_time
+FROM credential_audit_events
+WHERE source_kind = $1
+  AND source_name = $2
+`
+
+type GetLatestCredentialAuditEventTimeBySourceParams struct {
+	SourceKind string `json:"source_kind"`
+	SourceName string `json:"source_name"`
+}
+
+func (q *Queries) GetLatestCredentialAuditEventTimeBySource(ctx context.Context, arg GetLatestCredentialAuditEventTimeBySourceParams) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getLatestCredentialAuditEventTimeBySource, arg.SourceKind, arg.SourceName)
+	var last_event_time pgtype.Timestamptz
+	err := row.Scan(&last_event_time)
+	return last_event_time, err
+}
+
+const listCredentialAuditEventsForActors = `-- name: ListCredentialAuditEventsForActors :many
+WITH actor AS (
+  SELECT k.kind AS source_kind, n.name AS source_name, e.external_id AS actor_external_id
+  FROM unnest($2::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest($3::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+  JOIN unnest($4::text[]) WITH ORDINALITY AS e(external_id, ord) USING (ord)
+)
+SELECT cae.id, cae.source_kind, cae.source_name, cae.event_external_id, cae.event_type, cae.event_time, cae.actor_kind, cae.actor_external_id, cae.actor_display_name, cae.target_kind, cae.target_external_id, cae.target_display_name, cae.credential_kind, cae.credential_external_id, cae.raw_json, cae.created_at
+FROM credential_audit_events cae
+JOIN actor
+  ON actor.source_kind = cae.source_kind
+  AND actor.source_name = cae.source_name
+  AND actor.actor_external_id = cae.actor_external_id
+ORDER BY cae.event_time DESC, cae.id DESC
+LIMIT $1::int
+`
+
+type ListCredentialAuditEventsForActorsParams struct {
+	LimitRows        int32    `json:"limit_rows"`
+	SourceKinds      []string `json:"source_kinds"`
+	SourceNames      []string `json:"source_names"`
+	ActorExternalIds []string `json:"actor_external_ids"`
+}
+
+func (q *Queries) ListCredentialAuditEventsForActors(ctx context.Context, arg ListCredentialAuditEventsForActorsParams) ([]CredentialAuditEvent, error) {
+	rows, err := q.db.Query(ctx, listCredentialAuditEventsForActors,
+		arg.LimitRows,
+		arg.SourceKinds,
+		arg.SourceNames,
+		arg.ActorExternalIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CredentialAuditEvent
+	for rows.Next() {
+		var i CredentialAuditEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.SourceKind,
+			&i.SourceName,
+			&i.EventExternalID,
+			&i.EventType,
+			&i.EventTime,
+			&i.ActorKind,
+			&i.ActorExternalID,
+			&i.ActorDisplayName,
+			&i.TargetKind,
+			&i.TargetExternalID,
+			&i.TargetDisplayName,
+			&i.CredentialKind,
+			&i.CredentialExternalID,
+			&i.RawJson,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listCredentialAuditEventsForCredential = `-- name: ListCredentialAuditEventsForCredential :many
 SELECT cae.id, cae.source_kind, cae.source_name, cae.event_external_id, cae.event_type, cae.event_time, cae.actor_kind, cae.actor_external_id, cae.actor_display_name, cae.target_kind, cae.target_external_id, cae.target_display_name, cae.credential_kind, cae.credential_external_id, cae.raw_json, cae.created_at
 FROM credential_audit_events cae
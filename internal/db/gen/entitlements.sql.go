@@ -11,6 +11,147 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const listActiveEntitlementsForEscalation = `-- name: ListActiveEntitlementsForEscalation :many
+SELECT
+  e.id AS entitlement_id,
+  e.kind AS entitlement_kind,
+  e.resource AS entitlement_resource,
+  e.permission AS entitlement_permission,
+  e.privilege_level AS entitlement_privilege_level,
+  e.raw_json AS entitlement_raw_json,
+  au.id AS app_user_id,
+  au.source_kind AS app_user_source_kind,
+  au.source_name AS app_user_source_name,
+  au.external_id AS app_user_external_id,
+  au.email AS app_user_email,
+  au.display_name AS app_user_display_name
+FROM entitlements e
+JOIN accounts au ON au.id = e.app_user_id
+WHERE ($1::text = '' OR au.source_kind = $1::text)
+  AND au.expired_at IS NULL
+  AND au.last_observed_run_id IS NOT NULL
+  AND e.expired_at IS NULL
+  AND e.last_observed_run_id IS NOT NULL
+ORDER BY au.source_kind, au.source_name, au.external_id, e.kind, e.resource, e.id
+LIMIT $2
+`
+
+type ListActiveEntitlementsForEscalationParams struct {
+	SourceKind string `json:"source_kind"`
+	RowLimit   int32  `json:"row_limit"`
+}
+
+type ListActiveEntitlementsForEscalationRow struct {
+	EntitlementID             int64  `json:"entitlement_id"`
+	EntitlementKind           string `json:"entitlement_kind"`
+	EntitlementResource       string `json:"entitlement_resource"`
+	EntitlementPermission     string `json:"entitlement_permission"`
+	EntitlementPrivilegeLevel int16  `json:"entitlement_privilege_level"`
+	EntitlementRawJson        []byte `json:"entitlement_raw_json"`
+	AppUserID                 int64  `json:"app_user_id"`
+	AppUserSourceKind         string `json:"app_user_source_kind"`
+	AppUserSourceName         string `json:"app_user_source_name"`
+	AppUserExternalID         string `json:"app_user_external_id"`
+	AppUserEmail              string `json:"app_user_email"`
+	AppUserDisplayName        string `json:"app_user_display_name"`
+}
+
+// Every active entitlement of every active account, the input of escalation path detection.
+// Membership entitlements are included whatever their level, since they are the graph's edges.
+func (q *Queries) ListActiveEntitlementsForEscalation(ctx context.Context, arg ListActiveEntitlementsForEscalationParams) ([]ListActiveEntitlementsForEscalationRow, error) {
+	rows, err := q.db.Query(ctx, listActiveEntitlementsForEscalation, arg.SourceKind, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActiveEntitlementsForEscalationRow
+	for rows.Next() {
+		var i ListActiveEntitlementsForEscalationRow
+		if err := rows.Scan(
+			&i.EntitlementID,
+			&i.EntitlementKind,
+			&i.EntitlementResource,
+			&i.EntitlementPermission,
+			&i.EntitlementPrivilegeLevel,
+			&i.EntitlementRawJson,
+			&i.AppUserID,
+			&i.AppUserSourceKind,
+			&i.AppUserSourceName,
+			&i.AppUserExternalID,
+			&i.AppUserEmail,
+			&i.AppUserDisplayName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveEntitlementsForSnapshot = `-- name: ListActiveEntitlementsForSnapshot :many
+SELECT
+  au.source_kind AS app_user_source_kind,
+  au.source_name AS app_user_source_name,
+  au.external_id AS app_user_external_id,
+  au.email AS app_user_email,
+  e.kind AS entitlement_kind,
+  e.resource AS entitlement_resource,
+  e.permission AS entitlement_permission,
+  e.privilege_level AS entitlement_privilege_level
+FROM entitlements e
+JOIN accounts au ON au.id = e.app_user_id
+WHERE au.expired_at IS NULL
+  AND au.last_observed_run_id IS NOT NULL
+  AND e.expired_at IS NULL
+  AND e.last_observed_run_id IS NOT NULL
+ORDER BY au.source_kind, au.source_name, au.external_id, e.kind, e.resource, e.id
+`
+
+type ListActiveEntitlementsForSnapshotRow struct {
+	AppUserSourceKind         string `json:"app_user_source_kind"`
+	AppUserSourceName         string `json:"app_user_source_name"`
+	AppUserExternalID         string `json:"app_user_external_id"`
+	AppUserEmail              string `json:"app_user_email"`
+	EntitlementKind           string `json:"entitlement_kind"`
+	EntitlementResource       string `json:"entitlement_resource"`
+	EntitlementPermission     string `json:"entitlement_permission"`
+	EntitlementPrivilegeLevel int16  `json:"entitlement_privilege_level"`
+}
+
+// Every active entitlement of every active account, the entitlement half of a daily inventory
+// snapshot.
+func (q *Queries) ListActiveEntitlementsForSnapshot(ctx context.Context) ([]ListActiveEntitlementsForSnapshotRow, error) {
+	rows, err := q.db.Query(ctx, listActiveEntitlementsForSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActiveEntitlementsForSnapshotRow
+	for rows.Next() {
+		var i ListActiveEntitlementsForSnapshotRow
+		if err := rows.Scan(
+			&i.AppUserSourceKind,
+			&i.AppUserSourceName,
+			&i.AppUserExternalID,
+			&i.AppUserEmail,
+			&i.EntitlementKind,
+			&i.EntitlementResource,
+			&i.EntitlementPermission,
+			&i.EntitlementPrivilegeLevel,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEntitlementAccessBySourceAndResourceRef = `-- name: ListEntitlementAccessBySourceAndResourceRef :many
 SELECT
   e.id AS entitlement_id,
@@ -18,6 +159,7 @@ SELECT
   e.resource AS entitlement_resource,
   e.permission AS entitlement_permission,
   e.raw_json AS entitlement_raw_json,
+  e.assignment_state AS entitlement_assignment_state,
   e.created_at AS entitlement_created_at,
   e.updated_at AS entitlement_updated_at,
   au.id AS app_user_id,
@@ -53,25 +195,26 @@ type ListEntitlementAccessBySourceAndResourceRefParams struct {
 }
 
 type ListEntitlementAccessBySourceAndResourceRefRow struct {
-	EntitlementID         int64              `json:"entitlement_id"`
-	EntitlementKind       string             `json:"entitlement_kind"`
-	EntitlementResource   string             `json:"entitlement_resource"`
-	EntitlementPermission string             `json:"entitlement_permission"`
-	EntitlementRawJson    []byte             `json:"entitlement_raw_json"`
-	EntitlementCreatedAt  pgtype.Timestamptz `json:"entitlement_created_at"`
-	EntitlementUpdatedAt  pgtype.Timestamptz `json:"entitlement_updated_at"`
-	AppUserID             int64              `json:"app_user_id"`
-	AppUserSourceKind     string             `json:"app_user_source_kind"`
-	AppUserSourceName     string             `json:"app_user_source_name"`
-	AppUserExternalID     string             `json:"app_user_external_id"`
-	AppUserEmail          string             `json:"app_user_email"`
-	AppUserDisplayName    string             `json:"app_user_display_name"`
-	AppUserRawJson        []byte             `json:"app_user_raw_json"`
-	LinkReason            pgtype.Text        `json:"link_reason"`
-	IdpUserID             pgtype.Int8        `json:"idp_user_id"`
-	IdpUserEmail          pgtype.Text        `json:"idp_user_email"`
-	IdpUserDisplayName    pgtype.Text        `json:"idp_user_display_name"`
-	IdpUserStatus         pgtype.Text        `json:"idp_user_status"`
+	EntitlementID              int64              `json:"entitlement_id"`
+	EntitlementKind            string             `json:"entitlement_kind"`
+	EntitlementResource        string             `json:"entitlement_resource"`
+	EntitlementPermission      string             `json:"entitlement_permission"`
+	EntitlementRawJson         []byte             `json:"entitlement_raw_json"`
+	EntitlementAssignmentState string             `json:"entitlement_assignment_state"`
+	EntitlementCreatedAt       pgtype.Timestamptz `json:"entitlement_created_at"`
+	EntitlementUpdatedAt       pgtype.Timestamptz `json:"entitlement_updated_at"`
+	AppUserID                  int64              `json:"app_user_id"`
+	AppUserSourceKind          string             `json:"app_user_source_kind"`
+	AppUserSourceName          string             `json:"app_user_source_name"`
+	AppUserExternalID          string             `json:"app_user_external_id"`
+	AppUserEmail               string             `json:"app_user_email"`
+	AppUserDisplayName         string             `json:"app_user_display_name"`
+	AppUserRawJson             []byte             `json:"app_user_raw_json"`
+	LinkReason                 pgtype.Text        `json:"link_reason"`
+	IdpUserID                  pgtype.Int8        `json:"idp_user_id"`
+	IdpUserEmail               pgtype.Text        `json:"idp_user_email"`
+	IdpUserDisplayName         pgtype.Text        `json:"idp_user_display_name"`
+	IdpUserStatus              pgtype.Text        `json:"idp_user_status"`
 }
 
 func (q *Queries) ListEntitlementAccessBySourceAndResourceRef(ctx context.Context, arg ListEntitlementAccessBySourceAndResourceRefParams) ([]ListEntitlementAccessBySourceAndResourceRefRow, error) {
@@ -89,6 +232,7 @@ func (q *Queries) ListEntitlementAccessBySourceAndResourceRef(ctx context.Contex
 			&i.EntitlementResource,
 			&i.EntitlementPermission,
 			&i.EntitlementRawJson,
+			&i.EntitlementAssignmentState,
 			&i.EntitlementCreatedAt,
 			&i.EntitlementUpdatedAt,
 			&i.AppUserID,
@@ -114,6 +258,114 @@ func (q *Queries) ListEntitlementAccessBySourceAndResourceRef(ctx context.Contex
 	return items, nil
 }
 
+const listEntitlementChangeCandidatesBySource = `-- name: ListEntitlementChangeCandidatesBySource :many
+SELECT
+  e.id AS entitlement_id,
+  e.kind AS entitlement_kind,
+  e.resource AS entitlement_resource,
+  e.permission AS entitlement_permission,
+  e.assignment_state AS entitlement_assignment_state,
+  e.raw_json AS entitlement_raw_json,
+  e.last_observed_run_id,
+  e.previous_observed_run_id,
+  e.expired_run_id,
+  au.id AS app_user_id,
+  au.external_id AS app_user_external_id,
+  au.email AS app_user_email,
+  au.display_name AS app_user_display_name,
+  i.id AS identity_id,
+  i.display_name AS identity_display_name,
+  i.primary_email AS identity_primary_email
+FROM entitlements e
+JOIN accounts au ON au.id = e.app_user_id
+LEFT JOIN identity_accounts ia ON ia.account_id = au.id
+LEFT JOIN identities i ON i.id = ia.identity_id
+WHERE au.source_kind = $1::text
+  AND au.source_name = $2::text
+  AND (
+    (
+      e.last_observed_run_id = $3::bigint
+      AND e.expired_at IS NULL
+      AND e.previous_observed_run_id IS DISTINCT FROM $4::bigint
+    )
+    OR e.expired_run_id = $3::bigint
+  )
+ORDER BY e.kind, e.resource, au.external_id, e.permission, e.id
+LIMIT $5
+`
+
+type ListEntitlementChangeCandidatesBySourceParams struct {
+	SourceKind    string `json:"source_kind"`
+	SourceName    string `json:"source_name"`
+	CurrentRunID  int64  `json:"current_run_id"`
+	PreviousRunID int64  `json:"previous_run_id"`
+	LimitRows     int32  `json:"limit_rows"`
+}
+
+type ListEntitlementChangeCandidatesBySourceRow struct {
+	EntitlementID              int64       `json:"entitlement_id"`
+	EntitlementKind            string      `json:"entitlement_kind"`
+	EntitlementResource        string      `json:"entitlement_resource"`
+	EntitlementPermission      string      `json:"entitlement_permission"`
+	EntitlementAssignmentState string      `json:"entitlement_assignment_state"`
+	EntitlementRawJson         []byte      `json:"entitlement_raw_json"`
+	LastObservedRunID          pgtype.Int8 `json:"last_observed_run_id"`
+	PreviousObservedRunID      pgtype.Int8 `json:"previous_observed_run_id"`
+	ExpiredRunID               pgtype.Int8 `json:"expired_run_id"`
+	AppUserID                  int64       `json:"app_user_id"`
+	AppUserExternalID          string      `json:"app_user_external_id"`
+	AppUserEmail               string      `json:"app_user_email"`
+	AppUserDisplayName         string      `json:"app_user_display_name"`
+	IdentityID                 pgtype.Int8 `json:"identity_id"`
+	IdentityDisplayName        pgtype.Text `json:"identity_display_name"`
+	IdentityPrimaryEmail       pgtype.Text `json:"identity_primary_email"`
+}
+
+// Entitlements that differ between the previous and current successful runs of a source:
+// observed in the current run but not the previous one, or expired by the current run.
+func (q *Queries) ListEntitlementChangeCandidatesBySource(ctx context.Context, arg ListEntitlementChangeCandidatesBySourceParams) ([]ListEntitlementChangeCandidatesBySourceRow, error) {
+	rows, err := q.db.Query(ctx, listEntitlementChangeCandidatesBySource,
+		arg.SourceKind,
+		arg.SourceName,
+		arg.CurrentRunID,
+		arg.PreviousRunID,
+		arg.LimitRows,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEntitlementChangeCandidatesBySourceRow
+	for rows.Next() {
+		var i ListEntitlementChangeCandidatesBySourceRow
+		if err := rows.Scan(
+			&i.EntitlementID,
+			&i.EntitlementKind,
+			&i.EntitlementResource,
+			&i.EntitlementPermission,
+			&i.EntitlementAssignmentState,
+			&i.EntitlementRawJson,
+			&i.LastObservedRunID,
+			&i.PreviousObservedRunID,
+			&i.ExpiredRunID,
+			&i.AppUserID,
+			&i.AppUserExternalID,
+			&i.AppUserEmail,
+			&i.AppUserDisplayName,
+			&i.IdentityID,
+			&i.IdentityDisplayName,
+			&i.IdentityPrimaryEmail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEntitlementResourcesByAppUserIDsAndKind = `-- name: ListEntitlementResourcesByAppUserIDsAndKind :many
 SELECT
   app_user_id,
@@ -157,7 +409,7 @@ func (q *Queries) ListEntitlementResourcesByAppUserIDsAndKind(ctx context.Contex
 }
 
 const listEntitlementsForAppUser = `-- name: ListEntitlementsForAppUser :many
-SELECT id, app_user_id, kind, resource, permission, raw_json, created_at, seen_in_run_id, seen_at, last_observed_run_id, last_observed_at, expired_at, expired_run_id, updated_at
+SELECT id, app_user_id, kind, resource, permission, raw_json, created_at, seen_in_run_id, seen_at, last_observed_run_id, last_observed_at, expired_at, expired_run_id, updated_at, assignment_state, previous_observed_run_id, privilege_level
 FROM entitlements
 WHERE app_user_id = $1
   AND expired_at IS NULL
@@ -189,6 +441,9 @@ func (q *Queries) ListEntitlementsForAppUser(ctx context.Context, appUserID int6
 			&i.ExpiredAt,
 			&i.ExpiredRunID,
 			&i.UpdatedAt,
+			&i.AssignmentState,
+			&i.PreviousObservedRunID,
+			&i.PrivilegeLevel,
 		); err != nil {
 			return nil, err
 		}
@@ -201,7 +456,7 @@ func (q *Queries) ListEntitlementsForAppUser(ctx context.Context, appUserID int6
 }
 
 const listEntitlementsForAppUserIDs = `-- name: ListEntitlementsForAppUserIDs :many
-SELECT id, app_user_id, kind, resource, permission, raw_json, created_at, seen_in_run_id, seen_at, last_observed_run_id, last_observed_at, expired_at, expired_run_id, updated_at
+SELECT id, app_user_id, kind, resource, permission, raw_json, created_at, seen_in_run_id, seen_at, last_observed_run_id, last_observed_at, expired_at, expired_run_id, updated_at, assignment_state, previous_observed_run_id, privilege_level
 FROM entitlements
 WHERE app_user_id = ANY($1::bigint[])
   AND expired_at IS NULL
@@ -233,6 +488,117 @@ func (q *Queries) ListEntitlementsForAppUserIDs(ctx context.Context, appUserIds
 			&i.ExpiredAt,
 			&i.ExpiredRunID,
 			&i.UpdatedAt,
+			&i.AssignmentState,
+			&i.PreviousObservedRunID,
+			&i.PrivilegeLevel,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPrivilegedEntitlements = `-- name: ListPrivilegedEntitlements :many
+SELECT
+  e.id AS entitlement_id,
+  e.kind AS entitlement_kind,
+  e.resource AS entitlement_resource,
+  e.permission AS entitlement_permission,
+  e.privilege_level AS entitlement_privilege_level,
+  e.assignment_state AS entitlement_assignment_state,
+  e.raw_json AS entitlement_raw_json,
+  e.last_observed_at AS entitlement_last_observed_at,
+  au.id AS app_user_id,
+  au.source_kind AS app_user_source_kind,
+  au.source_name AS app_user_source_name,
+  au.external_id AS app_user_external_id,
+  au.email AS app_user_email,
+  au.display_name AS app_user_display_name,
+  i.id AS identity_id,
+  i.display_name AS identity_display_name,
+  i.primary_email AS identity_primary_email
+FROM entitlements e
+JOIN accounts au ON au.id = e.app_user_id
+LEFT JOIN identity_accounts ia ON ia.account_id = au.id
+LEFT JOIN identities i ON i.id = ia.identity_id
+WHERE (
+    e.privilege_level >= $1::smallint
+    OR e.kind = ANY($2::text[])
+  )
+  AND ($3::text = '' OR au.source_kind = $3::text)
+  AND au.expired_at IS NULL
+  AND au.last_observed_run_id IS NOT NULL
+  AND e.expired_at IS NULL
+  AND e.last_observed_run_id IS NOT NULL
+ORDER BY e.privilege_level DESC, au.source_kind, au.source_name, au.external_id, e.kind, e.resource, e.id
+LIMIT $4
+`
+
+type ListPrivilegedEntitlementsParams struct {
+	MinPrivilegeLevel int16    `json:"min_privilege_level"`
+	ExtraKinds        []string `json:"extra_kinds"`
+	SourceKind        string   `json:"source_kind"`
+	RowLimit          int32    `json:"row_limit"`
+}
+
+type ListPrivilegedEntitlementsRow struct {
+	EntitlementID              int64              `json:"entitlement_id"`
+	EntitlementKind            string             `json:"entitlement_kind"`
+	EntitlementResource        string             `json:"entitlement_resource"`
+	EntitlementPermission      string             `json:"entitlement_permission"`
+	EntitlementPrivilegeLevel  int16              `json:"entitlement_privilege_level"`
+	EntitlementAssignmentState string             `json:"entitlement_assignment_state"`
+	EntitlementRawJson         []byte             `json:"entitlement_raw_json"`
+	EntitlementLastObservedAt  pgtype.Timestamptz `json:"entitlement_last_observed_at"`
+	AppUserID                  int64              `json:"app_user_id"`
+	AppUserSourceKind          string             `json:"app_user_source_kind"`
+	AppUserSourceName          string             `json:"app_user_source_name"`
+	AppUserExternalID          string             `json:"app_user_external_id"`
+	AppUserEmail               string             `json:"app_user_email"`
+	AppUserDisplayName         string             `json:"app_user_display_name"`
+	IdentityID                 pgtype.Int8        `json:"identity_id"`
+	IdentityDisplayName        pgtype.Text        `json:"identity_display_name"`
+	IdentityPrimaryEmail       pgtype.Text        `json:"identity_primary_email"`
+}
+
+// Active entitlements at or above a canonical privilege level, or of one of extra_kinds, across
+// every source, with the linked identity where one exists.
+func (q *Queries) ListPrivilegedEntitlements(ctx context.Context, arg ListPrivilegedEntitlementsParams) ([]ListPrivilegedEntitlementsRow, error) {
+	rows, err := q.db.Query(ctx, listPrivilegedEntitlements,
+		arg.MinPrivilegeLevel,
+		arg.ExtraKinds,
+		arg.SourceKind,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPrivilegedEntitlementsRow
+	for rows.Next() {
+		var i ListPrivilegedEntitlementsRow
+		if err := rows.Scan(
+			&i.EntitlementID,
+			&i.EntitlementKind,
+			&i.EntitlementResource,
+			&i.EntitlementPermission,
+			&i.EntitlementPrivilegeLevel,
+			&i.EntitlementAssignmentState,
+			&i.EntitlementRawJson,
+			&i.EntitlementLastObservedAt,
+			&i.AppUserID,
+			&i.AppUserSourceKind,
+			&i.AppUserSourceName,
+			&i.AppUserExternalID,
+			&i.AppUserEmail,
+			&i.AppUserDisplayName,
+			&i.IdentityID,
+			&i.IdentityDisplayName,
+			&i.IdentityPrimaryEmail,
 		); err != nil {
 			return nil, err
 		}
@@ -252,7 +618,9 @@ WITH input AS (
     ($5::text[])[i] AS kind,
     ($6::text[])[i] AS resource,
     ($7::text[])[i] AS permission,
-    ($8::jsonb[])[i] AS raw_json
+    ($8::jsonb[])[i] AS raw_json,
+    COALESCE(($9::text[])[i], '') AS assignment_state,
+    COALESCE(($10::smallint[])[i], 0) AS privilege_level
   FROM generate_subscripts($4::text[], 1) AS s(i)
 ),
 dedup AS (
@@ -261,7 +629,9 @@ dedup AS (
     kind,
     resource,
     permission,
-    raw_json
+    raw_json,
+    assignment_state,
+    privilege_level
   FROM input
   ORDER BY app_user_external_id, kind, resource, permission, i DESC
 )
@@ -271,6 +641,8 @@ INSERT INTO entitlements (
   resource,
   permission,
   raw_json,
+  assignment_state,
+  privilege_level,
   seen_in_run_id,
   seen_at,
   updated_at
@@ -281,6 +653,8 @@ SELECT
   input.resource,
   input.permission,
   input.raw_json,
+  input.assignment_state,
+  input.privilege_level,
   $1::bigint,
   now(),
   now()
@@ -291,6 +665,8 @@ JOIN accounts au
   AND au.external_id = input.app_user_external_id
 ON CONFLICT (app_user_id, kind, resource, permission) DO UPDATE SET
   raw_json = EXCLUDED.raw_json,
+  assignment_state = EXCLUDED.assignment_state,
+  privilege_level = EXCLUDED.privilege_level,
   seen_in_run_id = EXCLUDED.seen_in_run_id,
   seen_at = EXCLUDED.seen_at,
   updated_at = now()
@@ -305,6 +681,8 @@ type UpsertEntitlementsBulkBySourceParams struct {
 	Resources          []string `json:"resources"`
 	Permissions        []string `json:"permissions"`
 	RawJsons           [][]byte `json:"raw_jsons"`
+	AssignmentStates   []string `json:"assignment_states"`
+	PrivilegeLevels    []int16  `json:"privilege_levels"`
 }
 
 func (q *Queries) UpsertEntitlementsBulkBySource(ctx context.Context, arg UpsertEntitlementsBulkBySourceParams) (int64, error) {
@@ -317,6 +695,8 @@ func (q *Queries) UpsertEntitlementsBulkBySource(ctx context.Context, arg Upsert
 		arg.Resources,
 		arg.Permissions,
 		arg.RawJsons,
+		arg.AssignmentStates,
+		arg.PrivilegeLevels,
 	)
 	if err != nil {
 		return 0, err
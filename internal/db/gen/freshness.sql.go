@@ -15,22 +15,34 @@ const expireAppUsersNotSeenInRun = `-- name: ExpireAppUsersNotSeenInRun :execrow
 UPDATE accounts
 SET
   expired_at = now(),
-  expired_run_id = $1
-WHERE source_kind = $2
-  AND source_name = $3
+  expired_run_id = $1::bigint
+WHERE source_kind = $2::text
+  AND source_name = $3::text
   AND expired_at IS NULL
   AND last_observed_run_id IS NOT NULL
-  AND (seen_in_run_id <> $1 OR seen_in_run_id IS NULL)
+  AND (seen_in_run_id <> $1::bigint OR seen_in_run_id IS NULL)
+  AND NOT EXISTS (
+    SELECT 1
+    FROM unnest($4::text[]) AS f(flag)
+    WHERE accounts.raw_json ->> f.flag = 'true'
+  )
 `
 
 type ExpireAppUsersNotSeenInRunParams struct {
-	ExpiredRunID pgtype.Int8 `json:"expired_run_id"`
-	SourceKind   string      `json:"source_kind"`
-	SourceName   string      `json:"source_name"`
+	ExpiredRunID     int64    `json:"expired_run_id"`
+	SourceKind       string   `json:"source_kind"`
+	SourceName       string   `json:"source_name"`
+	HeldRawJsonFlags []string `json:"held_raw_json_flags"`
 }
 
+// Accounts whose raw_json sets one of the held flags to true are skipped.
 func (q *Queries) ExpireAppUsersNotSeenInRun(ctx context.Context, arg ExpireAppUsersNotSeenInRunParams) (int64, error) {
-	result, err := q.db.Exec(ctx, expireAppUsersNotSeenInRun, arg.ExpiredRunID, arg.SourceKind, arg.SourceName)
+	result, err := q.db.Exec(ctx, expireAppUsersNotSeenInRun,
+		arg.ExpiredRunID,
+		arg.SourceKind,
+		arg.SourceName,
+		arg.HeldRawJsonFlags,
+	)
 	if err != nil {
 		return 0, err
 	}
@@ -41,24 +53,40 @@ const expireEntitlementsNotSeenInRunBySource = `-- name: ExpireEntitlementsNotSe
 UPDATE entitlements e
 SET
   expired_at = now(),
-  expired_run_id = $1
+  expired_run_id = $1::bigint
 FROM accounts au
 WHERE au.id = e.app_user_id
-  AND au.source_kind = $2
-  AND au.source_name = $3
+  AND au.source_kind = $2::text
+  AND au.source_name = $3::text
   AND e.expired_at IS NULL
   AND e.last_observed_run_id IS NOT NULL
-  AND (e.seen_in_run_id <> $1 OR e.seen_in_run_id IS NULL)
+  AND (e.seen_in_run_id <> $1::bigint OR e.seen_in_run_id IS NULL)
+  AND NOT EXISTS (
+    SELECT 1
+    FROM unnest($4::text[]) WITH ORDINALITY AS k(kind, ord)
+    JOIN unnest($5::text[]) WITH ORDINALITY AS r(resource, ord) USING (ord)
+    WHERE k.kind = e.kind
+      AND (r.resource = '' OR r.resource = e.resource)
+  )
 `
 
 type ExpireEntitlementsNotSeenInRunBySourceParams struct {
-	ExpiredRunID pgtype.Int8 `json:"expired_run_id"`
-	SourceKind   string      `json:"source_kind"`
-	SourceName   string      `json:"source_name"`
+	ExpiredRunID  int64    `json:"expired_run_id"`
+	SourceKind    string   `json:"source_kind"`
+	SourceName    string   `json:"source_name"`
+	HeldKinds     []string `json:"held_kinds"`
+	HeldResources []string `json:"held_resources"`
 }
 
+// Held (kind, resource) pairs are skipped; an empty held resource covers every resource of the kind.
 func (q *Queries) ExpireEntitlementsNotSeenInRunBySource(ctx context.Context, arg ExpireEntitlementsNotSeenInRunBySourceParams) (int64, error) {
-	result, err := q.db.Exec(ctx, expireEntitlementsNotSeenInRunBySource, arg.ExpiredRunID, arg.SourceKind, arg.SourceName)
+	result, err := q.db.Exec(ctx, expireEntitlementsNotSeenInRunBySource,
+		arg.ExpiredRunID,
+		arg.SourceKind,
+		arg.SourceName,
+		arg.HeldKinds,
+		arg.HeldResources,
+	)
 	if err != nil {
 		return 0, err
 	}
@@ -203,6 +231,10 @@ func (q *Queries) PromoteAppUsersSeenInRun(ctx context.Context, arg PromoteAppUs
 const promoteEntitlementsSeenInRunBySource = `-- name: PromoteEntitlementsSeenInRunBySource :execrows
 UPDATE entitlements e
 SET
+  previous_observed_run_id = CASE
+    WHEN e.last_observed_run_id IS DISTINCT FROM $1 THEN e.last_observed_run_id
+    ELSE e.previous_observed_run_id
+  END,
   last_observed_run_id = $1,
   last_observed_at = now(),
   expired_at = NULL,
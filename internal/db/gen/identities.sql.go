@@ -142,7 +142,7 @@ status_stats AS (
     aa.identity_id,
     COUNT(*)::bigint AS account_count,
     BOOL_OR(aa.normalized_status IN ('active', 'enabled')) AS has_active,
-    BOOL_OR(aa.normalized_status IN ('suspended', 'disabled', 'inactive', 'locked')) AS has_suspended,
+    BOOL_OR(aa.normalized_status IN ('suspended', 'archived', 'disabled', 'inactive', 'locked')) AS has_suspended,
     BOOL_AND(aa.normalized_status IN ('deleted', 'deprovisioned', 'terminated')) AS all_deleted
   FROM all_active_accounts aa
   GROUP BY aa.identity_id
@@ -408,6 +408,47 @@ func (q *Queries) GetPreferredIdentityByPrimaryEmail(ctx context.Context, primar
 	return i, err
 }
 
+const getPreferredIdentityBySecondaryEmail = `-- name: GetPreferredIdentityBySecondaryEmail :one
+WITH authoritative_identities AS (
+  SELECT DISTINCT ia.identity_id
+  FROM identity_accounts ia
+  JOIN accounts anchor ON anchor.id = ia.account_id
+  JOIN identity_source_settings iss
+    ON iss.source_kind = anchor.source_kind
+   AND iss.source_name = anchor.source_name
+   AND iss.is_authoritative
+  WHERE anchor.expired_at IS NULL
+    AND anchor.last_observed_run_id IS NOT NULL
+)
+SELECT i.id, i.kind, i.display_name, i.primary_email, i.created_at, i.updated_at
+FROM identities i
+JOIN identity_accounts ia ON ia.identity_id = i.id
+JOIN accounts a ON a.id = ia.account_id
+JOIN account_emails ae ON ae.account_id = a.id
+LEFT JOIN authoritative_identities ai ON ai.identity_id = i.id
+WHERE ae.email = lower(trim($1::text))
+  AND a.expired_at IS NULL
+  AND a.last_observed_run_id IS NOT NULL
+ORDER BY (ai.identity_id IS NOT NULL) DESC, i.id ASC
+LIMIT 1
+`
+
+// The identity linked to an active account that lists email as a secondary address, preferring
+// identities anchored by an authoritative source.
+func (q *Queries) GetPreferredIdentityBySecondaryEmail(ctx context.Context, email string) (Identity, error) {
+	row := q.db.QueryRow(ctx, getPreferredIdentityBySecondaryEmail, email)
+	var i Identity
+	err := row.Scan(
+		&i.ID,
+		&i.Kind,
+		&i.DisplayName,
+		&i.PrimaryEmail,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const listIdentitiesInventoryPageByFilters = `-- name: ListIdentitiesInventoryPageByFilters :many
 WITH configured_sources AS (
   SELECT
@@ -539,7 +580,7 @@ status_stats AS (
     aa.identity_id,
     COUNT(*)::bigint AS account_count,
     BOOL_OR(aa.normalized_status IN ('active', 'enabled')) AS has_active,
-    BOOL_OR(aa.normalized_status IN ('suspended', 'disabled', 'inactive', 'locked')) AS has_suspended,
+    BOOL_OR(aa.normalized_status IN ('suspended', 'archived', 'disabled', 'inactive', 'locked')) AS has_suspended,
     BOOL_AND(aa.normalized_status IN ('deleted', 'deprovisioned', 'terminated')) AS all_deleted
   FROM all_active_accounts aa
   GROUP BY aa.identity_id
@@ -884,6 +925,61 @@ func (q *Queries) ListIdentitiesInventoryPageByFilters(ctx context.Context, arg
 	return items, nil
 }
 
+const listPreferredIdentitiesByPrimaryEmails = `-- name: ListPreferredIdentitiesByPrimaryEmails :many
+WITH authoritative_identities AS (
+  SELECT DISTINCT ia.identity_id
+  FROM identity_accounts ia
+  JOIN accounts anchor ON anchor.id = ia.account_id
+  JOIN identity_source_settings iss
+    ON iss.source_kind = anchor.source_kind
+   AND iss.source_name = anchor.source_name
+   AND iss.is_authoritative
+  WHERE anchor.expired_at IS NULL
+    AND anchor.last_observed_run_id IS NOT NULL
+)
+SELECT DISTINCT ON (lower(trim(i.primary_email)))
+  lower(trim(i.primary_email))::text AS email,
+  i.id AS identity_id,
+  i.display_name,
+  i.primary_email
+FROM identities i
+LEFT JOIN authoritative_identities ai ON ai.identity_id = i.id
+WHERE lower(trim(i.primary_email)) = ANY($1::text[])
+ORDER BY lower(trim(i.primary_email)), (ai.identity_id IS NOT NULL) DESC, i.id ASC
+`
+
+type ListPreferredIdentitiesByPrimaryEmailsRow struct {
+	Email        string `json:"email"`
+	IdentityID   int64  `json:"identity_id"`
+	DisplayName  string `json:"display_name"`
+	PrimaryEmail string `json:"primary_email"`
+}
+
+func (q *Queries) ListPreferredIdentitiesByPrimaryEmails(ctx context.Context, primaryEmails []string) ([]ListPreferredIdentitiesByPrimaryEmailsRow, error) {
+	rows, err := q.db.Query(ctx, listPreferredIdentitiesByPrimaryEmails, primaryEmails)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPreferredIdentitiesByPrimaryEmailsRow
+	for rows.Next() {
+		var i ListPreferredIdentitiesByPrimaryEmailsRow
+		if err := rows.Scan(
+			&i.Email,
+			&i.IdentityID,
+			&i.DisplayName,
+			&i.PrimaryEmail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateIdentityAttributes = `-- name: UpdateIdentityAttributes :exec
 UPDATE identities
 SET
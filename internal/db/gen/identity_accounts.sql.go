@@ -80,6 +80,74 @@ func (q *Queries) GetIdentityBySourceAndExternalID(ctx context.Context, arg GetI
 	return i, err
 }
 
+const listIdentitiesBySourceAndExternalIDs = `-- name: ListIdentitiesBySourceAndExternalIDs :many
+WITH requested AS (
+  SELECT k.source_kind, n.source_name, e.external_id
+  FROM unnest($1::text[]) WITH ORDINALITY AS k(source_kind, ord)
+  JOIN unnest($2::text[]) WITH ORDINALITY AS n(source_name, ord) USING (ord)
+  JOIN unnest($3::text[]) WITH ORDINALITY AS e(external_id, ord) USING (ord)
+)
+SELECT DISTINCT ON (r.source_kind, r.source_name, r.external_id)
+  r.source_kind::text AS source_kind,
+  r.source_name::text AS source_name,
+  r.external_id::text AS external_id,
+  i.id AS identity_id,
+  i.display_name,
+  i.primary_email
+FROM requested r
+JOIN accounts a
+  ON lower(trim(a.source_kind)) = lower(trim(r.source_kind))
+ AND lower(trim(a.source_name)) = lower(trim(r.source_name))
+ AND lower(trim(a.external_id)) = lower(trim(r.external_id))
+JOIN identity_accounts ia ON ia.account_id = a.id
+JOIN identities i ON i.id = ia.identity_id
+WHERE a.expired_at IS NULL
+  AND a.last_observed_run_id IS NOT NULL
+ORDER BY r.source_kind, r.source_name, r.external_id, i.id ASC
+`
+
+type ListIdentitiesBySourceAndExternalIDsParams struct {
+	SourceKinds []string `json:"source_kinds"`
+	SourceNames []string `json:"source_names"`
+	ExternalIds []string `json:"external_ids"`
+}
+
+type ListIdentitiesBySourceAndExternalIDsRow struct {
+	SourceKind   string `json:"source_kind"`
+	SourceName   string `json:"source_name"`
+	ExternalID   string `json:"external_id"`
+	IdentityID   int64  `json:"identity_id"`
+	DisplayName  string `json:"display_name"`
+	PrimaryEmail string `json:"primary_email"`
+}
+
+func (q *Queries) ListIdentitiesBySourceAndExternalIDs(ctx context.Context, arg ListIdentitiesBySourceAndExternalIDsParams) ([]ListIdentitiesBySourceAndExternalIDsRow, error) {
+	rows, err := q.db.Query(ctx, listIdentitiesBySourceAndExternalIDs, arg.SourceKinds, arg.SourceNames, arg.ExternalIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListIdentitiesBySourceAndExternalIDsRow
+	for rows.Next() {
+		var i ListIdentitiesBySourceAndExternalIDsRow
+		if err := rows.Scan(
+			&i.SourceKind,
+			&i.SourceName,
+			&i.ExternalID,
+			&i.IdentityID,
+			&i.DisplayName,
+			&i.PrimaryEmail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listIdentityAccountAttributes = `-- name: ListIdentityAccountAttributes :many
 SELECT
   ia.identity_id,
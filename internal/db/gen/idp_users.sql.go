@@ -42,15 +42,34 @@ WHERE
     OR ($2::text = 'active' AND lower(status) = 'active')
     OR ($2::text = 'inactive' AND lower(status) <> 'active')
   )
+  AND (
+    $3::text = ''
+    OR ($3::text = 'none' AND NOT EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE'
+    ))
+    OR ($3::text = 'weak' AND EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE'
+    ) AND NOT EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE' AND f.strength = 'strong'
+    ))
+    OR ($3::text = 'strong' AND EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE' AND f.strength = 'strong'
+    ))
+  )
 `
 
 type CountIdPUsersByQueryAndStateParams struct {
 	Query string `json:"query"`
 	State string `json:"state"`
+	Mfa   string `json:"mfa"`
 }
 
 func (q *Queries) CountIdPUsersByQueryAndState(ctx context.Context, arg CountIdPUsersByQueryAndStateParams) (int64, error) {
-	row := q.db.QueryRow(ctx, countIdPUsersByQueryAndState, arg.Query, arg.State)
+	row := q.db.QueryRow(ctx, countIdPUsersByQueryAndState, arg.Query, arg.State, arg.Mfa)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -163,14 +182,33 @@ WHERE
     OR ($2::text = 'active' AND lower(status) = 'active')
     OR ($2::text = 'inactive' AND lower(status) <> 'active')
   )
+  AND (
+    $3::text = ''
+    OR ($3::text = 'none' AND NOT EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE'
+    ))
+    OR ($3::text = 'weak' AND EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE'
+    ) AND NOT EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE' AND f.strength = 'strong'
+    ))
+    OR ($3::text = 'strong' AND EXISTS (
+      SELECT 1 FROM okta_user_factors f
+      WHERE f.okta_user_account_id = accounts.id AND upper(f.status) = 'ACTIVE' AND f.strength = 'strong'
+    ))
+  )
 ORDER BY id ASC
-LIMIT $4::int
-OFFSET $3::int
+LIMIT $5::int
+OFFSET $4::int
 `
 
 type ListIdPUsersPageByQueryAndStateParams struct {
 	Query      string `json:"query"`
 	State      string `json:"state"`
+	Mfa        string `json:"mfa"`
 	PageOffset int32  `json:"page_offset"`
 	PageLimit  int32  `json:"page_limit"`
 }
@@ -179,6 +217,7 @@ func (q *Queries) ListIdPUsersPageByQueryAndState(ctx context.Context, arg ListI
 	rows, err := q.db.Query(ctx, listIdPUsersPageByQueryAndState,
 		arg.Query,
 		arg.State,
+		arg.Mfa,
 		arg.PageOffset,
 		arg.PageLimit,
 	)
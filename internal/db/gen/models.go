@@ -31,6 +31,12 @@ type Account struct {
 	AccountKind       string             `json:"account_kind"`
 }
 
+type AccountEmail struct {
+	AccountID int64              `json:"account_id"`
+	Email     string             `json:"email"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
 type AppAsset struct {
 	ID                int64              `json:"id"`
 	SourceKind        string             `json:"source_kind"`
@@ -71,6 +77,47 @@ type AppAssetOwner struct {
 	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
 }
 
+type AppAssetTag struct {
+	ID         int64              `json:"id"`
+	SourceKind string             `json:"source_kind"`
+	SourceName string             `json:"source_name"`
+	AssetKind  string             `json:"asset_kind"`
+	ExternalID string             `json:"external_id"`
+	TagKey     string             `json:"tag_key"`
+	TagValue   string             `json:"tag_value"`
+	RuleID     int64              `json:"rule_id"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type AppAssetTagRule struct {
+	ID         int64              `json:"id"`
+	Name       string             `json:"name"`
+	Conditions []byte             `json:"conditions"`
+	TagKey     string             `json:"tag_key"`
+	TagValue   string             `json:"tag_value"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type AppAuditLog struct {
+	ID              int64              `json:"id"`
+	OccurredAt      pgtype.Timestamptz `json:"occurred_at"`
+	OrgID           int64              `json:"org_id"`
+	ActorAuthUserID pgtype.Int8        `json:"actor_auth_user_id"`
+	ActorEmail      string             `json:"actor_email"`
+	ActorRole       string             `json:"actor_role"`
+	Action          string             `json:"action"`
+	Method          string             `json:"method"`
+	Route           string             `json:"route"`
+	Target          string             `json:"target"`
+	StatusCode      int32              `json:"status_code"`
+	BeforeJson      []byte             `json:"before_json"`
+	AfterJson       []byte             `json:"after_json"`
+	RequestID       string             `json:"request_id"`
+	RemoteIp        string             `json:"remote_ip"`
+}
+
 type AuthUser struct {
 	ID           int64              `json:"id"`
 	Email        string             `json:"email"`
@@ -81,6 +128,9 @@ type AuthUser struct {
 	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
 	LastLoginAt  pgtype.Timestamptz `json:"last_login_at"`
 	LastLoginIp  string             `json:"last_login_ip"`
+	OrgID        int64              `json:"org_id"`
+	AuthMethod   string             `json:"auth_method"`
+	Timezone     string             `json:"timezone"`
 }
 
 type ConnectorConfig struct {
@@ -91,6 +141,26 @@ type ConnectorConfig struct {
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 }
 
+type ConnectorGrant struct {
+	SourceKind string             `json:"source_kind"`
+	SourceName string             `json:"source_name"`
+	Granted    []string           `json:"granted"`
+	Required   []string           `json:"required"`
+	Missing    []string           `json:"missing"`
+	LastRunID  pgtype.Int8        `json:"last_run_id"`
+	CheckedAt  pgtype.Timestamptz `json:"checked_at"`
+}
+
+type ConnectorIncident struct {
+	ID         int64              `json:"id"`
+	SourceKind string             `json:"source_kind"`
+	SourceName string             `json:"source_name"`
+	DedupKey   string             `json:"dedup_key"`
+	Summary    string             `json:"summary"`
+	OpenedAt   pgtype.Timestamptz `json:"opened_at"`
+	ResolvedAt pgtype.Timestamptz `json:"resolved_at"`
+}
+
 type CredentialArtifact struct {
 	ID                    int64              `json:"id"`
 	SourceKind            string             `json:"source_kind"`
@@ -121,6 +191,7 @@ type CredentialArtifact struct {
 	ExpiredRunID          pgtype.Int8        `json:"expired_run_id"`
 	CreatedAt             pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
+	FirstSeenAt           pgtype.Timestamptz `json:"first_seen_at"`
 }
 
 type CredentialAuditEvent struct {
@@ -142,21 +213,52 @@ type CredentialAuditEvent struct {
 	CreatedAt            pgtype.Timestamptz `json:"created_at"`
 }
 
+type CredentialRevocationTask struct {
+	ID                    int64              `json:"id"`
+	SourceKind            string             `json:"source_kind"`
+	SourceName            string             `json:"source_name"`
+	CredentialKind        string             `json:"credential_kind"`
+	ExternalID            string             `json:"external_id"`
+	Method                string             `json:"method"`
+	Status                string             `json:"status"`
+	Reason                string             `json:"reason"`
+	ErrorMessage          string             `json:"error_message"`
+	RequestedByAuthUserID pgtype.Int8        `json:"requested_by_auth_user_id"`
+	ResolvedByAuthUserID  pgtype.Int8        `json:"resolved_by_auth_user_id"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	ResolvedAt            pgtype.Timestamptz `json:"resolved_at"`
+}
+
+type CredentialTag struct {
+	ID             int64              `json:"id"`
+	SourceKind     string             `json:"source_kind"`
+	SourceName     string             `json:"source_name"`
+	CredentialKind string             `json:"credential_kind"`
+	ExternalID     string             `json:"external_id"`
+	TagKey         string             `json:"tag_key"`
+	TagValue       string             `json:"tag_value"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Entitlement struct {
-	ID                int64              `json:"id"`
-	AppUserID         int64              `json:"app_user_id"`
-	Kind              string             `json:"kind"`
-	Resource          string             `json:"resource"`
-	Permission        string             `json:"permission"`
-	RawJson           []byte             `json:"raw_json"`
-	CreatedAt         pgtype.Timestamptz `json:"created_at"`
-	SeenInRunID       pgtype.Int8        `json:"seen_in_run_id"`
-	SeenAt            pgtype.Timestamptz `json:"seen_at"`
-	LastObservedRunID pgtype.Int8        `json:"last_observed_run_id"`
-	LastObservedAt    pgtype.Timestamptz `json:"last_observed_at"`
-	ExpiredAt         pgtype.Timestamptz `json:"expired_at"`
-	ExpiredRunID      pgtype.Int8        `json:"expired_run_id"`
-	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	ID                    int64              `json:"id"`
+	AppUserID             int64              `json:"app_user_id"`
+	Kind                  string             `json:"kind"`
+	Resource              string             `json:"resource"`
+	Permission            string             `json:"permission"`
+	RawJson               []byte             `json:"raw_json"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	SeenInRunID           pgtype.Int8        `json:"seen_in_run_id"`
+	SeenAt                pgtype.Timestamptz `json:"seen_at"`
+	LastObservedRunID     pgtype.Int8        `json:"last_observed_run_id"`
+	LastObservedAt        pgtype.Timestamptz `json:"last_observed_at"`
+	ExpiredAt             pgtype.Timestamptz `json:"expired_at"`
+	ExpiredRunID          pgtype.Int8        `json:"expired_run_id"`
+	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
+	AssignmentState       string             `json:"assignment_state"`
+	PreviousObservedRunID pgtype.Int8        `json:"previous_observed_run_id"`
+	PrivilegeLevel        int16              `json:"privilege_level"`
 }
 
 type Identity struct {
@@ -178,6 +280,20 @@ type IdentityAccount struct {
 	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
 }
 
+type IdentityMergeSuggestion struct {
+	ID                  int64              `json:"id"`
+	LowIdentityID       int64              `json:"low_identity_id"`
+	HighIdentityID      int64              `json:"high_identity_id"`
+	SurvivorIdentityID  int64              `json:"survivor_identity_id"`
+	Score               float32            `json:"score"`
+	Reasons             []string           `json:"reasons"`
+	Status              string             `json:"status"`
+	DecidedByAuthUserID pgtype.Int8        `json:"decided_by_auth_user_id"`
+	DecidedAt           pgtype.Timestamptz `json:"decided_at"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
+}
+
 type IdentitySourceSetting struct {
 	SourceKind      string             `json:"source_kind"`
 	SourceName      string             `json:"source_name"`
@@ -193,6 +309,15 @@ type IntegrationOktaAppMap struct {
 	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
 }
 
+type InventorySnapshot struct {
+	ID           int64              `json:"id"`
+	SnapshotDate pgtype.Date        `json:"snapshot_date"`
+	Kind         string             `json:"kind"`
+	RowCount     int32              `json:"row_count"`
+	Payload      []byte             `json:"payload"`
+	CapturedAt   pgtype.Timestamptz `json:"captured_at"`
+}
+
 type OktaApp struct {
 	ID                int64              `json:"id"`
 	ExternalID        string             `json:"external_id"`
@@ -261,6 +386,20 @@ type OktaUserAppAssignment struct {
 	OktaUserAccountID int64              `json:"okta_user_account_id"`
 }
 
+type OktaUserFactor struct {
+	ID                int64              `json:"id"`
+	OktaUserAccountID int64              `json:"okta_user_account_id"`
+	ExternalID        string             `json:"external_id"`
+	FactorType        string             `json:"factor_type"`
+	Provider          string             `json:"provider"`
+	VendorName        string             `json:"vendor_name"`
+	Status            string             `json:"status"`
+	Strength          string             `json:"strength"`
+	RawJson           []byte             `json:"raw_json"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+}
+
 type OktaUserGroup struct {
 	ID                int64              `json:"id"`
 	OktaGroupID       int64              `json:"okta_group_id"`
@@ -274,6 +413,20 @@ type OktaUserGroup struct {
 	OktaUserAccountID int64              `json:"okta_user_account_id"`
 }
 
+type Org struct {
+	ID        int64              `json:"id"`
+	Slug      string             `json:"slug"`
+	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type OrgSource struct {
+	SourceKind string             `json:"source_kind"`
+	SourceName string             `json:"source_name"`
+	OrgID      int64              `json:"org_id"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
 type Rule struct {
 	ID               int64              `json:"id"`
 	RulesetID        int64              `json:"ruleset_id"`
@@ -396,6 +549,7 @@ type SaasApp struct {
 	LastSeenAt                   pgtype.Timestamptz `json:"last_seen_at"`
 	CreatedAt                    pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt                    pgtype.Timestamptz `json:"updated_at"`
+	Category                     string             `json:"category"`
 }
 
 type SaasAppBinding struct {
@@ -447,6 +601,16 @@ type SaasAppGovernanceOverride struct {
 	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
 }
 
+type SaasAppOauthAnomaly struct {
+	SaasAppID      int64              `json:"saas_app_id"`
+	Reasons        []string           `json:"reasons"`
+	FirstGrantAt   pgtype.Timestamptz `json:"first_grant_at"`
+	ActorsInWindow int64              `json:"actors_in_window"`
+	HighRiskScopes []string           `json:"high_risk_scopes"`
+	DetectedAt     pgtype.Timestamptz `json:"detected_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
 type SaasAppSource struct {
 	ID                int64              `json:"id"`
 	SaasAppID         int64              `json:"saas_app_id"`
@@ -471,6 +635,15 @@ type Session struct {
 	Expiry pgtype.Timestamptz `json:"expiry"`
 }
 
+type SyncCheckpoint struct {
+	SourceKind     string             `json:"source_kind"`
+	SourceName     string             `json:"source_name"`
+	Stage          string             `json:"stage"`
+	ItemKey        string             `json:"item_key"`
+	PayloadJson    []byte             `json:"payload_json"`
+	CheckpointedAt pgtype.Timestamptz `json:"checkpointed_at"`
+}
+
 type SyncLock struct {
 	ScopeKind        string             `json:"scope_kind"`
 	ScopeName        string             `json:"scope_name"`
@@ -482,13 +655,16 @@ type SyncLock struct {
 }
 
 type SyncRun struct {
-	ID         int64              `json:"id"`
-	SourceKind string             `json:"source_kind"`
-	SourceName string             `json:"source_name"`
-	Status     string             `json:"status"`
-	StartedAt  pgtype.Timestamptz `json:"started_at"`
-	FinishedAt pgtype.Timestamptz `json:"finished_at"`
-	Message    string             `json:"message"`
-	Stats      []byte             `json:"stats"`
-	ErrorKind  string             `json:"error_kind"`
+	ID              int64              `json:"id"`
+	SourceKind      string             `json:"source_kind"`
+	SourceName      string             `json:"source_name"`
+	Status          string             `json:"status"`
+	StartedAt       pgtype.Timestamptz `json:"started_at"`
+	FinishedAt      pgtype.Timestamptz `json:"finished_at"`
+	Message         string             `json:"message"`
+	Stats           []byte             `json:"stats"`
+	ErrorKind       string             `json:"error_kind"`
+	Warnings        []byte             `json:"warnings"`
+	ApiRequestCount int64              `json:"api_request_count"`
+	ApiStats        []byte             `json:"api_stats"`
 }
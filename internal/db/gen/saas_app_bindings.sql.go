@@ -11,6 +11,48 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const listSaaSAppBindingsBySaaSAppID = `-- name: ListSaaSAppBindingsBySaaSAppID :many
+SELECT id, saas_app_id, connector_kind, connector_source_name, binding_source, confidence, is_primary, created_by_auth_user_id, created_at, updated_at
+FROM saas_app_bindings
+WHERE saas_app_id = $1
+ORDER BY
+  is_primary DESC,
+  CASE binding_source WHEN 'manual' THEN 0 WHEN 'auto' THEN 1 ELSE 2 END,
+  confidence DESC,
+  id ASC
+`
+
+func (q *Queries) ListSaaSAppBindingsBySaaSAppID(ctx context.Context, saasAppID int64) ([]SaasAppBinding, error) {
+	rows, err := q.db.Query(ctx, listSaaSAppBindingsBySaaSAppID, saasAppID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SaasAppBinding
+	for rows.Next() {
+		var i SaasAppBinding
+		if err := rows.Scan(
+			&i.ID,
+			&i.SaasAppID,
+			&i.ConnectorKind,
+			&i.ConnectorSourceName,
+			&i.BindingSource,
+			&i.Confidence,
+			&i.IsPrimary,
+			&i.CreatedByAuthUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const recomputePrimarySaaSAppBindingsForAll = `-- name: RecomputePrimarySaaSAppBindingsForAll :execrows
 WITH ranked AS (
   SELECT
@@ -19,19 +61,20 @@ WITH ranked AS (
     row_number() OVER (
       PARTITION BY saas_app_id
       ORDER BY
-        CASE WHEN binding_source = 'manual' THEN 0 ELSE 1 END,
+        CASE binding_source WHEN 'manual' THEN 0 WHEN 'auto' THEN 1 ELSE 2 END,
         confidence DESC,
         id ASC
-    ) AS rn
+    ) AS rn,
+    binding_source
   FROM saas_app_bindings
 )
 UPDATE saas_app_bindings b
 SET
-  is_primary = (r.rn = 1),
+  is_primary = (r.rn = 1 AND r.binding_source <> 'suggested'),
   updated_at = now()
 FROM ranked r
 WHERE b.id = r.id
-  AND b.is_primary IS DISTINCT FROM (r.rn = 1)
+  AND b.is_primary IS DISTINCT FROM (r.rn = 1 AND r.binding_source <> 'suggested')
 `
 
 func (q *Queries) RecomputePrimarySaaSAppBindingsForAll(ctx context.Context) (int64, error) {
@@ -71,7 +114,7 @@ ON CONFLICT (saas_app_id, connector_kind, connector_source_name) DO UPDATE SET
   updated_at = now()
 WHERE NOT (
   saas_app_bindings.binding_source = 'manual'
-  AND EXCLUDED.binding_source = 'auto'
+  AND EXCLUDED.binding_source IN ('auto', 'suggested')
 )
 `
 
@@ -117,12 +117,320 @@ func (q *Queries) ListSaaSAppEventsBySaaSAppID(ctx context.Context, arg ListSaaS
 	return items, nil
 }
 
+const listSaaSAppEventsExportPage = `-- name: ListSaaSAppEventsExportPage :many
+SELECT
+  e.id,
+  e.saas_app_id,
+  sa.canonical_key AS saas_app_canonical_key,
+  sa.display_name AS saas_app_display_name,
+  e.source_kind,
+  e.source_name,
+  e.signal_kind,
+  e.event_external_id,
+  e.source_app_id,
+  e.source_app_name,
+  e.source_app_domain,
+  e.actor_external_id,
+  e.actor_email,
+  e.actor_display_name,
+  e.observed_at,
+  e.scopes_json,
+  e.raw_json,
+  e.expired_at
+FROM saas_app_events e
+JOIN saas_apps sa ON sa.id = e.saas_app_id
+WHERE e.id > $1::bigint
+  AND ($2::text = '' OR e.source_kind = $2::text)
+  AND ($3::text = '' OR e.source_name = $3::text)
+  AND ($4::text = '' OR e.signal_kind = $4::text)
+  AND ($5::timestamptz IS NULL OR e.observed_at >= $5::timestamptz)
+  AND ($6::timestamptz IS NULL OR e.observed_at < $6::timestamptz)
+ORDER BY e.id ASC
+LIMIT $7::int
+`
+
+type ListSaaSAppEventsExportPageParams struct {
+	AfterID      int64              `json:"after_id"`
+	SourceKind   string             `json:"source_kind"`
+	SourceName   string             `json:"source_name"`
+	SignalKind   string             `json:"signal_kind"`
+	ObservedFrom pgtype.Timestamptz `json:"observed_from"`
+	ObservedTo   pgtype.Timestamptz `json:"observed_to"`
+	PageLimit    int32              `json:"page_limit"`
+}
+
+type ListSaaSAppEventsExportPageRow struct {
+	ID                  int64              `json:"id"`
+	SaasAppID           int64              `json:"saas_app_id"`
+	SaasAppCanonicalKey string             `json:"saas_app_canonical_key"`
+	SaasAppDisplayName  string             `json:"saas_app_display_name"`
+	SourceKind          string             `json:"source_kind"`
+	SourceName          string             `json:"source_name"`
+	SignalKind          string             `json:"signal_kind"`
+	EventExternalID     string             `json:"event_external_id"`
+	SourceAppID         string             `json:"source_app_id"`
+	SourceAppName       string             `json:"source_app_name"`
+	SourceAppDomain     string             `json:"source_app_domain"`
+	ActorExternalID     string             `json:"actor_external_id"`
+	ActorEmail          string             `json:"actor_email"`
+	ActorDisplayName    string             `json:"actor_display_name"`
+	ObservedAt          pgtype.Timestamptz `json:"observed_at"`
+	ScopesJson          []byte             `json:"scopes_json"`
+	RawJson             []byte             `json:"raw_json"`
+	ExpiredAt           pgtype.Timestamptz `json:"expired_at"`
+}
+
+func (q *Queries) ListSaaSAppEventsExportPage(ctx context.Context, arg ListSaaSAppEventsExportPageParams) ([]ListSaaSAppEventsExportPageRow, error) {
+	rows, err := q.db.Query(ctx, listSaaSAppEventsExportPage,
+		arg.AfterID,
+		arg.SourceKind,
+		arg.SourceName,
+		arg.SignalKind,
+		arg.ObservedFrom,
+		arg.ObservedTo,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSaaSAppEventsExportPageRow
+	for rows.Next() {
+		var i ListSaaSAppEventsExportPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SaasAppID,
+			&i.SaasAppCanonicalKey,
+			&i.SaasAppDisplayName,
+			&i.SourceKind,
+			&i.SourceName,
+			&i.SignalKind,
+			&i.EventExternalID,
+			&i.SourceAppID,
+			&i.SourceAppName,
+			&i.SourceAppDomain,
+			&i.ActorExternalID,
+			&i.ActorEmail,
+			&i.ActorDisplayName,
+			&i.ObservedAt,
+			&i.ScopesJson,
+			&i.RawJson,
+			&i.ExpiredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSaaSAppOAuthGrantsByActors = `-- name: ListSaaSAppOAuthGrantsByActors :many
+WITH actor_accounts AS (
+  SELECT k.kind AS source_kind, n.name AS source_name, e.external_id
+  FROM unnest($3::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest($4::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+  JOIN unnest($5::text[]) WITH ORDINALITY AS e(external_id, ord) USING (ord)
+)
+SELECT
+  ev.saas_app_id,
+  sa.canonical_key AS saas_app_canonical_key,
+  sa.display_name AS saas_app_display_name,
+  ev.source_kind,
+  ev.source_name,
+  count(*) AS event_count,
+  min(ev.observed_at)::timestamptz AS first_observed_at,
+  max(ev.observed_at)::timestamptz AS last_observed_at,
+  (array_agg(ev.scopes_json ORDER BY ev.observed_at DESC, ev.id DESC))[1]::jsonb AS latest_scopes_json
+FROM saas_app_events ev
+JOIN saas_apps sa ON sa.id = ev.saas_app_id
+WHERE
+  ev.signal_kind = 'oauth_grant'
+  AND ev.expired_at IS NULL
+  AND ev.last_observed_run_id IS NOT NULL
+  AND (
+    lower(trim(ev.actor_email)) = ANY($1::text[])
+    OR lower(trim(ev.actor_external_id)) = ANY($1::text[])
+    OR EXISTS (
+      SELECT 1
+      FROM actor_accounts act
+      WHERE act.source_kind = ev.source_kind
+        AND act.source_name = ev.source_name
+        AND act.external_id = lower(trim(ev.actor_external_id))
+    )
+  )
+GROUP BY ev.saas_app_id, sa.canonical_key, sa.display_name, ev.source_kind, ev.source_name
+ORDER BY max(ev.observed_at) DESC, ev.saas_app_id ASC, ev.source_kind, ev.source_name
+LIMIT $2::int
+`
+
+type ListSaaSAppOAuthGrantsByActorsParams struct {
+	Emails      []string `json:"emails"`
+	RowLimit    int32    `json:"row_limit"`
+	SourceKinds []string `json:"source_kinds"`
+	SourceNames []string `json:"source_names"`
+	ExternalIds []string `json:"external_ids"`
+}
+
+type ListSaaSAppOAuthGrantsByActorsRow struct {
+	SaasAppID           int64              `json:"saas_app_id"`
+	SaasAppCanonicalKey string             `json:"saas_app_canonical_key"`
+	SaasAppDisplayName  string             `json:"saas_app_display_name"`
+	SourceKind          string             `json:"source_kind"`
+	SourceName          string             `json:"source_name"`
+	EventCount          int64              `json:"event_count"`
+	FirstObservedAt     pgtype.Timestamptz `json:"first_observed_at"`
+	LastObservedAt      pgtype.Timestamptz `json:"last_observed_at"`
+	LatestScopesJson    []byte             `json:"latest_scopes_json"`
+}
+
+func (q *Queries) ListSaaSAppOAuthGrantsByActors(ctx context.Context, arg ListSaaSAppOAuthGrantsByActorsParams) ([]ListSaaSAppOAuthGrantsByActorsRow, error) {
+	rows, err := q.db.Query(ctx, listSaaSAppOAuthGrantsByActors,
+		arg.Emails,
+		arg.RowLimit,
+		arg.SourceKinds,
+		arg.SourceNames,
+		arg.ExternalIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSaaSAppOAuthGrantsByActorsRow
+	for rows.Next() {
+		var i ListSaaSAppOAuthGrantsByActorsRow
+		if err := rows.Scan(
+			&i.SaasAppID,
+			&i.SaasAppCanonicalKey,
+			&i.SaasAppDisplayName,
+			&i.SourceKind,
+			&i.SourceName,
+			&i.EventCount,
+			&i.FirstObservedAt,
+			&i.LastObservedAt,
+			&i.LatestScopesJson,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSaaSAppSignalKindCountsBySaaSAppID = `-- name: ListSaaSAppSignalKindCountsBySaaSAppID :many
+SELECT
+  lower(trim(signal_kind))::text AS signal_kind,
+  count(*)::bigint AS event_count,
+  count(DISTINCT COALESCE(
+    NULLIF(lower(trim(actor_email)), ''),
+    NULLIF(trim(actor_external_id), ''),
+    NULLIF(trim(actor_display_name), '')
+  ))::bigint AS actor_count,
+  max(observed_at)::timestamptz AS last_observed_at
+FROM saas_app_events
+WHERE saas_app_id = $1::bigint
+  AND expired_at IS NULL
+  AND last_observed_run_id IS NOT NULL
+GROUP BY 1
+ORDER BY event_count DESC, signal_kind
+`
+
+type ListSaaSAppSignalKindCountsBySaaSAppIDRow struct {
+	SignalKind     string             `json:"signal_kind"`
+	EventCount     int64              `json:"event_count"`
+	ActorCount     int64              `json:"actor_count"`
+	LastObservedAt pgtype.Timestamptz `json:"last_observed_at"`
+}
+
+// Active events and distinct actors per signal kind for a discovered app, so SSO-federated
+// use can be told apart from user-consented OAuth grants.
+func (q *Queries) ListSaaSAppSignalKindCountsBySaaSAppID(ctx context.Context, saasAppID int64) ([]ListSaaSAppSignalKindCountsBySaaSAppIDRow, error) {
+	rows, err := q.db.Query(ctx, listSaaSAppSignalKindCountsBySaaSAppID, saasAppID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSaaSAppSignalKindCountsBySaaSAppIDRow
+	for rows.Next() {
+		var i ListSaaSAppSignalKindCountsBySaaSAppIDRow
+		if err := rows.Scan(
+			&i.SignalKind,
+			&i.EventCount,
+			&i.ActorCount,
+			&i.LastObservedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSaaSAppWeeklyActorCountsBySaaSAppID = `-- name: ListSaaSAppWeeklyActorCountsBySaaSAppID :many
+SELECT
+  (date_trunc('week', observed_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC')::timestamptz AS week_start,
+  count(DISTINCT COALESCE(
+    NULLIF(lower(trim(actor_email)), ''),
+    NULLIF(trim(actor_external_id), ''),
+    NULLIF(trim(actor_display_name), '')
+  ))::bigint AS actor_count
+FROM saas_app_events
+WHERE saas_app_id = $1::bigint
+  AND last_observed_run_id IS NOT NULL
+  AND observed_at >= $2::timestamptz
+GROUP BY week_start
+ORDER BY week_start
+`
+
+type ListSaaSAppWeeklyActorCountsBySaaSAppIDParams struct {
+	SaasAppID     int64              `json:"saas_app_id"`
+	ObservedSince pgtype.Timestamptz `json:"observed_since"`
+}
+
+type ListSaaSAppWeeklyActorCountsBySaaSAppIDRow struct {
+	WeekStart  pgtype.Timestamptz `json:"week_start"`
+	ActorCount int64              `json:"actor_count"`
+}
+
+// Distinct actors per UTC week (weeks start Monday) observed using a discovered app since a
+// cutoff. Expired events are kept so the series covers weeks older than the active window.
+func (q *Queries) ListSaaSAppWeeklyActorCountsBySaaSAppID(ctx context.Context, arg ListSaaSAppWeeklyActorCountsBySaaSAppIDParams) ([]ListSaaSAppWeeklyActorCountsBySaaSAppIDRow, error) {
+	rows, err := q.db.Query(ctx, listSaaSAppWeeklyActorCountsBySaaSAppID, arg.SaasAppID, arg.ObservedSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSaaSAppWeeklyActorCountsBySaaSAppIDRow
+	for rows.Next() {
+		var i ListSaaSAppWeeklyActorCountsBySaaSAppIDRow
+		if err := rows.Scan(&i.WeekStart, &i.ActorCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTopActorsForSaaSAppByID = `-- name: ListTopActorsForSaaSAppByID :many
 SELECT
   COALESCE(NULLIF(trim(actor_display_name), ''), NULLIF(trim(actor_email), ''), NULLIF(trim(actor_external_id), ''), '')::text AS actor_label,
   COALESCE(NULLIF(trim(actor_email), ''), '')::text AS actor_email,
   COALESCE(NULLIF(trim(actor_external_id), ''), '')::text AS actor_external_id,
+  (array_agg(source_kind ORDER BY observed_at DESC))[1]::text AS source_kind,
+  (array_agg(source_name ORDER BY observed_at DESC))[1]::text AS source_name,
   count(*) AS event_count,
+  min(observed_at)::timestamptz AS first_observed_at,
   max(observed_at)::timestamptz AS last_observed_at
 FROM saas_app_events
 WHERE saas_app_id = $1::bigint
@@ -143,7 +451,10 @@ type ListTopActorsForSaaSAppByIDRow struct {
 	ActorLabel      string             `json:"actor_label"`
 	ActorEmail      string             `json:"actor_email"`
 	ActorExternalID string             `json:"actor_external_id"`
+	SourceKind      string             `json:"source_kind"`
+	SourceName      string             `json:"source_name"`
 	EventCount      int64              `json:"event_count"`
+	FirstObservedAt pgtype.Timestamptz `json:"first_observed_at"`
 	LastObservedAt  pgtype.Timestamptz `json:"last_observed_at"`
 }
 
@@ -160,7 +471,10 @@ func (q *Queries) ListTopActorsForSaaSAppByID(ctx context.Context, arg ListTopAc
 			&i.ActorLabel,
 			&i.ActorEmail,
 			&i.ActorExternalID,
+			&i.SourceKind,
+			&i.SourceName,
 			&i.EventCount,
+			&i.FirstObservedAt,
 			&i.LastObservedAt,
 		); err != nil {
 			return nil, err
@@ -199,25 +513,25 @@ func (q *Queries) PromoteSaaSAppEventsSeenInRunBySource(ctx context.Context, arg
 	return result.RowsAffected(), nil
 }
 
-const upsertSaaSAppEventsBulkBySource = `-- name: UpsertSaaSAppEventsBulkBySource :execrows
+const upsertSaaSAppEventsBulkBySource = `-- name: UpsertSaaSAppEventsBulkBySource :many
 WITH input AS (
   SELECT
     i,
-    $2::text AS source_kind,
-    $3::text AS source_name,
-    ($4::text[])[i] AS canonical_key,
-    ($5::text[])[i] AS signal_kind,
-    ($6::text[])[i] AS event_external_id,
-    ($7::text[])[i] AS source_app_id,
-    ($8::text[])[i] AS source_app_name,
-    ($9::text[])[i] AS source_app_domain,
-    ($10::text[])[i] AS actor_external_id,
-    ($11::text[])[i] AS actor_email,
-    ($12::text[])[i] AS actor_display_name,
-    ($13::timestamptz[])[i] AS observed_at,
-    ($14::jsonb[])[i] AS scopes_json,
-    ($15::jsonb[])[i] AS raw_json
-  FROM generate_subscripts($6::text[], 1) AS s(i)
+    $1::text AS source_kind,
+    $2::text AS source_name,
+    ($3::text[])[i] AS canonical_key,
+    ($4::text[])[i] AS signal_kind,
+    ($5::text[])[i] AS event_external_id,
+    ($6::text[])[i] AS source_app_id,
+    ($7::text[])[i] AS source_app_name,
+    ($8::text[])[i] AS source_app_domain,
+    ($9::text[])[i] AS actor_external_id,
+    ($10::text[])[i] AS actor_email,
+    ($11::text[])[i] AS actor_display_name,
+    ($12::timestamptz[])[i] AS observed_at,
+    ($13::jsonb[])[i] AS scopes_json,
+    ($14::jsonb[])[i] AS raw_json
+  FROM generate_subscripts($5::text[], 1) AS s(i)
 ),
 dedup AS (
   SELECT DISTINCT ON (source_kind, source_name, signal_kind, event_external_id)
@@ -238,64 +552,73 @@ dedup AS (
   FROM input
   WHERE trim(event_external_id) <> ''
   ORDER BY source_kind, source_name, signal_kind, event_external_id, i DESC
-)
-INSERT INTO saas_app_events (
-  saas_app_id,
-  source_kind,
-  source_name,
-  signal_kind,
-  event_external_id,
-  source_app_id,
-  source_app_name,
-  source_app_domain,
-  actor_external_id,
-  actor_email,
-  actor_display_name,
-  observed_at,
-  scopes_json,
-  raw_json,
-  seen_in_run_id,
-  seen_at,
-  updated_at
+),
+upserted AS (
+  INSERT INTO saas_app_events (
+    saas_app_id,
+    source_kind,
+    source_name,
+    signal_kind,
+    event_external_id,
+    source_app_id,
+    source_app_name,
+    source_app_domain,
+    actor_external_id,
+    actor_email,
+    actor_display_name,
+    observed_at,
+    scopes_json,
+    raw_json,
+    seen_in_run_id,
+    seen_at,
+    updated_at
+  )
+  SELECT
+    sa.id,
+    d.source_kind,
+    d.source_name,
+    d.signal_kind,
+    d.event_external_id,
+    d.source_app_id,
+    d.source_app_name,
+    d.source_app_domain,
+    d.actor_external_id,
+    lower(trim(d.actor_email)),
+    d.actor_display_name,
+    d.observed_at,
+    COALESCE(d.scopes_json, '[]'::jsonb),
+    COALESCE(d.raw_json, '{}'::jsonb),
+    $15::bigint,
+    now(),
+    now()
+  FROM dedup d
+  JOIN saas_apps sa ON sa.canonical_key = d.canonical_key
+  ON CONFLICT (source_kind, source_name, signal_kind, event_external_id) DO UPDATE SET
+    saas_app_id = EXCLUDED.saas_app_id,
+    source_app_id = EXCLUDED.source_app_id,
+    source_app_name = EXCLUDED.source_app_name,
+    source_app_domain = EXCLUDED.source_app_domain,
+    actor_external_id = EXCLUDED.actor_external_id,
+    actor_email = EXCLUDED.actor_email,
+    actor_display_name = EXCLUDED.actor_display_name,
+    observed_at = EXCLUDED.observed_at,
+    scopes_json = EXCLUDED.scopes_json,
+    raw_json = EXCLUDED.raw_json,
+    seen_in_run_id = EXCLUDED.seen_in_run_id,
+    seen_at = EXCLUDED.seen_at,
+    updated_at = now()
+  RETURNING signal_kind, (xmax = 0) AS inserted
 )
 SELECT
-  sa.id,
-  d.source_kind,
-  d.source_name,
-  d.signal_kind,
-  d.event_external_id,
-  d.source_app_id,
-  d.source_app_name,
-  d.source_app_domain,
-  d.actor_external_id,
-  lower(trim(d.actor_email)),
-  d.actor_display_name,
-  d.observed_at,
-  COALESCE(d.scopes_json, '[]'::jsonb),
-  COALESCE(d.raw_json, '{}'::jsonb),
-  $1::bigint,
-  now(),
-  now()
-FROM dedup d
-JOIN saas_apps sa ON sa.canonical_key = d.canonical_key
-ON CONFLICT (source_kind, source_name, signal_kind, event_external_id) DO UPDATE SET
-  saas_app_id = EXCLUDED.saas_app_id,
-  source_app_id = EXCLUDED.source_app_id,
-  source_app_name = EXCLUDED.source_app_name,
-  source_app_domain = EXCLUDED.source_app_domain,
-  actor_external_id = EXCLUDED.actor_external_id,
-  actor_email = EXCLUDED.actor_email,
-  actor_display_name = EXCLUDED.actor_display_name,
-  observed_at = EXCLUDED.observed_at,
-  scopes_json = EXCLUDED.scopes_json,
-  raw_json = EXCLUDED.raw_json,
-  seen_in_run_id = EXCLUDED.seen_in_run_id,
-  seen_at = EXCLUDED.seen_at,
-  updated_at = now()
+  signal_kind,
+  count(*) FILTER (WHERE inserted)::bigint AS inserted_count,
+  count(*) FILTER (WHERE NOT inserted)::bigint AS updated_count
+FROM upserted
+GROUP BY signal_kind
+ORDER BY signal_kind
 `
 
 type UpsertSaaSAppEventsBulkBySourceParams struct {
-	SeenInRunID       int64                `json:"seen_in_run_id"`
 	SourceKind        string               `json:"source_kind"`
 	SourceName        string               `json:"source_name"`
 	CanonicalKeys     []string             `json:"canonical_keys"`
@@ -310,11 +633,17 @@ type UpsertSaaSAppEventsBulkBySourceParams struct {
 	ObservedAts       []pgtype.Timestamptz `json:"observed_ats"`
 	ScopesJsons       [][]byte             `json:"scopes_jsons"`
 	RawJsons          [][]byte             `json:"raw_jsons"`
+	SeenInRunID       int64                `json:"seen_in_run_id"`
 }
 
-func (q *Queries) UpsertSaaSAppEventsBulkBySource(ctx context.Context, arg UpsertSaaSAppEventsBulkBySourceParams) (int64, error) {
-	result, err := q.db.Exec(ctx, upsertSaaSAppEventsBulkBySource,
-		arg.SeenInRunID,
+type UpsertSaaSAppEventsBulkBySourceRow struct {
+	SignalKind    string `json:"signal_kind"`
+	InsertedCount int64  `json:"inserted_count"`
+	UpdatedCount  int64  `json:"updated_count"`
+}
+
+func (q *Queries) UpsertSaaSAppEventsBulkBySource(ctx context.Context, arg UpsertSaaSAppEventsBulkBySourceParams) ([]UpsertSaaSAppEventsBulkBySourceRow, error) {
+	rows, err := q.db.Query(ctx, upsertSaaSAppEventsBulkBySource,
 		arg.SourceKind,
 		arg.SourceName,
 		arg.CanonicalKeys,
@@ -329,9 +658,22 @@ func (q *Queries) UpsertSaaSAppEventsBulkBySource(ctx context.Context, arg Upser
 		arg.ObservedAts,
 		arg.ScopesJsons,
 		arg.RawJsons,
+		arg.SeenInRunID,
 	)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return result.RowsAffected(), nil
+	defer rows.Close()
+	var items []UpsertSaaSAppEventsBulkBySourceRow
+	for rows.Next() {
+		var i UpsertSaaSAppEventsBulkBySourceRow
+		if err := rows.Scan(&i.SignalKind, &i.InsertedCount, &i.UpdatedCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
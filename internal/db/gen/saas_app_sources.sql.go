@@ -11,6 +11,42 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countSaaSAppSourcesGroupedBySource = `-- name: CountSaaSAppSourcesGroupedBySource :many
+SELECT sas.source_kind, sas.source_name, count(DISTINCT sas.saas_app_id) AS app_count
+FROM saas_app_sources sas
+WHERE
+  sas.expired_at IS NULL
+  AND sas.last_observed_run_id IS NOT NULL
+GROUP BY sas.source_kind, sas.source_name
+ORDER BY sas.source_kind, sas.source_name
+`
+
+type CountSaaSAppSourcesGroupedBySourceRow struct {
+	SourceKind string `json:"source_kind"`
+	SourceName string `json:"source_name"`
+	AppCount   int64  `json:"app_count"`
+}
+
+func (q *Queries) CountSaaSAppSourcesGroupedBySource(ctx context.Context) ([]CountSaaSAppSourcesGroupedBySourceRow, error) {
+	rows, err := q.db.Query(ctx, countSaaSAppSourcesGroupedBySource)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountSaaSAppSourcesGroupedBySourceRow
+	for rows.Next() {
+		var i CountSaaSAppSourcesGroupedBySourceRow
+		if err := rows.Scan(&i.SourceKind, &i.SourceName, &i.AppCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const expireSaaSAppSourcesNotSeenInRunBySource = `-- name: ExpireSaaSAppSourcesNotSeenInRunBySource :execrows
 UPDATE saas_app_sources
 SET
@@ -37,8 +73,13 @@ func (q *Queries) ExpireSaaSAppSourcesNotSeenInRunBySource(ctx context.Context,
 	return result.RowsAffected(), nil
 }
 
-const listEntraDiscoveryAppIDsWithManagedAssetsBySource = `-- name: ListEntraDiscoveryAppIDsWithManagedAssetsBySource :many
-SELECT DISTINCT sas.saas_app_id
+const listEntraDiscoveryAutoBindCandidatesBySource = `-- name: ListEntraDiscoveryAutoBindCandidatesBySource :many
+SELECT
+  sas.saas_app_id,
+  bool_or(
+    sas.source_app_id <> ''
+    AND (aa.external_id = sas.source_app_id OR aa.parent_external_id = sas.source_app_id)
+  )::boolean AS client_id_match
 FROM saas_app_sources sas
 JOIN app_assets aa
   ON aa.source_kind = 'entra'
@@ -55,22 +96,28 @@ WHERE sas.source_kind = 'entra'
   AND sas.source_name = $1::text
   AND sas.expired_at IS NULL
   AND sas.last_observed_run_id IS NOT NULL
+GROUP BY sas.saas_app_id
 ORDER BY sas.saas_app_id ASC
 `
 
-func (q *Queries) ListEntraDiscoveryAppIDsWithManagedAssetsBySource(ctx context.Context, sourceName string) ([]int64, error) {
-	rows, err := q.db.Query(ctx, listEntraDiscoveryAppIDsWithManagedAssetsBySource, sourceName)
+type ListEntraDiscoveryAutoBindCandidatesBySourceRow struct {
+	SaasAppID     int64 `json:"saas_app_id"`
+	ClientIDMatch bool  `json:"client_id_match"`
+}
+
+func (q *Queries) ListEntraDiscoveryAutoBindCandidatesBySource(ctx context.Context, sourceName string) ([]ListEntraDiscoveryAutoBindCandidatesBySourceRow, error) {
+	rows, err := q.db.Query(ctx, listEntraDiscoveryAutoBindCandidatesBySource, sourceName)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []int64
+	var items []ListEntraDiscoveryAutoBindCandidatesBySourceRow
 	for rows.Next() {
-		var saas_app_id int64
-		if err := rows.Scan(&saas_app_id); err != nil {
+		var i ListEntraDiscoveryAutoBindCandidatesBySourceRow
+		if err := rows.Scan(&i.SaasAppID, &i.ClientIDMatch); err != nil {
 			return nil, err
 		}
-		items = append(items, saas_app_id)
+		items = append(items, i)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
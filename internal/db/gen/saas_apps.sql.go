@@ -16,8 +16,8 @@ WITH configured_sources AS (
   SELECT
     k.kind AS source_kind,
     n.name AS source_name
-  FROM unnest($6::text[]) WITH ORDINALITY AS k(kind, ord)
-  JOIN unnest($7::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+  FROM unnest($7::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest($8::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
 )
 SELECT count(*)
 FROM saas_apps sa
@@ -49,10 +49,14 @@ WHERE EXISTS (
   )
   AND (
     $5::text = ''
-    OR sa.display_name ILIKE ('%' || $5::text || '%')
-    OR sa.primary_domain ILIKE ('%' || $5::text || '%')
-    OR sa.vendor_name ILIKE ('%' || $5::text || '%')
-    OR sa.canonical_key ILIKE ('%' || $5::text || '%')
+    OR sa.category = $5::text
+  )
+  AND (
+    $6::text = ''
+    OR sa.display_name ILIKE ('%' || $6::text || '%')
+    OR sa.primary_domain ILIKE ('%' || $6::text || '%')
+    OR sa.vendor_name ILIKE ('%' || $6::text || '%')
+    OR sa.canonical_key ILIKE ('%' || $6::text || '%')
   )
 `
 
@@ -61,6 +65,7 @@ type CountSaaSAppsByFiltersParams struct {
 	SourceName            string   `json:"source_name"`
 	ManagedState          string   `json:"managed_state"`
 	RiskLevel             string   `json:"risk_level"`
+	Category              string   `json:"category"`
 	Query                 string   `json:"query"`
 	ConfiguredSourceKinds []string `json:"configured_source_kinds"`
 	ConfiguredSourceNames []string `json:"configured_source_names"`
@@ -72,6 +77,7 @@ func (q *Queries) CountSaaSAppsByFilters(ctx context.Context, arg CountSaaSAppsB
 		arg.SourceName,
 		arg.ManagedState,
 		arg.RiskLevel,
+		arg.Category,
 		arg.Query,
 		arg.ConfiguredSourceKinds,
 		arg.ConfiguredSourceNames,
@@ -81,6 +87,97 @@ func (q *Queries) CountSaaSAppsByFilters(ctx context.Context, arg CountSaaSAppsB
 	return count, err
 }
 
+const countSaaSAppsGroupedByCategory = `-- name: CountSaaSAppsGroupedByCategory :many
+WITH configured_sources AS (
+  SELECT
+    k.kind AS source_kind,
+    n.name AS source_name
+  FROM unnest($6::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest($7::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+)
+SELECT sa.category, count(*) AS app_count
+FROM saas_apps sa
+WHERE EXISTS (
+  SELECT 1
+  FROM saas_app_sources sas
+  JOIN configured_sources cs
+    ON cs.source_kind = sas.source_kind
+   AND cs.source_name = sas.source_name
+  WHERE sas.saas_app_id = sa.id
+    AND sas.expired_at IS NULL
+    AND sas.last_observed_run_id IS NOT NULL
+    AND (
+      $1::text = ''
+      OR sas.source_kind = $1::text
+    )
+    AND (
+      $2::text = ''
+      OR sas.source_name = $2::text
+    )
+)
+  AND sa.category <> ''
+  AND (
+    $3::text = ''
+    OR sa.managed_state = $3::text
+  )
+  AND (
+    $4::text = ''
+    OR sa.risk_level = $4::text
+  )
+  AND (
+    $5::text = ''
+    OR sa.display_name ILIKE ('%' || $5::text || '%')
+    OR sa.primary_domain ILIKE ('%' || $5::text || '%')
+    OR sa.vendor_name ILIKE ('%' || $5::text || '%')
+    OR sa.canonical_key ILIKE ('%' || $5::text || '%')
+  )
+GROUP BY sa.category
+ORDER BY app_count DESC, sa.category
+`
+
+type CountSaaSAppsGroupedByCategoryParams struct {
+	SourceKind            string   `json:"source_kind"`
+	SourceName            string   `json:"source_name"`
+	ManagedState          string   `json:"managed_state"`
+	RiskLevel             string   `json:"risk_level"`
+	Query                 string   `json:"query"`
+	ConfiguredSourceKinds []string `json:"configured_source_kinds"`
+	ConfiguredSourceNames []string `json:"configured_source_names"`
+}
+
+type CountSaaSAppsGroupedByCategoryRow struct {
+	Category string `json:"category"`
+	AppCount int64  `json:"app_count"`
+}
+
+func (q *Queries) CountSaaSAppsGroupedByCategory(ctx context.Context, arg CountSaaSAppsGroupedByCategoryParams) ([]CountSaaSAppsGroupedByCategoryRow, error) {
+	rows, err := q.db.Query(ctx, countSaaSAppsGroupedByCategory,
+		arg.SourceKind,
+		arg.SourceName,
+		arg.ManagedState,
+		arg.RiskLevel,
+		arg.Query,
+		arg.ConfiguredSourceKinds,
+		arg.ConfiguredSourceNames,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountSaaSAppsGroupedByCategoryRow
+	for rows.Next() {
+		var i CountSaaSAppsGroupedByCategoryRow
+		if err := rows.Scan(&i.Category, &i.AppCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const countSaaSAppsGroupedByManagedState = `-- name: CountSaaSAppsGroupedByManagedState :many
 WITH configured_sources AS (
   SELECT
@@ -190,7 +287,7 @@ func (q *Queries) CountSaaSAppsGroupedByRiskLevel(ctx context.Context, arg Count
 }
 
 const getSaaSAppByID = `-- name: GetSaaSAppByID :one
-SELECT id, canonical_key, display_name, primary_domain, vendor_name, managed_state, managed_reason, bound_connector_kind, bound_connector_source_name, risk_score, risk_level, suggested_business_criticality, suggested_data_classification, first_seen_at, last_seen_at, created_at, updated_at
+SELECT id, canonical_key, display_name, primary_domain, vendor_name, managed_state, managed_reason, bound_connector_kind, bound_connector_source_name, risk_score, risk_level, suggested_business_criticality, suggested_data_classification, first_seen_at, last_seen_at, created_at, updated_at, category
 FROM saas_apps
 WHERE id = $1
 `
@@ -216,6 +313,7 @@ func (q *Queries) GetSaaSAppByID(ctx context.Context, id int64) (SaasApp, error)
 		&i.LastSeenAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Category,
 	)
 	return i, err
 }
@@ -229,7 +327,7 @@ WITH configured_sources AS (
   JOIN unnest($5::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
 )
 SELECT
-  sa.id, sa.canonical_key, sa.display_name, sa.primary_domain, sa.vendor_name, sa.managed_state, sa.managed_reason, sa.bound_connector_kind, sa.bound_connector_source_name, sa.risk_score, sa.risk_level, sa.suggested_business_criticality, sa.suggested_data_classification, sa.first_seen_at, sa.last_seen_at, sa.created_at, sa.updated_at,
+  sa.id, sa.canonical_key, sa.display_name, sa.primary_domain, sa.vendor_name, sa.managed_state, sa.managed_reason, sa.bound_connector_kind, sa.bound_connector_source_name, sa.risk_score, sa.risk_level, sa.suggested_business_criticality, sa.suggested_data_classification, sa.first_seen_at, sa.last_seen_at, sa.created_at, sa.updated_at, sa.category,
   go.owner_identity_id,
   COALESCE(owner.display_name, '') AS owner_display_name,
   COALESCE(owner.primary_email, '') AS owner_primary_email,
@@ -295,6 +393,7 @@ type ListSaaSAppHotspotsRow struct {
 	LastSeenAt                   pgtype.Timestamptz `json:"last_seen_at"`
 	CreatedAt                    pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt                    pgtype.Timestamptz `json:"updated_at"`
+	Category                     string             `json:"category"`
 	OwnerIdentityID              pgtype.Int8        `json:"owner_identity_id"`
 	OwnerDisplayName             string             `json:"owner_display_name"`
 	OwnerPrimaryEmail            string             `json:"owner_primary_email"`
@@ -334,6 +433,7 @@ func (q *Queries) ListSaaSAppHotspots(ctx context.Context, arg ListSaaSAppHotspo
 			&i.LastSeenAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Category,
 			&i.OwnerIdentityID,
 			&i.OwnerDisplayName,
 			&i.OwnerPrimaryEmail,
@@ -349,6 +449,133 @@ func (q *Queries) ListSaaSAppHotspots(ctx context.Context, arg ListSaaSAppHotspo
 	return items, nil
 }
 
+const listSaaSAppInventoryReport = `-- name: ListSaaSAppInventoryReport :many
+WITH configured_sources AS (
+  SELECT
+    k.kind AS source_kind,
+    n.name AS source_name
+  FROM unnest($1::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest($2::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+),
+active_sources AS (
+  SELECT sas.saas_app_id, sas.source_kind
+  FROM saas_app_sources sas
+  JOIN configured_sources cs
+    ON cs.source_kind = sas.source_kind
+   AND cs.source_name = sas.source_name
+  WHERE sas.expired_at IS NULL
+    AND sas.last_observed_run_id IS NOT NULL
+),
+source_kinds AS (
+  SELECT
+    s.saas_app_id,
+    string_agg(DISTINCT s.source_kind, ',' ORDER BY s.source_kind) AS discovery_sources
+  FROM active_sources s
+  GROUP BY s.saas_app_id
+),
+actor_counts AS (
+  SELECT
+    e.saas_app_id,
+    count(DISTINCT COALESCE(NULLIF(trim(e.actor_external_id), ''), NULLIF(lower(trim(e.actor_email)), ''))) AS user_count,
+    count(DISTINCT COALESCE(NULLIF(trim(e.actor_external_id), ''), NULLIF(lower(trim(e.actor_email)), ''))) FILTER (WHERE e.observed_at >= now() - interval '30 days') AS actors_30d
+  FROM saas_app_events e
+  WHERE e.expired_at IS NULL
+    AND e.last_observed_run_id IS NOT NULL
+  GROUP BY e.saas_app_id
+),
+primary_binding AS (
+  SELECT
+    b.saas_app_id,
+    b.connector_kind,
+    b.connector_source_name,
+    b.binding_source
+  FROM saas_app_bindings b
+  WHERE b.is_primary
+)
+SELECT
+  sa.id,
+  sa.canonical_key,
+  sa.display_name,
+  sa.primary_domain,
+  sa.vendor_name,
+  sa.managed_state,
+  sa.risk_level,
+  sa.first_seen_at,
+  sa.last_seen_at,
+  COALESCE(ac.user_count, 0)::bigint AS user_count,
+  COALESCE(ac.actors_30d, 0)::bigint AS actors_30d,
+  sk.discovery_sources::text AS discovery_sources,
+  COALESCE(pb.connector_kind, '')::text AS binding_connector_kind,
+  COALESCE(pb.connector_source_name, '')::text AS binding_connector_source_name,
+  COALESCE(pb.binding_source, '')::text AS binding_source
+FROM saas_apps sa
+JOIN source_kinds sk ON sk.saas_app_id = sa.id
+LEFT JOIN actor_counts ac ON ac.saas_app_id = sa.id
+LEFT JOIN primary_binding pb ON pb.saas_app_id = sa.id
+ORDER BY
+  lower(COALESCE(NULLIF(trim(sa.display_name), ''), sa.canonical_key)) ASC,
+  sa.id ASC
+`
+
+type ListSaaSAppInventoryReportParams struct {
+	ConfiguredSourceKinds []string `json:"configured_source_kinds"`
+	ConfiguredSourceNames []string `json:"configured_source_names"`
+}
+
+type ListSaaSAppInventoryReportRow struct {
+	ID                         int64              `json:"id"`
+	CanonicalKey               string             `json:"canonical_key"`
+	DisplayName                string             `json:"display_name"`
+	PrimaryDomain              string             `json:"primary_domain"`
+	VendorName                 string             `json:"vendor_name"`
+	ManagedState               string             `json:"managed_state"`
+	RiskLevel                  string             `json:"risk_level"`
+	FirstSeenAt                pgtype.Timestamptz `json:"first_seen_at"`
+	LastSeenAt                 pgtype.Timestamptz `json:"last_seen_at"`
+	UserCount                  int64              `json:"user_count"`
+	Actors30d                  int64              `json:"actors_30d"`
+	DiscoverySources           string             `json:"discovery_sources"`
+	BindingConnectorKind       string             `json:"binding_connector_kind"`
+	BindingConnectorSourceName string             `json:"binding_connector_source_name"`
+	BindingSource              string             `json:"binding_source"`
+}
+
+func (q *Queries) ListSaaSAppInventoryReport(ctx context.Context, arg ListSaaSAppInventoryReportParams) ([]ListSaaSAppInventoryReportRow, error) {
+	rows, err := q.db.Query(ctx, listSaaSAppInventoryReport, arg.ConfiguredSourceKinds, arg.ConfiguredSourceNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSaaSAppInventoryReportRow
+	for rows.Next() {
+		var i ListSaaSAppInventoryReportRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CanonicalKey,
+			&i.DisplayName,
+			&i.PrimaryDomain,
+			&i.VendorName,
+			&i.ManagedState,
+			&i.RiskLevel,
+			&i.FirstSeenAt,
+			&i.LastSeenAt,
+			&i.UserCount,
+			&i.Actors30d,
+			&i.DiscoverySources,
+			&i.BindingConnectorKind,
+			&i.BindingConnectorSourceName,
+			&i.BindingSource,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSaaSAppPostureInputs = `-- name: ListSaaSAppPostureInputs :many
 WITH active_events AS (
   SELECT e.id, e.saas_app_id, e.source_kind, e.source_name, e.signal_kind, e.event_external_id, e.source_app_id, e.source_app_name, e.source_app_domain, e.actor_external_id, e.actor_email, e.actor_display_name, e.observed_at, e.scopes_json, e.raw_json, e.seen_in_run_id, e.seen_at, e.last_observed_run_id, e.last_observed_at, e.expired_at, e.expired_run_id, e.created_at, e.updated_at
@@ -476,11 +703,11 @@ WITH configured_sources AS (
   SELECT
     k.kind AS source_kind,
     n.name AS source_name
-  FROM unnest($8::text[]) WITH ORDINALITY AS k(kind, ord)
-  JOIN unnest($9::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+  FROM unnest($9::text[]) WITH ORDINALITY AS k(kind, ord)
+  JOIN unnest($10::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
 )
 SELECT
-  sa.id, sa.canonical_key, sa.display_name, sa.primary_domain, sa.vendor_name, sa.managed_state, sa.managed_reason, sa.bound_connector_kind, sa.bound_connector_source_name, sa.risk_score, sa.risk_level, sa.suggested_business_criticality, sa.suggested_data_classification, sa.first_seen_at, sa.last_seen_at, sa.created_at, sa.updated_at,
+  sa.id, sa.canonical_key, sa.display_name, sa.primary_domain, sa.vendor_name, sa.managed_state, sa.managed_reason, sa.bound_connector_kind, sa.bound_connector_source_name, sa.risk_score, sa.risk_level, sa.suggested_business_criticality, sa.suggested_data_classification, sa.first_seen_at, sa.last_seen_at, sa.created_at, sa.updated_at, sa.category,
   go.owner_identity_id,
   COALESCE(owner.display_name, '') AS owner_display_name,
   COALESCE(owner.primary_email, '') AS owner_primary_email,
@@ -525,18 +752,22 @@ WHERE EXISTS (
   )
   AND (
     $5::text = ''
-    OR sa.display_name ILIKE ('%' || $5::text || '%')
-    OR sa.primary_domain ILIKE ('%' || $5::text || '%')
-    OR sa.vendor_name ILIKE ('%' || $5::text || '%')
-    OR sa.canonical_key ILIKE ('%' || $5::text || '%')
+    OR sa.category = $5::text
+  )
+  AND (
+    $6::text = ''
+    OR sa.display_name ILIKE ('%' || $6::text || '%')
+    OR sa.primary_domain ILIKE ('%' || $6::text || '%')
+    OR sa.vendor_name ILIKE ('%' || $6::text || '%')
+    OR sa.canonical_key ILIKE ('%' || $6::text || '%')
   )
 ORDER BY
   sa.risk_score DESC,
   sa.last_seen_at DESC,
   lower(COALESCE(NULLIF(trim(sa.display_name), ''), sa.canonical_key)) ASC,
   sa.id ASC
-LIMIT $7::int
-OFFSET $6::int
+LIMIT $8::int
+OFFSET $7::int
 `
 
 type ListSaaSAppsPageByFiltersParams struct {
@@ -544,6 +775,7 @@ type ListSaaSAppsPageByFiltersParams struct {
 	SourceName            string   `json:"source_name"`
 	ManagedState          string   `json:"managed_state"`
 	RiskLevel             string   `json:"risk_level"`
+	Category              string   `json:"category"`
 	Query                 string   `json:"query"`
 	PageOffset            int32    `json:"page_offset"`
 	PageLimit             int32    `json:"page_limit"`
@@ -569,6 +801,7 @@ type ListSaaSAppsPageByFiltersRow struct {
 	LastSeenAt                   pgtype.Timestamptz `json:"last_seen_at"`
 	CreatedAt                    pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt                    pgtype.Timestamptz `json:"updated_at"`
+	Category                     string             `json:"category"`
 	OwnerIdentityID              pgtype.Int8        `json:"owner_identity_id"`
 	OwnerDisplayName             string             `json:"owner_display_name"`
 	OwnerPrimaryEmail            string             `json:"owner_primary_email"`
@@ -581,6 +814,7 @@ func (q *Queries) ListSaaSAppsPageByFilters(ctx context.Context, arg ListSaaSApp
 		arg.SourceName,
 		arg.ManagedState,
 		arg.RiskLevel,
+		arg.Category,
 		arg.Query,
 		arg.PageOffset,
 		arg.PageLimit,
@@ -612,6 +846,7 @@ func (q *Queries) ListSaaSAppsPageByFilters(ctx context.Context, arg ListSaaSApp
 			&i.LastSeenAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Category,
 			&i.OwnerIdentityID,
 			&i.OwnerDisplayName,
 			&i.OwnerPrimaryEmail,
@@ -677,8 +912,9 @@ WITH input AS (
     ($2::text[])[i] AS display_name,
     ($3::text[])[i] AS primary_domain,
     ($4::text[])[i] AS vendor_name,
-    ($5::timestamptz[])[i] AS first_seen_at,
-    ($6::timestamptz[])[i] AS last_seen_at
+    ($5::text[])[i] AS category,
+    ($6::timestamptz[])[i] AS first_seen_at,
+    ($7::timestamptz[])[i] AS last_seen_at
   FROM generate_subscripts($1::text[], 1) AS s(i)
 ),
 dedup AS (
@@ -687,6 +923,7 @@ dedup AS (
     display_name,
     primary_domain,
     vendor_name,
+    category,
     first_seen_at,
     last_seen_at
   FROM input
@@ -697,6 +934,7 @@ INSERT INTO saas_apps (
   display_name,
   primary_domain,
   vendor_name,
+  category,
   first_seen_at,
   last_seen_at,
   updated_at
@@ -706,6 +944,7 @@ SELECT
   d.display_name,
   d.primary_domain,
   d.vendor_name,
+  d.category,
   COALESCE(d.first_seen_at, now()),
   COALESCE(d.last_seen_at, now()),
   now()
@@ -723,6 +962,10 @@ ON CONFLICT (canonical_key) DO UPDATE SET
     WHEN trim(EXCLUDED.vendor_name) <> '' THEN EXCLUDED.vendor_name
     ELSE saas_apps.vendor_name
   END,
+  category = CASE
+    WHEN trim(EXCLUDED.category) <> '' THEN EXCLUDED.category
+    ELSE saas_apps.category
+  END,
   first_seen_at = LEAST(saas_apps.first_seen_at, COALESCE(EXCLUDED.first_seen_at, saas_apps.first_seen_at)),
   last_seen_at = GREATEST(saas_apps.last_seen_at, COALESCE(EXCLUDED.last_seen_at, saas_apps.last_seen_at)),
   updated_at = now()
@@ -733,6 +976,7 @@ type UpsertSaaSAppsBulkParams struct {
 	DisplayNames   []string             `json:"display_names"`
 	PrimaryDomains []string             `json:"primary_domains"`
 	VendorNames    []string             `json:"vendor_names"`
+	Categories     []string             `json:"categories"`
 	FirstSeenAts   []pgtype.Timestamptz `json:"first_seen_ats"`
 	LastSeenAts    []pgtype.Timestamptz `json:"last_seen_ats"`
 }
@@ -743,6 +987,7 @@ func (q *Queries) UpsertSaaSAppsBulk(ctx context.Context, arg UpsertSaaSAppsBulk
 		arg.DisplayNames,
 		arg.PrimaryDomains,
 		arg.VendorNames,
+		arg.Categories,
 		arg.FirstSeenAts,
 		arg.LastSeenAts,
 	)
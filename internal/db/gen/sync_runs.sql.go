@@ -20,6 +20,62 @@ func (q *Queries) AcquireAdvisoryLock(ctx context.Context, dollar_1 int64) error
 	return err
 }
 
+const appendSyncRunWarnings = `-- name: AppendSyncRunWarnings :exec
+UPDATE sync_runs
+SET warnings = warnings || $1::jsonb
+WHERE id = $2::bigint
+`
+
+type AppendSyncRunWarningsParams struct {
+	Warnings []byte `json:"warnings"`
+	ID       int64  `json:"id"`
+}
+
+func (q *Queries) AppendSyncRunWarnings(ctx context.Context, arg AppendSyncRunWarningsParams) error {
+	_, err := q.db.Exec(ctx, appendSyncRunWarnings, arg.Warnings, arg.ID)
+	return err
+}
+
+const countSyncRunsByFilters = `-- name: CountSyncRunsByFilters :one
+SELECT count(*)
+FROM sync_runs r
+WHERE ($1::text = '' OR r.source_kind = $1::text)
+  AND ($2::text = '' OR r.source_name = $2::text)
+  AND (
+    $3::text = ''
+    OR ($3::text = 'warning' AND r.status = 'success' AND jsonb_array_length(r.warnings) > 0)
+    OR r.status = $3::text
+  )
+  AND EXISTS (
+    SELECT 1
+    FROM unnest($4::text[]) WITH ORDINALITY AS k(kind, ord)
+    JOIN unnest($5::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+    WHERE k.kind = r.source_kind
+      AND n.name = r.source_name
+  )
+`
+
+type CountSyncRunsByFiltersParams struct {
+	SourceKind         string   `json:"source_kind"`
+	SourceName         string   `json:"source_name"`
+	Outcome            string   `json:"outcome"`
+	VisibleSourceKinds []string `json:"visible_source_kinds"`
+	VisibleSourceNames []string `json:"visible_source_names"`
+}
+
+func (q *Queries) CountSyncRunsByFilters(ctx context.Context, arg CountSyncRunsByFiltersParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSyncRunsByFilters,
+		arg.SourceKind,
+		arg.SourceName,
+		arg.Outcome,
+		arg.VisibleSourceKinds,
+		arg.VisibleSourceNames,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createSyncRun = `-- name: CreateSyncRun :one
 INSERT INTO sync_runs (source_kind, source_name, status, started_at)
 VALUES ($1, $2, 'running', now())
@@ -38,6 +94,20 @@ func (q *Queries) CreateSyncRun(ctx context.Context, arg CreateSyncRunParams) (i
 	return id, err
 }
 
+const deleteSyncRun = `-- name: DeleteSyncRun :execrows
+DELETE FROM sync_runs
+WHERE id = $1::bigint
+  AND finished_at IS NOT NULL
+`
+
+func (q *Queries) DeleteSyncRun(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSyncRun, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const failSyncRun = `-- name: FailSyncRun :exec
 UPDATE sync_runs
 SET status = $2, finished_at = now(), message = $3, error_kind = $4
@@ -61,6 +131,143 @@ func (q *Queries) FailSyncRun(ctx context.Context, arg FailSyncRunParams) error
 	return err
 }
 
+const getLatestRunningSyncRunIDBySource = `-- name: GetLatestRunningSyncRunIDBySource :one
+SELECT id
+FROM sync_runs
+WHERE source_kind = $1
+  AND source_name = $2
+  AND status = 'running'
+ORDER BY started_at DESC, id DESC
+LIMIT 1
+`
+
+type GetLatestRunningSyncRunIDBySourceParams struct {
+	SourceKind string `json:"source_kind"`
+	SourceName string `json:"source_name"`
+}
+
+func (q *Queries) GetLatestRunningSyncRunIDBySource(ctx context.Context, arg GetLatestRunningSyncRunIDBySourceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getLatestRunningSyncRunIDBySource, arg.SourceKind, arg.SourceName)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getLatestSuccessfulSyncRunStatsBySource = `-- name: GetLatestSuccessfulSyncRunStatsBySource :one
+SELECT stats
+FROM sync_runs
+WHERE source_kind = $1
+  AND source_name = $2
+  AND status = 'success'
+ORDER BY finished_at DESC, id DESC
+LIMIT 1
+`
+
+type GetLatestSuccessfulSyncRunStatsBySourceParams struct {
+	SourceKind string `json:"source_kind"`
+	SourceName string `json:"source_name"`
+}
+
+func (q *Queries) GetLatestSuccessfulSyncRunStatsBySource(ctx context.Context, arg GetLatestSuccessfulSyncRunStatsBySourceParams) ([]byte, error) {
+	row := q.db.QueryRow(ctx, getLatestSuccessfulSyncRunStatsBySource, arg.SourceKind, arg.SourceName)
+	var stats []byte
+	err := row.Scan(&stats)
+	return stats, err
+}
+
+const getLatestSyncRunIDStartedSinceBySource = `-- name: GetLatestSyncRunIDStartedSinceBySource :one
+SELECT id
+FROM sync_runs
+WHERE source_kind = $1
+  AND source_name = $2
+  AND started_at >= $3
+ORDER BY started_at DESC, id DESC
+LIMIT 1
+`
+
+type GetLatestSyncRunIDStartedSinceBySourceParams struct {
+	SourceKind string             `json:"source_kind"`
+	SourceName string             `json:"source_name"`
+	StartedAt  pgtype.Timestamptz `json:"started_at"`
+}
+
+func (q *Queries) GetLatestSyncRunIDStartedSinceBySource(ctx context.Context, arg GetLatestSyncRunIDStartedSinceBySourceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getLatestSyncRunIDStartedSinceBySource, arg.SourceKind, arg.SourceName, arg.StartedAt)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getSyncRun = `-- name: GetSyncRun :one
+SELECT id, source_kind, source_name, status, started_at, finished_at, message, stats, error_kind, warnings, api_request_count, api_stats
+FROM sync_runs
+WHERE id = $1::bigint
+`
+
+func (q *Queries) GetSyncRun(ctx context.Context, id int64) (SyncRun, error) {
+	row := q.db.QueryRow(ctx, getSyncRun, id)
+	var i SyncRun
+	err := row.Scan(
+		&i.ID,
+		&i.SourceKind,
+		&i.SourceName,
+		&i.Status,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Message,
+		&i.Stats,
+		&i.ErrorKind,
+		&i.Warnings,
+		&i.ApiRequestCount,
+		&i.ApiStats,
+	)
+	return i, err
+}
+
+const getSyncRunAPIRequestUsage = `-- name: GetSyncRunAPIRequestUsage :one
+SELECT
+  COALESCE((
+    SELECT sum(r.api_request_count)
+    FROM sync_runs r
+    WHERE r.source_kind = ANY($1::text[])
+      AND r.source_name = $2::text
+      AND r.started_at >= $3::timestamptz
+  ), 0)::bigint AS used_since,
+  COALESCE((
+    SELECT r.api_request_count
+    FROM sync_runs r
+    WHERE r.source_kind = $4::text
+      AND r.source_name = $2::text
+      AND r.finished_at IS NOT NULL
+    ORDER BY r.finished_at DESC, r.id DESC
+    LIMIT 1
+  ), 0)::bigint AS last_run_count
+`
+
+type GetSyncRunAPIRequestUsageParams struct {
+	SourceKinds   []string           `json:"source_kinds"`
+	SourceName    string             `json:"source_name"`
+	Since         pgtype.Timestamptz `json:"since"`
+	RunSourceKind string             `json:"run_source_kind"`
+}
+
+type GetSyncRunAPIRequestUsageRow struct {
+	UsedSince    int64 `json:"used_since"`
+	LastRunCount int64 `json:"last_run_count"`
+}
+
+func (q *Queries) GetSyncRunAPIRequestUsage(ctx context.Context, arg GetSyncRunAPIRequestUsageParams) (GetSyncRunAPIRequestUsageRow, error) {
+	row := q.db.QueryRow(ctx, getSyncRunAPIRequestUsage,
+		arg.SourceKinds,
+		arg.SourceName,
+		arg.Since,
+		arg.RunSourceKind,
+	)
+	var i GetSyncRunAPIRequestUsageRow
+	err := row.Scan(&i.UsedSince, &i.LastRunCount)
+	return i, err
+}
+
 const getSyncRunRollupsForSources = `-- name: GetSyncRunRollupsForSources :many
 WITH requested AS (
   SELECT k.kind AS source_kind, n.name AS source_name
@@ -75,7 +282,8 @@ last_run AS (
     r.status AS last_run_status,
     r.started_at AS last_run_started_at,
     r.finished_at AS last_run_finished_at,
-    r.error_kind AS last_run_error_kind
+    r.error_kind AS last_run_error_kind,
+    jsonb_array_length(r.warnings) AS last_run_warning_count
   FROM sync_runs r
   JOIN requested q
     ON r.source_kind = q.source_kind
@@ -120,6 +328,7 @@ SELECT
   lr.last_run_started_at,
   lr.last_run_finished_at,
   lr.last_run_error_kind,
+  lr.last_run_warning_count,
   ls.last_success_at::timestamptz AS last_success_at,
   COALESCE(s.finished_count_7d, 0) AS finished_count_7d,
   COALESCE(s.success_count_7d, 0) AS success_count_7d,
@@ -150,6 +359,7 @@ type GetSyncRunRollupsForSourcesRow struct {
 	LastRunStartedAt       pgtype.Timestamptz `json:"last_run_started_at"`
 	LastRunFinishedAt      pgtype.Timestamptz `json:"last_run_finished_at"`
 	LastRunErrorKind       pgtype.Text        `json:"last_run_error_kind"`
+	LastRunWarningCount    pgtype.Int4        `json:"last_run_warning_count"`
 	LastSuccessAt          pgtype.Timestamptz `json:"last_success_at"`
 	FinishedCount7d        int64              `json:"finished_count_7d"`
 	SuccessCount7d         int64              `json:"success_count_7d"`
@@ -173,6 +383,7 @@ func (q *Queries) GetSyncRunRollupsForSources(ctx context.Context, arg GetSyncRu
 			&i.LastRunStartedAt,
 			&i.LastRunFinishedAt,
 			&i.LastRunErrorKind,
+			&i.LastRunWarningCount,
 			&i.LastSuccessAt,
 			&i.FinishedCount7d,
 			&i.SuccessCount7d,
@@ -188,6 +399,39 @@ func (q *Queries) GetSyncRunRollupsForSources(ctx context.Context, arg GetSyncRu
 	return items, nil
 }
 
+const listFinishedSyncRunsForRetention = `-- name: ListFinishedSyncRunsForRetention :many
+SELECT id, source_kind, source_name
+FROM sync_runs
+WHERE finished_at IS NOT NULL
+ORDER BY source_kind, source_name, started_at DESC, id DESC
+`
+
+type ListFinishedSyncRunsForRetentionRow struct {
+	ID         int64  `json:"id"`
+	SourceKind string `json:"source_kind"`
+	SourceName string `json:"source_name"`
+}
+
+func (q *Queries) ListFinishedSyncRunsForRetention(ctx context.Context) ([]ListFinishedSyncRunsForRetentionRow, error) {
+	rows, err := q.db.Query(ctx, listFinishedSyncRunsForRetention)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFinishedSyncRunsForRetentionRow
+	for rows.Next() {
+		var i ListFinishedSyncRunsForRetentionRow
+		if err := rows.Scan(&i.ID, &i.SourceKind, &i.SourceName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listLatestSuccessfulSyncFinishedAtForSources = `-- name: ListLatestSuccessfulSyncFinishedAtForSources :many
 WITH requested AS (
   SELECT k.kind AS source_kind, n.name AS source_name
@@ -407,6 +651,152 @@ func (q *Queries) ListRecentNonSuccessSyncRunsBySource(ctx context.Context, arg
 	return items, nil
 }
 
+const listRecentSuccessfulSyncRunsBySource = `-- name: ListRecentSuccessfulSyncRunsBySource :many
+SELECT id, finished_at
+FROM sync_runs
+WHERE source_kind = $1
+  AND source_name = $2
+  AND finished_at IS NOT NULL
+  AND status = 'success'
+ORDER BY finished_at DESC, id DESC
+LIMIT $3
+`
+
+type ListRecentSuccessfulSyncRunsBySourceParams struct {
+	SourceKind string `json:"source_kind"`
+	SourceName string `json:"source_name"`
+	Limit      int32  `json:"limit"`
+}
+
+type ListRecentSuccessfulSyncRunsBySourceRow struct {
+	ID         int64              `json:"id"`
+	FinishedAt pgtype.Timestamptz `json:"finished_at"`
+}
+
+func (q *Queries) ListRecentSuccessfulSyncRunsBySource(ctx context.Context, arg ListRecentSuccessfulSyncRunsBySourceParams) ([]ListRecentSuccessfulSyncRunsBySourceRow, error) {
+	rows, err := q.db.Query(ctx, listRecentSuccessfulSyncRunsBySource, arg.SourceKind, arg.SourceName, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRecentSuccessfulSyncRunsBySourceRow
+	for rows.Next() {
+		var i ListRecentSuccessfulSyncRunsBySourceRow
+		if err := rows.Scan(&i.ID, &i.FinishedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSyncRunSources = `-- name: ListSyncRunSources :many
+SELECT DISTINCT source_kind, source_name
+FROM sync_runs
+ORDER BY source_kind, source_name
+`
+
+type ListSyncRunSourcesRow struct {
+	SourceKind string `json:"source_kind"`
+	SourceName string `json:"source_name"`
+}
+
+func (q *Queries) ListSyncRunSources(ctx context.Context) ([]ListSyncRunSourcesRow, error) {
+	rows, err := q.db.Query(ctx, listSyncRunSources)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSyncRunSourcesRow
+	for rows.Next() {
+		var i ListSyncRunSourcesRow
+		if err := rows.Scan(&i.SourceKind, &i.SourceName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSyncRunsPageByFilters = `-- name: ListSyncRunsPageByFilters :many
+SELECT r.id, r.source_kind, r.source_name, r.status, r.started_at, r.finished_at, r.message, r.stats, r.error_kind, r.warnings, r.api_request_count, r.api_stats
+FROM sync_runs r
+WHERE ($1::text = '' OR r.source_kind = $1::text)
+  AND ($2::text = '' OR r.source_name = $2::text)
+  AND (
+    $3::text = ''
+    OR ($3::text = 'warning' AND r.status = 'success' AND jsonb_array_length(r.warnings) > 0)
+    OR r.status = $3::text
+  )
+  AND EXISTS (
+    SELECT 1
+    FROM unnest($4::text[]) WITH ORDINALITY AS k(kind, ord)
+    JOIN unnest($5::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+    WHERE k.kind = r.source_kind
+      AND n.name = r.source_name
+  )
+ORDER BY r.started_at DESC, r.id DESC
+LIMIT $7::int
+OFFSET $6::int
+`
+
+type ListSyncRunsPageByFiltersParams struct {
+	SourceKind         string   `json:"source_kind"`
+	SourceName         string   `json:"source_name"`
+	Outcome            string   `json:"outcome"`
+	VisibleSourceKinds []string `json:"visible_source_kinds"`
+	VisibleSourceNames []string `json:"visible_source_names"`
+	PageOffset         int32    `json:"page_offset"`
+	PageLimit          int32    `json:"page_limit"`
+}
+
+func (q *Queries) ListSyncRunsPageByFilters(ctx context.Context, arg ListSyncRunsPageByFiltersParams) ([]SyncRun, error) {
+	rows, err := q.db.Query(ctx, listSyncRunsPageByFilters,
+		arg.SourceKind,
+		arg.SourceName,
+		arg.Outcome,
+		arg.VisibleSourceKinds,
+		arg.VisibleSourceNames,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SyncRun
+	for rows.Next() {
+		var i SyncRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.SourceKind,
+			&i.SourceName,
+			&i.Status,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.Message,
+			&i.Stats,
+			&i.ErrorKind,
+			&i.Warnings,
+			&i.ApiRequestCount,
+			&i.ApiStats,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const markSyncRunSuccess = `-- name: MarkSyncRunSuccess :exec
 UPDATE sync_runs
 SET status = 'success', finished_at = now(), message = '', stats = $2, error_kind = ''
@@ -468,6 +858,40 @@ func (q *Queries) ReleaseAdvisoryLock(ctx context.Context, dollar_1 int64) error
 	return err
 }
 
+const setSyncRunAPIRequestCount = `-- name: SetSyncRunAPIRequestCount :exec
+UPDATE sync_runs
+SET api_request_count = $2,
+  api_stats = $3
+WHERE id = $1
+`
+
+type SetSyncRunAPIRequestCountParams struct {
+	ID              int64  `json:"id"`
+	ApiRequestCount int64  `json:"api_request_count"`
+	ApiStats        []byte `json:"api_stats"`
+}
+
+func (q *Queries) SetSyncRunAPIRequestCount(ctx context.Context, arg SetSyncRunAPIRequestCountParams) error {
+	_, err := q.db.Exec(ctx, setSyncRunAPIRequestCount, arg.ID, arg.ApiRequestCount, arg.ApiStats)
+	return err
+}
+
+const setSyncRunWarnings = `-- name: SetSyncRunWarnings :exec
+UPDATE sync_runs
+SET warnings = $2
+WHERE id = $1
+`
+
+type SetSyncRunWarningsParams struct {
+	ID       int64  `json:"id"`
+	Warnings []byte `json:"warnings"`
+}
+
+func (q *Queries) SetSyncRunWarnings(ctx context.Context, arg SetSyncRunWarningsParams) error {
+	_, err := q.db.Exec(ctx, setSyncRunWarnings, arg.ID, arg.Warnings)
+	return err
+}
+
 const tryAcquireAdvisoryLock = `-- name: TryAcquireAdvisoryLock :one
 SELECT pg_try_advisory_lock($1::bigint)
 `
@@ -0,0 +1,54 @@
+package discovery
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultIngestLookback is how far back a regular discovery pass reads when the source has
+// no watermark yet.
+const DefaultIngestLookback = 7 * 24 * time.Hour
+
+type backfillContextKey struct{}
+
+// WithBackfill marks ctx as a one-off backfill that ingests evidence from since onward,
+// ignoring the stored watermark. It is only set by explicit operator triggers, never by the
+// discovery scheduler.
+func WithBackfill(ctx context.Context, since time.Time) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if since.IsZero() {
+		return ctx
+	}
+	return context.WithValue(ctx, backfillContextKey{}, since.UTC())
+}
+
+// BackfillSince reports the backfill start date carried by ctx.
+func BackfillSince(ctx context.Context) (time.Time, bool) {
+	if ctx == nil {
+		return time.Time{}, false
+	}
+	since, ok := ctx.Value(backfillContextKey{}).(time.Time)
+	return since, ok
+}
+
+// IngestSince resolves the start of a discovery ingest window. A regular pass reads lookback
+// before now, moved forward to the latest stored observation minus skew. A backfill starts at
+// its requested date and never consults the watermark; the upserts it feeds are idempotent.
+func IngestSince(ctx context.Context, now time.Time, lookback, skew time.Duration, watermark func(context.Context) (time.Time, bool, error)) (time.Time, error) {
+	if backfillSince, ok := BackfillSince(ctx); ok {
+		return backfillSince, nil
+	}
+	since := now.Add(-lookback)
+	latest, ok, err := watermark(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if ok {
+		if candidate := latest.UTC().Add(-skew); candidate.After(since) {
+			since = candidate
+		}
+	}
+	return since, nil
+}
@@ -0,0 +1,53 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIngestSince(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	latest := now.Add(-time.Hour)
+	calls := 0
+	watermark := func(context.Context) (time.Time, bool, error) {
+		calls++
+		return latest, true, nil
+	}
+
+	since, err := IngestSince(context.Background(), now, DefaultIngestLookback, 15*time.Minute, watermark)
+	if err != nil {
+		t.Fatalf("IngestSince() error = %v", err)
+	}
+	if !since.Equal(latest.Add(-15*time.Minute)) || calls != 1 {
+		t.Fatalf("regular pass = %s after %d watermark calls, want clamp to watermark", since, calls)
+	}
+
+	noWatermark := func(context.Context) (time.Time, bool, error) { return time.Time{}, false, nil }
+	since, err = IngestSince(context.Background(), now, DefaultIngestLookback, 15*time.Minute, noWatermark)
+	if err != nil || !since.Equal(now.Add(-DefaultIngestLookback)) {
+		t.Fatalf("first pass = %s, %v; want default lookback", since, err)
+	}
+
+	farBack := now.Add(-180 * 24 * time.Hour)
+	calls = 0
+	ctx := WithBackfill(context.Background(), farBack)
+	since, err = IngestSince(ctx, now, DefaultIngestLookback, 15*time.Minute, watermark)
+	if err != nil {
+		t.Fatalf("IngestSince() backfill error = %v", err)
+	}
+	if !since.Equal(farBack) {
+		t.Fatalf("backfill pass = %s, want full window from %s", since, farBack)
+	}
+	if _, ok := BackfillSince(ctx); !ok {
+		t.Fatalf("BackfillSince() = false for a backfill context")
+	}
+	if _, ok := BackfillSince(context.Background()); ok {
+		t.Fatalf("BackfillSince() = true for a regular context")
+	}
+	if calls != 0 {
+		t.Fatalf("backfill consulted the watermark %d times", calls)
+	}
+}
@@ -1,20 +1,81 @@
 package discovery
 
 import (
+	"fmt"
 	"net"
 	"net/url"
 	"regexp"
 	"strings"
+	"sync/atomic"
 
 	"golang.org/x/net/publicsuffix"
 )
 
 var nonKeyNameChars = regexp.MustCompile(`[^a-z0-9]+`)
 
+// CanonicalKeyStrategy decides which source attribute discovery rows are merged into one app
+// on. Apps are keyed when their rows are written, so switching strategies leaves existing apps
+// under their old keys and keys new rows the new way; pick one before the first discovery sync.
+type CanonicalKeyStrategy string
+
+const (
+	// CanonicalKeyByDomain keys an app by its registrable domain, then its Entra or Okta app ID,
+	// then its name per source kind. It is the default: one app merges across connectors by
+	// domain, but distinct products on a shared vendor domain merge too.
+	CanonicalKeyByDomain CanonicalKeyStrategy = "domain"
+	// CanonicalKeyByClientID keys an app by its source app or OAuth client ID before its domain.
+	// Apps sharing a domain never merge, but the same app seen by two connectors stays split
+	// because each reports its own ID.
+	CanonicalKeyByClientID CanonicalKeyStrategy = "client_id"
+	// CanonicalKeyByVendor keys an app by its vendor, from the vendor catalog or the source,
+	// before its domain. It is the loosest: every product of one vendor becomes one app, and a
+	// catalog change that renames a vendor re-keys its apps.
+	CanonicalKeyByVendor CanonicalKeyStrategy = "vendor"
+	// CanonicalKeyByName keys an app by its normalized name across connectors before its
+	// domain. The same app reported by several connectors merges even without a domain, but two
+	// apps that share a name merge too.
+	CanonicalKeyByName CanonicalKeyStrategy = "name"
+)
+
+// ParseCanonicalKeyStrategy parses a strategy name. A blank name selects CanonicalKeyByDomain.
+func ParseCanonicalKeyStrategy(raw string) (CanonicalKeyStrategy, error) {
+	switch strategy := CanonicalKeyStrategy(strings.ToLower(strings.TrimSpace(raw))); strategy {
+	case "":
+		return CanonicalKeyByDomain, nil
+	case CanonicalKeyByDomain, CanonicalKeyByClientID, CanonicalKeyByVendor, CanonicalKeyByName:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown discovery canonical key strategy %q (want domain, client_id, vendor, or name)", raw)
+	}
+}
+
+var activeCanonicalKeyStrategy atomic.Value
+
+// SetCanonicalKeyStrategy replaces the strategy used by BuildMetadata.
+func SetCanonicalKeyStrategy(strategy CanonicalKeyStrategy) {
+	activeCanonicalKeyStrategy.Store(strategy)
+}
+
+// ActiveCanonicalKeyStrategy returns the strategy used by BuildMetadata.
+func ActiveCanonicalKeyStrategy() CanonicalKeyStrategy {
+	if strategy, ok := activeCanonicalKeyStrategy.Load().(CanonicalKeyStrategy); ok && strategy != "" {
+		return strategy
+	}
+	return CanonicalKeyByDomain
+}
+
 // BuildMetadata returns canonical metadata for discovery rows.
 func BuildMetadata(input CanonicalInput) AppMetadata {
+	return buildMetadataWithCatalog(input, ActiveVendorCatalog(), ActiveCanonicalKeyStrategy())
+}
+
+// buildMetadataWithCatalog applies catalog vendor, domain, and category over the source-derived
+// values when the app is known. Except under CanonicalKeyByVendor, the canonical key is derived
+// from the source input alone so a catalog change never re-keys existing apps.
+func buildMetadataWithCatalog(input CanonicalInput, catalog *VendorCatalog, strategy CanonicalKeyStrategy) AppMetadata {
 	domain := normalizeDomain(input.SourceDomain)
-	canonical := CanonicalKey(input)
+	entry, inCatalog := catalog.Lookup(input)
+	canonical := canonicalKeyWithStrategy(input, strategy, entry.Vendor)
 	display := strings.TrimSpace(input.SourceAppName)
 	if display == "" {
 		display = strings.TrimSpace(input.SourceAppID)
@@ -22,23 +83,91 @@ func BuildMetadata(input CanonicalInput) AppMetadata {
 	if display == "" {
 		display = "Unknown app"
 	}
-	return AppMetadata{
+	meta := AppMetadata{
 		CanonicalKey: canonical,
 		DisplayName:  display,
 		Domain:       domain,
 		VendorName:   inferVendorName(strings.TrimSpace(input.SourceVendorName), domain),
 	}
+	if inCatalog {
+		if entry.Vendor != "" {
+			meta.VendorName = entry.Vendor
+		}
+		if entry.Domain != "" {
+			meta.Domain = entry.Domain
+		}
+		meta.Category = entry.Category
+	}
+	return meta
+}
+
+// canonicalKeyWithStrategy keys input by the strategy's preferred attribute and falls back to
+// CanonicalKey when the input lacks it. catalogVendor is the vendor catalog's vendor for the app.
+func canonicalKeyWithStrategy(input CanonicalInput, strategy CanonicalKeyStrategy, catalogVendor string) string {
+	switch strategy {
+	case CanonicalKeyByClientID:
+		if key := clientIDCanonicalKey(input); key != "" {
+			return key
+		}
+	case CanonicalKeyByVendor:
+		vendor := strings.TrimSpace(catalogVendor)
+		if vendor == "" {
+			vendor = input.SourceVendorName
+		}
+		if name := normalizeKeyName(vendor); name != "" {
+			return "vendor:" + name
+		}
+	case CanonicalKeyByName:
+		if name := normalizeKeyName(input.SourceAppName); name != "" {
+			return "name:" + name
+		}
+	}
+	return CanonicalKey(input)
 }
 
-// CanonicalKey returns a deterministic MVP key for SaaS app identity.
+// CanonicalKey returns the CanonicalKeyByDomain key for SaaS app identity.
 func CanonicalKey(input CanonicalInput) string {
-	sourceKind := strings.ToLower(strings.TrimSpace(input.SourceKind))
-	sourceName := strings.ToLower(strings.TrimSpace(input.SourceName))
-	sourceAppID := strings.TrimSpace(input.SourceAppID)
 	if domain := normalizeDomain(input.SourceDomain); domain != "" {
 		return "domain:" + domain
 	}
+	if key := idpAppCanonicalKey(input); key != "" {
+		return key
+	}
 
+	sourceKind := strings.ToLower(strings.TrimSpace(input.SourceKind))
+	name := normalizeKeyName(input.SourceAppName)
+	if name == "" {
+		name = normalizeKeyName(input.SourceAppID)
+	}
+	if name == "" {
+		name = "unknown"
+	}
+	if sourceKind == "" {
+		sourceKind = "unknown"
+	}
+	return "name:" + name + ":" + sourceKind
+}
+
+// clientIDCanonicalKey keys input by the app ID its source reported: the IdP app ID, or any
+// other source's app or OAuth client ID. It returns "" without one.
+func clientIDCanonicalKey(input CanonicalInput) string {
+	if key := idpAppCanonicalKey(input); key != "" {
+		return key
+	}
+	sourceKind := strings.ToLower(strings.TrimSpace(input.SourceKind))
+	sourceAppID := strings.ToLower(strings.TrimSpace(input.SourceAppID))
+	if sourceKind == "" || sourceKind == "entra" || sourceKind == "okta" || sourceAppID == "" {
+		return ""
+	}
+	return "client_id:" + sourceKind + ":" + sourceAppID
+}
+
+// idpAppCanonicalKey keys input by its Entra appId or by its Okta app ID within the Okta org.
+// It returns "" for other sources and for rows without an app ID.
+func idpAppCanonicalKey(input CanonicalInput) string {
+	sourceKind := strings.ToLower(strings.TrimSpace(input.SourceKind))
+	sourceName := strings.ToLower(strings.TrimSpace(input.SourceName))
+	sourceAppID := strings.TrimSpace(input.SourceAppID)
 	if sourceKind == "entra" {
 		if entraAppID := normalizeGUIDLike(input.EntraAppID); entraAppID != "" {
 			return "entra_appid:" + entraAppID
@@ -54,18 +183,7 @@ func CanonicalKey(input CanonicalInput) string {
 		}
 		return "okta_app:" + sourceName + ":" + strings.ToLower(sourceAppID)
 	}
-
-	name := normalizeKeyName(input.SourceAppName)
-	if name == "" {
-		name = normalizeKeyName(sourceAppID)
-	}
-	if name == "" {
-		name = "unknown"
-	}
-	if sourceKind == "" {
-		sourceKind = "unknown"
-	}
-	return "name:" + name + ":" + sourceKind
+	return ""
 }
 
 func normalizeDomain(raw string) string {
@@ -134,3 +134,94 @@ func TestVendorLabelFromDomain(t *testing.T) {
 		})
 	}
 }
+
+func TestCanonicalKeyStrategies(t *testing.T) {
+	t.Parallel()
+
+	catalog := NewVendorCatalog([]VendorCatalogEntry{
+		{Vendor: "Atlassian", Domain: "atlassian.com", Names: []string{"Jira", "Confluence"}},
+	})
+	cases := []struct {
+		name     string
+		a, b     CanonicalInput
+		merge    CanonicalKeyStrategy
+		split    CanonicalKeyStrategy
+		wantKeyA string
+	}{
+		{
+			name:     "two apps sharing a domain",
+			a:        CanonicalInput{SourceKind: "google_workspace", SourceAppID: "111.apps.googleusercontent.com", SourceAppName: "Docs Add-on", SourceDomain: "example.com"},
+			b:        CanonicalInput{SourceKind: "google_workspace", SourceAppID: "222.apps.googleusercontent.com", SourceAppName: "Calendar Sync", SourceDomain: "example.com"},
+			merge:    CanonicalKeyByDomain,
+			split:    CanonicalKeyByClientID,
+			wantKeyA: "client_id:google_workspace:111.apps.googleusercontent.com",
+		},
+		{
+			name:     "one app seen by two connectors without a domain",
+			a:        CanonicalInput{SourceKind: "okta", SourceName: "acme.okta.com", SourceAppID: "0oa1", SourceAppName: "Payroll Tool"},
+			b:        CanonicalInput{SourceKind: "entra", SourceAppID: "11111111-2222-3333-4444-555555555555", SourceAppName: "payroll tool"},
+			merge:    CanonicalKeyByName,
+			split:    CanonicalKeyByDomain,
+			wantKeyA: "okta_app:acme.okta.com:0oa1",
+		},
+		{
+			name:     "two products of one vendor",
+			a:        CanonicalInput{SourceKind: "entra", SourceAppName: "Jira", SourceDomain: "acme.atlassian.net"},
+			b:        CanonicalInput{SourceKind: "entra", SourceAppName: "Confluence", SourceDomain: "trello.com"},
+			merge:    CanonicalKeyByVendor,
+			split:    CanonicalKeyByDomain,
+			wantKeyA: "domain:atlassian.net",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mergedA := buildMetadataWithCatalog(tc.a, catalog, tc.merge).CanonicalKey
+			mergedB := buildMetadataWithCatalog(tc.b, catalog, tc.merge).CanonicalKey
+			if mergedA != mergedB {
+				t.Fatalf("%s: keys = %q and %q, want one app", tc.merge, mergedA, mergedB)
+			}
+			splitA := buildMetadataWithCatalog(tc.a, catalog, tc.split).CanonicalKey
+			splitB := buildMetadataWithCatalog(tc.b, catalog, tc.split).CanonicalKey
+			if splitA == splitB {
+				t.Fatalf("%s: both keyed %q, want two apps", tc.split, splitA)
+			}
+			if splitA != tc.wantKeyA {
+				t.Fatalf("%s: key = %q, want %q", tc.split, splitA, tc.wantKeyA)
+			}
+		})
+	}
+}
+
+func TestCanonicalKeyStrategyFallsBackToDomainKey(t *testing.T) {
+	t.Parallel()
+
+	input := CanonicalInput{SourceKind: "entra", SourceDomain: "payroll.acme.com"}
+	for _, strategy := range []CanonicalKeyStrategy{CanonicalKeyByClientID, CanonicalKeyByVendor, CanonicalKeyByName} {
+		if got := buildMetadataWithCatalog(input, nil, strategy).CanonicalKey; got != "domain:acme.com" {
+			t.Fatalf("%s: CanonicalKey = %q, want domain:acme.com", strategy, got)
+		}
+	}
+}
+
+func TestParseCanonicalKeyStrategy(t *testing.T) {
+	t.Parallel()
+
+	for raw, want := range map[string]CanonicalKeyStrategy{
+		"":            CanonicalKeyByDomain,
+		"domain":      CanonicalKeyByDomain,
+		" Client_ID ": CanonicalKeyByClientID,
+		"vendor":      CanonicalKeyByVendor,
+		"name":        CanonicalKeyByName,
+	} {
+		got, err := ParseCanonicalKeyStrategy(raw)
+		if err != nil || got != want {
+			t.Fatalf("ParseCanonicalKeyStrategy(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+	if _, err := ParseCanonicalKeyStrategy("fuzzy"); err == nil {
+		t.Fatalf("ParseCanonicalKeyStrategy(fuzzy) error = nil, want error")
+	}
+}
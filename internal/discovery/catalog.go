@@ -0,0 +1,143 @@
+package discovery
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+//go:embed vendor_catalog.json
+var seedVendorCatalogJSON []byte
+
+// VendorCatalogEntry describes a known SaaS app. An entry matches discovery rows by source app
+// ID (e.g. an Entra appId or Google OAuth client ID), by normalized app name, or by domain.
+type VendorCatalogEntry struct {
+	Vendor   string   `json:"vendor"`
+	Domain   string   `json:"domain"`
+	Category string   `json:"category"`
+	AppIDs   []string `json:"app_ids"`
+	Names    []string `json:"names"`
+}
+
+// VendorCatalog looks up canonical vendor metadata for discovered apps.
+type VendorCatalog struct {
+	byAppID  map[string]VendorCatalogEntry
+	byName   map[string]VendorCatalogEntry
+	byDomain map[string]VendorCatalogEntry
+}
+
+var activeVendorCatalog atomic.Pointer[VendorCatalog]
+
+func init() {
+	entries, err := ParseVendorCatalog(seedVendorCatalogJSON)
+	if err != nil {
+		panic(fmt.Sprintf("parse seed vendor catalog: %v", err))
+	}
+	activeVendorCatalog.Store(NewVendorCatalog(entries))
+}
+
+// SeedVendorCatalogEntries returns the catalog shipped with the binary.
+func SeedVendorCatalogEntries() []VendorCatalogEntry {
+	entries, _ := ParseVendorCatalog(seedVendorCatalogJSON)
+	return entries
+}
+
+// ParseVendorCatalog decodes a JSON array of catalog entries.
+func ParseVendorCatalog(raw []byte) ([]VendorCatalogEntry, error) {
+	var entries []VendorCatalogEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// LoadVendorCatalogFile reads operator-provided catalog entries from path.
+func LoadVendorCatalogFile(path string) ([]VendorCatalogEntry, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read vendor catalog %s: %w", path, err)
+	}
+	entries, err := ParseVendorCatalog(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse vendor catalog %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// NewVendorCatalog indexes entries. When several entries claim the same app ID, name, or domain,
+// the later one wins, so operator entries appended after the seed override it.
+func NewVendorCatalog(entries []VendorCatalogEntry) *VendorCatalog {
+	c := &VendorCatalog{
+		byAppID:  make(map[string]VendorCatalogEntry),
+		byName:   make(map[string]VendorCatalogEntry),
+		byDomain: make(map[string]VendorCatalogEntry),
+	}
+	for _, entry := range entries {
+		entry.Vendor = strings.TrimSpace(entry.Vendor)
+		entry.Domain = normalizeDomain(entry.Domain)
+		entry.Category = strings.TrimSpace(entry.Category)
+		for _, appID := range entry.AppIDs {
+			if key := catalogAppIDKey(appID); key != "" {
+				c.byAppID[key] = entry
+			}
+		}
+		for _, name := range entry.Names {
+			if key := normalizeKeyName(name); key != "" {
+				c.byName[key] = entry
+			}
+		}
+		if entry.Domain != "" {
+			c.byDomain[entry.Domain] = entry
+		}
+	}
+	return c
+}
+
+// Lookup finds the entry for a discovery row, preferring the most specific match: app ID,
+// then app name, then domain.
+func (c *VendorCatalog) Lookup(input CanonicalInput) (VendorCatalogEntry, bool) {
+	if c == nil {
+		return VendorCatalogEntry{}, false
+	}
+	for _, appID := range []string{input.EntraAppID, input.SourceAppID} {
+		if key := catalogAppIDKey(appID); key != "" {
+			if entry, ok := c.byAppID[key]; ok {
+				return entry, true
+			}
+		}
+	}
+	if key := normalizeKeyName(input.SourceAppName); key != "" {
+		if entry, ok := c.byName[key]; ok {
+			return entry, true
+		}
+	}
+	if domain := normalizeDomain(input.SourceDomain); domain != "" {
+		if entry, ok := c.byDomain[domain]; ok {
+			return entry, true
+		}
+	}
+	return VendorCatalogEntry{}, false
+}
+
+// SetVendorCatalog replaces the catalog used by BuildMetadata. A nil catalog disables lookups.
+func SetVendorCatalog(c *VendorCatalog) {
+	if c == nil {
+		c = NewVendorCatalog(nil)
+	}
+	activeVendorCatalog.Store(c)
+}
+
+// ActiveVendorCatalog returns the catalog used by BuildMetadata.
+func ActiveVendorCatalog() *VendorCatalog {
+	return activeVendorCatalog.Load()
+}
+
+func catalogAppIDKey(raw string) string {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	raw = strings.TrimPrefix(raw, "{")
+	raw = strings.TrimSuffix(raw, "}")
+	return strings.TrimSpace(raw)
+}
@@ -0,0 +1,115 @@
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildMetadataVendorCatalog(t *testing.T) {
+	t.Parallel()
+
+	catalog := NewVendorCatalog([]VendorCatalogEntry{
+		{Vendor: "Acme Corp", Domain: "https://www.acme-saas.com", Category: "AI/LLM", AppIDs: []string{"{AAAAAAAA-2222-3333-4444-555555555555}"}, Names: []string{"Acme Assistant"}},
+	})
+
+	t.Run("app id hit overrides heuristic", func(t *testing.T) {
+		t.Parallel()
+
+		meta := buildMetadataWithCatalog(CanonicalInput{
+			SourceKind:       "entra",
+			SourceAppID:      "aaaaaaaa-2222-3333-4444-555555555555",
+			SourceAppName:    "Assistant (prod)",
+			SourceDomain:     "login.other-host.net",
+			SourceVendorName: "Other Host",
+		}, catalog, CanonicalKeyByDomain)
+		if meta.VendorName != "Acme Corp" || meta.Domain != "acme-saas.com" || meta.Category != "AI/LLM" {
+			t.Fatalf("meta = %+v, want catalog enrichment", meta)
+		}
+		if meta.CanonicalKey != "domain:other-host.net" {
+			t.Fatalf("CanonicalKey = %q, want source-derived key", meta.CanonicalKey)
+		}
+	})
+
+	t.Run("name hit fills blank vendor and domain", func(t *testing.T) {
+		t.Parallel()
+
+		meta := buildMetadataWithCatalog(CanonicalInput{
+			SourceKind:    "google_workspace",
+			SourceAppID:   "1234.apps.googleusercontent.com",
+			SourceAppName: "acme assistant",
+		}, catalog, CanonicalKeyByDomain)
+		if meta.VendorName != "Acme Corp" || meta.Domain != "acme-saas.com" {
+			t.Fatalf("meta = %+v, want catalog enrichment", meta)
+		}
+	})
+
+	t.Run("miss falls back to heuristic", func(t *testing.T) {
+		t.Parallel()
+
+		input := CanonicalInput{
+			SourceKind:    "entra",
+			SourceAppName: "Payroll Tool",
+			SourceDomain:  "payroll.example.com",
+		}
+		meta := buildMetadataWithCatalog(input, catalog, CanonicalKeyByDomain)
+		want := buildMetadataWithCatalog(input, nil, CanonicalKeyByDomain)
+		if meta != want {
+			t.Fatalf("meta = %+v, want %+v", meta, want)
+		}
+		if meta.VendorName != "Example" || meta.Category != "" {
+			t.Fatalf("meta = %+v, want domain-derived vendor without category", meta)
+		}
+	})
+}
+
+func TestSeedVendorCatalog(t *testing.T) {
+	t.Parallel()
+
+	entry, ok := NewVendorCatalog(SeedVendorCatalogEntries()).Lookup(CanonicalInput{SourceKind: "google_workspace", SourceAppName: "Slack"})
+	if !ok {
+		t.Fatalf("expected seed catalog to know Slack")
+	}
+	if entry.Vendor != "Slack" || entry.Domain != "slack.com" {
+		t.Fatalf("entry = %+v", entry)
+	}
+}
+
+func TestSeedVendorCatalogCategorizesApps(t *testing.T) {
+	t.Parallel()
+
+	catalog := NewVendorCatalog(SeedVendorCatalogEntries())
+
+	meta := buildMetadataWithCatalog(CanonicalInput{SourceKind: "okta", SourceName: "acme.okta.com", SourceAppID: "0oa1", SourceAppName: "ChatGPT"}, catalog, CanonicalKeyByDomain)
+	if meta.Category != "AI/LLM" {
+		t.Fatalf("ChatGPT category = %q, want AI/LLM", meta.Category)
+	}
+
+	meta = buildMetadataWithCatalog(CanonicalInput{SourceKind: "okta", SourceName: "acme.okta.com", SourceAppID: "0oa2", SourceAppName: "Internal Payroll"}, catalog, CanonicalKeyByDomain)
+	if meta.Category != "" {
+		t.Fatalf("unknown app category = %q, want uncategorized", meta.Category)
+	}
+}
+
+func TestLoadVendorCatalogFileOverridesSeed(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	if err := os.WriteFile(path, []byte(`[{"vendor":"Slack Technologies","domain":"slack.com","category":"Chat","names":["Slack"]}]`), 0o600); err != nil {
+		t.Fatalf("write catalog: %v", err)
+	}
+	extra, err := LoadVendorCatalogFile(path)
+	if err != nil {
+		t.Fatalf("LoadVendorCatalogFile() error = %v", err)
+	}
+
+	catalog := NewVendorCatalog(append(SeedVendorCatalogEntries(), extra...))
+	entry, ok := catalog.Lookup(CanonicalInput{SourceAppName: "Slack"})
+	if !ok || entry.Vendor != "Slack Technologies" || entry.Category != "Chat" {
+		t.Fatalf("entry = %+v ok=%v, want operator override", entry, ok)
+	}
+
+	if _, err := LoadVendorCatalogFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatalf("expected error for missing catalog file")
+	}
+}
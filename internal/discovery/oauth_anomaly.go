@@ -0,0 +1,69 @@
+package discovery
+
+import "time"
+
+const (
+	OAuthAnomalyReasonGrantBurst     = "grant_burst"
+	OAuthAnomalyReasonHighRiskScopes = "high_risk_scopes_on_first_sight"
+
+	DefaultOAuthAnomalyWindow       = 24 * time.Hour
+	DefaultOAuthAnomalyMinActors    = 10
+	DefaultOAuthAnomalyNewAppMaxAge = 7 * 24 * time.Hour
+)
+
+// OAuthAnomalyThresholds tunes the malicious-consent detector. Zero values fall back to the
+// package defaults.
+type OAuthAnomalyThresholds struct {
+	// Window is how long after an app's first OAuth grant distinct granting actors are counted.
+	Window time.Duration
+	// MinActors is the distinct-actor count within Window that flags a grant burst.
+	MinActors int64
+	// NewAppMaxAge limits detection to apps first seen this recently.
+	NewAppMaxAge time.Duration
+}
+
+func (t OAuthAnomalyThresholds) Normalized() OAuthAnomalyThresholds {
+	if t.Window <= 0 {
+		t.Window = DefaultOAuthAnomalyWindow
+	}
+	if t.MinActors <= 0 {
+		t.MinActors = DefaultOAuthAnomalyMinActors
+	}
+	if t.NewAppMaxAge <= 0 {
+		t.NewAppMaxAge = DefaultOAuthAnomalyNewAppMaxAge
+	}
+	return t
+}
+
+// OAuthGrantWindow summarizes an app's OAuth grants within the detection window that starts at
+// its first observed grant.
+type OAuthGrantWindow struct {
+	FirstSeenAt      time.Time
+	FirstGrantAt     time.Time
+	ActorsInWindow   int64
+	FirstSightScopes []string
+}
+
+type OAuthAnomaly struct {
+	Reasons        []string
+	HighRiskScopes []string
+}
+
+// EvaluateOAuthGrantAnomaly flags a newly-seen app that either collects grants from at least
+// MinActors distinct users within Window of its first grant, or requests privileged scopes in
+// those first grants. Apps first seen longer than NewAppMaxAge ago are never flagged.
+func EvaluateOAuthGrantAnomaly(window OAuthGrantWindow, thresholds OAuthAnomalyThresholds, now time.Time) (OAuthAnomaly, bool) {
+	thresholds = thresholds.Normalized()
+	if window.FirstSeenAt.IsZero() || now.Sub(window.FirstSeenAt) > thresholds.NewAppMaxAge {
+		return OAuthAnomaly{}, false
+	}
+
+	out := OAuthAnomaly{HighRiskScopes: PrivilegedScopes(window.FirstSightScopes)}
+	if window.ActorsInWindow >= thresholds.MinActors {
+		out.Reasons = append(out.Reasons, OAuthAnomalyReasonGrantBurst)
+	}
+	if len(out.HighRiskScopes) > 0 {
+		out.Reasons = append(out.Reasons, OAuthAnomalyReasonHighRiskScopes)
+	}
+	return out, len(out.Reasons) > 0
+}
@@ -0,0 +1,88 @@
+package discovery
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+type syntheticGrant struct {
+	actor  string
+	at     time.Time
+	scopes []string
+}
+
+// summarizeGrants mirrors ListSaaSAppOAuthGrantWindows for a single app.
+func summarizeGrants(grants []syntheticGrant, window time.Duration) OAuthGrantWindow {
+	out := OAuthGrantWindow{}
+	for _, grant := range grants {
+		if out.FirstGrantAt.IsZero() || grant.at.Before(out.FirstGrantAt) {
+			out.FirstGrantAt = grant.at
+		}
+	}
+	out.FirstSeenAt = out.FirstGrantAt
+	actors := map[string]struct{}{}
+	for _, grant := range grants {
+		if grant.at.Before(out.FirstGrantAt.Add(window)) {
+			actors[grant.actor] = struct{}{}
+			out.FirstSightScopes = append(out.FirstSightScopes, grant.scopes...)
+		}
+	}
+	out.ActorsInWindow = int64(len(actors))
+	return out
+}
+
+func TestEvaluateOAuthGrantAnomaly(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	thresholds := OAuthAnomalyThresholds{Window: 6 * time.Hour, MinActors: 10, NewAppMaxAge: 7 * 24 * time.Hour}
+
+	burstStart := now.Add(-3 * time.Hour)
+	var burst []syntheticGrant
+	for i := range 25 {
+		burst = append(burst, syntheticGrant{
+			actor:  fmt.Sprintf("user-%d@example.com", i),
+			at:     burstStart.Add(time.Duration(i) * 5 * time.Minute),
+			scopes: []string{"User.Read"},
+		})
+	}
+	anomaly, flagged := EvaluateOAuthGrantAnomaly(summarizeGrants(burst, thresholds.Window), thresholds, now)
+	if !flagged {
+		t.Fatalf("burst of 25 grants within 2h was not flagged")
+	}
+	if !slices.Equal(anomaly.Reasons, []string{OAuthAnomalyReasonGrantBurst}) {
+		t.Fatalf("burst reasons = %v", anomaly.Reasons)
+	}
+
+	// The same number of users spread over six days never crosses the threshold in one window.
+	slowStart := now.Add(-6 * 24 * time.Hour)
+	var slow []syntheticGrant
+	for i := range 25 {
+		slow = append(slow, syntheticGrant{
+			actor:  fmt.Sprintf("user-%d@example.com", i),
+			at:     slowStart.Add(time.Duration(i) * 5 * time.Hour),
+			scopes: []string{"User.Read"},
+		})
+	}
+	if anomaly, flagged := EvaluateOAuthGrantAnomaly(summarizeGrants(slow, thresholds.Window), thresholds, now); flagged {
+		t.Fatalf("slow-growing app flagged: %+v", anomaly)
+	}
+
+	risky := []syntheticGrant{{actor: "alice@example.com", at: now.Add(-time.Hour), scopes: []string{"Mail.Read", "Directory.ReadWrite.All"}}}
+	anomaly, flagged = EvaluateOAuthGrantAnomaly(summarizeGrants(risky, thresholds.Window), thresholds, now)
+	if !flagged || !slices.Equal(anomaly.Reasons, []string{OAuthAnomalyReasonHighRiskScopes}) {
+		t.Fatalf("high-risk first grant = %+v flagged=%v", anomaly, flagged)
+	}
+	if !slices.Equal(anomaly.HighRiskScopes, []string{"directory.readwrite.all"}) {
+		t.Fatalf("high-risk scopes = %v", anomaly.HighRiskScopes)
+	}
+
+	// A burst on an app first seen long ago is ordinary adoption, not a new-app anomaly.
+	old := summarizeGrants(burst, thresholds.Window)
+	old.FirstSeenAt = now.Add(-30 * 24 * time.Hour)
+	if _, flagged := EvaluateOAuthGrantAnomaly(old, thresholds, now); flagged {
+		t.Fatalf("established app flagged")
+	}
+}
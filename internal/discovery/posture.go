@@ -120,3 +120,10 @@ func normalizeDataClassification(raw string) string {
 		return "unknown"
 	}
 }
+
+// IsSanctioned reports whether an app is bound to a connector that is configured and enabled.
+// Unlike ManagedStateAndReason it ignores sync freshness: a stale connector makes an app
+// unmanaged for risk scoring, but the organization has still adopted it.
+func IsSanctioned(input ManagedStateInput) bool {
+	return input.HasPrimaryBinding && input.ConnectorConfigured && input.ConnectorEnabled
+}
@@ -127,3 +127,30 @@ func TestRiskAndSuggestions(t *testing.T) {
 		t.Fatalf("SuggestedDataClassification() internal = %q", got)
 	}
 }
+
+func TestIsSanctioned(t *testing.T) {
+	t.Parallel()
+
+	stale := ManagedStateInput{
+		HasPrimaryBinding:   true,
+		ConnectorConfigured: true,
+		ConnectorEnabled:    true,
+		Now:                 time.Date(2026, time.February, 8, 18, 0, 0, 0, time.UTC),
+	}
+	if state, _ := ManagedStateAndReason(stale); state != ManagedStateUnmanaged {
+		t.Fatalf("state without a recent sync = %q, want unmanaged", state)
+	}
+	if !IsSanctioned(stale) {
+		t.Fatalf("bound app on an enabled connector should be sanctioned regardless of sync freshness")
+	}
+
+	for name, input := range map[string]ManagedStateInput{
+		"no binding":         {ConnectorConfigured: true, ConnectorEnabled: true},
+		"unconfigured":       {HasPrimaryBinding: true, ConnectorEnabled: true},
+		"connector disabled": {HasPrimaryBinding: true, ConnectorConfigured: true},
+	} {
+		if IsSanctioned(input) {
+			t.Fatalf("%s: IsSanctioned() = true, want false", name)
+		}
+	}
+}
@@ -42,6 +42,17 @@ func HasPrivilegedScopes(scopes []string) bool {
 	return slices.ContainsFunc(NormalizeScopes(scopes), isPrivilegedScope)
 }
 
+// PrivilegedScopes returns the normalized scopes that HasPrivilegedScopes would flag.
+func PrivilegedScopes(scopes []string) []string {
+	out := make([]string, 0)
+	for _, scope := range NormalizeScopes(scopes) {
+		if isPrivilegedScope(scope) {
+			out = append(out, scope)
+		}
+	}
+	return out
+}
+
 func HasConfidentialScopes(scopes []string) bool {
 	return slices.ContainsFunc(NormalizeScopes(scopes), isConfidentialScope)
 }
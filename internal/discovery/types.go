@@ -30,6 +30,7 @@ type AppMetadata struct {
 	DisplayName  string
 	Domain       string
 	VendorName   string
+	Category     string
 }
 
 type ManagedStateInput struct {
@@ -0,0 +1,52 @@
+package discovery
+
+import "strings"
+
+// VendorEvidence is what a connector knows about who publishes a discovered app. Connectors fill
+// in whatever their API exposes and let ResolveVendorName pick the most trustworthy value.
+type VendorEvidence struct {
+	// VerifiedPublisher is a publisher name the IdP has verified (e.g. Entra verifiedPublisher).
+	VerifiedPublisher string
+	// PublisherDomain is the publisher's or app's domain, URL, or host.
+	PublisherDomain string
+	// PublisherName is a self-reported publisher name (e.g. Entra service principal
+	// publisherName).
+	PublisherName string
+	// AppID and AppName identify the app for the catalog lookup; AppName is also the last-resort
+	// vendor.
+	AppID   string
+	AppName string
+}
+
+// ResolveVendorName returns the vendor for a discovered app, preferring a verified publisher,
+// then the vendor derived from the publisher domain, then a self-reported publisher name, then
+// the vendor catalog, and only then the app name. An app name that merely echoes the app ID is
+// not used.
+func ResolveVendorName(evidence VendorEvidence) string {
+	return resolveVendorNameWithCatalog(evidence, ActiveVendorCatalog())
+}
+
+func resolveVendorNameWithCatalog(evidence VendorEvidence, catalog *VendorCatalog) string {
+	if vendor := strings.TrimSpace(evidence.VerifiedPublisher); vendor != "" {
+		return vendor
+	}
+	domain := normalizeDomain(evidence.PublisherDomain)
+	if domain == "" {
+		domain = evidence.PublisherDomain
+	}
+	if vendor := VendorLabelFromDomain(domain); vendor != "" {
+		return vendor
+	}
+	if vendor := strings.TrimSpace(evidence.PublisherName); vendor != "" {
+		return vendor
+	}
+	appID := strings.TrimSpace(evidence.AppID)
+	appName := strings.TrimSpace(evidence.AppName)
+	if entry, ok := catalog.Lookup(CanonicalInput{SourceAppID: appID, SourceAppName: appName}); ok && entry.Vendor != "" {
+		return entry.Vendor
+	}
+	if appName == "" || strings.EqualFold(appName, appID) {
+		return ""
+	}
+	return appName
+}
@@ -0,0 +1,62 @@
+package discovery
+
+import "testing"
+
+func TestResolveVendorNamePrecedence(t *testing.T) {
+	t.Parallel()
+
+	catalog := NewVendorCatalog([]VendorCatalogEntry{
+		{Vendor: "Atlassian", Names: []string{"Jira"}, AppIDs: []string{"client-jira"}},
+	})
+	cases := []struct {
+		name     string
+		evidence VendorEvidence
+		want     string
+	}{
+		{
+			name: "verified publisher wins",
+			evidence: VendorEvidence{
+				VerifiedPublisher: "Contoso Ltd",
+				PublisherDomain:   "fabrikam.com",
+				PublisherName:     "Fabrikam",
+				AppName:           "Jira",
+			},
+			want: "Contoso Ltd",
+		},
+		{
+			name:     "domain before publisher name and catalog",
+			evidence: VendorEvidence{PublisherDomain: "https://login.salesforce.com/app", PublisherName: "Sales Tenant", AppName: "Jira"},
+			want:     "Salesforce",
+		},
+		{
+			name:     "publisher name before catalog",
+			evidence: VendorEvidence{PublisherName: "Fabrikam", AppName: "Jira"},
+			want:     "Fabrikam",
+		},
+		{
+			name:     "catalog by app id before app name",
+			evidence: VendorEvidence{AppID: "client-jira", AppName: "Team Tracker"},
+			want:     "Atlassian",
+		},
+		{
+			name:     "catalog by app name",
+			evidence: VendorEvidence{AppName: "jira"},
+			want:     "Atlassian",
+		},
+		{
+			name:     "app name as last resort",
+			evidence: VendorEvidence{AppID: "123.apps.googleusercontent.com", AppName: "Expense Helper"},
+			want:     "Expense Helper",
+		},
+		{
+			name:     "app name echoing the id is not a vendor",
+			evidence: VendorEvidence{AppID: "123.apps.googleusercontent.com", AppName: "123.apps.googleusercontent.com"},
+			want:     "",
+		},
+	}
+	for _, tc := range cases {
+		if got := resolveVendorNameWithCatalog(tc.evidence, catalog); got != tc.want {
+			t.Fatalf("%s: resolveVendorNameWithCatalog() = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -16,7 +16,14 @@ import (
 const (
 	ContextKeyPrincipal = "auth_principal"
 
-	SessionKeyUserID = "auth_user_id"
+	SessionKeyUserID     = "auth_user_id"
+	SessionKeyAuthMethod = "auth_method"
+
+	// OIDC login attempt state, set when the user is sent to the IdP and consumed by the callback.
+	SessionKeyOIDCState    = "oidc_state"
+	SessionKeyOIDCNonce    = "oidc_nonce"
+	SessionKeyOIDCVerifier = "oidc_verifier"
+	SessionKeyOIDCNext     = "oidc_next"
 )
 
 func PrincipalFromContext(c *echo.Context) (auth.Principal, bool) {
@@ -43,11 +50,17 @@ func LoadPrincipal(ctx *echo.Context, sessions *scs.SessionManager, q *gen.Queri
 		return auth.Principal{}, false, nil
 	}
 
+	method := sessions.GetString(ctx.Request().Context(), SessionKeyAuthMethod)
+	if method == "" {
+		method = auth.MethodPassword
+	}
 	return auth.Principal{
-		UserID: user.ID,
-		Email:  user.Email,
-		Role:   user.Role,
-		Method: auth.MethodPassword,
+		UserID:   user.ID,
+		Email:    user.Email,
+		Role:     user.Role,
+		Method:   method,
+		OrgID:    user.OrgID,
+		Timezone: user.Timezone,
 	}, true, nil
 }
 
@@ -67,6 +80,7 @@ func RequireAuth(sessions *scs.SessionManager, q *gen.Queries) echo.MiddlewareFu
 	}
 }
 
+// RequireRole rejects principals whose role is below role in the viewer < analyst < admin order.
 func RequireRole(role string) echo.MiddlewareFunc {
 	role = strings.ToLower(strings.TrimSpace(role))
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -75,7 +89,7 @@ func RequireRole(role string) echo.MiddlewareFunc {
 			if !ok {
 				return handleUnauth(c)
 			}
-			if strings.ToLower(strings.TrimSpace(p.Role)) != role {
+			if !p.HasRole(role) {
 				if isAPIRequest(c) {
 					return c.JSON(http.StatusForbidden, map[string]string{"error": "forbidden"})
 				}
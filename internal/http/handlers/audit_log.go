@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/authn"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+	"github.com/open-sspm/open-sspm/internal/http/views"
+)
+
+const contextKeyAuditDetail = "audit_detail"
+
+// auditActionByRoute names the operator action behind each mutating route. Routes missing here
+// are still recorded, under "<method> <route>", so new endpoints are audited by default.
+var auditActionByRoute = map[string]string{
+	"POST /apps/map":                                                 "app.map",
+	"POST /links":                                                    "identity.link",
+	"POST /identities/duplicates/:id/accept":                         "identity.merge",
+	"POST /identities/duplicates/:id/dismiss":                        "identity.merge.dismiss",
+	"POST /discovery/apps/:id/bindings":                              "discovery.binding.create",
+	"POST /credentials/:id/tags":                                     "credential.tag.add",
+	"POST /credentials/:id/tags/delete":                              "credential.tag.remove",
+	"POST /credentials/:id/revoke":                                   "credential.revoke",
+	"POST /credentials/:id/revocation/complete":                      "credential.revocation.complete",
+	"POST /credentials/bulk":                                         "credential.bulk",
+	"POST /findings/rulesets/:rulesetKey/override":                   "finding.ruleset.override",
+	"POST /findings/rulesets/:rulesetKey/rules/:ruleKey/override":    "finding.rule.override",
+	"POST /findings/rulesets/:rulesetKey/rules/:ruleKey/attestation": "finding.rule.attestation",
+	"POST /settings/connector-health/sync":                           "sync.trigger",
+	"POST /settings/connectors/*":                                    "connector.update",
+	"POST /settings/users":                                           "user.create",
+	"POST /settings/users/:id":                                       "user.update",
+	"POST /settings/users/:id/delete":                                "user.delete",
+	"POST /settings/resync":                                          "sync.resync",
+	"POST /api/sync":                                                 "sync.trigger",
+	"POST /account/timezone":                                         "account.timezone",
+	"POST /logout":                                                   "auth.logout",
+}
+
+// auditDetail lets a handler refine what the audit middleware records for its request. Zero
+// fields keep the route-derived defaults; Before and After are stored as JSON.
+type auditDetail struct {
+	Action string
+	Target string
+	Before any
+	After  any
+}
+
+type auditLogWriter interface {
+	InsertAppAuditLogEntry(ctx context.Context, arg gen.InsertAppAuditLogEntryParams) error
+}
+
+type auditLogQueries interface {
+	CountAppAuditLogEntries(ctx context.Context, arg gen.CountAppAuditLogEntriesParams) (int64, error)
+	ListAppAuditLogEntries(ctx context.Context, arg gen.ListAppAuditLogEntriesParams) ([]gen.AppAuditLog, error)
+	ListAppAuditLogActions(ctx context.Context, orgID int64) ([]string, error)
+}
+
+// setAuditDetail records handler-specific audit detail for the current request.
+func setAuditDetail(c *echo.Context, detail auditDetail) {
+	c.Set(contextKeyAuditDetail, detail)
+}
+
+// AuditMutations records every non-GET request made by a signed-in operator to app_audit_log,
+// including requests the role checks reject. It must run after authn.RequireAuth. A failed
+// write is logged and never fails the request.
+func AuditMutations(q auditLogWriter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			method := c.Request().Method
+			if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+				return next(c)
+			}
+			err := next(c)
+			entry := buildAuditLogEntry(c, err)
+			if writeErr := q.InsertAppAuditLogEntry(context.WithoutCancel(c.Request().Context()), entry); writeErr != nil {
+				slog.Warn("audit log write failed", "action", entry.Action, "route", entry.Route, "err", writeErr)
+			}
+			return err
+		}
+	}
+}
+
+func buildAuditLogEntry(c *echo.Context, handlerErr error) gen.InsertAppAuditLogEntryParams {
+	method := c.Request().Method
+	route := c.Path()
+	if route == "" {
+		route = c.Request().URL.Path
+	}
+	entry := gen.InsertAppAuditLogEntryParams{
+		OrgID:      principalOrgID(c),
+		Action:     auditActionForRoute(method, route),
+		Method:     method,
+		Route:      route,
+		Target:     auditTargetFromPath(c),
+		StatusCode: int32(auditStatusCode(c, handlerErr)),
+		RemoteIp:   strings.TrimSpace(c.RealIP()),
+	}
+	entry.RequestID, _ = c.Get(ContextKeyRequestID).(string)
+	if principal, ok := authn.PrincipalFromContext(c); ok {
+		entry.ActorAuthUserID = principalUserID(c)
+		entry.ActorEmail = principal.Email
+		entry.ActorRole = principal.Role
+	}
+	if detail, ok := c.Get(contextKeyAuditDetail).(auditDetail); ok {
+		if detail.Action != "" {
+			entry.Action = detail.Action
+		}
+		if detail.Target != "" {
+			entry.Target = detail.Target
+		}
+		entry.BeforeJson = auditJSON(detail.Before)
+		entry.AfterJson = auditJSON(detail.After)
+	}
+	return entry
+}
+
+func auditActionForRoute(method, route string) string {
+	key := method + " " + route
+	if action, ok := auditActionByRoute[key]; ok {
+		return action
+	}
+	return strings.ToLower(key)
+}
+
+// auditTargetFromPath joins the route's path parameters, e.g. "id=42" or "rulesetKey=cis ruleKey=1.1".
+// A wildcard parameter is recorded as its bare value.
+func auditTargetFromPath(c *echo.Context) string {
+	parts := make([]string, 0, len(c.PathValues()))
+	for _, pv := range c.PathValues() {
+		value := strings.Trim(pv.Value, "/")
+		if value == "" {
+			continue
+		}
+		if pv.Name == "*" {
+			parts = append(parts, value)
+			continue
+		}
+		parts = append(parts, pv.Name+"="+value)
+	}
+	return strings.Join(parts, " ")
+}
+
+func auditStatusCode(c *echo.Context, handlerErr error) int {
+	if handlerErr != nil {
+		var coder echo.HTTPStatusCoder
+		if errors.As(handlerErr, &coder) {
+			return coder.StatusCode()
+		}
+		return http.StatusInternalServerError
+	}
+	if resp, err := echo.UnwrapResponse(c.Response()); err == nil && resp.Status != 0 {
+		return resp.Status
+	}
+	return http.StatusOK
+}
+
+func auditJSON(v any) []byte {
+	if v == nil {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
+
+// HandleAuditLog lists recorded operator actions for the signed-in operator's org, newest first.
+// ?action and ?actor narrow the list.
+func (h *Handlers) HandleAuditLog(c *echo.Context) error {
+	ctx := c.Request().Context()
+	layout, _, err := h.LayoutData(ctx, c, "Audit log")
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	data, err := buildAuditLogViewData(ctx, h.Q, principalOrgID(c),
+		strings.TrimSpace(c.QueryParam("action")),
+		strings.ToLower(strings.TrimSpace(c.QueryParam("actor"))),
+		parsePageParam(c), parsePerPageParam(c))
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	data.Layout = layout
+	return h.RenderComponent(c, views.SettingsAuditLogPage(data))
+}
+
+func buildAuditLogViewData(ctx context.Context, q auditLogQueries, orgID int64, action, actor string, page, perPage int) (viewmodels.AuditLogViewData, error) {
+	total, err := q.CountAppAuditLogEntries(ctx, gen.CountAppAuditLogEntriesParams{OrgID: orgID, Action: action, ActorEmail: actor})
+	if err != nil {
+		return viewmodels.AuditLogViewData{}, err
+	}
+	page, totalPages, offset := paginate(total, page, perPage)
+	rows, err := q.ListAppAuditLogEntries(ctx, gen.ListAppAuditLogEntriesParams{
+		OrgID:      orgID,
+		Action:     action,
+		ActorEmail: actor,
+		PageLimit:  int32(perPage),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return viewmodels.AuditLogViewData{}, err
+	}
+	actions, err := q.ListAppAuditLogActions(ctx, orgID)
+	if err != nil {
+		return viewmodels.AuditLogViewData{}, err
+	}
+
+	data := viewmodels.AuditLogViewData{
+		Actions:        actions,
+		SelectedAction: action,
+		Actor:          actor,
+		Page:           page,
+		PerPage:        perPage,
+		TotalPages:     totalPages,
+		TotalCount:     total,
+		HasEntries:     len(rows) > 0,
+	}
+	data.ShowingFrom, data.ShowingTo = showingRange(total, offset, len(rows))
+	for _, row := range rows {
+		actorLabel := row.ActorEmail
+		if actorLabel == "" {
+			actorLabel = "unknown"
+		}
+		data.Entries = append(data.Entries, viewmodels.AuditLogEntry{
+			OccurredAt:  discoveryReportTimestamp(row.OccurredAt),
+			Actor:       actorLabel,
+			ActorRole:   row.ActorRole,
+			Action:      row.Action,
+			Route:       row.Method + " " + row.Route,
+			Target:      row.Target,
+			StatusCode:  int(row.StatusCode),
+			StatusClass: auditStatusClass(row.StatusCode),
+			Before:      string(row.BeforeJson),
+			After:       string(row.AfterJson),
+			RequestID:   row.RequestID,
+		})
+	}
+	return data, nil
+}
+
+func auditStatusClass(status int32) string {
+	if status >= http.StatusBadRequest {
+		return badgeClassDanger()
+	}
+	return badgeClassNeutral()
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/auth"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestAuditActionForRoute(t *testing.T) {
+	t.Parallel()
+
+	if got := auditActionForRoute(http.MethodPost, "/credentials/:id/revoke"); got != "credential.revoke" {
+		t.Fatalf("mapped action = %q, want credential.revoke", got)
+	}
+	if got := auditActionForRoute(http.MethodPost, "/settings/new-thing/:id"); got != "post /settings/new-thing/:id" {
+		t.Fatalf("unmapped action = %q, want method and route", got)
+	}
+}
+
+func TestAuditTargetFromPath(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestContext(http.MethodPost, "http://example.com/findings/rulesets/cis/rules/1.1/override")
+	c.SetPathValues(echo.PathValues{{Name: "rulesetKey", Value: "cis"}, {Name: "ruleKey", Value: "1.1"}})
+	if got := auditTargetFromPath(c); got != "rulesetKey=cis ruleKey=1.1" {
+		t.Fatalf("target = %q", got)
+	}
+
+	c, _ = newTestContext(http.MethodPost, "http://example.com/settings/connectors/okta/toggle")
+	c.SetPathValues(echo.PathValues{{Name: "*", Value: "okta/toggle"}})
+	if got := auditTargetFromPath(c); got != "okta/toggle" {
+		t.Fatalf("wildcard target = %q", got)
+	}
+}
+
+// auditLogDB keeps app_audit_log in memory on top of the cross-org fixture.
+type auditLogDB struct {
+	*orgScopedDB
+
+	entries []gen.AppAuditLog
+}
+
+func (db *auditLogDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	if strings.Contains(sql, "-- name: InsertAppAuditLogEntry ") {
+		db.entries = append(db.entries, gen.AppAuditLog{
+			ID:         int64(len(db.entries) + 1),
+			OrgID:      args[0].(int64),
+			ActorEmail: args[2].(string),
+			Action:     args[4].(string),
+			Route:      args[6].(string),
+		})
+		return pgconn.NewCommandTag("INSERT 0 1"), nil
+	}
+	return db.orgScopedDB.Exec(ctx, sql, args...)
+}
+
+func (db *auditLogDB) orgEntries(orgID int64) []gen.AppAuditLog {
+	var out []gen.AppAuditLog
+	for _, entry := range db.entries {
+		if entry.OrgID == orgID {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+func (db *auditLogDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	switch {
+	case strings.Contains(sql, "-- name: ListAppAuditLogEntries "):
+		return newStructRows(db.orgEntries(args[0].(int64))), nil
+	case strings.Contains(sql, "-- name: ListAppAuditLogActions "):
+		var actions []struct{ Action string }
+		for _, entry := range db.orgEntries(args[0].(int64)) {
+			actions = append(actions, struct{ Action string }{entry.Action})
+		}
+		return newStructRows(actions), nil
+	}
+	return db.orgScopedDB.Query(ctx, sql, args...)
+}
+
+func (db *auditLogDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if strings.Contains(sql, "-- name: CountAppAuditLogEntries ") {
+		return countRow{count: int64(len(db.orgEntries(args[0].(int64))))}
+	}
+	return db.orgScopedDB.QueryRow(ctx, sql, args...)
+}
+
+func TestAuditLogKeepsEntriesInThePrincipalsOrg(t *testing.T) {
+	t.Parallel()
+
+	h, base := newCrossOrgHandlers(t)
+	db := &auditLogDB{orgScopedDB: base}
+	h.Q = gen.New(db)
+
+	c, _ := newOrgTestContext(http.MethodPost, "/credentials/10/revoke", crossOrgB)
+	c.SetPath("/credentials/:id/revoke")
+	revoke := AuditMutations(h.Q)(func(c *echo.Context) error { return c.NoContent(http.StatusNoContent) })
+	if err := revoke(c); err != nil {
+		t.Fatalf("audited handler error = %v", err)
+	}
+	if len(db.entries) != 1 || db.entries[0].OrgID != crossOrgB {
+		t.Fatalf("audit entries = %+v, want one entry in org %d", db.entries, crossOrgB)
+	}
+
+	for _, tc := range []struct {
+		orgID int64
+		want  bool
+	}{
+		{orgID: crossOrgB, want: true},
+		{orgID: auth.DefaultOrgID, want: false},
+	} {
+		c, rec := newOrgTestContext(http.MethodGet, "/settings/audit-log", tc.orgID)
+		if err := h.HandleAuditLog(c); err != nil {
+			t.Fatalf("HandleAuditLog() org %d error = %v", tc.orgID, err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("org %d status = %d, body = %s", tc.orgID, rec.Code, rec.Body.String())
+		}
+		if got := strings.Contains(rec.Body.String(), "/credentials/:id/revoke"); got != tc.want {
+			t.Fatalf("org %d sees the revoke entry = %v, want %v", tc.orgID, got, tc.want)
+		}
+	}
+}
@@ -38,6 +38,7 @@ func (h *Handlers) HandleLoginGet(c *echo.Context) error {
 		CSRFToken:     csrfToken,
 		Next:          authn.SanitizeNext(c.QueryParam("next")),
 		SetupRequired: count == 0,
+		SSOEnabled:    h.OIDC != nil,
 		Toast:         popFlashToast(c),
 	}
 	return h.RenderComponent(c, views.LoginPage(data))
@@ -61,9 +62,10 @@ func (h *Handlers) HandleLoginPost(c *echo.Context) error {
 
 	csrfToken, _ := c.Get(middleware.DefaultCSRFConfig.ContextKey).(string)
 	data := viewmodels.LoginViewData{
-		CSRFToken: csrfToken,
-		Email:     email,
-		Next:      next,
+		CSRFToken:  csrfToken,
+		Email:      email,
+		Next:       next,
+		SSOEnabled: h.OIDC != nil,
 	}
 
 	if count == 0 {
@@ -85,11 +87,17 @@ func (h *Handlers) HandleLoginPost(c *echo.Context) error {
 		}
 		return err
 	}
+	// With SSO configured, password login is kept only as a local-admin fallback.
+	if h.OIDC != nil && !principal.IsAdmin() {
+		data.ErrorMessage = "Use single sign-on to sign in."
+		return h.RenderComponent(c, views.LoginPage(data))
+	}
 
 	if err := h.Sessions.RenewToken(ctx); err != nil {
 		return err
 	}
 	h.Sessions.Put(ctx, authn.SessionKeyUserID, principal.UserID)
+	h.Sessions.Put(ctx, authn.SessionKeyAuthMethod, auth.MethodPassword)
 
 	_ = h.Q.UpdateAuthUserLoginMeta(ctx, gen.UpdateAuthUserLoginMetaParams{
 		ID:          principal.UserID,
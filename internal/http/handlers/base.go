@@ -8,12 +8,14 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/a-h/templ"
 	"github.com/alexedwards/scs/v2"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/middleware"
+	"github.com/open-sspm/open-sspm/internal/auth"
 	"github.com/open-sspm/open-sspm/internal/config"
 	"github.com/open-sspm/open-sspm/internal/connectors/configstore"
 	"github.com/open-sspm/open-sspm/internal/connectors/registry"
@@ -43,6 +45,10 @@ type Handlers struct {
 	Sessions *scs.SessionManager
 	Syncer   SyncRunner
 	Registry *registry.ConnectorRegistry
+	// OIDC is set when single sign-on is configured.
+	OIDC OIDCAuthenticator
+
+	sourceCounts connectorSourceCountsCache
 }
 
 // ConnectorSnapshot holds the current connector configuration state.
@@ -68,6 +74,14 @@ type ConnectorSnapshot struct {
 	Vault                       configstore.VaultConfig
 	VaultEnabled                bool
 	VaultConfigured             bool
+	Bitbucket                   configstore.BitbucketConfig
+	BitbucketEnabled            bool
+	BitbucketConfigured         bool
+
+	// Sources is filled only by LoadConnectorSnapshotWithStats.
+	Sources []ConnectorSourceStats
+	// Freshness is filled only by LayoutData, keyed by connector kind.
+	Freshness map[string]viewmodels.ConnectorFreshness
 }
 
 // LoadConnectorSnapshot retrieves the current connector configuration.
@@ -76,7 +90,26 @@ func (h *Handlers) LoadConnectorSnapshot(ctx context.Context) (ConnectorSnapshot
 	if err != nil {
 		return ConnectorSnapshot{}, err
 	}
+	return connectorSnapshotFromStates(states), nil
+}
+
+// LoadConnectorSnapshotWithStats adds per-source inventory counts and last-sync freshness to
+// the snapshot. Count failures are logged and leave that count unknown rather than failing.
+func (h *Handlers) LoadConnectorSnapshotWithStats(ctx context.Context) (ConnectorSnapshot, error) {
+	if h.Registry == nil {
+		return ConnectorSnapshot{}, nil
+	}
+	states, err := h.Registry.LoadStates(ctx, h.Q)
+	if err != nil {
+		return ConnectorSnapshot{}, err
+	}
+	snap := connectorSnapshotFromStates(states)
+	now := time.Now()
+	snap.Sources = buildConnectorSourceStats(ctx, h.Cfg, h.Q, states, h.sourceCounts.get(ctx, h.Q, now), now)
+	return snap, nil
+}
 
+func connectorSnapshotFromStates(states []registry.ConnectorState) ConnectorSnapshot {
 	var snap ConnectorSnapshot
 	for _, state := range states {
 		switch state.Definition.Kind() {
@@ -122,18 +155,27 @@ func (h *Handlers) LoadConnectorSnapshot(ctx context.Context) (ConnectorSnapshot
 				snap.VaultEnabled = state.Enabled
 				snap.VaultConfigured = state.Configured
 			}
+		case configstore.KindBitbucket:
+			if cfg, ok := state.Config.(configstore.BitbucketConfig); ok {
+				snap.Bitbucket = cfg
+				snap.BitbucketEnabled = state.Enabled
+				snap.BitbucketConfigured = state.Configured
+			}
 		}
 	}
 
-	return snap, nil
+	return snap
 }
 
 // LayoutData builds the common layout data for page rendering.
+// The snapshot only includes connectors whose source belongs to the principal's org.
 func (h *Handlers) LayoutData(ctx context.Context, c *echo.Context, title string) (viewmodels.LayoutData, ConnectorSnapshot, error) {
-	snap, err := h.LoadConnectorSnapshot(ctx)
+	states, err := h.loadScopedConnectorStates(c)
 	if err != nil {
-		return viewmodels.LayoutData{}, snap, err
+		return viewmodels.LayoutData{}, ConnectorSnapshot{}, err
 	}
+	snap := connectorSnapshotFromStates(states)
+	snap.Freshness = loadConnectorFreshness(ctx, h.Cfg, h.Q, states, time.Now())
 	principal, ok := authn.PrincipalFromContext(c)
 	csrfToken, _ := c.Get(middleware.DefaultCSRFConfig.ContextKey).(string)
 	awsName := strings.TrimSpace(snap.AWSIdentityCenter.Name)
@@ -210,6 +252,7 @@ func (h *Handlers) LayoutData(ctx context.Context, c *echo.Context, title string
 		UserEmail:                   principal.Email,
 		UserRole:                    principal.Role,
 		IsAdmin:                     ok && principal.IsAdmin(),
+		CanAnnotate:                 ok && principal.HasRole(auth.RoleAnalyst),
 		FindingsRulesets:            findingsRulesets,
 		GoogleWorkspaceCustomerID:   snap.GoogleWorkspace.CustomerID,
 		GoogleWorkspaceEnabled:      snap.GoogleWorkspaceEnabled,
@@ -226,11 +269,13 @@ func (h *Handlers) LayoutData(ctx context.Context, c *echo.Context, title string
 		EntraTenantID:               snap.Entra.TenantID,
 		EntraEnabled:                snap.EntraEnabled,
 		EntraConfigured:             snap.EntraConfigured,
+		ConnectorFreshness:          snap.Freshness,
 		Toast:                       popFlashToast(c),
 		ActivePath:                  c.Request().URL.Path,
 		CommandUsers:                commandUsers,
 		CommandApps:                 commandApps,
 	}
+	layout.Timezone, layout.DefaultTimezone, layout.TimezoneOptions = h.displayTimezoneOptions(c)
 	return layout, snap, nil
 }
 
@@ -305,6 +350,8 @@ func ConnectorDisplayName(kind string) string {
 		return "Microsoft Entra ID"
 	case configstore.KindVault:
 		return "Vault"
+	case configstore.KindBitbucket:
+		return "Bitbucket"
 	default:
 		return ""
 	}
@@ -313,7 +360,7 @@ func ConnectorDisplayName(kind string) string {
 // IsKnownConnectorKind checks if the kind is a recognized connector.
 func IsKnownConnectorKind(kind string) bool {
 	switch NormalizeConnectorKind(kind) {
-	case configstore.KindOkta, configstore.KindGoogleWorkspace, configstore.KindGitHub, configstore.KindDatadog, configstore.KindAWSIdentityCenter, configstore.KindEntra, configstore.KindVault:
+	case configstore.KindOkta, configstore.KindGoogleWorkspace, configstore.KindGitHub, configstore.KindDatadog, configstore.KindAWSIdentityCenter, configstore.KindEntra, configstore.KindVault, configstore.KindBitbucket:
 		return true
 	default:
 		return false
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/config"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+)
+
+// Connector freshness levels. Stale and never-synced connectors both render red.
+const (
+	connectorFreshnessFresh = "fresh"
+	connectorFreshnessAging = "aging"
+	connectorFreshnessStale = "stale"
+	connectorFreshnessNever = "never"
+)
+
+type connectorFreshnessQueries interface {
+	ListLatestSuccessfulSyncFinishedAtForSources(context.Context, gen.ListLatestSuccessfulSyncFinishedAtForSourcesParams) ([]gen.ListLatestSuccessfulSyncFinishedAtForSourcesRow, error)
+}
+
+// connectorFreshnessLevel grades the age of a connector's last successful sync against the
+// amber and red thresholds. An age equal to a threshold is still the greener level.
+func connectorFreshnessLevel(lastSuccessAt pgtype.Timestamptz, now time.Time, amberAfter, redAfter time.Duration) string {
+	if !lastSuccessAt.Valid {
+		return connectorFreshnessNever
+	}
+	age := now.Sub(lastSuccessAt.Time)
+	switch {
+	case age > redAfter:
+		return connectorFreshnessStale
+	case age > amberAfter:
+		return connectorFreshnessAging
+	default:
+		return connectorFreshnessFresh
+	}
+}
+
+func buildConnectorFreshness(lastSuccessAt pgtype.Timestamptz, now time.Time, amberAfter, redAfter time.Duration) viewmodels.ConnectorFreshness {
+	level := connectorFreshnessLevel(lastSuccessAt, now, amberAfter, redAfter)
+	freshness := viewmodels.ConnectorFreshness{Level: level}
+	switch level {
+	case connectorFreshnessNever:
+		freshness.Label = "Never synced"
+		freshness.Title = "No successful sync yet"
+		return freshness
+	case connectorFreshnessStale:
+		freshness.Label = "Stale"
+	case connectorFreshnessAging:
+		freshness.Label = "Aging"
+	default:
+		freshness.Label = "Fresh"
+	}
+	freshness.Title = "Last successful sync " + formatAge(now, lastSuccessAt.Time)
+	return freshness
+}
+
+// loadConnectorFreshness returns the freshness badge of each enabled, configured connector by
+// kind. A failed query is logged and yields no badges, so pages still render.
+func loadConnectorFreshness(ctx context.Context, cfg config.Config, q connectorFreshnessQueries, states []registry.ConnectorState, now time.Time) map[string]viewmodels.ConnectorFreshness {
+	kinds := make([]string, 0, len(states))
+	var sourceKinds, sourceNames []string
+	for _, st := range states {
+		sourceName := strings.TrimSpace(st.SourceName)
+		if !st.Enabled || !st.Configured || sourceName == "" {
+			continue
+		}
+		kinds = append(kinds, st.Definition.Kind())
+		sourceKinds = append(sourceKinds, connectorSyncKind(st.Definition.Kind()))
+		sourceNames = append(sourceNames, sourceName)
+	}
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	rows, err := q.ListLatestSuccessfulSyncFinishedAtForSources(ctx, gen.ListLatestSuccessfulSyncFinishedAtForSourcesParams{
+		SourceKinds: sourceKinds,
+		SourceNames: sourceNames,
+	})
+	if err != nil {
+		slog.Warn("connector freshness failed", "err", err)
+		return nil
+	}
+	lastSuccess := make(map[syncRollupKey]pgtype.Timestamptz, len(rows))
+	for _, row := range rows {
+		lastSuccess[syncRollupKey{kind: row.SourceKind, name: row.SourceName}] = row.LastSuccessAt
+	}
+	out := make(map[string]viewmodels.ConnectorFreshness, len(kinds))
+	for i, kind := range kinds {
+		at := lastSuccess[syncRollupKey{kind: sourceKinds[i], name: sourceNames[i]}]
+		out[kind] = buildConnectorFreshness(at, now, cfg.ConnectorFreshnessAmberAfter, cfg.ConnectorFreshnessRedAfter)
+	}
+	return out
+}
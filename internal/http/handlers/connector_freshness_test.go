@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/config"
+	connregistry "github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestConnectorFreshnessLevel(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: now.Add(-d), Valid: true}
+	}
+	for _, tc := range []struct {
+		name        string
+		lastSuccess pgtype.Timestamptz
+		want        string
+	}{
+		{name: "never synced", lastSuccess: pgtype.Timestamptz{}, want: connectorFreshnessNever},
+		{name: "just synced", lastSuccess: ago(0), want: connectorFreshnessFresh},
+		{name: "at the amber threshold", lastSuccess: ago(6 * time.Hour), want: connectorFreshnessFresh},
+		{name: "past the amber threshold", lastSuccess: ago(6*time.Hour + time.Second), want: connectorFreshnessAging},
+		{name: "at the red threshold", lastSuccess: ago(24 * time.Hour), want: connectorFreshnessAging},
+		{name: "past the red threshold", lastSuccess: ago(24*time.Hour + time.Second), want: connectorFreshnessStale},
+	} {
+		if got := connectorFreshnessLevel(tc.lastSuccess, now, 6*time.Hour, 24*time.Hour); got != tc.want {
+			t.Fatalf("%s: connectorFreshnessLevel() = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+type fakeFreshnessQueries struct {
+	lastSuccess map[string]time.Time
+}
+
+func (f fakeFreshnessQueries) ListLatestSuccessfulSyncFinishedAtForSources(_ context.Context, arg gen.ListLatestSuccessfulSyncFinishedAtForSourcesParams) ([]gen.ListLatestSuccessfulSyncFinishedAtForSourcesRow, error) {
+	var out []gen.ListLatestSuccessfulSyncFinishedAtForSourcesRow
+	for i := range arg.SourceKinds {
+		at, ok := f.lastSuccess[arg.SourceKinds[i]]
+		if !ok {
+			continue
+		}
+		out = append(out, gen.ListLatestSuccessfulSyncFinishedAtForSourcesRow{
+			SourceKind:    arg.SourceKinds[i],
+			SourceName:    arg.SourceNames[i],
+			LastSuccessAt: pgtype.Timestamptz{Time: at, Valid: true},
+		})
+	}
+	return out, nil
+}
+
+func TestLoadConnectorFreshness(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := config.Config{ConnectorFreshnessAmberAfter: 6 * time.Hour, ConnectorFreshnessRedAfter: 24 * time.Hour}
+	q := fakeFreshnessQueries{lastSuccess: map[string]time.Time{
+		"github": now.Add(-30 * time.Minute),
+		"aws":    now.Add(-3 * 24 * time.Hour),
+	}}
+	states := []connregistry.ConnectorState{
+		{Definition: fakeStatsDefinition{kind: "github"}, Enabled: true, Configured: true, SourceName: "acme"},
+		{Definition: fakeStatsDefinition{kind: "aws_identity_center"}, Enabled: true, Configured: true, SourceName: "123456789012"},
+		{Definition: fakeStatsDefinition{kind: "entra"}, Enabled: true, Configured: true, SourceName: "tenant"},
+		{Definition: fakeStatsDefinition{kind: "okta"}, Enabled: false, Configured: true, SourceName: "acme.okta.com"},
+	}
+
+	got := loadConnectorFreshness(context.Background(), cfg, q, states, now)
+	if len(got) != 3 {
+		t.Fatalf("len(freshness) = %d, want 3 (disabled connectors get no badge): %+v", len(got), got)
+	}
+	if github := got["github"]; github.Level != connectorFreshnessFresh || github.Title != "Last successful sync 30m ago" {
+		t.Fatalf("github freshness = %+v, want fresh as of 30m ago", github)
+	}
+	if aws := got["aws_identity_center"]; aws.Level != connectorFreshnessStale || aws.Label != "Stale" {
+		t.Fatalf("aws freshness = %+v, want stale", aws)
+	}
+	if entra := got["entra"]; entra.Level != connectorFreshnessNever || entra.Label != "Never synced" {
+		t.Fatalf("entra freshness = %+v, want never synced", entra)
+	}
+	if got := loadConnectorFreshness(context.Background(), cfg, q, states[3:], now); got != nil {
+		t.Fatalf("loadConnectorFreshness() = %+v, want nil without enabled connectors", got)
+	}
+}
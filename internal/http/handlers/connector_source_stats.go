@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/config"
+	connregistry "github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// connectorSourceCountsTTL bounds how long the grouped inventory counts are reused. The
+// dashboard is reloaded often and the counts only move when a sync finishes.
+const connectorSourceCountsTTL = 30 * time.Second
+
+// ConnectorSourceStats is the inventory and sync freshness of one connector source. Counts
+// that could not be loaded are left invalid so the rest of the snapshot still renders.
+type ConnectorSourceStats struct {
+	Kind          string
+	SourceKind    string
+	SourceName    string
+	DisplayName   string
+	Enabled       bool
+	Configured    bool
+	Users         pgtype.Int8
+	Assets        pgtype.Int8
+	Credentials   pgtype.Int8
+	DiscoveryApps pgtype.Int8
+	LastSuccessAt pgtype.Timestamptz
+	Stale         bool
+}
+
+type connectorSourceStatsQueries interface {
+	CountAppAssetsGroupedBySource(context.Context) ([]gen.CountAppAssetsGroupedBySourceRow, error)
+	CountAppUsersGroupedBySource(context.Context) ([]gen.CountAppUsersGroupedBySourceRow, error)
+	CountCredentialArtifactsGroupedBySource(context.Context) ([]gen.CountCredentialArtifactsGroupedBySourceRow, error)
+	CountSaaSAppSourcesGroupedBySource(context.Context) ([]gen.CountSaaSAppSourcesGroupedBySourceRow, error)
+	ListLatestSuccessfulSyncFinishedAtForSources(context.Context, gen.ListLatestSuccessfulSyncFinishedAtForSourcesParams) ([]gen.ListLatestSuccessfulSyncFinishedAtForSourcesRow, error)
+}
+
+// connectorSourceCounts holds the grouped inventory counts. A nil map means its query failed.
+type connectorSourceCounts struct {
+	users         map[syncRollupKey]int64
+	assets        map[syncRollupKey]int64
+	credentials   map[syncRollupKey]int64
+	discoveryApps map[syncRollupKey]int64
+}
+
+func (c connectorSourceCounts) complete() bool {
+	return c.users != nil && c.assets != nil && c.credentials != nil && c.discoveryApps != nil
+}
+
+type connectorSourceCountsCache struct {
+	mu       sync.Mutex
+	loadedAt time.Time
+	counts   connectorSourceCounts
+}
+
+// get returns cached counts while they are fresh. A partially failed load is returned but not
+// cached, so the failing query is retried on the next request.
+func (c *connectorSourceCountsCache) get(ctx context.Context, q connectorSourceStatsQueries, now time.Time) connectorSourceCounts {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loadedAt.IsZero() && now.Sub(c.loadedAt) < connectorSourceCountsTTL {
+		return c.counts
+	}
+	counts := loadConnectorSourceCounts(ctx, q)
+	if counts.complete() {
+		c.counts = counts
+		c.loadedAt = now
+	}
+	return counts
+}
+
+func loadConnectorSourceCounts(ctx context.Context, q connectorSourceStatsQueries) connectorSourceCounts {
+	var counts connectorSourceCounts
+	if rows, err := q.CountAppUsersGroupedBySource(ctx); err != nil {
+		slog.Warn("connector source user counts failed", "err", err)
+	} else {
+		counts.users = make(map[syncRollupKey]int64, len(rows))
+		for _, row := range rows {
+			counts.users[syncRollupKey{kind: row.SourceKind, name: row.SourceName}] = row.UserCount
+		}
+	}
+	if rows, err := q.CountAppAssetsGroupedBySource(ctx); err != nil {
+		slog.Warn("connector source asset counts failed", "err", err)
+	} else {
+		counts.assets = make(map[syncRollupKey]int64, len(rows))
+		for _, row := range rows {
+			counts.assets[syncRollupKey{kind: row.SourceKind, name: row.SourceName}] = row.AssetCount
+		}
+	}
+	if rows, err := q.CountCredentialArtifactsGroupedBySource(ctx); err != nil {
+		slog.Warn("connector source credential counts failed", "err", err)
+	} else {
+		counts.credentials = make(map[syncRollupKey]int64, len(rows))
+		for _, row := range rows {
+			counts.credentials[syncRollupKey{kind: row.SourceKind, name: row.SourceName}] = row.CredentialCount
+		}
+	}
+	if rows, err := q.CountSaaSAppSourcesGroupedBySource(ctx); err != nil {
+		slog.Warn("connector source discovery app counts failed", "err", err)
+	} else {
+		counts.discoveryApps = make(map[syncRollupKey]int64, len(rows))
+		for _, row := range rows {
+			counts.discoveryApps[syncRollupKey{kind: row.SourceKind, name: row.SourceName}] = row.AppCount
+		}
+	}
+	return counts
+}
+
+// buildConnectorSourceStats returns one entry per connector state. Only configured sources
+// get counts and freshness; unconfigured ones are listed so the dashboard can say so.
+func buildConnectorSourceStats(ctx context.Context, cfg config.Config, q connectorSourceStatsQueries, states []connregistry.ConnectorState, counts connectorSourceCounts, now time.Time) []ConnectorSourceStats {
+	out := make([]ConnectorSourceStats, 0, len(states))
+	var sourceKinds, sourceNames []string
+	for _, st := range states {
+		kind := strings.TrimSpace(st.Definition.Kind())
+		displayName := strings.TrimSpace(st.Definition.DisplayName())
+		if displayName == "" {
+			displayName = kind
+		}
+		stats := ConnectorSourceStats{
+			Kind:        kind,
+			SourceKind:  connectorSyncKind(kind),
+			SourceName:  strings.TrimSpace(st.SourceName),
+			DisplayName: displayName,
+			Enabled:     st.Enabled,
+			Configured:  st.Configured && strings.TrimSpace(st.SourceName) != "",
+		}
+		if stats.Configured {
+			key := syncRollupKey{kind: stats.SourceKind, name: stats.SourceName}
+			stats.Users = connectorSourceCount(counts.users, key)
+			stats.Assets = connectorSourceCount(counts.assets, key)
+			stats.Credentials = connectorSourceCount(counts.credentials, key)
+			stats.DiscoveryApps = connectorSourceCount(counts.discoveryApps, key)
+			sourceKinds = append(sourceKinds, stats.SourceKind)
+			sourceNames = append(sourceNames, stats.SourceName)
+		}
+		out = append(out, stats)
+	}
+	if len(sourceKinds) == 0 {
+		return out
+	}
+
+	rows, err := q.ListLatestSuccessfulSyncFinishedAtForSources(ctx, gen.ListLatestSuccessfulSyncFinishedAtForSourcesParams{
+		SourceKinds: sourceKinds,
+		SourceNames: sourceNames,
+	})
+	if err != nil {
+		slog.Warn("connector source freshness failed", "err", err)
+		return out
+	}
+	lastSuccess := make(map[syncRollupKey]pgtype.Timestamptz, len(rows))
+	for _, row := range rows {
+		lastSuccess[syncRollupKey{kind: row.SourceKind, name: row.SourceName}] = row.LastSuccessAt
+	}
+	for i := range out {
+		if !out[i].Configured {
+			continue
+		}
+		out[i].LastSuccessAt = lastSuccess[syncRollupKey{kind: out[i].SourceKind, name: out[i].SourceName}]
+		staleAfter := staleAfterInterval(expectedIntervalForSyncKind(cfg, out[i].SourceKind))
+		out[i].Stale = out[i].Enabled && (!out[i].LastSuccessAt.Valid || now.Sub(out[i].LastSuccessAt.Time) > staleAfter)
+	}
+	return out
+}
+
+// connectorSourceCount reports a missing row as zero, but a failed query (nil map) as unknown.
+func connectorSourceCount(counts map[syncRollupKey]int64, key syncRollupKey) pgtype.Int8 {
+	if counts == nil {
+		return pgtype.Int8{}
+	}
+	return pgtype.Int8{Int64: counts[key], Valid: true}
+}
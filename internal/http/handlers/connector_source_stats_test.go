@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/config"
+	connregistry "github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+type fakeStatsDefinition struct {
+	kind        string
+	displayName string
+}
+
+func (f fakeStatsDefinition) Kind() string                                { return f.kind }
+func (f fakeStatsDefinition) DisplayName() string                         { return f.displayName }
+func (fakeStatsDefinition) Role() connregistry.IntegrationRole            { return connregistry.RoleApp }
+func (fakeStatsDefinition) DecodeConfig(raw []byte) (any, error)          { return nil, nil }
+func (fakeStatsDefinition) ValidateConfig(cfg any) error                  { return nil }
+func (fakeStatsDefinition) IsConfigured(cfg any) bool                     { return false }
+func (fakeStatsDefinition) SourceName(cfg any) string                     { return "" }
+func (fakeStatsDefinition) DefaultSubtitle() string                       { return "" }
+func (fakeStatsDefinition) ConfiguredSubtitle(cfg any) string             { return "" }
+func (fakeStatsDefinition) SettingsHref() string                          { return "" }
+func (fakeStatsDefinition) MetricsProvider() connregistry.MetricsProvider { return nil }
+func (fakeStatsDefinition) NewIntegration(cfg any) (connregistry.Integration, error) {
+	return nil, nil
+}
+
+type fakeSourceStatsQueries struct {
+	credentialsErr error
+	lastSuccess    time.Time
+	countCalls     int
+}
+
+func (f *fakeSourceStatsQueries) CountAppAssetsGroupedBySource(context.Context) ([]gen.CountAppAssetsGroupedBySourceRow, error) {
+	return []gen.CountAppAssetsGroupedBySourceRow{{SourceKind: "github", SourceName: "acme", AssetCount: 12}}, nil
+}
+
+func (f *fakeSourceStatsQueries) CountAppUsersGroupedBySource(context.Context) ([]gen.CountAppUsersGroupedBySourceRow, error) {
+	f.countCalls++
+	return []gen.CountAppUsersGroupedBySourceRow{
+		{SourceKind: "github", SourceName: "acme", UserCount: 40},
+		{SourceKind: "okta", SourceName: "stale.okta.com", UserCount: 7},
+	}, nil
+}
+
+func (f *fakeSourceStatsQueries) CountCredentialArtifactsGroupedBySource(context.Context) ([]gen.CountCredentialArtifactsGroupedBySourceRow, error) {
+	if f.credentialsErr != nil {
+		return nil, f.credentialsErr
+	}
+	return []gen.CountCredentialArtifactsGroupedBySourceRow{{SourceKind: "github", SourceName: "acme", CredentialCount: 3}}, nil
+}
+
+func (f *fakeSourceStatsQueries) CountSaaSAppSourcesGroupedBySource(context.Context) ([]gen.CountSaaSAppSourcesGroupedBySourceRow, error) {
+	return nil, nil
+}
+
+func (f *fakeSourceStatsQueries) ListLatestSuccessfulSyncFinishedAtForSources(_ context.Context, arg gen.ListLatestSuccessfulSyncFinishedAtForSourcesParams) ([]gen.ListLatestSuccessfulSyncFinishedAtForSourcesRow, error) {
+	out := make([]gen.ListLatestSuccessfulSyncFinishedAtForSourcesRow, 0, len(arg.SourceKinds))
+	for i := range arg.SourceKinds {
+		out = append(out, gen.ListLatestSuccessfulSyncFinishedAtForSourcesRow{
+			SourceKind:    arg.SourceKinds[i],
+			SourceName:    arg.SourceNames[i],
+			LastSuccessAt: pgtype.Timestamptz{Time: f.lastSuccess, Valid: true},
+		})
+	}
+	return out, nil
+}
+
+func TestBuildConnectorSourceStats(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	q := &fakeSourceStatsQueries{credentialsErr: errors.New("statement timeout"), lastSuccess: now.Add(-10 * time.Minute)}
+	states := []connregistry.ConnectorState{
+		{Definition: fakeStatsDefinition{kind: "github", displayName: "GitHub"}, Enabled: true, Configured: true, SourceName: "acme"},
+		{Definition: fakeStatsDefinition{kind: "okta", displayName: "Okta"}, SourceName: ""},
+	}
+
+	ctx := context.Background()
+	counts := loadConnectorSourceCounts(ctx, q)
+	stats := buildConnectorSourceStats(ctx, config.Config{SyncInterval: 15 * time.Minute}, q, states, counts, now)
+	if len(stats) != 2 {
+		t.Fatalf("len(stats) = %d, want 2", len(stats))
+	}
+
+	github := stats[0]
+	if !github.Configured || github.Users != (pgtype.Int8{Int64: 40, Valid: true}) || github.Assets != (pgtype.Int8{Int64: 12, Valid: true}) {
+		t.Fatalf("github stats = %+v", github)
+	}
+	if github.Credentials.Valid {
+		t.Fatalf("credential count = %+v, want unknown after query failure", github.Credentials)
+	}
+	if github.DiscoveryApps != (pgtype.Int8{Int64: 0, Valid: true}) {
+		t.Fatalf("discovery apps = %+v, want a known zero", github.DiscoveryApps)
+	}
+	if !github.LastSuccessAt.Valid || github.Stale {
+		t.Fatalf("github freshness = %+v stale=%v, want fresh", github.LastSuccessAt, github.Stale)
+	}
+
+	okta := stats[1]
+	if okta.Configured || okta.Users.Valid || okta.LastSuccessAt.Valid {
+		t.Fatalf("unconfigured okta stats = %+v, want no counts or freshness", okta)
+	}
+
+	items := dashboardConnectorItems(stats, now)
+	if items[0].StatusLabel != "Fresh" || items[0].Credentials != "—" || items[0].Users != "40" {
+		t.Fatalf("github item = %+v", items[0])
+	}
+	if items[1].StatusLabel != "Not configured" {
+		t.Fatalf("okta item = %+v", items[1])
+	}
+}
+
+func TestConnectorSourceCountsCacheSkipsPartialLoads(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	q := &fakeSourceStatsQueries{credentialsErr: errors.New("statement timeout")}
+	var cache connectorSourceCountsCache
+	ctx := context.Background()
+
+	cache.get(ctx, q, now)
+	cache.get(ctx, q, now)
+	if q.countCalls != 2 {
+		t.Fatalf("count queries ran %d times, want a retry after a partial load", q.countCalls)
+	}
+
+	q.credentialsErr = nil
+	cache.get(ctx, q, now)
+	cache.get(ctx, q, now.Add(connectorSourceCountsTTL/2))
+	if q.countCalls != 3 {
+		t.Fatalf("count queries ran %d times, want cached result within the TTL", q.countCalls)
+	}
+	cache.get(ctx, q, now.Add(connectorSourceCountsTTL))
+	if q.countCalls != 4 {
+		t.Fatalf("count queries ran %d times, want reload after the TTL", q.countCalls)
+	}
+}
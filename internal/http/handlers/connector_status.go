@@ -24,6 +24,7 @@ const (
 type syncRunRollup struct {
 	lastRunStatus          string
 	lastRunErrorKind       string
+	lastRunWarningCount    int64
 	lastRunFinishedAt      *time.Time
 	lastSuccessAt          *time.Time
 	finishedCount7d        int64
@@ -87,7 +88,7 @@ func connectorHealth(input connectorHealthInput) connectorHealthResult {
 	}
 
 	if rollup.lastRunFinishedAt != nil && !rollup.lastRunFinishedAt.IsZero() {
-		result.lastRunLabel = formatRunLabel(rollup.lastRunStatus, rollup.lastRunErrorKind, formatAge(input.now, *rollup.lastRunFinishedAt))
+		result.lastRunLabel = formatRunLabel(rollup.lastRunStatus, rollup.lastRunErrorKind, rollup.lastRunWarningCount, formatAge(input.now, *rollup.lastRunFinishedAt))
 	}
 
 	if !input.enabled {
@@ -183,7 +184,7 @@ func formatAge(now time.Time, t time.Time) string {
 	}
 }
 
-func formatRunLabel(status, errorKind, age string) string {
+func formatRunLabel(status, errorKind string, warningCount int64, age string) string {
 	status = strings.ToLower(strings.TrimSpace(status))
 	errorKind = strings.ToLower(strings.TrimSpace(errorKind))
 	age = strings.TrimSpace(age)
@@ -206,6 +207,13 @@ func formatRunLabel(status, errorKind, age string) string {
 		label = strings.Title(label) //nolint:staticcheck // acceptable for short UI labels
 	}
 
+	switch {
+	case warningCount == 1:
+		label += " with 1 warning"
+	case warningCount > 1:
+		label += fmt.Sprintf(" with %d warnings", warningCount)
+	}
+
 	if age != "" && age != "—" {
 		return label + " · " + age
 	}
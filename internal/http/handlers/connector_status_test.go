@@ -170,6 +170,31 @@ func TestConnectorHealthWrapper(t *testing.T) {
 	})
 }
 
+func TestConnectorHealthSuccessWithWarningsStaysHealthy(t *testing.T) {
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	fiveMinutesAgo := now.Add(-5 * time.Minute)
+
+	got := connectorHealth(connectorHealthInput{
+		syncable:         true,
+		configured:       true,
+		enabled:          true,
+		expectedInterval: 15 * time.Minute,
+		now:              now,
+		rollup: syncRunRollup{
+			lastSuccessAt:       &fiveMinutesAgo,
+			lastRunStatus:       "success",
+			lastRunWarningCount: 2,
+			lastRunFinishedAt:   &fiveMinutesAgo,
+		},
+	})
+	if got.status != connectorHealthHealthy {
+		t.Fatalf("status=%q want %q", got.status, connectorHealthHealthy)
+	}
+	if want := "Success with 2 warnings · 5m ago"; got.lastRunLabel != want {
+		t.Fatalf("lastRunLabel=%q want %q", got.lastRunLabel, want)
+	}
+}
+
 func TestFormatSuccessRate(t *testing.T) {
 	if got := formatSuccessRate(0, 0); got != "—" {
 		t.Fatalf("got %q want %q", got, "—")
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+const (
+	// credentialExpiringUnownedDefaultDays is the expiry window when ?days is not set.
+	credentialExpiringUnownedDefaultDays = 30
+	// credentialExpiringUnownedCandidateLimit bounds the expiring credentials checked for owners.
+	credentialExpiringUnownedCandidateLimit = 50000
+)
+
+// credentialOwnerResolver links a recorded actor to an identity; *identityLinkResolver is the
+// implementation, returning "" when no identity matches.
+type credentialOwnerResolver interface {
+	Resolve(sourceKind, sourceName, externalID, email, displayName string) string
+}
+
+// credentialExpiringUnownedReportRow is one credential expiring soon that nobody can be notified
+// about. Field order matches the CSV columns.
+type credentialExpiringUnownedReportRow struct {
+	ID              int64  `json:"id"`
+	SourceKind      string `json:"source_kind"`
+	SourceName      string `json:"source_name"`
+	CredentialKind  string `json:"credential_kind"`
+	ExternalID      string `json:"external_id"`
+	DisplayName     string `json:"display_name"`
+	Status          string `json:"status"`
+	ExpiresAtSource string `json:"expires_at_source"`
+	DaysUntilExpiry int    `json:"days_until_expiry"`
+	CreatedBy       string `json:"created_by"`
+	ApprovedBy      string `json:"approved_by"`
+	AssetOwners     string `json:"asset_owners"`
+}
+
+var credentialExpiringUnownedReportColumns = []string{
+	"id",
+	"source_kind",
+	"source_name",
+	"credential_kind",
+	"external_id",
+	"display_name",
+	"status",
+	"expires_at_source",
+	"days_until_expiry",
+	"created_by",
+	"approved_by",
+	"asset_owners",
+}
+
+func (r credentialExpiringUnownedReportRow) csvRecord() []string {
+	return []string{
+		strconv.FormatInt(r.ID, 10),
+		r.SourceKind,
+		r.SourceName,
+		r.CredentialKind,
+		r.ExternalID,
+		r.DisplayName,
+		r.Status,
+		r.ExpiresAtSource,
+		strconv.Itoa(r.DaysUntilExpiry),
+		r.CreatedBy,
+		r.ApprovedBy,
+		r.AssetOwners,
+	}
+}
+
+// HandleCredentialExpiringUnownedReport exports active credentials expiring within ?days (30 by
+// default) whose creator, approver, and asset owners all fail to resolve to an identity, soonest
+// first: nobody will be told to rotate them. Use ?format=json for JSON; CSV is the default.
+func (h *Handlers) HandleCredentialExpiringUnownedReport(c *echo.Context) error {
+	format := strings.ToLower(strings.TrimSpace(c.QueryParam("format")))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		return c.String(http.StatusBadRequest, "format must be csv or json")
+	}
+	days := min(max(parseIntParamDefault(c.QueryParam("days"), credentialExpiringUnownedDefaultDays), 1), 3650)
+
+	ctx := c.Request().Context()
+	rows, err := h.Q.ListCredentialArtifactsExpiringWithin(ctx, gen.ListCredentialArtifactsExpiringWithinParams{
+		WithinDays:     int32(days),
+		ActiveStatuses: h.Cfg.CredentialActiveStatuses,
+		RowLimit:       credentialExpiringUnownedCandidateLimit,
+	})
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	candidates := make([]gen.ListCredentialArtifactsExpiringWithinRow, 0, len(rows))
+	for _, row := range rows {
+		if scope.AllowsSource(row.SourceKind, row.SourceName) {
+			candidates = append(candidates, row)
+		}
+	}
+	owners, err := h.loadExpiringCredentialAssetOwners(ctx, candidates)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	report := credentialExpiringUnownedReportRows(candidates, owners, newIdentityLinkResolver(h, ctx), time.Now())
+
+	filename := "credentials-expiring-unowned-" + time.Now().UTC().Format("20060102") + "." + format
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if format == "json" {
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c.Response().WriteHeader(http.StatusOK)
+		return json.NewEncoder(c.Response()).Encode(report)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().WriteHeader(http.StatusOK)
+	w := csv.NewWriter(c.Response())
+	if err := w.Write(credentialExpiringUnownedReportColumns); err != nil {
+		return err
+	}
+	for _, row := range report {
+		if err := w.Write(row.csvRecord()); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// loadExpiringCredentialAssetOwners loads the owners of the candidates' app assets in one query,
+// keyed by asset ID.
+func (h *Handlers) loadExpiringCredentialAssetOwners(ctx context.Context, rows []gen.ListCredentialArtifactsExpiringWithinRow) (map[int64][]gen.AppAssetOwner, error) {
+	seen := make(map[int64]struct{})
+	assetIDs := make([]int64, 0)
+	for _, row := range rows {
+		if !row.AppAssetID.Valid {
+			continue
+		}
+		if _, ok := seen[row.AppAssetID.Int64]; ok {
+			continue
+		}
+		seen[row.AppAssetID.Int64] = struct{}{}
+		assetIDs = append(assetIDs, row.AppAssetID.Int64)
+	}
+	if len(assetIDs) == 0 {
+		return nil, nil
+	}
+	owners, err := h.Q.ListAppAssetOwnersByAssetIDs(ctx, assetIDs)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int64][]gen.AppAssetOwner, len(assetIDs))
+	for _, owner := range owners {
+		out[owner.AppAssetID] = append(out[owner.AppAssetID], owner)
+	}
+	return out, nil
+}
+
+// credentialExpiringUnownedReportRows keeps the expiring credentials (already ordered soonest
+// first) with no creator, approver, or asset owner that resolves to an identity. Resolution stops
+// at the first match, so owned credentials cost one lookup.
+func credentialExpiringUnownedReportRows(rows []gen.ListCredentialArtifactsExpiringWithinRow, owners map[int64][]gen.AppAssetOwner, resolver credentialOwnerResolver, now time.Time) []credentialExpiringUnownedReportRow {
+	out := make([]credentialExpiringUnownedReportRow, 0)
+	for _, row := range rows {
+		sourceKind := strings.TrimSpace(row.SourceKind)
+		sourceName := strings.TrimSpace(row.SourceName)
+		if resolver.Resolve(sourceKind, sourceName, row.CreatedByExternalID, "", row.CreatedByDisplayName) != "" {
+			continue
+		}
+		if resolver.Resolve(sourceKind, sourceName, row.ApprovedByExternalID, "", row.ApprovedByDisplayName) != "" {
+			continue
+		}
+		var assetOwners []gen.AppAssetOwner
+		if row.AppAssetID.Valid {
+			assetOwners = owners[row.AppAssetID.Int64]
+		}
+		owned := false
+		ownerNames := make([]string, 0, len(assetOwners))
+		for _, owner := range assetOwners {
+			if resolver.Resolve(sourceKind, sourceName, owner.OwnerExternalID, owner.OwnerEmail, owner.OwnerDisplayName) != "" {
+				owned = true
+				break
+			}
+			name := actorDisplayName(owner.OwnerDisplayName, owner.OwnerEmail)
+			if name == "" {
+				name = strings.TrimSpace(owner.OwnerExternalID)
+			}
+			if name != "" {
+				ownerNames = append(ownerNames, name)
+			}
+		}
+		if owned {
+			continue
+		}
+
+		out = append(out, credentialExpiringUnownedReportRow{
+			ID:              row.ID,
+			SourceKind:      sourceKind,
+			SourceName:      sourceName,
+			CredentialKind:  strings.TrimSpace(row.CredentialKind),
+			ExternalID:      strings.TrimSpace(row.ExternalID),
+			DisplayName:     strings.TrimSpace(row.DisplayName),
+			Status:          strings.TrimSpace(row.Status),
+			ExpiresAtSource: discoveryReportTimestamp(row.ExpiresAtSource),
+			DaysUntilExpiry: max(int(row.ExpiresAtSource.Time.Sub(now)/(24*time.Hour)), 0),
+			CreatedBy:       actorDisplayName(row.CreatedByDisplayName, row.CreatedByExternalID),
+			ApprovedBy:      actorDisplayName(row.ApprovedByDisplayName, row.ApprovedByExternalID),
+			AssetOwners:     strings.Join(ownerNames, "; "),
+		})
+	}
+	return out
+}
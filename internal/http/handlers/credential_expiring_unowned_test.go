@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// stubOwnerResolver resolves the actors whose external ID or email it knows.
+type stubOwnerResolver map[string]string
+
+func (r stubOwnerResolver) Resolve(_, _, externalID, email, _ string) string {
+	if href := r[externalID]; href != "" {
+		return href
+	}
+	return r[email]
+}
+
+func TestCredentialExpiringUnownedReportRowsKeepsOnlyUnownedCredentials(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	expiring := func(id int64, inDays int) gen.ListCredentialArtifactsExpiringWithinRow {
+		return gen.ListCredentialArtifactsExpiringWithinRow{
+			ID:              id,
+			SourceKind:      "entra",
+			SourceName:      "tenant",
+			CredentialKind:  "entra_client_secret",
+			Status:          "active",
+			ExpiresAtSource: pgtype.Timestamptz{Time: now.AddDate(0, 0, inDays), Valid: true},
+		}
+	}
+
+	orphan := expiring(1, 2)
+	orphan.CreatedByExternalID = "departed-user"
+	orphan.CreatedByDisplayName = "Departed User"
+
+	createdByKnown := expiring(2, 3)
+	createdByKnown.CreatedByExternalID = "alice"
+
+	approvedByKnown := expiring(3, 4)
+	approvedByKnown.CreatedByExternalID = "departed-user"
+	approvedByKnown.ApprovedByExternalID = "bob"
+
+	assetOwned := expiring(4, 5)
+	assetOwned.AppAssetID = pgtype.Int8{Int64: 40, Valid: true}
+
+	assetOrphan := expiring(5, 9)
+	assetOrphan.AppAssetID = pgtype.Int8{Int64: 50, Valid: true}
+
+	owners := map[int64][]gen.AppAssetOwner{
+		40: {
+			{AppAssetID: 40, OwnerExternalID: "gone-owner", OwnerDisplayName: "Gone Owner"},
+			{AppAssetID: 40, OwnerExternalID: "sp-owner", OwnerEmail: "carol@example.com"},
+		},
+		50: {
+			{AppAssetID: 50, OwnerExternalID: "gone-owner", OwnerDisplayName: "Gone Owner"},
+			{AppAssetID: 50, OwnerEmail: "former@example.com"},
+		},
+	}
+	resolver := stubOwnerResolver{
+		"alice":             "/identities/1",
+		"bob":               "/identities/2",
+		"carol@example.com": "/identities/3",
+	}
+
+	report := credentialExpiringUnownedReportRows(
+		[]gen.ListCredentialArtifactsExpiringWithinRow{orphan, createdByKnown, approvedByKnown, assetOwned, assetOrphan},
+		owners, resolver, now,
+	)
+
+	ids := make([]int64, 0, len(report))
+	for _, row := range report {
+		ids = append(ids, row.ID)
+	}
+	if !slices.Equal(ids, []int64{1, 5}) {
+		t.Fatalf("report ids = %v, want the unowned credentials [1 5] in expiry order", ids)
+	}
+	if report[0].CreatedBy != "Departed User" || report[0].DaysUntilExpiry != 2 {
+		t.Fatalf("orphan row = %+v, want creator Departed User expiring in 2 days", report[0])
+	}
+	if report[1].AssetOwners != "Gone Owner; former@example.com" || report[1].DaysUntilExpiry != 9 {
+		t.Fatalf("asset orphan row = %+v, want both unresolved owners listed", report[1])
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/views"
+)
+
+// credentialExpiryCalendarDefaultDays is the forecast horizon when ?days is not set.
+const credentialExpiryCalendarDefaultDays = 90
+
+// HandleCredentialExpiryCalendar serves an iCalendar feed with one event per credential expiring
+// within ?days (90 by default), so rotation deadlines can be subscribed to from a calendar.
+// ?source_kind, ?source_name, and ?risk_level narrow the feed like they do the credentials page.
+func (h *Handlers) HandleCredentialExpiryCalendar(c *echo.Context) error {
+	days := min(max(parseIntParamDefault(c.QueryParam("days"), credentialExpiryCalendarDefaultDays), 1), 3650)
+	riskLevel := normalizeCredentialRiskFilter(c.QueryParam("risk_level"))
+
+	states, err := h.loadScopedConnectorStates(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	sources := availableProgrammaticSources(connectorSnapshotFromStates(states))
+	var rows []gen.CredentialArtifact
+	if selected, ok := selectProgrammaticSource(c, sources); ok {
+		policy := h.credentialRiskPolicy()
+		sqlRiskLevel := riskLevel
+		if policy.customized() {
+			sqlRiskLevel = ""
+		}
+		rows, _, err = h.listCredentialsAcrossSources(c.Request().Context(), effectiveProgrammaticSources(selected, sources), "", "", sqlRiskLevel, "", "", days, 0, false, false, identityAccountFilter{}, credentialTagFilter{}, "", h.multiSourceRowLimit())
+		if err != nil {
+			return h.RenderError(c, err)
+		}
+		if sqlRiskLevel != riskLevel {
+			rows = filterCredentialsByRiskLevel(rows, riskLevel, time.Now(), policy)
+		}
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Response().Header().Set("Content-Disposition", `attachment; filename="credential-expiry.ics"`)
+	c.Response().WriteHeader(http.StatusOK)
+	return writeCredentialExpiryCalendar(c.Response(), rows, c.Scheme()+"://"+c.Request().Host, time.Now(), days)
+}
+
+// writeCredentialExpiryCalendar writes rows expiring within days of now as VEVENTs, soonest
+// first, each linking to its credential page under baseURL. An event's UID is stable per
+// credential, so a re-imported feed moves the event when the expiry changes.
+func writeCredentialExpiryCalendar(w io.Writer, rows []gen.CredentialArtifact, baseURL string, now time.Time, days int) error {
+	now = now.UTC()
+	horizon := now.AddDate(0, 0, days)
+	upcoming := make([]gen.CredentialArtifact, 0, len(rows))
+	for _, row := range rows {
+		if !row.ExpiresAtSource.Valid {
+			continue
+		}
+		expiresAt := row.ExpiresAtSource.Time
+		if expiresAt.Before(now) || expiresAt.After(horizon) {
+			continue
+		}
+		upcoming = append(upcoming, row)
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].ExpiresAtSource.Time.Before(upcoming[j].ExpiresAtSource.Time)
+	})
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Open-SSPM//Credential expiry//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Credential expirations",
+	}
+	stamp := now.Format(icsTimestampLayout)
+	baseURL = strings.TrimRight(baseURL, "/")
+	for _, row := range upcoming {
+		name := firstNonEmpty(row.DisplayName, row.ExternalID)
+		href := baseURL + "/credentials/" + strconv.FormatInt(row.ID, 10)
+		description := fmt.Sprintf("%s from %s expires. Rotate it before then.\n%s",
+			views.HumanizeCredentialKind(row.CredentialKind), sourceDiagnosticLabel(row.SourceKind, row.SourceName), href)
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:credential-"+strconv.FormatInt(row.ID, 10)+"@open-sspm",
+			"DTSTAMP:"+stamp,
+			"DTSTART:"+row.ExpiresAtSource.Time.UTC().Format(icsTimestampLayout),
+			"SUMMARY:"+icsEscapeText("Credential expires: "+name),
+			"DESCRIPTION:"+icsEscapeText(description),
+			"URL:"+href,
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, icsFoldLine(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const icsTimestampLayout = "20060102T150405Z"
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// icsEscapeText escapes a TEXT property value (RFC 5545 section 3.3.11).
+func icsEscapeText(s string) string {
+	return icsTextEscaper.Replace(strings.TrimSpace(s))
+}
+
+// icsFoldLine splits a content line into 75-octet lines joined by CRLF and a space, without
+// splitting a UTF-8 sequence (RFC 5545 section 3.1).
+func icsFoldLine(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+	var b strings.Builder
+	width := limit
+	for len(line) > width {
+		cut := width
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward their 75 octets.
+		width = limit - 1
+	}
+	b.WriteString(line)
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestWriteCredentialExpiryCalendarListsCredentialsWithinHorizon(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	credential := func(id int64, name string, expiresInDays int) gen.CredentialArtifact {
+		return gen.CredentialArtifact{
+			ID:              id,
+			SourceKind:      "entra",
+			SourceName:      "tenant",
+			CredentialKind:  "entra_client_secret",
+			DisplayName:     name,
+			ExpiresAtSource: pgtype.Timestamptz{Time: now.AddDate(0, 0, expiresInDays), Valid: true},
+		}
+	}
+	noExpiry := credential(4, "never expires", 0)
+	noExpiry.ExpiresAtSource = pgtype.Timestamptz{}
+
+	var b strings.Builder
+	err := writeCredentialExpiryCalendar(&b, []gen.CredentialArtifact{
+		credential(1, "payroll, prod", 20),
+		credential(2, "ci deploy", 5),
+		credential(3, "beyond horizon", 45),
+		noExpiry,
+		credential(5, "already expired", -1),
+	}, "https://sspm.example.com/", now, 30)
+	if err != nil {
+		t.Fatalf("writeCredentialExpiryCalendar() error = %v", err)
+	}
+	ics := b.String()
+
+	if !strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(ics, "END:VCALENDAR\r\n") {
+		t.Fatalf("calendar = %q, want a CRLF-delimited VCALENDAR", ics)
+	}
+	if got := strings.Count(ics, "BEGIN:VEVENT"); got != 2 {
+		t.Fatalf("VEVENT count = %d, want 2 (credentials within the horizon)", got)
+	}
+	for _, want := range []string{
+		"UID:credential-2@open-sspm\r\nDTSTAMP:20260601T120000Z\r\nDTSTART:20260606T120000Z\r\nSUMMARY:Credential expires: ci deploy\r\n",
+		"UID:credential-1@open-sspm\r\nDTSTAMP:20260601T120000Z\r\nDTSTART:20260621T120000Z\r\nSUMMARY:Credential expires: payroll\\, prod\r\n",
+		"URL:https://sspm.example.com/credentials/1\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Fatalf("calendar missing %q:\n%s", want, ics)
+		}
+	}
+	if strings.Index(ics, "credential-2@") > strings.Index(ics, "credential-1@") {
+		t.Fatalf("calendar events are not soonest first:\n%s", ics)
+	}
+	for _, excluded := range []string{"beyond horizon", "never expires", "already expired"} {
+		if strings.Contains(ics, excluded) {
+			t.Fatalf("calendar includes %q, want it outside the horizon:\n%s", excluded, ics)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("line %q is %d octets, want content lines folded at 75", line, len(line))
+		}
+	}
+}
+
+func TestICSFoldLineKeepsUTF8Sequences(t *testing.T) {
+	t.Parallel()
+
+	line := "SUMMARY:" + strings.Repeat("é", 60)
+	folded := icsFoldLine(line)
+	if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != line {
+		t.Fatalf("unfolded = %q, want %q", unfolded, line)
+	}
+	for _, part := range strings.Split(folded, "\r\n") {
+		if len(part) > 75 {
+			t.Fatalf("folded part %q is %d octets, want at most 75", part, len(part))
+		}
+		if !utf8.ValidString(part) {
+			t.Fatalf("folded part %q splits a UTF-8 sequence", part)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/http/views"
+)
+
+// credentialKindsAPIResponse is the JSON body returned by HandleAPICredentialKinds.
+type credentialKindsAPIResponse struct {
+	Items []registry.CredentialKindInfo `json:"items"`
+}
+
+type credentialKindAPIError struct {
+	Message string `json:"message"`
+}
+
+// HandleAPICredentialKinds serves the credential kind glossary registered by the connectors.
+func (h *Handlers) HandleAPICredentialKinds(c *echo.Context) error {
+	resp := credentialKindsAPIResponse{Items: []registry.CredentialKindInfo{}}
+	if h.Registry != nil {
+		resp.Items = append(resp.Items, h.Registry.CredentialKindGlossary()...)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// HandleAPICredentialKindShow serves the glossary entry of one credential kind.
+func (h *Handlers) HandleAPICredentialKindShow(c *echo.Context) error {
+	kind := strings.TrimSpace(c.Param("kind"))
+	if h.Registry != nil {
+		if info, ok := h.Registry.CredentialKindInfo(kind); ok {
+			return c.JSON(http.StatusOK, info)
+		}
+	}
+	return c.JSON(http.StatusNotFound, credentialKindAPIError{Message: "Unknown credential kind."})
+}
+
+// credentialKindGlossary indexes the registered glossary by kind. It is nil without a registry.
+func (h *Handlers) credentialKindGlossary() map[string]registry.CredentialKindInfo {
+	if h.Registry == nil {
+		return nil
+	}
+	entries := h.Registry.CredentialKindGlossary()
+	out := make(map[string]registry.CredentialKindInfo, len(entries))
+	for _, info := range entries {
+		out[info.Kind] = info
+	}
+	return out
+}
+
+// lookupCredentialKind returns the glossary entry for kind. Kinds without an entry get the
+// humanized kind as their label and no description.
+func lookupCredentialKind(glossary map[string]registry.CredentialKindInfo, kind string) registry.CredentialKindInfo {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	if info, ok := glossary[kind]; ok {
+		return info
+	}
+	return registry.CredentialKindInfo{Kind: kind, Label: views.HumanizeCredentialKind(kind)}
+}
+
+// credentialKindTooltip renders a glossary entry as a title attribute: the raw kind, what it
+// is, and how to rotate it.
+func credentialKindTooltip(info registry.CredentialKindInfo) string {
+	parts := []string{info.Kind}
+	if info.Description != "" {
+		parts = append(parts, info.Description)
+	}
+	if info.RotationGuidance != "" {
+		parts = append(parts, "Rotation: "+info.RotationGuidance)
+	}
+	return strings.Join(parts, "\n\n")
+}
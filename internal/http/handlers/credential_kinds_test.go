@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	connregistry "github.com/open-sspm/open-sspm/internal/connectors/registry"
+)
+
+type fakeCredentialKindDefinition struct {
+	fakeStatsDefinition
+	kinds []connregistry.CredentialKindInfo
+}
+
+func (f fakeCredentialKindDefinition) CredentialKinds() []connregistry.CredentialKindInfo {
+	return f.kinds
+}
+
+func newCredentialKindTestHandlers(t *testing.T) *Handlers {
+	t.Helper()
+
+	reg := connregistry.NewRegistry()
+	if err := reg.Register(fakeCredentialKindDefinition{
+		fakeStatsDefinition: fakeStatsDefinition{kind: "github"},
+		kinds: []connregistry.CredentialKindInfo{
+			{Kind: "github_pat_fine_grained", Label: "GitHub fine-grained PAT", Description: "A token.", RotationGuidance: "Regenerate it."},
+			{Kind: "github_deploy_key", Label: "GitHub deploy key", Description: "An SSH key.", RotationGuidance: "Replace it."},
+		},
+	}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := reg.Register(fakeStatsDefinition{kind: "aws"}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	return &Handlers{Registry: reg}
+}
+
+func TestHandleAPICredentialKinds(t *testing.T) {
+	t.Parallel()
+
+	h := newCredentialKindTestHandlers(t)
+	c, rec := newTestContext(http.MethodGet, "/api/credential-kinds")
+	if err := h.HandleAPICredentialKinds(c); err != nil {
+		t.Fatalf("HandleAPICredentialKinds() error = %v", err)
+	}
+	var resp credentialKindsAPIResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Items) != 2 || resp.Items[0].Kind != "github_deploy_key" || resp.Items[1].Kind != "github_pat_fine_grained" {
+		t.Fatalf("items = %+v, want both github kinds sorted by kind", resp.Items)
+	}
+
+	c, rec = newTestContext(http.MethodGet, "/api/credential-kinds/github_deploy_key")
+	c.SetPathValues(echo.PathValues{{Name: "kind", Value: "GitHub_Deploy_Key"}})
+	if err := h.HandleAPICredentialKindShow(c); err != nil {
+		t.Fatalf("HandleAPICredentialKindShow() error = %v", err)
+	}
+	var info connregistry.CredentialKindInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil || info.RotationGuidance != "Replace it." {
+		t.Fatalf("entry = %+v, %v; want the deploy key entry", info, err)
+	}
+
+	c, rec = newTestContext(http.MethodGet, "/api/credential-kinds/nope")
+	c.SetPathValues(echo.PathValues{{Name: "kind", Value: "nope"}})
+	if err := h.HandleAPICredentialKindShow(c); err != nil {
+		t.Fatalf("HandleAPICredentialKindShow() error = %v", err)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404 for an unknown kind", rec.Code)
+	}
+}
+
+func TestLookupCredentialKindFallsBackToHumanizedKind(t *testing.T) {
+	t.Parallel()
+
+	glossary := newCredentialKindTestHandlers(t).credentialKindGlossary()
+	info := lookupCredentialKind(glossary, " github_deploy_key ")
+	if info.Label != "GitHub deploy key" || !strings.Contains(credentialKindTooltip(info), "Rotation: Replace it.") {
+		t.Fatalf("info = %+v, tooltip = %q", info, credentialKindTooltip(info))
+	}
+	unknown := lookupCredentialKind(glossary, "vendor_api_key")
+	if unknown.Label != "Vendor Api Key" {
+		t.Fatalf("unknown label = %q, want a humanized kind", unknown.Label)
+	}
+	if credentialKindTooltip(unknown) != "vendor_api_key" {
+		t.Fatalf("unknown tooltip = %q, want just the raw kind", credentialKindTooltip(unknown))
+	}
+}
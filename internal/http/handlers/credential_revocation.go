@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v5"
+	connregistry "github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/authn"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+)
+
+const (
+	// credentialRevokeConfirmation must be typed into the revoke form; it guards against
+	// revoking a live credential with a stray click.
+	credentialRevokeConfirmation = "revoke"
+	maxCredentialRevokeReasonLen = 500
+
+	credentialRevocationMethodAPI    = "api"
+	credentialRevocationMethodManual = "manual"
+	credentialRevocationStatusOpen   = "open"
+	credentialRevocationStatusDone   = "completed"
+)
+
+type credentialRevocationQueries interface {
+	CompleteOpenCredentialRevocationTasks(context.Context, gen.CompleteOpenCredentialRevocationTasksParams) (int64, error)
+	CreateCredentialRevocationTask(context.Context, gen.CreateCredentialRevocationTaskParams) (gen.CredentialRevocationTask, error)
+}
+
+// HandleCredentialRevoke revokes a credential through its connector when the connector
+// supports it, and otherwise records an open revocation task for manual follow-up.
+func (h *Handlers) HandleCredentialRevoke(c *echo.Context) error {
+	credential, err := h.loadCredentialForUpdate(c)
+	if err != nil || credential.ID == 0 {
+		return err
+	}
+	if strings.TrimSpace(c.FormValue("confirm")) != credentialRevokeConfirmation {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("type %q to confirm revocation", credentialRevokeConfirmation))
+	}
+	reason := strings.TrimSpace(c.FormValue("reason"))
+	if len(reason) > maxCredentialRevokeReasonLen {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("reason must be at most %d characters", maxCredentialRevokeReasonLen))
+	}
+
+	ctx := c.Request().Context()
+	integration, err := h.credentialIntegration(ctx, credential)
+	if err != nil {
+		// A connector that cannot be built still leaves the manual path available.
+		slog.Warn("credential revocation integration unavailable", "credential_id", credential.ID, "source_kind", credential.SourceKind, "err", err)
+		integration = nil
+	}
+
+	task, err := revokeCredential(ctx, h.Q, integration, credential, reason, principalUserID(c))
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
+	switch {
+	case task.Method == credentialRevocationMethodAPI:
+		setFlashToast(c, viewmodels.ToastViewData{
+			Category:    "success",
+			Title:       "Credential revoked",
+			Description: "The provider confirmed the revocation.",
+		})
+	case task.ErrorMessage != "":
+		setFlashToast(c, viewmodels.ToastViewData{
+			Category:    "warning",
+			Title:       "Revocation task created",
+			Description: "The provider rejected the revocation; follow up manually.",
+		})
+	default:
+		setFlashToast(c, viewmodels.ToastViewData{
+			Category:    "success",
+			Title:       "Revocation task created",
+			Description: "This connector cannot revoke credentials; follow up manually.",
+		})
+	}
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/credentials/%d", credential.ID))
+}
+
+// HandleCredentialRevocationComplete marks the open manual revocation task for a credential
+// as done.
+func (h *Handlers) HandleCredentialRevocationComplete(c *echo.Context) error {
+	credential, err := h.loadCredentialForUpdate(c)
+	if err != nil || credential.ID == 0 {
+		return err
+	}
+	identity := credentialTagIdentity(credential)
+	if _, err := h.Q.CompleteOpenCredentialRevocationTasks(c.Request().Context(), gen.CompleteOpenCredentialRevocationTasksParams{
+		ResolvedByAuthUserID: principalUserID(c),
+		SourceKind:           identity.SourceKind,
+		SourceName:           identity.SourceName,
+		CredentialKind:       identity.CredentialKind,
+		ExternalID:           identity.ExternalID,
+	}); err != nil {
+		return h.RenderError(c, err)
+	}
+	setFlashToast(c, viewmodels.ToastViewData{
+		Category:    "success",
+		Title:       "Revocation completed",
+		Description: "The revocation task was marked as done.",
+	})
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/credentials/%d", credential.ID))
+}
+
+// revokeCredential calls the integration's revoker when it has one. A successful call is
+// recorded as a completed API task and closes any open manual task; an unsupported
+// credential, an integration without the capability, or a provider error falls back to an
+// open manual task carrying the error.
+func revokeCredential(ctx context.Context, q credentialRevocationQueries, integration connregistry.Integration, credential gen.CredentialArtifact, reason string, requestedBy pgtype.Int8) (gen.CredentialRevocationTask, error) {
+	identity := credentialTagIdentity(credential)
+	params := gen.CreateCredentialRevocationTaskParams{
+		SourceKind:            identity.SourceKind,
+		SourceName:            identity.SourceName,
+		CredentialKind:        identity.CredentialKind,
+		ExternalID:            identity.ExternalID,
+		Method:                credentialRevocationMethodManual,
+		Status:                credentialRevocationStatusOpen,
+		Reason:                reason,
+		RequestedByAuthUserID: requestedBy,
+	}
+
+	if revoker, ok := integration.(connregistry.CredentialRevoker); ok {
+		err := revoker.Revoke(ctx, credential)
+		switch {
+		case err == nil:
+			if _, err := q.CompleteOpenCredentialRevocationTasks(ctx, gen.CompleteOpenCredentialRevocationTasksParams{
+				ResolvedByAuthUserID: requestedBy,
+				SourceKind:           identity.SourceKind,
+				SourceName:           identity.SourceName,
+				CredentialKind:       identity.CredentialKind,
+				ExternalID:           identity.ExternalID,
+			}); err != nil {
+				return gen.CredentialRevocationTask{}, err
+			}
+			params.Method = credentialRevocationMethodAPI
+			params.Status = credentialRevocationStatusDone
+		case errors.Is(err, connregistry.ErrRevocationUnsupported):
+		default:
+			params.ErrorMessage = err.Error()
+		}
+	}
+
+	return q.CreateCredentialRevocationTask(ctx, params)
+}
+
+// credentialIntegration builds the integration for the connector that ingested credential. It
+// returns nil when that connector is no longer configured or enabled.
+func (h *Handlers) credentialIntegration(ctx context.Context, credential gen.CredentialArtifact) (connregistry.Integration, error) {
+	if h.Registry == nil {
+		return nil, nil
+	}
+	states, err := h.Registry.LoadStates(ctx, h.Q)
+	if err != nil {
+		return nil, err
+	}
+	sourceKind := NormalizeConnectorKind(credential.SourceKind)
+	sourceName := strings.TrimSpace(credential.SourceName)
+	for _, state := range states {
+		if NormalizeConnectorKind(state.Definition.Kind()) != sourceKind || !strings.EqualFold(strings.TrimSpace(state.SourceName), sourceName) {
+			continue
+		}
+		if !state.Configured || !state.Enabled {
+			return nil, nil
+		}
+		cfg, err := h.Registry.ResolveConfig(ctx, state.Config)
+		if err != nil {
+			return nil, err
+		}
+		return state.Definition.NewIntegration(cfg)
+	}
+	return nil, nil
+}
+
+func principalUserID(c *echo.Context) pgtype.Int8 {
+	principal, ok := authn.PrincipalFromContext(c)
+	if !ok || principal.UserID <= 0 {
+		return pgtype.Int8{}
+	}
+	return pgtype.Int8{Int64: principal.UserID, Valid: true}
+}
+
+func credentialRevocationTaskItems(tasks []gen.ListCredentialRevocationTasksForCredentialRow, loc *time.Location) []viewmodels.CredentialRevocationTaskItem {
+	items := make([]viewmodels.CredentialRevocationTaskItem, 0, len(tasks))
+	for _, task := range tasks {
+		items = append(items, viewmodels.CredentialRevocationTaskItem{
+			Method:       task.Method,
+			Status:       task.Status,
+			Reason:       fallbackDash(task.Reason),
+			ErrorMessage: task.ErrorMessage,
+			RequestedBy:  fallbackDash(task.RequestedByEmail),
+			CreatedAt:    formatProgrammaticDate(task.CreatedAt, loc),
+			ResolvedAt:   formatProgrammaticDate(task.ResolvedAt, loc),
+		})
+	}
+	return items
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	connregistry "github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+type fakeRevokerIntegration struct {
+	fakeSyncTriggerIntegration
+	err     error
+	revoked []string
+}
+
+func (f *fakeRevokerIntegration) Revoke(_ context.Context, credential gen.CredentialArtifact) error {
+	f.revoked = append(f.revoked, credential.ExternalID)
+	return f.err
+}
+
+type fakeCredentialRevocationQueries struct {
+	created   []gen.CreateCredentialRevocationTaskParams
+	completed int
+}
+
+func (f *fakeCredentialRevocationQueries) CompleteOpenCredentialRevocationTasks(context.Context, gen.CompleteOpenCredentialRevocationTasksParams) (int64, error) {
+	f.completed++
+	return 1, nil
+}
+
+func (f *fakeCredentialRevocationQueries) CreateCredentialRevocationTask(_ context.Context, arg gen.CreateCredentialRevocationTaskParams) (gen.CredentialRevocationTask, error) {
+	f.created = append(f.created, arg)
+	return gen.CredentialRevocationTask{Method: arg.Method, Status: arg.Status, ErrorMessage: arg.ErrorMessage}, nil
+}
+
+var testRevocationCredential = gen.CredentialArtifact{
+	ID:             7,
+	SourceKind:     "github",
+	SourceName:     "acme",
+	CredentialKind: "github_deploy_key",
+	ExternalID:     "42",
+}
+
+func TestRevokeCredentialCallsProviderWhenSupported(t *testing.T) {
+	t.Parallel()
+
+	q := &fakeCredentialRevocationQueries{}
+	integration := &fakeRevokerIntegration{fakeSyncTriggerIntegration: fakeSyncTriggerIntegration{kind: "github", name: "acme"}}
+	requestedBy := pgtype.Int8{Int64: 3, Valid: true}
+
+	task, err := revokeCredential(context.Background(), q, integration, testRevocationCredential, "leaked", requestedBy)
+	if err != nil {
+		t.Fatalf("revokeCredential() error = %v", err)
+	}
+	if len(integration.revoked) != 1 || integration.revoked[0] != "42" {
+		t.Fatalf("revoked = %v, want provider call for 42", integration.revoked)
+	}
+	if task.Method != credentialRevocationMethodAPI || task.Status != credentialRevocationStatusDone {
+		t.Fatalf("task = %+v, want completed api task", task)
+	}
+	if q.completed != 1 {
+		t.Fatalf("open tasks completed %d times, want 1", q.completed)
+	}
+	if got := q.created[0]; got.Reason != "leaked" || got.RequestedByAuthUserID != requestedBy || got.ExternalID != "42" {
+		t.Fatalf("created task params = %+v", got)
+	}
+}
+
+func TestRevokeCredentialFallsBackToManualTask(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		integration connregistry.Integration
+		wantError   bool
+	}{
+		{name: "no integration"},
+		{name: "no capability", integration: fakeSyncTriggerIntegration{kind: "okta", name: "acme"}},
+		{name: "unsupported credential", integration: &fakeRevokerIntegration{err: connregistry.ErrRevocationUnsupported}},
+		{name: "provider error", integration: &fakeRevokerIntegration{err: errors.New("github api 403")}, wantError: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := &fakeCredentialRevocationQueries{}
+			task, err := revokeCredential(context.Background(), q, tc.integration, testRevocationCredential, "", pgtype.Int8{})
+			if err != nil {
+				t.Fatalf("revokeCredential() error = %v", err)
+			}
+			if task.Method != credentialRevocationMethodManual || task.Status != credentialRevocationStatusOpen {
+				t.Fatalf("task = %+v, want open manual task", task)
+			}
+			if q.completed != 0 {
+				t.Fatalf("open tasks completed on fallback")
+			}
+			if (task.ErrorMessage != "") != tc.wantError {
+				t.Fatalf("error message = %q, want error %v", task.ErrorMessage, tc.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/open-sspm/open-sspm/internal/config"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// credentialRiskPolicy is the configurable part of credential risk scoring: which kinds are
+// high privilege, which statuses still grant access, and per-kind overrides applied to the
+// heuristic level.
+type credentialRiskPolicy struct {
+	HighPrivilegeKinds []string
+	ActiveStatuses     []string
+	Overrides          []config.CredentialRiskOverride
+}
+
+func (h *Handlers) credentialRiskPolicy() credentialRiskPolicy {
+	return credentialRiskPolicy{
+		HighPrivilegeKinds: h.Cfg.CredentialHighPrivilegeKinds,
+		ActiveStatuses:     h.Cfg.CredentialActiveStatuses,
+		Overrides:          h.Cfg.CredentialRiskOverrides,
+	}
+}
+
+// customized reports whether the policy differs from the built-in one. The risk filter and sort
+// in the credential list queries only know the built-in policy.
+func (p credentialRiskPolicy) customized() bool {
+	return config.Config{
+		CredentialHighPrivilegeKinds: p.HighPrivilegeKinds,
+		CredentialActiveStatuses:     p.ActiveStatuses,
+		CredentialRiskOverrides:      p.Overrides,
+	}.CredentialRiskPolicyCustomized()
+}
+
+func (p credentialRiskPolicy) isHighPrivilegeKind(kind string) bool {
+	return slices.Contains(p.HighPrivilegeKinds, strings.ToLower(strings.TrimSpace(kind)))
+}
+
+func (p credentialRiskPolicy) isActiveStatus(status string) bool {
+	return isCredentialStatusActiveLike(status, p.ActiveStatuses)
+}
+
+// applyOverrides runs the matching overrides over level in order and returns the final level
+// along with the overrides that changed it.
+func (p credentialRiskPolicy) applyOverrides(credential gen.CredentialArtifact, level string) (string, []config.CredentialRiskOverride) {
+	var applied []config.CredentialRiskOverride
+	for _, override := range p.Overrides {
+		if !credentialRiskOverrideMatches(override, credential) {
+			continue
+		}
+		next := level
+		switch override.Op {
+		case config.CredentialRiskCap:
+			if credentialRiskRank(level) > credentialRiskRank(override.Level) {
+				next = override.Level
+			}
+		case config.CredentialRiskFloor:
+			if credentialRiskRank(level) < credentialRiskRank(override.Level) {
+				next = override.Level
+			}
+		default:
+			next = override.Level
+		}
+		if next != level {
+			level = next
+			applied = append(applied, override)
+		}
+	}
+	return level, applied
+}
+
+func credentialRiskOverrideMatches(override config.CredentialRiskOverride, credential gen.CredentialArtifact) bool {
+	if override.Kind != strings.ToLower(strings.TrimSpace(credential.CredentialKind)) {
+		return false
+	}
+	if override.Scope == "" {
+		return true
+	}
+	var scope any
+	if err := json.Unmarshal(credential.ScopeJson, &scope); err != nil {
+		return false
+	}
+	return credentialScopeContains(scope, override.Scope)
+}
+
+// credentialScopeContains reports whether a decoded scope lists want: as an array entry or
+// string value anywhere in it, or as an object key set to true.
+func credentialScopeContains(scope any, want string) bool {
+	switch v := scope.(type) {
+	case string:
+		return strings.EqualFold(strings.TrimSpace(v), want)
+	case []any:
+		return slices.ContainsFunc(v, func(item any) bool { return credentialScopeContains(item, want) })
+	case map[string]any:
+		for key, value := range v {
+			if flag, ok := value.(bool); ok && flag && strings.EqualFold(key, want) {
+				return true
+			}
+			if credentialScopeContains(value, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func credentialRiskOverrideReason(override config.CredentialRiskOverride) string {
+	target := override.Kind
+	if override.Scope != "" {
+		target += " (" + override.Scope + ")"
+	}
+	switch override.Op {
+	case config.CredentialRiskCap:
+		return fmt.Sprintf("Risk policy caps %s credentials at %s.", target, override.Level)
+	case config.CredentialRiskFloor:
+		return fmt.Sprintf("Risk policy raises %s credentials to at least %s.", target, override.Level)
+	default:
+		return fmt.Sprintf("Risk policy sets %s credentials to %s.", target, override.Level)
+	}
+}
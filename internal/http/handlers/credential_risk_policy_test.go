@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/config"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// builtinCredentialRiskPolicy is the policy of a deployment that configures no overrides.
+func builtinCredentialRiskPolicy() credentialRiskPolicy {
+	return credentialRiskPolicy{
+		HighPrivilegeKinds: config.DefaultCredentialHighPrivilegeKinds(),
+		ActiveStatuses:     config.DefaultCredentialActiveStatuses(),
+	}
+}
+
+func TestCredentialRiskPolicyOverrides(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	readOnlyDeployKey := gen.CredentialArtifact{
+		Status:         "active",
+		CredentialKind: "github_deploy_key",
+		ScopeJson:      []byte(`{"repository":"acme/api","read_only":true,"verified":true}`),
+	}
+	writeDeployKey := readOnlyDeployKey
+	writeDeployKey.ScopeJson = []byte(`{"repository":"acme/api","read_only":false,"verified":true}`)
+	mailGrant := gen.CredentialArtifact{
+		Status:              "active",
+		CredentialKind:      "google_oauth_grant",
+		CreatedByExternalID: "alice@example.com",
+		LastUsedAtSource:    timestamptz(now.Add(-24 * time.Hour)),
+		ScopeJson:           []byte(`["https://mail.google.com/","openid"]`),
+	}
+	profileGrant := mailGrant
+	profileGrant.ScopeJson = []byte(`["openid","email"]`)
+
+	builtin := builtinCredentialRiskPolicy()
+	for _, tc := range []struct {
+		credential gen.CredentialArtifact
+		want       string
+	}{
+		{readOnlyDeployKey, "critical"},
+		{mailGrant, "low"},
+	} {
+		if got := credentialRiskLevel(tc.credential, now, builtin); got != tc.want {
+			t.Fatalf("built-in credentialRiskLevel(%s) = %q, want %q", tc.credential.CredentialKind, got, tc.want)
+		}
+	}
+
+	policy := builtinCredentialRiskPolicy()
+	policy.Overrides = []config.CredentialRiskOverride{
+		{Kind: "github_deploy_key", Scope: "read_only", Op: config.CredentialRiskCap, Level: "medium"},
+		{Kind: "google_oauth_grant", Scope: "https://mail.google.com/", Op: config.CredentialRiskFloor, Level: "critical"},
+	}
+	for _, tc := range []struct {
+		name       string
+		credential gen.CredentialArtifact
+		want       string
+	}{
+		{name: "read-only deploy key capped", credential: readOnlyDeployKey, want: "medium"},
+		{name: "writable deploy key untouched", credential: writeDeployKey, want: "critical"},
+		{name: "full mail grant raised", credential: mailGrant, want: "critical"},
+		{name: "profile grant untouched", credential: profileGrant, want: "low"},
+	} {
+		if got := credentialRiskLevel(tc.credential, now, policy); got != tc.want {
+			t.Fatalf("%s: credentialRiskLevel() = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+	if reasons := credentialRiskReasons(mailGrant, now, nil, nil, policy); !slices.Contains(reasons, "Risk policy raises google_oauth_grant (https://mail.google.com/) credentials to at least critical.") {
+		t.Fatalf("expected override reason, got %v", reasons)
+	}
+
+	// Dropping a kind from the high-privilege list stops the unattributed critical rating.
+	policy.HighPrivilegeKinds = []string{"entra_client_secret"}
+	if got := credentialRiskLevel(writeDeployKey, now, policy); got != "high" {
+		t.Fatalf("credentialRiskLevel(non-high-privilege deploy key) = %q, want high", got)
+	}
+	if !policy.customized() || builtin.customized() {
+		t.Fatalf("customized() = %v / %v, want true for the configured policy only", policy.customized(), builtin.customized())
+	}
+}
+
+func TestCredentialRiskPolicyActiveStatuses(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	expired := gen.CredentialArtifact{
+		Status:              "Enabled",
+		CredentialKind:      "snowflake_key_pair",
+		CreatedByExternalID: "alice@example.com",
+		ExpiresAtSource:     timestamptz(now.Add(-24 * time.Hour)),
+	}
+
+	builtin := builtinCredentialRiskPolicy()
+	if got := credentialRiskLevel(expired, now, builtin); got != "high" {
+		t.Fatalf("built-in credentialRiskLevel(expired enabled) = %q, want high", got)
+	}
+
+	policy := builtinCredentialRiskPolicy()
+	policy.ActiveStatuses = append(policy.ActiveStatuses, "enabled")
+	if got := credentialRiskLevel(expired, now, policy); got != "critical" {
+		t.Fatalf("credentialRiskLevel(expired enabled) = %q, want critical", got)
+	}
+	if reasons := credentialRiskReasons(expired, now, nil, nil, policy); !slices.Contains(reasons, "Credential has expired while still marked active.") {
+		t.Fatalf("expected expired-while-active reason, got %v", reasons)
+	}
+	if !policy.customized() {
+		t.Fatalf("customized() = false, want true for extra active statuses")
+	}
+
+	// A blank status counts as "active" whatever else is configured.
+	if !isCredentialStatusActiveLike(" ", []string{"active"}) || isCredentialStatusActiveLike("", []string{"enabled"}) {
+		t.Fatalf("blank status should match only when active is configured")
+	}
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// credentialRotationSLADefaultKey is the policy entry applied to kinds without their own entry.
+const credentialRotationSLADefaultKey = "default"
+
+// credentialRotationReportRowLimit bounds the rotation SLA export.
+const credentialRotationReportRowLimit = 50000
+
+// credentialRotationSLADays returns the rotation SLA in days for a credential kind; zero means
+// the kind has no rotation requirement.
+func credentialRotationSLADays(policy map[string]int, credentialKind string) int {
+	if days, ok := policy[strings.ToLower(strings.TrimSpace(credentialKind))]; ok {
+		return max(days, 0)
+	}
+	return max(policy[credentialRotationSLADefaultKey], 0)
+}
+
+// credentialRotationSLAAge reports the credential's age in whole days and its SLA, and whether it
+// is past the SLA. It mirrors ListCredentialArtifactsPastRotationSLA: only active-like,
+// unexpired credentials with a known creation time can violate the policy.
+func credentialRotationSLAAge(credential gen.CredentialArtifact, now time.Time, policy map[string]int, activeStatuses []string) (ageDays, slaDays int, violated bool) {
+	if !credential.CreatedAtSource.Valid || !isCredentialStatusActiveLike(credential.Status, activeStatuses) {
+		return 0, 0, false
+	}
+	now = now.UTC()
+	if credential.ExpiresAtSource.Valid && credential.ExpiresAtSource.Time.UTC().Before(now) {
+		return 0, 0, false
+	}
+	slaDays = credentialRotationSLADays(policy, credential.CredentialKind)
+	ageDays = int(now.Sub(credential.CreatedAtSource.Time.UTC()) / (24 * time.Hour))
+	return ageDays, slaDays, slaDays > 0 && ageDays >= slaDays
+}
+
+// credentialRotationSLAParams flattens the policy into the query's parallel arrays; the default
+// entry is passed separately.
+func credentialRotationSLAParams(policy map[string]int, activeStatuses []string, rowLimit int32) gen.ListCredentialArtifactsPastRotationSLAParams {
+	params := gen.ListCredentialArtifactsPastRotationSLAParams{
+		PolicyKinds:    make([]string, 0, len(policy)),
+		PolicyDays:     make([]int32, 0, len(policy)),
+		ActiveStatuses: activeStatuses,
+		RowLimit:       rowLimit,
+	}
+	kinds := make([]string, 0, len(policy))
+	for kind := range policy {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		days := int32(max(policy[kind], 0))
+		if kind == credentialRotationSLADefaultKey {
+			params.DefaultSlaDays = days
+			continue
+		}
+		params.PolicyKinds = append(params.PolicyKinds, kind)
+		params.PolicyDays = append(params.PolicyDays, days)
+	}
+	return params
+}
+
+// credentialRotationReportRow is one credential past its rotation SLA. Field order matches the
+// CSV columns.
+type credentialRotationReportRow struct {
+	ID                int64  `json:"id"`
+	SourceKind        string `json:"source_kind"`
+	SourceName        string `json:"source_name"`
+	CredentialKind    string `json:"credential_kind"`
+	ExternalID        string `json:"external_id"`
+	DisplayName       string `json:"display_name"`
+	Status            string `json:"status"`
+	CreatedAtSource   string `json:"created_at_source"`
+	ExpiresAtSource   string `json:"expires_at_source"`
+	LastUsedAtSource  string `json:"last_used_at_source"`
+	AgeDays           int32  `json:"age_days"`
+	SLADays           int32  `json:"sla_days"`
+	DaysOverSLA       int32  `json:"days_over_sla"`
+	DaysSinceLastUsed string `json:"days_since_last_used"`
+}
+
+var credentialRotationReportColumns = []string{
+	"id",
+	"source_kind",
+	"source_name",
+	"credential_kind",
+	"external_id",
+	"display_name",
+	"status",
+	"created_at_source",
+	"expires_at_source",
+	"last_used_at_source",
+	"age_days",
+	"sla_days",
+	"days_over_sla",
+	"days_since_last_used",
+}
+
+func (r credentialRotationReportRow) csvRecord() []string {
+	return []string{
+		strconv.FormatInt(r.ID, 10),
+		r.SourceKind,
+		r.SourceName,
+		r.CredentialKind,
+		r.ExternalID,
+		r.DisplayName,
+		r.Status,
+		r.CreatedAtSource,
+		r.ExpiresAtSource,
+		r.LastUsedAtSource,
+		strconv.FormatInt(int64(r.AgeDays), 10),
+		strconv.FormatInt(int64(r.SLADays), 10),
+		strconv.FormatInt(int64(r.DaysOverSLA), 10),
+		r.DaysSinceLastUsed,
+	}
+}
+
+// HandleCredentialRotationReport exports credentials older than their kind's rotation SLA,
+// whether or not they expire. Use ?format=json for JSON; CSV is the default.
+func (h *Handlers) HandleCredentialRotationReport(c *echo.Context) error {
+	format := strings.ToLower(strings.TrimSpace(c.QueryParam("format")))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		return c.String(http.StatusBadRequest, "format must be csv or json")
+	}
+
+	ctx := c.Request().Context()
+	rows, err := h.Q.ListCredentialArtifactsPastRotationSLA(ctx, credentialRotationSLAParams(h.Cfg.CredentialRotationSLADays, h.Cfg.CredentialActiveStatuses, credentialRotationReportRowLimit))
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	report := credentialRotationReportRows(rows, scope, time.Now())
+
+	filename := "credential-rotation-sla-" + time.Now().UTC().Format("20060102") + "." + format
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if format == "json" {
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c.Response().WriteHeader(http.StatusOK)
+		return json.NewEncoder(c.Response()).Encode(report)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().WriteHeader(http.StatusOK)
+	w := csv.NewWriter(c.Response())
+	if err := w.Write(credentialRotationReportColumns); err != nil {
+		return err
+	}
+	for _, row := range report {
+		if err := w.Write(row.csvRecord()); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func credentialRotationReportRows(rows []gen.ListCredentialArtifactsPastRotationSLARow, scope orgScope, now time.Time) []credentialRotationReportRow {
+	out := make([]credentialRotationReportRow, 0, len(rows))
+	for _, row := range rows {
+		if !scope.AllowsSource(row.SourceKind, row.SourceName) {
+			continue
+		}
+		daysSinceLastUsed := ""
+		if row.LastUsedAtSource.Valid {
+			daysSinceLastUsed = strconv.FormatInt(int64(now.UTC().Sub(row.LastUsedAtSource.Time.UTC())/(24*time.Hour)), 10)
+		}
+		out = append(out, credentialRotationReportRow{
+			ID:                row.ID,
+			SourceKind:        strings.TrimSpace(row.SourceKind),
+			SourceName:        strings.TrimSpace(row.SourceName),
+			CredentialKind:    strings.TrimSpace(row.CredentialKind),
+			ExternalID:        strings.TrimSpace(row.ExternalID),
+			DisplayName:       strings.TrimSpace(row.DisplayName),
+			Status:            strings.TrimSpace(row.Status),
+			CreatedAtSource:   discoveryReportTimestamp(row.CreatedAtSource),
+			ExpiresAtSource:   discoveryReportTimestamp(row.ExpiresAtSource),
+			LastUsedAtSource:  discoveryReportTimestamp(row.LastUsedAtSource),
+			AgeDays:           row.AgeDays,
+			SLADays:           row.SlaDays,
+			DaysOverSLA:       row.AgeDays - row.SlaDays,
+			DaysSinceLastUsed: daysSinceLastUsed,
+		})
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// credentialRotationEventTokens mark an audit event type as replacing a credential's secret.
+// Connectors keep the provider's own event types (GitHub "repo.deploy_key.update", Entra
+// "Update application – Certificates and secrets management"), so types match on tokens.
+var credentialRotationEventTokens = []string{"rotat", "regenerat", "renew", "reset", "update"}
+
+// credentialRotationHistory is a credential's rotation history derived from its audit events.
+type credentialRotationHistory struct {
+	HasAuditHistory bool
+	RotationCount   int
+	LastRotatedAt   pgtype.Timestamptz
+}
+
+// isCredentialRotationEvent reports whether eventType records a secret being replaced rather
+// than created, read, or deleted.
+func isCredentialRotationEvent(eventType string) bool {
+	eventType = strings.ToLower(strings.TrimSpace(eventType))
+	for _, token := range credentialRotationEventTokens {
+		if strings.Contains(eventType, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// deriveCredentialRotationHistory counts the rotation events among a credential's audit events
+// and finds the latest. Only the events passed in are counted, so a credential with more audit
+// events than the credential page lists may report fewer rotations than it had.
+func deriveCredentialRotationHistory(events []gen.CredentialAuditEvent) credentialRotationHistory {
+	history := credentialRotationHistory{HasAuditHistory: len(events) > 0}
+	for _, event := range events {
+		if !isCredentialRotationEvent(event.EventType) {
+			continue
+		}
+		history.RotationCount++
+		if event.EventTime.Valid && (!history.LastRotatedAt.Valid || event.EventTime.Time.After(history.LastRotatedAt.Time)) {
+			history.LastRotatedAt = event.EventTime
+		}
+	}
+	return history
+}
+
+// credentialRotationLabels renders a rotation history as the last-rotated value and a summary
+// line. A credential with audit history but no rotation reads "Never"; one without audit
+// history cannot be judged and reads "—".
+func credentialRotationLabels(history credentialRotationHistory, loc *time.Location) (lastRotatedAt, summary string) {
+	switch {
+	case !history.HasAuditHistory:
+		return "—", "No audit history"
+	case history.RotationCount == 0:
+		return "Never", "Not rotated since creation"
+	}
+	lastRotatedAt = formatProgrammaticDate(history.LastRotatedAt, loc)
+	if history.RotationCount == 1 {
+		return lastRotatedAt, "1 rotation"
+	}
+	return lastRotatedAt, strconv.Itoa(history.RotationCount) + " rotations"
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestDeriveCredentialRotationHistoryCountsUpdates(t *testing.T) {
+	t.Parallel()
+
+	day := func(d int) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: time.Date(2026, time.October, d, 9, 0, 0, 0, time.UTC), Valid: true}
+	}
+	// Newest first, as ListCredentialAuditEventsForCredential returns them.
+	events := []gen.CredentialAuditEvent{
+		{EventType: "repo.deploy_key.access", EventTime: day(14)},
+		{EventType: "Update application – Certificates and secrets management", EventTime: day(12)},
+		{EventType: "repo.deploy_key.update", EventTime: day(13)},
+		{EventType: "vault_approle_secret_id.rotated", EventTime: day(5)},
+		{EventType: "repo.deploy_key.create", EventTime: day(1)},
+	}
+
+	history := deriveCredentialRotationHistory(events)
+	if !history.HasAuditHistory || history.RotationCount != 3 {
+		t.Fatalf("history = %+v, want audit history with 3 rotations", history)
+	}
+	if !history.LastRotatedAt.Time.Equal(day(13).Time) {
+		t.Fatalf("LastRotatedAt = %s, want %s", history.LastRotatedAt.Time, day(13).Time)
+	}
+	if last, summary := credentialRotationLabels(history, time.UTC); last != formatProgrammaticDate(day(13), time.UTC) || summary != "3 rotations" {
+		t.Fatalf("labels = %q, %q", last, summary)
+	}
+}
+
+func TestDeriveCredentialRotationHistoryWithoutRotations(t *testing.T) {
+	t.Parallel()
+
+	created := []gen.CredentialAuditEvent{{EventType: "vault_token.created", EventTime: pgtype.Timestamptz{Time: time.Now(), Valid: true}}}
+	history := deriveCredentialRotationHistory(created)
+	if !history.HasAuditHistory || history.RotationCount != 0 || history.LastRotatedAt.Valid {
+		t.Fatalf("history = %+v, want audit history without rotations", history)
+	}
+	if last, summary := credentialRotationLabels(history, time.UTC); last != "Never" || summary != "Not rotated since creation" {
+		t.Fatalf("labels = %q, %q", last, summary)
+	}
+
+	history = deriveCredentialRotationHistory(nil)
+	if history.HasAuditHistory || history.RotationCount != 0 {
+		t.Fatalf("history = %+v, want no audit history", history)
+	}
+	if last, summary := credentialRotationLabels(history, time.UTC); last != "—" || summary != "No audit history" {
+		t.Fatalf("labels = %q, %q", last, summary)
+	}
+}
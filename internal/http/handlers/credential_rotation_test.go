@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/config"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestCredentialRotationSLAAge(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	policy := map[string]int{"default": 365, "entra_client_secret": 180}
+	activeStatuses := config.DefaultCredentialActiveStatuses()
+	credential := func(ageDays int) gen.CredentialArtifact {
+		return gen.CredentialArtifact{
+			CredentialKind:  "entra_client_secret",
+			Status:          "active",
+			CreatedAtSource: pgtype.Timestamptz{Time: now.AddDate(0, 0, -ageDays), Valid: true},
+		}
+	}
+
+	old := credential(200)
+	ageDays, slaDays, violated := credentialRotationSLAAge(old, now, policy, activeStatuses)
+	if !violated || ageDays != 200 || slaDays != 180 {
+		t.Fatalf("200-day-old secret: age=%d sla=%d violated=%v, want 200/180/true", ageDays, slaDays, violated)
+	}
+	reason := "Credential is 200 days old, past its 180-day rotation SLA."
+	if reasons := credentialRiskReasons(old, now, nil, policy, builtinCredentialRiskPolicy()); !slices.Contains(reasons, reason) {
+		t.Fatalf("expected rotation SLA reason, got %v", reasons)
+	}
+
+	if _, _, violated := credentialRotationSLAAge(credential(30), now, policy, activeStatuses); violated {
+		t.Fatalf("30-day-old secret violates the 180-day SLA")
+	}
+
+	pat := credential(200)
+	pat.CredentialKind = "github_pat_fine_grained"
+	if _, slaDays, violated := credentialRotationSLAAge(pat, now, policy, activeStatuses); violated || slaDays != 365 {
+		t.Fatalf("kind without its own entry: sla=%d violated=%v, want default 365 and no violation", slaDays, violated)
+	}
+
+	expired := credential(400)
+	expired.ExpiresAtSource = pgtype.Timestamptz{Time: now.AddDate(0, 0, -1), Valid: true}
+	if _, _, violated := credentialRotationSLAAge(expired, now, policy, activeStatuses); violated {
+		t.Fatalf("expired credential reported as a rotation violation")
+	}
+}
+
+func TestCredentialRotationSLAParams(t *testing.T) {
+	t.Parallel()
+
+	params := credentialRotationSLAParams(map[string]int{"default": 365, "vault_token": 0, "entra_client_secret": 180}, []string{"active"}, 10)
+	if params.DefaultSlaDays != 365 || params.RowLimit != 10 || !slices.Equal(params.ActiveStatuses, []string{"active"}) {
+		t.Fatalf("params = %+v", params)
+	}
+	if !slices.Equal(params.PolicyKinds, []string{"entra_client_secret", "vault_token"}) || !slices.Equal(params.PolicyDays, []int32{180, 0}) {
+		t.Fatalf("policy arrays = %v %v", params.PolicyKinds, params.PolicyDays)
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+type credentialScopeQueries interface {
+	GetCredentialArtifactByID(ctx context.Context, id int64) (gen.CredentialArtifact, error)
+}
+
+// HandleCredentialScopeJSON serves a credential's complete scope JSON. The detail page renders
+// only the first inlineJSONMaxBytes of it and links here for the rest.
+func (h *Handlers) HandleCredentialScopeJSON(c *echo.Context) error {
+	credentialID, err := parsePositiveInt64Param(c.Param("id"))
+	if err != nil {
+		return RenderNotFound(c)
+	}
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	document, err := credentialScopeDocument(c.Request().Context(), h.Q, scope, credentialID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return RenderNotFound(c)
+		}
+		return h.RenderError(c, err)
+	}
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, document)
+}
+
+// credentialScopeDocument returns the credential's scope JSON, indented when it parses. A
+// credential outside the scope is reported as pgx.ErrNoRows, like one that does not exist.
+func credentialScopeDocument(ctx context.Context, q credentialScopeQueries, scope orgScope, credentialID int64) ([]byte, error) {
+	credential, err := q.GetCredentialArtifactByID(ctx, credentialID)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.AllowsSource(credential.SourceKind, credential.SourceName) {
+		return nil, pgx.ErrNoRows
+	}
+	if len(credential.ScopeJson) == 0 {
+		return []byte("{}"), nil
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, credential.ScopeJson, "", "  "); err != nil {
+		return credential.ScopeJson, nil
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// largeCredentialScope is a scope document whose pretty-printed form is well past
+// inlineJSONMaxBytes.
+func largeCredentialScope(t *testing.T) []byte {
+	t.Helper()
+	permissions := map[string]string{}
+	for i := 0; i < 4000; i++ {
+		permissions[fmt.Sprintf("permission_%04d", i)] = "write"
+	}
+	raw, err := json.Marshal(map[string]any{"permissions": permissions})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	return raw
+}
+
+func TestTruncatePrettyJSONCutsLargeDocuments(t *testing.T) {
+	t.Parallel()
+
+	pretty := prettyProgrammaticJSON(largeCredentialScope(t))
+	got, truncated := truncatePrettyJSON(pretty, inlineJSONMaxBytes)
+	if !truncated {
+		t.Fatalf("truncated = false for a %d byte document", len(pretty))
+	}
+	if len(got) > inlineJSONMaxBytes || !strings.HasPrefix(pretty, got) {
+		t.Fatalf("len(got) = %d, want a prefix of at most %d bytes", len(got), inlineJSONMaxBytes)
+	}
+	if next := pretty[len(got)]; next != '\n' {
+		t.Fatalf("cut before %q, want a line boundary", next)
+	}
+
+	small := prettyProgrammaticJSON([]byte(`{"repo":"acme/site","read_only":true}`))
+	if got, truncated := truncatePrettyJSON(small, inlineJSONMaxBytes); truncated || got != small {
+		t.Fatalf("small document = %q (truncated %v), want it unchanged", got, truncated)
+	}
+}
+
+func TestCredentialScopeDocumentReturnsCompleteDocument(t *testing.T) {
+	t.Parallel()
+
+	raw := largeCredentialScope(t)
+	q := &fakeCredentialBulkQueries{credentials: map[int64]gen.CredentialArtifact{
+		1: {ID: 1, SourceKind: "github", SourceName: "acme", ScopeJson: raw},
+		3: {ID: 3, SourceKind: "github", SourceName: "other-org", ScopeJson: raw},
+	}}
+	scope := testCredentialBulkScope()
+
+	document, err := credentialScopeDocument(context.Background(), q, scope, 1)
+	if err != nil {
+		t.Fatalf("credentialScopeDocument() error = %v", err)
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, document); err != nil {
+		t.Fatalf("json.Compact() error = %v", err)
+	}
+	if !bytes.Equal(compact.Bytes(), raw) {
+		t.Fatalf("document differs from the stored scope (%d vs %d bytes)", compact.Len(), len(raw))
+	}
+
+	if _, err := credentialScopeDocument(context.Background(), q, scope, 3); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("other org's credential error = %v, want pgx.ErrNoRows", err)
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+	"github.com/open-sspm/open-sspm/internal/http/views"
+)
+
+const (
+	maxCredentialTagKeyLen   = 64
+	maxCredentialTagValueLen = 256
+)
+
+var credentialTagKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._:/-]*$`)
+
+// credentialTagFilter narrows the credential list to rows carrying a tag. An empty Value
+// matches any value for Key.
+type credentialTagFilter struct {
+	Key   string
+	Value string
+}
+
+// parseCredentialTagFilter accepts "key" or "key=value". Invalid input yields an empty
+// filter rather than an error so a bad link degrades to the unfiltered list.
+func parseCredentialTagFilter(raw string) credentialTagFilter {
+	keyRaw, value, _ := strings.Cut(strings.TrimSpace(raw), "=")
+	key, err := normalizeCredentialTagKey(keyRaw)
+	if err != nil {
+		return credentialTagFilter{}
+	}
+	return credentialTagFilter{Key: key, Value: strings.TrimSpace(value)}
+}
+
+func (f credentialTagFilter) String() string {
+	if f.Key == "" || f.Value == "" {
+		return f.Key
+	}
+	return f.Key + "=" + f.Value
+}
+
+func normalizeCredentialTagKey(raw string) (string, error) {
+	key := strings.ToLower(strings.TrimSpace(raw))
+	switch {
+	case key == "":
+		return "", errors.New("tag key is required")
+	case len(key) > maxCredentialTagKeyLen:
+		return "", fmt.Errorf("tag key must be at most %d characters", maxCredentialTagKeyLen)
+	case !credentialTagKeyPattern.MatchString(key):
+		return "", errors.New("tag key may only contain lowercase letters, digits, and . _ : / -")
+	}
+	return key, nil
+}
+
+func parseCredentialTagForm(c *echo.Context) (key, value string, err error) {
+	key, err = normalizeCredentialTagKey(c.FormValue("key"))
+	if err != nil {
+		return "", "", err
+	}
+	value = strings.TrimSpace(c.FormValue("value"))
+	if len(value) > maxCredentialTagValueLen {
+		return "", "", fmt.Errorf("tag value must be at most %d characters", maxCredentialTagValueLen)
+	}
+	return key, value, nil
+}
+
+// credentialTagIdentity is the natural key tags are stored under. It deliberately omits
+// credential_artifacts.id so tags follow a credential across re-ingests that recreate its row.
+func credentialTagIdentity(credential gen.CredentialArtifact) gen.ListCredentialTagsForCredentialParams {
+	return gen.ListCredentialTagsForCredentialParams{
+		SourceKind:     strings.TrimSpace(credential.SourceKind),
+		SourceName:     strings.TrimSpace(credential.SourceName),
+		CredentialKind: strings.TrimSpace(credential.CredentialKind),
+		ExternalID:     strings.TrimSpace(credential.ExternalID),
+	}
+}
+
+func credentialTagItems(credential gen.CredentialArtifact, tags []gen.CredentialTag) []viewmodels.CredentialTagItem {
+	items := make([]viewmodels.CredentialTagItem, 0, len(tags))
+	for _, tag := range tags {
+		filter := credentialTagFilter{Key: tag.TagKey, Value: tag.TagValue}
+		items = append(items, viewmodels.CredentialTagItem{
+			Key:        tag.TagKey,
+			Value:      tag.TagValue,
+			FilterHref: views.CredentialsListURL(strings.TrimSpace(credential.SourceKind), strings.TrimSpace(credential.SourceName), "", "", "", "", "", "", 0, 0, false, false, 0, filter.String(), "", 1),
+		})
+	}
+	return items
+}
+
+// HandleCredentialTagsCreate adds a tag to a credential, replacing the value of an existing
+// tag with the same key.
+func (h *Handlers) HandleCredentialTagsCreate(c *echo.Context) error {
+	credential, err := h.loadCredentialForUpdate(c)
+	if err != nil || credential.ID == 0 {
+		return err
+	}
+	key, value, err := parseCredentialTagForm(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	identity := credentialTagIdentity(credential)
+	if err := h.Q.UpsertCredentialTag(c.Request().Context(), gen.UpsertCredentialTagParams{
+		SourceKind:     identity.SourceKind,
+		SourceName:     identity.SourceName,
+		CredentialKind: identity.CredentialKind,
+		ExternalID:     identity.ExternalID,
+		TagKey:         key,
+		TagValue:       value,
+	}); err != nil {
+		return h.RenderError(c, err)
+	}
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/credentials/%d", credential.ID))
+}
+
+// HandleCredentialTagDelete removes a tag from a credential by key.
+func (h *Handlers) HandleCredentialTagDelete(c *echo.Context) error {
+	credential, err := h.loadCredentialForUpdate(c)
+	if err != nil || credential.ID == 0 {
+		return err
+	}
+	key, err := normalizeCredentialTagKey(c.FormValue("key"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	identity := credentialTagIdentity(credential)
+	if err := h.Q.DeleteCredentialTag(c.Request().Context(), gen.DeleteCredentialTagParams{
+		SourceKind:     identity.SourceKind,
+		SourceName:     identity.SourceName,
+		CredentialKind: identity.CredentialKind,
+		ExternalID:     identity.ExternalID,
+		TagKey:         key,
+	}); err != nil {
+		return h.RenderError(c, err)
+	}
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/credentials/%d", credential.ID))
+}
+
+// loadCredentialForUpdate returns a zero credential after rendering the response when the
+// id is invalid or unknown.
+func (h *Handlers) loadCredentialForUpdate(c *echo.Context) (gen.CredentialArtifact, error) {
+	if c.Request().Method != http.MethodPost {
+		return gen.CredentialArtifact{}, c.NoContent(http.StatusMethodNotAllowed)
+	}
+	credentialID, err := parsePositiveInt64Param(c.Param("id"))
+	if err != nil {
+		return gen.CredentialArtifact{}, RenderNotFound(c)
+	}
+	credential, err := h.Q.GetCredentialArtifactByID(c.Request().Context(), credentialID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return gen.CredentialArtifact{}, RenderNotFound(c)
+		}
+		return gen.CredentialArtifact{}, h.RenderError(c, err)
+	}
+	if visible, err := h.sourceVisible(c, credential.SourceKind, credential.SourceName); err != nil {
+		return gen.CredentialArtifact{}, h.RenderError(c, err)
+	} else if !visible {
+		return gen.CredentialArtifact{}, RenderNotFound(c)
+	}
+	return credential, nil
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestCredentialTagIdentitySurvivesResyncWithNewSurrogateID(t *testing.T) {
+	t.Parallel()
+
+	before := gen.CredentialArtifact{
+		ID:                 10,
+		SourceKind:         "github",
+		SourceName:         "acme",
+		CredentialKind:     "github_deploy_key",
+		ExternalID:         "123",
+		AssetRefKind:       "github_repo",
+		AssetRefExternalID: "acme/api",
+		DisplayName:        "deploy",
+	}
+	// A re-ingest that recreated the row: new surrogate id, refreshed metadata.
+	after := before
+	after.ID = 42
+	after.DisplayName = "deploy (rotated)"
+	after.Status = "active"
+
+	stored := map[gen.ListCredentialTagsForCredentialParams][]gen.CredentialTag{
+		credentialTagIdentity(before): {{TagKey: "owner", TagValue: "team-platform"}},
+	}
+	tags := stored[credentialTagIdentity(after)]
+	if len(tags) != 1 || tags[0].TagKey != "owner" || tags[0].TagValue != "team-platform" {
+		t.Fatalf("tags after re-sync = %+v, want owner=team-platform", tags)
+	}
+
+	other := before
+	other.ExternalID = "456"
+	if _, ok := stored[credentialTagIdentity(other)]; ok {
+		t.Fatalf("a different credential inherited tags")
+	}
+}
+
+func TestParseCredentialTagFilter(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		raw  string
+		want credentialTagFilter
+	}{
+		{raw: "", want: credentialTagFilter{}},
+		{raw: "owner", want: credentialTagFilter{Key: "owner"}},
+		{raw: " Owner = team-platform ", want: credentialTagFilter{Key: "owner", Value: "team-platform"}},
+		{raw: "env=prod=eu", want: credentialTagFilter{Key: "env", Value: "prod=eu"}},
+		{raw: "=prod", want: credentialTagFilter{}},
+		{raw: "bad key=x", want: credentialTagFilter{}},
+	}
+	for _, tc := range cases {
+		if got := parseCredentialTagFilter(tc.raw); got != tc.want {
+			t.Fatalf("parseCredentialTagFilter(%q) = %+v, want %+v", tc.raw, got, tc.want)
+		}
+	}
+	if got := parseCredentialTagFilter("Owner=team").String(); got != "owner=team" {
+		t.Fatalf("String() = %q", got)
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v5"
+)
+
+// credentialsAPIResponse is the JSON body returned by HandleAPICredentials. Filters echo the
+// parameters after validation and clamping, so clients can see what was actually applied.
+type credentialsAPIResponse struct {
+	Items      []credentialAPIItem  `json:"items"`
+	Page       int                  `json:"page"`
+	PerPage    int                  `json:"per_page"`
+	TotalCount int64                `json:"total_count"`
+	TotalPages int                  `json:"total_pages"`
+	Truncated  bool                 `json:"truncated"`
+	Filters    credentialAPIFilters `json:"filters"`
+}
+
+type credentialAPIFilters struct {
+	SourceKind       string `json:"source_kind,omitempty"`
+	SourceName       string `json:"source_name,omitempty"`
+	Query            string `json:"q,omitempty"`
+	CredentialKind   string `json:"credential_kind,omitempty"`
+	Status           string `json:"status,omitempty"`
+	RiskLevel        string `json:"risk_level,omitempty"`
+	ExpiryState      string `json:"expiry_state,omitempty"`
+	ExpiresInDays    int    `json:"expires_in_days,omitempty"`
+	IntroducedInDays int    `json:"introduced_in_days,omitempty"`
+	LastUsed         string `json:"last_used,omitempty"`
+	Shared           bool   `json:"shared,omitempty"`
+	BroadScope       bool   `json:"broad_scope,omitempty"`
+	CreatedBy        int64  `json:"created_by,omitempty"`
+	Tag              string `json:"tag,omitempty"`
+	Sort             string `json:"sort,omitempty"`
+}
+
+type credentialAPIItem struct {
+	ID                   int64      `json:"id"`
+	SourceKind           string     `json:"source_kind"`
+	SourceName           string     `json:"source_name"`
+	CredentialKind       string     `json:"credential_kind"`
+	ExternalID           string     `json:"external_id"`
+	DisplayName          string     `json:"display_name"`
+	AssetRefKind         string     `json:"asset_ref_kind,omitempty"`
+	AssetRefExternalID   string     `json:"asset_ref_external_id,omitempty"`
+	Status               string     `json:"status"`
+	RiskLevel            string     `json:"risk_level"`
+	SharedService        bool       `json:"shared_service"`
+	BroadScope           bool       `json:"broad_scope"`
+	CreatedAt            *time.Time `json:"created_at"`
+	ExpiresAt            *time.Time `json:"expires_at"`
+	LastUsedAt           *time.Time `json:"last_used_at"`
+	LastUsedBucket       string     `json:"last_used_bucket"`
+	CreatedByExternalID  string     `json:"created_by_external_id,omitempty"`
+	CreatedByDisplayName string     `json:"created_by_display_name,omitempty"`
+}
+
+// HandleAPICredentials serves the credential inventory as JSON. It accepts exactly the query
+// parameters of the credentials page, validated and clamped the same way.
+func (h *Handlers) HandleAPICredentials(c *echo.Context) error {
+	states, err := h.loadScopedConnectorStates(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	return h.renderCredentialsAPI(c, connectorSnapshotFromStates(states))
+}
+
+func (h *Handlers) renderCredentialsAPI(c *echo.Context, snap ConnectorSnapshot) error {
+	ctx := c.Request().Context()
+	sources := programmaticSources(snap, c.QueryParam("include_disabled") == "1")
+	selected, hasSource := selectProgrammaticSource(c, sources)
+	filters, err := h.parseCredentialListFilters(ctx, c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
+	resp := credentialsAPIResponse{
+		Items:      []credentialAPIItem{},
+		Page:       1,
+		PerPage:    filters.PerPage,
+		TotalPages: 1,
+		Filters: credentialAPIFilters{
+			SourceKind:       selected.SourceKind,
+			SourceName:       selected.SourceName,
+			Query:            filters.Query,
+			CredentialKind:   filters.CredentialKind,
+			Status:           filters.Status,
+			RiskLevel:        filters.RiskLevel,
+			ExpiryState:      filters.ExpiryState,
+			ExpiresInDays:    filters.ExpiresInDays,
+			IntroducedInDays: filters.IntroducedInDays,
+			LastUsed:         filters.LastUsed,
+			Shared:           filters.SharedOnly,
+			BroadScope:       filters.BroadScopeOnly,
+			CreatedBy:        filters.CreatedBy.IdentityID,
+			Tag:              filters.Tag.String(),
+			Sort:             filters.Sort,
+		},
+	}
+	if !hasSource {
+		return c.JSON(http.StatusOK, resp)
+	}
+	activeSources := effectiveProgrammaticSources(selected, sources)
+	if len(activeSources) == 0 {
+		return c.JSON(http.StatusOK, resp)
+	}
+
+	page, err := h.loadCredentialListPage(ctx, filters, activeSources)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
+	now := time.Now().UTC()
+	for _, row := range page.Rows {
+		resp.Items = append(resp.Items, credentialAPIItem{
+			ID:                   row.ID,
+			SourceKind:           strings.TrimSpace(row.SourceKind),
+			SourceName:           strings.TrimSpace(row.SourceName),
+			CredentialKind:       strings.TrimSpace(row.CredentialKind),
+			ExternalID:           strings.TrimSpace(row.ExternalID),
+			DisplayName:          strings.TrimSpace(row.DisplayName),
+			AssetRefKind:         strings.TrimSpace(row.AssetRefKind),
+			AssetRefExternalID:   strings.TrimSpace(row.AssetRefExternalID),
+			Status:               strings.TrimSpace(row.Status),
+			RiskLevel:            credentialRiskLevel(row, now, h.credentialRiskPolicy()),
+			SharedService:        isSharedServiceCredential(row, h.Cfg.CredentialSharedNamePatterns),
+			BroadScope:           isBroadScopeCredential(row),
+			CreatedAt:            apiTimestamp(row.CreatedAtSource),
+			ExpiresAt:            apiTimestamp(row.ExpiresAtSource),
+			LastUsedAt:           apiTimestamp(row.LastUsedAtSource),
+			LastUsedBucket:       credentialLastUsedBucket(row.LastUsedAtSource, now),
+			CreatedByExternalID:  strings.TrimSpace(row.CreatedByExternalID),
+			CreatedByDisplayName: strings.TrimSpace(row.CreatedByDisplayName),
+		})
+	}
+	resp.Page = page.Page
+	resp.TotalCount = page.TotalCount
+	resp.TotalPages = page.TotalPages
+	resp.Truncated = page.Truncated
+	return c.JSON(http.StatusOK, resp)
+}
+
+// apiTimestamp returns ts in UTC, or nil when it is unset.
+func apiTimestamp(ts pgtype.Timestamptz) *time.Time {
+	if !ts.Valid {
+		return nil
+	}
+	t := ts.Time.UTC()
+	return &t
+}
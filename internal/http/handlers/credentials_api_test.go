@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/connectors/configstore"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+)
+
+// credentialInventoryDB answers the credential count and page queries from an in-memory
+// inventory, applying the source, credential kind, status, and introduced-in-days filters plus the
+// page window, and records the arguments of every query.
+type credentialInventoryDB struct {
+	rows []gen.CredentialArtifact
+
+	mu    gosync.Mutex
+	calls []string
+}
+
+func (db *credentialInventoryDB) record(sql string, args []interface{}) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.calls = append(db.calls, fmt.Sprintf("%s %v", strings.SplitN(sql, "\n", 2)[0], args))
+}
+
+func (db *credentialInventoryDB) takeCalls() []string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	calls := db.calls
+	db.calls = nil
+	return calls
+}
+
+func (db *credentialInventoryDB) match(args []interface{}) []gen.CredentialArtifact {
+	var out []gen.CredentialArtifact
+	for _, row := range db.rows {
+		if row.SourceKind != args[0] || row.SourceName != args[1] {
+			continue
+		}
+		if kind := args[2].(string); kind != "" && row.CredentialKind != kind {
+			continue
+		}
+		if status := args[3].(string); status != "" && row.Status != status {
+			continue
+		}
+		if days := args[7].(int32); days > 0 && (!row.FirstSeenAt.Valid || row.FirstSeenAt.Time.Before(time.Now().AddDate(0, 0, -int(days)))) {
+			continue
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
+func (db *credentialInventoryDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, fmt.Errorf("unexpected Exec")
+}
+
+func (db *credentialInventoryDB) Query(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	db.record(sql, args)
+	if !strings.Contains(sql, "-- name: ListCredentialArtifactsPageBySourceAndQueryAndFilters ") {
+		return nil, fmt.Errorf("unexpected Query: %s", strings.SplitN(sql, "\n", 2)[0])
+	}
+	rows := db.match(args)
+	offset, limit := int(args[len(args)-2].(int32)), int(args[len(args)-1].(int32))
+	rows = rows[min(offset, len(rows)):min(offset+limit, len(rows))]
+	return &credentialArtifactRows{rows: rows, idx: -1}, nil
+}
+
+func (db *credentialInventoryDB) QueryRow(_ context.Context, sql string, args ...interface{}) pgx.Row {
+	db.record(sql, args)
+	if !strings.Contains(sql, "-- name: CountCredentialArtifactsBySourceAndQueryAndFilters ") {
+		return errRow{err: fmt.Errorf("unexpected QueryRow: %s", strings.SplitN(sql, "\n", 2)[0])}
+	}
+	return countRow{count: int64(len(db.match(args)))}
+}
+
+type countRow struct{ count int64 }
+
+func (r countRow) Scan(dest ...any) error {
+	*(dest[0].(*int64)) = r.count
+	return nil
+}
+
+type errRow struct{ err error }
+
+func (r errRow) Scan(...any) error { return r.err }
+
+// credentialArtifactRows scans gen.CredentialArtifact values field by field, matching the column
+// order of the generated queries.
+type credentialArtifactRows struct {
+	rows []gen.CredentialArtifact
+	idx  int
+}
+
+func (r *credentialArtifactRows) Close()                                       {}
+func (r *credentialArtifactRows) Err() error                                   { return nil }
+func (r *credentialArtifactRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *credentialArtifactRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *credentialArtifactRows) Values() ([]any, error)                       { return nil, nil }
+func (r *credentialArtifactRows) RawValues() [][]byte                          { return nil }
+func (r *credentialArtifactRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *credentialArtifactRows) Next() bool {
+	r.idx++
+	return r.idx < len(r.rows)
+}
+
+func (r *credentialArtifactRows) Scan(dest ...any) error {
+	row := reflect.ValueOf(r.rows[r.idx])
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(row.Field(i))
+	}
+	return nil
+}
+
+func credentialInventoryFixture() []gen.CredentialArtifact {
+	now := time.Now().UTC()
+	var rows []gen.CredentialArtifact
+	add := func(sourceKind, sourceName, kind, status string, expiresIn time.Duration) {
+		id := int64(len(rows) + 1)
+		rows = append(rows, gen.CredentialArtifact{
+			ID:              id,
+			SourceKind:      sourceKind,
+			SourceName:      sourceName,
+			CredentialKind:  kind,
+			ExternalID:      fmt.Sprintf("cred-%d", id),
+			DisplayName:     fmt.Sprintf("Credential %d", id),
+			Status:          status,
+			ExpiresAtSource: pgtype.Timestamptz{Time: now.Add(expiresIn), Valid: true},
+		})
+	}
+	for idx := 0; idx < 30; idx++ {
+		kind := "github_deploy_key"
+		if idx%3 == 0 {
+			kind = "github_pat_fine_grained"
+		}
+		status := "active"
+		if idx%4 == 0 {
+			status = "revoked"
+		}
+		add("github", "acme", kind, status, time.Duration(idx+1)*24*time.Hour)
+	}
+	for idx := 0; idx < 12; idx++ {
+		add("entra", "tenant-a", "entra_client_secret", "active", time.Duration(idx+2)*36*time.Hour)
+	}
+	return rows
+}
+
+var credentialRowHrefPattern = regexp.MustCompile(`data-row-href="/credentials/(\d+)"`)
+
+func TestCredentialsAPIMatchesHTMLForSameFilters(t *testing.T) {
+	t.Parallel()
+
+	snap := ConnectorSnapshot{
+		Entra:            configstore.EntraConfig{TenantID: "tenant-a"},
+		EntraEnabled:     true,
+		EntraConfigured:  true,
+		GitHub:           configstore.GitHubConfig{Org: "acme"},
+		GitHubEnabled:    true,
+		GitHubConfigured: true,
+	}
+
+	queries := []string{
+		"",
+		"source_kind=github",
+		"source_kind=github&credential_kind=github_deploy_key&status=active",
+		"source_kind=github&per_page=7&page=2&sort=last_used",
+		"source_kind=github&page=999&expires_in_days=999999&risk_level=bogus&expiry_state=nope",
+		"credential_kind=entra_client_secret&per_page=5&page=2",
+		"source_kind=unknown&status=revoked&last_used=unused_90d&sort=risk",
+	}
+
+	for _, query := range queries {
+		t.Run(query, func(t *testing.T) {
+			db := &credentialInventoryDB{rows: credentialInventoryFixture()}
+			h := &Handlers{Q: gen.New(db)}
+
+			htmlCtx, htmlRec := newTestContext(http.MethodGet, "/credentials?"+query)
+			if err := h.renderCredentials(htmlCtx, viewmodels.LayoutData{}, snap); err != nil {
+				t.Fatalf("renderCredentials() error = %v", err)
+			}
+			if htmlRec.Code != http.StatusOK {
+				t.Fatalf("HTML status = %d, body = %s", htmlRec.Code, htmlRec.Body.String())
+			}
+			htmlCalls := db.takeCalls()
+			var htmlIDs []int64
+			for _, m := range credentialRowHrefPattern.FindAllStringSubmatch(htmlRec.Body.String(), -1) {
+				var id int64
+				_, _ = fmt.Sscan(m[1], &id)
+				htmlIDs = append(htmlIDs, id)
+			}
+
+			apiCtx, apiRec := newTestContext(http.MethodGet, "/api/credentials?"+query)
+			if err := h.renderCredentialsAPI(apiCtx, snap); err != nil {
+				t.Fatalf("renderCredentialsAPI() error = %v", err)
+			}
+			if apiRec.Code != http.StatusOK {
+				t.Fatalf("API status = %d, body = %s", apiRec.Code, apiRec.Body.String())
+			}
+			apiCalls := db.takeCalls()
+			var resp credentialsAPIResponse
+			if err := json.Unmarshal(apiRec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode API response: %v", err)
+			}
+			apiIDs := make([]int64, 0, len(resp.Items))
+			for _, item := range resp.Items {
+				apiIDs = append(apiIDs, item.ID)
+			}
+
+			if len(htmlIDs) == 0 {
+				t.Fatalf("HTML rendered no credentials for %q", query)
+			}
+			if !slices.Equal(htmlIDs, apiIDs) {
+				t.Fatalf("ids differ for %q: html=%v api=%v", query, htmlIDs, apiIDs)
+			}
+			if !slices.Equal(htmlCalls, apiCalls) {
+				t.Fatalf("queries differ for %q:\nhtml=%v\napi=%v", query, htmlCalls, apiCalls)
+			}
+		})
+	}
+}
+
+func TestCredentialsAPIEchoesClampedFilters(t *testing.T) {
+	t.Parallel()
+
+	db := &credentialInventoryDB{rows: credentialInventoryFixture()}
+	h := &Handlers{Q: gen.New(db)}
+	snap := ConnectorSnapshot{
+		GitHub:           configstore.GitHubConfig{Org: "acme"},
+		GitHubEnabled:    true,
+		GitHubConfigured: true,
+	}
+
+	c, rec := newTestContext(http.MethodGet, "/api/credentials?source_kind=github&expires_in_days=99999&introduced_in_days=-4&risk_level=bogus&expiry_state=EXPIRED&broad_scope=1&per_page=3&page=999&sort=nope")
+	if err := h.renderCredentialsAPI(c, snap); err != nil {
+		t.Fatalf("renderCredentialsAPI() error = %v", err)
+	}
+	var resp credentialsAPIResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode API response: %v", err)
+	}
+
+	want := credentialAPIFilters{SourceKind: "github", ExpiryState: "expired", ExpiresInDays: 3650, BroadScope: true, Sort: normalizeCredentialSort("")}
+	if resp.Filters != want {
+		t.Fatalf("filters = %+v, want %+v", resp.Filters, want)
+	}
+	if resp.PerPage != normalizePerPage("3") {
+		t.Fatalf("per_page = %d, want %d", resp.PerPage, normalizePerPage("3"))
+	}
+	if resp.Page != resp.TotalPages {
+		t.Fatalf("page = %d, want clamped to total pages %d", resp.Page, resp.TotalPages)
+	}
+}
+
+func TestCredentialsAPIIntroducedFilterUsesFirstSeenTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	seen := func(d time.Duration) pgtype.Timestamptz { return pgtype.Timestamptz{Time: now.Add(-d), Valid: true} }
+	db := &credentialInventoryDB{rows: []gen.CredentialArtifact{
+		// Seen again by the latest run but first seen two months ago.
+		{ID: 1, SourceKind: "github", SourceName: "acme", CredentialKind: "github_deploy_key", ExternalID: "old", Status: "active", FirstSeenAt: seen(60 * 24 * time.Hour), LastObservedAt: seen(time.Hour)},
+		{ID: 2, SourceKind: "github", SourceName: "acme", CredentialKind: "github_deploy_key", ExternalID: "new", Status: "active", FirstSeenAt: seen(2 * 24 * time.Hour), LastObservedAt: seen(time.Hour)},
+	}}
+	h := &Handlers{Q: gen.New(db)}
+	snap := ConnectorSnapshot{
+		GitHub:           configstore.GitHubConfig{Org: "acme"},
+		GitHubEnabled:    true,
+		GitHubConfigured: true,
+	}
+
+	c, rec := newTestContext(http.MethodGet, "/api/credentials?source_kind=github&introduced_in_days=7")
+	if err := h.renderCredentialsAPI(c, snap); err != nil {
+		t.Fatalf("renderCredentialsAPI() error = %v", err)
+	}
+	var resp credentialsAPIResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode API response: %v", err)
+	}
+	if resp.Filters.IntroducedInDays != 7 {
+		t.Fatalf("introduced_in_days = %d, want 7", resp.Filters.IntroducedInDays)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].ID != 2 {
+		t.Fatalf("items = %+v, want only the credential first seen two days ago", resp.Items)
+	}
+}
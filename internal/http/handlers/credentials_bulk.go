@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/auth"
+	connregistry "github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/authn"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+)
+
+const (
+	credentialBulkActionTag         = "tag"
+	credentialBulkActionAssignOwner = "assign_owner"
+	credentialBulkActionSuppress    = "suppress"
+	credentialBulkActionRevoke      = "revoke"
+
+	// maxCredentialBulkIDs bounds how many credentials one bulk request may change.
+	maxCredentialBulkIDs = 500
+
+	// credentialOwnerTagKey and credentialSuppressedTagKey are the tags the assign_owner and
+	// suppress actions set, so both stay filterable on the credential list like any other tag.
+	credentialOwnerTagKey      = "owner"
+	credentialSuppressedTagKey = "suppressed"
+
+	credentialBulkStatusOK    = "ok"
+	credentialBulkStatusError = "error"
+)
+
+// credentialBulkActionRoles is the minimum role for each bulk action. It mirrors the
+// per-credential routes: analysts annotate, while suppressing and revoking stay with admins.
+var credentialBulkActionRoles = map[string]string{
+	credentialBulkActionTag:         auth.RoleAnalyst,
+	credentialBulkActionAssignOwner: auth.RoleAnalyst,
+	credentialBulkActionSuppress:    auth.RoleAdmin,
+	credentialBulkActionRevoke:      auth.RoleAdmin,
+}
+
+type credentialBulkQueries interface {
+	credentialRevocationQueries
+	GetCredentialArtifactByID(ctx context.Context, id int64) (gen.CredentialArtifact, error)
+	UpsertCredentialTag(ctx context.Context, arg gen.UpsertCredentialTagParams) error
+}
+
+// credentialBulkRequest is a parsed bulk form. IDs holds the raw submitted ids so an invalid one
+// is reported back as submitted.
+type credentialBulkRequest struct {
+	Action   string
+	IDs      []string
+	TagKey   string
+	TagValue string
+	Reason   string
+}
+
+// credentialBulkResult is the outcome for one submitted credential id.
+type credentialBulkResult struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// credentialBulkResponse is the JSON body returned by HandleCredentialsBulk with ?format=json.
+type credentialBulkResponse struct {
+	Action    string                 `json:"action"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Results   []credentialBulkResult `json:"results"`
+}
+
+// credentialBulkActionAllowed reports whether role may apply action.
+func credentialBulkActionAllowed(role, action string) bool {
+	minRole, ok := credentialBulkActionRoles[action]
+	return ok && auth.RoleAtLeast(role, minRole)
+}
+
+// parseCredentialBulkForm validates the action and its arguments. Credential ids are split on
+// commas and whitespace, deduplicated, and checked one by one when the action is applied.
+func parseCredentialBulkForm(form map[string][]string) (credentialBulkRequest, error) {
+	first := func(key string) string {
+		if values := form[key]; len(values) > 0 {
+			return strings.TrimSpace(values[0])
+		}
+		return ""
+	}
+	req := credentialBulkRequest{Action: strings.ToLower(first("action"))}
+	if _, ok := credentialBulkActionRoles[req.Action]; !ok {
+		return req, errors.New("action must be tag, assign_owner, suppress, or revoke")
+	}
+
+	seen := map[string]bool{}
+	for _, raw := range form["ids"] {
+		for _, id := range strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == ' ' || r == '\n' || r == '\t' }) {
+			if !seen[id] {
+				seen[id] = true
+				req.IDs = append(req.IDs, id)
+			}
+		}
+	}
+	switch {
+	case len(req.IDs) == 0:
+		return req, errors.New("select at least one credential")
+	case len(req.IDs) > maxCredentialBulkIDs:
+		return req, fmt.Errorf("select at most %d credentials", maxCredentialBulkIDs)
+	}
+
+	switch req.Action {
+	case credentialBulkActionTag:
+		key, err := normalizeCredentialTagKey(first("key"))
+		if err != nil {
+			return req, err
+		}
+		req.TagKey, req.TagValue = key, first("value")
+	case credentialBulkActionAssignOwner:
+		req.TagKey, req.TagValue = credentialOwnerTagKey, first("owner")
+		if req.TagValue == "" {
+			return req, errors.New("owner is required")
+		}
+	case credentialBulkActionSuppress:
+		req.TagKey, req.TagValue = credentialSuppressedTagKey, first("reason")
+	case credentialBulkActionRevoke:
+		if first("confirm") != credentialRevokeConfirmation {
+			return req, fmt.Errorf("type %q to confirm revocation", credentialRevokeConfirmation)
+		}
+		req.Reason = first("reason")
+		if len(req.Reason) > maxCredentialRevokeReasonLen {
+			return req, fmt.Errorf("reason must be at most %d characters", maxCredentialRevokeReasonLen)
+		}
+	}
+	if len(req.TagValue) > maxCredentialTagValueLen {
+		return req, fmt.Errorf("tag value must be at most %d characters", maxCredentialTagValueLen)
+	}
+	return req, nil
+}
+
+// applyCredentialBulkAction applies req to each credential in turn. A credential that is
+// unknown, outside the scope's org, or fails to update is reported and skipped; the others are
+// still changed. integrationFor returns the revoking connector for a credential, or nil.
+func applyCredentialBulkAction(ctx context.Context, q credentialBulkQueries, req credentialBulkRequest, scope orgScope, integrationFor func(gen.CredentialArtifact) connregistry.Integration, requestedBy pgtype.Int8) credentialBulkResponse {
+	resp := credentialBulkResponse{Action: req.Action, Results: make([]credentialBulkResult, 0, len(req.IDs))}
+	for _, rawID := range req.IDs {
+		message, err := applyCredentialBulkActionToID(ctx, q, req, scope, integrationFor, requestedBy, rawID)
+		if err != nil {
+			resp.Failed++
+			resp.Results = append(resp.Results, credentialBulkResult{ID: rawID, Status: credentialBulkStatusError, Message: err.Error()})
+			continue
+		}
+		resp.Succeeded++
+		resp.Results = append(resp.Results, credentialBulkResult{ID: rawID, Status: credentialBulkStatusOK, Message: message})
+	}
+	return resp
+}
+
+// applyCredentialBulkActionToID applies req to the credential with rawID and returns a note on
+// what was done. Errors are safe to show to the operator.
+func applyCredentialBulkActionToID(ctx context.Context, q credentialBulkQueries, req credentialBulkRequest, scope orgScope, integrationFor func(gen.CredentialArtifact) connregistry.Integration, requestedBy pgtype.Int8, rawID string) (string, error) {
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil || id <= 0 {
+		return "", errors.New("invalid credential id")
+	}
+	credential, err := q.GetCredentialArtifactByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !scope.AllowsSource(credential.SourceKind, credential.SourceName)) {
+		return "", errors.New("credential not found")
+	}
+	if err != nil {
+		slog.Warn("credential bulk action failed", "action", req.Action, "credential_id", id, "err", err)
+		return "", errors.New("could not load credential")
+	}
+
+	identity := credentialTagIdentity(credential)
+	if req.Action != credentialBulkActionRevoke {
+		if err := q.UpsertCredentialTag(ctx, gen.UpsertCredentialTagParams{
+			SourceKind:     identity.SourceKind,
+			SourceName:     identity.SourceName,
+			CredentialKind: identity.CredentialKind,
+			ExternalID:     identity.ExternalID,
+			TagKey:         req.TagKey,
+			TagValue:       req.TagValue,
+		}); err != nil {
+			slog.Warn("credential bulk action failed", "action", req.Action, "credential_id", id, "err", err)
+			return "", errors.New("could not tag credential")
+		}
+		return "", nil
+	}
+
+	var integration connregistry.Integration
+	if integrationFor != nil {
+		integration = integrationFor(credential)
+	}
+	task, err := revokeCredential(ctx, q, integration, credential, req.Reason, requestedBy)
+	if err != nil {
+		slog.Warn("credential bulk action failed", "action", req.Action, "credential_id", id, "err", err)
+		return "", errors.New("could not record revocation")
+	}
+	switch {
+	case task.Method == credentialRevocationMethodAPI:
+		return "revoked by the provider", nil
+	case task.ErrorMessage != "":
+		return "provider rejected the revocation; revocation task created", nil
+	default:
+		return "revocation task created", nil
+	}
+}
+
+// HandleCredentialsBulk applies one action to several credentials: tag, assign_owner, suppress,
+// or revoke. Each credential succeeds or fails on its own, and the per-credential results are
+// returned as JSON with ?format=json or summarized in a toast on the credential list otherwise.
+func (h *Handlers) HandleCredentialsBulk(c *echo.Context) error {
+	wantJSON := strings.EqualFold(strings.TrimSpace(c.QueryParam("format")), "json")
+	fail := func(status int, message string) error {
+		if wantJSON {
+			return c.JSON(status, map[string]string{"error": message})
+		}
+		return c.String(status, message)
+	}
+
+	form, err := c.FormValues()
+	if err != nil {
+		return fail(http.StatusBadRequest, "invalid form")
+	}
+	req, err := parseCredentialBulkForm(form)
+	if err != nil {
+		return fail(http.StatusBadRequest, err.Error())
+	}
+	principal, _ := authn.PrincipalFromContext(c)
+	if !credentialBulkActionAllowed(principal.Role, req.Action) {
+		return fail(http.StatusForbidden, "forbidden")
+	}
+
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	ctx := c.Request().Context()
+	integrations := map[string]connregistry.Integration{}
+	integrationFor := func(credential gen.CredentialArtifact) connregistry.Integration {
+		key := orgScopeSourceKey(credential.SourceKind, credential.SourceName)
+		if integration, ok := integrations[key]; ok {
+			return integration
+		}
+		integration, err := h.credentialIntegration(ctx, credential)
+		if err != nil {
+			// A connector that cannot be built still leaves the manual path available.
+			slog.Warn("credential revocation integration unavailable", "credential_id", credential.ID, "source_kind", credential.SourceKind, "err", err)
+			integration = nil
+		}
+		integrations[key] = integration
+		return integration
+	}
+	resp := applyCredentialBulkAction(ctx, h.Q, req, scope, integrationFor, principalUserID(c))
+	setAuditDetail(c, auditDetail{Action: "credential.bulk." + req.Action, After: resp})
+
+	if wantJSON {
+		return c.JSON(http.StatusOK, resp)
+	}
+	setFlashToast(c, credentialBulkToast(resp))
+	return c.Redirect(http.StatusSeeOther, credentialBulkReturnPath(c.FormValue("return_to")))
+}
+
+// credentialBulkToast summarizes a bulk response, naming up to three failed credentials.
+func credentialBulkToast(resp credentialBulkResponse) viewmodels.ToastViewData {
+	title := fmt.Sprintf("%d of %d credentials updated", resp.Succeeded, resp.Succeeded+resp.Failed)
+	if resp.Failed == 0 {
+		return viewmodels.ToastViewData{Category: "success", Title: title}
+	}
+	var failures []string
+	for _, result := range resp.Results {
+		if result.Status != credentialBulkStatusError {
+			continue
+		}
+		if len(failures) == 3 {
+			failures = append(failures, fmt.Sprintf("and %d more", resp.Failed-3))
+			break
+		}
+		failures = append(failures, "#"+result.ID+": "+result.Message)
+	}
+	category := "warning"
+	if resp.Succeeded == 0 {
+		category = "error"
+	}
+	return viewmodels.ToastViewData{Category: category, Title: title, Description: strings.Join(failures, "; ")}
+}
+
+// credentialBulkReturnPath keeps the redirect on the credential list, with its filters.
+func credentialBulkReturnPath(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "/credentials" || strings.HasPrefix(raw, "/credentials?") {
+		return raw
+	}
+	return "/credentials"
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/auth"
+	connregistry "github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/authn"
+)
+
+type fakeCredentialBulkQueries struct {
+	fakeCredentialRevocationQueries
+	credentials map[int64]gen.CredentialArtifact
+	tagged      []gen.UpsertCredentialTagParams
+	tagErr      map[string]error
+}
+
+func (f *fakeCredentialBulkQueries) GetCredentialArtifactByID(_ context.Context, id int64) (gen.CredentialArtifact, error) {
+	credential, ok := f.credentials[id]
+	if !ok {
+		return gen.CredentialArtifact{}, pgx.ErrNoRows
+	}
+	return credential, nil
+}
+
+func (f *fakeCredentialBulkQueries) UpsertCredentialTag(_ context.Context, arg gen.UpsertCredentialTagParams) error {
+	if err := f.tagErr[arg.ExternalID]; err != nil {
+		return err
+	}
+	f.tagged = append(f.tagged, arg)
+	return nil
+}
+
+func newFakeCredentialBulkQueries() *fakeCredentialBulkQueries {
+	return &fakeCredentialBulkQueries{credentials: map[int64]gen.CredentialArtifact{
+		1: {ID: 1, SourceKind: "github", SourceName: "acme", CredentialKind: "github_deploy_key", ExternalID: "11"},
+		2: {ID: 2, SourceKind: "github", SourceName: "acme", CredentialKind: "github_deploy_key", ExternalID: "22"},
+		3: {ID: 3, SourceKind: "github", SourceName: "other-org", CredentialKind: "github_deploy_key", ExternalID: "33"},
+	}}
+}
+
+// testCredentialBulkScope is the default org's scope with the other-org source assigned away.
+func testCredentialBulkScope() orgScope {
+	return orgScope{orgID: auth.DefaultOrgID, owners: map[string]int64{orgScopeSourceKey("github", "other-org"): auth.DefaultOrgID + 1}}
+}
+
+func TestApplyCredentialBulkActionPartialSuccess(t *testing.T) {
+	t.Parallel()
+
+	q := newFakeCredentialBulkQueries()
+	q.tagErr = map[string]error{"22": errors.New("db down")}
+	req, err := parseCredentialBulkForm(url.Values{
+		"action": {"assign_owner"},
+		"owner":  {" alice@example.com "},
+		"ids":    {"1", "2,abc", "3 404", "1"},
+	})
+	if err != nil {
+		t.Fatalf("parseCredentialBulkForm() error = %v", err)
+	}
+
+	resp := applyCredentialBulkAction(context.Background(), q, req, testCredentialBulkScope(), nil, pgtype.Int8{})
+	if resp.Succeeded != 1 || resp.Failed != 4 || len(resp.Results) != 5 {
+		t.Fatalf("resp = %+v, want 1 succeeded and 4 failed", resp)
+	}
+	want := map[string]string{
+		"1":   "",
+		"2":   "could not tag credential",
+		"abc": "invalid credential id",
+		"3":   "credential not found",
+		"404": "credential not found",
+	}
+	for _, result := range resp.Results {
+		wantMessage, ok := want[result.ID]
+		if !ok {
+			t.Fatalf("unexpected result %+v", result)
+		}
+		wantStatus := credentialBulkStatusError
+		if wantMessage == "" {
+			wantStatus = credentialBulkStatusOK
+		}
+		if result.Status != wantStatus || result.Message != wantMessage {
+			t.Fatalf("result %s = %+v, want status %q message %q", result.ID, result, wantStatus, wantMessage)
+		}
+	}
+	if len(q.tagged) != 1 || q.tagged[0].ExternalID != "11" || q.tagged[0].TagKey != "owner" || q.tagged[0].TagValue != "alice@example.com" {
+		t.Fatalf("tagged = %+v, want owner tag on credential 11 only", q.tagged)
+	}
+}
+
+func TestApplyCredentialBulkActionRevokes(t *testing.T) {
+	t.Parallel()
+
+	q := newFakeCredentialBulkQueries()
+	req, err := parseCredentialBulkForm(url.Values{"action": {"revoke"}, "confirm": {"revoke"}, "reason": {"leaked"}, "ids": {"1,2"}})
+	if err != nil {
+		t.Fatalf("parseCredentialBulkForm() error = %v", err)
+	}
+	integration := &fakeRevokerIntegration{fakeSyncTriggerIntegration: fakeSyncTriggerIntegration{kind: "github", name: "acme"}}
+	integrationFor := func(credential gen.CredentialArtifact) connregistry.Integration {
+		if credential.ID == 1 {
+			return integration
+		}
+		return nil
+	}
+
+	resp := applyCredentialBulkAction(context.Background(), q, req, testCredentialBulkScope(), integrationFor, pgtype.Int8{Int64: 5, Valid: true})
+	if resp.Succeeded != 2 || resp.Failed != 0 {
+		t.Fatalf("resp = %+v, want both revoked", resp)
+	}
+	if resp.Results[0].Message != "revoked by the provider" || resp.Results[1].Message != "revocation task created" {
+		t.Fatalf("results = %+v", resp.Results)
+	}
+	if len(integration.revoked) != 1 || len(q.created) != 2 || q.created[1].Reason != "leaked" {
+		t.Fatalf("revoked = %v, tasks = %+v", integration.revoked, q.created)
+	}
+}
+
+func TestParseCredentialBulkFormRejectsInvalidRequests(t *testing.T) {
+	t.Parallel()
+
+	for name, form := range map[string]url.Values{
+		"unknown action":       {"action": {"delete"}, "ids": {"1"}},
+		"no ids":               {"action": {"tag"}, "key": {"team"}},
+		"bad tag key":          {"action": {"tag"}, "key": {"Team Name"}, "ids": {"1"}},
+		"owner missing":        {"action": {"assign_owner"}, "ids": {"1"}},
+		"revoke unconfirmed":   {"action": {"revoke"}, "ids": {"1"}},
+		"too many credentials": {"action": {"suppress"}, "ids": testCredentialBulkIDs(maxCredentialBulkIDs + 1)},
+		"suppress reason long": {"action": {"suppress"}, "ids": {"1"}, "reason": {strings.Repeat("x", maxCredentialTagValueLen+1)}},
+	} {
+		if _, err := parseCredentialBulkForm(form); err == nil {
+			t.Fatalf("%s: parseCredentialBulkForm() error = nil, want error", name)
+		}
+	}
+}
+
+func testCredentialBulkIDs(n int) []string {
+	ids := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+	return ids
+}
+
+func TestCredentialBulkActionAllowed(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		role   string
+		action string
+		want   bool
+	}{
+		{role: auth.RoleViewer, action: credentialBulkActionTag, want: false},
+		{role: auth.RoleAnalyst, action: credentialBulkActionTag, want: true},
+		{role: auth.RoleAnalyst, action: credentialBulkActionAssignOwner, want: true},
+		{role: auth.RoleAnalyst, action: credentialBulkActionSuppress, want: false},
+		{role: auth.RoleAnalyst, action: credentialBulkActionRevoke, want: false},
+		{role: auth.RoleAdmin, action: credentialBulkActionSuppress, want: true},
+		{role: auth.RoleAdmin, action: credentialBulkActionRevoke, want: true},
+		{role: auth.RoleAdmin, action: "delete", want: false},
+	} {
+		if got := credentialBulkActionAllowed(tc.role, tc.action); got != tc.want {
+			t.Fatalf("credentialBulkActionAllowed(%q, %q) = %v, want %v", tc.role, tc.action, got, tc.want)
+		}
+	}
+}
+
+func TestHandleCredentialsBulkForbidsAnalystRevocation(t *testing.T) {
+	t.Parallel()
+
+	form := url.Values{"action": {"revoke"}, "confirm": {"revoke"}, "ids": {"1,2"}}
+	req := httptest.NewRequest(http.MethodPost, "/credentials/bulk?format=json", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(authn.ContextKeyPrincipal, auth.Principal{UserID: 1, Role: auth.RoleAnalyst})
+
+	h := &Handlers{}
+	if err := h.HandleCredentialsBulk(c); err != nil {
+		t.Fatalf("HandleCredentialsBulk() error = %v", err)
+	}
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/labstack/echo/v5"
 	"github.com/open-sspm/open-sspm/internal/db/gen"
 	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
@@ -33,15 +36,18 @@ func (h *Handlers) HandleDashboard(c *echo.Context) error {
 		return h.RenderError(c, err)
 	}
 
+	snap, err := h.LoadConnectorSnapshotWithStats(ctx)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	snap.Sources = scope.filterSourceStats(snap.Sources)
 	sourceNameByKind := map[string]string{}
-	if h.Registry != nil {
-		states, err := h.Registry.LoadStates(ctx, h.Q)
-		if err != nil {
-			return h.RenderError(c, err)
-		}
-		for _, st := range states {
-			sourceNameByKind[strings.ToLower(strings.TrimSpace(st.Definition.Kind()))] = strings.TrimSpace(st.SourceName)
-		}
+	for _, source := range snap.Sources {
+		sourceNameByKind[strings.ToLower(source.Kind)] = source.SourceName
 	}
 
 	rulesets, err := h.Q.ListRulesets(ctx)
@@ -127,11 +133,64 @@ func (h *Handlers) HandleDashboard(c *echo.Context) error {
 		AppCount:          appCount,
 		ConnectedAppCount: connectedAppCount,
 		FrameworkPosture:  frameworkPosture,
+		Connectors:        dashboardConnectorItems(snap.Sources, time.Now()),
 	}
 
 	return h.RenderComponent(c, views.DashboardPage(data))
 }
 
+// dashboardConnectorItems lists configured sources first, then the unconfigured ones.
+func dashboardConnectorItems(sources []ConnectorSourceStats, now time.Time) []viewmodels.DashboardConnectorItem {
+	items := make([]viewmodels.DashboardConnectorItem, 0, len(sources))
+	var unconfigured []viewmodels.DashboardConnectorItem
+	for _, source := range sources {
+		item := viewmodels.DashboardConnectorItem{
+			Name:             source.DisplayName,
+			SourceName:       source.SourceName,
+			Users:            "—",
+			Assets:           "—",
+			Credentials:      "—",
+			DiscoveryApps:    "—",
+			LastSuccessLabel: "—",
+		}
+		switch {
+		case !source.Configured:
+			item.StatusLabel = "Not configured"
+			item.StatusClass = badgeClassNeutral()
+			unconfigured = append(unconfigured, item)
+			continue
+		case !source.Enabled:
+			item.StatusLabel = "Disabled"
+			item.StatusClass = badgeClassNeutral()
+		case !source.LastSuccessAt.Valid:
+			item.StatusLabel = "Never synced"
+			item.StatusClass = badgeClassWarning()
+		case source.Stale:
+			item.StatusLabel = "Stale"
+			item.StatusClass = badgeClassDanger()
+		default:
+			item.StatusLabel = "Fresh"
+			item.StatusClass = badgeClassSuccess()
+		}
+		item.Users = formatOptionalCount(source.Users)
+		item.Assets = formatOptionalCount(source.Assets)
+		item.Credentials = formatOptionalCount(source.Credentials)
+		item.DiscoveryApps = formatOptionalCount(source.DiscoveryApps)
+		if source.LastSuccessAt.Valid {
+			item.LastSuccessLabel = formatAge(now, source.LastSuccessAt.Time)
+		}
+		items = append(items, item)
+	}
+	return append(items, unconfigured...)
+}
+
+func formatOptionalCount(count pgtype.Int8) string {
+	if !count.Valid {
+		return "—"
+	}
+	return strconv.FormatInt(count.Int64, 10)
+}
+
 func dashboardFrameworkBadgeLabel(name string) string {
 	name = strings.TrimSpace(name)
 	if name == "" {
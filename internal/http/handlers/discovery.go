@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"sort"
 	"strconv"
 	"strings"
@@ -57,9 +58,24 @@ func (h *Handlers) HandleDiscoveryApps(c *echo.Context) error {
 	query := strings.TrimSpace(c.QueryParam("q"))
 	managedState := normalizeDiscoveryManagedState(c.QueryParam("managed_state"))
 	riskLevel := normalizeDiscoveryRiskLevel(c.QueryParam("risk_level"))
+	category := strings.TrimSpace(c.QueryParam("category"))
 	page := parsePageParam(c)
 
 	totalCount, err := h.Q.CountSaaSAppsByFilters(ctx, gen.CountSaaSAppsByFiltersParams{
+		ConfiguredSourceKinds: configuredSourceKinds,
+		ConfiguredSourceNames: configuredSourceNames,
+		SourceKind:            selectedSourceKind,
+		SourceName:            selectedSourceName,
+		ManagedState:          managedState,
+		RiskLevel:             riskLevel,
+		Category:              category,
+		Query:                 query,
+	})
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
+	categoryCounts, err := h.Q.CountSaaSAppsGroupedByCategory(ctx, gen.CountSaaSAppsGroupedByCategoryParams{
 		ConfiguredSourceKinds: configuredSourceKinds,
 		ConfiguredSourceNames: configuredSourceNames,
 		SourceKind:            selectedSourceKind,
@@ -80,6 +96,7 @@ func (h *Handlers) HandleDiscoveryApps(c *echo.Context) error {
 		SourceName:            selectedSourceName,
 		ManagedState:          managedState,
 		RiskLevel:             riskLevel,
+		Category:              category,
 		Query:                 query,
 		PageOffset:            int32(offset),
 		PageLimit:             int32(discoveryAppsPerPage),
@@ -89,6 +106,7 @@ func (h *Handlers) HandleDiscoveryApps(c *echo.Context) error {
 	}
 
 	items := make([]viewmodels.DiscoveryAppListItem, 0, len(rows))
+	loc := h.displayLocation(c)
 	for _, row := range rows {
 		displayName := strings.TrimSpace(row.DisplayName)
 		if displayName == "" {
@@ -102,13 +120,14 @@ func (h *Handlers) HandleDiscoveryApps(c *echo.Context) error {
 			DisplayName:   displayName,
 			Domain:        domainLabel,
 			VendorName:    vendorLabel,
+			Category:      strings.TrimSpace(row.Category),
 			ManagedState:  strings.TrimSpace(row.ManagedState),
 			ManagedReason: strings.TrimSpace(row.ManagedReason),
 			RiskScore:     row.RiskScore,
 			RiskLevel:     strings.TrimSpace(row.RiskLevel),
 			Owner:         ownerLabel,
 			Actors30d:     row.Actors30d,
-			LastSeenAt:    formatProgrammaticDate(row.LastSeenAt),
+			LastSeenAt:    formatProgrammaticDate(row.LastSeenAt, loc),
 		})
 	}
 
@@ -123,6 +142,8 @@ func (h *Handlers) HandleDiscoveryApps(c *echo.Context) error {
 		Query:              query,
 		ManagedState:       managedState,
 		RiskLevel:          riskLevel,
+		Category:           category,
+		CategoryFacets:     discoveryCategoryFacets(categoryCounts, category),
 		Items:              items,
 		ShowingCount:       showingCount,
 		ShowingFrom:        showingFrom,
@@ -196,6 +217,13 @@ func (h *Handlers) HandleDiscoveryHotspots(c *echo.Context) error {
 		})
 	}
 
+	anomalyRows, err := h.Q.ListSaaSAppOAuthAnomalies(ctx, discoveryOAuthAnomaliesLimit)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	loc := h.displayLocation(c)
+	anomalies := discoveryOAuthAnomalyItems(anomalyRows, loc)
+
 	data := viewmodels.DiscoveryHotspotsViewData{
 		Layout:             layout,
 		SourceOptions:      sourceKindOptions(sourceOptions),
@@ -205,6 +233,8 @@ func (h *Handlers) HandleDiscoveryHotspots(c *echo.Context) error {
 		Items:              items,
 		HasItems:           len(items) > 0,
 		EmptyStateMsg:      "No discovery hotspots are currently above the high-risk threshold.",
+		OAuthAnomalies:     anomalies,
+		HasOAuthAnomalies:  len(anomalies) > 0,
 	}
 
 	if isHX(c) && isHXTarget(c, "discovery-hotspots-results") {
@@ -232,7 +262,7 @@ func (h *Handlers) HandleDiscoveryAppShow(c *echo.Context) error {
 		return h.RenderError(c, err)
 	}
 
-	layout, _, err := h.LayoutData(ctx, c, "Discovered SaaS App")
+	layout, snap, err := h.LayoutData(ctx, c, "Discovered SaaS App")
 	if err != nil {
 		return h.RenderError(c, err)
 	}
@@ -241,7 +271,17 @@ func (h *Handlers) HandleDiscoveryAppShow(c *echo.Context) error {
 	if err != nil {
 		return h.RenderError(c, err)
 	}
+	managedAssetHref := ""
+	managedAsset, hasManagedAsset, err := resolveDiscoveryManagedAsset(ctx, h.Q, appID, sources)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	if hasManagedAsset {
+		managedAssetHref = "/app-assets/" + strconv.FormatInt(managedAsset.ID, 10)
+	}
+
 	sourceItems := make([]viewmodels.DiscoverySourceEvidenceItem, 0, len(sources))
+	loc := h.displayLocation(c)
 	for _, source := range sources {
 		sourceItems = append(sourceItems, viewmodels.DiscoverySourceEvidenceItem{
 			SourceKind:      strings.TrimSpace(source.SourceKind),
@@ -249,7 +289,7 @@ func (h *Handlers) HandleDiscoveryAppShow(c *echo.Context) error {
 			SourceAppID:     fallbackDash(strings.TrimSpace(source.SourceAppID)),
 			SourceAppName:   fallbackDash(strings.TrimSpace(source.SourceAppName)),
 			SourceAppDomain: fallbackDash(strings.TrimSpace(source.SourceAppDomain)),
-			LastObservedAt:  formatProgrammaticDate(source.LastObservedAt),
+			LastObservedAt:  formatProgrammaticDate(source.LastObservedAt, loc),
 		})
 	}
 
@@ -278,7 +318,7 @@ func (h *Handlers) HandleDiscoveryAppShow(c *echo.Context) error {
 		}
 		eventItems = append(eventItems, viewmodels.DiscoveryEventItem{
 			SignalKind:    strings.TrimSpace(event.SignalKind),
-			ObservedAt:    formatProgrammaticDate(event.ObservedAt),
+			ObservedAt:    formatProgrammaticDate(event.ObservedAt, loc),
 			Actor:         fallbackDash(actor),
 			SourceApp:     fallbackDash(sourceApp),
 			ScopesSummary: summarizeDiscoveryScopes(event.ScopesJson),
@@ -292,14 +332,63 @@ func (h *Handlers) HandleDiscoveryAppShow(c *echo.Context) error {
 	if err != nil {
 		return h.RenderError(c, err)
 	}
-	actorItems := make([]viewmodels.DiscoveryActorItem, 0, len(actors))
-	for _, actor := range actors {
-		actorItems = append(actorItems, viewmodels.DiscoveryActorItem{
-			ActorLabel:      fallbackDash(strings.TrimSpace(actor.ActorLabel)),
-			ActorEmail:      fallbackDash(strings.TrimSpace(actor.ActorEmail)),
-			ActorExternalID: fallbackDash(strings.TrimSpace(actor.ActorExternalID)),
-			EventCount:      actor.EventCount,
-			LastObservedAt:  formatProgrammaticDate(actor.LastObservedAt),
+	actorItems, err := linkDiscoveryActors(ctx, h.Q, actors, loc)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
+	now := time.Now()
+	trendRows, err := h.Q.ListSaaSAppWeeklyActorCountsBySaaSAppID(ctx, gen.ListSaaSAppWeeklyActorCountsBySaaSAppIDParams{
+		SaasAppID:     appID,
+		ObservedSince: discoveryActorTrendSince(now),
+	})
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	signalRows, err := h.Q.ListSaaSAppSignalKindCountsBySaaSAppID(ctx, appID)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
+	bindings, err := h.Q.ListSaaSAppBindingsBySaaSAppID(ctx, appID)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	bindingItems := make([]viewmodels.DiscoveryBindingItem, 0, len(bindings))
+	for _, binding := range bindings {
+		connectorKind := NormalizeConnectorKind(binding.ConnectorKind)
+		sourceName := strings.TrimSpace(binding.ConnectorSourceName)
+		bindingSource := strings.TrimSpace(binding.BindingSource)
+		bindingItems = append(bindingItems, viewmodels.DiscoveryBindingItem{
+			ConnectorLabel: sourceDiagnosticLabel(connectorKind, sourceName),
+			BindingSource:  bindingSource,
+			Confidence:     strconv.FormatFloat(float64(binding.Confidence), 'f', 2, 32),
+			IsPrimary:      binding.IsPrimary,
+			Suggested:      bindingSource == "suggested",
+			SourceValue:    connectorKind + ":" + sourceName,
+		})
+	}
+
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	credentials, err := listDiscoveryAppCredentials(ctx, h.Q, appID, sources, scope)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	policy := h.credentialRiskPolicy()
+	credentialItems := make([]viewmodels.DiscoveryCredentialItem, 0, len(credentials))
+	for _, credential := range credentials {
+		credentialItems = append(credentialItems, viewmodels.DiscoveryCredentialItem{
+			ID:             credential.ID,
+			Href:           "/credentials/" + strconv.FormatInt(credential.ID, 10),
+			Name:           firstNonEmpty(credential.DisplayName, credential.ExternalID),
+			CredentialKind: credential.CredentialKind,
+			SourceLabel:    sourceDiagnosticLabel(credential.SourceKind, credential.SourceName),
+			Status:         fallbackDash(strings.TrimSpace(credential.Status)),
+			ExpiresAt:      formatProgrammaticDate(credential.ExpiresAtSource, loc),
+			RiskLevel:      credentialRiskLevel(credential, now, policy),
 		})
 	}
 
@@ -317,26 +406,287 @@ func (h *Handlers) HandleDiscoveryAppShow(c *echo.Context) error {
 			CanonicalKey:                 strings.TrimSpace(app.CanonicalKey),
 			PrimaryDomain:                domainLabel,
 			VendorName:                   vendorLabel,
+			Category:                     strings.TrimSpace(app.Category),
 			ManagedState:                 strings.TrimSpace(app.ManagedState),
 			ManagedReason:                strings.TrimSpace(app.ManagedReason),
 			RiskScore:                    app.RiskScore,
 			RiskLevel:                    strings.TrimSpace(app.RiskLevel),
 			SuggestedBusinessCriticality: strings.TrimSpace(app.SuggestedBusinessCriticality),
 			SuggestedDataClassification:  strings.TrimSpace(app.SuggestedDataClassification),
-			FirstSeenAt:                  formatProgrammaticDate(app.FirstSeenAt),
-			LastSeenAt:                   formatProgrammaticDate(app.LastSeenAt),
+			FirstSeenAt:                  formatProgrammaticDate(app.FirstSeenAt, loc),
+			LastSeenAt:                   formatProgrammaticDate(app.LastSeenAt, loc),
+			ManagedAssetHref:             managedAssetHref,
 		},
-		Sources:      sourceItems,
-		TopActors:    actorItems,
-		Events:       eventItems,
-		HasSources:   len(sourceItems) > 0,
-		HasTopActors: len(actorItems) > 0,
-		HasEvents:    len(eventItems) > 0,
+		Sources:        sourceItems,
+		TopActors:      actorItems,
+		ActorTrend:     buildDiscoveryActorTrend(trendRows, now),
+		SignalKinds:    buildDiscoverySignalBreakdown(signalRows, loc),
+		Events:         eventItems,
+		Bindings:       bindingItems,
+		Credentials:    credentialItems,
+		BindingSources: availableIdentitySourcePairs(snap),
+		HasSources:     len(sourceItems) > 0,
+		HasTopActors:   len(actorItems) > 0,
+		HasEvents:      len(eventItems) > 0,
+	}
+
+	anomaly, err := h.Q.GetSaaSAppOAuthAnomalyBySaaSAppID(ctx, appID)
+	switch {
+	case err == nil:
+		data.OAuthAnomaly = &viewmodels.DiscoveryOAuthAnomalyItem{
+			SaaSAppID:      anomaly.SaasAppID,
+			DisplayName:    displayName,
+			Reasons:        anomaly.Reasons,
+			ActorsInWindow: anomaly.ActorsInWindow,
+			HighRiskScopes: anomaly.HighRiskScopes,
+			FirstGrantAt:   formatProgrammaticDate(anomaly.FirstGrantAt, loc),
+			DetectedAt:     formatProgrammaticDate(anomaly.DetectedAt, loc),
+		}
+	case !errors.Is(err, pgx.ErrNoRows):
+		return h.RenderError(c, err)
 	}
 
 	return h.RenderComponent(c, views.DiscoveryAppShowPage(data))
 }
 
+// HandleDiscoveryAppBindingCreate binds a discovered app to a configured connector source.
+// Manual bindings outrank auto bindings, which never overwrite them, and record the admin who
+// made them.
+func (h *Handlers) HandleDiscoveryAppBindingCreate(c *echo.Context) error {
+	appID, err := parsePositiveInt64Param(c.Param("id"))
+	if err != nil {
+		return RenderNotFound(c)
+	}
+
+	ctx := c.Request().Context()
+	if _, err := h.Q.GetSaaSAppByID(ctx, appID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return RenderNotFound(c)
+		}
+		return h.RenderError(c, err)
+	}
+
+	connectorKind, sourceName, _ := strings.Cut(c.FormValue("source"), ":")
+	connectorKind = NormalizeConnectorKind(connectorKind)
+	sourceName = strings.TrimSpace(sourceName)
+	snap, err := h.LoadConnectorSnapshot(ctx)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	sourceName, ok := resolveDiscoveryBindingSource(availableIdentitySourcePairs(snap), scope, connectorKind, sourceName)
+	if !ok {
+		return c.String(http.StatusBadRequest, "source must be a configured connector source")
+	}
+
+	if err := h.Q.UpsertSaaSAppBinding(ctx, gen.UpsertSaaSAppBindingParams{
+		SaasAppID:           appID,
+		ConnectorKind:       connectorKind,
+		ConnectorSourceName: sourceName,
+		BindingSource:       "manual",
+		Confidence:          1,
+		IsPrimary:           false,
+		CreatedByAuthUserID: principalUserID(c),
+	}); err != nil {
+		return h.RenderError(c, err)
+	}
+	if _, err := h.Q.RecomputePrimarySaaSAppBindingsForAll(ctx); err != nil {
+		return h.RenderError(c, err)
+	}
+	setAuditDetail(c, auditDetail{
+		After: map[string]string{"connector_kind": connectorKind, "connector_source_name": sourceName, "binding_source": "manual"},
+	})
+	setFlashToast(c, viewmodels.ToastViewData{
+		Category: "success",
+		Title:    "Binding saved",
+	})
+	return c.Redirect(http.StatusSeeOther, "/discovery/apps/"+strconv.FormatInt(appID, 10))
+}
+
+// resolveDiscoveryBindingSource matches a submitted source against the configured connector
+// sources visible to the request's org and returns the configured source name.
+func resolveDiscoveryBindingSource(options []viewmodels.ProgrammaticSourceOption, scope orgScope, connectorKind, sourceName string) (string, bool) {
+	if connectorKind == "" || sourceName == "" {
+		return "", false
+	}
+	for _, option := range options {
+		if option.SourceKind == connectorKind && strings.EqualFold(option.SourceName, sourceName) && scope.AllowsSource(option.SourceKind, option.SourceName) {
+			return option.SourceName, true
+		}
+	}
+	return "", false
+}
+
+type discoveryManagedAssetQueries interface {
+	ListSaaSAppBindingsBySaaSAppID(context.Context, int64) ([]gen.SaasAppBinding, error)
+	GetAppAssetBySourceAndKindAndExternalID(context.Context, gen.GetAppAssetBySourceAndKindAndExternalIDParams) (gen.AppAsset, error)
+}
+
+// resolveDiscoveryManagedAsset finds the managed app asset that backs a bound discovered app.
+// Bindings are tried primary-first, and only source rows observed by the bound connector are
+// considered since the source app id is the asset's external id within that connector.
+func resolveDiscoveryManagedAsset(ctx context.Context, q discoveryManagedAssetQueries, appID int64, sources []gen.SaasAppSource) (gen.AppAsset, bool, error) {
+	bindings, err := q.ListSaaSAppBindingsBySaaSAppID(ctx, appID)
+	if err != nil {
+		return gen.AppAsset{}, false, fmt.Errorf("list bindings for saas app %d: %w", appID, err)
+	}
+	sort.SliceStable(bindings, func(i, j int) bool {
+		return bindings[i].IsPrimary && !bindings[j].IsPrimary
+	})
+
+	for _, binding := range bindings {
+		connectorKind := NormalizeConnectorKind(binding.ConnectorKind)
+		connectorSource := strings.TrimSpace(binding.ConnectorSourceName)
+		assetKinds := discoveryManagedAssetKinds(connectorKind)
+		if connectorSource == "" || len(assetKinds) == 0 || binding.BindingSource == "suggested" {
+			continue
+		}
+
+		for _, source := range sources {
+			if NormalizeConnectorKind(source.SourceKind) != connectorKind || !strings.EqualFold(strings.TrimSpace(source.SourceName), connectorSource) {
+				continue
+			}
+			sourceAppID := strings.TrimSpace(source.SourceAppID)
+			if sourceAppID == "" {
+				continue
+			}
+			for _, assetKind := range assetKinds {
+				asset, err := q.GetAppAssetBySourceAndKindAndExternalID(ctx, gen.GetAppAssetBySourceAndKindAndExternalIDParams{
+					SourceKind: connectorKind,
+					SourceName: connectorSource,
+					AssetKind:  assetKind,
+					ExternalID: sourceAppID,
+				})
+				if err == nil {
+					return asset, true, nil
+				}
+				if !errors.Is(err, pgx.ErrNoRows) {
+					return gen.AppAsset{}, false, fmt.Errorf("lookup managed app asset for saas app %d: %w", appID, err)
+				}
+			}
+		}
+	}
+	return gen.AppAsset{}, false, nil
+}
+
+func discoveryManagedAssetKinds(connectorKind string) []string {
+	switch NormalizeConnectorKind(connectorKind) {
+	case configstore.KindGoogleWorkspace:
+		return []string{"google_oauth_client"}
+	case configstore.KindEntra:
+		return []string{"entra_service_principal", "entra_application"}
+	default:
+		return nil
+	}
+}
+
+type discoveryAppCredentialQueries interface {
+	ListSaaSAppBindingsBySaaSAppID(context.Context, int64) ([]gen.SaasAppBinding, error)
+	ListCredentialArtifactsForAssetRefs(context.Context, gen.ListCredentialArtifactsForAssetRefsParams) ([]gen.CredentialArtifact, error)
+}
+
+// listDiscoveryAppCredentials gathers the credentials of a discovered app from every connector
+// source it is bound to. Each bound source's evidence rows name the app as that connector knows it
+// (an Entra app id, a Google OAuth client id, an Okta app id), which resolves to the asset refs its
+// credentials are stored under, so e.g. an Entra client secret and a Google OAuth grant for the same
+// app are listed together.
+func listDiscoveryAppCredentials(ctx context.Context, q discoveryAppCredentialQueries, appID int64, sources []gen.SaasAppSource, scope orgScope) ([]gen.CredentialArtifact, error) {
+	bindings, err := q.ListSaaSAppBindingsBySaaSAppID(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("list bindings for saas app %d: %w", appID, err)
+	}
+
+	type sourceCredentialRefs struct {
+		sourceKind     string
+		sourceName     string
+		refKinds       []string
+		refExternalIDs []string
+	}
+	refGroups := map[string]*sourceCredentialRefs{}
+	groupOrder := make([]string, 0, len(bindings))
+	for _, binding := range bindings {
+		connectorKind := NormalizeConnectorKind(binding.ConnectorKind)
+		connectorSource := strings.TrimSpace(binding.ConnectorSourceName)
+		assetKinds := discoveryCredentialAssetKinds(connectorKind)
+		if connectorSource == "" || len(assetKinds) == 0 || binding.BindingSource == "suggested" || !scope.AllowsSource(connectorKind, connectorSource) {
+			continue
+		}
+
+		for _, source := range sources {
+			if NormalizeConnectorKind(source.SourceKind) != connectorKind || !strings.EqualFold(strings.TrimSpace(source.SourceName), connectorSource) {
+				continue
+			}
+			sourceAppID := strings.TrimSpace(source.SourceAppID)
+			if sourceAppID == "" {
+				continue
+			}
+			for _, assetKind := range assetKinds {
+				refs := appAssetCredentialRefs(gen.AppAsset{
+					SourceKind: connectorKind,
+					SourceName: connectorSource,
+					AssetKind:  assetKind,
+					ExternalID: sourceAppID,
+				})
+				for _, ref := range refs {
+					groupKey := sourceKey(ref.SourceKind, ref.SourceName)
+					group := refGroups[groupKey]
+					if group == nil {
+						group = &sourceCredentialRefs{sourceKind: ref.SourceKind, sourceName: ref.SourceName}
+						refGroups[groupKey] = group
+						groupOrder = append(groupOrder, groupKey)
+					}
+					group.refKinds = append(group.refKinds, ref.AssetRefKind)
+					group.refExternalIDs = append(group.refExternalIDs, ref.AssetRefExternalID)
+				}
+			}
+		}
+	}
+
+	seen := map[int64]struct{}{}
+	out := make([]gen.CredentialArtifact, 0)
+	for _, groupKey := range groupOrder {
+		group := refGroups[groupKey]
+		credentials, err := q.ListCredentialArtifactsForAssetRefs(ctx, gen.ListCredentialArtifactsForAssetRefsParams{
+			SourceKind:          group.sourceKind,
+			SourceName:          group.sourceName,
+			AssetRefKinds:       group.refKinds,
+			AssetRefExternalIds: group.refExternalIDs,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list credentials for saas app %d: %w", appID, err)
+		}
+		for _, credential := range credentials {
+			if _, ok := seen[credential.ID]; ok {
+				continue
+			}
+			seen[credential.ID] = struct{}{}
+			out = append(out, credential)
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		left := strings.ToLower(strings.TrimSpace(out[i].DisplayName))
+		right := strings.ToLower(strings.TrimSpace(out[j].DisplayName))
+		if left == right {
+			return out[i].ID < out[j].ID
+		}
+		return left < right
+	})
+	return out, nil
+}
+
+// discoveryCredentialAssetKinds lists the asset kinds whose credentials belong to a discovered app
+// bound to connectorKind. Okta apps carry client secrets but are not managed-asset candidates.
+func discoveryCredentialAssetKinds(connectorKind string) []string {
+	if NormalizeConnectorKind(connectorKind) == configstore.KindOkta {
+		return []string{"okta_app"}
+	}
+	return discoveryManagedAssetKinds(connectorKind)
+}
+
 func (h *Handlers) recomputeDiscoveryPosture(ctx context.Context) error {
 	rows, err := h.Q.ListSaaSAppPostureInputs(ctx)
 	if err != nil {
@@ -448,6 +798,10 @@ func (h *Handlers) recomputeDiscoveryPosture(ctx context.Context) error {
 		}
 	}
 
+	if err := h.detectDiscoveryOAuthAnomalies(ctx, now); err != nil {
+		return err
+	}
+
 	if err := h.refreshDiscoveryMetrics(ctx, configuredSourceKinds, configuredSourceNames); err != nil {
 		return err
 	}
@@ -758,6 +1112,26 @@ func normalizeDiscoveryManagedState(raw string) string {
 	}
 }
 
+// discoveryCategoryFacets turns per-category app counts into facets. A selected category with no
+// matching apps is kept with a zero count so the active filter stays visible and can be cleared.
+func discoveryCategoryFacets(rows []gen.CountSaaSAppsGroupedByCategoryRow, selectedCategory string) []viewmodels.DiscoveryCategoryFacet {
+	facets := make([]viewmodels.DiscoveryCategoryFacet, 0, len(rows)+1)
+	selectedSeen := selectedCategory == ""
+	for _, row := range rows {
+		category := strings.TrimSpace(row.Category)
+		if category == "" {
+			continue
+		}
+		selected := category == selectedCategory
+		selectedSeen = selectedSeen || selected
+		facets = append(facets, viewmodels.DiscoveryCategoryFacet{Category: category, Count: row.AppCount, Selected: selected})
+	}
+	if !selectedSeen {
+		facets = append(facets, viewmodels.DiscoveryCategoryFacet{Category: selectedCategory, Selected: true})
+	}
+	return facets
+}
+
 func normalizeDiscoveryRiskLevel(raw string) string {
 	switch strings.ToLower(strings.TrimSpace(raw)) {
 	case "low":
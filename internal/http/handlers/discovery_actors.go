@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+	"github.com/open-sspm/open-sspm/internal/matching"
+)
+
+// discoveryActorIdentityQueries resolves discovery actors to identities in one round trip
+// per match strategy rather than one per actor.
+type discoveryActorIdentityQueries interface {
+	ListIdentitiesBySourceAndExternalIDs(ctx context.Context, arg gen.ListIdentitiesBySourceAndExternalIDsParams) ([]gen.ListIdentitiesBySourceAndExternalIDsRow, error)
+	ListPreferredIdentitiesByPrimaryEmails(ctx context.Context, primaryEmails []string) ([]gen.ListPreferredIdentitiesByPrimaryEmailsRow, error)
+}
+
+type discoveryActorIdentity struct {
+	ID    int64
+	Label string
+}
+
+func discoveryActorSourceKey(sourceKind, sourceName, externalID string) string {
+	return strings.ToLower(strings.TrimSpace(sourceKind)) + "|" + strings.ToLower(strings.TrimSpace(sourceName)) + "|" + strings.ToLower(strings.TrimSpace(externalID))
+}
+
+func discoveryActorIdentityLabel(displayName, primaryEmail string, id int64) string {
+	if label := strings.TrimSpace(displayName); label != "" {
+		return label
+	}
+	if label := strings.TrimSpace(primaryEmail); label != "" {
+		return label
+	}
+	return "Identity " + strconv.FormatInt(id, 10)
+}
+
+// linkDiscoveryActors builds the actor rows for a discovered app, linking each actor to a
+// known identity. Candidates are tried in the same order as identityLinkResolver: an
+// email-shaped external id, the source account, the actor email, then an email-shaped label.
+func linkDiscoveryActors(ctx context.Context, q discoveryActorIdentityQueries, actors []gen.ListTopActorsForSaaSAppByIDRow, loc *time.Location) ([]viewmodels.DiscoveryActorItem, error) {
+	emailSet := map[string]struct{}{}
+	sourceSet := map[string]struct{}{}
+	var params gen.ListIdentitiesBySourceAndExternalIDsParams
+	for _, actor := range actors {
+		for _, raw := range []string{actor.ActorExternalID, actor.ActorEmail, actor.ActorLabel} {
+			if email := matching.NormalizeEmail(raw); email != "" {
+				emailSet[email] = struct{}{}
+			}
+		}
+		sourceKind := strings.TrimSpace(actor.SourceKind)
+		sourceName := strings.TrimSpace(actor.SourceName)
+		externalID := strings.TrimSpace(actor.ActorExternalID)
+		if sourceKind == "" || sourceName == "" || externalID == "" {
+			continue
+		}
+		key := discoveryActorSourceKey(sourceKind, sourceName, externalID)
+		if _, ok := sourceSet[key]; ok {
+			continue
+		}
+		sourceSet[key] = struct{}{}
+		params.SourceKinds = append(params.SourceKinds, sourceKind)
+		params.SourceNames = append(params.SourceNames, sourceName)
+		params.ExternalIds = append(params.ExternalIds, externalID)
+	}
+
+	byEmail := make(map[string]discoveryActorIdentity, len(emailSet))
+	if len(emailSet) > 0 {
+		emails := make([]string, 0, len(emailSet))
+		for email := range emailSet {
+			emails = append(emails, email)
+		}
+		rows, err := q.ListPreferredIdentitiesByPrimaryEmails(ctx, emails)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			byEmail[matching.NormalizeEmail(row.Email)] = discoveryActorIdentity{
+				ID:    row.IdentityID,
+				Label: discoveryActorIdentityLabel(row.DisplayName, row.PrimaryEmail, row.IdentityID),
+			}
+		}
+	}
+
+	bySource := make(map[string]discoveryActorIdentity, len(params.ExternalIds))
+	if len(params.ExternalIds) > 0 {
+		rows, err := q.ListIdentitiesBySourceAndExternalIDs(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			bySource[discoveryActorSourceKey(row.SourceKind, row.SourceName, row.ExternalID)] = discoveryActorIdentity{
+				ID:    row.IdentityID,
+				Label: discoveryActorIdentityLabel(row.DisplayName, row.PrimaryEmail, row.IdentityID),
+			}
+		}
+	}
+
+	lookupEmail := func(raw string) (discoveryActorIdentity, bool) {
+		email := matching.NormalizeEmail(raw)
+		if email == "" {
+			return discoveryActorIdentity{}, false
+		}
+		identity, ok := byEmail[email]
+		return identity, ok
+	}
+
+	items := make([]viewmodels.DiscoveryActorItem, 0, len(actors))
+	for _, actor := range actors {
+		identity, ok := lookupEmail(actor.ActorExternalID)
+		if !ok {
+			identity, ok = bySource[discoveryActorSourceKey(actor.SourceKind, actor.SourceName, actor.ActorExternalID)]
+		}
+		if !ok {
+			identity, ok = lookupEmail(actor.ActorEmail)
+		}
+		if !ok {
+			identity, ok = lookupEmail(actor.ActorLabel)
+		}
+
+		item := viewmodels.DiscoveryActorItem{
+			ActorLabel:      fallbackDash(strings.TrimSpace(actor.ActorLabel)),
+			ActorEmail:      fallbackDash(strings.TrimSpace(actor.ActorEmail)),
+			ActorExternalID: fallbackDash(strings.TrimSpace(actor.ActorExternalID)),
+			EventCount:      actor.EventCount,
+			FirstObservedAt: formatProgrammaticDate(actor.FirstObservedAt, loc),
+			LastObservedAt:  formatProgrammaticDate(actor.LastObservedAt, loc),
+		}
+		if ok {
+			item.IdentityHref = "/identities/" + strconv.FormatInt(identity.ID, 10)
+			item.IdentityLabel = identity.Label
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
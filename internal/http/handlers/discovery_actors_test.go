@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+type fakeDiscoveryActorIdentityQueries struct {
+	identitiesByEmail map[string]gen.ListPreferredIdentitiesByPrimaryEmailsRow
+	emailCalls        int
+	sourceCalls       int
+}
+
+func (f *fakeDiscoveryActorIdentityQueries) ListIdentitiesBySourceAndExternalIDs(_ context.Context, arg gen.ListIdentitiesBySourceAndExternalIDsParams) ([]gen.ListIdentitiesBySourceAndExternalIDsRow, error) {
+	f.sourceCalls++
+	return nil, nil
+}
+
+func (f *fakeDiscoveryActorIdentityQueries) ListPreferredIdentitiesByPrimaryEmails(_ context.Context, primaryEmails []string) ([]gen.ListPreferredIdentitiesByPrimaryEmailsRow, error) {
+	f.emailCalls++
+	var rows []gen.ListPreferredIdentitiesByPrimaryEmailsRow
+	for _, email := range primaryEmails {
+		if row, ok := f.identitiesByEmail[email]; ok {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func TestLinkDiscoveryActors(t *testing.T) {
+	t.Parallel()
+
+	first := pgtype.Timestamptz{Time: time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC), Valid: true}
+	last := pgtype.Timestamptz{Time: time.Date(2026, time.March, 5, 9, 0, 0, 0, time.UTC), Valid: true}
+	q := &fakeDiscoveryActorIdentityQueries{
+		identitiesByEmail: map[string]gen.ListPreferredIdentitiesByPrimaryEmailsRow{
+			"alice@example.com": {Email: "alice@example.com", IdentityID: 7, DisplayName: "Alice Doe", PrimaryEmail: "alice@example.com"},
+		},
+	}
+	actors := []gen.ListTopActorsForSaaSAppByIDRow{
+		{ActorLabel: "Alice", ActorEmail: "Alice@Example.com", ActorExternalID: "00u1", SourceKind: "okta", SourceName: "acme", EventCount: 4, FirstObservedAt: first, LastObservedAt: last},
+		{ActorLabel: "Mallory", ActorEmail: "mallory@unknown.test", ActorExternalID: "00u2", SourceKind: "okta", SourceName: "acme", EventCount: 1, FirstObservedAt: first, LastObservedAt: first},
+	}
+
+	items, err := linkDiscoveryActors(context.Background(), q, actors, time.UTC)
+	if err != nil {
+		t.Fatalf("linkDiscoveryActors() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("len(items) = %d, want 2", len(items))
+	}
+	if items[0].IdentityHref != "/identities/7" || items[0].IdentityLabel != "Alice Doe" {
+		t.Fatalf("matched actor link = %q (%q), want /identities/7 (Alice Doe)", items[0].IdentityHref, items[0].IdentityLabel)
+	}
+	if items[0].FirstObservedAt != formatProgrammaticDate(first, time.UTC) || items[0].LastObservedAt != formatProgrammaticDate(last, time.UTC) {
+		t.Fatalf("matched actor seen = %q..%q", items[0].FirstObservedAt, items[0].LastObservedAt)
+	}
+	if items[1].IdentityHref != "" || items[1].IdentityLabel != "" {
+		t.Fatalf("unknown actor linked to %q (%q), want unlinked", items[1].IdentityHref, items[1].IdentityLabel)
+	}
+	if q.emailCalls != 1 || q.sourceCalls != 1 {
+		t.Fatalf("lookups: email=%d source=%d, want one batched call each", q.emailCalls, q.sourceCalls)
+	}
+}
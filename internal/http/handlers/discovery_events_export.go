@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/discovery"
+)
+
+// discoveryEventExportPageSize is how many events each keyset page loads; the export never
+// holds more than one page in memory.
+const discoveryEventExportPageSize = 1000
+
+// discoveryEventExportFilter narrows the event export. Empty strings and unset timestamps
+// match everything; ObservedTo is exclusive.
+type discoveryEventExportFilter struct {
+	SourceKind   string
+	SourceName   string
+	SignalKind   string
+	ObservedFrom pgtype.Timestamptz
+	ObservedTo   pgtype.Timestamptz
+}
+
+// discoveryEventExportRow is one raw discovery event with its normalized fields. Field order
+// matches the CSV columns.
+type discoveryEventExportRow struct {
+	ID                  int64           `json:"id"`
+	SaaSAppID           int64           `json:"saas_app_id"`
+	SaaSAppCanonicalKey string          `json:"saas_app_canonical_key"`
+	SaaSAppName         string          `json:"saas_app_name"`
+	SourceKind          string          `json:"source_kind"`
+	SourceName          string          `json:"source_name"`
+	SignalKind          string          `json:"signal_kind"`
+	EventExternalID     string          `json:"event_external_id"`
+	SourceAppID         string          `json:"source_app_id"`
+	SourceAppName       string          `json:"source_app_name"`
+	SourceAppDomain     string          `json:"source_app_domain"`
+	ActorExternalID     string          `json:"actor_external_id"`
+	ActorEmail          string          `json:"actor_email"`
+	ActorDisplayName    string          `json:"actor_display_name"`
+	ObservedAt          string          `json:"observed_at"`
+	ExpiredAt           string          `json:"expired_at"`
+	Scopes              json.RawMessage `json:"scopes"`
+	RawJSON             json.RawMessage `json:"raw_json"`
+}
+
+var discoveryEventExportColumns = []string{
+	"id",
+	"saas_app_id",
+	"saas_app_canonical_key",
+	"saas_app_name",
+	"source_kind",
+	"source_name",
+	"signal_kind",
+	"event_external_id",
+	"source_app_id",
+	"source_app_name",
+	"source_app_domain",
+	"actor_external_id",
+	"actor_email",
+	"actor_display_name",
+	"observed_at",
+	"expired_at",
+	"scopes",
+	"raw_json",
+}
+
+func (r discoveryEventExportRow) csvRecord() []string {
+	return []string{
+		strconv.FormatInt(r.ID, 10),
+		strconv.FormatInt(r.SaaSAppID, 10),
+		r.SaaSAppCanonicalKey,
+		r.SaaSAppName,
+		r.SourceKind,
+		r.SourceName,
+		r.SignalKind,
+		r.EventExternalID,
+		r.SourceAppID,
+		r.SourceAppName,
+		r.SourceAppDomain,
+		r.ActorExternalID,
+		r.ActorEmail,
+		r.ActorDisplayName,
+		r.ObservedAt,
+		r.ExpiredAt,
+		string(r.Scopes),
+		string(r.RawJSON),
+	}
+}
+
+// HandleDiscoveryEventsExport streams every raw discovery event (saas_app_events) as NDJSON
+// (?format=ndjson) or CSV (the default), filtered by ?source_kind, ?source_name, ?signal_kind,
+// and an ?observed_from/?observed_to range (RFC 3339 or YYYY-MM-DD; a date "to" includes that
+// day). Events are read in id-ordered keyset pages, each its own short query, so the export
+// holds neither a transaction nor more than one page of rows.
+func (h *Handlers) HandleDiscoveryEventsExport(c *echo.Context) error {
+	format := strings.ToLower(strings.TrimSpace(c.QueryParam("format")))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "ndjson" {
+		return c.String(http.StatusBadRequest, "format must be csv or ndjson")
+	}
+	filter, err := parseDiscoveryEventExportFilter(c.QueryParams())
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	ctx := c.Request().Context()
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
+	// Headers go out with the first page, so a failing first query still renders an error page.
+	res := c.Response()
+	var csvWriter *csv.Writer
+	started := false
+	start := func() error {
+		started = true
+		filename := "discovery-events-" + time.Now().UTC().Format("20060102") + "." + format
+		res.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		if format == "ndjson" {
+			res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+			res.WriteHeader(http.StatusOK)
+			return nil
+		}
+		res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		res.WriteHeader(http.StatusOK)
+		csvWriter = csv.NewWriter(res)
+		return csvWriter.Write(discoveryEventExportColumns)
+	}
+	ndjson := json.NewEncoder(res)
+
+	fetch := func(ctx context.Context, afterID int64) ([]gen.ListSaaSAppEventsExportPageRow, error) {
+		rows, err := h.Q.ListSaaSAppEventsExportPage(ctx, filter.pageParams(afterID, discoveryEventExportPageSize))
+		if err != nil {
+			return nil, err
+		}
+		if !started {
+			if err := start(); err != nil {
+				return nil, err
+			}
+		}
+		return rows, nil
+	}
+	emit := func(row gen.ListSaaSAppEventsExportPageRow) error {
+		if !scope.AllowsSource(row.SourceKind, row.SourceName) {
+			return nil
+		}
+		if csvWriter != nil {
+			return csvWriter.Write(discoveryEventExportRowFrom(row).csvRecord())
+		}
+		return ndjson.Encode(discoveryEventExportRowFrom(row))
+	}
+	flush := func() error {
+		if csvWriter != nil {
+			csvWriter.Flush()
+			if err := csvWriter.Error(); err != nil {
+				return err
+			}
+		}
+		_ = http.NewResponseController(res).Flush()
+		return nil
+	}
+
+	if err := streamDiscoveryEvents(ctx, discoveryEventExportPageSize, fetch, emit, flush); err != nil {
+		if !started {
+			return h.RenderError(c, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// streamDiscoveryEvents pages through events after the last emitted id until a short page,
+// emitting each row and flushing after every page.
+func streamDiscoveryEvents(
+	ctx context.Context,
+	pageSize int,
+	fetch func(ctx context.Context, afterID int64) ([]gen.ListSaaSAppEventsExportPageRow, error),
+	emit func(gen.ListSaaSAppEventsExportPageRow) error,
+	flush func() error,
+) error {
+	var afterID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rows, err := fetch(ctx, afterID)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := emit(row); err != nil {
+				return err
+			}
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+		if len(rows) < pageSize {
+			return nil
+		}
+		afterID = rows[len(rows)-1].ID
+	}
+}
+
+func parseDiscoveryEventExportFilter(values url.Values) (discoveryEventExportFilter, error) {
+	filter := discoveryEventExportFilter{
+		SourceKind: NormalizeConnectorKind(values.Get("source_kind")),
+		SourceName: strings.TrimSpace(values.Get("source_name")),
+		SignalKind: strings.ToLower(strings.TrimSpace(values.Get("signal_kind"))),
+	}
+	switch filter.SignalKind {
+	case "", discovery.SignalKindIDPSSO, discovery.SignalKindOAuth:
+	default:
+		return discoveryEventExportFilter{}, fmt.Errorf("signal_kind must be %s or %s", discovery.SignalKindIDPSSO, discovery.SignalKindOAuth)
+	}
+
+	from, _, err := parseDiscoveryEventExportTime(values.Get("observed_from"))
+	if err != nil {
+		return discoveryEventExportFilter{}, fmt.Errorf("observed_from: %w", err)
+	}
+	to, dateOnly, err := parseDiscoveryEventExportTime(values.Get("observed_to"))
+	if err != nil {
+		return discoveryEventExportFilter{}, fmt.Errorf("observed_to: %w", err)
+	}
+	if !to.IsZero() && dateOnly {
+		to = to.AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return discoveryEventExportFilter{}, errors.New("observed_from must be before observed_to")
+	}
+	if !from.IsZero() {
+		filter.ObservedFrom = pgtype.Timestamptz{Time: from, Valid: true}
+	}
+	if !to.IsZero() {
+		filter.ObservedTo = pgtype.Timestamptz{Time: to, Valid: true}
+	}
+	return filter, nil
+}
+
+// parseDiscoveryEventExportTime parses an RFC 3339 timestamp or a YYYY-MM-DD date (UTC
+// midnight), reporting which it was. Blank input is the zero time.
+func parseDiscoveryEventExportTime(raw string) (time.Time, bool, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, false, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t.UTC(), false, nil
+	}
+	t, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return time.Time{}, false, errors.New("must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+	}
+	return t, true, nil
+}
+
+func (f discoveryEventExportFilter) pageParams(afterID int64, pageSize int) gen.ListSaaSAppEventsExportPageParams {
+	return gen.ListSaaSAppEventsExportPageParams{
+		AfterID:      afterID,
+		SourceKind:   f.SourceKind,
+		SourceName:   f.SourceName,
+		SignalKind:   f.SignalKind,
+		ObservedFrom: f.ObservedFrom,
+		ObservedTo:   f.ObservedTo,
+		PageLimit:    int32(pageSize),
+	}
+}
+
+func discoveryEventExportRowFrom(row gen.ListSaaSAppEventsExportPageRow) discoveryEventExportRow {
+	return discoveryEventExportRow{
+		ID:                  row.ID,
+		SaaSAppID:           row.SaasAppID,
+		SaaSAppCanonicalKey: strings.TrimSpace(row.SaasAppCanonicalKey),
+		SaaSAppName:         strings.TrimSpace(row.SaasAppDisplayName),
+		SourceKind:          strings.TrimSpace(row.SourceKind),
+		SourceName:          strings.TrimSpace(row.SourceName),
+		SignalKind:          strings.TrimSpace(row.SignalKind),
+		EventExternalID:     strings.TrimSpace(row.EventExternalID),
+		SourceAppID:         strings.TrimSpace(row.SourceAppID),
+		SourceAppName:       strings.TrimSpace(row.SourceAppName),
+		SourceAppDomain:     strings.TrimSpace(row.SourceAppDomain),
+		ActorExternalID:     strings.TrimSpace(row.ActorExternalID),
+		ActorEmail:          strings.TrimSpace(row.ActorEmail),
+		ActorDisplayName:    strings.TrimSpace(row.ActorDisplayName),
+		ObservedAt:          discoveryReportTimestamp(row.ObservedAt),
+		ExpiredAt:           discoveryReportTimestamp(row.ExpiredAt),
+		Scopes:              discoveryEventExportJSON(row.ScopesJson, "[]"),
+		RawJSON:             discoveryEventExportJSON(row.RawJson, "{}"),
+	}
+}
+
+// discoveryEventExportJSON returns raw as-is when it is valid JSON, else fallback, so one bad
+// payload cannot corrupt an NDJSON line.
+func discoveryEventExportJSON(raw []byte, fallback string) json.RawMessage {
+	if len(raw) == 0 || !json.Valid(raw) {
+		return json.RawMessage(fallback)
+	}
+	return json.RawMessage(raw)
+}
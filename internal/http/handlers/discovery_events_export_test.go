@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestParseDiscoveryEventExportFilter(t *testing.T) {
+	t.Parallel()
+
+	filter, err := parseDiscoveryEventExportFilter(url.Values{
+		"source_kind":   {" AWS "},
+		"source_name":   {" acme "},
+		"signal_kind":   {"OAUTH_GRANT"},
+		"observed_from": {"2026-03-01T09:00:00-05:00"},
+		"observed_to":   {"2026-03-31"},
+	})
+	if err != nil {
+		t.Fatalf("parseDiscoveryEventExportFilter() error = %v", err)
+	}
+	params := filter.pageParams(42, 100)
+	if params.SourceKind != "aws_identity_center" || params.SourceName != "acme" || params.SignalKind != "oauth_grant" {
+		t.Fatalf("params = %+v", params)
+	}
+	if params.AfterID != 42 || params.PageLimit != 100 {
+		t.Fatalf("page = after %d limit %d, want after 42 limit 100", params.AfterID, params.PageLimit)
+	}
+	if !params.ObservedFrom.Valid || !params.ObservedFrom.Time.Equal(time.Date(2026, time.March, 1, 14, 0, 0, 0, time.UTC)) {
+		t.Fatalf("observed_from = %+v", params.ObservedFrom)
+	}
+	// A date-only upper bound includes the whole day.
+	if !params.ObservedTo.Valid || !params.ObservedTo.Time.Equal(time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("observed_to = %+v", params.ObservedTo)
+	}
+
+	empty, err := parseDiscoveryEventExportFilter(url.Values{})
+	if err != nil {
+		t.Fatalf("parseDiscoveryEventExportFilter(empty) error = %v", err)
+	}
+	if empty.SourceKind != "" || empty.SignalKind != "" || empty.ObservedFrom.Valid || empty.ObservedTo.Valid {
+		t.Fatalf("empty filter = %+v, want no predicates", empty)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		values url.Values
+		want   string
+	}{
+		{name: "signal kind", values: url.Values{"signal_kind": {"login"}}, want: "signal_kind must be"},
+		{name: "bad time", values: url.Values{"observed_from": {"yesterday"}}, want: "observed_from: "},
+		{name: "empty range", values: url.Values{"observed_from": {"2026-03-02"}, "observed_to": {"2026-03-01T23:59:59Z"}}, want: "must be before"},
+	} {
+		if _, err := parseDiscoveryEventExportFilter(tc.values); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: error = %v, want it to contain %q", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestStreamDiscoveryEventsPagesUntilShortPage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		total     int64
+		wantCalls []int64
+	}{
+		{name: "short last page", total: 5, wantCalls: []int64{0, 2, 4}},
+		{name: "exact multiple", total: 4, wantCalls: []int64{0, 2, 4}},
+		{name: "no events", total: 0, wantCalls: []int64{0}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls, emitted []int64
+			flushes := 0
+			fetch := func(_ context.Context, afterID int64) ([]gen.ListSaaSAppEventsExportPageRow, error) {
+				calls = append(calls, afterID)
+				var rows []gen.ListSaaSAppEventsExportPageRow
+				for id := afterID + 1; id <= tc.total && len(rows) < 2; id++ {
+					rows = append(rows, gen.ListSaaSAppEventsExportPageRow{ID: id})
+				}
+				return rows, nil
+			}
+			emit := func(row gen.ListSaaSAppEventsExportPageRow) error {
+				emitted = append(emitted, row.ID)
+				return nil
+			}
+			flush := func() error {
+				flushes++
+				return nil
+			}
+
+			if err := streamDiscoveryEvents(context.Background(), 2, fetch, emit, flush); err != nil {
+				t.Fatalf("streamDiscoveryEvents() error = %v", err)
+			}
+			if !slices.Equal(calls, tc.wantCalls) {
+				t.Fatalf("fetched after ids %v, want %v", calls, tc.wantCalls)
+			}
+			if int64(len(emitted)) != tc.total || flushes != len(tc.wantCalls) {
+				t.Fatalf("emitted %v with %d flushes", emitted, flushes)
+			}
+		})
+	}
+}
+
+func TestStreamDiscoveryEventsStopsOnError(t *testing.T) {
+	t.Parallel()
+
+	fullPage := func(_ context.Context, afterID int64) ([]gen.ListSaaSAppEventsExportPageRow, error) {
+		return []gen.ListSaaSAppEventsExportPageRow{{ID: afterID + 1}, {ID: afterID + 2}}, nil
+	}
+	noFlush := func() error { return nil }
+
+	// A failing write (client gone) ends the stream instead of paging forever.
+	writeErr := errors.New("broken pipe")
+	emitted := 0
+	err := streamDiscoveryEvents(context.Background(), 2, fullPage, func(gen.ListSaaSAppEventsExportPageRow) error {
+		emitted++
+		if emitted == 3 {
+			return writeErr
+		}
+		return nil
+	}, noFlush)
+	if !errors.Is(err, writeErr) || emitted != 3 {
+		t.Fatalf("streamDiscoveryEvents() = %v after %d rows, want %v after 3", err, emitted, writeErr)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pages := 0
+	err = streamDiscoveryEvents(ctx, 2, func(ctx context.Context, afterID int64) ([]gen.ListSaaSAppEventsExportPageRow, error) {
+		pages++
+		cancel()
+		return fullPage(ctx, afterID)
+	}, func(gen.ListSaaSAppEventsExportPageRow) error { return nil }, noFlush)
+	if !errors.Is(err, context.Canceled) || pages != 1 {
+		t.Fatalf("streamDiscoveryEvents() = %v after %d pages, want context.Canceled after 1", err, pages)
+	}
+}
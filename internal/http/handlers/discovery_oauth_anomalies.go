@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/discovery"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+)
+
+const discoveryOAuthAnomaliesLimit = 50
+
+func (h *Handlers) discoveryOAuthAnomalyThresholds() discovery.OAuthAnomalyThresholds {
+	return discovery.OAuthAnomalyThresholds{
+		Window:       h.Cfg.DiscoveryOAuthAnomalyWindow,
+		MinActors:    int64(h.Cfg.DiscoveryOAuthAnomalyMinActors),
+		NewAppMaxAge: h.Cfg.DiscoveryOAuthAnomalyNewAppMaxAge,
+	}.Normalized()
+}
+
+// detectDiscoveryOAuthAnomalies persists a finding for every recently-seen app whose early OAuth
+// grants trip the anomaly thresholds. Findings are never cleared here; an app that stops matching
+// (for example because it aged past NewAppMaxAge) keeps its original finding.
+func (h *Handlers) detectDiscoveryOAuthAnomalies(ctx context.Context, now time.Time) error {
+	thresholds := h.discoveryOAuthAnomalyThresholds()
+	rows, err := h.Q.ListSaaSAppOAuthGrantWindows(ctx, gen.ListSaaSAppOAuthGrantWindowsParams{
+		NewAppMaxAgeSeconds: int32(thresholds.NewAppMaxAge / time.Second),
+		WindowSeconds:       int32(thresholds.Window / time.Second),
+	})
+	if err != nil {
+		return fmt.Errorf("list oauth grant windows: %w", err)
+	}
+	for _, row := range rows {
+		anomaly, flagged := discovery.EvaluateOAuthGrantAnomaly(discovery.OAuthGrantWindow{
+			FirstSeenAt:      row.FirstSeenAt.Time,
+			FirstGrantAt:     row.FirstGrantAt.Time,
+			ActorsInWindow:   row.ActorsInWindow,
+			FirstSightScopes: row.FirstSightScopes,
+		}, thresholds, now)
+		if !flagged {
+			continue
+		}
+		if err := h.Q.UpsertSaaSAppOAuthAnomaly(ctx, gen.UpsertSaaSAppOAuthAnomalyParams{
+			SaasAppID:      row.SaasAppID,
+			Reasons:        anomaly.Reasons,
+			FirstGrantAt:   row.FirstGrantAt,
+			ActorsInWindow: row.ActorsInWindow,
+			HighRiskScopes: anomaly.HighRiskScopes,
+		}); err != nil {
+			return fmt.Errorf("record oauth anomaly for app %d: %w", row.SaasAppID, err)
+		}
+	}
+	return nil
+}
+
+func discoveryOAuthAnomalyItems(rows []gen.ListSaaSAppOAuthAnomaliesRow, loc *time.Location) []viewmodels.DiscoveryOAuthAnomalyItem {
+	items := make([]viewmodels.DiscoveryOAuthAnomalyItem, 0, len(rows))
+	for _, row := range rows {
+		displayName := strings.TrimSpace(row.DisplayName)
+		if displayName == "" {
+			displayName = strings.TrimSpace(row.CanonicalKey)
+		}
+		domainLabel, _ := discoveryAppSecondaryLabels(displayName, row.PrimaryDomain, row.VendorName)
+		items = append(items, viewmodels.DiscoveryOAuthAnomalyItem{
+			SaaSAppID:      row.SaasAppID,
+			DisplayName:    displayName,
+			Domain:         domainLabel,
+			Reasons:        row.Reasons,
+			ActorsInWindow: row.ActorsInWindow,
+			HighRiskScopes: row.HighRiskScopes,
+			FirstGrantAt:   formatProgrammaticDate(row.FirstGrantAt, loc),
+			DetectedAt:     formatProgrammaticDate(row.DetectedAt, loc),
+		})
+	}
+	return items
+}
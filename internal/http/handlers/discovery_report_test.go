@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestDiscoveryInventoryReportRowsSanctionedFromBindings(t *testing.T) {
+	t.Parallel()
+
+	seen := pgtype.Timestamptz{Time: time.Date(2026, time.March, 1, 9, 30, 0, 0, time.FixedZone("EST", -5*3600)), Valid: true}
+	rows := []gen.ListSaaSAppInventoryReportRow{
+		{ID: 1, CanonicalKey: "github.com", DisplayName: "GitHub", BindingConnectorKind: "github", BindingConnectorSourceName: "acme", UserCount: 12, FirstSeenAt: seen},
+		{ID: 2, CanonicalKey: "datadoghq.com", BindingConnectorKind: "datadog", BindingConnectorSourceName: "acme"},
+		{ID: 3, CanonicalKey: "notion.so", DisplayName: "Notion"},
+		{ID: 4, CanonicalKey: "github.com/other", DisplayName: "GitHub (other org)", BindingConnectorKind: "github", BindingConnectorSourceName: "other-org"},
+	}
+	runtimes := map[string]discoveryConnectorRuntime{
+		"github":  {SourceName: "Acme", Configured: true, Enabled: true},
+		"datadog": {SourceName: "acme", Configured: true, Enabled: false},
+	}
+
+	got := discoveryInventoryReportRows(rows, runtimes)
+	want := map[int64]bool{1: true, 2: false, 3: false, 4: false}
+	for _, row := range got {
+		if row.Sanctioned != want[row.ID] {
+			t.Fatalf("app %d sanctioned = %v, want %v", row.ID, row.Sanctioned, want[row.ID])
+		}
+	}
+	if got[1].Name != "datadoghq.com" {
+		t.Fatalf("name fallback = %q, want canonical key", got[1].Name)
+	}
+	if got[0].FirstSeenAt != "2026-03-01T14:30:00Z" || got[0].LastSeenAt != "" {
+		t.Fatalf("timestamps = %q / %q", got[0].FirstSeenAt, got[0].LastSeenAt)
+	}
+	if record := got[0].csvRecord(); len(record) != len(discoveryInventoryReportColumns) || record[4] != "true" || record[9] != "12" {
+		t.Fatalf("csv record = %v", record)
+	}
+}
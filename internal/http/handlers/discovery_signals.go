@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/discovery"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+	"github.com/open-sspm/open-sspm/internal/http/views"
+)
+
+// discoverySignalKindOrder lists the signal kinds every app's breakdown shows, even at zero, so
+// a missing OAuth grant count reads as "none" rather than "unknown".
+var discoverySignalKindOrder = []string{discovery.SignalKindIDPSSO, discovery.SignalKindOAuth}
+
+// buildDiscoverySignalBreakdown turns the per-signal-kind counts into breakdown rows: the known
+// kinds first in a fixed order, then any other kinds in query order.
+func buildDiscoverySignalBreakdown(rows []gen.ListSaaSAppSignalKindCountsBySaaSAppIDRow, loc *time.Location) []viewmodels.DiscoverySignalKindItem {
+	byKind := make(map[string]gen.ListSaaSAppSignalKindCountsBySaaSAppIDRow, len(rows))
+	kinds := append([]string(nil), discoverySignalKindOrder...)
+	var total int64
+	for _, row := range rows {
+		kind := strings.ToLower(strings.TrimSpace(row.SignalKind))
+		if kind == "" {
+			continue
+		}
+		if !slices.Contains(discoverySignalKindOrder, kind) {
+			kinds = append(kinds, kind)
+		}
+		byKind[kind] = row
+		total += row.EventCount
+	}
+
+	items := make([]viewmodels.DiscoverySignalKindItem, 0, len(kinds))
+	for _, kind := range kinds {
+		row := byKind[kind]
+		item := viewmodels.DiscoverySignalKindItem{
+			SignalKind:     kind,
+			Label:          views.HumanizeDiscoverySignalKind(kind),
+			EventCount:     row.EventCount,
+			ActorCount:     row.ActorCount,
+			LastObservedAt: formatProgrammaticDate(row.LastObservedAt, loc),
+		}
+		if total > 0 {
+			item.SharePercent = int(row.EventCount * 100 / total)
+		}
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestBuildDiscoverySignalBreakdownMixedSignals(t *testing.T) {
+	t.Parallel()
+
+	seen := pgtype.Timestamptz{Time: time.Date(2026, time.October, 14, 9, 0, 0, 0, time.UTC), Valid: true}
+	rows := []gen.ListSaaSAppSignalKindCountsBySaaSAppIDRow{
+		{SignalKind: "oauth_grant", EventCount: 4, ActorCount: 3, LastObservedAt: seen},
+		{SignalKind: "idp_sso", EventCount: 116, ActorCount: 40, LastObservedAt: seen},
+	}
+
+	items := buildDiscoverySignalBreakdown(rows, time.UTC)
+	if len(items) != 2 {
+		t.Fatalf("len(items) = %d, want 2", len(items))
+	}
+	sso, oauth := items[0], items[1]
+	if sso.SignalKind != "idp_sso" || sso.Label != "IdP SSO" || sso.EventCount != 116 || sso.ActorCount != 40 || sso.SharePercent != 96 {
+		t.Fatalf("sso item = %+v", sso)
+	}
+	if oauth.SignalKind != "oauth_grant" || oauth.Label != "OAuth grant" || oauth.EventCount != 4 || oauth.ActorCount != 3 || oauth.SharePercent != 3 {
+		t.Fatalf("oauth item = %+v", oauth)
+	}
+	if sso.LastObservedAt == "—" {
+		t.Fatalf("sso LastObservedAt = %q, want a formatted date", sso.LastObservedAt)
+	}
+}
+
+func TestBuildDiscoverySignalBreakdownZeroFillsMissingKinds(t *testing.T) {
+	t.Parallel()
+
+	rows := []gen.ListSaaSAppSignalKindCountsBySaaSAppIDRow{
+		{SignalKind: "idp_sso", EventCount: 12, ActorCount: 5},
+		{SignalKind: "Vendor_Audit", EventCount: 4, ActorCount: 1},
+	}
+
+	items := buildDiscoverySignalBreakdown(rows, time.UTC)
+	if len(items) != 3 {
+		t.Fatalf("len(items) = %d, want 3", len(items))
+	}
+	if got := items[1]; got.SignalKind != "oauth_grant" || got.EventCount != 0 || got.ActorCount != 0 || got.SharePercent != 0 || got.LastObservedAt != "—" {
+		t.Fatalf("oauth item = %+v, want zero-filled", got)
+	}
+	if got := items[0]; got.SharePercent != 75 {
+		t.Fatalf("sso SharePercent = %d, want 75", got.SharePercent)
+	}
+	if got := items[2]; got.SignalKind != "vendor_audit" || got.SharePercent != 25 {
+		t.Fatalf("extra item = %+v", got)
+	}
+
+	for _, item := range buildDiscoverySignalBreakdown(nil, time.UTC) {
+		if item.EventCount != 0 || item.SharePercent != 0 {
+			t.Fatalf("empty app item = %+v, want zero", item)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"testing"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/open-sspm/open-sspm/internal/connectors/configstore"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
 	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
 )
 
@@ -99,3 +102,171 @@ func TestDiscoveryAppSecondaryLabels(t *testing.T) {
 		}
 	})
 }
+
+type fakeDiscoveryManagedAssetQueries struct {
+	bindings []gen.SaasAppBinding
+	assets   map[string]gen.AppAsset
+}
+
+func (f fakeDiscoveryManagedAssetQueries) ListSaaSAppBindingsBySaaSAppID(context.Context, int64) ([]gen.SaasAppBinding, error) {
+	return f.bindings, nil
+}
+
+func (f fakeDiscoveryManagedAssetQueries) GetAppAssetBySourceAndKindAndExternalID(_ context.Context, arg gen.GetAppAssetBySourceAndKindAndExternalIDParams) (gen.AppAsset, error) {
+	asset, ok := f.assets[arg.SourceKind+"|"+arg.SourceName+"|"+arg.AssetKind+"|"+arg.ExternalID]
+	if !ok {
+		return gen.AppAsset{}, pgx.ErrNoRows
+	}
+	return asset, nil
+}
+
+func TestDiscoveryCategoryFacets(t *testing.T) {
+	t.Parallel()
+
+	rows := []gen.CountSaaSAppsGroupedByCategoryRow{
+		{Category: "AI/LLM", AppCount: 4},
+		{Category: "File sharing", AppCount: 2},
+	}
+
+	facets := discoveryCategoryFacets(rows, "")
+	if len(facets) != 2 || facets[0].Category != "AI/LLM" || facets[0].Count != 4 || facets[0].Selected {
+		t.Fatalf("facets = %+v, want unselected counts in query order", facets)
+	}
+
+	facets = discoveryCategoryFacets(rows, "File sharing")
+	if !facets[1].Selected || facets[0].Selected {
+		t.Fatalf("facets = %+v, want only File sharing selected", facets)
+	}
+
+	facets = discoveryCategoryFacets(rows, "Marketing")
+	last := facets[len(facets)-1]
+	if len(facets) != 3 || last.Category != "Marketing" || last.Count != 0 || !last.Selected {
+		t.Fatalf("facets = %+v, want selected category without matches kept at zero", facets)
+	}
+}
+
+func TestResolveDiscoveryManagedAsset(t *testing.T) {
+	t.Parallel()
+
+	sources := []gen.SaasAppSource{
+		{SourceKind: "entra", SourceName: "tenant-1", SourceAppID: "sp-1"},
+		{SourceKind: configstore.KindGoogleWorkspace, SourceName: "C0123", SourceAppID: "client-123"},
+	}
+	assets := map[string]gen.AppAsset{
+		"entra|tenant-1|entra_service_principal|sp-1":           {ID: 11},
+		"google_workspace|C0123|google_oauth_client|client-123": {ID: 22},
+	}
+
+	t.Run("unbound app has no managed asset", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok, err := resolveDiscoveryManagedAsset(context.Background(), fakeDiscoveryManagedAssetQueries{assets: assets}, 1, sources)
+		if err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+		if ok {
+			t.Fatalf("expected no managed asset for unbound app")
+		}
+	})
+
+	t.Run("bound app resolves through the primary binding", func(t *testing.T) {
+		t.Parallel()
+
+		q := fakeDiscoveryManagedAssetQueries{
+			bindings: []gen.SaasAppBinding{
+				{ConnectorKind: "entra", ConnectorSourceName: "tenant-1", IsPrimary: false},
+				{ConnectorKind: configstore.KindGoogleWorkspace, ConnectorSourceName: "C0123", IsPrimary: true},
+			},
+			assets: assets,
+		}
+		asset, ok, err := resolveDiscoveryManagedAsset(context.Background(), q, 1, sources)
+		if err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+		if !ok || asset.ID != 22 {
+			t.Fatalf("asset = %+v ok=%v, want primary binding asset 22", asset, ok)
+		}
+	})
+
+	t.Run("binding without matching source evidence has no managed asset", func(t *testing.T) {
+		t.Parallel()
+
+		q := fakeDiscoveryManagedAssetQueries{
+			bindings: []gen.SaasAppBinding{
+				{ConnectorKind: "entra", ConnectorSourceName: "tenant-2", IsPrimary: true},
+			},
+			assets: assets,
+		}
+		_, ok, err := resolveDiscoveryManagedAsset(context.Background(), q, 1, sources)
+		if err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+		if ok {
+			t.Fatalf("expected no managed asset for binding to another tenant")
+		}
+	})
+}
+
+type fakeDiscoveryAppCredentialQueries struct {
+	bindings    []gen.SaasAppBinding
+	credentials []gen.CredentialArtifact
+}
+
+func (f fakeDiscoveryAppCredentialQueries) ListSaaSAppBindingsBySaaSAppID(context.Context, int64) ([]gen.SaasAppBinding, error) {
+	return f.bindings, nil
+}
+
+func (f fakeDiscoveryAppCredentialQueries) ListCredentialArtifactsForAssetRefs(_ context.Context, arg gen.ListCredentialArtifactsForAssetRefsParams) ([]gen.CredentialArtifact, error) {
+	var out []gen.CredentialArtifact
+	for _, credential := range f.credentials {
+		if credential.SourceKind != arg.SourceKind || credential.SourceName != arg.SourceName {
+			continue
+		}
+		for i := range arg.AssetRefKinds {
+			if credential.AssetRefKind == arg.AssetRefKinds[i] && credential.AssetRefExternalID == arg.AssetRefExternalIds[i] {
+				out = append(out, credential)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func TestListDiscoveryAppCredentialsAcrossBoundSources(t *testing.T) {
+	t.Parallel()
+
+	sources := []gen.SaasAppSource{
+		{SourceKind: "entra", SourceName: "tenant-1", SourceAppID: "app-1"},
+		{SourceKind: configstore.KindGoogleWorkspace, SourceName: "C0123", SourceAppID: "client-123"},
+		{SourceKind: "okta", SourceName: "acme.okta.com", SourceAppID: "0oa1"},
+	}
+	q := fakeDiscoveryAppCredentialQueries{
+		bindings: []gen.SaasAppBinding{
+			{ConnectorKind: "entra", ConnectorSourceName: "tenant-1", IsPrimary: true},
+			{ConnectorKind: configstore.KindGoogleWorkspace, ConnectorSourceName: "C0123"},
+		},
+		credentials: []gen.CredentialArtifact{
+			{ID: 1, SourceKind: "entra", SourceName: "tenant-1", AssetRefKind: "app_asset", AssetRefExternalID: "entra_application:app-1", CredentialKind: "entra_client_secret", DisplayName: "Notion SSO secret"},
+			{ID: 2, SourceKind: configstore.KindGoogleWorkspace, SourceName: "C0123", AssetRefKind: "google_oauth_client", AssetRefExternalID: "google_oauth_client:client-123", CredentialKind: "google_oauth_grant", DisplayName: "Notion grant for alice"},
+			{ID: 3, SourceKind: "entra", SourceName: "tenant-1", AssetRefKind: "app_asset", AssetRefExternalID: "entra_application:app-2", CredentialKind: "entra_client_secret", DisplayName: "Other app secret"},
+			{ID: 4, SourceKind: "okta", SourceName: "acme.okta.com", AssetRefKind: "app_asset", AssetRefExternalID: "okta_app:0oa1", CredentialKind: "okta_oidc_client_secret", DisplayName: "Unbound okta secret"},
+		},
+	}
+
+	credentials, err := listDiscoveryAppCredentials(context.Background(), q, 1, sources, orgScope{})
+	if err != nil {
+		t.Fatalf("listDiscoveryAppCredentials() error = %v", err)
+	}
+	if len(credentials) != 2 || credentials[0].ID != 2 || credentials[1].ID != 1 {
+		t.Fatalf("credentials = %+v, want the Google grant and Entra secret bound to the app", credentials)
+	}
+
+	q.bindings = append(q.bindings, gen.SaasAppBinding{ConnectorKind: "okta", ConnectorSourceName: "acme.okta.com"})
+	credentials, err = listDiscoveryAppCredentials(context.Background(), q, 1, sources, orgScope{})
+	if err != nil {
+		t.Fatalf("listDiscoveryAppCredentials() error = %v", err)
+	}
+	if len(credentials) != 3 {
+		t.Fatalf("len(credentials) = %d, want the Okta client secret added once bound", len(credentials))
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+)
+
+// discoveryActorTrendWeeks is how many weeks, including the current one, the actor trend covers.
+const discoveryActorTrendWeeks = 12
+
+// discoveryWeekStart returns the Monday 00:00 UTC that starts t's week, matching
+// date_trunc('week', ...) over UTC timestamps.
+func discoveryWeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	daysSinceMonday := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -daysSinceMonday)
+}
+
+// discoveryActorTrendSince returns the start of the oldest week in the trend ending at now.
+func discoveryActorTrendSince(now time.Time) pgtype.Timestamptz {
+	since := discoveryWeekStart(now).AddDate(0, 0, -7*(discoveryActorTrendWeeks-1))
+	return pgtype.Timestamptz{Time: since, Valid: true}
+}
+
+// buildDiscoveryActorTrend lays the weekly counts onto every week of the trend ending at now,
+// so weeks without events show as zero rather than being skipped.
+func buildDiscoveryActorTrend(rows []gen.ListSaaSAppWeeklyActorCountsBySaaSAppIDRow, now time.Time) []viewmodels.DiscoveryActorTrendPoint {
+	counts := make(map[time.Time]int64, len(rows))
+	for _, row := range rows {
+		if !row.WeekStart.Valid {
+			continue
+		}
+		counts[discoveryWeekStart(row.WeekStart.Time)] += row.ActorCount
+	}
+
+	points := make([]viewmodels.DiscoveryActorTrendPoint, 0, discoveryActorTrendWeeks)
+	var peak int64
+	week := discoveryActorTrendSince(now).Time
+	for range discoveryActorTrendWeeks {
+		count := counts[week]
+		peak = max(peak, count)
+		points = append(points, viewmodels.DiscoveryActorTrendPoint{
+			WeekStart:  week.Format("2006-01-02"),
+			ActorCount: count,
+		})
+		week = week.AddDate(0, 0, 7)
+	}
+	if peak > 0 {
+		for i := range points {
+			points[i].HeightPercent = int(points[i].ActorCount * 100 / peak)
+		}
+	}
+	return points
+}
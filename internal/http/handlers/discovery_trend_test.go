@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestDiscoveryWeekStartBoundaries(t *testing.T) {
+	t.Parallel()
+
+	monday := time.Date(2026, time.October, 12, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name string
+		at   time.Time
+		want time.Time
+	}{
+		{name: "monday midnight starts its own week", at: monday, want: monday},
+		{name: "sunday last second belongs to the previous week", at: monday.Add(-time.Second), want: monday.AddDate(0, 0, -7)},
+		{name: "mid week", at: time.Date(2026, time.October, 15, 13, 30, 0, 0, time.UTC), want: monday},
+		{name: "sunday end of week", at: time.Date(2026, time.October, 18, 23, 59, 59, 0, time.UTC), want: monday},
+		{name: "non-utc offset is bucketed by its utc instant", at: time.Date(2026, time.October, 12, 1, 0, 0, 0, time.FixedZone("CEST", 2*60*60)), want: monday.AddDate(0, 0, -7)},
+	} {
+		if got := discoveryWeekStart(tc.at); !got.Equal(tc.want) {
+			t.Fatalf("%s: discoveryWeekStart(%s) = %s, want %s", tc.name, tc.at, got, tc.want)
+		}
+	}
+}
+
+func TestBuildDiscoveryActorTrend(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.October, 15, 9, 0, 0, 0, time.UTC)
+	thisWeek := time.Date(2026, time.October, 12, 0, 0, 0, 0, time.UTC)
+	week := func(weeksAgo int) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: thisWeek.AddDate(0, 0, -7*weeksAgo), Valid: true}
+	}
+	rows := []gen.ListSaaSAppWeeklyActorCountsBySaaSAppIDRow{
+		{WeekStart: week(discoveryActorTrendWeeks), ActorCount: 50},
+		{WeekStart: week(discoveryActorTrendWeeks - 1), ActorCount: 3},
+		{WeekStart: week(4), ActorCount: 30},
+		{WeekStart: week(0), ActorCount: 300},
+	}
+
+	points := buildDiscoveryActorTrend(rows, now)
+	if len(points) != discoveryActorTrendWeeks {
+		t.Fatalf("len(points) = %d, want %d", len(points), discoveryActorTrendWeeks)
+	}
+	if points[0].WeekStart != "2026-07-27" || points[0].ActorCount != 3 {
+		t.Fatalf("oldest point = %+v, want 3 actors in the week of 2026-07-27", points[0])
+	}
+	last := points[len(points)-1]
+	if last.WeekStart != "2026-10-12" || last.ActorCount != 300 || last.HeightPercent != 100 {
+		t.Fatalf("latest point = %+v, want 300 actors at full height in the week of 2026-10-12", last)
+	}
+	if got := points[len(points)-5]; got.ActorCount != 30 || got.HeightPercent != 10 {
+		t.Fatalf("point four weeks ago = %+v, want 30 actors at 10%%", got)
+	}
+	var total int64
+	for _, point := range points {
+		total += point.ActorCount
+	}
+	if total != 333 {
+		t.Fatalf("total actors = %d, want 333 with weeks before the window dropped", total)
+	}
+	if got := points[1]; got.ActorCount != 0 || got.HeightPercent != 0 {
+		t.Fatalf("empty week = %+v, want a zero point", got)
+	}
+
+	if got := buildDiscoveryActorTrend(nil, now); len(got) != discoveryActorTrendWeeks || got[0].HeightPercent != 0 {
+		t.Fatalf("buildDiscoveryActorTrend(nil) = %+v, want %d zero weeks", got, discoveryActorTrendWeeks)
+	}
+}
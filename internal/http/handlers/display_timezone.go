@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/authn"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+)
+
+// displayTimezoneChoices are the zones offered in the user menu. Any valid IANA zone is accepted
+// when submitted; this list only keeps the picker short.
+var displayTimezoneChoices = []string{
+	"UTC",
+	"America/Los_Angeles",
+	"America/Denver",
+	"America/Chicago",
+	"America/New_York",
+	"America/Sao_Paulo",
+	"Europe/London",
+	"Europe/Berlin",
+	"Europe/Paris",
+	"Europe/Helsinki",
+	"Africa/Johannesburg",
+	"Asia/Dubai",
+	"Asia/Kolkata",
+	"Asia/Singapore",
+	"Asia/Tokyo",
+	"Australia/Sydney",
+	"Pacific/Auckland",
+}
+
+// displayLocation returns the zone dates are rendered in for this request: the signed-in user's
+// preference, then DISPLAY_TIMEZONE, then UTC.
+func (h *Handlers) displayLocation(c *echo.Context) *time.Location {
+	if principal, ok := authn.PrincipalFromContext(c); ok {
+		if loc, ok := loadDisplayLocation(principal.Timezone); ok {
+			return loc
+		}
+	}
+	if loc, ok := loadDisplayLocation(h.Cfg.DisplayTimezone); ok {
+		return loc
+	}
+	return time.UTC
+}
+
+func loadDisplayLocation(name string) (*time.Location, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, false
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, false
+	}
+	return loc, true
+}
+
+// inDisplayLocation converts t to loc; a nil loc renders in UTC.
+func inDisplayLocation(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		return t.UTC()
+	}
+	return t.In(loc)
+}
+
+func (h *Handlers) displayTimezoneOptions(c *echo.Context) (selected string, defaultZone string, options []string) {
+	defaultZone = strings.TrimSpace(h.Cfg.DisplayTimezone)
+	if _, ok := loadDisplayLocation(defaultZone); !ok {
+		defaultZone = "UTC"
+	}
+	if principal, ok := authn.PrincipalFromContext(c); ok {
+		selected = strings.TrimSpace(principal.Timezone)
+	}
+	options = append(options, displayTimezoneChoices...)
+	for _, zone := range []string{defaultZone, selected} {
+		if zone != "" && !slices.Contains(options, zone) {
+			options = append(options, zone)
+		}
+	}
+	return selected, defaultZone, options
+}
+
+// HandleAccountTimezone saves the signed-in user's display zone. An empty value returns the user
+// to the deployment default.
+func (h *Handlers) HandleAccountTimezone(c *echo.Context) error {
+	principal, ok := authn.PrincipalFromContext(c)
+	if !ok {
+		return RenderNotFound(c)
+	}
+	zone := strings.TrimSpace(c.FormValue("timezone"))
+	if zone != "" {
+		if _, ok := loadDisplayLocation(zone); !ok {
+			return c.String(http.StatusBadRequest, "timezone must be an IANA time zone such as Europe/Berlin")
+		}
+	}
+	if err := h.Q.UpdateAuthUserTimezone(c.Request().Context(), gen.UpdateAuthUserTimezoneParams{Timezone: zone, ID: principal.UserID}); err != nil {
+		return h.RenderError(c, err)
+	}
+	setAuditDetail(c, auditDetail{
+		Before: map[string]string{"timezone": principal.Timezone},
+		After:  map[string]string{"timezone": zone},
+	})
+	setFlashToast(c, viewmodels.ToastViewData{
+		Category: "success",
+		Title:    "Time zone saved",
+	})
+	next := authn.SanitizeNext(c.FormValue("next"))
+	if next == "" {
+		next = "/"
+	}
+	return c.Redirect(http.StatusSeeOther, next)
+}
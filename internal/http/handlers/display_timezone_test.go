@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/open-sspm/open-sspm/internal/auth"
+	"github.com/open-sspm/open-sspm/internal/config"
+	"github.com/open-sspm/open-sspm/internal/http/authn"
+)
+
+func TestFormatProgrammaticTimeInDisplayZones(t *testing.T) {
+	t.Parallel()
+
+	// 23:30 UTC is already the next day in Tokyo and still the same day in New York.
+	value := timestamptz(time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC))
+	cases := []struct {
+		zone     string
+		wantTime string
+		wantDate string
+	}{
+		{zone: "UTC", wantTime: "Mar 1, 2026 23:30 UTC", wantDate: "Mar 1, 2026"},
+		{zone: "Asia/Tokyo", wantTime: "Mar 2, 2026 08:30 JST", wantDate: "Mar 2, 2026"},
+		{zone: "America/New_York", wantTime: "Mar 1, 2026 18:30 EST", wantDate: "Mar 1, 2026"},
+	}
+	for _, tc := range cases {
+		loc, err := time.LoadLocation(tc.zone)
+		if err != nil {
+			t.Fatalf("LoadLocation(%q) error = %v", tc.zone, err)
+		}
+		if got := formatProgrammaticTime(value, loc); got != tc.wantTime {
+			t.Fatalf("%s: formatProgrammaticTime() = %q, want %q", tc.zone, got, tc.wantTime)
+		}
+		if got := formatProgrammaticDate(value, loc); got != tc.wantDate {
+			t.Fatalf("%s: formatProgrammaticDate() = %q, want %q", tc.zone, got, tc.wantDate)
+		}
+	}
+}
+
+func TestDisplayLocationPrefersUserZone(t *testing.T) {
+	t.Parallel()
+
+	h := &Handlers{Cfg: config.Config{DisplayTimezone: "Europe/Berlin"}}
+
+	c, _ := newTestContext(http.MethodGet, "http://example.com/credentials")
+	if got := h.displayLocation(c).String(); got != "Europe/Berlin" {
+		t.Fatalf("no principal: displayLocation() = %q, want deployment default", got)
+	}
+
+	c.Set(authn.ContextKeyPrincipal, auth.Principal{UserID: 1, Role: auth.RoleViewer, Timezone: "Asia/Tokyo"})
+	if got := h.displayLocation(c).String(); got != "Asia/Tokyo" {
+		t.Fatalf("user zone: displayLocation() = %q, want Asia/Tokyo", got)
+	}
+
+	c.Set(authn.ContextKeyPrincipal, auth.Principal{UserID: 1, Role: auth.RoleViewer, Timezone: "Nowhere/Invalid"})
+	if got := h.displayLocation(c).String(); got != "Europe/Berlin" {
+		t.Fatalf("invalid user zone: displayLocation() = %q, want deployment default", got)
+	}
+
+	if got := (&Handlers{}).displayLocation(c); got != time.UTC {
+		t.Fatalf("no default: displayLocation() = %v, want UTC", got)
+	}
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/accessgraph"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+	"github.com/open-sspm/open-sspm/internal/http/views"
+)
+
+const entitlementChangesRowLimit int32 = 5000
+
+const (
+	entitlementChangeGranted  = "granted"
+	entitlementChangeRevoked  = "revoked"
+	entitlementChangeModified = "modified"
+)
+
+// HandleEntitlementChanges lists entitlements granted, revoked, or modified between the two most
+// recent successful runs of a source.
+func (h *Handlers) HandleEntitlementChanges(c *echo.Context) error {
+	ctx := c.Request().Context()
+	layout, snap, err := h.LayoutData(ctx, c, "Access Changes")
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
+	sources := availableIdentitySourcePairs(snap)
+	data := viewmodels.EntitlementChangesViewData{
+		Layout:        layout,
+		Sources:       sources,
+		EmptyStateMsg: "No access changes between the last two successful runs.",
+	}
+
+	selected, ok := selectEntitlementChangeSource(c.QueryParam("source_kind"), c.QueryParam("source_name"), sources)
+	if !ok {
+		data.EmptyStateMsg = "Configure a connector to track access changes between syncs."
+		return h.RenderComponent(c, views.EntitlementChangesPage(data))
+	}
+	data.SelectedSourceKind = selected.SourceKind
+	data.SelectedSourceName = selected.SourceName
+	data.SelectedSourceLabel = sourceDiagnosticLabel(selected.SourceKind, selected.SourceName)
+
+	runs, err := h.Q.ListRecentSuccessfulSyncRunsBySource(ctx, gen.ListRecentSuccessfulSyncRunsBySourceParams{
+		SourceKind: selected.SourceKind,
+		SourceName: selected.SourceName,
+		Limit:      2,
+	})
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	if len(runs) < 2 {
+		data.EmptyStateMsg = "Access changes appear after two successful syncs of this source."
+		return h.RenderComponent(c, views.EntitlementChangesPage(data))
+	}
+
+	now := time.Now()
+	current, previous := runs[0], runs[1]
+	data.CurrentRunID = current.ID
+	data.PreviousRunID = previous.ID
+	data.CurrentRunLabel = formatAge(now, current.FinishedAt.Time)
+	data.PreviousRunLabel = formatAge(now, previous.FinishedAt.Time)
+
+	rows, err := h.Q.ListEntitlementChangeCandidatesBySource(ctx, gen.ListEntitlementChangeCandidatesBySourceParams{
+		SourceKind:    selected.SourceKind,
+		SourceName:    selected.SourceName,
+		CurrentRunID:  current.ID,
+		PreviousRunID: previous.ID,
+		LimitRows:     entitlementChangesRowLimit,
+	})
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	data.Truncated = len(rows) >= int(entitlementChangesRowLimit)
+
+	changes := buildEntitlementChanges(selected.SourceKind, selected.SourceName, rows, current.ID, previous.ID)
+	for _, change := range changes {
+		switch change.ChangeType {
+		case entitlementChangeGranted:
+			data.GrantedCount++
+		case entitlementChangeRevoked:
+			data.RevokedCount++
+		case entitlementChangeModified:
+			data.ModifiedCount++
+		}
+	}
+	data.Rows = changes
+	data.HasRows = len(changes) > 0
+
+	return h.RenderComponent(c, views.EntitlementChangesPage(data))
+}
+
+// selectEntitlementChangeSource resolves the requested source, falling back to the first source of
+// the requested kind and then to the first configured source. The diff is per source, so there is
+// no "all sources" option.
+func selectEntitlementChangeSource(rawKind, rawName string, sources []viewmodels.ProgrammaticSourceOption) (viewmodels.ProgrammaticSourceOption, bool) {
+	if len(sources) == 0 {
+		return viewmodels.ProgrammaticSourceOption{}, false
+	}
+	kind := NormalizeConnectorKind(rawKind)
+	name := strings.TrimSpace(rawName)
+
+	for _, source := range sources {
+		if source.SourceKind == kind && strings.EqualFold(source.SourceName, name) {
+			return source, true
+		}
+	}
+	for _, source := range sources {
+		if source.SourceKind == kind {
+			return source, true
+		}
+	}
+	return sources[0], true
+}
+
+type entitlementChangeKey struct {
+	appUserID int64
+	kind      string
+	resource  string
+}
+
+// buildEntitlementChanges classifies change candidates for the current run against the previous
+// one. Entitlements observed now but not in the previous run are granted; entitlements expired by
+// the current run are revoked. A grant and a revoke for the same account, kind, and resource are a
+// permission change and collapse into one modified row.
+func buildEntitlementChanges(sourceKind, sourceName string, rows []gen.ListEntitlementChangeCandidatesBySourceRow, currentRunID, previousRunID int64) []viewmodels.EntitlementChangeRow {
+	var granted, revoked []gen.ListEntitlementChangeCandidatesBySourceRow
+	seen := make(map[int64]struct{}, len(rows))
+	for _, row := range rows {
+		if _, ok := seen[row.EntitlementID]; ok {
+			continue
+		}
+		seen[row.EntitlementID] = struct{}{}
+
+		switch {
+		case row.ExpiredRunID.Valid && row.ExpiredRunID.Int64 == currentRunID:
+			revoked = append(revoked, row)
+		case row.LastObservedRunID.Valid && row.LastObservedRunID.Int64 == currentRunID &&
+			(!row.PreviousObservedRunID.Valid || row.PreviousObservedRunID.Int64 != previousRunID):
+			granted = append(granted, row)
+		}
+	}
+
+	revokedByKey := make(map[entitlementChangeKey][]int, len(revoked))
+	for idx, row := range revoked {
+		key := entitlementChangeKeyFor(row)
+		revokedByKey[key] = append(revokedByKey[key], idx)
+	}
+
+	pairedRevoked := make(map[int]struct{})
+	out := make([]viewmodels.EntitlementChangeRow, 0, len(granted)+len(revoked))
+	for _, row := range granted {
+		key := entitlementChangeKeyFor(row)
+		if pending := revokedByKey[key]; len(pending) > 0 {
+			prev := revoked[pending[0]]
+			revokedByKey[key] = pending[1:]
+			pairedRevoked[pending[0]] = struct{}{}
+			change := entitlementChangeRow(sourceKind, sourceName, entitlementChangeModified, row)
+			change.PreviousPermission = strings.TrimSpace(prev.EntitlementPermission)
+			out = append(out, change)
+			continue
+		}
+		out = append(out, entitlementChangeRow(sourceKind, sourceName, entitlementChangeGranted, row))
+	}
+	for idx, row := range revoked {
+		if _, ok := pairedRevoked[idx]; ok {
+			continue
+		}
+		change := entitlementChangeRow(sourceKind, sourceName, entitlementChangeRevoked, row)
+		change.PreviousPermission = change.Permission
+		change.Permission = ""
+		out = append(out, change)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].ResourceLabel != out[j].ResourceLabel {
+			return out[i].ResourceLabel < out[j].ResourceLabel
+		}
+		if out[i].AppUserExternalID != out[j].AppUserExternalID {
+			return out[i].AppUserExternalID < out[j].AppUserExternalID
+		}
+		return out[i].ChangeType < out[j].ChangeType
+	})
+	return out
+}
+
+func entitlementChangeKeyFor(row gen.ListEntitlementChangeCandidatesBySourceRow) entitlementChangeKey {
+	return entitlementChangeKey{
+		appUserID: row.AppUserID,
+		kind:      strings.TrimSpace(row.EntitlementKind),
+		resource:  strings.TrimSpace(row.EntitlementResource),
+	}
+}
+
+func entitlementChangeRow(sourceKind, sourceName, changeType string, row gen.ListEntitlementChangeCandidatesBySourceRow) viewmodels.EntitlementChangeRow {
+	resource := strings.TrimSpace(row.EntitlementResource)
+	out := viewmodels.EntitlementChangeRow{
+		ChangeType:         changeType,
+		ChangeLabel:        entitlementChangeLabel(changeType),
+		ChangeClass:        entitlementChangeClass(changeType),
+		Kind:               strings.TrimSpace(row.EntitlementKind),
+		Resource:           resource,
+		ResourceLabel:      accessgraph.DisplayResourceLabel(resource, row.EntitlementRawJson),
+		ResourceHref:       accessgraph.BuildResourceHrefFromResourceRef(sourceKind, sourceName, resource),
+		Permission:         strings.TrimSpace(row.EntitlementPermission),
+		AssignmentState:    strings.TrimSpace(row.EntitlementAssignmentState),
+		AppUserExternalID:  strings.TrimSpace(row.AppUserExternalID),
+		AppUserEmail:       strings.TrimSpace(row.AppUserEmail),
+		AppUserDisplayName: strings.TrimSpace(row.AppUserDisplayName),
+	}
+	if out.ResourceLabel == "" {
+		out.ResourceLabel = resource
+	}
+	if row.IdentityID.Valid {
+		out.IdentityID = row.IdentityID.Int64
+		out.IdentityHref = "/identities/" + strconv.FormatInt(row.IdentityID.Int64, 10)
+		switch {
+		case row.IdentityDisplayName.Valid && strings.TrimSpace(row.IdentityDisplayName.String) != "":
+			out.IdentityLabel = strings.TrimSpace(row.IdentityDisplayName.String)
+		case row.IdentityPrimaryEmail.Valid && strings.TrimSpace(row.IdentityPrimaryEmail.String) != "":
+			out.IdentityLabel = strings.TrimSpace(row.IdentityPrimaryEmail.String)
+		default:
+			out.IdentityLabel = fmt.Sprintf("Identity %d", row.IdentityID.Int64)
+		}
+	}
+	return out
+}
+
+func entitlementChangeLabel(changeType string) string {
+	switch changeType {
+	case entitlementChangeGranted:
+		return "Granted"
+	case entitlementChangeRevoked:
+		return "Revoked"
+	case entitlementChangeModified:
+		return "Modified"
+	default:
+		return changeType
+	}
+}
+
+func entitlementChangeClass(changeType string) string {
+	switch changeType {
+	case entitlementChangeGranted:
+		return badgeClassSuccess()
+	case entitlementChangeRevoked:
+		return badgeClassDanger()
+	case entitlementChangeModified:
+		return badgeClassWarning()
+	default:
+		return badgeClassNeutral()
+	}
+}
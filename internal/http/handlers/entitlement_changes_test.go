@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+)
+
+func TestBuildEntitlementChangesDetectsGrantRevokeAndModify(t *testing.T) {
+	const previousRun, currentRun int64 = 10, 11
+	run := func(id int64) pgtype.Int8 { return pgtype.Int8{Int64: id, Valid: true} }
+
+	rows := []gen.ListEntitlementChangeCandidatesBySourceRow{
+		// Observed in both runs: not a change.
+		{EntitlementID: 1, AppUserID: 100, AppUserExternalID: "alice", EntitlementKind: "github_team_repo_permission", EntitlementResource: "github_repo:acme/api", EntitlementPermission: "push", LastObservedRunID: run(currentRun), PreviousObservedRunID: run(previousRun)},
+		// New in the current run.
+		{EntitlementID: 2, AppUserID: 100, AppUserExternalID: "alice", EntitlementKind: "github_team_member", EntitlementResource: "github_team:acme/core", EntitlementPermission: "member", LastObservedRunID: run(currentRun), IdentityID: run(7), IdentityDisplayName: pgtype.Text{String: "Alice", Valid: true}},
+		// Re-granted after an earlier revocation.
+		{EntitlementID: 3, AppUserID: 200, AppUserExternalID: "bob", EntitlementKind: "github_team_member", EntitlementResource: "github_team:acme/ops", EntitlementPermission: "member", LastObservedRunID: run(currentRun), PreviousObservedRunID: run(4)},
+		// Present in the previous run, gone now.
+		{EntitlementID: 4, AppUserID: 200, AppUserExternalID: "bob", EntitlementKind: "github_org_role", EntitlementResource: "github_org:acme", EntitlementPermission: "admin", LastObservedRunID: run(previousRun), ExpiredRunID: run(currentRun)},
+		// Permission changed on the same resource: old row expired, new row granted.
+		{EntitlementID: 5, AppUserID: 300, AppUserExternalID: "carol", EntitlementKind: "github_team_repo_permission", EntitlementResource: "github_repo:acme/web", EntitlementPermission: "pull", LastObservedRunID: run(previousRun), ExpiredRunID: run(currentRun)},
+		{EntitlementID: 6, AppUserID: 300, AppUserExternalID: "carol", EntitlementKind: "github_team_repo_permission", EntitlementResource: "github_repo:acme/web", EntitlementPermission: "admin", LastObservedRunID: run(currentRun)},
+		// Duplicate join row for an entitlement already seen.
+		{EntitlementID: 2, AppUserID: 100, AppUserExternalID: "alice", EntitlementKind: "github_team_member", EntitlementResource: "github_team:acme/core", EntitlementPermission: "member", LastObservedRunID: run(currentRun)},
+	}
+
+	changes := buildEntitlementChanges("github", "acme", rows, currentRun, previousRun)
+
+	byKey := map[string]viewmodels.EntitlementChangeRow{}
+	counts := map[string]int{}
+	for _, change := range changes {
+		counts[change.ChangeType]++
+		byKey[change.AppUserExternalID+"|"+change.Resource] = change
+	}
+	if len(changes) != 4 || counts[entitlementChangeGranted] != 2 || counts[entitlementChangeRevoked] != 1 || counts[entitlementChangeModified] != 1 {
+		t.Fatalf("changes = %+v", changes)
+	}
+
+	granted := byKey["alice|github_team:acme/core"]
+	if granted.ChangeType != entitlementChangeGranted || granted.Permission != "member" {
+		t.Fatalf("granted = %+v", granted)
+	}
+	if granted.IdentityHref != "/identities/7" || granted.IdentityLabel != "Alice" {
+		t.Fatalf("granted identity = %q %q", granted.IdentityHref, granted.IdentityLabel)
+	}
+	if regranted := byKey["bob|github_team:acme/ops"]; regranted.ChangeType != entitlementChangeGranted {
+		t.Fatalf("re-granted = %+v", regranted)
+	}
+
+	revoked := byKey["bob|github_org:acme"]
+	if revoked.ChangeType != entitlementChangeRevoked || revoked.PreviousPermission != "admin" || revoked.Permission != "" {
+		t.Fatalf("revoked = %+v", revoked)
+	}
+	if revoked.IdentityHref != "" {
+		t.Fatalf("revoked identity href = %q, want unlinked", revoked.IdentityHref)
+	}
+
+	modified := byKey["carol|github_repo:acme/web"]
+	if modified.ChangeType != entitlementChangeModified || modified.PreviousPermission != "pull" || modified.Permission != "admin" {
+		t.Fatalf("modified = %+v", modified)
+	}
+	if _, ok := byKey["alice|github_repo:acme/api"]; ok {
+		t.Fatalf("unchanged entitlement reported as a change")
+	}
+}
+
+func TestSelectEntitlementChangeSource(t *testing.T) {
+	sources := []viewmodels.ProgrammaticSourceOption{
+		{SourceKind: "entra", SourceName: "tenant-1"},
+		{SourceKind: "github", SourceName: "acme"},
+		{SourceKind: "github", SourceName: "globex"},
+	}
+
+	if got, ok := selectEntitlementChangeSource("github", "GLOBEX", sources); !ok || got.SourceName != "globex" {
+		t.Fatalf("exact match = %+v %v", got, ok)
+	}
+	if got, _ := selectEntitlementChangeSource("github", "unknown", sources); got.SourceName != "acme" {
+		t.Fatalf("kind fallback = %+v", got)
+	}
+	if got, _ := selectEntitlementChangeSource("", "", sources); got.SourceKind != "entra" {
+		t.Fatalf("default = %+v", got)
+	}
+	if _, ok := selectEntitlementChangeSource("github", "acme", nil); ok {
+		t.Fatalf("expected no source without configured connectors")
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/open-sspm/open-sspm/internal/accessgraph"
+	"github.com/open-sspm/open-sspm/internal/config"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+)
+
+// entitlementPrivilegePolicy is the configurable high-privilege classification of entitlements:
+// at or above MinLevel on the canonical ladder, or matching one of Patterns whatever the level.
+// A MinLevel of PrivilegeUnknown disables the level test.
+type entitlementPrivilegePolicy struct {
+	MinLevel registry.PrivilegeLevel
+	Patterns []config.EntitlementPrivilegePattern
+}
+
+func (h *Handlers) entitlementPrivilegePolicy() entitlementPrivilegePolicy {
+	return entitlementPrivilegePolicy{
+		MinLevel: registry.ParsePrivilegeLevel(h.Cfg.EntitlementHighPrivilegeMinLevel),
+		Patterns: h.Cfg.EntitlementHighPrivilegePatterns,
+	}
+}
+
+// patternKinds returns the entitlement kinds named by the patterns, so a query can load them
+// whatever their level.
+func (p entitlementPrivilegePolicy) patternKinds() []string {
+	var kinds []string
+	seen := map[string]bool{}
+	for _, pattern := range p.Patterns {
+		if !seen[pattern.Kind] {
+			seen[pattern.Kind] = true
+			kinds = append(kinds, pattern.Kind)
+		}
+	}
+	return kinds
+}
+
+// classify reports whether an entitlement is high privilege, and why. A pattern value matches
+// the permission, the role name recorded in the raw JSON, or the resource's external id.
+func (p entitlementPrivilegePolicy) classify(kind, resource, permission string, level registry.PrivilegeLevel, rawJSON []byte) (bool, string) {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	var candidates []string
+	for _, pattern := range p.Patterns {
+		if pattern.Kind != kind {
+			continue
+		}
+		if pattern.Value == "" {
+			return true, "Matches high-privilege pattern " + pattern.Kind + "."
+		}
+		if candidates == nil {
+			candidates = entitlementPrivilegeCandidates(resource, permission, rawJSON)
+		}
+		for _, candidate := range candidates {
+			if matched, err := path.Match(pattern.Value, candidate); err == nil && matched {
+				return true, "Matches high-privilege pattern " + pattern.Kind + "=" + pattern.Value + "."
+			}
+		}
+	}
+	if p.MinLevel > registry.PrivilegeUnknown && level >= p.MinLevel {
+		return true, "Privilege level " + level.String() + " is at or above " + p.MinLevel.String() + "."
+	}
+	return false, ""
+}
+
+// entitlementPrivilegeCandidates returns the lowercased values a pattern value is matched with.
+func entitlementPrivilegeCandidates(resource, permission string, rawJSON []byte) []string {
+	candidates := []string{strings.ToLower(strings.TrimSpace(permission))}
+	var payload struct {
+		RoleName string `json:"role_name"`
+	}
+	if len(rawJSON) > 0 && json.Unmarshal(rawJSON, &payload) == nil {
+		if role := strings.ToLower(strings.TrimSpace(payload.RoleName)); role != "" {
+			candidates = append(candidates, role)
+		}
+	}
+	if _, externalID, ok := accessgraph.ParseCanonicalResourceRef(resource); ok {
+		candidates = append(candidates, strings.ToLower(externalID))
+	}
+	return candidates
+}
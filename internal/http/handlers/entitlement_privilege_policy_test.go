@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/config"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func defaultEntitlementPrivilegePolicy() entitlementPrivilegePolicy {
+	return entitlementPrivilegePolicy{
+		MinLevel: registry.PrivilegeAdmin,
+		Patterns: config.DefaultEntitlementHighPrivilegePatterns(),
+	}
+}
+
+func TestEntitlementPrivilegePolicyClassify(t *testing.T) {
+	t.Parallel()
+
+	policy := defaultEntitlementPrivilegePolicy()
+	cases := []struct {
+		name       string
+		kind       string
+		resource   string
+		permission string
+		level      registry.PrivilegeLevel
+		rawJSON    string
+		want       bool
+	}{
+		{name: "github org admin", kind: "github_org_role", resource: "github_org:acme", permission: "admin", level: registry.PrivilegeOwner, want: true},
+		{name: "entra global administrator by role name", kind: "entra_directory_role", resource: "entra_directory_role:62e90394", permission: "member", level: registry.PrivilegeUnknown, rawJSON: `{"role_name":"Global Administrator"}`, want: true},
+		{name: "google admin role of any name", kind: "google_admin_role", resource: "google_admin_role:9", permission: "global", level: registry.PrivilegeUnknown, want: true},
+		{name: "admin level without a pattern", kind: "github_team_repo_permission", resource: "github_repo:acme/site", permission: "admin", level: registry.PrivilegeAdmin, want: true},
+		{name: "read-only org membership", kind: "github_org_role", resource: "github_org:acme", permission: "member", level: registry.PrivilegeRead, want: false},
+		{name: "read-only group membership", kind: "google_group_member", resource: "google_group:eng", permission: "member", level: registry.PrivilegeRead, want: false},
+		{name: "entra reader role", kind: "entra_directory_role", resource: "entra_directory_role:88d8e3e3", permission: "member", level: registry.PrivilegeRead, rawJSON: `{"role_name":"Directory Readers"}`, want: false},
+	}
+	for _, tc := range cases {
+		got, reason := policy.classify(tc.kind, tc.resource, tc.permission, tc.level, []byte(tc.rawJSON))
+		if got != tc.want {
+			t.Fatalf("%s: classify() = %v (%q), want %v", tc.name, got, reason, tc.want)
+		}
+		if got && reason == "" {
+			t.Fatalf("%s: classify() gave no reason", tc.name)
+		}
+	}
+}
+
+func TestEntitlementPrivilegePolicyLevelTestDisabled(t *testing.T) {
+	t.Parallel()
+
+	policy := entitlementPrivilegePolicy{Patterns: []config.EntitlementPrivilegePattern{{Kind: "aws_permission_set", Value: "*poweruser*"}}}
+	if got, _ := policy.classify("github_org_role", "github_org:acme", "admin", registry.PrivilegeOwner, nil); got {
+		t.Fatalf("owner without a pattern flagged with the level test disabled")
+	}
+	if got, _ := policy.classify("aws_permission_set", "aws_account:123", "PowerUserAccess", registry.PrivilegeWrite, nil); !got {
+		t.Fatalf("write-level PowerUserAccess not flagged by its pattern")
+	}
+	if kinds := policy.patternKinds(); len(kinds) != 1 || kinds[0] != "aws_permission_set" {
+		t.Fatalf("patternKinds() = %v, want [aws_permission_set]", kinds)
+	}
+}
+
+func TestBuildPrivilegedAccessRowsHighPrivilegeOnly(t *testing.T) {
+	t.Parallel()
+
+	rows := buildPrivilegedAccessRows([]gen.ListPrivilegedEntitlementsRow{
+		{EntitlementID: 1, EntitlementKind: "github_org_role", EntitlementResource: "github_org:acme", EntitlementPermission: "admin", EntitlementPrivilegeLevel: int16(registry.PrivilegeOwner), AppUserSourceKind: "github", AppUserSourceName: "acme"},
+		{EntitlementID: 2, EntitlementKind: "github_team_repo_permission", EntitlementResource: "github_repo:acme/site", EntitlementPermission: "push", EntitlementPrivilegeLevel: int16(registry.PrivilegeWrite), AppUserSourceKind: "github", AppUserSourceName: "acme"},
+	}, orgScope{}, defaultEntitlementPrivilegePolicy(), true)
+
+	if len(rows) != 1 || rows[0].EntitlementID != 1 || !rows[0].HighPrivilege || rows[0].HighPrivilegeReason == "" {
+		t.Fatalf("rows = %+v, want only the flagged org admin", rows)
+	}
+}
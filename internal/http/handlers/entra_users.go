@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"strings"
 
 	"github.com/labstack/echo/v5"
@@ -18,6 +19,12 @@ func (h *Handlers) HandleEntraUsers(c *echo.Context) error {
 
 	const perPage = 20
 	query := strings.TrimSpace(c.QueryParam("q"))
+	userType := strings.ToLower(strings.TrimSpace(c.QueryParam("user_type")))
+	switch userType {
+	case "guest", "member":
+	default:
+		userType = ""
+	}
 	page := parsePageParam(c)
 
 	sourceName := strings.TrimSpace(snap.Entra.TenantID)
@@ -31,6 +38,7 @@ func (h *Handlers) HandleEntraUsers(c *echo.Context) error {
 			Layout:         layout,
 			Users:          nil,
 			Query:          query,
+			UserType:       userType,
 			ShowingCount:   0,
 			ShowingFrom:    0,
 			ShowingTo:      0,
@@ -49,6 +57,7 @@ func (h *Handlers) HandleEntraUsers(c *echo.Context) error {
 		SourceKind: "entra",
 		SourceName: sourceName,
 		Query:      query,
+		UserType:   userType,
 	})
 	if err != nil {
 		return h.RenderError(c, err)
@@ -59,6 +68,7 @@ func (h *Handlers) HandleEntraUsers(c *echo.Context) error {
 		SourceKind: "entra",
 		SourceName: sourceName,
 		Query:      query,
+		UserType:   userType,
 		PageLimit:  int32(perPage),
 		PageOffset: int32(offset),
 	})
@@ -111,13 +121,14 @@ func (h *Handlers) HandleEntraUsers(c *echo.Context) error {
 			Email:              strings.TrimSpace(user.Email),
 			DisplayName:        userName,
 			IdpUserID:          user.IdpUserID,
+			IsGuest:            accountIsGuest(user.RawJson),
 			DirectoryRoleCount: directoryRoleCounts[user.ID],
 			EnterpriseAppCount: enterpriseAppCounts[user.ID],
 		})
 	}
 
 	emptyState := "No Microsoft Entra ID users synced yet."
-	if query != "" {
+	if query != "" || userType != "" {
 		emptyState = "No Microsoft Entra ID users match the current search."
 	}
 
@@ -125,6 +136,7 @@ func (h *Handlers) HandleEntraUsers(c *echo.Context) error {
 		Layout:         layout,
 		Users:          items,
 		Query:          query,
+		UserType:       userType,
 		ShowingCount:   showingCount,
 		ShowingFrom:    showingFrom,
 		ShowingTo:      showingTo,
@@ -224,6 +236,18 @@ func (h *Handlers) HandleUnmatchedEntra(c *echo.Context) error {
 	return h.RenderComponent(c, views.UnmatchedEntraPage(data))
 }
 
+// accountIsGuest reports whether an account's raw JSON carries the external guest flag
+// (is_guest) that connectors set for B2B guests.
+func accountIsGuest(raw []byte) bool {
+	var payload struct {
+		IsGuest bool `json:"is_guest"`
+	}
+	if len(raw) == 0 || json.Unmarshal(raw, &payload) != nil {
+		return false
+	}
+	return payload.IsGuest
+}
+
 func countUniqueEntitlementResources(rows []gen.ListEntitlementResourcesByAppUserIDsAndKindRow) map[int64]int {
 	counts := make(map[int64]int)
 	var lastUserID int64
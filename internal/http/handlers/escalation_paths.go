@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/accessgraph"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+	"github.com/open-sspm/open-sspm/internal/http/views"
+)
+
+// escalationEntitlementRowLimit bounds the entitlements loaded into the graph. Paths through
+// entitlements past the limit are not found, and the page says so.
+const escalationEntitlementRowLimit int32 = 100000
+
+// HandleEscalationPaths lists the privilege escalation paths found in the access graph: accounts
+// that can reach a level above the one they hold through a group they belong to or manage, or
+// through a role that can hand out higher access. ?source_kind narrows the sources and
+// ?format=csv|json exports the paths instead of rendering the page.
+func (h *Handlers) HandleEscalationPaths(c *echo.Context) error {
+	sourceKind := NormalizeConnectorKind(c.QueryParam("source_kind"))
+	format := strings.ToLower(strings.TrimSpace(c.QueryParam("format")))
+	if format != "" && format != "csv" && format != "json" {
+		return c.String(http.StatusBadRequest, "format must be csv or json")
+	}
+
+	ctx := c.Request().Context()
+	rows, err := h.Q.ListActiveEntitlementsForEscalation(ctx, gen.ListActiveEntitlementsForEscalationParams{
+		SourceKind: sourceKind,
+		RowLimit:   escalationEntitlementRowLimit,
+	})
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	paths := buildEscalationPathRows(rows, scope)
+
+	if format != "" {
+		return writeEscalationPathsExport(c, format, paths)
+	}
+
+	layout, snap, err := h.LayoutData(ctx, c, "Escalation Paths")
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	data := viewmodels.EscalationPathsViewData{
+		Layout:             layout,
+		SelectedSourceKind: sourceKind,
+		Rows:               paths,
+		HasRows:            len(paths) > 0,
+		Truncated:          len(rows) >= int(escalationEntitlementRowLimit),
+		EmptyStateMsg:      "No account can reach a higher privilege level than it holds.",
+	}
+	seenKinds := map[string]struct{}{}
+	for _, source := range availableIdentitySourcePairs(snap) {
+		if _, ok := seenKinds[source.SourceKind]; ok {
+			continue
+		}
+		seenKinds[source.SourceKind] = struct{}{}
+		data.SourceKinds = append(data.SourceKinds, viewmodels.ProgrammaticSourceOption{SourceKind: source.SourceKind, Label: source.Label})
+	}
+	return h.RenderComponent(c, views.EscalationPathsPage(data))
+}
+
+// buildEscalationPathRows runs escalation detection over the entitlements of the sources the
+// scope allows and labels each path's nodes with account names and resource labels.
+func buildEscalationPathRows(rows []gen.ListActiveEntitlementsForEscalationRow, scope orgScope) []viewmodels.EscalationPathRow {
+	grants := make([]accessgraph.EscalationGrant, 0, len(rows))
+	accounts := map[string]gen.ListActiveEntitlementsForEscalationRow{}
+	resourceJSON := map[string][]byte{}
+	for _, row := range rows {
+		if !scope.AllowsSource(row.AppUserSourceKind, row.AppUserSourceName) {
+			continue
+		}
+		sourceKey := orgScopeSourceKey(row.AppUserSourceKind, row.AppUserSourceName)
+		accounts[sourceKey+"\x00"+strings.TrimSpace(row.AppUserExternalID)] = row
+		if key := sourceKey + "\x00" + strings.TrimSpace(row.EntitlementResource); resourceJSON[key] == nil {
+			resourceJSON[key] = row.EntitlementRawJson
+		}
+		grants = append(grants, accessgraph.EscalationGrant{
+			SourceKind:        row.AppUserSourceKind,
+			SourceName:        row.AppUserSourceName,
+			AccountExternalID: row.AppUserExternalID,
+			Kind:              row.EntitlementKind,
+			Resource:          row.EntitlementResource,
+			Permission:        row.EntitlementPermission,
+			Level:             registry.PrivilegeLevel(row.EntitlementPrivilegeLevel),
+			RawJSON:           row.EntitlementRawJson,
+		})
+	}
+
+	paths := accessgraph.DetectEscalationPaths(grants)
+	out := make([]viewmodels.EscalationPathRow, 0, len(paths))
+	for _, path := range paths {
+		sourceKey := orgScopeSourceKey(path.SourceKind, path.SourceName)
+		account := accounts[sourceKey+"\x00"+path.AccountExternalID]
+		row := viewmodels.EscalationPathRow{
+			SourceKind:         path.SourceKind,
+			SourceName:         path.SourceName,
+			SourceLabel:        sourceDiagnosticLabel(path.SourceKind, path.SourceName),
+			AppUserExternalID:  path.AccountExternalID,
+			AppUserEmail:       strings.TrimSpace(account.AppUserEmail),
+			AppUserDisplayName: strings.TrimSpace(account.AppUserDisplayName),
+			HeldLevel:          path.HeldLevel.String(),
+			HeldClass:          privilegeLevelClass(path.HeldLevel),
+			ReachableLevel:     path.ReachableLevel.String(),
+			ReachableClass:     privilegeLevelClass(path.ReachableLevel),
+			Reason:             path.Reason,
+		}
+		for _, step := range path.Steps {
+			item := viewmodels.EscalationPathStep{Edge: step.Edge, Label: step.Node}
+			if externalID, ok := accessgraph.EscalationAccountExternalID(step.Node); ok {
+				if known, ok := accounts[sourceKey+"\x00"+externalID]; ok {
+					item.Label = firstNonEmpty(known.AppUserDisplayName, known.AppUserEmail, externalID)
+				} else {
+					item.Label = externalID
+				}
+			} else {
+				if label := accessgraph.DisplayResourceLabel(step.Node, resourceJSON[sourceKey+"\x00"+step.Node]); label != "" {
+					item.Label = label
+				}
+				item.Href = accessgraph.BuildResourceHrefFromResourceRef(path.SourceKind, path.SourceName, step.Node)
+			}
+			row.Steps = append(row.Steps, item)
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
+// escalationPathReportRow is one exported path. Field order matches the CSV columns.
+type escalationPathReportRow struct {
+	SourceKind        string `json:"source_kind"`
+	SourceName        string `json:"source_name"`
+	AppUserExternalID string `json:"app_user_external_id"`
+	AppUserEmail      string `json:"app_user_email"`
+	HeldLevel         string `json:"held_level"`
+	ReachableLevel    string `json:"reachable_level"`
+	Path              string `json:"path"`
+	Reason            string `json:"reason"`
+}
+
+var escalationPathColumns = []string{
+	"source_kind",
+	"source_name",
+	"app_user_external_id",
+	"app_user_email",
+	"held_level",
+	"reachable_level",
+	"path",
+	"reason",
+}
+
+// escalationPathText renders a path's steps as "alice -manages-> Admins -acts as-> ...".
+func escalationPathText(steps []viewmodels.EscalationPathStep) string {
+	var b strings.Builder
+	for _, step := range steps {
+		if step.Edge != "" {
+			b.WriteString(" -" + step.Edge + "-> ")
+		}
+		b.WriteString(step.Label)
+	}
+	return b.String()
+}
+
+func (r escalationPathReportRow) csvRecord() []string {
+	return []string{
+		r.SourceKind,
+		r.SourceName,
+		r.AppUserExternalID,
+		r.AppUserEmail,
+		r.HeldLevel,
+		r.ReachableLevel,
+		r.Path,
+		r.Reason,
+	}
+}
+
+func writeEscalationPathsExport(c *echo.Context, format string, rows []viewmodels.EscalationPathRow) error {
+	report := make([]escalationPathReportRow, 0, len(rows))
+	for _, row := range rows {
+		report = append(report, escalationPathReportRow{
+			SourceKind:        row.SourceKind,
+			SourceName:        row.SourceName,
+			AppUserExternalID: row.AppUserExternalID,
+			AppUserEmail:      row.AppUserEmail,
+			HeldLevel:         row.HeldLevel,
+			ReachableLevel:    row.ReachableLevel,
+			Path:              escalationPathText(row.Steps),
+			Reason:            row.Reason,
+		})
+	}
+
+	filename := "escalation-paths-" + time.Now().UTC().Format("20060102") + "." + format
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if format == "json" {
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c.Response().WriteHeader(http.StatusOK)
+		return json.NewEncoder(c.Response()).Encode(report)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().WriteHeader(http.StatusOK)
+	w := csv.NewWriter(c.Response())
+	if err := w.Write(escalationPathColumns); err != nil {
+		return err
+	}
+	for _, row := range report {
+		if err := w.Write(row.csvRecord()); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
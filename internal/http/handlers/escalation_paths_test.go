@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestBuildEscalationPathRowsScopesAndLabels(t *testing.T) {
+	t.Parallel()
+
+	scope, err := loadOrgScope(context.Background(), fakeOrgScopeQueries{sources: []gen.OrgSource{
+		{SourceKind: "google_workspace", SourceName: "globex.com", OrgID: 3},
+	}}, 0)
+	if err != nil {
+		t.Fatalf("loadOrgScope() error = %v", err)
+	}
+	grants := func(sourceName string) []gen.ListActiveEntitlementsForEscalationRow {
+		return []gen.ListActiveEntitlementsForEscalationRow{
+			{
+				EntitlementKind:           "google_group_member",
+				EntitlementResource:       "google_group:g1",
+				EntitlementPermission:     "manager",
+				EntitlementPrivilegeLevel: int16(registry.PrivilegeAdmin),
+				AppUserSourceKind:         "google_workspace",
+				AppUserSourceName:         sourceName,
+				AppUserExternalID:         "u1",
+				AppUserEmail:              "alice@example.com",
+			},
+			{
+				EntitlementKind:           "google_admin_role",
+				EntitlementResource:       "google_admin_role:r1",
+				EntitlementPermission:     "global",
+				EntitlementPrivilegeLevel: int16(registry.PrivilegeOwner),
+				EntitlementRawJson:        []byte(`{"role_name":"Super Admin"}`),
+				AppUserSourceKind:         "google_workspace",
+				AppUserSourceName:         sourceName,
+				AppUserExternalID:         "g1",
+				AppUserDisplayName:        "Admins",
+			},
+		}
+	}
+	rows := buildEscalationPathRows(append(grants("example.com"), grants("globex.com")...), scope)
+
+	if len(rows) != 1 {
+		t.Fatalf("len(rows) = %d, want 1 (other org's source filtered out)", len(rows))
+	}
+	row := rows[0]
+	if row.SourceName != "example.com" || row.AppUserEmail != "alice@example.com" {
+		t.Fatalf("row = %+v, want alice in example.com", row)
+	}
+	if row.HeldLevel != "admin" || row.ReachableLevel != "owner" || row.ReachableClass != badgeClassDanger() {
+		t.Fatalf("levels = %q -> %q (%q), want admin -> owner (danger)", row.HeldLevel, row.ReachableLevel, row.ReachableClass)
+	}
+	if got, want := escalationPathText(row.Steps), "alice@example.com -manages-> g1 -acts as-> Admins -holds-> r1"; got != want {
+		t.Fatalf("path = %q, want %q", got, want)
+	}
+}
@@ -18,6 +18,11 @@ func (h *Handlers) HandleGlobalView(c *echo.Context) error {
 	if err != nil {
 		return h.RenderError(c, err)
 	}
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	states = scope.filterStates(states)
 
 	cards := make([]viewmodels.GlobalViewAppCard, 0, len(states))
 	for _, state := range states {
@@ -366,6 +366,7 @@ func (h *Handlers) HandleGoogleWorkspaceOAuthApps(c *echo.Context) error {
 	}
 
 	items := make([]viewmodels.GoogleWorkspaceOAuthAppListItem, 0, len(assets))
+	loc := h.displayLocation(c)
 	for _, asset := range assets {
 		displayName := strings.TrimSpace(asset.DisplayName)
 		if displayName == "" {
@@ -381,7 +382,7 @@ func (h *Handlers) HandleGoogleWorkspaceOAuthApps(c *echo.Context) error {
 			Status:      fallbackDash(strings.TrimSpace(asset.Status)),
 			OwnerCount:  ownerCounts[asset.ID],
 			GrantCount:  grantCounts[asset.ID],
-			LastSeenAt:  formatProgrammaticDate(asset.LastObservedAt),
+			LastSeenAt:  formatProgrammaticDate(asset.LastObservedAt, loc),
 		})
 	}
 
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -27,8 +28,13 @@ func (h *Handlers) HandleIdentities(c *echo.Context) error {
 		return h.RenderError(c, err)
 	}
 
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
 	const perPage = 20
-	sourcePairs := availableIdentitySourcePairs(snap)
+	sourcePairs := scopedIdentitySourcePairs(scope, availableIdentitySourcePairs(snap))
 	sourceKindOptions := identitySourceKindOptions(sourcePairs)
 	selectedSourceKind, selectedSourceName := normalizeIdentitySourceSelection(
 		c.QueryParam("source_kind"),
@@ -128,6 +134,7 @@ func (h *Handlers) HandleIdentities(c *echo.Context) error {
 	}
 
 	items := make([]viewmodels.IdentityListItem, 0, len(rows))
+	loc := h.displayLocation(c)
 	for _, row := range rows {
 		linkReason := strings.TrimSpace(row.LinkReason)
 		if linkReason == "" {
@@ -145,8 +152,8 @@ func (h *Handlers) HandleIdentities(c *echo.Context) error {
 			PrivilegedRoles:   row.PrivilegedRoles,
 			Status:            strings.TrimSpace(row.Status),
 			ActivityState:     strings.TrimSpace(row.ActivityState),
-			LastSeenOn:        identityCalendarDate(row.LastSeenAt),
-			FirstSeenOn:       identityCalendarDate(row.FirstSeenAt),
+			LastSeenOn:        identityCalendarDate(row.LastSeenAt, loc),
+			FirstSeenOn:       identityCalendarDate(row.FirstSeenAt, loc),
 			LinkQuality:       strings.TrimSpace(row.LinkQuality),
 			LinkReason:        linkReason,
 			MinLinkConfidence: row.MinLinkConfidence,
@@ -172,6 +179,18 @@ func (h *Handlers) HandleIdentities(c *echo.Context) error {
 	return renderIdentities()
 }
 
+// scopedIdentitySourcePairs keeps the sources the request's org may read, so the inventory only
+// lists identities with an account in one of them and counts only those accounts.
+func scopedIdentitySourcePairs(scope orgScope, pairs []viewmodels.ProgrammaticSourceOption) []viewmodels.ProgrammaticSourceOption {
+	out := make([]viewmodels.ProgrammaticSourceOption, 0, len(pairs))
+	for _, pair := range pairs {
+		if scope.AllowsSource(pair.SourceKind, pair.SourceName) {
+			out = append(out, pair)
+		}
+	}
+	return out
+}
+
 func availableIdentitySourcePairs(snap ConnectorSnapshot) []viewmodels.ProgrammaticSourceOption {
 	out := make([]viewmodels.ProgrammaticSourceOption, 0, 7)
 	appendSource := func(kind, sourceName string) {
@@ -212,6 +231,9 @@ func availableIdentitySourcePairs(snap ConnectorSnapshot) []viewmodels.Programma
 	if snap.VaultConfigured {
 		appendSource("vault", snap.Vault.SourceName())
 	}
+	if snap.BitbucketConfigured {
+		appendSource(configstore.KindBitbucket, snap.Bitbucket.Workspace)
+	}
 
 	seen := map[string]struct{}{}
 	deduped := make([]viewmodels.ProgrammaticSourceOption, 0, len(out))
@@ -546,11 +568,11 @@ func identityNameSecondary(displayName, primaryEmail string) string {
 	return primaryEmail
 }
 
-func identityCalendarDate(value pgtype.Timestamptz) string {
+func identityCalendarDate(value pgtype.Timestamptz, loc *time.Location) string {
 	if !value.Valid {
 		return "—"
 	}
-	return value.Time.UTC().Format("Jan 2, 2006")
+	return inDisplayLocation(value.Time, loc).Format("Jan 2, 2006")
 }
 
 func (h *Handlers) HandleIdentityShow(c *echo.Context) error {
@@ -577,6 +599,14 @@ func (h *Handlers) HandleIdentityShow(c *echo.Context) error {
 	if err != nil {
 		return h.RenderError(c, err)
 	}
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	if !scope.AllowsIdentity(accounts) {
+		return c.String(http.StatusNotFound, "identity not found")
+	}
+	accounts = scope.filterAccounts(accounts)
 
 	entitlementsByAccountID := make(map[int64]int, len(accounts))
 	if len(accounts) > 0 {
@@ -602,16 +632,16 @@ func (h *Handlers) HandleIdentityShow(c *echo.Context) error {
 		})
 	}
 
-	programmaticAccessHref := ""
-	if email := strings.TrimSpace(summary.PrimaryEmail); email != "" {
-		programmaticAccessHref = "/credentials?q=" + url.QueryEscape(email)
-	}
+	identityParam := strconv.FormatInt(summary.ID, 10)
+	programmaticAccessHref := "/credentials?created_by=" + identityParam
+	ownedAssetsHref := "/app-assets?owner_identity=" + identityParam
 
 	return h.RenderComponent(c, views.IdentityShowPage(viewmodels.IdentityShowViewData{
 		Layout:                 layout,
 		Identity:               summary,
 		LinkedAccounts:         linkedAccounts,
 		ProgrammaticAccessHref: programmaticAccessHref,
+		OwnedAssetsHref:        ownedAssetsHref,
 		HasLinkedAccounts:      len(linkedAccounts) > 0,
 	}))
 }
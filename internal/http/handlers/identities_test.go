@@ -102,12 +102,12 @@ func TestIdentityCalendarDate(t *testing.T) {
 	got := identityCalendarDate(pgtype.Timestamptz{
 		Time:  time.Date(2026, time.February, 8, 23, 11, 0, 0, time.FixedZone("PST", -8*60*60)),
 		Valid: true,
-	})
+	}, time.UTC)
 	if got != "Feb 9, 2026" {
 		t.Fatalf("identityCalendarDate(valid) = %q, want %q", got, "Feb 9, 2026")
 	}
 
-	if got := identityCalendarDate(pgtype.Timestamptz{}); got != "—" {
+	if got := identityCalendarDate(pgtype.Timestamptz{}, time.UTC); got != "—" {
 		t.Fatalf("identityCalendarDate(invalid) = %q, want %q", got, "—")
 	}
 }
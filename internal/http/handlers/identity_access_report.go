@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/accessgraph"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+	"github.com/open-sspm/open-sspm/internal/http/views"
+	"github.com/open-sspm/open-sspm/internal/matching"
+)
+
+// identityAccessReportRowLimit caps each category of the offboarding report.
+const identityAccessReportRowLimit = 1000
+
+type identityAccessReportQueries interface {
+	GetIdentitySummaryByID(ctx context.Context, id int64) (gen.GetIdentitySummaryByIDRow, error)
+	ListLinkedAccountsForIdentity(ctx context.Context, identityID int64) ([]gen.Account, error)
+	ListEntitlementsForAppUserIDs(ctx context.Context, appUserIds []int64) ([]gen.Entitlement, error)
+	ListOktaUserAppAssignmentsForIdpUser(ctx context.Context, oktaUserAccountID int64) ([]gen.ListOktaUserAppAssignmentsForIdpUserRow, error)
+	ListCredentialArtifactsByActorAccounts(ctx context.Context, arg gen.ListCredentialArtifactsByActorAccountsParams) ([]gen.CredentialArtifact, error)
+	ListAppAssetsOwnedByAccounts(ctx context.Context, arg gen.ListAppAssetsOwnedByAccountsParams) ([]gen.AppAsset, error)
+	ListSaaSAppOAuthGrantsByActors(ctx context.Context, arg gen.ListSaaSAppOAuthGrantsByActorsParams) ([]gen.ListSaaSAppOAuthGrantsByActorsRow, error)
+}
+
+// identityReportActors is every way an identity shows up as an actor in source data: its linked
+// accounts as parallel source/external id arrays, and the emails it is known by. It is resolved
+// once per report and shared by the credential, asset owner, and OAuth grant queries.
+type identityReportActors struct {
+	SourceKinds []string
+	SourceNames []string
+	ExternalIDs []string
+	Emails      []string
+	keys        map[string]struct{}
+}
+
+func newIdentityReportActors(primaryEmail string, accounts []gen.Account) identityReportActors {
+	actors := identityReportActors{keys: make(map[string]struct{}, len(accounts))}
+	emailSet := map[string]struct{}{}
+	addEmail := func(raw string) {
+		email := matching.NormalizeEmail(raw)
+		if email == "" {
+			return
+		}
+		if _, ok := emailSet[email]; ok {
+			return
+		}
+		emailSet[email] = struct{}{}
+		actors.Emails = append(actors.Emails, email)
+	}
+
+	addEmail(primaryEmail)
+	for _, account := range accounts {
+		addEmail(account.Email)
+		addEmail(account.ExternalID)
+
+		sourceKind := strings.TrimSpace(account.SourceKind)
+		sourceName := strings.TrimSpace(account.SourceName)
+		externalID := strings.ToLower(strings.TrimSpace(account.ExternalID))
+		if sourceKind == "" || sourceName == "" || externalID == "" {
+			continue
+		}
+		key := discoveryActorSourceKey(sourceKind, sourceName, externalID)
+		if _, ok := actors.keys[key]; ok {
+			continue
+		}
+		actors.keys[key] = struct{}{}
+		actors.SourceKinds = append(actors.SourceKinds, sourceKind)
+		actors.SourceNames = append(actors.SourceNames, sourceName)
+		actors.ExternalIDs = append(actors.ExternalIDs, externalID)
+	}
+	return actors
+}
+
+// matches reports whether an actor recorded on a source row is one of the identity's accounts.
+func (a identityReportActors) matches(sourceKind, sourceName, externalID string) bool {
+	if strings.TrimSpace(externalID) == "" {
+		return false
+	}
+	_, ok := a.keys[discoveryActorSourceKey(sourceKind, sourceName, externalID)]
+	return ok
+}
+
+func (h *Handlers) HandleIdentityAccessReport(c *echo.Context) error {
+	ctx := c.Request().Context()
+	layout, _, err := h.LayoutData(ctx, c, "Identity access report")
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
+	id, err := strconv.ParseInt(strings.TrimSpace(c.Param("id")), 10, 64)
+	if err != nil || id <= 0 {
+		return c.String(http.StatusBadRequest, "invalid identity id")
+	}
+
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
+	data, err := buildIdentityAccessReport(ctx, h.Q, id, scope, h.credentialRiskPolicy(), time.Now(), h.displayLocation(c))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.String(http.StatusNotFound, "identity not found")
+		}
+		return h.RenderError(c, err)
+	}
+	data.Layout = layout
+	return h.RenderComponent(c, views.IdentityAccessReportPage(data))
+}
+
+// buildIdentityAccessReport gathers an identity's access and the artifacts tied to it across
+// every source the request may see. A missing identity, or one outside the scope, is reported
+// as pgx.ErrNoRows.
+func buildIdentityAccessReport(ctx context.Context, q identityAccessReportQueries, identityID int64, scope orgScope, policy credentialRiskPolicy, now time.Time, loc *time.Location) (viewmodels.IdentityAccessReportViewData, error) {
+	summary, err := q.GetIdentitySummaryByID(ctx, identityID)
+	if err != nil {
+		return viewmodels.IdentityAccessReportViewData{}, err
+	}
+	linked, err := q.ListLinkedAccountsForIdentity(ctx, identityID)
+	if err != nil {
+		return viewmodels.IdentityAccessReportViewData{}, err
+	}
+
+	if !scope.AllowsIdentity(linked) {
+		return viewmodels.IdentityAccessReportViewData{}, pgx.ErrNoRows
+	}
+	accounts := scope.filterAccounts(linked)
+	data := viewmodels.IdentityAccessReportViewData{Identity: summary}
+
+	data.Accounts, err = identityAccessAccounts(ctx, q, accounts)
+	if err != nil {
+		return viewmodels.IdentityAccessReportViewData{}, err
+	}
+
+	actors := newIdentityReportActors(summary.PrimaryEmail, accounts)
+	if len(actors.ExternalIDs) > 0 {
+		credentials, err := q.ListCredentialArtifactsByActorAccounts(ctx, gen.ListCredentialArtifactsByActorAccountsParams{
+			SourceKinds: actors.SourceKinds,
+			SourceNames: actors.SourceNames,
+			ExternalIds: actors.ExternalIDs,
+			RowLimit:    identityAccessReportRowLimit,
+		})
+		if err != nil {
+			return viewmodels.IdentityAccessReportViewData{}, err
+		}
+		data.Truncated = data.Truncated || len(credentials) >= identityAccessReportRowLimit
+		for _, credential := range credentials {
+			if !scope.AllowsSource(credential.SourceKind, credential.SourceName) {
+				continue
+			}
+			data.Credentials = append(data.Credentials, viewmodels.IdentityAccessCredentialItem{
+				ID:             credential.ID,
+				Href:           "/credentials/" + strconv.FormatInt(credential.ID, 10),
+				Name:           firstNonEmpty(credential.DisplayName, credential.ExternalID),
+				CredentialKind: credential.CredentialKind,
+				SourceLabel:    sourceDiagnosticLabel(credential.SourceKind, credential.SourceName),
+				Status:         strings.TrimSpace(credential.Status),
+				Role:           identityCredentialRole(credential, actors),
+				ExpiresAt:      formatProgrammaticDate(credential.ExpiresAtSource, loc),
+				RiskLevel:      credentialRiskLevel(credential, now, policy),
+			})
+		}
+
+		assets, err := q.ListAppAssetsOwnedByAccounts(ctx, gen.ListAppAssetsOwnedByAccountsParams{
+			SourceKinds: actors.SourceKinds,
+			SourceNames: actors.SourceNames,
+			ExternalIds: actors.ExternalIDs,
+			RowLimit:    identityAccessReportRowLimit,
+		})
+		if err != nil {
+			return viewmodels.IdentityAccessReportViewData{}, err
+		}
+		data.Truncated = data.Truncated || len(assets) >= identityAccessReportRowLimit
+		for _, asset := range assets {
+			if !scope.AllowsSource(asset.SourceKind, asset.SourceName) {
+				continue
+			}
+			data.OwnedAssets = append(data.OwnedAssets, viewmodels.IdentityAccessAssetItem{
+				ID:          asset.ID,
+				Href:        "/app-assets/" + strconv.FormatInt(asset.ID, 10),
+				Name:        firstNonEmpty(asset.DisplayName, asset.ExternalID),
+				AssetKind:   asset.AssetKind,
+				SourceLabel: sourceDiagnosticLabel(asset.SourceKind, asset.SourceName),
+				Status:      strings.TrimSpace(asset.Status),
+			})
+		}
+	}
+
+	if len(actors.ExternalIDs) > 0 || len(actors.Emails) > 0 {
+		grants, err := q.ListSaaSAppOAuthGrantsByActors(ctx, gen.ListSaaSAppOAuthGrantsByActorsParams{
+			SourceKinds: actors.SourceKinds,
+			SourceNames: actors.SourceNames,
+			ExternalIds: actors.ExternalIDs,
+			Emails:      actors.Emails,
+			RowLimit:    identityAccessReportRowLimit,
+		})
+		if err != nil {
+			return viewmodels.IdentityAccessReportViewData{}, err
+		}
+		data.Truncated = data.Truncated || len(grants) >= identityAccessReportRowLimit
+		for _, grant := range grants {
+			if !scope.AllowsSource(grant.SourceKind, grant.SourceName) {
+				continue
+			}
+			data.OAuthGrants = append(data.OAuthGrants, viewmodels.IdentityAccessOAuthGrantItem{
+				SaaSAppID:       grant.SaasAppID,
+				Href:            discoveryAppHref(grant.SaasAppID),
+				AppName:         firstNonEmpty(grant.SaasAppDisplayName, grant.SaasAppCanonicalKey),
+				SourceLabel:     sourceDiagnosticLabel(grant.SourceKind, grant.SourceName),
+				ScopesSummary:   summarizeDiscoveryScopes(grant.LatestScopesJson),
+				EventCount:      grant.EventCount,
+				FirstObservedAt: formatProgrammaticDate(grant.FirstObservedAt, loc),
+				LastObservedAt:  formatProgrammaticDate(grant.LastObservedAt, loc),
+			})
+		}
+	}
+
+	return data, nil
+}
+
+// identityAccessAccounts lists each linked account with its entitlements. Okta accounts carry
+// app assignments rather than entitlements, so those are listed as app entitlements.
+func identityAccessAccounts(ctx context.Context, q identityAccessReportQueries, accounts []gen.Account) ([]viewmodels.IdentityAccessAccountItem, error) {
+	entitlementsByAccountID := make(map[int64][]gen.Entitlement, len(accounts))
+	if len(accounts) > 0 {
+		accountIDs := make([]int64, 0, len(accounts))
+		for _, account := range accounts {
+			accountIDs = append(accountIDs, account.ID)
+		}
+		entitlements, err := q.ListEntitlementsForAppUserIDs(ctx, accountIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, entitlement := range entitlements {
+			entitlementsByAccountID[entitlement.AppUserID] = append(entitlementsByAccountID[entitlement.AppUserID], entitlement)
+		}
+	}
+
+	items := make([]viewmodels.IdentityAccessAccountItem, 0, len(accounts))
+	for _, account := range accounts {
+		sourceKind := strings.TrimSpace(account.SourceKind)
+		sourceName := strings.TrimSpace(account.SourceName)
+		item := viewmodels.IdentityAccessAccountItem{
+			SourceKind:  sourceKind,
+			SourceName:  sourceName,
+			SourceLabel: sourceDiagnosticLabel(sourceKind, sourceName),
+			Label:       firstNonEmpty(account.Email, account.DisplayName, account.ExternalID),
+			ExternalID:  strings.TrimSpace(account.ExternalID),
+			Email:       strings.TrimSpace(account.Email),
+			DisplayName: strings.TrimSpace(account.DisplayName),
+			Status:      strings.TrimSpace(account.Status),
+			DetailHref:  linkedAccountDetailHref(account),
+		}
+		for _, entitlement := range entitlementsByAccountID[account.ID] {
+			resource := strings.TrimSpace(entitlement.Resource)
+			level := registry.PrivilegeLevel(entitlement.PrivilegeLevel)
+			label := accessgraph.DisplayResourceLabel(resource, entitlement.RawJson)
+			if label == "" {
+				label = resource
+			}
+			item.Entitlements = append(item.Entitlements, viewmodels.IdentityAccessEntitlementItem{
+				Kind:           strings.TrimSpace(entitlement.Kind),
+				ResourceLabel:  label,
+				ResourceHref:   accessgraph.BuildResourceHrefFromResourceRef(sourceKind, sourceName, resource),
+				Permission:     strings.TrimSpace(entitlement.Permission),
+				PrivilegeLevel: level.String(),
+				PrivilegeClass: privilegeLevelClass(level),
+			})
+		}
+		if strings.EqualFold(sourceKind, "okta") {
+			assignments, err := q.ListOktaUserAppAssignmentsForIdpUser(ctx, account.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, assignment := range assignments {
+				item.Entitlements = append(item.Entitlements, viewmodels.IdentityAccessEntitlementItem{
+					Kind:           "okta_app_assignment",
+					ResourceLabel:  firstNonEmpty(assignment.AppLabel, assignment.AppName, assignment.OktaAppExternalID),
+					ResourceHref:   "/apps/" + url.PathEscape(assignment.OktaAppExternalID),
+					Permission:     strings.ToLower(strings.TrimSpace(assignment.Scope)),
+					PrivilegeLevel: registry.PrivilegeUnknown.String(),
+					PrivilegeClass: privilegeLevelClass(registry.PrivilegeUnknown),
+				})
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func identityCredentialRole(credential gen.CredentialArtifact, actors identityReportActors) string {
+	created := actors.matches(credential.SourceKind, credential.SourceName, credential.CreatedByExternalID)
+	approved := actors.matches(credential.SourceKind, credential.SourceName, credential.ApprovedByExternalID)
+	switch {
+	case created && approved:
+		return "Created and approved"
+	case approved:
+		return "Approved"
+	default:
+		return "Created"
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
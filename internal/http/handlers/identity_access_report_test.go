@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+type fakeIdentityAccessReportQueries struct {
+	accounts     []gen.Account
+	entitlements []gen.Entitlement
+	assignments  map[int64][]gen.ListOktaUserAppAssignmentsForIdpUserRow
+	credentials  []gen.CredentialArtifact
+	assets       []gen.AppAsset
+	grants       []gen.ListSaaSAppOAuthGrantsByActorsRow
+
+	credentialArgs []gen.ListCredentialArtifactsByActorAccountsParams
+	assetArgs      []gen.ListAppAssetsOwnedByAccountsParams
+	grantArgs      []gen.ListSaaSAppOAuthGrantsByActorsParams
+}
+
+func (f *fakeIdentityAccessReportQueries) GetIdentitySummaryByID(_ context.Context, id int64) (gen.GetIdentitySummaryByIDRow, error) {
+	return gen.GetIdentitySummaryByIDRow{ID: id, DisplayName: "Alice Doe", PrimaryEmail: "Alice@Example.com"}, nil
+}
+
+func (f *fakeIdentityAccessReportQueries) ListLinkedAccountsForIdentity(context.Context, int64) ([]gen.Account, error) {
+	return f.accounts, nil
+}
+
+func (f *fakeIdentityAccessReportQueries) ListEntitlementsForAppUserIDs(context.Context, []int64) ([]gen.Entitlement, error) {
+	return f.entitlements, nil
+}
+
+func (f *fakeIdentityAccessReportQueries) ListOktaUserAppAssignmentsForIdpUser(_ context.Context, accountID int64) ([]gen.ListOktaUserAppAssignmentsForIdpUserRow, error) {
+	return f.assignments[accountID], nil
+}
+
+func (f *fakeIdentityAccessReportQueries) ListCredentialArtifactsByActorAccounts(_ context.Context, arg gen.ListCredentialArtifactsByActorAccountsParams) ([]gen.CredentialArtifact, error) {
+	f.credentialArgs = append(f.credentialArgs, arg)
+	return f.credentials, nil
+}
+
+func (f *fakeIdentityAccessReportQueries) ListAppAssetsOwnedByAccounts(_ context.Context, arg gen.ListAppAssetsOwnedByAccountsParams) ([]gen.AppAsset, error) {
+	f.assetArgs = append(f.assetArgs, arg)
+	return f.assets, nil
+}
+
+func (f *fakeIdentityAccessReportQueries) ListSaaSAppOAuthGrantsByActors(_ context.Context, arg gen.ListSaaSAppOAuthGrantsByActorsParams) ([]gen.ListSaaSAppOAuthGrantsByActorsRow, error) {
+	f.grantArgs = append(f.grantArgs, arg)
+	return f.grants, nil
+}
+
+func TestBuildIdentityAccessReportPopulatesEveryCategory(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	q := &fakeIdentityAccessReportQueries{
+		accounts: []gen.Account{
+			{ID: 10, SourceKind: "okta", SourceName: "acme.okta.com", ExternalID: "00u1", Email: "alice@example.com"},
+			{ID: 11, SourceKind: "github", SourceName: "acme", ExternalID: "AliceGH", Email: "alice@users.noreply.github.com"},
+		},
+		entitlements: []gen.Entitlement{
+			{AppUserID: 11, Kind: "github_team_repo_permission", Resource: "github_repo:acme/api", Permission: "admin", PrivilegeLevel: 3},
+		},
+		assignments: map[int64][]gen.ListOktaUserAppAssignmentsForIdpUserRow{
+			10: {{OktaAppExternalID: "0oa1", AppLabel: "Billing", Scope: "USER"}},
+		},
+		credentials: []gen.CredentialArtifact{
+			{ID: 1, SourceKind: "github", SourceName: "acme", CredentialKind: "github_deploy_key", DisplayName: "deploy", Status: "active", CreatedByExternalID: "alicegh"},
+			{ID: 2, SourceKind: "github", SourceName: "acme", CredentialKind: "github_pat_request", DisplayName: "pat", Status: "approved", CreatedByExternalID: "bob", ApprovedByExternalID: "AliceGH"},
+			{ID: 3, SourceKind: "okta", SourceName: "acme.okta.com", CredentialKind: "okta_oidc_client_secret", ExternalID: "ocs1", Status: "active", CreatedByExternalID: "00u1", ApprovedByExternalID: "00u1"},
+		},
+		assets: []gen.AppAsset{
+			{ID: 7, SourceKind: "okta", SourceName: "acme.okta.com", AssetKind: "okta_app", ExternalID: "0oa1", DisplayName: "Billing", Status: "active"},
+		},
+		grants: []gen.ListSaaSAppOAuthGrantsByActorsRow{
+			{SaasAppID: 42, SaasAppCanonicalKey: "notion.so", SourceKind: "google_workspace", SourceName: "example.com", EventCount: 3, LatestScopesJson: []byte(`["openid","email"]`)},
+		},
+	}
+
+	data, err := buildIdentityAccessReport(context.Background(), q, 5, orgScope{}, builtinCredentialRiskPolicy(), now, time.UTC)
+	if err != nil {
+		t.Fatalf("buildIdentityAccessReport() error = %v", err)
+	}
+
+	if len(data.Accounts) != 2 {
+		t.Fatalf("len(Accounts) = %d, want 2", len(data.Accounts))
+	}
+	okta, github := data.Accounts[0], data.Accounts[1]
+	if len(okta.Entitlements) != 1 || okta.Entitlements[0].ResourceLabel != "Billing" || okta.Entitlements[0].ResourceHref != "/apps/0oa1" {
+		t.Fatalf("okta entitlements = %+v, want the Billing app assignment", okta.Entitlements)
+	}
+	if len(github.Entitlements) != 1 || github.Entitlements[0].PrivilegeLevel != "admin" {
+		t.Fatalf("github entitlements = %+v, want one admin entitlement", github.Entitlements)
+	}
+
+	if len(data.Credentials) != 3 {
+		t.Fatalf("len(Credentials) = %d, want 3", len(data.Credentials))
+	}
+	roles := []string{data.Credentials[0].Role, data.Credentials[1].Role, data.Credentials[2].Role}
+	if !slices.Equal(roles, []string{"Created", "Approved", "Created and approved"}) {
+		t.Fatalf("credential roles = %v", roles)
+	}
+	if data.Credentials[2].Name != "ocs1" || data.Credentials[0].Href != "/credentials/1" {
+		t.Fatalf("credentials = %+v", data.Credentials)
+	}
+
+	if len(data.OwnedAssets) != 1 || data.OwnedAssets[0].Href != "/app-assets/7" {
+		t.Fatalf("OwnedAssets = %+v", data.OwnedAssets)
+	}
+	if len(data.OAuthGrants) != 1 || data.OAuthGrants[0].AppName != "notion.so" || data.OAuthGrants[0].ScopesSummary != "openid, email" {
+		t.Fatalf("OAuthGrants = %+v", data.OAuthGrants)
+	}
+	if data.Truncated {
+		t.Fatalf("Truncated = true, want false")
+	}
+
+	// The identity's actors are resolved once and the same arrays reach every query.
+	if len(q.credentialArgs) != 1 || len(q.assetArgs) != 1 || len(q.grantArgs) != 1 {
+		t.Fatalf("query calls = %d/%d/%d, want one each", len(q.credentialArgs), len(q.assetArgs), len(q.grantArgs))
+	}
+	wantExternalIDs := []string{"00u1", "alicegh"}
+	if !slices.Equal(q.credentialArgs[0].ExternalIds, wantExternalIDs) || !slices.Equal(q.assetArgs[0].ExternalIds, wantExternalIDs) || !slices.Equal(q.grantArgs[0].ExternalIds, wantExternalIDs) {
+		t.Fatalf("external ids = %v / %v / %v, want %v", q.credentialArgs[0].ExternalIds, q.assetArgs[0].ExternalIds, q.grantArgs[0].ExternalIds, wantExternalIDs)
+	}
+	if !slices.Equal(q.grantArgs[0].Emails, []string{"alice@example.com", "alice@users.noreply.github.com"}) {
+		t.Fatalf("grant emails = %v", q.grantArgs[0].Emails)
+	}
+}
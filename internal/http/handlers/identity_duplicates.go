@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+	"github.com/open-sspm/open-sspm/internal/http/views"
+	"github.com/open-sspm/open-sspm/internal/identity"
+)
+
+const identityDuplicatesLimit = 200
+
+// HandleIdentityDuplicates lists open suggestions to merge identities that probably belong to the
+// same person. Suggestions are refreshed by identity resolution after every sync.
+func (h *Handlers) HandleIdentityDuplicates(c *echo.Context) error {
+	ctx := c.Request().Context()
+	layout, _, err := h.LayoutData(ctx, c, "Duplicate Identities")
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
+	rows, err := h.Q.ListOpenIdentityMergeSuggestions(ctx, identityDuplicatesLimit+1)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	identityIDs := make([]int64, 0, 2*len(rows))
+	for _, row := range rows {
+		identityIDs = append(identityIDs, row.SurvivorIdentityID, row.DuplicateIdentityID)
+	}
+	owned, err := identitiesOwnedByScope(ctx, h.Q, scope, identityIDs)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	data := viewmodels.IdentityDuplicatesViewData{
+		Layout:        layout,
+		EmptyStateMsg: "No likely duplicate identities were found.",
+	}
+	if len(rows) > identityDuplicatesLimit {
+		rows = rows[:identityDuplicatesLimit]
+		data.Truncated = true
+	}
+
+	loc := h.displayLocation(c)
+	data.Items = make([]viewmodels.IdentityMergeSuggestionItem, 0, len(rows))
+	for _, row := range rows {
+		if !owned[row.SurvivorIdentityID] || !owned[row.DuplicateIdentityID] {
+			continue
+		}
+		data.Items = append(data.Items, viewmodels.IdentityMergeSuggestionItem{
+			ID:                     row.ID,
+			Score:                  strconv.Itoa(int(row.Score*100+0.5)) + "%",
+			Reasons:                row.Reasons,
+			UpdatedAt:              formatProgrammaticDate(row.UpdatedAt, loc),
+			SurvivorID:             row.SurvivorIdentityID,
+			SurvivorNamePrimary:    identityNamePrimary(row.SurvivorDisplayName, row.SurvivorPrimaryEmail, row.SurvivorIdentityID),
+			SurvivorNameSecondary:  identityNameSecondary(row.SurvivorDisplayName, row.SurvivorPrimaryEmail),
+			SurvivorAccounts:       row.SurvivorAccountCount,
+			DuplicateID:            row.DuplicateIdentityID,
+			DuplicateNamePrimary:   identityNamePrimary(row.DuplicateDisplayName, row.DuplicatePrimaryEmail, row.DuplicateIdentityID),
+			DuplicateNameSecondary: identityNameSecondary(row.DuplicateDisplayName, row.DuplicatePrimaryEmail),
+			DuplicateAccounts:      row.DuplicateAccountCount,
+		})
+	}
+	return h.RenderComponent(c, views.IdentityDuplicatesPage(data))
+}
+
+// HandleIdentityMergeAccept merges the duplicate identity of a suggestion into its survivor: the
+// duplicate's linked app accounts and owned apps move to the survivor and the duplicate is
+// deleted, so later syncs cannot link new accounts to it by email.
+func (h *Handlers) HandleIdentityMergeAccept(c *echo.Context) error {
+	suggestionID, err := strconv.ParseInt(strings.TrimSpace(c.Param("id")), 10, 64)
+	if err != nil || suggestionID <= 0 {
+		return c.String(http.StatusBadRequest, "invalid suggestion id")
+	}
+	if h.Pool == nil {
+		return h.RenderError(c, errors.New("database pool not configured"))
+	}
+
+	ctx := c.Request().Context()
+	tx, err := h.Pool.Begin(ctx)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := h.Q.WithTx(tx)
+	suggestion, err := qtx.GetOpenIdentityMergeSuggestionForUpdate(ctx, suggestionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return identityMergeGone(c)
+		}
+		return h.RenderError(c, err)
+	}
+	inScope, err := h.identityMergeSuggestionInScope(c, qtx, suggestion)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	if !inScope {
+		return c.String(http.StatusNotFound, "suggestion not found")
+	}
+	survivorID := suggestion.SurvivorIdentityID
+	duplicateID := suggestion.LowIdentityID
+	if duplicateID == survivorID {
+		duplicateID = suggestion.HighIdentityID
+	}
+
+	moved, err := qtx.MoveIdentityAccountLinks(ctx, gen.MoveIdentityAccountLinksParams{
+		ToIdentityID:   survivorID,
+		FromIdentityID: duplicateID,
+	})
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	if err := qtx.ReassignSaaSAppGovernanceOwnerIdentity(ctx, gen.ReassignSaaSAppGovernanceOwnerIdentityParams{
+		ToIdentityID:   survivorID,
+		FromIdentityID: duplicateID,
+	}); err != nil {
+		return h.RenderError(c, err)
+	}
+	if err := qtx.DeleteIdentity(ctx, duplicateID); err != nil {
+		return h.RenderError(c, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return h.RenderError(c, err)
+	}
+
+	// Recompute the survivor's display name and email from its new accounts.
+	if _, err := identity.Resolve(ctx, h.Q); err != nil {
+		slog.Warn("identity resolution after merge failed", "survivor_identity_id", survivorID, "err", err)
+	}
+
+	setAuditDetail(c, auditDetail{
+		Target: strconv.FormatInt(survivorID, 10),
+		After: map[string]int64{
+			"survivor_identity_id":  survivorID,
+			"duplicate_identity_id": duplicateID,
+			"moved_accounts":        moved,
+		},
+	})
+	setFlashToast(c, viewmodels.ToastViewData{
+		Category:    "success",
+		Title:       "Identities merged",
+		Description: fmt.Sprintf("Moved %d linked account(s) to identity %d.", moved, survivorID),
+	})
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/identities/%d", survivorID))
+}
+
+// HandleIdentityMergeDismiss records that a suggested pair is not the same person, so detection
+// does not suggest it again.
+func (h *Handlers) HandleIdentityMergeDismiss(c *echo.Context) error {
+	suggestionID, err := strconv.ParseInt(strings.TrimSpace(c.Param("id")), 10, 64)
+	if err != nil || suggestionID <= 0 {
+		return c.String(http.StatusBadRequest, "invalid suggestion id")
+	}
+	ctx := c.Request().Context()
+	suggestion, err := h.Q.GetOpenIdentityMergeSuggestionForUpdate(ctx, suggestionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return identityMergeGone(c)
+		}
+		return h.RenderError(c, err)
+	}
+	inScope, err := h.identityMergeSuggestionInScope(c, h.Q, suggestion)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	if !inScope {
+		return c.String(http.StatusNotFound, "suggestion not found")
+	}
+	dismissed, err := h.Q.DismissIdentityMergeSuggestion(ctx, gen.DismissIdentityMergeSuggestionParams{
+		DecidedByAuthUserID: principalUserID(c),
+		ID:                  suggestionID,
+	})
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	if dismissed == 0 {
+		return identityMergeGone(c)
+	}
+	setFlashToast(c, viewmodels.ToastViewData{
+		Category: "success",
+		Title:    "Suggestion dismissed",
+	})
+	return c.Redirect(http.StatusSeeOther, "/identities/duplicates")
+}
+
+// identityMergeSuggestionInScope reports whether both identities of a suggestion belong to the
+// request's org.
+func (h *Handlers) identityMergeSuggestionInScope(c *echo.Context, q *gen.Queries, suggestion gen.IdentityMergeSuggestion) (bool, error) {
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return false, err
+	}
+	owned, err := identitiesOwnedByScope(c.Request().Context(), q, scope, []int64{suggestion.LowIdentityID, suggestion.HighIdentityID})
+	if err != nil {
+		return false, err
+	}
+	return owned[suggestion.LowIdentityID] && owned[suggestion.HighIdentityID], nil
+}
+
+// identitiesOwnedByScope reports which of the identities have every linked account in the
+// scope's org. A merge moves an identity's accounts as a whole, so unlike AllowsIdentity a single
+// account from another org hides the identity.
+func identitiesOwnedByScope(ctx context.Context, q *gen.Queries, scope orgScope, identityIDs []int64) (map[int64]bool, error) {
+	rows, err := q.ListIdentityAccountSources(ctx, identityIDs)
+	if err != nil {
+		return nil, err
+	}
+	linked := make(map[int64][]gen.Account, len(identityIDs))
+	for _, row := range rows {
+		linked[row.IdentityID] = append(linked[row.IdentityID], gen.Account{SourceKind: row.SourceKind, SourceName: row.SourceName})
+	}
+	owned := make(map[int64]bool, len(identityIDs))
+	for _, id := range identityIDs {
+		accounts := linked[id]
+		owned[id] = scope.AllowsIdentity(accounts) && len(scope.filterAccounts(accounts)) == len(accounts)
+	}
+	return owned, nil
+}
+
+func identityMergeGone(c *echo.Context) error {
+	setFlashToast(c, viewmodels.ToastViewData{
+		Category:    "warning",
+		Title:       "Suggestion no longer open",
+		Description: "It was already accepted or dismissed, or the identities changed.",
+	})
+	return c.Redirect(http.StatusSeeOther, "/identities/duplicates")
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+	"github.com/open-sspm/open-sspm/internal/http/views"
+	"github.com/open-sspm/open-sspm/internal/sync"
+)
+
+// inventorySnapshotListLimit bounds the snapshot picker to roughly a year of daily snapshots of
+// both kinds.
+const inventorySnapshotListLimit int32 = 800
+
+type inventorySnapshotViewQueries interface {
+	ListInventorySnapshots(ctx context.Context, rowLimit int32) ([]gen.ListInventorySnapshotsRow, error)
+	GetInventorySnapshot(ctx context.Context, arg gen.GetInventorySnapshotParams) (gen.InventorySnapshot, error)
+}
+
+// HandleInventorySnapshots shows the credential or entitlement inventory as captured by the daily
+// snapshot for ?date (the latest by default). ?kind picks credentials or entitlements.
+func (h *Handlers) HandleInventorySnapshots(c *echo.Context) error {
+	ctx := c.Request().Context()
+	layout, _, err := h.LayoutData(ctx, c, "Inventory Snapshots")
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	data, err := buildInventorySnapshotViewData(ctx, h.Q, scope,
+		strings.TrimSpace(c.QueryParam("date")),
+		strings.TrimSpace(c.QueryParam("kind")),
+		parsePageParam(c), parsePerPageParam(c))
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	data.Layout = layout
+	return h.RenderComponent(c, views.InventorySnapshotsPage(data))
+}
+
+func buildInventorySnapshotViewData(ctx context.Context, q inventorySnapshotViewQueries, scope orgScope, date, kind string, page, perPage int) (viewmodels.InventorySnapshotsViewData, error) {
+	if kind != sync.InventorySnapshotEntitlements {
+		kind = sync.InventorySnapshotCredentials
+	}
+	data := viewmodels.InventorySnapshotsViewData{
+		SelectedKind: kind,
+		PerPage:      perPage,
+		EmptyState:   "The worker captures the first snapshot shortly after it starts.",
+	}
+
+	snapshots, err := q.ListInventorySnapshots(ctx, inventorySnapshotListLimit)
+	if err != nil {
+		return data, err
+	}
+	for _, snapshot := range snapshots {
+		day := snapshot.SnapshotDate.Time.Format(time.DateOnly)
+		if n := len(data.Dates); n == 0 || data.Dates[n-1] != day {
+			data.Dates = append(data.Dates, day)
+		}
+	}
+	if len(data.Dates) == 0 {
+		return data, nil
+	}
+	if date == "" {
+		date = data.Dates[0]
+	}
+	data.SelectedDate = date
+
+	day, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		data.EmptyState = "Pick a snapshot date from the list."
+		return data, nil
+	}
+	snapshot, err := q.GetInventorySnapshot(ctx, gen.GetInventorySnapshotParams{
+		SnapshotDate: pgtype.Date{Time: day, Valid: true},
+		Kind:         kind,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		data.EmptyState = "No " + kind + " snapshot was captured on " + date + "."
+		return data, nil
+	}
+	if err != nil {
+		return data, err
+	}
+	data.CapturedAt = discoveryReportTimestamp(snapshot.CapturedAt)
+	data.EmptyState = "No " + kind + " were active when this snapshot was taken."
+
+	var total int
+	if kind == sync.InventorySnapshotEntitlements {
+		rows, err := sync.DecodeEntitlementSnapshot(snapshot.Payload)
+		if err != nil {
+			return data, err
+		}
+		visible := rows[:0]
+		for _, row := range rows {
+			if scope.AllowsSource(row.SourceKind, row.SourceName) {
+				visible = append(visible, row)
+			}
+		}
+		total = len(visible)
+		page, totalPages, offset := paginate(int64(total), page, perPage)
+		data.Page, data.TotalPages = page, totalPages
+		for _, row := range visible[offset:min(offset+perPage, total)] {
+			data.Entitlements = append(data.Entitlements, viewmodels.InventorySnapshotEntitlementRow{
+				SourceLabel:    sourceDiagnosticLabel(row.SourceKind, row.SourceName),
+				Account:        firstNonEmpty(row.AccountEmail, row.AccountExternalID),
+				Kind:           row.Kind,
+				Resource:       row.Resource,
+				Permission:     row.Permission,
+				PrivilegeLevel: registry.PrivilegeLevel(row.PrivilegeLevel).String(),
+			})
+		}
+		data.ShowingFrom, data.ShowingTo = showingRange(int64(total), offset, len(data.Entitlements))
+	} else {
+		rows, err := sync.DecodeCredentialSnapshot(snapshot.Payload)
+		if err != nil {
+			return data, err
+		}
+		visible := rows[:0]
+		for _, row := range rows {
+			if scope.AllowsSource(row.SourceKind, row.SourceName) {
+				visible = append(visible, row)
+			}
+		}
+		total = len(visible)
+		page, totalPages, offset := paginate(int64(total), page, perPage)
+		data.Page, data.TotalPages = page, totalPages
+		for _, row := range visible[offset:min(offset+perPage, total)] {
+			assetRef := row.AssetRefExternalID
+			if row.AssetRefKind != "" && assetRef != "" {
+				assetRef = row.AssetRefKind + ":" + assetRef
+			}
+			data.Credentials = append(data.Credentials, viewmodels.InventorySnapshotCredentialRow{
+				SourceLabel:    sourceDiagnosticLabel(row.SourceKind, row.SourceName),
+				CredentialKind: row.CredentialKind,
+				ExternalID:     row.ExternalID,
+				DisplayName:    firstNonEmpty(row.DisplayName, row.ExternalID),
+				Status:         row.Status,
+				AssetRef:       assetRef,
+				ExpiresAt:      inventorySnapshotTimestamp(row.ExpiresAt),
+				LastUsedAt:     inventorySnapshotTimestamp(row.LastUsedAt),
+			})
+		}
+		data.ShowingFrom, data.ShowingTo = showingRange(int64(total), offset, len(data.Credentials))
+	}
+	data.TotalCount = int64(total)
+	data.HasRows = total > 0
+	return data, nil
+}
+
+func inventorySnapshotTimestamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
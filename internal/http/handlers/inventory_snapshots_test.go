@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/sync"
+)
+
+type fakeInventorySnapshotViewQueries struct {
+	snapshots []gen.InventorySnapshot
+}
+
+func (f *fakeInventorySnapshotViewQueries) ListInventorySnapshots(context.Context, int32) ([]gen.ListInventorySnapshotsRow, error) {
+	var out []gen.ListInventorySnapshotsRow
+	for _, snapshot := range f.snapshots {
+		out = append(out, gen.ListInventorySnapshotsRow{SnapshotDate: snapshot.SnapshotDate, Kind: snapshot.Kind, RowCount: snapshot.RowCount})
+	}
+	return out, nil
+}
+
+func (f *fakeInventorySnapshotViewQueries) GetInventorySnapshot(_ context.Context, arg gen.GetInventorySnapshotParams) (gen.InventorySnapshot, error) {
+	for _, snapshot := range f.snapshots {
+		if snapshot.SnapshotDate.Time.Equal(arg.SnapshotDate.Time) && snapshot.Kind == arg.Kind {
+			return snapshot, nil
+		}
+	}
+	return gen.InventorySnapshot{}, pgx.ErrNoRows
+}
+
+func testInventorySnapshot(t *testing.T, day, kind string, rows any) gen.InventorySnapshot {
+	t.Helper()
+	date, err := time.Parse(time.DateOnly, day)
+	if err != nil {
+		t.Fatalf("time.Parse(%q) error = %v", day, err)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(rows); err != nil {
+		t.Fatalf("encode snapshot: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close snapshot: %v", err)
+	}
+	return gen.InventorySnapshot{SnapshotDate: pgtype.Date{Time: date, Valid: true}, Kind: kind, Payload: buf.Bytes()}
+}
+
+func TestBuildInventorySnapshotViewDataShowsChosenDate(t *testing.T) {
+	t.Parallel()
+
+	q := &fakeInventorySnapshotViewQueries{snapshots: []gen.InventorySnapshot{
+		testInventorySnapshot(t, "2026-10-02", sync.InventorySnapshotCredentials, []sync.CredentialSnapshotRow{
+			{SourceKind: "github", SourceName: "acme", CredentialKind: "github_deploy_key", ExternalID: "11", Status: "revoked"},
+			{SourceKind: "github", SourceName: "acme", CredentialKind: "github_deploy_key", ExternalID: "22", Status: "active"},
+		}),
+		testInventorySnapshot(t, "2026-10-01", sync.InventorySnapshotCredentials, []sync.CredentialSnapshotRow{
+			{SourceKind: "github", SourceName: "acme", CredentialKind: "github_deploy_key", ExternalID: "11", Status: "active"},
+			{SourceKind: "github", SourceName: "other-org", CredentialKind: "github_deploy_key", ExternalID: "33", Status: "active"},
+		}),
+	}}
+	scope := testCredentialBulkScope()
+
+	latest, err := buildInventorySnapshotViewData(context.Background(), q, scope, "", "", 1, 50)
+	if err != nil {
+		t.Fatalf("buildInventorySnapshotViewData() error = %v", err)
+	}
+	if latest.SelectedDate != "2026-10-02" || len(latest.Dates) != 2 || latest.TotalCount != 2 {
+		t.Fatalf("latest = date %q, dates %v, %d rows, want 2026-10-02 of 2 dates with 2 rows", latest.SelectedDate, latest.Dates, latest.TotalCount)
+	}
+
+	asOf, err := buildInventorySnapshotViewData(context.Background(), q, scope, "2026-10-01", sync.InventorySnapshotCredentials, 1, 50)
+	if err != nil {
+		t.Fatalf("buildInventorySnapshotViewData(2026-10-01) error = %v", err)
+	}
+	if len(asOf.Credentials) != 1 || asOf.Credentials[0].ExternalID != "11" || asOf.Credentials[0].Status != "active" {
+		t.Fatalf("credentials as of 2026-10-01 = %+v, want only 11, active (other org hidden)", asOf.Credentials)
+	}
+
+	missing, err := buildInventorySnapshotViewData(context.Background(), q, scope, "2026-10-01", sync.InventorySnapshotEntitlements, 1, 50)
+	if err != nil {
+		t.Fatalf("buildInventorySnapshotViewData(entitlements) error = %v", err)
+	}
+	if missing.HasRows || missing.EmptyState == "" {
+		t.Fatalf("missing snapshot = %+v, want an empty state", missing)
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/auth"
+	"github.com/open-sspm/open-sspm/internal/auth/providers"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/authn"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+	"golang.org/x/oauth2"
+)
+
+// OIDCAuthenticator runs the IdP side of the OIDC login flow. *providers.OIDCProvider
+// implements it.
+type OIDCAuthenticator interface {
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, code, verifier, nonce string) (providers.OIDCIdentity, error)
+}
+
+// oidcUserQueries is the subset of queries used to resolve an SSO login to an auth user.
+type oidcUserQueries interface {
+	GetAuthUserByEmail(ctx context.Context, email string) (gen.AuthUser, error)
+	CreateOIDCAuthUser(ctx context.Context, arg gen.CreateOIDCAuthUserParams) (gen.AuthUser, error)
+	UpdateAuthUserRole(ctx context.Context, arg gen.UpdateAuthUserRoleParams) error
+}
+
+var (
+	errOIDCUserNotAllowed  = errors.New("oidc user is not allowed to sign in")
+	errOIDCPasswordAccount = errors.New("oidc login matches a password account")
+)
+
+// HandleOIDCLogin starts an SSO login: it binds a fresh state, nonce, and PKCE verifier to the
+// session and redirects to the IdP.
+func (h *Handlers) HandleOIDCLogin(c *echo.Context) error {
+	if h.Sessions == nil {
+		return errors.New("auth sessions not configured")
+	}
+	if h.OIDC == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "single sign-on is not configured")
+	}
+
+	ctx := c.Request().Context()
+	state, nonce, verifier := rand.Text(), rand.Text(), oauth2.GenerateVerifier()
+	location, err := h.OIDC.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return err
+	}
+	h.Sessions.Put(ctx, authn.SessionKeyOIDCState, state)
+	h.Sessions.Put(ctx, authn.SessionKeyOIDCNonce, nonce)
+	h.Sessions.Put(ctx, authn.SessionKeyOIDCVerifier, verifier)
+	h.Sessions.Put(ctx, authn.SessionKeyOIDCNext, authn.SanitizeNext(c.QueryParam("next")))
+	return c.Redirect(http.StatusSeeOther, location)
+}
+
+// HandleOIDCCallback completes an SSO login. The state must match the one bound to this session
+// before the code is redeemed; the attempt's session values are consumed either way so a
+// callback cannot be replayed.
+func (h *Handlers) HandleOIDCCallback(c *echo.Context) error {
+	if h.Sessions == nil {
+		return errors.New("auth sessions not configured")
+	}
+	if h.OIDC == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "single sign-on is not configured")
+	}
+
+	ctx := c.Request().Context()
+	expectedState := h.Sessions.PopString(ctx, authn.SessionKeyOIDCState)
+	nonce := h.Sessions.PopString(ctx, authn.SessionKeyOIDCNonce)
+	verifier := h.Sessions.PopString(ctx, authn.SessionKeyOIDCVerifier)
+	next := authn.SanitizeNext(h.Sessions.PopString(ctx, authn.SessionKeyOIDCNext))
+
+	if idpError := strings.TrimSpace(c.QueryParam("error")); idpError != "" {
+		return oidcLoginFailed(c, "The identity provider returned: "+idpError)
+	}
+	state := c.QueryParam("state")
+	if expectedState == "" || subtle.ConstantTimeCompare([]byte(state), []byte(expectedState)) != 1 {
+		return oidcLoginFailed(c, "The sign-in attempt expired or was started in another browser. Try again.")
+	}
+	code := strings.TrimSpace(c.QueryParam("code"))
+	if code == "" {
+		return oidcLoginFailed(c, "The identity provider did not return an authorization code.")
+	}
+
+	identity, err := h.OIDC.Exchange(ctx, code, verifier, nonce)
+	if err != nil {
+		slog.Warn("oidc login failed", "err", err)
+		return oidcLoginFailed(c, "The identity provider response could not be verified.")
+	}
+	role, ok := providers.OIDCRoleForGroups(identity.Groups, h.Cfg.OIDCAdminGroups, h.Cfg.OIDCAnalystGroups, h.Cfg.OIDCViewerGroups)
+	if !ok {
+		return oidcLoginFailed(c, "Your account is not in a group allowed to use Open-SSPM.")
+	}
+	user, err := resolveOIDCUser(ctx, h.Q, identity.Email, role)
+	if err != nil {
+		if errors.Is(err, errOIDCUserNotAllowed) {
+			return oidcLoginFailed(c, "Your account is disabled.")
+		}
+		if errors.Is(err, errOIDCPasswordAccount) {
+			return oidcLoginFailed(c, "Your email belongs to a password account. Sign in with your password.")
+		}
+		return err
+	}
+
+	if err := h.Sessions.RenewToken(ctx); err != nil {
+		return err
+	}
+	h.Sessions.Put(ctx, authn.SessionKeyUserID, user.ID)
+	h.Sessions.Put(ctx, authn.SessionKeyAuthMethod, auth.MethodOIDC)
+
+	_ = h.Q.UpdateAuthUserLoginMeta(ctx, gen.UpdateAuthUserLoginMetaParams{
+		ID:          user.ID,
+		LastLoginAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		LastLoginIp: strings.TrimSpace(c.RealIP()),
+	})
+
+	if next != "" {
+		return c.Redirect(http.StatusSeeOther, next)
+	}
+	return c.Redirect(http.StatusSeeOther, "/")
+}
+
+// resolveOIDCUser finds or provisions the auth user for an SSO login. Users provisioned by SSO
+// follow their IdP groups on every login. A local password account with the same email is not
+// linked, since it would keep a role the IdP groups do not grant.
+func resolveOIDCUser(ctx context.Context, q oidcUserQueries, email, role string) (gen.AuthUser, error) {
+	user, err := q.GetAuthUserByEmail(ctx, email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return q.CreateOIDCAuthUser(ctx, gen.CreateOIDCAuthUserParams{Email: email, Role: role})
+	}
+	if err != nil {
+		return gen.AuthUser{}, err
+	}
+	if !user.IsActive {
+		return gen.AuthUser{}, errOIDCUserNotAllowed
+	}
+	if user.AuthMethod != auth.MethodOIDC {
+		return gen.AuthUser{}, errOIDCPasswordAccount
+	}
+	if user.Role != role {
+		if err := q.UpdateAuthUserRole(ctx, gen.UpdateAuthUserRoleParams{Role: role, ID: user.ID}); err != nil {
+			return gen.AuthUser{}, err
+		}
+		user.Role = role
+	}
+	return user, nil
+}
+
+func oidcLoginFailed(c *echo.Context, description string) error {
+	setFlashToast(c, viewmodels.ToastViewData{
+		Category:    "error",
+		Title:       "Single sign-on failed",
+		Description: description,
+	})
+	return c.Redirect(http.StatusSeeOther, "/login")
+}
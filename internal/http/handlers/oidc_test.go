@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/open-sspm/open-sspm/internal/auth"
+	"github.com/open-sspm/open-sspm/internal/auth/providers"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/authn"
+)
+
+type fakeOIDCAuthenticator struct {
+	exchanged bool
+}
+
+func (f *fakeOIDCAuthenticator) AuthCodeURL(_ context.Context, state, nonce, verifier string) (string, error) {
+	return "https://idp.example.com/authorize?state=" + state + "&nonce=" + nonce, nil
+}
+
+func (f *fakeOIDCAuthenticator) Exchange(context.Context, string, string, string) (providers.OIDCIdentity, error) {
+	f.exchanged = true
+	return providers.OIDCIdentity{Email: "alice@example.com"}, nil
+}
+
+type fakeOIDCUserQueries struct {
+	users   map[string]gen.AuthUser
+	created []gen.CreateOIDCAuthUserParams
+	updated []gen.UpdateAuthUserRoleParams
+}
+
+func (f *fakeOIDCUserQueries) GetAuthUserByEmail(_ context.Context, email string) (gen.AuthUser, error) {
+	user, ok := f.users[email]
+	if !ok {
+		return gen.AuthUser{}, pgx.ErrNoRows
+	}
+	return user, nil
+}
+
+func (f *fakeOIDCUserQueries) CreateOIDCAuthUser(_ context.Context, arg gen.CreateOIDCAuthUserParams) (gen.AuthUser, error) {
+	f.created = append(f.created, arg)
+	return gen.AuthUser{ID: 99, Email: arg.Email, Role: arg.Role, IsActive: true, AuthMethod: auth.MethodOIDC}, nil
+}
+
+func (f *fakeOIDCUserQueries) UpdateAuthUserRole(_ context.Context, arg gen.UpdateAuthUserRoleParams) error {
+	f.updated = append(f.updated, arg)
+	return nil
+}
+
+func TestHandleOIDCLoginBindsStateToSession(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "http://example.com/login/oidc?next=/credentials")
+	h := newAuthHandlerWithSessionContext(t, c)
+	h.OIDC = &fakeOIDCAuthenticator{}
+
+	if err := h.HandleOIDCLogin(c); err != nil {
+		t.Fatalf("HandleOIDCLogin() error = %v", err)
+	}
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusSeeOther)
+	}
+	ctx := c.Request().Context()
+	state := h.Sessions.GetString(ctx, authn.SessionKeyOIDCState)
+	if state == "" || h.Sessions.GetString(ctx, authn.SessionKeyOIDCNonce) == "" || h.Sessions.GetString(ctx, authn.SessionKeyOIDCVerifier) == "" {
+		t.Fatalf("session is missing the login attempt state")
+	}
+	if !strings.Contains(rec.Header().Get("Location"), "state="+state) {
+		t.Fatalf("Location = %q, want the session state", rec.Header().Get("Location"))
+	}
+	if got := h.Sessions.GetString(ctx, authn.SessionKeyOIDCNext); got != "/credentials" {
+		t.Fatalf("next = %q, want /credentials", got)
+	}
+}
+
+func TestHandleOIDCCallbackRejectsStateMismatch(t *testing.T) {
+	cases := map[string]string{
+		"mismatch": "http://example.com/login/oidc/callback?state=forged&code=abc",
+		"missing":  "http://example.com/login/oidc/callback?code=abc",
+	}
+	for name, target := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, rec := newTestContext(http.MethodGet, target)
+			h := newAuthHandlerWithSessionContext(t, c)
+			authenticator := &fakeOIDCAuthenticator{}
+			h.OIDC = authenticator
+			h.Sessions.Put(c.Request().Context(), authn.SessionKeyOIDCState, "expected")
+
+			if err := h.HandleOIDCCallback(c); err != nil {
+				t.Fatalf("HandleOIDCCallback() error = %v", err)
+			}
+			if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/login" {
+				t.Fatalf("response = %d %q, want redirect to /login", rec.Code, rec.Header().Get("Location"))
+			}
+			if authenticator.exchanged {
+				t.Fatalf("code was exchanged despite a state mismatch")
+			}
+			if h.Sessions.GetString(c.Request().Context(), authn.SessionKeyOIDCState) != "" {
+				t.Fatalf("state was not consumed")
+			}
+			if h.Sessions.GetInt64(c.Request().Context(), authn.SessionKeyUserID) != 0 {
+				t.Fatalf("session was signed in")
+			}
+		})
+	}
+}
+
+func TestResolveOIDCUser(t *testing.T) {
+	t.Parallel()
+
+	q := &fakeOIDCUserQueries{users: map[string]gen.AuthUser{
+		"sso@example.com":      {ID: 1, Email: "sso@example.com", Role: auth.RoleViewer, IsActive: true, AuthMethod: auth.MethodOIDC},
+		"local@example.com":    {ID: 2, Email: "local@example.com", Role: auth.RoleAdmin, IsActive: true, AuthMethod: auth.MethodPassword},
+		"disabled@example.com": {ID: 3, Email: "disabled@example.com", Role: auth.RoleViewer, IsActive: false, AuthMethod: auth.MethodOIDC},
+	}}
+	ctx := context.Background()
+
+	created, err := resolveOIDCUser(ctx, q, "new@example.com", auth.RoleViewer)
+	if err != nil || created.ID != 99 || len(q.created) != 1 || q.created[0].Role != auth.RoleViewer {
+		t.Fatalf("new user: user=%+v err=%v created=%+v, want provisioned viewer", created, err, q.created)
+	}
+
+	promoted, err := resolveOIDCUser(ctx, q, "sso@example.com", auth.RoleAdmin)
+	if err != nil || promoted.Role != auth.RoleAdmin || len(q.updated) != 1 {
+		t.Fatalf("sso user: user=%+v err=%v updated=%+v, want role synced from groups", promoted, err, q.updated)
+	}
+
+	// A local admin must not be reachable through an SSO login the IdP maps to viewer.
+	if local, err := resolveOIDCUser(ctx, q, "local@example.com", auth.RoleViewer); err != errOIDCPasswordAccount || len(q.updated) != 1 {
+		t.Fatalf("local user: user=%+v err=%v updated=%+v, want errOIDCPasswordAccount and no role change", local, err, q.updated)
+	}
+
+	if _, err := resolveOIDCUser(ctx, q, "disabled@example.com", auth.RoleViewer); err != errOIDCUserNotAllowed {
+		t.Fatalf("disabled user: err=%v, want errOIDCUserNotAllowed", err)
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/auth"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/authn"
+)
+
+const contextKeyOrgScope = "org_scope"
+
+// orgScopeQueries loads connector source ownership for tenant scoping.
+type orgScopeQueries interface {
+	ListOrgSources(ctx context.Context) ([]gen.OrgSource, error)
+}
+
+// orgScope limits a request to the connector sources owned by the principal's org. Inventory
+// rows (accounts, assets, credentials, sync runs) are keyed by source, so checking the source
+// is enough to isolate tenants. Sources without an assignment belong to auth.DefaultOrgID.
+type orgScope struct {
+	orgID  int64
+	owners map[string]int64
+}
+
+func orgScopeSourceKey(sourceKind, sourceName string) string {
+	return NormalizeConnectorKind(sourceKind) + "|" + strings.ToLower(strings.TrimSpace(sourceName))
+}
+
+func loadOrgScope(ctx context.Context, q orgScopeQueries, orgID int64) (orgScope, error) {
+	if orgID <= 0 {
+		orgID = auth.DefaultOrgID
+	}
+	scope := orgScope{orgID: orgID, owners: map[string]int64{}}
+	rows, err := q.ListOrgSources(ctx)
+	if err != nil {
+		return orgScope{}, err
+	}
+	for _, row := range rows {
+		scope.owners[orgScopeSourceKey(row.SourceKind, row.SourceName)] = row.OrgID
+	}
+	return scope, nil
+}
+
+// AllowsSource reports whether rows from the source are visible to the scope's org.
+func (s orgScope) AllowsSource(sourceKind, sourceName string) bool {
+	owner, ok := s.owners[orgScopeSourceKey(sourceKind, sourceName)]
+	if !ok {
+		owner = auth.DefaultOrgID
+	}
+	orgID := s.orgID
+	if orgID <= 0 {
+		orgID = auth.DefaultOrgID
+	}
+	return owner == orgID
+}
+
+// filterStates drops configured connectors whose source belongs to another org, so pages built
+// from the snapshot treat them as not configured. Unconfigured connectors are kept.
+func (s orgScope) filterStates(states []registry.ConnectorState) []registry.ConnectorState {
+	out := make([]registry.ConnectorState, 0, len(states))
+	for _, state := range states {
+		sourceName := strings.TrimSpace(state.SourceName)
+		if sourceName != "" && !s.AllowsSource(connectorSyncKind(state.Definition.Kind()), sourceName) {
+			continue
+		}
+		out = append(out, state)
+	}
+	return out
+}
+
+func (s orgScope) filterSourceStats(sources []ConnectorSourceStats) []ConnectorSourceStats {
+	out := make([]ConnectorSourceStats, 0, len(sources))
+	for _, source := range sources {
+		if s.AllowsSource(source.SourceKind, source.SourceName) {
+			out = append(out, source)
+		}
+	}
+	return out
+}
+
+// filterAccounts drops accounts whose source belongs to another org.
+func (s orgScope) filterAccounts(accounts []gen.Account) []gen.Account {
+	out := make([]gen.Account, 0, len(accounts))
+	for _, account := range accounts {
+		if s.AllowsSource(account.SourceKind, account.SourceName) {
+			out = append(out, account)
+		}
+	}
+	return out
+}
+
+// AllowsIdentity reports whether an identity with the given linked accounts is visible: identities
+// span sources, so one visible account is enough. An identity without linked accounts has no
+// source to check and, like an unassigned source, belongs to auth.DefaultOrgID.
+func (s orgScope) AllowsIdentity(linked []gen.Account) bool {
+	if len(linked) == 0 {
+		orgID := s.orgID
+		if orgID <= 0 {
+			orgID = auth.DefaultOrgID
+		}
+		return orgID == auth.DefaultOrgID
+	}
+	return len(s.filterAccounts(linked)) > 0
+}
+
+func principalOrgID(c *echo.Context) int64 {
+	principal, ok := authn.PrincipalFromContext(c)
+	if !ok || principal.OrgID <= 0 {
+		return auth.DefaultOrgID
+	}
+	return principal.OrgID
+}
+
+// requestOrgScope loads the principal's org scope once per request.
+func (h *Handlers) requestOrgScope(c *echo.Context) (orgScope, error) {
+	if scope, ok := c.Get(contextKeyOrgScope).(orgScope); ok {
+		return scope, nil
+	}
+	scope, err := loadOrgScope(c.Request().Context(), h.Q, principalOrgID(c))
+	if err != nil {
+		return orgScope{}, err
+	}
+	c.Set(contextKeyOrgScope, scope)
+	return scope, nil
+}
+
+// loadScopedConnectorStates returns the connector states visible to the request's org.
+func (h *Handlers) loadScopedConnectorStates(c *echo.Context) ([]registry.ConnectorState, error) {
+	if h.Registry == nil {
+		return nil, nil
+	}
+	states, err := h.Registry.LoadStates(c.Request().Context(), h.Q)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return nil, err
+	}
+	return scope.filterStates(states), nil
+}
+
+// sourceVisible reports whether the request's org may read rows from the source.
+func (h *Handlers) sourceVisible(c *echo.Context, sourceKind, sourceName string) (bool, error) {
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return false, err
+	}
+	return scope.AllowsSource(sourceKind, sourceName), nil
+}
@@ -0,0 +1,542 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/auth"
+	"github.com/open-sspm/open-sspm/internal/connectors/configstore"
+	connregistry "github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/authn"
+)
+
+type fakeOrgScopeQueries struct {
+	sources []gen.OrgSource
+}
+
+func (f fakeOrgScopeQueries) ListOrgSources(context.Context) ([]gen.OrgSource, error) {
+	return f.sources, nil
+}
+
+func TestOrgScopeIsolatesCredentialsByOrg(t *testing.T) {
+	t.Parallel()
+
+	const orgA, orgB int64 = 2, 3
+	q := fakeOrgScopeQueries{sources: []gen.OrgSource{
+		{SourceKind: "github", SourceName: "acme", OrgID: orgA},
+		{SourceKind: "github", SourceName: "globex", OrgID: orgB},
+	}}
+	credentialB := gen.CredentialArtifact{ID: 10, SourceKind: "github", SourceName: "Globex", CredentialKind: "github_deploy_key", ExternalID: "42"}
+	credentialA := gen.CredentialArtifact{ID: 11, SourceKind: "github", SourceName: "acme", CredentialKind: "github_deploy_key", ExternalID: "7"}
+
+	scopeA, err := loadOrgScope(context.Background(), q, orgA)
+	if err != nil {
+		t.Fatalf("loadOrgScope() error = %v", err)
+	}
+	if scopeA.AllowsSource(credentialB.SourceKind, credentialB.SourceName) {
+		t.Fatalf("org A can read org B's credential %+v", credentialB)
+	}
+	if !scopeA.AllowsSource(credentialA.SourceKind, credentialA.SourceName) {
+		t.Fatalf("org A cannot read its own credential %+v", credentialA)
+	}
+
+	scopeB, err := loadOrgScope(context.Background(), q, orgB)
+	if err != nil {
+		t.Fatalf("loadOrgScope() error = %v", err)
+	}
+	if !scopeB.AllowsSource(credentialB.SourceKind, credentialB.SourceName) || scopeB.AllowsSource(credentialA.SourceKind, credentialA.SourceName) {
+		t.Fatalf("org B scope does not match its assignments")
+	}
+
+	// Unassigned sources stay with the default org so single-tenant installs see everything.
+	scopeDefault, err := loadOrgScope(context.Background(), q, 0)
+	if err != nil {
+		t.Fatalf("loadOrgScope() error = %v", err)
+	}
+	if !scopeDefault.AllowsSource("vault", "prod") || scopeA.AllowsSource("vault", "prod") {
+		t.Fatalf("unassigned source visibility: default=%v orgA=%v", scopeDefault.AllowsSource("vault", "prod"), scopeA.AllowsSource("vault", "prod"))
+	}
+	if scopeDefault.AllowsSource(credentialA.SourceKind, credentialA.SourceName) {
+		t.Fatalf("default org can read org A's credential")
+	}
+}
+
+func TestOrgScopeFilterStates(t *testing.T) {
+	t.Parallel()
+
+	scope, err := loadOrgScope(context.Background(), fakeOrgScopeQueries{sources: []gen.OrgSource{
+		{SourceKind: "aws", SourceName: "111111111111", OrgID: 2},
+	}}, 3)
+	if err != nil {
+		t.Fatalf("loadOrgScope() error = %v", err)
+	}
+	states := []connregistry.ConnectorState{
+		{Definition: fakeStatsDefinition{kind: "aws_identity_center"}, Configured: true, Enabled: true, SourceName: "111111111111"},
+		{Definition: fakeStatsDefinition{kind: "okta"}, Configured: false},
+	}
+	got := scope.filterStates(states)
+	if len(got) != 1 || got[0].Definition.Kind() != "okta" {
+		t.Fatalf("filterStates() = %+v, want only the unconfigured okta state", got)
+	}
+}
+
+// orgScopedDB extends credentialInventoryDB with the org source assignments, connector configs,
+// and single-row lookups the scoped handlers read.
+type orgScopedDB struct {
+	*credentialInventoryDB
+
+	sources []gen.OrgSource
+	configs []gen.ConnectorConfig
+	assets  []gen.AppAsset
+	runs    []gen.SyncRun
+
+	accounts         []gen.Account
+	identities       []gen.GetIdentitySummaryByIDRow
+	identityAccounts map[int64][]int64
+	suggestions      []gen.IdentityMergeSuggestion
+	dismissed        []int64
+}
+
+func (db *orgScopedDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	if strings.Contains(sql, "-- name: DismissIdentityMergeSuggestion ") {
+		db.dismissed = append(db.dismissed, args[1].(int64))
+		return pgconn.NewCommandTag("UPDATE 1"), nil
+	}
+	return db.credentialInventoryDB.Exec(ctx, sql, args...)
+}
+
+func (db *orgScopedDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	switch {
+	case strings.Contains(sql, "-- name: ListOrgSources "):
+		return newStructRows(db.sources), nil
+	case strings.Contains(sql, "-- name: ListConnectorConfigs "):
+		return newStructRows(db.configs), nil
+	case strings.Contains(sql, "-- name: ListRulesets "),
+		strings.Contains(sql, "-- name: ListIdPUsersForCommand "),
+		strings.Contains(sql, "-- name: ListOktaAppsForCommand "):
+		// The layout's navigation data; the scoped pages render fine without it.
+		return newStructRows([]any(nil)), nil
+	case strings.Contains(sql, "-- name: ListLinkedAccountsForIdentity "):
+		var linked []gen.Account
+		for _, accountID := range db.identityAccounts[args[0].(int64)] {
+			for _, account := range db.accounts {
+				if account.ID == accountID {
+					linked = append(linked, account)
+				}
+			}
+		}
+		return newStructRows(linked), nil
+	case strings.Contains(sql, "-- name: ListIdentityAccountSources "):
+		var sources []gen.ListIdentityAccountSourcesRow
+		for _, identityID := range args[0].([]int64) {
+			for _, accountID := range db.identityAccounts[identityID] {
+				for _, account := range db.accounts {
+					if account.ID == accountID {
+						sources = append(sources, gen.ListIdentityAccountSourcesRow{IdentityID: identityID, SourceKind: account.SourceKind, SourceName: account.SourceName})
+					}
+				}
+			}
+		}
+		return newStructRows(sources), nil
+	case strings.Contains(sql, "-- name: ListOpenIdentityMergeSuggestions "):
+		var open []gen.ListOpenIdentityMergeSuggestionsRow
+		for _, row := range db.suggestions {
+			duplicateID := row.LowIdentityID
+			if duplicateID == row.SurvivorIdentityID {
+				duplicateID = row.HighIdentityID
+			}
+			open = append(open, gen.ListOpenIdentityMergeSuggestionsRow{ID: row.ID, Score: row.Score, SurvivorIdentityID: row.SurvivorIdentityID, DuplicateIdentityID: duplicateID})
+		}
+		return newStructRows(open), nil
+	}
+	return db.credentialInventoryDB.Query(ctx, sql, args...)
+}
+
+func (db *orgScopedDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	switch {
+	case strings.Contains(sql, "-- name: GetCredentialArtifactByID "):
+		for _, row := range db.rows {
+			if row.ID == args[0] {
+				return structRow{value: row}
+			}
+		}
+		return errRow{err: pgx.ErrNoRows}
+	case strings.Contains(sql, "-- name: GetAppAssetByID "):
+		for _, row := range db.assets {
+			if row.ID == args[0] {
+				return structRow{value: row}
+			}
+		}
+		return errRow{err: pgx.ErrNoRows}
+	case strings.Contains(sql, "-- name: GetSyncRun "):
+		for _, row := range db.runs {
+			if row.ID == args[0] {
+				return structRow{value: row}
+			}
+		}
+		return errRow{err: pgx.ErrNoRows}
+	case strings.Contains(sql, "-- name: GetIdPUser "):
+		for _, row := range db.accounts {
+			if row.ID == args[0] && row.SourceKind == "okta" {
+				return structRow{value: row}
+			}
+		}
+		return errRow{err: pgx.ErrNoRows}
+	case strings.Contains(sql, "-- name: GetOpenIdentityMergeSuggestionForUpdate "):
+		for _, row := range db.suggestions {
+			if row.ID == args[0] {
+				return structRow{value: row}
+			}
+		}
+		return errRow{err: pgx.ErrNoRows}
+	case strings.Contains(sql, "-- name: GetIdentitySummaryByID "):
+		for _, row := range db.identities {
+			if row.ID == args[0] {
+				return structRow{value: row}
+			}
+		}
+		return errRow{err: pgx.ErrNoRows}
+	}
+	return db.credentialInventoryDB.QueryRow(ctx, sql, args...)
+}
+
+// structRow scans a generated model field by field, matching the column order of its queries.
+type structRow struct{ value any }
+
+func (r structRow) Scan(dest ...any) error {
+	row := reflect.ValueOf(r.value)
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(row.Field(i))
+	}
+	return nil
+}
+
+type structRows struct {
+	credentialArtifactRows
+	values []any
+}
+
+func newStructRows[T any](rows []T) *structRows {
+	out := &structRows{credentialArtifactRows: credentialArtifactRows{idx: -1}}
+	for _, row := range rows {
+		out.values = append(out.values, row)
+	}
+	return out
+}
+
+func (r *structRows) Next() bool {
+	r.idx++
+	return r.idx < len(r.values)
+}
+
+func (r *structRows) Scan(dest ...any) error {
+	return structRow{value: r.values[r.idx]}.Scan(dest...)
+}
+
+// fakeGitHubDefinition decodes the raw connector config as the GitHub org name.
+type fakeGitHubDefinition struct {
+	fakeStatsDefinition
+}
+
+func (fakeGitHubDefinition) DecodeConfig(raw []byte) (any, error) {
+	return configstore.GitHubConfig{Org: string(raw)}, nil
+}
+
+func (fakeGitHubDefinition) IsConfigured(cfg any) bool { return true }
+
+func (fakeGitHubDefinition) SourceName(cfg any) string {
+	return cfg.(configstore.GitHubConfig).Org
+}
+
+const (
+	crossOrgA int64 = 2
+	crossOrgB int64 = 3
+)
+
+// newCrossOrgHandlers returns handlers, and their fake database, over a GitHub source "globex" and an Okta source
+// "globex.okta.com" that belong to org B.
+func newCrossOrgHandlers(t *testing.T) (*Handlers, *orgScopedDB) {
+	t.Helper()
+
+	reg := connregistry.NewRegistry()
+	if err := reg.Register(fakeGitHubDefinition{fakeStatsDefinition{kind: configstore.KindGitHub}}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	db := &orgScopedDB{
+		credentialInventoryDB: &credentialInventoryDB{rows: []gen.CredentialArtifact{
+			{ID: 10, SourceKind: "github", SourceName: "globex", CredentialKind: "github_deploy_key", ExternalID: "42", Status: "active"},
+		}},
+		sources: []gen.OrgSource{
+			{SourceKind: "github", SourceName: "globex", OrgID: crossOrgB},
+			{SourceKind: "okta", SourceName: "globex.okta.com", OrgID: crossOrgB},
+		},
+		configs: []gen.ConnectorConfig{{Kind: configstore.KindGitHub, Enabled: true, Config: []byte("globex")}},
+		assets: []gen.AppAsset{
+			{ID: 20, SourceKind: "github", SourceName: "globex", AssetKind: "github_app_installation", ExternalID: "7"},
+		},
+		runs: []gen.SyncRun{{ID: 30, SourceKind: "github_discovery", SourceName: "globex", Status: "success"}},
+		accounts: []gen.Account{
+			{ID: 40, SourceKind: "okta", SourceName: "globex.okta.com", ExternalID: "00u1", Email: "ana@globex.test"},
+			{ID: 41, SourceKind: "github", SourceName: "globex", ExternalID: "ana"},
+			{ID: 42, SourceKind: "github", SourceName: "globex", ExternalID: "ana-g"},
+			{ID: 43, SourceKind: "github", SourceName: "acme", ExternalID: "ana"},
+		},
+		identities:       []gen.GetIdentitySummaryByIDRow{{ID: 50, Kind: "human", PrimaryEmail: "ana@globex.test", LinkedAccounts: 2}},
+		identityAccounts: map[int64][]int64{50: {40, 41}, 51: {42}, 52: {43}},
+		suggestions: []gen.IdentityMergeSuggestion{
+			// Both identities belong to org B.
+			{ID: 60, LowIdentityID: 50, HighIdentityID: 51, SurvivorIdentityID: 50, Score: 0.9, Status: "open"},
+			// Identity 52's only account is in the unassigned "acme" source, so the pair spans orgs.
+			{ID: 61, LowIdentityID: 51, HighIdentityID: 52, SurvivorIdentityID: 51, Score: 0.8, Status: "open"},
+		},
+	}
+	return &Handlers{Q: gen.New(db), Registry: reg}, db
+}
+
+func newOrgTestContext(method, target string, orgID int64) (*echo.Context, *httptest.ResponseRecorder) {
+	c, rec := newTestContext(method, target)
+	c.Set(authn.ContextKeyPrincipal, auth.Principal{UserID: 1, Role: auth.RoleViewer, OrgID: orgID})
+	return c, rec
+}
+
+func TestHandleCredentialShowHidesOtherOrgsCredentials(t *testing.T) {
+	t.Parallel()
+
+	h, _ := newCrossOrgHandlers(t)
+	for _, tc := range []struct {
+		orgID    int64
+		notFound bool
+	}{
+		{orgID: crossOrgA, notFound: true},
+		{orgID: auth.DefaultOrgID, notFound: true},
+		// The owning org gets past the scope check; the fake does not serve the rest of the page.
+		{orgID: crossOrgB, notFound: false},
+	} {
+		c, rec := newOrgTestContext(http.MethodGet, "/credentials/10", tc.orgID)
+		c.SetPathValues(echo.PathValues{{Name: "id", Value: "10"}})
+		if err := h.HandleCredentialShow(c); err != nil {
+			t.Fatalf("HandleCredentialShow() org %d error = %v", tc.orgID, err)
+		}
+		if got := rec.Code == http.StatusNotFound; got != tc.notFound {
+			t.Fatalf("org %d status = %d, want not found = %v", tc.orgID, rec.Code, tc.notFound)
+		}
+	}
+}
+
+func TestHandleAppAssetShowHidesOtherOrgsAssets(t *testing.T) {
+	t.Parallel()
+
+	h, _ := newCrossOrgHandlers(t)
+	for _, tc := range []struct {
+		orgID    int64
+		notFound bool
+	}{
+		{orgID: crossOrgA, notFound: true},
+		{orgID: crossOrgB, notFound: false},
+	} {
+		c, rec := newOrgTestContext(http.MethodGet, "/app-assets/20", tc.orgID)
+		c.SetPathValues(echo.PathValues{{Name: "id", Value: "20"}})
+		if err := h.HandleAppAssetShow(c); err != nil {
+			t.Fatalf("HandleAppAssetShow() org %d error = %v", tc.orgID, err)
+		}
+		if got := rec.Code == http.StatusNotFound; got != tc.notFound {
+			t.Fatalf("org %d status = %d, want not found = %v", tc.orgID, rec.Code, tc.notFound)
+		}
+	}
+}
+
+func TestHandleAPICredentialsListsOnlyOwnOrgsCredentials(t *testing.T) {
+	t.Parallel()
+
+	h, _ := newCrossOrgHandlers(t)
+	for _, tc := range []struct {
+		orgID     int64
+		wantItems int
+	}{
+		{orgID: crossOrgA, wantItems: 0},
+		{orgID: crossOrgB, wantItems: 1},
+	} {
+		for _, query := range []string{"", "source_kind=github&source_name=globex"} {
+			c, rec := newOrgTestContext(http.MethodGet, "/api/credentials?"+query, tc.orgID)
+			if err := h.HandleAPICredentials(c); err != nil {
+				t.Fatalf("HandleAPICredentials() org %d error = %v", tc.orgID, err)
+			}
+			if rec.Code != http.StatusOK {
+				t.Fatalf("org %d %q status = %d, body = %s", tc.orgID, query, rec.Code, rec.Body.String())
+			}
+			var resp credentialsAPIResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if len(resp.Items) != tc.wantItems || resp.TotalCount != int64(tc.wantItems) {
+				t.Fatalf("org %d %q items = %+v total = %d, want %d", tc.orgID, query, resp.Items, resp.TotalCount, tc.wantItems)
+			}
+		}
+	}
+}
+
+func TestHandleSyncRunShowHidesOtherOrgsRuns(t *testing.T) {
+	t.Parallel()
+
+	h, _ := newCrossOrgHandlers(t)
+	for _, tc := range []struct {
+		orgID    int64
+		notFound bool
+	}{
+		{orgID: crossOrgA, notFound: true},
+		{orgID: crossOrgB, notFound: false},
+	} {
+		c, rec := newOrgTestContext(http.MethodGet, "/settings/sync-runs/30", tc.orgID)
+		c.SetPathValues(echo.PathValues{{Name: "id", Value: "30"}})
+		if err := h.HandleSyncRunShow(c); err != nil {
+			t.Fatalf("HandleSyncRunShow() org %d error = %v", tc.orgID, err)
+		}
+		if got := rec.Code == http.StatusNotFound; got != tc.notFound {
+			t.Fatalf("org %d status = %d, want not found = %v", tc.orgID, rec.Code, tc.notFound)
+		}
+	}
+}
+
+func TestHandleIdpUserShowHidesOtherOrgsUsers(t *testing.T) {
+	t.Parallel()
+
+	h, _ := newCrossOrgHandlers(t)
+	for _, tc := range []struct {
+		orgID    int64
+		notFound bool
+	}{
+		{orgID: crossOrgA, notFound: true},
+		{orgID: auth.DefaultOrgID, notFound: true},
+		{orgID: crossOrgB, notFound: false},
+	} {
+		c, rec := newOrgTestContext(http.MethodGet, "/idp-users/40", tc.orgID)
+		c.SetPathValues(echo.PathValues{{Name: "*", Value: "40"}})
+		if err := h.HandleIdpUserShow(c); err != nil {
+			t.Fatalf("HandleIdpUserShow() org %d error = %v", tc.orgID, err)
+		}
+		if got := rec.Code == http.StatusNotFound; got != tc.notFound {
+			t.Fatalf("org %d status = %d, want not found = %v", tc.orgID, rec.Code, tc.notFound)
+		}
+	}
+}
+
+func TestHandleIdentityShowHidesOtherOrgsIdentities(t *testing.T) {
+	t.Parallel()
+
+	h, _ := newCrossOrgHandlers(t)
+	for _, tc := range []struct {
+		orgID    int64
+		notFound bool
+	}{
+		{orgID: crossOrgA, notFound: true},
+		{orgID: auth.DefaultOrgID, notFound: true},
+		{orgID: crossOrgB, notFound: false},
+	} {
+		c, rec := newOrgTestContext(http.MethodGet, "/identities/50", tc.orgID)
+		c.SetPathValues(echo.PathValues{{Name: "id", Value: "50"}})
+		if err := h.HandleIdentityShow(c); err != nil {
+			t.Fatalf("HandleIdentityShow() org %d error = %v", tc.orgID, err)
+		}
+		if got := rec.Code == http.StatusNotFound; got != tc.notFound {
+			t.Fatalf("org %d status = %d, want not found = %v", tc.orgID, rec.Code, tc.notFound)
+		}
+	}
+}
+
+func TestOrgScopeAllowsIdentity(t *testing.T) {
+	t.Parallel()
+
+	scope, err := loadOrgScope(context.Background(), fakeOrgScopeQueries{sources: []gen.OrgSource{
+		{SourceKind: "okta", SourceName: "globex.okta.com", OrgID: crossOrgB},
+	}}, crossOrgA)
+	if err != nil {
+		t.Fatalf("loadOrgScope() error = %v", err)
+	}
+	other := gen.Account{ID: 1, SourceKind: "okta", SourceName: "globex.okta.com"}
+	unassigned := gen.Account{ID: 2, SourceKind: "github", SourceName: "acme"}
+	if scope.AllowsIdentity([]gen.Account{other}) {
+		t.Fatalf("org A can read an identity whose only account belongs to org B")
+	}
+	if scope.AllowsIdentity(nil) {
+		t.Fatalf("org A can read an identity without linked accounts")
+	}
+
+	scope.orgID = auth.DefaultOrgID
+	if !scope.AllowsIdentity([]gen.Account{other, unassigned}) {
+		t.Fatalf("default org cannot read an identity with an unassigned account")
+	}
+	if got := scope.filterAccounts([]gen.Account{other, unassigned}); len(got) != 1 || got[0].ID != unassigned.ID {
+		t.Fatalf("filterAccounts() = %+v, want only the unassigned account", got)
+	}
+}
+
+func TestIdentityDuplicatesHideOtherOrgsSuggestions(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		orgID       int64
+		wantVisible []string
+		wantHidden  []string
+	}{
+		// Suggestion 60 is the only one linking identity 50, and 61 the only one linking 52.
+		{orgID: crossOrgA, wantHidden: []string{`href="/identities/50"`, `href="/identities/52"`}},
+		{orgID: auth.DefaultOrgID, wantHidden: []string{`href="/identities/50"`, `href="/identities/52"`}},
+		{orgID: crossOrgB, wantVisible: []string{`href="/identities/50"`}, wantHidden: []string{`href="/identities/52"`}},
+	} {
+		h, _ := newCrossOrgHandlers(t)
+		c, rec := newOrgTestContext(http.MethodGet, "/identities/duplicates", tc.orgID)
+		if err := h.HandleIdentityDuplicates(c); err != nil {
+			t.Fatalf("HandleIdentityDuplicates() org %d error = %v", tc.orgID, err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("org %d status = %d, body = %s", tc.orgID, rec.Code, rec.Body.String())
+		}
+		for _, action := range tc.wantVisible {
+			if !strings.Contains(rec.Body.String(), action) {
+				t.Fatalf("org %d page is missing %s", tc.orgID, action)
+			}
+		}
+		for _, action := range tc.wantHidden {
+			if strings.Contains(rec.Body.String(), action) {
+				t.Fatalf("org %d page shows %s", tc.orgID, action)
+			}
+		}
+	}
+}
+
+func TestHandleIdentityMergeDismissRejectsOtherOrgsSuggestions(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		orgID        int64
+		suggestionID string
+		notFound     bool
+	}{
+		{orgID: crossOrgA, suggestionID: "60", notFound: true},
+		{orgID: crossOrgB, suggestionID: "61", notFound: true},
+		{orgID: auth.DefaultOrgID, suggestionID: "61", notFound: true},
+		{orgID: crossOrgB, suggestionID: "60", notFound: false},
+	} {
+		h, db := newCrossOrgHandlers(t)
+		c, rec := newOrgTestContext(http.MethodPost, "/identities/duplicates/"+tc.suggestionID+"/dismiss", tc.orgID)
+		c.SetPathValues(echo.PathValues{{Name: "id", Value: tc.suggestionID}})
+		if err := h.HandleIdentityMergeDismiss(c); err != nil {
+			t.Fatalf("HandleIdentityMergeDismiss() org %d error = %v", tc.orgID, err)
+		}
+		if got := rec.Code == http.StatusNotFound; got != tc.notFound {
+			t.Fatalf("org %d suggestion %s status = %d, want not found = %v", tc.orgID, tc.suggestionID, rec.Code, tc.notFound)
+		}
+		if dismissed := len(db.dismissed) > 0; dismissed == tc.notFound {
+			t.Fatalf("org %d suggestion %s dismissed = %v", tc.orgID, tc.suggestionID, db.dismissed)
+		}
+	}
+}
@@ -1,12 +1,34 @@
 package handlers
 
 import (
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/http/views"
 )
 
+// parsePerPageParam reads per_page, accepting only the allowlisted page sizes: anything above
+// the largest clamps to it, and any other value uses the default.
+func parsePerPageParam(c *echo.Context) int {
+	return normalizePerPage(c.QueryParam("per_page"))
+}
+
+func normalizePerPage(raw string) int {
+	parsed, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil {
+		return views.DefaultPerPage
+	}
+	if largest := views.PerPageOptions[len(views.PerPageOptions)-1]; parsed > largest {
+		return largest
+	}
+	if slices.Contains(views.PerPageOptions, parsed) {
+		return parsed
+	}
+	return views.DefaultPerPage
+}
+
 func parsePageParam(c *echo.Context) int {
 	page := 1
 	if rawPage := strings.TrimSpace(c.QueryParam("page")); rawPage != "" {
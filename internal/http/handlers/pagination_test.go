@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/views"
+)
+
+func TestNormalizePerPage(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]int{
+		"":     20,
+		"abc":  20,
+		"-5":   20,
+		"0":    20,
+		"20":   20,
+		"30":   20,
+		"99":   20,
+		"50":   50,
+		"100":  100,
+		"5000": 100,
+	}
+	for raw, want := range cases {
+		if got := normalizePerPage(raw); got != want {
+			t.Fatalf("normalizePerPage(%q) = %d, want %d", raw, got, want)
+		}
+	}
+}
+
+func TestPerPageDrivesPaginationMath(t *testing.T) {
+	t.Parallel()
+
+	rows := make([]gen.CredentialArtifact, 250)
+	for i := range rows {
+		rows[i] = gen.CredentialArtifact{ID: int64(i + 1), ExternalID: fmt.Sprintf("cred-%d", i+1)}
+	}
+	total := int64(len(rows))
+
+	perPage := normalizePerPage("100")
+	page, totalPages, offset := paginate(total, 2, perPage)
+	pageRows := paginateCredentials(rows, offset, perPage)
+	from, to := showingRange(total, offset, len(pageRows))
+	if page != 2 || totalPages != 3 || len(pageRows) != 100 || from != 101 || to != 200 {
+		t.Fatalf("per_page=100 page 2 = page %d of %d, %d rows, showing %d-%d", page, totalPages, len(pageRows), from, to)
+	}
+
+	perPage = normalizePerPage("not-a-number")
+	page, totalPages, offset = paginate(total, 1, perPage)
+	pageRows = paginateCredentials(rows, offset, perPage)
+	if page != 1 || totalPages != 13 || len(pageRows) != views.DefaultPerPage {
+		t.Fatalf("invalid per_page = page %d of %d, %d rows, want default page size", page, totalPages, len(pageRows))
+	}
+}
+
+func TestWithPerPage(t *testing.T) {
+	t.Parallel()
+
+	if got := views.WithPerPage("/idp-users", views.DefaultPerPage); got != "/idp-users" {
+		t.Fatalf("default page size href = %q", got)
+	}
+	if got := views.WithPerPage("/idp-users?q=alice&page=2", 50); got != "/idp-users?q=alice&page=2&per_page=50" {
+		t.Fatalf("href with query = %q", got)
+	}
+	if got := views.WithPerPage("/credentials", 100); got != "/credentials?per_page=100" {
+		t.Fatalf("href without query = %q", got)
+	}
+}
@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/accessgraph"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+	"github.com/open-sspm/open-sspm/internal/http/views"
+)
+
+const (
+	privilegedAccessPageRowLimit   int32 = 5000
+	privilegedAccessExportRowLimit int32 = 50000
+
+	// privilegedAccessHighPrivilege is the min_level value that lists the entitlements the
+	// high-privilege policy flags instead of those at or above a level.
+	privilegedAccessHighPrivilege = "high"
+)
+
+// privilegedAccessLevels are the selectable minimum levels, lowest first.
+var privilegedAccessLevels = []registry.PrivilegeLevel{
+	registry.PrivilegeWrite,
+	registry.PrivilegeAdmin,
+	registry.PrivilegeOwner,
+}
+
+// HandlePrivilegedAccess lists active entitlements at or above a canonical privilege level across
+// every connector. ?min_level=write|admin|owner (default admin) and ?source_kind narrow the list,
+// and ?min_level=high lists the entitlements the high-privilege policy flags; ?format=csv|json
+// exports it instead of rendering the page.
+func (h *Handlers) HandlePrivilegedAccess(c *echo.Context) error {
+	highPrivilegeOnly := strings.EqualFold(strings.TrimSpace(c.QueryParam("min_level")), privilegedAccessHighPrivilege)
+	minLevel := parsePrivilegedAccessMinLevel(c.QueryParam("min_level"))
+	policy := h.entitlementPrivilegePolicy()
+	sourceKind := NormalizeConnectorKind(c.QueryParam("source_kind"))
+	format := strings.ToLower(strings.TrimSpace(c.QueryParam("format")))
+	if format != "" && format != "csv" && format != "json" {
+		return c.String(http.StatusBadRequest, "format must be csv or json")
+	}
+
+	ctx := c.Request().Context()
+	rowLimit := privilegedAccessPageRowLimit
+	if format != "" {
+		rowLimit = privilegedAccessExportRowLimit
+	}
+	params := gen.ListPrivilegedEntitlementsParams{
+		MinPrivilegeLevel: int16(minLevel),
+		SourceKind:        sourceKind,
+		RowLimit:          rowLimit,
+	}
+	if highPrivilegeOnly {
+		params.MinPrivilegeLevel = int16(policy.MinLevel)
+		if policy.MinLevel == registry.PrivilegeUnknown {
+			// Only the patterns apply, so no level alone qualifies.
+			params.MinPrivilegeLevel = int16(registry.PrivilegeOwner) + 1
+		}
+		params.ExtraKinds = policy.patternKinds()
+	}
+	rows, err := h.Q.ListPrivilegedEntitlements(ctx, params)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	access := buildPrivilegedAccessRows(rows, scope, policy, highPrivilegeOnly)
+
+	if format != "" {
+		return writePrivilegedAccessExport(c, format, access)
+	}
+
+	layout, snap, err := h.LayoutData(ctx, c, "Privileged Access")
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	data := viewmodels.PrivilegedAccessViewData{
+		Layout:             layout,
+		MinLevel:           minLevel.String(),
+		SelectedSourceKind: sourceKind,
+		Rows:               access,
+		HasRows:            len(access) > 0,
+		Truncated:          len(rows) >= int(rowLimit),
+		EmptyStateMsg:      "No active entitlements at or above this privilege level.",
+	}
+	if highPrivilegeOnly {
+		data.MinLevel = privilegedAccessHighPrivilege
+		data.EmptyStateMsg = "No active entitlements match the high-privilege policy."
+	}
+	for _, level := range privilegedAccessLevels {
+		data.Levels = append(data.Levels, level.String())
+	}
+	seenKinds := map[string]struct{}{}
+	for _, source := range availableIdentitySourcePairs(snap) {
+		if _, ok := seenKinds[source.SourceKind]; ok {
+			continue
+		}
+		seenKinds[source.SourceKind] = struct{}{}
+		data.SourceKinds = append(data.SourceKinds, viewmodels.ProgrammaticSourceOption{SourceKind: source.SourceKind, Label: source.Label})
+	}
+	for _, row := range access {
+		switch row.PrivilegeLevel {
+		case registry.PrivilegeOwner.String():
+			data.OwnerCount++
+		case registry.PrivilegeAdmin.String():
+			data.AdminCount++
+		}
+		if row.HighPrivilege {
+			data.HighPrivilegeCount++
+		}
+	}
+	return h.RenderComponent(c, views.PrivilegedAccessPage(data))
+}
+
+// parsePrivilegedAccessMinLevel accepts write, admin, or owner; anything else selects admin.
+func parsePrivilegedAccessMinLevel(raw string) registry.PrivilegeLevel {
+	level := registry.ParsePrivilegeLevel(raw)
+	for _, allowed := range privilegedAccessLevels {
+		if level == allowed {
+			return level
+		}
+	}
+	return registry.PrivilegeAdmin
+}
+
+// buildPrivilegedAccessRows labels the entitlements the scope allows and classifies them with
+// policy. With highPrivilegeOnly, entitlements the policy does not flag are dropped.
+func buildPrivilegedAccessRows(rows []gen.ListPrivilegedEntitlementsRow, scope orgScope, policy entitlementPrivilegePolicy, highPrivilegeOnly bool) []viewmodels.PrivilegedAccessRow {
+	out := make([]viewmodels.PrivilegedAccessRow, 0, len(rows))
+	for _, row := range rows {
+		if !scope.AllowsSource(row.AppUserSourceKind, row.AppUserSourceName) {
+			continue
+		}
+		highPrivilege, reason := policy.classify(row.EntitlementKind, row.EntitlementResource, row.EntitlementPermission, registry.PrivilegeLevel(row.EntitlementPrivilegeLevel), row.EntitlementRawJson)
+		if highPrivilegeOnly && !highPrivilege {
+			continue
+		}
+		sourceKind := strings.TrimSpace(row.AppUserSourceKind)
+		sourceName := strings.TrimSpace(row.AppUserSourceName)
+		resource := strings.TrimSpace(row.EntitlementResource)
+		level := registry.PrivilegeLevel(row.EntitlementPrivilegeLevel)
+		access := viewmodels.PrivilegedAccessRow{
+			EntitlementID:      row.EntitlementID,
+			SourceKind:         sourceKind,
+			SourceName:         sourceName,
+			SourceLabel:        sourceDiagnosticLabel(sourceKind, sourceName),
+			Kind:               strings.TrimSpace(row.EntitlementKind),
+			Resource:           resource,
+			ResourceLabel:      accessgraph.DisplayResourceLabel(resource, row.EntitlementRawJson),
+			ResourceHref:       accessgraph.BuildResourceHrefFromResourceRef(sourceKind, sourceName, resource),
+			Permission:         strings.TrimSpace(row.EntitlementPermission),
+			PrivilegeLevel:     level.String(),
+			PrivilegeClass:     privilegeLevelClass(level),
+			AssignmentState:    strings.TrimSpace(row.EntitlementAssignmentState),
+			AppUserExternalID:  strings.TrimSpace(row.AppUserExternalID),
+			AppUserEmail:       strings.TrimSpace(row.AppUserEmail),
+			AppUserDisplayName: strings.TrimSpace(row.AppUserDisplayName),
+			LastObservedAt:     discoveryReportTimestamp(row.EntitlementLastObservedAt),
+			// Flagged by the configurable policy, independently of the selected level.
+			HighPrivilege:       highPrivilege,
+			HighPrivilegeReason: reason,
+		}
+		if access.ResourceLabel == "" {
+			access.ResourceLabel = resource
+		}
+		if row.IdentityID.Valid {
+			access.IdentityID = row.IdentityID.Int64
+			access.IdentityHref = "/identities/" + strconv.FormatInt(row.IdentityID.Int64, 10)
+			switch {
+			case row.IdentityDisplayName.Valid && strings.TrimSpace(row.IdentityDisplayName.String) != "":
+				access.IdentityLabel = strings.TrimSpace(row.IdentityDisplayName.String)
+			case row.IdentityPrimaryEmail.Valid && strings.TrimSpace(row.IdentityPrimaryEmail.String) != "":
+				access.IdentityLabel = strings.TrimSpace(row.IdentityPrimaryEmail.String)
+			default:
+				access.IdentityLabel = fmt.Sprintf("Identity %d", row.IdentityID.Int64)
+			}
+		}
+		out = append(out, access)
+	}
+	return out
+}
+
+func privilegeLevelClass(level registry.PrivilegeLevel) string {
+	switch level {
+	case registry.PrivilegeOwner:
+		return badgeClassDanger()
+	case registry.PrivilegeAdmin:
+		return badgeClassWarning()
+	default:
+		return badgeClassNeutral()
+	}
+}
+
+// privilegedAccessReportRow is one exported entitlement. Field order matches the CSV columns.
+type privilegedAccessReportRow struct {
+	EntitlementID     int64  `json:"entitlement_id"`
+	SourceKind        string `json:"source_kind"`
+	SourceName        string `json:"source_name"`
+	IdentityID        string `json:"identity_id"`
+	Identity          string `json:"identity"`
+	AppUserExternalID string `json:"app_user_external_id"`
+	AppUserEmail      string `json:"app_user_email"`
+	EntitlementKind   string `json:"entitlement_kind"`
+	Resource          string `json:"resource"`
+	Permission        string `json:"permission"`
+	PrivilegeLevel    string `json:"privilege_level"`
+	AssignmentState   string `json:"assignment_state"`
+	LastObservedAt    string `json:"last_observed_at"`
+	HighPrivilege     bool   `json:"high_privilege"`
+}
+
+var privilegedAccessColumns = []string{
+	"entitlement_id",
+	"source_kind",
+	"source_name",
+	"identity_id",
+	"identity",
+	"app_user_external_id",
+	"app_user_email",
+	"entitlement_kind",
+	"resource",
+	"permission",
+	"privilege_level",
+	"assignment_state",
+	"last_observed_at",
+	"high_privilege",
+}
+
+func privilegedAccessReportRowFor(row viewmodels.PrivilegedAccessRow) privilegedAccessReportRow {
+	identityID := ""
+	if row.IdentityID > 0 {
+		identityID = strconv.FormatInt(row.IdentityID, 10)
+	}
+	return privilegedAccessReportRow{
+		EntitlementID:     row.EntitlementID,
+		SourceKind:        row.SourceKind,
+		SourceName:        row.SourceName,
+		IdentityID:        identityID,
+		Identity:          row.IdentityLabel,
+		AppUserExternalID: row.AppUserExternalID,
+		AppUserEmail:      row.AppUserEmail,
+		EntitlementKind:   row.Kind,
+		Resource:          row.Resource,
+		Permission:        row.Permission,
+		PrivilegeLevel:    row.PrivilegeLevel,
+		AssignmentState:   row.AssignmentState,
+		LastObservedAt:    row.LastObservedAt,
+		HighPrivilege:     row.HighPrivilege,
+	}
+}
+
+func (r privilegedAccessReportRow) csvRecord() []string {
+	return []string{
+		strconv.FormatInt(r.EntitlementID, 10),
+		r.SourceKind,
+		r.SourceName,
+		r.IdentityID,
+		r.Identity,
+		r.AppUserExternalID,
+		r.AppUserEmail,
+		r.EntitlementKind,
+		r.Resource,
+		r.Permission,
+		r.PrivilegeLevel,
+		r.AssignmentState,
+		r.LastObservedAt,
+		strconv.FormatBool(r.HighPrivilege),
+	}
+}
+
+func writePrivilegedAccessExport(c *echo.Context, format string, rows []viewmodels.PrivilegedAccessRow) error {
+	report := make([]privilegedAccessReportRow, 0, len(rows))
+	for _, row := range rows {
+		report = append(report, privilegedAccessReportRowFor(row))
+	}
+
+	filename := "privileged-access-" + time.Now().UTC().Format("20060102") + "." + format
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if format == "json" {
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c.Response().WriteHeader(http.StatusOK)
+		return json.NewEncoder(c.Response()).Encode(report)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().WriteHeader(http.StatusOK)
+	w := csv.NewWriter(c.Response())
+	if err := w.Write(privilegedAccessColumns); err != nil {
+		return err
+	}
+	for _, row := range report {
+		if err := w.Write(row.csvRecord()); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestParsePrivilegedAccessMinLevel(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]registry.PrivilegeLevel{
+		"":      registry.PrivilegeAdmin,
+		"read":  registry.PrivilegeAdmin,
+		"bogus": registry.PrivilegeAdmin,
+		"write": registry.PrivilegeWrite,
+		"Owner": registry.PrivilegeOwner,
+	}
+	for raw, want := range cases {
+		if got := parsePrivilegedAccessMinLevel(raw); got != want {
+			t.Fatalf("parsePrivilegedAccessMinLevel(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestBuildPrivilegedAccessRowsScopesAndLabels(t *testing.T) {
+	t.Parallel()
+
+	scope, err := loadOrgScope(context.Background(), fakeOrgScopeQueries{sources: []gen.OrgSource{
+		{SourceKind: "github", SourceName: "globex", OrgID: 3},
+	}}, 0)
+	if err != nil {
+		t.Fatalf("loadOrgScope() error = %v", err)
+	}
+	rows := buildPrivilegedAccessRows([]gen.ListPrivilegedEntitlementsRow{
+		{
+			EntitlementID:             1,
+			EntitlementKind:           "entra_directory_role",
+			EntitlementResource:       "entra_directory_role:62e90394",
+			EntitlementPermission:     "member",
+			EntitlementPrivilegeLevel: int16(registry.PrivilegeOwner),
+			AppUserSourceKind:         "entra",
+			AppUserSourceName:         "tenant-1",
+			AppUserExternalID:         "u1",
+			IdentityID:                pgtype.Int8{Int64: 7, Valid: true},
+			IdentityPrimaryEmail:      pgtype.Text{String: "alice@example.com", Valid: true},
+		},
+		{
+			EntitlementID:             2,
+			EntitlementKind:           "github_org_role",
+			EntitlementResource:       "github_org:globex",
+			EntitlementPermission:     "admin",
+			EntitlementPrivilegeLevel: int16(registry.PrivilegeOwner),
+			AppUserSourceKind:         "github",
+			AppUserSourceName:         "globex",
+			AppUserExternalID:         "bob",
+		},
+	}, scope, entitlementPrivilegePolicy{}, false)
+
+	if len(rows) != 1 {
+		t.Fatalf("len(rows) = %d, want 1 (other org's source filtered out)", len(rows))
+	}
+	row := rows[0]
+	if row.PrivilegeLevel != "owner" || row.PrivilegeClass != badgeClassDanger() {
+		t.Fatalf("privilege = %q/%q, want owner/danger", row.PrivilegeLevel, row.PrivilegeClass)
+	}
+	if row.IdentityHref != "/identities/7" || row.IdentityLabel != "alice@example.com" {
+		t.Fatalf("identity = %q %q", row.IdentityHref, row.IdentityLabel)
+	}
+	if got := privilegedAccessReportRowFor(row).csvRecord(); len(got) != len(privilegedAccessColumns) {
+		t.Fatalf("csvRecord() has %d fields, want %d", len(got), len(privilegedAccessColumns))
+	}
+}
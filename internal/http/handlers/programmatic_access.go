@@ -6,10 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -18,8 +21,11 @@ import (
 	"github.com/open-sspm/open-sspm/internal/db/gen"
 	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
 	"github.com/open-sspm/open-sspm/internal/http/views"
+	"github.com/open-sspm/open-sspm/internal/matching"
 )
 
+const defaultMultiSourceListRowLimit = 50000
+
 func (h *Handlers) HandleAppAssets(c *echo.Context) error {
 	addVary(c, "HX-Request", "HX-Target")
 
@@ -29,20 +35,33 @@ func (h *Handlers) HandleAppAssets(c *echo.Context) error {
 		return h.RenderError(c, err)
 	}
 
-	sources := availableProgrammaticSources(snap)
-	selected, hasSource := selectProgrammaticSource(c, sources)
+	includeDisabled := h.includeDisabledProgrammaticSources(c)
+	sources := programmaticSources(snap, includeDisabled)
+	selected, hasSource := h.selectRememberedProgrammaticSource(c, sources)
 	query := strings.TrimSpace(c.QueryParam("q"))
 	assetKind := strings.TrimSpace(c.QueryParam("asset_kind"))
+	tag := parseCredentialTagFilter(c.QueryParam("tag"))
+	sortKey := normalizeAppAssetSort(c.QueryParam("sort"))
 	page := parsePageParam(c)
-	const perPage = 20
+	perPage := parsePerPageParam(c)
+
+	owner, err := h.loadIdentityAccountFilter(ctx, c.QueryParam("owner_identity"))
+	if err != nil {
+		return h.RenderError(c, err)
+	}
 
 	data := viewmodels.AppAssetsViewData{
 		Layout:             layout,
 		Sources:            sources,
+		IncludeDisabled:    includeDisabled,
 		SelectedSourceKind: selected.SourceKind,
 		SelectedSourceName: selected.SourceName,
 		Query:              query,
 		AssetKind:          assetKind,
+		Tag:                tag.String(),
+		OwnerIdentityID:    owner.IdentityID,
+		OwnerIdentityLabel: owner.Label,
+		Sort:               sortKey,
 		Page:               1,
 		PerPage:            perPage,
 		TotalPages:         1,
@@ -56,7 +75,7 @@ func (h *Handlers) HandleAppAssets(c *echo.Context) error {
 	}
 
 	if !hasSource {
-		data.EmptyStateMsg = "Configure and enable GitHub, Microsoft Entra, or Vault connectors to populate app assets."
+		data.EmptyStateMsg = "Configure and enable GitHub, Microsoft Entra, Vault, or Bitbucket connectors to populate app assets."
 		return renderAppAssets()
 	}
 
@@ -65,19 +84,34 @@ func (h *Handlers) HandleAppAssets(c *echo.Context) error {
 		data.EmptyStateMsg = "No matching source found. Choose another source filter."
 		return renderAppAssets()
 	}
+	data.StaleSources = staleProgrammaticSources(activeSources)
 
+	kindCounts, err := h.Q.ListAppAssetKindCountsBySourceSet(ctx, appAssetKindCountsParams(activeSources, owner, tag, query))
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	data.AssetKindFacets = appAssetKindFacets(kindCounts, assetKind)
+
+	now := time.Now().UTC()
 	var totalCount int64
 	var totalPages int
 	var offset int
 	var assets []gen.AppAsset
+	var credentialSummaries map[int64]appAssetCredentialSummary
 
-	if len(activeSources) == 1 {
+	// Sorting by credential risk needs every matching asset's credentials, so it takes the
+	// in-memory path even for a single source.
+	if len(activeSources) == 1 && sortKey != "risk_desc" {
 		source := activeSources[0]
 		totalCount, err = h.Q.CountAppAssetsBySourceAndQueryAndKind(ctx, gen.CountAppAssetsBySourceAndQueryAndKindParams{
-			SourceKind: source.SourceKind,
-			SourceName: source.SourceName,
-			AssetKind:  assetKind,
-			Query:      query,
+			SourceKind:       source.SourceKind,
+			SourceName:       source.SourceName,
+			AssetKind:        assetKind,
+			Query:            query,
+			OwnerFilter:      owner.Active(),
+			OwnerExternalIds: owner.ExternalIDsForSource(source.SourceKind, source.SourceName),
+			TagKey:           tag.Key,
+			TagValue:         tag.Value,
 		})
 		if err != nil {
 			return h.RenderError(c, err)
@@ -85,64 +119,52 @@ func (h *Handlers) HandleAppAssets(c *echo.Context) error {
 
 		page, totalPages, offset = paginate(totalCount, page, perPage)
 		assets, err = h.Q.ListAppAssetsPageBySourceAndQueryAndKind(ctx, gen.ListAppAssetsPageBySourceAndQueryAndKindParams{
-			SourceKind: source.SourceKind,
-			SourceName: source.SourceName,
-			AssetKind:  assetKind,
-			Query:      query,
-			PageLimit:  int32(perPage),
-			PageOffset: int32(offset),
+			SourceKind:       source.SourceKind,
+			SourceName:       source.SourceName,
+			AssetKind:        assetKind,
+			Query:            query,
+			PageLimit:        int32(perPage),
+			PageOffset:       int32(offset),
+			Sort:             sortKey,
+			OwnerFilter:      owner.Active(),
+			OwnerExternalIds: owner.ExternalIDsForSource(source.SourceKind, source.SourceName),
+			TagKey:           tag.Key,
+			TagValue:         tag.Value,
 		})
 		if err != nil {
 			return h.RenderError(c, err)
 		}
 	} else {
-		allAssets, err := h.listAppAssetsAcrossSources(ctx, activeSources, assetKind, query)
+		rowLimit := h.multiSourceRowLimit()
+		allAssets, truncated, err := h.listAppAssetsAcrossSources(ctx, activeSources, assetKind, owner, tag, query, rowLimit)
 		if err != nil {
 			return h.RenderError(c, err)
 		}
-		sortAppAssetsForList(allAssets)
+		if truncated {
+			data.ResultsTruncatedMsg = multiSourceTruncatedMessage(rowLimit)
+		}
+		var riskRanks map[int64]int
+		if sortKey == "risk_desc" {
+			credentialSummaries, err = h.loadAppAssetCredentialSummaries(ctx, allAssets, now)
+			if err != nil {
+				return h.RenderError(c, err)
+			}
+			riskRanks = make(map[int64]int, len(credentialSummaries))
+			for assetID, summary := range credentialSummaries {
+				riskRanks[assetID] = summary.riskRank()
+			}
+		}
+		sortAppAssetsForList(allAssets, sortKey, riskRanks)
 		totalCount = int64(len(allAssets))
 		page, totalPages, offset = paginate(totalCount, page, perPage)
 		assets = paginateAppAssets(allAssets, offset, perPage)
 	}
 
 	ownerCounts := map[int64]int{}
-	credentialCounts := map[int64]int{}
-
 	assetIDs := make([]int64, 0, len(assets))
-	type sourceCredentialRefs struct {
-		sourceKind     string
-		sourceName     string
-		refKinds       []string
-		refExternalIDs []string
-	}
-	refGroups := map[string]*sourceCredentialRefs{}
-	refToAssetID := map[string]int64{}
 	for _, asset := range assets {
 		assetIDs = append(assetIDs, asset.ID)
-		refKind, refExternalID := appAssetCredentialRef(asset)
-		if refKind == "" || refExternalID == "" {
-			continue
-		}
-
-		assetSourceKind := strings.TrimSpace(asset.SourceKind)
-		assetSourceName := strings.TrimSpace(asset.SourceName)
-		groupKey := sourceKey(assetSourceKind, assetSourceName)
-		group := refGroups[groupKey]
-		if group == nil {
-			group = &sourceCredentialRefs{
-				sourceKind:     assetSourceKind,
-				sourceName:     assetSourceName,
-				refKinds:       make([]string, 0, len(assets)),
-				refExternalIDs: make([]string, 0, len(assets)),
-			}
-			refGroups[groupKey] = group
-		}
-		group.refKinds = append(group.refKinds, refKind)
-		group.refExternalIDs = append(group.refExternalIDs, refExternalID)
-		refToAssetID[credentialSourceRefKey(assetSourceKind, assetSourceName, refKind, refExternalID)] = asset.ID
 	}
-
 	if len(assetIDs) > 0 {
 		owners, err := h.Q.ListAppAssetOwnersByAssetIDs(ctx, assetIDs)
 		if err != nil {
@@ -153,29 +175,15 @@ func (h *Handlers) HandleAppAssets(c *echo.Context) error {
 		}
 	}
 
-	for _, group := range refGroups {
-		if len(group.refKinds) == 0 {
-			continue
-		}
-		countRows, err := h.Q.ListCredentialArtifactCountsByAssetRef(ctx, gen.ListCredentialArtifactCountsByAssetRefParams{
-			SourceKind:          group.sourceKind,
-			SourceName:          group.sourceName,
-			AssetRefKinds:       group.refKinds,
-			AssetRefExternalIds: group.refExternalIDs,
-		})
+	if credentialSummaries == nil {
+		credentialSummaries, err = h.loadAppAssetCredentialSummaries(ctx, assets, now)
 		if err != nil {
 			return h.RenderError(c, err)
 		}
-		for _, row := range countRows {
-			assetID, ok := refToAssetID[credentialSourceRefKey(group.sourceKind, group.sourceName, row.AssetRefKind, row.AssetRefExternalID)]
-			if !ok {
-				continue
-			}
-			credentialCounts[assetID] = int(row.CredentialCount)
-		}
 	}
 
 	items := make([]viewmodels.AppAssetListItem, 0, len(assets))
+	loc := h.displayLocation(c)
 	for _, asset := range assets {
 		displayName := strings.TrimSpace(asset.DisplayName)
 		if displayName == "" {
@@ -193,8 +201,10 @@ func (h *Handlers) HandleAppAssets(c *echo.Context) error {
 			ExternalID:       strings.TrimSpace(asset.ExternalID),
 			Status:           fallbackDash(strings.TrimSpace(asset.Status)),
 			OwnersCount:      ownerCounts[asset.ID],
-			CredentialsCount: credentialCounts[asset.ID],
-			LastSeenAt:       formatProgrammaticDate(asset.LastObservedAt),
+			CredentialsCount: credentialSummaries[asset.ID].Count,
+			CredentialRisk:   credentialSummaries[asset.ID].RiskLevel,
+			Orphan:           parseAppAssetLinkage(asset.RawJson).Orphan,
+			LastSeenAt:       formatProgrammaticDate(asset.LastObservedAt, loc),
 		})
 	}
 
@@ -209,7 +219,7 @@ func (h *Handlers) HandleAppAssets(c *echo.Context) error {
 	data.Page = page
 	data.TotalPages = totalPages
 	data.HasItems = showingCount > 0
-	if query != "" || assetKind != "" {
+	if query != "" || assetKind != "" || owner.Active() {
 		data.EmptyStateMsg = "No app assets match the current search filters."
 	}
 
@@ -230,6 +240,11 @@ func (h *Handlers) HandleAppAssetShow(c *echo.Context) error {
 		}
 		return h.RenderError(c, err)
 	}
+	if visible, err := h.sourceVisible(c, asset.SourceKind, asset.SourceName); err != nil {
+		return h.RenderError(c, err)
+	} else if !visible {
+		return RenderNotFound(c)
+	}
 
 	layout, _, err := h.LayoutData(ctx, c, "App Asset")
 	if err != nil {
@@ -269,6 +284,7 @@ func (h *Handlers) HandleAppAssetShow(c *echo.Context) error {
 
 	credentialItems := make([]viewmodels.AppAssetCredentialItem, 0, len(credentialRows))
 	credentialDisplayByRef := map[string]string{}
+	loc := h.displayLocation(c)
 	for _, credential := range credentialRows {
 		displayName := strings.TrimSpace(credential.DisplayName)
 		if displayName == "" {
@@ -280,9 +296,9 @@ func (h *Handlers) HandleAppAssetShow(c *echo.Context) error {
 			CredentialKind: fallbackDash(strings.TrimSpace(credential.CredentialKind)),
 			DisplayName:    fallbackDash(displayName),
 			Status:         fallbackDash(strings.TrimSpace(credential.Status)),
-			RiskLevel:      credentialRiskLevel(credential, now),
-			ExpiresAt:      formatProgrammaticDate(credential.ExpiresAtSource),
-			LastUsedAt:     formatProgrammaticDate(credential.LastUsedAtSource),
+			RiskLevel:      credentialRiskLevel(credential, now, h.credentialRiskPolicy()),
+			ExpiresAt:      formatProgrammaticDate(credential.ExpiresAtSource, loc),
+			LastUsedAt:     formatProgrammaticDate(credential.LastUsedAtSource, loc),
 			CreatedBy:      fallbackDash(actorDisplayName(credential.CreatedByDisplayName, credential.CreatedByExternalID)),
 			CreatedByHref:  linkResolver.Resolve(strings.TrimSpace(credential.SourceKind), strings.TrimSpace(credential.SourceName), credential.CreatedByExternalID, "", credential.CreatedByDisplayName),
 		})
@@ -307,7 +323,7 @@ func (h *Handlers) HandleAppAssetShow(c *echo.Context) error {
 		credentialName := credentialDisplayByRef[credentialRefKey(credentialKind, credentialExternalID)]
 		auditItems = append(auditItems, viewmodels.ProgrammaticAuditEventItem{
 			EventType:             fallbackDash(strings.TrimSpace(event.EventType)),
-			EventTime:             formatProgrammaticDate(event.EventTime),
+			EventTime:             formatProgrammaticDate(event.EventTime, loc),
 			Actor:                 fallbackDash(actorDisplayName(event.ActorDisplayName, event.ActorExternalID)),
 			Target:                fallbackDash(actorDisplayName(event.TargetDisplayName, event.TargetExternalID)),
 			CredentialKind:        fallbackDash(credentialKind),
@@ -324,6 +340,9 @@ func (h *Handlers) HandleAppAssetShow(c *echo.Context) error {
 		displayName = fmt.Sprintf("Asset %d", asset.ID)
 	}
 
+	linkage := parseAppAssetLinkage(asset.RawJson)
+	linkedLabel, linkedHref := h.appAssetLinkedAsset(ctx, asset, linkage)
+
 	data := viewmodels.AppAssetShowViewData{
 		Layout: layout,
 		Asset: viewmodels.AppAssetSummaryView{
@@ -335,9 +354,12 @@ func (h *Handlers) HandleAppAssetShow(c *echo.Context) error {
 			ExternalID:       strings.TrimSpace(asset.ExternalID),
 			ParentExternalID: fallbackDash(strings.TrimSpace(asset.ParentExternalID)),
 			Status:           fallbackDash(strings.TrimSpace(asset.Status)),
-			CreatedAtSource:  formatProgrammaticDate(asset.CreatedAtSource),
-			UpdatedAtSource:  formatProgrammaticDate(asset.UpdatedAtSource),
-			LastObservedAt:   formatProgrammaticDate(asset.LastObservedAt),
+			CreatedAtSource:  formatProgrammaticDate(asset.CreatedAtSource, loc),
+			UpdatedAtSource:  formatProgrammaticDate(asset.UpdatedAtSource, loc),
+			LastObservedAt:   formatProgrammaticDate(asset.LastObservedAt, loc),
+			Orphan:           linkage.Orphan,
+			LinkedLabel:      linkedLabel,
+			LinkedHref:       linkedHref,
 		},
 		Owners:         ownerItems,
 		Credentials:    credentialItems,
@@ -353,46 +375,48 @@ func (h *Handlers) HandleAppAssetShow(c *echo.Context) error {
 func (h *Handlers) HandleCredentials(c *echo.Context) error {
 	addVary(c, "HX-Request", "HX-Target")
 
-	ctx := c.Request().Context()
-	layout, snap, err := h.LayoutData(ctx, c, "Credentials")
+	layout, snap, err := h.LayoutData(c.Request().Context(), c, "Credentials")
 	if err != nil {
 		return h.RenderError(c, err)
 	}
+	return h.renderCredentials(c, layout, snap)
+}
 
-	sources := availableProgrammaticSources(snap)
-	selected, hasSource := selectProgrammaticSource(c, sources)
-	query := strings.TrimSpace(c.QueryParam("q"))
-	credentialKind := strings.TrimSpace(c.QueryParam("credential_kind"))
-	status := strings.TrimSpace(c.QueryParam("status"))
-	riskLevel := normalizeCredentialRiskFilter(c.QueryParam("risk_level"))
-	expiryState := strings.ToLower(strings.TrimSpace(c.QueryParam("expiry_state")))
-	switch expiryState {
-	case "", "active", "expired":
-	default:
-		expiryState = ""
-	}
-	expiresInDays := max(parseIntParamDefault(c.QueryParam("expires_in_days"), 0), 0)
-	if expiresInDays > 3650 {
-		expiresInDays = 3650
+// renderCredentials renders the credential inventory page for the request's filters.
+func (h *Handlers) renderCredentials(c *echo.Context, layout viewmodels.LayoutData, snap ConnectorSnapshot) error {
+	ctx := c.Request().Context()
+	includeDisabled := h.includeDisabledProgrammaticSources(c)
+	sources := programmaticSources(snap, includeDisabled)
+	selected, hasSource := h.selectRememberedProgrammaticSource(c, sources)
+	filters, err := h.parseCredentialListFilters(ctx, c)
+	if err != nil {
+		return h.RenderError(c, err)
 	}
-	page := parsePageParam(c)
-	const perPage = 20
 
 	data := viewmodels.CredentialsViewData{
-		Layout:             layout,
-		Sources:            sources,
-		SelectedSourceKind: selected.SourceKind,
-		SelectedSourceName: selected.SourceName,
-		Query:              query,
-		CredentialKind:     credentialKind,
-		Status:             status,
-		RiskLevel:          riskLevel,
-		ExpiryState:        expiryState,
-		ExpiresInDays:      expiresInDays,
-		Page:               1,
-		PerPage:            perPage,
-		TotalPages:         1,
-		EmptyStateMsg:      "No credentials found for the current filters.",
+		Layout:                 layout,
+		Sources:                sources,
+		IncludeDisabled:        includeDisabled,
+		SelectedSourceKind:     selected.SourceKind,
+		SelectedSourceName:     selected.SourceName,
+		Query:                  filters.Query,
+		CredentialKind:         filters.CredentialKind,
+		Status:                 filters.Status,
+		RiskLevel:              filters.RiskLevel,
+		ExpiryState:            filters.ExpiryState,
+		LastUsed:               filters.LastUsed,
+		ExpiresInDays:          filters.ExpiresInDays,
+		IntroducedInDays:       filters.IntroducedInDays,
+		SharedOnly:             filters.SharedOnly,
+		BroadScopeOnly:         filters.BroadScopeOnly,
+		CreatedByIdentityID:    filters.CreatedBy.IdentityID,
+		CreatedByIdentityLabel: filters.CreatedBy.Label,
+		Tag:                    filters.Tag.String(),
+		Sort:                   filters.Sort,
+		Page:                   1,
+		PerPage:                filters.PerPage,
+		TotalPages:             1,
+		EmptyStateMsg:          "No credentials found for the current filters.",
 	}
 	renderCredentials := func() error {
 		if isHX(c) && isHXTarget(c, "credentials-results") {
@@ -402,7 +426,7 @@ func (h *Handlers) HandleCredentials(c *echo.Context) error {
 	}
 
 	if !hasSource {
-		data.EmptyStateMsg = "Configure and enable GitHub, Microsoft Entra, or Vault connectors to populate credential inventory."
+		data.EmptyStateMsg = "Configure and enable GitHub, Microsoft Entra, Vault, or Bitbucket connectors to populate credential inventory."
 		return renderCredentials()
 	}
 
@@ -411,59 +435,22 @@ func (h *Handlers) HandleCredentials(c *echo.Context) error {
 		data.EmptyStateMsg = "No matching source found. Choose another source filter."
 		return renderCredentials()
 	}
+	data.StaleSources = staleProgrammaticSources(activeSources)
 
-	var totalCount int64
-	var totalPages int
-	var offset int
-	var rows []gen.CredentialArtifact
-
-	if len(activeSources) == 1 {
-		source := activeSources[0]
-		totalCount, err = h.Q.CountCredentialArtifactsBySourceAndQueryAndFilters(ctx, gen.CountCredentialArtifactsBySourceAndQueryAndFiltersParams{
-			SourceKind:     source.SourceKind,
-			SourceName:     source.SourceName,
-			CredentialKind: credentialKind,
-			Status:         status,
-			RiskLevel:      riskLevel,
-			ExpiryState:    expiryState,
-			ExpiresInDays:  int32(expiresInDays),
-			Query:          query,
-		})
-		if err != nil {
-			return h.RenderError(c, err)
-		}
-
-		page, totalPages, offset = paginate(totalCount, page, perPage)
-		rows, err = h.Q.ListCredentialArtifactsPageBySourceAndQueryAndFilters(ctx, gen.ListCredentialArtifactsPageBySourceAndQueryAndFiltersParams{
-			SourceKind:     source.SourceKind,
-			SourceName:     source.SourceName,
-			CredentialKind: credentialKind,
-			Status:         status,
-			RiskLevel:      riskLevel,
-			ExpiryState:    expiryState,
-			ExpiresInDays:  int32(expiresInDays),
-			Query:          query,
-			PageLimit:      int32(perPage),
-			PageOffset:     int32(offset),
-		})
-		if err != nil {
-			return h.RenderError(c, err)
-		}
-	} else {
-		rows, err = h.listCredentialsAcrossSources(ctx, activeSources, credentialKind, status, riskLevel, expiryState, expiresInDays, query)
-		if err != nil {
-			return h.RenderError(c, err)
-		}
-		sortCredentialsForList(rows)
-		totalCount = int64(len(rows))
-		page, totalPages, offset = paginate(totalCount, page, perPage)
-		rows = paginateCredentials(rows, offset, perPage)
+	page, err := h.loadCredentialListPage(ctx, filters, activeSources)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	if page.Truncated {
+		data.ResultsTruncatedMsg = multiSourceTruncatedMessage(h.multiSourceRowLimit())
 	}
 
 	now := time.Now().UTC()
 	linkResolver := newIdentityLinkResolver(h, ctx)
-	items := make([]viewmodels.CredentialArtifactListItem, 0, len(rows))
-	for _, row := range rows {
+	kindGlossary := h.credentialKindGlossary()
+	items := make([]viewmodels.CredentialArtifactListItem, 0, len(page.Rows))
+	loc := h.displayLocation(c)
+	for _, row := range page.Rows {
 		displayName := strings.TrimSpace(row.DisplayName)
 		if displayName == "" {
 			displayName = strings.TrimSpace(row.ExternalID)
@@ -481,6 +468,7 @@ func (h *Handlers) HandleCredentials(c *echo.Context) error {
 		}
 		createdBy := fallbackDash(actorDisplayName(row.CreatedByDisplayName, row.CreatedByExternalID))
 		approvedBy := fallbackDash(actorDisplayName(row.ApprovedByDisplayName, row.ApprovedByExternalID))
+		kindInfo := lookupCredentialKind(kindGlossary, row.CredentialKind)
 		items = append(items, viewmodels.CredentialArtifactListItem{
 			ID:             row.ID,
 			SourceKind:     strings.TrimSpace(row.SourceKind),
@@ -492,34 +480,191 @@ func (h *Handlers) HandleCredentials(c *echo.Context) error {
 			AssetRefKind:   fallbackDash(assetRefKind),
 			AssetRefID:     fallbackDash(assetRefExternalID),
 			Status:         fallbackDash(strings.TrimSpace(row.Status)),
-			RiskLevel:      credentialRiskLevel(row, now),
-			ExpiresAt:      formatProgrammaticDate(row.ExpiresAtSource),
-			LastUsedAt:     formatProgrammaticDate(row.LastUsedAtSource),
+			RiskLevel:      credentialRiskLevel(row, now, h.credentialRiskPolicy()),
+			SharedService:  isSharedServiceCredential(row, h.Cfg.CredentialSharedNamePatterns),
+			BroadScope:     isBroadScopeCredential(row),
+			ExpiresAt:      formatProgrammaticDate(row.ExpiresAtSource, loc),
+			LastUsedAt:     formatProgrammaticDate(row.LastUsedAtSource, loc),
+			LastUsedBucket: credentialLastUsedBucket(row.LastUsedAtSource, now),
 			CreatedBy:      createdBy,
 			CreatedByHref:  linkResolver.Resolve(strings.TrimSpace(row.SourceKind), strings.TrimSpace(row.SourceName), row.CreatedByExternalID, "", row.CreatedByDisplayName),
 			ApprovedBy:     approvedBy,
 			ApprovedByHref: linkResolver.Resolve(strings.TrimSpace(row.SourceKind), strings.TrimSpace(row.SourceName), row.ApprovedByExternalID, "", row.ApprovedByDisplayName),
+
+			CredentialKindLabel: kindInfo.Label,
+			CredentialKindHelp:  credentialKindTooltip(kindInfo),
 		})
 	}
 
 	showingCount := len(items)
-	showingFrom, showingTo := showingRange(totalCount, offset, showingCount)
+	showingFrom, showingTo := showingRange(page.TotalCount, page.Offset, showingCount)
 
 	data.Items = items
 	data.ShowingCount = showingCount
 	data.ShowingFrom = showingFrom
 	data.ShowingTo = showingTo
-	data.TotalCount = totalCount
-	data.Page = page
-	data.TotalPages = totalPages
+	data.TotalCount = page.TotalCount
+	data.Page = page.Page
+	data.TotalPages = page.TotalPages
 	data.HasItems = showingCount > 0
-	if query != "" || credentialKind != "" || status != "" || riskLevel != "" || expiryState != "" || expiresInDays > 0 {
+	if filters.Active() {
 		data.EmptyStateMsg = "No credentials match the current search filters."
 	}
 
 	return renderCredentials()
 }
 
+// credentialListFilters are the credential inventory filters, parsed and clamped once so the
+// HTML page and the JSON API always query the same rows for the same parameters.
+type credentialListFilters struct {
+	Query            string
+	CredentialKind   string
+	Status           string
+	RiskLevel        string
+	ExpiryState      string
+	ExpiresInDays    int
+	IntroducedInDays int
+	LastUsed         string
+	SharedOnly       bool
+	BroadScopeOnly   bool
+	CreatedBy        identityAccountFilter
+	Tag              credentialTagFilter
+	Sort             string
+	Page             int
+	PerPage          int
+}
+
+func (h *Handlers) parseCredentialListFilters(ctx context.Context, c *echo.Context) (credentialListFilters, error) {
+	expiryState := strings.ToLower(strings.TrimSpace(c.QueryParam("expiry_state")))
+	switch expiryState {
+	case "", "active", "expired":
+	default:
+		expiryState = ""
+	}
+	createdBy, err := h.loadIdentityAccountFilter(ctx, c.QueryParam("created_by"))
+	if err != nil {
+		return credentialListFilters{}, err
+	}
+	return credentialListFilters{
+		Query:            strings.TrimSpace(c.QueryParam("q")),
+		CredentialKind:   strings.TrimSpace(c.QueryParam("credential_kind")),
+		Status:           strings.TrimSpace(c.QueryParam("status")),
+		RiskLevel:        normalizeCredentialRiskFilter(c.QueryParam("risk_level")),
+		ExpiryState:      expiryState,
+		ExpiresInDays:    min(max(parseIntParamDefault(c.QueryParam("expires_in_days"), 0), 0), 3650),
+		IntroducedInDays: min(max(parseIntParamDefault(c.QueryParam("introduced_in_days"), 0), 0), 3650),
+		LastUsed:         normalizeCredentialLastUsedFilter(c.QueryParam("last_used")),
+		SharedOnly:       ParseBoolForm(c.QueryParam("shared")),
+		BroadScopeOnly:   ParseBoolForm(c.QueryParam("broad_scope")),
+		CreatedBy:        createdBy,
+		Tag:              parseCredentialTagFilter(c.QueryParam("tag")),
+		Sort:             normalizeCredentialSort(c.QueryParam("sort")),
+		Page:             parsePageParam(c),
+		PerPage:          parsePerPageParam(c),
+	}, nil
+}
+
+// Active reports whether any filter narrows the inventory.
+func (f credentialListFilters) Active() bool {
+	return f.Query != "" || f.CredentialKind != "" || f.Status != "" || f.RiskLevel != "" || f.ExpiryState != "" || f.LastUsed != "" || f.ExpiresInDays > 0 || f.IntroducedInDays > 0 || f.SharedOnly || f.BroadScopeOnly || f.CreatedBy.Active() || f.Tag.Key != ""
+}
+
+// credentialListPage is one page of the credential inventory. Page is clamped to TotalPages.
+type credentialListPage struct {
+	Rows       []gen.CredentialArtifact
+	TotalCount int64
+	Page       int
+	TotalPages int
+	Offset     int
+	// Truncated is set when the multi-source listing hit the row limit.
+	Truncated bool
+}
+
+// loadCredentialListPage pages through the credentials of activeSources matching filters. A
+// single source is filtered, sorted, and paged in SQL; several sources are merged in memory.
+// The SQL risk filter and sort only know the built-in risk policy, so with a customized policy
+// any risk filtering or sorting happens in memory too.
+func (h *Handlers) loadCredentialListPage(ctx context.Context, filters credentialListFilters, activeSources []viewmodels.ProgrammaticSourceOption) (credentialListPage, error) {
+	var out credentialListPage
+
+	policy := h.credentialRiskPolicy()
+	sortKey := normalizeCredentialSort(filters.Sort)
+	riskInMemory := policy.customized() && (filters.RiskLevel != "" || sortKey == "risk_desc" || sortKey == "risk_asc")
+	if len(activeSources) == 1 && !riskInMemory {
+		source := activeSources[0]
+		totalCount, err := h.Q.CountCredentialArtifactsBySourceAndQueryAndFilters(ctx, gen.CountCredentialArtifactsBySourceAndQueryAndFiltersParams{
+			SourceKind:           source.SourceKind,
+			SourceName:           source.SourceName,
+			CredentialKind:       filters.CredentialKind,
+			Status:               filters.Status,
+			RiskLevel:            filters.RiskLevel,
+			ExpiryState:          filters.ExpiryState,
+			ExpiresInDays:        int32(filters.ExpiresInDays),
+			IntroducedInDays:     int32(filters.IntroducedInDays),
+			Query:                filters.Query,
+			SharedOnly:           filters.SharedOnly,
+			SharedNamePatterns:   h.Cfg.CredentialSharedNamePatterns,
+			CreatedByFilter:      filters.CreatedBy.Active(),
+			CreatedByExternalIds: filters.CreatedBy.ExternalIDsForSource(source.SourceKind, source.SourceName),
+			TagKey:               filters.Tag.Key,
+			TagValue:             filters.Tag.Value,
+			LastUsed:             filters.LastUsed,
+			BroadScopeOnly:       filters.BroadScopeOnly,
+		})
+		if err != nil {
+			return out, err
+		}
+
+		out.TotalCount = totalCount
+		out.Page, out.TotalPages, out.Offset = paginate(totalCount, filters.Page, filters.PerPage)
+		out.Rows, err = h.Q.ListCredentialArtifactsPageBySourceAndQueryAndFilters(ctx, gen.ListCredentialArtifactsPageBySourceAndQueryAndFiltersParams{
+			SourceKind:           source.SourceKind,
+			SourceName:           source.SourceName,
+			CredentialKind:       filters.CredentialKind,
+			Status:               filters.Status,
+			RiskLevel:            filters.RiskLevel,
+			ExpiryState:          filters.ExpiryState,
+			ExpiresInDays:        int32(filters.ExpiresInDays),
+			IntroducedInDays:     int32(filters.IntroducedInDays),
+			Query:                filters.Query,
+			PageLimit:            int32(filters.PerPage),
+			PageOffset:           int32(out.Offset),
+			Sort:                 filters.Sort,
+			SharedOnly:           filters.SharedOnly,
+			SharedNamePatterns:   h.Cfg.CredentialSharedNamePatterns,
+			CreatedByFilter:      filters.CreatedBy.Active(),
+			CreatedByExternalIds: filters.CreatedBy.ExternalIDsForSource(source.SourceKind, source.SourceName),
+			TagKey:               filters.Tag.Key,
+			TagValue:             filters.Tag.Value,
+			LastUsed:             filters.LastUsed,
+			BroadScopeOnly:       filters.BroadScopeOnly,
+		})
+		if err != nil {
+			return out, err
+		}
+		return out, nil
+	}
+
+	riskLevel := filters.RiskLevel
+	if policy.customized() {
+		riskLevel = ""
+	}
+	rows, truncated, err := h.listCredentialsAcrossSources(ctx, activeSources, filters.CredentialKind, filters.Status, riskLevel, filters.ExpiryState, filters.LastUsed, filters.ExpiresInDays, filters.IntroducedInDays, filters.SharedOnly, filters.BroadScopeOnly, filters.CreatedBy, filters.Tag, filters.Query, h.multiSourceRowLimit())
+	if err != nil {
+		return out, err
+	}
+	now := time.Now()
+	if riskLevel != filters.RiskLevel {
+		rows = filterCredentialsByRiskLevel(rows, filters.RiskLevel, now, policy)
+	}
+	sortCredentialsForList(rows, filters.Sort, now, policy)
+	out.Truncated = truncated
+	out.TotalCount = int64(len(rows))
+	out.Page, out.TotalPages, out.Offset = paginate(out.TotalCount, filters.Page, filters.PerPage)
+	out.Rows = paginateCredentials(rows, out.Offset, filters.PerPage)
+	return out, nil
+}
+
 func (h *Handlers) HandleCredentialShow(c *echo.Context) error {
 	credentialID, err := parsePositiveInt64Param(c.Param("id"))
 	if err != nil {
@@ -534,6 +679,11 @@ func (h *Handlers) HandleCredentialShow(c *echo.Context) error {
 		}
 		return h.RenderError(c, err)
 	}
+	if visible, err := h.sourceVisible(c, credential.SourceKind, credential.SourceName); err != nil {
+		return h.RenderError(c, err)
+	} else if !visible {
+		return RenderNotFound(c)
+	}
 
 	layout, _, err := h.LayoutData(ctx, c, "Credential")
 	if err != nil {
@@ -551,11 +701,35 @@ func (h *Handlers) HandleCredentialShow(c *echo.Context) error {
 		return h.RenderError(c, err)
 	}
 
+	tags, err := h.Q.ListCredentialTagsForCredential(ctx, credentialTagIdentity(credential))
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
+	identity := credentialTagIdentity(credential)
+	revocationTasks, err := h.Q.ListCredentialRevocationTasksForCredential(ctx, gen.ListCredentialRevocationTasksForCredentialParams{
+		SourceKind:     identity.SourceKind,
+		SourceName:     identity.SourceName,
+		CredentialKind: identity.CredentialKind,
+		ExternalID:     identity.ExternalID,
+	})
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	hasOpenRevocationTask := false
+	for _, task := range revocationTasks {
+		if task.Status == credentialRevocationStatusOpen {
+			hasOpenRevocationTask = true
+			break
+		}
+	}
+
 	eventItems := make([]viewmodels.ProgrammaticAuditEventItem, 0, len(events))
+	loc := h.displayLocation(c)
 	for _, event := range events {
 		eventItems = append(eventItems, viewmodels.ProgrammaticAuditEventItem{
 			EventType:            fallbackDash(strings.TrimSpace(event.EventType)),
-			EventTime:            formatProgrammaticDate(event.EventTime),
+			EventTime:            formatProgrammaticDate(event.EventTime, loc),
 			Actor:                fallbackDash(actorDisplayName(event.ActorDisplayName, event.ActorExternalID)),
 			Target:               fallbackDash(actorDisplayName(event.TargetDisplayName, event.TargetExternalID)),
 			CredentialKind:       fallbackDash(strings.TrimSpace(event.CredentialKind)),
@@ -569,9 +743,12 @@ func (h *Handlers) HandleCredentialShow(c *echo.Context) error {
 		displayName = strings.TrimSpace(credential.ExternalID)
 	}
 	now := time.Now().UTC()
-	riskLevel := credentialRiskLevel(credential, now)
-	riskReasons := credentialRiskReasons(credential, now)
+	riskLevel := credentialRiskLevel(credential, now, h.credentialRiskPolicy())
+	riskReasons := credentialRiskReasons(credential, now, h.Cfg.CredentialSharedNamePatterns, h.Cfg.CredentialRotationSLADays, h.credentialRiskPolicy())
 	linkResolver := newIdentityLinkResolver(h, ctx)
+	kindInfo := lookupCredentialKind(h.credentialKindGlossary(), credential.CredentialKind)
+	lastRotatedAt, rotationSummary := credentialRotationLabels(deriveCredentialRotationHistory(events), loc)
+	scopeJSON, scopeJSONTruncated := truncatePrettyJSON(prettyProgrammaticJSON(credential.ScopeJson), inlineJSONMaxBytes)
 
 	data := viewmodels.CredentialShowViewData{
 		Layout: layout,
@@ -586,64 +763,66 @@ func (h *Handlers) HandleCredentialShow(c *echo.Context) error {
 			AssetRefExternalID: fallbackDash(strings.TrimSpace(credential.AssetRefExternalID)),
 			Status:             fallbackDash(strings.TrimSpace(credential.Status)),
 			RiskLevel:          riskLevel,
-			CreatedAtSource:    formatProgrammaticDate(credential.CreatedAtSource),
-			ExpiresAtSource:    formatProgrammaticDate(credential.ExpiresAtSource),
-			LastUsedAtSource:   formatProgrammaticDate(credential.LastUsedAtSource),
+			CreatedAtSource:    formatProgrammaticDate(credential.CreatedAtSource, loc),
+			ExpiresAtSource:    formatProgrammaticDate(credential.ExpiresAtSource, loc),
+			LastUsedAtSource:   formatProgrammaticDate(credential.LastUsedAtSource, loc),
+			FirstSeenAt:        formatProgrammaticDate(credential.FirstSeenAt, loc),
 			CreatedBy:          fallbackDash(actorDisplayName(credential.CreatedByDisplayName, credential.CreatedByExternalID)),
 			CreatedByHref:      linkResolver.Resolve(strings.TrimSpace(credential.SourceKind), strings.TrimSpace(credential.SourceName), credential.CreatedByExternalID, "", credential.CreatedByDisplayName),
 			ApprovedBy:         fallbackDash(actorDisplayName(credential.ApprovedByDisplayName, credential.ApprovedByExternalID)),
 			ApprovedByHref:     linkResolver.Resolve(strings.TrimSpace(credential.SourceKind), strings.TrimSpace(credential.SourceName), credential.ApprovedByExternalID, "", credential.ApprovedByDisplayName),
 			AssetHref:          assetHref,
+
+			CredentialKindLabel:       kindInfo.Label,
+			CredentialKindDescription: kindInfo.Description,
+			CredentialKindRotation:    kindInfo.RotationGuidance,
+			LastRotatedAt:             lastRotatedAt,
+			RotationSummary:           rotationSummary,
 		},
-		ScopeJSON:   prettyProgrammaticJSON(credential.ScopeJson),
-		AuditEvents: eventItems,
-		RiskReasons: riskReasons,
-		HasEvents:   len(eventItems) > 0,
+		ScopeJSON:             scopeJSON,
+		ScopeJSONTruncated:    scopeJSONTruncated,
+		ScopeJSONSize:         formatByteSize(int64(len(credential.ScopeJson))),
+		AuditEvents:           eventItems,
+		RiskReasons:           riskReasons,
+		Tags:                  credentialTagItems(credential, tags),
+		RevocationTasks:       credentialRevocationTaskItems(revocationTasks, loc),
+		HasOpenRevocationTask: hasOpenRevocationTask,
+		HasEvents:             len(eventItems) > 0,
 	}
 
 	return h.RenderComponent(c, views.CredentialShowPage(data))
 }
 
 func availableProgrammaticSources(snap ConnectorSnapshot) []viewmodels.ProgrammaticSourceOption {
-	sources := make([]viewmodels.ProgrammaticSourceOption, 0, 4)
+	return programmaticSources(snap, false)
+}
 
-	if snap.EntraEnabled && snap.EntraConfigured {
-		if sourceName := strings.TrimSpace(snap.Entra.TenantID); sourceName != "" {
-			sources = append(sources, viewmodels.ProgrammaticSourceOption{
-				SourceKind: "entra",
-				SourceName: sourceName,
-				Label:      sourcePrimaryLabel("entra"),
-			})
-		}
-	}
-	if snap.GoogleWorkspaceEnabled && snap.GoogleWorkspaceConfigured {
-		if sourceName := strings.TrimSpace(snap.GoogleWorkspace.CustomerID); sourceName != "" {
-			sources = append(sources, viewmodels.ProgrammaticSourceOption{
-				SourceKind: configstore.KindGoogleWorkspace,
-				SourceName: sourceName,
-				Label:      sourcePrimaryLabel(configstore.KindGoogleWorkspace),
-			})
-		}
-	}
-	if snap.GitHubEnabled && snap.GitHubConfigured {
-		if sourceName := strings.TrimSpace(snap.GitHub.Org); sourceName != "" {
-			sources = append(sources, viewmodels.ProgrammaticSourceOption{
-				SourceKind: "github",
-				SourceName: sourceName,
-				Label:      sourcePrimaryLabel("github"),
-			})
-		}
-	}
-	if snap.VaultEnabled && snap.VaultConfigured {
-		if sourceName := strings.TrimSpace(snap.Vault.SourceName()); sourceName != "" {
-			sources = append(sources, viewmodels.ProgrammaticSourceOption{
-				SourceKind: "vault",
-				SourceName: sourceName,
-				Label:      sourcePrimaryLabel("vault"),
-			})
+// programmaticSources lists the configured connector sources with programmatic access data.
+// Disabled connectors keep their synced rows, so includeDisabled lists them too, marked
+// Disabled, for reviewing their stale inventory.
+func programmaticSources(snap ConnectorSnapshot, includeDisabled bool) []viewmodels.ProgrammaticSourceOption {
+	sources := make([]viewmodels.ProgrammaticSourceOption, 0, 4)
+	add := func(kind, sourceName string, enabled, configured bool) {
+		sourceName = strings.TrimSpace(sourceName)
+		if !configured || sourceName == "" || (!enabled && !includeDisabled) {
+			return
 		}
+		sources = append(sources, viewmodels.ProgrammaticSourceOption{
+			SourceKind: kind,
+			SourceName: sourceName,
+			Label:      sourcePrimaryLabel(kind),
+			Disabled:   !enabled,
+			Freshness:  snap.Freshness[kind],
+		})
 	}
 
+	add(configstore.KindOkta, snap.Okta.Domain, snap.OktaEnabled, snap.OktaConfigured)
+	add("entra", snap.Entra.TenantID, snap.EntraEnabled, snap.EntraConfigured)
+	add(configstore.KindGoogleWorkspace, snap.GoogleWorkspace.CustomerID, snap.GoogleWorkspaceEnabled, snap.GoogleWorkspaceConfigured)
+	add("github", snap.GitHub.Org, snap.GitHubEnabled, snap.GitHubConfigured)
+	add("vault", snap.Vault.SourceName(), snap.VaultEnabled, snap.VaultConfigured)
+	add(configstore.KindBitbucket, snap.Bitbucket.Workspace, snap.BitbucketEnabled, snap.BitbucketConfigured)
+
 	sort.SliceStable(sources, func(i, j int) bool {
 		if sources[i].Label == sources[j].Label {
 			return strings.ToLower(sources[i].SourceName) < strings.ToLower(sources[j].SourceName)
@@ -654,35 +833,117 @@ func availableProgrammaticSources(snap ConnectorSnapshot) []viewmodels.Programma
 }
 
 func selectProgrammaticSource(c *echo.Context, sources []viewmodels.ProgrammaticSourceOption) (viewmodels.ProgrammaticSourceOption, bool) {
+	return resolveProgrammaticSource(c.QueryParam("source_kind"), c.QueryParam("source_name"), sources)
+}
+
+// resolveProgrammaticSource matches a source filter against the configured sources. A name picks
+// that one source, so two sources of the same kind stay distinguishable; a kind alone picks every
+// source of the kind.
+func resolveProgrammaticSource(kind, name string, sources []viewmodels.ProgrammaticSourceOption) (viewmodels.ProgrammaticSourceOption, bool) {
 	if len(sources) == 0 {
 		return viewmodels.ProgrammaticSourceOption{}, false
 	}
 
-	queryKind := strings.ToLower(strings.TrimSpace(c.QueryParam("source_kind")))
-	queryName := strings.TrimSpace(c.QueryParam("source_name"))
-
-	for _, source := range sources {
-		if source.SourceKind == queryKind {
-			return viewmodels.ProgrammaticSourceOption{
-				SourceKind: source.SourceKind,
-			}, true
-		}
-	}
+	queryKind := strings.ToLower(strings.TrimSpace(kind))
+	queryName := strings.TrimSpace(name)
 
 	if queryName != "" {
 		for _, source := range sources {
+			if queryKind != "" && source.SourceKind != queryKind {
+				continue
+			}
 			if strings.EqualFold(strings.TrimSpace(source.SourceName), queryName) {
 				return viewmodels.ProgrammaticSourceOption{
 					SourceKind: source.SourceKind,
+					SourceName: source.SourceName,
 				}, true
 			}
 		}
 	}
 
+	for _, source := range sources {
+		if source.SourceKind == queryKind {
+			return viewmodels.ProgrammaticSourceOption{
+				SourceKind: source.SourceKind,
+			}, true
+		}
+	}
+
 	// Unknown source filters fall back to "All configured".
 	return viewmodels.ProgrammaticSourceOption{}, true
 }
 
+// sessionKeyProgrammaticSource holds the operator's last source filter on the app asset and
+// credential pages, encoded as its source_kind/source_name query.
+const sessionKeyProgrammaticSource = "programmatic_source"
+
+// selectRememberedProgrammaticSource is selectProgrammaticSource for pages that default to the
+// operator's last source filter. A request that names a source filter, including an empty one
+// for "All configured", overrides the remembered filter and replaces it.
+func (h *Handlers) selectRememberedProgrammaticSource(c *echo.Context, sources []viewmodels.ProgrammaticSourceOption) (viewmodels.ProgrammaticSourceOption, bool) {
+	if h.Sessions == nil {
+		return selectProgrammaticSource(c, sources)
+	}
+	ctx := c.Request().Context()
+
+	params := c.QueryParams()
+	_, hasKind := params["source_kind"]
+	_, hasName := params["source_name"]
+	if hasKind || hasName {
+		selected, ok := selectProgrammaticSource(c, sources)
+		if ok {
+			remembered := url.Values{}
+			if selected.SourceKind != "" {
+				remembered.Set("source_kind", selected.SourceKind)
+			}
+			if selected.SourceName != "" {
+				remembered.Set("source_name", selected.SourceName)
+			}
+			h.Sessions.Put(ctx, sessionKeyProgrammaticSource, remembered.Encode())
+		}
+		return selected, ok
+	}
+
+	remembered, err := url.ParseQuery(h.Sessions.GetString(ctx, sessionKeyProgrammaticSource))
+	if err != nil {
+		return resolveProgrammaticSource("", "", sources)
+	}
+	return resolveProgrammaticSource(remembered.Get("source_kind"), remembered.Get("source_name"), sources)
+}
+
+// sessionKeyIncludeDisabledSources holds whether the operator chose to list data from disabled
+// connectors on the app asset and credential pages.
+const sessionKeyIncludeDisabledSources = "programmatic_include_disabled"
+
+// includeDisabledProgrammaticSources reports whether the request lists data from disabled
+// connectors. The include_disabled parameter sets the choice, which is remembered for the
+// session like the source filter; without either, disabled connectors are hidden.
+func (h *Handlers) includeDisabledProgrammaticSources(c *echo.Context) bool {
+	ctx := c.Request().Context()
+	if values, ok := c.QueryParams()["include_disabled"]; ok {
+		include := len(values) > 0 && strings.TrimSpace(values[0]) == "1"
+		if h.Sessions != nil {
+			h.Sessions.Put(ctx, sessionKeyIncludeDisabledSources, include)
+		}
+		return include
+	}
+	if h.Sessions == nil {
+		return false
+	}
+	return h.Sessions.GetBool(ctx, sessionKeyIncludeDisabledSources)
+}
+
+// staleProgrammaticSources labels the disabled connector sources among sources.
+func staleProgrammaticSources(sources []viewmodels.ProgrammaticSourceOption) []string {
+	var out []string
+	for _, source := range sources {
+		if source.Disabled {
+			out = append(out, source.Label+" ("+source.SourceName+")")
+		}
+	}
+	return out
+}
+
 func effectiveProgrammaticSources(selected viewmodels.ProgrammaticSourceOption, all []viewmodels.ProgrammaticSourceOption) []viewmodels.ProgrammaticSourceOption {
 	if len(all) == 0 {
 		return nil
@@ -709,90 +970,369 @@ func effectiveProgrammaticSources(selected viewmodels.ProgrammaticSourceOption,
 	return out
 }
 
-func (h *Handlers) listAppAssetsAcrossSources(ctx context.Context, sources []viewmodels.ProgrammaticSourceOption, assetKind, query string) ([]gen.AppAsset, error) {
-	out := make([]gen.AppAsset, 0)
+// multiSourceRowLimit caps how many rows the "All configured" lists merge in memory before sorting
+// and paginating.
+func (h *Handlers) multiSourceRowLimit() int {
+	if h.Cfg.MultiSourceListRowLimit > 0 {
+		return h.Cfg.MultiSourceListRowLimit
+	}
+	return defaultMultiSourceListRowLimit
+}
+
+// collectAcrossSources fetches rows source by source, in source order, until more than limit rows
+// are held. It returns at most limit rows and reports whether any were dropped. fetch receives the
+// number of rows still wanted so per-source pagination can stop early.
+func collectAcrossSources[T any](sources []viewmodels.ProgrammaticSourceOption, limit int, fetch func(source viewmodels.ProgrammaticSourceOption, maxRows int) ([]T, error)) ([]T, bool, error) {
+	out := make([]T, 0)
 	for _, source := range sources {
-		rows, err := h.listAppAssetsForSource(ctx, source, assetKind, query)
+		if len(out) > limit {
+			break
+		}
+		rows, err := fetch(source, limit+1-len(out))
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 		out = append(out, rows...)
 	}
-	return out, nil
+	if len(out) > limit {
+		return out[:limit], true, nil
+	}
+	return out, false, nil
 }
 
-func (h *Handlers) listAppAssetsForSource(ctx context.Context, source viewmodels.ProgrammaticSourceOption, assetKind, query string) ([]gen.AppAsset, error) {
-	const pageSize = 1000
-	out := make([]gen.AppAsset, 0)
-	for offset := 0; ; offset += pageSize {
-		rows, err := h.Q.ListAppAssetsPageBySourceAndQueryAndKind(ctx, gen.ListAppAssetsPageBySourceAndQueryAndKindParams{
-			SourceKind: source.SourceKind,
-			SourceName: source.SourceName,
-			AssetKind:  assetKind,
-			Query:      query,
-			PageLimit:  int32(pageSize),
-			PageOffset: int32(offset),
+func multiSourceTruncatedMessage(limit int) string {
+	return fmt.Sprintf("Results truncated to the first %d matches across sources; narrow your filters or pick a single source.", limit)
+}
+
+// appAssetCredentialSummary is what the asset list shows about an asset's credentials. RiskLevel
+// is the worst credentialRiskLevel among them and empty when the asset has none.
+type appAssetCredentialSummary struct {
+	Count     int
+	RiskLevel string
+}
+
+func (s appAssetCredentialSummary) riskRank() int {
+	if s.Count == 0 {
+		return 0
+	}
+	return credentialRiskRank(s.RiskLevel)
+}
+
+// loadAppAssetCredentialSummaries fetches the credentials behind each asset's credential ref,
+// one query per source, and summarizes them by asset ID.
+func (h *Handlers) loadAppAssetCredentialSummaries(ctx context.Context, assets []gen.AppAsset, now time.Time) (map[int64]appAssetCredentialSummary, error) {
+	type sourceCredentialRefs struct {
+		sourceKind     string
+		sourceName     string
+		refKinds       []string
+		refExternalIDs []string
+	}
+	refGroups := map[string]*sourceCredentialRefs{}
+	groupOrder := make([]string, 0)
+	refToAssetID := map[string]int64{}
+	for _, asset := range assets {
+		refKind, refExternalID := appAssetCredentialRef(asset)
+		if refKind == "" || refExternalID == "" {
+			continue
+		}
+		assetSourceKind := strings.TrimSpace(asset.SourceKind)
+		assetSourceName := strings.TrimSpace(asset.SourceName)
+		groupKey := sourceKey(assetSourceKind, assetSourceName)
+		group := refGroups[groupKey]
+		if group == nil {
+			group = &sourceCredentialRefs{sourceKind: assetSourceKind, sourceName: assetSourceName}
+			refGroups[groupKey] = group
+			groupOrder = append(groupOrder, groupKey)
+		}
+		group.refKinds = append(group.refKinds, refKind)
+		group.refExternalIDs = append(group.refExternalIDs, refExternalID)
+		refToAssetID[credentialSourceRefKey(assetSourceKind, assetSourceName, refKind, refExternalID)] = asset.ID
+	}
+
+	summaries := make(map[int64]appAssetCredentialSummary, len(assets))
+	for _, groupKey := range groupOrder {
+		group := refGroups[groupKey]
+		credentials, err := h.Q.ListCredentialArtifactsForAssetRefs(ctx, gen.ListCredentialArtifactsForAssetRefsParams{
+			SourceKind:          group.sourceKind,
+			SourceName:          group.sourceName,
+			AssetRefKinds:       group.refKinds,
+			AssetRefExternalIds: group.refExternalIDs,
 		})
 		if err != nil {
 			return nil, err
 		}
-		out = append(out, rows...)
-		if len(rows) < pageSize {
-			break
+		summarizeAppAssetCredentials(summaries, refToAssetID, credentials, now, h.credentialRiskPolicy())
+	}
+	return summaries, nil
+}
+
+// summarizeAppAssetCredentials adds credentials to the summaries of the assets their refs point at.
+func summarizeAppAssetCredentials(summaries map[int64]appAssetCredentialSummary, refToAssetID map[string]int64, credentials []gen.CredentialArtifact, now time.Time, policy credentialRiskPolicy) {
+	for _, credential := range credentials {
+		key := credentialSourceRefKey(strings.TrimSpace(credential.SourceKind), strings.TrimSpace(credential.SourceName), credential.AssetRefKind, credential.AssetRefExternalID)
+		assetID, ok := refToAssetID[key]
+		if !ok {
+			continue
 		}
+		summary := summaries[assetID]
+		level := credentialRiskLevel(credential, now, policy)
+		if summary.Count == 0 || credentialRiskRank(level) > credentialRiskRank(summary.RiskLevel) {
+			summary.RiskLevel = level
+		}
+		summary.Count++
+		summaries[assetID] = summary
 	}
-	return out, nil
 }
 
-func (h *Handlers) listCredentialsAcrossSources(ctx context.Context, sources []viewmodels.ProgrammaticSourceOption, credentialKind, status, riskLevel, expiryState string, expiresInDays int, query string) ([]gen.CredentialArtifact, error) {
-	out := make([]gen.CredentialArtifact, 0)
+func appAssetKindCountsParams(sources []viewmodels.ProgrammaticSourceOption, owner identityAccountFilter, tag credentialTagFilter, query string) gen.ListAppAssetKindCountsBySourceSetParams {
+	params := gen.ListAppAssetKindCountsBySourceSetParams{
+		SourceKinds:      make([]string, 0, len(sources)),
+		SourceNames:      make([]string, 0, len(sources)),
+		OwnerSourceKinds: []string{},
+		OwnerSourceNames: []string{},
+		OwnerExternalIds: []string{},
+		OwnerFilter:      owner.Active(),
+		Query:            query,
+		TagKey:           tag.Key,
+		TagValue:         tag.Value,
+	}
 	for _, source := range sources {
-		rows, err := h.listCredentialsForSource(ctx, source, credentialKind, status, riskLevel, expiryState, expiresInDays, query)
+		params.SourceKinds = append(params.SourceKinds, source.SourceKind)
+		params.SourceNames = append(params.SourceNames, source.SourceName)
+		for _, externalID := range owner.ExternalIDsForSource(source.SourceKind, source.SourceName) {
+			params.OwnerSourceKinds = append(params.OwnerSourceKinds, source.SourceKind)
+			params.OwnerSourceNames = append(params.OwnerSourceNames, source.SourceName)
+			params.OwnerExternalIds = append(params.OwnerExternalIds, externalID)
+		}
+	}
+	return params
+}
+
+// appAssetKindFacets turns per-kind counts into facets. A selected kind with no matching assets
+// is kept with a zero count so the active filter stays visible and can be cleared.
+func appAssetKindFacets(rows []gen.ListAppAssetKindCountsBySourceSetRow, selectedKind string) []viewmodels.AppAssetKindFacet {
+	facets := make([]viewmodels.AppAssetKindFacet, 0, len(rows)+1)
+	selectedSeen := selectedKind == ""
+	for _, row := range rows {
+		kind := strings.TrimSpace(row.AssetKind)
+		if kind == "" {
+			continue
+		}
+		selected := kind == selectedKind
+		selectedSeen = selectedSeen || selected
+		facets = append(facets, viewmodels.AppAssetKindFacet{Kind: kind, Count: row.AssetCount, Selected: selected})
+	}
+	if !selectedSeen {
+		facets = append(facets, viewmodels.AppAssetKindFacet{Kind: selectedKind, Selected: true})
+	}
+	return facets
+}
+
+func (h *Handlers) listAppAssetsAcrossSources(ctx context.Context, sources []viewmodels.ProgrammaticSourceOption, assetKind string, owner identityAccountFilter, tag credentialTagFilter, query string, limit int) ([]gen.AppAsset, bool, error) {
+	return collectAcrossSources(sources, limit, func(source viewmodels.ProgrammaticSourceOption, maxRows int) ([]gen.AppAsset, error) {
+		if owner.Active() && len(owner.ExternalIDsForSource(source.SourceKind, source.SourceName)) == 0 {
+			return nil, nil
+		}
+		return h.listAppAssetsForSource(ctx, source, assetKind, owner, tag, query, maxRows)
+	})
+}
+
+func (h *Handlers) listAppAssetsForSource(ctx context.Context, source viewmodels.ProgrammaticSourceOption, assetKind string, owner identityAccountFilter, tag credentialTagFilter, query string, maxRows int) ([]gen.AppAsset, error) {
+	const pageSize = 1000
+	out := make([]gen.AppAsset, 0)
+	for offset := 0; len(out) < maxRows; offset += pageSize {
+		limit := min(pageSize, maxRows-len(out))
+		rows, err := h.Q.ListAppAssetsPageBySourceAndQueryAndKind(ctx, gen.ListAppAssetsPageBySourceAndQueryAndKindParams{
+			SourceKind:       source.SourceKind,
+			SourceName:       source.SourceName,
+			AssetKind:        assetKind,
+			Query:            query,
+			PageLimit:        int32(limit),
+			PageOffset:       int32(offset),
+			OwnerFilter:      owner.Active(),
+			OwnerExternalIds: owner.ExternalIDsForSource(source.SourceKind, source.SourceName),
+			TagKey:           tag.Key,
+			TagValue:         tag.Value,
+		})
 		if err != nil {
 			return nil, err
 		}
 		out = append(out, rows...)
+		if len(rows) < limit {
+			break
+		}
 	}
 	return out, nil
 }
 
-func (h *Handlers) listCredentialsForSource(ctx context.Context, source viewmodels.ProgrammaticSourceOption, credentialKind, status, riskLevel, expiryState string, expiresInDays int, query string) ([]gen.CredentialArtifact, error) {
+func (h *Handlers) listCredentialsAcrossSources(ctx context.Context, sources []viewmodels.ProgrammaticSourceOption, credentialKind, status, riskLevel, expiryState, lastUsed string, expiresInDays, introducedInDays int, sharedOnly, broadScopeOnly bool, createdBy identityAccountFilter, tag credentialTagFilter, query string, limit int) ([]gen.CredentialArtifact, bool, error) {
+	return collectAcrossSources(sources, limit, func(source viewmodels.ProgrammaticSourceOption, maxRows int) ([]gen.CredentialArtifact, error) {
+		if createdBy.Active() && len(createdBy.ExternalIDsForSource(source.SourceKind, source.SourceName)) == 0 {
+			return nil, nil
+		}
+		return h.listCredentialsForSource(ctx, source, credentialKind, status, riskLevel, expiryState, lastUsed, expiresInDays, introducedInDays, sharedOnly, broadScopeOnly, createdBy, tag, query, maxRows)
+	})
+}
+
+func (h *Handlers) listCredentialsForSource(ctx context.Context, source viewmodels.ProgrammaticSourceOption, credentialKind, status, riskLevel, expiryState, lastUsed string, expiresInDays, introducedInDays int, sharedOnly, broadScopeOnly bool, createdBy identityAccountFilter, tag credentialTagFilter, query string, maxRows int) ([]gen.CredentialArtifact, error) {
 	const pageSize = 1000
 	out := make([]gen.CredentialArtifact, 0)
-	for offset := 0; ; offset += pageSize {
+	for offset := 0; len(out) < maxRows; offset += pageSize {
+		limit := min(pageSize, maxRows-len(out))
 		rows, err := h.Q.ListCredentialArtifactsPageBySourceAndQueryAndFilters(ctx, gen.ListCredentialArtifactsPageBySourceAndQueryAndFiltersParams{
-			SourceKind:     source.SourceKind,
-			SourceName:     source.SourceName,
-			CredentialKind: credentialKind,
-			Status:         status,
-			RiskLevel:      riskLevel,
-			ExpiryState:    expiryState,
-			ExpiresInDays:  int32(expiresInDays),
-			Query:          query,
-			PageLimit:      int32(pageSize),
-			PageOffset:     int32(offset),
+			SourceKind:           source.SourceKind,
+			SourceName:           source.SourceName,
+			CredentialKind:       credentialKind,
+			Status:               status,
+			RiskLevel:            riskLevel,
+			ExpiryState:          expiryState,
+			ExpiresInDays:        int32(expiresInDays),
+			IntroducedInDays:     int32(introducedInDays),
+			Query:                query,
+			PageLimit:            int32(limit),
+			PageOffset:           int32(offset),
+			SharedOnly:           sharedOnly,
+			SharedNamePatterns:   h.Cfg.CredentialSharedNamePatterns,
+			CreatedByFilter:      createdBy.Active(),
+			CreatedByExternalIds: createdBy.ExternalIDsForSource(source.SourceKind, source.SourceName),
+			TagKey:               tag.Key,
+			TagValue:             tag.Value,
+			LastUsed:             lastUsed,
+			BroadScopeOnly:       broadScopeOnly,
 		})
 		if err != nil {
 			return nil, err
 		}
 		out = append(out, rows...)
-		if len(rows) < pageSize {
+		if len(rows) < limit {
 			break
 		}
 	}
 	return out, nil
 }
 
-func sortAppAssetsForList(rows []gen.AppAsset) {
-	sort.SliceStable(rows, func(i, j int) bool {
-		leftName := strings.ToLower(strings.TrimSpace(rows[i].DisplayName))
-		if leftName == "" {
-			leftName = strings.ToLower(strings.TrimSpace(rows[i].ExternalID))
+// identityAccountFilter narrows programmatic lists to rows created or owned by
+// one of an identity's linked accounts. External IDs are grouped by source so
+// each per-source query only matches accounts observed in that source.
+type identityAccountFilter struct {
+	IdentityID  int64
+	Label       string
+	externalIDs map[string][]string
+}
+
+func newIdentityAccountFilter(identityID int64, label string, accounts []gen.Account) identityAccountFilter {
+	filter := identityAccountFilter{
+		IdentityID:  identityID,
+		Label:       strings.TrimSpace(label),
+		externalIDs: make(map[string][]string, len(accounts)),
+	}
+	seen := make(map[string]struct{}, len(accounts))
+	for _, account := range accounts {
+		externalID := strings.ToLower(strings.TrimSpace(account.ExternalID))
+		if externalID == "" {
+			continue
+		}
+		key := sourceKey(account.SourceKind, account.SourceName)
+		if _, ok := seen[key+"::"+externalID]; ok {
+			continue
 		}
-		rightName := strings.ToLower(strings.TrimSpace(rows[j].DisplayName))
-		if rightName == "" {
-			rightName = strings.ToLower(strings.TrimSpace(rows[j].ExternalID))
+		seen[key+"::"+externalID] = struct{}{}
+		filter.externalIDs[key] = append(filter.externalIDs[key], externalID)
+	}
+	return filter
+}
+
+func (f identityAccountFilter) Active() bool {
+	return f.IdentityID > 0
+}
+
+// ExternalIDsForSource returns the lowercased external IDs of the identity's
+// accounts in the given source. The result is never nil so the query receives
+// an empty array rather than NULL.
+func (f identityAccountFilter) ExternalIDsForSource(sourceKind, sourceName string) []string {
+	ids := f.externalIDs[sourceKey(sourceKind, sourceName)]
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
+
+func (h *Handlers) loadIdentityAccountFilter(ctx context.Context, raw string) (identityAccountFilter, error) {
+	identityID, err := parsePositiveInt64Param(raw)
+	if err != nil {
+		return identityAccountFilter{}, nil
+	}
+
+	label := fmt.Sprintf("Identity #%d", identityID)
+	summary, err := h.Q.GetIdentitySummaryByID(ctx, identityID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return newIdentityAccountFilter(identityID, label, nil), nil
+	case err != nil:
+		return identityAccountFilter{}, err
+	}
+	label = identityNamePrimary(summary.DisplayName, summary.PrimaryEmail, identityID)
+
+	accounts, err := h.Q.ListLinkedAccountsForIdentity(ctx, identityID)
+	if err != nil {
+		return identityAccountFilter{}, err
+	}
+	return newIdentityAccountFilter(identityID, label, accounts), nil
+}
+
+// normalizeAppAssetSort maps the sort query param onto the app asset allowlist.
+// The empty string is the default name-ascending order.
+func normalizeAppAssetSort(raw string) string {
+	switch value := strings.ToLower(strings.TrimSpace(raw)); value {
+	case "name_desc", "kind_asc", "updated_desc", "updated_asc", "risk_desc":
+		return value
+	default:
+		return ""
+	}
+}
+
+// normalizeCredentialSort maps the sort query param onto the credential allowlist.
+// The empty string is the default expiry-ascending order.
+func normalizeCredentialSort(raw string) string {
+	switch value := strings.ToLower(strings.TrimSpace(raw)); value {
+	case "expires_desc", "risk_desc", "risk_asc", "last_used_desc", "last_used_asc", "name_asc", "name_desc":
+		return value
+	default:
+		return ""
+	}
+}
+
+// sortAppAssetsForList mirrors the ORDER BY of ListAppAssetsPageBySourceAndQueryAndKind so
+// multi-source pages match single-source ones. risk_desc, which has no SQL equivalent, orders by riskRanks (keyed by asset ID).
+func sortAppAssetsForList(rows []gen.AppAsset, sortKey string, riskRanks map[int64]int) {
+	sortKey = normalizeAppAssetSort(sortKey)
+	sort.SliceStable(rows, func(i, j int) bool {
+		leftName := programmaticSortName(rows[i].DisplayName, rows[i].ExternalID)
+		rightName := programmaticSortName(rows[j].DisplayName, rows[j].ExternalID)
+
+		switch sortKey {
+		case "risk_desc":
+			if riskRanks[rows[i].ID] != riskRanks[rows[j].ID] {
+				return riskRanks[rows[i].ID] > riskRanks[rows[j].ID]
+			}
+		case "name_desc":
+			if leftName != rightName {
+				return leftName > rightName
+			}
+		case "kind_asc":
+			if rows[i].AssetKind != rows[j].AssetKind {
+				return rows[i].AssetKind < rows[j].AssetKind
+			}
+		case "updated_desc":
+			if c := compareTimestamptzNullsFirst(rows[i].UpdatedAtSource, rows[j].UpdatedAtSource); c != 0 {
+				return c > 0
+			}
+		case "updated_asc":
+			if c := compareTimestamptzNullsFirst(rows[i].UpdatedAtSource, rows[j].UpdatedAtSource); c != 0 {
+				return c < 0
+			}
 		}
+
 		if leftName != rightName {
 			return leftName < rightName
 		}
@@ -806,25 +1346,56 @@ func sortAppAssetsForList(rows []gen.AppAsset) {
 	})
 }
 
-func sortCredentialsForList(rows []gen.CredentialArtifact) {
-	sort.SliceStable(rows, func(i, j int) bool {
-		leftExpires := rows[i].ExpiresAtSource
-		rightExpires := rows[j].ExpiresAtSource
-		if leftExpires.Valid != rightExpires.Valid {
-			// NULL sorts last, equivalent to COALESCE(expires_at_source, 'infinity').
-			return leftExpires.Valid
-		}
-		if leftExpires.Valid && !leftExpires.Time.Equal(rightExpires.Time) {
-			return leftExpires.Time.Before(rightExpires.Time)
+// sortCredentialsForList mirrors the ORDER BY of ListCredentialArtifactsPageBySourceAndQueryAndFilters
+// so multi-source pages match single-source ones.
+func sortCredentialsForList(rows []gen.CredentialArtifact, sortKey string, now time.Time, policy credentialRiskPolicy) {
+	sortKey = normalizeCredentialSort(sortKey)
+
+	var riskRanks map[int64]int
+	if sortKey == "risk_desc" || sortKey == "risk_asc" {
+		riskRanks = make(map[int64]int, len(rows))
+		for _, row := range rows {
+			riskRanks[row.ID] = credentialRiskRank(credentialRiskLevel(row, now, policy))
 		}
+	}
 
-		leftName := strings.ToLower(strings.TrimSpace(rows[i].DisplayName))
-		if leftName == "" {
-			leftName = strings.ToLower(strings.TrimSpace(rows[i].ExternalID))
+	sort.SliceStable(rows, func(i, j int) bool {
+		leftName := programmaticSortName(rows[i].DisplayName, rows[i].ExternalID)
+		rightName := programmaticSortName(rows[j].DisplayName, rows[j].ExternalID)
+
+		switch sortKey {
+		case "risk_desc":
+			if riskRanks[rows[i].ID] != riskRanks[rows[j].ID] {
+				return riskRanks[rows[i].ID] > riskRanks[rows[j].ID]
+			}
+		case "risk_asc":
+			if riskRanks[rows[i].ID] != riskRanks[rows[j].ID] {
+				return riskRanks[rows[i].ID] < riskRanks[rows[j].ID]
+			}
+		case "last_used_desc":
+			if c := compareTimestamptzNullsFirst(rows[i].LastUsedAtSource, rows[j].LastUsedAtSource); c != 0 {
+				return c > 0
+			}
+		case "last_used_asc":
+			if c := compareTimestamptzNullsFirst(rows[i].LastUsedAtSource, rows[j].LastUsedAtSource); c != 0 {
+				return c < 0
+			}
+		case "name_asc":
+			if leftName != rightName {
+				return leftName < rightName
+			}
+		case "name_desc":
+			if leftName != rightName {
+				return leftName > rightName
+			}
+		case "expires_desc":
+			if c := compareExpiresAt(rows[i].ExpiresAtSource, rows[j].ExpiresAtSource); c != 0 {
+				return c > 0
+			}
 		}
-		rightName := strings.ToLower(strings.TrimSpace(rows[j].DisplayName))
-		if rightName == "" {
-			rightName = strings.ToLower(strings.TrimSpace(rows[j].ExternalID))
+
+		if c := compareExpiresAt(rows[i].ExpiresAtSource, rows[j].ExpiresAtSource); c != 0 {
+			return c < 0
 		}
 		if leftName != rightName {
 			return leftName < rightName
@@ -839,6 +1410,57 @@ func sortCredentialsForList(rows []gen.CredentialArtifact) {
 	})
 }
 
+func programmaticSortName(displayName, externalID string) string {
+	name := strings.ToLower(strings.TrimSpace(displayName))
+	if name == "" {
+		name = strings.ToLower(strings.TrimSpace(externalID))
+	}
+	return name
+}
+
+// compareExpiresAt orders a missing expiry after every real one, equivalent to
+// COALESCE(expires_at_source, 'infinity').
+func compareExpiresAt(left, right pgtype.Timestamptz) int {
+	if left.Valid != right.Valid {
+		if left.Valid {
+			return -1
+		}
+		return 1
+	}
+	if !left.Valid {
+		return 0
+	}
+	return left.Time.Compare(right.Time)
+}
+
+// compareTimestamptzNullsFirst orders a missing timestamp before every real one, so a
+// never-used credential reads as the least recently used.
+func compareTimestamptzNullsFirst(left, right pgtype.Timestamptz) int {
+	if left.Valid != right.Valid {
+		if left.Valid {
+			return 1
+		}
+		return -1
+	}
+	if !left.Valid {
+		return 0
+	}
+	return left.Time.Compare(right.Time)
+}
+
+func credentialRiskRank(level string) int {
+	switch level {
+	case "critical":
+		return 4
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	default:
+		return 1
+	}
+}
+
 func paginateAppAssets(rows []gen.AppAsset, offset, limit int) []gen.AppAsset {
 	if offset < 0 {
 		offset = 0
@@ -885,6 +1507,55 @@ func parseIntParamDefault(raw string, defaultValue int) int {
 	return value
 }
 
+// Credential last-used filters and buckets. The filter values and day thresholds mirror the
+// last_used clause of ListCredentialArtifactsPageBySourceAndQueryAndFilters.
+const (
+	credentialLastUsedNever      = "never"
+	credentialLastUsedRecent     = "used_30d"
+	credentialLastUsedUnused90d  = "unused_90d"
+	credentialLastUsedUnused180d = "unused_180d"
+	// credentialLastUsedIdle is a display-only bucket for 30–90 days; it has no filter.
+	credentialLastUsedIdle = "idle"
+)
+
+func normalizeCredentialLastUsedFilter(raw string) string {
+	switch v := strings.ToLower(strings.TrimSpace(raw)); v {
+	case credentialLastUsedNever, credentialLastUsedRecent, credentialLastUsedUnused90d, credentialLastUsedUnused180d:
+		return v
+	default:
+		return ""
+	}
+}
+
+// credentialLastUsedBucket places a credential's last use into the narrowest recency bucket.
+func credentialLastUsedBucket(lastUsed pgtype.Timestamptz, now time.Time) string {
+	if !lastUsed.Valid {
+		return credentialLastUsedNever
+	}
+	age := now.Sub(lastUsed.Time)
+	switch {
+	case age <= 30*24*time.Hour:
+		return credentialLastUsedRecent
+	case age <= 90*24*time.Hour:
+		return credentialLastUsedIdle
+	case age <= 180*24*time.Hour:
+		return credentialLastUsedUnused90d
+	default:
+		return credentialLastUsedUnused180d
+	}
+}
+
+// filterCredentialsByRiskLevel keeps the rows whose risk level under policy is level.
+func filterCredentialsByRiskLevel(rows []gen.CredentialArtifact, level string, now time.Time, policy credentialRiskPolicy) []gen.CredentialArtifact {
+	out := rows[:0]
+	for _, row := range rows {
+		if credentialRiskLevel(row, now, policy) == level {
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
 func normalizeCredentialRiskFilter(raw string) string {
 	switch strings.ToLower(strings.TrimSpace(raw)) {
 	case "critical", "high", "medium", "low":
@@ -910,18 +1581,29 @@ func actorDisplayName(displayName, externalID string) string {
 	return strings.TrimSpace(externalID)
 }
 
-func formatProgrammaticTime(value pgtype.Timestamptz) string {
+// formatProgrammaticTime renders value as a minute-precision time in the viewer's display zone.
+func formatProgrammaticTime(value pgtype.Timestamptz, loc *time.Location) string {
 	if !value.Valid {
 		return "—"
 	}
-	return value.Time.UTC().Format("Jan 2, 2006 15:04 UTC")
+	return inDisplayLocation(value.Time, loc).Format("Jan 2, 2006 15:04 MST")
 }
 
-func formatProgrammaticDate(value pgtype.Timestamptz) string {
-	return identityCalendarDate(value)
+// formatProgrammaticDate renders value as a calendar date in the viewer's display zone.
+func formatProgrammaticDate(value pgtype.Timestamptz, loc *time.Location) string {
+	return identityCalendarDate(value, loc)
 }
 
-func credentialRiskLevel(credential gen.CredentialArtifact, now time.Time) string {
+// credentialRiskLevel rates credential with the built-in heuristics, then bounds the result by
+// the policy's per-kind overrides.
+func credentialRiskLevel(credential gen.CredentialArtifact, now time.Time, policy credentialRiskPolicy) string {
+	level, _ := policy.applyOverrides(credential, credentialHeuristicRiskLevel(credential, now, policy))
+	return level
+}
+
+// credentialHeuristicRiskLevel is the Go counterpart of the credential_heuristic_risk_level SQL
+// function (migration 000052) used by the list filter, risk sort, and metrics; change both together.
+func credentialHeuristicRiskLevel(credential gen.CredentialArtifact, now time.Time, policy credentialRiskPolicy) string {
 	now = now.UTC()
 	status := strings.ToLower(strings.TrimSpace(credential.Status))
 	credentialKind := strings.ToLower(strings.TrimSpace(credential.CredentialKind))
@@ -929,13 +1611,13 @@ func credentialRiskLevel(credential gen.CredentialArtifact, now time.Time) strin
 	approvedByExternalID := strings.TrimSpace(credential.ApprovedByExternalID)
 
 	if credential.ExpiresAtSource.Valid && credential.ExpiresAtSource.Time.UTC().Before(now) {
-		if isCredentialStatusActiveLike(status) {
+		if policy.isActiveStatus(status) {
 			return "critical"
 		}
 		return "high"
 	}
 
-	if isHighPrivilegeCredentialKind(credentialKind) && createdByExternalID == "" && approvedByExternalID == "" {
+	if policy.isHighPrivilegeKind(credentialKind) && createdByExternalID == "" && approvedByExternalID == "" {
 		return "critical"
 	}
 
@@ -946,6 +1628,14 @@ func credentialRiskLevel(credential gen.CredentialArtifact, now time.Time) strin
 		}
 	}
 
+	if !credential.ExpiresAtSource.Valid && isExpiryExpectedCredentialKind(credentialKind) && policy.isActiveStatus(status) {
+		return "high"
+	}
+
+	if !credential.ExpiresAtSource.Valid && !credential.LastUsedAtSource.Valid && isSharedNonExpiringCredentialKind(credentialKind) && policy.isActiveStatus(status) {
+		return "high"
+	}
+
 	if createdByExternalID == "" {
 		return "high"
 	}
@@ -964,7 +1654,7 @@ func credentialRiskLevel(credential gen.CredentialArtifact, now time.Time) strin
 	return "low"
 }
 
-func credentialRiskReasons(credential gen.CredentialArtifact, now time.Time) []string {
+func credentialRiskReasons(credential gen.CredentialArtifact, now time.Time, sharedNamePatterns []string, rotationSLADays map[string]int, policy credentialRiskPolicy) []string {
 	now = now.UTC()
 	reasons := make([]string, 0, 4)
 
@@ -974,14 +1664,14 @@ func credentialRiskReasons(credential gen.CredentialArtifact, now time.Time) []s
 	approvedByExternalID := strings.TrimSpace(credential.ApprovedByExternalID)
 
 	if credential.ExpiresAtSource.Valid && credential.ExpiresAtSource.Time.UTC().Before(now) {
-		if isCredentialStatusActiveLike(status) {
+		if policy.isActiveStatus(status) {
 			reasons = append(reasons, "Credential has expired while still marked active.")
 		} else {
 			reasons = append(reasons, "Credential has expired.")
 		}
 	}
 
-	if isHighPrivilegeCredentialKind(credentialKind) && createdByExternalID == "" && approvedByExternalID == "" {
+	if policy.isHighPrivilegeKind(credentialKind) && createdByExternalID == "" && approvedByExternalID == "" {
 		reasons = append(reasons, "High-privilege credential has no creator or approver attribution.")
 	}
 
@@ -994,14 +1684,39 @@ func credentialRiskReasons(credential gen.CredentialArtifact, now time.Time) []s
 		}
 	}
 
+	if !credential.ExpiresAtSource.Valid && isExpiryExpectedCredentialKind(credentialKind) && policy.isActiveStatus(status) {
+		reasons = append(reasons, "Credential never expires.")
+	}
+
+	if !credential.ExpiresAtSource.Valid && !credential.LastUsedAtSource.Valid && isSharedNonExpiringCredentialKind(credentialKind) && policy.isActiveStatus(status) {
+		reasons = append(reasons, "Shared, never-expiring credential has no recorded use.")
+	}
+
+	if ageDays, slaDays, violated := credentialRotationSLAAge(credential, now, rotationSLADays, policy.ActiveStatuses); violated {
+		reasons = append(reasons, fmt.Sprintf("Credential is %d days old, past its %d-day rotation SLA.", ageDays, slaDays))
+	}
+
 	if createdByExternalID == "" {
 		reasons = append(reasons, "Creator attribution is missing.")
 	}
 
+	if isSharedServiceCredential(credential, sharedNamePatterns) {
+		reasons = append(reasons, "Credential appears to belong to a shared or service identity.")
+	}
+
 	if credential.LastUsedAtSource.Valid && credential.LastUsedAtSource.Time.UTC().Before(now.Add(-90*24*time.Hour)) {
 		reasons = append(reasons, "Credential has not been used in over 90 days.")
 	}
 
+	if isBroadScopeCredential(credential) {
+		reasons = append(reasons, "Credential can access every repository in the organization rather than selected ones.")
+	}
+
+	_, applied := policy.applyOverrides(credential, credentialHeuristicRiskLevel(credential, now, policy))
+	for _, override := range applied {
+		reasons = append(reasons, credentialRiskOverrideReason(override))
+	}
+
 	if len(reasons) == 0 {
 		reasons = append(reasons, "Credential metadata appears healthy based on current heuristics.")
 	}
@@ -1009,24 +1724,93 @@ func credentialRiskReasons(credential gen.CredentialArtifact, now time.Time) []s
 	return reasons
 }
 
-func isCredentialStatusActiveLike(status string) bool {
-	switch strings.ToLower(strings.TrimSpace(status)) {
-	case "", "active", "approved", "pending_approval":
+// isCredentialStatusActiveLike reports whether status is one of activeStatuses, the configured
+// statuses that still grant access. A blank status counts as "active", as in the credential queries.
+func isCredentialStatusActiveLike(status string, activeStatuses []string) bool {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if status == "" {
+		status = "active"
+	}
+	return slices.Contains(activeStatuses, status)
+}
+
+// isExpiryExpectedCredentialKind reports whether kind normally carries a TTL, so a missing
+// expiry means the credential was issued without one rather than that the source omits it.
+func isExpiryExpectedCredentialKind(kind string) bool {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "vault_approle_secret_id", "vault_token":
 		return true
 	default:
 		return false
 	}
 }
 
-func isHighPrivilegeCredentialKind(kind string) bool {
+// isSharedNonExpiringCredentialKind reports whether kind can never expire and is commonly
+// shared between people or scripts, so an unused one is standing access nobody relies on.
+func isSharedNonExpiringCredentialKind(kind string) bool {
 	switch strings.ToLower(strings.TrimSpace(kind)) {
-	case "entra_client_secret", "github_deploy_key", "github_pat_request", "github_pat_fine_grained":
+	case "bitbucket_app_password":
 		return true
 	default:
 		return false
 	}
 }
 
+// isSharedServiceCredential reports whether credential looks owned by a shared or service identity
+// rather than a person: a service-like creator kind, a creator whose name has one of the shared
+// name patterns as a word, or, when no creator is recorded, a credential name that has one. It mirrors the
+// shared_only filter of the credential list queries.
+func isSharedServiceCredential(credential gen.CredentialArtifact, namePatterns []string) bool {
+	if isServiceLikeActorKind(credential.CreatedByKind) {
+		return true
+	}
+	if strings.TrimSpace(credential.CreatedByExternalID) != "" {
+		return matchesSharedNamePattern(namePatterns, credential.CreatedByExternalID, credential.CreatedByDisplayName)
+	}
+	return matchesSharedNamePattern(namePatterns, programmaticSortName(credential.DisplayName, credential.ExternalID))
+}
+
+// isBroadScopeCredential reports whether credential's stored scope grants access to every
+// repository of its organization (repository_selection "all"), as GitHub fine-grained tokens and
+// token requests can, rather than to selected repositories. It mirrors the broad_scope_only
+// filter of the credential list queries.
+func isBroadScopeCredential(credential gen.CredentialArtifact) bool {
+	var scope struct {
+		RepositorySelection string `json:"repository_selection"`
+	}
+	if err := json.Unmarshal(credential.ScopeJson, &scope); err != nil {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(scope.RepositorySelection), "all")
+}
+
+func isServiceLikeActorKind(kind string) bool {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	for _, marker := range []string{"service_account", "service_principal", "bot", "application"} {
+		if strings.Contains(kind, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesSharedNamePattern reports whether any pattern is a whole word of one of values, so
+// "bot" matches "ci-bot@example.com" but not "abbott@example.com". Words are the runs of ASCII
+// letters and digits, as in the regexp_split_to_array of the credential list queries.
+func matchesSharedNamePattern(patterns []string, values ...string) bool {
+	for _, value := range values {
+		words := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+			return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+		})
+		for _, pattern := range patterns {
+			if pattern != "" && slices.Contains(words, pattern) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 type identityLinkResolver struct {
 	h                    *Handlers
 	ctx                  context.Context
@@ -1048,7 +1832,7 @@ func (r *identityLinkResolver) Resolve(sourceKind, sourceName, externalID, email
 		return ""
 	}
 
-	if candidate := emailCandidate(externalID); candidate != "" {
+	if candidate := matching.NormalizeEmail(externalID); candidate != "" {
 		if href := r.resolveByEmail(candidate); href != "" {
 			return href
 		}
@@ -1058,13 +1842,13 @@ func (r *identityLinkResolver) Resolve(sourceKind, sourceName, externalID, email
 		return href
 	}
 
-	if candidate := emailCandidate(email); candidate != "" {
+	if candidate := matching.NormalizeEmail(email); candidate != "" {
 		if href := r.resolveByEmail(candidate); href != "" {
 			return href
 		}
 	}
 
-	if candidate := emailCandidate(displayName); candidate != "" {
+	if candidate := matching.NormalizeEmail(displayName); candidate != "" {
 		if href := r.resolveByEmail(candidate); href != "" {
 			return href
 		}
@@ -1102,7 +1886,7 @@ func (r *identityLinkResolver) resolveBySourceAndExternalID(sourceKind, sourceNa
 }
 
 func (r *identityLinkResolver) resolveByEmail(candidate string) string {
-	candidate = emailCandidate(candidate)
+	candidate = matching.NormalizeEmail(candidate)
 	if candidate == "" {
 		return ""
 	}
@@ -1121,29 +1905,6 @@ func (r *identityLinkResolver) resolveByEmail(candidate string) string {
 	return href
 }
 
-func emailCandidate(raw string) string {
-	raw = strings.TrimSpace(raw)
-	if raw == "" {
-		return ""
-	}
-
-	if left := strings.Index(raw, "<"); left >= 0 {
-		if right := strings.Index(raw[left+1:], ">"); right >= 0 {
-			raw = raw[left+1 : left+1+right]
-		}
-	}
-
-	raw = strings.Trim(strings.TrimSpace(raw), "\"'<>[](){}.,;")
-	raw = strings.ToLower(strings.TrimSpace(raw))
-	if raw == "" || strings.Count(raw, "@") != 1 {
-		return ""
-	}
-	if strings.ContainsAny(raw, " \t\r\n") {
-		return ""
-	}
-	return raw
-}
-
 func prettyProgrammaticJSON(raw []byte) string {
 	if len(raw) == 0 {
 		return "{}"
@@ -1155,6 +1916,27 @@ func prettyProgrammaticJSON(raw []byte) string {
 	return out.String()
 }
 
+// inlineJSONMaxBytes caps the pretty-printed JSON a detail page renders inline. Provider
+// payloads can run to megabytes, so larger documents are cut short and the page links to the
+// full document instead.
+const inlineJSONMaxBytes = 32 << 10
+
+// truncatePrettyJSON cuts pretty-printed JSON to at most limit bytes, at the last line break
+// when there is one, and reports whether anything was cut.
+func truncatePrettyJSON(pretty string, limit int) (string, bool) {
+	if limit <= 0 || len(pretty) <= limit {
+		return pretty, false
+	}
+	for limit > 0 && !utf8.RuneStart(pretty[limit]) {
+		limit--
+	}
+	cut := pretty[:limit]
+	if i := strings.LastIndexByte(cut, '\n'); i > 0 {
+		cut = cut[:i]
+	}
+	return cut, true
+}
+
 func credentialRefKey(kind, externalID string) string {
 	return strings.TrimSpace(kind) + "|" + strings.TrimSpace(externalID)
 }
@@ -1171,7 +1953,7 @@ func appAssetCredentialRef(asset gen.AppAsset) (string, string) {
 	}
 
 	switch NormalizeConnectorKind(asset.SourceKind) {
-	case configstore.KindEntra:
+	case configstore.KindEntra, configstore.KindOkta:
 		return "app_asset", appAssetRefExternalID(assetKind, externalID)
 	case configstore.KindGoogleWorkspace:
 		refKind := assetKind
@@ -1232,6 +2014,49 @@ func appAssetCredentialRefs(asset gen.AppAsset) []gen.ListCredentialArtifactsFor
 	return refs
 }
 
+// appAssetLinkage is the app registration and service principal pairing a connector stores in
+// an asset's raw JSON, with the orphan flag set when one side is missing.
+type appAssetLinkage struct {
+	ServicePrincipalID string `json:"service_principal_id"`
+	ApplicationID      string `json:"application_id"`
+	Orphan             string `json:"orphan"`
+}
+
+func parseAppAssetLinkage(raw []byte) appAssetLinkage {
+	var linkage appAssetLinkage
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &linkage)
+	}
+	linkage.ServicePrincipalID = strings.TrimSpace(linkage.ServicePrincipalID)
+	linkage.ApplicationID = strings.TrimSpace(linkage.ApplicationID)
+	linkage.Orphan = strings.TrimSpace(linkage.Orphan)
+	return linkage
+}
+
+// appAssetLinkedAsset returns the label and href of the asset paired with asset, or empty
+// strings when it has none or the pair is not in inventory.
+func (h *Handlers) appAssetLinkedAsset(ctx context.Context, asset gen.AppAsset, linkage appAssetLinkage) (string, string) {
+	var linkedKind, linkedExternalID, label string
+	switch {
+	case linkage.ServicePrincipalID != "":
+		linkedKind, linkedExternalID, label = "entra_service_principal", linkage.ServicePrincipalID, "Service principal"
+	case linkage.ApplicationID != "":
+		linkedKind, linkedExternalID, label = "entra_application", linkage.ApplicationID, "App registration"
+	default:
+		return "", ""
+	}
+	linked, err := h.Q.GetAppAssetBySourceAndKindAndExternalID(ctx, gen.GetAppAssetBySourceAndKindAndExternalIDParams{
+		SourceKind: strings.TrimSpace(asset.SourceKind),
+		SourceName: strings.TrimSpace(asset.SourceName),
+		AssetKind:  linkedKind,
+		ExternalID: linkedExternalID,
+	})
+	if err != nil {
+		return "", ""
+	}
+	return label + ": " + firstNonEmpty(strings.TrimSpace(linked.DisplayName), linkedExternalID), "/app-assets/" + strconv.FormatInt(linked.ID, 10)
+}
+
 func appAssetRefExternalID(assetKind, externalID string) string {
 	assetKind = strings.TrimSpace(assetKind)
 	externalID = strings.TrimSpace(externalID)
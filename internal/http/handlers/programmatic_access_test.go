@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/alexedwards/scs/v2"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/open-sspm/open-sspm/internal/connectors/configstore"
 	"github.com/open-sspm/open-sspm/internal/db/gen"
@@ -35,6 +39,43 @@ func TestNormalizeCredentialRiskFilter(t *testing.T) {
 	}
 }
 
+func TestCredentialLastUsedBucket(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: now.Add(-time.Duration(days) * 24 * time.Hour), Valid: true}
+	}
+
+	cases := []struct {
+		name     string
+		lastUsed pgtype.Timestamptz
+		want     string
+	}{
+		{name: "never used", lastUsed: pgtype.Timestamptz{}, want: credentialLastUsedNever},
+		{name: "null time with zero value", lastUsed: pgtype.Timestamptz{Time: now, Valid: false}, want: credentialLastUsedNever},
+		{name: "used today", lastUsed: daysAgo(0), want: credentialLastUsedRecent},
+		{name: "used 30 days ago", lastUsed: daysAgo(30), want: credentialLastUsedRecent},
+		{name: "idle", lastUsed: daysAgo(45), want: credentialLastUsedIdle},
+		{name: "dormant 91 days", lastUsed: daysAgo(91), want: credentialLastUsedUnused90d},
+		{name: "dormant 181 days", lastUsed: daysAgo(181), want: credentialLastUsedUnused180d},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := credentialLastUsedBucket(tc.lastUsed, now); got != tc.want {
+				t.Fatalf("credentialLastUsedBucket() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	for raw, want := range map[string]string{"never": "never", " Unused_180D ": "unused_180d", "used_30d": "used_30d", "idle": "", "bogus": ""} {
+		if got := normalizeCredentialLastUsedFilter(raw); got != want {
+			t.Fatalf("normalizeCredentialLastUsedFilter(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
 func TestFormatProgrammaticDate(t *testing.T) {
 	t.Parallel()
 
@@ -58,7 +99,7 @@ func TestFormatProgrammaticDate(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			if got := formatProgrammaticDate(tc.value); got != tc.want {
+			if got := formatProgrammaticDate(tc.value, time.UTC); got != tc.want {
 				t.Fatalf("formatProgrammaticDate() = %q, want %q", got, tc.want)
 			}
 		})
@@ -112,6 +153,35 @@ func TestCredentialRiskLevel(t *testing.T) {
 			},
 			want: "high",
 		},
+		{
+			name: "high when vault secret-id never expires",
+			credential: gen.CredentialArtifact{
+				Status:               "active",
+				CredentialKind:       "vault_approle_secret_id",
+				CreatedByExternalID:  "owner@example.com",
+				ApprovedByExternalID: "approver@example.com",
+			},
+			want: "high",
+		},
+		{
+			name: "high when shared never-expiring app password has never been used",
+			credential: gen.CredentialArtifact{
+				Status:              "active",
+				CredentialKind:      "bitbucket_app_password",
+				CreatedByExternalID: "{owner}",
+			},
+			want: "high",
+		},
+		{
+			name: "low when shared never-expiring app password is in recent use",
+			credential: gen.CredentialArtifact{
+				Status:              "active",
+				CredentialKind:      "bitbucket_app_password",
+				CreatedByExternalID: "{owner}",
+				LastUsedAtSource:    timestamptz(now.Add(-2 * 24 * time.Hour)),
+			},
+			want: "low",
+		},
 		{
 			name: "medium when expiring within thirty days",
 			credential: gen.CredentialArtifact{
@@ -139,7 +209,7 @@ func TestCredentialRiskLevel(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			if got := credentialRiskLevel(tc.credential, now); got != tc.want {
+			if got := credentialRiskLevel(tc.credential, now, builtinCredentialRiskPolicy()); got != tc.want {
 				t.Fatalf("credentialRiskLevel() = %q, want %q", got, tc.want)
 			}
 		})
@@ -157,27 +227,149 @@ func TestCredentialRiskReasons(t *testing.T) {
 		LastUsedAtSource: timestamptz(now.Add(-120 * 24 * time.Hour)),
 	}
 
-	reasons := credentialRiskReasons(credential, now)
+	reasons := credentialRiskReasons(credential, now, nil, nil, builtinCredentialRiskPolicy())
 	if len(reasons) < 3 {
 		t.Fatalf("expected multiple reasons, got %v", reasons)
 	}
+
+	nonExpiring := gen.CredentialArtifact{
+		Status:              "active",
+		CredentialKind:      "vault_approle_secret_id",
+		CreatedByExternalID: "owner@example.com",
+	}
+	if reasons := credentialRiskReasons(nonExpiring, now, nil, nil, builtinCredentialRiskPolicy()); !slices.Contains(reasons, "Credential never expires.") {
+		t.Fatalf("expected never-expires reason, got %v", reasons)
+	}
+
+	dormantAppPassword := gen.CredentialArtifact{
+		Status:              "active",
+		CredentialKind:      "bitbucket_app_password",
+		CreatedByExternalID: "{owner}",
+		LastUsedAtSource:    timestamptz(now.Add(-120 * 24 * time.Hour)),
+	}
+	if got := credentialRiskLevel(dormantAppPassword, now, builtinCredentialRiskPolicy()); got != "high" {
+		t.Fatalf("credentialRiskLevel(dormant app password) = %q, want high", got)
+	}
+	reasons = credentialRiskReasons(dormantAppPassword, now, nil, nil, builtinCredentialRiskPolicy())
+	if !slices.Contains(reasons, "Credential has not been used in over 90 days.") {
+		t.Fatalf("expected dormant-usage reason, got %v", reasons)
+	}
+	if slices.Contains(reasons, "Credential never expires.") || slices.Contains(reasons, "Shared, never-expiring credential has no recorded use.") {
+		t.Fatalf("unexpected expiry reasons for used app password, got %v", reasons)
+	}
+
+	unusedAppPassword := gen.CredentialArtifact{
+		Status:              "active",
+		CredentialKind:      "bitbucket_app_password",
+		CreatedByExternalID: "{owner}",
+	}
+	if reasons := credentialRiskReasons(unusedAppPassword, now, nil, nil, builtinCredentialRiskPolicy()); !slices.Contains(reasons, "Shared, never-expiring credential has no recorded use.") {
+		t.Fatalf("expected unused shared credential reason, got %v", reasons)
+	}
 }
 
-func TestEmailCandidate(t *testing.T) {
+func TestIsSharedServiceCredential(t *testing.T) {
 	t.Parallel()
 
-	cases := []struct {
-		raw  string
-		want string
-	}{
-		{raw: "alice@example.com", want: "alice@example.com"},
-		{raw: "Alice <alice@example.com>", want: "alice@example.com"},
-		{raw: "not-an-email", want: ""},
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	patterns := []string{"bot", "svc", "service", "shared", "automation", "ci"}
+	sharedReason := "Credential appears to belong to a shared or service identity."
+
+	ciBot := gen.CredentialArtifact{
+		Status:         "active",
+		CredentialKind: "github_pat_fine_grained",
+		DisplayName:    "ci-bot",
+		ExternalID:     "pat-1",
 	}
-	for _, tc := range cases {
-		if got := emailCandidate(tc.raw); got != tc.want {
-			t.Fatalf("emailCandidate(%q) = %q, want %q", tc.raw, got, tc.want)
-		}
+	if !isSharedServiceCredential(ciBot, patterns) {
+		t.Fatalf("expected ci-bot token without a human owner to be shared")
+	}
+	if reasons := credentialRiskReasons(ciBot, now, patterns, nil, builtinCredentialRiskPolicy()); !slices.Contains(reasons, sharedReason) {
+		t.Fatalf("expected shared reason, got %v", reasons)
+	}
+	if isSharedServiceCredential(ciBot, nil) {
+		t.Fatalf("expected name matching to be disabled without patterns")
+	}
+
+	userToken := gen.CredentialArtifact{
+		Status:               "active",
+		CredentialKind:       "github_pat_fine_grained",
+		DisplayName:          "alice laptop token",
+		ExternalID:           "pat-2",
+		CreatedByKind:        "github_user",
+		CreatedByExternalID:  "alice",
+		CreatedByDisplayName: "Alice",
+	}
+	if isSharedServiceCredential(userToken, patterns) {
+		t.Fatalf("expected user token not to be shared")
+	}
+	if reasons := credentialRiskReasons(userToken, now, patterns, nil, builtinCredentialRiskPolicy()); slices.Contains(reasons, sharedReason) {
+		t.Fatalf("unexpected shared reason, got %v", reasons)
+	}
+
+	// "bot" and "service" appear inside these names but not as words.
+	humanCreator := gen.CredentialArtifact{
+		Status:               "active",
+		CredentialKind:       "github_pat_fine_grained",
+		DisplayName:          "deploy token",
+		CreatedByKind:        "github_user",
+		CreatedByExternalID:  "abbott@example.com",
+		CreatedByDisplayName: "Customer Servicedesk",
+	}
+	if isSharedServiceCredential(humanCreator, patterns) {
+		t.Fatalf("expected %q not to match shared name patterns", humanCreator.CreatedByExternalID)
+	}
+	humanCreator.CreatedByExternalID = "release-bot@example.com"
+	if !isSharedServiceCredential(humanCreator, patterns) {
+		t.Fatalf("expected %q to match shared name patterns", humanCreator.CreatedByExternalID)
+	}
+
+	serviceAccountKey := gen.CredentialArtifact{
+		Status:              "active",
+		CredentialKind:      "gcp_service_account_key",
+		DisplayName:         "key-1",
+		CreatedByKind:       "google_service_account",
+		CreatedByExternalID: "deployer@project.iam.gserviceaccount.com",
+	}
+	if !isSharedServiceCredential(serviceAccountKey, nil) {
+		t.Fatalf("expected service account owner to be shared")
+	}
+}
+
+func TestIsBroadScopeCredential(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	broadReason := "Credential can access every repository in the organization rather than selected ones."
+
+	allRepos := gen.CredentialArtifact{
+		Status:              "active",
+		CredentialKind:      "github_pat_fine_grained",
+		DisplayName:         "release token",
+		ExternalID:          "pat-1",
+		CreatedByKind:       "github_user",
+		CreatedByExternalID: "alice",
+		ScopeJson:           []byte(`{"organization":"acme","repository_selection":"all","permissions":{"contents":"write"}}`),
+	}
+	if !isBroadScopeCredential(allRepos) {
+		t.Fatalf("expected all-repositories token to be broad scope")
+	}
+	if reasons := credentialRiskReasons(allRepos, now, nil, nil, builtinCredentialRiskPolicy()); !slices.Contains(reasons, broadReason) {
+		t.Fatalf("expected broad scope reason, got %v", reasons)
+	}
+
+	selectedRepos := allRepos
+	selectedRepos.ExternalID = "pat-2"
+	selectedRepos.ScopeJson = []byte(`{"organization":"acme","repository_selection":"selected","permissions":{"contents":"write"}}`)
+	if isBroadScopeCredential(selectedRepos) {
+		t.Fatalf("expected selected-repositories token not to be broad scope")
+	}
+	if reasons := credentialRiskReasons(selectedRepos, now, nil, nil, builtinCredentialRiskPolicy()); slices.Contains(reasons, broadReason) {
+		t.Fatalf("unexpected broad scope reason, got %v", reasons)
+	}
+
+	if isBroadScopeCredential(gen.CredentialArtifact{CredentialKind: "github_deploy_key", ScopeJson: []byte(`{"read_only":true}`)}) {
+		t.Fatalf("expected credential without repository selection not to be broad scope")
 	}
 }
 
@@ -202,7 +394,7 @@ func TestSelectProgrammaticSource(t *testing.T) {
 		}
 	})
 
-	t.Run("selects source kind when kind and name are provided", func(t *testing.T) {
+	t.Run("selects the named source when kind and name are provided", func(t *testing.T) {
 		t.Parallel()
 
 		c, _ := newTestContext(http.MethodGet, "/credentials?source_kind=github&source_name=acme")
@@ -210,12 +402,26 @@ func TestSelectProgrammaticSource(t *testing.T) {
 		if !ok {
 			t.Fatalf("expected source selection to be available")
 		}
-		if selected.SourceKind != "github" || selected.SourceName != "" {
+		if selected.SourceKind != "github" || selected.SourceName != "acme" {
 			t.Fatalf("unexpected selection kind=%q name=%q", selected.SourceKind, selected.SourceName)
 		}
 	})
 
-	t.Run("maps source name to source kind for backward compatibility", func(t *testing.T) {
+	t.Run("keeps same-kind sources apart by name", func(t *testing.T) {
+		t.Parallel()
+
+		sameKind := append(slices.Clone(sources), viewmodels.ProgrammaticSourceOption{SourceKind: "github", SourceName: "acme-labs", Label: "GitHub"})
+		c, _ := newTestContext(http.MethodGet, "/credentials?source_kind=github&source_name=acme-labs")
+		selected, _ := selectProgrammaticSource(c, sameKind)
+		if selected.SourceKind != "github" || selected.SourceName != "acme-labs" {
+			t.Fatalf("unexpected selection kind=%q name=%q", selected.SourceKind, selected.SourceName)
+		}
+		if got := effectiveProgrammaticSources(selected, sameKind); len(got) != 1 || got[0].SourceName != "acme-labs" {
+			t.Fatalf("effective sources = %+v, want only acme-labs", got)
+		}
+	})
+
+	t.Run("maps a source name alone to its source", func(t *testing.T) {
 		t.Parallel()
 
 		c, _ := newTestContext(http.MethodGet, "/credentials?source_name=acme")
@@ -223,12 +429,73 @@ func TestSelectProgrammaticSource(t *testing.T) {
 		if !ok {
 			t.Fatalf("expected source selection to be available")
 		}
+		if selected.SourceKind != "github" || selected.SourceName != "acme" {
+			t.Fatalf("unexpected selection kind=%q name=%q", selected.SourceKind, selected.SourceName)
+		}
+	})
+
+	t.Run("falls back to the kind when the name is unknown", func(t *testing.T) {
+		t.Parallel()
+
+		c, _ := newTestContext(http.MethodGet, "/credentials?source_kind=github&source_name=missing")
+		selected, _ := selectProgrammaticSource(c, sources)
 		if selected.SourceKind != "github" || selected.SourceName != "" {
 			t.Fatalf("unexpected selection kind=%q name=%q", selected.SourceKind, selected.SourceName)
 		}
 	})
 }
 
+func TestSelectRememberedProgrammaticSource(t *testing.T) {
+	sources := []viewmodels.ProgrammaticSourceOption{
+		{SourceKind: "entra", SourceName: "tenant-1", Label: "Microsoft Entra"},
+		{SourceKind: "github", SourceName: "acme", Label: "GitHub"},
+	}
+	sessions := scs.New()
+	sessionCtx, err := sessions.Load(context.Background(), "")
+	if err != nil {
+		t.Fatalf("sessions.Load() error = %v", err)
+	}
+	h := &Handlers{Sessions: sessions}
+	selectFor := func(target string) viewmodels.ProgrammaticSourceOption {
+		c, _ := newTestContext(http.MethodGet, target)
+		c.SetRequest(c.Request().WithContext(sessionCtx))
+		selected, ok := h.selectRememberedProgrammaticSource(c, sources)
+		if !ok {
+			t.Fatalf("%s: expected source selection to be available", target)
+		}
+		return selected
+	}
+
+	if got := selectFor("/credentials"); got.SourceKind != "" {
+		t.Fatalf("first visit selection kind=%q, want all configured", got.SourceKind)
+	}
+
+	selectFor("/credentials?source_kind=github&source_name=acme")
+	if got := selectFor("/app-assets"); got.SourceKind != "github" || got.SourceName != "acme" {
+		t.Fatalf("remembered selection kind=%q name=%q, want github/acme", got.SourceKind, got.SourceName)
+	}
+
+	// An explicit filter overrides the remembered one, and an explicit "All configured" is
+	// remembered too.
+	if got := selectFor("/credentials?source_kind=entra"); got.SourceKind != "entra" || got.SourceName != "" {
+		t.Fatalf("explicit selection kind=%q name=%q, want entra", got.SourceKind, got.SourceName)
+	}
+	if got := selectFor("/credentials?source_kind="); got.SourceKind != "" {
+		t.Fatalf("explicit all configured kind=%q, want empty", got.SourceKind)
+	}
+	if got := selectFor("/credentials"); got.SourceKind != "" {
+		t.Fatalf("remembered all configured kind=%q, want empty", got.SourceKind)
+	}
+
+	// A remembered source that is no longer configured falls back to all configured.
+	selectFor("/credentials?source_kind=github")
+	c, _ := newTestContext(http.MethodGet, "/credentials")
+	c.SetRequest(c.Request().WithContext(sessionCtx))
+	if got, _ := h.selectRememberedProgrammaticSource(c, sources[:1]); got.SourceKind != "" {
+		t.Fatalf("stale remembered selection kind=%q, want all configured", got.SourceKind)
+	}
+}
+
 func TestAvailableProgrammaticSourcesUsesPrimaryLabels(t *testing.T) {
 	t.Parallel()
 
@@ -336,6 +603,392 @@ func TestAppAssetCredentialRefsGoogleWorkspaceIncludesGoogleRef(t *testing.T) {
 	}
 }
 
+func TestNormalizeCredentialSort(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		raw  string
+		want string
+	}{
+		{raw: "", want: ""},
+		{raw: "expires_asc", want: ""},
+		{raw: "RISK_DESC", want: "risk_desc"},
+		{raw: " last_used_asc ", want: "last_used_asc"},
+		{raw: "id; DROP TABLE credential_artifacts", want: ""},
+	}
+	for _, tc := range cases {
+		if got := normalizeCredentialSort(tc.raw); got != tc.want {
+			t.Fatalf("normalizeCredentialSort(%q) = %q, want %q", tc.raw, got, tc.want)
+		}
+	}
+	if got := normalizeAppAssetSort("risk_asc"); got != "" {
+		t.Fatalf("normalizeAppAssetSort(risk_asc) = %q, want default", got)
+	}
+}
+
+func TestSortCredentialsForListByRisk(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	rows := []gen.CredentialArtifact{
+		{ID: 1, DisplayName: "healthy", Status: "active", CredentialKind: "entra_certificate", CreatedByExternalID: "owner", ExpiresAtSource: timestamptz(now.Add(60 * 24 * time.Hour))},
+		{ID: 2, DisplayName: "expired", Status: "active", CredentialKind: "entra_certificate", CreatedByExternalID: "owner", ExpiresAtSource: timestamptz(now.Add(-time.Hour))},
+		{ID: 3, DisplayName: "expiring", Status: "active", CredentialKind: "entra_certificate", CreatedByExternalID: "owner", ExpiresAtSource: timestamptz(now.Add(20 * 24 * time.Hour))},
+		{ID: 4, DisplayName: "unattributed", Status: "active", CredentialKind: "entra_certificate", ExpiresAtSource: timestamptz(now.Add(90 * 24 * time.Hour))},
+	}
+
+	sortCredentialsForList(rows, "risk_desc", now, builtinCredentialRiskPolicy())
+	if got := credentialIDs(rows); !slices.Equal(got, []int64{2, 4, 3, 1}) {
+		t.Fatalf("risk_desc order = %v, want critical, high, medium, low", got)
+	}
+
+	sortCredentialsForList(rows, "risk_asc", now, builtinCredentialRiskPolicy())
+	if got := credentialIDs(rows); !slices.Equal(got, []int64{1, 3, 4, 2}) {
+		t.Fatalf("risk_asc order = %v, want low, medium, high, critical", got)
+	}
+}
+
+func TestSortCredentialsForListByLastUsed(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	rows := []gen.CredentialArtifact{
+		{ID: 1, DisplayName: "recent", LastUsedAtSource: timestamptz(now.Add(-time.Hour))},
+		{ID: 2, DisplayName: "never"},
+		{ID: 3, DisplayName: "stale", LastUsedAtSource: timestamptz(now.Add(-200 * 24 * time.Hour))},
+		{ID: 4, DisplayName: "also never"},
+	}
+
+	sortCredentialsForList(rows, "last_used_desc", now, builtinCredentialRiskPolicy())
+	if got := credentialIDs(rows); !slices.Equal(got, []int64{1, 3, 4, 2}) {
+		t.Fatalf("last_used_desc order = %v, want newest first and never-used last by name", got)
+	}
+
+	sortCredentialsForList(rows, "last_used_asc", now, builtinCredentialRiskPolicy())
+	if got := credentialIDs(rows); !slices.Equal(got, []int64{4, 2, 3, 1}) {
+		t.Fatalf("last_used_asc order = %v, want never-used first by name, then oldest", got)
+	}
+}
+
+func TestSortCredentialsForListDefaultsToExpiry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	rows := []gen.CredentialArtifact{
+		{ID: 1, DisplayName: "no expiry"},
+		{ID: 2, DisplayName: "later", ExpiresAtSource: timestamptz(now.Add(48 * time.Hour))},
+		{ID: 3, DisplayName: "sooner", ExpiresAtSource: timestamptz(now.Add(time.Hour))},
+	}
+
+	sortCredentialsForList(rows, "bogus", now, builtinCredentialRiskPolicy())
+	if got := credentialIDs(rows); !slices.Equal(got, []int64{3, 2, 1}) {
+		t.Fatalf("default order = %v, want expiry ascending with missing expiry last", got)
+	}
+}
+
+func TestSortCredentialsForListPagesAreDisjointForEverySort(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	expiry := timestamptz(now.Add(48 * time.Hour))
+	var rows []gen.CredentialArtifact
+	// Every row ties on every sort key except the source and ID tie-breakers.
+	for id := int64(9); id >= 1; id-- {
+		sourceName := "acme"
+		if id%2 == 0 {
+			sourceName = "globex"
+		}
+		rows = append(rows, gen.CredentialArtifact{ID: id, SourceKind: "github", SourceName: sourceName, DisplayName: "deploy", Status: "active", CredentialKind: "github_deploy_key", ExpiresAtSource: expiry})
+	}
+
+	for _, sortKey := range []string{"", "expires_desc", "risk_desc", "risk_asc", "last_used_desc", "last_used_asc", "name_asc", "name_desc"} {
+		sorted := slices.Clone(rows)
+		sortCredentialsForList(sorted, sortKey, now, builtinCredentialRiskPolicy())
+		if got := credentialIDs(sorted); !slices.Equal(got, []int64{1, 3, 5, 7, 9, 2, 4, 6, 8}) {
+			t.Fatalf("%q order = %v, want ties broken by source name then ID", sortKey, got)
+		}
+
+		var paged []int64
+		for offset := 0; offset < len(sorted); offset += 4 {
+			paged = append(paged, credentialIDs(paginateCredentials(sorted, offset, 4))...)
+		}
+		if !slices.Equal(paged, credentialIDs(sorted)) {
+			t.Fatalf("%q pages = %v, want each row exactly once", sortKey, paged)
+		}
+	}
+}
+
+func TestSortAppAssetsForListByUpdated(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	rows := []gen.AppAsset{
+		{ID: 1, DisplayName: "b", UpdatedAtSource: timestamptz(now.Add(-48 * time.Hour))},
+		{ID: 2, DisplayName: "a"},
+		{ID: 3, DisplayName: "c", UpdatedAtSource: timestamptz(now)},
+	}
+
+	sortAppAssetsForList(rows, "updated_desc", nil)
+	got := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		got = append(got, row.ID)
+	}
+	if !slices.Equal(got, []int64{3, 1, 2}) {
+		t.Fatalf("updated_desc order = %v, want newest first and missing last", got)
+	}
+}
+
+func TestSummarizeAppAssetCredentialsExpiredActiveIsCritical(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	refToAssetID := map[string]int64{
+		credentialSourceRefKey("github", "acme", "github_repo", "acme/api"): 1,
+		credentialSourceRefKey("github", "acme", "github_repo", "acme/web"): 2,
+	}
+	credentials := []gen.CredentialArtifact{
+		{ID: 10, SourceKind: "github", SourceName: "acme", AssetRefKind: "github_repo", AssetRefExternalID: "acme/api", CredentialKind: "github_deploy_key", Status: "active", CreatedByExternalID: "octo", ExpiresAtSource: timestamptz(now.Add(90 * 24 * time.Hour))},
+		{ID: 11, SourceKind: "github", SourceName: "acme", AssetRefKind: "github_repo", AssetRefExternalID: "acme/api", CredentialKind: "github_pat_fine_grained", Status: "active", CreatedByExternalID: "octo", ExpiresAtSource: timestamptz(now.Add(-time.Hour))},
+		{ID: 12, SourceKind: "github", SourceName: "acme", AssetRefKind: "github_repo", AssetRefExternalID: "acme/web", CredentialKind: "github_deploy_key", Status: "active", CreatedByExternalID: "octo", ExpiresAtSource: timestamptz(now.Add(90 * 24 * time.Hour))},
+	}
+
+	summaries := map[int64]appAssetCredentialSummary{}
+	summarizeAppAssetCredentials(summaries, refToAssetID, credentials, now, builtinCredentialRiskPolicy())
+
+	if got := summaries[1]; got.Count != 2 || got.RiskLevel != "critical" {
+		t.Fatalf("asset 1 summary = %+v, want 2 credentials at critical", got)
+	}
+	if got := summaries[2]; got.Count != 1 || got.RiskLevel == "critical" {
+		t.Fatalf("asset 2 summary = %+v, want 1 credential below critical", got)
+	}
+
+	rows := []gen.AppAsset{{ID: 2, DisplayName: "a"}, {ID: 3, DisplayName: "b"}, {ID: 1, DisplayName: "c"}}
+	riskRanks := map[int64]int{}
+	for assetID, summary := range summaries {
+		riskRanks[assetID] = summary.riskRank()
+	}
+	sortAppAssetsForList(rows, "risk_desc", riskRanks)
+	got := []int64{rows[0].ID, rows[1].ID, rows[2].ID}
+	if !slices.Equal(got, []int64{1, 2, 3}) {
+		t.Fatalf("risk_desc order = %v, want [1 2 3]", got)
+	}
+}
+
+func credentialIDs(rows []gen.CredentialArtifact) []int64 {
+	out := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, row.ID)
+	}
+	return out
+}
+
 func timestamptz(ts time.Time) pgtype.Timestamptz {
 	return pgtype.Timestamptz{Time: ts.UTC(), Valid: true}
 }
+
+func TestIdentityAccountFilterScopesExternalIDsBySource(t *testing.T) {
+	t.Parallel()
+
+	filter := newIdentityAccountFilter(7, "Alice", []gen.Account{
+		{SourceKind: "github", SourceName: "acme", ExternalID: "Alice-GH"},
+		{SourceKind: "github", SourceName: "acme", ExternalID: "alice-gh"},
+		{SourceKind: "entra", SourceName: "tenant-1", ExternalID: " user-1 "},
+		{SourceKind: "vault", SourceName: "prod", ExternalID: ""},
+	})
+	if !filter.Active() {
+		t.Fatalf("filter should be active for identity 7")
+	}
+
+	github := filter.ExternalIDsForSource("GitHub", "acme")
+	if len(github) != 1 || github[0] != "alice-gh" {
+		t.Fatalf("github ids = %v, want [alice-gh]", github)
+	}
+	entra := filter.ExternalIDsForSource("entra", "tenant-1")
+	if len(entra) != 1 || entra[0] != "user-1" {
+		t.Fatalf("entra ids = %v, want [user-1]", entra)
+	}
+	vault := filter.ExternalIDsForSource("vault", "prod")
+	if vault == nil || len(vault) != 0 {
+		t.Fatalf("vault ids = %#v, want empty non-nil slice", vault)
+	}
+	if other := filter.ExternalIDsForSource("github", "other-org"); len(other) != 0 {
+		t.Fatalf("ids for unrelated source = %v, want none", other)
+	}
+
+	if (identityAccountFilter{}).Active() {
+		t.Fatalf("zero filter should be inactive")
+	}
+}
+
+func TestCollectAcrossSourcesTruncatesAtLimit(t *testing.T) {
+	sources := []viewmodels.ProgrammaticSourceOption{
+		{SourceKind: "github", SourceName: "acme"},
+		{SourceKind: "entra", SourceName: "tenant-1"},
+		{SourceKind: "vault", SourceName: "prod"},
+	}
+	available := map[string][]int{
+		"github": {1, 2, 3},
+		"entra":  {4, 5, 6},
+		"vault":  {7, 8},
+	}
+	var asked []string
+	fetch := func(source viewmodels.ProgrammaticSourceOption, maxRows int) ([]int, error) {
+		asked = append(asked, source.SourceKind)
+		rows := available[source.SourceKind]
+		if len(rows) > maxRows {
+			rows = rows[:maxRows]
+		}
+		return rows, nil
+	}
+
+	rows, truncated, err := collectAcrossSources(sources, 4, fetch)
+	if err != nil {
+		t.Fatalf("collectAcrossSources() error = %v", err)
+	}
+	if !truncated {
+		t.Fatalf("truncated = false, want true")
+	}
+	if want := []int{1, 2, 3, 4}; !slices.Equal(rows, want) {
+		t.Fatalf("rows = %v, want %v", rows, want)
+	}
+	if want := []string{"github", "entra"}; !slices.Equal(asked, want) {
+		t.Fatalf("fetched sources = %v, want %v (vault should be skipped once the cap is reached)", asked, want)
+	}
+
+	asked = nil
+	rows, truncated, err = collectAcrossSources(sources, 8, fetch)
+	if err != nil {
+		t.Fatalf("collectAcrossSources() error = %v", err)
+	}
+	if truncated || len(rows) != 8 {
+		t.Fatalf("rows = %v truncated = %v, want all 8 rows untruncated", rows, truncated)
+	}
+
+	msg := multiSourceTruncatedMessage(4)
+	if !strings.Contains(msg, "4") || !strings.Contains(msg, "narrow your filters") {
+		t.Fatalf("truncated message = %q", msg)
+	}
+	if got := (&Handlers{}).multiSourceRowLimit(); got != defaultMultiSourceListRowLimit {
+		t.Fatalf("multiSourceRowLimit() = %d, want default %d", got, defaultMultiSourceListRowLimit)
+	}
+}
+
+func TestAppAssetKindFacetsMatchListTotals(t *testing.T) {
+	t.Parallel()
+
+	rows := []gen.ListAppAssetKindCountsBySourceSetRow{
+		{AssetKind: "entra_service_principal", AssetCount: 12},
+		{AssetKind: "entra_application", AssetCount: 5},
+		{AssetKind: "github_app_installation", AssetCount: 3},
+	}
+	unfilteredTotal := int64(20)
+
+	facets := appAssetKindFacets(rows, "")
+	var sum int64
+	for _, facet := range facets {
+		if facet.Selected {
+			t.Fatalf("facet %q selected without an asset kind filter", facet.Kind)
+		}
+		sum += facet.Count
+	}
+	if sum != unfilteredTotal {
+		t.Fatalf("facet counts sum to %d, want list total %d", sum, unfilteredTotal)
+	}
+
+	facets = appAssetKindFacets(rows, "entra_application")
+	var selected []viewmodels.AppAssetKindFacet
+	for _, facet := range facets {
+		if facet.Selected {
+			selected = append(selected, facet)
+		}
+	}
+	if len(selected) != 1 || selected[0].Kind != "entra_application" || selected[0].Count != 5 {
+		t.Fatalf("selected facets = %#v, want entra_application with the filtered total 5", selected)
+	}
+
+	facets = appAssetKindFacets(rows, "vault_auth_role")
+	last := facets[len(facets)-1]
+	if last.Kind != "vault_auth_role" || !last.Selected || last.Count != 0 {
+		t.Fatalf("missing selected kind facet = %#v, want vault_auth_role selected with 0", last)
+	}
+}
+
+func TestAppAssetKindCountsParamsPairsOwnerIDsWithSource(t *testing.T) {
+	t.Parallel()
+
+	sources := []viewmodels.ProgrammaticSourceOption{
+		{SourceKind: "github", SourceName: "acme"},
+		{SourceKind: "entra", SourceName: "tenant-1"},
+	}
+	owner := newIdentityAccountFilter(7, "Alice", []gen.Account{
+		{SourceKind: "entra", SourceName: "tenant-1", ExternalID: "user-1"},
+	})
+
+	params := appAssetKindCountsParams(sources, owner, parseCredentialTagFilter("automation=bots"), "deploy")
+	if !slices.Equal(params.SourceKinds, []string{"github", "entra"}) || !slices.Equal(params.SourceNames, []string{"acme", "tenant-1"}) {
+		t.Fatalf("source set = %v/%v, want github/acme and entra/tenant-1", params.SourceKinds, params.SourceNames)
+	}
+	if !params.OwnerFilter || params.Query != "deploy" {
+		t.Fatalf("owner filter/query = %v/%q, want true/deploy", params.OwnerFilter, params.Query)
+	}
+	if params.TagKey != "automation" || params.TagValue != "bots" {
+		t.Fatalf("tag filter = %q=%q, want automation=bots", params.TagKey, params.TagValue)
+	}
+	if !slices.Equal(params.OwnerSourceKinds, []string{"entra"}) || !slices.Equal(params.OwnerSourceNames, []string{"tenant-1"}) || !slices.Equal(params.OwnerExternalIds, []string{"user-1"}) {
+		t.Fatalf("owner accounts = %v/%v/%v, want entra/tenant-1/user-1", params.OwnerSourceKinds, params.OwnerSourceNames, params.OwnerExternalIds)
+	}
+
+	params = appAssetKindCountsParams(sources, identityAccountFilter{}, credentialTagFilter{}, "")
+	if params.OwnerFilter || params.OwnerExternalIds == nil || len(params.OwnerExternalIds) != 0 {
+		t.Fatalf("inactive owner params = %#v, want filter off with empty arrays", params)
+	}
+}
+
+func TestProgrammaticSourcesIncludeDisabledConnectors(t *testing.T) {
+	snap := ConnectorSnapshot{
+		GitHub:           configstore.GitHubConfig{Org: "acme-org"},
+		GitHubEnabled:    true,
+		GitHubConfigured: true,
+		Entra:            configstore.EntraConfig{TenantID: "tenant-old"},
+		EntraConfigured:  true,
+	}
+
+	// A disabled connector's stale data is hidden by default.
+	sources := availableProgrammaticSources(snap)
+	if len(sources) != 1 || sources[0].SourceKind != "github" {
+		t.Fatalf("default sources = %+v, want only github", sources)
+	}
+	if stale := staleProgrammaticSources(sources); len(stale) != 0 {
+		t.Fatalf("default stale sources = %v, want none", stale)
+	}
+
+	sources = programmaticSources(snap, true)
+	if len(sources) != 2 || sources[0].Disabled || !sources[1].Disabled || sources[1].SourceName != "tenant-old" {
+		t.Fatalf("sources with disabled = %+v, want github then disabled entra", sources)
+	}
+	if stale := staleProgrammaticSources(sources); !slices.Equal(stale, []string{"Microsoft Entra (tenant-old)"}) {
+		t.Fatalf("stale sources = %v, want the disabled entra tenant", stale)
+	}
+
+	// The toggle is remembered for the session until it is switched off.
+	sessions := scs.New()
+	sessionCtx, err := sessions.Load(context.Background(), "")
+	if err != nil {
+		t.Fatalf("sessions.Load() error = %v", err)
+	}
+	h := &Handlers{Sessions: sessions}
+	includeFor := func(target string) bool {
+		c, _ := newTestContext(http.MethodGet, target)
+		c.SetRequest(c.Request().WithContext(sessionCtx))
+		return h.includeDisabledProgrammaticSources(c)
+	}
+	if includeFor("/app-assets") {
+		t.Fatalf("first visit includes disabled connectors, want hidden")
+	}
+	if !includeFor("/app-assets?include_disabled=1") || !includeFor("/credentials") {
+		t.Fatalf("include_disabled=1 was not remembered")
+	}
+	if includeFor("/credentials?include_disabled=0") || includeFor("/app-assets") {
+		t.Fatalf("include_disabled=0 was not remembered")
+	}
+}
@@ -35,6 +35,12 @@ func (h *Handlers) HandleResourceShow(c *echo.Context) error {
 		}
 	}
 
+	if visible, err := h.sourceVisible(c, sourceKind, sourceName); err != nil {
+		return h.RenderError(c, err)
+	} else if !visible {
+		return RenderNotFound(c)
+	}
+
 	resourceKind = strings.ToLower(strings.TrimSpace(resourceKind))
 	resourceRef := resourceKind + ":" + externalID
 
@@ -100,17 +106,18 @@ func (h *Handlers) HandleResourceShow(c *echo.Context) error {
 		}
 
 		accessRows = append(accessRows, viewmodels.ResourceAccessRow{
-			IdpUserID:             idpUserID,
-			IdpUserHref:           idpUserHref,
-			IdpUserEmail:          idpUserEmail,
-			IdpUserDisplayName:    idpUserName,
-			IdpUserStatus:         idpUserStatus,
-			AppUserExternalID:     strings.TrimSpace(row.AppUserExternalID),
-			AppUserEmail:          strings.TrimSpace(row.AppUserEmail),
-			AppUserDisplayName:    strings.TrimSpace(row.AppUserDisplayName),
-			EntitlementKind:       strings.TrimSpace(row.EntitlementKind),
-			EntitlementPermission: strings.TrimSpace(row.EntitlementPermission),
-			LinkReason:            linkReason,
+			IdpUserID:                  idpUserID,
+			IdpUserHref:                idpUserHref,
+			IdpUserEmail:               idpUserEmail,
+			IdpUserDisplayName:         idpUserName,
+			IdpUserStatus:              idpUserStatus,
+			AppUserExternalID:          strings.TrimSpace(row.AppUserExternalID),
+			AppUserEmail:               strings.TrimSpace(row.AppUserEmail),
+			AppUserDisplayName:         strings.TrimSpace(row.AppUserDisplayName),
+			EntitlementKind:            strings.TrimSpace(row.EntitlementKind),
+			EntitlementPermission:      strings.TrimSpace(row.EntitlementPermission),
+			EntitlementAssignmentState: strings.TrimSpace(row.EntitlementAssignmentState),
+			LinkReason:                 linkReason,
 		})
 	}
 
@@ -147,6 +154,8 @@ func humanizeResourceKind(resourceKind string) string {
 		return "Role"
 	case accessgraph.ResourceKindAWSAccount:
 		return "AWS account"
+	case accessgraph.ResourceKindEntraDirectoryRole:
+		return "Directory role"
 	case accessgraph.ResourceKindVaultPolicy:
 		return "Vault policy"
 	case accessgraph.ResourceKindVaultGroup:
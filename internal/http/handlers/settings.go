@@ -3,11 +3,15 @@ package handlers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v5"
 	"github.com/open-sspm/open-sspm/internal/connectors/configstore"
+	connregistry "github.com/open-sspm/open-sspm/internal/connectors/registry"
 	"github.com/open-sspm/open-sspm/internal/db/gen"
 	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
 	"github.com/open-sspm/open-sspm/internal/http/views"
@@ -119,26 +123,25 @@ func (h *Handlers) handleConnectorToggle(c *echo.Context, kind string) error {
 	}
 	if enabled {
 		if err := validateConnectorConfig(kind, cfg.Config); err != nil {
-			if isHX(c) {
-				setFlashToast(c, viewmodels.ToastViewData{
-					Category:    "error",
-					Title:       ConnectorDisplayName(kind) + " not enabled",
-					Description: err.Error(),
-				})
-				setHXRedirect(c, "/settings/connectors?open="+kind)
-				return c.NoContent(http.StatusOK)
-			}
-			alert := &viewmodels.ConnectorAlert{
-				Class:   "alert-error",
-				Title:   ConnectorDisplayName(kind) + " not enabled",
-				Message: err.Error(),
-			}
-			return h.renderConnectorsPage(c, kind, "", alert)
+			return h.renderConnectorNotEnabled(c, kind, err)
+		}
+		if err := h.testConnectorConnection(ctx, kind, cfg.Config); err != nil {
+			return h.renderConnectorNotEnabled(c, kind, err)
 		}
 	}
 	if _, err := h.Q.UpdateConnectorConfigEnabled(ctx, gen.UpdateConnectorConfigEnabledParams{Kind: kind, Enabled: enabled}); err != nil {
 		return h.RenderError(c, err)
 	}
+	action := "connector.disable"
+	if enabled {
+		action = "connector.enable"
+	}
+	setAuditDetail(c, auditDetail{
+		Action: action,
+		Target: kind,
+		Before: map[string]bool{"enabled": cfg.Enabled},
+		After:  map[string]bool{"enabled": enabled},
+	})
 	if isHX(c) {
 		data, err := h.buildConnectorsViewData(ctx, c, "", "", nil)
 		if err != nil {
@@ -149,6 +152,24 @@ func (h *Handlers) handleConnectorToggle(c *echo.Context, kind string) error {
 	return c.Redirect(http.StatusSeeOther, "/settings/connectors?saved="+kind)
 }
 
+func (h *Handlers) renderConnectorNotEnabled(c *echo.Context, kind string, err error) error {
+	if isHX(c) {
+		setFlashToast(c, viewmodels.ToastViewData{
+			Category:    "error",
+			Title:       ConnectorDisplayName(kind) + " not enabled",
+			Description: err.Error(),
+		})
+		setHXRedirect(c, "/settings/connectors?open="+kind)
+		return c.NoContent(http.StatusOK)
+	}
+	alert := &viewmodels.ConnectorAlert{
+		Class:   "alert-error",
+		Title:   ConnectorDisplayName(kind) + " not enabled",
+		Message: err.Error(),
+	}
+	return h.renderConnectorsPage(c, kind, "", alert)
+}
+
 func (h *Handlers) handleConnectorSave(c *echo.Context, kind string) error {
 	ctx := c.Request().Context()
 	cfgRow, err := h.Q.GetConnectorConfig(ctx, kind)
@@ -191,6 +212,7 @@ func (h *Handlers) handleConnectorSave(c *echo.Context, kind string) error {
 			ServiceAccountJSON:  c.FormValue("service_account_json"),
 			ServiceAccountEmail: c.FormValue("service_account_email"),
 			DiscoveryEnabled:    ParseBoolForm(c.FormValue("discovery_enabled")),
+			DirectLoginEvents:   ParseBoolForm(c.FormValue("direct_login_events")),
 		}
 		merged := configstore.MergeGoogleWorkspaceConfig(current, update).Normalized()
 		if cfgRow.Enabled {
@@ -207,12 +229,18 @@ func (h *Handlers) handleConnectorSave(c *echo.Context, kind string) error {
 		if err != nil {
 			return h.RenderError(c, err)
 		}
+		workers, err := parseGitHubWorkersForm(c.FormValue("workers"))
+		if err != nil {
+			return h.renderConnectorsPage(c, kind, "", connectorAlert(err))
+		}
 		update := configstore.GitHubConfig{
-			Org:         c.FormValue("org"),
-			APIBase:     c.FormValue("api_base"),
-			Enterprise:  c.FormValue("enterprise"),
-			Token:       c.FormValue("token"),
-			SCIMEnabled: ParseBoolForm(c.FormValue("scim_enabled")),
+			Org:                   c.FormValue("org"),
+			APIBase:               c.FormValue("api_base"),
+			Enterprise:            c.FormValue("enterprise"),
+			Token:                 c.FormValue("token"),
+			SCIMEnabled:           ParseBoolForm(c.FormValue("scim_enabled")),
+			SecretScanningEnabled: ParseBoolForm(c.FormValue("secret_scanning_enabled")),
+			Workers:               workers,
 		}
 		merged := configstore.MergeGitHubConfig(current, update).Normalized()
 		if cfgRow.Enabled {
@@ -318,13 +346,41 @@ func (h *Handlers) handleConnectorSave(c *echo.Context, kind string) error {
 		if err != nil {
 			return h.RenderError(c, err)
 		}
+	case configstore.KindBitbucket:
+		current, err := configstore.DecodeBitbucketConfig(cfgRow.Config)
+		if err != nil {
+			return h.RenderError(c, err)
+		}
+		update := configstore.BitbucketConfig{
+			Workspace: c.FormValue("workspace"),
+			Username:  c.FormValue("username"),
+			Token:     c.FormValue("token"),
+			APIBase:   c.FormValue("api_base"),
+		}
+		merged := configstore.MergeBitbucketConfig(current, update).Normalized()
+		if cfgRow.Enabled {
+			if err := merged.Validate(); err != nil {
+				return h.renderConnectorsPage(c, kind, "", connectorAlert(err))
+			}
+		}
+		raw, err = configstore.EncodeConfig(merged)
+		if err != nil {
+			return h.RenderError(c, err)
+		}
 	default:
 		return RenderNotFound(c)
 	}
 
+	if cfgRow.Enabled {
+		if err := h.testConnectorConnection(ctx, kind, raw); err != nil {
+			return h.renderConnectorsPage(c, kind, "", connectorAlert(err))
+		}
+	}
 	if _, err := h.Q.UpdateConnectorConfig(ctx, gen.UpdateConnectorConfigParams{Kind: kind, Config: raw}); err != nil {
 		return h.RenderError(c, err)
 	}
+	// Connector configs hold secrets, so only the fact of the change is audited.
+	setAuditDetail(c, auditDetail{Action: "connector.configure", Target: kind})
 	return c.Redirect(http.StatusSeeOther, "/settings/connectors?saved="+kind)
 }
 
@@ -359,6 +415,11 @@ func (h *Handlers) handleConnectorAuthoritativeToggle(c *echo.Context, kind stri
 	}); err != nil {
 		return h.RenderError(c, err)
 	}
+	setAuditDetail(c, auditDetail{
+		Action: "connector.authoritative",
+		Target: kind + ":" + sourceName,
+		After:  map[string]bool{"authoritative": enabled},
+	})
 
 	if _, err := identity.Resolve(ctx, h.Q); err != nil {
 		return h.RenderError(c, err)
@@ -398,6 +459,8 @@ func (h *Handlers) renderConnectorRow(c *echo.Context, kind string, data viewmod
 		return h.RenderComponent(c, views.AWSIdentityCenterConnectorRow(data))
 	case configstore.KindVault:
 		return h.RenderComponent(c, views.VaultConnectorRow(data))
+	case configstore.KindBitbucket:
+		return h.RenderComponent(c, views.BitbucketConnectorRow(data))
 	default:
 		return RenderNotFound(c)
 	}
@@ -456,19 +519,23 @@ func (h *Handlers) buildConnectorsViewData(ctx context.Context, c *echo.Context,
 					HasServiceAccountJSON: cfg.ServiceAccountJSON != "",
 					ServiceAccountEmail:   cfg.ServiceAccountEmail,
 					DiscoveryEnabled:      cfg.DiscoveryEnabled,
+					DirectLoginEvents:     cfg.DirectLoginEvents,
 				}
 			}
 		case configstore.KindGitHub:
 			if cfg, ok := state.Config.(configstore.GitHubConfig); ok {
 				data.GitHub = viewmodels.GitHubConnectorViewData{
-					Enabled:     state.Enabled,
-					Configured:  state.Configured,
-					Org:         cfg.Org,
-					APIBase:     cfg.APIBase,
-					Enterprise:  cfg.Enterprise,
-					SCIMEnabled: cfg.SCIMEnabled,
-					TokenMasked: configstore.MaskSecret(cfg.Token),
-					HasToken:    cfg.Token != "",
+					Enabled:               state.Enabled,
+					Configured:            state.Configured,
+					Org:                   cfg.Org,
+					APIBase:               cfg.APIBase,
+					Enterprise:            cfg.Enterprise,
+					SCIMEnabled:           cfg.SCIMEnabled,
+					SecretScanningEnabled: cfg.SecretScanningEnabled,
+					Workers:               cfg.Workers,
+					MaxWorkers:            configstore.MaxGitHubWorkers,
+					TokenMasked:           configstore.MaskSecret(cfg.Token),
+					HasToken:              cfg.Token != "",
 				}
 			}
 		case configstore.KindDatadog:
@@ -538,6 +605,19 @@ func (h *Handlers) buildConnectorsViewData(ctx context.Context, c *echo.Context,
 					HasTLSCACert:        cfg.TLSCACertPEM != "",
 				}
 			}
+		case configstore.KindBitbucket:
+			if cfg, ok := state.Config.(configstore.BitbucketConfig); ok {
+				cfg = cfg.Normalized()
+				data.Bitbucket = viewmodels.BitbucketConnectorViewData{
+					Enabled:     state.Enabled,
+					Configured:  state.Configured,
+					Workspace:   cfg.Workspace,
+					Username:    cfg.Username,
+					APIBase:     cfg.APIBase,
+					TokenMasked: configstore.MaskSecret(cfg.Token),
+					HasToken:    cfg.Token != "",
+				}
+			}
 		}
 	}
 
@@ -560,6 +640,20 @@ func (h *Handlers) buildConnectorsViewData(ctx context.Context, c *echo.Context,
 	return data, nil
 }
 
+// parseGitHubWorkersForm reads the GitHub connector's worker count; blank keeps the deployment
+// default.
+func parseGitHubWorkersForm(raw string) (int, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 || n > configstore.MaxGitHubWorkers {
+		return 0, fmt.Errorf("GitHub workers must be a number from 0 to %d", configstore.MaxGitHubWorkers)
+	}
+	return n, nil
+}
+
 func connectorAlert(err error) *viewmodels.ConnectorAlert {
 	return &viewmodels.ConnectorAlert{
 		Class:   "alert-error",
@@ -568,6 +662,40 @@ func connectorAlert(err error) *viewmodels.ConnectorAlert {
 	}
 }
 
+// connectorConnectionTestTimeout bounds the provider calls made before enabling a connector.
+const connectorConnectionTestTimeout = 15 * time.Second
+
+// testConnectorConnection builds the connector's integration from raw and runs its connection
+// test, so an enabled connector with bad credentials or missing scopes is rejected up front
+// instead of failing its first sync.
+func (h *Handlers) testConnectorConnection(ctx context.Context, kind string, raw []byte) error {
+	if h.Registry == nil {
+		return nil
+	}
+	def, ok := h.Registry.Get(NormalizeConnectorKind(kind))
+	if !ok {
+		return nil
+	}
+	decoded, err := def.DecodeConfig(raw)
+	if err != nil {
+		return err
+	}
+	resolved, err := h.Registry.ResolveConfig(ctx, decoded)
+	if err != nil {
+		return err
+	}
+	integration, err := def.NewIntegration(resolved)
+	if err != nil {
+		return err
+	}
+	if integration == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, connectorConnectionTestTimeout)
+	defer cancel()
+	return connregistry.VerifyConnection(ctx, integration)
+}
+
 func validateConnectorConfig(kind string, raw []byte) error {
 	switch NormalizeConnectorKind(kind) {
 	case configstore.KindOkta:
@@ -612,6 +740,12 @@ func validateConnectorConfig(kind string, raw []byte) error {
 			return err
 		}
 		return cfg.Normalized().Validate()
+	case configstore.KindBitbucket:
+		cfg, err := configstore.DecodeBitbucketConfig(raw)
+		if err != nil {
+			return err
+		}
+		return cfg.Normalized().Validate()
 	default:
 		return errors.New("unknown connector")
 	}
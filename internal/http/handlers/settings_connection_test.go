@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	connregistry "github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+type fakeConnectionTesterIntegration struct {
+	granted []string
+}
+
+func (fakeConnectionTesterIntegration) Kind() string { return "github" }
+func (fakeConnectionTesterIntegration) Name() string { return "acme" }
+func (fakeConnectionTesterIntegration) Role() connregistry.IntegrationRole {
+	return connregistry.RoleApp
+}
+func (fakeConnectionTesterIntegration) InitEvents() []connregistry.Event { return nil }
+func (fakeConnectionTesterIntegration) Run(context.Context, *gen.Queries, *pgxpool.Pool, func(connregistry.Event), connregistry.RunMode) error {
+	return nil
+}
+func (fakeConnectionTesterIntegration) RequiredGrants() []string { return []string{"read:org", "repo"} }
+func (f fakeConnectionTesterIntegration) TestConnection(context.Context) (connregistry.ConnectorGrants, bool, error) {
+	return connregistry.EvaluateGrants(f.granted, f.RequiredGrants(), nil), true, nil
+}
+
+type fakeConnectionTestDefinition struct {
+	fakeStatsDefinition
+	integration connregistry.Integration
+}
+
+func (f fakeConnectionTestDefinition) NewIntegration(any) (connregistry.Integration, error) {
+	return f.integration, nil
+}
+
+func TestTestConnectorConnection(t *testing.T) {
+	t.Parallel()
+
+	handlerFor := func(granted []string) *Handlers {
+		reg := connregistry.NewRegistry()
+		if err := reg.Register(fakeConnectionTestDefinition{
+			fakeStatsDefinition: fakeStatsDefinition{kind: "github", displayName: "GitHub"},
+			integration:         fakeConnectionTesterIntegration{granted: granted},
+		}); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+		return &Handlers{Registry: reg}
+	}
+
+	err := handlerFor([]string{"read:org"}).testConnectorConnection(context.Background(), "github", []byte(`{}`))
+	var missing *connregistry.MissingGrantsError
+	if !errors.As(err, &missing) || !slices.Equal(missing.Missing, []string{"repo"}) {
+		t.Fatalf("testConnectorConnection() error = %v, want missing [repo]", err)
+	}
+	if err.Error() != "credentials are missing required permissions: repo" {
+		t.Fatalf("error message = %q", err.Error())
+	}
+
+	if err := handlerFor([]string{"read:org", "repo"}).testConnectorConnection(context.Background(), "github", []byte(`{}`)); err != nil {
+		t.Fatalf("testConnectorConnection() with sufficient scopes error = %v", err)
+	}
+
+	// Connectors whose integration cannot be tested are enabled as before.
+	if err := handlerFor(nil).testConnectorConnection(context.Background(), "okta", []byte(`{}`)); err != nil {
+		t.Fatalf("testConnectorConnection() for unregistered kind error = %v", err)
+	}
+}
@@ -33,12 +33,9 @@ func (h *Handlers) HandleConnectorHealth(c *echo.Context) error {
 		return h.RenderError(c, err)
 	}
 
-	var states []connregistry.ConnectorState
-	if h.Registry != nil {
-		states, err = h.Registry.LoadStates(ctx, h.Q)
-		if err != nil {
-			return h.RenderError(c, err)
-		}
+	states, err := h.loadScopedConnectorStates(c)
+	if err != nil {
+		return h.RenderError(c, err)
 	}
 
 	data, err := buildConnectorHealthViewData(h.Cfg, h.Q, ctx, states, h.Syncer != nil)
@@ -90,7 +87,7 @@ func (h *Handlers) HandleConnectorHealthSync(c *echo.Context) error {
 		})
 	}
 
-	states, err := h.Registry.LoadStates(c.Request().Context(), h.Q)
+	states, err := h.loadScopedConnectorStates(c)
 	if err != nil {
 		return h.RenderError(c, err)
 	}
@@ -283,6 +280,15 @@ func buildConnectorHealthViewData(cfg config.Config, q *gen.Queries, ctx context
 		}
 	}
 
+	grantRows, err := q.ListConnectorGrants(ctx)
+	if err != nil {
+		return viewmodels.ConnectorHealthViewData{}, err
+	}
+	grantsByKey := make(map[syncRollupKey]gen.ConnectorGrant, len(grantRows))
+	for _, row := range grantRows {
+		grantsByKey[syncRollupKey{kind: row.SourceKind, name: row.SourceName}] = row
+	}
+
 	items := make([]viewmodels.ConnectorHealthItem, 0, len(states))
 	var (
 		enabledTotal        int
@@ -322,7 +328,7 @@ func buildConnectorHealthViewData(cfg config.Config, q *gen.Queries, ctx context
 			rollup:           rollup,
 		})
 
-		items = append(items, viewmodels.ConnectorHealthItem{
+		item := viewmodels.ConnectorHealthItem{
 			Kind:             kind,
 			Name:             displayName,
 			SourceKind:       syncKind,
@@ -336,7 +342,13 @@ func buildConnectorHealthViewData(cfg config.Config, q *gen.Queries, ctx context
 			DetailsURL:       detailsURL,
 			CanViewDetails:   canViewDetails,
 			CanTriggerSync:   canTriggerSync && syncable && st.Configured && st.Enabled && sourceName != "" && IsKnownConnectorKind(kind),
-		})
+		}
+		if canViewDetails {
+			if grant, ok := grantsByKey[syncRollupKey{kind: syncKind, name: sourceName}]; ok {
+				applyConnectorGrant(&item, grant)
+			}
+		}
+		items = append(items, item)
 
 		if res.countsAsEnabled {
 			enabledTotal++
@@ -379,6 +391,24 @@ func buildConnectorHealthViewData(cfg config.Config, q *gen.Queries, ctx context
 	return data, nil
 }
 
+// applyConnectorGrant summarizes the stored grants: missing grants are listed so operators can see
+// why a sync is partial, and the granted set goes into the title.
+func applyConnectorGrant(item *viewmodels.ConnectorHealthItem, grant gen.ConnectorGrant) {
+	item.MissingGrants = append([]string(nil), grant.Missing...)
+	if len(grant.Granted) > 0 {
+		item.GrantsTitle = "Granted: " + strings.Join(grant.Granted, ", ")
+	} else {
+		item.GrantsTitle = "No grants reported"
+	}
+	if len(item.MissingGrants) > 0 {
+		item.GrantsLabel = fmt.Sprintf("%d missing", len(item.MissingGrants))
+		item.GrantsClass = badgeClassWarning()
+		return
+	}
+	item.GrantsLabel = "Complete"
+	item.GrantsClass = badgeClassSuccess()
+}
+
 func connectorSyncKind(connectorKind string) string {
 	connectorKind = strings.ToLower(strings.TrimSpace(connectorKind))
 	switch connectorKind {
@@ -431,6 +461,9 @@ func syncRunRollupFromRow(row gen.GetSyncRunRollupsForSourcesRow) syncRunRollup
 	if row.LastRunErrorKind.Valid {
 		rollup.lastRunErrorKind = strings.TrimSpace(row.LastRunErrorKind.String)
 	}
+	if row.LastRunWarningCount.Valid {
+		rollup.lastRunWarningCount = int64(row.LastRunWarningCount.Int32)
+	}
 	if row.LastRunFinishedAt.Valid {
 		t := row.LastRunFinishedAt.Time
 		rollup.lastRunFinishedAt = &t
@@ -4,6 +4,9 @@ import (
 	"strings"
 	"testing"
 	"unicode/utf8"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
 )
 
 func TestSizeConnectorHealthErrorMessage(t *testing.T) {
@@ -90,3 +93,31 @@ func TestConnectorHealthErrorDetailsURL(t *testing.T) {
 		t.Fatalf("url missing connector_name: %q", url)
 	}
 }
+
+func TestApplyConnectorGrant(t *testing.T) {
+	var missing viewmodels.ConnectorHealthItem
+	applyConnectorGrant(&missing, gen.ConnectorGrant{
+		Granted:  []string{"read:org", "repo"},
+		Required: []string{"read:org", "repo", "read:audit_log"},
+		Missing:  []string{"read:audit_log"},
+	})
+	if missing.GrantsLabel != "1 missing" || missing.GrantsClass != badgeClassWarning() {
+		t.Fatalf("missing grants label/class = %q %q", missing.GrantsLabel, missing.GrantsClass)
+	}
+	if len(missing.MissingGrants) != 1 || missing.MissingGrants[0] != "read:audit_log" {
+		t.Fatalf("missing grants = %v", missing.MissingGrants)
+	}
+	if missing.GrantsTitle != "Granted: read:org, repo" {
+		t.Fatalf("grants title = %q", missing.GrantsTitle)
+	}
+
+	var complete viewmodels.ConnectorHealthItem
+	applyConnectorGrant(&complete, gen.ConnectorGrant{
+		Granted:  []string{"admin:org", "repo", "read:audit_log"},
+		Required: []string{"read:org", "repo", "read:audit_log"},
+		Missing:  []string{},
+	})
+	if complete.GrantsLabel != "Complete" || complete.GrantsClass != badgeClassSuccess() || len(complete.MissingGrants) != 0 {
+		t.Fatalf("complete grants = %+v", complete)
+	}
+}
@@ -74,14 +74,14 @@ func (h *Handlers) HandleSettingsUsersCreate(c *echo.Context) error {
 	}
 
 	switch form.Role {
-	case auth.RoleAdmin, auth.RoleViewer:
+	case auth.RoleAdmin, auth.RoleAnalyst, auth.RoleViewer:
 	default:
 		return h.renderSettingsUsersPage(c, settingsUsersPageOptions{
 			openAdd: true,
 			addForm: form,
 			alert: &viewmodels.SettingsUsersAlert{
 				Title:       "Invalid group",
-				Message:     "Group must be admin or viewer.",
+				Message:     "Group must be admin, analyst, or viewer.",
 				Destructive: true,
 			},
 		})
@@ -147,6 +147,7 @@ func (h *Handlers) HandleSettingsUsersCreate(c *echo.Context) error {
 		PasswordHash: hash,
 		Role:         form.Role,
 		IsActive:     true,
+		OrgID:        principalOrgID(c),
 	})
 	if err != nil {
 		if isUniqueViolation(err) {
@@ -203,7 +204,7 @@ func (h *Handlers) HandleSettingsUserUpdate(c *echo.Context) error {
 
 	if role != "" {
 		switch role {
-		case auth.RoleAdmin, auth.RoleViewer:
+		case auth.RoleAdmin, auth.RoleAnalyst, auth.RoleViewer:
 		default:
 			return h.renderSettingsUsersPage(c, settingsUsersPageOptions{
 				openEdit:   true,
@@ -211,7 +212,7 @@ func (h *Handlers) HandleSettingsUserUpdate(c *echo.Context) error {
 				editRole:   role,
 				alert: &viewmodels.SettingsUsersAlert{
 					Title:       "Invalid group",
-					Message:     "Group must be admin or viewer.",
+					Message:     "Group must be admin, analyst, or viewer.",
 					Destructive: true,
 				},
 			})
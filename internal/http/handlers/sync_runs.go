@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/connectors/httpclient"
+	connregistry "github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
+	"github.com/open-sspm/open-sspm/internal/http/views"
+)
+
+const (
+	syncRunOutcomeWarning = "warning"
+	syncRunOutcomeRunning = "running"
+
+	// syncRunListCounts is how many stats counts a list row shows before "+N more".
+	syncRunListCounts   = 3
+	syncRunMessageRunes = 240
+)
+
+var syncRunOutcomes = []struct{ value, label string }{
+	{"", "Any outcome"},
+	{"success", "Success"},
+	{syncRunOutcomeWarning, "Success with warnings"},
+	{connregistry.SyncStatusError, "Error"},
+	{connregistry.SyncStatusCanceled, "Canceled"},
+	{syncRunOutcomeRunning, "Running"},
+}
+
+type syncRunQueries interface {
+	CountSyncRunsByFilters(ctx context.Context, arg gen.CountSyncRunsByFiltersParams) (int64, error)
+	ListSyncRunsPageByFilters(ctx context.Context, arg gen.ListSyncRunsPageByFiltersParams) ([]gen.SyncRun, error)
+	ListSyncRunSources(ctx context.Context) ([]gen.ListSyncRunSourcesRow, error)
+}
+
+// syncRunStats is the stats JSON connectors store on a successful run.
+type syncRunStats struct {
+	Counts map[string]int64 `json:"counts"`
+}
+
+// HandleSyncRuns lists sync runs across connectors, newest first. ?source_kind and ?source_name
+// narrow the list to one source (discovery runs have their own source kind) and ?outcome to one
+// run status, or to successful runs with warnings. Only runs of sources the principal's org owns
+// are listed.
+func (h *Handlers) HandleSyncRuns(c *echo.Context) error {
+	ctx := c.Request().Context()
+	layout, _, err := h.LayoutData(ctx, c, "Sync runs")
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	data, err := buildSyncRunsViewData(ctx, h.Q, scope, h.displayLocation(c),
+		strings.TrimSpace(c.QueryParam("source_kind")),
+		strings.TrimSpace(c.QueryParam("source_name")),
+		normalizeSyncRunOutcome(c.QueryParam("outcome")),
+		parsePageParam(c), parsePerPageParam(c))
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	data.Layout = layout
+	data.Retention = h.Cfg.SyncRunRetention
+	return h.RenderComponent(c, views.SettingsSyncRunsPage(data))
+}
+
+// HandleSyncRunShow renders one sync run with its counts, warnings, and failure message.
+func (h *Handlers) HandleSyncRunShow(c *echo.Context) error {
+	id, err := strconv.ParseInt(strings.TrimSpace(c.Param("id")), 10, 64)
+	if err != nil || id <= 0 {
+		return RenderNotFound(c)
+	}
+	ctx := c.Request().Context()
+	run, err := h.Q.GetSyncRun(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return RenderNotFound(c)
+		}
+		return h.RenderError(c, err)
+	}
+	if visible, err := h.sourceVisible(c, syncRunConnectorKind(run.SourceKind), run.SourceName); err != nil {
+		return h.RenderError(c, err)
+	} else if !visible {
+		return RenderNotFound(c)
+	}
+	layout, _, err := h.LayoutData(ctx, c, "Sync runs")
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
+	loc := h.displayLocation(c)
+	item, stats := syncRunListItem(run, loc, time.Now())
+	item.Message = strings.TrimSpace(run.Message)
+	data := viewmodels.SyncRunShowViewData{
+		Layout: layout,
+		Run:    item,
+		Counts: syncRunCounts(stats.Counts),
+	}
+	if item.APIRequests > 0 {
+		data.APICalls = syncRunAPICalls(run.ApiStats)
+	}
+	for _, warning := range syncRunWarnings(run.Warnings) {
+		data.Warnings = append(data.Warnings, viewmodels.SyncRunWarningItem{
+			Source:  warning.Source,
+			Stage:   warning.Stage,
+			Message: warning.Message,
+			At:      inDisplayLocation(warning.At, loc).Format("2006-01-02 15:04:05 MST"),
+		})
+	}
+	return h.RenderComponent(c, views.SettingsSyncRunShowPage(data))
+}
+
+func buildSyncRunsViewData(ctx context.Context, q syncRunQueries, scope orgScope, loc *time.Location, sourceKind, sourceName, outcome string, page, perPage int) (viewmodels.SyncRunsViewData, error) {
+	allSources, err := q.ListSyncRunSources(ctx)
+	if err != nil {
+		return viewmodels.SyncRunsViewData{}, err
+	}
+	sources := make([]gen.ListSyncRunSourcesRow, 0, len(allSources))
+	visibleKinds := make([]string, 0, len(allSources))
+	visibleNames := make([]string, 0, len(allSources))
+	for _, source := range allSources {
+		if !scope.AllowsSource(syncRunConnectorKind(source.SourceKind), source.SourceName) {
+			continue
+		}
+		sources = append(sources, source)
+		visibleKinds = append(visibleKinds, source.SourceKind)
+		visibleNames = append(visibleNames, source.SourceName)
+	}
+
+	total, err := q.CountSyncRunsByFilters(ctx, gen.CountSyncRunsByFiltersParams{
+		SourceKind:         sourceKind,
+		SourceName:         sourceName,
+		Outcome:            outcome,
+		VisibleSourceKinds: visibleKinds,
+		VisibleSourceNames: visibleNames,
+	})
+	if err != nil {
+		return viewmodels.SyncRunsViewData{}, err
+	}
+	page, totalPages, offset := paginate(total, page, perPage)
+	runs, err := q.ListSyncRunsPageByFilters(ctx, gen.ListSyncRunsPageByFiltersParams{
+		SourceKind:         sourceKind,
+		SourceName:         sourceName,
+		Outcome:            outcome,
+		VisibleSourceKinds: visibleKinds,
+		VisibleSourceNames: visibleNames,
+		PageLimit:          int32(perPage),
+		PageOffset:         int32(offset),
+	})
+	if err != nil {
+		return viewmodels.SyncRunsViewData{}, err
+	}
+
+	data := viewmodels.SyncRunsViewData{
+		SourceKind: sourceKind,
+		SourceName: sourceName,
+		Outcome:    outcome,
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: totalPages,
+		TotalCount: total,
+		HasItems:   len(runs) > 0,
+	}
+	data.ShowingFrom, data.ShowingTo = showingRange(total, offset, len(runs))
+
+	data.Sources = append(data.Sources, viewmodels.SyncRunFilterOption{
+		Label:    "All sources",
+		Href:     views.SyncRunsURL("", "", outcome, 1),
+		Selected: sourceKind == "" && sourceName == "",
+	})
+	for _, source := range sources {
+		label := sourceDiagnosticLabel(syncRunConnectorKind(source.SourceKind), source.SourceName)
+		if syncRunMode(source.SourceKind) == connregistry.RunModeDiscovery {
+			label += " · discovery"
+		}
+		data.Sources = append(data.Sources, viewmodels.SyncRunFilterOption{
+			Label:    label,
+			Href:     views.SyncRunsURL(source.SourceKind, source.SourceName, outcome, 1),
+			Selected: source.SourceKind == sourceKind && source.SourceName == sourceName,
+		})
+	}
+	for _, option := range syncRunOutcomes {
+		data.Outcomes = append(data.Outcomes, viewmodels.SyncRunFilterOption{
+			Label:    option.label,
+			Href:     views.SyncRunsURL(sourceKind, sourceName, option.value, 1),
+			Selected: option.value == outcome,
+		})
+	}
+
+	now := time.Now()
+	for _, run := range runs {
+		item, _ := syncRunListItem(run, loc, now)
+		data.Items = append(data.Items, item)
+	}
+	return data, nil
+}
+
+func syncRunListItem(run gen.SyncRun, loc *time.Location, now time.Time) (viewmodels.SyncRunListItem, syncRunStats) {
+	var stats syncRunStats
+	if len(run.Stats) > 0 {
+		_ = json.Unmarshal(run.Stats, &stats)
+	}
+	warnings := syncRunWarnings(run.Warnings)
+
+	item := viewmodels.SyncRunListItem{
+		ID:           run.ID,
+		Href:         "/settings/sync-runs/" + strconv.FormatInt(run.ID, 10),
+		SourceLabel:  sourceDiagnosticLabel(syncRunConnectorKind(run.SourceKind), run.SourceName),
+		Mode:         string(syncRunMode(run.SourceKind)),
+		Status:       strings.TrimSpace(run.Status),
+		ErrorKind:    strings.TrimSpace(run.ErrorKind),
+		StartedAt:    formatProgrammaticTime(run.StartedAt, loc),
+		FinishedAt:   formatProgrammaticTime(run.FinishedAt, loc),
+		WarningCount: len(warnings),
+		APIRequests:  run.ApiRequestCount,
+	}
+	item.StatusClass = syncRunStatusClass(item.Status, item.WarningCount)
+	if message, truncated := truncateRunes(strings.TrimSpace(run.Message), syncRunMessageRunes); truncated {
+		item.Message = message + "…"
+	} else {
+		item.Message = message
+	}
+
+	switch {
+	case run.StartedAt.Valid && run.FinishedAt.Valid:
+		item.Duration = formatDuration(run.FinishedAt.Time.Sub(run.StartedAt.Time))
+	case run.StartedAt.Valid && item.Status == syncRunOutcomeRunning:
+		item.Duration = formatDuration(now.Sub(run.StartedAt.Time)) + " so far"
+	default:
+		item.Duration = "—"
+	}
+
+	counts := syncRunCounts(stats.Counts)
+	if len(counts) > syncRunListCounts {
+		item.MoreCounts = len(counts) - syncRunListCounts
+		counts = counts[:syncRunListCounts]
+	}
+	item.Counts = counts
+	return item, stats
+}
+
+func syncRunCounts(counts map[string]int64) []viewmodels.SyncRunCount {
+	out := make([]viewmodels.SyncRunCount, 0, len(counts))
+	for name, value := range counts {
+		out = append(out, viewmodels.SyncRunCount{Name: name, Value: value})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// syncRunAPICalls decodes a run's API request breakdown. Runs recorded before the breakdown
+// existed only have a total and yield zero bytes and no hosts.
+func syncRunAPICalls(raw []byte) viewmodels.SyncRunAPICalls {
+	var stats httpclient.RequestStats
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &stats)
+	}
+	calls := viewmodels.SyncRunAPICalls{
+		BytesSent:     formatByteSize(stats.BytesSent),
+		BytesReceived: formatByteSize(stats.BytesReceived),
+		Hosts:         syncRunCounts(stats.Hosts),
+		Errors:        syncRunCounts(stats.Errors),
+	}
+	sort.SliceStable(calls.Hosts, func(i, j int) bool { return calls.Hosts[i].Value > calls.Hosts[j].Value })
+	return calls
+}
+
+// formatByteSize renders n bytes in binary units, e.g. "1.5 MiB".
+func formatByteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func syncRunWarnings(raw []byte) []connregistry.SyncWarning {
+	if len(raw) == 0 {
+		return nil
+	}
+	var warnings []connregistry.SyncWarning
+	if err := json.Unmarshal(raw, &warnings); err != nil {
+		return nil
+	}
+	return warnings
+}
+
+func syncRunStatusClass(status string, warningCount int) string {
+	switch status {
+	case "success":
+		if warningCount > 0 {
+			return badgeClassWarning()
+		}
+		return badgeClassSuccess()
+	case connregistry.SyncStatusError:
+		return badgeClassDanger()
+	default:
+		return badgeClassNeutral()
+	}
+}
+
+// syncRunMode reports the run mode encoded in a sync run's source kind.
+func syncRunMode(sourceKind string) connregistry.RunMode {
+	if strings.HasSuffix(sourceKind, "_"+string(connregistry.RunModeDiscovery)) {
+		return connregistry.RunModeDiscovery
+	}
+	return connregistry.RunModeFull
+}
+
+// syncRunConnectorKind strips the run mode from a sync run's source kind.
+func syncRunConnectorKind(sourceKind string) string {
+	return strings.TrimSuffix(sourceKind, "_"+string(connregistry.RunModeDiscovery))
+}
+
+func normalizeSyncRunOutcome(raw string) string {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	for _, option := range syncRunOutcomes {
+		if option.value == raw {
+			return raw
+		}
+	}
+	return ""
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// syncRunQueriesStub filters and pages runs the way the sync run queries do, newest first.
+type syncRunQueriesStub struct {
+	runs []gen.SyncRun
+	args []gen.ListSyncRunsPageByFiltersParams
+}
+
+func (s *syncRunQueriesStub) match(sourceKind, sourceName, outcome string, visibleKinds, visibleNames []string) []gen.SyncRun {
+	var out []gen.SyncRun
+	for _, run := range s.runs {
+		if sourceKind != "" && run.SourceKind != sourceKind || sourceName != "" && run.SourceName != sourceName {
+			continue
+		}
+		visible := false
+		for i := range visibleKinds {
+			visible = visible || visibleKinds[i] == run.SourceKind && visibleNames[i] == run.SourceName
+		}
+		if !visible {
+			continue
+		}
+		switch outcome {
+		case "":
+		case syncRunOutcomeWarning:
+			if run.Status != "success" || len(syncRunWarnings(run.Warnings)) == 0 {
+				continue
+			}
+		default:
+			if run.Status != outcome {
+				continue
+			}
+		}
+		out = append(out, run)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if !out[i].StartedAt.Time.Equal(out[j].StartedAt.Time) {
+			return out[i].StartedAt.Time.After(out[j].StartedAt.Time)
+		}
+		return out[i].ID > out[j].ID
+	})
+	return out
+}
+
+func (s *syncRunQueriesStub) CountSyncRunsByFilters(_ context.Context, arg gen.CountSyncRunsByFiltersParams) (int64, error) {
+	return int64(len(s.match(arg.SourceKind, arg.SourceName, arg.Outcome, arg.VisibleSourceKinds, arg.VisibleSourceNames))), nil
+}
+
+func (s *syncRunQueriesStub) ListSyncRunsPageByFilters(_ context.Context, arg gen.ListSyncRunsPageByFiltersParams) ([]gen.SyncRun, error) {
+	s.args = append(s.args, arg)
+	rows := s.match(arg.SourceKind, arg.SourceName, arg.Outcome, arg.VisibleSourceKinds, arg.VisibleSourceNames)
+	offset, limit := int(arg.PageOffset), int(arg.PageLimit)
+	return rows[min(offset, len(rows)):min(offset+limit, len(rows))], nil
+}
+
+func (s *syncRunQueriesStub) ListSyncRunSources(context.Context) ([]gen.ListSyncRunSourcesRow, error) {
+	var out []gen.ListSyncRunSourcesRow
+	for _, run := range s.runs {
+		row := gen.ListSyncRunSourcesRow{SourceKind: run.SourceKind, SourceName: run.SourceName}
+		if !slices.Contains(out, row) {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func TestBuildSyncRunsViewDataNewestFirst(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	run := func(id int64, kind, status string, startedAgo time.Duration, warnings string) gen.SyncRun {
+		started := base.Add(-startedAgo)
+		r := gen.SyncRun{
+			ID:         id,
+			SourceKind: kind,
+			SourceName: "acme",
+			Status:     status,
+			StartedAt:  pgtype.Timestamptz{Time: started, Valid: true},
+			Warnings:   []byte(warnings),
+		}
+		if status != syncRunOutcomeRunning {
+			r.FinishedAt = pgtype.Timestamptz{Time: started.Add(90 * time.Second), Valid: true}
+		}
+		return r
+	}
+	stub := &syncRunQueriesStub{runs: []gen.SyncRun{
+		run(1, "github", "success", 72*time.Hour, "[]"),
+		run(4, "github", "error", 2*time.Hour, "[]"),
+		run(2, "github", "success", 48*time.Hour, `[{"source":"github","stage":"list-teams","message":"rate limited"}]`),
+		run(5, "github", syncRunOutcomeRunning, 0, "[]"),
+		run(3, "okta_discovery", "success", 24*time.Hour, "[]"),
+	}}
+
+	data, err := buildSyncRunsViewData(context.Background(), stub, orgScope{}, time.UTC, "", "", "", 1, 10)
+	if err != nil {
+		t.Fatalf("buildSyncRunsViewData() error = %v", err)
+	}
+	var ids []int64
+	for _, item := range data.Items {
+		ids = append(ids, item.ID)
+	}
+	if want := []int64{5, 4, 3, 2, 1}; !slices.Equal(ids, want) {
+		t.Fatalf("ids = %v, want newest first %v", ids, want)
+	}
+	if data.Items[2].Mode != "discovery" || data.Items[0].Mode != "full" {
+		t.Fatalf("modes = %q/%q, want discovery for okta_discovery and full otherwise", data.Items[2].Mode, data.Items[0].Mode)
+	}
+	if data.Items[1].Duration != "1m 30s" {
+		t.Fatalf("duration = %q, want 1m 30s", data.Items[1].Duration)
+	}
+
+	data, err = buildSyncRunsViewData(context.Background(), stub, orgScope{}, time.UTC, "github", "acme", syncRunOutcomeWarning, 1, 10)
+	if err != nil {
+		t.Fatalf("buildSyncRunsViewData() error = %v", err)
+	}
+	if len(data.Items) != 1 || data.Items[0].ID != 2 || data.Items[0].WarningCount != 1 {
+		t.Fatalf("warning outcome items = %+v, want only run 2 with one warning", data.Items)
+	}
+	if data.Items[0].StatusClass != badgeClassWarning() {
+		t.Fatalf("status class = %q, want the warning badge", data.Items[0].StatusClass)
+	}
+
+	data, err = buildSyncRunsViewData(context.Background(), stub, orgScope{}, time.UTC, "", "", "", 2, 2)
+	if err != nil {
+		t.Fatalf("buildSyncRunsViewData() error = %v", err)
+	}
+	if len(data.Items) != 2 || data.Items[0].ID != 3 || data.Items[1].ID != 2 {
+		t.Fatalf("page 2 items = %+v, want runs 3 and 2", data.Items)
+	}
+	if last := stub.args[len(stub.args)-1]; last.PageOffset != 2 || last.PageLimit != 2 {
+		t.Fatalf("page args = %+v, want offset 2 limit 2", last)
+	}
+}
+
+func TestSyncRunAPICallsBusiestHostFirst(t *testing.T) {
+	t.Parallel()
+
+	calls := syncRunAPICalls([]byte(`{"requests":9900,"bytes_sent":512,"bytes_received":3670016,"hosts":{"oauth2.googleapis.com":100,"admin.googleapis.com":9800},"errors":{"throttled":12}}`))
+	if len(calls.Hosts) != 2 || calls.Hosts[0].Name != "admin.googleapis.com" || calls.Hosts[0].Value != 9800 {
+		t.Fatalf("Hosts = %+v, want admin.googleapis.com first", calls.Hosts)
+	}
+	if calls.BytesSent != "512 B" || calls.BytesReceived != "3.5 MiB" {
+		t.Fatalf("bytes = %q/%q, want 512 B/3.5 MiB", calls.BytesSent, calls.BytesReceived)
+	}
+	if len(calls.Errors) != 1 || calls.Errors[0].Name != "throttled" || calls.Errors[0].Value != 12 {
+		t.Fatalf("Errors = %+v, want 12 throttled", calls.Errors)
+	}
+
+	// Runs recorded before the breakdown existed only have the total.
+	if legacy := syncRunAPICalls([]byte(`{}`)); len(legacy.Hosts) != 0 || legacy.BytesReceived != "0 B" {
+		t.Fatalf("legacy = %+v, want no hosts and 0 B", legacy)
+	}
+}
+
+func TestBuildSyncRunsViewDataListsOnlyOwnOrgsRuns(t *testing.T) {
+	t.Parallel()
+
+	stub := &syncRunQueriesStub{runs: []gen.SyncRun{
+		{ID: 1, SourceKind: "github", SourceName: "acme", Status: "success"},
+		{ID: 2, SourceKind: "github_discovery", SourceName: "globex", Status: "success"},
+		{ID: 3, SourceKind: "github", SourceName: "globex", Status: "error"},
+	}}
+	scope, err := loadOrgScope(context.Background(), fakeOrgScopeQueries{sources: []gen.OrgSource{
+		{SourceKind: "github", SourceName: "acme", OrgID: 2},
+		{SourceKind: "github", SourceName: "globex", OrgID: 3},
+	}}, 2)
+	if err != nil {
+		t.Fatalf("loadOrgScope() error = %v", err)
+	}
+
+	for _, filter := range []struct{ sourceKind, sourceName string }{{}, {"github", "globex"}} {
+		data, err := buildSyncRunsViewData(context.Background(), stub, scope, time.UTC, filter.sourceKind, filter.sourceName, "", 1, 10)
+		if err != nil {
+			t.Fatalf("buildSyncRunsViewData() error = %v", err)
+		}
+		if filter.sourceName == "" && (len(data.Items) != 1 || data.Items[0].ID != 1 || data.TotalCount != 1) {
+			t.Fatalf("items = %+v total = %d, want only org 2's acme run", data.Items, data.TotalCount)
+		}
+		if filter.sourceName != "" && (len(data.Items) != 0 || data.TotalCount != 0) {
+			t.Fatalf("filtered items = %+v, want none of org 3's runs", data.Items)
+		}
+		for _, source := range data.Sources {
+			if strings.Contains(source.Label, "globex") {
+				t.Fatalf("source filter %+v lists another org's source", source)
+			}
+		}
+	}
+}
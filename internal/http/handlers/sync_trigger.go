@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v5"
+	connregistry "github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/sync"
+)
+
+const (
+	syncTriggerStatusStarted        = "started"
+	syncTriggerStatusQueued         = "queued"
+	syncTriggerStatusAlreadyRunning = "already_running"
+)
+
+// syncTriggerResponse is the JSON body returned by HandlePostSync.
+// RunID is zero when the run has been queued but not yet picked up by a worker;
+// clients poll connector health until it appears.
+type syncTriggerResponse struct {
+	Status     string `json:"status,omitempty"`
+	Message    string `json:"message"`
+	SourceKind string `json:"source_kind,omitempty"`
+	SourceName string `json:"source_name,omitempty"`
+	Mode       string `json:"mode,omitempty"`
+	RunID      int64  `json:"run_id,omitempty"`
+}
+
+type syncTriggerRunQueries interface {
+	GetLatestRunningSyncRunIDBySource(context.Context, gen.GetLatestRunningSyncRunIDBySourceParams) (int64, error)
+	GetLatestSyncRunIDStartedSinceBySource(context.Context, gen.GetLatestSyncRunIDStartedSinceBySourceParams) (int64, error)
+}
+
+// HandlePostSync triggers a full or discovery sync for a single connector source.
+func (h *Handlers) HandlePostSync(c *echo.Context) error {
+	if c.Request().Method != http.MethodPost {
+		return c.NoContent(http.StatusMethodNotAllowed)
+	}
+
+	connectorKind := NormalizeConnectorKind(c.FormValue("source_kind"))
+	sourceName := strings.TrimSpace(c.FormValue("source_name"))
+	if !IsKnownConnectorKind(connectorKind) || sourceName == "" {
+		return c.JSON(http.StatusBadRequest, syncTriggerResponse{Message: "source_kind and source_name are required."})
+	}
+	mode, ok := connregistry.LookupRunMode(c.FormValue("mode"))
+	if !ok {
+		return c.JSON(http.StatusBadRequest, syncTriggerResponse{Message: "mode must be full or discovery."})
+	}
+	if h.Syncer == nil {
+		return c.JSON(http.StatusServiceUnavailable, syncTriggerResponse{Message: "Manual sync is not configured on this server."})
+	}
+	if h.Registry == nil || h.Q == nil {
+		return c.JSON(http.StatusServiceUnavailable, syncTriggerResponse{Message: "Connector state could not be loaded."})
+	}
+
+	ctx := c.Request().Context()
+	states, err := h.loadScopedConnectorStates(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
+	var selected *connregistry.ConnectorState
+	for idx := range states {
+		state := &states[idx]
+		if NormalizeConnectorKind(state.Definition.Kind()) == connectorKind && strings.EqualFold(strings.TrimSpace(state.SourceName), sourceName) {
+			selected = state
+			break
+		}
+	}
+	if selected == nil {
+		return c.JSON(http.StatusNotFound, syncTriggerResponse{Message: "The selected connector source could not be resolved."})
+	}
+	if !selected.Configured || !selected.Enabled {
+		return c.JSON(http.StatusConflict, syncTriggerResponse{Message: "Enable and configure the connector before triggering sync."})
+	}
+
+	cfg, err := h.Registry.ResolveConfig(ctx, selected.Config)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	integration, err := selected.Definition.NewIntegration(cfg)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
+	status, resp, err := triggerConnectorSync(ctx, h.Syncer, h.Q, integration, mode)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	return c.JSON(status, resp)
+}
+
+// triggerConnectorSync validates that integration supports mode and asks syncer to run it.
+// The syncer's run-once lock for mode is the overlap guard; when it reports a run already in flight,
+// the running run's id is returned with a 200 so repeated triggers are safe.
+func triggerConnectorSync(ctx context.Context, syncer SyncRunner, runs syncTriggerRunQueries, integration connregistry.Integration, mode connregistry.RunMode) (int, syncTriggerResponse, error) {
+	if integration == nil {
+		return http.StatusUnprocessableEntity, syncTriggerResponse{Message: "The selected connector does not support sync."}, nil
+	}
+
+	connectorKind := NormalizeConnectorKind(integration.Kind())
+	sourceName := strings.TrimSpace(integration.Name())
+	resp := syncTriggerResponse{
+		SourceKind: connectorKind,
+		SourceName: sourceName,
+		Mode:       string(mode),
+	}
+	if !connregistry.IntegrationSupportsRunMode(integration, mode) {
+		resp.Message = ConnectorDisplayName(connectorKind) + " does not support " + string(mode) + " sync."
+		return http.StatusUnprocessableEntity, resp, nil
+	}
+
+	runKey := gen.GetLatestRunningSyncRunIDBySourceParams{
+		SourceKind: connregistry.SyncRunSourceKind(connectorKind, mode),
+		SourceName: sourceName,
+	}
+
+	requestedAt := time.Now().UTC()
+	triggerCtx := sync.WithRunMode(sync.WithConnectorScope(sync.WithForcedSync(ctx), connectorKind, sourceName), mode)
+	switch runErr := syncer.RunOnce(triggerCtx); {
+	case runErr == nil:
+		resp.Status = syncTriggerStatusStarted
+	case errors.Is(runErr, sync.ErrSyncQueued):
+		resp.Status = syncTriggerStatusQueued
+	case errors.Is(runErr, sync.ErrSyncAlreadyRunning):
+		runningID, err := latestRunningSyncRunID(ctx, runs, runKey)
+		if err != nil {
+			return 0, resp, err
+		}
+		resp.Status = syncTriggerStatusAlreadyRunning
+		resp.Message = "Sync already running."
+		resp.RunID = runningID
+		return http.StatusOK, resp, nil
+	case errors.Is(runErr, sync.ErrNoEnabledConnectors), errors.Is(runErr, sync.ErrNoConnectorsDue):
+		resp.Message = "No eligible connector work was found for this request."
+		return http.StatusConflict, resp, nil
+	default:
+		return 0, resp, runErr
+	}
+
+	runID, err := runs.GetLatestSyncRunIDStartedSinceBySource(ctx, gen.GetLatestSyncRunIDStartedSinceBySourceParams{
+		SourceKind: runKey.SourceKind,
+		SourceName: runKey.SourceName,
+		StartedAt:  pgtype.Timestamptz{Time: requestedAt, Valid: true},
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, resp, err
+	}
+	resp.Message = "Sync started."
+	resp.RunID = runID
+	return http.StatusAccepted, resp, nil
+}
+
+func latestRunningSyncRunID(ctx context.Context, runs syncTriggerRunQueries, key gen.GetLatestRunningSyncRunIDBySourceParams) (int64, error) {
+	id, err := runs.GetLatestRunningSyncRunIDBySource(ctx, key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return id, err
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	connregistry "github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/sync"
+)
+
+type fakeSyncTriggerIntegration struct {
+	kind          string
+	name          string
+	discoveryMode bool
+}
+
+func (f fakeSyncTriggerIntegration) Kind() string                       { return f.kind }
+func (f fakeSyncTriggerIntegration) Name() string                       { return f.name }
+func (f fakeSyncTriggerIntegration) Role() connregistry.IntegrationRole { return connregistry.RoleIdP }
+func (f fakeSyncTriggerIntegration) InitEvents() []connregistry.Event   { return nil }
+func (f fakeSyncTriggerIntegration) Run(context.Context, *gen.Queries, *pgxpool.Pool, func(connregistry.Event), connregistry.RunMode) error {
+	return nil
+}
+func (f fakeSyncTriggerIntegration) SupportsRunMode(mode connregistry.RunMode) bool {
+	return mode == connregistry.RunModeFull || f.discoveryMode
+}
+
+type fakeSyncTriggerRunner struct {
+	err   error
+	calls int
+}
+
+func (f *fakeSyncTriggerRunner) RunOnce(context.Context) error {
+	f.calls++
+	return f.err
+}
+
+type fakeSyncTriggerRuns struct {
+	runningID int64
+}
+
+func (f fakeSyncTriggerRuns) GetLatestRunningSyncRunIDBySource(context.Context, gen.GetLatestRunningSyncRunIDBySourceParams) (int64, error) {
+	if f.runningID == 0 {
+		return 0, pgx.ErrNoRows
+	}
+	return f.runningID, nil
+}
+
+func (f fakeSyncTriggerRuns) GetLatestSyncRunIDStartedSinceBySource(context.Context, gen.GetLatestSyncRunIDStartedSinceBySourceParams) (int64, error) {
+	return 0, pgx.ErrNoRows
+}
+
+func TestTriggerConnectorSyncRejectsUnsupportedMode(t *testing.T) {
+	t.Parallel()
+
+	runner := &fakeSyncTriggerRunner{}
+	integration := fakeSyncTriggerIntegration{kind: "github", name: "acme"}
+	status, resp, err := triggerConnectorSync(context.Background(), runner, fakeSyncTriggerRuns{}, integration, connregistry.RunModeDiscovery)
+	if err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d", status, http.StatusUnprocessableEntity)
+	}
+	if resp.Status != "" {
+		t.Fatalf("response status = %q, want empty", resp.Status)
+	}
+	if runner.calls != 0 {
+		t.Fatalf("syncer called %d times for unsupported mode", runner.calls)
+	}
+}
+
+func TestTriggerConnectorSyncReportsRunAlreadyInFlight(t *testing.T) {
+	t.Parallel()
+
+	runner := &fakeSyncTriggerRunner{err: sync.ErrSyncAlreadyRunning}
+	integration := fakeSyncTriggerIntegration{kind: "okta", name: "acme.okta.com", discoveryMode: true}
+	status, resp, err := triggerConnectorSync(context.Background(), runner, fakeSyncTriggerRuns{runningID: 42}, integration, connregistry.RunModeDiscovery)
+	if err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+	if resp.Status != syncTriggerStatusAlreadyRunning || resp.RunID != 42 {
+		t.Fatalf("response = %+v, want already_running with run 42", resp)
+	}
+}
+
+func TestTriggerConnectorSyncQueuesSupportedMode(t *testing.T) {
+	t.Parallel()
+
+	runner := &fakeSyncTriggerRunner{err: sync.ErrSyncQueued}
+	integration := fakeSyncTriggerIntegration{kind: "okta", name: "acme.okta.com", discoveryMode: true}
+	status, resp, err := triggerConnectorSync(context.Background(), runner, fakeSyncTriggerRuns{}, integration, connregistry.RunModeDiscovery)
+	if err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	if status != http.StatusAccepted || resp.Status != syncTriggerStatusQueued {
+		t.Fatalf("status = %d response = %+v, want 202 queued", status, resp)
+	}
+	if runner.calls != 1 {
+		t.Fatalf("syncer calls = %d, want 1", runner.calls)
+	}
+}
@@ -12,10 +12,12 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v5"
 	"github.com/open-sspm/open-sspm/internal/accessgraph"
+	"github.com/open-sspm/open-sspm/internal/connectors/okta"
 	"github.com/open-sspm/open-sspm/internal/db/gen"
 	"github.com/open-sspm/open-sspm/internal/http/viewmodels"
 	"github.com/open-sspm/open-sspm/internal/http/views"
@@ -28,7 +30,7 @@ func (h *Handlers) HandleIdpUsers(c *echo.Context) error {
 	if err != nil {
 		return h.RenderError(c, err)
 	}
-	const perPage = 20
+	perPage := parsePerPageParam(c)
 
 	query := strings.TrimSpace(c.QueryParam("q"))
 	state := strings.ToLower(strings.TrimSpace(c.QueryParam("state")))
@@ -40,11 +42,18 @@ func (h *Handlers) HandleIdpUsers(c *echo.Context) error {
 	default:
 		state = ""
 	}
+	mfa := strings.ToLower(strings.TrimSpace(c.QueryParam("mfa")))
+	switch mfa {
+	case okta.MFALevelNone, okta.MFALevelWeak, okta.MFALevelStrong:
+	default:
+		mfa = ""
+	}
 	page := parsePageParam(c)
 
 	totalCount, err := h.Q.CountIdPUsersByQueryAndState(ctx, gen.CountIdPUsersByQueryAndStateParams{
 		Query: query,
 		State: state,
+		Mfa:   mfa,
 	})
 	if err != nil {
 		return h.RenderError(c, err)
@@ -54,6 +63,7 @@ func (h *Handlers) HandleIdpUsers(c *echo.Context) error {
 	users, err := h.Q.ListIdPUsersPageByQueryAndState(ctx, gen.ListIdPUsersPageByQueryAndStateParams{
 		Query:      query,
 		State:      state,
+		Mfa:        mfa,
 		PageLimit:  int32(perPage),
 		PageOffset: int32(offset),
 	})
@@ -65,7 +75,7 @@ func (h *Handlers) HandleIdpUsers(c *echo.Context) error {
 	showingFrom, showingTo := showingRange(totalCount, offset, showingCount)
 
 	emptyState := "No Okta accounts synced yet."
-	if query != "" || state != "" {
+	if query != "" || state != "" || mfa != "" {
 		emptyState = "No Okta accounts match the current search."
 	}
 
@@ -74,6 +84,7 @@ func (h *Handlers) HandleIdpUsers(c *echo.Context) error {
 		Users:         users,
 		Query:         query,
 		State:         state,
+		MFA:           mfa,
 		ShowingCount:  showingCount,
 		ShowingFrom:   showingFrom,
 		ShowingTo:     showingTo,
@@ -107,10 +118,18 @@ func (h *Handlers) HandleIdpUserShow(c *echo.Context) error {
 	if err != nil {
 		return RenderNotFound(c)
 	}
+	scope, err := h.requestOrgScope(c)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	if !scope.AllowsSource("okta", user.SourceName) {
+		return RenderNotFound(c)
+	}
 	_, linked, err := h.linkedAccountsForOktaAccount(ctx, user.ID)
 	if err != nil {
 		return h.RenderError(c, err)
 	}
+	linked = scope.filterAccounts(linked)
 	linkedIDs := make([]int64, 0, len(linked))
 	for _, app := range linked {
 		linkedIDs = append(linkedIDs, app.ID)
@@ -146,17 +165,31 @@ func (h *Handlers) HandleIdpUserShow(c *echo.Context) error {
 				}
 			}
 			entitlementViews = append(entitlementViews, viewmodels.LinkedEntitlementView{
-				Kind:          strings.TrimSpace(ent.Kind),
-				ResourceKind:  resourceKind,
-				ResourceID:    resourceID,
-				ResourceLabel: resourceLabel,
-				ResourceHref:  resourceHref,
-				Permission:    strings.TrimSpace(ent.Permission),
+				Kind:            strings.TrimSpace(ent.Kind),
+				ResourceKind:    resourceKind,
+				ResourceID:      resourceID,
+				ResourceLabel:   resourceLabel,
+				ResourceHref:    resourceHref,
+				Permission:      strings.TrimSpace(ent.Permission),
+				AssignmentState: strings.TrimSpace(ent.AssignmentState),
 			})
 		}
 		linkedApps = append(linkedApps, viewmodels.LinkedAppView{AppUser: app, Entitlements: entitlementViews})
 	}
 
+	actorAccounts := append([]gen.Account{user}, linked...)
+	credentialEvents, err := h.Q.ListCredentialAuditEventsForActors(ctx, credentialActivityActorParams(actorAccounts, identityCredentialActivityLimit))
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+	loc := h.displayLocation(c)
+	credentialActivity := buildCredentialActivityTimeline(credentialEvents, actorAccounts, loc)
+
+	factors, err := h.Q.ListOktaUserFactorsByAccountID(ctx, user.ID)
+	if err != nil {
+		return h.RenderError(c, err)
+	}
+
 	assignments, err := h.Q.ListOktaUserAppAssignmentsForIdpUser(ctx, user.ID)
 	if err != nil {
 		return h.RenderError(c, err)
@@ -243,16 +276,128 @@ func (h *Handlers) HandleIdpUserShow(c *echo.Context) error {
 	data := viewmodels.IdPUserShowViewData{
 		Layout:          layout,
 		User:            user,
+		MFALevel:        oktaMFALevel(factors),
+		MFAFactors:      mfaFactorViews(factors),
 		OktaAssignments: oktaAssignments,
 		OktaAppCount:    len(oktaAssignments),
 		LinkedApps:      linkedApps,
 		LinkedAppsCount: len(linkedApps),
 		HasLinkedApps:   len(linkedApps) > 0,
+
+		CredentialActivity:    credentialActivity,
+		HasCredentialActivity: len(credentialActivity) > 0,
 	}
 
 	return h.RenderComponent(c, views.IdPUserShowPage(data))
 }
 
+// oktaMFALevel is the MFA level of a user from their synced factors.
+func oktaMFALevel(factors []gen.OktaUserFactor) string {
+	out := make([]okta.Factor, 0, len(factors))
+	for _, factor := range factors {
+		out = append(out, okta.Factor{FactorType: factor.FactorType, Status: factor.Status})
+	}
+	return okta.UserMFALevel(out)
+}
+
+func mfaFactorViews(factors []gen.OktaUserFactor) []viewmodels.MFAFactorView {
+	out := make([]viewmodels.MFAFactorView, 0, len(factors))
+	for _, factor := range factors {
+		out = append(out, viewmodels.MFAFactorView{
+			FactorType: factor.FactorType,
+			Provider:   factor.Provider,
+			Status:     factor.Status,
+			Strength:   factor.Strength,
+		})
+	}
+	return out
+}
+
+const identityCredentialActivityLimit = 200
+
+type credentialActorKey struct {
+	sourceKind string
+	sourceName string
+	externalID string
+}
+
+func credentialActorKeyForAccount(account gen.Account) credentialActorKey {
+	return credentialActorKey{
+		sourceKind: strings.TrimSpace(account.SourceKind),
+		sourceName: strings.TrimSpace(account.SourceName),
+		externalID: strings.TrimSpace(account.ExternalID),
+	}
+}
+
+// credentialActivityActorParams builds an actor filter matching audit events
+// authored by any of the given accounts.
+func credentialActivityActorParams(accounts []gen.Account, limit int32) gen.ListCredentialAuditEventsForActorsParams {
+	params := gen.ListCredentialAuditEventsForActorsParams{
+		SourceKinds:      make([]string, 0, len(accounts)),
+		SourceNames:      make([]string, 0, len(accounts)),
+		ActorExternalIds: make([]string, 0, len(accounts)),
+		LimitRows:        limit,
+	}
+	seen := make(map[credentialActorKey]struct{}, len(accounts))
+	for _, account := range accounts {
+		key := credentialActorKeyForAccount(account)
+		if key.sourceKind == "" || key.externalID == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		params.SourceKinds = append(params.SourceKinds, key.sourceKind)
+		params.SourceNames = append(params.SourceNames, key.sourceName)
+		params.ActorExternalIds = append(params.ActorExternalIds, key.externalID)
+	}
+	return params
+}
+
+// buildCredentialActivityTimeline keeps events whose actor is one of the
+// accounts and orders them newest first.
+func buildCredentialActivityTimeline(events []gen.CredentialAuditEvent, accounts []gen.Account, loc *time.Location) []viewmodels.CredentialActivityItem {
+	accountsByKey := make(map[credentialActorKey]gen.Account, len(accounts))
+	for _, account := range accounts {
+		accountsByKey[credentialActorKeyForAccount(account)] = account
+	}
+
+	matched := make([]gen.CredentialAuditEvent, 0, len(events))
+	for _, event := range events {
+		key := credentialActorKey{
+			sourceKind: strings.TrimSpace(event.SourceKind),
+			sourceName: strings.TrimSpace(event.SourceName),
+			externalID: strings.TrimSpace(event.ActorExternalID),
+		}
+		if _, ok := accountsByKey[key]; !ok {
+			continue
+		}
+		matched = append(matched, event)
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		left, right := matched[i].EventTime.Time, matched[j].EventTime.Time
+		if !left.Equal(right) {
+			return left.After(right)
+		}
+		return matched[i].ID > matched[j].ID
+	})
+
+	items := make([]viewmodels.CredentialActivityItem, 0, len(matched))
+	for _, event := range matched {
+		items = append(items, viewmodels.CredentialActivityItem{
+			EventTime:            formatProgrammaticTime(event.EventTime, loc),
+			EventType:            fallbackDash(event.EventType),
+			Actor:                fallbackDash(actorDisplayName(event.ActorDisplayName, event.ActorExternalID)),
+			ActorSource:          strings.TrimSpace(event.SourceKind) + " • " + strings.TrimSpace(event.SourceName),
+			Target:               fallbackDash(actorDisplayName(event.TargetDisplayName, event.TargetExternalID)),
+			CredentialKind:       fallbackDash(event.CredentialKind),
+			CredentialExternalID: fallbackDash(event.CredentialExternalID),
+		})
+	}
+	return items
+}
+
 // HandleGitHubUsers renders the GitHub users page.
 func (h *Handlers) HandleGitHubUsers(c *echo.Context) error {
 	ctx := c.Request().Context()
@@ -261,7 +406,7 @@ func (h *Handlers) HandleGitHubUsers(c *echo.Context) error {
 		return h.RenderError(c, err)
 	}
 
-	const perPage = 20
+	perPage := parsePerPageParam(c)
 	query := strings.TrimSpace(c.QueryParam("q"))
 	page := parsePageParam(c)
 
@@ -732,17 +877,25 @@ func (h *Handlers) HandleIdpUserAccessTree(c *echo.Context) error {
 	}
 
 	ctx := c.Request().Context()
-	_, err = h.Q.GetIdPUser(ctx, id)
+	idpUser, err := h.Q.GetIdPUser(ctx, id)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return renderAccessTreeError(http.StatusNotFound, "idp user not found")
 		}
 		return renderAccessTreeError(http.StatusInternalServerError, "internal error")
 	}
+	visibleScope, err := h.requestOrgScope(c)
+	if err != nil {
+		return renderAccessTreeError(http.StatusInternalServerError, "internal error")
+	}
+	if !visibleScope.AllowsSource("okta", idpUser.SourceName) {
+		return renderAccessTreeError(http.StatusNotFound, "idp user not found")
+	}
 	currentIdentityID, linkedAccounts, err := h.linkedAccountsForOktaAccount(ctx, id)
 	if err != nil {
 		return renderAccessTreeError(http.StatusInternalServerError, "internal error")
 	}
+	linkedAccounts = visibleScope.filterAccounts(linkedAccounts)
 
 	nodeID := strings.TrimSpace(c.QueryParam("node"))
 	if nodeID == "" {
@@ -937,6 +1090,11 @@ func (h *Handlers) HandleIdpUserAccessTree(c *echo.Context) error {
 			}
 			return renderAccessTreeError(http.StatusInternalServerError, "internal error")
 		}
+		if visible, err := h.sourceVisible(c, appUser.SourceKind, appUser.SourceName); err != nil {
+			return renderAccessTreeError(http.StatusInternalServerError, "internal error")
+		} else if !visible {
+			return renderAccessTreeError(http.StatusNotFound, "app user not found")
+		}
 
 		ents, err := h.Q.ListEntitlementsForAppUser(ctx, appUserID)
 		if err != nil {
@@ -6,8 +6,11 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
 )
 
 func TestParseCreateLinkFormSupportsIdentityPayload(t *testing.T) {
@@ -70,6 +73,60 @@ func TestParseCreateLinkFormRejectsInvalidValues(t *testing.T) {
 	}
 }
 
+func TestCredentialActivityIncludesEventsFromLinkedAccounts(t *testing.T) {
+	t.Parallel()
+
+	accounts := []gen.Account{
+		{ID: 1, SourceKind: "okta", SourceName: "acme.okta.com", ExternalID: "00u1"},
+		{ID: 2, SourceKind: "github", SourceName: "acme", ExternalID: "alice"},
+		{ID: 3, SourceKind: "entra", SourceName: "tenant-1", ExternalID: "user-1"},
+		{ID: 4, SourceKind: "github", SourceName: "acme", ExternalID: "alice"},
+	}
+
+	params := credentialActivityActorParams(accounts, 50)
+	if len(params.ActorExternalIds) != 3 || len(params.SourceKinds) != 3 || len(params.SourceNames) != 3 {
+		t.Fatalf("params = %+v, want 3 deduped actors", params)
+	}
+	if params.SourceKinds[1] != "github" || params.SourceNames[1] != "acme" || params.ActorExternalIds[1] != "alice" {
+		t.Fatalf("params[1] = %s %s %s", params.SourceKinds[1], params.SourceNames[1], params.ActorExternalIds[1])
+	}
+	if params.LimitRows != 50 {
+		t.Fatalf("limit = %d, want 50", params.LimitRows)
+	}
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: base.Add(offset), Valid: true}
+	}
+	events := []gen.CredentialAuditEvent{
+		{ID: 10, SourceKind: "github", SourceName: "acme", EventType: "personal_access_token.create", EventTime: at(-2 * time.Hour), ActorExternalID: "alice"},
+		{ID: 11, SourceKind: "entra", SourceName: "tenant-1", EventType: "Update application - Certificates and secrets management", EventTime: at(0), ActorExternalID: "user-1", ActorDisplayName: "Alice Example"},
+		{ID: 12, SourceKind: "github", SourceName: "other-org", EventType: "personal_access_token.create", EventTime: at(time.Hour), ActorExternalID: "alice"},
+		{ID: 13, SourceKind: "okta", SourceName: "acme.okta.com", EventType: "system.api_token.create", EventTime: at(-time.Hour), ActorExternalID: "00u1"},
+	}
+
+	items := buildCredentialActivityTimeline(events, accounts, time.UTC)
+	if len(items) != 3 {
+		t.Fatalf("timeline = %+v, want 3 events", items)
+	}
+	wantTypes := []string{
+		"Update application - Certificates and secrets management",
+		"system.api_token.create",
+		"personal_access_token.create",
+	}
+	for i, want := range wantTypes {
+		if items[i].EventType != want {
+			t.Fatalf("items[%d].EventType = %q, want %q", i, items[i].EventType, want)
+		}
+	}
+	if items[0].Actor != "Alice Example" || items[0].ActorSource != "entra • tenant-1" {
+		t.Fatalf("items[0] actor = %q %q", items[0].Actor, items[0].ActorSource)
+	}
+	if items[2].Actor != "alice" || items[2].CredentialKind != "—" {
+		t.Fatalf("items[2] = %+v", items[2])
+	}
+}
+
 func newLinkFormContext(t *testing.T, values map[string]string) *echo.Context {
 	t.Helper()
 
@@ -19,6 +19,7 @@ import (
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/middleware"
 	"github.com/open-sspm/open-sspm/internal/auth"
+	"github.com/open-sspm/open-sspm/internal/auth/providers"
 	"github.com/open-sspm/open-sspm/internal/config"
 	"github.com/open-sspm/open-sspm/internal/connectors/registry"
 	"github.com/open-sspm/open-sspm/internal/db/gen"
@@ -49,6 +50,16 @@ func NewEchoServer(cfg config.Config, pool *pgxpool.Pool, q *gen.Queries, syncer
 	sessions.Cookie.Secure = cfg.AuthCookieSecure
 
 	h := &handlers.Handlers{Cfg: cfg, Q: q, Pool: pool, Syncer: syncer, Registry: reg, Sessions: sessions}
+	if cfg.OIDCEnabled() {
+		h.OIDC = providers.NewOIDCProvider(providers.OIDCConfig{
+			IssuerURL:    cfg.OIDCIssuerURL,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Scopes:       cfg.OIDCScopes,
+			GroupsClaim:  cfg.OIDCGroupsClaim,
+		})
+	}
 	es := &EchoServer{h: h, e: newEcho()}
 	es.e.Use(middleware.RequestIDWithConfig(middleware.RequestIDConfig{
 		RequestIDHandler: func(c *echo.Context, id string) {
@@ -218,23 +229,42 @@ func (es *EchoServer) registerRoutes() {
 		},
 	}))
 
+	es.e.GET("/login/oidc", es.h.HandleOIDCLogin)
+	es.e.GET("/login/oidc/callback", es.h.HandleOIDCCallback)
+
 	authed.Use(authn.RequireAuth(es.h.Sessions, es.h.Q))
+	authed.Use(handlers.AuditMutations(es.h.Q))
 	authed.GET("/", es.h.HandleDashboard)
 	authed.GET("/global-view", es.h.HandleGlobalView)
 	authed.GET("/apps", es.h.HandleApps)
 	authed.GET("/apps/*", es.h.HandleOktaAppShow)
 	authed.GET("/discovery/apps", es.h.HandleDiscoveryApps)
+	authed.GET("/discovery/apps/report", es.h.HandleDiscoveryAppsReport)
+	authed.GET("/discovery/events/export", es.h.HandleDiscoveryEventsExport)
 	authed.GET("/discovery/apps/:id", es.h.HandleDiscoveryAppShow)
 	authed.GET("/discovery/hotspots", es.h.HandleDiscoveryHotspots)
 	authed.GET("/app-assets", es.h.HandleAppAssets)
 	authed.GET("/app-assets/:id", es.h.HandleAppAssetShow)
 	authed.GET("/identities", es.h.HandleIdentities)
+	authed.GET("/identities/duplicates", es.h.HandleIdentityDuplicates)
 	authed.GET("/identities/:id", es.h.HandleIdentityShow)
+	authed.GET("/identities/:id/access-report", es.h.HandleIdentityAccessReport)
+	authed.GET("/entitlement-changes", es.h.HandleEntitlementChanges)
+	authed.GET("/privileged-access", es.h.HandlePrivilegedAccess)
+	authed.GET("/privileged-access/escalations", es.h.HandleEscalationPaths)
 	authed.GET("/credentials", es.h.HandleCredentials)
+	authed.GET("/credentials/rotation-sla", es.h.HandleCredentialRotationReport)
+	authed.GET("/credentials/expiring-unowned", es.h.HandleCredentialExpiringUnownedReport)
+	authed.GET("/credentials/expiry.ics", es.h.HandleCredentialExpiryCalendar)
 	authed.GET("/credentials/:id", es.h.HandleCredentialShow)
+	authed.GET("/credentials/:id/scope.json", es.h.HandleCredentialScopeJSON)
+	authed.GET("/inventory-snapshots", es.h.HandleInventorySnapshots)
 	authed.GET("/idp-users", es.h.HandleIdpUsers)
 	authed.GET("/idp-users/*", es.h.HandleIdpUserShow)
 	authed.GET("/api/idp-users/:id/access-tree", es.h.HandleIdpUserAccessTree)
+	authed.GET("/api/credentials", es.h.HandleAPICredentials)
+	authed.GET("/api/credential-kinds", es.h.HandleAPICredentialKinds)
+	authed.GET("/api/credential-kinds/:kind", es.h.HandleAPICredentialKindShow)
 	authed.GET("/resources/:sourceKind/:sourceName/:resourceKind/*", es.h.HandleResourceShow)
 	authed.GET("/findings", es.h.HandleFindings)
 	authed.GET("/findings/rulesets/:rulesetKey", es.h.HandleFindingsRuleset)
@@ -252,18 +282,35 @@ func (es *EchoServer) registerRoutes() {
 	authed.GET("/unmatched/aws", es.h.HandleUnmatchedAWS)
 	authed.GET("/unmatched/datadog/*", es.h.HandleUnmatchedDatadog)
 	authed.POST("/logout", es.h.HandleLogoutPost)
+	authed.POST("/account/timezone", es.h.HandleAccountTimezone)
+
+	// Analysts may annotate inventory; anything that changes what is synced, bound, suppressed,
+	// or revoked stays with admins.
+	analyst := authed.Group("")
+	analyst.Use(authn.RequireRole(auth.RoleAnalyst))
+	analyst.POST("/credentials/:id/tags", es.h.HandleCredentialTagsCreate)
+	analyst.POST("/credentials/:id/tags/delete", es.h.HandleCredentialTagDelete)
+	analyst.POST("/credentials/bulk", es.h.HandleCredentialsBulk)
+	analyst.POST("/findings/rulesets/:rulesetKey/rules/:ruleKey/attestation", es.h.HandleFindingsRuleAttestation)
 
 	admin := authed.Group("")
 	admin.Use(authn.RequireRole(auth.RoleAdmin))
 	admin.POST("/apps/map", es.h.HandleAppsMap)
 	admin.POST("/links", es.h.HandleCreateLink)
+	admin.POST("/identities/duplicates/:id/accept", es.h.HandleIdentityMergeAccept)
+	admin.POST("/identities/duplicates/:id/dismiss", es.h.HandleIdentityMergeDismiss)
+	admin.POST("/discovery/apps/:id/bindings", es.h.HandleDiscoveryAppBindingCreate)
+	admin.POST("/credentials/:id/revoke", es.h.HandleCredentialRevoke)
+	admin.POST("/credentials/:id/revocation/complete", es.h.HandleCredentialRevocationComplete)
 	admin.POST("/findings/rulesets/:rulesetKey/override", es.h.HandleFindingsRulesetOverride)
 	admin.POST("/findings/rulesets/:rulesetKey/rules/:ruleKey/override", es.h.HandleFindingsRuleOverride)
-	admin.POST("/findings/rulesets/:rulesetKey/rules/:ruleKey/attestation", es.h.HandleFindingsRuleAttestation)
 	admin.GET("/settings", es.h.HandleSettings)
 	admin.GET("/settings/connectors", es.h.HandleConnectors)
 	admin.GET("/settings/connector-health", es.h.HandleConnectorHealth)
 	admin.GET("/settings/connector-health/errors", es.h.HandleConnectorHealthErrorDetails)
+	admin.GET("/settings/audit-log", es.h.HandleAuditLog)
+	admin.GET("/settings/sync-runs", es.h.HandleSyncRuns)
+	admin.GET("/settings/sync-runs/:id", es.h.HandleSyncRunShow)
 	admin.POST("/settings/connector-health/sync", es.h.HandleConnectorHealthSync)
 	admin.POST("/settings/connectors/*", es.h.HandleConnectorAction)
 	admin.GET("/settings/users", es.h.HandleSettingsUsers)
@@ -271,6 +318,7 @@ func (es *EchoServer) registerRoutes() {
 	admin.POST("/settings/users/:id", es.h.HandleSettingsUserUpdate)
 	admin.POST("/settings/users/:id/delete", es.h.HandleSettingsUserDelete)
 	admin.POST("/settings/resync", es.h.HandleResync)
+	admin.POST("/api/sync", es.h.HandlePostSync)
 
 	staticDir, ok := resolveStaticDir(es.h.Cfg.StaticDir)
 	if ok {
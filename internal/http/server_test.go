@@ -2,6 +2,7 @@ package httpapp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -13,7 +14,14 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/alexedwards/scs/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/labstack/echo/v5"
+	"github.com/open-sspm/open-sspm/internal/auth"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/http/authn"
 	"github.com/open-sspm/open-sspm/internal/http/handlers"
 )
 
@@ -239,3 +247,221 @@ func TestStaticServesAssetsAndBlocksTraversal(t *testing.T) {
 		})
 	}
 }
+
+// fakeAuthUserDB serves the signed-in user and, when set, one connector config, and records
+// every Exec so tests can inspect writes such as audit log entries.
+type fakeAuthUserDB struct {
+	user      gen.AuthUser
+	connector gen.ConnectorConfig
+	execs     []fakeExecCall
+}
+
+type fakeExecCall struct {
+	sql  string
+	args []interface{}
+}
+
+func (f *fakeAuthUserDB) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	f.execs = append(f.execs, fakeExecCall{sql: sql, args: args})
+	return pgconn.CommandTag{}, nil
+}
+
+func (f *fakeAuthUserDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("unexpected query")
+}
+
+func (f *fakeAuthUserDB) QueryRow(_ context.Context, sql string, args ...interface{}) pgx.Row {
+	return fakeAuthUserRow{db: f, sql: sql, args: args}
+}
+
+func (f *fakeAuthUserDB) execsFor(name string) []fakeExecCall {
+	var out []fakeExecCall
+	for _, call := range f.execs {
+		if strings.Contains(call.sql, "name: "+name+" ") {
+			out = append(out, call)
+		}
+	}
+	return out
+}
+
+type fakeAuthUserRow struct {
+	db   *fakeAuthUserDB
+	sql  string
+	args []interface{}
+}
+
+// Scan fills the GetAuthUser columns that LoadPrincipal reads and the connector config columns
+// used by the connector toggle.
+func (r fakeAuthUserRow) Scan(dest ...any) error {
+	switch {
+	case strings.Contains(r.sql, "name: GetAuthUser :one") && len(dest) >= 10:
+		*dest[0].(*int64) = r.db.user.ID
+		*dest[1].(*string) = r.db.user.Email
+		*dest[3].(*string) = r.db.user.Role
+		*dest[4].(*bool) = r.db.user.IsActive
+		*dest[9].(*int64) = r.db.user.OrgID
+		return nil
+	case strings.Contains(r.sql, "name: GetConnectorConfig :one") && r.db.connector.Kind != "":
+		*dest[0].(*string) = r.db.connector.Kind
+		*dest[1].(*bool) = r.db.connector.Enabled
+		*dest[2].(*[]byte) = r.db.connector.Config
+		return nil
+	case strings.Contains(r.sql, "name: UpdateConnectorConfigEnabled :one") && r.db.connector.Kind != "":
+		r.db.connector.Enabled = r.args[1].(bool)
+		*dest[0].(*string) = r.db.connector.Kind
+		*dest[1].(*bool) = r.db.connector.Enabled
+		*dest[2].(*[]byte) = r.db.connector.Config
+		return nil
+	}
+	return pgx.ErrNoRows
+}
+
+type fakeSyncRunner struct {
+	runs int
+}
+
+func (f *fakeSyncRunner) RunOnce(context.Context) error {
+	f.runs++
+	return nil
+}
+
+// newRoleTestServer registers the real routes with an in-memory session signed in as a user with
+// role, and returns the session cookie to send.
+func newRoleTestServer(t *testing.T, role string) (*EchoServer, *http.Cookie, *fakeSyncRunner) {
+	t.Helper()
+	return newRoleTestServerWithDB(t, role, &fakeAuthUserDB{})
+}
+
+func newRoleTestServerWithDB(t *testing.T, role string, db *fakeAuthUserDB) (*EchoServer, *http.Cookie, *fakeSyncRunner) {
+	t.Helper()
+
+	sessions := scs.New()
+	syncer := &fakeSyncRunner{}
+	user := gen.AuthUser{ID: 7, Email: role + "@example.com", Role: role, IsActive: true, OrgID: 1}
+	db.user = user
+	h := &handlers.Handlers{Q: gen.New(db), Sessions: sessions, Syncer: syncer}
+	es := &EchoServer{h: h, e: newEcho()}
+	es.e.Use(echo.WrapMiddleware(sessions.LoadAndSave))
+	es.registerRoutes()
+
+	ctx, err := sessions.Load(context.Background(), "")
+	if err != nil {
+		t.Fatalf("sessions.Load() error = %v", err)
+	}
+	sessions.Put(ctx, authn.SessionKeyUserID, user.ID)
+	token, _, err := sessions.Commit(ctx)
+	if err != nil {
+		t.Fatalf("sessions.Commit() error = %v", err)
+	}
+	return es, &http.Cookie{Name: sessions.Cookie.Name, Value: token}, syncer
+}
+
+func TestSyncTriggerRequiresAdminRole(t *testing.T) {
+	cases := []struct {
+		role       string
+		wantStatus int
+		wantRuns   int
+	}{
+		{role: auth.RoleViewer, wantStatus: http.StatusForbidden},
+		{role: auth.RoleAnalyst, wantStatus: http.StatusForbidden},
+		{role: auth.RoleAdmin, wantStatus: http.StatusSeeOther, wantRuns: 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.role, func(t *testing.T) {
+			es, cookie, syncer := newRoleTestServer(t, tc.role)
+
+			req := httptest.NewRequest(http.MethodPost, "/settings/resync", nil)
+			req.AddCookie(cookie)
+			rec := httptest.NewRecorder()
+			es.e.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tc.wantStatus)
+			}
+			if syncer.runs != tc.wantRuns {
+				t.Fatalf("sync runs = %d, want %d", syncer.runs, tc.wantRuns)
+			}
+			if tc.role == auth.RoleAdmin && rec.Header().Get("Location") != "/settings?resync=success" {
+				t.Fatalf("Location = %q, want resync success", rec.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestAPISyncTriggerForbidsViewer(t *testing.T) {
+	es, cookie, syncer := newRoleTestServer(t, auth.RoleViewer)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sync", strings.NewReader("source_kind=github&source_name=acme&mode=full"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	es.e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "forbidden") {
+		t.Fatalf("response = %d %s, want 403 forbidden JSON", rec.Code, rec.Body.String())
+	}
+	if syncer.runs != 0 {
+		t.Fatalf("viewer triggered %d sync runs", syncer.runs)
+	}
+}
+
+func TestConnectorEnableIsAudited(t *testing.T) {
+	db := &fakeAuthUserDB{connector: gen.ConnectorConfig{
+		Kind:   "okta",
+		Config: []byte(`{"domain":"acme.okta.com","token":"secret-token"}`),
+	}}
+	es, cookie, _ := newRoleTestServerWithDB(t, auth.RoleAdmin, db)
+
+	req := httptest.NewRequest(http.MethodPost, "/settings/connectors/okta/toggle", strings.NewReader("enabled=1"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	es.e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusSeeOther, rec.Body.String())
+	}
+	entries := db.execsFor("InsertAppAuditLogEntry")
+	if len(entries) != 1 {
+		t.Fatalf("audit entries = %d, want 1", len(entries))
+	}
+	args := entries[0].args
+	if actor := args[1].(pgtype.Int8); !actor.Valid || actor.Int64 != 7 {
+		t.Fatalf("actor_auth_user_id = %+v, want 7", actor)
+	}
+	want := map[int]any{
+		0: int64(1),
+		2: "admin@example.com",
+		3: auth.RoleAdmin,
+		4: "connector.enable",
+		5: http.MethodPost,
+		6: "/settings/connectors/*",
+		7: "okta",
+		8: int32(http.StatusSeeOther),
+	}
+	for idx, value := range want {
+		if args[idx] != value {
+			t.Fatalf("audit arg %d = %v, want %v", idx, args[idx], value)
+		}
+	}
+	if before, after := string(args[9].([]byte)), string(args[10].([]byte)); before != `{"enabled":false}` || after != `{"enabled":true}` {
+		t.Fatalf("before/after = %s / %s, want enabled false -> true", before, after)
+	}
+}
+
+func TestForbiddenMutationIsAudited(t *testing.T) {
+	db := &fakeAuthUserDB{}
+	es, cookie, _ := newRoleTestServerWithDB(t, auth.RoleViewer, db)
+
+	req := httptest.NewRequest(http.MethodPost, "/settings/resync", nil)
+	req.AddCookie(cookie)
+	es.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := db.execsFor("InsertAppAuditLogEntry")
+	if len(entries) != 1 {
+		t.Fatalf("audit entries = %d, want 1", len(entries))
+	}
+	if action, status := entries[0].args[4], entries[0].args[8]; action != "sync.resync" || status != int32(http.StatusForbidden) {
+		t.Fatalf("audit entry = %v %v, want sync.resync 403", action, status)
+	}
+}
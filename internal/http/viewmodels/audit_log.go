@@ -0,0 +1,30 @@
+package viewmodels
+
+type AuditLogEntry struct {
+	OccurredAt  string
+	Actor       string
+	ActorRole   string
+	Action      string
+	Route       string
+	Target      string
+	StatusCode  int
+	StatusClass string
+	Before      string
+	After       string
+	RequestID   string
+}
+
+type AuditLogViewData struct {
+	Layout         LayoutData
+	Entries        []AuditLogEntry
+	HasEntries     bool
+	Actions        []string
+	SelectedAction string
+	Actor          string
+	Page           int
+	PerPage        int
+	TotalPages     int
+	TotalCount     int64
+	ShowingFrom    int
+	ShowingTo      int
+}
@@ -24,6 +24,12 @@ type ConnectorHealthItem struct {
 	DetailsURL       string
 	CanViewDetails   bool
 	CanTriggerSync   bool
+
+	// Grants come from the connector's latest run; empty labels mean the source did not report them.
+	GrantsLabel   string
+	GrantsClass   string
+	GrantsTitle   string
+	MissingGrants []string
 }
 
 type ConnectorHealthErrorDetailsDialogViewData struct {
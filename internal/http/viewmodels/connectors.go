@@ -27,15 +27,21 @@ type GoogleWorkspaceConnectorViewData struct {
 	HasServiceAccountJSON bool
 	ServiceAccountEmail   string
 	DiscoveryEnabled      bool
+	DirectLoginEvents     bool
 }
 
 type GitHubConnectorViewData struct {
-	Enabled     bool
-	Configured  bool
-	Org         string
-	APIBase     string
-	Enterprise  string
-	SCIMEnabled bool
+	Enabled               bool
+	Configured            bool
+	Org                   string
+	APIBase               string
+	Enterprise            string
+	SCIMEnabled           bool
+	SecretScanningEnabled bool
+	// Workers is the configured request concurrency, up to MaxWorkers; 0 uses the deployment
+	// default.
+	Workers     int
+	MaxWorkers  int
 	TokenMasked string
 	HasToken    bool
 }
@@ -85,6 +91,16 @@ type VaultConnectorViewData struct {
 	HasTLSCACert        bool
 }
 
+type BitbucketConnectorViewData struct {
+	Enabled     bool
+	Configured  bool
+	Workspace   string
+	Username    string
+	APIBase     string
+	TokenMasked string
+	HasToken    bool
+}
+
 type EntraConnectorViewData struct {
 	Enabled            bool
 	Configured         bool
@@ -108,4 +124,5 @@ type ConnectorsViewData struct {
 	AWSIdentityCenter AWSIdentityCenterConnectorViewData
 	Entra             EntraConnectorViewData
 	Vault             VaultConnectorViewData
+	Bitbucket         BitbucketConnectorViewData
 }
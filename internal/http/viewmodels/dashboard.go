@@ -6,6 +6,21 @@ type DashboardViewData struct {
 	AppCount          int64
 	ConnectedAppCount int64
 	FrameworkPosture  []DashboardFrameworkPostureItem
+	Connectors        []DashboardConnectorItem
+}
+
+// DashboardConnectorItem is one connector row on the dashboard health card. Counts are
+// preformatted; "—" marks a count that could not be loaded.
+type DashboardConnectorItem struct {
+	Name             string
+	SourceName       string
+	StatusLabel      string
+	StatusClass      string
+	Users            string
+	Assets           string
+	Credentials      string
+	DiscoveryApps    string
+	LastSuccessLabel string
 }
 
 type DashboardCommandUserItem struct {
@@ -11,6 +11,7 @@ type DiscoveryAppListItem struct {
 	DisplayName   string
 	Domain        string
 	VendorName    string
+	Category      string
 	ManagedState  string
 	ManagedReason string
 	RiskScore     int32
@@ -29,16 +30,26 @@ type DiscoveryAppsViewData struct {
 	Query              string
 	ManagedState       string
 	RiskLevel          string
-	Items              []DiscoveryAppListItem
-	ShowingCount       int
-	ShowingFrom        int
-	ShowingTo          int
-	TotalCount         int64
-	Page               int
-	PerPage            int
-	TotalPages         int
-	HasItems           bool
-	EmptyStateMsg      string
+	Category           string
+	// CategoryFacets counts catalog-categorized apps under the current filters, ignoring the
+	// category filter itself.
+	CategoryFacets []DiscoveryCategoryFacet
+	Items          []DiscoveryAppListItem
+	ShowingCount   int
+	ShowingFrom    int
+	ShowingTo      int
+	TotalCount     int64
+	Page           int
+	PerPage        int
+	TotalPages     int
+	HasItems       bool
+	EmptyStateMsg  string
+}
+
+type DiscoveryCategoryFacet struct {
+	Category string
+	Count    int64
+	Selected bool
 }
 
 type DiscoveryHotspotItem struct {
@@ -61,6 +72,21 @@ type DiscoveryHotspotsViewData struct {
 	Items              []DiscoveryHotspotItem
 	HasItems           bool
 	EmptyStateMsg      string
+	OAuthAnomalies     []DiscoveryOAuthAnomalyItem
+	HasOAuthAnomalies  bool
+}
+
+// DiscoveryOAuthAnomalyItem is a newly-seen app flagged for a suspicious burst of OAuth grants or
+// high-risk scopes on its first grants.
+type DiscoveryOAuthAnomalyItem struct {
+	SaaSAppID      int64
+	DisplayName    string
+	Domain         string
+	Reasons        []string
+	ActorsInWindow int64
+	HighRiskScopes []string
+	FirstGrantAt   string
+	DetectedAt     string
 }
 
 type DiscoverySourceEvidenceItem struct {
@@ -76,10 +102,32 @@ type DiscoveryActorItem struct {
 	ActorLabel      string
 	ActorEmail      string
 	ActorExternalID string
+	IdentityHref    string
+	IdentityLabel   string
 	EventCount      int64
+	FirstObservedAt string
 	LastObservedAt  string
 }
 
+// DiscoveryActorTrendPoint is one week of a discovered app's distinct-actor series.
+type DiscoveryActorTrendPoint struct {
+	WeekStart  string
+	ActorCount int64
+	// HeightPercent scales ActorCount against the busiest week in the series.
+	HeightPercent int
+}
+
+// DiscoverySignalKindItem counts a discovered app's active events and actors for one signal kind.
+type DiscoverySignalKindItem struct {
+	SignalKind     string
+	Label          string
+	EventCount     int64
+	ActorCount     int64
+	LastObservedAt string
+	// SharePercent is the signal kind's share of the app's active events.
+	SharePercent int
+}
+
 type DiscoveryEventItem struct {
 	SignalKind    string
 	ObservedAt    string
@@ -94,6 +142,7 @@ type DiscoveryAppSummaryView struct {
 	CanonicalKey                 string
 	PrimaryDomain                string
 	VendorName                   string
+	Category                     string
 	ManagedState                 string
 	ManagedReason                string
 	RiskScore                    int32
@@ -102,15 +151,49 @@ type DiscoveryAppSummaryView struct {
 	SuggestedDataClassification  string
 	FirstSeenAt                  string
 	LastSeenAt                   string
+	ManagedAssetHref             string
+}
+
+type DiscoveryBindingItem struct {
+	ConnectorLabel string
+	BindingSource  string
+	Confidence     string
+	IsPrimary      bool
+	// Suggested bindings scored below the auto-bind threshold and await an admin's confirmation;
+	// SourceValue is the connector source an admin confirms them with.
+	Suggested   bool
+	SourceValue string
 }
 
 type DiscoveryAppShowViewData struct {
-	Layout       LayoutData
-	App          DiscoveryAppSummaryView
-	Sources      []DiscoverySourceEvidenceItem
-	TopActors    []DiscoveryActorItem
-	Events       []DiscoveryEventItem
-	HasSources   bool
-	HasTopActors bool
-	HasEvents    bool
+	Layout    LayoutData
+	App       DiscoveryAppSummaryView
+	Sources   []DiscoverySourceEvidenceItem
+	TopActors []DiscoveryActorItem
+	// ActorTrend is the weekly distinct-actor count, oldest week first, with empty weeks kept.
+	ActorTrend []DiscoveryActorTrendPoint
+	Events     []DiscoveryEventItem
+	Bindings   []DiscoveryBindingItem
+	// Credentials are the app's credentials from every bound connector source.
+	Credentials []DiscoveryCredentialItem
+	// BindingSources are the configured connector sources an admin can bind the app to.
+	BindingSources []ProgrammaticSourceOption
+	HasSources     bool
+	HasTopActors   bool
+	HasEvents      bool
+	OAuthAnomaly   *DiscoveryOAuthAnomalyItem
+	// SignalKinds breaks the app's active events down by how they were observed; SSO and OAuth
+	// grant are always listed.
+	SignalKinds []DiscoverySignalKindItem
+}
+
+type DiscoveryCredentialItem struct {
+	ID             int64
+	Href           string
+	Name           string
+	CredentialKind string
+	SourceLabel    string
+	Status         string
+	ExpiresAt      string
+	RiskLevel      string
 }
@@ -0,0 +1,46 @@
+package viewmodels
+
+type EntitlementChangeRow struct {
+	ChangeType  string
+	ChangeLabel string
+	ChangeClass string
+
+	Kind               string
+	Resource           string
+	ResourceLabel      string
+	ResourceHref       string
+	PreviousPermission string
+	Permission         string
+	AssignmentState    string
+
+	AppUserExternalID  string
+	AppUserEmail       string
+	AppUserDisplayName string
+
+	IdentityID    int64
+	IdentityHref  string
+	IdentityLabel string
+}
+
+type EntitlementChangesViewData struct {
+	Layout LayoutData
+
+	Sources             []ProgrammaticSourceOption
+	SelectedSourceKind  string
+	SelectedSourceName  string
+	SelectedSourceLabel string
+
+	CurrentRunID     int64
+	PreviousRunID    int64
+	CurrentRunLabel  string
+	PreviousRunLabel string
+
+	GrantedCount  int
+	RevokedCount  int
+	ModifiedCount int
+
+	Rows          []EntitlementChangeRow
+	HasRows       bool
+	Truncated     bool
+	EmptyStateMsg string
+}
@@ -8,6 +8,7 @@ type EntraUserListItem struct {
 	Email              string
 	DisplayName        string
 	IdpUserID          int64
+	IsGuest            bool
 	DirectoryRoleCount int
 	EnterpriseAppCount int
 }
@@ -16,6 +17,7 @@ type EntraUsersViewData struct {
 	Layout         LayoutData
 	Users          []EntraUserListItem
 	Query          string
+	UserType       string
 	ShowingCount   int
 	ShowingFrom    int
 	ShowingTo      int
@@ -63,5 +63,95 @@ type IdentityShowViewData struct {
 	Identity               gen.GetIdentitySummaryByIDRow
 	LinkedAccounts         []IdentityLinkedAccountView
 	ProgrammaticAccessHref string
+	OwnedAssetsHref        string
 	HasLinkedAccounts      bool
 }
+
+type IdentityMergeSuggestionItem struct {
+	ID                     int64
+	Score                  string
+	Reasons                []string
+	UpdatedAt              string
+	SurvivorID             int64
+	SurvivorNamePrimary    string
+	SurvivorNameSecondary  string
+	SurvivorAccounts       int64
+	DuplicateID            int64
+	DuplicateNamePrimary   string
+	DuplicateNameSecondary string
+	DuplicateAccounts      int64
+}
+
+type IdentityDuplicatesViewData struct {
+	Layout        LayoutData
+	Items         []IdentityMergeSuggestionItem
+	Truncated     bool
+	EmptyStateMsg string
+}
+
+// IdentityAccessReportViewData is the offboarding report for one identity: the access it holds
+// and the artifacts it created, approved, owns, or authorized across every connected source.
+type IdentityAccessReportViewData struct {
+	Layout      LayoutData
+	Identity    gen.GetIdentitySummaryByIDRow
+	Accounts    []IdentityAccessAccountItem
+	Credentials []IdentityAccessCredentialItem
+	OwnedAssets []IdentityAccessAssetItem
+	OAuthGrants []IdentityAccessOAuthGrantItem
+	// Truncated reports that a category hit the report's row limit.
+	Truncated bool
+}
+
+type IdentityAccessAccountItem struct {
+	SourceKind   string
+	SourceName   string
+	SourceLabel  string
+	Label        string
+	ExternalID   string
+	Email        string
+	DisplayName  string
+	Status       string
+	DetailHref   string
+	Entitlements []IdentityAccessEntitlementItem
+}
+
+type IdentityAccessEntitlementItem struct {
+	Kind           string
+	ResourceLabel  string
+	ResourceHref   string
+	Permission     string
+	PrivilegeLevel string
+	PrivilegeClass string
+}
+
+type IdentityAccessCredentialItem struct {
+	ID             int64
+	Href           string
+	Name           string
+	CredentialKind string
+	SourceLabel    string
+	Status         string
+	Role           string
+	ExpiresAt      string
+	RiskLevel      string
+}
+
+type IdentityAccessAssetItem struct {
+	ID          int64
+	Href        string
+	Name        string
+	AssetKind   string
+	SourceLabel string
+	Status      string
+}
+
+type IdentityAccessOAuthGrantItem struct {
+	SaaSAppID       int64
+	Href            string
+	AppName         string
+	SourceLabel     string
+	ScopesSummary   string
+	EventCount      int64
+	FirstObservedAt string
+	LastObservedAt  string
+}
@@ -3,12 +3,13 @@ package viewmodels
 import "github.com/open-sspm/open-sspm/internal/db/gen"
 
 type LinkedEntitlementView struct {
-	Kind          string
-	ResourceKind  string
-	ResourceID    string
-	ResourceLabel string
-	ResourceHref  string
-	Permission    string
+	Kind            string
+	ResourceKind    string
+	ResourceID      string
+	ResourceLabel   string
+	ResourceHref    string
+	Permission      string
+	AssignmentState string
 }
 
 type LinkedAppView struct {
@@ -16,12 +17,33 @@ type LinkedAppView struct {
 	Entitlements []LinkedEntitlementView
 }
 
+type MFAFactorView struct {
+	FactorType string
+	Provider   string
+	Status     string
+	Strength   string
+}
+
 type IdPUserShowViewData struct {
-	Layout          LayoutData
-	User            gen.Account
-	OktaAssignments []OktaAssignmentView
-	OktaAppCount    int
-	LinkedApps      []LinkedAppView
-	LinkedAppsCount int
-	HasLinkedApps   bool
+	Layout                LayoutData
+	User                  gen.Account
+	MFALevel              string
+	MFAFactors            []MFAFactorView
+	OktaAssignments       []OktaAssignmentView
+	OktaAppCount          int
+	LinkedApps            []LinkedAppView
+	LinkedAppsCount       int
+	HasLinkedApps         bool
+	CredentialActivity    []CredentialActivityItem
+	HasCredentialActivity bool
+}
+
+type CredentialActivityItem struct {
+	EventTime            string
+	EventType            string
+	Actor                string
+	ActorSource          string
+	Target               string
+	CredentialKind       string
+	CredentialExternalID string
 }
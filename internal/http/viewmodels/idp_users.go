@@ -7,6 +7,7 @@ type IdPUsersViewData struct {
 	Users         []gen.Account
 	Query         string
 	State         string
+	MFA           string
 	ShowingCount  int
 	ShowingFrom   int
 	ShowingTo     int
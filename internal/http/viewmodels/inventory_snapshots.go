@@ -0,0 +1,39 @@
+package viewmodels
+
+type InventorySnapshotCredentialRow struct {
+	SourceLabel    string
+	CredentialKind string
+	ExternalID     string
+	DisplayName    string
+	Status         string
+	AssetRef       string
+	ExpiresAt      string
+	LastUsedAt     string
+}
+
+type InventorySnapshotEntitlementRow struct {
+	SourceLabel    string
+	Account        string
+	Kind           string
+	Resource       string
+	Permission     string
+	PrivilegeLevel string
+}
+
+type InventorySnapshotsViewData struct {
+	Layout       LayoutData
+	Dates        []string
+	SelectedDate string
+	SelectedKind string
+	CapturedAt   string
+	Credentials  []InventorySnapshotCredentialRow
+	Entitlements []InventorySnapshotEntitlementRow
+	HasRows      bool
+	EmptyState   string
+	Page         int
+	PerPage      int
+	TotalPages   int
+	TotalCount   int64
+	ShowingFrom  int
+	ShowingTo    int
+}
@@ -6,6 +6,7 @@ type LayoutData struct {
 	UserEmail                   string
 	UserRole                    string
 	IsAdmin                     bool
+	CanAnnotate                 bool // analyst or admin: may tag credentials and record attestations
 	FindingsRulesets            []FindingsRulesetItem
 	GoogleWorkspaceCustomerID   string
 	GoogleWorkspaceEnabled      bool
@@ -22,8 +23,20 @@ type LayoutData struct {
 	EntraTenantID               string
 	EntraEnabled                bool
 	EntraConfigured             bool
+	ConnectorFreshness          map[string]ConnectorFreshness // by connector kind; enabled, configured connectors only
 	Toast                       *ToastViewData
 	ActivePath                  string
 	CommandUsers                []DashboardCommandUserItem
 	CommandApps                 []DashboardCommandAppItem
+	Timezone                    string // the user's display zone; empty follows DefaultTimezone
+	DefaultTimezone             string
+	TimezoneOptions             []string
+}
+
+// ConnectorFreshness is the data freshness badge of a connector, from the age of its last
+// successful sync. Level is fresh, aging, stale, or never.
+type ConnectorFreshness struct {
+	Level string
+	Label string
+	Title string
 }
@@ -6,5 +6,7 @@ type LoginViewData struct {
 	Next          string
 	ErrorMessage  string
 	SetupRequired bool
-	Toast         *ToastViewData
+	// SSOEnabled shows the single sign-on button; the password form stays as the local fallback.
+	SSOEnabled bool
+	Toast      *ToastViewData
 }
@@ -0,0 +1,83 @@
+package viewmodels
+
+type PrivilegedAccessRow struct {
+	EntitlementID int64
+	SourceKind    string
+	SourceName    string
+	SourceLabel   string
+
+	Kind            string
+	Resource        string
+	ResourceLabel   string
+	ResourceHref    string
+	Permission      string
+	PrivilegeLevel  string
+	PrivilegeClass  string
+	AssignmentState string
+	LastObservedAt  string
+
+	HighPrivilege       bool
+	HighPrivilegeReason string
+
+	AppUserExternalID  string
+	AppUserEmail       string
+	AppUserDisplayName string
+
+	IdentityID    int64
+	IdentityHref  string
+	IdentityLabel string
+}
+
+type PrivilegedAccessViewData struct {
+	Layout LayoutData
+
+	Levels             []string
+	MinLevel           string
+	SourceKinds        []ProgrammaticSourceOption
+	SelectedSourceKind string
+
+	OwnerCount int
+	AdminCount int
+
+	HighPrivilegeCount int
+
+	Rows          []PrivilegedAccessRow
+	HasRows       bool
+	Truncated     bool
+	EmptyStateMsg string
+}
+
+type EscalationPathStep struct {
+	Edge  string
+	Label string
+	Href  string
+}
+
+type EscalationPathRow struct {
+	SourceKind  string
+	SourceName  string
+	SourceLabel string
+
+	AppUserExternalID  string
+	AppUserEmail       string
+	AppUserDisplayName string
+
+	HeldLevel      string
+	HeldClass      string
+	ReachableLevel string
+	ReachableClass string
+	Steps          []EscalationPathStep
+	Reason         string
+}
+
+type EscalationPathsViewData struct {
+	Layout LayoutData
+
+	SourceKinds        []ProgrammaticSourceOption
+	SelectedSourceKind string
+
+	Rows          []EscalationPathRow
+	HasRows       bool
+	Truncated     bool
+	EmptyStateMsg string
+}
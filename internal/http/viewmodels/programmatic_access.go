@@ -4,6 +4,10 @@ type ProgrammaticSourceOption struct {
 	SourceKind string
 	SourceName string
 	Label      string
+	// Disabled marks a source whose connector is disabled; its rows are stale.
+	Disabled bool
+	// Freshness is the source's sync freshness badge; empty when it could not be loaded.
+	Freshness ConnectorFreshness
 }
 
 type AppAssetListItem struct {
@@ -16,16 +20,30 @@ type AppAssetListItem struct {
 	Status           string
 	OwnersCount      int
 	CredentialsCount int
-	LastSeenAt       string
+	// CredentialRisk is the highest risk level among the asset's credentials, empty without any.
+	CredentialRisk string
+	// Orphan flags an app registration or service principal whose counterpart is missing.
+	Orphan     string
+	LastSeenAt string
 }
 
 type AppAssetsViewData struct {
-	Layout             LayoutData
-	Sources            []ProgrammaticSourceOption
+	Layout  LayoutData
+	Sources []ProgrammaticSourceOption
+	// IncludeDisabled lists disabled connectors' stale data; StaleSources labels those shown.
+	IncludeDisabled    bool
+	StaleSources       []string
 	SelectedSourceKind string
 	SelectedSourceName string
 	Query              string
 	AssetKind          string
+	// AssetKindFacets counts assets per kind under the current source, owner, and query filters,
+	// ignoring the asset kind filter itself.
+	AssetKindFacets    []AppAssetKindFacet
+	Tag                string // "key" or "key=value" set by asset tag rules
+	OwnerIdentityID    int64
+	OwnerIdentityLabel string
+	Sort               string
 	Items              []AppAssetListItem
 	ShowingCount       int
 	ShowingFrom        int
@@ -36,6 +54,14 @@ type AppAssetsViewData struct {
 	TotalPages         int
 	HasItems           bool
 	EmptyStateMsg      string
+	// ResultsTruncatedMsg is set when the "All configured" view hit the merged row limit.
+	ResultsTruncatedMsg string
+}
+
+type AppAssetKindFacet struct {
+	Kind     string
+	Count    int64
+	Selected bool
 }
 
 type ProgrammaticAuditEventItem struct {
@@ -81,6 +107,10 @@ type AppAssetSummaryView struct {
 	CreatedAtSource  string
 	UpdatedAtSource  string
 	LastObservedAt   string
+	Orphan           string
+	// LinkedLabel and LinkedHref point at the paired app registration or service principal.
+	LinkedLabel string
+	LinkedHref  string
 }
 
 type AppAssetShowViewData struct {
@@ -106,35 +136,54 @@ type CredentialArtifactListItem struct {
 	AssetRefID     string
 	Status         string
 	RiskLevel      string
+	SharedService  bool
+	BroadScope     bool
 	ExpiresAt      string
 	LastUsedAt     string
+	LastUsedBucket string
 	CreatedBy      string
 	CreatedByHref  string
 	ApprovedBy     string
 	ApprovedByHref string
+	// CredentialKindLabel and CredentialKindHelp come from the connectors' credential kind glossary.
+	CredentialKindLabel string
+	CredentialKindHelp  string
 }
 
 type CredentialsViewData struct {
-	Layout             LayoutData
-	Sources            []ProgrammaticSourceOption
-	SelectedSourceKind string
-	SelectedSourceName string
-	Query              string
-	CredentialKind     string
-	Status             string
-	RiskLevel          string
-	ExpiryState        string
-	ExpiresInDays      int
-	Items              []CredentialArtifactListItem
-	ShowingCount       int
-	ShowingFrom        int
-	ShowingTo          int
-	TotalCount         int64
-	Page               int
-	PerPage            int
-	TotalPages         int
-	HasItems           bool
-	EmptyStateMsg      string
+	Layout  LayoutData
+	Sources []ProgrammaticSourceOption
+	// IncludeDisabled lists disabled connectors' stale data; StaleSources labels those shown.
+	IncludeDisabled        bool
+	StaleSources           []string
+	SelectedSourceKind     string
+	SelectedSourceName     string
+	Query                  string
+	CredentialKind         string
+	Status                 string
+	RiskLevel              string
+	ExpiryState            string
+	LastUsed               string
+	ExpiresInDays          int
+	IntroducedInDays       int
+	SharedOnly             bool
+	BroadScopeOnly         bool
+	CreatedByIdentityID    int64
+	CreatedByIdentityLabel string
+	Tag                    string
+	Sort                   string
+	Items                  []CredentialArtifactListItem
+	ShowingCount           int
+	ShowingFrom            int
+	ShowingTo              int
+	TotalCount             int64
+	Page                   int
+	PerPage                int
+	TotalPages             int
+	HasItems               bool
+	EmptyStateMsg          string
+	// ResultsTruncatedMsg is set when the "All configured" view hit the merged row limit.
+	ResultsTruncatedMsg string
 }
 
 type CredentialArtifactSummaryView struct {
@@ -151,18 +200,54 @@ type CredentialArtifactSummaryView struct {
 	CreatedAtSource    string
 	ExpiresAtSource    string
 	LastUsedAtSource   string
+	FirstSeenAt        string
 	CreatedBy          string
 	CreatedByHref      string
 	ApprovedBy         string
 	ApprovedByHref     string
 	AssetHref          string
+	// CredentialKindLabel, CredentialKindDescription, and CredentialKindRotation come from the
+	// connectors' credential kind glossary.
+	CredentialKindLabel       string
+	CredentialKindDescription string
+	CredentialKindRotation    string
+	// LastRotatedAt and RotationSummary are derived from the credential's audit events.
+	LastRotatedAt   string
+	RotationSummary string
+}
+
+// CredentialTagItem is one operator-assigned key/value tag on a credential.
+type CredentialTagItem struct {
+	Key        string
+	Value      string
+	FilterHref string
+}
+
+// CredentialRevocationTaskItem is one revocation request for a credential, either revoked
+// through the provider API or left open for manual follow-up.
+type CredentialRevocationTaskItem struct {
+	Method       string
+	Status       string
+	Reason       string
+	ErrorMessage string
+	RequestedBy  string
+	CreatedAt    string
+	ResolvedAt   string
 }
 
 type CredentialShowViewData struct {
-	Layout      LayoutData
-	Credential  CredentialArtifactSummaryView
-	ScopeJSON   string
-	AuditEvents []ProgrammaticAuditEventItem
-	RiskReasons []string
-	HasEvents   bool
+	Layout                LayoutData
+	Credential            CredentialArtifactSummaryView
+	ScopeJSON             string
+	AuditEvents           []ProgrammaticAuditEventItem
+	RiskReasons           []string
+	Tags                  []CredentialTagItem
+	RevocationTasks       []CredentialRevocationTaskItem
+	HasOpenRevocationTask bool
+	HasEvents             bool
+
+	// ScopeJSONTruncated is set when ScopeJSON is cut short; the full document of
+	// ScopeJSONSize is fetched on demand.
+	ScopeJSONTruncated bool
+	ScopeJSONSize      string
 }
@@ -11,9 +11,10 @@ type ResourceAccessRow struct {
 	AppUserEmail       string
 	AppUserDisplayName string
 
-	EntitlementKind       string
-	EntitlementPermission string
-	LinkReason            string
+	EntitlementKind            string
+	EntitlementPermission      string
+	EntitlementAssignmentState string
+	LinkReason                 string
 }
 
 type ResourceShowViewData struct {
@@ -0,0 +1,71 @@
+package viewmodels
+
+type SyncRunFilterOption struct {
+	Label    string
+	Href     string
+	Selected bool
+}
+
+type SyncRunCount struct {
+	Name  string
+	Value int64
+}
+
+type SyncRunWarningItem struct {
+	Source  string
+	Stage   string
+	Message string
+	At      string
+}
+
+type SyncRunListItem struct {
+	ID           int64
+	Href         string
+	SourceLabel  string
+	Mode         string
+	Status       string
+	StatusClass  string
+	ErrorKind    string
+	Message      string
+	StartedAt    string
+	FinishedAt   string
+	Duration     string
+	Counts       []SyncRunCount
+	MoreCounts   int
+	WarningCount int
+	APIRequests  int64
+}
+
+type SyncRunsViewData struct {
+	Layout      LayoutData
+	Items       []SyncRunListItem
+	HasItems    bool
+	Sources     []SyncRunFilterOption
+	Outcomes    []SyncRunFilterOption
+	SourceKind  string
+	SourceName  string
+	Outcome     string
+	Retention   int
+	Page        int
+	PerPage     int
+	TotalPages  int
+	TotalCount  int64
+	ShowingFrom int
+	ShowingTo   int
+}
+
+// SyncRunAPICalls breaks down the provider API requests of a run. Hosts are busiest first.
+type SyncRunAPICalls struct {
+	BytesSent     string
+	BytesReceived string
+	Hosts         []SyncRunCount
+	Errors        []SyncRunCount
+}
+
+type SyncRunShowViewData struct {
+	Layout   LayoutData
+	Run      SyncRunListItem
+	Counts   []SyncRunCount
+	APICalls SyncRunAPICalls
+	Warnings []SyncRunWarningItem
+}
@@ -949,7 +949,7 @@ func FindingsRulePage(data viewmodels.FindingsRuleViewData) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			if data.Layout.IsAdmin {
+			if data.Layout.CanAnnotate {
 				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 98, "<form method=\"post\" action=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -1032,7 +1032,7 @@ func FindingsRulePage(data viewmodels.FindingsRuleViewData) templ.Component {
 					return templ_7745c5c3_Err
 				}
 			} else {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 109, "<div class=\"space-y-2 text-sm text-muted-foreground\"><div>Attestations can only be changed by an admin.</div>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 109, "<div class=\"space-y-2 text-sm text-muted-foreground\"><div>Attestations can only be changed by an analyst or admin.</div>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
@@ -33,7 +33,7 @@ func IdentityManagedTextClass(managed bool) string {
 	return "text-sm font-medium text-amber-700 dark:text-amber-300"
 }
 
-func AppAssetsListURL(sourceKind, sourceName, query, assetKind string, page int) string {
+func AppAssetsListURL(sourceKind, sourceName, query, assetKind string, ownerIdentityID int64, tag, sortKey string, page int) string {
 	values := url.Values{}
 	if sourceKind = strings.TrimSpace(sourceKind); sourceKind != "" {
 		values.Set("source_kind", sourceKind)
@@ -47,6 +47,15 @@ func AppAssetsListURL(sourceKind, sourceName, query, assetKind string, page int)
 	if assetKind = strings.TrimSpace(assetKind); assetKind != "" {
 		values.Set("asset_kind", assetKind)
 	}
+	if ownerIdentityID > 0 {
+		values.Set("owner_identity", strconv.FormatInt(ownerIdentityID, 10))
+	}
+	if tag = strings.TrimSpace(tag); tag != "" {
+		values.Set("tag", tag)
+	}
+	if sortKey = strings.TrimSpace(sortKey); sortKey != "" {
+		values.Set("sort", sortKey)
+	}
 	if page > 1 {
 		values.Set("page", strconv.Itoa(page))
 	}
@@ -66,7 +75,7 @@ func AppDetailURL(integratedHref, externalID string) string {
 	return "/apps"
 }
 
-func CredentialsListURL(sourceKind, sourceName, query, credentialKind, status, riskLevel, expiryState string, expiresInDays int, page int) string {
+func CredentialsListURL(sourceKind, sourceName, query, credentialKind, status, riskLevel, expiryState, lastUsed string, expiresInDays, introducedInDays int, sharedOnly, broadScopeOnly bool, createdByIdentityID int64, tag, sortKey string, page int) string {
 	values := url.Values{}
 	if sourceKind = strings.TrimSpace(sourceKind); sourceKind != "" {
 		values.Set("source_kind", sourceKind)
@@ -89,9 +98,30 @@ func CredentialsListURL(sourceKind, sourceName, query, credentialKind, status, r
 	if expiryState = strings.TrimSpace(expiryState); expiryState != "" {
 		values.Set("expiry_state", expiryState)
 	}
+	if lastUsed = strings.TrimSpace(lastUsed); lastUsed != "" {
+		values.Set("last_used", lastUsed)
+	}
 	if expiresInDays > 0 {
 		values.Set("expires_in_days", strconv.Itoa(expiresInDays))
 	}
+	if introducedInDays > 0 {
+		values.Set("introduced_in_days", strconv.Itoa(introducedInDays))
+	}
+	if sharedOnly {
+		values.Set("shared", "1")
+	}
+	if broadScopeOnly {
+		values.Set("broad_scope", "1")
+	}
+	if createdByIdentityID > 0 {
+		values.Set("created_by", strconv.FormatInt(createdByIdentityID, 10))
+	}
+	if tag = strings.TrimSpace(tag); tag != "" {
+		values.Set("tag", tag)
+	}
+	if sortKey = strings.TrimSpace(sortKey); sortKey != "" {
+		values.Set("sort", sortKey)
+	}
 	if page > 1 {
 		values.Set("page", strconv.Itoa(page))
 	}
@@ -101,6 +131,112 @@ func CredentialsListURL(sourceKind, sourceName, query, credentialKind, status, r
 	return "/credentials?" + values.Encode()
 }
 
+func EntitlementChangesURL(sourceKind, sourceName string) string {
+	values := url.Values{}
+	if sourceKind = strings.TrimSpace(sourceKind); sourceKind != "" {
+		values.Set("source_kind", sourceKind)
+	}
+	if sourceName = strings.TrimSpace(sourceName); sourceName != "" {
+		values.Set("source_name", sourceName)
+	}
+	if len(values) == 0 {
+		return "/entitlement-changes"
+	}
+	return "/entitlement-changes?" + values.Encode()
+}
+
+func PrivilegedAccessURL(minLevel, sourceKind, format string) string {
+	values := url.Values{}
+	if minLevel = strings.TrimSpace(minLevel); minLevel != "" {
+		values.Set("min_level", minLevel)
+	}
+	if sourceKind = strings.TrimSpace(sourceKind); sourceKind != "" {
+		values.Set("source_kind", sourceKind)
+	}
+	if format = strings.TrimSpace(format); format != "" {
+		values.Set("format", format)
+	}
+	if len(values) == 0 {
+		return "/privileged-access"
+	}
+	return "/privileged-access?" + values.Encode()
+}
+
+func EscalationPathsURL(sourceKind, format string) string {
+	values := url.Values{}
+	if sourceKind = strings.TrimSpace(sourceKind); sourceKind != "" {
+		values.Set("source_kind", sourceKind)
+	}
+	if format = strings.TrimSpace(format); format != "" {
+		values.Set("format", format)
+	}
+	if len(values) == 0 {
+		return "/privileged-access/escalations"
+	}
+	return "/privileged-access/escalations?" + values.Encode()
+}
+
+func AuditLogURL(action, actor string, page int) string {
+	values := url.Values{}
+	if action = strings.TrimSpace(action); action != "" {
+		values.Set("action", action)
+	}
+	if actor = strings.TrimSpace(actor); actor != "" {
+		values.Set("actor", actor)
+	}
+	if page > 1 {
+		values.Set("page", strconv.Itoa(page))
+	}
+	if len(values) == 0 {
+		return "/settings/audit-log"
+	}
+	return "/settings/audit-log?" + values.Encode()
+}
+
+func InventorySnapshotsURL(date, kind string, page int) string {
+	values := url.Values{}
+	if date = strings.TrimSpace(date); date != "" {
+		values.Set("date", date)
+	}
+	if kind = strings.TrimSpace(kind); kind != "" {
+		values.Set("kind", kind)
+	}
+	if page > 1 {
+		values.Set("page", strconv.Itoa(page))
+	}
+	if len(values) == 0 {
+		return "/inventory-snapshots"
+	}
+	return "/inventory-snapshots?" + values.Encode()
+}
+
+func SyncRunsURL(sourceKind, sourceName, outcome string, page int) string {
+	values := url.Values{}
+	if sourceKind = strings.TrimSpace(sourceKind); sourceKind != "" {
+		values.Set("source_kind", sourceKind)
+	}
+	if sourceName = strings.TrimSpace(sourceName); sourceName != "" {
+		values.Set("source_name", sourceName)
+	}
+	if outcome = strings.TrimSpace(outcome); outcome != "" {
+		values.Set("outcome", outcome)
+	}
+	if page > 1 {
+		values.Set("page", strconv.Itoa(page))
+	}
+	if len(values) == 0 {
+		return "/settings/sync-runs"
+	}
+	return "/settings/sync-runs?" + values.Encode()
+}
+
+func LoginOIDCURL(next string) string {
+	if next = strings.TrimSpace(next); next != "" {
+		return "/login/oidc?" + url.Values{"next": {next}}.Encode()
+	}
+	return "/login/oidc"
+}
+
 func HumanizeProgrammaticKind(kind string) string {
 	kind = strings.TrimSpace(kind)
 	if kind == "" {
@@ -122,6 +258,25 @@ func HumanizeProgrammaticKind(kind string) string {
 	return strings.Join(parts, " ")
 }
 
+// DefaultPerPage is the list page size when per_page is absent or not one of PerPageOptions,
+// the sizes a list page offers.
+const DefaultPerPage = 20
+
+var PerPageOptions = []int{20, 50, 100}
+
+// WithPerPage adds per_page to href unless it is the default size, so links built by the list
+// URL helpers keep the chosen page size.
+func WithPerPage(href string, perPage int) string {
+	if perPage <= 0 || perPage == DefaultPerPage {
+		return href
+	}
+	sep := "?"
+	if strings.Contains(href, "?") {
+		sep = "&"
+	}
+	return href + sep + "per_page=" + strconv.Itoa(perPage)
+}
+
 func ListURL(baseHref string, query string, state string, page int) string {
 	query = strings.TrimSpace(query)
 	state = strings.TrimSpace(state)
@@ -142,6 +297,45 @@ func ListURL(baseHref string, query string, state string, page int) string {
 	return baseHref + "?" + values.Encode()
 }
 
+// IdPUsersListURL builds the Okta accounts list URL, keeping the state and MFA filters.
+func IdPUsersListURL(query, state, mfa string, page int) string {
+	values := url.Values{}
+	if query = strings.TrimSpace(query); query != "" {
+		values.Set("q", query)
+	}
+	if state = strings.TrimSpace(state); state != "" {
+		values.Set("state", state)
+	}
+	if mfa = strings.TrimSpace(mfa); mfa != "" {
+		values.Set("mfa", mfa)
+	}
+	if page > 1 {
+		values.Set("page", strconv.Itoa(page))
+	}
+	if len(values) == 0 {
+		return "/idp-users"
+	}
+	return "/idp-users?" + values.Encode()
+}
+
+// EntraUsersListURL builds the Entra users list URL, keeping the member/guest filter.
+func EntraUsersListURL(query, userType string, page int) string {
+	values := url.Values{}
+	if query = strings.TrimSpace(query); query != "" {
+		values.Set("q", query)
+	}
+	if userType = strings.TrimSpace(userType); userType != "" {
+		values.Set("user_type", userType)
+	}
+	if page > 1 {
+		values.Set("page", strconv.Itoa(page))
+	}
+	if len(values) == 0 {
+		return "/entra-users"
+	}
+	return "/entra-users?" + values.Encode()
+}
+
 func IdentitiesListURL(sourceKind, sourceName, query, identityType, managedState string, privilegedOnly bool, status, activityState, linkQuality, sortBy, sortDir string, showFirstSeen, showLinkQuality, showLinkReason bool, page int) string {
 	values := url.Values{}
 	if sourceKind = strings.TrimSpace(sourceKind); sourceKind != "" {
@@ -195,7 +389,7 @@ func IdentitiesListURL(sourceKind, sourceName, query, identityType, managedState
 	return "/identities?" + values.Encode()
 }
 
-func DiscoveryAppsListURL(sourceKind, sourceName, query, managedState, riskLevel string, page int) string {
+func DiscoveryAppsListURL(sourceKind, sourceName, query, managedState, riskLevel, category string, page int) string {
 	values := url.Values{}
 	if sourceKind = strings.TrimSpace(sourceKind); sourceKind != "" {
 		values.Set("source_kind", sourceKind)
@@ -212,6 +406,9 @@ func DiscoveryAppsListURL(sourceKind, sourceName, query, managedState, riskLevel
 	if riskLevel = strings.TrimSpace(riskLevel); riskLevel != "" {
 		values.Set("risk_level", riskLevel)
 	}
+	if category = strings.TrimSpace(category); category != "" {
+		values.Set("category", category)
+	}
 	if page > 1 {
 		values.Set("page", strconv.Itoa(page))
 	}
@@ -261,6 +458,64 @@ func CredentialRiskBadgeClass(risk string) string {
 	}
 }
 
+// MFALevelBadgeClass colors an Okta user's MFA level: no MFA and weak-only MFA stand out.
+func MFALevelBadgeClass(level string) string {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "strong":
+		return "badge bg-emerald-100 text-emerald-800 dark:bg-emerald-900/50 dark:text-emerald-100"
+	case "weak":
+		return "badge bg-amber-100 text-amber-800 dark:bg-amber-900/50 dark:text-amber-100"
+	case "none":
+		return "badge bg-rose-100 text-rose-800 dark:bg-rose-900/50 dark:text-rose-100"
+	default:
+		return "badge-outline"
+	}
+}
+
+func MFALevelLabel(level string) string {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "strong":
+		return "Strong MFA"
+	case "weak":
+		return "Weak MFA only"
+	case "none":
+		return "No MFA"
+	default:
+		return "Unknown"
+	}
+}
+
+// CredentialLastUsedBadgeClass colors a credential last-used recency bucket; long-dormant and
+// never-used credentials stand out as revocation candidates.
+func CredentialLastUsedBadgeClass(bucket string) string {
+	switch strings.ToLower(strings.TrimSpace(bucket)) {
+	case "used_30d":
+		return "badge bg-emerald-100 text-emerald-800 dark:bg-emerald-900/50 dark:text-emerald-100"
+	case "unused_90d":
+		return "badge bg-amber-100 text-amber-800 dark:bg-amber-900/50 dark:text-amber-100"
+	case "unused_180d", "never":
+		return "badge bg-rose-100 text-rose-800 dark:bg-rose-900/50 dark:text-rose-100"
+	default:
+		return "badge-outline"
+	}
+}
+
+// ConnectorFreshnessDotClass colors a connector freshness level: green while fresh, amber
+// while aging, and red once stale or when the connector never synced.
+func ConnectorFreshnessDotClass(level string) string {
+	base := "ml-auto size-2 shrink-0 rounded-full "
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "fresh":
+		return base + "bg-emerald-500"
+	case "aging":
+		return base + "bg-amber-500"
+	case "stale", "never":
+		return base + "bg-rose-500"
+	default:
+		return base + "bg-slate-400"
+	}
+}
+
 func DiscoveryManagedBadgeClass(state string) string {
 	switch strings.ToLower(strings.TrimSpace(state)) {
 	case "managed":
@@ -283,6 +538,17 @@ func HumanizeDiscoveryManagedState(state string) string {
 	}
 }
 
+func HumanizeDiscoveryOAuthAnomalyReason(reason string) string {
+	switch strings.ToLower(strings.TrimSpace(reason)) {
+	case "grant_burst":
+		return "Grant burst"
+	case "high_risk_scopes_on_first_sight":
+		return "High-risk scopes on first grant"
+	default:
+		return fallbackHumanized(reason)
+	}
+}
+
 func HumanizeDiscoveryManagedReason(reason string) string {
 	switch strings.ToLower(strings.TrimSpace(reason)) {
 	case "active_binding_fresh_sync":
@@ -364,6 +630,40 @@ func HumanizeCredentialRisk(risk string) string {
 	}
 }
 
+func HumanizeCredentialLastUsed(bucket string) string {
+	switch strings.ToLower(strings.TrimSpace(bucket)) {
+	case "used_30d":
+		return "Used ≤ 30d"
+	case "idle":
+		return "Idle 30–90d"
+	case "unused_90d":
+		return "Unused > 90d"
+	case "unused_180d":
+		return "Unused > 180d"
+	case "never":
+		return "Never used"
+	default:
+		return "—"
+	}
+}
+
+// HumanizeAssignmentState labels how an entitlement is held; it is empty for sources that do not
+// distinguish standing from just-in-time access.
+func HumanizeAssignmentState(state string) string {
+	switch strings.ToLower(strings.TrimSpace(state)) {
+	case "permanent":
+		return "Permanent"
+	case "active":
+		return "Active (time-bound)"
+	case "eligible":
+		return "Eligible"
+	case "unknown":
+		return "Unknown"
+	default:
+		return strings.TrimSpace(state)
+	}
+}
+
 func HumanizeIdentityType(identityType string) string {
 	switch strings.ToLower(strings.TrimSpace(identityType)) {
 	case "human":
@@ -497,6 +797,20 @@ func IdentityLinkQualityBadgeClass(linkQuality string) string {
 	}
 }
 
+// HumanizeAppAssetOrphan labels an app asset orphan flag, or returns "" when the asset has none.
+func HumanizeAppAssetOrphan(orphan string) string {
+	switch strings.ToLower(strings.TrimSpace(orphan)) {
+	case "":
+		return ""
+	case "application_without_service_principal":
+		return "No service principal"
+	case "service_principal_without_application":
+		return "No app registration"
+	default:
+		return HumanizeProgrammaticKind(orphan)
+	}
+}
+
 func HumanizeCredentialKind(kind string) string {
 	switch strings.ToLower(strings.TrimSpace(kind)) {
 	case "entra_client_secret":
@@ -509,6 +823,10 @@ func HumanizeCredentialKind(kind string) string {
 		return "GitHub PAT request"
 	case "github_pat_fine_grained":
 		return "GitHub fine-grained PAT"
+	case "github_secret_scanning_alert":
+		return "GitHub secret scanning alert"
+	case "okta_oidc_client_secret":
+		return "Okta OIDC client secret"
 	default:
 		return fallbackHumanized(kind)
 	}
@@ -664,6 +982,8 @@ func HumanizeAuthUserRole(role string) string {
 	switch strings.ToLower(strings.TrimSpace(role)) {
 	case "admin":
 		return "Admin"
+	case "analyst":
+		return "Analyst"
 	case "viewer":
 		return "Viewer"
 	default:
@@ -679,6 +999,8 @@ func AuthUserRoleBadgeClass(role string) string {
 	switch strings.ToLower(strings.TrimSpace(role)) {
 	case "admin":
 		return "badge bg-sky-100 text-sky-800 dark:bg-sky-900/50 dark:text-sky-100"
+	case "analyst":
+		return "badge bg-violet-100 text-violet-800 dark:bg-violet-900/50 dark:text-violet-100"
 	case "viewer":
 		return "badge bg-slate-100 text-slate-800 dark:bg-slate-900/50 dark:text-slate-100"
 	default:
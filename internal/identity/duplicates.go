@@ -0,0 +1,80 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/matching"
+)
+
+type duplicateQueryRunner interface {
+	ListIdentityDuplicateCandidates(context.Context) ([]gen.ListIdentityDuplicateCandidatesRow, error)
+	UpsertIdentityMergeSuggestion(context.Context, gen.UpsertIdentityMergeSuggestionParams) error
+	DeleteStaleIdentityMergeSuggestions(context.Context, gen.DeleteStaleIdentityMergeSuggestionsParams) (int64, error)
+}
+
+// DetectDuplicates refreshes the open merge suggestions from the current identity graph. New
+// pairs are suggested, scores of open ones are updated, and open suggestions that no longer
+// score above the threshold are removed. Dismissed pairs are left alone. It returns the number of
+// open suggestions.
+func DetectDuplicates(ctx context.Context, q duplicateQueryRunner) (int64, error) {
+	if q == nil {
+		return 0, errors.New("identity duplicate query runner is nil")
+	}
+
+	rows, err := q.ListIdentityDuplicateCandidates(ctx)
+	if err != nil {
+		return 0, err
+	}
+	suggestions := matching.FindDuplicateIdentities(duplicateRecords(rows))
+
+	lows := make([]int64, 0, len(suggestions))
+	highs := make([]int64, 0, len(suggestions))
+	for _, s := range suggestions {
+		if err := q.UpsertIdentityMergeSuggestion(ctx, gen.UpsertIdentityMergeSuggestionParams{
+			SurvivorIdentityID:  s.SurvivorID,
+			DuplicateIdentityID: s.DuplicateID,
+			Score:               float32(s.Score),
+			Reasons:             s.Reasons,
+		}); err != nil {
+			return 0, err
+		}
+		lows = append(lows, min(s.SurvivorID, s.DuplicateID))
+		highs = append(highs, max(s.SurvivorID, s.DuplicateID))
+	}
+
+	if _, err := q.DeleteStaleIdentityMergeSuggestions(ctx, gen.DeleteStaleIdentityMergeSuggestionsParams{
+		LowIdentityIds:  lows,
+		HighIdentityIds: highs,
+	}); err != nil {
+		return 0, err
+	}
+	return int64(len(suggestions)), nil
+}
+
+// duplicateRecords folds the per-account candidate rows (ordered by identity) into one record per
+// identity.
+func duplicateRecords(rows []gen.ListIdentityDuplicateCandidatesRow) []matching.IdentityRecord {
+	var out []matching.IdentityRecord
+	for _, row := range rows {
+		if len(out) == 0 || out[len(out)-1].ID != row.IdentityID {
+			record := matching.IdentityRecord{ID: row.IdentityID, DisplayName: strings.TrimSpace(row.DisplayName)}
+			if email := strings.TrimSpace(row.PrimaryEmail); email != "" {
+				record.Emails = append(record.Emails, email)
+			}
+			out = append(out, record)
+		}
+		record := &out[len(out)-1]
+		record.Accounts++
+		record.Authoritative = record.Authoritative || row.IsAuthoritative
+		if email := strings.TrimSpace(row.AccountEmail); email != "" {
+			record.Emails = append(record.Emails, email)
+		}
+		if handle := strings.TrimSpace(row.ExternalID); handle != "" && !strings.Contains(handle, "@") {
+			record.Handles = append(record.Handles, handle)
+		}
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package identity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+type duplicateStub struct {
+	rows     []gen.ListIdentityDuplicateCandidatesRow
+	upserted []gen.UpsertIdentityMergeSuggestionParams
+	kept     gen.DeleteStaleIdentityMergeSuggestionsParams
+}
+
+func (s *duplicateStub) ListIdentityDuplicateCandidates(context.Context) ([]gen.ListIdentityDuplicateCandidatesRow, error) {
+	return s.rows, nil
+}
+
+func (s *duplicateStub) UpsertIdentityMergeSuggestion(_ context.Context, arg gen.UpsertIdentityMergeSuggestionParams) error {
+	s.upserted = append(s.upserted, arg)
+	return nil
+}
+
+func (s *duplicateStub) DeleteStaleIdentityMergeSuggestions(_ context.Context, arg gen.DeleteStaleIdentityMergeSuggestionsParams) (int64, error) {
+	s.kept = arg
+	return 0, nil
+}
+
+func TestDetectDuplicatesSuggestsAndKeepsOnlyCurrentPairs(t *testing.T) {
+	stub := &duplicateStub{rows: []gen.ListIdentityDuplicateCandidatesRow{
+		{IdentityID: 3, DisplayName: "Jane Doe", PrimaryEmail: "jane.doe@acme.com", ExternalID: "00u1", AccountEmail: "jane.doe@acme.com", IsAuthoritative: true},
+		{IdentityID: 3, DisplayName: "Jane Doe", PrimaryEmail: "jane.doe@acme.com", ExternalID: "jane-doe", AccountEmail: ""},
+		{IdentityID: 9, DisplayName: "Jane Doe", PrimaryEmail: "jdoe@contractor.io", ExternalID: "jdoe@contractor.io", AccountEmail: "jdoe@contractor.io"},
+		{IdentityID: 12, DisplayName: "Sam Lee", PrimaryEmail: "sam@acme.com", ExternalID: "sam@acme.com", AccountEmail: "sam@acme.com"},
+	}}
+
+	count, err := DetectDuplicates(context.Background(), stub)
+	if err != nil {
+		t.Fatalf("DetectDuplicates() error = %v", err)
+	}
+	if count != 1 || len(stub.upserted) != 1 {
+		t.Fatalf("count = %d, upserted = %+v, want one suggestion", count, stub.upserted)
+	}
+	if got := stub.upserted[0]; got.SurvivorIdentityID != 3 || got.DuplicateIdentityID != 9 {
+		t.Fatalf("survivor/duplicate = %d/%d, want 3/9", got.SurvivorIdentityID, got.DuplicateIdentityID)
+	}
+	if len(stub.kept.LowIdentityIds) != 1 || stub.kept.LowIdentityIds[0] != 3 || stub.kept.HighIdentityIds[0] != 9 {
+		t.Fatalf("kept pairs = %+v, want only 3/9", stub.kept)
+	}
+}
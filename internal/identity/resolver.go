@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/open-sspm/open-sspm/internal/connectors/registry"
 	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/matching"
 )
 
 const (
@@ -20,6 +21,8 @@ type queryRunner interface {
 	CountUnlinkedAccounts(context.Context) (int64, error)
 	ListUnlinkedAccountsPage(context.Context, gen.ListUnlinkedAccountsPageParams) ([]gen.Account, error)
 	GetPreferredIdentityByPrimaryEmail(context.Context, string) (gen.Identity, error)
+	GetPreferredIdentityBySecondaryEmail(context.Context, string) (gen.Identity, error)
+	ListAccountSecondaryEmails(context.Context, int64) ([]string, error)
 	CreateIdentity(context.Context, gen.CreateIdentityParams) (gen.Identity, error)
 	UpsertIdentityAccountLink(context.Context, gen.UpsertIdentityAccountLinkParams) (gen.IdentityAccount, error)
 	GetIdentityAccountLinkByAccountID(context.Context, int64) (gen.IdentityAccount, error)
@@ -38,11 +41,17 @@ type Stats struct {
 	AutoLinked       int64
 	AutoCreatedLinks int64
 	UpdatedIdentites int64
+	MergeSuggestions int64
 }
 
 func Resolve(ctx context.Context, q *gen.Queries) (Stats, error) {
 	r := Resolver{Q: q}
-	return r.Resolve(ctx)
+	out, err := r.Resolve(ctx)
+	if err != nil {
+		return out, err
+	}
+	out.MergeSuggestions, err = DetectDuplicates(ctx, q)
+	return out, err
 }
 
 func (r Resolver) Resolve(ctx context.Context) (Stats, error) {
@@ -118,16 +127,16 @@ func (r Resolver) resolveIdentityIDForAccount(ctx context.Context, account gen.A
 		return 0, "", false, err
 	}
 
-	email := normalizeEmail(account.Email)
+	email := matching.NormalizeEmail(account.Email)
 	accountKind := registry.NormalizeAccountKind(account.AccountKind)
-	if email != "" && accountKind != registry.AccountKindService && accountKind != registry.AccountKindBot {
-		identity, findErr := r.Q.GetPreferredIdentityByPrimaryEmail(ctx, email)
-		if findErr == nil {
-			return identity.ID, linkReasonAutoEmail, false, nil
-		}
-		if !errors.Is(findErr, pgx.ErrNoRows) {
+	if accountKind != registry.AccountKindService && accountKind != registry.AccountKindBot {
+		identityID, found, findErr := r.findIdentityByEmails(ctx, account, email)
+		if findErr != nil {
 			return 0, "", false, findErr
 		}
+		if found {
+			return identityID, linkReasonAutoEmail, false, nil
+		}
 	}
 
 	identity, err := r.Q.CreateIdentity(ctx, gen.CreateIdentityParams{
@@ -141,6 +150,45 @@ func (r Resolver) resolveIdentityIDForAccount(ctx context.Context, account gen.A
 	return identity.ID, linkReasonAutoCreate, true, nil
 }
 
+// findIdentityByEmails matches an account to an identity by email, trying in order: its email
+// as an identity's primary email, its email as a secondary email of an account already linked
+// to an identity, and then each of its own secondary emails as an identity's primary email.
+func (r Resolver) findIdentityByEmails(ctx context.Context, account gen.Account, email string) (int64, bool, error) {
+	if email != "" {
+		for _, find := range []func(context.Context, string) (gen.Identity, error){
+			r.Q.GetPreferredIdentityByPrimaryEmail,
+			r.Q.GetPreferredIdentityBySecondaryEmail,
+		} {
+			identity, err := find(ctx, email)
+			if err == nil {
+				return identity.ID, true, nil
+			}
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return 0, false, err
+			}
+		}
+	}
+
+	secondaryEmails, err := r.Q.ListAccountSecondaryEmails(ctx, account.ID)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, secondary := range secondaryEmails {
+		secondary = matching.NormalizeEmail(secondary)
+		if secondary == "" || secondary == email {
+			continue
+		}
+		identity, err := r.Q.GetPreferredIdentityByPrimaryEmail(ctx, secondary)
+		if err == nil {
+			return identity.ID, true, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, err
+		}
+	}
+	return 0, false, nil
+}
+
 func (r Resolver) refreshIdentityAttributes(ctx context.Context) (int64, error) {
 	sources, err := r.Q.ListAuthoritativeSources(ctx)
 	if err != nil {
@@ -200,8 +248,8 @@ func chooseIdentityAttributes(candidates []gen.ListIdentityAccountAttributesRow,
 		if leftAuth != rightAuth {
 			return leftAuth
 		}
-		leftEmail := normalizeEmail(left.Email)
-		rightEmail := normalizeEmail(right.Email)
+		leftEmail := matching.NormalizeEmail(left.Email)
+		rightEmail := matching.NormalizeEmail(right.Email)
 		if (leftEmail != "") != (rightEmail != "") {
 			return leftEmail != ""
 		}
@@ -210,7 +258,7 @@ func chooseIdentityAttributes(candidates []gen.ListIdentityAccountAttributesRow,
 
 	for _, candidate := range sorted {
 		if email == "" {
-			email = normalizeEmail(candidate.Email)
+			email = matching.NormalizeEmail(candidate.Email)
 		}
 		if displayName == "" {
 			displayName = strings.TrimSpace(candidate.DisplayName)
@@ -225,7 +273,7 @@ func chooseIdentityAttributes(candidates []gen.ListIdentityAccountAttributesRow,
 
 func firstNonEmptyEmail(candidates []gen.ListIdentityAccountAttributesRow) (string, bool) {
 	for _, candidate := range candidates {
-		email := normalizeEmail(candidate.Email)
+		email := matching.NormalizeEmail(candidate.Email)
 		if email != "" {
 			return email, true
 		}
@@ -254,7 +302,7 @@ func chooseIdentityKindByHeuristic(candidates []gen.ListIdentityAccountAttribute
 	hasEmail := false
 	hasServiceSignal := false
 	for _, candidate := range candidates {
-		email := normalizeEmail(candidate.Email)
+		email := matching.NormalizeEmail(candidate.Email)
 		if email != "" {
 			hasEmail = true
 		}
@@ -296,7 +344,3 @@ func buildIdentityClassifierText(candidate gen.ListIdentityAccountAttributesRow,
 func sourceKey(kind, name string) string {
 	return strings.ToLower(strings.TrimSpace(kind)) + "::" + strings.ToLower(strings.TrimSpace(name))
 }
-
-func normalizeEmail(email string) string {
-	return strings.ToLower(strings.TrimSpace(email))
-}
@@ -2,6 +2,7 @@ package identity
 
 import (
 	"context"
+	"slices"
 	"sort"
 	"strings"
 	"testing"
@@ -16,6 +17,8 @@ type resolverStub struct {
 	identities     map[int64]gen.Identity
 	linksByAccount map[int64]gen.IdentityAccount
 	sources        []gen.IdentitySourceSetting
+	// secondaryEmails are the account_emails rows, keyed by account ID.
+	secondaryEmails map[int64][]string
 
 	nextIdentityID int64
 	nextLinkID     int64
@@ -23,11 +26,12 @@ type resolverStub struct {
 
 func newResolverStub() *resolverStub {
 	return &resolverStub{
-		accounts:       make(map[int64]gen.Account),
-		identities:     make(map[int64]gen.Identity),
-		linksByAccount: make(map[int64]gen.IdentityAccount),
-		nextIdentityID: 1,
-		nextLinkID:     1,
+		accounts:        make(map[int64]gen.Account),
+		identities:      make(map[int64]gen.Identity),
+		linksByAccount:  make(map[int64]gen.IdentityAccount),
+		secondaryEmails: make(map[int64][]string),
+		nextIdentityID:  1,
+		nextLinkID:      1,
 	}
 }
 
@@ -128,6 +132,28 @@ func (s *resolverStub) GetPreferredIdentityByPrimaryEmail(_ context.Context, ema
 	return candidates[0], nil
 }
 
+func (s *resolverStub) GetPreferredIdentityBySecondaryEmail(_ context.Context, email string) (gen.Identity, error) {
+	target := normalizeTestEmail(email)
+	var found *gen.Identity
+	for accountID, link := range s.linksByAccount {
+		account, ok := s.accounts[accountID]
+		if !ok || !isActiveAccount(account) || !slices.Contains(s.secondaryEmails[accountID], target) {
+			continue
+		}
+		if identity, ok := s.identities[link.IdentityID]; ok && (found == nil || identity.ID < found.ID) {
+			found = &identity
+		}
+	}
+	if found == nil {
+		return gen.Identity{}, pgx.ErrNoRows
+	}
+	return *found, nil
+}
+
+func (s *resolverStub) ListAccountSecondaryEmails(_ context.Context, accountID int64) ([]string, error) {
+	return s.secondaryEmails[accountID], nil
+}
+
 func (s *resolverStub) CreateIdentity(_ context.Context, params gen.CreateIdentityParams) (gen.Identity, error) {
 	row := gen.Identity{
 		ID:           s.nextIdentityID,
@@ -275,6 +301,37 @@ func TestResolverResolveExactEmailLink(t *testing.T) {
 	}
 }
 
+func TestResolverResolveLinksBySecondaryEmail(t *testing.T) {
+	t.Parallel()
+
+	stub := newResolverStub()
+	stub.putIdentity(gen.Identity{ID: 1, PrimaryEmail: "alice@corp.example.com", DisplayName: "Alice"})
+	stub.accounts[10] = makeActiveAccount(10, "entra", "tenant", "alice@corp.example.com", "Alice")
+	stub.secondaryEmails[10] = []string{"alice.dev@example.net"}
+	stub.putLink(gen.IdentityAccount{ID: 1, IdentityID: 1, AccountID: 10, LinkReason: linkReasonAutoEmail})
+	stub.putIdentity(gen.Identity{ID: 2, PrimaryEmail: "bob@corp.example.com", DisplayName: "Bob"})
+
+	// The GitHub account's email is only known to the IdP as one of Alice's other mails.
+	stub.accounts[20] = makeActiveAccount(20, "github", "acme", "Alice.Dev@example.net", "alice-dev")
+	// The Okta account's own alias is Bob's primary email.
+	stub.accounts[30] = makeActiveAccount(30, "okta", "acme", "robert@example.org", "Robert")
+	stub.secondaryEmails[30] = []string{"bob@corp.example.com"}
+
+	stats, err := Resolver{Q: stub}.Resolve(context.Background())
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if stats.AutoLinked != 2 || stats.NewIdentities != 0 {
+		t.Fatalf("stats = %+v, want 2 auto links and no new identities", stats)
+	}
+	for accountID, wantIdentityID := range map[int64]int64{20: 1, 30: 2} {
+		link := stub.linksByAccount[accountID]
+		if link.IdentityID != wantIdentityID || link.LinkReason != linkReasonAutoEmail {
+			t.Fatalf("account %d link = %+v, want identity %d via %s", accountID, link, wantIdentityID, linkReasonAutoEmail)
+		}
+	}
+}
+
 func TestResolverResolveSkipsEmailAutoLinkForNonHumanAccountKinds(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,238 @@
+package incidents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+const reconcileTimeout = time.Minute
+
+// Queries is the subset of gen.Queries used by Reconciler.
+type Queries interface {
+	ListConnectorConfigs(ctx context.Context) ([]gen.ConnectorConfig, error)
+	ListLatestSuccessfulSyncFinishedAtForSources(ctx context.Context, arg gen.ListLatestSuccessfulSyncFinishedAtForSourcesParams) ([]gen.ListLatestSuccessfulSyncFinishedAtForSourcesRow, error)
+	ListOpenConnectorIncidents(ctx context.Context) ([]gen.ConnectorIncident, error)
+	OpenConnectorIncident(ctx context.Context, arg gen.OpenConnectorIncidentParams) error
+	ResolveConnectorIncident(ctx context.Context, arg gen.ResolveConnectorIncidentParams) error
+}
+
+// Reconciler opens an incident when an enabled connector has had no successful full sync
+// within the SLA and resolves it once the connector succeeds again (or is disabled). Open
+// incidents are persisted so restarts neither re-open nor orphan them; the sink sees the same
+// dedup key for every event about a connector. The SLA and check interval come from config
+// (CONNECTOR_INCIDENT_SLA, CONNECTOR_INCIDENT_CHECK_INTERVAL), which requires both to be positive.
+type Reconciler struct {
+	q        Queries
+	registry *registry.ConnectorRegistry
+	sink     Sink
+	sla      time.Duration
+	now      func() time.Time
+}
+
+func NewReconciler(q Queries, reg *registry.ConnectorRegistry, sink Sink, sla time.Duration) *Reconciler {
+	return &Reconciler{
+		q:        q,
+		registry: reg,
+		sink:     sink,
+		sla:      sla,
+		now:      time.Now,
+	}
+}
+
+// Run reconciles immediately and then every interval until ctx is cancelled.
+func (r *Reconciler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		runCtx, cancel := context.WithTimeout(ctx, reconcileTimeout)
+		if err := r.Reconcile(runCtx); err != nil && !errors.Is(err, context.Canceled) {
+			slog.Error("connector incident reconcile failed", "err", err)
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type connectorSource struct {
+	kind string
+	name string
+	// since is when the connector was last configured; it stands in for the last success of a
+	// connector that has never synced so a fresh connector gets a full SLA window.
+	since time.Time
+}
+
+// Reconcile evaluates every enabled connector once. Sink failures leave the stored state
+// untouched so the transition is retried on the next pass.
+func (r *Reconciler) Reconcile(ctx context.Context) error {
+	if r == nil || r.q == nil || r.sink == nil {
+		return errors.New("connector incident reconciler is not configured")
+	}
+
+	sources, err := r.enabledSources(ctx)
+	if err != nil {
+		return err
+	}
+	open, err := r.q.ListOpenConnectorIncidents(ctx)
+	if err != nil {
+		return err
+	}
+	openByKey := make(map[string]gen.ConnectorIncident, len(open))
+	for _, incident := range open {
+		openByKey[sourceKey(incident.SourceKind, incident.SourceName)] = incident
+	}
+
+	lastSuccess := make(map[string]time.Time, len(sources))
+	if len(sources) > 0 {
+		kinds := make([]string, 0, len(sources))
+		names := make([]string, 0, len(sources))
+		for _, source := range sources {
+			kinds = append(kinds, source.kind)
+			names = append(names, source.name)
+		}
+		rows, err := r.q.ListLatestSuccessfulSyncFinishedAtForSources(ctx, gen.ListLatestSuccessfulSyncFinishedAtForSourcesParams{
+			SourceKinds: kinds,
+			SourceNames: names,
+		})
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if row.LastSuccessAt.Valid {
+				lastSuccess[sourceKey(row.SourceKind, row.SourceName)] = row.LastSuccessAt.Time
+			}
+		}
+	}
+
+	now := r.now()
+	var errs []error
+	for _, source := range sources {
+		key := sourceKey(source.kind, source.name)
+		last, succeeded := lastSuccess[key]
+		reference := last
+		if !succeeded {
+			reference = source.since
+		}
+		stale := now.Sub(reference) > r.sla
+		incident, isOpen := openByKey[key]
+		delete(openByKey, key)
+
+		switch {
+		case stale && !isOpen:
+			errs = append(errs, r.open(ctx, source, last, now))
+		case !stale && isOpen:
+			errs = append(errs, r.resolve(ctx, incident, "recovered", now))
+		}
+	}
+
+	// Whatever is left belongs to connectors that were disabled or removed; nothing will sync
+	// them again, so close their incidents instead of paging forever.
+	for _, incident := range openByKey {
+		errs = append(errs, r.resolve(ctx, incident, "connector disabled", now))
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Reconciler) open(ctx context.Context, source connectorSource, lastSuccess, now time.Time) error {
+	summary := fmt.Sprintf("Connector %s/%s has not synced successfully in %s", source.kind, source.name, r.sla)
+	if !lastSuccess.IsZero() {
+		summary += fmt.Sprintf(" (last success %s)", lastSuccess.UTC().Format(time.RFC3339))
+	} else {
+		summary += " (no successful sync yet)"
+	}
+	event := Event{
+		Action:        ActionTrigger,
+		DedupKey:      DedupKey(source.kind, source.name),
+		Summary:       summary,
+		SourceKind:    source.kind,
+		SourceName:    source.name,
+		LastSuccessAt: lastSuccess,
+		SLASeconds:    int64(r.sla / time.Second),
+	}
+	if err := r.sink.Send(ctx, event); err != nil {
+		return fmt.Errorf("trigger incident for %s/%s: %w", source.kind, source.name, err)
+	}
+	if err := r.q.OpenConnectorIncident(ctx, gen.OpenConnectorIncidentParams{
+		SourceKind: source.kind,
+		SourceName: source.name,
+		DedupKey:   event.DedupKey,
+		Summary:    summary,
+		OpenedAt:   pgtype.Timestamptz{Time: now, Valid: true},
+	}); err != nil {
+		return fmt.Errorf("record incident for %s/%s: %w", source.kind, source.name, err)
+	}
+	slog.Warn("connector incident opened", "kind", source.kind, "name", source.name, "dedup_key", event.DedupKey)
+	return nil
+}
+
+func (r *Reconciler) resolve(ctx context.Context, incident gen.ConnectorIncident, reason string, now time.Time) error {
+	event := Event{
+		Action:     ActionResolve,
+		DedupKey:   incident.DedupKey,
+		Summary:    fmt.Sprintf("Connector %s/%s %s", incident.SourceKind, incident.SourceName, reason),
+		SourceKind: incident.SourceKind,
+		SourceName: incident.SourceName,
+		SLASeconds: int64(r.sla / time.Second),
+	}
+	if err := r.sink.Send(ctx, event); err != nil {
+		return fmt.Errorf("resolve incident for %s/%s: %w", incident.SourceKind, incident.SourceName, err)
+	}
+	if err := r.q.ResolveConnectorIncident(ctx, gen.ResolveConnectorIncidentParams{
+		ResolvedAt: pgtype.Timestamptz{Time: now, Valid: true},
+		SourceKind: incident.SourceKind,
+		SourceName: incident.SourceName,
+	}); err != nil {
+		return fmt.Errorf("record incident resolution for %s/%s: %w", incident.SourceKind, incident.SourceName, err)
+	}
+	slog.Info("connector incident resolved", "kind", incident.SourceKind, "name", incident.SourceName, "reason", reason)
+	return nil
+}
+
+func (r *Reconciler) enabledSources(ctx context.Context) ([]connectorSource, error) {
+	configs, err := r.q.ListConnectorConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]connectorSource, 0, len(configs))
+	for _, cfgRow := range configs {
+		if !cfgRow.Enabled || r.registry == nil {
+			continue
+		}
+		kind := strings.ToLower(strings.TrimSpace(cfgRow.Kind))
+		def, ok := r.registry.Get(kind)
+		if !ok {
+			continue
+		}
+		cfg, err := def.DecodeConfig(cfgRow.Config)
+		if err != nil || !def.IsConfigured(cfg) {
+			continue
+		}
+		name := strings.TrimSpace(def.SourceName(cfg))
+		if name == "" {
+			continue
+		}
+		out = append(out, connectorSource{kind: kind, name: name, since: cfgRow.UpdatedAt.Time})
+	}
+	return out, nil
+}
+
+// DedupKey is the sink correlation key for a connector's staleness incident.
+func DedupKey(sourceKind, sourceName string) string {
+	return "open-sspm/connector-stale/" + strings.TrimSpace(sourceKind) + "/" + strings.TrimSpace(sourceName)
+}
+
+func sourceKey(kind, name string) string {
+	return strings.TrimSpace(kind) + "\x00" + strings.TrimSpace(name)
+}
@@ -0,0 +1,205 @@
+package incidents
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+type fakeDefinition struct{}
+
+func (fakeDefinition) Kind() string                              { return "github" }
+func (fakeDefinition) DisplayName() string                       { return "GitHub" }
+func (fakeDefinition) Role() registry.IntegrationRole            { return registry.RoleApp }
+func (fakeDefinition) DecodeConfig(raw []byte) (any, error)      { return string(raw), nil }
+func (fakeDefinition) ValidateConfig(cfg any) error              { return nil }
+func (fakeDefinition) IsConfigured(cfg any) bool                 { return cfg.(string) != "" }
+func (fakeDefinition) SourceName(cfg any) string                 { return cfg.(string) }
+func (fakeDefinition) DefaultSubtitle() string                   { return "" }
+func (fakeDefinition) ConfiguredSubtitle(cfg any) string         { return "" }
+func (fakeDefinition) SettingsHref() string                      { return "" }
+func (fakeDefinition) MetricsProvider() registry.MetricsProvider { return nil }
+func (fakeDefinition) NewIntegration(cfg any) (registry.Integration, error) {
+	return nil, errors.New("not syncable")
+}
+
+type fakeQueries struct {
+	configs     []gen.ConnectorConfig
+	lastSuccess map[string]time.Time
+	open        map[string]gen.ConnectorIncident
+}
+
+func (f *fakeQueries) ListConnectorConfigs(context.Context) ([]gen.ConnectorConfig, error) {
+	return f.configs, nil
+}
+
+func (f *fakeQueries) ListLatestSuccessfulSyncFinishedAtForSources(_ context.Context, arg gen.ListLatestSuccessfulSyncFinishedAtForSourcesParams) ([]gen.ListLatestSuccessfulSyncFinishedAtForSourcesRow, error) {
+	out := make([]gen.ListLatestSuccessfulSyncFinishedAtForSourcesRow, 0, len(arg.SourceKinds))
+	for i := range arg.SourceKinds {
+		row := gen.ListLatestSuccessfulSyncFinishedAtForSourcesRow{SourceKind: arg.SourceKinds[i], SourceName: arg.SourceNames[i]}
+		if t, ok := f.lastSuccess[sourceKey(arg.SourceKinds[i], arg.SourceNames[i])]; ok {
+			row.LastSuccessAt = pgtype.Timestamptz{Time: t, Valid: true}
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+func (f *fakeQueries) ListOpenConnectorIncidents(context.Context) ([]gen.ConnectorIncident, error) {
+	out := make([]gen.ConnectorIncident, 0, len(f.open))
+	for _, incident := range f.open {
+		out = append(out, incident)
+	}
+	return out, nil
+}
+
+func (f *fakeQueries) OpenConnectorIncident(_ context.Context, arg gen.OpenConnectorIncidentParams) error {
+	key := sourceKey(arg.SourceKind, arg.SourceName)
+	if _, ok := f.open[key]; ok {
+		return nil
+	}
+	f.open[key] = gen.ConnectorIncident{SourceKind: arg.SourceKind, SourceName: arg.SourceName, DedupKey: arg.DedupKey, Summary: arg.Summary, OpenedAt: arg.OpenedAt}
+	return nil
+}
+
+func (f *fakeQueries) ResolveConnectorIncident(_ context.Context, arg gen.ResolveConnectorIncidentParams) error {
+	delete(f.open, sourceKey(arg.SourceKind, arg.SourceName))
+	return nil
+}
+
+type recordingSink struct {
+	events []Event
+	err    error
+}
+
+func (s *recordingSink) Send(_ context.Context, event Event) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func newTestReconciler(t *testing.T, q *fakeQueries, sink *recordingSink, clock *time.Time) *Reconciler {
+	t.Helper()
+	reg := registry.NewRegistry()
+	if err := reg.Register(fakeDefinition{}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	r := NewReconciler(q, reg, sink, 24*time.Hour)
+	r.now = func() time.Time { return *clock }
+	return r
+}
+
+func TestReconcilerOpensOnSLABreachAndResolvesOnRecovery(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := start
+	q := &fakeQueries{
+		configs: []gen.ConnectorConfig{{
+			Kind:      "github",
+			Enabled:   true,
+			Config:    []byte("acme"),
+			UpdatedAt: pgtype.Timestamptz{Time: start.Add(-72 * time.Hour), Valid: true},
+		}},
+		lastSuccess: map[string]time.Time{sourceKey("github", "acme"): start.Add(-time.Hour)},
+		open:        map[string]gen.ConnectorIncident{},
+	}
+	sink := &recordingSink{}
+	r := newTestReconciler(t, q, sink, &clock)
+
+	if err := r.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(sink.events) != 0 {
+		t.Fatalf("fresh connector sent events %+v", sink.events)
+	}
+
+	// 23h after the last success: still within the SLA.
+	clock = start.Add(22 * time.Hour)
+	if err := r.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(sink.events) != 0 {
+		t.Fatalf("connector within SLA sent events %+v", sink.events)
+	}
+
+	clock = start.Add(24 * time.Hour)
+	if err := r.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(sink.events) != 1 || sink.events[0].Action != ActionTrigger {
+		t.Fatalf("events after breach = %+v, want one trigger", sink.events)
+	}
+	if got, want := sink.events[0].DedupKey, DedupKey("github", "acme"); got != want {
+		t.Fatalf("dedup key = %q, want %q", got, want)
+	}
+	if _, ok := q.open[sourceKey("github", "acme")]; !ok {
+		t.Fatalf("incident was not recorded as open")
+	}
+
+	// Repeated evaluations while still stale must not trigger again.
+	clock = start.Add(30 * time.Hour)
+	if err := r.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(sink.events) != 1 {
+		t.Fatalf("events after repeated evaluation = %+v, want still one", sink.events)
+	}
+
+	q.lastSuccess[sourceKey("github", "acme")] = clock.Add(-time.Minute)
+	if err := r.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(sink.events) != 2 || sink.events[1].Action != ActionResolve || sink.events[1].DedupKey != DedupKey("github", "acme") {
+		t.Fatalf("events after recovery = %+v, want trigger then resolve with the same dedup key", sink.events)
+	}
+	if len(q.open) != 0 {
+		t.Fatalf("open incidents after recovery = %+v", q.open)
+	}
+}
+
+func TestReconcilerRetriesWhenSinkFails(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	q := &fakeQueries{
+		configs: []gen.ConnectorConfig{{
+			Kind:      "github",
+			Enabled:   true,
+			Config:    []byte("acme"),
+			UpdatedAt: pgtype.Timestamptz{Time: clock.Add(-48 * time.Hour), Valid: true},
+		}},
+		open: map[string]gen.ConnectorIncident{},
+	}
+	sink := &recordingSink{err: errors.New("sink unavailable")}
+	r := newTestReconciler(t, q, sink, &clock)
+
+	if err := r.Reconcile(ctx); err == nil {
+		t.Fatalf("Reconcile() error = nil, want sink failure")
+	}
+	if len(q.open) != 0 {
+		t.Fatalf("incident recorded despite sink failure: %+v", q.open)
+	}
+
+	sink.err = nil
+	if err := r.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(sink.events) != 1 || sink.events[0].Action != ActionTrigger {
+		t.Fatalf("events after retry = %+v, want one trigger", sink.events)
+	}
+
+	// Disabling the connector closes its incident.
+	q.configs[0].Enabled = false
+	if err := r.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(sink.events) != 2 || sink.events[1].Action != ActionResolve {
+		t.Fatalf("events after disable = %+v, want resolve", sink.events)
+	}
+}
@@ -0,0 +1,144 @@
+package incidents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	ActionTrigger = "trigger"
+	ActionResolve = "resolve"
+
+	// DefaultPagerDutyEventsURL is the PagerDuty Events API v2 enqueue endpoint.
+	DefaultPagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+	defaultSinkTimeout = 10 * time.Second
+)
+
+// Event is a trigger or resolve notification for one connector. DedupKey is stable per
+// connector so sinks collapse repeated triggers into a single open incident.
+type Event struct {
+	Action        string    `json:"action"`
+	DedupKey      string    `json:"dedup_key"`
+	Summary       string    `json:"summary"`
+	SourceKind    string    `json:"source_kind"`
+	SourceName    string    `json:"source_name"`
+	LastSuccessAt time.Time `json:"last_success_at,omitzero"`
+	SLASeconds    int64     `json:"sla_seconds"`
+}
+
+// Sink delivers incident events to an external system.
+type Sink interface {
+	Send(ctx context.Context, event Event) error
+}
+
+// PagerDutySink sends events to the PagerDuty Events API v2.
+type PagerDutySink struct {
+	routingKey string
+	url        string
+	client     *http.Client
+}
+
+func NewPagerDutySink(routingKey string) (*PagerDutySink, error) {
+	routingKey = strings.TrimSpace(routingKey)
+	if routingKey == "" {
+		return nil, errors.New("pagerduty routing key is required")
+	}
+	return &PagerDutySink{
+		routingKey: routingKey,
+		url:        DefaultPagerDutyEventsURL,
+		client:     &http.Client{Timeout: defaultSinkTimeout},
+	}, nil
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	Component     string         `json:"component,omitempty"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+func (s *PagerDutySink) Send(ctx context.Context, event Event) error {
+	body := pagerDutyEvent{
+		RoutingKey:  s.routingKey,
+		EventAction: event.Action,
+		DedupKey:    event.DedupKey,
+	}
+	if event.Action == ActionTrigger {
+		details := map[string]any{
+			"source_kind": event.SourceKind,
+			"source_name": event.SourceName,
+			"sla_seconds": event.SLASeconds,
+		}
+		if !event.LastSuccessAt.IsZero() {
+			details["last_success_at"] = event.LastSuccessAt.UTC().Format(time.RFC3339)
+		}
+		body.Payload = &pagerDutyPayload{
+			Summary:       event.Summary,
+			Source:        "open-sspm",
+			Severity:      "error",
+			Component:     event.SourceKind,
+			CustomDetails: details,
+		}
+	}
+	return postJSON(ctx, s.client, s.url, body)
+}
+
+// WebhookSink POSTs each Event as JSON to a generic endpoint.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string) (*WebhookSink, error) {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return nil, errors.New("incident webhook url is required")
+	}
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return nil, fmt.Errorf("incident webhook url %q must be http(s)", url)
+	}
+	return &WebhookSink{url: url, client: &http.Client{Timeout: defaultSinkTimeout}}, nil
+}
+
+func (s *WebhookSink) Send(ctx context.Context, event Event) error {
+	return postJSON(ctx, s.client, s.url, event)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("incident sink returned %s: %s", resp.Status, strings.TrimSpace(string(snippet)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,320 @@
+package matching
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// IdentityRecord is what duplicate detection knows about one identity.
+type IdentityRecord struct {
+	ID          int64
+	DisplayName string
+	// Emails are the identity's primary email and the emails of its linked accounts.
+	Emails []string
+	// Handles are the logins of its linked accounts (for example a GitHub login), compared
+	// against the other identity's handles and email local parts.
+	Handles []string
+	// Accounts is the number of linked accounts; Authoritative is set when one of them comes
+	// from an authoritative source. Both only decide which identity of a pair survives.
+	Accounts      int
+	Authoritative bool
+}
+
+// DuplicateSuggestion proposes merging DuplicateID into SurvivorID.
+type DuplicateSuggestion struct {
+	SurvivorID  int64
+	DuplicateID int64
+	Score       float64
+	Reasons     []string
+}
+
+// DuplicateThreshold is the lowest score FindDuplicateIdentities suggests. No single signal
+// other than an identical email reaches it, so a shared name alone is never enough.
+const DuplicateThreshold = 0.7
+
+const (
+	duplicateReasonSameEmail      = "same email address"
+	duplicateReasonSameLocalPart  = "same email local part"
+	duplicateReasonSameName       = "same display name"
+	duplicateReasonSimilarName    = "similar display name"
+	duplicateReasonNameAliasEmail = "email local part matches the other display name"
+	duplicateReasonSharedHandle   = "shared account login"
+
+	// duplicateBlockLimit skips candidate keys so common that comparing everyone sharing them
+	// would be quadratic in the inventory size; such keys carry little signal anyway.
+	duplicateBlockLimit = 200
+)
+
+// genericLocalParts are mailbox names shared by unrelated people and never treated as a match.
+var genericLocalParts = map[string]struct{}{
+	"admin": {}, "administrator": {}, "info": {}, "support": {}, "help": {}, "noreply": {},
+	"no-reply": {}, "root": {}, "it": {}, "ops": {}, "security": {}, "team": {}, "contact": {},
+}
+
+type duplicateProfile struct {
+	record IdentityRecord
+	emails map[string]struct{}
+	locals map[string]struct{}
+	// nameKey is the display name as sorted lower-case tokens; empty for single-word names,
+	// which are too ambiguous to compare.
+	nameKey    string
+	nameTokens []string
+	aliases    map[string]struct{}
+	handles    map[string]struct{}
+}
+
+// FindDuplicateIdentities suggests pairs of identities that probably belong to the same person,
+// scored from normalized email local parts, display names, and overlapping account logins.
+// Suggestions are ordered by score, highest first.
+func FindDuplicateIdentities(records []IdentityRecord) []DuplicateSuggestion {
+	profiles := make([]duplicateProfile, 0, len(records))
+	blocks := make(map[string][]int)
+	for _, record := range records {
+		p := newDuplicateProfile(record)
+		idx := len(profiles)
+		profiles = append(profiles, p)
+		for key := range p.blockKeys() {
+			blocks[key] = append(blocks[key], idx)
+		}
+	}
+
+	type pair struct{ a, b int }
+	seen := make(map[pair]struct{})
+	var out []DuplicateSuggestion
+	for _, members := range blocks {
+		if len(members) < 2 || len(members) > duplicateBlockLimit {
+			continue
+		}
+		for i := 0; i < len(members); i++ {
+			for j := i + 1; j < len(members); j++ {
+				key := pair{min(members[i], members[j]), max(members[i], members[j])}
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+				left, right := profiles[key.a], profiles[key.b]
+				if left.record.ID == right.record.ID {
+					continue
+				}
+				score, reasons := scoreDuplicate(left, right)
+				if score < DuplicateThreshold {
+					continue
+				}
+				survivor, duplicate := chooseSurvivor(left.record, right.record)
+				out = append(out, DuplicateSuggestion{
+					SurvivorID:  survivor.ID,
+					DuplicateID: duplicate.ID,
+					Score:       score,
+					Reasons:     reasons,
+				})
+			}
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		if out[i].SurvivorID != out[j].SurvivorID {
+			return out[i].SurvivorID < out[j].SurvivorID
+		}
+		return out[i].DuplicateID < out[j].DuplicateID
+	})
+	return out
+}
+
+func newDuplicateProfile(record IdentityRecord) duplicateProfile {
+	p := duplicateProfile{
+		record:  record,
+		emails:  make(map[string]struct{}),
+		locals:  make(map[string]struct{}),
+		aliases: make(map[string]struct{}),
+		handles: make(map[string]struct{}),
+	}
+	for _, raw := range record.Emails {
+		email := NormalizeEmailWithOptions(raw, EmailOptions{StripPlusAddressing: true})
+		if email == "" {
+			continue
+		}
+		p.emails[email] = struct{}{}
+		local, _, _ := strings.Cut(email, "@")
+		if _, generic := genericLocalParts[local]; generic {
+			continue
+		}
+		if key := compactHandle(local); len(key) >= 3 {
+			p.locals[key] = struct{}{}
+		}
+	}
+	for _, raw := range record.Handles {
+		if key := compactHandle(raw); len(key) >= 3 && !looksOpaqueID(key) {
+			p.handles[key] = struct{}{}
+		}
+	}
+
+	p.nameTokens = nameTokens(record.DisplayName)
+	if len(p.nameTokens) >= 2 {
+		sorted := append([]string(nil), p.nameTokens...)
+		sort.Strings(sorted)
+		p.nameKey = strings.Join(sorted, " ")
+		first, last := p.nameTokens[0], p.nameTokens[len(p.nameTokens)-1]
+		for _, alias := range []string{
+			first + last,
+			last + first,
+			first[:1] + last,
+			first + last[:1],
+			last + first[:1],
+		} {
+			if len(alias) >= 3 {
+				p.aliases[alias] = struct{}{}
+			}
+		}
+	}
+	return p
+}
+
+func (p duplicateProfile) blockKeys() map[string]struct{} {
+	keys := make(map[string]struct{})
+	for email := range p.emails {
+		keys["e:"+email] = struct{}{}
+	}
+	// Local parts, name aliases, and logins share one namespace so that, for example, a
+	// "jdoe" mailbox meets the "jdoe" alias of "Jane Doe" and a "jdoe" GitHub login.
+	for local := range p.locals {
+		keys["h:"+local] = struct{}{}
+	}
+	for alias := range p.aliases {
+		keys["h:"+alias] = struct{}{}
+	}
+	for handle := range p.handles {
+		keys["h:"+handle] = struct{}{}
+	}
+	if len(p.nameTokens) >= 2 {
+		keys["n:"+p.nameTokens[len(p.nameTokens)-1]] = struct{}{}
+	}
+	return keys
+}
+
+func scoreDuplicate(left, right duplicateProfile) (float64, []string) {
+	var score float64
+	var reasons []string
+	add := func(weight float64, reason string) {
+		score += weight
+		reasons = append(reasons, reason)
+	}
+
+	switch {
+	case intersects(left.emails, right.emails):
+		add(0.7, duplicateReasonSameEmail)
+	case intersects(left.locals, right.locals):
+		add(0.45, duplicateReasonSameLocalPart)
+	}
+
+	switch {
+	case left.nameKey != "" && left.nameKey == right.nameKey:
+		add(0.45, duplicateReasonSameName)
+	case left.nameKey != "" && right.nameKey != "" && similarity(left.nameKey, right.nameKey) >= 0.85:
+		add(0.3, duplicateReasonSimilarName)
+	}
+
+	if intersects(left.locals, right.aliases) || intersects(right.locals, left.aliases) {
+		add(0.35, duplicateReasonNameAliasEmail)
+	}
+
+	if intersects(left.handles, right.handles) || intersects(left.handles, right.locals) || intersects(right.handles, left.locals) {
+		add(0.3, duplicateReasonSharedHandle)
+	}
+
+	return math.Min(score, 1), reasons
+}
+
+// chooseSurvivor keeps the identity anchored in an authoritative source, then the one with
+// more linked accounts, then the older one.
+func chooseSurvivor(a, b IdentityRecord) (survivor, duplicate IdentityRecord) {
+	switch {
+	case a.Authoritative != b.Authoritative:
+		if a.Authoritative {
+			return a, b
+		}
+		return b, a
+	case a.Accounts != b.Accounts:
+		if a.Accounts > b.Accounts {
+			return a, b
+		}
+		return b, a
+	case a.ID < b.ID:
+		return a, b
+	default:
+		return b, a
+	}
+}
+
+// nameTokens splits a display name into lower-case letter/digit tokens.
+func nameTokens(raw string) []string {
+	return strings.FieldsFunc(strings.ToLower(strings.TrimSpace(raw)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// compactHandle lower-cases raw and drops separators so "jane.doe", "jane_doe", and "Jane-Doe"
+// compare equal.
+func compactHandle(raw string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(raw)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// looksOpaqueID reports whether a compacted handle is a system identifier (numeric or a long
+// hex/base62 token such as a UUID or Okta ID) rather than a human-chosen login.
+func looksOpaqueID(handle string) bool {
+	digits := 0
+	for _, r := range handle {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+	return digits == len(handle) || (len(handle) >= 16 && digits >= 4)
+}
+
+func intersects(a, b map[string]struct{}) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	for key := range a {
+		if _, ok := b[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// similarity is 1 minus the Levenshtein distance between a and b relative to the longer one.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
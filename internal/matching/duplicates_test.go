@@ -0,0 +1,74 @@
+package matching
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindDuplicateIdentitiesSuggestsAliasEmails(t *testing.T) {
+	t.Parallel()
+
+	suggestions := FindDuplicateIdentities([]IdentityRecord{
+		{ID: 1, DisplayName: "Jane Doe", Emails: []string{"jane.doe@acme.com"}, Accounts: 3, Authoritative: true},
+		{ID: 2, DisplayName: "Doe, Jane", Emails: []string{"JDoe+contractor@acme-labs.io"}, Handles: []string{"janedoe-gh"}, Accounts: 1},
+		{ID: 3, DisplayName: "Robert Smith", Emails: []string{"rsmith@acme.com"}, Accounts: 2},
+	})
+	if len(suggestions) != 1 {
+		t.Fatalf("suggestions = %+v, want exactly one", suggestions)
+	}
+	got := suggestions[0]
+	if got.SurvivorID != 1 || got.DuplicateID != 2 {
+		t.Fatalf("survivor/duplicate = %d/%d, want 1/2", got.SurvivorID, got.DuplicateID)
+	}
+	if got.Score < DuplicateThreshold {
+		t.Fatalf("score = %v, want >= %v", got.Score, DuplicateThreshold)
+	}
+	for _, reason := range []string{duplicateReasonSameName, duplicateReasonNameAliasEmail} {
+		if !slices.Contains(got.Reasons, reason) {
+			t.Fatalf("reasons = %v, want %q", got.Reasons, reason)
+		}
+	}
+}
+
+func TestFindDuplicateIdentitiesIgnoresDifferentPeople(t *testing.T) {
+	t.Parallel()
+
+	suggestions := FindDuplicateIdentities([]IdentityRecord{
+		// John's "jdoe" mailbox is also an alias of Jane's name, but nothing else lines up.
+		{ID: 1, DisplayName: "Jane Doe", Emails: []string{"jane.doe@acme.com"}, Accounts: 2},
+		{ID: 2, DisplayName: "John Doe", Emails: []string{"jdoe@acme.com"}, Accounts: 2},
+		{ID: 3, DisplayName: "Priya Raman", Emails: []string{"priya@acme.com"}, Handles: []string{"praman"}},
+		{ID: 4, DisplayName: "Support", Emails: []string{"support@acme.com"}},
+		{ID: 5, DisplayName: "Support", Emails: []string{"support@acme-labs.io"}},
+	})
+	if len(suggestions) != 0 {
+		t.Fatalf("suggestions = %+v, want none", suggestions)
+	}
+}
+
+func TestFindDuplicateIdentitiesSharedLoginAndSimilarName(t *testing.T) {
+	t.Parallel()
+
+	suggestions := FindDuplicateIdentities([]IdentityRecord{
+		{ID: 7, DisplayName: "Jon Smyth", Handles: []string{"jsmyth"}, Accounts: 1},
+		{ID: 4, DisplayName: "Jon Smith", Emails: []string{"j.smyth@acme.com"}, Accounts: 1},
+	})
+	if len(suggestions) != 1 {
+		t.Fatalf("suggestions = %+v, want one", suggestions)
+	}
+	if suggestions[0].SurvivorID != 4 || suggestions[0].DuplicateID != 7 {
+		t.Fatalf("survivor/duplicate = %d/%d, want the older identity 4 to survive", suggestions[0].SurvivorID, suggestions[0].DuplicateID)
+	}
+}
+
+func TestFindDuplicateIdentitiesIgnoresOpaqueAccountIDs(t *testing.T) {
+	t.Parallel()
+
+	suggestions := FindDuplicateIdentities([]IdentityRecord{
+		{ID: 1, DisplayName: "Alex Kim", Handles: []string{"00u1a2b3c4d5e6f7g8h9"}},
+		{ID: 2, DisplayName: "Alex Kim", Handles: []string{"00u1a2b3c4d5e6f7g8h9"}},
+	})
+	if len(suggestions) != 0 {
+		t.Fatalf("suggestions = %+v, want none for a shared opaque id and name", suggestions)
+	}
+}
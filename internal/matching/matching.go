@@ -7,8 +7,49 @@ import (
 	"github.com/open-sspm/open-sspm/internal/db/gen"
 )
 
-func NormalizeEmail(email string) string {
-	return strings.ToLower(strings.TrimSpace(email))
+// EmailOptions controls optional normalization steps applied by NormalizeEmailWithOptions.
+type EmailOptions struct {
+	// StripPlusAddressing drops a "+tag" suffix from the local part so that
+	// alice+build@example.com and alice@example.com match the same person.
+	StripPlusAddressing bool
+}
+
+// NormalizeEmail returns the canonical, lower-cased form of an email address, or ""
+// when raw does not contain exactly one address. Display-name-wrapped forms such as
+// `Alice <alice@example.com>` are unwrapped.
+func NormalizeEmail(raw string) string {
+	return NormalizeEmailWithOptions(raw, EmailOptions{})
+}
+
+// NormalizeEmailWithOptions is NormalizeEmail with optional extra normalization.
+func NormalizeEmailWithOptions(raw string, opts EmailOptions) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+
+	if left := strings.Index(raw, "<"); left >= 0 {
+		if right := strings.Index(raw[left+1:], ">"); right >= 0 {
+			raw = raw[left+1 : left+1+right]
+		}
+	}
+
+	email := strings.Trim(strings.TrimSpace(raw), "\"'<>[](){}.,;")
+	email = strings.ToLower(strings.TrimSpace(email))
+	if strings.ContainsAny(email, " \t\r\n") {
+		return ""
+	}
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" || domain == "" || strings.Contains(domain, "@") {
+		return ""
+	}
+
+	if opts.StripPlusAddressing {
+		if idx := strings.IndexByte(local, '+'); idx > 0 {
+			local = local[:idx]
+		}
+	}
+	return local + "@" + domain
 }
 
 func AutoLinkByEmail(ctx context.Context, q *gen.Queries, sourceKind, sourceName string) (int, error) {
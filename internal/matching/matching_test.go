@@ -0,0 +1,39 @@
+package matching
+
+import "testing"
+
+func TestNormalizeEmail(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		raw  string
+		opts EmailOptions
+		want string
+	}{
+		{name: "plain", raw: "alice@example.com", want: "alice@example.com"},
+		{name: "mixed case", raw: "Alice@Example.COM", want: "alice@example.com"},
+		{name: "surrounding whitespace", raw: "  bob@example.com\t", want: "bob@example.com"},
+		{name: "display name wrapped", raw: "Alice <Alice@example.com>", want: "alice@example.com"},
+		{name: "quoted display name wrapped", raw: `"Smith, Carol" <carol@example.com>`, want: "carol@example.com"},
+		{name: "trailing punctuation", raw: "dave@example.com.", want: "dave@example.com"},
+		{name: "plus addressing kept by default", raw: "Erin+CI@example.com", want: "erin+ci@example.com"},
+		{name: "plus addressing stripped when enabled", raw: "Erin+CI@example.com", opts: EmailOptions{StripPlusAddressing: true}, want: "erin@example.com"},
+		{name: "leading plus not stripped", raw: "+ops@example.com", opts: EmailOptions{StripPlusAddressing: true}, want: "+ops@example.com"},
+		{name: "empty", raw: "", want: ""},
+		{name: "not an email", raw: "not-an-email", want: ""},
+		{name: "missing local part", raw: "@example.com", want: ""},
+		{name: "missing domain", raw: "frank@", want: ""},
+		{name: "multiple at signs", raw: "a@b@example.com", want: ""},
+		{name: "embedded whitespace", raw: "gina smith@example.com", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := NormalizeEmailWithOptions(tt.raw, tt.opts); got != tt.want {
+				t.Fatalf("NormalizeEmailWithOptions(%q, %+v) = %q, want %q", tt.raw, tt.opts, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	inventoryQueryTimeout = 10 * time.Second
+	inventoryDormantDays  = 90
+)
+
+// credentialRiskLevels is the fixed label set for credentials_by_risk; every level is
+// exported on each scrape so absent levels read as zero instead of disappearing.
+var credentialRiskLevels = []string{"critical", "high", "medium", "low"}
+
+// InventoryQueries is the subset of gen.Queries used by InventoryCollector.
+type InventoryQueries interface {
+	CountActiveCredentialArtifactsGroupedByKind(ctx context.Context) ([]gen.CountActiveCredentialArtifactsGroupedByKindRow, error)
+	CountCredentialArtifactsGroupedByRiskLevel(ctx context.Context) ([]gen.CountCredentialArtifactsGroupedByRiskLevelRow, error)
+	CountAppAssetsGroupedBySourceKind(ctx context.Context) ([]gen.CountAppAssetsGroupedBySourceKindRow, error)
+	CountDormantAppUsersGroupedBySourceKind(ctx context.Context, dormantDays int32) ([]gen.CountDormantAppUsersGroupedBySourceKindRow, error)
+}
+
+type inventorySnapshot struct {
+	activeCredentialsByKind map[string]int64
+	credentialsByRisk       map[string]int64
+	appAssetsBySource       map[string]int64
+	dormantAccountsBySource map[string]int64
+}
+
+// InventoryCollector exports current inventory gauges computed from COUNT queries.
+// Results are cached for ttl (METRICS_INVENTORY_CACHE_TTL) so frequent scrapes do not hit Postgres
+// each time; when a refresh fails the previous snapshot keeps being served.
+type InventoryCollector struct {
+	q          InventoryQueries
+	ttl        time.Duration
+	exportRisk bool
+	now        func() time.Time
+
+	mu        sync.Mutex
+	snapshot  inventorySnapshot
+	fetchedAt time.Time
+	hasData   bool
+
+	activeCredentialsDesc *prometheus.Desc
+	credentialsByRiskDesc *prometheus.Desc
+	appAssetsDesc         *prometheus.Desc
+	dormantAccountsDesc   *prometheus.Desc
+}
+
+// NewInventoryCollector returns a collector over q. credentials_by_risk is counted in SQL with the
+// built-in credential risk policy, so exportRisk should be false when that policy is customized;
+// the gauge is then left out rather than disagreeing with the risk levels shown in the UI.
+func NewInventoryCollector(q InventoryQueries, ttl time.Duration, exportRisk bool) *InventoryCollector {
+	return &InventoryCollector{
+		q:          q,
+		ttl:        ttl,
+		exportRisk: exportRisk,
+		now:        time.Now,
+		activeCredentialsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "credentials_active_total"),
+			"Current active credentials by credential kind.",
+			[]string{"credential_kind"}, nil,
+		),
+		credentialsByRiskDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "credentials_by_risk"),
+			"Current credentials by computed risk level.",
+			[]string{"level"}, nil,
+		),
+		appAssetsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "app_assets_total"),
+			"Current app assets by source kind.",
+			[]string{"source_kind"}, nil,
+		),
+		dormantAccountsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "dormant_accounts_total"),
+			"Current active accounts with no login in the last 90 days, by source kind.",
+			[]string{"source_kind"}, nil,
+		),
+	}
+}
+
+// RegisterInventoryCollector registers an InventoryCollector with the default registry
+// served by StartServer.
+func RegisterInventoryCollector(q InventoryQueries, ttl time.Duration, exportRisk bool) error {
+	return prometheus.Register(NewInventoryCollector(q, ttl, exportRisk))
+}
+
+func (c *InventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.activeCredentialsDesc
+	if c.exportRisk {
+		ch <- c.credentialsByRiskDesc
+	}
+	ch <- c.appAssetsDesc
+	ch <- c.dormantAccountsDesc
+}
+
+func (c *InventoryCollector) Collect(ch chan<- prometheus.Metric) {
+	snapshot, ok := c.currentSnapshot()
+	if !ok {
+		return
+	}
+
+	for kind, count := range snapshot.activeCredentialsByKind {
+		ch <- prometheus.MustNewConstMetric(c.activeCredentialsDesc, prometheus.GaugeValue, float64(count), kind)
+	}
+	if c.exportRisk {
+		for _, level := range credentialRiskLevels {
+			ch <- prometheus.MustNewConstMetric(c.credentialsByRiskDesc, prometheus.GaugeValue, float64(snapshot.credentialsByRisk[level]), level)
+		}
+	}
+	for sourceKind, count := range snapshot.appAssetsBySource {
+		ch <- prometheus.MustNewConstMetric(c.appAssetsDesc, prometheus.GaugeValue, float64(count), sourceKind)
+	}
+	for sourceKind, count := range snapshot.dormantAccountsBySource {
+		ch <- prometheus.MustNewConstMetric(c.dormantAccountsDesc, prometheus.GaugeValue, float64(count), sourceKind)
+	}
+}
+
+func (c *InventoryCollector) currentSnapshot() (inventorySnapshot, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if c.hasData && now.Sub(c.fetchedAt) < c.ttl {
+		return c.snapshot, true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), inventoryQueryTimeout)
+	defer cancel()
+	snapshot, err := c.fetch(ctx)
+	if err != nil {
+		slog.Warn("inventory metrics refresh failed", "err", err)
+		return c.snapshot, c.hasData
+	}
+	c.snapshot = snapshot
+	c.fetchedAt = now
+	c.hasData = true
+	return snapshot, true
+}
+
+func (c *InventoryCollector) fetch(ctx context.Context) (inventorySnapshot, error) {
+	snapshot := inventorySnapshot{
+		activeCredentialsByKind: map[string]int64{},
+		credentialsByRisk:       map[string]int64{},
+		appAssetsBySource:       map[string]int64{},
+		dormantAccountsBySource: map[string]int64{},
+	}
+
+	byKind, err := c.q.CountActiveCredentialArtifactsGroupedByKind(ctx)
+	if err != nil {
+		return inventorySnapshot{}, err
+	}
+	for _, row := range byKind {
+		snapshot.activeCredentialsByKind[inventoryLabel(row.CredentialKind)] += row.CredentialCount
+	}
+
+	if c.exportRisk {
+		byRisk, err := c.q.CountCredentialArtifactsGroupedByRiskLevel(ctx)
+		if err != nil {
+			return inventorySnapshot{}, err
+		}
+		for _, row := range byRisk {
+			snapshot.credentialsByRisk[inventoryLabel(row.RiskLevel)] += row.CredentialCount
+		}
+	}
+
+	assets, err := c.q.CountAppAssetsGroupedBySourceKind(ctx)
+	if err != nil {
+		return inventorySnapshot{}, err
+	}
+	for _, row := range assets {
+		snapshot.appAssetsBySource[inventoryLabel(row.SourceKind)] += row.AssetCount
+	}
+
+	dormant, err := c.q.CountDormantAppUsersGroupedBySourceKind(ctx, inventoryDormantDays)
+	if err != nil {
+		return inventorySnapshot{}, err
+	}
+	for _, row := range dormant {
+		snapshot.dormantAccountsBySource[inventoryLabel(row.SourceKind)] += row.AccountCount
+	}
+
+	return snapshot, nil
+}
+
+func inventoryLabel(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type fakeInventoryQueries struct {
+	calls     int
+	riskCalls int
+	err       error
+}
+
+func (f *fakeInventoryQueries) CountActiveCredentialArtifactsGroupedByKind(context.Context) ([]gen.CountActiveCredentialArtifactsGroupedByKindRow, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []gen.CountActiveCredentialArtifactsGroupedByKindRow{
+		{CredentialKind: "entra_client_secret", CredentialCount: 4},
+		{CredentialKind: "github_deploy_key", CredentialCount: 2},
+	}, nil
+}
+
+func (f *fakeInventoryQueries) CountCredentialArtifactsGroupedByRiskLevel(context.Context) ([]gen.CountCredentialArtifactsGroupedByRiskLevelRow, error) {
+	f.riskCalls++
+	return []gen.CountCredentialArtifactsGroupedByRiskLevelRow{
+		{RiskLevel: "critical", CredentialCount: 1},
+		{RiskLevel: "high", CredentialCount: 5},
+	}, nil
+}
+
+func (f *fakeInventoryQueries) CountAppAssetsGroupedBySourceKind(context.Context) ([]gen.CountAppAssetsGroupedBySourceKindRow, error) {
+	return []gen.CountAppAssetsGroupedBySourceKindRow{
+		{SourceKind: "entra", AssetCount: 7},
+		{SourceKind: "github", AssetCount: 3},
+	}, nil
+}
+
+func (f *fakeInventoryQueries) CountDormantAppUsersGroupedBySourceKind(_ context.Context, dormantDays int32) ([]gen.CountDormantAppUsersGroupedBySourceKindRow, error) {
+	if dormantDays != inventoryDormantDays {
+		return nil, errors.New("unexpected dormant days")
+	}
+	return []gen.CountDormantAppUsersGroupedBySourceKindRow{
+		{SourceKind: "okta", AccountCount: 9},
+	}, nil
+}
+
+// gatherInventory registers collector on a fresh registry and returns gauge values keyed
+// by metric name and then by label value.
+func gatherInventory(t *testing.T, collector *InventoryCollector) map[string]map[string]float64 {
+	t.Helper()
+
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(collector); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	out := make(map[string]map[string]float64, len(families))
+	for _, family := range families {
+		values := make(map[string]float64, len(family.GetMetric()))
+		for _, metric := range family.GetMetric() {
+			if len(metric.GetLabel()) != 1 {
+				t.Fatalf("%s has %d labels, want 1", family.GetName(), len(metric.GetLabel()))
+			}
+			values[metric.GetLabel()[0].GetValue()] = metric.GetGauge().GetValue()
+		}
+		out[family.GetName()] = values
+	}
+	return out
+}
+
+func countSeries(families map[string]map[string]float64) int {
+	total := 0
+	for _, values := range families {
+		total += len(values)
+	}
+	return total
+}
+
+func TestInventoryCollectorExportsSeededCounts(t *testing.T) {
+	t.Parallel()
+
+	got := gatherInventory(t, NewInventoryCollector(&fakeInventoryQueries{}, time.Minute, true))
+
+	want := map[string]map[string]float64{
+		"opensspm_credentials_active_total": {"entra_client_secret": 4, "github_deploy_key": 2},
+		"opensspm_credentials_by_risk":      {"critical": 1, "high": 5, "medium": 0, "low": 0},
+		"opensspm_app_assets_total":         {"entra": 7, "github": 3},
+		"opensspm_dormant_accounts_total":   {"okta": 9},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("metric families = %v, want %d families", got, len(want))
+	}
+	for name, wantValues := range want {
+		gotValues, ok := got[name]
+		if !ok {
+			t.Fatalf("missing metric family %s", name)
+		}
+		if len(gotValues) != len(wantValues) {
+			t.Fatalf("%s series = %v, want %v", name, gotValues, wantValues)
+		}
+		for label, wantValue := range wantValues {
+			if gotValue, ok := gotValues[label]; !ok || gotValue != wantValue {
+				t.Fatalf("%s{%s} = %v (present=%v), want %v", name, label, gotValue, ok, wantValue)
+			}
+		}
+	}
+}
+
+func TestInventoryCollectorOmitsRiskGaugeForCustomPolicy(t *testing.T) {
+	t.Parallel()
+
+	q := &fakeInventoryQueries{}
+	got := gatherInventory(t, NewInventoryCollector(q, time.Minute, false))
+	if _, ok := got["opensspm_credentials_by_risk"]; ok {
+		t.Fatalf("credentials_by_risk exported with a customized risk policy: %v", got)
+	}
+	if q.riskCalls != 0 {
+		t.Fatalf("risk level query ran %d times, want 0", q.riskCalls)
+	}
+	if len(got) != 3 {
+		t.Fatalf("metric families = %v, want the three other gauges", got)
+	}
+}
+
+func TestInventoryCollectorCachesWithinTTL(t *testing.T) {
+	t.Parallel()
+
+	q := &fakeInventoryQueries{}
+	collector := NewInventoryCollector(q, time.Minute, true)
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	collector.now = func() time.Time { return now }
+
+	gatherInventory(t, collector)
+	gatherInventory(t, collector)
+	if q.calls != 1 {
+		t.Fatalf("queries ran %d times within TTL, want 1", q.calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	gatherInventory(t, collector)
+	if q.calls != 2 {
+		t.Fatalf("queries ran %d times after TTL, want 2", q.calls)
+	}
+}
+
+func TestInventoryCollectorServesStaleSnapshotOnError(t *testing.T) {
+	t.Parallel()
+
+	q := &fakeInventoryQueries{}
+	collector := NewInventoryCollector(q, time.Minute, true)
+	now := time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC)
+	collector.now = func() time.Time { return now }
+
+	want := countSeries(gatherInventory(t, collector))
+
+	q.err = errors.New("database unavailable")
+	now = now.Add(2 * time.Minute)
+	if got := countSeries(gatherInventory(t, collector)); got != want {
+		t.Fatalf("series after failed refresh = %d, want stale %d", got, want)
+	}
+}
@@ -32,12 +32,42 @@ var (
 		Help:      "Unix timestamp of the last successful sync.",
 	}, []string{"connector_kind", "connector_name"})
 
+	SyncRunsActive = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sync_runs_active",
+		Help:      "Integration runs currently holding a concurrency slot in this process.",
+	})
+
+	SyncRunsQueued = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sync_runs_queued",
+		Help:      "Integration runs waiting for a concurrency slot in this process.",
+	})
+
 	SyncMetricsCollectionFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: namespace,
 		Name:      "sync_metrics_collection_failures_total",
 		Help:      "Count of metrics collection failures after successful syncs.",
 	}, []string{"connector_kind", "connector_name", "reason"})
 
+	ConnectorAPIRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "connector_api_requests_total",
+		Help:      "Provider API requests made by connector syncs, by API host.",
+	}, []string{"connector_kind", "connector_name", "host"})
+
+	ConnectorAPIBytesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "connector_api_bytes_total",
+		Help:      "Request and response body bytes of provider API requests made by connector syncs.",
+	}, []string{"connector_kind", "connector_name", "direction"})
+
+	ConnectorAPIErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "connector_api_errors_total",
+		Help:      "Failed provider API requests made by connector syncs, by error class.",
+	}, []string{"connector_kind", "connector_name", "error_class"})
+
 	// Resource Metrics
 	ResourcesTotal = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
@@ -51,6 +81,18 @@ var (
 		Help:      "Number of discovery evidence events ingested.",
 	}, []string{"source_kind", "signal_kind"})
 
+	DiscoveryBackfillEventsIngestedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "discovery_backfill_events_ingested_total",
+		Help:      "Number of discovery evidence events ingested by operator-triggered backfills.",
+	}, []string{"source_kind", "signal_kind"})
+
+	DiscoveryEventsUpsertedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "discovery_events_upserted_total",
+		Help:      "Discovery evidence rows written, by whether the event was new (inserted) or already stored (updated).",
+	}, []string{"source_kind", "signal_kind", "result"})
+
 	DiscoveryIngestFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: namespace,
 		Name:      "discovery_ingest_failures_total",
@@ -75,6 +117,12 @@ var (
 		Help:      "Number of identities automatically linked by email.",
 	}, []string{"connector_kind", "connector_name"})
 
+	RawJSONTruncationsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "raw_json_truncations_total",
+		Help:      "Number of provider raw JSON payloads truncated to the configured size limit.",
+	})
+
 	// Rules Engine Metrics
 	RuleEvaluationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: namespace,
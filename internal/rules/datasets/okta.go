@@ -13,6 +13,7 @@ import (
 	"sync"
 
 	runtimev2 "github.com/open-sspm/open-sspm-spec/gen/go/opensspm/runtime/v2"
+	"github.com/open-sspm/open-sspm/internal/connectors/httpclient"
 	"github.com/open-sspm/open-sspm/internal/connectors/oktaapi"
 	"github.com/open-sspm/open-sspm/internal/rules/engine"
 )
@@ -616,7 +617,7 @@ func (p *OktaProvider) loadApps(ctx context.Context) ([]any, error) {
 		req.Header.Set("Accept", "application/json")
 		req.Header.Set("Authorization", "SSWS "+token)
 
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := httpclient.NewForKind("okta", 0).Do(req)
 		if err != nil {
 			return nil, err
 		}
@@ -872,7 +873,7 @@ func oktaListJSONPages[T any](ctx context.Context, baseURL, token, path string)
 		req.Header.Set("Accept", "application/json")
 		req.Header.Set("Authorization", "SSWS "+strings.TrimSpace(token))
 
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := httpclient.NewForKind("okta", 0).Do(req)
 		if err != nil {
 			return nil, err
 		}
@@ -913,7 +914,7 @@ func oktaGetJSON(ctx context.Context, baseURL, token, path string) (map[string]a
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Authorization", "SSWS "+strings.TrimSpace(token))
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.NewForKind("okta", 0).Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,89 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+	"github.com/open-sspm/open-sspm/internal/normalize"
+)
+
+// defaultAPIRequestBudgetWindow is the window an API request budget applies to when the policy
+// sets none, matching the hourly quotas of GitHub and Microsoft Graph.
+const defaultAPIRequestBudgetWindow = time.Hour
+
+// APIRequestBudgetEstimate is a source's recent provider API usage against its budget. Runs of
+// every mode count toward Used, since full and discovery syncs share the tenant's quota.
+type APIRequestBudgetEstimate struct {
+	Budget int64
+	Window time.Duration
+	// Used is the requests made by runs started within Window.
+	Used int64
+	// NextRun is the expected cost of the next run, taken from the last finished run of its mode.
+	NextRun int64
+}
+
+// NearLimit reports whether the next run would likely push the source over its budget.
+func (e APIRequestBudgetEstimate) NearLimit() bool {
+	return e.Budget > 0 && e.Used+e.NextRun > e.Budget
+}
+
+func (p RunPolicy) apiRequestBudget(kind string) int64 {
+	if p.APIRequestBudgetByKind == nil {
+		return 0
+	}
+	return p.APIRequestBudgetByKind[normalize.Lower(kind)]
+}
+
+func (p RunPolicy) apiRequestBudgetWindow() time.Duration {
+	if p.APIRequestBudgetWindow <= 0 {
+		return defaultAPIRequestBudgetWindow
+	}
+	return p.APIRequestBudgetWindow
+}
+
+// apiRequestBudgetEstimate returns the budget estimate for a connector kind's source, or ok=false
+// when the kind has no budget.
+func (r *DBRunner) apiRequestBudgetEstimate(ctx context.Context, kind, name string) (APIRequestBudgetEstimate, bool, error) {
+	if r.q == nil || r.policy == nil {
+		return APIRequestBudgetEstimate{}, false, nil
+	}
+	budget := r.policy.apiRequestBudget(kind)
+	if budget <= 0 {
+		return APIRequestBudgetEstimate{}, false, nil
+	}
+
+	window := r.policy.apiRequestBudgetWindow()
+	sourceKinds := []string{registry.SyncRunSourceKind(kind, registry.RunModeFull)}
+	if discoveryKind := registry.SyncRunSourceKind(kind, registry.RunModeDiscovery); !slices.Contains(sourceKinds, discoveryKind) {
+		sourceKinds = append(sourceKinds, discoveryKind)
+	}
+	usage, err := r.q.GetSyncRunAPIRequestUsage(ctx, gen.GetSyncRunAPIRequestUsageParams{
+		SourceKinds:   sourceKinds,
+		SourceName:    normalize.Trim(name),
+		Since:         pgtype.Timestamptz{Time: r.policy.now().Add(-window), Valid: true},
+		RunSourceKind: registry.SyncRunSourceKind(kind, r.runMode()),
+	})
+	if err != nil {
+		return APIRequestBudgetEstimate{}, false, err
+	}
+	return APIRequestBudgetEstimate{
+		Budget:  budget,
+		Window:  window,
+		Used:    usage.UsedSince,
+		NextRun: usage.LastRunCount,
+	}, true, nil
+}
+
+// apiRequestBudgetDeferral returns why a run should wait for its source's API budget to
+// recover, or "" when it may run.
+func apiRequestBudgetDeferral(estimate APIRequestBudgetEstimate) string {
+	if !estimate.NearLimit() {
+		return ""
+	}
+	return fmt.Sprintf("api request budget: %d of %d used in the last %s, next run expected to use %d", estimate.Used, estimate.Budget, estimate.Window, estimate.NextRun)
+}
@@ -0,0 +1,54 @@
+package sync
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+func TestDBRunner_APIRequestBudgetEstimate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	db := &syncRunRequestsDB{row: []int64{4600, 600}}
+	runner := &DBRunner{q: gen.New(db), mode: registry.RunModeDiscovery}
+	runner.SetRunPolicy(RunPolicy{
+		APIRequestBudgetByKind: map[string]int64{"entra": 5000},
+		Now:                    func() time.Time { return now },
+	})
+
+	if _, ok, err := runner.apiRequestBudgetEstimate(context.Background(), "okta", "example.okta.com"); ok || err != nil {
+		t.Fatalf("okta estimate ok/err = %v/%v, want no budget", ok, err)
+	}
+	if len(db.queryArgs) != 0 {
+		t.Fatalf("queried %d times for a kind without a budget", len(db.queryArgs))
+	}
+
+	estimate, ok, err := runner.apiRequestBudgetEstimate(context.Background(), "entra", "tenant-1")
+	if err != nil || !ok {
+		t.Fatalf("entra estimate ok/err = %v/%v", ok, err)
+	}
+	if estimate.Budget != 5000 || estimate.Window != time.Hour || estimate.Used != 4600 || estimate.NextRun != 600 {
+		t.Fatalf("estimate = %+v", estimate)
+	}
+	args := db.queryArgs[0]
+	if kinds, _ := args[0].([]string); !slices.Equal(kinds, []string{"entra", "entra_discovery"}) {
+		t.Fatalf("usage source kinds = %v, want both modes", args[0])
+	}
+	if args[3] != "entra_discovery" {
+		t.Fatalf("next run estimated from %v, want entra_discovery", args[3])
+	}
+
+	if reason := apiRequestBudgetDeferral(estimate); !strings.Contains(reason, "4600 of 5000") {
+		t.Fatalf("deferral = %q, want the usage named", reason)
+	}
+	estimate.NextRun = 400
+	if reason := apiRequestBudgetDeferral(estimate); reason != "" {
+		t.Fatalf("deferral at exactly the budget = %q, want none", reason)
+	}
+}
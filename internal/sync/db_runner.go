@@ -22,6 +22,7 @@ type DBRunner struct {
 	policy         *RunPolicy
 	globalEvalMode string
 	locks          LockManager
+	limiter        *RunLimiter
 	mode           registry.RunMode
 }
 
@@ -61,6 +62,12 @@ func (r *DBRunner) SetLockManager(m LockManager) {
 	r.locks = m
 }
 
+// SetRunLimiter shares l with every orchestrator this runner creates. Pass the same limiter
+// to all runners in a process to cap integration runs process-wide.
+func (r *DBRunner) SetRunLimiter(l *RunLimiter) {
+	r.limiter = l
+}
+
 func (r *DBRunner) SetRunPolicy(policy RunPolicy) {
 	r.policy = &policy
 }
@@ -81,6 +88,10 @@ func (r *DBRunner) RunOnce(ctx context.Context) error {
 		return errors.New("sync runner is not configured")
 	}
 
+	if requestedMode, ok := RunModeFromContext(ctx); ok && requestedMode != r.runMode() {
+		return ErrNoConnectorsDue
+	}
+
 	configs, err := r.q.ListConnectorConfigs(ctx)
 	if err != nil {
 		return err
@@ -98,6 +109,7 @@ func (r *DBRunner) RunOnce(ctx context.Context) error {
 		r.locks = locks
 	}
 	orchestrator.SetLockManager(r.locks)
+	orchestrator.SetRunLimiter(r.limiter)
 	if strings.TrimSpace(r.globalEvalMode) != "" {
 		orchestrator.SetGlobalEvalMode(r.globalEvalMode)
 	}
@@ -138,22 +150,9 @@ func (r *DBRunner) RunOnce(ctx context.Context) error {
 			continue
 		}
 
-		cfg, err := def.DecodeConfig(cfgRow.Config)
-		if err != nil {
-			errList = append(errList, fmt.Errorf("%s config: %w", kind, err))
-			skippedKinds = append(skippedKinds, kind)
-			continue
-		}
-
-		if err := def.ValidateConfig(cfg); err != nil {
-			errList = append(errList, fmt.Errorf("%s config: %w", kind, err))
-			skippedKinds = append(skippedKinds, kind)
-			continue
-		}
-
-		integration, err := def.NewIntegration(cfg)
+		integration, err := r.buildIntegration(ctx, def, cfgRow.Config)
 		if err != nil {
-			errList = append(errList, fmt.Errorf("%s integration: %w", kind, err))
+			errList = append(errList, fmt.Errorf("%s %w", kind, err))
 			skippedKinds = append(skippedKinds, kind)
 			continue
 		}
@@ -223,6 +222,15 @@ func (r *DBRunner) RunOnce(ctx context.Context) error {
 				deferred = append(deferred, fmt.Sprintf("%s/%s (%s)", candidate.runKind, candidate.runName, reason))
 				continue
 			}
+			estimate, hasBudget, err := r.apiRequestBudgetEstimate(ctx, candidate.kind, candidate.runName)
+			if err != nil {
+				slog.Warn("api request budget lookup failed; running without it", "kind", candidate.kind, "name", candidate.runName, "err", err)
+			} else if hasBudget {
+				if reason := apiRequestBudgetDeferral(estimate); reason != "" {
+					deferred = append(deferred, fmt.Sprintf("%s/%s (%s)", candidate.runKind, candidate.runName, reason))
+					continue
+				}
+			}
 		}
 
 		if err := orchestrator.AddIntegration(candidate.integration); err != nil {
@@ -274,19 +282,29 @@ func (r *DBRunner) integrationRunSourceKind(integration registry.Integration) st
 	return registry.SyncRunSourceKind(integration.Kind(), r.runMode())
 }
 
-func (r *DBRunner) integrationSupportsRunMode(integration registry.Integration) bool {
-	mode := r.runMode()
-	if integration == nil {
-		return false
+// buildIntegration decodes raw, resolves its secret references, and validates the result
+// before constructing the integration, so connectors only ever see resolved secrets.
+func (r *DBRunner) buildIntegration(ctx context.Context, def registry.ConnectorDefinition, raw []byte) (registry.Integration, error) {
+	cfg, err := def.DecodeConfig(raw)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
 	}
-
-	modeAware, ok := integration.(registry.ModeAwareIntegration)
-	if ok {
-		return modeAware.SupportsRunMode(mode)
+	cfg, err = r.registry.ResolveConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if err := def.ValidateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	integration, err := def.NewIntegration(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("integration: %w", err)
 	}
+	return integration, nil
+}
 
-	// Legacy integrations default to full-only.
-	return mode == registry.RunModeFull
+func (r *DBRunner) integrationSupportsRunMode(integration registry.Integration) bool {
+	return registry.IntegrationSupportsRunMode(integration, r.runMode())
 }
 
 func matchesRequestedConnectorScope(kind, sourceName, requestedKind, requestedSourceName string) bool {
@@ -89,7 +89,7 @@ func TestDBRunner_DiscoveryModeFiltering(t *testing.T) {
 		t.Fatalf("okta integration with discovery disabled should be skipped")
 	}
 
-	entraDiscoveryDisabled := entra.NewEntraIntegration(nil, "tenant", false)
+	entraDiscoveryDisabled := entra.NewEntraIntegration(nil, "tenant", 1, false)
 	if discoveryRunner.integrationSupportsRunMode(entraDiscoveryDisabled) {
 		t.Fatalf("entra integration with discovery disabled should be skipped")
 	}
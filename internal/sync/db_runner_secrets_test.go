@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/configstore"
+	"github.com/open-sspm/open-sspm/internal/connectors/entra"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+)
+
+type fakeSecretProvider map[string]string
+
+func (p fakeSecretProvider) Resolve(_ context.Context, ref string) (string, error) {
+	value, ok := p[ref]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return value, nil
+}
+
+// recordingEntraDefinition captures the config the connector is built with.
+type recordingEntraDefinition struct {
+	*entra.Definition
+	built []configstore.EntraConfig
+}
+
+func (d *recordingEntraDefinition) NewIntegration(cfg any) (registry.Integration, error) {
+	d.built = append(d.built, cfg.(configstore.EntraConfig))
+	return d.Definition.NewIntegration(cfg)
+}
+
+func TestDBRunner_BuildIntegrationResolvesSecrets(t *testing.T) {
+	t.Parallel()
+
+	def := &recordingEntraDefinition{Definition: entra.NewDefinition(1)}
+	reg := registry.NewRegistry()
+	reg.SetSecretProvider(fakeSecretProvider{"entra/client-secret": "resolved-secret"})
+	runner := &DBRunner{registry: reg}
+
+	raw := []byte(`{"tenant_id":"11111111-1111-1111-1111-111111111111","client_id":"22222222-2222-2222-2222-222222222222","client_secret":"secret://entra/client-secret"}`)
+	if _, err := runner.buildIntegration(context.Background(), def, raw); err != nil {
+		t.Fatalf("buildIntegration() error = %v", err)
+	}
+	if len(def.built) != 1 || def.built[0].ClientSecret != "resolved-secret" {
+		t.Fatalf("connector built with %+v, want resolved client secret", def.built)
+	}
+
+	missing := []byte(`{"tenant_id":"11111111-1111-1111-1111-111111111111","client_id":"22222222-2222-2222-2222-222222222222","client_secret":"secret://entra/other"}`)
+	if _, err := runner.buildIntegration(context.Background(), def, missing); err == nil {
+		t.Fatalf("buildIntegration() with an unresolvable reference returned nil error")
+	}
+	if len(def.built) != 1 {
+		t.Fatalf("connector was built despite an unresolved secret")
+	}
+}
@@ -0,0 +1,226 @@
+package sync
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// InventorySnapshotInterval is how often the worker checks whether the day's inventory snapshot
+// has been taken. A day is captured once, on the first check after UTC midnight.
+const InventorySnapshotInterval = time.Hour
+
+const inventorySnapshotTimeout = 10 * time.Minute
+
+// Inventory snapshot kinds, one snapshot row each per day.
+const (
+	InventorySnapshotCredentials  = "credentials"
+	InventorySnapshotEntitlements = "entitlements"
+)
+
+var inventorySnapshotKinds = []string{InventorySnapshotCredentials, InventorySnapshotEntitlements}
+
+type inventorySnapshotQueries interface {
+	CountInventorySnapshotsForDate(ctx context.Context, snapshotDate pgtype.Date) (int64, error)
+	ListCredentialArtifactsForSnapshot(ctx context.Context) ([]gen.ListCredentialArtifactsForSnapshotRow, error)
+	ListActiveEntitlementsForSnapshot(ctx context.Context) ([]gen.ListActiveEntitlementsForSnapshotRow, error)
+	InsertInventorySnapshot(ctx context.Context, arg gen.InsertInventorySnapshotParams) (int64, error)
+}
+
+// CredentialSnapshotRow is one active credential as captured by an inventory snapshot.
+type CredentialSnapshotRow struct {
+	SourceKind         string     `json:"source_kind"`
+	SourceName         string     `json:"source_name"`
+	CredentialKind     string     `json:"credential_kind"`
+	ExternalID         string     `json:"external_id"`
+	DisplayName        string     `json:"display_name,omitempty"`
+	Status             string     `json:"status,omitempty"`
+	AssetRefKind       string     `json:"asset_ref_kind,omitempty"`
+	AssetRefExternalID string     `json:"asset_ref_external_id,omitempty"`
+	CreatedAt          *time.Time `json:"created_at,omitempty"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt         *time.Time `json:"last_used_at,omitempty"`
+}
+
+// EntitlementSnapshotRow is one active entitlement as captured by an inventory snapshot.
+type EntitlementSnapshotRow struct {
+	SourceKind        string `json:"source_kind"`
+	SourceName        string `json:"source_name"`
+	AccountExternalID string `json:"account_external_id"`
+	AccountEmail      string `json:"account_email,omitempty"`
+	Kind              string `json:"kind"`
+	Resource          string `json:"resource"`
+	Permission        string `json:"permission,omitempty"`
+	PrivilegeLevel    int16  `json:"privilege_level,omitempty"`
+}
+
+// InventorySnapshotDate is the snapshot day t falls on, in UTC.
+func InventorySnapshotDate(t time.Time) pgtype.Date {
+	y, m, d := t.UTC().Date()
+	return pgtype.Date{Time: time.Date(y, m, d, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+// CaptureInventorySnapshot takes the day's snapshot of the credential and entitlement inventory
+// unless it was already taken. Each kind is stored as gzip-compressed JSON rows, and a day
+// already captured for a kind keeps its first snapshot, so restarts and concurrent workers
+// capture a day once. It returns the number of kinds written.
+func CaptureInventorySnapshot(ctx context.Context, q inventorySnapshotQueries, now time.Time) (int, error) {
+	day := InventorySnapshotDate(now)
+	existing, err := q.CountInventorySnapshotsForDate(ctx, day)
+	if err != nil {
+		return 0, err
+	}
+	if existing >= int64(len(inventorySnapshotKinds)) {
+		return 0, nil
+	}
+
+	credentials, err := q.ListCredentialArtifactsForSnapshot(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list credentials for snapshot: %w", err)
+	}
+	entitlements, err := q.ListActiveEntitlementsForSnapshot(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list entitlements for snapshot: %w", err)
+	}
+
+	written := 0
+	for _, snapshot := range []struct {
+		kind  string
+		rows  any
+		count int
+	}{
+		{kind: InventorySnapshotCredentials, rows: credentialSnapshotRows(credentials), count: len(credentials)},
+		{kind: InventorySnapshotEntitlements, rows: entitlementSnapshotRows(entitlements), count: len(entitlements)},
+	} {
+		payload, err := encodeInventorySnapshot(snapshot.rows)
+		if err != nil {
+			return written, fmt.Errorf("encode %s snapshot: %w", snapshot.kind, err)
+		}
+		n, err := q.InsertInventorySnapshot(ctx, gen.InsertInventorySnapshotParams{
+			SnapshotDate: day,
+			Kind:         snapshot.kind,
+			RowCount:     int32(snapshot.count),
+			Payload:      payload,
+		})
+		if err != nil {
+			return written, fmt.Errorf("insert %s snapshot: %w", snapshot.kind, err)
+		}
+		written += int(n)
+	}
+	return written, nil
+}
+
+func credentialSnapshotRows(rows []gen.ListCredentialArtifactsForSnapshotRow) []CredentialSnapshotRow {
+	out := make([]CredentialSnapshotRow, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, CredentialSnapshotRow{
+			SourceKind:         row.SourceKind,
+			SourceName:         row.SourceName,
+			CredentialKind:     row.CredentialKind,
+			ExternalID:         row.ExternalID,
+			DisplayName:        row.DisplayName,
+			Status:             row.Status,
+			AssetRefKind:       row.AssetRefKind,
+			AssetRefExternalID: row.AssetRefExternalID,
+			CreatedAt:          snapshotTime(row.CreatedAtSource),
+			ExpiresAt:          snapshotTime(row.ExpiresAtSource),
+			LastUsedAt:         snapshotTime(row.LastUsedAtSource),
+		})
+	}
+	return out
+}
+
+func entitlementSnapshotRows(rows []gen.ListActiveEntitlementsForSnapshotRow) []EntitlementSnapshotRow {
+	out := make([]EntitlementSnapshotRow, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, EntitlementSnapshotRow{
+			SourceKind:        row.AppUserSourceKind,
+			SourceName:        row.AppUserSourceName,
+			AccountExternalID: row.AppUserExternalID,
+			AccountEmail:      row.AppUserEmail,
+			Kind:              row.EntitlementKind,
+			Resource:          row.EntitlementResource,
+			Permission:        row.EntitlementPermission,
+			PrivilegeLevel:    row.EntitlementPrivilegeLevel,
+		})
+	}
+	return out
+}
+
+func snapshotTime(ts pgtype.Timestamptz) *time.Time {
+	if !ts.Valid {
+		return nil
+	}
+	t := ts.Time.UTC()
+	return &t
+}
+
+func encodeInventorySnapshot(rows any) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(rows); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeCredentialSnapshot returns the rows of a credentials snapshot payload.
+func DecodeCredentialSnapshot(payload []byte) ([]CredentialSnapshotRow, error) {
+	return decodeInventorySnapshot[CredentialSnapshotRow](payload)
+}
+
+// DecodeEntitlementSnapshot returns the rows of an entitlements snapshot payload.
+func DecodeEntitlementSnapshot(payload []byte) ([]EntitlementSnapshotRow, error) {
+	return decodeInventorySnapshot[EntitlementSnapshotRow](payload)
+}
+
+func decodeInventorySnapshot[T any](payload []byte) ([]T, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	var rows []T
+	if err := json.NewDecoder(zr).Decode(&rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// RunInventorySnapshots captures the day's inventory snapshot immediately and then checks again
+// every interval until ctx is cancelled.
+func RunInventorySnapshots(ctx context.Context, q inventorySnapshotQueries, interval time.Duration) {
+	if interval <= 0 {
+		interval = InventorySnapshotInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		runCtx, cancel := context.WithTimeout(ctx, inventorySnapshotTimeout)
+		written, err := CaptureInventorySnapshot(runCtx, q, time.Now())
+		cancel()
+		switch {
+		case err != nil && !errors.Is(err, context.Canceled):
+			slog.Error("inventory snapshot failed", "written", written, "err", err)
+		case written > 0:
+			slog.Info("captured inventory snapshot", "date", InventorySnapshotDate(time.Now()).Time.Format(time.DateOnly), "kinds", written)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
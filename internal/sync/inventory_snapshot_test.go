@@ -0,0 +1,117 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// inventorySnapshotStub is a live inventory that tests change between captures, and an
+// append-only snapshot table keyed by day and kind.
+type inventorySnapshotStub struct {
+	credentials  []gen.ListCredentialArtifactsForSnapshotRow
+	entitlements []gen.ListActiveEntitlementsForSnapshotRow
+	snapshots    map[string]gen.InsertInventorySnapshotParams
+}
+
+func inventorySnapshotKey(day pgtype.Date, kind string) string {
+	return day.Time.Format(time.DateOnly) + "/" + kind
+}
+
+func (s *inventorySnapshotStub) CountInventorySnapshotsForDate(_ context.Context, day pgtype.Date) (int64, error) {
+	var n int64
+	for _, kind := range inventorySnapshotKinds {
+		if _, ok := s.snapshots[inventorySnapshotKey(day, kind)]; ok {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *inventorySnapshotStub) ListCredentialArtifactsForSnapshot(context.Context) ([]gen.ListCredentialArtifactsForSnapshotRow, error) {
+	return append([]gen.ListCredentialArtifactsForSnapshotRow(nil), s.credentials...), nil
+}
+
+func (s *inventorySnapshotStub) ListActiveEntitlementsForSnapshot(context.Context) ([]gen.ListActiveEntitlementsForSnapshotRow, error) {
+	return append([]gen.ListActiveEntitlementsForSnapshotRow(nil), s.entitlements...), nil
+}
+
+func (s *inventorySnapshotStub) InsertInventorySnapshot(_ context.Context, arg gen.InsertInventorySnapshotParams) (int64, error) {
+	key := inventorySnapshotKey(arg.SnapshotDate, arg.Kind)
+	if _, ok := s.snapshots[key]; ok {
+		return 0, nil
+	}
+	s.snapshots[key] = arg
+	return 1, nil
+}
+
+func (s *inventorySnapshotStub) credentialsAsOf(t *testing.T, day time.Time) []CredentialSnapshotRow {
+	t.Helper()
+	snapshot, ok := s.snapshots[inventorySnapshotKey(InventorySnapshotDate(day), InventorySnapshotCredentials)]
+	if !ok {
+		t.Fatalf("no credentials snapshot for %s", day.Format(time.DateOnly))
+	}
+	rows, err := DecodeCredentialSnapshot(snapshot.Payload)
+	if err != nil {
+		t.Fatalf("DecodeCredentialSnapshot() error = %v", err)
+	}
+	return rows
+}
+
+func TestCaptureInventorySnapshotKeepsStateAtCaptureTime(t *testing.T) {
+	t.Parallel()
+
+	expires := time.Date(2026, time.December, 1, 0, 0, 0, 0, time.UTC)
+	stub := &inventorySnapshotStub{
+		credentials: []gen.ListCredentialArtifactsForSnapshotRow{
+			{SourceKind: "github", SourceName: "acme", CredentialKind: "github_deploy_key", ExternalID: "11", Status: "active", ExpiresAtSource: pgtype.Timestamptz{Time: expires, Valid: true}},
+		},
+		entitlements: []gen.ListActiveEntitlementsForSnapshotRow{
+			{AppUserSourceKind: "github", AppUserSourceName: "acme", AppUserExternalID: "alice", EntitlementKind: "github_org_role", EntitlementResource: "github_org:acme", EntitlementPermission: "admin", EntitlementPrivilegeLevel: 4},
+		},
+		snapshots: map[string]gen.InsertInventorySnapshotParams{},
+	}
+	day1 := time.Date(2026, time.October, 1, 6, 0, 0, 0, time.UTC)
+
+	written, err := CaptureInventorySnapshot(context.Background(), stub, day1)
+	if err != nil || written != 2 {
+		t.Fatalf("CaptureInventorySnapshot() = %d, %v, want 2 kinds", written, err)
+	}
+
+	// The live inventory changes later the same day and on the next.
+	stub.credentials[0].Status = "revoked"
+	stub.credentials = append(stub.credentials, gen.ListCredentialArtifactsForSnapshotRow{SourceKind: "github", SourceName: "acme", CredentialKind: "github_deploy_key", ExternalID: "22", Status: "active"})
+	stub.entitlements = nil
+	if written, err := CaptureInventorySnapshot(context.Background(), stub, day1.Add(12*time.Hour)); err != nil || written != 0 {
+		t.Fatalf("second capture on %s = %d, %v, want the day's snapshot kept", day1.Format(time.DateOnly), written, err)
+	}
+
+	asOfDay1 := stub.credentialsAsOf(t, day1)
+	if len(asOfDay1) != 1 || asOfDay1[0].ExternalID != "11" || asOfDay1[0].Status != "active" {
+		t.Fatalf("credentials as of day 1 = %+v, want only 11, active", asOfDay1)
+	}
+	if asOfDay1[0].ExpiresAt == nil || !asOfDay1[0].ExpiresAt.Equal(expires) || asOfDay1[0].LastUsedAt != nil {
+		t.Fatalf("credential times = %+v, want expiry %s and no last use", asOfDay1[0], expires)
+	}
+	entitlements, err := DecodeEntitlementSnapshot(stub.snapshots[inventorySnapshotKey(InventorySnapshotDate(day1), InventorySnapshotEntitlements)].Payload)
+	if err != nil {
+		t.Fatalf("DecodeEntitlementSnapshot() error = %v", err)
+	}
+	if len(entitlements) != 1 || entitlements[0].AccountExternalID != "alice" || entitlements[0].PrivilegeLevel != 4 {
+		t.Fatalf("entitlements as of day 1 = %+v, want alice's org admin role", entitlements)
+	}
+
+	day2 := day1.Add(24 * time.Hour)
+	if written, err := CaptureInventorySnapshot(context.Background(), stub, day2); err != nil || written != 2 {
+		t.Fatalf("CaptureInventorySnapshot(day 2) = %d, %v, want 2 kinds", written, err)
+	}
+	if asOfDay2 := stub.credentialsAsOf(t, day2); len(asOfDay2) != 2 || asOfDay2[0].Status != "revoked" {
+		t.Fatalf("credentials as of day 2 = %+v, want both, 11 revoked", asOfDay2)
+	}
+	if got := stub.snapshots[inventorySnapshotKey(InventorySnapshotDate(day2), InventorySnapshotEntitlements)].RowCount; got != 0 {
+		t.Fatalf("entitlements row count on day 2 = %d, want 0", got)
+	}
+}
@@ -22,6 +22,19 @@ func RunOnceScopeNameForMode(mode registry.RunMode) string {
 	}
 }
 
+// runModeForRunOnceScopeName reports the mode of a per-mode run-once lane; the legacy
+// shared scope serves every mode.
+func runModeForRunOnceScopeName(scopeName string) (registry.RunMode, bool) {
+	switch scopeName {
+	case RunOnceScopeNameFull:
+		return registry.RunModeFull, true
+	case RunOnceScopeNameDiscovery:
+		return registry.RunModeDiscovery, true
+	default:
+		return "", false
+	}
+}
+
 func ResyncNotifyChannelForMode(mode registry.RunMode) string {
 	switch mode.Normalize() {
 	case registry.RunModeDiscovery:
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-sspm/open-sspm/internal/connectors/httpclient"
 	"github.com/open-sspm/open-sspm/internal/connectors/registry"
 	"github.com/open-sspm/open-sspm/internal/db/gen"
 	"github.com/open-sspm/open-sspm/internal/identity"
@@ -33,6 +34,7 @@ type Orchestrator struct {
 	reporter       registry.Reporter
 	globalEvalMode string
 	locks          LockManager
+	limiter        *RunLimiter
 	mode           registry.RunMode
 	identityFn     func(context.Context, *gen.Queries) (identity.Stats, error)
 	globalEvalFn   func(context.Context, *gen.Queries, string, bool, func(registry.Event)) error
@@ -123,6 +125,11 @@ func (o *Orchestrator) SetLockManager(m LockManager) {
 	o.locks = m
 }
 
+// SetRunLimiter bounds concurrent integration runs; nil leaves them unbounded.
+func (o *Orchestrator) SetRunLimiter(l *RunLimiter) {
+	o.limiter = l
+}
+
 func (o *Orchestrator) SetGlobalEvalMode(mode string) {
 	mode = strings.ToLower(strings.TrimSpace(mode))
 	if mode == "" {
@@ -224,6 +231,12 @@ func (o *Orchestrator) RunOnce(ctx context.Context) error {
 	_ = g.Wait()
 
 	if o.mode.Normalize() == registry.RunModeDiscovery {
+		// Discovery seeders only request the primary binding recompute; run it once for the
+		// whole pass rather than leaving it to the timer.
+		if err := registry.FlushPrimaryBindingRecompute(ctx); err != nil {
+			slog.Error("primary saas app binding recompute failed", "err", err)
+			errs = append(errs, fmt.Errorf("recompute primary bindings: %w", err))
+		}
 		err := errors.Join(errs...)
 		o.report(registry.Event{Source: "sync", Stage: "done", Done: true, Err: err})
 		return err
@@ -250,7 +263,7 @@ func (o *Orchestrator) RunOnce(ctx context.Context) error {
 			Stage:   "resolve",
 			Current: 1,
 			Total:   1,
-			Message: fmt.Sprintf("identity resolution: linked=%d created=%d updated=%d", resolveStats.AutoLinked, resolveStats.NewIdentities, resolveStats.UpdatedIdentites),
+			Message: fmt.Sprintf("identity resolution: linked=%d created=%d updated=%d merge_suggestions=%d", resolveStats.AutoLinked, resolveStats.NewIdentities, resolveStats.UpdatedIdentites, resolveStats.MergeSuggestions),
 		})
 	}
 
@@ -355,9 +368,7 @@ func (o *Orchestrator) runIntegrationWithRetry(ctx context.Context, integration
 			}
 		}
 
-		runErr = o.withConnectorLock(ctx, kind, name, func(lockCtx context.Context) error {
-			return integration.Run(lockCtx, o.q, o.pool, o.report, mode)
-		})
+		runErr = o.runIntegrationAttempt(ctx, integration, kind, name, mode)
 		if runErr == nil {
 			return nil
 		}
@@ -368,6 +379,67 @@ func (o *Orchestrator) runIntegrationWithRetry(ctx context.Context, integration
 	return runErr
 }
 
+// runIntegrationAttempt waits for a run slot, then takes the connector lock and runs under the
+// kind's sync run timeout. The slot is released between retry attempts so a backing-off
+// integration does not starve others. The provider API requests the attempt makes are
+// recorded on the sync run it created.
+func (o *Orchestrator) runIntegrationAttempt(ctx context.Context, integration registry.Integration, kind, name string, mode registry.RunMode) error {
+	release, err := o.limiter.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return o.withConnectorLock(ctx, kind, name, func(lockCtx context.Context) error {
+		runCtx, cancel := registry.WithSyncRunTimeout(lockCtx, o.registry.SyncRunTimeout(kind))
+		defer cancel()
+		counter := &httpclient.RequestCounter{}
+		runCtx = httpclient.WithRequestCounter(runCtx, counter)
+		runCtx = registry.WithSyncRunID(runCtx)
+		err := integration.Run(runCtx, o.q, o.pool, o.report, mode)
+		if cause := registry.SyncRunTimeoutCause(runCtx); cause != nil && err != nil && !errors.As(err, new(*registry.SyncRunTimeoutError)) {
+			err = fmt.Errorf("%w: %w", cause, err)
+		}
+		runKind := registry.SyncRunSourceKind(kind, mode)
+		stats := counter.Stats()
+		observeAPIRequestStats(runKind, name, stats)
+		if runID, ok := registry.SyncRunIDFromContext(runCtx); ok {
+			// runCtx may be past its run timeout, so write under the lock's context.
+			if recordErr := recordSyncRunAPIRequests(lockCtx, o.q, runID, stats); recordErr != nil {
+				slog.Warn("failed to record sync run api requests", "kind", kind, "name", name, "err", recordErr)
+			}
+		}
+		return err
+	})
+}
+
+// recordSyncRunAPIRequests stores stats on runID, the sync run the integration created.
+func recordSyncRunAPIRequests(ctx context.Context, q *gen.Queries, runID int64, stats httpclient.RequestStats) error {
+	if q == nil || stats.Requests <= 0 {
+		return nil
+	}
+	return q.SetSyncRunAPIRequestCount(ctx, gen.SetSyncRunAPIRequestCountParams{
+		ID:              runID,
+		ApiRequestCount: stats.Requests,
+		ApiStats:        registry.MarshalJSON(stats),
+	})
+}
+
+// observeAPIRequestStats adds a run's provider API requests to the connector API metrics.
+func observeAPIRequestStats(runKind, name string, stats httpclient.RequestStats) {
+	for host, count := range stats.Hosts {
+		metrics.ConnectorAPIRequestsTotal.WithLabelValues(runKind, name, host).Add(float64(count))
+	}
+	if stats.BytesSent > 0 {
+		metrics.ConnectorAPIBytesTotal.WithLabelValues(runKind, name, "sent").Add(float64(stats.BytesSent))
+	}
+	if stats.BytesReceived > 0 {
+		metrics.ConnectorAPIBytesTotal.WithLabelValues(runKind, name, "received").Add(float64(stats.BytesReceived))
+	}
+	for class, count := range stats.Errors {
+		metrics.ConnectorAPIErrorsTotal.WithLabelValues(runKind, name, class).Add(float64(count))
+	}
+}
+
 func isRetryableTimeoutError(err error) bool {
 	if err == nil {
 		return false
@@ -379,6 +451,10 @@ func isRetryableTimeoutError(err error) bool {
 	if errors.Is(err, errSyncLockLost) {
 		return false
 	}
+	// A run that used up its whole sync run timeout would likely do so again.
+	if errors.As(err, new(*registry.SyncRunTimeoutError)) {
+		return false
+	}
 	if errors.Is(err, context.DeadlineExceeded) {
 		return true
 	}
@@ -0,0 +1,153 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-sspm/open-sspm/internal/connectors/httpclient"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+type syncRunRequestsDB struct {
+	row       []int64
+	queryArgs [][]any
+	execSQL   []string
+	execArgs  [][]any
+}
+
+func (db *syncRunRequestsDB) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	db.execSQL = append(db.execSQL, sql)
+	db.execArgs = append(db.execArgs, args)
+	return pgconn.CommandTag{}, nil
+}
+
+func (db *syncRunRequestsDB) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	panic("Query not expected")
+}
+
+func (db *syncRunRequestsDB) QueryRow(_ context.Context, _ string, args ...any) pgx.Row {
+	db.queryArgs = append(db.queryArgs, args)
+	return int64Row(db.row)
+}
+
+type int64Row []int64
+
+func (r int64Row) Scan(dest ...any) error {
+	for i := range dest {
+		*dest[i].(*int64) = r[i]
+	}
+	return nil
+}
+
+type orchestratorHTTPIntegration struct {
+	url      string
+	requests int
+	noRun    bool
+}
+
+func (i *orchestratorHTTPIntegration) Kind() string { return "okta" }
+func (i *orchestratorHTTPIntegration) Name() string { return "example.okta.com" }
+func (i *orchestratorHTTPIntegration) Role() registry.IntegrationRole {
+	return registry.RoleIdP
+}
+func (i *orchestratorHTTPIntegration) InitEvents() []registry.Event { return nil }
+func (i *orchestratorHTTPIntegration) Run(ctx context.Context, q *gen.Queries, _ *pgxpool.Pool, _ func(registry.Event), mode registry.RunMode) error {
+	if !i.noRun {
+		if _, err := registry.CreateSyncRun(ctx, q, gen.CreateSyncRunParams{
+			SourceKind: registry.SyncRunSourceKind(i.Kind(), mode),
+			SourceName: i.Name(),
+		}); err != nil {
+			return err
+		}
+	}
+	client := httpclient.New(0)
+	for range i.requests {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, i.url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+	return nil
+}
+
+func TestOrchestrator_RecordsAPIRequestsOnSyncRun(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	db := &syncRunRequestsDB{row: []int64{41}}
+	orch := NewOrchestrator(&pgxpool.Pool{}, nil)
+	orch.q = gen.New(db)
+	orch.SetLockManager(orchestratorTestLockManager{})
+	orch.SetRunMode(registry.RunModeDiscovery)
+
+	integration := &orchestratorHTTPIntegration{url: server.URL, requests: 3}
+	if err := orch.AddIntegration(integration); err != nil {
+		t.Fatalf("AddIntegration() error = %v", err)
+	}
+	if err := orch.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+
+	if len(db.queryArgs) != 1 || db.queryArgs[0][0] != "okta_discovery" || db.queryArgs[0][1] != "example.okta.com" {
+		t.Fatalf("create run args = %v, want the okta_discovery run of example.okta.com", db.queryArgs)
+	}
+	if len(db.execArgs) != 1 || !strings.Contains(db.execSQL[0], "SetSyncRunAPIRequestCount") {
+		t.Fatalf("execs = %v, want one SetSyncRunAPIRequestCount", db.execSQL)
+	}
+	if db.execArgs[0][0] != int64(41) || db.execArgs[0][1] != int64(3) {
+		t.Fatalf("persisted %v, want run 41 with 3 requests", db.execArgs[0])
+	}
+	var stats httpclient.RequestStats
+	if err := json.Unmarshal(db.execArgs[0][2].([]byte), &stats); err != nil {
+		t.Fatalf("decode api stats: %v", err)
+	}
+	if host := strings.TrimPrefix(server.URL, "http://"); stats.Requests != 3 || stats.Hosts[strings.Split(host, ":")[0]] != 3 {
+		t.Fatalf("api stats = %+v, want 3 requests to the test server", stats)
+	}
+}
+
+func TestOrchestrator_SkipsAPIRequestsWithoutSyncRun(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	db := &syncRunRequestsDB{row: []int64{41}}
+	orch := NewOrchestrator(&pgxpool.Pool{}, nil)
+	orch.q = gen.New(db)
+	orch.SetLockManager(orchestratorTestLockManager{})
+	orch.SetRunMode(registry.RunModeDiscovery)
+
+	integration := &orchestratorHTTPIntegration{url: server.URL, requests: 2, noRun: true}
+	if err := orch.AddIntegration(integration); err != nil {
+		t.Fatalf("AddIntegration() error = %v", err)
+	}
+	if err := orch.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+
+	// No run was created, so there is no run to attach the requests to; an earlier run of the
+	// source must not pick them up.
+	if len(db.queryArgs) != 0 || len(db.execArgs) != 0 {
+		t.Fatalf("queries = %v, execs = %v, want none", db.queryArgs, db.execSQL)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/open-sspm/open-sspm/internal/connectors/registry"
@@ -149,3 +150,46 @@ func TestOrchestrator_DoesNotRetryLockLostErrors(t *testing.T) {
 		t.Fatalf("lock-lost error should not be retryable")
 	}
 }
+
+type orchestratorHungIntegration struct {
+	orchestratorRetryIntegration
+}
+
+func (i *orchestratorHungIntegration) Run(ctx context.Context, _ *gen.Queries, _ *pgxpool.Pool, _ func(registry.Event), _ registry.RunMode) error {
+	i.calls++
+	<-ctx.Done()
+	return fmt.Errorf("list users: %w", ctx.Err())
+}
+
+func TestOrchestrator_SyncRunTimeoutStopsHungRunWithoutRetry(t *testing.T) {
+	t.Parallel()
+
+	reg := registry.NewRegistry()
+	reg.SetSyncRunTimeouts(map[string]time.Duration{"default": 20 * time.Millisecond})
+	orch := NewOrchestrator(&pgxpool.Pool{}, reg)
+	orch.SetLockManager(orchestratorTestLockManager{})
+	orch.SetRunMode(registry.RunModeDiscovery)
+	orch.timeoutRetryAttempts = 2
+	orch.timeoutRetryDelay = 0
+
+	integration := &orchestratorHungIntegration{orchestratorRetryIntegration{
+		kind: "okta",
+		name: "example.okta.com",
+		role: registry.RoleIdP,
+	}}
+	if err := orch.AddIntegration(integration); err != nil {
+		t.Fatalf("AddIntegration() error = %v", err)
+	}
+
+	err := orch.RunOnce(context.Background())
+	var timeoutErr *registry.SyncRunTimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Timeout != 20*time.Millisecond {
+		t.Fatalf("RunOnce() error = %v, want a sync run timeout", err)
+	}
+	if got := registry.ClassifySyncError(err); got != registry.SyncErrorKindTimeout {
+		t.Fatalf("ClassifySyncError() = %q, want %q", got, registry.SyncErrorKindTimeout)
+	}
+	if integration.calls != 1 {
+		t.Fatalf("calls = %d, want 1 (sync run timeouts are not retried)", integration.calls)
+	}
+}
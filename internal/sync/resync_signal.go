@@ -59,6 +59,9 @@ func (r *ResyncSignalRunner) RunOnce(ctx context.Context) error {
 		scopeName = RunOnceScopeNameForMode(modeForResyncChannel(r.notifyChannel))
 	}
 	notifyChannel := normalizeNotifyChannel(r.notifyChannel)
+	if requestedMode, ok := RunModeFromContext(ctx); ok && requestedMode != modeForResyncChannel(notifyChannel) {
+		return ErrNoConnectorsDue
+	}
 	request := TriggerRequest{}
 	if connectorKind, sourceName, ok := ConnectorScopeFromContext(ctx); ok {
 		request = TriggerRequest{
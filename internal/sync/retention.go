@@ -0,0 +1,94 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+// SyncRunRetentionInterval is how often the worker prunes old sync runs.
+const SyncRunRetentionInterval = time.Hour
+
+const (
+	syncRunRetentionTimeout = 10 * time.Minute
+	pgForeignKeyViolation   = "23503"
+)
+
+type syncRunRetentionQueries interface {
+	ListFinishedSyncRunsForRetention(ctx context.Context) ([]gen.ListFinishedSyncRunsForRetentionRow, error)
+	DeleteSyncRun(ctx context.Context, id int64) (int64, error)
+}
+
+// PruneSyncRuns deletes finished sync runs beyond the keep newest per source kind and name.
+// Discovery runs use their own source kind, so each source keeps keep runs per mode. Runs still
+// referenced by inventory rows (for example as the run that first saw a row) are kept; running
+// runs are never pruned. It returns the number of runs deleted.
+func PruneSyncRuns(ctx context.Context, q syncRunRetentionQueries, keep int) (int64, error) {
+	if keep <= 0 {
+		return 0, nil
+	}
+	runs, err := q.ListFinishedSyncRunsForRetention(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for _, id := range syncRunsBeyondRetention(runs, keep) {
+		n, err := q.DeleteSyncRun(ctx, id)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+				continue
+			}
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
+
+// syncRunsBeyondRetention returns the ids past the keep newest runs of each source. runs must be
+// grouped by source and ordered newest first within a source.
+func syncRunsBeyondRetention(runs []gen.ListFinishedSyncRunsForRetentionRow, keep int) []int64 {
+	type sourceKey struct{ kind, name string }
+	seen := make(map[sourceKey]int)
+	var out []int64
+	for _, run := range runs {
+		key := sourceKey{kind: run.SourceKind, name: run.SourceName}
+		seen[key]++
+		if seen[key] > keep {
+			out = append(out, run.ID)
+		}
+	}
+	return out
+}
+
+// RunSyncRunRetention prunes sync runs immediately and then every interval until ctx is cancelled.
+func RunSyncRunRetention(ctx context.Context, q syncRunRetentionQueries, keep int, interval time.Duration) {
+	if interval <= 0 {
+		interval = SyncRunRetentionInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		runCtx, cancel := context.WithTimeout(ctx, syncRunRetentionTimeout)
+		deleted, err := PruneSyncRuns(runCtx, q, keep)
+		cancel()
+		switch {
+		case err != nil && !errors.Is(err, context.Canceled):
+			slog.Error("sync run retention failed", "keep", keep, "deleted", deleted, "err", err)
+		case deleted > 0:
+			slog.Info("pruned old sync runs", "keep", keep, "deleted", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+type retentionQueriesStub struct {
+	runs       []gen.ListFinishedSyncRunsForRetentionRow
+	referenced map[int64]bool
+	deleted    []int64
+}
+
+func (s *retentionQueriesStub) ListFinishedSyncRunsForRetention(context.Context) ([]gen.ListFinishedSyncRunsForRetentionRow, error) {
+	return s.runs, nil
+}
+
+func (s *retentionQueriesStub) DeleteSyncRun(_ context.Context, id int64) (int64, error) {
+	if s.referenced[id] {
+		return 0, &pgconn.PgError{Code: pgForeignKeyViolation}
+	}
+	s.deleted = append(s.deleted, id)
+	return 1, nil
+}
+
+func TestPruneSyncRunsKeepsLatestPerSource(t *testing.T) {
+	t.Parallel()
+
+	// Grouped by source, newest first, as the query returns them.
+	stub := &retentionQueriesStub{
+		runs: []gen.ListFinishedSyncRunsForRetentionRow{
+			{ID: 90, SourceKind: "github", SourceName: "acme"},
+			{ID: 80, SourceKind: "github", SourceName: "acme"},
+			{ID: 70, SourceKind: "github", SourceName: "acme"},
+			{ID: 60, SourceKind: "github", SourceName: "acme"},
+			{ID: 50, SourceKind: "github", SourceName: "acme"},
+			{ID: 95, SourceKind: "okta", SourceName: "acme.okta.com"},
+			{ID: 40, SourceKind: "okta", SourceName: "acme.okta.com"},
+			{ID: 99, SourceKind: "okta_discovery", SourceName: "acme.okta.com"},
+			{ID: 20, SourceKind: "okta_discovery", SourceName: "acme.okta.com"},
+			{ID: 10, SourceKind: "okta_discovery", SourceName: "acme.okta.com"},
+		},
+		referenced: map[int64]bool{50: true},
+	}
+
+	deleted, err := PruneSyncRuns(context.Background(), stub, 2)
+	if err != nil {
+		t.Fatalf("PruneSyncRuns() error = %v", err)
+	}
+	if want := []int64{70, 60, 10}; !slices.Equal(stub.deleted, want) {
+		t.Fatalf("deleted ids = %v, want %v", stub.deleted, want)
+	}
+	if deleted != 3 {
+		t.Fatalf("deleted = %d, want 3 (run 50 is still referenced)", deleted)
+	}
+}
+
+func TestPruneSyncRunsDisabled(t *testing.T) {
+	t.Parallel()
+
+	stub := &retentionQueriesStub{runs: []gen.ListFinishedSyncRunsForRetentionRow{
+		{ID: 2, SourceKind: "github", SourceName: "acme"},
+		{ID: 1, SourceKind: "github", SourceName: "acme"},
+	}}
+	if _, err := PruneSyncRuns(context.Background(), stub, 0); err != nil {
+		t.Fatalf("PruneSyncRuns() error = %v", err)
+	}
+	if len(stub.deleted) != 0 {
+		t.Fatalf("deleted ids = %v, want none when retention is disabled", stub.deleted)
+	}
+}
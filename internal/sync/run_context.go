@@ -3,6 +3,7 @@ package sync
 import (
 	"context"
 
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
 	"github.com/open-sspm/open-sspm/internal/normalize"
 )
 
@@ -11,6 +12,7 @@ type syncRunContextKey int
 const (
 	syncRunContextKeyForce syncRunContextKey = iota
 	syncRunContextKeyConnectorScope
+	syncRunContextKeyRunMode
 )
 
 type TriggerRequest struct {
@@ -75,3 +77,20 @@ func ConnectorScopeFromContext(ctx context.Context) (connectorKind, sourceName s
 	}
 	return req.ConnectorKind, req.SourceName, true
 }
+
+// WithRunMode restricts a trigger to runners operating in mode; runners for
+// other modes report ErrNoConnectorsDue.
+func WithRunMode(ctx context.Context, mode registry.RunMode) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, syncRunContextKeyRunMode, mode.Normalize())
+}
+
+func RunModeFromContext(ctx context.Context) (registry.RunMode, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	mode, ok := ctx.Value(syncRunContextKeyRunMode).(registry.RunMode)
+	return mode, ok
+}
@@ -0,0 +1,50 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/open-sspm/open-sspm/internal/metrics"
+)
+
+// RunLimiter caps how many integration runs execute at once. A single limiter is shared by
+// every runner in the process so full and discovery lanes draw from the same slots; runs
+// beyond the limit queue until a slot frees.
+//
+// Slots are taken before the per-source connector lock, so a queued run never holds a lock
+// that would block another instance from syncing that source.
+type RunLimiter struct {
+	slots chan struct{}
+}
+
+// NewRunLimiter returns a limiter allowing n concurrent runs, or nil (unlimited) when n <= 0.
+func NewRunLimiter(n int) *RunLimiter {
+	if n <= 0 {
+		return nil
+	}
+	return &RunLimiter{slots: make(chan struct{}, n)}
+}
+
+// Acquire blocks until a slot is free or ctx is done. The returned release must be called
+// exactly once. A nil limiter never blocks.
+func (l *RunLimiter) Acquire(ctx context.Context) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+	default:
+		metrics.SyncRunsQueued.Inc()
+		select {
+		case l.slots <- struct{}{}:
+			metrics.SyncRunsQueued.Dec()
+		case <-ctx.Done():
+			metrics.SyncRunsQueued.Dec()
+			return nil, ctx.Err()
+		}
+	}
+	metrics.SyncRunsActive.Inc()
+	return func() {
+		metrics.SyncRunsActive.Dec()
+		<-l.slots
+	}, nil
+}
@@ -0,0 +1,99 @@
+package sync
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+	"github.com/open-sspm/open-sspm/internal/db/gen"
+)
+
+type orchestratorConcurrencyIntegration struct {
+	kind    string
+	name    string
+	running *atomic.Int32
+	peak    *atomic.Int32
+	runs    atomic.Int32
+}
+
+func (i *orchestratorConcurrencyIntegration) Kind() string { return i.kind }
+func (i *orchestratorConcurrencyIntegration) Name() string { return i.name }
+func (i *orchestratorConcurrencyIntegration) Role() registry.IntegrationRole {
+	return registry.RoleApp
+}
+func (i *orchestratorConcurrencyIntegration) InitEvents() []registry.Event { return nil }
+func (i *orchestratorConcurrencyIntegration) Run(context.Context, *gen.Queries, *pgxpool.Pool, func(registry.Event), registry.RunMode) error {
+	now := i.running.Add(1)
+	for {
+		peak := i.peak.Load()
+		if now <= peak || i.peak.CompareAndSwap(peak, now) {
+			break
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	i.running.Add(-1)
+	i.runs.Add(1)
+	return nil
+}
+
+func TestOrchestrator_RunLimiterSerializesRuns(t *testing.T) {
+	t.Parallel()
+
+	var running, peak atomic.Int32
+	orch := NewOrchestrator(&pgxpool.Pool{}, nil)
+	orch.SetLockManager(orchestratorTestLockManager{})
+	orch.SetRunMode(registry.RunModeDiscovery)
+	orch.SetRunLimiter(NewRunLimiter(1))
+
+	integrations := []*orchestratorConcurrencyIntegration{
+		{kind: "github", name: "acme", running: &running, peak: &peak},
+		{kind: "datadog", name: "acme", running: &running, peak: &peak},
+	}
+	for _, integration := range integrations {
+		if err := orch.AddIntegration(integration); err != nil {
+			t.Fatalf("AddIntegration() error = %v", err)
+		}
+	}
+
+	if err := orch.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	for _, integration := range integrations {
+		if got := integration.runs.Load(); got != 1 {
+			t.Fatalf("%s runs = %d, want 1", integration.kind, got)
+		}
+	}
+	if got := peak.Load(); got != 1 {
+		t.Fatalf("peak concurrent runs = %d, want 1", got)
+	}
+}
+
+func TestRunLimiterAcquireHonorsContext(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewRunLimiter(1)
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.Acquire(ctx); err == nil {
+		t.Fatalf("Acquire() on a full limiter returned nil error")
+	}
+
+	release()
+	release, err = limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire() after release error = %v", err)
+	}
+	release()
+
+	if NewRunLimiter(0) != nil {
+		t.Fatalf("NewRunLimiter(0) should be unlimited (nil)")
+	}
+}
@@ -9,7 +9,11 @@ type RunPolicy struct {
 	FailureBackoffBase   time.Duration
 	FailureBackoffMax    time.Duration
 	RecentFinishedRunCap int
-	Now                  func() time.Time
+	// APIRequestBudgetByKind caps the provider API requests a connector kind's source may use
+	// per APIRequestBudgetWindow across all run modes; a run expected to exceed it is deferred.
+	APIRequestBudgetByKind map[string]int64
+	APIRequestBudgetWindow time.Duration
+	Now                    func() time.Time
 }
 
 func (p RunPolicy) now() time.Time {
@@ -63,6 +63,13 @@ func (r *runOnceLockRunner) RunOnce(ctx context.Context) error {
 	if scopeName == "" {
 		scopeName = legacyRunOnceScopeName
 	}
+	// A trigger for the other mode is not this lane's work. Answer before taking the lane's lock
+	// so a busy lane does not report the other mode's trigger as already running.
+	if requestedMode, ok := RunModeFromContext(ctx); ok {
+		if laneMode, ok := runModeForRunOnceScopeName(scopeName); ok && laneMode != requestedMode {
+			return ErrNoConnectorsDue
+		}
+	}
 
 	var (
 		lock Lock
@@ -0,0 +1,34 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/open-sspm/open-sspm/internal/connectors/registry"
+)
+
+func TestTryRunOnceLockRunnerIgnoresBusyLaneOfOtherMode(t *testing.T) {
+	t.Parallel()
+
+	// The full lane's lock is held; trackingLockManager never grants a lock.
+	locks := &trackingLockManager{}
+	full := NewTryRunOnceLockRunnerWithScope(locks, stubRunner{}, RunOnceScopeNameFull)
+
+	err := full.RunOnce(WithRunMode(context.Background(), registry.RunModeDiscovery))
+	if !errors.Is(err, ErrNoConnectorsDue) {
+		t.Fatalf("discovery trigger on the full lane: err = %v, want ErrNoConnectorsDue", err)
+	}
+	if locks.tryAcquireCalls != 0 {
+		t.Fatalf("TryAcquire calls = %d, want the full lane lock left alone", locks.tryAcquireCalls)
+	}
+
+	composite := NewCompositeRunner(full, stubRunner{err: ErrNoConnectorsDue})
+	if err := composite.RunOnce(WithRunMode(context.Background(), registry.RunModeDiscovery)); errors.Is(err, ErrSyncAlreadyRunning) {
+		t.Fatalf("discovery trigger reported already running while only the full lane is busy")
+	}
+
+	if err := full.RunOnce(WithRunMode(context.Background(), registry.RunModeFull)); !errors.Is(err, ErrSyncAlreadyRunning) {
+		t.Fatalf("full trigger on the busy full lane: err = %v, want ErrSyncAlreadyRunning", err)
+	}
+}